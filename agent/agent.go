@@ -150,6 +150,17 @@ const (
 	MongoOplogSize    = "MONGO_OPLOG_SIZE"
 	NUMACtlPreference = "NUMA_CTL_PREFERENCE"
 
+	// DeployerContext selects the deployer.Context implementation used
+	// by the machine agent to deploy unit agents. When unset, unit
+	// agents are installed as services on the local init system.
+	DeployerContext = "DEPLOYER_CONTEXT"
+
+	// InstanceIdentityEnrollment, when "true", indicates that a new
+	// machine agent has no provisioning password, and must instead
+	// present the signed identity document of its cloud instance the
+	// first time it connects to the controller.
+	InstanceIdentityEnrollment = "INSTANCE_IDENTITY_ENROLLMENT"
+
 	AgentLoginRateLimit  = "AGENT_LOGIN_RATE_LIMIT"
 	AgentLoginMinPause   = "AGENT_LOGIN_MIN_PAUSE"
 	AgentLoginMaxPause   = "AGENT_LOGIN_MAX_PAUSE"
@@ -163,6 +174,10 @@ const (
 
 	MgoStatsEnabled = "MGO_STATS_ENABLED"
 
+	// MachineShutdownGracePeriod is the time allowed for a machine
+	// agent's shutdown tasks to run when its machine is being removed.
+	MachineShutdownGracePeriod = "MACHINE_SHUTDOWN_GRACE_PERIOD"
+
 	// LoggingOverride will set the logging for this agent to the value
 	// specified. Model configuration will be ignored and this value takes
 	// precidence for the agent.
@@ -429,7 +444,7 @@ func NewAgentConfig(configParams AgentConfigParams) (ConfigSetterWriter, error)
 	if configParams.UpgradedToVersion == version.Zero {
 		return nil, errors.Trace(requiredError("upgradedToVersion"))
 	}
-	if configParams.Password == "" {
+	if configParams.Password == "" && configParams.Values[InstanceIdentityEnrollment] != "true" {
 		return nil, errors.Trace(requiredError("password"))
 	}
 	if uuid := configParams.Controller.Id(); uuid == "" {
@@ -57,6 +57,21 @@ var agentConfigTests = []struct {
 		UpgradedToVersion: jujuversion.Current,
 	},
 	checkErr: "password not found in configuration",
+}, {
+	about: "no password with instance identity enrollment",
+	params: agent.AgentConfigParams{
+		Paths:             agent.Paths{DataDir: "/data/dir"},
+		Tag:               names.NewMachineTag("1"),
+		UpgradedToVersion: jujuversion.Current,
+		Controller:        testing.ControllerTag,
+		Model:             testing.ModelTag,
+		CACert:            "ca cert",
+		Values:            map[string]string{agent.InstanceIdentityEnrollment: "true"},
+	},
+	inspectConfig: func(c *gc.C, cfg agent.Config) {
+		c.Check(cfg.OldPassword(), gc.Equals, "")
+		c.Check(cfg.Value(agent.InstanceIdentityEnrollment), gc.Equals, "true")
+	},
 }, {
 	about: "missing model tag",
 	params: agent.AgentConfigParams{
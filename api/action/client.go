@@ -43,6 +43,9 @@ func (c *Client) FindActionTagsByPrefix(arg params.FindTags) (params.FindTagsRes
 // queued Action, or an error if there was a problem queueing up the
 // Action.
 func (c *Client) Enqueue(arg params.Actions) (params.ActionResults, error) {
+	if arg.Webhook != nil && c.BestAPIVersion() < 5 {
+		return params.ActionResults{}, errors.NotSupportedf("action webhooks by this version of Juju")
+	}
 	results := params.ActionResults{}
 	err := c.facade.FacadeCall("Enqueue", arg, &results)
 	return results, err
@@ -56,13 +59,45 @@ func (c *Client) FindActionsByNames(arg params.FindActionsByNames) (params.Actio
 	return results, err
 }
 
+// listAllPageSize is the number of actions fetched by each call to
+// ListAll, when the controller supports fetching them a page at a time.
+const listAllPageSize = 500
+
 // ListAll takes a list of Entities representing ActionReceivers and returns
 // all of the Actions that have been queued or run by each of those
 // Entities.
 func (c *Client) ListAll(arg params.Entities) (params.ActionsByReceivers, error) {
 	results := params.ActionsByReceivers{}
-	err := c.facade.FacadeCall("ListAll", arg, &results)
-	return results, err
+	if c.BestAPIVersion() < 5 {
+		err := c.facade.FacadeCall("ListAll", arg, &results)
+		return results, err
+	}
+	// Fetch the actions a page at a time so that no single response
+	// is too large, however many actions there are.
+	args := params.ListActionsArgs{
+		Entities: arg.Entities,
+		Page:     params.PageParams{Limit: listAllPageSize},
+	}
+	for {
+		var page params.ActionsByReceivers
+		if err := c.facade.FacadeCall("ListAll", args, &page); err != nil {
+			return params.ActionsByReceivers{}, errors.Trace(err)
+		}
+		if len(page.Actions) != len(arg.Entities) {
+			return params.ActionsByReceivers{}, errors.Errorf("expected %d results, got %d", len(arg.Entities), len(page.Actions))
+		}
+		if results.Actions == nil {
+			results.Actions = page.Actions
+		} else {
+			for i, receiver := range page.Actions {
+				results.Actions[i].Actions = append(results.Actions[i].Actions, receiver.Actions...)
+			}
+		}
+		if page.Page == nil || page.Page.NextCursor == "" {
+			return results, nil
+		}
+		args.Page.Cursor = page.Page.NextCursor
+	}
 }
 
 // ListPending takes a list of Entities representing ActionReceivers
@@ -109,6 +109,48 @@ func (s *actionSuite) TestApplicationCharmActions(c *gc.C) {
 	}
 }
 
+func (s *actionSuite) TestListAllPages(c *gc.C) {
+	c.Assert(s.client.BestAPIVersion(), jc.GreaterThan, 4)
+	entities := []params.Entity{{Tag: "unit-mysql-0"}, {Tag: "unit-wordpress-0"}}
+	pages := []params.ActionsByReceivers{{
+		Actions: []params.ActionsByReceiver{
+			{Receiver: "unit-mysql-0", Actions: []params.ActionResult{{Status: "one"}, {Status: "two"}}},
+			{Receiver: "unit-wordpress-0"},
+		},
+		Page: &params.PageInfo{NextCursor: "next"},
+	}, {
+		Actions: []params.ActionsByReceiver{
+			{Receiver: "unit-mysql-0"},
+			{Receiver: "unit-wordpress-0", Actions: []params.ActionResult{{Status: "three"}}},
+		},
+		Page: &params.PageInfo{},
+	}}
+	var cursors []string
+	cleanup := action.PatchClientFacadeCall(s.client,
+		func(req string, paramsIn interface{}, resp interface{}) error {
+			c.Assert(req, gc.Equals, "ListAll")
+			args, ok := paramsIn.(params.ListActionsArgs)
+			c.Assert(ok, jc.IsTrue)
+			c.Check(args.Entities, jc.DeepEquals, entities)
+			c.Check(args.Page.Limit, jc.GreaterThan, 0)
+			cursors = append(cursors, args.Page.Cursor)
+			*(resp.(*params.ActionsByReceivers)) = pages[len(cursors)-1]
+			return nil
+		},
+	)
+	defer cleanup()
+
+	result, err := s.client.ListAll(params.Entities{Entities: entities})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cursors, jc.DeepEquals, []string{"", "next"})
+	c.Assert(result, jc.DeepEquals, params.ActionsByReceivers{
+		Actions: []params.ActionsByReceiver{
+			{Receiver: "unit-mysql-0", Actions: []params.ActionResult{{Status: "one"}, {Status: "two"}}},
+			{Receiver: "unit-wordpress-0", Actions: []params.ActionResult{{Status: "three"}}},
+		},
+	})
+}
+
 // replace sCharmActions" facade call with required results and error
 // if desired
 func patchApplicationCharmActions(c *gc.C, apiCli *action.Client, patchResults []params.ApplicationCharmActionsResult, err string) func() {
@@ -0,0 +1,66 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actionwebhooks
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	apiwatcher "github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/watcher"
+)
+
+// Client provides access to the ActionWebhooks API facade.
+type Client struct {
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client-side ActionWebhooks facade.
+func NewClient(caller base.APICaller) *Client {
+	return &Client{
+		facade: base.NewFacadeCaller(caller, "ActionWebhooks"),
+	}
+}
+
+// WatchActionWebhooks returns a StringsWatcher that notifies of the ids
+// of operations whose webhooks have been added, changed or removed.
+func (c *Client) WatchActionWebhooks() (watcher.StringsWatcher, error) {
+	var result params.StringsWatchResult
+	if err := c.facade.FacadeCall("WatchActionWebhooks", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return apiwatcher.NewStringsWatcher(c.facade.RawAPICaller(), result), nil
+}
+
+// ActionWebhook returns the webhook of the given operation, along with
+// the results of its actions if they have all completed.
+func (c *Client) ActionWebhook(operation string) (params.ActionWebhookResult, error) {
+	var results params.ActionWebhookResults
+	args := params.ActionWebhookOperations{Operations: []string{operation}}
+	if err := c.facade.FacadeCall("ActionWebhooks", args, &results); err != nil {
+		return params.ActionWebhookResult{}, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return params.ActionWebhookResult{}, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return params.ActionWebhookResult{}, result.Error
+	}
+	return result, nil
+}
+
+// RemoveActionWebhook removes the webhook of the given operation.
+func (c *Client) RemoveActionWebhook(operation string) error {
+	var results params.ErrorResults
+	args := params.ActionWebhookOperations{Operations: []string{operation}}
+	if err := c.facade.FacadeCall("RemoveActionWebhooks", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
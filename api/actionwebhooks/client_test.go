@@ -0,0 +1,92 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actionwebhooks_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/actionwebhooks"
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/apiserver/params"
+)
+
+type clientSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&clientSuite{})
+
+func (s *clientSuite) TestActionWebhook(c *gc.C) {
+	stub := new(testing.Stub)
+	apiCaller := basetesting.APICallerFunc(func(
+		objType string, version int,
+		id, request string,
+		args, response interface{},
+	) error {
+		c.Check(objType, gc.Equals, "ActionWebhooks")
+		stub.AddCall(request, args)
+		*response.(*params.ActionWebhookResults) = params.ActionWebhookResults{
+			Results: []params.ActionWebhookResult{{
+				Operation: "op-1",
+				URL:       "https://example.com/hook",
+			}},
+		}
+		return nil
+	})
+	client := actionwebhooks.NewClient(apiCaller)
+
+	result, err := client.ActionWebhook("op-1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ActionWebhookResult{
+		Operation: "op-1",
+		URL:       "https://example.com/hook",
+	})
+	stub.CheckCalls(c, []testing.StubCall{{
+		"ActionWebhooks", []interface{}{params.ActionWebhookOperations{
+			Operations: []string{"op-1"},
+		}},
+	}})
+}
+
+func (s *clientSuite) TestActionWebhookError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(
+		objType string, version int,
+		id, request string,
+		args, response interface{},
+	) error {
+		*response.(*params.ActionWebhookResults) = params.ActionWebhookResults{
+			Results: []params.ActionWebhookResult{{
+				Error: &params.Error{Code: params.CodeNotFound, Message: "not here"},
+			}},
+		}
+		return nil
+	})
+	client := actionwebhooks.NewClient(apiCaller)
+
+	_, err := client.ActionWebhook("op-1")
+	c.Assert(err, jc.Satisfies, params.IsCodeNotFound)
+}
+
+func (s *clientSuite) TestRemoveActionWebhook(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(
+		objType string, version int,
+		id, request string,
+		args, response interface{},
+	) error {
+		c.Check(request, gc.Equals, "RemoveActionWebhooks")
+		c.Check(args, jc.DeepEquals, params.ActionWebhookOperations{
+			Operations: []string{"op-1"},
+		})
+		*response.(*params.ErrorResults) = params.ErrorResults{
+			Results: []params.ErrorResult{{Error: &params.Error{Message: "boom"}}},
+		}
+		return nil
+	})
+	client := actionwebhooks.NewClient(apiCaller)
+
+	err := client.RemoveActionWebhook("op-1")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
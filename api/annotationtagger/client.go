@@ -0,0 +1,33 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package annotationtagger
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client provides access to the AnnotationTagger API facade.
+type Client struct {
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client-side AnnotationTagger facade.
+func NewClient(caller base.APICaller) *Client {
+	return &Client{
+		facade: base.NewFacadeCaller(caller, "AnnotationTagger"),
+	}
+}
+
+// InstanceTags returns the provider tags mirrored from the annotations
+// of each provisioned machine in the model and its units.
+func (c *Client) InstanceTags() ([]params.InstanceTagsResult, error) {
+	var results params.InstanceTagsResults
+	if err := c.facade.FacadeCall("InstanceTags", nil, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results.Results, nil
+}
@@ -0,0 +1,49 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package annotationtagger_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/annotationtagger"
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/apiserver/params"
+)
+
+type clientSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&clientSuite{})
+
+func (s *clientSuite) TestInstanceTags(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(
+		objType string, version int,
+		id, request string,
+		args, response interface{},
+	) error {
+		c.Check(objType, gc.Equals, "AnnotationTagger")
+		c.Check(request, gc.Equals, "InstanceTags")
+		c.Check(args, gc.IsNil)
+		*response.(*params.InstanceTagsResults) = params.InstanceTagsResults{
+			Results: []params.InstanceTagsResult{{
+				MachineTag: "machine-0",
+				InstanceId: "inst-0",
+				Tags:       map[string]string{"cost-centre": "ops"},
+			}},
+		}
+		return nil
+	})
+	client := annotationtagger.NewClient(apiCaller)
+
+	results, err := client.InstanceTags()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.InstanceTagsResult{{
+		MachineTag: "machine-0",
+		InstanceId: "inst-0",
+		Tags:       map[string]string{"cost-centre": "ops"},
+	}})
+}
@@ -25,8 +25,10 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/utils"
+	"github.com/juju/utils/featureflag"
 	"github.com/juju/utils/parallel"
 	"github.com/juju/version"
+	"go.opencensus.io/trace"
 	"gopkg.in/juju/names.v3"
 	"gopkg.in/macaroon-bakery.v2-unstable/httpbakery"
 	"gopkg.in/macaroon.v2-unstable"
@@ -35,6 +37,8 @@ import (
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/core/tracing"
+	"github.com/juju/juju/feature"
 	"github.com/juju/juju/rpc"
 	"github.com/juju/juju/rpc/jsoncodec"
 	"github.com/juju/juju/utils/proxy"
@@ -60,6 +64,7 @@ var logger = loggo.GetLogger("juju.api")
 
 type rpcConnection interface {
 	Call(req rpc.Request, params, response interface{}) error
+	CallContext(ctx context.Context, req rpc.Request, params, response interface{}) error
 	Dead() <-chan struct{}
 	Close() error
 }
@@ -105,6 +110,10 @@ type state struct {
 	// Login
 	facadeVersions map[string][]int
 
+	// deprecatedFacades holds the facade versions reported by Login
+	// as deprecated.
+	deprecatedFacades []params.DeprecatedFacade
+
 	// pingFacadeVersion is the version to use for the pinger. This is lazily
 	// set at initialization to avoid a race in our tests. See
 	// http://pad.lv/1614732 for more details regarding the race.
@@ -285,6 +294,14 @@ func Open(info *Info, opts DialOpts) (Connection, error) {
 func loginWithContext(ctx context.Context, st *state, info *Info) error {
 	result := make(chan error, 1)
 	go func() {
+		if info.APIKey != "" {
+			result <- st.LoginWithAPIKey(info.APIKey)
+			return
+		}
+		if info.InstanceIdentity != "" {
+			result <- st.LoginWithInstanceIdentity(info.Tag, info.Nonce, info.InstanceIdentity)
+			return
+		}
 		result <- st.Login(info.Tag, info.Password, info.Nonce, info.Macaroons)
 	}()
 	select {
@@ -1170,20 +1187,46 @@ var apiCallRetryStrategy = retry.LimitTime(10*time.Second,
 	},
 )
 
+const (
+	// maxRateLimitRetries is the number of times a call refused for
+	// exceeding the controller's rate limit is tried again.
+	maxRateLimitRetries = 5
+
+	// defaultRateLimitDelay is how long to wait before trying a rate
+	// limited call again, when the controller doesn't say.
+	defaultRateLimitDelay = time.Second
+
+	// maxRateLimitDelay caps how long to wait before trying a rate
+	// limited call again.
+	maxRateLimitDelay = 10 * time.Second
+)
+
 // APICall places a call to the remote machine.
 //
 // This fills out the rpc.Request on the given facade, version for a given
 // object id, and the specific RPC method. It marshalls the Arguments, and will
 // unmarshall the result into the response object that is supplied.
 func (s *state) APICall(facade string, version int, id, method string, args, response interface{}) error {
+	ctx := context.Background()
+	var span *trace.Span
+	if featureflag.Enabled(feature.APITracing) {
+		ctx, span = startCallSpan(ctx, facade, version, method)
+		defer span.End()
+	}
 	for a := retry.Start(apiCallRetryStrategy, s.clock); a.Next(); {
-		err := s.client.Call(rpc.Request{
+		err := s.callWithBackoff(ctx, rpc.Request{
 			Type:    facade,
 			Version: version,
 			Id:      id,
 			Action:  method,
 		}, args, response)
 		if params.ErrCode(err) != params.CodeRetry {
+			if err != nil && span != nil {
+				span.SetStatus(trace.Status{
+					Code:    trace.StatusCodeUnknown,
+					Message: err.Error(),
+				})
+			}
 			return errors.Trace(err)
 		}
 		if !a.More() {
@@ -1193,6 +1236,53 @@ func (s *state) APICall(facade string, version int, id, method string, args, res
 	panic("unreachable")
 }
 
+// callWithBackoff makes an API call, waiting and trying again, for as
+// long as the controller asks, when the call is refused for exceeding
+// the controller's rate limit.
+func (s *state) callWithBackoff(ctx context.Context, req rpc.Request, args, response interface{}) error {
+	for attempt := 0; ; attempt++ {
+		err := s.client.CallContext(ctx, req, args, response)
+		if !params.IsCodeRateLimitExceeded(err) || attempt >= maxRateLimitRetries {
+			return err
+		}
+		delay := rateLimitDelay(err)
+		logger.Debugf("%s.%s call rate limited, trying again in %v", req.Type, req.Action, delay)
+		select {
+		case <-s.clock.After(delay):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+// rateLimitDelay returns how long to wait before trying again a call
+// that failed with the given rate limit error.
+func rateLimitDelay(err error) time.Duration {
+	delay := defaultRateLimitDelay
+	if rpcErr, ok := errors.Cause(err).(*rpc.RequestError); ok {
+		var info params.RateLimitExceededErrorInfo
+		if rpcErr.UnmarshalInfo(&info) == nil && info.RetryAfter > 0 {
+			delay = info.RetryAfter
+		}
+	}
+	if delay > maxRateLimitDelay {
+		delay = maxRateLimitDelay
+	}
+	return delay
+}
+
+// startCallSpan starts a span covering an API call, which is always
+// sampled so that the controller traces the call too.
+func startCallSpan(ctx context.Context, facade string, version int, method string) (context.Context, *trace.Span) {
+	tracing.RegisterLogExporter()
+	ctx, span := trace.StartSpan(ctx, facade+"."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithSampler(trace.AlwaysSample()),
+	)
+	span.AddAttributes(trace.Int64Attribute("version", int64(version)))
+	return ctx, span
+}
+
 func (s *state) Close() error {
 	err := s.client.Close()
 	select {
@@ -1294,6 +1384,32 @@ func (s *state) BestFacadeVersion(facade string) int {
 	return bestVersion(facadeVersions[facade], s.facadeVersions[facade])
 }
 
+// DeprecationWarning describes a deprecated facade version used by the
+// client, which will be removed in the next major release of the server.
+type DeprecationWarning struct {
+	Facade  string
+	Version int
+	Message string
+}
+
+// DeprecationWarnings returns a warning for each facade whose best
+// available version, as reported by BestFacadeVersion, is deprecated
+// by the server.
+func (s *state) DeprecationWarnings() []DeprecationWarning {
+	var warnings []DeprecationWarning
+	for _, f := range s.deprecatedFacades {
+		if s.BestFacadeVersion(f.Name) != f.Version {
+			continue
+		}
+		warnings = append(warnings, DeprecationWarning{
+			Facade:  f.Name,
+			Version: f.Version,
+			Message: f.Message,
+		})
+	}
+	return warnings
+}
+
 // serverRoot returns the cached API server address and port used
 // to login, prefixed with "<URI scheme>://" (usually https).
 func (s *state) serverRoot() string {
@@ -1145,6 +1145,62 @@ func (s *apiclientSuite) TestAPICallRetriesLimit(c *gc.C) {
 	})
 }
 
+func (s *apiclientSuite) TestAPICallRateLimited(c *gc.C) {
+	clock := &fakeClock{}
+	conn := api.NewTestingState(api.TestingStateParams{
+		RPCConnection: newRPCConnection(
+			errors.Trace(&rpc.RequestError{
+				Message: "rate limit exceeded",
+				Code:    params.CodeRateLimitExceeded,
+				Info: params.RateLimitExceededErrorInfo{
+					RetryAfter: 500 * time.Millisecond,
+				}.AsMap(),
+			}),
+			errors.Trace(&rpc.RequestError{
+				Message: "rate limit exceeded",
+				Code:    params.CodeRateLimitExceeded,
+			}),
+		),
+		Clock: clock,
+	})
+
+	err := conn.APICall("facade", 1, "id", "method", nil, nil)
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(clock.waits, jc.DeepEquals, []time.Duration{
+		500 * time.Millisecond,
+		time.Second,
+	})
+}
+
+func (s *apiclientSuite) TestAPICallRateLimitedLimit(c *gc.C) {
+	clock := &fakeClock{}
+	limitError := errors.Trace(&rpc.RequestError{
+		Message: "rate limit exceeded",
+		Code:    params.CodeRateLimitExceeded,
+		Info: params.RateLimitExceededErrorInfo{
+			RetryAfter: time.Minute,
+		}.AsMap(),
+	})
+	var errors []error
+	for i := 0; i < 10; i++ {
+		errors = append(errors, limitError)
+	}
+	conn := api.NewTestingState(api.TestingStateParams{
+		RPCConnection: newRPCConnection(errors...),
+		Clock:         clock,
+	})
+
+	err := conn.APICall("facade", 1, "id", "method", nil, nil)
+	c.Check(err, jc.Satisfies, params.IsCodeRateLimitExceeded)
+	c.Check(clock.waits, jc.DeepEquals, []time.Duration{
+		10 * time.Second,
+		10 * time.Second,
+		10 * time.Second,
+		10 * time.Second,
+		10 * time.Second,
+	})
+}
+
 func (s *apiclientSuite) TestPing(c *gc.C) {
 	clock := &fakeClock{}
 	rpcConn := newRPCConnection()
@@ -1300,6 +1356,13 @@ func (f *fakeRPCConnection) Close() error {
 	return nil
 }
 
+func (f *fakeRPCConnection) CallContext(ctx context.Context, req rpc.Request, params, response interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return f.Call(req, params, response)
+}
+
 func (f *fakeRPCConnection) Call(req rpc.Request, params, response interface{}) error {
 	f.stub.AddCall(req.Type+"."+req.Action, req.Version, params)
 	if f.response != nil {
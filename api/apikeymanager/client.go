@@ -0,0 +1,81 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apikeymanager
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/permission"
+)
+
+// Client allows access to the API key manager API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the API key manager api.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "APIKeyManager")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// IssueAPIKey issues a new API key granting the given access to the
+// model. The returned token is passed as the APIKey in api.Info when
+// connecting; it cannot be retrieved again. A zero expires time means
+// the key never expires.
+func (c *Client) IssueAPIKey(model names.ModelTag, access permission.Access, expires time.Time) (params.APIKeyInfo, string, error) {
+	arg := params.IssueAPIKeyArg{
+		ModelTag: model.String(),
+		Access:   string(access),
+	}
+	if !expires.IsZero() {
+		arg.Expires = &expires
+	}
+	args := params.IssueAPIKeysArgs{Keys: []params.IssueAPIKeyArg{arg}}
+	var results params.IssueAPIKeyResults
+	if err := c.facade.FacadeCall("IssueAPIKeys", args, &results); err != nil {
+		return params.APIKeyInfo{}, "", errors.Trace(err)
+	}
+	if n := len(results.Results); n != 1 {
+		return params.APIKeyInfo{}, "", errors.Errorf("expected 1 result, got %d", n)
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return params.APIKeyInfo{}, "", errors.Trace(result.Error)
+	}
+	return *result.Key, result.Token, nil
+}
+
+// ListAPIKeys returns the API keys issued for the model.
+func (c *Client) ListAPIKeys(model names.ModelTag) ([]params.APIKeyInfo, error) {
+	args := params.Entities{Entities: []params.Entity{{Tag: model.String()}}}
+	var results params.ListAPIKeysResults
+	if err := c.facade.FacadeCall("ListAPIKeys", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if n := len(results.Results); n != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", n)
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, errors.Trace(result.Error)
+	}
+	return result.Keys, nil
+}
+
+// RevokeAPIKeys revokes the API keys with the given IDs.
+func (c *Client) RevokeAPIKeys(ids ...string) error {
+	args := params.RevokeAPIKeysArgs{Ids: ids}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("RevokeAPIKeys", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.Combine()
+}
@@ -0,0 +1,122 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apikeymanager_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/apikeymanager"
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/permission"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type APIKeyManagerSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&APIKeyManagerSuite{})
+
+func (s *APIKeyManagerSuite) TestIssueAPIKey(c *gc.C) {
+	expires := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "APIKeyManager")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "IssueAPIKeys")
+			c.Check(a, jc.DeepEquals, params.IssueAPIKeysArgs{
+				Keys: []params.IssueAPIKeyArg{{
+					ModelTag: coretesting.ModelTag.String(),
+					Access:   "write",
+					Expires:  &expires,
+				}},
+			})
+			*(result.(*params.IssueAPIKeyResults)) = params.IssueAPIKeyResults{
+				Results: []params.IssueAPIKeyResult{{
+					Key:   &params.APIKeyInfo{Id: "key-id", Access: "write"},
+					Token: "key-id:secret",
+				}},
+			}
+			return nil
+		})
+	client := apikeymanager.NewClient(apiCaller)
+	info, token, err := client.IssueAPIKey(coretesting.ModelTag, permission.WriteAccess, expires)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info, jc.DeepEquals, params.APIKeyInfo{Id: "key-id", Access: "write"})
+	c.Assert(token, gc.Equals, "key-id:secret")
+}
+
+func (s *APIKeyManagerSuite) TestIssueAPIKeyError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			*(result.(*params.IssueAPIKeyResults)) = params.IssueAPIKeyResults{
+				Results: []params.IssueAPIKeyResult{{
+					Error: common.ServerError(errors.New("boom")),
+				}},
+			}
+			return nil
+		})
+	client := apikeymanager.NewClient(apiCaller)
+	_, _, err := client.IssueAPIKey(coretesting.ModelTag, permission.ReadAccess, time.Time{})
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *APIKeyManagerSuite) TestListAPIKeys(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "APIKeyManager")
+			c.Check(request, gc.Equals, "ListAPIKeys")
+			c.Check(a, jc.DeepEquals, params.Entities{
+				Entities: []params.Entity{{Tag: coretesting.ModelTag.String()}},
+			})
+			*(result.(*params.ListAPIKeysResults)) = params.ListAPIKeysResults{
+				Results: []params.ListAPIKeysResult{{
+					Keys: []params.APIKeyInfo{{Id: "a"}, {Id: "b", Revoked: true}},
+				}},
+			}
+			return nil
+		})
+	client := apikeymanager.NewClient(apiCaller)
+	keys, err := client.ListAPIKeys(coretesting.ModelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keys, jc.DeepEquals, []params.APIKeyInfo{{Id: "a"}, {Id: "b", Revoked: true}})
+}
+
+func (s *APIKeyManagerSuite) TestRevokeAPIKeys(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "APIKeyManager")
+			c.Check(request, gc.Equals, "RevokeAPIKeys")
+			c.Check(a, jc.DeepEquals, params.RevokeAPIKeysArgs{Ids: []string{"a", "b"}})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{}, {Error: common.ServerError(errors.New("nope"))}},
+			}
+			return nil
+		})
+	client := apikeymanager.NewClient(apiCaller)
+	err := client.RevokeAPIKeys("a", "b")
+	c.Assert(err, gc.ErrorMatches, "nope")
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/juju/juju/core/crossmodel"
 	"github.com/juju/juju/core/devices"
 	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/storage"
 )
 
@@ -113,6 +114,10 @@ type DeployArgs struct {
 	// value being the unique ID of a pre-uploaded resources in
 	// storage.
 	Resources map[string]string
+
+	// IdempotencyKey, if set, ensures that a retry of the call with the
+	// same key does not deploy the application again.
+	IdempotencyKey string
 }
 
 // Deploy obtains the charm, either locally or from the charm store, and deploys
@@ -127,6 +132,9 @@ func (c *Client) Deploy(args DeployArgs) error {
 			return errors.New("this juju controller does not support AttachStorage")
 		}
 	}
+	if args.IdempotencyKey != "" && c.BestAPIVersion() < 11 {
+		return errors.NotSupportedf("idempotency keys")
+	}
 	attachStorage := make([]string, len(args.AttachStorage))
 	for i, id := range args.AttachStorage {
 		if !names.IsValidStorage(id) {
@@ -150,6 +158,7 @@ func (c *Client) Deploy(args DeployArgs) error {
 			AttachStorage:    attachStorage,
 			EndpointBindings: args.EndpointBindings,
 			Resources:        args.Resources,
+			IdempotencyKey:   args.IdempotencyKey,
 		}},
 	}
 	var results params.ErrorResults
@@ -399,6 +408,10 @@ type AddUnitsParams struct {
 	// attached to the application unit that will be deployed. This
 	// may be non-empty only if NumUnits is 1.
 	AttachStorage []string
+
+	// IdempotencyKey, if set, ensures that a retry of the call with the
+	// same key returns the units already added instead of adding more.
+	IdempotencyKey string
 }
 
 // AddUnits adds a given number of units to an application using the specified
@@ -412,6 +425,9 @@ func (c *Client) AddUnits(args AddUnitsParams) ([]string, error) {
 			return nil, errors.New("this juju controller does not support AttachStorage")
 		}
 	}
+	if args.IdempotencyKey != "" && c.BestAPIVersion() < 11 {
+		return nil, errors.NotSupportedf("idempotency keys")
+	}
 	attachStorage := make([]string, len(args.AttachStorage))
 	for i, id := range args.AttachStorage {
 		if !names.IsValidStorage(id) {
@@ -426,6 +442,7 @@ func (c *Client) AddUnits(args AddUnitsParams) ([]string, error) {
 		Placement:       args.Placement,
 		Policy:          args.Policy,
 		AttachStorage:   attachStorage,
+		IdempotencyKey:  args.IdempotencyKey,
 	}, results)
 	return results.Units, err
 }
@@ -766,9 +783,18 @@ func (c *Client) SetConstraints(application string, constraints constraints.Valu
 }
 
 // Expose changes the juju-managed firewall to expose any ports that
-// were also explicitly marked by units as open.
-func (c *Client) Expose(application string) error {
-	args := params.ApplicationExpose{ApplicationName: application}
+// were also explicitly marked by units as open. If exposedEndpoints
+// is non-empty, the sources allowed to reach each named endpoint are
+// merged into the application's existing expose settings; the empty
+// endpoint name refers to every endpoint of the application.
+func (c *Client) Expose(application string, exposedEndpoints map[string]params.ExposedEndpoint) error {
+	if len(exposedEndpoints) > 0 && c.BestAPIVersion() < 11 {
+		return errors.NotSupportedf("exposing endpoints to specific spaces or CIDRs")
+	}
+	args := params.ApplicationExpose{
+		ApplicationName:  application,
+		ExposedEndpoints: exposedEndpoints,
+	}
 	return c.facade.FacadeCall("Expose", args, nil)
 }
 
@@ -939,6 +965,61 @@ func (c *Client) UnsetApplicationConfig(branchName, application string, options
 	return results.OneError()
 }
 
+// SetEgressRules replaces the egress rules of an application. Setting
+// no rules removes any restriction on outgoing traffic.
+func (c *Client) SetEgressRules(application string, rules []network.EgressRule) error {
+	if c.BestAPIVersion() < 11 {
+		return errors.NotSupportedf("SetEgressRules not supported by this version of Juju")
+	}
+	arg := params.ApplicationEgressRules{
+		ApplicationTag: names.NewApplicationTag(application).String(),
+		Rules:          make([]params.EgressRule, len(rules)),
+	}
+	for i, rule := range rules {
+		arg.Rules[i] = params.EgressRule{
+			PortRange:        params.FromNetworkPortRange(rule.PortRange),
+			DestinationCIDRs: rule.DestinationCIDRs,
+		}
+	}
+	args := params.ApplicationEgressRulesArgs{Args: []params.ApplicationEgressRules{arg}}
+	var results params.ErrorResults
+	err := c.facade.FacadeCall("SetEgressRules", args, &results)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// EgressRules returns the egress rules of an application.
+func (c *Client) EgressRules(application string) ([]network.EgressRule, error) {
+	if c.BestAPIVersion() < 11 {
+		return nil, errors.NotSupportedf("EgressRules not supported by this version of Juju")
+	}
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: names.NewApplicationTag(application).String()}},
+	}
+	var results params.ApplicationEgressRulesResults
+	err := c.facade.FacadeCall("EgressRules", args, &results)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	var rules []network.EgressRule
+	for _, rule := range result.Rules {
+		rules = append(rules, network.EgressRule{
+			PortRange:        rule.PortRange.NetworkPortRange(),
+			DestinationCIDRs: rule.DestinationCIDRs,
+		})
+	}
+	return rules, nil
+}
+
 // ResolveUnitErrors clears errors on one or more units.
 // Either specify one or more units, or all.
 func (c *Client) ResolveUnitErrors(units []string, all, retry bool) error {
@@ -971,6 +1052,49 @@ func (c *Client) ResolveUnitErrors(units []string, all, retry bool) error {
 	return errors.Trace(results.Combine())
 }
 
+// RotateUnitPasswords requests that the agents of the given units be
+// given new passwords by the deployers responsible for them.
+func (c *Client) RotateUnitPasswords(units ...string) error {
+	if c.BestAPIVersion() < 11 {
+		return errors.NotSupportedf("RotateUnitPasswords not supported by this version of Juju")
+	}
+	entities := make([]params.Entity, len(units))
+	for i, unit := range units {
+		if !names.IsValidUnit(unit) {
+			return errors.NotValidf("unit name %q", unit)
+		}
+		entities[i].Tag = names.NewUnitTag(unit).String()
+	}
+	var results params.ErrorResults
+	err := c.facade.FacadeCall("RotateUnitPasswords", params.Entities{Entities: entities}, &results)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(results.Combine())
+}
+
+// SetSubordinatePolicy sets the placement policy of a subordinate
+// application. A policy with no restrictions removes any existing one.
+func (c *Client) SetSubordinatePolicy(application string, principals []string, machineSelector map[string]string, maxPerMachine int) error {
+	if c.BestAPIVersion() < 11 {
+		return errors.NotSupportedf("SetSubordinatePolicy not supported by this version of Juju")
+	}
+	args := params.ApplicationSubordinatePolicies{
+		Policies: []params.ApplicationSubordinatePolicy{{
+			ApplicationTag:  names.NewApplicationTag(application).String(),
+			Principals:      principals,
+			MachineSelector: machineSelector,
+			MaxPerMachine:   maxPerMachine,
+		}},
+	}
+	var results params.ErrorResults
+	err := c.facade.FacadeCall("SetSubordinatePolicies", args, &results)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
 func validateApplicationScale(scale, scaleChange int) error {
 	if scale < 0 && scaleChange == 0 {
 		return errors.NotValidf("scale < 0")
@@ -23,6 +23,7 @@ import (
 	"github.com/juju/juju/core/crossmodel"
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/model"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/storage"
 	coretesting "github.com/juju/juju/testing"
 )
@@ -199,6 +200,50 @@ func (s *applicationSuite) TestAddUnits(c *gc.C) {
 	c.Assert(units, jc.DeepEquals, []string{"foo/0"})
 }
 
+func (s *applicationSuite) TestAddUnitsIdempotencyKey(c *gc.C) {
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Assert(request, gc.Equals, "AddUnits")
+				args, ok := a.(params.AddApplicationUnits)
+				c.Assert(ok, jc.IsTrue)
+				c.Assert(args.IdempotencyKey, gc.Equals, "key-1")
+				result := response.(*params.AddApplicationUnitsResults)
+				result.Units = []string{"foo/0"}
+				return nil
+			},
+		),
+		BestVersion: 11,
+	})
+
+	units, err := client.AddUnits(application.AddUnitsParams{
+		ApplicationName: "foo",
+		NumUnits:        1,
+		IdempotencyKey:  "key-1",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, jc.DeepEquals, []string{"foo/0"})
+}
+
+func (s *applicationSuite) TestAddUnitsIdempotencyKeyNotSupported(c *gc.C) {
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Fatalf("unexpected call to %s", request)
+				return nil
+			},
+		),
+		BestVersion: 10,
+	})
+
+	_, err := client.AddUnits(application.AddUnitsParams{
+		ApplicationName: "foo",
+		NumUnits:        1,
+		IdempotencyKey:  "key-1",
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 func (s *applicationSuite) TestAddUnitsAttachStorageV4(c *gc.C) {
 	var called bool
 	client := application.NewClient(basetesting.BestVersionCaller{
@@ -1559,3 +1604,198 @@ func (s *applicationSuite) TestApplicationsInfoResultMismatch(c *gc.C) {
 	c.Check(called, jc.IsTrue)
 	c.Assert(err, gc.ErrorMatches, "expected 2 results, got 3")
 }
+
+func (s *applicationSuite) TestSetEgressRules(c *gc.C) {
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Assert(request, gc.Equals, "SetEgressRules")
+				c.Assert(a, jc.DeepEquals, params.ApplicationEgressRulesArgs{
+					Args: []params.ApplicationEgressRules{{
+						ApplicationTag: "application-foo",
+						Rules: []params.EgressRule{{
+							PortRange:        params.PortRange{FromPort: 443, ToPort: 443, Protocol: "tcp"},
+							DestinationCIDRs: []string{"10.0.0.0/8"},
+						}},
+					}}})
+				result, ok := response.(*params.ErrorResults)
+				c.Assert(ok, jc.IsTrue)
+				result.Results = []params.ErrorResult{
+					{Error: &params.Error{Message: "FAIL"}},
+				}
+				return nil
+			},
+		),
+		BestVersion: 11,
+	})
+
+	err := client.SetEgressRules("foo", []network.EgressRule{
+		network.MustNewEgressRule("tcp", 443, 443, "10.0.0.0/8"),
+	})
+	c.Assert(err, gc.ErrorMatches, "FAIL")
+}
+
+func (s *applicationSuite) TestRotateUnitPasswords(c *gc.C) {
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Assert(request, gc.Equals, "RotateUnitPasswords")
+				c.Assert(a, jc.DeepEquals, params.Entities{
+					Entities: []params.Entity{{Tag: "unit-foo-0"}, {Tag: "unit-foo-1"}},
+				})
+				result, ok := response.(*params.ErrorResults)
+				c.Assert(ok, jc.IsTrue)
+				result.Results = []params.ErrorResult{
+					{}, {Error: &params.Error{Message: "FAIL"}},
+				}
+				return nil
+			},
+		),
+		BestVersion: 11,
+	})
+
+	err := client.RotateUnitPasswords("foo/0", "foo/1")
+	c.Assert(err, gc.ErrorMatches, "FAIL")
+}
+
+func (s *applicationSuite) TestRotateUnitPasswordsNotSupported(c *gc.C) {
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Fatalf("unexpected call to %q", request)
+				return nil
+			},
+		),
+		BestVersion: 10,
+	})
+
+	err := client.RotateUnitPasswords("foo/0")
+	c.Assert(err, gc.ErrorMatches, "RotateUnitPasswords not supported by this version of Juju")
+}
+
+func (s *applicationSuite) TestSetSubordinatePolicy(c *gc.C) {
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Assert(request, gc.Equals, "SetSubordinatePolicies")
+				c.Assert(a, jc.DeepEquals, params.ApplicationSubordinatePolicies{
+					Policies: []params.ApplicationSubordinatePolicy{{
+						ApplicationTag:  "application-foo",
+						Principals:      []string{"bar"},
+						MachineSelector: map[string]string{"logging": "enabled"},
+						MaxPerMachine:   1,
+					}},
+				})
+				result, ok := response.(*params.ErrorResults)
+				c.Assert(ok, jc.IsTrue)
+				result.Results = []params.ErrorResult{
+					{Error: &params.Error{Message: "FAIL"}},
+				}
+				return nil
+			},
+		),
+		BestVersion: 11,
+	})
+
+	err := client.SetSubordinatePolicy("foo", []string{"bar"}, map[string]string{"logging": "enabled"}, 1)
+	c.Assert(err, gc.ErrorMatches, "FAIL")
+}
+
+func (s *applicationSuite) TestSetSubordinatePolicyNotSupported(c *gc.C) {
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Fatalf("unexpected call to %q", request)
+				return nil
+			},
+		),
+		BestVersion: 10,
+	})
+
+	err := client.SetSubordinatePolicy("foo", nil, nil, 1)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *applicationSuite) TestEgressRules(c *gc.C) {
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Assert(request, gc.Equals, "EgressRules")
+				c.Assert(a, jc.DeepEquals, params.Entities{
+					Entities: []params.Entity{{Tag: "application-foo"}},
+				})
+				result, ok := response.(*params.ApplicationEgressRulesResults)
+				c.Assert(ok, jc.IsTrue)
+				result.Results = []params.ApplicationEgressRulesResult{{
+					Rules: []params.EgressRule{{
+						PortRange: params.PortRange{FromPort: 53, ToPort: 53, Protocol: "udp"},
+					}},
+				}}
+				return nil
+			},
+		),
+		BestVersion: 11,
+	})
+
+	rules, err := client.EgressRules("foo")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, []network.EgressRule{network.MustNewEgressRule("udp", 53, 53)})
+}
+
+func (s *applicationSuite) TestEgressRulesNotSupported(c *gc.C) {
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Fail()
+				return nil
+			}),
+		BestVersion: 10,
+	})
+	err := client.SetEgressRules("foo", nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	_, err = client.EgressRules("foo")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *applicationSuite) TestExposeEndpoints(c *gc.C) {
+	exposedEndpoints := map[string]params.ExposedEndpoint{
+		"db": {
+			ExposeToSpaces: []string{"internal"},
+			ExposeToCIDRs:  []string{"10.0.0.0/24"},
+		},
+	}
+	called := false
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				called = true
+				c.Assert(request, gc.Equals, "Expose")
+				c.Assert(a, jc.DeepEquals, params.ApplicationExpose{
+					ApplicationName:  "foo",
+					ExposedEndpoints: exposedEndpoints,
+				})
+				return nil
+			},
+		),
+		BestVersion: 11,
+	})
+
+	err := client.Expose("foo", exposedEndpoints)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *applicationSuite) TestExposeEndpointsNotSupported(c *gc.C) {
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Fail()
+				return nil
+			}),
+		BestVersion: 10,
+	})
+	err := client.Expose("foo", map[string]params.ExposedEndpoint{
+		"": {ExposeToCIDRs: []string{"10.0.0.0/24"}},
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
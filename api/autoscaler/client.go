@@ -0,0 +1,82 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package autoscaler provides a client for the Autoscaler facade, which
+// lets external autoscalers change the number of units of applications
+// without racing each other.
+package autoscaler
+
+import (
+	"sort"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client allows access to the autoscaler API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the autoscaler API.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "Autoscaler")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// Plan returns the plans to bring each of the named applications to the
+// given number of units, ordered by application name. The plans are not
+// applied.
+func (c *Client) Plan(scales map[string]int) ([]params.ScalePlan, error) {
+	appNames := make([]string, 0, len(scales))
+	for appName := range scales {
+		if !names.IsValidApplication(appName) {
+			return nil, errors.NotValidf("application name %q", appName)
+		}
+		appNames = append(appNames, appName)
+	}
+	sort.Strings(appNames)
+	args := params.ScaleApplicationsParams{
+		Applications: make([]params.ScaleApplicationParams, len(appNames)),
+	}
+	for i, appName := range appNames {
+		args.Applications[i] = params.ScaleApplicationParams{
+			ApplicationTag: names.NewApplicationTag(appName).String(),
+			Scale:          scales[appName],
+		}
+	}
+	var results params.ScalePlanResults
+	if err := c.facade.FacadeCall("Plan", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != len(appNames) {
+		return nil, errors.Errorf("expected %d results, got %d", len(appNames), len(results.Results))
+	}
+	plans := make([]params.ScalePlan, len(appNames))
+	for i, result := range results.Results {
+		if result.Error != nil {
+			return nil, errors.Annotatef(result.Error, "planning %q", appNames[i])
+		}
+		plans[i] = *result.Result
+	}
+	return plans, nil
+}
+
+// Apply applies the plans returned by Plan. If token is not empty, plans
+// already applied with the same token are not applied again, so the call
+// can be retried safely.
+func (c *Client) Apply(token string, plans []params.ScalePlan) (params.ApplyScalePlansResult, error) {
+	args := params.ApplyScalePlansParams{
+		Token: token,
+		Plans: plans,
+	}
+	var result params.ApplyScalePlansResult
+	if err := c.facade.FacadeCall("Apply", args, &result); err != nil {
+		return params.ApplyScalePlansResult{}, errors.Trace(err)
+	}
+	return result, nil
+}
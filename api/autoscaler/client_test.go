@@ -0,0 +1,101 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package autoscaler_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/autoscaler"
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type autoscalerSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&autoscalerSuite{})
+
+func (s *autoscalerSuite) TestPlan(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "Autoscaler")
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "Plan")
+		c.Check(arg, jc.DeepEquals, params.ScaleApplicationsParams{
+			Applications: []params.ScaleApplicationParams{
+				{ApplicationTag: "application-mysql", Scale: 1},
+				{ApplicationTag: "application-wordpress", Scale: 3},
+			},
+		})
+		*(result.(*params.ScalePlanResults)) = params.ScalePlanResults{
+			Results: []params.ScalePlanResult{{
+				Result: &params.ScalePlan{
+					ApplicationTag: "application-mysql",
+					CurrentScale:   2,
+					Scale:          1,
+					RemoveUnits:    []string{"mysql/1"},
+				},
+			}, {
+				Result: &params.ScalePlan{
+					ApplicationTag: "application-wordpress",
+					CurrentScale:   1,
+					Scale:          3,
+					AddUnits:       2,
+				},
+			}},
+		}
+		return nil
+	})
+	client := autoscaler.NewClient(apiCaller)
+	plans, err := client.Plan(map[string]int{"wordpress": 3, "mysql": 1})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(plans, gc.HasLen, 2)
+	c.Assert(plans[0].RemoveUnits, jc.DeepEquals, []string{"mysql/1"})
+	c.Assert(plans[1].AddUnits, gc.Equals, 2)
+}
+
+func (s *autoscalerSuite) TestPlanError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		*(result.(*params.ScalePlanResults)) = params.ScalePlanResults{
+			Results: []params.ScalePlanResult{{
+				Error: &params.Error{Message: `application "mysql" not found`},
+			}},
+		}
+		return nil
+	})
+	client := autoscaler.NewClient(apiCaller)
+	_, err := client.Plan(map[string]int{"mysql": 1})
+	c.Assert(err, gc.ErrorMatches, `planning "mysql": application "mysql" not found`)
+}
+
+func (s *autoscalerSuite) TestApply(c *gc.C) {
+	plans := []params.ScalePlan{{
+		ApplicationTag: "application-wordpress",
+		CurrentScale:   1,
+		Scale:          3,
+		AddUnits:       2,
+	}}
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "Autoscaler")
+		c.Check(request, gc.Equals, "Apply")
+		c.Check(arg, jc.DeepEquals, params.ApplyScalePlansParams{
+			Token: "scale-1",
+			Plans: plans,
+		})
+		*(result.(*params.ApplyScalePlansResult)) = params.ApplyScalePlansResult{
+			AddedUnits:     []string{"wordpress/1", "wordpress/2"},
+			AlreadyApplied: true,
+		}
+		return nil
+	})
+	client := autoscaler.NewClient(apiCaller)
+	result, err := client.Apply("scale-1", plans)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ApplyScalePlansResult{
+		AddedUnits:     []string{"wordpress/1", "wordpress/2"},
+		AlreadyApplied: true,
+	})
+}
@@ -19,6 +19,7 @@ import (
 type UserModel struct {
 	Name           string
 	UUID           string
+	Alias          string
 	Type           model.ModelType
 	Owner          string
 	LastConnection *time.Time
@@ -57,6 +58,7 @@ type Machine struct {
 type ModelInfo struct {
 	Name            string
 	UUID            string
+	Alias           string
 	Type            model.ModelType
 	ControllerUUID  string
 	IsController    bool
@@ -111,6 +113,7 @@ type Filesystem struct {
 type UserModelSummary struct {
 	Name               string
 	UUID               string
+	Alias              string
 	Type               model.ModelType
 	ControllerUUID     string
 	IsController       bool
@@ -4,6 +4,8 @@
 package caasoperator
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/version"
 	"gopkg.in/juju/charm.v6"
@@ -13,6 +15,7 @@ import (
 	"github.com/juju/juju/api/common"
 	apiwatcher "github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/lease"
 	"github.com/juju/juju/core/life"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/core/status"
@@ -263,3 +266,59 @@ func (c *Client) SetVersion(appName string, v version.Binary) error {
 	}
 	return results.OneError()
 }
+
+// ClaimOperator attempts to claim, on behalf of the named pod, the right
+// to run the operator of the specified application for the supplied
+// duration. If the claim is denied, it will return lease.ErrClaimDenied.
+// If the controller does not support operator claims, it will return an
+// error satisfying errors.IsNotSupported.
+func (c *Client) ClaimOperator(application, holder string, duration time.Duration) error {
+	if c.facade.BestAPIVersion() < 2 {
+		return errors.NotSupportedf("operator claims")
+	}
+	tag, err := c.appTag(application)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	args := params.OperatorClaims{
+		Claims: []params.OperatorClaim{{
+			ApplicationTag: tag.String(),
+			Holder:         holder,
+			Duration:       duration,
+		}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("ClaimOperator", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	err = results.OneError()
+	switch {
+	case err == nil:
+		return nil
+	case params.IsCodeLeaseClaimDenied(err):
+		return lease.ErrClaimDenied
+	case params.IsCodeNotSupported(err):
+		return errors.NewNotSupported(err, "")
+	}
+	return errors.Trace(err)
+}
+
+// WaitOperatorReleased blocks until no pod has the right to run the
+// operator of the specified application.
+func (c *Client) WaitOperatorReleased(application string) error {
+	if c.facade.BestAPIVersion() < 2 {
+		return errors.NotSupportedf("operator claims")
+	}
+	tag, err := c.appTag(application)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: tag.String()}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("WaitOperatorReleased", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
@@ -4,6 +4,8 @@
 package caasoperator_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
@@ -14,6 +16,7 @@ import (
 	basetesting "github.com/juju/juju/api/base/testing"
 	"github.com/juju/juju/api/caasoperator"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/lease"
 	"github.com/juju/juju/core/life"
 	"github.com/juju/juju/core/model"
 )
@@ -331,3 +334,68 @@ func (s *operatorSuite) TestSetVersionInvalidApplicationName(c *gc.C) {
 	err := client.SetVersion("", version.Binary{})
 	c.Assert(err, gc.ErrorMatches, `application name "" not valid`)
 }
+
+func (s *operatorSuite) TestClaimOperator(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(objType, gc.Equals, "CAASOperator")
+			c.Check(version, gc.Equals, 2)
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "ClaimOperator")
+			c.Check(arg, jc.DeepEquals, params.OperatorClaims{
+				Claims: []params.OperatorClaim{{
+					ApplicationTag: "application-gitlab",
+					Holder:         "gitlab-operator-0",
+					Duration:       time.Minute,
+				}},
+			})
+			c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{Error: &params.Error{
+					Code:    params.CodeLeaseClaimDenied,
+					Message: "lease claim denied",
+				}}},
+			}
+			return nil
+		},
+		BestVersion: 2,
+	}
+
+	client := caasoperator.NewClient(apiCaller)
+	err := client.ClaimOperator("gitlab", "gitlab-operator-0", time.Minute)
+	c.Assert(err, gc.Equals, lease.ErrClaimDenied)
+}
+
+func (s *operatorSuite) TestClaimOperatorNotSupported(c *gc.C) {
+	client := caasoperator.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: func(_ string, _ int, _, _ string, _, _ interface{}) error {
+			return errors.New("should not be called")
+		},
+		BestVersion: 1,
+	})
+	err := client.ClaimOperator("gitlab", "gitlab-operator-0", time.Minute)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *operatorSuite) TestWaitOperatorReleased(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(objType, gc.Equals, "CAASOperator")
+			c.Check(version, gc.Equals, 2)
+			c.Check(request, gc.Equals, "WaitOperatorReleased")
+			c.Check(arg, jc.DeepEquals, params.Entities{
+				Entities: []params.Entity{{Tag: "application-gitlab"}},
+			})
+			c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{}},
+			}
+			return nil
+		},
+		BestVersion: 2,
+	}
+
+	client := caasoperator.NewClient(apiCaller)
+	err := client.WaitOperatorReleased("gitlab")
+	c.Assert(err, jc.ErrorIsNil)
+}
@@ -9,26 +9,78 @@ import (
 	"gopkg.in/juju/names.v3"
 
 	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/common"
 	apiwatcher "github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/life"
+	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/core/watcher"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/storage"
 )
 
 // Client allows access to the CAAS operator provisioner API endpoint.
 type Client struct {
-	facade base.FacadeCaller
+	facade       base.FacadeCaller
+	modelWatcher *common.ModelWatcher
 }
 
 // NewClient returns a client used to access the CAAS Operator Provisioner API.
 func NewClient(caller base.APICaller) *Client {
 	facadeCaller := base.NewFacadeCaller(caller, "CAASOperatorProvisioner")
 	return &Client{
-		facade: facadeCaller,
+		facade:       facadeCaller,
+		modelWatcher: common.NewModelWatcher(facadeCaller),
 	}
 }
 
+// WatchForModelConfigChanges returns a NotifyWatcher waiting for the
+// model configuration to change.
+func (c *Client) WatchForModelConfigChanges() (watcher.NotifyWatcher, error) {
+	if c.facade.BestAPIVersion() < 2 {
+		return nil, errors.NotSupportedf("watching model config")
+	}
+	return c.modelWatcher.WatchForModelConfigChanges()
+}
+
+// ModelConfig returns the current model configuration.
+func (c *Client) ModelConfig() (*config.Config, error) {
+	if c.facade.BestAPIVersion() < 2 {
+		return nil, errors.NotSupportedf("getting model config")
+	}
+	return c.modelWatcher.ModelConfig()
+}
+
+// OperatorVersion returns the agent version reported by the operator
+// of the specified application, or the zero version if the operator
+// hasn't reported one yet.
+func (c *Client) OperatorVersion(appName string) (version.Number, error) {
+	if c.facade.BestAPIVersion() < 2 {
+		return version.Zero, errors.NotSupportedf("getting operator versions")
+	}
+	if !names.IsValidApplication(appName) {
+		return version.Zero, errors.NotValidf("application name %q", appName)
+	}
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: names.NewApplicationTag(appName).String()}},
+	}
+	var results params.VersionResults
+	if err := c.facade.FacadeCall("OperatorVersions", args, &results); err != nil {
+		return version.Zero, errors.Trace(err)
+	}
+	if n := len(results.Results); n != 1 {
+		return version.Zero, errors.Errorf("expected 1 result, got %d", n)
+	}
+	result := results.Results[0]
+	if err := result.Error; err != nil {
+		return version.Zero, maybeNotFound(err)
+	}
+	if result.Version == nil {
+		return version.Zero, nil
+	}
+	return *result.Version, nil
+}
+
 // WatchApplications returns a StringsWatcher that notifies of
 // changes to the lifecycles of CAAS applications in the current model.
 func (c *Client) WatchApplications() (watcher.StringsWatcher, error) {
@@ -101,29 +153,98 @@ func (c *Client) Life(appName string) (life.Value, error) {
 	return life.Value(results.Results[0].Life), nil
 }
 
+// SetOperatorStatus sets the operator status of the specified application.
+func (c *Client) SetOperatorStatus(appName string, s status.Status, message string, data map[string]interface{}) error {
+	if c.facade.BestAPIVersion() < 2 {
+		return errors.NotSupportedf("setting operator status")
+	}
+	if !names.IsValidApplication(appName) {
+		return errors.NotValidf("application name %q", appName)
+	}
+	args := params.SetStatus{
+		Entities: []params.EntityStatusArgs{{
+			Tag:    names.NewApplicationTag(appName).String(),
+			Status: s.String(),
+			Info:   message,
+			Data:   data,
+		}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("SetOperatorStatus", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
 // OperatorProvisioningInfo holds the info needed to provision an operator.
 type OperatorProvisioningInfo struct {
-	ImagePath    string
-	Version      version.Number
-	APIAddresses []string
-	Tags         map[string]string
-	CharmStorage storage.KubernetesFilesystemParams
+	// ProvisioningMode is where the application's charm runs. It is
+	// empty, meaning an operator pod, for controllers which only
+	// support operators.
+	ProvisioningMode string
+	ImagePath        string
+	ImageUsername    string
+	ImagePassword    string
+	Version          version.Number
+	APIAddresses     []string
+	Tags             map[string]string
+	CharmStorage     storage.KubernetesFilesystemParams
+	CPU              string
+	Memory           string
+	NodeSelector     []string
+	NodeAffinity     []string
+	Tolerations      []string
+	Replicas         int
 }
 
-// OperatorProvisioningInfo returns the info needed to provision an operator.
-func (c *Client) OperatorProvisioningInfo() (OperatorProvisioningInfo, error) {
-	var result params.OperatorProvisioningInfo
-	if err := c.facade.FacadeCall("OperatorProvisioningInfo", nil, &result); err != nil {
-		return OperatorProvisioningInfo{}, err
+// OperatorProvisioningInfo returns the info needed to provision the
+// operator of the specified application. Controllers that predate
+// per-application operator storage return the same info for every
+// application.
+func (c *Client) OperatorProvisioningInfo(appName string) (OperatorProvisioningInfo, error) {
+	if c.facade.BestAPIVersion() < 2 {
+		var result params.OperatorProvisioningInfo
+		if err := c.facade.FacadeCall("OperatorProvisioningInfo", nil, &result); err != nil {
+			return OperatorProvisioningInfo{}, err
+		}
+		return operatorProvisioningInfoFromParams(result), nil
+	}
+	if !names.IsValidApplication(appName) {
+		return OperatorProvisioningInfo{}, errors.NotValidf("application name %q", appName)
 	}
-	info := OperatorProvisioningInfo{
-		ImagePath:    result.ImagePath,
-		Version:      result.Version,
-		APIAddresses: result.APIAddresses,
-		Tags:         result.Tags,
-		CharmStorage: filesystemFromParams(result.CharmStorage),
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: names.NewApplicationTag(appName).String()}},
+	}
+	var results params.OperatorProvisioningInfoResults
+	if err := c.facade.FacadeCall("OperatorProvisioningInfo", args, &results); err != nil {
+		return OperatorProvisioningInfo{}, errors.Trace(err)
+	}
+	if n := len(results.Results); n != 1 {
+		return OperatorProvisioningInfo{}, errors.Errorf("expected 1 result, got %d", n)
+	}
+	if err := results.Results[0].Error; err != nil {
+		return OperatorProvisioningInfo{}, maybeNotFound(err)
+	}
+	return operatorProvisioningInfoFromParams(*results.Results[0].Result), nil
+}
+
+func operatorProvisioningInfoFromParams(result params.OperatorProvisioningInfo) OperatorProvisioningInfo {
+	return OperatorProvisioningInfo{
+		ProvisioningMode: result.ProvisioningMode,
+		ImagePath:        result.ImagePath,
+		ImageUsername:    result.ImageUsername,
+		ImagePassword:    result.ImagePassword,
+		Version:          result.Version,
+		APIAddresses:     result.APIAddresses,
+		Tags:             result.Tags,
+		CharmStorage:     filesystemFromParams(result.CharmStorage),
+		CPU:              result.CPU,
+		Memory:           result.Memory,
+		NodeSelector:     result.NodeSelector,
+		NodeAffinity:     result.NodeAffinity,
+		Tolerations:      result.Tolerations,
+		Replicas:         result.Replicas,
 	}
-	return info, nil
 }
 
 func filesystemFromParams(in params.KubernetesFilesystemParams) storage.KubernetesFilesystemParams {
@@ -15,6 +15,7 @@ import (
 	"github.com/juju/juju/api/caasoperatorprovisioner"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/life"
+	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/storage"
 )
 
@@ -155,19 +156,21 @@ func (s *provisionerSuite) TestLifeCount(c *gc.C) {
 	c.Check(err, gc.ErrorMatches, `expected 1 result, got 2`)
 }
 
-func (s *provisionerSuite) OperatorProvisioningInfo(c *gc.C) {
+func (s *provisionerSuite) TestOperatorProvisioningInfoV1(c *gc.C) {
 	vers := version.MustParse("2.99.0")
-	client := newClient(func(objType string, version int, id, request string, a, result interface{}) error {
+	client := caasoperatorprovisioner.NewClient(basetesting.BestVersionCaller{func(objType string, version int, id, request string, a, result interface{}) error {
 		c.Check(objType, gc.Equals, "CAASOperatorProvisioner")
 		c.Check(id, gc.Equals, "")
 		c.Assert(request, gc.Equals, "OperatorProvisioningInfo")
 		c.Assert(a, gc.IsNil)
 		c.Assert(result, gc.FitsTypeOf, &params.OperatorProvisioningInfo{})
 		*(result.(*params.OperatorProvisioningInfo)) = params.OperatorProvisioningInfo{
-			ImagePath:    "juju-operator-image",
-			Version:      vers,
-			APIAddresses: []string{"10.0.0.1:1"},
-			Tags:         map[string]string{"foo": "bar"},
+			ImagePath:     "juju-operator-image",
+			ImageUsername: "fred",
+			ImagePassword: "secret",
+			Version:       vers,
+			APIAddresses:  []string{"10.0.0.1:1"},
+			Tags:          map[string]string{"foo": "bar"},
 			CharmStorage: params.KubernetesFilesystemParams{
 				Size:        10,
 				Provider:    "kubernetes",
@@ -175,16 +178,24 @@ func (s *provisionerSuite) OperatorProvisioningInfo(c *gc.C) {
 				Tags:        map[string]string{"model": "model-tag"},
 				Attributes:  map[string]interface{}{"key": "value"},
 			},
+			CPU:          "500m",
+			Memory:       "256Mi",
+			NodeSelector: []string{"pool=controllers"},
+			NodeAffinity: []string{"zone=a|b"},
+			Tolerations:  []string{"dedicated=juju:NoSchedule"},
+			Replicas:     3,
 		}
 		return nil
-	})
-	info, err := client.OperatorProvisioningInfo()
+	}, 1})
+	info, err := client.OperatorProvisioningInfo("gitlab")
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(info, jc.DeepEquals, caasoperatorprovisioner.OperatorProvisioningInfo{
-		ImagePath:    "juju-operator-image",
-		Version:      vers,
-		APIAddresses: []string{"10.0.0.1:1"},
-		Tags:         map[string]string{"foo": "bar"},
+		ImagePath:     "juju-operator-image",
+		ImageUsername: "fred",
+		ImagePassword: "secret",
+		Version:       vers,
+		APIAddresses:  []string{"10.0.0.1:1"},
+		Tags:          map[string]string{"foo": "bar"},
 		CharmStorage: storage.KubernetesFilesystemParams{
 			Size:         10,
 			Provider:     "kubernetes",
@@ -192,5 +203,155 @@ func (s *provisionerSuite) OperatorProvisioningInfo(c *gc.C) {
 			ResourceTags: map[string]string{"model": "model-tag"},
 			Attributes:   map[string]interface{}{"key": "value"},
 		},
+		CPU:          "500m",
+		Memory:       "256Mi",
+		NodeSelector: []string{"pool=controllers"},
+		NodeAffinity: []string{"zone=a|b"},
+		Tolerations:  []string{"dedicated=juju:NoSchedule"},
+		Replicas:     3,
+	})
+}
+
+func (s *provisionerSuite) TestOperatorProvisioningInfo(c *gc.C) {
+	client := newClient(func(objType string, version int, id, request string, a, result interface{}) error {
+		c.Check(objType, gc.Equals, "CAASOperatorProvisioner")
+		c.Check(id, gc.Equals, "")
+		c.Assert(request, gc.Equals, "OperatorProvisioningInfo")
+		c.Assert(a, jc.DeepEquals, params.Entities{
+			Entities: []params.Entity{{Tag: "application-gitlab"}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.OperatorProvisioningInfoResults{})
+		*(result.(*params.OperatorProvisioningInfoResults)) = params.OperatorProvisioningInfoResults{
+			Results: []params.OperatorProvisioningInfoResult{{
+				Result: &params.OperatorProvisioningInfo{
+					ProvisioningMode: "operator",
+					ImagePath:        "juju-operator-image",
+					CharmStorage: params.KubernetesFilesystemParams{
+						Provider:   "kubernetes",
+						Attributes: map[string]interface{}{"storage-class": "fast"},
+					},
+				},
+			}},
+		}
+		return nil
+	})
+	info, err := client.OperatorProvisioningInfo("gitlab")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.ProvisioningMode, gc.Equals, "operator")
+	c.Assert(info.ImagePath, gc.Equals, "juju-operator-image")
+	c.Assert(info.CharmStorage, jc.DeepEquals, storage.KubernetesFilesystemParams{
+		Provider:   "kubernetes",
+		Attributes: map[string]interface{}{"storage-class": "fast"},
+	})
+}
+
+func (s *provisionerSuite) TestOperatorProvisioningInfoNotFound(c *gc.C) {
+	client := newClient(func(objType string, version int, id, request string, a, result interface{}) error {
+		*(result.(*params.OperatorProvisioningInfoResults)) = params.OperatorProvisioningInfoResults{
+			Results: []params.OperatorProvisioningInfoResult{{
+				Error: &params.Error{Code: params.CodeNotFound, Message: `application "gitlab" not found`},
+			}},
+		}
+		return nil
+	})
+	_, err := client.OperatorProvisioningInfo("gitlab")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *provisionerSuite) TestSetOperatorStatus(c *gc.C) {
+	var called bool
+	client := newClient(func(objType string, version int, id, request string, a, result interface{}) error {
+		called = true
+		c.Check(objType, gc.Equals, "CAASOperatorProvisioner")
+		c.Check(id, gc.Equals, "")
+		c.Assert(request, gc.Equals, "SetOperatorStatus")
+		c.Assert(a, jc.DeepEquals, params.SetStatus{
+			Entities: []params.EntityStatusArgs{{
+				Tag:    "application-app",
+				Status: "error",
+				Info:   "failed to start operator",
+				Data:   map[string]interface{}{"foo": "bar"},
+			}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{Error: &params.Error{Message: "FAIL"}}},
+		}
+		return nil
+	})
+	err := client.SetOperatorStatus("app", status.Error, "failed to start operator", map[string]interface{}{"foo": "bar"})
+	c.Check(err, gc.ErrorMatches, "FAIL")
+	c.Check(called, jc.IsTrue)
+}
+
+func (s *provisionerSuite) TestSetOperatorStatusNotSupported(c *gc.C) {
+	client := caasoperatorprovisioner.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: func(_ string, _ int, _, _ string, _, _ interface{}) error {
+			return errors.New("should not be called")
+		},
+		BestVersion: 1,
+	})
+	err := client.SetOperatorStatus("app", status.Error, "failed", nil)
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *provisionerSuite) TestOperatorVersion(c *gc.C) {
+	vers := version.MustParse("2.6.1")
+	var called bool
+	client := newClient(func(objType string, version int, id, request string, a, result interface{}) error {
+		called = true
+		c.Check(objType, gc.Equals, "CAASOperatorProvisioner")
+		c.Check(id, gc.Equals, "")
+		c.Assert(request, gc.Equals, "OperatorVersions")
+		c.Assert(a, jc.DeepEquals, params.Entities{
+			Entities: []params.Entity{{Tag: "application-app"}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.VersionResults{})
+		*(result.(*params.VersionResults)) = params.VersionResults{
+			Results: []params.VersionResult{{Version: &vers}},
+		}
+		return nil
+	})
+	result, err := client.OperatorVersion("app")
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(called, jc.IsTrue)
+	c.Check(result, gc.Equals, vers)
+}
+
+func (s *provisionerSuite) TestOperatorVersionNotReported(c *gc.C) {
+	client := newClient(func(objType string, version int, id, request string, a, result interface{}) error {
+		*(result.(*params.VersionResults)) = params.VersionResults{
+			Results: []params.VersionResult{{}},
+		}
+		return nil
+	})
+	result, err := client.OperatorVersion("app")
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(result, gc.Equals, version.Zero)
+}
+
+func (s *provisionerSuite) TestOperatorVersionNotFound(c *gc.C) {
+	client := newClient(func(objType string, version int, id, request string, a, result interface{}) error {
+		*(result.(*params.VersionResults)) = params.VersionResults{
+			Results: []params.VersionResult{{Error: &params.Error{Code: params.CodeNotFound}}},
+		}
+		return nil
+	})
+	_, err := client.OperatorVersion("app")
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *provisionerSuite) TestRollingUpgradesNotSupported(c *gc.C) {
+	client := caasoperatorprovisioner.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: func(_ string, _ int, _, _ string, _, _ interface{}) error {
+			return errors.New("should not be called")
+		},
+		BestVersion: 1,
 	})
+	_, err := client.WatchForModelConfigChanges()
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+	_, err = client.ModelConfig()
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+	_, err = client.OperatorVersion("app")
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
 }
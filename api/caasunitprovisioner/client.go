@@ -149,6 +149,23 @@ type DeploymentInfo struct {
 	ServiceType    string
 }
 
+// SidecarInfo holds the info needed to run the charm of an application
+// in sidecar mode, alongside the workload in each pod.
+type SidecarInfo struct {
+	ImagePath     string
+	ImageUsername string
+	ImagePassword string
+	Mounts        []SidecarMount
+}
+
+// SidecarMount holds where an application filesystem is mounted in
+// the charm container.
+type SidecarMount struct {
+	StorageName string
+	MountPoint  string
+	ReadOnly    bool
+}
+
 // ProvisioningInfo holds unit provisioning info.
 type ProvisioningInfo struct {
 	DeploymentInfo DeploymentInfo
@@ -157,6 +174,9 @@ type ProvisioningInfo struct {
 	Filesystems    []storage.KubernetesFilesystemParams
 	Devices        []devices.KubernetesDeviceParams
 	Tags           map[string]string
+
+	// Sidecar is set if the application's charm runs in sidecar mode.
+	Sidecar *SidecarInfo
 }
 
 // ProvisioningInfo returns the provisioning info for the specified CAAS
@@ -190,6 +210,20 @@ func (c *Client) ProvisioningInfo(appName string) (*ProvisioningInfo, error) {
 			ServiceType:    result.DeploymentInfo.ServiceType,
 		}
 	}
+	if result.Sidecar != nil {
+		info.Sidecar = &SidecarInfo{
+			ImagePath:     result.Sidecar.ImagePath,
+			ImageUsername: result.Sidecar.ImageUsername,
+			ImagePassword: result.Sidecar.ImagePassword,
+		}
+		for _, m := range result.Sidecar.Mounts {
+			info.Sidecar.Mounts = append(info.Sidecar.Mounts, SidecarMount{
+				StorageName: m.StorageName,
+				MountPoint:  m.MountPoint,
+				ReadOnly:    m.ReadOnly,
+			})
+		}
+	}
 
 	for _, fs := range result.Filesystems {
 		fsInfo, err := filesystemFromParams(fs)
@@ -72,6 +72,14 @@ func (s *unitprovisionerSuite) TestProvisioningInfo(c *gc.C) {
 							Attributes: map[string]string{"gpu": "nvidia-tesla-p100"},
 						},
 					},
+					Sidecar: &params.KubernetesSidecarInfo{
+						ImagePath: "jujusolutions/jujud-operator:2.6.1",
+						Mounts: []params.KubernetesSidecarMount{{
+							StorageName: "database",
+							MountPoint:  "/path/to/here",
+							ReadOnly:    true,
+						}},
+					},
 				},
 			}},
 		}
@@ -108,6 +116,14 @@ func (s *unitprovisionerSuite) TestProvisioningInfo(c *gc.C) {
 			Count:      3,
 			Attributes: map[string]string{"gpu": "nvidia-tesla-p100"},
 		}},
+		Sidecar: &caasunitprovisioner.SidecarInfo{
+			ImagePath: "jujusolutions/jujud-operator:2.6.1",
+			Mounts: []caasunitprovisioner.SidecarMount{{
+				StorageName: "database",
+				MountPoint:  "/path/to/here",
+				ReadOnly:    true,
+			}},
+		},
 	})
 }
 
@@ -0,0 +1,53 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package catrustupdater
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	apiwatcher "github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/watcher"
+)
+
+// Facade provides access to the CATrustUpdater API facade.
+type Facade struct {
+	caller base.FacadeCaller
+}
+
+// NewFacade creates a new client-side CATrustUpdater facade.
+func NewFacade(caller base.APICaller) *Facade {
+	return &Facade{
+		caller: base.NewFacadeCaller(caller, "CATrustUpdater"),
+	}
+}
+
+// WatchForCATrustBundleChanges returns a NotifyWatcher waiting for the
+// CA trust bundle of the model to change.
+func (f *Facade) WatchForCATrustBundleChanges() (watcher.NotifyWatcher, error) {
+	var result params.NotifyWatchResult
+	err := f.caller.FacadeCall("WatchForCATrustBundleChanges", nil, &result)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return apiwatcher.NewNotifyWatcher(f.caller.RawAPICaller(), result), nil
+}
+
+// CATrustBundle returns the PEM encoded CA certificates of the model,
+// or the empty string if there are none.
+func (f *Facade) CATrustBundle() (string, error) {
+	var result params.StringResult
+	err := f.caller.FacadeCall("CATrustBundle", nil, &result)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if result.Error != nil {
+		return "", result.Error
+	}
+	return result.Result, nil
+}
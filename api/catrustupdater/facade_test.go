@@ -0,0 +1,77 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package catrustupdater_test
+
+import (
+	"errors"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/catrustupdater"
+	"github.com/juju/juju/apiserver/params"
+)
+
+type facadeSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&facadeSuite{})
+
+func (s *facadeSuite) TestCATrustBundle(c *gc.C) {
+	stub := new(testing.Stub)
+	apiCaller := basetesting.APICallerFunc(func(
+		objType string, version int,
+		id, request string,
+		args, response interface{},
+	) error {
+		c.Check(objType, gc.Equals, "CATrustUpdater")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		stub.AddCall(request, args)
+		*response.(*params.StringResult) = params.StringResult{
+			Result: "bundle",
+		}
+		return nil
+	})
+	facade := catrustupdater.NewFacade(apiCaller)
+
+	bundle, err := facade.CATrustBundle()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(bundle, gc.Equals, "bundle")
+	stub.CheckCalls(c, []testing.StubCall{{"CATrustBundle", []interface{}{nil}}})
+}
+
+func (s *facadeSuite) TestCATrustBundleCallError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(
+		objType string, version int,
+		id, request string,
+		args, response interface{},
+	) error {
+		return errors.New("blam")
+	})
+	facade := catrustupdater.NewFacade(apiCaller)
+
+	_, err := facade.CATrustBundle()
+	c.Assert(err, gc.ErrorMatches, "blam")
+}
+
+func (s *facadeSuite) TestCATrustBundleInnerError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(
+		objType string, version int,
+		id, request string,
+		args, response interface{},
+	) error {
+		*response.(*params.StringResult) = params.StringResult{
+			Error: &params.Error{Message: "blam"},
+		}
+		return nil
+	})
+	facade := catrustupdater.NewFacade(apiCaller)
+
+	_, err := facade.CATrustBundle()
+	c.Assert(err, gc.ErrorMatches, "blam")
+}
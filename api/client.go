@@ -47,19 +47,73 @@ type Client struct {
 	st     *state
 }
 
+// statusPageSize is the number of applications, machines, offers and
+// remote applications fetched by each call to FullStatus.
+const statusPageSize = 1000
+
 // Status returns the status of the juju model.
 func (c *Client) Status(patterns []string) (*params.FullStatus, error) {
-	var result params.FullStatus
-	p := params.StatusParams{Patterns: patterns}
-	if err := c.facade.FacadeCall("FullStatus", p, &result); err != nil {
-		return nil, err
+	// The status is fetched a page at a time so that no single
+	// response is too large, however big the model is. Older servers
+	// ignore the page requested and return the whole status at once.
+	p := params.StatusParams{
+		Patterns: patterns,
+		Page:     params.PageParams{Limit: statusPageSize},
+	}
+	var result *params.FullStatus
+	for {
+		var page params.FullStatus
+		if err := c.facade.FacadeCall("FullStatus", p, &page); err != nil {
+			return nil, err
+		}
+		if result == nil {
+			result = &page
+		} else {
+			mergeStatusPage(result, &page)
+		}
+		if page.Page == nil || page.Page.NextCursor == "" {
+			break
+		}
+		p.Page.Cursor = page.Page.NextCursor
 	}
+	result.Page = nil
 	// Older servers don't fill out model type, but
 	// we know a missing type is an "iaas" model.
 	if result.Model.Type == "" {
 		result.Model.Type = model.IAAS.String()
 	}
-	return &result, nil
+	return result, nil
+}
+
+// mergeStatusPage adds the contents of a page of the status to the
+// status fetched so far. The model, controller timestamp and branches
+// of the latest page win; relations may be repeated across pages.
+func mergeStatusPage(status, page *params.FullStatus) {
+	status.Model = page.Model
+	status.ControllerTimestamp = page.ControllerTimestamp
+	status.Branches = page.Branches
+	for id, m := range page.Machines {
+		status.Machines[id] = m
+	}
+	for name, app := range page.Applications {
+		status.Applications[name] = app
+	}
+	for name, app := range page.RemoteApplications {
+		status.RemoteApplications[name] = app
+	}
+	for name, offer := range page.Offers {
+		status.Offers[name] = offer
+	}
+	seen := make(map[int]bool)
+	for _, r := range status.Relations {
+		seen[r.Id] = true
+	}
+	for _, r := range page.Relations {
+		if !seen[r.Id] {
+			status.Relations = append(status.Relations, r)
+			seen[r.Id] = true
+		}
+	}
 }
 
 // StatusHistory retrieves the last <size> results of
@@ -394,6 +394,53 @@ func testMinVer(client *api.Client, t minverTest, c *gc.C) {
 	}
 }
 
+func (s *clientSuite) TestStatusPages(c *gc.C) {
+	client := s.APIState.Client()
+	pages := []params.FullStatus{{
+		Model:        params.ModelStatusInfo{Name: "controller", Type: "iaas"},
+		Machines:     map[string]params.MachineStatus{"0": {Id: "0"}},
+		Applications: map[string]params.ApplicationStatus{"mysql": {Charm: "cs:mysql"}},
+		Relations:    []params.RelationStatus{{Id: 1}},
+		Page:         &params.PageInfo{NextCursor: "next"},
+	}, {
+		Model:        params.ModelStatusInfo{Name: "controller", Type: "iaas"},
+		Machines:     map[string]params.MachineStatus{"1": {Id: "1"}},
+		Applications: map[string]params.ApplicationStatus{"wordpress": {Charm: "cs:wordpress"}},
+		Relations:    []params.RelationStatus{{Id: 1}, {Id: 2}},
+		Page:         &params.PageInfo{},
+	}}
+	var cursors []string
+	cleanup := api.PatchClientFacadeCall(client,
+		func(request string, paramsIn interface{}, response interface{}) error {
+			c.Assert(request, gc.Equals, "FullStatus")
+			args, ok := paramsIn.(params.StatusParams)
+			c.Assert(ok, jc.IsTrue)
+			c.Check(args.Patterns, jc.DeepEquals, []string{"mysql", "wordpress"})
+			c.Check(args.Page.Limit, jc.GreaterThan, 0)
+			cursors = append(cursors, args.Page.Cursor)
+			*(response.(*params.FullStatus)) = pages[len(cursors)-1]
+			return nil
+		},
+	)
+	defer cleanup()
+
+	status, err := client.Status([]string{"mysql", "wordpress"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cursors, jc.DeepEquals, []string{"", "next"})
+	c.Assert(status, jc.DeepEquals, &params.FullStatus{
+		Model: params.ModelStatusInfo{Name: "controller", Type: "iaas"},
+		Machines: map[string]params.MachineStatus{
+			"0": {Id: "0"},
+			"1": {Id: "1"},
+		},
+		Applications: map[string]params.ApplicationStatus{
+			"mysql":     {Charm: "cs:mysql"},
+			"wordpress": {Charm: "cs:wordpress"},
+		},
+		Relations: []params.RelationStatus{{Id: 1}, {Id: 2}},
+	})
+}
+
 func (s *clientSuite) TestOpenURIFound(c *gc.C) {
 	// Use tools download to test OpenURI
 	const toolsVersion = "2.0.0-xenial-ppc64"
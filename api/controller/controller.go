@@ -257,6 +257,113 @@ func (c *Client) ConfigSet(values map[string]interface{}) error {
 	)
 }
 
+// ModelFeatures returns the feature flags enabled for the given model,
+// in addition to those enabled for the whole controller.
+func (c *Client) ModelFeatures(model names.ModelTag) ([]string, error) {
+	if c.BestAPIVersion() < 9 {
+		return nil, errors.NotSupportedf("model feature flags by this version of Juju")
+	}
+	args := params.Entities{Entities: []params.Entity{{Tag: model.String()}}}
+	var results params.StringsResults
+	if err := c.facade.FacadeCall("ModelFeatures", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	if err := results.Results[0].Error; err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results.Results[0].Result, nil
+}
+
+// UpdateModelFeatures enables and disables feature flags for the given
+// model. Flags that are not mentioned are left as they are.
+func (c *Client) UpdateModelFeatures(model names.ModelTag, enable, disable []string) error {
+	if c.BestAPIVersion() < 9 {
+		return errors.NotSupportedf("model feature flags by this version of Juju")
+	}
+	args := params.UpdateModelFeaturesArgs{
+		Args: []params.UpdateModelFeaturesArg{{
+			ModelTag: model.String(),
+			Enable:   enable,
+			Disable:  disable,
+		}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("UpdateModelFeatures", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// ModelStats returns the number and approximate size of the documents
+// the given model has in each collection, as last collected by the
+// controller.
+func (c *Client) ModelStats(model names.ModelTag) (params.ModelStats, error) {
+	if c.BestAPIVersion() < 9 {
+		return params.ModelStats{}, errors.NotSupportedf("model stats by this version of Juju")
+	}
+	args := params.Entities{Entities: []params.Entity{{Tag: model.String()}}}
+	var results params.ModelStatsResults
+	if err := c.facade.FacadeCall("ModelStats", args, &results); err != nil {
+		return params.ModelStats{}, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return params.ModelStats{}, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	if err := results.Results[0].Error; err != nil {
+		return params.ModelStats{}, errors.Trace(err)
+	}
+	return *results.Results[0].Result, nil
+}
+
+// AuditRecords returns the most recent API calls recorded in the
+// controller's audit trail that match the given filter, newest first.
+func (c *Client) AuditRecords(filter params.AuditRecordsFilter) ([]params.AuditRecord, error) {
+	if c.BestAPIVersion() < 9 {
+		return nil, errors.NotSupportedf("audit records by this version of Juju")
+	}
+	var result params.AuditRecordsResult
+	if err := c.facade.FacadeCall("AuditRecords", filter, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Records, nil
+}
+
+// UpgradePreChecks evaluates the checks the controller runs before
+// upgrading, returning the problems found. Nothing is changed.
+func (c *Client) UpgradePreChecks() ([]params.UpgradePreCheckProblem, error) {
+	if c.BestAPIVersion() < 9 {
+		return nil, errors.NotSupportedf("upgrade pre-checks by this version of Juju")
+	}
+	var result params.UpgradePreCheckResults
+	if err := c.facade.FacadeCall("UpgradePreChecks", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Problems, nil
+}
+
+// UpgradeStragglers returns the machine agents in the given model that
+// haven't completed their upgrade steps for the model's agent version.
+func (c *Client) UpgradeStragglers(model names.ModelTag) (params.UpgradeStragglersResult, error) {
+	if c.BestAPIVersion() < 9 {
+		return params.UpgradeStragglersResult{}, errors.NotSupportedf("upgrade stragglers by this version of Juju")
+	}
+	args := params.Entities{Entities: []params.Entity{{Tag: model.String()}}}
+	var results params.UpgradeStragglersResults
+	if err := c.facade.FacadeCall("UpgradeStragglers", args, &results); err != nil {
+		return params.UpgradeStragglersResult{}, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return params.UpgradeStragglersResult{}, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	if err := results.Results[0].Error; err != nil {
+		return params.UpgradeStragglersResult{}, errors.Trace(err)
+	}
+	return results.Results[0], nil
+}
+
 // MigrationSpec holds the details required to start the migration of
 // a single model.
 type MigrationSpec struct {
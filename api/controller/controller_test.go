@@ -10,6 +10,7 @@ import (
 	jujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
+	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v3"
 	"gopkg.in/macaroon.v2-unstable"
@@ -374,6 +375,191 @@ func (s *Suite) TestConfigSet(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "ruth mundy")
 }
 
+func (s *Suite) TestUpdateModelFeatures(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 9,
+		APICallerFunc: func(objType string, version int, id, request string, args, result interface{}) error {
+			c.Assert(objType, gc.Equals, "Controller")
+			c.Assert(request, gc.Equals, "UpdateModelFeatures")
+			c.Assert(args, jc.DeepEquals, params.UpdateModelFeaturesArgs{
+				Args: []params.UpdateModelFeaturesArg{{
+					ModelTag: coretesting.ModelTag.String(),
+					Enable:   []string{"foo"},
+					Disable:  []string{"bar"},
+				}},
+			})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{}},
+			}
+			return nil
+		},
+	}
+	client := controller.NewClient(apiCaller)
+	err := client.UpdateModelFeatures(coretesting.ModelTag, []string{"foo"}, []string{"bar"})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *Suite) TestModelFeatures(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 9,
+		APICallerFunc: func(objType string, version int, id, request string, args, result interface{}) error {
+			c.Assert(request, gc.Equals, "ModelFeatures")
+			c.Assert(args, jc.DeepEquals, params.Entities{
+				Entities: []params.Entity{{Tag: coretesting.ModelTag.String()}},
+			})
+			*(result.(*params.StringsResults)) = params.StringsResults{
+				Results: []params.StringsResult{{Result: []string{"foo"}}},
+			}
+			return nil
+		},
+	}
+	client := controller.NewClient(apiCaller)
+	features, err := client.ModelFeatures(coretesting.ModelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(features, jc.DeepEquals, []string{"foo"})
+}
+
+func (s *Suite) TestModelFeaturesAgainstOlderAPIVersion(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{BestVersion: 8}
+	client := controller.NewClient(apiCaller)
+	_, err := client.ModelFeatures(coretesting.ModelTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	err = client.UpdateModelFeatures(coretesting.ModelTag, []string{"foo"}, nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *Suite) TestModelStats(c *gc.C) {
+	stats := params.ModelStats{
+		ModelTag: coretesting.ModelTag.String(),
+		Collections: []params.CollectionStats{
+			{Collection: "units", Count: 3, Size: 1500},
+		},
+	}
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 9,
+		APICallerFunc: func(objType string, version int, id, request string, args, result interface{}) error {
+			c.Assert(request, gc.Equals, "ModelStats")
+			c.Assert(args, jc.DeepEquals, params.Entities{
+				Entities: []params.Entity{{Tag: coretesting.ModelTag.String()}},
+			})
+			*(result.(*params.ModelStatsResults)) = params.ModelStatsResults{
+				Results: []params.ModelStatsResult{{Result: &stats}},
+			}
+			return nil
+		},
+	}
+	client := controller.NewClient(apiCaller)
+	result, err := client.ModelStats(coretesting.ModelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, stats)
+}
+
+func (s *Suite) TestModelStatsAgainstOlderAPIVersion(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{BestVersion: 8}
+	client := controller.NewClient(apiCaller)
+	_, err := client.ModelStats(coretesting.ModelTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *Suite) TestAuditRecords(c *gc.C) {
+	filter := params.AuditRecordsFilter{
+		UserTag: "user-bob",
+		Limit:   10,
+	}
+	records := []params.AuditRecord{{
+		UserTag: "user-bob",
+		Facade:  "Application",
+		Version: 9,
+		Method:  "Deploy",
+	}}
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 9,
+		APICallerFunc: func(objType string, version int, id, request string, args, result interface{}) error {
+			c.Assert(request, gc.Equals, "AuditRecords")
+			c.Assert(args, jc.DeepEquals, filter)
+			*(result.(*params.AuditRecordsResult)) = params.AuditRecordsResult{
+				Records: records,
+			}
+			return nil
+		},
+	}
+	client := controller.NewClient(apiCaller)
+	result, err := client.AuditRecords(filter)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, records)
+}
+
+func (s *Suite) TestAuditRecordsAgainstOlderAPIVersion(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{BestVersion: 8}
+	client := controller.NewClient(apiCaller)
+	_, err := client.AuditRecords(params.AuditRecordsFilter{})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *Suite) TestUpgradePreChecks(c *gc.C) {
+	problems := []params.UpgradePreCheckProblem{{
+		Check:    "mongo-version",
+		Message:  "mongo 2.4.6 is too old, at least 3.2 is required",
+		Blocking: true,
+	}}
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 9,
+		APICallerFunc: func(objType string, version int, id, request string, args, result interface{}) error {
+			c.Assert(request, gc.Equals, "UpgradePreChecks")
+			c.Assert(args, gc.IsNil)
+			*(result.(*params.UpgradePreCheckResults)) = params.UpgradePreCheckResults{
+				Problems: problems,
+			}
+			return nil
+		},
+	}
+	client := controller.NewClient(apiCaller)
+	result, err := client.UpgradePreChecks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, problems)
+}
+
+func (s *Suite) TestUpgradePreChecksAgainstOlderAPIVersion(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{BestVersion: 8}
+	client := controller.NewClient(apiCaller)
+	_, err := client.UpgradePreChecks()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *Suite) TestUpgradeStragglers(c *gc.C) {
+	stragglers := params.UpgradeStragglersResult{
+		ModelTag:      coretesting.ModelTag.String(),
+		TargetVersion: version.MustParse("2.7.0"),
+		Stragglers: []params.UpgradeStraggler{
+			{Tag: "machine-1", Version: version.MustParse("2.6.10")},
+		},
+	}
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 9,
+		APICallerFunc: func(objType string, version int, id, request string, args, result interface{}) error {
+			c.Assert(request, gc.Equals, "UpgradeStragglers")
+			c.Assert(args, jc.DeepEquals, params.Entities{
+				Entities: []params.Entity{{Tag: coretesting.ModelTag.String()}},
+			})
+			*(result.(*params.UpgradeStragglersResults)) = params.UpgradeStragglersResults{
+				Results: []params.UpgradeStragglersResult{stragglers},
+			}
+			return nil
+		},
+	}
+	client := controller.NewClient(apiCaller)
+	result, err := client.UpgradeStragglers(coretesting.ModelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, stragglers)
+}
+
+func (s *Suite) TestUpgradeStragglersAgainstOlderAPIVersion(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{BestVersion: 8}
+	client := controller.NewClient(apiCaller)
+	_, err := client.UpgradeStragglers(coretesting.ModelTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 func (s *Suite) TestConfigSetAgainstOlderAPIVersion(c *gc.C) {
 	apiCaller := apitesting.BestVersionCaller{BestVersion: 4}
 	client := controller.NewClient(apiCaller)
@@ -0,0 +1,95 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+	"gopkg.in/macaroon.v2-unstable"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// PeerController holds the credentials with which the controller lists
+// a user's models on a peer controller on the user's behalf. Either the
+// password of AuthTag, or macaroons, should be given.
+type PeerController struct {
+	ControllerTag names.ControllerTag
+	AuthTag       names.UserTag
+	Password      string
+	Macaroons     []macaroon.Slice
+}
+
+// WatchModelSummaries returns a ModelSummaryWatcher which reports the
+// summaries of the models that the user can see on this controller and
+// on the given peer controllers. If all is true, and the user is a
+// controller admin, the summaries of all models are reported.
+func (c *Client) WatchModelSummaries(user names.UserTag, all bool, peers ...PeerController) (*ModelSummaryWatcher, error) {
+	if c.BestAPIVersion() < 9 {
+		return nil, errors.NotSupportedf("WatchModelSummaries not supported by this version of Juju")
+	}
+	args := params.WatchModelSummariesArgs{
+		UserTag: user.String(),
+		All:     all,
+		Peers:   make([]params.PeerControllerAuth, len(peers)),
+	}
+	for i, peer := range peers {
+		macsJSON, err := macaroonsToJSON(peer.Macaroons)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		auth := params.PeerControllerAuth{
+			ControllerTag: peer.ControllerTag.String(),
+			Password:      peer.Password,
+			Macaroons:     macsJSON,
+		}
+		if peer.AuthTag.Id() != "" {
+			auth.AuthTag = peer.AuthTag.String()
+		}
+		args.Peers[i] = auth
+	}
+	var result params.ModelSummaryWatcherId
+	if err := c.facade.FacadeCall("WatchModelSummaries", args, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ModelSummaryWatcher{
+		caller: c.facade.RawAPICaller(),
+		id:     result.ModelSummaryWatcherId,
+	}, nil
+}
+
+// ModelSummaryWatcher reports the changes to the summaries of the models
+// that a user can see across controllers.
+type ModelSummaryWatcher struct {
+	caller base.APICaller
+	id     string
+}
+
+// Next returns the changes to the model summaries, and any errors
+// listing the models on peer controllers, since the last call to Next.
+// The first call returns the summaries of all models. Next blocks until
+// there are changes.
+func (w *ModelSummaryWatcher) Next() (params.ModelSummaryWatcherNextResults, error) {
+	var result params.ModelSummaryWatcherNextResults
+	err := w.caller.APICall(
+		"ModelSummaryWatcher",
+		w.caller.BestFacadeVersion("ModelSummaryWatcher"),
+		w.id,
+		"Next",
+		nil, &result,
+	)
+	return result, errors.Trace(err)
+}
+
+// Stop shuts down the ModelSummaryWatcher.
+func (w *ModelSummaryWatcher) Stop() error {
+	return w.caller.APICall(
+		"ModelSummaryWatcher",
+		w.caller.BestFacadeVersion("ModelSummaryWatcher"),
+		w.id,
+		"Stop",
+		nil, nil,
+	)
+}
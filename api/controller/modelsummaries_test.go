@@ -0,0 +1,94 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller_test
+
+import (
+	"encoding/json"
+
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+	"gopkg.in/macaroon.v2-unstable"
+
+	apitesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/controller"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+func (s *Suite) TestWatchModelSummariesPriorV9(c *gc.C) {
+	called := false
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 8,
+		APICallerFunc: func(string, int, string, string, interface{}, interface{}) error {
+			called = true
+			return nil
+		},
+	}
+	client := controller.NewClient(apiCaller)
+	_, err := client.WatchModelSummaries(names.NewUserTag("bob"), false)
+	c.Assert(err, gc.ErrorMatches, "WatchModelSummaries not supported by this version of Juju not supported")
+	c.Assert(called, jc.IsFalse)
+}
+
+func (s *Suite) TestWatchModelSummaries(c *gc.C) {
+	mac, err := macaroon.New([]byte("secret"), []byte("id"), "location")
+	c.Assert(err, jc.ErrorIsNil)
+	macs := []macaroon.Slice{{mac}}
+	macsJSON, err := json.Marshal(macs)
+	c.Assert(err, jc.ErrorIsNil)
+
+	var stub jujutesting.Stub
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 9,
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			stub.AddCall(objType+"."+request, id, arg)
+			switch request {
+			case "WatchModelSummaries":
+				*result.(*params.ModelSummaryWatcherId) = params.ModelSummaryWatcherId{
+					ModelSummaryWatcherId: "42",
+				}
+			case "Next":
+				*result.(*params.ModelSummaryWatcherNextResults) = params.ModelSummaryWatcherNextResults{
+					Changes: []params.ModelSummaryChange{{
+						ControllerTag: coretesting.ControllerTag.String(),
+						ModelTag:      coretesting.ModelTag.String(),
+						Removed:       true,
+					}},
+				}
+			}
+			return nil
+		},
+	}
+	client := controller.NewClient(apiCaller)
+	peerTag := names.NewControllerTag("deadbeef-2bad-500d-9000-4b1d0d06f00d")
+	w, err := client.WatchModelSummaries(names.NewUserTag("bob"), true, controller.PeerController{
+		ControllerTag: peerTag,
+		Macaroons:     macs,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := w.Next()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.Changes, jc.DeepEquals, []params.ModelSummaryChange{{
+		ControllerTag: coretesting.ControllerTag.String(),
+		ModelTag:      coretesting.ModelTag.String(),
+		Removed:       true,
+	}})
+	c.Assert(w.Stop(), jc.ErrorIsNil)
+
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"Controller.WatchModelSummaries", []interface{}{"", params.WatchModelSummariesArgs{
+			UserTag: "user-bob",
+			All:     true,
+			Peers: []params.PeerControllerAuth{{
+				ControllerTag: peerTag.String(),
+				Macaroons:     string(macsJSON),
+			}},
+		}}},
+		{"ModelSummaryWatcher.Next", []interface{}{"42", nil}},
+		{"ModelSummaryWatcher.Stop", []interface{}{"42", nil}},
+	})
+}
@@ -239,3 +239,37 @@ func (s *deployerSuite) TestUnitSetStatus(c *gc.C) {
 		Data:    map[string]interface{}{"foo": "bar"},
 	})
 }
+
+func (s *deployerSuite) TestUnitSetAgentStatus(c *gc.C) {
+	unit, err := s.st.Unit(s.principal.Tag().(names.UnitTag))
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.SetAgentStatus(status.Failed, "agent service not running", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	stateUnit, err := s.BackingState.Unit(unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	sInfo, err := stateUnit.AgentStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sInfo.Status, gc.Equals, status.Failed)
+	c.Assert(sInfo.Message, gc.Equals, "agent service not running")
+}
+
+func (s *deployerSuite) TestUnitPasswordRotationRequested(c *gc.C) {
+	unit, err := s.st.Unit(s.principal.Tag().(names.UnitTag))
+	c.Assert(err, jc.ErrorIsNil)
+	requested, err := unit.PasswordRotationRequested()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(requested, jc.IsFalse)
+
+	err = s.principal.RequestPasswordRotation()
+	c.Assert(err, jc.ErrorIsNil)
+	requested, err = unit.PasswordRotationRequested()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(requested, jc.IsTrue)
+
+	err = unit.SetPassword("phony-12345678901234567890")
+	c.Assert(err, jc.ErrorIsNil)
+	requested, err = unit.PasswordRotationRequested()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(requested, jc.IsFalse)
+}
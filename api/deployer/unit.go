@@ -4,6 +4,7 @@
 package deployer
 
 import (
+	"github.com/juju/errors"
 	"gopkg.in/juju/names.v3"
 
 	"github.com/juju/juju/api/common"
@@ -86,3 +87,45 @@ func (u *Unit) SetStatus(unitStatus status.Status, info string, data map[string]
 	}
 	return result.OneError()
 }
+
+// SetAgentStatus sets the status of the unit's agent.
+func (u *Unit) SetAgentStatus(agentStatus status.Status, info string, data map[string]interface{}) error {
+	if u.st.facade.BestAPIVersion() < 2 {
+		return errors.NotSupportedf("setting unit agent status by this version of Juju")
+	}
+	var result params.ErrorResults
+	args := params.SetStatus{
+		Entities: []params.EntityStatusArgs{
+			{Tag: u.tag.String(), Status: agentStatus.String(), Info: info, Data: data},
+		},
+	}
+	err := u.st.facade.FacadeCall("SetAgentStatus", args, &result)
+	if err != nil {
+		return err
+	}
+	return result.OneError()
+}
+
+// PasswordRotationRequested reports whether the unit's agent is waiting
+// to be given a new password. Setting the password clears the request.
+func (u *Unit) PasswordRotationRequested() (bool, error) {
+	if u.st.facade.BestAPIVersion() < 2 {
+		return false, errors.NotSupportedf("rotating unit passwords by this version of Juju")
+	}
+	var results params.BoolResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("PasswordRotationRequested", args, &results)
+	if err != nil {
+		return false, err
+	}
+	if len(results.Results) != 1 {
+		return false, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.Result, nil
+}
@@ -14,6 +14,7 @@ import (
 	"gopkg.in/macaroon.v2-unstable"
 
 	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/rpc/jsoncodec"
 )
@@ -68,6 +69,7 @@ type TestingStateParams struct {
 	ModelTag       string
 	APIHostPorts   [][]network.HostPort
 	FacadeVersions map[string][]int
+	Deprecated     []params.DeprecatedFacade
 	ServerScheme   string
 	ServerRoot     string
 	RPCConnection  RPCConnection
@@ -94,6 +96,7 @@ func NewTestingState(params TestingStateParams) Connection {
 		modelTag:          modelTag,
 		hostPorts:         params.APIHostPorts,
 		facadeVersions:    params.FacadeVersions,
+		deprecatedFacades: params.Deprecated,
 		serverScheme:      params.ServerScheme,
 		serverRootAddress: params.ServerRoot,
 		broken:            params.Broken,
@@ -11,60 +11,66 @@ package api
 // New facades should start at 1.
 // Facades that existed before versioning start at 0.
 var facadeVersions = map[string]int{
-	"Action":                       4,
+	"Action":                       5,
 	"ActionPruner":                 1,
+	"ActionWebhooks":               1,
 	"Agent":                        2,
 	"AgentTools":                   1,
 	"AllModelWatcher":              2,
 	"AllWatcher":                   1,
+	"AnnotationTagger":             1,
 	"Annotations":                  2,
-	"Application":                  10,
+	"APIKeyManager":                1,
+	"Application":                  11,
 	"ApplicationOffers":            2,
 	"ApplicationScaler":            1,
+	"Autoscaler":                   1,
 	"Backups":                      2,
 	"Block":                        2,
 	"Bundle":                       3,
 	"CAASAgent":                    1,
 	"CAASFirewaller":               1,
-	"CAASOperator":                 1,
-	"CAASOperatorProvisioner":      1,
+	"CAASOperator":                 2,
+	"CAASOperatorProvisioner":      2,
 	"CAASOperatorUpgrader":         1,
 	"CAASUnitProvisioner":          1,
+	"CATrustUpdater":               1,
 	"CharmRevisionUpdater":         2,
 	"Charms":                       2,
 	"Cleaner":                      2,
 	"Client":                       2,
 	"Cloud":                        6,
-	"Controller":                   8,
+	"Controller":                   9,
 	"CredentialManager":            1,
 	"CredentialValidator":          2,
 	"CrossController":              1,
 	"CrossModelRelations":          1,
-	"Deployer":                     1,
+	"Deployer":                     2,
 	"DiskManager":                  2,
 	"EntityWatcher":                2,
 	"ExternalControllerUpdater":    1,
 	"FanConfigurer":                1,
 	"FilesystemAttachmentsWatcher": 2,
-	"Firewaller":                   5,
+	"Firewaller":                   6,
 	"FirewallRules":                1,
 	"HighAvailability":             2,
 	"HostKeyReporter":              1,
 	"ImageManager":                 2,
 	"ImageMetadata":                3,
 	"ImageMetadataManager":         1,
-	"InstanceMutater":              2,
-	"InstancePoller":               3,
+	"ImportValidator":              1,
+	"InstanceMutater":              3,
+	"InstancePoller":               4,
 	"KeyManager":                   1,
 	"KeyUpdater":                   1,
 	"LeadershipService":            2,
 	"LifeFlag":                     1,
-	"LogForwarding":                1,
+	"LogForwarding":                2,
 	"Logger":                       1,
 	"MachineActions":               1,
-	"MachineManager":               6,
+	"MachineManager":               7,
 	"MachineUndertaker":            1,
-	"Machiner":                     1,
+	"Machiner":                     2,
 	"MeterStatus":                  1,
 	"MetricsAdder":                 2,
 	"MetricsDebug":                 2,
@@ -77,13 +83,15 @@ var facadeVersions = map[string]int{
 	"ModelConfig":                  2,
 	"ModelGeneration":              2,
 	"ModelManager":                 8,
+	"ModelSummaryWatcher":          1,
 	"ModelUpgrader":                1,
+	"NetworkReconciler":            1,
 	"NotifyWatcher":                1,
 	"OfferStatusWatcher":           1,
 	"Payloads":                     1,
 	"PayloadsHookContext":          1,
 	"Pinger":                       1,
-	"Provisioner":                  9,
+	"Provisioner":                  10,
 	"ProxyUpdater":                 2,
 	"Reboot":                       2,
 	"RelationStatusWatcher":        1,
@@ -93,20 +101,21 @@ var facadeVersions = map[string]int{
 	"ResourcesHookContext":         1,
 	"Resumer":                      2,
 	"RetryStrategy":                1,
+	"SecondFactor":                 1,
 	"Singular":                     2,
-	"Spaces":                       5,
+	"Spaces":                       6,
 	"SSHClient":                    2,
 	"StatusHistory":                2,
-	"Storage":                      6,
+	"Storage":                      7,
 	"StorageProvisioner":           4,
 	"StringsWatcher":               1,
-	"Subnets":                      3,
+	"Subnets":                      4,
 	"Undertaker":                   1,
 	"UnitAssigner":                 1,
-	"Uniter":                       12,
+	"Uniter":                       13,
 	"Upgrader":                     1,
 	"UpgradeSeries":                1,
-	"UpgradeSteps":                 1,
+	"UpgradeSteps":                 2,
 	"UserManager":                  2,
 	"VolumeAttachmentsWatcher":     2,
 	"VolumeAttachmentPlansWatcher": 1,
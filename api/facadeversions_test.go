@@ -10,6 +10,7 @@ import (
 
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/feature"
 	coretesting "github.com/juju/juju/testing"
 )
@@ -118,3 +119,21 @@ func (s *facadeVersionSuite) TestBestFacadeVersionServerUnknown(c *gc.C) {
 		}})
 	c.Check(st.BestFacadeVersion("TestingAPI"), gc.Equals, 0)
 }
+
+func (s *facadeVersionSuite) TestDeprecationWarnings(c *gc.C) {
+	s.PatchValue(api.FacadeVersions, map[string]int{"Client": 1, "Pinger": 1})
+	st := api.NewTestingState(api.TestingStateParams{
+		FacadeVersions: map[string][]int{
+			"Client": {1, 2},
+			"Pinger": {1, 2},
+		},
+		Deprecated: []params.DeprecatedFacade{
+			{Name: "Client", Version: 1, Message: "use Client v2"},
+			{Name: "Pinger", Version: 2, Message: "use Pinger v3"},
+		},
+	})
+	// Only the versions the client would use are reported.
+	c.Check(st.DeprecationWarnings(), jc.DeepEquals, []api.DeprecationWarning{
+		{Facade: "Client", Version: 1, Message: "use Client v2"},
+	})
+}
@@ -9,7 +9,10 @@ import (
 	"gopkg.in/juju/names.v3"
 
 	"github.com/juju/juju/api/firewaller"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/watcher/watchertest"
+	jujunetwork "github.com/juju/juju/network"
+	"github.com/juju/juju/state"
 )
 
 type applicationSuite struct {
@@ -78,3 +81,39 @@ func (s *applicationSuite) TestIsExposed(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(isExposed, jc.IsFalse)
 }
+
+func (s *applicationSuite) TestExposeInfo(c *gc.C) {
+	exposed, exposedEndpoints, err := s.apiApplication.ExposeInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(exposed, jc.IsFalse)
+	c.Assert(exposedEndpoints, gc.HasLen, 0)
+
+	err = s.application.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"url": {ExposeToCIDRs: []string{"10.0.0.0/24"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	exposed, exposedEndpoints, err = s.apiApplication.ExposeInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(exposed, jc.IsTrue)
+	c.Assert(exposedEndpoints, jc.DeepEquals, map[string]params.ExposedEndpoint{
+		"url": {ExposeToCIDRs: []string{"10.0.0.0/24"}},
+	})
+}
+
+func (s *applicationSuite) TestEgressRules(c *gc.C) {
+	rules, err := s.apiApplication.EgressRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, gc.HasLen, 0)
+
+	expected := []jujunetwork.EgressRule{
+		jujunetwork.MustNewEgressRule("tcp", 443, 443, "10.0.0.0/8"),
+		jujunetwork.MustNewEgressRule("udp", 53, 53),
+	}
+	err = s.application.SetEgressRules(expected)
+	c.Assert(err, jc.ErrorIsNil)
+
+	rules, err = s.apiApplication.EgressRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, s.application.EgressRules())
+}
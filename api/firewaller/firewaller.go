@@ -255,3 +255,21 @@ func (c *Client) FirewallRules(knownServices ...string) ([]params.FirewallRule,
 	}
 	return results.Rules, nil
 }
+
+// WatchFirewallRules returns a NotifyWatcher that notifies of changes
+// to the model's firewall rules.
+func (c *Client) WatchFirewallRules() (watcher.NotifyWatcher, error) {
+	if c.BestAPIVersion() < 6 {
+		return nil, errors.NotSupportedf("WatchFirewallRules on v%d facade", c.BestAPIVersion())
+	}
+	var result params.NotifyWatchResult
+	err := c.facade.FacadeCall("WatchFirewallRules", nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	w := apiwatcher.NewNotifyWatcher(c.facade.RawAPICaller(), result)
+	return w, nil
+}
@@ -100,3 +100,21 @@ func (s *stateSuite) TestWatchOpenedPorts(c *gc.C) {
 	wc.AssertChange("1:")
 	wc.AssertNoChange()
 }
+
+func (s *stateSuite) TestWatchFirewallRules(c *gc.C) {
+	w, err := s.firewaller.WatchFirewallRules()
+	c.Assert(err, jc.ErrorIsNil)
+	wc := watchertest.NewNotifyWatcherC(c, w, s.BackingState.StartSync)
+	defer wc.AssertStops()
+
+	// Initial event.
+	wc.AssertOneChange()
+
+	// Whitelist exposed applications, make sure it's detected.
+	err = state.NewFirewallRules(s.State).Save(state.FirewallRule{
+		WellKnownService: state.JujuExposedApplicationRule,
+		WhitelistCIDRs:   []string{"10.0.0.0/8"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
@@ -0,0 +1,33 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package importvalidator
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client is the client-side API for the ImportValidator facade, with
+// which a serialized model can be checked for import into a controller
+// before the import is attempted.
+type Client struct {
+	caller base.FacadeCaller
+}
+
+// NewClient returns a new Client based on an existing API connection.
+func NewClient(caller base.APICaller) *Client {
+	return &Client{caller: base.NewFacadeCaller(caller, "ImportValidator")}
+}
+
+// ValidateImport checks whether the serialized model could be imported
+// into the controller, and returns a report of any problems found. The
+// model is checked against the controller's state, charm store and
+// cloud, but is not imported, so not every import failure is caught.
+func (c *Client) ValidateImport(bytes []byte) (params.ImportValidationReport, error) {
+	var result params.ImportValidationReport
+	err := c.caller.FacadeCall("ValidateImport", params.SerializedModel{Bytes: bytes}, &result)
+	return result, errors.Trace(err)
+}
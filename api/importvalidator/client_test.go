@@ -0,0 +1,50 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package importvalidator_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/importvalidator"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type clientSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&clientSuite{})
+
+func (s *clientSuite) TestValidateImport(c *gc.C) {
+	expected := params.ImportValidationReport{
+		ModelUUID: "uuid",
+		ModelName: "model",
+		Issues: []params.ImportValidationIssue{{
+			Kind:    "charm",
+			Entity:  "mysql",
+			Message: `charm "cs:mysql" has no revision`,
+		}},
+	}
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "ImportValidator")
+		c.Check(request, gc.Equals, "ValidateImport")
+		c.Check(arg, jc.DeepEquals, params.SerializedModel{Bytes: []byte("model")})
+		*(result.(*params.ImportValidationReport)) = expected
+		return nil
+	})
+	report, err := importvalidator.NewClient(apiCaller).ValidateImport([]byte("model"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(report, jc.DeepEquals, expected)
+}
+
+func (s *clientSuite) TestValidateImportError(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		return &params.Error{Message: "permission denied", Code: params.CodeUnauthorized}
+	})
+	_, err := importvalidator.NewClient(apiCaller).ValidateImport([]byte("model"))
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
@@ -47,6 +47,25 @@ func (c *Client) WatchMachines() (watcher.StringsWatcher, error) {
 	return apiwatcher.NewStringsWatcher(c.facade.RawAPICaller(), result), nil
 }
 
+// WatchLXDProfileMachines returns a StringsWatcher reporting changes to the
+// machines hosting units of applications whose charms declare an LXD
+// profile. Controllers that cannot filter the machines report them all,
+// as WatchMachines does.
+func (c *Client) WatchLXDProfileMachines() (watcher.StringsWatcher, error) {
+	if c.facade.BestAPIVersion() < 3 {
+		return c.WatchMachines()
+	}
+	var result params.StringsWatchResult
+	err := c.facade.FacadeCall("WatchLXDProfileMachines", nil, &result)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return apiwatcher.NewStringsWatcher(c.facade.RawAPICaller(), result), nil
+}
+
 // Machine provides access to methods of a state.Machine through the
 // facade.
 func (c *Client) Machine(tag names.MachineTag) (MutaterMachine, error) {
@@ -84,6 +84,40 @@ func (s *instanceMutaterSuite) TestWatchMachinesServerError(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "failed")
 }
 
+func (s *instanceMutaterSuite) TestWatchLXDProfileMachines(c *gc.C) {
+	defer s.setup(c).Finish()
+
+	api := s.clientForScenario(c,
+		s.expectWatchLXDProfileMachines,
+		s.expectStringsWatcher,
+	)
+	ch, err := api.WatchLXDProfileMachines()
+	c.Assert(err, jc.ErrorIsNil)
+
+	select {
+	case <-ch.Changes():
+	case <-time.After(jujutesting.LongWait):
+		c.Fail()
+	}
+}
+
+func (s *instanceMutaterSuite) TestWatchLXDProfileMachinesFallsBackToWatchMachines(c *gc.C) {
+	defer s.setup(c).Finish()
+
+	api := s.clientForScenario(c,
+		s.expectWatchMachines,
+		s.expectStringsWatcher,
+	)
+	ch, err := api.WatchLXDProfileMachines()
+	c.Assert(err, jc.ErrorIsNil)
+
+	select {
+	case <-ch.Changes():
+	case <-time.After(jujutesting.LongWait):
+		c.Fail()
+	}
+}
+
 func (s *instanceMutaterSuite) setup(c *gc.C) *gomock.Controller {
 	ctrl := gomock.NewController(c)
 
@@ -107,6 +141,12 @@ func (s *instanceMutaterSuite) expectWatchMachines() {
 	aExp.APICall("InstanceMutater", 1, "", "WatchMachines", nil, gomock.Any()).Return(nil)
 }
 
+func (s *instanceMutaterSuite) expectWatchLXDProfileMachines() {
+	aExp := s.apiCaller.EXPECT()
+	aExp.BestFacadeVersion("InstanceMutater").Return(3)
+	aExp.APICall("InstanceMutater", 3, "", "WatchLXDProfileMachines", nil, gomock.Any()).Return(nil)
+}
+
 func (s *instanceMutaterSuite) expectStringsWatcher() {
 	aExp := s.apiCaller.EXPECT()
 	aExp.BestFacadeVersion("StringsWatcher").Return(1)
@@ -212,6 +212,10 @@ type UnitProfileInfo struct {
 	InstanceId      instance.Id
 	ProfileChanges  []UnitProfileChanges
 	CurrentProfiles []string
+
+	// RollbackProfiles, if set, holds the profiles to which the
+	// machine's profiles are to be rolled back.
+	RollbackProfiles []string
 }
 
 type UnitProfileChanges struct {
@@ -232,9 +236,10 @@ func (m *Machine) CharmProfilingInfo() (*UnitProfileInfo, error) {
 		return nil, errors.Trace(result.Error)
 	}
 	returnResult := &UnitProfileInfo{
-		InstanceId:      result.InstanceId,
-		ModelName:       result.ModelName,
-		CurrentProfiles: result.CurrentProfiles,
+		InstanceId:       result.InstanceId,
+		ModelName:        result.ModelName,
+		CurrentProfiles:  result.CurrentProfiles,
+		RollbackProfiles: result.RollbackProfiles,
 	}
 	profileChanges := make([]UnitProfileChanges, len(result.ProfileChanges))
 	for i, change := range result.ProfileChanges {
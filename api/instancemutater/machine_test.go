@@ -132,10 +132,11 @@ func (s *instanceMutaterMachineSuite) TestCharmProfilingInfoSuccessChanges(c *gc
 
 	args := params.Entity{Tag: s.tag.String()}
 	results := params.CharmProfilingInfoResult{
-		InstanceId:      instance.Id("juju-gd4c23-0"),
-		ModelName:       "default",
-		CurrentProfiles: []string{"juju-default-neutron-ovswitch-255"},
-		Error:           nil,
+		InstanceId:       instance.Id("juju-gd4c23-0"),
+		ModelName:        "default",
+		CurrentProfiles:  []string{"juju-default-neutron-ovswitch-255"},
+		RollbackProfiles: []string{"default", "juju-default"},
+		Error:            nil,
 		ProfileChanges: []params.ProfileInfoResult{{
 			Profile: &params.CharmLXDProfile{
 				Description: "Test Profile",
@@ -151,6 +152,7 @@ func (s *instanceMutaterMachineSuite) TestCharmProfilingInfoSuccessChanges(c *gc
 	c.Assert(info.InstanceId, gc.Equals, results.InstanceId)
 	c.Assert(info.ModelName, gc.Equals, results.ModelName)
 	c.Assert(info.CurrentProfiles, gc.DeepEquals, results.CurrentProfiles)
+	c.Assert(info.RollbackProfiles, gc.DeepEquals, results.RollbackProfiles)
 	c.Assert(info.ProfileChanges[0].Profile.Description, gc.Equals, "Test Profile")
 }
 
@@ -80,6 +80,15 @@ type Info struct {
 	// Nonce holds the nonce used when provisioning the machine. Used
 	// only by the machine agent.
 	Nonce string `yaml:",omitempty"`
+
+	// APIKey holds a token issued by the APIKeyManager facade. If set,
+	// it is used to log in instead of Tag, Password and Macaroons.
+	APIKey string `yaml:",omitempty"`
+
+	// InstanceIdentity holds the signed identity document of the
+	// cloud instance a machine agent is running on. If set, it is used
+	// together with Tag and Nonce to log in in place of Password.
+	InstanceIdentity string `yaml:"-"`
 }
 
 // Ports returns the unique ports for the api addresses.
@@ -114,6 +123,12 @@ func (info *Info) Validate() error {
 		if len(info.Macaroons) > 0 {
 			return errors.NotValidf("specifying Macaroons and SkipLogin")
 		}
+		if info.APIKey != "" {
+			return errors.NotValidf("specifying APIKey and SkipLogin")
+		}
+	}
+	if info.APIKey != "" && (info.Tag != nil || info.Password != "" || len(info.Macaroons) > 0) {
+		return errors.NotValidf("specifying APIKey with other credentials")
 	}
 	return nil
 }
@@ -280,6 +295,11 @@ type Connection interface {
 	// keeping it for now, but it's not apparently used anywhere else.
 	AllFacadeVersions() map[string][]int
 
+	// DeprecationWarnings returns a warning for each deprecated
+	// facade version that the connection would use, so that callers
+	// can warn before the version is removed from the server.
+	DeprecationWarnings() []DeprecationWarning
+
 	// AuthTag returns the tag of the authorized user of the state API
 	// connection.
 	AuthTag() names.Tag
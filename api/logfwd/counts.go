@@ -0,0 +1,112 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logfwd
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Counts holds the numbers of log records sent to, and dropped instead
+// of being sent to, a log forwarding target.
+type Counts struct {
+	LastSentID
+
+	// Sent is the number of records forwarded for a given model
+	// and sink.
+	Sent int64
+
+	// Dropped is the number of records dropped instead of being
+	// forwarded for a given model and sink.
+	Dropped int64
+}
+
+// CountsResult holds a single result from a bulk API call.
+type CountsResult struct {
+	Counts
+
+	// Error holds the error, if any, that resulted while handling the
+	// request for the ID.
+	Error error
+}
+
+// CountsClient exposes the sent and dropped record counting methods
+// of the LogForwarding API facade.
+type CountsClient struct {
+	caller FacadeCaller
+}
+
+// NewCountsClient creates a new API client for the facade.
+func NewCountsClient(newFacadeCaller func(string) FacadeCaller) *CountsClient {
+	return &CountsClient{
+		caller: newFacadeCaller("LogForwarding"),
+	}
+}
+
+// AddCounts makes an "AddCounts" call on the facade, adding the given
+// counts to the controller's totals, and returns the results in the
+// same order.
+func (c CountsClient) AddCounts(reqs []Counts) ([]CountsResult, error) {
+	var args params.LogForwardingAddCountsParams
+	args.Params = make([]params.LogForwardingAddCountsParam, len(reqs))
+	for i, req := range reqs {
+		args.Params[i] = params.LogForwardingAddCountsParam{
+			LogForwardingID: params.LogForwardingID{
+				ModelTag: req.Model.String(),
+				Sink:     req.Sink,
+			},
+			Sent:    req.Sent,
+			Dropped: req.Dropped,
+		}
+	}
+
+	var apiResults params.ErrorResults
+	err := c.caller.FacadeCall("AddCounts", args, &apiResults)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	results := make([]CountsResult, len(reqs))
+	for i, apiRes := range apiResults.Results {
+		results[i] = CountsResult{
+			Counts: reqs[i],
+			Error:  common.RestoreError(apiRes.Error),
+		}
+	}
+	return results, nil
+}
+
+// GetCounts makes a "GetCounts" call on the facade and returns the
+// controller's totals in the same order.
+func (c CountsClient) GetCounts(ids []LastSentID) ([]CountsResult, error) {
+	var args params.LogForwardingIDs
+	args.IDs = make([]params.LogForwardingID, len(ids))
+	for i, id := range ids {
+		args.IDs[i] = params.LogForwardingID{
+			ModelTag: id.Model.String(),
+			Sink:     id.Sink,
+		}
+	}
+
+	var apiResults params.LogForwardingGetCountsResults
+	err := c.caller.FacadeCall("GetCounts", args, &apiResults)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	results := make([]CountsResult, len(ids))
+	for i, apiRes := range apiResults.Results {
+		results[i] = CountsResult{
+			Counts: Counts{
+				LastSentID: ids[i],
+				Sent:       apiRes.Sent,
+				Dropped:    apiRes.Dropped,
+			},
+			Error: common.RestoreError(apiRes.Error),
+		}
+	}
+	return results, nil
+}
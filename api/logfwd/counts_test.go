@@ -0,0 +1,99 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logfwd_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/api/logfwd"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+)
+
+type CountsSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&CountsSuite{})
+
+func (s *CountsSuite) TestAddCounts(c *gc.C) {
+	stub := &testing.Stub{}
+	caller := &stubFacadeCaller{stub: stub}
+	caller.ReturnFacadeCallSet = params.ErrorResults{
+		Results: []params.ErrorResult{{}},
+	}
+	client := logfwd.NewCountsClient(caller.newFacadeCaller)
+	modelTag := names.NewModelTag("deadbeef-2f18-4fd2-967d-db9663db7bea")
+	counts := logfwd.Counts{
+		LastSentID: logfwd.LastSentID{
+			Model: modelTag,
+			Sink:  "spam",
+		},
+		Sent:    10,
+		Dropped: 2,
+	}
+
+	results, err := client.AddCounts([]logfwd.Counts{counts})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(results, jc.DeepEquals, []logfwd.CountsResult{{
+		Counts: counts,
+	}})
+	stub.CheckCallNames(c, "newFacadeCaller", "FacadeCall")
+	stub.CheckCall(c, 0, "newFacadeCaller", "LogForwarding")
+	stub.CheckCall(c, 1, "FacadeCall", "AddCounts", params.LogForwardingAddCountsParams{
+		Params: []params.LogForwardingAddCountsParam{{
+			LogForwardingID: params.LogForwardingID{
+				ModelTag: modelTag.String(),
+				Sink:     "spam",
+			},
+			Sent:    10,
+			Dropped: 2,
+		}},
+	})
+}
+
+func (s *CountsSuite) TestGetCounts(c *gc.C) {
+	stub := &testing.Stub{}
+	caller := &stubFacadeCaller{stub: stub}
+	caller.ReturnFacadeCallGetCounts = params.LogForwardingGetCountsResults{
+		Results: []params.LogForwardingGetCountsResult{{
+			Sent:    10,
+			Dropped: 2,
+		}, {
+			Error: common.ServerError(errors.New("boom")),
+		}},
+	}
+	client := logfwd.NewCountsClient(caller.newFacadeCaller)
+	modelTag := names.NewModelTag("deadbeef-2f18-4fd2-967d-db9663db7bea")
+	spam := logfwd.LastSentID{Model: modelTag, Sink: "spam"}
+	eggs := logfwd.LastSentID{Model: modelTag, Sink: "eggs"}
+
+	results, err := client.GetCounts([]logfwd.LastSentID{spam, eggs})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(results, gc.HasLen, 2)
+	c.Check(results[0], jc.DeepEquals, logfwd.CountsResult{
+		Counts: logfwd.Counts{
+			LastSentID: spam,
+			Sent:       10,
+			Dropped:    2,
+		},
+	})
+	c.Check(results[1].Counts, jc.DeepEquals, logfwd.Counts{LastSentID: eggs})
+	c.Check(results[1].Error, gc.ErrorMatches, "boom")
+	stub.CheckCall(c, 1, "FacadeCall", "GetCounts", params.LogForwardingIDs{
+		IDs: []params.LogForwardingID{{
+			ModelTag: modelTag.String(),
+			Sink:     "spam",
+		}, {
+			ModelTag: modelTag.String(),
+			Sink:     "eggs",
+		}},
+	})
+}
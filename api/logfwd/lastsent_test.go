@@ -203,6 +203,8 @@ type stubFacadeCaller struct {
 
 	ReturnFacadeCallGet params.LogForwardingGetLastSentResults
 	ReturnFacadeCallSet params.ErrorResults
+
+	ReturnFacadeCallGetCounts params.LogForwardingGetCountsResults
 }
 
 func (s *stubFacadeCaller) newFacadeCaller(facade string) logfwd.FacadeCaller {
@@ -222,9 +224,12 @@ func (s *stubFacadeCaller) FacadeCall(request string, args, response interface{}
 	case "GetLastSent":
 		actual := response.(*params.LogForwardingGetLastSentResults)
 		*actual = s.ReturnFacadeCallGet
-	case "SetLastSent":
+	case "SetLastSent", "AddCounts":
 		actual := response.(*params.ErrorResults)
 		*actual = s.ReturnFacadeCallSet
+	case "GetCounts":
+		actual := response.(*params.LogForwardingGetCountsResults)
+		*actual = s.ReturnFacadeCallGetCounts
 	}
 	return nil
 }
@@ -13,6 +13,7 @@ import (
 	apiwatcher "github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/core/watcher"
 )
 
@@ -76,14 +77,14 @@ func (client *Client) DestroyMachinesWithParams(force, keep bool, maxWait *time.
 	allResults := make([]params.DestroyMachineResult, len(machines))
 	index := make([]int, 0, len(machines))
 	for i, machineId := range machines {
-		if !names.IsValidMachine(machineId) {
+		if !names.IsValidMachine(machineId) && !model.IsValidMachineAlias(machineId) {
 			allResults[i].Error = &params.Error{
 				Message: errors.NotValidf("machine ID %q", machineId).Error(),
 			}
 			continue
 		}
 		index = append(index, i)
-		args.MachineTags = append(args.MachineTags, names.NewMachineTag(machineId).String())
+		args.MachineTags = append(args.MachineTags, machineTagString(machineId))
 	}
 	if len(args.MachineTags) > 0 {
 		var result params.DestroyMachineResults
@@ -107,7 +108,7 @@ func (client *Client) destroyMachines(method string, machines []string) ([]param
 	allResults := make([]params.DestroyMachineResult, len(machines))
 	index := make([]int, 0, len(machines))
 	for i, machineId := range machines {
-		if !names.IsValidMachine(machineId) {
+		if !names.IsValidMachine(machineId) && !model.IsValidMachineAlias(machineId) {
 			allResults[i].Error = &params.Error{
 				Message: errors.NotValidf("machine ID %q", machineId).Error(),
 			}
@@ -115,7 +116,7 @@ func (client *Client) destroyMachines(method string, machines []string) ([]param
 		}
 		index = append(index, i)
 		args.Entities = append(args.Entities, params.Entity{
-			Tag: names.NewMachineTag(machineId).String(),
+			Tag: machineTagString(machineId),
 		})
 	}
 	if len(args.Entities) > 0 {
@@ -259,3 +260,201 @@ func (client *Client) GetUpgradeSeriesMessages(machineName, watcherId string) ([
 
 	return result.Result, nil
 }
+
+// RebootMachines asks the machine agents of the given machines to reboot
+// them, reporting the outcome for each machine separately.
+func (client *Client) RebootMachines(machines ...string) ([]params.ErrorResult, error) {
+	return client.bulkMachineCall("RebootMachines", machines, func(entities []params.Entity) interface{} {
+		return params.Entities{Entities: entities}
+	})
+}
+
+// UpgradeSeriesPrepareMachines prepares each of the given machines for a
+// series upgrade, reporting the outcome for each machine separately.
+func (client *Client) UpgradeSeriesPrepareMachines(series string, force bool, machines ...string) ([]params.ErrorResult, error) {
+	return client.bulkMachineCall("UpgradeSeriesPrepareMachines", machines, func(entities []params.Entity) interface{} {
+		args := params.UpdateSeriesArgs{
+			Args: make([]params.UpdateSeriesArg, len(entities)),
+		}
+		for i, entity := range entities {
+			args.Args[i] = params.UpdateSeriesArg{
+				Entity: entity,
+				Series: series,
+				Force:  force,
+			}
+		}
+		return args
+	})
+}
+
+// SetMachinesAnnotations sets the given annotations on each of the given
+// machines, reporting the outcome for each machine separately.
+func (client *Client) SetMachinesAnnotations(annotations map[string]string, machines ...string) ([]params.ErrorResult, error) {
+	return client.bulkMachineCall("SetMachinesAnnotations", machines, func(entities []params.Entity) interface{} {
+		return params.MachinesAnnotations{
+			Machines:    entities,
+			Annotations: annotations,
+		}
+	})
+}
+
+// RetryProvisioningMachines marks the provisioning errors of the given
+// machines as transient, so that the provisioner retries them, reporting
+// the outcome for each machine separately.
+func (client *Client) RetryProvisioningMachines(machines ...string) ([]params.ErrorResult, error) {
+	return client.bulkMachineCall("RetryProvisioningMachines", machines, func(entities []params.Entity) interface{} {
+		return params.Entities{Entities: entities}
+	})
+}
+
+// RollbackLXDProfiles requests that the lxd profiles of the given machines
+// be rolled back to those applied before their charm profiles were last
+// changed, reporting the outcome for each machine separately.
+func (client *Client) RollbackLXDProfiles(machines ...string) ([]params.ErrorResult, error) {
+	if client.BestAPIVersion() < 7 {
+		return nil, errors.NotSupportedf("RollbackLXDProfiles")
+	}
+	return client.bulkMachineCall("RollbackLXDProfiles", machines, func(entities []params.Entity) interface{} {
+		return params.Entities{Entities: entities}
+	})
+}
+
+// CordonMachines cordons the given machines, so that no new units are
+// assigned to them, reporting the outcome for each machine separately.
+func (client *Client) CordonMachines(machines ...string) ([]params.ErrorResult, error) {
+	if client.BestAPIVersion() < 7 {
+		return nil, errors.NotSupportedf("CordonMachines")
+	}
+	return client.bulkMachineCall("CordonMachines", machines, func(entities []params.Entity) interface{} {
+		return params.Entities{Entities: entities}
+	})
+}
+
+// UncordonMachines uncordons the given machines, so that units may be
+// assigned to them again, reporting the outcome for each machine
+// separately.
+func (client *Client) UncordonMachines(machines ...string) ([]params.ErrorResult, error) {
+	if client.BestAPIVersion() < 7 {
+		return nil, errors.NotSupportedf("UncordonMachines")
+	}
+	return client.bulkMachineCall("UncordonMachines", machines, func(entities []params.Entity) interface{} {
+		return params.Entities{Entities: entities}
+	})
+}
+
+// PortsHistory returns the most recent port ranges opened and closed on
+// the given machine, newest first. At most size changes are returned; if
+// size is zero, all of the recorded changes are returned.
+func (client *Client) PortsHistory(machine string, size int) ([]params.PortChange, error) {
+	if client.BestAPIVersion() < 7 {
+		return nil, errors.NotSupportedf("PortsHistory")
+	}
+	if !names.IsValidMachine(machine) && !model.IsValidMachineAlias(machine) {
+		return nil, errors.NotValidf("machine ID %q", machine)
+	}
+	args := params.PortsHistoryArgs{
+		Args: []params.PortsHistoryArg{{
+			Tag:  machineTagString(machine),
+			Size: size,
+		}},
+	}
+	var results params.PortsHistoryResults
+	if err := client.facade.FacadeCall("PortsHistory", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if n := len(results.Results); n != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", n)
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, errors.Trace(result.Error)
+	}
+	return result.History, nil
+}
+
+// RequestReboot asks the machine agents of the given machines to reboot
+// them once their units have finished running any current hooks,
+// reporting the outcome for each machine separately. The progress of
+// each reboot may be followed with RebootStatus.
+func (client *Client) RequestReboot(machines ...string) ([]params.ErrorResult, error) {
+	if client.BestAPIVersion() < 7 {
+		return nil, errors.NotSupportedf("RequestReboot")
+	}
+	return client.bulkMachineCall("RequestReboot", machines, func(entities []params.Entity) interface{} {
+		return params.Entities{Entities: entities}
+	})
+}
+
+// RebootStatus returns the progress of the most recent reboot of the
+// given machine requested with RequestReboot.
+func (client *Client) RebootStatus(machine string) (params.RebootStatusResult, error) {
+	if client.BestAPIVersion() < 7 {
+		return params.RebootStatusResult{}, errors.NotSupportedf("RebootStatus")
+	}
+	if !names.IsValidMachine(machine) && !model.IsValidMachineAlias(machine) {
+		return params.RebootStatusResult{}, errors.NotValidf("machine ID %q", machine)
+	}
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: machineTagString(machine)}},
+	}
+	var results params.RebootStatusResults
+	if err := client.facade.FacadeCall("RebootStatus", args, &results); err != nil {
+		return params.RebootStatusResult{}, errors.Trace(err)
+	}
+	if n := len(results.Results); n != 1 {
+		return params.RebootStatusResult{}, errors.Errorf("expected 1 result, got %d", n)
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return params.RebootStatusResult{}, errors.Trace(result.Error)
+	}
+	return result, nil
+}
+
+// machineTagString returns the tag string for the given machine id or
+// machine alias.
+func machineTagString(machineId string) string {
+	if names.IsValidMachine(machineId) {
+		return names.NewMachineTag(machineId).String()
+	}
+	return names.MachineTagKind + "-" + machineId
+}
+
+// bulkMachineCall calls the named bulk machine method with the arguments
+// built by makeArgs from the valid machine IDs. Invalid machine IDs are
+// reported in the results without being sent to the controller.
+func (client *Client) bulkMachineCall(
+	method string, machines []string, makeArgs func([]params.Entity) interface{},
+) ([]params.ErrorResult, error) {
+	if client.BestAPIVersion() < 7 {
+		return nil, errors.NotSupportedf(method)
+	}
+	entities := make([]params.Entity, 0, len(machines))
+	allResults := make([]params.ErrorResult, len(machines))
+	index := make([]int, 0, len(machines))
+	for i, machineId := range machines {
+		if !names.IsValidMachine(machineId) && !model.IsValidMachineAlias(machineId) {
+			allResults[i].Error = &params.Error{
+				Message: errors.NotValidf("machine ID %q", machineId).Error(),
+			}
+			continue
+		}
+		index = append(index, i)
+		entities = append(entities, params.Entity{
+			Tag: machineTagString(machineId),
+		})
+	}
+	if len(entities) > 0 {
+		var result params.ErrorResults
+		if err := client.facade.FacadeCall(method, makeArgs(entities), &result); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if n := len(result.Results); n != len(entities) {
+			return nil, errors.Errorf("expected %d result(s), got %d", len(entities), n)
+		}
+		for i, result := range result.Results {
+			allResults[index[i]] = result
+		}
+	}
+	return allResults, nil
+}
@@ -182,6 +182,28 @@ func (s *MachinemanagerSuite) TestDestroyMachinesInvalidIds(c *gc.C) {
 	c.Assert(results, jc.DeepEquals, expectedResults)
 }
 
+func (s *MachinemanagerSuite) TestDestroyMachinesAliases(c *gc.C) {
+	expectedResults := []params.DestroyMachineResult{{
+		Info: &params.DestroyMachineInfo{},
+	}, {
+		Info: &params.DestroyMachineInfo{},
+	}}
+	client := newClient(func(objType string, version int, id, request string, a, response interface{}) error {
+		c.Assert(a, jc.DeepEquals, params.Entities{
+			Entities: []params.Entity{
+				{Tag: "machine-1a2b3c4d:0"},
+				{Tag: "machine-1a2b3c4d:0/lxd/1"},
+			},
+		})
+		out := response.(*params.DestroyMachineResults)
+		*out = params.DestroyMachineResults{Results: expectedResults}
+		return nil
+	})
+	results, err := client.DestroyMachines("1a2b3c4d:0", "1a2b3c4d:0/lxd/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, expectedResults)
+}
+
 func (s *MachinemanagerSuite) clientToTestDestroyMachinesWithParams(c *gc.C, v int, maxWait *time.Duration) (*machinemanager.Client, []params.DestroyMachineResult) {
 	expectedResults := []params.DestroyMachineResult{{
 		Error: &params.Error{Message: "boo"},
@@ -248,3 +270,260 @@ func (s *MachinemanagerSuite) TestDestroyMachinesWithParamsNilWait(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(results, jc.DeepEquals, expected)
 }
+
+func (s *MachinemanagerSuite) newBulkClient(c *gc.C, methodName string, expectedArgs interface{}) *machinemanager.Client {
+	return machinemanager.NewClient(
+		basetesting.BestVersionCaller{
+			BestVersion: 7,
+			APICallerFunc: basetesting.APICallerFunc(func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Assert(request, gc.Equals, methodName)
+				c.Assert(a, jc.DeepEquals, expectedArgs)
+				c.Assert(response, gc.FitsTypeOf, &params.ErrorResults{})
+				out := response.(*params.ErrorResults)
+				*out = params.ErrorResults{Results: []params.ErrorResult{
+					{Error: &params.Error{Message: "boo"}},
+					{},
+				}}
+				return nil
+			})})
+}
+
+func (s *MachinemanagerSuite) TestRebootMachines(c *gc.C) {
+	client := s.newBulkClient(c, "RebootMachines", params.Entities{
+		Entities: []params.Entity{
+			{Tag: "machine-0"},
+			{Tag: "machine-0-lxd-1"},
+		},
+	})
+	results, err := client.RebootMachines("0", "ha!", "0/lxd/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.ErrorResult{
+		{Error: &params.Error{Message: "boo"}},
+		{Error: &params.Error{Message: `machine ID "ha!" not valid`}},
+		{},
+	})
+}
+
+func (s *MachinemanagerSuite) TestUpgradeSeriesPrepareMachines(c *gc.C) {
+	client := s.newBulkClient(c, "UpgradeSeriesPrepareMachines", params.UpdateSeriesArgs{
+		Args: []params.UpdateSeriesArg{
+			{Entity: params.Entity{Tag: "machine-0"}, Series: "bionic", Force: true},
+			{Entity: params.Entity{Tag: "machine-1"}, Series: "bionic", Force: true},
+		},
+	})
+	results, err := client.UpgradeSeriesPrepareMachines("bionic", true, "0", "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+}
+
+func (s *MachinemanagerSuite) TestSetMachinesAnnotations(c *gc.C) {
+	annotations := map[string]string{"owner": "ops"}
+	client := s.newBulkClient(c, "SetMachinesAnnotations", params.MachinesAnnotations{
+		Machines: []params.Entity{
+			{Tag: "machine-2"},
+			{Tag: "machine-3"},
+		},
+		Annotations: annotations,
+	})
+	results, err := client.SetMachinesAnnotations(annotations, "2", "3")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+}
+
+func (s *MachinemanagerSuite) TestRetryProvisioningMachines(c *gc.C) {
+	client := s.newBulkClient(c, "RetryProvisioningMachines", params.Entities{
+		Entities: []params.Entity{
+			{Tag: "machine-5"},
+			{Tag: "machine-6"},
+		},
+	})
+	results, err := client.RetryProvisioningMachines("5", "6")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+}
+
+func (s *MachinemanagerSuite) TestRollbackLXDProfiles(c *gc.C) {
+	client := machinemanager.NewClient(
+		basetesting.BestVersionCaller{
+			BestVersion: 7,
+			APICallerFunc: basetesting.APICallerFunc(func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Assert(request, gc.Equals, "RollbackLXDProfiles")
+				c.Assert(a, jc.DeepEquals, params.Entities{
+					Entities: []params.Entity{{Tag: "machine-0-lxd-1"}},
+				})
+				*(response.(*params.ErrorResults)) = params.ErrorResults{
+					Results: []params.ErrorResult{{}},
+				}
+				return nil
+			})})
+	results, err := client.RollbackLXDProfiles("0/lxd/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.ErrorResult{{}})
+}
+
+func (s *MachinemanagerSuite) TestRollbackLXDProfilesNotSupported(c *gc.C) {
+	client := machinemanager.NewClient(
+		basetesting.BestVersionCaller{
+			BestVersion: 6,
+			APICallerFunc: basetesting.APICallerFunc(func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Fatalf("unexpected call to %s", request)
+				return nil
+			})})
+	_, err := client.RollbackLXDProfiles("0")
+	c.Assert(err, gc.ErrorMatches, "RollbackLXDProfiles not supported")
+}
+
+func (s *MachinemanagerSuite) TestCordonMachines(c *gc.C) {
+	client := s.newCordonClient(c, "CordonMachines")
+	results, err := client.CordonMachines("1", "2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.ErrorResult{{}, {}})
+}
+
+func (s *MachinemanagerSuite) TestUncordonMachines(c *gc.C) {
+	client := s.newCordonClient(c, "UncordonMachines")
+	results, err := client.UncordonMachines("1", "2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.ErrorResult{{}, {}})
+}
+
+func (s *MachinemanagerSuite) newCordonClient(c *gc.C, method string) *machinemanager.Client {
+	return machinemanager.NewClient(
+		basetesting.BestVersionCaller{
+			BestVersion: 7,
+			APICallerFunc: basetesting.APICallerFunc(func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Assert(request, gc.Equals, method)
+				c.Assert(a, jc.DeepEquals, params.Entities{
+					Entities: []params.Entity{{Tag: "machine-1"}, {Tag: "machine-2"}},
+				})
+				*(response.(*params.ErrorResults)) = params.ErrorResults{
+					Results: []params.ErrorResult{{}, {}},
+				}
+				return nil
+			})})
+}
+
+func (s *MachinemanagerSuite) TestCordonMachinesNotSupported(c *gc.C) {
+	client := machinemanager.NewClient(
+		basetesting.BestVersionCaller{
+			BestVersion: 6,
+			APICallerFunc: basetesting.APICallerFunc(func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Fatalf("unexpected call to %s", request)
+				return nil
+			})})
+	_, err := client.CordonMachines("0")
+	c.Assert(err, gc.ErrorMatches, "CordonMachines not supported")
+	_, err = client.UncordonMachines("0")
+	c.Assert(err, gc.ErrorMatches, "UncordonMachines not supported")
+}
+
+func (s *MachinemanagerSuite) TestPortsHistory(c *gc.C) {
+	history := []params.PortChange{{
+		UnitTag:  "unit-mysql-0",
+		Hook:     "install",
+		Protocol: "tcp",
+		FromPort: 22,
+		ToPort:   22,
+		Opened:   true,
+		Time:     time.Date(2019, 5, 1, 12, 0, 0, 0, time.UTC),
+	}}
+	client := machinemanager.NewClient(
+		basetesting.BestVersionCaller{
+			BestVersion: 7,
+			APICallerFunc: basetesting.APICallerFunc(func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Assert(request, gc.Equals, "PortsHistory")
+				c.Assert(a, jc.DeepEquals, params.PortsHistoryArgs{
+					Args: []params.PortsHistoryArg{{Tag: "machine-1", Size: 5}},
+				})
+				*(response.(*params.PortsHistoryResults)) = params.PortsHistoryResults{
+					Results: []params.PortsHistoryResult{{History: history}},
+				}
+				return nil
+			})})
+	result, err := client.PortsHistory("1", 5)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, history)
+}
+
+func (s *MachinemanagerSuite) TestPortsHistoryNotSupported(c *gc.C) {
+	client := machinemanager.NewClient(
+		basetesting.BestVersionCaller{
+			BestVersion: 6,
+			APICallerFunc: basetesting.APICallerFunc(func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Fatalf("unexpected call to %s", request)
+				return nil
+			})})
+	_, err := client.PortsHistory("0", 0)
+	c.Assert(err, gc.ErrorMatches, "PortsHistory not supported")
+}
+
+func (s *MachinemanagerSuite) TestRequestReboot(c *gc.C) {
+	client := machinemanager.NewClient(
+		basetesting.BestVersionCaller{
+			BestVersion: 7,
+			APICallerFunc: basetesting.APICallerFunc(func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Assert(request, gc.Equals, "RequestReboot")
+				c.Assert(a, jc.DeepEquals, params.Entities{
+					Entities: []params.Entity{{Tag: "machine-1"}},
+				})
+				*(response.(*params.ErrorResults)) = params.ErrorResults{
+					Results: []params.ErrorResult{{}},
+				}
+				return nil
+			})})
+	results, err := client.RequestReboot("1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.ErrorResult{{}})
+}
+
+func (s *MachinemanagerSuite) TestRebootStatus(c *gc.C) {
+	requested := time.Date(2019, 5, 1, 12, 0, 0, 0, time.UTC)
+	client := machinemanager.NewClient(
+		basetesting.BestVersionCaller{
+			BestVersion: 7,
+			APICallerFunc: basetesting.APICallerFunc(func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Assert(request, gc.Equals, "RebootStatus")
+				c.Assert(a, jc.DeepEquals, params.Entities{
+					Entities: []params.Entity{{Tag: "machine-1"}},
+				})
+				*(response.(*params.RebootStatusResults)) = params.RebootStatusResults{
+					Results: []params.RebootStatusResult{{
+						State:     "requested",
+						Requested: &requested,
+					}},
+				}
+				return nil
+			})})
+	result, err := client.RebootStatus("1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.RebootStatusResult{
+		State:     "requested",
+		Requested: &requested,
+	})
+}
+
+func (s *MachinemanagerSuite) TestRequestRebootNotSupported(c *gc.C) {
+	client := machinemanager.NewClient(
+		basetesting.BestVersionCaller{
+			BestVersion: 6,
+			APICallerFunc: basetesting.APICallerFunc(func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Fatalf("unexpected call to %s", request)
+				return nil
+			})})
+	_, err := client.RequestReboot("0")
+	c.Assert(err, gc.ErrorMatches, "RequestReboot not supported")
+	_, err = client.RebootStatus("0")
+	c.Assert(err, gc.ErrorMatches, "RebootStatus not supported")
+}
+
+func (s *MachinemanagerSuite) TestBulkMachinesNotSupported(c *gc.C) {
+	client := machinemanager.NewClient(
+		basetesting.BestVersionCaller{
+			BestVersion: 6,
+			APICallerFunc: basetesting.APICallerFunc(func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Fatalf("unexpected call to %s", request)
+				return nil
+			})})
+	_, err := client.RebootMachines("0")
+	c.Assert(err, gc.ErrorMatches, "RebootMachines not supported")
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/juju/juju/api/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/core/watcher"
@@ -111,17 +112,186 @@ func (m *Machine) Jobs() (*params.JobsResult, error) {
 }
 
 // SetObservedNetworkConfig sets the machine network config as observed on the
-// machine.
-func (m *Machine) SetObservedNetworkConfig(netConfig []params.NetworkConfig) error {
+// machine. It returns the problems the controller found with the machine's
+// network config, which controllers before Machiner v8 do not report.
+func (m *Machine) SetObservedNetworkConfig(netConfig []params.NetworkConfig) ([]params.NetworkConfigValidation, error) {
+	return m.setObservedNetworkConfig("SetObservedNetworkConfig", netConfig)
+}
+
+// SetObservedNetworkConfigChanges updates the machine network config, as
+// observed on the machine, with that of the interfaces which have been
+// added or changed since it was last set. Controllers which cannot accept
+// the changes alone are sent them with SetObservedNetworkConfig, which
+// leaves the config of the interfaces not given as it was too. The
+// problems found with the whole of the machine's network config are
+// returned, as for SetObservedNetworkConfig.
+func (m *Machine) SetObservedNetworkConfigChanges(netConfig []params.NetworkConfig) ([]params.NetworkConfigValidation, error) {
+	if m.st.facade.BestAPIVersion() < 2 {
+		if len(netConfig) == 0 {
+			return nil, nil
+		}
+		return m.SetObservedNetworkConfig(netConfig)
+	}
+	return m.setObservedNetworkConfig("SetObservedNetworkConfigChanges", netConfig)
+}
+
+func (m *Machine) setObservedNetworkConfig(method string, netConfig []params.NetworkConfig) ([]params.NetworkConfigValidation, error) {
 	args := params.SetMachineNetworkConfig{
 		Tag:    m.Tag().String(),
 		Config: netConfig,
 	}
-	err := m.st.facade.FacadeCall("SetObservedNetworkConfig", args, nil)
+	if m.st.facade.BestAPIVersion() < 2 {
+		err := m.st.facade.FacadeCall(method, args, nil)
+		return nil, errors.Trace(err)
+	}
+	var result params.SetMachineNetworkConfigResult
+	if err := m.st.facade.FacadeCall(method, args, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Validations, nil
+}
+
+// SetBootID records the boot id of the machine's kernel, returning true
+// if the machine has been rebooted, since its boot id was last recorded,
+// without Juju having rebooted it.
+func (m *Machine) SetBootID(bootID string) (bool, error) {
+	if m.st.facade.BestAPIVersion() < 2 {
+		return false, errors.NotSupportedf("recording boot ids by this version of Juju")
+	}
+	var results params.BoolResults
+	args := params.SetMachineBootIDs{
+		Args: []params.MachineBootID{{Tag: m.tag.String(), BootID: bootID}},
+	}
+	err := m.st.facade.FacadeCall("SetBootIDs", args, &results)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return false, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.Result, nil
+}
+
+// SetHostname records the hostname of the machine.
+func (m *Machine) SetHostname(hostname string) error {
+	if m.st.facade.BestAPIVersion() < 2 {
+		return errors.NotSupportedf("recording hostnames by this version of Juju")
+	}
+	var results params.ErrorResults
+	args := params.SetMachineHostnames{
+		Args: []params.MachineHostname{{Tag: m.tag.String(), Hostname: hostname}},
+	}
+	err := m.st.facade.FacadeCall("SetHostnames", args, &results)
 	if err != nil {
 		return errors.Trace(err)
 	}
-	return nil
+	return results.OneError()
+}
+
+// Hostname returns the hostname of the machine, as last recorded by
+// SetHostname.
+func (m *Machine) Hostname() (string, error) {
+	if m.st.facade.BestAPIVersion() < 2 {
+		return "", errors.NotSupportedf("getting hostnames by this version of Juju")
+	}
+	var results params.StringResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: m.tag.String()}},
+	}
+	err := m.st.facade.FacadeCall("Hostnames", args, &results)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return "", errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return "", result.Error
+	}
+	return result.Result, nil
+}
+
+// SetShutdownProgress records the shutdown task the machine agent is
+// running while the machine is dying, before it is made dead. The machine
+// is reported as draining while the progress is set.
+func (m *Machine) SetShutdownProgress(progress string) error {
+	if m.st.facade.BestAPIVersion() < 2 {
+		return errors.NotSupportedf("recording shutdown progress by this version of Juju")
+	}
+	var results params.ErrorResults
+	args := params.SetMachineShutdownProgress{
+		Args: []params.MachineShutdownProgress{{Tag: m.tag.String(), Progress: progress}},
+	}
+	err := m.st.facade.FacadeCall("SetShutdownProgress", args, &results)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// HostInfo describes the operating system and hardware of a machine, as
+// observed by its machine agent.
+type HostInfo struct {
+	// KernelVersion is the version of the running kernel.
+	KernelVersion string
+
+	// Series is the series of the installed operating system.
+	Series string
+
+	// Hardware holds the machine's memory, CPU cores and root disk size.
+	// Only those that are set are recorded.
+	Hardware *instance.HardwareCharacteristics
+}
+
+// SetHostInfo records the kernel version, series and hardware of the
+// machine.
+func (m *Machine) SetHostInfo(info HostInfo) error {
+	if m.st.facade.BestAPIVersion() < 2 {
+		return errors.NotSupportedf("recording host info by this version of Juju")
+	}
+	var results params.ErrorResults
+	args := params.SetMachinesHostInfo{
+		Args: []params.MachineHostInfo{{
+			Tag:           m.tag.String(),
+			KernelVersion: info.KernelVersion,
+			Series:        info.Series,
+			Hardware:      info.Hardware,
+		}},
+	}
+	err := m.st.facade.FacadeCall("SetHostInfo", args, &results)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// Cordoned reports whether the machine is cordoned, in which case no new
+// units are assigned to it.
+func (m *Machine) Cordoned() (bool, error) {
+	if m.st.facade.BestAPIVersion() < 2 {
+		return false, errors.NotSupportedf("cordoning machines by this version of Juju")
+	}
+	var results params.BoolResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: m.tag.String()}},
+	}
+	err := m.st.facade.FacadeCall("Cordoned", args, &results)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return false, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.Result, nil
 }
 
 // SetProviderNetworkConfig sets the machine network config as seen by the
@@ -14,6 +14,13 @@ import (
 
 const machinerFacade = "Machiner"
 
+// MachineResult provides a found Machine and any Error related to
+// finding it.
+type MachineResult struct {
+	Machine *Machine
+	Err     *params.Error
+}
+
 // State provides access to the Machiner API facade.
 type State struct {
 	facade base.FacadeCaller
@@ -46,3 +53,33 @@ func (st *State) Machine(tag names.MachineTag) (*Machine, error) {
 		st:   st,
 	}, nil
 }
+
+// Machines provides access to methods of a state.Machine through the
+// facade for each of the given tags, looking them all up in a single call.
+// A result is returned for each tag, in the same order.
+func (st *State) Machines(tags ...names.MachineTag) ([]MachineResult, error) {
+	genericTags := make([]names.Tag, len(tags))
+	for i, t := range tags {
+		genericTags[i] = t
+	}
+	result, err := common.Life(st.facade, genericTags)
+	if err != nil {
+		return nil, errors.Annotate(err, "can't get life for machines")
+	}
+	if len(result) != len(tags) {
+		return nil, errors.Errorf("expected %d results, got %d", len(tags), len(result))
+	}
+	machines := make([]MachineResult, len(tags))
+	for i, r := range result {
+		if r.Error != nil {
+			machines[i].Err = r.Error
+			continue
+		}
+		machines[i].Machine = &Machine{
+			tag:  tags[i],
+			life: r.Life,
+			st:   st,
+		}
+	}
+	return machines, nil
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/juju/juju/api/machiner"
 	apitesting "github.com/juju/juju/api/testing"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/core/watcher/watchertest"
@@ -68,6 +69,21 @@ func (s *machinerSuite) TestMachineAndMachineTag(c *gc.C) {
 	c.Assert(machine.Tag(), gc.Equals, machine1)
 }
 
+func (s *machinerSuite) TestMachines(c *gc.C) {
+	machine1 := names.NewMachineTag("1")
+	results, err := s.machiner.Machines(machine1, names.NewMachineTag("42"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+
+	c.Assert(results[0].Err, gc.IsNil)
+	c.Assert(results[0].Machine.Tag(), gc.Equals, machine1)
+	c.Assert(results[0].Machine.Life(), gc.Equals, params.Alive)
+
+	c.Assert(results[1].Machine, gc.IsNil)
+	c.Assert(results[1].Err, gc.ErrorMatches, "permission denied")
+	c.Assert(results[1].Err, jc.Satisfies, params.IsCodeUnauthorized)
+}
+
 func (s *machinerSuite) TestSetStatus(c *gc.C) {
 	machine, err := s.machiner.Machine(names.NewMachineTag("1"))
 	c.Assert(err, jc.ErrorIsNil)
@@ -181,6 +197,121 @@ func (s *machinerSuite) TestSetEmptyMachineAddresses(c *gc.C) {
 	c.Assert(s.machine.MachineAddresses(), gc.HasLen, 0)
 }
 
+func (s *machinerSuite) TestSetObservedNetworkConfigChanges(c *gc.C) {
+	err := s.machine.SetInstanceInfo("i-foo", "", "FAKE_NONCE", nil, nil, nil, nil, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSubnet(network.SubnetInfo{CIDR: "0.20.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.machiner.Machine(names.NewMachineTag("1"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	validations, err := machine.SetObservedNetworkConfigChanges([]params.NetworkConfig{{
+		InterfaceName: "eth0",
+		InterfaceType: "ethernet",
+		MACAddress:    "aa:bb:cc:dd:ee:f0",
+		CIDR:          "0.10.0.0/24",
+		Address:       "0.10.0.2",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(validations, jc.DeepEquals, []params.NetworkConfigValidation{{
+		InterfaceName: "eth0",
+		Address:       "0.10.0.2",
+		CIDR:          "0.10.0.0/24",
+		Message:       `subnet "0.10.0.0/24" is not known to the model`,
+	}})
+
+	_, err = machine.SetObservedNetworkConfigChanges(nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	devices, err := s.machine.AllLinkLayerDevices()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(devices, gc.HasLen, 1)
+	c.Assert(devices[0].Name(), gc.Equals, "eth0")
+}
+
+func (s *machinerSuite) TestSetBootID(c *gc.C) {
+	machine, err := s.machiner.Machine(names.NewMachineTag("1"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	rebooted, err := machine.SetBootID("boot-1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rebooted, jc.IsFalse)
+
+	err = s.machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.BootID(), gc.Equals, "boot-1")
+
+	rebooted, err = machine.SetBootID("boot-2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rebooted, jc.IsTrue)
+}
+
+func (s *machinerSuite) TestSetHostname(c *gc.C) {
+	machine, err := s.machiner.Machine(names.NewMachineTag("1"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = machine.SetHostname("juju-1.example.com")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.Hostname(), gc.Equals, "juju-1.example.com")
+
+	hostname, err := machine.Hostname()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hostname, gc.Equals, "juju-1.example.com")
+}
+
+func (s *machinerSuite) TestSetShutdownProgress(c *gc.C) {
+	machine, err := s.machiner.Machine(names.NewMachineTag("1"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.machine.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = machine.SetShutdownProgress("flushing logs")
+	c.Assert(err, jc.ErrorIsNil)
+
+	statusInfo, err := s.machine.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statusInfo.Status, gc.Equals, status.Draining)
+	c.Assert(statusInfo.Message, gc.Equals, "flushing logs")
+}
+
+func (s *machinerSuite) TestSetHostInfo(c *gc.C) {
+	machine, err := s.machiner.Machine(names.NewMachineTag("1"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	mem := uint64(8192)
+	err = machine.SetHostInfo(machiner.HostInfo{
+		KernelVersion: "4.15.0-58-generic",
+		Hardware:      &instance.HardwareCharacteristics{Mem: &mem},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.KernelVersion(), gc.Equals, "4.15.0-58-generic")
+	hc, err := s.machine.HardwareCharacteristics()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hc.Mem, jc.DeepEquals, &mem)
+}
+
+func (s *machinerSuite) TestCordoned(c *gc.C) {
+	machine, err := s.machiner.Machine(names.NewMachineTag("1"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	cordoned, err := machine.Cordoned()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cordoned, jc.IsFalse)
+
+	err = s.machine.SetCordoned(true)
+	c.Assert(err, jc.ErrorIsNil)
+	cordoned, err = machine.Cordoned()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cordoned, jc.IsTrue)
+}
+
 func (s *machinerSuite) TestWatch(c *gc.C) {
 	machine, err := s.machiner.Machine(names.NewMachineTag("1"))
 	c.Assert(err, jc.ErrorIsNil)
@@ -104,6 +104,7 @@ func convertParamsModelInfo(modelInfo params.ModelInfo) (base.ModelInfo, error)
 	result := base.ModelInfo{
 		Name:            modelInfo.Name,
 		UUID:            modelInfo.UUID,
+		Alias:           modelInfo.Alias,
 		ControllerUUID:  modelInfo.ControllerUUID,
 		IsController:    modelInfo.IsController,
 		ProviderType:    modelInfo.ProviderType,
@@ -191,6 +192,7 @@ func (c *Client) ListModels(user string) ([]base.UserModel, error) {
 		result[i] = base.UserModel{
 			Name:           usermodel.Name,
 			UUID:           usermodel.UUID,
+			Alias:          usermodel.Alias,
 			Type:           modelType,
 			Owner:          owner.Id(),
 			LastConnection: usermodel.LastConnection,
@@ -224,6 +226,7 @@ func (c *Client) ListModelSummaries(user string, all bool) ([]base.UserModelSumm
 		summaries[i] = base.UserModelSummary{
 			Name:               summary.Name,
 			UUID:               summary.UUID,
+			Alias:              summary.Alias,
 			Type:               modelType,
 			ControllerUUID:     summary.ControllerUUID,
 			IsController:       summary.IsController,
@@ -60,6 +60,7 @@ func (s *modelmanagerSuite) TestCreateModel(c *gc.C) {
 		out.Name = "dowhatimean"
 		out.Type = "iaas"
 		out.UUID = "youyoueyedee"
+		out.Alias = "you"
 		out.ControllerUUID = "youyoueyedeetoo"
 		out.ProviderType = "C-123"
 		out.DefaultSeries = "M*A*S*H"
@@ -85,6 +86,7 @@ func (s *modelmanagerSuite) TestCreateModel(c *gc.C) {
 		Name:           "dowhatimean",
 		Type:           model.IAAS,
 		UUID:           "youyoueyedee",
+		Alias:          "you",
 		ControllerUUID: "youyoueyedeetoo",
 		ProviderType:   "C-123",
 		DefaultSeries:  "M*A*S*H",
@@ -123,6 +125,7 @@ func (s *modelmanagerSuite) TestListModels(c *gc.C) {
 				Model: params.Model{
 					Name:     "yo",
 					UUID:     "wei",
+					Alias:    "w",
 					Type:     "caas",
 					OwnerTag: "user-user@remote",
 				},
@@ -145,6 +148,7 @@ func (s *modelmanagerSuite) TestListModels(c *gc.C) {
 	c.Assert(models, jc.DeepEquals, []base.UserModel{{
 		Name:           "yo",
 		UUID:           "wei",
+		Alias:          "w",
 		Type:           model.CAAS,
 		Owner:          "user@remote",
 		LastConnection: &lastConnection,
@@ -490,6 +494,7 @@ func createModelSummary() *params.ModelSummary {
 	return &params.ModelSummary{
 		Name:               "name",
 		UUID:               "uuid",
+		Alias:              "uu",
 		Type:               "iaas",
 		ControllerUUID:     "controllerUUID",
 		ProviderType:       "aws",
@@ -537,6 +542,7 @@ func (s *modelmanagerSuite) TestListModelSummaries(c *gc.C) {
 	c.Assert(results, gc.HasLen, 2)
 	c.Assert(results[0], jc.DeepEquals, base.UserModelSummary{Name: testModelInfo.Name,
 		UUID:            testModelInfo.UUID,
+		Alias:           testModelInfo.Alias,
 		Type:            model.IAAS,
 		ControllerUUID:  testModelInfo.ControllerUUID,
 		ProviderType:    testModelInfo.ProviderType,
@@ -0,0 +1,40 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package networkreconciler
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client provides access to the NetworkReconciler API facade.
+type Client struct {
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client-side NetworkReconciler facade.
+func NewClient(caller base.APICaller) *Client {
+	return &Client{
+		facade: base.NewFacadeCaller(caller, "NetworkReconciler"),
+	}
+}
+
+// ModelSubnets returns the subnets recorded for the model, excluding
+// FAN overlays.
+func (c *Client) ModelSubnets() ([]params.Subnet, error) {
+	var results params.ListSubnetsResults
+	if err := c.facade.FacadeCall("ModelSubnets", nil, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results.Results, nil
+}
+
+// SetNetworkDrift records how the model's subnets differ from those
+// reported by the provider. An empty drift clears any previously
+// recorded one.
+func (c *Client) SetNetworkDrift(drift params.NetworkDrift) error {
+	return errors.Trace(c.facade.FacadeCall("SetNetworkDrift", drift, nil))
+}
@@ -0,0 +1,62 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package networkreconciler_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/networkreconciler"
+	"github.com/juju/juju/apiserver/params"
+)
+
+type clientSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&clientSuite{})
+
+func (s *clientSuite) TestModelSubnets(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(
+		objType string, version int,
+		id, request string,
+		args, response interface{},
+	) error {
+		c.Check(objType, gc.Equals, "NetworkReconciler")
+		c.Check(request, gc.Equals, "ModelSubnets")
+		c.Check(args, gc.IsNil)
+		*response.(*params.ListSubnetsResults) = params.ListSubnetsResults{
+			Results: []params.Subnet{{CIDR: "10.0.0.0/24", ProviderId: "subnet-0"}},
+		}
+		return nil
+	})
+	client := networkreconciler.NewClient(apiCaller)
+
+	subnets, err := client.ModelSubnets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnets, jc.DeepEquals, []params.Subnet{{CIDR: "10.0.0.0/24", ProviderId: "subnet-0"}})
+}
+
+func (s *clientSuite) TestSetNetworkDrift(c *gc.C) {
+	drift := params.NetworkDrift{
+		Removed: []params.Subnet{{CIDR: "10.0.0.0/24"}},
+	}
+	apiCaller := basetesting.APICallerFunc(func(
+		objType string, version int,
+		id, request string,
+		args, response interface{},
+	) error {
+		c.Check(objType, gc.Equals, "NetworkReconciler")
+		c.Check(request, gc.Equals, "SetNetworkDrift")
+		c.Check(args, jc.DeepEquals, drift)
+		c.Check(response, gc.IsNil)
+		return nil
+	})
+	client := networkreconciler.NewClient(apiCaller)
+
+	err := client.SetNetworkDrift(drift)
+	c.Assert(err, jc.ErrorIsNil)
+}
@@ -122,6 +122,11 @@ type MachineProvisioner interface {
 
 	// SetCharmProfiles records the given slice of charm profile names.
 	SetCharmProfiles([]string) error
+
+	// PublishNetworkBootUserData publishes the rendered user-data of the
+	// machine, which is to be provisioned by network boot, through the
+	// controller.
+	PublishNetworkBootUserData(userData []byte) (params.NetworkBootSeed, error)
 }
 
 // Machine represents a juju machine as seen by the provisioner worker.
@@ -580,3 +585,29 @@ func (m *Machine) SetCharmProfiles(profiles []string) error {
 	}
 	return nil
 }
+
+// PublishNetworkBootUserData implements MachineProvisioner.PublishNetworkBootUserData.
+func (m *Machine) PublishNetworkBootUserData(userData []byte) (params.NetworkBootSeed, error) {
+	if m.st.facade.BestAPIVersion() < 10 {
+		return params.NetworkBootSeed{}, errors.NotSupportedf("network boot user-data publishing by this version of Juju")
+	}
+	var results params.NetworkBootSeedResults
+	args := params.NetworkBootUserDataArgs{
+		Args: []params.NetworkBootUserDataArg{{
+			Tag:      m.tag.String(),
+			UserData: userData,
+		}},
+	}
+	err := m.st.facade.FacadeCall("PublishNetworkBootUserData", args, &results)
+	if err != nil {
+		return params.NetworkBootSeed{}, err
+	}
+	if len(results.Results) != 1 {
+		return params.NetworkBootSeed{}, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return params.NetworkBootSeed{}, result.Error
+	}
+	return *result.Result, nil
+}
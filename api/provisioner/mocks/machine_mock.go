@@ -190,6 +190,19 @@ func (mr *MockMachineProvisionerMockRecorder) ProvisioningInfo() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProvisioningInfo", reflect.TypeOf((*MockMachineProvisioner)(nil).ProvisioningInfo))
 }
 
+// PublishNetworkBootUserData mocks base method
+func (m *MockMachineProvisioner) PublishNetworkBootUserData(arg0 []byte) (params.NetworkBootSeed, error) {
+	ret := m.ctrl.Call(m, "PublishNetworkBootUserData", arg0)
+	ret0, _ := ret[0].(params.NetworkBootSeed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishNetworkBootUserData indicates an expected call of PublishNetworkBootUserData
+func (mr *MockMachineProvisionerMockRecorder) PublishNetworkBootUserData(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishNetworkBootUserData", reflect.TypeOf((*MockMachineProvisioner)(nil).PublishNetworkBootUserData), arg0)
+}
+
 // Refresh mocks base method
 func (m *MockMachineProvisioner) Refresh() error {
 	ret := m.ctrl.Call(m, "Refresh")
@@ -355,6 +355,8 @@ func (st *State) HostChangesForContainer(containerTag names.MachineTag) ([]netwo
 		res[i].BridgeName = bridgeInfo.BridgeName
 		res[i].DeviceName = bridgeInfo.HostDeviceName
 		res[i].MACAddress = bridgeInfo.MACAddress
+		res[i].MTU = bridgeInfo.MTU
+		res[i].OpenvSwitch = bridgeInfo.OpenvSwitch
 	}
 	return res, result.Results[0].ReconfigureDelay, nil
 }
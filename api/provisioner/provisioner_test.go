@@ -5,6 +5,7 @@ package provisioner_test
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/golang/mock/gomock"
@@ -762,6 +763,20 @@ func (s *provisionerSuite) TestSupportsNoContainers(c *gc.C) {
 	c.Assert(containers, gc.DeepEquals, []instance.ContainerType{})
 }
 
+func (s *provisionerSuite) TestPublishNetworkBootUserData(c *gc.C) {
+	apiMachine := s.assertGetOneMachine(c, s.machine.MachineTag())
+	seed, err := apiMachine.PublishNetworkBootUserData([]byte("#cloud-config\n"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(seed.URL, gc.Matches, `https://.*/model/`+s.State.ModelUUID()+`/network-boot/[[:xdigit:]]+`)
+	c.Assert(seed.CACert, gc.Equals, coretesting.CACert)
+
+	token := seed.URL[strings.LastIndex(seed.URL, "/")+1:]
+	data, err := s.State.ConsumeNetworkBootUserData(token)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data.MachineId, gc.Equals, s.machine.Id())
+	c.Assert(string(data.UserData), gc.Equals, "#cloud-config\n")
+}
+
 func (s *provisionerSuite) TestFindToolsNoArch(c *gc.C) {
 	s.testFindTools(c, false, nil, nil)
 }
@@ -0,0 +1,71 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package secondfactor
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client provides access to the SecondFactor facade, with which a user
+// asserts a second authentication factor on their API connection.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient returns a new SecondFactor client.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "SecondFactor")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// Challenge returns a new challenge to be signed by the user's
+// authenticator and passed to Assert.
+func (c *Client) Challenge() (params.SecondFactorChallenge, error) {
+	var result params.SecondFactorChallenge
+	err := c.facade.FacadeCall("Challenge", nil, &result)
+	return result, errors.Trace(err)
+}
+
+// Assert asserts the second factor on the connection, with the
+// authenticator's response to the last challenge.
+func (c *Client) Assert(assertion params.SecondFactorAssertion) error {
+	return errors.Trace(c.facade.FacadeCall("Assert", assertion, nil))
+}
+
+// SetKey registers the PKIX, DER-encoded public key of the user's
+// authenticator. Registering the user's first key requires the
+// enrollment token issued to them by a controller administrator;
+// replacing a key requires instead that the old one has been asserted.
+func (c *Client) SetKey(publicKey []byte, enrollmentToken string) error {
+	args := params.SecondFactorKey{
+		PublicKey:       publicKey,
+		EnrollmentToken: enrollmentToken,
+	}
+	return errors.Trace(c.facade.FacadeCall("SetKey", args, nil))
+}
+
+// IssueEnrollmentToken issues the named local user a one-time token
+// with which to register their first authenticator. The token is to be
+// passed to the user out of band.
+func (c *Client) IssueEnrollmentToken(user string) (string, error) {
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: names.NewUserTag(user).String()}},
+	}
+	var results params.StringResults
+	if err := c.facade.FacadeCall("IssueEnrollmentTokens", args, &results); err != nil {
+		return "", errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return "", errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	if err := results.Results[0].Error; err != nil {
+		return "", errors.Trace(err)
+	}
+	return results.Results[0].Result, nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package secondfactor_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/secondfactor"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type clientSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&clientSuite{})
+
+func (s *clientSuite) TestChallenge(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "SecondFactor")
+		c.Check(request, gc.Equals, "Challenge")
+		c.Check(arg, gc.IsNil)
+		*(result.(*params.SecondFactorChallenge)) = params.SecondFactorChallenge{
+			Challenge:      "challenge",
+			RelyingPartyID: "juju.example.com",
+		}
+		return nil
+	})
+	client := secondfactor.NewClient(apiCaller)
+	challenge, err := client.Challenge()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(challenge, jc.DeepEquals, params.SecondFactorChallenge{
+		Challenge:      "challenge",
+		RelyingPartyID: "juju.example.com",
+	})
+}
+
+func (s *clientSuite) TestAssert(c *gc.C) {
+	assertion := params.SecondFactorAssertion{
+		ClientDataJSON:    []byte("{}"),
+		AuthenticatorData: []byte("data"),
+		Signature:         []byte("sig"),
+	}
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "SecondFactor")
+		c.Check(request, gc.Equals, "Assert")
+		c.Check(arg, jc.DeepEquals, assertion)
+		return &params.Error{Message: "permission denied", Code: params.CodeUnauthorized}
+	})
+	client := secondfactor.NewClient(apiCaller)
+	err := client.Assert(assertion)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *clientSuite) TestSetKey(c *gc.C) {
+	called := false
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		called = true
+		c.Check(objType, gc.Equals, "SecondFactor")
+		c.Check(request, gc.Equals, "SetKey")
+		c.Check(arg, jc.DeepEquals, params.SecondFactorKey{PublicKey: []byte("key"), EnrollmentToken: "token"})
+		return nil
+	})
+	client := secondfactor.NewClient(apiCaller)
+	err := client.SetKey([]byte("key"), "token")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *clientSuite) TestIssueEnrollmentToken(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "SecondFactor")
+		c.Check(request, gc.Equals, "IssueEnrollmentTokens")
+		c.Check(arg, jc.DeepEquals, params.Entities{Entities: []params.Entity{{Tag: "user-bob"}}})
+		*(result.(*params.StringResults)) = params.StringResults{
+			Results: []params.StringResult{{Result: "token"}},
+		}
+		return nil
+	})
+	client := secondfactor.NewClient(apiCaller)
+	token, err := client.IssueEnrollmentToken("bob")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(token, gc.Equals, "token")
+}
+
+func (s *clientSuite) TestIssueEnrollmentTokenError(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		*(result.(*params.StringResults)) = params.StringResults{
+			Results: []params.StringResult{{Error: &params.Error{Message: "boom"}}},
+		}
+		return nil
+	})
+	client := secondfactor.NewClient(apiCaller)
+	_, err := client.IssueEnrollmentToken("bob")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -102,3 +102,97 @@ func (api *API) ReloadSpaces() error {
 	}
 	return err
 }
+
+// RenameSpace renames a space, updating the constraints, endpoint bindings
+// and controller config settings that refer to it.
+func (api *API) RenameSpace(fromName, toName string) error {
+	if api.facade.BestAPIVersion() < 6 {
+		return errors.NewNotSupported(nil, "Controller does not support renaming spaces")
+	}
+	args := params.RenameSpacesParams{
+		Changes: []params.RenameSpaceParams{{
+			FromSpaceTag: names.NewSpaceTag(fromName).String(),
+			ToSpaceTag:   names.NewSpaceTag(toName).String(),
+		}},
+	}
+	var response params.ErrorResults
+	err := api.facade.FacadeCall("RenameSpace", args, &response)
+	if err != nil {
+		if params.IsCodeNotSupported(err) {
+			return errors.NewNotSupported(nil, err.Error())
+		}
+		return errors.Trace(err)
+	}
+	return response.OneError()
+}
+
+// MoveSubnets moves the subnets with the given CIDRs into the named space.
+// If the move was refused because it would break the network requirements
+// of machines or applications, the violations are returned along with the
+// error.
+func (api *API) MoveSubnets(spaceName string, cidrs []string) ([]params.MoveSubnetsViolation, error) {
+	if api.facade.BestAPIVersion() < 6 {
+		return nil, errors.NewNotSupported(nil, "Controller does not support moving subnets")
+	}
+	args := params.MoveSubnetsParams{
+		Args: []params.MoveSubnetsParam{{
+			SpaceTag: names.NewSpaceTag(spaceName).String(),
+			CIDRs:    cidrs,
+		}},
+	}
+	var response params.MoveSubnetsResults
+	err := api.facade.FacadeCall("MoveSubnets", args, &response)
+	if err != nil {
+		if params.IsCodeNotSupported(err) {
+			return nil, errors.NewNotSupported(nil, err.Error())
+		}
+		return nil, errors.Trace(err)
+	}
+	if len(response.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(response.Results))
+	}
+	result := response.Results[0]
+	if result.Error != nil {
+		return result.Violations, result.Error
+	}
+	return nil, nil
+}
+
+// RemoveSpace removes the named space, moving its subnets to the default
+// space. If force is true, constraints, endpoint bindings and controller
+// settings still using the space are rewritten to use the default space.
+func (api *API) RemoveSpace(name string, force bool) error {
+	if api.facade.BestAPIVersion() < 6 {
+		return errors.NewNotSupported(nil, "Controller does not support removing spaces")
+	}
+	args := params.RemoveSpaceParams{
+		Args: []params.RemoveSpaceParam{{
+			SpaceTag: names.NewSpaceTag(name).String(),
+			Force:    force,
+		}},
+	}
+	var response params.ErrorResults
+	err := api.facade.FacadeCall("RemoveSpace", args, &response)
+	if err != nil {
+		if params.IsCodeNotSupported(err) {
+			return errors.NewNotSupported(nil, err.Error())
+		}
+		return errors.Trace(err)
+	}
+	return response.OneError()
+}
+
+// NetworkTopology returns the model's spaces and subnets, the network
+// devices and addresses of its machines, and the ports opened on each
+// subnet, as a single document.
+func (api *API) NetworkTopology() (params.NetworkTopology, error) {
+	var response params.NetworkTopology
+	if api.facade.BestAPIVersion() < 6 {
+		return response, errors.NewNotSupported(nil, "Controller does not support network topology")
+	}
+	err := api.facade.FacadeCall("NetworkTopology", nil, &response)
+	if params.IsCodeNotSupported(err) {
+		return response, errors.NewNotSupported(nil, err.Error())
+	}
+	return response, errors.Trace(err)
+}
@@ -30,7 +30,7 @@ var _ = gc.Suite(&SpacesSuite{})
 func (s *SpacesSuite) init(c *gc.C, args apitesting.APICall) {
 	s.apiCaller = apitesting.APICallChecker(c, args)
 	best := &apitesting.BestVersionCaller{
-		BestVersion:   5,
+		BestVersion:   6,
 		APICallerFunc: s.apiCaller.APICallerFunc,
 	}
 	s.api = spaces.NewAPI(best)
@@ -200,3 +200,114 @@ func (s *SpacesSuite) TestListSpacesManyResults(c *gc.C) {
 func (s *SpacesSuite) TestListSpacesServerError(c *gc.C) {
 	s.testListSpaces(c, nil, errors.New("boom"), "boom")
 }
+
+func (s *SpacesSuite) TestRenameSpace(c *gc.C) {
+	s.init(c, apitesting.APICall{
+		Facade: "Spaces",
+		Method: "RenameSpace",
+		Args: params.RenameSpacesParams{
+			Changes: []params.RenameSpaceParams{{
+				FromSpaceTag: "space-db",
+				ToSpaceTag:   "space-database",
+			}},
+		},
+		Results: params.ErrorResults{
+			Results: []params.ErrorResult{{}},
+		},
+	})
+	err := s.api.RenameSpace("db", "database")
+	c.Assert(s.apiCaller.CallCount, gc.Equals, 1)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *SpacesSuite) TestRenameSpaceNotSupported(c *gc.C) {
+	apicaller := &apitesting.BestVersionCaller{
+		APICallerFunc: apitesting.APICallerFunc(
+			func(string, int, string, string, interface{}, interface{}) error {
+				c.Fatalf("unexpected API call")
+				return nil
+			},
+		),
+		BestVersion: 5,
+	}
+	err := spaces.NewAPI(apicaller).RenameSpace("db", "database")
+	c.Assert(err, gc.ErrorMatches, "Controller does not support renaming spaces")
+}
+
+func (s *SpacesSuite) TestMoveSubnets(c *gc.C) {
+	violations := []params.MoveSubnetsViolation{{
+		Tag:    "application-mysql",
+		Reason: "endpoint \"server\" is bound to space \"db\"",
+	}}
+	s.init(c, apitesting.APICall{
+		Facade: "Spaces",
+		Method: "MoveSubnets",
+		Args: params.MoveSubnetsParams{
+			Args: []params.MoveSubnetsParam{{
+				SpaceTag: "space-other",
+				CIDRs:    []string{"10.0.0.0/24"},
+			}},
+		},
+		Results: params.MoveSubnetsResults{
+			Results: []params.MoveSubnetsResult{{
+				Violations: violations,
+				Error:      &params.Error{Message: "moving subnets would break network requirements"},
+			}},
+		},
+	})
+	gotViolations, err := s.api.MoveSubnets("other", []string{"10.0.0.0/24"})
+	c.Assert(s.apiCaller.CallCount, gc.Equals, 1)
+	c.Assert(err, gc.ErrorMatches, "moving subnets would break network requirements")
+	c.Assert(gotViolations, jc.DeepEquals, violations)
+}
+
+func (s *SpacesSuite) TestRemoveSpace(c *gc.C) {
+	s.init(c, apitesting.APICall{
+		Facade: "Spaces",
+		Method: "RemoveSpace",
+		Args: params.RemoveSpaceParams{
+			Args: []params.RemoveSpaceParam{{
+				SpaceTag: "space-db",
+				Force:    true,
+			}},
+		},
+		Results: params.ErrorResults{
+			Results: []params.ErrorResult{{}},
+		},
+	})
+	err := s.api.RemoveSpace("db", true)
+	c.Assert(s.apiCaller.CallCount, gc.Equals, 1)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *SpacesSuite) TestNetworkTopology(c *gc.C) {
+	expected := params.NetworkTopology{
+		Spaces: []params.Space{{Id: "1", Name: "db"}},
+		Machines: []params.MachineNetworkTopology{{
+			MachineTag: "machine-0",
+		}},
+	}
+	s.init(c, apitesting.APICall{
+		Facade:  "Spaces",
+		Method:  "NetworkTopology",
+		Results: expected,
+	})
+	topology, err := s.api.NetworkTopology()
+	c.Assert(s.apiCaller.CallCount, gc.Equals, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(topology, jc.DeepEquals, expected)
+}
+
+func (s *SpacesSuite) TestNetworkTopologyNotSupported(c *gc.C) {
+	apicaller := &apitesting.BestVersionCaller{
+		APICallerFunc: apitesting.APICallerFunc(
+			func(string, int, string, string, interface{}, interface{}) error {
+				c.Fatalf("unexpected API call")
+				return nil
+			},
+		),
+		BestVersion: 5,
+	}
+	_, err := spaces.NewAPI(apicaller).NetworkTopology()
+	c.Assert(err, gc.ErrorMatches, "Controller does not support network topology")
+}
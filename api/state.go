@@ -128,6 +128,44 @@ func (st *state) Login(tag names.Tag, password, nonce string, macaroons []macaro
 		}
 	}
 
+	return st.completeLogin(tag, result)
+}
+
+// LoginWithAPIKey authenticates using an API key token issued by the
+// APIKeyManager facade. Subsequent requests act on behalf of the key's
+// owner, with access limited to the key's model and access level.
+func (st *state) LoginWithAPIKey(token string) error {
+	request := &params.LoginRequest{
+		APIKey:  token,
+		CLIArgs: utils.CommandString(os.Args...),
+	}
+	var result params.LoginResult
+	if err := st.APICall("Admin", 3, "", "Login", request, &result); err != nil {
+		return errors.Trace(err)
+	}
+	return st.completeLogin(nil, result)
+}
+
+// LoginWithInstanceIdentity authenticates a machine agent that was
+// provisioned without a password, using the signed identity document
+// of the cloud instance it is running on.
+func (st *state) LoginWithInstanceIdentity(tag names.Tag, nonce, document string) error {
+	request := &params.LoginRequest{
+		AuthTag:          tagToString(tag),
+		Nonce:            nonce,
+		InstanceIdentity: document,
+		CLIArgs:          utils.CommandString(os.Args...),
+	}
+	var result params.LoginResult
+	if err := st.APICall("Admin", 3, "", "Login", request, &result); err != nil {
+		return errors.Trace(err)
+	}
+	return st.completeLogin(tag, result)
+}
+
+// completeLogin records the details of a successful login.
+func (st *state) completeLogin(tag names.Tag, result params.LoginResult) error {
+	var err error
 	var controllerAccess string
 	var modelAccess string
 	if result.UserInfo != nil {
@@ -155,6 +193,7 @@ func (st *state) Login(tag names.Tag, password, nonce string, macaroons []macaro
 	if err != nil {
 		return errors.Trace(err)
 	}
+	st.deprecatedFacades = result.DeprecatedFacades
 	return nil
 }
 
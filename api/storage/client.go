@@ -39,25 +39,44 @@ func (c *Client) StorageDetails(tags []names.StorageTag) ([]params.StorageDetail
 	return found.Results, nil
 }
 
+// listStoragePageSize is the number of storage instances fetched by each
+// call to ListStorageDetails, when the controller supports fetching them
+// a page at a time.
+const listStoragePageSize = 500
+
 // ListStorageDetails lists all storage.
 func (c *Client) ListStorageDetails() ([]params.StorageDetails, error) {
-	args := params.StorageFilters{
-		[]params.StorageFilter{{}}, // one empty filter
-	}
-	var results params.StorageDetailsListResults
-	if err := c.facade.FacadeCall("ListStorageDetails", args, &results); err != nil {
-		return nil, errors.Trace(err)
-	}
-	if len(results.Results) != 1 {
-		return nil, errors.Errorf(
-			"expected 1 result, got %d",
-			len(results.Results),
-		)
-	}
-	if results.Results[0].Error != nil {
-		return nil, errors.Trace(results.Results[0].Error)
+	filter := params.StorageFilter{} // one empty filter
+	if c.BestAPIVersion() >= 7 {
+		// Fetch the storage a page at a time so that no single
+		// response is too large, however much storage there is.
+		filter.Page = params.PageParams{Limit: listStoragePageSize}
+	}
+	var all []params.StorageDetails
+	for {
+		args := params.StorageFilters{
+			[]params.StorageFilter{filter},
+		}
+		var results params.StorageDetailsListResults
+		if err := c.facade.FacadeCall("ListStorageDetails", args, &results); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(results.Results) != 1 {
+			return nil, errors.Errorf(
+				"expected 1 result, got %d",
+				len(results.Results),
+			)
+		}
+		if results.Results[0].Error != nil {
+			return nil, errors.Trace(results.Results[0].Error)
+		}
+		all = append(all, results.Results[0].Result...)
+		page := results.Results[0].Page
+		if page == nil || page.NextCursor == "" {
+			return all, nil
+		}
+		filter.Page.Cursor = page.NextCursor
 	}
-	return results.Results[0].Result, nil
 }
 
 // ListPools returns a list of pools that matches given filter.
@@ -150,6 +150,51 @@ func (s *storageMockSuite) TestListStorageDetails(c *gc.C) {
 	c.Assert(found, jc.DeepEquals, expected)
 }
 
+func (s *storageMockSuite) TestListStorageDetailsPages(c *gc.C) {
+	var cursors []string
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string,
+				version int,
+				id, request string,
+				a, result interface{},
+			) error {
+				c.Check(objType, gc.Equals, "Storage")
+				c.Check(request, gc.Equals, "ListStorageDetails")
+				args, ok := a.(params.StorageFilters)
+				c.Assert(ok, jc.IsTrue)
+				c.Assert(args.Filters, gc.HasLen, 1)
+				page := args.Filters[0].Page
+				c.Check(page.Limit, jc.GreaterThan, 0)
+				cursors = append(cursors, page.Cursor)
+
+				results := result.(*params.StorageDetailsListResults)
+				if page.Cursor == "" {
+					results.Results = []params.StorageDetailsListResult{{
+						Result: []params.StorageDetails{{StorageTag: "storage-data-0"}},
+						Page:   &params.PageInfo{NextCursor: "next"},
+					}}
+				} else {
+					results.Results = []params.StorageDetailsListResult{{
+						Result: []params.StorageDetails{{StorageTag: "storage-data-1"}},
+						Page:   &params.PageInfo{},
+					}}
+				}
+				return nil
+			},
+		),
+		BestVersion: 7,
+	}
+	storageClient := storage.NewClient(apiCaller)
+	found, err := storageClient.ListStorageDetails()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cursors, jc.DeepEquals, []string{"", "next"})
+	c.Assert(found, jc.DeepEquals, []params.StorageDetails{
+		{StorageTag: "storage-data-0"},
+		{StorageTag: "storage-data-1"},
+	})
+}
+
 func (s *storageMockSuite) TestListStorageDetailsFacadeCallError(c *gc.C) {
 	msg := "facade failure"
 	apiCaller := basetesting.APICallerFunc(
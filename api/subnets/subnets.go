@@ -8,8 +8,10 @@ import (
 	"gopkg.in/juju/names.v3"
 
 	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/common/networkingcommon"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/network"
+	jujunetwork "github.com/juju/juju/network"
 )
 
 const subnetsFacade = "Subnets"
@@ -96,6 +98,36 @@ func (api *API) ListSubnets(spaceTag *names.SpaceTag, zone string) ([]params.Sub
 	return response.Results, nil
 }
 
+// FanConfig returns the FAN overlays configured for the model.
+func (api *API) FanConfig() (jujunetwork.FanConfig, error) {
+	if api.BestAPIVersion() < 4 {
+		return nil, errors.NewNotSupported(nil, "Controller does not support FAN configuration")
+	}
+	var result params.FanConfigResult
+	if err := api.facade.FacadeCall("FanConfig", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return networkingcommon.FanConfigResultToFanConfig(result)
+}
+
+// SetFanConfig replaces the FAN overlays configured for the model.
+func (api *API) SetFanConfig(fans jujunetwork.FanConfig) error {
+	if api.BestAPIVersion() < 4 {
+		return errors.NewNotSupported(nil, "Controller does not support FAN configuration")
+	}
+	args := params.SetFanConfigParams{
+		Fans: networkingcommon.FanConfigToFanConfigResult(fans).Fans,
+	}
+	var result params.ErrorResult
+	if err := api.facade.FacadeCall("SetFanConfig", args, &result); err != nil {
+		return errors.Trace(err)
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
 func makeAddSubnetsParamsV2(cidr string, providerId network.Id, space names.SpaceTag, zones []string) params.AddSubnetsParamsV2 {
 	var subnetTag string
 	if cidr != "" {
@@ -4,8 +4,7 @@
 package subnets_test
 
 import (
-	"errors"
-
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v3"
@@ -14,6 +13,7 @@ import (
 	"github.com/juju/juju/api/subnets"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/network"
+	jujunetwork "github.com/juju/juju/network"
 	coretesting "github.com/juju/juju/testing"
 )
 
@@ -252,3 +252,52 @@ func (s *SubnetsSuite) TestListSubnetsFails(c *gc.C) {
 	var expectedResults []params.Subnet
 	c.Assert(results, jc.DeepEquals, expectedResults)
 }
+
+func (s *SubnetsSuite) prepareFanAPICall(c *gc.C, args apitesting.APICall) {
+	s.apiCaller = apitesting.APICallChecker(c, args)
+	s.api = subnets.NewAPI(&apitesting.BestVersionCaller{
+		BestVersion:   4,
+		APICallerFunc: s.apiCaller.APICallerFunc,
+	})
+}
+
+func (s *SubnetsSuite) TestFanConfig(c *gc.C) {
+	s.prepareFanAPICall(c, apitesting.APICall{
+		Facade: "Subnets",
+		Method: "FanConfig",
+		Results: params.FanConfigResult{
+			Fans: []params.FanConfigEntry{{Underlay: "172.31.0.0/16", Overlay: "252.0.0.0/8"}},
+		},
+	})
+	fans, err := s.api.FanConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.apiCaller.CallCount, gc.Equals, 1)
+	c.Check(fans.String(), gc.Equals, "172.31.0.0/16=252.0.0.0/8")
+}
+
+func (s *SubnetsSuite) TestSetFanConfig(c *gc.C) {
+	s.prepareFanAPICall(c, apitesting.APICall{
+		Facade: "Subnets",
+		Method: "SetFanConfig",
+		Args: params.SetFanConfigParams{
+			Fans: []params.FanConfigEntry{{Underlay: "172.31.0.0/16", Overlay: "252.0.0.0/8"}},
+		},
+		Results: params.ErrorResult{
+			Error: &params.Error{Message: `FAN overlay "252.0.0.0/8" overlaps subnet "252.1.0.0/16"`},
+		},
+	})
+	fans, err := jujunetwork.ParseFanConfig("172.31.0.0/16=252.0.0.0/8")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.api.SetFanConfig(fans)
+	c.Assert(s.apiCaller.CallCount, gc.Equals, 1)
+	c.Assert(err, gc.ErrorMatches, `FAN overlay "252.0.0.0/8" overlaps subnet "252.1.0.0/16"`)
+}
+
+func (s *SubnetsSuite) TestFanConfigNotSupported(c *gc.C) {
+	s.prepareAPICall(c, apitesting.APICall{})
+	_, err := s.api.FanConfig()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	err = s.api.SetFanConfig(nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(s.apiCaller.CallCount, gc.Equals, 0)
+}
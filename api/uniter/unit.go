@@ -195,6 +195,28 @@ func (u *Unit) EnsureDead() error {
 	return result.OneError()
 }
 
+// SetEgressAddresses records the addresses from which the unit's traffic
+// actually originates, keyed on endpoint binding name, where they differ
+// from the unit's own addresses; for example, when the unit's traffic is
+// routed through a NAT gateway. Addresses keyed on the empty binding
+// apply to all endpoints without addresses of their own.
+func (u *Unit) SetEgressAddresses(bindings map[string][]string) error {
+	if u.st.facade.BestAPIVersion() < 13 {
+		return errors.NotImplementedf("SetEgressAddresses (need V13+)")
+	}
+	var result params.ErrorResults
+	args := params.SetUnitsEgressAddresses{
+		Args: []params.UnitEgressAddresses{
+			{Tag: u.tag.String(), Bindings: bindings},
+		},
+	}
+	err := u.st.facade.FacadeCall("SetEgressAddresses", args, &result)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return result.OneError()
+}
+
 // Watch returns a watcher for observing changes to the unit.
 func (u *Unit) Watch() (watcher.NotifyWatcher, error) {
 	return common.Watch(u.st.facade, "Watch", u.tag)
@@ -458,6 +480,12 @@ func (u *Unit) AvailabilityZone() (string, error) {
 // OpenPorts sets the policy of the port range with protocol to be
 // opened.
 func (u *Unit) OpenPorts(protocol string, fromPort, toPort int) error {
+	return u.OpenPortsInHook("", protocol, fromPort, toPort)
+}
+
+// OpenPortsInHook sets the policy of the port range with protocol to be
+// opened, recording that it was opened in the named hook.
+func (u *Unit) OpenPortsInHook(hook, protocol string, fromPort, toPort int) error {
 	var result params.ErrorResults
 	args := params.EntitiesPortRanges{
 		Entities: []params.EntityPortRange{{
@@ -465,6 +493,7 @@ func (u *Unit) OpenPorts(protocol string, fromPort, toPort int) error {
 			Protocol: protocol,
 			FromPort: fromPort,
 			ToPort:   toPort,
+			Hook:     hook,
 		}},
 	}
 	err := u.st.facade.FacadeCall("OpenPorts", args, &result)
@@ -477,6 +506,12 @@ func (u *Unit) OpenPorts(protocol string, fromPort, toPort int) error {
 // ClosePorts sets the policy of the port range with protocol to be
 // closed.
 func (u *Unit) ClosePorts(protocol string, fromPort, toPort int) error {
+	return u.ClosePortsInHook("", protocol, fromPort, toPort)
+}
+
+// ClosePortsInHook sets the policy of the port range with protocol to be
+// closed, recording that it was closed in the named hook.
+func (u *Unit) ClosePortsInHook(hook, protocol string, fromPort, toPort int) error {
 	var result params.ErrorResults
 	args := params.EntitiesPortRanges{
 		Entities: []params.EntityPortRange{{
@@ -484,6 +519,7 @@ func (u *Unit) ClosePorts(protocol string, fromPort, toPort int) error {
 			Protocol: protocol,
 			FromPort: fromPort,
 			ToPort:   toPort,
+			Hook:     hook,
 		}},
 	}
 	err := u.st.facade.FacadeCall("ClosePorts", args, &result)
@@ -811,3 +847,27 @@ func (u *Unit) NetworkInfo(bindings []string, relationId *int) (map[string]param
 
 	return results.Results, nil
 }
+
+// RelatedUnitAddresses returns the addresses of the units related to
+// the unit on the named endpoint.
+func (u *Unit) RelatedUnitAddresses(endpoint string) ([]params.RelatedUnitAddress, error) {
+	if u.st.facade.BestAPIVersion() < 13 {
+		return nil, errors.NotImplementedf("RelatedUnitAddresses (need V14+)")
+	}
+	var results params.RelatedUnitAddressesResults
+	args := params.UnitEndpoints{
+		Entities: []params.UnitEndpoint{{Tag: u.tag.String(), Endpoint: endpoint}},
+	}
+	err := u.st.facade.FacadeCall("RelatedUnitAddresses", args, &results)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Units, nil
+}
@@ -148,6 +148,16 @@ func (s *unitSuite) TestUnitStatus(c *gc.C) {
 	})
 }
 
+func (s *unitSuite) TestSetEgressAddresses(c *gc.C) {
+	bindings := map[string][]string{"": {"203.0.113.7"}}
+	err := s.apiUnit.SetEgressAddresses(bindings)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.wordpressUnit.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.wordpressUnit.EgressAddresses(), jc.DeepEquals, bindings)
+}
+
 func (s *unitSuite) TestEnsureDead(c *gc.C) {
 	c.Assert(s.wordpressUnit.Life(), gc.Equals, state.Alive)
 
@@ -522,6 +532,22 @@ func (s *unitSuite) TestNetworkInfo(c *gc.C) {
 	c.Assert(called, gc.Equals, 2)
 }
 
+func (s *unitSuite) TestRelatedUnitAddresses(c *gc.C) {
+	s.addMachineAppCharmAndUnit(c, "mysql")
+	rel := s.addRelation(c, "wordpress", "mysql")
+
+	addresses, err := s.apiUnit.RelatedUnitAddresses("db")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addresses, jc.DeepEquals, []params.RelatedUnitAddress{{
+		Unit:        "mysql/0",
+		Application: "mysql",
+		RelationId:  rel.Id(),
+	}})
+
+	_, err = s.apiUnit.RelatedUnitAddresses("nonsense")
+	c.Assert(err, gc.ErrorMatches, `application "wordpress" has no "nonsense" relation`)
+}
+
 func (s *unitSuite) TestConfigSettings(c *gc.C) {
 	// Make sure ConfigSettings returns an error when
 	// no charm URL is set, as its state counterpart does.
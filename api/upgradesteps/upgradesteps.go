@@ -5,6 +5,7 @@ package upgradesteps
 
 import (
 	"github.com/juju/errors"
+	"github.com/juju/version"
 	"gopkg.in/juju/names.v3"
 
 	"github.com/juju/juju/api/base"
@@ -44,3 +45,20 @@ func (c *Client) ResetKVMMachineModificationStatusIdle(tag names.Tag) error {
 	}
 	return nil
 }
+
+// SetUpgradeStepsCompleted tells the controller that the agent with the
+// given tag has completed its upgrade steps for the given version.
+func (c *Client) SetUpgradeStepsCompleted(tag names.Tag, vers version.Number) error {
+	if c.facade.BestAPIVersion() < 2 {
+		return errors.NotSupportedf("recording upgrade steps completed by this version of Juju")
+	}
+	var results params.ErrorResults
+	args := params.UpgradeStepsCompletedArgs{
+		Args: []params.UpgradeStepsCompleted{{Tag: tag.String(), Version: vers}},
+	}
+	err := c.facade.FacadeCall("SetUpgradeStepsCompleted", args, &results)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
@@ -5,7 +5,9 @@ package upgradesteps_test
 
 import (
 	"github.com/golang/mock/gomock"
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v3"
 
@@ -52,6 +54,34 @@ func (s *upgradeStepsSuite) TestResetKVMMachineModificationStatusIdleError(c *gc
 	c.Assert(err, gc.ErrorMatches, "did not find")
 }
 
+func (s *upgradeStepsSuite) TestSetUpgradeStepsCompleted(c *gc.C) {
+	defer s.setupMocks(c).Finish()
+
+	vers := version.MustParse("2.7.0")
+	args := params.UpgradeStepsCompletedArgs{
+		Args: []params.UpgradeStepsCompleted{{Tag: s.tag.String(), Version: vers}},
+	}
+	fExp := s.fCaller.EXPECT()
+	fExp.BestAPIVersion().Return(2)
+	fExp.FacadeCall("SetUpgradeStepsCompleted", args, gomock.Any()).SetArg(2, params.ErrorResults{
+		Results: []params.ErrorResult{{}},
+	})
+
+	client := upgradesteps.NewClientFromFacade(s.fCaller)
+	err := client.SetUpgradeStepsCompleted(s.tag, vers)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *upgradeStepsSuite) TestSetUpgradeStepsCompletedNotSupported(c *gc.C) {
+	defer s.setupMocks(c).Finish()
+
+	s.fCaller.EXPECT().BestAPIVersion().Return(1)
+
+	client := upgradesteps.NewClientFromFacade(s.fCaller)
+	err := client.SetUpgradeStepsCompleted(s.tag, version.MustParse("2.7.0"))
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 func (s *upgradeStepsSuite) setupMocks(c *gc.C) *gomock.Controller {
 	ctrl := gomock.NewController(c)
 	s.fCaller = mocks.NewMockFacadeCaller(ctrl)
@@ -134,6 +134,12 @@ func (a *admin) login(req params.LoginRequest, loginVersion int) (params.LoginRe
 	if err != nil {
 		return fail, errors.Trace(err)
 	}
+	if a.root.entity != nil && !authResult.controllerMachineLogin {
+		// Controller agents are never rate limited.
+		apiRoot = restrictRoot(apiRoot, rateLimitedMethods(a.root.shared, a.root.entity.Tag()))
+	}
+	apiRoot = newDrainingRoot(apiRoot, a.srv.drainer)
+	apiRoot = newDeprecationRoot(apiRoot, a.srv.facades)
 
 	var facadeFilters []facadeFilterFunc
 	var modelTag string
@@ -153,8 +159,18 @@ func (a *admin) login(req params.LoginRequest, loginVersion int) (params.LoginRe
 		return fail, errors.Trace(err)
 	}
 
-	recorderFactory := observer.NewRecorderFactory(
-		a.apiObserver, auditRecorder, auditConfig.CaptureAPIArgs,
+	recorderFactory := func() rpc.Recorder {
+		// Changes to the audit config apply to connections that
+		// have already logged in.
+		captureArgs := a.srv.GetAuditConfig().CaptureAPIArgs
+		return observer.NewRecorderFactory(a.apiObserver, auditRecorder, captureArgs)()
+	}
+	facades := withoutCanaryVersions(
+		a.srv.facades,
+		filterFacades(a.srv.facades, facadeFilters...),
+		func(flag string) bool {
+			return a.root.shared.modelFeatureEnabled(a.root.model.UUID(), flag)
+		},
 	)
 	a.root.rpcConn.ServeRoot(apiRoot, recorderFactory, serverError)
 	return params.LoginResult{
@@ -164,7 +180,9 @@ func (a *admin) login(req params.LoginRequest, loginVersion int) (params.LoginRe
 		ServerVersion: jujuversion.Current.String(),
 		PublicDNSName: a.srv.publicDNSName(),
 		ModelTag:      modelTag,
-		Facades:       filterFacades(a.srv.facades, facadeFilters...),
+		Facades:       facades,
+
+		DeprecatedFacades: deprecatedFacades(a.srv.facades, facades),
 	}, nil
 }
 
@@ -174,7 +192,10 @@ func (a *admin) getAuditRecorder(req params.LoginRequest, authResult *authResult
 	}
 	// Wrap the audit logger in a filter that prevents us from logging
 	// lots of readonly conversations (like "juju status" requests).
-	filter := observer.MakeInterestingRequestFilter(cfg.ExcludeMethods)
+	filter := func(req auditlog.Request) bool {
+		excludeMethods := a.srv.GetAuditConfig().ExcludeMethods
+		return observer.MakeInterestingRequestFilter(excludeMethods)(req)
+	}
 	result, err := auditlog.NewRecorder(
 		observer.NewAuditLogFilter(cfg.Target, filter),
 		a.srv.clock,
@@ -199,6 +220,7 @@ type authResult struct {
 	userLogin              bool // false if anonymous user
 	controllerOnlyLogin    bool
 	controllerMachineLogin bool
+	apiKeyLogin            bool
 	userInfo               *params.AuthUserInfo
 }
 
@@ -282,7 +304,12 @@ func (a *admin) authenticate(req params.LoginRequest) (*authResult, error) {
 		if err != nil {
 			return nil, a.handleAuthError(err)
 		}
+		if authInfo.AccessLimit != "" && result.controllerOnlyLogin {
+			// API keys are scoped to a single model.
+			return nil, errors.Trace(common.ErrPerm)
+		}
 		result.controllerMachineLogin = authInfo.Controller
+		result.apiKeyLogin = authInfo.AccessLimit != ""
 		// controllerConn is used to indicate a connection from the controller
 		// to a non-controller model.
 		controllerConn := false
@@ -293,6 +320,7 @@ func (a *admin) authenticate(req params.LoginRequest) (*authResult, error) {
 			controllerConn = true
 		}
 		a.root.entity = authInfo.Entity
+		a.root.accessLimit = authInfo.AccessLimit
 		// TODO(wallyworld) - we can't yet observe anonymous logins as entity must be non-nil
 		a.apiObserver.Login(
 			authInfo.Entity.Tag(),
@@ -474,6 +502,15 @@ func (a *admin) checkUserPermissions(userTag names.UserTag, controllerOnlyLogin
 		logger.Debugf("model login: user %s has %q for controller; %q for model %s",
 			userTag.Id(), controllerAccess, modelAccess, a.root.model.ModelTag().Id())
 	}
+	if limit := a.root.accessLimit; limit != "" {
+		// Logins using API keys never get more than the key's access.
+		if modelAccess.GreaterModelAccessThan(limit) {
+			modelAccess = limit
+		}
+		if controllerAccess.GreaterControllerAccessThan(permission.LoginAccess) {
+			controllerAccess = permission.LoginAccess
+		}
+	}
 	return &params.AuthUserInfo{
 		Identity:         userTag.String(),
 		ControllerAccess: string(controllerAccess),
@@ -500,6 +537,35 @@ func filterFacades(registry *facade.Registry, allowFacadeAllMustMatch ...facadeF
 	return out
 }
 
+// withoutCanaryVersions removes the facade versions that are only
+// available to models with a feature flag enabled (see
+// facade.Registry.RegisterCanary) unless featureEnabled reports that
+// the flag is enabled for the logged in model.
+func withoutCanaryVersions(
+	registry *facade.Registry,
+	facades []params.FacadeVersions,
+	featureEnabled func(flag string) bool,
+) []params.FacadeVersions {
+	out := make([]params.FacadeVersions, 0, len(facades))
+	for _, f := range facades {
+		versions := make([]int, 0, len(f.Versions))
+		for _, version := range f.Versions {
+			feature, err := registry.GetFeature(f.Name, version)
+			if err != nil || (feature != "" && !featureEnabled(feature)) {
+				continue
+			}
+			versions = append(versions, version)
+		}
+		if len(versions) > 0 {
+			out = append(out, params.FacadeVersions{
+				Name:     f.Name,
+				Versions: versions,
+			})
+		}
+	}
+	return out
+}
+
 func (a *admin) maintenanceInProgress() bool {
 	if !a.srv.upgradeComplete() {
 		return true
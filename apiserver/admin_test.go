@@ -1089,6 +1089,136 @@ func (s *loginSuite) TestLoginResultLocalUserEveryoneCreateOnlyNonLocal(c *gc.C)
 	c.Check(result.UserInfo.ModelAccess, gc.Equals, "admin")
 }
 
+func (s *loginSuite) issueAPIKey(c *gc.C, access permission.Access) (*state.User, *state.APIKey, string) {
+	user := s.Factory.MakeUser(c, nil)
+	key, secret, err := s.State.AddAPIKey(state.AddAPIKeyArgs{
+		Model:  s.Model.ModelTag(),
+		Owner:  user.UserTag(),
+		Access: access,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return user, key, state.APIKeyToken(key.Id(), secret)
+}
+
+func (s *loginSuite) loginWithAPIKey(c *gc.C, info *api.Info, token string) (params.LoginResult, error) {
+	conn := s.openAPIWithoutLogin(c, info)
+	var result params.LoginResult
+	request := &params.LoginRequest{APIKey: token}
+	err := conn.APICall("Admin", 3, "", "Login", request, &result)
+	return result, err
+}
+
+func (s *loginSuite) TestLoginWithAPIKey(c *gc.C) {
+	info, srv := s.newServer(c)
+	defer assertStop(c, srv)
+	info.ModelTag = s.Model.ModelTag()
+
+	user, _, token := s.issueAPIKey(c, permission.ReadAccess)
+	result, err := s.loginWithAPIKey(c, info, token)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.UserInfo, gc.NotNil)
+	c.Check(result.UserInfo.Identity, gc.Equals, user.Tag().String())
+	c.Check(result.UserInfo.ControllerAccess, gc.Equals, "login")
+	// The user is a model admin, but the key only grants read access.
+	c.Check(result.UserInfo.ModelAccess, gc.Equals, "read")
+}
+
+func (s *loginSuite) TestLoginWithAPIKeyViaOpen(c *gc.C) {
+	info, srv := s.newServer(c)
+	defer assertStop(c, srv)
+	info.ModelTag = s.Model.ModelTag()
+	info.Tag = nil
+	info.Password = ""
+	info.Macaroons = nil
+
+	user, _, token := s.issueAPIKey(c, permission.WriteAccess)
+	info.APIKey = token
+	conn, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer conn.Close()
+	c.Assert(conn.AuthTag(), gc.Equals, user.Tag())
+	c.Assert(conn.ModelAccess(), gc.Equals, "write")
+}
+
+func (s *loginSuite) TestAPIKeyLoginCannotManageAPIKeys(c *gc.C) {
+	info, srv := s.newServer(c)
+	defer assertStop(c, srv)
+	info.ModelTag = s.Model.ModelTag()
+	info.Tag = nil
+	info.Password = ""
+	info.Macaroons = nil
+
+	_, _, token := s.issueAPIKey(c, permission.AdminAccess)
+	info.APIKey = token
+	conn, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer conn.Close()
+
+	// Otherwise, an admin key could issue itself keys without its
+	// expiry, or with more access.
+	var result params.IssueAPIKeyResults
+	err = conn.APICall("APIKeyManager", 1, "", "IssueAPIKeys", params.IssueAPIKeysArgs{
+		Keys: []params.IssueAPIKeyArg{{
+			ModelTag: s.Model.ModelTag().String(),
+			Access:   string(permission.AdminAccess),
+		}},
+	}, &result)
+	c.Assert(err, gc.ErrorMatches, `facade "APIKeyManager" not supported for .* connection`)
+	c.Assert(params.ErrCode(err), gc.Equals, params.CodeNotSupported)
+}
+
+func (s *loginSuite) TestLoginWithRevokedAPIKey(c *gc.C) {
+	info, srv := s.newServer(c)
+	defer assertStop(c, srv)
+	info.ModelTag = s.Model.ModelTag()
+
+	_, key, token := s.issueAPIKey(c, permission.ReadAccess)
+	err := key.Revoke()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.loginWithAPIKey(c, info, token)
+	assertInvalidEntityPassword(c, err)
+}
+
+func (s *loginSuite) TestLoginWithAPIKeyBadSecret(c *gc.C) {
+	info, srv := s.newServer(c)
+	defer assertStop(c, srv)
+	info.ModelTag = s.Model.ModelTag()
+
+	_, key, _ := s.issueAPIKey(c, permission.ReadAccess)
+	_, err := s.loginWithAPIKey(c, info, state.APIKeyToken(key.Id(), "wrong"))
+	assertInvalidEntityPassword(c, err)
+}
+
+func (s *loginSuite) TestLoginWithAPIKeyOtherModel(c *gc.C) {
+	info, srv := s.newServer(c)
+	defer assertStop(c, srv)
+	modelState := s.Factory.MakeModel(c, nil)
+	defer modelState.Close()
+	model, err := modelState.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	info.ModelTag = model.ModelTag()
+
+	_, _, token := s.issueAPIKey(c, permission.ReadAccess)
+	_, err = s.loginWithAPIKey(c, info, token)
+	c.Assert(err, gc.ErrorMatches, `API key ".*" is not valid for model ".*"`)
+	c.Assert(params.ErrCode(err), gc.Equals, params.CodeUnauthorized)
+}
+
+func (s *loginSuite) TestLoginWithAPIKeyControllerOnly(c *gc.C) {
+	info, srv := s.newServer(c)
+	defer assertStop(c, srv)
+	info.ModelTag = names.ModelTag{}
+
+	key, secret, err := s.State.AddAPIKey(state.AddAPIKeyArgs{
+		Model:  s.State.ControllerModelTag(),
+		Owner:  s.AdminUserTag(c),
+		Access: permission.AdminAccess,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.loginWithAPIKey(c, info, state.APIKeyToken(key.Id(), secret))
+	assertPermissionDenied(c, err)
+}
+
 func (s *loginSuite) assertRemoteModel(c *gc.C, api api.Connection, expected names.ModelTag) {
 	// Look at what the api thinks it has.
 	tag, ok := api.ModelTag()
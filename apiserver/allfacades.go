@@ -15,6 +15,7 @@ import (
 	"github.com/juju/juju/apiserver/facades/agent/agent"
 	"github.com/juju/juju/apiserver/facades/agent/caasagent"
 	"github.com/juju/juju/apiserver/facades/agent/caasoperator"
+	"github.com/juju/juju/apiserver/facades/agent/catrustupdater"
 	"github.com/juju/juju/apiserver/facades/agent/credentialvalidator"
 	"github.com/juju/juju/apiserver/facades/agent/deployer"
 	"github.com/juju/juju/apiserver/facades/agent/diskmanager"
@@ -44,10 +45,12 @@ import (
 	"github.com/juju/juju/apiserver/facades/agent/upgradesteps"
 	"github.com/juju/juju/apiserver/facades/client/action"
 	"github.com/juju/juju/apiserver/facades/client/annotations" // ModelUser Write
+	"github.com/juju/juju/apiserver/facades/client/apikeymanager"
 	"github.com/juju/juju/apiserver/facades/client/application" // ModelUser Write
 	"github.com/juju/juju/apiserver/facades/client/applicationoffers"
-	"github.com/juju/juju/apiserver/facades/client/backups" // ModelUser Write
-	"github.com/juju/juju/apiserver/facades/client/block"   // ModelUser Write
+	"github.com/juju/juju/apiserver/facades/client/autoscaler" // ModelUser Write
+	"github.com/juju/juju/apiserver/facades/client/backups"    // ModelUser Write
+	"github.com/juju/juju/apiserver/facades/client/block"      // ModelUser Write
 	"github.com/juju/juju/apiserver/facades/client/bundle"
 	"github.com/juju/juju/apiserver/facades/client/charms"     // ModelUser Write
 	"github.com/juju/juju/apiserver/facades/client/client"     // ModelUser Write
@@ -66,13 +69,16 @@ import (
 	"github.com/juju/juju/apiserver/facades/client/modelmanager" // ModelUser Write
 	"github.com/juju/juju/apiserver/facades/client/payloads"
 	"github.com/juju/juju/apiserver/facades/client/resources"
+	"github.com/juju/juju/apiserver/facades/client/secondfactor"
 	"github.com/juju/juju/apiserver/facades/client/spaces"    // ModelUser Write
 	"github.com/juju/juju/apiserver/facades/client/sshclient" // ModelUser Write
 	"github.com/juju/juju/apiserver/facades/client/storage"
 	"github.com/juju/juju/apiserver/facades/client/subnets"
 	"github.com/juju/juju/apiserver/facades/client/usermanager"
 	"github.com/juju/juju/apiserver/facades/controller/actionpruner"
+	"github.com/juju/juju/apiserver/facades/controller/actionwebhooks"
 	"github.com/juju/juju/apiserver/facades/controller/agenttools"
+	"github.com/juju/juju/apiserver/facades/controller/annotationtagger"
 	"github.com/juju/juju/apiserver/facades/controller/applicationscaler"
 	"github.com/juju/juju/apiserver/facades/controller/caasfirewaller"
 	"github.com/juju/juju/apiserver/facades/controller/caasoperatorprovisioner"
@@ -85,6 +91,7 @@ import (
 	"github.com/juju/juju/apiserver/facades/controller/externalcontrollerupdater"
 	"github.com/juju/juju/apiserver/facades/controller/firewaller"
 	"github.com/juju/juju/apiserver/facades/controller/imagemetadata"
+	"github.com/juju/juju/apiserver/facades/controller/importvalidator"
 	"github.com/juju/juju/apiserver/facades/controller/instancepoller"
 	"github.com/juju/juju/apiserver/facades/controller/lifeflag"
 	"github.com/juju/juju/apiserver/facades/controller/logfwd"
@@ -93,6 +100,7 @@ import (
 	"github.com/juju/juju/apiserver/facades/controller/migrationmaster"
 	"github.com/juju/juju/apiserver/facades/controller/migrationtarget"
 	"github.com/juju/juju/apiserver/facades/controller/modelupgrader"
+	"github.com/juju/juju/apiserver/facades/controller/networkreconciler"
 	"github.com/juju/juju/apiserver/facades/controller/remoterelations"
 	"github.com/juju/juju/apiserver/facades/controller/resumer"
 	"github.com/juju/juju/apiserver/facades/controller/singular"
@@ -133,10 +141,14 @@ func AllFacades() *facade.Registry {
 	reg("Action", 2, action.NewActionAPIV2)
 	reg("Action", 3, action.NewActionAPIV3)
 	reg("Action", 4, action.NewActionAPIV4)
+	reg("Action", 5, action.NewActionAPIV5) // adds webhooks and idempotency keys to Enqueue, and pagination to ListAll
 	reg("ActionPruner", 1, actionpruner.NewAPI)
+	reg("ActionWebhooks", 1, actionwebhooks.NewFacade)
 	reg("Agent", 2, agent.NewAgentAPIV2)
 	reg("AgentTools", 1, agenttools.NewFacade)
+	reg("AnnotationTagger", 1, annotationtagger.NewFacade)
 	reg("Annotations", 2, annotations.NewAPI)
+	reg("APIKeyManager", 1, apikeymanager.NewAPIKeyManagerAPI)
 
 	// Application facade versions 1-4 share NewFacadeV4 as
 	// the newer methodology for versioning wasn't started with
@@ -151,10 +163,12 @@ func AllFacades() *facade.Registry {
 	reg("Application", 8, application.NewFacadeV8)
 	reg("Application", 9, application.NewFacadeV9)   // ApplicationInfo; generational config; Force on App, Relation and Unit Removal.
 	reg("Application", 10, application.NewFacadeV10) // --force and --no-wait parameters
+	reg("Application", 11, application.NewFacadeV11) // idempotency keys; egress rules; per-endpoint expose; RotateUnitPasswords; SetSubordinatePolicies
 
 	reg("ApplicationOffers", 1, applicationoffers.NewOffersAPI)
 	reg("ApplicationOffers", 2, applicationoffers.NewOffersAPIV2)
 	reg("ApplicationScaler", 1, applicationscaler.NewAPI)
+	reg("Autoscaler", 1, autoscaler.NewAPI)
 	reg("Backups", 1, backups.NewFacade)
 	reg("Backups", 2, backups.NewFacadeV2)
 	reg("Block", 2, block.NewAPI)
@@ -176,11 +190,14 @@ func AllFacades() *facade.Registry {
 	// CAAS related facades.
 	// Move these to the correct place above once the feature flag disappears.
 	reg("CAASFirewaller", 1, caasfirewaller.NewStateFacade)
-	reg("CAASOperator", 1, caasoperator.NewStateFacade)
+	reg("CAASOperator", 1, caasoperator.NewStateFacadeV1)
+	reg("CAASOperator", 2, caasoperator.NewStateFacade) // Adds ClaimOperator and WaitOperatorReleased
 	reg("CAASAgent", 1, caasagent.NewStateFacade)
-	reg("CAASOperatorProvisioner", 1, caasoperatorprovisioner.NewStateCAASOperatorProvisionerAPI)
+	reg("CAASOperatorProvisioner", 1, caasoperatorprovisioner.NewStateCAASOperatorProvisionerAPIV1)
+	reg("CAASOperatorProvisioner", 2, caasoperatorprovisioner.NewStateCAASOperatorProvisionerAPI) // Adds SetOperatorStatus, WatchForModelConfigChanges, ModelConfig, OperatorVersions; OperatorProvisioningInfo takes applications
 	reg("CAASOperatorUpgrader", 1, caasoperatorupgrader.NewStateCAASOperatorUpgraderAPI)
 	reg("CAASUnitProvisioner", 1, caasunitprovisioner.NewStateFacade)
+	reg("CATrustUpdater", 1, catrustupdater.NewCATrustUpdaterAPI)
 
 	reg("Controller", 3, controller.NewControllerAPIv3)
 	reg("Controller", 4, controller.NewControllerAPIv4)
@@ -188,6 +205,7 @@ func AllFacades() *facade.Registry {
 	reg("Controller", 6, controller.NewControllerAPIv6)
 	reg("Controller", 7, controller.NewControllerAPIv7)
 	reg("Controller", 8, controller.NewControllerAPIv8)
+	reg("Controller", 9, controller.NewControllerAPIv9) // adds WatchModelSummaries, ModelFeatures, UpdateModelFeatures, ModelStats, AuditRecords, UpgradePreChecks and UpgradeStragglers
 	reg("CrossModelRelations", 1, crossmodelrelations.NewStateCrossModelRelationsAPI)
 	reg("CrossController", 1, crosscontroller.NewStateCrossControllerAPI)
 	reg("CredentialManager", 1, credentialmanager.NewCredentialManagerAPI)
@@ -195,12 +213,14 @@ func AllFacades() *facade.Registry {
 	reg("CredentialValidator", 2, credentialvalidator.NewCredentialValidatorAPI) // adds WatchModelCredential
 	reg("ExternalControllerUpdater", 1, externalcontrollerupdater.NewStateAPI)
 
-	reg("Deployer", 1, deployer.NewDeployerAPI)
+	reg("Deployer", 1, deployer.NewDeployerAPIV1)
+	reg("Deployer", 2, deployer.NewDeployerAPI) // adds SetAgentStatus and PasswordRotationRequested
 	reg("DiskManager", 2, diskmanager.NewDiskManagerAPI)
 	reg("FanConfigurer", 1, fanconfigurer.NewFanConfigurerAPI)
 	reg("Firewaller", 3, firewaller.NewStateFirewallerAPIV3)
 	reg("Firewaller", 4, firewaller.NewStateFirewallerAPIV4)
 	reg("Firewaller", 5, firewaller.NewStateFirewallerAPIV5)
+	reg("Firewaller", 6, firewaller.NewStateFirewallerAPIV6) // Adds GetEgressRules, WatchFirewallRules and GetExposeInfo.
 	reg("FirewallRules", 1, firewallrules.NewFacade)
 	reg("HighAvailability", 2, highavailability.NewHighAvailabilityAPI)
 	reg("HostKeyReporter", 1, hostkeyreporter.NewFacade)
@@ -211,10 +231,14 @@ func AllFacades() *facade.Registry {
 		reg("ImageMetadataManager", 1, imagemetadatamanager.NewAPI)
 	}
 
+	reg("ImportValidator", 1, importvalidator.NewFacade)
+
 	reg("InstanceMutater", 1, instancemutater.NewFacadeV1)
 	reg("InstanceMutater", 2, instancemutater.NewFacadeV2)
+	reg("InstanceMutater", 3, instancemutater.NewFacadeV3)
 
-	reg("InstancePoller", 3, instancepoller.NewFacade)
+	reg("InstancePoller", 3, instancepoller.NewFacadeV3)
+	reg("InstancePoller", 4, instancepoller.NewFacade) // Adds SetEgressAddresses.
 	reg("KeyManager", 1, keymanager.NewKeyManagerAPI)
 	reg("KeyUpdater", 1, keyupdater.NewKeyUpdaterAPI)
 
@@ -222,7 +246,8 @@ func AllFacades() *facade.Registry {
 
 	reg("LifeFlag", 1, lifeflag.NewExternalFacade)
 	reg("Logger", 1, loggerapi.NewLoggerAPI)
-	reg("LogForwarding", 1, logfwd.NewFacade)
+	reg("LogForwarding", 1, logfwd.NewFacadeV1)
+	reg("LogForwarding", 2, logfwd.NewFacade)
 	reg("MachineActions", 1, machineactions.NewExternalFacade)
 
 	reg("MachineManager", 2, machinemanager.NewFacade)
@@ -230,9 +255,11 @@ func AllFacades() *facade.Registry {
 	reg("MachineManager", 4, machinemanager.NewFacadeV4) // Adds DestroyMachineWithParams.
 	reg("MachineManager", 5, machinemanager.NewFacadeV5) // Adds UpgradeSeriesPrepare, removes UpdateMachineSeries.
 	reg("MachineManager", 6, machinemanager.NewFacadeV6) // DestroyMachinesWithParams gains maxWait.
+	reg("MachineManager", 7, machinemanager.NewFacadeV7) // Adds RebootMachines, UpgradeSeriesPrepareMachines, SetMachinesAnnotations, RetryProvisioningMachines, RollbackLXDProfiles, CordonMachines, UncordonMachines, PortsHistory, RequestReboot and RebootStatus; AddMachines takes idempotency keys.
 
 	reg("MachineUndertaker", 1, machineundertaker.NewFacade)
-	reg("Machiner", 1, machine.NewMachinerAPI)
+	reg("Machiner", 1, machine.NewMachinerAPIV1)
+	reg("Machiner", 2, machine.NewMachinerAPI) // adds SetObservedNetworkConfigChanges, SetBootIDs, SetHostnames, Hostnames, SetShutdownProgress, SetHostInfo and Cordoned; SetObservedNetworkConfig returns validations
 
 	reg("MeterStatus", 1, meterstatus.NewMeterStatusFacade)
 	reg("MetricsAdder", 2, metricsadder.NewMetricsAdderAPI)
@@ -257,6 +284,8 @@ func AllFacades() *facade.Registry {
 	reg("ModelManager", 8, modelmanager.NewFacadeV8) // ModelInfo gains credential validity in return.
 	reg("ModelUpgrader", 1, modelupgrader.NewStateFacade)
 
+	reg("NetworkReconciler", 1, networkreconciler.NewFacade)
+
 	reg("Payloads", 1, payloads.NewFacade)
 	regHookContext(
 		"PayloadsHookContext", 1,
@@ -267,11 +296,12 @@ func AllFacades() *facade.Registry {
 	reg("Pinger", 1, NewPinger)
 	reg("Provisioner", 3, provisioner.NewProvisionerAPIV4) // Yes this is weird.
 	reg("Provisioner", 4, provisioner.NewProvisionerAPIV4)
-	reg("Provisioner", 5, provisioner.NewProvisionerAPIV5) // v5 adds DistributionGroupByMachineId()
-	reg("Provisioner", 6, provisioner.NewProvisionerAPIV6) // v6 adds more proxy settings
-	reg("Provisioner", 7, provisioner.NewProvisionerAPIV7) // v7 adds charm profile watcher
-	reg("Provisioner", 8, provisioner.NewProvisionerAPIV8) // v8 adds changes charm profile and modification status
-	reg("Provisioner", 9, provisioner.NewProvisionerAPIV9) // v9 adds supported containers
+	reg("Provisioner", 5, provisioner.NewProvisionerAPIV5)   // v5 adds DistributionGroupByMachineId()
+	reg("Provisioner", 6, provisioner.NewProvisionerAPIV6)   // v6 adds more proxy settings
+	reg("Provisioner", 7, provisioner.NewProvisionerAPIV7)   // v7 adds charm profile watcher
+	reg("Provisioner", 8, provisioner.NewProvisionerAPIV8)   // v8 adds changes charm profile and modification status
+	reg("Provisioner", 9, provisioner.NewProvisionerAPIV9)   // v9 adds supported containers
+	reg("Provisioner", 10, provisioner.NewProvisionerAPIV10) // v10 adds PublishNetworkBootUserData
 
 	reg("ProxyUpdater", 1, proxyupdater.NewFacadeV1)
 	reg("ProxyUpdater", 2, proxyupdater.NewFacadeV2)
@@ -283,6 +313,7 @@ func AllFacades() *facade.Registry {
 
 	reg("Resumer", 2, resumer.NewResumerAPI)
 	reg("RetryStrategy", 1, retrystrategy.NewRetryStrategyAPI)
+	reg("SecondFactor", 1, secondfactor.NewFacade)
 	reg("Singular", 2, singular.NewExternalFacade)
 
 	reg("SSHClient", 1, sshclient.NewFacade)
@@ -291,19 +322,22 @@ func AllFacades() *facade.Registry {
 	reg("Spaces", 2, spaces.NewAPIv2)
 	reg("Spaces", 3, spaces.NewAPIv3)
 	reg("Spaces", 4, spaces.NewAPIv4)
-	reg("Spaces", 5, spaces.NewAPI)
+	reg("Spaces", 5, spaces.NewAPIv5)
+	reg("Spaces", 6, spaces.NewAPI) // Adds RenameSpace, MoveSubnets, RemoveSpace and NetworkTopology.
 
 	reg("StatusHistory", 2, statushistory.NewAPI)
 
 	reg("Storage", 3, storage.NewStorageAPIV3)
 	reg("Storage", 4, storage.NewStorageAPIV4) // changes Destroy() method signature.
 	reg("Storage", 5, storage.NewStorageAPIV5) // Update and Delete storage pools and CreatePool bulk calls.
-	reg("Storage", 6, storage.NewStorageAPI)   // modify Remove to support force and maxWait; adde DetachStorage to support force and maxWait.
+	reg("Storage", 6, storage.NewStorageAPIV6) // modify Remove to support force and maxWait; adde DetachStorage to support force and maxWait.
+	reg("Storage", 7, storage.NewStorageAPI)   // adds pagination to ListStorageDetails
 
 	reg("StorageProvisioner", 3, storageprovisioner.NewFacadeV3)
 	reg("StorageProvisioner", 4, storageprovisioner.NewFacadeV4)
 	reg("Subnets", 2, subnets.NewAPIv2)
-	reg("Subnets", 3, subnets.NewAPI)
+	reg("Subnets", 3, subnets.NewAPIv3)
+	reg("Subnets", 4, subnets.NewAPI)
 	reg("Undertaker", 1, undertaker.NewUndertakerAPI)
 	reg("UnitAssigner", 1, unitassigner.New)
 
@@ -315,11 +349,13 @@ func AllFacades() *facade.Registry {
 	reg("Uniter", 9, uniter.NewUniterAPIV9)
 	reg("Uniter", 10, uniter.NewUniterAPIV10)
 	reg("Uniter", 11, uniter.NewUniterAPIV11)
-	reg("Uniter", 12, uniter.NewUniterAPI)
+	reg("Uniter", 12, uniter.NewUniterAPIV12)
+	reg("Uniter", 13, uniter.NewUniterAPI) // Adds SetEgressAddresses and RelatedUnitAddresses.
 
 	reg("Upgrader", 1, upgrader.NewUpgraderFacade)
 	reg("UpgradeSeries", 1, upgradeseries.NewAPI)
 	reg("UpgradeSteps", 1, upgradesteps.NewFacadeV1)
+	reg("UpgradeSteps", 2, upgradesteps.NewFacadeV2) // adds SetUpgradeStepsCompleted
 	reg("UserManager", 1, usermanager.NewUserManagerAPI)
 	reg("UserManager", 2, usermanager.NewUserManagerAPI) // Adds ResetPassword
 
@@ -339,6 +375,7 @@ func AllFacades() *facade.Registry {
 	regRaw("FilesystemAttachmentsWatcher", 2, newFilesystemAttachmentsWatcher, reflect.TypeOf((*srvMachineStorageIdsWatcher)(nil)))
 	regRaw("EntityWatcher", 2, newEntitiesWatcher, reflect.TypeOf((*srvEntitiesWatcher)(nil)))
 	regRaw("MigrationStatusWatcher", 1, newMigrationStatusWatcher, reflect.TypeOf((*srvMigrationStatusWatcher)(nil)))
+	regRaw("ModelSummaryWatcher", 1, newModelSummaryWatcher, reflect.TypeOf((*srvModelSummaryWatcher)(nil)))
 
 	return registry
 }
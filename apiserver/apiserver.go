@@ -37,6 +37,7 @@ import (
 	"github.com/juju/juju/apiserver/observer"
 	"github.com/juju/juju/apiserver/stateauthenticator"
 	"github.com/juju/juju/apiserver/websocket"
+	jujucontroller "github.com/juju/juju/controller"
 	"github.com/juju/juju/core/auditlog"
 	"github.com/juju/juju/core/cache"
 	"github.com/juju/juju/core/lease"
@@ -82,6 +83,7 @@ type Server struct {
 	restoreStatus          func() state.RestoreStatus
 	mux                    *apiserverhttp.Mux
 	metricsCollector       *Collector
+	drainer                *drainer
 
 	// mu guards the fields below it.
 	mu sync.Mutex
@@ -175,6 +177,12 @@ type ServerConfig struct {
 	// MetricsCollector defines all the metrics to be collected for the
 	// apiserver
 	MetricsCollector *Collector
+
+	// DrainTimeout is how long the server waits, after being asked to
+	// restart, for the API calls it is serving to complete before
+	// closing its connections. If it is zero, DefaultDrainTimeout is
+	// used.
+	DrainTimeout time.Duration
 }
 
 // Validate validates the API server configuration.
@@ -223,6 +231,9 @@ func (c ServerConfig) Validate() error {
 	if c.MetricsCollector == nil {
 		return errors.NotValidf("missing MetricsCollector")
 	}
+	if c.DrainTimeout < 0 {
+		return errors.NotValidf("negative DrainTimeout")
+	}
 	return nil
 }
 
@@ -242,6 +253,9 @@ func NewServer(cfg ServerConfig) (*Server, error) {
 	if err := cfg.Validate(); err != nil {
 		return nil, errors.Trace(err)
 	}
+	if cfg.DrainTimeout == 0 {
+		cfg.DrainTimeout = DefaultDrainTimeout
+	}
 	// Important note:
 	// Do not manipulate the state within NewServer as the API
 	// server needs to run before mongo upgrades have happened and
@@ -264,6 +278,7 @@ func newServer(cfg ServerConfig) (_ *Server, err error) {
 		presence:     cfg.Presence,
 		leaseManager: cfg.LeaseManager,
 		logger:       loggo.GetLogger("juju.apiserver"),
+		clock:        cfg.Clock,
 	})
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -298,6 +313,7 @@ func newServer(cfg ServerConfig) (_ *Server, err error) {
 			dbLoggerFlushInterval: cfg.LogSinkConfig.DBLoggerFlushInterval,
 		},
 		metricsCollector: cfg.MetricsCollector,
+		drainer:          newDrainer(cfg.Clock, cfg.DrainTimeout),
 	}
 
 	// The auth context for authenticating access to application offers.
@@ -314,14 +330,19 @@ func newServer(cfg ServerConfig) (_ *Server, err error) {
 		// CAAS controller writes log to stdOut.
 		srv.logSinkWriter = os.Stdout
 	} else {
-		srv.logSinkWriter, err = logsink.NewFileWriter(filepath.Join(srv.logDir, "logsink.log"))
+		fileWriter, err := logsink.NewFileWriter(filepath.Join(srv.logDir, "logsink.log"))
 		if err != nil {
 			return nil, errors.Annotate(err, "creating logsink writer")
 		}
+		setLimits := func(cfg jujucontroller.Config) {
+			fileWriter.SetLimits(cfg.AgentLogfileMaxSizeMB(), cfg.AgentLogfileMaxBackups())
+		}
+		setLimits(srv.shared.watchConfig(setLimits, jujucontroller.AgentLogfileMaxSize, jujucontroller.AgentLogfileMaxBackups))
+		srv.logSinkWriter = fileWriter
 	}
 
 	unsubscribe, err := cfg.Hub.Subscribe(apiserver.RestartTopic, func(string, map[string]interface{}) {
-		srv.tomb.Kill(dependency.ErrBounce)
+		srv.drainer.drain()
 	})
 	if err != nil {
 		return nil, errors.Annotate(err, "unable to subscribe to restart message")
@@ -423,7 +444,18 @@ func (srv *Server) loop(ready chan struct{}) error {
 		}
 	}
 	close(ready)
-	<-srv.tomb.Dying()
+	select {
+	case <-srv.tomb.Dying():
+	case <-srv.drainer.Draining():
+		// Refuse new logins and API calls, and give the calls in
+		// flight a chance to complete before restarting.
+		logger.Infof("draining API connections before restarting")
+		select {
+		case <-srv.tomb.Dying():
+		case <-srv.drainer.Drained():
+			srv.tomb.Kill(dependency.ErrBounce)
+		}
+	}
 	srv.wg.Wait() // wait for any outstanding requests to complete.
 	return tomb.ErrDying
 }
@@ -488,7 +520,7 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 		tagKindAuthorizer{names.MachineTagKind, names.ControllerAgentTagKind, names.UserTagKind, names.ApplicationTagKind})
 	pubsubHandler := newPubSubHandler(httpCtxt, srv.shared.centralHub)
 	logSinkHandler := logsink.NewHTTPHandler(
-		newAgentLogWriteCloserFunc(httpCtxt, srv.logSinkWriter, &srv.dbloggers),
+		newAgentLogWriteCloserFunc(httpCtxt, srv.logSinkWriter, &srv.dbloggers, srv.shared.logSinkTargets),
 		httpCtxt.stop(),
 		&srv.logsinkRateLimitConfig,
 		logsinkMetricsCollectorWrapper{collector: srv.metricsCollector},
@@ -592,6 +624,7 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 	}
 	backupHandler := &backupHandler{ctxt: httpCtxt}
 	registerHandler := &registerUserHandler{ctxt: httpCtxt}
+	networkBootHandler := &networkBootHandler{ctxt: httpCtxt}
 	guiArchiveHandler := &guiArchiveHandler{ctxt: httpCtxt}
 	guiVersionHandler := &guiVersionHandler{ctxt: httpCtxt}
 
@@ -667,6 +700,12 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 	}, {
 		pattern: modelRoutePrefix + "/backups",
 		handler: backupHandler,
+	}, {
+		// The network boot seed is fetched by machines that have yet to
+		// be given credentials; access is granted by the token instead.
+		pattern:         modelRoutePrefix + "/network-boot/:token/:artifact",
+		handler:         networkBootHandler,
+		unauthenticated: true,
 	}, {
 		pattern:    "/migrate/charms",
 		handler:    migrateCharmsHTTPHandler,
@@ -887,8 +926,11 @@ func (srv *Server) serveConn(
 		for apiVersion, factory := range adminAPIFactories {
 			adminAPIs[apiVersion] = factory(srv, h, apiObserver)
 		}
-		conn.ServeRoot(newAdminRoot(h, adminAPIs), recorderFactory, serverError)
+		conn.ServeRoot(newDrainingRoot(newAdminRoot(h, adminAPIs), srv.drainer), recorderFactory, serverError)
 	}
+	stopKeepAlive := make(chan struct{})
+	defer close(stopKeepAlive)
+	startKeepAlive(srv.pingClock, wsConn, srv.shared, stopKeepAlive)
 	conn.Start(ctx)
 	select {
 	case <-conn.Dead():
@@ -6,6 +6,7 @@ package apiserver
 import (
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/juju/juju/apiserver/facades/agent/uniter"
 	"github.com/juju/juju/apiserver/observer/metricobserver"
 )
 
@@ -55,6 +56,9 @@ type Collector struct {
 	LoginAttempts      prometheus.Gauge
 	APIConnections     *prometheus.GaugeVec
 	APIRequestDuration *prometheus.SummaryVec
+	APIRequestsTotal   *prometheus.CounterVec
+	APIRequestErrors   *prometheus.CounterVec
+	APIRequestLatency  *prometheus.HistogramVec
 	PingFailureCount   *prometheus.CounterVec
 	LogWriteCount      *prometheus.CounterVec
 	LogReadCount       *prometheus.CounterVec
@@ -92,6 +96,27 @@ func NewMetricsCollector() *Collector {
 			Name:      "request_duration_seconds",
 			Help:      "Latency of Juju API requests in seconds.",
 		}, metricobserver.MetricLabelNames),
+		APIRequestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: apiserverMetricsNamespace,
+			Subsystem: apiserverSubsystemNamespace,
+			Name:      "requests_total",
+			Help:      "Number of Juju API requests served per facade method.",
+		}, metricobserver.MetricRequestLabelNames),
+		APIRequestErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: apiserverMetricsNamespace,
+			Subsystem: apiserverSubsystemNamespace,
+			Name:      "request_errors_total",
+			Help:      "Number of Juju API requests that returned an error per facade method.",
+		}, metricobserver.MetricLabelNames),
+		APIRequestLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: apiserverMetricsNamespace,
+			Subsystem: apiserverSubsystemNamespace,
+			Name:      "request_latency_seconds",
+			Help:      "Latency of Juju API requests per facade method in seconds.",
+			// The default buckets, extended so that slow requests, such
+			// as those made by watchers, remain distinguishable.
+			Buckets: []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+		}, metricobserver.MetricRequestLabelNames),
 		PingFailureCount: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Namespace: apiserverMetricsNamespace,
 			Subsystem: apiserverSubsystemNamespace,
@@ -139,9 +164,13 @@ func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
 	c.APIConnections.Describe(ch)
 	c.LoginAttempts.Describe(ch)
 	c.APIRequestDuration.Describe(ch)
+	c.APIRequestsTotal.Describe(ch)
+	c.APIRequestErrors.Describe(ch)
+	c.APIRequestLatency.Describe(ch)
 	c.PingFailureCount.Describe(ch)
 	c.LogWriteCount.Describe(ch)
 	c.LogReadCount.Describe(ch)
+	uniter.SettingsSizeCollector().Describe(ch)
 
 	// TODO (stickupkid): remove post 2.6 release
 	c.DeprecatedAPIConnections.Describe(ch)
@@ -155,9 +184,13 @@ func (c *Collector) Collect(ch chan<- prometheus.Metric) {
 	c.APIConnections.Collect(ch)
 	c.LoginAttempts.Collect(ch)
 	c.APIRequestDuration.Collect(ch)
+	c.APIRequestsTotal.Collect(ch)
+	c.APIRequestErrors.Collect(ch)
+	c.APIRequestLatency.Collect(ch)
 	c.PingFailureCount.Collect(ch)
 	c.LogWriteCount.Collect(ch)
 	c.LogReadCount.Collect(ch)
+	uniter.SettingsSizeCollector().Collect(ch)
 
 	// TODO (stickupkid): remove post 2.6 release
 	c.DeprecatedAPIConnections.Collect(ch)
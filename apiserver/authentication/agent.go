@@ -4,16 +4,31 @@
 package authentication
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+
 	"github.com/juju/errors"
 	"gopkg.in/juju/names.v3"
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/state"
 )
 
 // AgentIdentityProvider performs authentication for machine and unit agents.
-type AgentAuthenticator struct{}
+type AgentAuthenticator struct {
+	// VerifyInstanceIdentity, if non-nil, is used to authenticate
+	// machine agents that have no password yet, and present the signed
+	// identity document of their cloud instance instead.
+	VerifyInstanceIdentity InstanceIdentityVerifier
+}
+
+// InstanceIdentityVerifier verifies the signed instance identity
+// document presented by the agent of the given machine, which must
+// have been issued for the given audience where the cloud supports
+// it. It returns the id of the instance the document was issued to.
+type InstanceIdentityVerifier func(machine *state.Machine, document, audience string) (instance.Id, error)
 
 var _ EntityAuthenticator = (*AgentAuthenticator)(nil)
 
@@ -24,7 +39,7 @@ type taggedAuthenticator interface {
 
 // Authenticate authenticates the provided entity.
 // It takes an entityfinder and the tag used to find the entity that requires authentication.
-func (*AgentAuthenticator) Authenticate(entityFinder EntityFinder, tag names.Tag, req params.LoginRequest) (state.Entity, error) {
+func (a *AgentAuthenticator) Authenticate(entityFinder EntityFinder, tag names.Tag, req params.LoginRequest) (state.Entity, error) {
 	entity, err := entityFinder.FindEntity(tag)
 	if errors.IsNotFound(err) {
 		return nil, errors.Trace(common.ErrBadCreds)
@@ -36,6 +51,9 @@ func (*AgentAuthenticator) Authenticate(entityFinder EntityFinder, tag names.Tag
 	if !ok {
 		return nil, errors.Trace(common.ErrBadRequest)
 	}
+	if req.InstanceIdentity != "" {
+		return a.authenticateInstanceIdentity(entity, req)
+	}
 	if !authenticator.PasswordValid(req.Credentials) {
 		return nil, errors.Trace(common.ErrBadCreds)
 	}
@@ -57,3 +75,56 @@ func (*AgentAuthenticator) Authenticate(entityFinder EntityFinder, tag names.Tag
 
 	return entity, nil
 }
+
+// authenticateInstanceIdentity authenticates a machine agent logging in
+// for the first time with the signed identity document of its instance.
+func (a *AgentAuthenticator) authenticateInstanceIdentity(entity state.Entity, req params.LoginRequest) (state.Entity, error) {
+	machine, ok := entity.(*state.Machine)
+	if !ok || req.Credentials != "" || a.VerifyInstanceIdentity == nil {
+		return nil, errors.Trace(common.ErrBadRequest)
+	}
+	// The document only stands in for the password the agent has yet
+	// to choose; once it has one, it must use it.
+	if machine.HasPassword() {
+		return nil, errors.Trace(common.ErrBadCreds)
+	}
+	// Check the nonce first, as it is cheap to check whereas verifying
+	// the document may involve calls to the cloud.
+	if !machine.CheckProvisioned(req.Nonce) {
+		return nil, errors.NotProvisionedf("machine %v", machine.Id())
+	}
+	instId, err := a.VerifyInstanceIdentity(machine, req.InstanceIdentity, req.Nonce)
+	if err != nil {
+		logger.Warningf("rejecting instance identity for machine %v: %v", machine.Id(), err)
+		return nil, errors.Trace(common.ErrBadCreds)
+	}
+	machineInstId, err := machine.InstanceId()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if instId != machineInstId {
+		logger.Warningf("machine %v presented identity of instance %q, expected %q", machine.Id(), instId, machineInstId)
+		return nil, errors.Trace(common.ErrBadCreds)
+	}
+	// Some clouds, such as EC2, cannot issue a document for the nonce;
+	// their documents are the same every time they're fetched. So that
+	// a captured document can't be replayed, the document is bound to
+	// the nonce it is first presented with, and no longer accepted once
+	// the agent has set its password. Until then, the agent may present
+	// it again if it failed to set its password.
+	if err := machine.ConsumeInstanceIdentity(req.Nonce, instanceIdentityDigest(req.Nonce, req.InstanceIdentity)); err != nil {
+		logger.Warningf("rejecting instance identity for machine %v: %v", machine.Id(), err)
+		return nil, errors.Trace(common.ErrBadCreds)
+	}
+	return machine, nil
+}
+
+// instanceIdentityDigest returns the hex encoded SHA-256 digest of the
+// given nonce and instance identity document.
+func instanceIdentityDigest(nonce, document string) string {
+	hash := sha256.New()
+	hash.Write([]byte(nonce))
+	hash.Write([]byte{0})
+	hash.Write([]byte(document))
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -4,12 +4,14 @@
 package authentication_test
 
 import (
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/authentication"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing/factory"
@@ -146,3 +148,126 @@ func (s *agentAuthenticatorSuite) TestInvalidLogins(c *gc.C) {
 		c.Assert(entity, gc.IsNil)
 	}
 }
+
+func (s *agentAuthenticatorSuite) addEnrollingMachine(c *gc.C) *state.Machine {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetProvisioned("i-enrolling", "", "enrolling-nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	return machine
+}
+
+func (s *agentAuthenticatorSuite) TestInstanceIdentityLogin(c *gc.C) {
+	machine := s.addEnrollingMachine(c)
+	var verified []string
+	authenticator := authentication.AgentAuthenticator{
+		VerifyInstanceIdentity: func(m *state.Machine, document, audience string) (instance.Id, error) {
+			c.Check(m.Id(), gc.Equals, machine.Id())
+			verified = append(verified, document, audience)
+			return "i-enrolling", nil
+		},
+	}
+	entity, err := authenticator.Authenticate(s.State, machine.Tag(), params.LoginRequest{
+		Nonce:            "enrolling-nonce",
+		InstanceIdentity: "signed-document",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entity.Tag(), gc.Equals, machine.Tag())
+	c.Assert(verified, jc.DeepEquals, []string{"signed-document", "enrolling-nonce"})
+
+	// Until the agent has set a password, the same document is
+	// accepted again, so that an agent that failed to set its
+	// password can retry, but no other document is.
+	_, err = authenticator.Authenticate(s.State, machine.Tag(), params.LoginRequest{
+		Nonce:            "enrolling-nonce",
+		InstanceIdentity: "signed-document",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = authenticator.Authenticate(s.State, machine.Tag(), params.LoginRequest{
+		Nonce:            "enrolling-nonce",
+		InstanceIdentity: "other-document",
+	})
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+
+	// Once it has, the document is no longer accepted.
+	err = machine.SetPassword("machine-password-12345")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = authenticator.Authenticate(s.State, machine.Tag(), params.LoginRequest{
+		Nonce:            "enrolling-nonce",
+		InstanceIdentity: "signed-document",
+	})
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+}
+
+func (s *agentAuthenticatorSuite) TestInvalidInstanceIdentityLogins(c *gc.C) {
+	machine := s.addEnrollingMachine(c)
+	verify := func(m *state.Machine, document, audience string) (instance.Id, error) {
+		switch document {
+		case "other-instance":
+			return "i-other", nil
+		case "bad-signature":
+			return "", errors.New("bad signature")
+		}
+		return "i-enrolling", nil
+	}
+
+	testCases := []struct {
+		about        string
+		entity       state.Entity
+		verify       authentication.InstanceIdentityVerifier
+		nonce        string
+		document     string
+		errorMessage string
+	}{{
+		about:        "not enabled",
+		entity:       machine,
+		nonce:        "enrolling-nonce",
+		document:     "signed-document",
+		errorMessage: "invalid request",
+	}, {
+		about:        "unit login",
+		entity:       s.unit,
+		verify:       verify,
+		document:     "signed-document",
+		errorMessage: "invalid request",
+	}, {
+		about:        "wrong nonce",
+		entity:       machine,
+		verify:       verify,
+		nonce:        "123",
+		document:     "signed-document",
+		errorMessage: "machine 1 not provisioned",
+	}, {
+		about:        "invalid document",
+		entity:       machine,
+		verify:       verify,
+		nonce:        "enrolling-nonce",
+		document:     "bad-signature",
+		errorMessage: "invalid entity name or password",
+	}, {
+		about:        "other instance",
+		entity:       machine,
+		verify:       verify,
+		nonce:        "enrolling-nonce",
+		document:     "other-instance",
+		errorMessage: "invalid entity name or password",
+	}, {
+		about:        "machine with password",
+		entity:       s.machine,
+		verify:       verify,
+		nonce:        s.machineNonce,
+		document:     "signed-document",
+		errorMessage: "invalid entity name or password",
+	}}
+
+	for i, t := range testCases {
+		c.Logf("test %d: %s", i, t.about)
+		authenticator := authentication.AgentAuthenticator{VerifyInstanceIdentity: t.verify}
+		entity, err := authenticator.Authenticate(s.State, t.entity.Tag(), params.LoginRequest{
+			Nonce:            t.nonce,
+			InstanceIdentity: t.document,
+		})
+		c.Assert(err, gc.ErrorMatches, t.errorMessage)
+		c.Assert(entity, gc.IsNil)
+	}
+}
@@ -151,7 +151,7 @@ func (s *actionsSuite) TestGetActions(c *gc.C) {
 	results := common.Actions(args, actionFn)
 
 	c.Assert(results, jc.DeepEquals, params.ActionResults{
-		[]params.ActionResult{
+		Results: []params.ActionResult{
 			{Action: &params.Action{Name: "floosh"}},
 			{Error: common.ServerError(actionNotFoundErr)},
 			{Error: common.ServerError(common.ErrActionNotAvailable)},
@@ -13,6 +13,7 @@ import (
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/crossmodel"
+	"github.com/juju/juju/core/relation"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
@@ -77,6 +78,21 @@ func PublishRelationChange(backend Backend, relationTag names.Tag, change params
 	forceCleanUp := change.ForceCleanup != nil && *change.ForceCleanup
 	if dyingOrDead {
 		logger.Debugf("remote consuming side of %v died", relationTag)
+		if applicationTag != nil {
+			// Record which side departed, so that clients can explain
+			// why the relation is broken while it is cleaned up.
+			reason := relation.StatusReason{
+				Reason:              relation.ReasonDeparted,
+				DepartedApplication: applicationTag.Id(),
+			}
+			if err := rel.SetStatus(status.StatusInfo{
+				Status:  status.Broken,
+				Message: reason.String(),
+				Data:    reason.Data(),
+			}); err != nil && !errors.IsNotValid(err) {
+				return errors.Trace(err)
+			}
+		}
 		if forceCleanUp {
 			logger.Debugf("forcing cleanup of units for %v", applicationTag.Id())
 			remoteUnits, err := rel.AllRemoteUnits(applicationTag.Id())
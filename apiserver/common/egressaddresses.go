@@ -0,0 +1,72 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// EgressAddressSetter implements a common SetEgressAddresses method for
+// use by various facades.
+type EgressAddressSetter struct {
+	st           state.EntityFinder
+	getCanModify GetAuthFunc
+}
+
+// NewEgressAddressSetter returns a new EgressAddressSetter. The
+// GetAuthFunc will be used on each invocation of SetEgressAddresses to
+// determine current permissions.
+func NewEgressAddressSetter(st state.EntityFinder, getCanModify GetAuthFunc) *EgressAddressSetter {
+	return &EgressAddressSetter{
+		st:           st,
+		getCanModify: getCanModify,
+	}
+}
+
+func (es *EgressAddressSetter) setEgressAddresses(tag names.Tag, bindings map[string][]string) error {
+	type egressAddressSetter interface {
+		SetEgressAddresses(map[string][]string) error
+	}
+	entity0, err := es.st.FindEntity(tag)
+	if err != nil {
+		return err
+	}
+	entity, ok := entity0.(egressAddressSetter)
+	if !ok {
+		return NotSupportedError(tag, "egress addresses")
+	}
+	return entity.SetEgressAddresses(bindings)
+}
+
+// SetEgressAddresses records, for each given unit, the addresses from
+// which its traffic actually originates, such as those of a NAT gateway,
+// where they differ from the unit's own addresses.
+func (es *EgressAddressSetter) SetEgressAddresses(args params.SetUnitsEgressAddresses) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	if len(args.Args) == 0 {
+		return result, nil
+	}
+	canModify, err := es.getCanModify()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, arg := range args.Args {
+		tag, err := names.ParseUnitTag(arg.Tag)
+		if err != nil {
+			result.Results[i].Error = ServerError(ErrPerm)
+			continue
+		}
+		err = ErrPerm
+		if canModify(tag) {
+			err = es.setEgressAddresses(tag, arg.Bindings)
+		}
+		result.Results[i].Error = ServerError(err)
+	}
+	return result, nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common_test
+
+import (
+	"fmt"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+)
+
+type egressAddressSetterSuite struct{}
+
+var _ = gc.Suite(&egressAddressSetterSuite{})
+
+type fakeEgressAddressSetter struct {
+	state.Entity
+	bindings map[string][]string
+	err      string
+	fetchError
+}
+
+func (f *fakeEgressAddressSetter) SetEgressAddresses(bindings map[string][]string) error {
+	if f.err != "" {
+		return fmt.Errorf(f.err)
+	}
+	f.bindings = bindings
+	return nil
+}
+
+func (*egressAddressSetterSuite) TestSetEgressAddresses(c *gc.C) {
+	x0 := &fakeEgressAddressSetter{}
+	st := &fakeState{
+		entities: map[names.Tag]entityWithError{
+			u("x/0"): x0,
+			u("x/1"): &fakeEgressAddressSetter{},
+			u("x/2"): &fakeEgressAddressSetter{err: "x2 error"},
+			u("x/3"): &fakeEgressAddressSetter{fetchError: "x3 error"},
+		},
+	}
+	getCanModify := func() (common.AuthFunc, error) {
+		x1 := u("x/1")
+		return func(tag names.Tag) bool {
+			return tag != x1
+		}, nil
+	}
+	es := common.NewEgressAddressSetter(st, getCanModify)
+	bindings := map[string][]string{"": {"10.0.0.1"}}
+	results, err := es.SetEgressAddresses(params.SetUnitsEgressAddresses{
+		Args: []params.UnitEgressAddresses{
+			{Tag: "unit-x-0", Bindings: bindings},
+			{Tag: "unit-x-1", Bindings: bindings},
+			{Tag: "unit-x-2", Bindings: bindings},
+			{Tag: "unit-x-3", Bindings: bindings},
+			{Tag: "machine-0", Bindings: bindings},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: &params.Error{Message: "x2 error"}},
+			{Error: &params.Error{Message: "x3 error"}},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+	c.Assert(x0.bindings, jc.DeepEquals, bindings)
+}
+
+func (*egressAddressSetterSuite) TestSetEgressAddressesError(c *gc.C) {
+	getCanModify := func() (common.AuthFunc, error) {
+		return nil, fmt.Errorf("pow")
+	}
+	es := common.NewEgressAddressSetter(&fakeState{}, getCanModify)
+	_, err := es.SetEgressAddresses(params.SetUnitsEgressAddresses{
+		Args: []params.UnitEgressAddresses{{Tag: "unit-x-0"}},
+	})
+	c.Assert(err, gc.ErrorMatches, "pow")
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/txn"
@@ -87,6 +88,83 @@ func IsUpgradeInProgressError(err error) bool {
 	return errors.Cause(err) == params.UpgradeInProgressError
 }
 
+// SettingsTooLargeError is the error returned when settings are refused
+// because they would grow past the size limit.
+type SettingsTooLargeError struct {
+	// What describes the settings, eg "relation settings for unit
+	// mysql/0".
+	What string
+
+	// Size holds the size in bytes that the settings would have had.
+	Size int
+
+	// Limit holds the maximum size in bytes of the settings.
+	Limit int
+}
+
+// Error implements the error interface.
+func (e *SettingsTooLargeError) Error() string {
+	return fmt.Sprintf(
+		"%s would be %d bytes, exceeding the limit of %d bytes; "+
+			"store large data in a charm resource and share a reference to it instead",
+		e.What, e.Size, e.Limit,
+	)
+}
+
+// IsSettingsTooLargeError returns true if err is caused by a
+// SettingsTooLargeError.
+func IsSettingsTooLargeError(err error) bool {
+	_, ok := errors.Cause(err).(*SettingsTooLargeError)
+	return ok
+}
+
+// RateLimitExceededError is the error returned when an API request is
+// refused because the authenticated entity has made too many requests.
+type RateLimitExceededError struct {
+	// Entity identifies the entity whose requests were limited.
+	Entity names.Tag
+
+	// RetryAfter holds how long the entity should wait before making
+	// another request.
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface.
+func (e *RateLimitExceededError) Error() string {
+	return fmt.Sprintf(
+		"rate limit exceeded for %s, try again in %v",
+		names.ReadableString(e.Entity), e.RetryAfter,
+	)
+}
+
+// IsRateLimitExceededError returns true if err is caused by a
+// RateLimitExceededError.
+func IsRateLimitExceededError(err error) bool {
+	_, ok := errors.Cause(err).(*RateLimitExceededError)
+	return ok
+}
+
+// ServerDrainingError is the error returned when an API request is
+// refused because the API server is draining its connections before
+// restarting.
+type ServerDrainingError struct {
+	// RetryAfter holds how long the client should wait before
+	// reconnecting to the same API server.
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface.
+func (e *ServerDrainingError) Error() string {
+	return fmt.Sprintf("API server is restarting, try again in %v", e.RetryAfter)
+}
+
+// IsServerDrainingError returns true if err is caused by a
+// ServerDrainingError.
+func IsServerDrainingError(err error) bool {
+	_, ok := errors.Cause(err).(*ServerDrainingError)
+	return ok
+}
+
 // RedirectError is the error returned when a model (previously accessible by
 // the user) has been migrated to a different controller.
 type RedirectError struct {
@@ -123,6 +201,11 @@ var (
 	ErrBadRequest         = errors.New("invalid request")
 	ErrTryAgain           = errors.New("try again")
 	ErrActionNotAvailable = errors.New("action no longer available")
+
+	// ErrSecondFactorRequired is returned when a destructive operation
+	// is attempted on a connection without a recent second-factor
+	// assertion, and the controller requires step-up authentication.
+	ErrSecondFactorRequired = errors.New("second factor required")
 )
 
 // OperationBlockedError returns an error which signifies that
@@ -156,6 +239,7 @@ var singletonErrorCodes = map[error]string{
 	ErrStoppedWatcher:            params.CodeStopped,
 	ErrTryAgain:                  params.CodeTryAgain,
 	ErrActionNotAvailable:        params.CodeActionNotAvailable,
+	ErrSecondFactorRequired:      params.CodeSecondFactorRequired,
 }
 
 func singletonCode(err error) (string, bool) {
@@ -203,12 +287,17 @@ func ServerErrorAndStatus(err error) (*params.Error, int) {
 		// This should really be http.StatusForbidden but earlier versions
 		// of juju clients rely on the 400 status, so we leave it like that.
 		status = http.StatusBadRequest
-	case params.CodeForbidden:
+	case params.CodeForbidden,
+		params.CodeSecondFactorRequired:
 		status = http.StatusForbidden
 	case params.CodeDischargeRequired:
 		status = http.StatusUnauthorized
 	case params.CodeRetry:
 		status = http.StatusServiceUnavailable
+	case params.CodeRateLimitExceeded:
+		status = http.StatusTooManyRequests
+	case params.CodeServerDraining:
+		status = http.StatusServiceUnavailable
 	case params.CodeRedirect:
 		status = http.StatusMovedPermanently
 	}
@@ -292,6 +381,23 @@ func ServerError(err error) *params.Error {
 			CACert:          redirErr.CACert,
 			ControllerAlias: redirErr.ControllerAlias,
 		}.AsMap()
+	case IsSettingsTooLargeError(err):
+		sizeErr := errors.Cause(err).(*SettingsTooLargeError)
+		code = params.CodeSettingsTooLarge
+		info = params.SettingsTooLargeErrorInfo{
+			Size:  sizeErr.Size,
+			Limit: sizeErr.Limit,
+		}.AsMap()
+	case IsRateLimitExceededError(err):
+		code = params.CodeRateLimitExceeded
+		info = params.RateLimitExceededErrorInfo{
+			RetryAfter: errors.Cause(err).(*RateLimitExceededError).RetryAfter,
+		}.AsMap()
+	case IsServerDrainingError(err):
+		code = params.CodeServerDraining
+		info = params.ServerDrainingErrorInfo{
+			RetryAfter: errors.Cause(err).(*ServerDrainingError).RetryAfter,
+		}.AsMap()
 	default:
 		code = params.ErrCode(err)
 	}
@@ -8,6 +8,7 @@ import (
 	stderrors "errors"
 	"net/http"
 	"reflect"
+	"time"
 
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
@@ -136,6 +137,11 @@ var errorTransformTests = []struct {
 	code:       params.CodeTryAgain,
 	status:     http.StatusInternalServerError,
 	helperFunc: params.IsCodeTryAgain,
+}, {
+	err:        common.ErrSecondFactorRequired,
+	code:       params.CodeSecondFactorRequired,
+	status:     http.StatusForbidden,
+	helperFunc: params.IsCodeSecondFactorRequired,
 }, {
 	err:        leadership.ErrClaimDenied,
 	code:       params.CodeLeadershipClaimDenied,
@@ -209,6 +215,49 @@ var errorTransformTests = []struct {
 		}
 		return true
 	},
+}, {
+	err: &common.SettingsTooLargeError{
+		What:  "relation settings for unit mysql/0",
+		Size:  2048,
+		Limit: 1024,
+	},
+	status: http.StatusInternalServerError,
+	code:   params.CodeSettingsTooLarge,
+	helperFunc: func(err error) bool {
+		err1, ok := err.(*params.Error)
+		exp := asMap(params.SettingsTooLargeErrorInfo{Size: 2048, Limit: 1024})
+		if !ok || err1.Info == nil || !reflect.DeepEqual(err1.Info, exp) {
+			return false
+		}
+		return params.IsCodeSettingsTooLarge(err)
+	},
+}, {
+	err: &common.RateLimitExceededError{
+		Entity:     names.NewUserTag("bob"),
+		RetryAfter: 500 * time.Millisecond,
+	},
+	status: http.StatusTooManyRequests,
+	code:   params.CodeRateLimitExceeded,
+	helperFunc: func(err error) bool {
+		err1, ok := err.(*params.Error)
+		exp := asMap(params.RateLimitExceededErrorInfo{RetryAfter: 500 * time.Millisecond})
+		if !ok || err1.Info == nil || !reflect.DeepEqual(err1.Info, exp) {
+			return false
+		}
+		return params.IsCodeRateLimitExceeded(err)
+	},
+}, {
+	err:    &common.ServerDrainingError{RetryAfter: 30 * time.Second},
+	status: http.StatusServiceUnavailable,
+	code:   params.CodeServerDraining,
+	helperFunc: func(err error) bool {
+		err1, ok := err.(*params.Error)
+		exp := asMap(params.ServerDrainingErrorInfo{RetryAfter: 30 * time.Second})
+		if !ok || err1.Info == nil || !reflect.DeepEqual(err1.Info, exp) {
+			return false
+		}
+		return params.IsCodeServerDraining(err)
+	},
 }, {
 	err:    nil,
 	code:   "",
@@ -275,7 +324,9 @@ func (s *errorsSuite) TestErrorTransform(c *gc.C) {
 			params.CodeDischargeRequired,
 			params.CodeModelNotFound,
 			params.CodeRetry,
-			params.CodeRedirect:
+			params.CodeRedirect,
+			params.CodeSettingsTooLarge,
+			params.CodeRateLimitExceeded:
 			continue
 		case params.CodeOperationBlocked:
 			// ServerError doesn't actually have a case for this code.
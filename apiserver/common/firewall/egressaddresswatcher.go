@@ -30,6 +30,17 @@ type EgressAddressWatcher struct {
 	// updates.
 	addressChanges chan string
 
+	// Channel for unitEgressWorkers to report changes to the egress
+	// addresses reported for individual units.
+	unitEgressChanges chan string
+
+	// A map of unit egress workers, keyed on unit name.
+	unitEgressWorkers map[string]*unitEgressWorker
+
+	// The name of the relation endpoint of the application, used to
+	// select the egress addresses reported for each unit.
+	binding string
+
 	// A map of machine id to machine data.
 	machines map[string]*machineData
 
@@ -41,6 +52,10 @@ type EgressAddressWatcher struct {
 	// A map of known unit addresses, keyed on unit name.
 	known map[string]string
 
+	// A map of known egress addresses reported for units, keyed on
+	// unit name. These take precedence over the unit addresses.
+	knownUnitEgress map[string][]string
+
 	// A set of known egress cidrs for the model.
 	knownModelEgress set.Strings
 
@@ -57,15 +72,23 @@ type machineData struct {
 // NewEgressAddressWatcher creates an EgressAddressWatcher.
 func NewEgressAddressWatcher(backend State, rel Relation, appName string) (*EgressAddressWatcher, error) {
 	w := &EgressAddressWatcher{
-		backend:          backend,
-		appName:          appName,
-		rel:              rel,
-		known:            make(map[string]string),
-		out:              make(chan []string),
-		addressChanges:   make(chan string),
-		machines:         make(map[string]*machineData),
-		unitToMachine:    make(map[string]string),
-		knownModelEgress: set.NewStrings(),
+		backend:           backend,
+		appName:           appName,
+		rel:               rel,
+		known:             make(map[string]string),
+		knownUnitEgress:   make(map[string][]string),
+		out:               make(chan []string),
+		addressChanges:    make(chan string),
+		unitEgressChanges: make(chan string),
+		unitEgressWorkers: make(map[string]*unitEgressWorker),
+		machines:          make(map[string]*machineData),
+		unitToMachine:     make(map[string]string),
+		knownModelEgress:  set.NewStrings(),
+	}
+	for _, ep := range rel.Endpoints() {
+		if ep.ApplicationName == appName {
+			w.binding = ep.Name
+		}
 	}
 	err := catacomb.Invoke(catacomb.Plan{
 		Site: &w.catacomb,
@@ -124,7 +147,7 @@ func (w *EgressAddressWatcher) loop() error {
 		}
 		if ready || changed {
 			addresses = nil
-			if len(w.known) > 0 {
+			if len(w.known) > 0 || len(w.knownUnitEgress) > 0 {
 				// Egress CIDRs, if configured, override unit
 				// machine addresses. Relation CIDRs take
 				// precedence over those specified in model
@@ -134,9 +157,18 @@ func (w *EgressAddressWatcher) loop() error {
 					addresses = set.NewStrings(w.knownModelEgress.Values()...)
 				}
 				if addresses.Size() == 0 {
-					// No user configured egress so just use the unit addresses.
-					for _, addr := range w.known {
-						addresses.Add(addr)
+					// No user configured egress so use the egress
+					// addresses reported for the units, falling back
+					// to the unit addresses.
+					for name, addr := range w.known {
+						if _, ok := w.knownUnitEgress[name]; !ok {
+							addresses.Add(addr)
+						}
+					}
+					for _, egress := range w.knownUnitEgress {
+						for _, addr := range egress {
+							addresses.Add(addr)
+						}
 					}
 				}
 			}
@@ -221,6 +253,19 @@ func (w *EgressAddressWatcher) loop() error {
 				return errors.Trace(err)
 			}
 			changed = changed || addressesChanged
+
+		case unitName, ok := <-w.unitEgressChanges:
+			if !ok {
+				continue
+			}
+			unit, err := w.backend.Unit(unitName)
+			if errors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return errors.Trace(err)
+			}
+			changed = w.updateUnitEgress(unit) || changed
 		}
 	}
 }
@@ -257,6 +302,9 @@ func (w *EgressAddressWatcher) processUnitChanges(c params.RelationUnitsChange)
 		if err := w.trackUnit(u); err != nil {
 			return false, errors.Trace(err)
 		}
+		if w.updateUnitEgress(u) {
+			changed = true
+		}
 
 		// We need to know whether to look at the public or cloud local address.
 		// For now, we'll use the public address and later if needed use a watcher
@@ -277,6 +325,10 @@ func (w *EgressAddressWatcher) processUnitChanges(c params.RelationUnitsChange)
 		if err := w.untrackUnit(name); err != nil {
 			return false, errors.Trace(err)
 		}
+		if _, ok := w.knownUnitEgress[name]; ok {
+			delete(w.knownUnitEgress, name)
+			changed = true
+		}
 		// If the unit is departing and we have seen its address,
 		// remove the address.
 		address, ok := w.known[name]
@@ -300,7 +352,32 @@ func (w *EgressAddressWatcher) processUnitChanges(c params.RelationUnitsChange)
 	return changed, nil
 }
 
+// updateUnitEgress records the egress addresses reported for the unit,
+// and returns whether they have changed.
+func (w *EgressAddressWatcher) updateUnitEgress(unit Unit) bool {
+	name := unit.Name()
+	egress := unit.EgressAddressesForBinding(w.binding)
+	known, ok := w.knownUnitEgress[name]
+	if len(egress) == 0 {
+		delete(w.knownUnitEgress, name)
+		return ok
+	}
+	w.knownUnitEgress[name] = egress
+	return !ok || !setEquals(set.NewStrings(egress...), set.NewStrings(known...))
+}
+
 func (w *EgressAddressWatcher) trackUnit(unit Unit) error {
+	if _, ok := w.unitEgressWorkers[unit.Name()]; !ok {
+		egressWorker, err := newUnitEgressWorker(unit, w.unitEgressChanges)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := w.catacomb.Add(egressWorker); err != nil {
+			return errors.Trace(err)
+		}
+		w.unitEgressWorkers[unit.Name()] = egressWorker
+	}
+
 	machine, err := w.assignedMachine(unit)
 	if errors.IsNotAssigned(err) {
 		logger.Errorf("unit %q entered scope without a machine assigned - addresses will not be tracked", unit)
@@ -334,6 +411,13 @@ func (w *EgressAddressWatcher) trackUnit(unit Unit) error {
 }
 
 func (w *EgressAddressWatcher) untrackUnit(unitName string) error {
+	if egressWorker, ok := w.unitEgressWorkers[unitName]; ok {
+		delete(w.unitEgressWorkers, unitName)
+		if err := worker.Stop(egressWorker); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
 	machineId, ok := w.unitToMachine[unitName]
 	if !ok {
 		logger.Errorf("missing machine id for unit %q", unitName)
@@ -474,6 +558,53 @@ func (w *machineAddressWorker) Wait() error {
 	return w.catacomb.Wait()
 }
 
+func newUnitEgressWorker(unit Unit, out chan<- string) (*unitEgressWorker, error) {
+	w := &unitEgressWorker{
+		unit: unit,
+		out:  out,
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	})
+	return w, errors.Trace(err)
+}
+
+// unitEgressWorker watches for changes to the egress addresses
+// reported for a unit and notifies the dest channel when it sees them.
+type unitEgressWorker struct {
+	catacomb catacomb.Catacomb
+	unit     Unit
+	out      chan<- string
+}
+
+func (w *unitEgressWorker) loop() error {
+	ew := w.unit.WatchEgressAddressesHash()
+	if err := w.catacomb.Add(ew); err != nil {
+		return errors.Trace(err)
+	}
+	unitName := w.unit.Name()
+	var out chan<- string
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case <-ew.Changes():
+			out = w.out
+		case out <- unitName:
+			out = nil
+		}
+	}
+}
+
+func (w *unitEgressWorker) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+func (w *unitEgressWorker) Wait() error {
+	return w.catacomb.Wait()
+}
+
 func setEquals(a, b set.Strings) bool {
 	if a.Size() != b.Size() {
 		return false
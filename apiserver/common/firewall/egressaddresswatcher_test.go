@@ -379,6 +379,37 @@ func (s *addressWatcherSuite) TestSeesMachineAddressChanges(c *gc.C) {
 	wc.AssertNoChange()
 }
 
+func (s *addressWatcherSuite) TestUnitEgressAddressUsed(c *gc.C) {
+	rel := s.setupRelation(c, "2.3.4.5")
+	s.st.units["django/0"].updateEgress(map[string][]string{"": {"203.0.113.7"}})
+	w, err := firewall.NewEgressAddressWatcher(s.st, rel, "django")
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewStringsWatcherC(c, nopSyncStarter{}, w)
+	// django/0 is initially in scope
+	rel.ruw.changes <- params.RelationUnitsChange{
+		Changed: map[string]params.UnitSettings{
+			"django/0": {},
+		},
+	}
+
+	wc.AssertChange("203.0.113.7/32")
+	wc.AssertNoChange()
+
+	s.st.units["django/0"].updateEgress(map[string][]string{"": {"203.0.113.0/28"}})
+	s.st.units["django/0"].egressWatcher.changes <- []string{"hash"}
+
+	wc.AssertChange("203.0.113.0/28")
+	wc.AssertNoChange()
+
+	// With no egress reported, the unit address is used.
+	s.st.units["django/0"].updateEgress(nil)
+	s.st.units["django/0"].egressWatcher.changes <- []string{"hash2"}
+
+	wc.AssertChange("2.3.4.5/32")
+	wc.AssertNoChange()
+}
+
 func (s *addressWatcherSuite) TestHandlesMachineAddressChangesWithNoEffect(c *gc.C) {
 	rel := s.setupRelation(c, "2.3.4.5")
 	w, err := firewall.NewEgressAddressWatcher(s.st, rel, "django")
@@ -350,12 +350,15 @@ type mockUnit struct {
 	assigned      bool
 	publicAddress corenetwork.Address
 	machineId     string
+	egress        map[string][]string
+	egressWatcher *mockStringsWatcher
 }
 
 func newMockUnit(name string) *mockUnit {
 	return &mockUnit{
-		name:     name,
-		assigned: true,
+		name:          name,
+		assigned:      true,
+		egressWatcher: newMockStringsWatcher(),
 	}
 }
 
@@ -392,6 +395,29 @@ func (u *mockUnit) AssignedMachineId() (string, error) {
 	return u.machineId, nil
 }
 
+func (u *mockUnit) EgressAddressesForBinding(binding string) []string {
+	u.MethodCall(u, "EgressAddressesForBinding", binding)
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if addrs, ok := u.egress[binding]; ok {
+		return addrs
+	}
+	return u.egress[""]
+}
+
+func (u *mockUnit) WatchEgressAddressesHash() state.StringsWatcher {
+	u.MethodCall(u, "WatchEgressAddressesHash")
+	return u.egressWatcher
+}
+
+func (u *mockUnit) updateEgress(egress map[string][]string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.egress = egress
+}
+
 func (u *mockUnit) updateAddress(value string) {
 	u.mu.Lock()
 	defer u.mu.Unlock()
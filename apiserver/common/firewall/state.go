@@ -80,6 +80,8 @@ type Unit interface {
 	Name() string
 	PublicAddress() (network.Address, error)
 	AssignedMachineId() (string, error)
+	EgressAddressesForBinding(binding string) []string
+	WatchEgressAddressesHash() state.StringsWatcher
 }
 
 func (st stateShim) Unit(name string) (Unit, error) {
@@ -0,0 +1,83 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/state"
+)
+
+// IdempotencyBackend records the mutating calls made with client-supplied
+// idempotency keys. It is implemented by *state.State.
+type IdempotencyBackend interface {
+	StartIdempotentCall(user, operation, key string) (state.IdempotentCall, error)
+	CompleteIdempotentCall(user, key string, started time.Time, results []string) error
+	AbortIdempotentCall(user, key string, started time.Time) error
+}
+
+// completeIdempotentCallAttempts is how many times CallIdempotently
+// tries to record the results of a call that succeeded.
+const completeIdempotentCallAttempts = 3
+
+// CallIdempotently makes the given call, on behalf of the given user,
+// under the given idempotency key, recording the identifiers of the
+// entities it returns. If the user has already used the key for a
+// successful call, the call is not made again; instead the results
+// recorded for it are returned, and replayed is true. Keys are scoped
+// to the user, so users can't see each other's results. If the key is
+// empty, the call is simply made.
+//
+// Once the call has succeeded its results are returned even if they
+// can't be recorded, since the call can't be undone; a retry with the
+// key is then not recognised once the call goes stale.
+func CallIdempotently(
+	backend IdempotencyBackend,
+	user names.Tag,
+	operation, key string,
+	call func() ([]string, error),
+) (results []string, replayed bool, err error) {
+	if key == "" {
+		results, err := call()
+		return results, false, errors.Trace(err)
+	}
+	recorded, err := backend.StartIdempotentCall(user.String(), operation, key)
+	if errors.IsAlreadyExists(err) {
+		if recorded.Operation != operation {
+			return nil, false, errors.Errorf(
+				"idempotency key %q was used for %s, not %s",
+				key, recorded.Operation, operation,
+			)
+		}
+		if !recorded.Completed {
+			return nil, false, errors.Errorf("call with idempotency key %q is in progress", key)
+		}
+		return recorded.Results, true, nil
+	} else if err != nil {
+		return nil, false, errors.Trace(err)
+	}
+
+	results, err = call()
+	if err != nil {
+		if abortErr := backend.AbortIdempotentCall(user.String(), key, recorded.Started); abortErr != nil {
+			logger.Errorf("%v", abortErr)
+		}
+		return nil, false, errors.Trace(err)
+	}
+	for attempt := 1; ; attempt++ {
+		err := backend.CompleteIdempotentCall(user.String(), key, recorded.Started, results)
+		if err == nil {
+			break
+		}
+		if errors.IsNotFound(err) || attempt == completeIdempotentCallAttempts {
+			logger.Errorf("%v", err)
+			break
+		}
+		logger.Warningf("%v (retrying)", err)
+	}
+	return results, false, nil
+}
@@ -0,0 +1,157 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/state"
+)
+
+type idempotencySuite struct {
+	testing.IsolationSuite
+	backend *fakeIdempotencyBackend
+	calls   int
+}
+
+var _ = gc.Suite(&idempotencySuite{})
+
+var bob = names.NewUserTag("bob")
+
+func (s *idempotencySuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.backend = &fakeIdempotencyBackend{calls: make(map[string]state.IdempotentCall)}
+	s.calls = 0
+}
+
+func (s *idempotencySuite) call(results ...string) func() ([]string, error) {
+	return func() ([]string, error) {
+		s.calls++
+		return results, nil
+	}
+}
+
+func (s *idempotencySuite) TestNoKey(c *gc.C) {
+	results, replayed, err := common.CallIdempotently(s.backend, bob, "deploy", "", s.call("mysql"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []string{"mysql"})
+	c.Assert(replayed, jc.IsFalse)
+	c.Assert(s.backend.calls, gc.HasLen, 0)
+}
+
+func (s *idempotencySuite) TestReplayed(c *gc.C) {
+	results, replayed, err := common.CallIdempotently(s.backend, bob, "deploy", "key-1", s.call("mysql"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []string{"mysql"})
+	c.Assert(replayed, jc.IsFalse)
+
+	results, replayed, err = common.CallIdempotently(s.backend, bob, "deploy", "key-1", s.call("other"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []string{"mysql"})
+	c.Assert(replayed, jc.IsTrue)
+	c.Assert(s.calls, gc.Equals, 1)
+}
+
+func (s *idempotencySuite) TestInProgress(c *gc.C) {
+	s.backend.calls["user-bob#key-1"] = state.IdempotentCall{User: "user-bob", Key: "key-1", Operation: "deploy"}
+	_, _, err := common.CallIdempotently(s.backend, bob, "deploy", "key-1", s.call())
+	c.Assert(err, gc.ErrorMatches, `call with idempotency key "key-1" is in progress`)
+	c.Assert(s.calls, gc.Equals, 0)
+}
+
+func (s *idempotencySuite) TestDifferentOperation(c *gc.C) {
+	s.backend.calls["user-bob#key-1"] = state.IdempotentCall{User: "user-bob", Key: "key-1", Operation: "deploy", Completed: true}
+	_, _, err := common.CallIdempotently(s.backend, bob, "add-unit", "key-1", s.call())
+	c.Assert(err, gc.ErrorMatches, `idempotency key "key-1" was used for deploy, not add-unit`)
+	c.Assert(s.calls, gc.Equals, 0)
+}
+
+func (s *idempotencySuite) TestFailureAborts(c *gc.C) {
+	_, _, err := common.CallIdempotently(s.backend, bob, "deploy", "key-1", func() ([]string, error) {
+		return nil, errors.New("boom")
+	})
+	c.Assert(err, gc.ErrorMatches, "boom")
+	c.Assert(s.backend.calls, gc.HasLen, 0)
+
+	// The key can be used again.
+	results, replayed, err := common.CallIdempotently(s.backend, bob, "deploy", "key-1", s.call("mysql"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []string{"mysql"})
+	c.Assert(replayed, jc.IsFalse)
+}
+
+func (s *idempotencySuite) TestCompleteRetried(c *gc.C) {
+	s.backend.completeErrs = []error{errors.New("boom")}
+	results, replayed, err := common.CallIdempotently(s.backend, bob, "deploy", "key-1", s.call("mysql"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []string{"mysql"})
+	c.Assert(replayed, jc.IsFalse)
+
+	results, replayed, err = common.CallIdempotently(s.backend, bob, "deploy", "key-1", s.call("other"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []string{"mysql"})
+	c.Assert(replayed, jc.IsTrue)
+	c.Assert(s.calls, gc.Equals, 1)
+}
+
+func (s *idempotencySuite) TestCompleteFailureReportsResults(c *gc.C) {
+	s.backend.completeErrs = []error{errors.New("boom"), errors.New("boom"), errors.New("boom")}
+	results, replayed, err := common.CallIdempotently(s.backend, bob, "deploy", "key-1", s.call("mysql"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []string{"mysql"})
+	c.Assert(replayed, jc.IsFalse)
+	c.Assert(s.backend.completeErrs, gc.HasLen, 0)
+}
+
+func (s *idempotencySuite) TestKeysScopedToUser(c *gc.C) {
+	_, _, err := common.CallIdempotently(s.backend, bob, "deploy", "key-1", s.call("mysql"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, replayed, err := common.CallIdempotently(s.backend, names.NewUserTag("alice"), "deploy", "key-1", s.call("other"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []string{"other"})
+	c.Assert(replayed, jc.IsFalse)
+	c.Assert(s.calls, gc.Equals, 2)
+}
+
+type fakeIdempotencyBackend struct {
+	calls        map[string]state.IdempotentCall
+	completeErrs []error
+}
+
+func (b *fakeIdempotencyBackend) StartIdempotentCall(user, operation, key string) (state.IdempotentCall, error) {
+	if call, ok := b.calls[user+"#"+key]; ok {
+		return call, errors.AlreadyExistsf("idempotency key %q", key)
+	}
+	b.calls[user+"#"+key] = state.IdempotentCall{User: user, Key: key, Operation: operation}
+	return b.calls[user+"#"+key], nil
+}
+
+func (b *fakeIdempotencyBackend) CompleteIdempotentCall(user, key string, started time.Time, results []string) error {
+	if len(b.completeErrs) > 0 {
+		err := b.completeErrs[0]
+		b.completeErrs = b.completeErrs[1:]
+		return err
+	}
+	call, ok := b.calls[user+"#"+key]
+	if !ok || !call.Started.Equal(started) {
+		return errors.NotFoundf("idempotency key %q", key)
+	}
+	call.Completed = true
+	call.Results = results
+	b.calls[user+"#"+key] = call
+	return nil
+}
+
+func (b *fakeIdempotencyBackend) AbortIdempotentCall(user, key string, started time.Time) error {
+	delete(b.calls, user+"#"+key)
+	return nil
+}
@@ -30,6 +30,7 @@ type ModelManagerBackend interface {
 	state.CloudAccessor
 
 	ModelUUID() string
+	ResolveModelUUID(uuidOrAlias string) (string, error)
 	ModelUUIDsForUser(names.UserTag) ([]string, error)
 	ModelBasicInfoForUser(user names.UserTag) ([]state.ModelAccessInfo, error)
 	ModelSummariesForUser(user names.UserTag, all bool) ([]state.ModelSummary, error)
@@ -100,6 +101,7 @@ type Model interface {
 	MigrationMode() state.MigrationMode
 	Name() string
 	UUID() string
+	Alias() string
 	ControllerUUID() string
 	LastModelConnection(user names.UserTag) (time.Time, error)
 	AddUser(state.UserAccessSpec) (permission.UserAccess, error)
@@ -0,0 +1,80 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"time"
+
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// ModelSummaryFromState converts state.ModelSummary to params.ModelSummary.
+func ModelSummaryFromState(mi state.ModelSummary) *params.ModelSummary {
+	summary := &params.ModelSummary{
+		Name:           mi.Name,
+		UUID:           mi.UUID,
+		Alias:          mi.Alias,
+		Type:           string(mi.Type),
+		OwnerTag:       names.NewUserTag(mi.Owner).String(),
+		ControllerUUID: mi.ControllerUUID,
+		IsController:   mi.IsController,
+		Life:           params.Life(mi.Life.String()),
+
+		CloudTag:    mi.CloudTag,
+		CloudRegion: mi.CloudRegion,
+
+		CloudCredentialTag: mi.CloudCredentialTag,
+
+		SLA: &params.ModelSLAInfo{
+			Level: mi.SLALevel,
+			Owner: mi.Owner,
+		},
+
+		DefaultSeries: mi.DefaultSeries,
+		ProviderType:  mi.ProviderType,
+		AgentVersion:  mi.AgentVersion,
+
+		Status:             EntityStatusFromState(mi.Status),
+		Counts:             []params.ModelEntityCount{},
+		UserLastConnection: mi.UserLastConnection,
+	}
+
+	if mi.MachineCount > 0 {
+		summary.Counts = append(summary.Counts, params.ModelEntityCount{params.Machines, mi.MachineCount})
+	}
+
+	if mi.CoreCount > 0 {
+		summary.Counts = append(summary.Counts, params.ModelEntityCount{params.Cores, mi.CoreCount})
+	}
+
+	if mi.UnitCount > 0 {
+		summary.Counts = append(summary.Counts, params.ModelEntityCount{params.Units, mi.UnitCount})
+	}
+
+	access, err := StateToParamsUserAccessPermission(mi.Access)
+	if err == nil {
+		summary.UserAccess = access
+	}
+	if mi.Migration != nil {
+		migration := mi.Migration
+		startTime := migration.StartTime()
+		endTime := new(time.Time)
+		*endTime = migration.EndTime()
+		var zero time.Time
+		if *endTime == zero {
+			endTime = nil
+		}
+
+		summary.Migration = &params.ModelMigrationStatus{
+			Status: migration.StatusMessage(),
+			Start:  &startTime,
+			End:    endTime,
+		}
+	}
+
+	return summary
+}
@@ -0,0 +1,166 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package networkingcommon
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/core/life"
+	corenetwork "github.com/juju/juju/core/network"
+)
+
+// SpacesBacking defines the methods needed to check that endpoint
+// bindings can be satisfied by the model's spaces.
+type SpacesBacking interface {
+	// AllSpaces returns all known Juju network spaces.
+	AllSpaces() ([]BackingSpace, error)
+}
+
+// PlacementZones returns the availability zones targeted by the given
+// zone constraints and "zone=<name>" placement directives. Placements to
+// existing machines do not target a zone, as those machines are already
+// started. An empty result means no particular zone is targeted.
+func PlacementZones(zones *[]string, placements []*instance.Placement) []string {
+	targeted := set.NewStrings()
+	if zones != nil {
+		targeted = set.NewStrings(*zones...)
+	}
+	for _, p := range placements {
+		if p == nil || p.Scope == instance.MachineScope {
+			continue
+		}
+		if name := strings.TrimPrefix(p.Directive, "zone="); name != p.Directive && name != "" {
+			targeted.Add(name)
+		}
+	}
+	return targeted.SortedValues()
+}
+
+// ValidateBindingsFeasible checks that every space bound in the given
+// endpoint bindings has at least one alive subnet in each of the targeted
+// availability zones, so that machines started for the application can
+// be given an address in each space. When no zones are targeted, each
+// space only needs an alive subnet. Subnets which do not record zones are
+// taken to be available in all of them. Bindings to the default space are
+// always satisfiable.
+//
+// A NotValid error describing every problem found is returned when a
+// binding cannot be satisfied.
+func ValidateBindingsFeasible(backing SpacesBacking, bindings map[string]string, zones []string) error {
+	bySpace := make(map[string][]string)
+	for endpoint, spaceName := range bindings {
+		if spaceName == corenetwork.DefaultSpaceName {
+			continue
+		}
+		bySpace[spaceName] = append(bySpace[spaceName], endpoint)
+	}
+	if len(bySpace) == 0 {
+		return nil
+	}
+
+	spaces, err := backing.AllSpaces()
+	if err != nil {
+		return errors.Annotate(err, "getting spaces")
+	}
+	spacesByName := make(map[string]BackingSpace, len(spaces))
+	for _, space := range spaces {
+		spacesByName[space.Name()] = space
+	}
+
+	spaceNames := make([]string, 0, len(bySpace))
+	for name := range bySpace {
+		spaceNames = append(spaceNames, name)
+	}
+	sort.Strings(spaceNames)
+
+	var problems []string
+	for _, name := range spaceNames {
+		endpoints := bySpace[name]
+		sort.Strings(endpoints)
+		bound := fmt.Sprintf("%s %s bound to space %q", plural("endpoint", len(endpoints)), quoteAll(endpoints), name)
+
+		space, ok := spacesByName[name]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s: space not found", bound))
+			continue
+		}
+		missing, err := zonesWithoutSubnets(space, zones)
+		if err != nil {
+			return errors.Annotatef(err, "getting subnets of space %q", name)
+		}
+		switch {
+		case missing == nil:
+			continue
+		case len(missing) == 0:
+			problems = append(problems, fmt.Sprintf(
+				"%s: space has no subnets; add a subnet to it or bind to another space", bound))
+		default:
+			problems = append(problems, fmt.Sprintf(
+				"%s: space has no subnets in availability %s %s; choose other zones or bind to another space",
+				bound, plural("zone", len(missing)), quoteAll(missing)))
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return errors.NewNotValid(nil, fmt.Sprintf(
+		"endpoint bindings cannot be satisfied: %s", strings.Join(problems, "; ")))
+}
+
+// zonesWithoutSubnets returns the targeted zones in which the space has no
+// alive subnet, or an empty slice if the space has no alive subnets at all.
+// It returns nil if the space can be used in every targeted zone.
+func zonesWithoutSubnets(space BackingSpace, zones []string) ([]string, error) {
+	subnets, err := space.Subnets()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	available := set.NewStrings()
+	anyZone := false
+	alive := 0
+	for _, subnet := range subnets {
+		if l := subnet.Life(); l == life.Dying || l == life.Dead {
+			continue
+		}
+		alive++
+		if len(subnet.AvailabilityZones()) == 0 {
+			anyZone = true
+		}
+		available = available.Union(set.NewStrings(subnet.AvailabilityZones()...))
+	}
+	if alive == 0 {
+		return []string{}, nil
+	}
+	if anyZone {
+		return nil, nil
+	}
+	var missing []string
+	for _, zone := range zones {
+		if !available.Contains(zone) {
+			missing = append(missing, zone)
+		}
+	}
+	return missing, nil
+}
+
+func plural(noun string, n int) string {
+	if n == 1 {
+		return noun
+	}
+	return noun + "s"
+}
+
+func quoteAll(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = fmt.Sprintf("%q", v)
+	}
+	return strings.Join(quoted, ", ")
+}
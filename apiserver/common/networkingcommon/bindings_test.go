@@ -0,0 +1,146 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package networkingcommon_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common/networkingcommon"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/core/instance"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type BindingsSuite struct {
+	coretesting.BaseSuite
+
+	backing *fakeSpacesBacking
+}
+
+var _ = gc.Suite(&BindingsSuite{})
+
+func (s *BindingsSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.backing = &fakeSpacesBacking{spaces: []networkingcommon.BackingSpace{
+		newFakeSpace("db",
+			subnetInfo("10.0.0.0/24", params.Alive, "az1"),
+			subnetInfo("10.0.1.0/24", params.Alive, "az2"),
+		),
+		newFakeSpace("dmz",
+			subnetInfo("10.1.0.0/24", params.Alive, "az1"),
+			subnetInfo("10.1.1.0/24", params.Dying, "az2"),
+		),
+		newFakeSpace("empty"),
+		newFakeSpace("maas",
+			subnetInfo("10.2.0.0/24", params.Alive),
+		),
+	}}
+}
+
+func (s *BindingsSuite) TestValidateBindingsFeasibleNoZones(c *gc.C) {
+	err := networkingcommon.ValidateBindingsFeasible(s.backing, map[string]string{
+		"":       "",
+		"server": "db",
+		"public": "dmz",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *BindingsSuite) TestValidateBindingsFeasibleDefaultSpaceOnly(c *gc.C) {
+	s.backing.err = errors.New("boom")
+	err := networkingcommon.ValidateBindingsFeasible(s.backing, map[string]string{
+		"":       "",
+		"server": "",
+	}, []string{"az1"})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *BindingsSuite) TestValidateBindingsFeasibleInZones(c *gc.C) {
+	err := networkingcommon.ValidateBindingsFeasible(s.backing, map[string]string{
+		"server": "db",
+		"admin":  "maas",
+	}, []string{"az1", "az2"})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *BindingsSuite) TestValidateBindingsFeasibleMissingZone(c *gc.C) {
+	err := networkingcommon.ValidateBindingsFeasible(s.backing, map[string]string{
+		"server": "db",
+		"public": "dmz",
+	}, []string{"az2", "az3"})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err, gc.ErrorMatches, `endpoint bindings cannot be satisfied: `+
+		`endpoint "server" bound to space "db": space has no subnets in availability zone "az3"; choose other zones or bind to another space; `+
+		`endpoint "public" bound to space "dmz": space has no subnets in availability zones "az2", "az3"; choose other zones or bind to another space`)
+}
+
+func (s *BindingsSuite) TestValidateBindingsFeasibleEmptyAndUnknownSpaces(c *gc.C) {
+	err := networkingcommon.ValidateBindingsFeasible(s.backing, map[string]string{
+		"server": "empty",
+		"admin":  "empty",
+		"public": "missing",
+	}, nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err, gc.ErrorMatches, `endpoint bindings cannot be satisfied: `+
+		`endpoints "admin", "server" bound to space "empty": space has no subnets; add a subnet to it or bind to another space; `+
+		`endpoint "public" bound to space "missing": space not found`)
+}
+
+func (s *BindingsSuite) TestValidateBindingsFeasibleBackingError(c *gc.C) {
+	s.backing.err = errors.New("boom")
+	err := networkingcommon.ValidateBindingsFeasible(s.backing, map[string]string{"server": "db"}, nil)
+	c.Assert(err, gc.ErrorMatches, "getting spaces: boom")
+}
+
+func (s *BindingsSuite) TestPlacementZones(c *gc.C) {
+	zones := []string{"az2", "az1"}
+	placements := []*instance.Placement{
+		{Scope: instance.MachineScope, Directive: "0"},
+		{Scope: coretesting.ModelTag.Id(), Directive: "zone=az3"},
+		{Scope: coretesting.ModelTag.Id(), Directive: "zone=az1"},
+		{Scope: coretesting.ModelTag.Id(), Directive: "system-id=abc"},
+		nil,
+	}
+	c.Assert(networkingcommon.PlacementZones(&zones, placements), jc.DeepEquals, []string{"az1", "az2", "az3"})
+	c.Assert(networkingcommon.PlacementZones(nil, nil), gc.HasLen, 0)
+}
+
+type fakeSpacesBacking struct {
+	spaces []networkingcommon.BackingSpace
+	err    error
+}
+
+func (b *fakeSpacesBacking) AllSpaces() ([]networkingcommon.BackingSpace, error) {
+	return b.spaces, b.err
+}
+
+// fakeSpace is a FakeSpace whose subnets are given explicitly.
+type fakeSpace struct {
+	apiservertesting.FakeSpace
+	subnets []networkingcommon.BackingSubnet
+}
+
+func newFakeSpace(name string, infos ...networkingcommon.BackingSubnetInfo) *fakeSpace {
+	space := &fakeSpace{FakeSpace: apiservertesting.FakeSpace{SpaceId: name, SpaceName: name}}
+	for _, info := range infos {
+		info.SpaceName = name
+		space.subnets = append(space.subnets, &apiservertesting.FakeSubnet{Info: info})
+	}
+	return space
+}
+
+func (f *fakeSpace) Subnets() ([]networkingcommon.BackingSubnet, error) {
+	return f.subnets, nil
+}
+
+func subnetInfo(cidr string, life params.Life, zones ...string) networkingcommon.BackingSubnetInfo {
+	return networkingcommon.BackingSubnetInfo{
+		CIDR:              cidr,
+		AvailabilityZones: zones,
+		Life:              life,
+	}
+}
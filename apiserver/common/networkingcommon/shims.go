@@ -84,6 +84,22 @@ func (s *stateShim) AllSpaces() ([]BackingSpace, error) {
 	return spaces, nil
 }
 
+func (s *stateShim) RenameSpace(fromName, toName string) error {
+	space, err := s.st.Space(fromName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(space.Rename(toName))
+}
+
+func (s *stateShim) RemoveSpace(name string, force bool) error {
+	return errors.Trace(s.st.RemoveSpace(name, force))
+}
+
+func (s *stateShim) MoveSubnets(spaceName string, cidrs []string) error {
+	return errors.Trace(s.st.MoveSubnets(spaceName, cidrs))
+}
+
 func (s *stateShim) AddSubnet(info BackingSubnetInfo) (BackingSubnet, error) {
 	_, err := s.st.AddSubnet(corenetwork.SubnetInfo{
 		CIDR:              info.CIDR,
@@ -108,6 +124,77 @@ func (s *stateShim) AllSubnets() ([]BackingSubnet, error) {
 	return subnets, nil
 }
 
+func (s *stateShim) AllMachineNetworks() ([]BackingMachineNetwork, error) {
+	subnets, err := s.st.AllSubnets()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	subnetCIDRs := make(map[string]string, len(subnets))
+	for _, subnet := range subnets {
+		subnetCIDRs[subnet.ID()] = subnet.CIDR()
+	}
+
+	machines, err := s.st.AllMachines()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	results := make([]BackingMachineNetwork, len(machines))
+	for i, m := range machines {
+		result := BackingMachineNetwork{
+			MachineId:   m.Id(),
+			OpenedPorts: make(map[string][]BackingPortRange),
+		}
+
+		addresses, err := m.AllAddresses()
+		if err != nil {
+			return nil, errors.Annotatef(err, "getting addresses of machine %q", m.Id())
+		}
+		deviceAddresses := make(map[string][]BackingAddress)
+		for _, addr := range addresses {
+			deviceAddresses[addr.DeviceName()] = append(deviceAddresses[addr.DeviceName()], BackingAddress{
+				Value:      addr.Value(),
+				SubnetCIDR: addr.SubnetCIDR(),
+			})
+		}
+		devices, err := m.AllLinkLayerDevices()
+		if err != nil {
+			return nil, errors.Annotatef(err, "getting devices of machine %q", m.Id())
+		}
+		for _, dev := range devices {
+			result.Devices = append(result.Devices, BackingDevice{
+				Name:       dev.Name(),
+				Type:       string(dev.Type()),
+				MACAddress: dev.MACAddress(),
+				ParentName: dev.ParentName(),
+				Addresses:  deviceAddresses[dev.Name()],
+			})
+		}
+
+		allPorts, err := m.AllPorts()
+		if err != nil {
+			return nil, errors.Annotatef(err, "getting opened ports of machine %q", m.Id())
+		}
+		for _, ports := range allPorts {
+			cidr := ""
+			if ports.SubnetID() != "" {
+				var ok bool
+				if cidr, ok = subnetCIDRs[ports.SubnetID()]; !ok {
+					// The subnet has been removed.
+					continue
+				}
+			}
+			for portRange, unitName := range ports.AllPortRanges() {
+				result.OpenedPorts[cidr] = append(result.OpenedPorts[cidr], BackingPortRange{
+					UnitName:  unitName,
+					PortRange: portRange,
+				})
+			}
+		}
+		results[i] = result
+	}
+	return results, nil
+}
+
 func (s *stateShim) AvailabilityZones() ([]providercommon.AvailabilityZone, error) {
 	// TODO(dimitern): Fix this to get them from state when available!
 	return nil, nil
@@ -5,15 +5,19 @@ package networkingcommon
 
 import (
 	"fmt"
+	"net"
 
+	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"gopkg.in/juju/names.v3"
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/life"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/state"
 )
 
 // SupportsSpaces checks if the environment implements NetworkingEnviron
@@ -50,8 +54,93 @@ func CreateSpaces(backing NetworkBacking, ctx context.ProviderCallContext, args
 	return results, nil
 }
 
+// RenameSpaces renames Juju network spaces, updating every constraint,
+// endpoint binding and controller config setting that refers to them.
+func RenameSpaces(backing NetworkBacking, args params.RenameSpacesParams) params.ErrorResults {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Changes)),
+	}
+	for i, change := range args.Changes {
+		if err := RenameOneSpace(backing, change); err != nil {
+			results.Results[i].Error = common.ServerError(errors.Trace(err))
+		}
+	}
+	return results
+}
+
+// RenameOneSpace renames one Juju network space.
+func RenameOneSpace(backing NetworkBacking, args params.RenameSpaceParams) error {
+	fromTag, err := names.ParseSpaceTag(args.FromSpaceTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	toTag, err := names.ParseSpaceTag(args.ToSpaceTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(backing.RenameSpace(fromTag.Id(), toTag.Id()))
+}
+
+// RemoveSpaces removes Juju network spaces. Unless forced, a space that is
+// still used by constraints, endpoint bindings or controller settings is
+// not removed.
+func RemoveSpaces(backing NetworkBacking, args params.RemoveSpaceParams) params.ErrorResults {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	for i, arg := range args.Args {
+		spaceTag, err := names.ParseSpaceTag(arg.SpaceTag)
+		if err == nil {
+			err = backing.RemoveSpace(spaceTag.Id(), arg.Force)
+		}
+		if err != nil {
+			results.Results[i].Error = common.ServerError(errors.Trace(err))
+		}
+	}
+	return results
+}
+
+// MoveSubnets moves subnets between Juju network spaces. A move that
+// would break an endpoint binding or a machine's space requirements is
+// refused, and the result lists each of the broken requirements.
+func MoveSubnets(backing NetworkBacking, args params.MoveSubnetsParams) params.MoveSubnetsResults {
+	results := params.MoveSubnetsResults{
+		Results: make([]params.MoveSubnetsResult, len(args.Args)),
+	}
+	for i, arg := range args.Args {
+		err := moveSubnets(backing, arg)
+		if violationsErr, ok := errors.Cause(err).(*state.MoveSubnetsViolationsError); ok {
+			for _, v := range violationsErr.Violations {
+				results.Results[i].Violations = append(results.Results[i].Violations, params.MoveSubnetsViolation{
+					Tag:    v.Tag,
+					Reason: v.Reason,
+				})
+			}
+		}
+		if err != nil {
+			results.Results[i].Error = common.ServerError(errors.Trace(err))
+		}
+	}
+	return results
+}
+
+func moveSubnets(backing NetworkBacking, args params.MoveSubnetsParam) error {
+	spaceTag, err := names.ParseSpaceTag(args.SpaceTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	cidrs := normaliseCIDRs(args.CIDRs)
+	for _, cidr := range cidrs {
+		if !network.IsValidCidr(cidr) {
+			return errors.NotValidf("CIDR %q", cidr)
+		}
+	}
+	return errors.Trace(backing.MoveSubnets(spaceTag.Id(), cidrs))
+}
+
 // CreateOneSpace creates one new Juju network space, associating the
-// specified subnets with it (optional; can be empty).
+// specified subnets with it (optional; can be empty). The subnets must
+// not overlap any subnet that stays in another space.
 func CreateOneSpace(backing NetworkBacking, args params.CreateSpaceParams) error {
 	// Validate the args, assemble information for api.backing.AddSpaces
 	spaceTag, err := names.ParseSpaceTag(args.SpaceTag)
@@ -59,16 +148,96 @@ func CreateOneSpace(backing NetworkBacking, args params.CreateSpaceParams) error
 		return errors.Trace(err)
 	}
 
-	for _, cidr := range args.CIDRs {
+	cidrs := normaliseCIDRs(args.CIDRs)
+	for _, cidr := range cidrs {
 		if !network.IsValidCidr(cidr) {
 			return errors.New(fmt.Sprintf("%q is not a valid CIDR", cidr))
 		}
 	}
+	if err := validateSubnetOverlaps(backing, cidrs); err != nil {
+		return errors.Trace(err)
+	}
 
 	// Add the validated space.
-	err = backing.AddSpace(spaceTag.Id(), network.Id(args.ProviderId), args.CIDRs, args.Public)
+	err = backing.AddSpace(spaceTag.Id(), network.Id(args.ProviderId), cidrs, args.Public)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// normaliseCIDRs returns the given CIDRs in canonical form, so that IPv6
+// subnets can be referred to however their addresses are spelled.
+func normaliseCIDRs(cidrs []string) []string {
+	if cidrs == nil {
+		return nil
+	}
+	result := make([]string, len(cidrs))
+	for i, cidr := range cidrs {
+		result[i] = network.NormaliseCIDR(cidr)
+	}
+	return result
+}
+
+// validateSubnetOverlaps returns an error identifying the first subnet
+// of the model that overlaps one of the given CIDRs without being added
+// to the same space. A FAN overlay subnet and its underlay are allowed
+// to overlap if the model's allow-fan-subnet-overlap config is set.
+func validateSubnetOverlaps(backing NetworkBacking, cidrs []string) error {
+	if len(cidrs) == 0 {
+		return nil
+	}
+	cfg, err := backing.ModelConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	subnets, err := backing.AllSubnets()
 	if err != nil {
 		return errors.Trace(err)
 	}
+
+	requested := set.NewStrings(cidrs...)
+	byCIDR := make(map[string]BackingSubnet, len(subnets))
+	for _, subnet := range subnets {
+		byCIDR[subnet.CIDR()] = subnet
+	}
+
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, subnet := range subnets {
+			if requested.Contains(subnet.CIDR()) || subnet.Life() == life.Dead {
+				continue
+			}
+			_, subnetNet, err := net.ParseCIDR(subnet.CIDR())
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if !ipNet.Contains(subnetNet.IP) && !subnetNet.Contains(ipNet.IP) {
+				continue
+			}
+			if cfg.AllowFanSubnetOverlap() && isFanPair(cidr, byCIDR[cidr], subnet) {
+				continue
+			}
+			spaceName := subnet.SpaceName()
+			if spaceName == "" {
+				spaceName = network.DefaultSpaceName
+			}
+			return errors.NewNotValid(nil, fmt.Sprintf(
+				"CIDR %q overlaps subnet %q in space %q", cidr, subnet.CIDR(), spaceName))
+		}
+	}
 	return nil
 }
+
+// isFanPair returns whether either the subnet with the given CIDR or
+// other is a FAN overlay of the other. The subnet is nil if the CIDR is
+// not known to the model.
+func isFanPair(cidr string, subnet, other BackingSubnet) bool {
+	if other.FanLocalUnderlay() == cidr {
+		return true
+	}
+	return subnet != nil && subnet.FanLocalUnderlay() == other.CIDR()
+}
@@ -52,6 +52,14 @@ type checkCreateSpacesParams struct {
 	Error      string
 	Public     bool
 	ProviderId string
+
+	// ChecksOverlaps is set when the subnets are checked for overlaps
+	// even though an error is expected.
+	ChecksOverlaps bool
+
+	// AddedSubnets, if set, are the subnets expected to be added to
+	// the space when they differ from Subnets.
+	AddedSubnets []string
 }
 
 func (s *SpacesSuite) checkCreateSpaces(c *gc.C, p checkCreateSpacesParams) {
@@ -86,7 +94,18 @@ func (s *SpacesSuite) checkCreateSpaces(c *gc.C, p checkCreateSpacesParams) {
 		apiservertesting.ZonedNetworkingEnvironCall("SupportsSpaces", callCtx),
 	}
 
-	addSpaceCalls := append(baseCalls, apiservertesting.BackingCall("AddSpace", p.Name, network.Id(p.ProviderId), p.Subnets, p.Public))
+	if len(p.Subnets) > 0 && (p.Error == "" || p.ChecksOverlaps) {
+		baseCalls = append(baseCalls,
+			apiservertesting.BackingCall("ModelConfig"),
+			apiservertesting.BackingCall("AllSubnets"),
+		)
+	}
+
+	added := p.Subnets
+	if p.AddedSubnets != nil {
+		added = p.AddedSubnets
+	}
+	addSpaceCalls := append(baseCalls, apiservertesting.BackingCall("AddSpace", p.Name, network.Id(p.ProviderId), added, p.Public))
 
 	if p.Error == "" {
 		apiservertesting.CheckMethodCalls(c, apiservertesting.SharedStub, addSpaceCalls...)
@@ -113,6 +132,80 @@ func (s *SpacesSuite) TestCreateInvalidCIDR(c *gc.C) {
 	s.checkCreateSpaces(c, p)
 }
 
+func (s *SpacesSuite) TestCreateOverlappingSubnet(c *gc.C) {
+	p := checkCreateSpacesParams{
+		Name:           "foo",
+		Subnets:        []string{"10.10.0.0/16"},
+		Error:          `CIDR "10.10.0.0/16" overlaps subnet "10.10.0.0/24" in space "private"`,
+		ChecksOverlaps: true,
+	}
+	s.checkCreateSpaces(c, p)
+}
+
+func (s *SpacesSuite) TestCreateContainedSubnet(c *gc.C) {
+	p := checkCreateSpacesParams{
+		Name:           "foo",
+		Subnets:        []string{"10.10.0.128/25"},
+		Error:          `CIDR "10.10.0.128/25" overlaps subnet "10.10.0.0/24" in space "private"`,
+		ChecksOverlaps: true,
+	}
+	s.checkCreateSpaces(c, p)
+}
+
+func (s *SpacesSuite) TestCreateWithOverlappingSubnets(c *gc.C) {
+	p := checkCreateSpacesParams{
+		Name:    "foo",
+		Subnets: []string{"10.10.0.0/24", "10.10.0.0/16"},
+	}
+	s.checkCreateSpaces(c, p)
+}
+
+func (s *SpacesSuite) TestCreateIPv6SpaceNormalisesCIDRs(c *gc.C) {
+	p := checkCreateSpacesParams{
+		Name:         "foo",
+		Subnets:      []string{"2001:DB8:0::/32", "10.20.0.0/16"},
+		AddedSubnets: []string{"2001:db8::/32", "10.20.0.0/16"},
+	}
+	s.checkCreateSpaces(c, p)
+}
+
+func (s *SpacesSuite) addFanOverlay(c *gc.C) {
+	apiservertesting.BackingInstance.Subnets = append(apiservertesting.BackingInstance.Subnets,
+		&apiservertesting.FakeSubnet{Info: networkingcommon.BackingSubnetInfo{
+			CIDR:             "10.0.0.0/8",
+			SpaceName:        "private",
+			FanLocalUnderlay: "10.10.0.0/24",
+		}},
+	)
+}
+
+func (s *SpacesSuite) TestCreateOverlappingFanOverlay(c *gc.C) {
+	s.addFanOverlay(c)
+	p := checkCreateSpacesParams{
+		Name:           "foo",
+		Subnets:        []string{"10.10.0.0/24"},
+		Error:          `CIDR "10.10.0.0/24" overlaps subnet "10.0.0.0/8" in space "private"`,
+		ChecksOverlaps: true,
+	}
+	s.checkCreateSpaces(c, p)
+}
+
+func (s *SpacesSuite) TestCreateOverlappingFanOverlayAllowed(c *gc.C) {
+	s.addFanOverlay(c)
+	backing := apiservertesting.BackingInstance
+	cfg, err := backing.EnvConfig.Apply(map[string]interface{}{
+		"allow-fan-subnet-overlap": true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	backing.EnvConfig = cfg
+
+	p := checkCreateSpacesParams{
+		Name:    "foo",
+		Subnets: []string{"10.10.0.0/24"},
+	}
+	s.checkCreateSpaces(c, p)
+}
+
 func (s *SpacesSuite) TestPublic(c *gc.C) {
 	p := checkCreateSpacesParams{
 		Name:    "foo",
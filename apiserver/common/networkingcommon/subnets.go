@@ -212,6 +212,7 @@ func (cache *addSubnetsCache) cacheSubnets(ctx context.ProviderCallContext) erro
 
 	for i := range subnetInfo {
 		subnet := subnetInfo[i]
+		subnet.CIDR = network.NormaliseCIDR(subnet.CIDR)
 		cidr := subnet.CIDR
 		providerId := string(subnet.ProviderId)
 		logger.Tracef(
@@ -279,6 +280,7 @@ func (cache *addSubnetsCache) validateSubnet(ctx context.ProviderCallContext, ci
 		return nil, errors.Errorf("CIDR and SubnetProviderId cannot be both set")
 	}
 	if haveCidr {
+		cidr = network.NormaliseCIDR(cidr)
 		if !network.IsValidCidr(cidr) {
 			return nil, errors.New(fmt.Sprintf("%q is not a valid CIDR", cidr))
 		}
@@ -0,0 +1,112 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package networkingcommon
+
+import (
+	"sort"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// NetworkTopology returns the spaces and subnets known to the backing,
+// the network devices of every machine, and the ports opened on each
+// subnet, as a single document.
+func NetworkTopology(backing NetworkBacking) (params.NetworkTopology, error) {
+	var result params.NetworkTopology
+
+	spaces, err := backing.AllSpaces()
+	if err != nil {
+		return result, errors.Annotate(err, "getting spaces")
+	}
+	result.Spaces = make([]params.Space, len(spaces))
+	for i, space := range spaces {
+		subnets, err := space.Subnets()
+		if err != nil {
+			return result, errors.Annotatef(err, "getting subnets of space %q", space.Name())
+		}
+		result.Spaces[i] = params.Space{
+			Id:      space.Id(),
+			Name:    space.Name(),
+			Subnets: make([]params.Subnet, len(subnets)),
+		}
+		for j, subnet := range subnets {
+			result.Spaces[i].Subnets[j] = BackingSubnetToParamsSubnet(subnet)
+		}
+	}
+
+	machines, err := backing.AllMachineNetworks()
+	if err != nil {
+		return result, errors.Annotate(err, "getting machine networks")
+	}
+	result.Machines = make([]params.MachineNetworkTopology, len(machines))
+	openedPorts := make(map[string][]params.OpenedPortRange)
+	for i, machine := range machines {
+		machineTag := names.NewMachineTag(machine.MachineId).String()
+		result.Machines[i] = params.MachineNetworkTopology{
+			MachineTag: machineTag,
+			Devices:    make([]params.NetworkDeviceTopology, len(machine.Devices)),
+		}
+		for j, dev := range machine.Devices {
+			device := params.NetworkDeviceTopology{
+				Name:       dev.Name,
+				Type:       dev.Type,
+				MACAddress: dev.MACAddress,
+				ParentName: dev.ParentName,
+			}
+			for _, addr := range dev.Addresses {
+				device.Addresses = append(device.Addresses, params.NetworkAddressTopology{
+					Value:      addr.Value,
+					SubnetCIDR: addr.SubnetCIDR,
+				})
+			}
+			result.Machines[i].Devices[j] = device
+		}
+		for cidr, portRanges := range machine.OpenedPorts {
+			for _, portRange := range portRanges {
+				openedPorts[cidr] = append(openedPorts[cidr], params.OpenedPortRange{
+					MachineTag: machineTag,
+					UnitTag:    names.NewUnitTag(portRange.UnitName).String(),
+					PortRange:  params.FromNetworkPortRange(portRange.PortRange),
+				})
+			}
+		}
+	}
+
+	cidrs := make([]string, 0, len(openedPorts))
+	for cidr := range openedPorts {
+		cidrs = append(cidrs, cidr)
+	}
+	sort.Strings(cidrs)
+	result.OpenedPorts = make([]params.SubnetOpenedPorts, len(cidrs))
+	for i, cidr := range cidrs {
+		ports := openedPorts[cidr]
+		sort.Slice(ports, func(a, b int) bool {
+			return openedPortRangeLess(ports[a], ports[b])
+		})
+		result.OpenedPorts[i] = params.SubnetOpenedPorts{
+			SubnetCIDR: cidr,
+			Ports:      ports,
+		}
+	}
+	return result, nil
+}
+
+func openedPortRangeLess(a, b params.OpenedPortRange) bool {
+	if a.MachineTag != b.MachineTag {
+		return a.MachineTag < b.MachineTag
+	}
+	if a.UnitTag != b.UnitTag {
+		return a.UnitTag < b.UnitTag
+	}
+	if a.PortRange.Protocol != b.PortRange.Protocol {
+		return a.PortRange.Protocol < b.PortRange.Protocol
+	}
+	if a.PortRange.FromPort != b.PortRange.FromPort {
+		return a.PortRange.FromPort < b.PortRange.FromPort
+	}
+	return a.PortRange.ToPort < b.PortRange.ToPort
+}
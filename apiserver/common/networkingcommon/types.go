@@ -35,6 +35,7 @@ type BackingSubnet interface {
 	Status() string
 	SpaceName() string
 	SpaceID() string
+	FanLocalUnderlay() string
 	Life() life.Value
 }
 
@@ -74,6 +75,10 @@ type BackingSubnetInfo struct {
 	SpaceName string
 	SpaceID   string
 
+	// FanLocalUnderlay holds the CIDR of the underlay subnet of a FAN
+	// overlay subnet. It is empty for other subnets.
+	FanLocalUnderlay string
+
 	// Status holds the status of the subnet. Normally this will be
 	// calculated from the reference count and Life of a subnet.
 	Status string
@@ -98,6 +103,42 @@ type BackingSpace interface {
 	ProviderId() corenetwork.Id
 }
 
+// BackingMachineNetwork describes the network devices of a machine and
+// the ports opened on it.
+type BackingMachineNetwork struct {
+	// MachineId is the id of the machine.
+	MachineId string
+
+	// Devices holds the machine's link-layer devices.
+	Devices []BackingDevice
+
+	// OpenedPorts holds the port ranges opened on the machine, keyed by
+	// the CIDR of the subnet they are opened on. Ports opened on all of
+	// the machine's subnets have an empty key.
+	OpenedPorts map[string][]BackingPortRange
+}
+
+// BackingDevice describes a link-layer device of a machine.
+type BackingDevice struct {
+	Name       string
+	Type       string
+	MACAddress string
+	ParentName string
+	Addresses  []BackingAddress
+}
+
+// BackingAddress describes an address assigned to a link-layer device.
+type BackingAddress struct {
+	Value      string
+	SubnetCIDR string
+}
+
+// BackingPortRange describes a port range opened by a unit.
+type BackingPortRange struct {
+	UnitName  string
+	PortRange corenetwork.PortRange
+}
+
 // NetworkBacking defines the methods needed by the API facade to store and
 // retrieve information from the underlying persistency layer (state
 // DB).
@@ -118,12 +159,27 @@ type NetworkBacking interface {
 	// AllSpaces returns all known Juju network spaces.
 	AllSpaces() ([]BackingSpace, error)
 
+	// RenameSpace renames a space, along with every reference to it.
+	RenameSpace(fromName, toName string) error
+
+	// RemoveSpace removes a space, moving its subnets to the default space.
+	// Unless force is true, removal fails while anything else uses the space.
+	RemoveSpace(name string, force bool) error
+
+	// MoveSubnets moves the subnets with the given CIDRs into the named
+	// space, provided no network requirement would be broken.
+	MoveSubnets(spaceName string, cidrs []string) error
+
 	// AddSubnet creates a backing subnet for an existing subnet.
 	AddSubnet(BackingSubnetInfo) (BackingSubnet, error)
 
 	// AllSubnets returns all backing subnets.
 	AllSubnets() ([]BackingSubnet, error)
 
+	// AllMachineNetworks returns the network devices of every machine,
+	// along with the ports opened on them.
+	AllMachineNetworks() ([]BackingMachineNetwork, error)
+
 	// ModelTag returns the tag of the model this state is associated to.
 	ModelTag() names.ModelTag
 
@@ -0,0 +1,105 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package networkingcommon
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/juju/collections/set"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+)
+
+// hostOnlyBridges holds the names of the bridges set up on hosts for
+// their containers. Their subnets are local to the host, and so are
+// never known to the model.
+var hostOnlyBridges = set.NewStrings(
+	network.DefaultLXCBridge,
+	network.DefaultLXDBridge,
+	network.DefaultKVMBridge,
+)
+
+// ValidateNetworkConfig returns the problems with the given network config
+// of a machine which the controller cannot reconcile with the CIDRs of the
+// model's known subnets: subnets which overlap a known subnet, or those of
+// the machine's other devices, without matching it, and, if the model
+// knows of any subnets, subnets it does not know. Loopback and link-local
+// addresses, and those of the container bridges, are not checked.
+func ValidateNetworkConfig(config []params.NetworkConfig, knownCIDRs []string) []params.NetworkConfigValidation {
+	known := make(map[string]*net.IPNet)
+	knownNames := set.NewStrings()
+	for _, cidr := range knownCIDRs {
+		if _, ipNet, err := net.ParseCIDR(cidr); err == nil {
+			known[ipNet.String()] = ipNet
+			knownNames.Add(ipNet.String())
+		}
+	}
+	sortedKnown := knownNames.SortedValues()
+
+	type deviceSubnet struct {
+		config params.NetworkConfig
+		ipNet  *net.IPNet
+	}
+	var subnets []deviceSubnet
+	for _, c := range config {
+		if c.CIDR == "" || c.Address == "" || hostOnlyBridges.Contains(c.InterfaceName) {
+			continue
+		}
+		ip := net.ParseIP(c.Address)
+		_, ipNet, err := net.ParseCIDR(c.CIDR)
+		if ip == nil || err != nil {
+			// Config which cannot be parsed is not recorded.
+			continue
+		}
+		if ip.IsLoopback() || ip.IsLinkLocalUnicast() {
+			continue
+		}
+		subnets = append(subnets, deviceSubnet{c, ipNet})
+	}
+
+	var validations []params.NetworkConfigValidation
+	for _, s := range subnets {
+		cidr := s.ipNet.String()
+		if _, ok := known[cidr]; ok {
+			continue
+		}
+		message := ""
+		for _, other := range sortedKnown {
+			if overlaps(s.ipNet, known[other]) {
+				message = fmt.Sprintf("subnet %q overlaps known subnet %q", cidr, other)
+				break
+			}
+		}
+		if message == "" {
+			for _, other := range subnets {
+				otherCIDR := other.ipNet.String()
+				if otherCIDR != cidr && overlaps(s.ipNet, other.ipNet) {
+					message = fmt.Sprintf("subnet %q overlaps subnet %q of %q", cidr, otherCIDR, other.config.InterfaceName)
+					break
+				}
+			}
+		}
+		if message == "" && len(known) > 0 {
+			message = fmt.Sprintf("subnet %q is not known to the model", cidr)
+		}
+		if message == "" {
+			continue
+		}
+		validations = append(validations, params.NetworkConfigValidation{
+			InterfaceName: s.config.InterfaceName,
+			Address:       s.config.Address,
+			CIDR:          s.config.CIDR,
+			Message:       message,
+		})
+	}
+	return validations
+}
+
+// overlaps reports whether the given subnets have any addresses in
+// common.
+func overlaps(a, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
@@ -0,0 +1,74 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package networkingcommon_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common/networkingcommon"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ValidationSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&ValidationSuite{})
+
+func (s *ValidationSuite) TestValidateNetworkConfigKnownSubnets(c *gc.C) {
+	config := []params.NetworkConfig{
+		{InterfaceName: "lo", CIDR: "127.0.0.0/8", Address: "127.0.0.1"},
+		{InterfaceName: "eth0", CIDR: "10.0.0.0/24", Address: "10.0.0.2"},
+		{InterfaceName: "eth0", CIDR: "fe80::/64", Address: "fe80::1"},
+		{InterfaceName: "lxdbr0", CIDR: "10.160.31.0/24", Address: "10.160.31.1"},
+		{InterfaceName: "eth1"},
+	}
+	validations := networkingcommon.ValidateNetworkConfig(config, []string{"10.0.0.0/24"})
+	c.Assert(validations, gc.HasLen, 0)
+}
+
+func (s *ValidationSuite) TestValidateNetworkConfigUnknownSubnet(c *gc.C) {
+	config := []params.NetworkConfig{
+		{InterfaceName: "eth0", CIDR: "10.0.0.0/24", Address: "10.0.0.2"},
+		{InterfaceName: "eth1", CIDR: "192.168.1.0/24", Address: "192.168.1.2"},
+	}
+	validations := networkingcommon.ValidateNetworkConfig(config, []string{"10.0.0.0/24"})
+	c.Assert(validations, jc.DeepEquals, []params.NetworkConfigValidation{{
+		InterfaceName: "eth1",
+		Address:       "192.168.1.2",
+		CIDR:          "192.168.1.0/24",
+		Message:       `subnet "192.168.1.0/24" is not known to the model`,
+	}})
+
+	// Models which know no subnets cannot tell which are unknown.
+	validations = networkingcommon.ValidateNetworkConfig(config, nil)
+	c.Assert(validations, gc.HasLen, 0)
+}
+
+func (s *ValidationSuite) TestValidateNetworkConfigOverlappingSubnets(c *gc.C) {
+	config := []params.NetworkConfig{
+		{InterfaceName: "eth0", CIDR: "10.0.0.0/16", Address: "10.0.0.2"},
+		{InterfaceName: "eth1", CIDR: "192.168.0.0/16", Address: "192.168.1.2"},
+		{InterfaceName: "eth2", CIDR: "192.168.1.0/24", Address: "192.168.1.3"},
+	}
+	validations := networkingcommon.ValidateNetworkConfig(config, []string{"10.0.0.0/24"})
+	c.Assert(validations, jc.DeepEquals, []params.NetworkConfigValidation{{
+		InterfaceName: "eth0",
+		Address:       "10.0.0.2",
+		CIDR:          "10.0.0.0/16",
+		Message:       `subnet "10.0.0.0/16" overlaps known subnet "10.0.0.0/24"`,
+	}, {
+		InterfaceName: "eth1",
+		Address:       "192.168.1.2",
+		CIDR:          "192.168.0.0/16",
+		Message:       `subnet "192.168.0.0/16" overlaps subnet "192.168.1.0/24" of "eth2"`,
+	}, {
+		InterfaceName: "eth2",
+		Address:       "192.168.1.3",
+		CIDR:          "192.168.1.0/24",
+		Message:       `subnet "192.168.1.0/24" overlaps subnet "192.168.0.0/16" of "eth1"`,
+	}})
+}
@@ -0,0 +1,67 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"encoding/base64"
+	"sort"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+const (
+	// DefaultPageSize is the number of results in a page when the
+	// client asks for a page without giving a limit.
+	DefaultPageSize = 100
+
+	// MaxPageSize is the largest number of results in a page.
+	MaxPageSize = 1000
+)
+
+// Paginate returns the bounds, start inclusive and end exclusive, of
+// the page of the given keys requested, along with the page info to
+// return to the client. The keys identify the results of a list-style
+// API call; they must be unique and sorted.
+//
+// Cursors are opaque to clients but encode the key of the last result
+// returned, so that a page starts in the right place even if results
+// have been added or removed since the previous page was fetched.
+//
+// If no page is requested, the bounds cover all the keys and the page
+// info is nil.
+func Paginate(page params.PageParams, keys []string) (start, end int, info *params.PageInfo, err error) {
+	if page.Limit < 0 {
+		return 0, 0, nil, errors.BadRequestf("negative page limit %d", page.Limit)
+	}
+	if page == (params.PageParams{}) {
+		return 0, len(keys), nil, nil
+	}
+	limit := page.Limit
+	switch {
+	case limit == 0:
+		limit = DefaultPageSize
+	case limit > MaxPageSize:
+		limit = MaxPageSize
+	}
+	if page.Cursor != "" {
+		after, err := base64.RawURLEncoding.DecodeString(page.Cursor)
+		if err != nil {
+			return 0, 0, nil, errors.BadRequestf("invalid page cursor %q", page.Cursor)
+		}
+		start = sort.SearchStrings(keys, string(after))
+		if start < len(keys) && keys[start] == string(after) {
+			start++
+		}
+	}
+	end = start + limit
+	info = &params.PageInfo{}
+	if end < len(keys) {
+		info.NextCursor = base64.RawURLEncoding.EncodeToString([]byte(keys[end-1]))
+	} else {
+		end = len(keys)
+	}
+	return start, end, info, nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common_test
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+)
+
+type paginationSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&paginationSuite{})
+
+var pageKeys = []string{"a", "b", "c", "d", "e"}
+
+func (*paginationSuite) TestNoPage(c *gc.C) {
+	start, end, info, err := common.Paginate(params.PageParams{}, pageKeys)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(start, gc.Equals, 0)
+	c.Assert(end, gc.Equals, 5)
+	c.Assert(info, gc.IsNil)
+}
+
+func (*paginationSuite) TestPages(c *gc.C) {
+	var pages [][]string
+	page := params.PageParams{Limit: 2}
+	for {
+		start, end, info, err := common.Paginate(page, pageKeys)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(info, gc.NotNil)
+		pages = append(pages, pageKeys[start:end])
+		if info.NextCursor == "" {
+			break
+		}
+		page.Cursor = info.NextCursor
+	}
+	c.Assert(pages, jc.DeepEquals, [][]string{{"a", "b"}, {"c", "d"}, {"e"}})
+}
+
+func (*paginationSuite) TestCursorSurvivesChanges(c *gc.C) {
+	_, _, info, err := common.Paginate(params.PageParams{Limit: 2}, pageKeys)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The last result of the first page has been removed, and
+	// another added before it.
+	keys := []string{"a", "aa", "c", "d", "e"}
+	start, end, _, err := common.Paginate(params.PageParams{Cursor: info.NextCursor, Limit: 2}, keys)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keys[start:end], jc.DeepEquals, []string{"c", "d"})
+}
+
+func (*paginationSuite) TestDefaultAndMaxPageSize(c *gc.C) {
+	keys := make([]string, common.MaxPageSize+10)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("%06d", i)
+	}
+	_, _, info, err := common.Paginate(params.PageParams{Limit: 2}, keys)
+	c.Assert(err, jc.ErrorIsNil)
+
+	start, end, _, err := common.Paginate(params.PageParams{Cursor: info.NextCursor}, keys)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(end-start, gc.Equals, common.DefaultPageSize)
+
+	start, end, _, err = common.Paginate(params.PageParams{Limit: common.MaxPageSize + 5}, keys)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(end-start, gc.Equals, common.MaxPageSize)
+}
+
+func (*paginationSuite) TestInvalidPage(c *gc.C) {
+	_, _, _, err := common.Paginate(params.PageParams{Limit: -1}, pageKeys)
+	c.Assert(err, jc.Satisfies, errors.IsBadRequest)
+	_, _, _, err = common.Paginate(params.PageParams{Cursor: "!!"}, pageKeys)
+	c.Assert(err, jc.Satisfies, errors.IsBadRequest)
+}
@@ -0,0 +1,44 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/controller"
+)
+
+// CheckStepUpAuth returns ErrSecondFactorRequired if the controller
+// requires step-up authentication for destructive operations, and the
+// connection authorized by auth has not had a second factor asserted on
+// it within the maximum age allowed by the controller config.
+func CheckStepUpAuth(auth facade.Authorizer, cfg controller.Config, now time.Time) error {
+	maxAge := cfg.StepUpAuthMaxAge()
+	if maxAge <= 0 {
+		return nil
+	}
+	session, ok := auth.(facade.StepUpSession)
+	if !ok {
+		return errors.Trace(ErrSecondFactorRequired)
+	}
+	assertedAt := session.SecondFactorAssertedAt()
+	if assertedAt.IsZero() || now.Sub(assertedAt) > maxAge {
+		return errors.Trace(ErrSecondFactorRequired)
+	}
+	return nil
+}
+
+// CheckOperationStepUpAuth returns ErrSecondFactorRequired if the
+// controller requires step-up authentication for the given destructive
+// operation, one of those that may be listed in the controller's
+// step-up-auth-operations config, and CheckStepUpAuth fails.
+func CheckOperationStepUpAuth(auth facade.Authorizer, cfg controller.Config, operation string, now time.Time) error {
+	if !cfg.StepUpAuthOperations().Contains(operation) {
+		return nil
+	}
+	return errors.Trace(CheckStepUpAuth(auth, cfg, now))
+}
@@ -0,0 +1,78 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/testing"
+)
+
+type stepUpSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&stepUpSuite{})
+
+var stepUpConfig = controller.Config{
+	controller.StepUpAuthMaxAge: 5 * time.Minute,
+	controller.StepUpAuthRPID:   "juju.example.com",
+}
+
+func (s *stepUpSuite) TestNotRequired(c *gc.C) {
+	auth := apiservertesting.FakeAuthorizer{Tag: names.NewUserTag("fred")}
+	err := common.CheckStepUpAuth(auth, controller.Config{}, time.Now())
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *stepUpSuite) TestRequiredWithoutSession(c *gc.C) {
+	auth := apiservertesting.FakeAuthorizer{Tag: names.NewUserTag("fred")}
+	err := common.CheckStepUpAuth(auth, stepUpConfig, time.Now())
+	c.Assert(params.IsCodeSecondFactorRequired(common.ServerError(err)), jc.IsTrue)
+}
+
+func (s *stepUpSuite) TestRequiredNeverAsserted(c *gc.C) {
+	auth := &apiservertesting.FakeStepUpAuthorizer{}
+	err := common.CheckStepUpAuth(auth, stepUpConfig, time.Now())
+	c.Assert(err, gc.ErrorMatches, "second factor required")
+}
+
+func (s *stepUpSuite) TestRecentAssertion(c *gc.C) {
+	now := time.Now()
+	auth := &apiservertesting.FakeStepUpAuthorizer{AssertedAt: now.Add(-time.Minute)}
+	err := common.CheckStepUpAuth(auth, stepUpConfig, now)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *stepUpSuite) TestStaleAssertion(c *gc.C) {
+	now := time.Now()
+	auth := &apiservertesting.FakeStepUpAuthorizer{AssertedAt: now.Add(-10 * time.Minute)}
+	err := common.CheckStepUpAuth(auth, stepUpConfig, now)
+	c.Assert(err, gc.ErrorMatches, "second factor required")
+}
+
+func (s *stepUpSuite) TestOperationListed(c *gc.C) {
+	cfg := controller.Config{
+		controller.StepUpAuthMaxAge:     5 * time.Minute,
+		controller.StepUpAuthOperations: []interface{}{controller.StepUpDestroyController},
+		controller.StepUpAuthRPID:       "juju.example.com",
+	}
+	auth := &apiservertesting.FakeStepUpAuthorizer{}
+	err := common.CheckOperationStepUpAuth(auth, cfg, controller.StepUpDestroyController, time.Now())
+	c.Assert(err, gc.ErrorMatches, "second factor required")
+}
+
+func (s *stepUpSuite) TestOperationNotListed(c *gc.C) {
+	auth := &apiservertesting.FakeStepUpAuthorizer{}
+	err := common.CheckOperationStepUpAuth(auth, stepUpConfig, controller.StepUpDestroyController, time.Now())
+	c.Assert(err, jc.ErrorIsNil)
+}
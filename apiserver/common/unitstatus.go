@@ -64,6 +64,11 @@ func canBeLost(agent, workload status.StatusInfo) bool {
 	switch agent.Status {
 	case status.Allocating, status.Running:
 		return false
+	case status.Failed:
+		// The agent has been reported as failed, by itself or by the
+		// deployer that runs it, and the reason given is more useful
+		// than "lost".
+		return false
 	case status.Executing:
 		return agent.Message != operation.RunningHookMessage(string(hooks.Install))
 	}
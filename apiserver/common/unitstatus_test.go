@@ -130,6 +130,15 @@ func (s *UnitStatusSuite) TestPresenceError(c *gc.C) {
 	s.checkUntouched(c)
 }
 
+func (s *UnitStatusSuite) TestNotLostIfFailed(c *gc.C) {
+	s.ctx.Presence = agentDown(s.unit.Tag().String())
+	s.unit.agentStatus = status.StatusInfo{
+		Status:  status.Failed,
+		Message: "agent service not running",
+	}
+	s.checkUntouched(c)
+}
+
 func (s *UnitStatusSuite) TestNotLostIfAllocatingLegacy(c *gc.C) {
 	s.ctx.Presence = nil
 	s.unit.presence = false
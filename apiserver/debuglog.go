@@ -36,13 +36,17 @@ type debugLogHandler struct {
 
 type debugLogHandlerFunc func(
 	clock.Clock,
-	time.Duration,
+	maxDurationFunc,
 	state.LogTailerState,
 	debugLogParams,
 	debugLogSocket,
 	<-chan struct{},
 ) error
 
+// maxDurationFunc returns the maximum duration of a debug-log session,
+// and a channel that is closed when it changes.
+type maxDurationFunc func() (time.Duration, <-chan struct{})
+
 func newDebugLogHandler(
 	ctxt httpContext,
 	authenticator httpcontext.Authenticator,
@@ -116,7 +120,7 @@ func (h *debugLogHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		}
 
 		clock := h.ctxt.srv.clock
-		maxDuration := h.ctxt.srv.shared.maxDebugLogDuration()
+		maxDuration := h.ctxt.srv.shared.maxDebugLogDuration
 
 		if err := h.handle(clock, maxDuration, st, params, socket, h.ctxt.stop()); err != nil {
 			if isBrokenPipe(err) {
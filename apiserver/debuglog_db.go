@@ -5,7 +5,6 @@ package apiserver
 
 import (
 	"net/http"
-	"time"
 
 	"github.com/juju/clock"
 	"github.com/juju/errors"
@@ -25,7 +24,7 @@ func newDebugLogDBHandler(
 
 func handleDebugLogDBRequest(
 	clock clock.Clock,
-	maxDuration time.Duration,
+	maxDuration maxDurationFunc,
 	st state.LogTailerState,
 	reqParams debugLogParams,
 	socket debugLogSocket,
@@ -41,7 +40,11 @@ func handleDebugLogDBRequest(
 	// Indicate that all is well.
 	socket.sendOk()
 
-	timeout := clock.After(maxDuration)
+	// The session ends once the maximum duration has passed since it
+	// started, taking account of changes to the maximum meanwhile.
+	started := clock.Now()
+	duration, changed := maxDuration()
+	timeout := clock.After(duration)
 
 	var lineCount uint
 	for {
@@ -50,6 +53,9 @@ func handleDebugLogDBRequest(
 			return nil
 		case <-timeout:
 			return nil
+		case <-changed:
+			duration, changed = maxDuration()
+			timeout = clock.After(duration - clock.Now().Sub(started))
 		case rec, ok := <-tailer.Logs():
 			if !ok {
 				return errors.Annotate(tailer.Err(), "tailer stopped")
@@ -23,6 +23,7 @@ type debugLogDBIntSuite struct {
 	sock    *fakeDebugLogSocket
 	clock   *testclock.Clock
 	timeout time.Duration
+	changed chan struct{}
 }
 
 var _ = gc.Suite(&debugLogDBIntSuite{})
@@ -32,6 +33,11 @@ func (s *debugLogDBIntSuite) SetUpTest(c *gc.C) {
 	s.sock = newFakeDebugLogSocket()
 	s.clock = testclock.NewClock(time.Now())
 	s.timeout = time.Minute
+	s.changed = make(chan struct{})
+}
+
+func (s *debugLogDBIntSuite) maxDuration() (time.Duration, <-chan struct{}) {
+	return s.timeout, s.changed
 }
 
 func (s *debugLogDBIntSuite) TestParamConversion(c *gc.C) {
@@ -68,7 +74,7 @@ func (s *debugLogDBIntSuite) TestParamConversion(c *gc.C) {
 
 	stop := make(chan struct{})
 	close(stop) // Stop the request immediately.
-	err := handleDebugLogDBRequest(s.clock, s.timeout, nil, reqParams, s.sock, stop)
+	err := handleDebugLogDBRequest(s.clock, s.maxDuration, nil, reqParams, s.sock, stop)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(called, jc.IsTrue)
 }
@@ -91,7 +97,7 @@ func (s *debugLogDBIntSuite) TestParamConversionReplay(c *gc.C) {
 
 	stop := make(chan struct{})
 	close(stop) // Stop the request immediately.
-	err := handleDebugLogDBRequest(s.clock, s.timeout, nil, reqParams, s.sock, stop)
+	err := handleDebugLogDBRequest(s.clock, s.maxDuration, nil, reqParams, s.sock, stop)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(called, jc.IsTrue)
 }
@@ -172,6 +178,27 @@ func (s *debugLogDBIntSuite) TestTimeout(c *gc.C) {
 	s.assertStops(c, done, tailer)
 }
 
+func (s *debugLogDBIntSuite) TestMaxDurationChanged(c *gc.C) {
+	tailer := newFakeLogTailer()
+	s.PatchValue(&newLogTailer, func(_ state.LogTailerState, params state.LogTailerParams) (state.LogTailer, error) {
+		return tailer, nil
+	})
+
+	done := s.runRequest(debugLogParams{}, nil)
+	s.assertOutput(c, []string{"ok"})
+	c.Assert(s.clock.WaitAdvance(30*time.Second, coretesting.LongWait, 1), jc.ErrorIsNil)
+
+	// Shortening the maximum duration applies to the running request.
+	changed := s.changed
+	s.timeout = 45 * time.Second
+	s.changed = make(chan struct{})
+	close(changed)
+	c.Assert(s.clock.WaitAdvance(10*time.Second, coretesting.LongWait, 2), jc.ErrorIsNil)
+	s.assertRunning(c, done, tailer)
+	s.clock.Advance(5 * time.Second)
+	s.assertStops(c, done, tailer)
+}
+
 func (s *debugLogDBIntSuite) TestRequestStopsWhenTailerStops(c *gc.C) {
 	tailer := newFakeLogTailer()
 	s.PatchValue(&newLogTailer, func(_ state.LogTailerState, params state.LogTailerParams) (state.LogTailer, error) {
@@ -179,7 +206,7 @@ func (s *debugLogDBIntSuite) TestRequestStopsWhenTailerStops(c *gc.C) {
 		return tailer, nil
 	})
 
-	err := handleDebugLogDBRequest(s.clock, s.timeout, nil, debugLogParams{}, s.sock, nil)
+	err := handleDebugLogDBRequest(s.clock, s.maxDuration, nil, debugLogParams{}, s.sock, nil)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(tailer.stopped, jc.IsTrue)
 }
@@ -217,7 +244,7 @@ func (s *debugLogDBIntSuite) TestMaxLines(c *gc.C) {
 func (s *debugLogDBIntSuite) runRequest(params debugLogParams, stop chan struct{}) chan error {
 	done := make(chan error)
 	go func() {
-		done <- handleDebugLogDBRequest(s.clock, s.timeout, &fakeState{}, params, s.sock, stop)
+		done <- handleDebugLogDBRequest(s.clock, s.maxDuration, &fakeState{}, params, s.sock, stop)
 	}()
 	return done
 }
@@ -0,0 +1,74 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"github.com/juju/rpcreflect"
+
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/rpc"
+)
+
+// newDeprecationRoot wraps the provided root so that the replies to
+// calls on deprecated facade versions tell the client about the
+// deprecation (see facade.Registry.Deprecate).
+func newDeprecationRoot(root rpc.Root, registry *facade.Registry) *deprecationRoot {
+	return &deprecationRoot{
+		Root:     root,
+		registry: registry,
+	}
+}
+
+type deprecationRoot struct {
+	rpc.Root
+	registry *facade.Registry
+}
+
+// FindMethod implements rpc.Root.
+func (r *deprecationRoot) FindMethod(facadeName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	caller, err := r.Root.FindMethod(facadeName, version, methodName)
+	if err != nil {
+		return nil, err
+	}
+	message, err := r.registry.GetDeprecation(facadeName, version)
+	if err != nil || message == "" {
+		return caller, nil
+	}
+	return &deprecatedCaller{
+		MethodCaller: caller,
+		message:      message,
+	}, nil
+}
+
+// deprecatedCaller implements rpc.DeprecatedMethodCaller.
+type deprecatedCaller struct {
+	rpcreflect.MethodCaller
+	message string
+}
+
+// Deprecation is part of the rpc.DeprecatedMethodCaller interface.
+func (c *deprecatedCaller) Deprecation() string {
+	return c.message
+}
+
+// deprecatedFacades returns the facade versions, of those given, that
+// are deprecated.
+func deprecatedFacades(registry *facade.Registry, facades []params.FacadeVersions) []params.DeprecatedFacade {
+	var out []params.DeprecatedFacade
+	for _, f := range facades {
+		for _, version := range f.Versions {
+			message, err := registry.GetDeprecation(f.Name, version)
+			if err != nil || message == "" {
+				continue
+			}
+			out = append(out, params.DeprecatedFacade{
+				Name:    f.Name,
+				Version: version,
+				Message: message,
+			})
+		}
+	}
+	return out
+}
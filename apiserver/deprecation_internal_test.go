@@ -0,0 +1,66 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/rpc"
+)
+
+type deprecationSuite struct {
+	testing.IsolationSuite
+
+	registry *facade.Registry
+}
+
+var _ = gc.Suite(&deprecationSuite{})
+
+func (s *deprecationSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	newFacade := func(facade.Context) (facade.Facade, error) {
+		return nil, nil
+	}
+	s.registry = &facade.Registry{}
+	for _, version := range []int{1, 2} {
+		err := s.registry.Register("Client", version, newFacade, nil)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	err := s.registry.Deprecate("Client", 1, "use Client v2")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *deprecationSuite) TestDeprecationRoot(c *gc.C) {
+	root := newDeprecationRoot(&fakeRoot{}, s.registry)
+
+	caller, err := root.FindMethod("Client", 1, "FullStatus")
+	c.Assert(err, jc.ErrorIsNil)
+	deprecated, ok := caller.(rpc.DeprecatedMethodCaller)
+	c.Assert(ok, jc.IsTrue)
+	c.Check(deprecated.Deprecation(), gc.Equals, "use Client v2")
+
+	caller, err = root.FindMethod("Client", 2, "FullStatus")
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok = caller.(rpc.DeprecatedMethodCaller)
+	c.Check(ok, jc.IsFalse)
+}
+
+func (s *deprecationSuite) TestDeprecatedFacades(c *gc.C) {
+	deprecated := deprecatedFacades(s.registry, []params.FacadeVersions{
+		{Name: "Client", Versions: []int{1, 2}},
+		{Name: "Unknown", Versions: []int{1}},
+	})
+	c.Check(deprecated, jc.DeepEquals, []params.DeprecatedFacade{
+		{Name: "Client", Version: 1, Message: "use Client v2"},
+	})
+
+	deprecated = deprecatedFacades(s.registry, []params.FacadeVersions{
+		{Name: "Client", Versions: []int{2}},
+	})
+	c.Check(deprecated, gc.HasLen, 0)
+}
@@ -0,0 +1,189 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/rpcreflect"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/rpc"
+)
+
+// DefaultDrainTimeout is how long the API server waits, after being
+// asked to restart, for the API calls it is serving to complete before
+// closing its connections.
+const DefaultDrainTimeout = 30 * time.Second
+
+// drainer refuses new API calls, including logins, once the API server
+// starts draining its connections, and tracks the API calls in flight
+// so that the server can wait for them to complete.
+type drainer struct {
+	clock   clock.Clock
+	timeout time.Duration
+
+	mu       sync.Mutex
+	deadline time.Time
+	draining chan struct{}
+	calls    int
+	idle     chan struct{}
+}
+
+func newDrainer(clock clock.Clock, timeout time.Duration) *drainer {
+	idle := make(chan struct{})
+	close(idle)
+	return &drainer{
+		clock:    clock,
+		timeout:  timeout,
+		draining: make(chan struct{}),
+		idle:     idle,
+	}
+}
+
+// drain starts draining. It is safe to call more than once.
+func (d *drainer) drain() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	select {
+	case <-d.draining:
+		return
+	default:
+	}
+	d.deadline = d.clock.Now().Add(d.timeout)
+	close(d.draining)
+}
+
+// Draining returns a channel that is closed when draining starts.
+func (d *drainer) Draining() <-chan struct{} {
+	return d.draining
+}
+
+// Drained returns a channel that is closed when no tracked API calls
+// are in flight, or when the grace period given to the calls in
+// flight when draining started has passed, whichever is sooner.
+func (d *drainer) Drained() <-chan struct{} {
+	d.mu.Lock()
+	idle := d.idle
+	remaining := d.deadline.Sub(d.clock.Now())
+	d.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		defer close(drained)
+		select {
+		case <-idle:
+		case <-d.clock.After(remaining):
+			logger.Warningf("API calls still in flight after %v, closing connections", d.timeout)
+		}
+	}()
+	return drained
+}
+
+// checkDraining returns a *common.ServerDrainingError if draining
+// has started.
+func (d *drainer) checkDraining() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.drainingError()
+}
+
+// drainingError is the implementation of checkDraining. It must be
+// called with d.mu held.
+func (d *drainer) drainingError() error {
+	select {
+	case <-d.draining:
+	default:
+		return nil
+	}
+	// Hint that the client should come back once this server has
+	// closed its connections and restarted.
+	retryAfter := d.deadline.Sub(d.clock.Now())
+	if retryAfter < 0 {
+		retryAfter = 0
+	}
+	return &common.ServerDrainingError{RetryAfter: retryAfter}
+}
+
+// startCall records the start of an API call, or returns a
+// *common.ServerDrainingError if draining has started.
+func (d *drainer) startCall() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.drainingError(); err != nil {
+		return err
+	}
+	if d.calls == 0 {
+		d.idle = make(chan struct{})
+	}
+	d.calls++
+	return nil
+}
+
+// endCall records the end of an API call started with startCall.
+func (d *drainer) endCall() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.calls--
+	if d.calls == 0 {
+		close(d.idle)
+	}
+}
+
+// newDrainingRoot wraps the provided root so that API calls are
+// refused once the API server starts draining its connections, and so
+// that the server can wait for the calls in flight to complete.
+func newDrainingRoot(root rpc.Root, d *drainer) *drainingRoot {
+	return &drainingRoot{
+		Root:    root,
+		drainer: d,
+	}
+}
+
+type drainingRoot struct {
+	rpc.Root
+	drainer *drainer
+}
+
+// FindMethod implements rpc.Root.
+func (r *drainingRoot) FindMethod(facadeName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	caller, err := r.Root.FindMethod(facadeName, version, methodName)
+	if err != nil {
+		return nil, err
+	}
+	return &drainCaller{
+		MethodCaller: caller,
+		drainer:      r.drainer,
+		untracked:    strings.HasSuffix(facadeName, "Watcher"),
+	}, nil
+}
+
+type drainCaller struct {
+	rpcreflect.MethodCaller
+	drainer   *drainer
+	untracked bool
+}
+
+// Call is part of the rpcreflect.MethodCaller interface.
+func (c *drainCaller) Call(ctx context.Context, objId string, arg reflect.Value) (reflect.Value, error) {
+	if c.untracked {
+		// Watchers block until there are changes to report, and
+		// are stopped when their connections are closed, so the
+		// server doesn't wait for them.
+		if err := c.drainer.checkDraining(); err != nil {
+			return reflect.Value{}, err
+		}
+		return c.MethodCaller.Call(ctx, objId, arg)
+	}
+	if err := c.drainer.startCall(); err != nil {
+		return reflect.Value{}, err
+	}
+	defer c.drainer.endCall()
+	return c.MethodCaller.Call(ctx, objId, arg)
+}
@@ -0,0 +1,156 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/rpcreflect"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/rpc"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type drainSuite struct {
+	testing.IsolationSuite
+
+	clock   *testclock.Clock
+	drainer *drainer
+}
+
+var _ = gc.Suite(&drainSuite{})
+
+func (s *drainSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.clock = testclock.NewClock(time.Now())
+	s.drainer = newDrainer(s.clock, 30*time.Second)
+}
+
+func (s *drainSuite) TestDrainedWhenIdle(c *gc.C) {
+	s.drainer.drain()
+	s.assertDrained(c, s.drainer.Drained())
+}
+
+func (s *drainSuite) TestDrainedWhenCallsComplete(c *gc.C) {
+	c.Assert(s.drainer.startCall(), jc.ErrorIsNil)
+	s.drainer.drain()
+	drained := s.drainer.Drained()
+	s.assertNotDrained(c, drained)
+
+	s.drainer.endCall()
+	s.assertDrained(c, drained)
+}
+
+func (s *drainSuite) TestDrainedAfterTimeout(c *gc.C) {
+	c.Assert(s.drainer.startCall(), jc.ErrorIsNil)
+	s.drainer.drain()
+	drained := s.drainer.Drained()
+	s.assertNotDrained(c, drained)
+
+	c.Assert(s.clock.WaitAdvance(30*time.Second, coretesting.LongWait, 1), jc.ErrorIsNil)
+	s.assertDrained(c, drained)
+}
+
+func (s *drainSuite) TestCallsRefusedWhileDraining(c *gc.C) {
+	c.Assert(s.drainer.startCall(), jc.ErrorIsNil)
+	s.drainer.endCall()
+
+	s.drainer.drain()
+	s.clock.Advance(10 * time.Second)
+	err := s.drainer.startCall()
+	c.Assert(err, jc.Satisfies, common.IsServerDrainingError)
+	c.Assert(err.(*common.ServerDrainingError).RetryAfter, gc.Equals, 20*time.Second)
+}
+
+func (s *drainSuite) TestDrainingRoot(c *gc.C) {
+	root := newDrainingRoot(&fakeRoot{}, s.drainer)
+
+	caller, err := root.FindMethod("Client", 2, "FullStatus")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = caller.Call(context.Background(), "", reflect.Value{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.drainer.drain()
+	_, err = caller.Call(context.Background(), "", reflect.Value{})
+	c.Assert(err, jc.Satisfies, common.IsServerDrainingError)
+
+	caller, err = root.FindMethod("NotifyWatcher", 1, "Next")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = caller.Call(context.Background(), "", reflect.Value{})
+	c.Assert(err, jc.Satisfies, common.IsServerDrainingError)
+}
+
+func (s *drainSuite) TestDrainingRootIgnoresWatchers(c *gc.C) {
+	calling := make(chan struct{})
+	release := make(chan struct{})
+	root := newDrainingRoot(&fakeRoot{calling: calling, release: release}, s.drainer)
+	caller, err := root.FindMethod("NotifyWatcher", 1, "Next")
+	c.Assert(err, jc.ErrorIsNil)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		caller.Call(context.Background(), "", reflect.Value{})
+	}()
+	<-calling
+
+	// The watcher's call doesn't hold up draining.
+	s.drainer.drain()
+	s.assertDrained(c, s.drainer.Drained())
+	close(release)
+	<-done
+}
+
+func (s *drainSuite) assertDrained(c *gc.C, drained <-chan struct{}) {
+	select {
+	case <-drained:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting to be drained")
+	}
+}
+
+func (s *drainSuite) assertNotDrained(c *gc.C, drained <-chan struct{}) {
+	select {
+	case <-drained:
+		c.Fatalf("drained unexpectedly")
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+type fakeRoot struct {
+	rpc.Root
+	calling chan struct{}
+	release chan struct{}
+}
+
+func (r *fakeRoot) FindMethod(string, int, string) (rpcreflect.MethodCaller, error) {
+	return fakeCaller{r}, nil
+}
+
+type fakeCaller struct {
+	root *fakeRoot
+}
+
+func (fakeCaller) ParamsType() reflect.Type {
+	return nil
+}
+
+func (fakeCaller) ResultType() reflect.Type {
+	return nil
+}
+
+func (c fakeCaller) Call(context.Context, string, reflect.Value) (reflect.Value, error) {
+	if c.root.calling != nil {
+		close(c.root.calling)
+		<-c.root.release
+	}
+	return reflect.Value{}, nil
+}
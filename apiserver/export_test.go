@@ -108,6 +108,13 @@ func TestingControllerOnlyRoot() rpc.Root {
 	return restrictRoot(r, controllerFacadesOnly)
 }
 
+// TestingAPIKeyRoot returns a restricted srvRoot as if logged in
+// using an API key.
+func TestingAPIKeyRoot() rpc.Root {
+	r := TestingAPIRoot(AllFacades())
+	return restrictRoot(r, apiKeyFacadesOnly)
+}
+
 // TestingModelOnlyRoot returns a restricted srvRoot as if
 // logged in to a model.
 func TestingModelOnlyRoot() rpc.Root {
@@ -23,12 +23,14 @@ type Context struct {
 	StatePool_  *state.StatePool
 	Controller_ *cache.Controller
 	ID_         string
+	Features_   []string
 
 	LeadershipClaimer_ leadership.Claimer
 	LeadershipChecker_ leadership.Checker
 	LeadershipPinner_  leadership.Pinner
 	LeadershipReader_  leadership.Reader
 	SingularClaimer_   lease.Claimer
+	OperatorClaimer_   lease.Claimer
 	// Identity is not part of the facade.Context interface, but is instead
 	// used to make sure that the context objects are the same.
 	Identity string
@@ -74,6 +76,16 @@ func (context Context) StatePool() *state.StatePool {
 	return context.StatePool_
 }
 
+// FeatureEnabled is part of the facade.Context interface.
+func (context Context) FeatureEnabled(flag string) bool {
+	for _, feature := range context.Features_ {
+		if feature == flag {
+			return true
+		}
+	}
+	return false
+}
+
 // ID is part of the facade.Context interface.
 func (context Context) ID() string {
 	return context.ID_
@@ -114,3 +126,8 @@ func (context Context) LeadershipReader(modelUUID string) (leadership.Reader, er
 func (context Context) SingularClaimer() (lease.Claimer, error) {
 	return context.SingularClaimer_, nil
 }
+
+// OperatorClaimer implements facade.Context.
+func (context Context) OperatorClaimer() (lease.Claimer, error) {
+	return context.OperatorClaimer_, nil
+}
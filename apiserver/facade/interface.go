@@ -4,6 +4,8 @@
 package facade
 
 import (
+	"time"
+
 	"gopkg.in/juju/names.v3"
 
 	"github.com/juju/juju/core/cache"
@@ -86,6 +88,11 @@ type Context interface {
 	// At least at this stage, facades only need to publish events.
 	Hub() Hub
 
+	// FeatureEnabled reports whether the feature flag is enabled for
+	// this context's model, either for the whole controller or for
+	// the model alone.
+	FeatureEnabled(flag string) bool
+
 	// ID returns a string that should almost always be "", unless
 	// this is a watcher facade, in which case it exists in lieu of
 	// actual arguments in the Next() call, and is used as a key
@@ -112,6 +119,11 @@ type Context interface {
 	// SingularClaimer returns a lease.Claimer for singular leases for
 	// this context's model.
 	SingularClaimer() (lease.Claimer, error)
+
+	// OperatorClaimer returns a lease.Claimer for the leases that
+	// determine which pod runs each CAAS application operator in
+	// this context's model.
+	OperatorClaimer() (lease.Claimer, error)
 }
 
 //go:generate mockgen -package mocks -destination mocks/facade_mock.go github.com/juju/juju/apiserver/facade Resources,Authorizer
@@ -160,6 +172,29 @@ type Authorizer interface {
 	ConnectedModel() string
 }
 
+// StepUpSession is implemented by the Authorizers of API connections
+// on which a client can assert a second authentication factor, as
+// required by the controller before destructive operations.
+type StepUpSession interface {
+
+	// SecondFactorChallenge returns the challenge most recently issued
+	// on the connection for a second-factor assertion, if any.
+	SecondFactorChallenge() string
+
+	// SetSecondFactorChallenge records the challenge issued on the
+	// connection; an empty challenge clears it.
+	SetSecondFactorChallenge(challenge string)
+
+	// SecondFactorAssertedAt returns the time at which a second factor
+	// was last asserted on the connection, or the zero time if none
+	// has been.
+	SecondFactorAssertedAt() time.Time
+
+	// SetSecondFactorAssertedAt records the time at which a second
+	// factor was asserted on the connection.
+	SetSecondFactorAssertedAt(time.Time)
+}
+
 // Resources allows you to store and retrieve Resource implementations.
 //
 // The lack of error returns are in deference to the existing
@@ -18,6 +18,14 @@ import (
 type record struct {
 	factory    Factory
 	facadeType reflect.Type
+
+	// feature, if set, is the feature flag a model must have enabled
+	// to use the facade.
+	feature string
+
+	// deprecation, if set, explains why the facade version is
+	// deprecated and is reported to clients that use it.
+	deprecation string
 }
 
 // versions is our internal structure for tracking specific versions of a
@@ -45,18 +53,63 @@ func (f *Registry) RegisterStandard(name string, version int, newFunc interface{
 	return nil
 }
 
+// RegisterCanary registers a facade like RegisterStandard, but only
+// makes it available to models that have the given feature flag
+// enabled, either for the whole controller or for the model alone.
+// This allows a new version of a facade to be rolled out to a few
+// models on a shared controller before it is made available to all.
+// Once it is considered stable, it should be registered with
+// RegisterStandard instead.
+func (f *Registry) RegisterCanary(name string, version int, feature string, newFunc interface{}) error {
+	if feature == "" {
+		return errors.NotValidf("empty feature flag for %s(%d)", name, version)
+	}
+	wrapped, facadeType, err := wrapNewFacade(newFunc)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = f.register(name, version, record{
+		factory:    wrapped,
+		facadeType: facadeType,
+		feature:    feature,
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// Deprecate marks an already registered facade version as deprecated,
+// so that clients are warned that it will be removed in the next major
+// release. The message should tell clients what to use instead.
+func (f *Registry) Deprecate(name string, version int, message string) error {
+	if message == "" {
+		return errors.NotValidf("empty deprecation message for %s(%d)", name, version)
+	}
+	record, err := f.lookup(name, version)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	record.deprecation = message
+	f.facades[name][version] = record
+	return nil
+}
+
 // Register adds a single named facade at a given version to the registry.
 // Factory will be called when someone wants to instantiate an object of
 // this facade, and facadeType defines the concrete type that the returned object will be.
 // The Type information is used to define what methods will be exported in the
 // API, and it must exactly match the actual object returned by the factory.
 func (f *Registry) Register(name string, version int, factory Factory, facadeType reflect.Type) error {
-	if f.facades == nil {
-		f.facades = make(map[string]versions, 1)
-	}
-	record := record{
+	return f.register(name, version, record{
 		factory:    factory,
 		facadeType: facadeType,
+	})
+}
+
+func (f *Registry) register(name string, version int, record record) error {
+	if f.facades == nil {
+		f.facades = make(map[string]versions, 1)
 	}
 	if vers, ok := f.facades[name]; ok {
 		if _, ok := vers[version]; ok {
@@ -101,6 +154,28 @@ func (f *Registry) GetType(name string, version int) (reflect.Type, error) {
 	return record.facadeType, nil
 }
 
+// GetFeature returns the feature flag a model must have enabled to use
+// the given Facade name and version. It returns the empty string if the
+// facade is available to all models.
+func (f *Registry) GetFeature(name string, version int) (string, error) {
+	record, err := f.lookup(name, version)
+	if err != nil {
+		return "", err
+	}
+	return record.feature, nil
+}
+
+// GetDeprecation returns the message explaining why the given Facade
+// name and version is deprecated. It returns the empty string if the
+// facade version is not deprecated.
+func (f *Registry) GetDeprecation(name string, version int) (string, error) {
+	record, err := f.lookup(name, version)
+	if err != nil {
+		return "", err
+	}
+	return record.deprecation, nil
+}
+
 // Description describes the name and what versions of a facade have been
 // registered.
 type Description struct {
@@ -154,6 +229,12 @@ type Details struct {
 	// details of the facade without actually creating
 	// a facade instance (see rpcreflect.ObjTypeOf).
 	Type reflect.Type
+	// Feature holds the feature flag a model must have
+	// enabled to use the facade, if any (see RegisterCanary).
+	Feature string
+	// Deprecation holds the reason the facade version is
+	// deprecated, if it is (see Deprecate).
+	Deprecation string
 }
 
 // ListDetails returns information about all the facades
@@ -168,10 +249,12 @@ func (f *Registry) ListDetails() []Details {
 	for _, name := range names {
 		for v, info := range f.facades[name] {
 			details = append(details, Details{
-				Name:    name,
-				Version: v,
-				Factory: info.factory,
-				Type:    info.facadeType,
+				Name:        name,
+				Version:     v,
+				Factory:     info.factory,
+				Type:        info.facadeType,
+				Feature:     info.feature,
+				Deprecation: info.deprecation,
 			})
 		}
 	}
@@ -261,6 +261,80 @@ func (s *RegistrySuite) TestRegisterStandardError(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `badtest\(0\) not found`)
 }
 
+func (s *RegistrySuite) TestRegisterCanary(c *gc.C) {
+	registry := &facade.Registry{}
+	err := registry.RegisterStandard("testing", 1, validFactory)
+	c.Assert(err, jc.ErrorIsNil)
+	err = registry.RegisterCanary("testing", 2, "new-testing", validContextFactory)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(registry.List(), jc.DeepEquals, []facade.Description{
+		{Name: "testing", Versions: []int{1, 2}},
+	})
+	feature, err := registry.GetFeature("testing", 1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(feature, gc.Equals, "")
+	feature, err = registry.GetFeature("testing", 2)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(feature, gc.Equals, "new-testing")
+	_, err = registry.GetFeature("testing", 3)
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+
+	wrapped, err := registry.GetFactory("testing", 2)
+	c.Assert(err, jc.ErrorIsNil)
+	val, err := wrapped(facadetest.Context{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(*(val.(*int)), gc.Equals, 100)
+
+	for _, details := range registry.ListDetails() {
+		if details.Version == 2 {
+			c.Check(details.Feature, gc.Equals, "new-testing")
+		} else {
+			c.Check(details.Feature, gc.Equals, "")
+		}
+	}
+}
+
+func (s *RegistrySuite) TestRegisterCanaryNoFeature(c *gc.C) {
+	registry := &facade.Registry{}
+	err := registry.RegisterCanary("testing", 2, "", validContextFactory)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err, gc.ErrorMatches, `empty feature flag for testing\(2\) not valid`)
+}
+
+func (s *RegistrySuite) TestDeprecate(c *gc.C) {
+	registry := &facade.Registry{}
+	assertRegister(c, registry, "testing", 1)
+	assertRegister(c, registry, "testing", 2)
+	err := registry.Deprecate("testing", 1, "use testing v2")
+	c.Assert(err, jc.ErrorIsNil)
+
+	message, err := registry.GetDeprecation("testing", 1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(message, gc.Equals, "use testing v2")
+	message, err = registry.GetDeprecation("testing", 2)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(message, gc.Equals, "")
+
+	for _, details := range registry.ListDetails() {
+		if details.Version == 1 {
+			c.Check(details.Deprecation, gc.Equals, "use testing v2")
+		} else {
+			c.Check(details.Deprecation, gc.Equals, "")
+		}
+	}
+}
+
+func (s *RegistrySuite) TestDeprecateNotRegistered(c *gc.C) {
+	registry := &facade.Registry{}
+	assertRegister(c, registry, "testing", 1)
+	err := registry.Deprecate("testing", 2, "gone")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = registry.Deprecate("testing", 1, "")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err, gc.ErrorMatches, `empty deprecation message for testing\(1\) not valid`)
+}
+
 func assertRegister(c *gc.C, registry *facade.Registry, name string, version int) {
 	assertRegisterFlag(c, registry, name, version)
 }
@@ -0,0 +1,93 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasoperator
+
+import (
+	"context"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// ClaimOperator makes the supplied operator lease claims, so that only
+// one of the pods running an application's operator is active at a time.
+// Claims may only be made for the connected application.
+func (f *Facade) ClaimOperator(args params.OperatorClaims) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Claims)),
+	}
+	for i, claim := range args.Claims {
+		err := f.claimOperator(claim)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (f *Facade) claimOperator(claim params.OperatorClaim) error {
+	if !allowedDuration(claim.Duration) {
+		return common.ErrPerm
+	}
+	tag, err := f.authApplication(claim.ApplicationTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if f.claimer == nil {
+		return errors.NotSupportedf("operator claims")
+	}
+	return f.claimer.Claim(tag.Id(), claim.Holder, claim.Duration)
+}
+
+// WaitOperatorReleased waits for the operator lease of each of the
+// supplied applications to expire.
+func (f *Facade) WaitOperatorReleased(ctx context.Context, args params.Entities) params.ErrorResults {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		err := f.waitOperatorReleased(ctx, entity.Tag)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results
+}
+
+func (f *Facade) waitOperatorReleased(ctx context.Context, tagString string) error {
+	tag, err := f.authApplication(tagString)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if f.claimer == nil {
+		return errors.NotSupportedf("operator claims")
+	}
+	return f.claimer.WaitUntilExpired(tag.Id(), ctx.Done())
+}
+
+func (f *Facade) authApplication(tagString string) (names.ApplicationTag, error) {
+	tag, err := names.ParseApplicationTag(tagString)
+	if err != nil {
+		return names.ApplicationTag{}, errors.Trace(err)
+	}
+	if tag != f.auth.GetAuthTag() {
+		return names.ApplicationTag{}, common.ErrPerm
+	}
+	return tag, nil
+}
+
+// allowedDuration returns true if the supplied duration is at least one
+// second, and no more than one minute.
+func allowedDuration(duration time.Duration) bool {
+	if duration < time.Second {
+		return false
+	}
+	return duration <= time.Minute
+}
+
+// ClaimOperator isn't on the v1 API.
+func (f *FacadeV1) ClaimOperator(_, _ struct{}) {}
+
+// WaitOperatorReleased isn't on the v1 API.
+func (f *FacadeV1) WaitOperatorReleased(_, _ struct{}) {}
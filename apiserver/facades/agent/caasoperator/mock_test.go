@@ -4,6 +4,8 @@
 package caasoperator_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/testing"
 	"github.com/juju/version"
@@ -223,3 +225,17 @@ func (ch *mockCharm) URL() *charm.URL {
 func (ch *mockCharm) BundleSha256() string {
 	return ch.sha256
 }
+
+type mockClaimer struct {
+	testing.Stub
+}
+
+func (c *mockClaimer) Claim(leaseName, holderName string, duration time.Duration) error {
+	c.MethodCall(c, "Claim", leaseName, holderName, duration)
+	return c.NextErr()
+}
+
+func (c *mockClaimer) WaitUntilExpired(leaseName string, cancel <-chan struct{}) error {
+	c.MethodCall(c, "WaitUntilExpired", leaseName)
+	return c.NextErr()
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
 	k8sspecs "github.com/juju/juju/caas/kubernetes/provider/specs"
+	"github.com/juju/juju/core/lease"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/state/watcher"
 )
@@ -25,14 +26,39 @@ type Facade struct {
 	*common.ToolsSetter
 	*common.APIAddresser
 
-	model Model
+	model   Model
+	claimer lease.Claimer
+}
+
+// FacadeV1 is the V1 CAASOperator facade, which doesn't support
+// operator claims.
+type FacadeV1 struct {
+	*Facade
+}
+
+// NewStateFacadeV1 provides the signature required for facade
+// registration of the V1 facade.
+func NewStateFacadeV1(ctx facade.Context) (*FacadeV1, error) {
+	f, err := NewStateFacade(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &FacadeV1{f}, nil
 }
 
 // NewStateFacade provides the signature required for facade registration.
 func NewStateFacade(ctx facade.Context) (*Facade, error) {
 	authorizer := ctx.Auth()
 	resources := ctx.Resources()
-	return NewFacade(resources, authorizer, stateShim{ctx.State()})
+	claimer, err := ctx.OperatorClaimer()
+	if errors.IsNotSupported(err) {
+		// Claims will be refused; the operator agent falls
+		// back to running a single operator.
+		claimer = nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return NewFacade(resources, authorizer, stateShim{ctx.State()}, claimer)
 }
 
 // NewFacade returns a new CAASOperator facade.
@@ -40,6 +66,7 @@ func NewFacade(
 	resources facade.Resources,
 	authorizer facade.Authorizer,
 	st CAASOperatorState,
+	claimer lease.Claimer,
 ) (*Facade, error) {
 	if !authorizer.AuthApplicationAgent() {
 		return nil, common.ErrPerm
@@ -87,6 +114,7 @@ func NewFacade(
 		resources:          resources,
 		state:              st,
 		model:              model,
+		claimer:            claimer,
 	}, nil
 }
 
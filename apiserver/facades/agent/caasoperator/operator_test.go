@@ -4,7 +4,11 @@
 package caasoperator_test
 
 import (
+	"context"
+	"time"
+
 	"github.com/juju/errors"
+	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
@@ -15,6 +19,7 @@ import (
 	"github.com/juju/juju/apiserver/facades/agent/caasoperator"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/core/lease"
 	"github.com/juju/juju/core/status"
 	coretesting "github.com/juju/juju/testing"
 )
@@ -28,6 +33,7 @@ type CAASOperatorSuite struct {
 	authorizer *apiservertesting.FakeAuthorizer
 	facade     *caasoperator.Facade
 	st         *mockState
+	claimer    *mockClaimer
 }
 
 func (s *CAASOperatorSuite) SetUpTest(c *gc.C) {
@@ -41,11 +47,12 @@ func (s *CAASOperatorSuite) SetUpTest(c *gc.C) {
 	}
 
 	s.st = newMockState()
+	s.claimer = &mockClaimer{}
 	s.AddCleanup(func(c *gc.C) {
 		workertest.CleanKill(c, s.st.app.unitsWatcher)
 	})
 
-	facade, err := caasoperator.NewFacade(s.resources, s.authorizer, s.st)
+	facade, err := caasoperator.NewFacade(s.resources, s.authorizer, s.st, s.claimer)
 	c.Assert(err, jc.ErrorIsNil)
 	s.facade = facade
 }
@@ -54,7 +61,7 @@ func (s *CAASOperatorSuite) TestPermission(c *gc.C) {
 	s.authorizer = &apiservertesting.FakeAuthorizer{
 		Tag: names.NewMachineTag("0"),
 	}
-	_, err := caasoperator.NewFacade(s.resources, s.authorizer, s.st)
+	_, err := caasoperator.NewFacade(s.resources, s.authorizer, s.st, s.claimer)
 	c.Assert(err, gc.ErrorMatches, "permission denied")
 }
 
@@ -332,3 +339,52 @@ func (s *CAASOperatorSuite) TestWatchAPIHostPorts(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	s.st.CheckCallNames(c, "Model", "WatchAPIHostPortsForAgents")
 }
+
+func (s *CAASOperatorSuite) TestClaimOperator(c *gc.C) {
+	s.claimer.SetErrors(nil, lease.ErrClaimDenied)
+	results, err := s.facade.ClaimOperator(params.OperatorClaims{
+		Claims: []params.OperatorClaim{
+			{ApplicationTag: "application-gitlab", Holder: "gitlab-operator-0", Duration: time.Minute},
+			{ApplicationTag: "application-gitlab", Holder: "gitlab-operator-1", Duration: time.Minute},
+			{ApplicationTag: "application-mysql", Holder: "mysql-operator-0", Duration: time.Minute},
+			{ApplicationTag: "application-gitlab", Holder: "gitlab-operator-0", Duration: time.Hour},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 4)
+	c.Check(results.Results[0].Error, gc.IsNil)
+	c.Check(results.Results[1].Error, jc.Satisfies, params.IsCodeLeaseClaimDenied)
+	c.Check(results.Results[2].Error, jc.Satisfies, params.IsCodeUnauthorized)
+	c.Check(results.Results[3].Error, jc.Satisfies, params.IsCodeUnauthorized)
+	s.claimer.CheckCalls(c, []testing.StubCall{
+		{"Claim", []interface{}{"gitlab", "gitlab-operator-0", time.Minute}},
+		{"Claim", []interface{}{"gitlab", "gitlab-operator-1", time.Minute}},
+	})
+}
+
+func (s *CAASOperatorSuite) TestClaimOperatorNotSupported(c *gc.C) {
+	facade, err := caasoperator.NewFacade(s.resources, s.authorizer, s.st, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := facade.ClaimOperator(params.OperatorClaims{
+		Claims: []params.OperatorClaim{
+			{ApplicationTag: "application-gitlab", Holder: "gitlab-operator-0", Duration: time.Minute},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Check(results.Results[0].Error, jc.Satisfies, params.IsCodeNotSupported)
+}
+
+func (s *CAASOperatorSuite) TestWaitOperatorReleased(c *gc.C) {
+	results := s.facade.WaitOperatorReleased(context.Background(), params.Entities{
+		Entities: []params.Entity{
+			{Tag: "application-gitlab"},
+			{Tag: "application-mysql"},
+		},
+	})
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Check(results.Results[0].Error, gc.IsNil)
+	c.Check(results.Results[1].Error, jc.Satisfies, params.IsCodeUnauthorized)
+	s.claimer.CheckCallNames(c, "WaitUntilExpired")
+	s.claimer.CheckCall(c, 0, "WaitUntilExpired", "gitlab")
+}
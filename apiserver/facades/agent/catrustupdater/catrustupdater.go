@@ -0,0 +1,78 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package catrustupdater
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+)
+
+// CATrustUpdater defines the methods on the CATrustUpdater API endpoint.
+type CATrustUpdater interface {
+	WatchForCATrustBundleChanges() (params.NotifyWatchResult, error)
+	CATrustBundle() (params.StringResult, error)
+}
+
+// CATrustUpdaterAPI gives agents access to the CA trust bundle of
+// their model.
+type CATrustUpdaterAPI struct {
+	model     state.ModelAccessor
+	resources facade.Resources
+}
+
+var _ CATrustUpdater = (*CATrustUpdaterAPI)(nil)
+
+// NewCATrustUpdaterAPI creates a new CATrustUpdater API endpoint on
+// server-side.
+func NewCATrustUpdaterAPI(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*CATrustUpdaterAPI, error) {
+	model, err := st.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return NewCATrustUpdaterAPIForModel(model, resources, authorizer)
+}
+
+// NewCATrustUpdaterAPIForModel creates a new CATrustUpdater API endpoint
+// for the given model.
+func NewCATrustUpdaterAPIForModel(model state.ModelAccessor, resources facade.Resources, authorizer facade.Authorizer) (*CATrustUpdaterAPI, error) {
+	if !authorizer.AuthMachineAgent() && !authorizer.AuthUnitAgent() {
+		return nil, common.ErrPerm
+	}
+	return &CATrustUpdaterAPI{
+		model:     model,
+		resources: resources,
+	}, nil
+}
+
+// WatchForCATrustBundleChanges returns a NotifyWatcher that observes
+// changes to the model config, and so to the CA trust bundle.
+func (api *CATrustUpdaterAPI) WatchForCATrustBundleChanges() (params.NotifyWatchResult, error) {
+	result := params.NotifyWatchResult{}
+	watch := api.model.WatchForModelConfigChanges()
+	// Consume the initial event. Technically, API
+	// calls to Watch 'transmit' the initial event
+	// in the Watch response. But NotifyWatchers
+	// have no state to transmit.
+	if _, ok := <-watch.Changes(); ok {
+		result.NotifyWatcherId = api.resources.Register(watch)
+	} else {
+		return result, watcher.EnsureErr(watch)
+	}
+	return result, nil
+}
+
+// CATrustBundle returns the PEM encoded CA certificates that agents
+// should add to the trust store of the machines they run on.
+func (api *CATrustUpdaterAPI) CATrustBundle() (params.StringResult, error) {
+	config, err := api.model.ModelConfig()
+	if err != nil {
+		return params.StringResult{}, errors.Trace(err)
+	}
+	return params.StringResult{Result: config.CATrustBundle()}, nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package catrustupdater_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facades/agent/catrustupdater"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing"
+)
+
+type catrustupdaterSuite struct {
+	testing.BaseSuite
+	resources *common.Resources
+}
+
+var _ = gc.Suite(&catrustupdaterSuite{})
+
+type fakeModelAccessor struct {
+	modelConfig      *config.Config
+	modelConfigError error
+}
+
+func (*fakeModelAccessor) WatchForModelConfigChanges() state.NotifyWatcher {
+	return apiservertesting.NewFakeNotifyWatcher()
+}
+
+func (f *fakeModelAccessor) ModelConfig() (*config.Config, error) {
+	if f.modelConfigError != nil {
+		return nil, f.modelConfigError
+	}
+	return f.modelConfig, nil
+}
+
+func (s *catrustupdaterSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.resources = common.NewResources()
+	s.AddCleanup(func(_ *gc.C) { s.resources.StopAll() })
+}
+
+func (s *catrustupdaterSuite) newAPI(c *gc.C, model state.ModelAccessor) *catrustupdater.CATrustUpdaterAPI {
+	api, err := catrustupdater.NewCATrustUpdaterAPIForModel(
+		model,
+		s.resources,
+		apiservertesting.FakeAuthorizer{Tag: names.NewMachineTag("0")},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *catrustupdaterSuite) TestAuth(c *gc.C) {
+	for _, tag := range []names.Tag{
+		names.NewMachineTag("0"),
+		names.NewUnitTag("mysql/0"),
+	} {
+		_, err := catrustupdater.NewCATrustUpdaterAPIForModel(
+			&fakeModelAccessor{},
+			s.resources,
+			apiservertesting.FakeAuthorizer{Tag: tag},
+		)
+		c.Check(err, jc.ErrorIsNil)
+	}
+	_, err := catrustupdater.NewCATrustUpdaterAPIForModel(
+		&fakeModelAccessor{},
+		s.resources,
+		apiservertesting.FakeAuthorizer{Tag: names.NewUserTag("vito")},
+	)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *catrustupdaterSuite) TestWatch(c *gc.C) {
+	api := s.newAPI(c, &fakeModelAccessor{})
+	result, err := api.WatchForCATrustBundleChanges()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.NotifyWatchResult{NotifyWatcherId: "1"})
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+}
+
+func (s *catrustupdaterSuite) TestCATrustBundle(c *gc.C) {
+	cfg := testing.CustomModelConfig(c, testing.Attrs{
+		"ca-trust-bundle": testing.CACert,
+	})
+	api := s.newAPI(c, &fakeModelAccessor{modelConfig: cfg})
+	result, err := api.CATrustBundle()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.StringResult{Result: testing.CACert})
+}
+
+func (s *catrustupdaterSuite) TestCATrustBundleError(c *gc.C) {
+	api := s.newAPI(c, &fakeModelAccessor{modelConfigError: errors.New("pow")})
+	_, err := api.CATrustBundle()
+	c.Assert(err, gc.ErrorMatches, "pow")
+}
@@ -23,9 +23,30 @@ type DeployerAPI struct {
 	*common.UnitsWatcher
 	*common.StatusSetter
 
-	st         *state.State
-	resources  facade.Resources
-	authorizer facade.Authorizer
+	st          *state.State
+	resources   facade.Resources
+	authorizer  facade.Authorizer
+	agentSetter *common.StatusSetter
+	getCanRead  common.GetAuthFunc
+}
+
+// DeployerAPIV1 implements the V1 Deployer API, which lacks
+// SetAgentStatus and PasswordRotationRequested.
+type DeployerAPIV1 struct {
+	*DeployerAPI
+}
+
+// NewDeployerAPIV1 creates a new server-side V1 DeployerAPI facade.
+func NewDeployerAPIV1(
+	st *state.State,
+	resources facade.Resources,
+	authorizer facade.Authorizer,
+) (*DeployerAPIV1, error) {
+	api, err := NewDeployerAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &DeployerAPIV1{api}, nil
 }
 
 // NewDeployerAPI creates a new server-side DeployerAPI facade.
@@ -69,6 +90,8 @@ func NewDeployerAPI(
 		st:              st,
 		resources:       resources,
 		authorizer:      authorizer,
+		agentSetter:     common.NewStatusSetter(&common.UnitAgentFinder{st}, getAuthFunc),
+		getCanRead:      getAuthFunc,
 	}, nil
 }
 
@@ -90,6 +113,46 @@ func (d *DeployerAPI) SetStatus(args params.SetStatus) (params.ErrorResults, err
 	return d.StatusSetter.SetStatus(args)
 }
 
+// SetAgentStatus sets the agent status of the specified units. The
+// deployer uses it to report agent services that have failed, which
+// would otherwise only show as lost.
+func (d *DeployerAPI) SetAgentStatus(args params.SetStatus) (params.ErrorResults, error) {
+	return d.agentSetter.SetStatus(args)
+}
+
+// SetAgentStatus isn't on the V1 API.
+func (*DeployerAPIV1) SetAgentStatus(_, _ struct{}) {}
+
+// PasswordRotationRequested reports, for each of the specified units,
+// whether its agent is waiting to be given a new password. The deployer
+// rotates the password with SetPasswords, which clears the request.
+func (d *DeployerAPI) PasswordRotationRequested(args params.Entities) (params.BoolResults, error) {
+	result := params.BoolResults{
+		Results: make([]params.BoolResult, len(args.Entities)),
+	}
+	canRead, err := d.getCanRead()
+	if err != nil {
+		return result, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil || !canRead(tag) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		unit, err := d.st.Unit(tag.Id())
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Result = unit.PasswordRotationRequested()
+	}
+	return result, nil
+}
+
+// PasswordRotationRequested isn't on the V1 API.
+func (*DeployerAPIV1) PasswordRotationRequested(_, _ struct{}) {}
+
 // getAllUnits returns a list of all principal and subordinate units
 // assigned to the given machine.
 func getAllUnits(st *state.State, tag names.Tag) ([]string, error) {
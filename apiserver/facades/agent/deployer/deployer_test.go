@@ -346,3 +346,66 @@ func (s *deployerSuite) TestSetStatus(c *gc.C) {
 		Data:    map[string]interface{}{"foo": "bar"},
 	})
 }
+
+func (s *deployerSuite) TestSetAgentStatus(c *gc.C) {
+	args := params.SetStatus{
+		Entities: []params.EntityStatusArgs{
+			{Tag: "unit-mysql-0", Status: "failed", Info: "agent service not running"},
+			{Tag: "unit-mysql-1", Status: "failed", Info: "agent service not running"},
+			{Tag: "unit-fake-42", Status: "failed", Info: "agent service not running"},
+		},
+	}
+	results, err := s.deployer.SetAgentStatus(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+	sInfo, err := s.principal0.AgentStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sInfo.Status, gc.Equals, status.Failed)
+	c.Assert(sInfo.Message, gc.Equals, "agent service not running")
+}
+
+func (s *deployerSuite) TestPasswordRotationRequested(c *gc.C) {
+	err := s.subordinate0.RequestPasswordRotation()
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-mysql-1"},
+		{Tag: "unit-logging-0"},
+		{Tag: "unit-fake-42"},
+		{Tag: "machine-1"},
+	}}
+	result, err := s.deployer.PasswordRotationRequested(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.BoolResults{
+		Results: []params.BoolResult{
+			{Result: false},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Result: true},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	// Setting the password clears the request.
+	results, err := s.deployer.SetPasswords(params.EntityPasswords{
+		Changes: []params.EntityPassword{
+			{Tag: "unit-logging-0", Password: "xxx-12345678901234567890"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), jc.ErrorIsNil)
+	result, err = s.deployer.PasswordRotationRequested(params.Entities{
+		Entities: []params.Entity{{Tag: "unit-logging-0"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.BoolResults{
+		Results: []params.BoolResult{{Result: false}},
+	})
+}
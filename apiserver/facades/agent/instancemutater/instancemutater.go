@@ -46,6 +46,19 @@ type InstanceMutaterV2 interface {
 	WatchLXDProfileVerificationNeeded(args params.Entities) (params.NotifyWatchResults, error)
 }
 
+// InstanceMutaterV3 defines the methods on the instance mutater API facade, version 3.
+type InstanceMutaterV3 interface {
+	Life(args params.Entities) (params.LifeResults, error)
+
+	CharmProfilingInfo(arg params.Entity) (params.CharmProfilingInfoResult, error)
+	ContainerType(arg params.Entity) (params.ContainerTypeResult, error)
+	SetCharmProfiles(args params.SetProfileArgs) (params.ErrorResults, error)
+	SetModificationStatus(args params.SetStatus) (params.ErrorResults, error)
+	WatchMachines() (params.StringsWatchResult, error)
+	WatchLXDProfileMachines() (params.StringsWatchResult, error)
+	WatchLXDProfileVerificationNeeded(args params.Entities) (params.NotifyWatchResults, error)
+}
+
 type InstanceMutaterAPI struct {
 	*common.LifeGetter
 
@@ -56,18 +69,23 @@ type InstanceMutaterAPI struct {
 	getAuthFunc common.GetAuthFunc
 }
 
-type InstanceMutaterAPIV1 struct {
+type InstanceMutaterAPIV2 struct {
 	*InstanceMutaterAPI
 }
 
+type InstanceMutaterAPIV1 struct {
+	*InstanceMutaterAPIV2
+}
+
 // using apiserver/facades/client/cloud as an example.
 var (
-	_ InstanceMutaterV2 = (*InstanceMutaterAPI)(nil)
+	_ InstanceMutaterV3 = (*InstanceMutaterAPI)(nil)
+	_ InstanceMutaterV2 = (*InstanceMutaterAPIV2)(nil)
 	_ InstanceMutaterV1 = (*InstanceMutaterAPIV1)(nil)
 )
 
-// NewFacadeV2 is used for API registration.
-func NewFacadeV2(ctx facade.Context) (*InstanceMutaterAPI, error) {
+// NewFacadeV3 is used for API registration.
+func NewFacadeV3(ctx facade.Context) (*InstanceMutaterAPI, error) {
 	st := &instanceMutaterStateShim{State: ctx.State()}
 
 	model, err := ctx.Controller().Model(st.ModelUUID())
@@ -79,6 +97,15 @@ func NewFacadeV2(ctx facade.Context) (*InstanceMutaterAPI, error) {
 	return NewInstanceMutaterAPI(st, modelCache, ctx.Resources(), ctx.Auth())
 }
 
+// NewFacadeV2 is used for API registration.
+func NewFacadeV2(ctx facade.Context) (*InstanceMutaterAPIV2, error) {
+	v3, err := NewFacadeV3(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &InstanceMutaterAPIV2{v3}, nil
+}
+
 // NewFacadeV1 is used for API registration.
 func NewFacadeV1(ctx facade.Context) (*InstanceMutaterAPIV1, error) {
 	v2, err := NewFacadeV2(ctx)
@@ -134,6 +161,8 @@ func (api *InstanceMutaterAPI) CharmProfilingInfo(arg params.Entity) (params.Cha
 	lxdProfileInfo, err := api.machineLXDProfileInfo(m)
 	if err != nil {
 		result.Error = common.ServerError(errors.Annotatef(err, "%s", tag))
+	} else if result.RollbackProfiles, err = api.machineCharmProfilesRollback(canAccess, tag); err != nil {
+		result.Error = common.ServerError(errors.Annotatef(err, "%s", tag))
 	}
 
 	// use the results from the machineLXDProfileInfo and apply them to the
@@ -226,6 +255,32 @@ func (api *InstanceMutaterAPI) WatchMachines() (params.StringsWatchResult, error
 	return result, nil
 }
 
+// WatchLXDProfileMachines starts a watcher to track the machines hosting
+// units of applications whose charms declare an LXD profile. Unlike
+// WatchMachines, machines that will never need a profile change are not
+// reported, so the mutater does not need a worker for each of them.
+func (api *InstanceMutaterAPI) WatchLXDProfileMachines() (params.StringsWatchResult, error) {
+	result := params.StringsWatchResult{}
+	if !api.authorizer.AuthController() {
+		return result, common.ErrPerm
+	}
+
+	watch, err := api.model.WatchLXDProfileMachines()
+	if err != nil {
+		return result, err
+	}
+	if changes, ok := <-watch.Changes(); ok {
+		result.StringsWatcherId = api.resources.Register(watch)
+		result.Changes = changes
+	} else {
+		return result, errors.Errorf("cannot obtain initial model machines with lxd profiles")
+	}
+	return result, nil
+}
+
+// WatchLXDProfileMachines isn't on the v2 API.
+func (api *InstanceMutaterAPIV2) WatchLXDProfileMachines(_, _ struct{}) {}
+
 // WatchContainers starts a watcher to track Containers on a given
 // machine.
 func (api *InstanceMutaterAPI) WatchContainers(arg params.Entity) (params.StringsWatchResult, error) {
@@ -394,6 +449,18 @@ func (api *InstanceMutaterAPI) machineLXDProfileInfo(m ModelCacheMachine) (lxdPr
 	}, nil
 }
 
+// machineCharmProfilesRollback returns the profiles to which the
+// machine's lxd profiles are to be rolled back, if a rollback has been
+// requested. The request is read from state rather than the model cache,
+// which does not hold it.
+func (api *InstanceMutaterAPI) machineCharmProfilesRollback(canAccess common.AuthFunc, tag names.MachineTag) ([]string, error) {
+	machine, err := api.getMachine(canAccess, tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return machine.CharmProfilesRollback()
+}
+
 func (api *InstanceMutaterAPI) setOneMachineCharmProfiles(machineTag string, profiles []string, canAccess common.AuthFunc) error {
 	mTag, err := names.ParseMachineTag(machineTag)
 	if err != nil {
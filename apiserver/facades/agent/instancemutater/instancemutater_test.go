@@ -235,11 +235,12 @@ type entityShim struct {
 type InstanceMutaterAPICharmProfilingInfoSuite struct {
 	instanceMutaterAPISuite
 
-	machine     *mocks.MockModelCacheMachine
-	unit        *mocks.MockModelCacheUnit
-	application *mocks.MockModelCacheApplication
-	charm       *mocks.MockModelCacheCharm
-	lxdProfile  *mocks.MockLXDProfile
+	machine      *mocks.MockModelCacheMachine
+	stateMachine *mocks.MockMachine
+	unit         *mocks.MockModelCacheUnit
+	application  *mocks.MockModelCacheApplication
+	charm        *mocks.MockModelCacheCharm
+	lxdProfile   *mocks.MockLXDProfile
 }
 
 var _ = gc.Suite(&InstanceMutaterAPICharmProfilingInfoSuite{})
@@ -248,6 +249,7 @@ func (s *InstanceMutaterAPICharmProfilingInfoSuite) setup(c *gc.C) *gomock.Contr
 	ctrl := s.instanceMutaterAPISuite.setup(c)
 
 	s.machine = mocks.NewMockModelCacheMachine(ctrl)
+	s.stateMachine = mocks.NewMockMachine(ctrl)
 	s.unit = mocks.NewMockModelCacheUnit(ctrl)
 	s.application = mocks.NewMockModelCacheApplication(ctrl)
 	s.charm = mocks.NewMockModelCacheCharm(ctrl)
@@ -268,6 +270,7 @@ func (s *InstanceMutaterAPICharmProfilingInfoSuite) TestCharmProfilingInfo(c *gc
 		s.expectCharmProfiles,
 		s.expectProfileExtraction,
 		s.expectName,
+		s.expectCharmProfilesRollback(nil),
 	)
 
 	results, err := facade.CharmProfilingInfo(params.Entity{Tag: "machine-0"})
@@ -312,6 +315,7 @@ func (s *InstanceMutaterAPICharmProfilingInfoSuite) TestCharmProfilingInfoWithNo
 		s.expectProfileExtraction,
 		s.expectProfileExtractionWithEmpty,
 		s.expectName,
+		s.expectCharmProfilesRollback(nil),
 	)
 
 	results, err := facade.CharmProfilingInfo(params.Entity{Tag: "machine-0"})
@@ -347,6 +351,28 @@ func (s *InstanceMutaterAPICharmProfilingInfoSuite) TestCharmProfilingInfoWithNo
 	})
 }
 
+func (s *InstanceMutaterAPICharmProfilingInfoSuite) TestCharmProfilingInfoWithRollback(c *gc.C) {
+	defer s.setup(c).Finish()
+
+	facade := s.facadeAPIForScenario(c,
+		s.expectAuthMachineAgent,
+		s.expectLife(s.machineTag),
+		s.expectMachine(instance.Id("0")),
+		s.expectInstanceId(instance.Id("0")),
+		s.expectUnits(1),
+		s.expectCharmProfiles,
+		s.expectProfileExtraction,
+		s.expectName,
+		s.expectCharmProfilesRollback([]string{"default", "juju-foo"}),
+	)
+
+	results, err := facade.CharmProfilingInfo(params.Entity{Tag: "machine-0"})
+	c.Assert(err, gc.IsNil)
+	c.Assert(results.Error, gc.IsNil)
+	c.Assert(results.CurrentProfiles, gc.DeepEquals, []string{"charm-app-0"})
+	c.Assert(results.RollbackProfiles, gc.DeepEquals, []string{"default", "juju-foo"})
+}
+
 func (s *InstanceMutaterAPICharmProfilingInfoSuite) TestCharmProfilingInfoWithInvalidMachine(c *gc.C) {
 	defer s.setup(c).Finish()
 
@@ -452,6 +478,17 @@ func (s *InstanceMutaterAPICharmProfilingInfoSuite) expectProfileExtractionWithE
 	charmExp.LXDProfile().Return(lxdprofile.Profile{})
 }
 
+func (s *InstanceMutaterAPICharmProfilingInfoSuite) expectCharmProfilesRollback(profiles []string) func() {
+	return func() {
+		s.state.EXPECT().FindEntity(s.machineTag).Return(machineEntityShim{
+			Machine: s.stateMachine,
+			Entity:  s.entity,
+			Lifer:   s.lifer,
+		}, nil)
+		s.stateMachine.EXPECT().CharmProfilesRollback().Return(profiles, nil)
+	}
+}
+
 func (s *InstanceMutaterAPICharmProfilingInfoSuite) expectName() {
 	modelExp := s.model.EXPECT()
 	modelExp.Name().Return("foo")
@@ -704,6 +741,49 @@ func (s *InstanceMutaterAPIWatchMachinesSuite) TestWatchMachinesModelCacheError(
 	c.Assert(result, gc.DeepEquals, params.StringsWatchResult{})
 }
 
+func (s *InstanceMutaterAPIWatchMachinesSuite) TestWatchLXDProfileMachines(c *gc.C) {
+	defer s.setup(c).Finish()
+
+	facade := s.facadeAPIForScenario(c,
+		s.expectAuthMachineAgent,
+		s.expectAuthController,
+		s.expectWatchLXDProfileMachinesWithNotify(1),
+	)
+
+	result, err := facade.WatchLXDProfileMachines()
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, gc.DeepEquals, params.StringsWatchResult{
+		StringsWatcherId: "1",
+		Changes:          []string{"0"},
+	})
+	s.assertNotifyStop(c)
+}
+
+func (s *InstanceMutaterAPIWatchMachinesSuite) TestWatchLXDProfileMachinesWithClosedChannel(c *gc.C) {
+	defer s.setup(c).Finish()
+
+	facade := s.facadeAPIForScenario(c,
+		s.expectAuthMachineAgent,
+		s.expectAuthController,
+		s.expectWatchLXDProfileMachinesWithClosedChannel,
+	)
+
+	_, err := facade.WatchLXDProfileMachines()
+	c.Assert(err, gc.ErrorMatches, "cannot obtain initial model machines with lxd profiles")
+}
+
+func (s *InstanceMutaterAPIWatchMachinesSuite) TestWatchLXDProfileMachinesNotController(c *gc.C) {
+	defer s.setup(c).Finish()
+
+	facade := s.facadeAPIForScenario(c,
+		s.expectAuthMachineAgent,
+		func() { s.authorizer.EXPECT().AuthController().Return(false) },
+	)
+
+	_, err := facade.WatchLXDProfileMachines()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
 func (s *InstanceMutaterAPIWatchMachinesSuite) expectAuthController() {
 	s.authorizer.EXPECT().AuthController().Return(true)
 }
@@ -733,6 +813,31 @@ func (s *InstanceMutaterAPIWatchMachinesSuite) expectWatchMachinesWithClosedChan
 	s.watcher.EXPECT().Changes().Return(ch)
 }
 
+func (s *InstanceMutaterAPIWatchMachinesSuite) expectWatchLXDProfileMachinesWithNotify(times int) func() {
+	return func() {
+		ch := make(chan []string)
+
+		go func() {
+			for i := 0; i < times; i++ {
+				ch <- []string{fmt.Sprintf("%d", i)}
+			}
+			close(s.notifyDone)
+		}()
+
+		s.model.EXPECT().WatchLXDProfileMachines().Return(s.watcher, nil)
+		s.watcher.EXPECT().Changes().Return(ch)
+		s.resources.EXPECT().Register(s.watcher).Return("1")
+	}
+}
+
+func (s *InstanceMutaterAPIWatchMachinesSuite) expectWatchLXDProfileMachinesWithClosedChannel() {
+	ch := make(chan []string)
+	close(ch)
+
+	s.model.EXPECT().WatchLXDProfileMachines().Return(s.watcher, nil)
+	s.watcher.EXPECT().Changes().Return(ch)
+}
+
 func (s *InstanceMutaterAPIWatchMachinesSuite) expectWatchMachinesError() {
 	s.model.EXPECT().WatchMachines().Return(s.watcher, errors.New("error from model cache"))
 }
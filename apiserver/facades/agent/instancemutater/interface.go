@@ -21,6 +21,7 @@ type InstanceMutaterState interface {
 
 // Machine represents point of use methods from the state machine object
 type Machine interface {
+	CharmProfilesRollback() ([]string, error)
 	SetCharmProfiles([]string) error
 	SetModificationStatus(status.StatusInfo) error
 }
@@ -41,6 +42,7 @@ type ModelCache interface {
 	Charm(charmURL string) (ModelCacheCharm, error)
 	Machine(machineId string) (ModelCacheMachine, error)
 	WatchMachines() (cache.StringsWatcher, error)
+	WatchLXDProfileMachines() (cache.StringsWatcher, error)
 }
 
 // ModelCacheApplication represents a point of use Application from the cache
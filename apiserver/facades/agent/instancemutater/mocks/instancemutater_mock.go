@@ -85,6 +85,19 @@ func (m *MockMachine) EXPECT() *MockMachineMockRecorder {
 	return m.recorder
 }
 
+// CharmProfilesRollback mocks base method
+func (m *MockMachine) CharmProfilesRollback() ([]string, error) {
+	ret := m.ctrl.Call(m, "CharmProfilesRollback")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CharmProfilesRollback indicates an expected call of CharmProfilesRollback
+func (mr *MockMachineMockRecorder) CharmProfilesRollback() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CharmProfilesRollback", reflect.TypeOf((*MockMachine)(nil).CharmProfilesRollback))
+}
+
 // SetCharmProfiles mocks base method
 func (m *MockMachine) SetCharmProfiles(arg0 []string) error {
 	ret := m.ctrl.Call(m, "SetCharmProfiles", arg0)
@@ -87,6 +87,19 @@ func (mr *MockModelCacheMockRecorder) Name() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockModelCache)(nil).Name))
 }
 
+// WatchLXDProfileMachines mocks base method
+func (m *MockModelCache) WatchLXDProfileMachines() (cache.StringsWatcher, error) {
+	ret := m.ctrl.Call(m, "WatchLXDProfileMachines")
+	ret0, _ := ret[0].(cache.StringsWatcher)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WatchLXDProfileMachines indicates an expected call of WatchLXDProfileMachines
+func (mr *MockModelCacheMockRecorder) WatchLXDProfileMachines() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchLXDProfileMachines", reflect.TypeOf((*MockModelCache)(nil).WatchLXDProfileMachines))
+}
+
 // WatchMachines mocks base method
 func (m *MockModelCache) WatchMachines() (cache.StringsWatcher, error) {
 	ret := m.ctrl.Call(m, "WatchMachines")
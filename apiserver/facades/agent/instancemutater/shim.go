@@ -22,6 +22,10 @@ func (s *modelCacheShim) WatchMachines() (cache.StringsWatcher, error) {
 	return s.Model.WatchMachines()
 }
 
+func (s *modelCacheShim) WatchLXDProfileMachines() (cache.StringsWatcher, error) {
+	return s.Model.WatchLXDProfileMachines()
+}
+
 func (s modelCacheShim) Charm(charmURL string) (ModelCacheCharm, error) {
 	ch, err := s.Model.Charm(charmURL)
 	if err != nil {
@@ -14,6 +14,7 @@ import (
 	"github.com/juju/juju/apiserver/common/networkingcommon"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/multiwatcher"
 )
@@ -35,6 +36,24 @@ type MachinerAPI struct {
 	getCanRead   common.GetAuthFunc
 }
 
+// MachinerAPIV1 implements the V1 Machiner API, which lacks
+// SetObservedNetworkConfigChanges, SetBootIDs, SetHostnames, Hostnames,
+// SetShutdownProgress, SetHostInfo and Cordoned, and in which
+// SetObservedNetworkConfig does not return the problems found with the
+// machine's network config.
+type MachinerAPIV1 struct {
+	*MachinerAPI
+}
+
+// NewMachinerAPIV1 creates a new instance of the V1 Machiner API.
+func NewMachinerAPIV1(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*MachinerAPIV1, error) {
+	api, err := NewMachinerAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &MachinerAPIV1{api}, nil
+}
+
 // NewMachinerAPI creates a new instance of the Machiner API.
 func NewMachinerAPI(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*MachinerAPI, error) {
 	if !authorizer.AuthMachineAgent() {
@@ -98,6 +117,282 @@ func (api *MachinerAPI) SetMachineAddresses(args params.SetMachinesAddresses) (p
 	return results, nil
 }
 
+// SetObservedNetworkConfig sets the network config observed on the
+// machine, and returns the problems found with the machine's network
+// config which the controller cannot reconcile with the model's subnets.
+func (api *MachinerAPI) SetObservedNetworkConfig(args params.SetMachineNetworkConfig) (params.SetMachineNetworkConfigResult, error) {
+	if err := api.NetworkConfigAPI.SetObservedNetworkConfig(args); err != nil {
+		return params.SetMachineNetworkConfigResult{}, err
+	}
+	return api.validateNetworkConfig(args.Tag)
+}
+
+// SetObservedNetworkConfigChanges updates the network config observed
+// on the machine with that of the interfaces which have been added or
+// changed since it was last set. The network config of the interfaces
+// not given is left as it was, so a machiner need not report its whole
+// network config each time, nor anything when it is unchanged. The
+// problems found with the whole of the machine's network config are
+// returned.
+func (api *MachinerAPI) SetObservedNetworkConfigChanges(args params.SetMachineNetworkConfig) (params.SetMachineNetworkConfigResult, error) {
+	if len(args.Config) > 0 {
+		return api.SetObservedNetworkConfig(args)
+	}
+	return api.validateNetworkConfig(args.Tag)
+}
+
+// validateNetworkConfig returns the problems found with the network
+// config recorded for the given machine. The network config of
+// containers is not recorded by their machiners, and so not checked.
+func (api *MachinerAPI) validateNetworkConfig(tag string) (params.SetMachineNetworkConfigResult, error) {
+	var result params.SetMachineNetworkConfigResult
+	canModify, err := api.getCanModify()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	m, err := api.authMachine(canModify, tag)
+	if err != nil {
+		return result, err
+	}
+	if m.IsContainer() {
+		return result, nil
+	}
+	addresses, err := m.AllAddresses()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	subnets, err := api.st.AllSubnets()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	config := make([]params.NetworkConfig, len(addresses))
+	for i, addr := range addresses {
+		config[i] = params.NetworkConfig{
+			InterfaceName: addr.DeviceName(),
+			CIDR:          addr.SubnetCIDR(),
+			Address:       addr.Value(),
+		}
+	}
+	knownCIDRs := make([]string, len(subnets))
+	for i, subnet := range subnets {
+		knownCIDRs[i] = subnet.CIDR()
+	}
+	result.Validations = networkingcommon.ValidateNetworkConfig(config, knownCIDRs)
+	for _, v := range result.Validations {
+		logger.Warningf("network config of machine %s: %s: %s", m.Id(), v.InterfaceName, v.Message)
+	}
+	return result, nil
+}
+
+// SetObservedNetworkConfig sets the network config observed on the
+// machine. The V1 API does not return the problems found with it.
+func (api *MachinerAPIV1) SetObservedNetworkConfig(args params.SetMachineNetworkConfig) error {
+	return api.NetworkConfigAPI.SetObservedNetworkConfig(args)
+}
+
+// SetObservedNetworkConfigChanges isn't on the V1 API.
+func (*MachinerAPIV1) SetObservedNetworkConfigChanges(_, _ struct{}) {}
+
+// SetBootIDs records the boot id of the kernel of each of the given
+// machines, returning whether each has been rebooted, since its boot id
+// was last recorded, without Juju having rebooted it.
+func (api *MachinerAPI) SetBootIDs(args params.SetMachineBootIDs) (params.BoolResults, error) {
+	results := params.BoolResults{
+		Results: make([]params.BoolResult, len(args.Args)),
+	}
+	canModify, err := api.getCanModify()
+	if err != nil {
+		return results, err
+	}
+	for i, arg := range args.Args {
+		rebooted, err := api.setBootID(canModify, arg)
+		results.Results[i].Result = rebooted
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (api *MachinerAPI) setBootID(canModify common.AuthFunc, arg params.MachineBootID) (bool, error) {
+	m, err := api.authMachine(canModify, arg.Tag)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	rebooted, err := m.SetBootID(arg.BootID)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if rebooted {
+		logger.Warningf("machine %s rebooted outside of Juju", m.Id())
+	}
+	return rebooted, nil
+}
+
+// SetBootIDs isn't on the V1 API.
+func (*MachinerAPIV1) SetBootIDs(_, _ struct{}) {}
+
+// SetHostnames records the hostname of each of the given machines, as
+// observed by their machine agents.
+func (api *MachinerAPI) SetHostnames(args params.SetMachineHostnames) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	canModify, err := api.getCanModify()
+	if err != nil {
+		return results, err
+	}
+	for i, arg := range args.Args {
+		m, err := api.authMachine(canModify, arg.Tag)
+		if err == nil {
+			err = m.SetHostname(arg.Hostname)
+		}
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// Hostnames returns the hostname of each of the given machines, as last
+// recorded by SetHostnames.
+func (api *MachinerAPI) Hostnames(args params.Entities) (params.StringResults, error) {
+	results := params.StringResults{
+		Results: make([]params.StringResult, len(args.Entities)),
+	}
+	canRead, err := api.getCanRead()
+	if err != nil {
+		return results, err
+	}
+	for i, entity := range args.Entities {
+		m, err := api.authMachine(canRead, entity.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].Result = m.Hostname()
+	}
+	return results, nil
+}
+
+// authMachine returns the machine with the given tag, if it is
+// authorized.
+func (api *MachinerAPI) authMachine(auth common.AuthFunc, tagString string) (*state.Machine, error) {
+	tag, err := names.ParseMachineTag(tagString)
+	if err != nil || !auth(tag) {
+		return nil, common.ErrPerm
+	}
+	m, err := api.getMachine(tag)
+	if errors.IsNotFound(err) {
+		return nil, common.ErrPerm
+	}
+	return m, errors.Trace(err)
+}
+
+// SetHostnames isn't on the V1 API.
+func (*MachinerAPIV1) SetHostnames(_, _ struct{}) {}
+
+// Hostnames isn't on the V1 API.
+func (*MachinerAPIV1) Hostnames(_, _ struct{}) {}
+
+// SetShutdownProgress records the progress of the shutdown tasks run by
+// the agents of the given dying machines, before they are made dead. The
+// progress is reported as the machine's status, which is set to draining.
+func (api *MachinerAPI) SetShutdownProgress(args params.SetMachineShutdownProgress) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	canModify, err := api.getCanModify()
+	if err != nil {
+		return results, err
+	}
+	for i, arg := range args.Args {
+		m, err := api.authMachine(canModify, arg.Tag)
+		if err == nil {
+			err = m.SetStatus(status.StatusInfo{
+				Status:  status.Draining,
+				Message: arg.Progress,
+			})
+		}
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// SetShutdownProgress isn't on the V1 API.
+func (*MachinerAPIV1) SetShutdownProgress(_, _ struct{}) {}
+
+// SetHostInfo records the kernel version, series and hardware of each of
+// the given machines, as observed by their machine agents. The series is
+// not recorded while the machine's series is being upgraded; that is
+// recorded when the upgrade completes.
+func (api *MachinerAPI) SetHostInfo(args params.SetMachinesHostInfo) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	canModify, err := api.getCanModify()
+	if err != nil {
+		return results, err
+	}
+	for i, arg := range args.Args {
+		m, err := api.authMachine(canModify, arg.Tag)
+		if err == nil {
+			err = setHostInfo(m, arg)
+		}
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func setHostInfo(m *state.Machine, arg params.MachineHostInfo) error {
+	info := state.HostInfo{KernelVersion: arg.KernelVersion}
+	if hw := arg.Hardware; hw != nil {
+		info.Mem = hw.Mem
+		info.CpuCores = hw.CpuCores
+		info.RootDisk = hw.RootDisk
+	}
+	if err := m.SetHostInfo(info); err != nil {
+		return errors.Trace(err)
+	}
+	if arg.Series == "" || arg.Series == m.Series() {
+		return nil
+	}
+	locked, err := m.IsLockedForSeriesUpgrade()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if locked {
+		return nil
+	}
+	// The operating system has been upgraded already, so the series
+	// is recorded even if the charms deployed don't support it.
+	logger.Infof("series of machine %q changed from %q to %q", m.Id(), m.Series(), arg.Series)
+	return errors.Trace(m.UpdateMachineSeries(arg.Series, true))
+}
+
+// SetHostInfo isn't on the V1 API.
+func (*MachinerAPIV1) SetHostInfo(_, _ struct{}) {}
+
+// Cordoned returns whether each of the given machines is cordoned, in
+// which case no new units are assigned to it.
+func (api *MachinerAPI) Cordoned(args params.Entities) (params.BoolResults, error) {
+	results := params.BoolResults{
+		Results: make([]params.BoolResult, len(args.Entities)),
+	}
+	canRead, err := api.getCanRead()
+	if err != nil {
+		return results, err
+	}
+	for i, entity := range args.Entities {
+		m, err := api.authMachine(canRead, entity.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].Result = m.IsCordoned()
+	}
+	return results, nil
+}
+
+// Cordoned isn't on the V1 API.
+func (*MachinerAPIV1) Cordoned(_, _ struct{}) {}
+
 // Jobs returns the jobs assigned to the given entities.
 func (api *MachinerAPI) Jobs(args params.Entities) (params.JobsResults, error) {
 	result := params.JobsResults{
@@ -15,6 +15,7 @@ import (
 	"github.com/juju/juju/apiserver/facades/agent/machine"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/state"
@@ -283,3 +284,250 @@ func (s *machinerSuite) TestWatch(c *gc.C) {
 	wc := statetesting.NewNotifyWatcherC(c, s.State, resource.(state.NotifyWatcher))
 	wc.AssertNoChange()
 }
+
+func (s *machinerSuite) TestSetObservedNetworkConfigChanges(c *gc.C) {
+	err := s.machine1.SetInstanceInfo("i-foo", "", "FAKE_NONCE", nil, nil, nil, nil, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.SetMachineNetworkConfig{
+		Tag: s.machine1.Tag().String(),
+		Config: []params.NetworkConfig{{
+			InterfaceName: "eth0",
+			InterfaceType: "ethernet",
+			MACAddress:    "aa:bb:cc:dd:ee:f0",
+			CIDR:          "0.10.0.0/24",
+			Address:       "0.10.0.2",
+		}},
+	}
+	_, err = s.machiner.SetObservedNetworkConfigChanges(args)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Changes to other interfaces leave eth0 as it was.
+	args.Config = []params.NetworkConfig{{
+		InterfaceName: "eth1",
+		InterfaceType: "ethernet",
+		MACAddress:    "aa:bb:cc:dd:ee:f1",
+		CIDR:          "0.20.0.0/24",
+		Address:       "0.20.0.2",
+	}}
+	_, err = s.machiner.SetObservedNetworkConfigChanges(args)
+	c.Assert(err, jc.ErrorIsNil)
+
+	devices, err := s.machine1.AllLinkLayerDevices()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(devices, gc.HasLen, 2)
+	for _, device := range devices {
+		c.Check(device.Name(), gc.Matches, `eth[01]`)
+	}
+}
+
+func (s *machinerSuite) TestSetObservedNetworkConfigChangesNone(c *gc.C) {
+	_, err := s.machiner.SetObservedNetworkConfigChanges(params.SetMachineNetworkConfig{
+		Tag: s.machine1.Tag().String(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.machiner.SetObservedNetworkConfigChanges(params.SetMachineNetworkConfig{
+		Tag: s.machine0.Tag().String(),
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *machinerSuite) TestSetObservedNetworkConfigValidations(c *gc.C) {
+	err := s.machine1.SetInstanceInfo("i-foo", "", "FAKE_NONCE", nil, nil, nil, nil, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSubnet(network.SubnetInfo{CIDR: "0.10.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.SetMachineNetworkConfig{
+		Tag: s.machine1.Tag().String(),
+		Config: []params.NetworkConfig{{
+			InterfaceName: "eth0",
+			InterfaceType: "ethernet",
+			MACAddress:    "aa:bb:cc:dd:ee:f0",
+			CIDR:          "0.10.0.0/24",
+			Address:       "0.10.0.2",
+		}, {
+			InterfaceName: "eth1",
+			InterfaceType: "ethernet",
+			MACAddress:    "aa:bb:cc:dd:ee:f1",
+			CIDR:          "0.20.0.0/24",
+			Address:       "0.20.0.2",
+		}},
+	}
+	result, err := s.machiner.SetObservedNetworkConfig(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.SetMachineNetworkConfigResult{
+		Validations: []params.NetworkConfigValidation{{
+			InterfaceName: "eth1",
+			Address:       "0.20.0.2",
+			CIDR:          "0.20.0.0/24",
+			Message:       `subnet "0.20.0.0/24" is not known to the model`,
+		}},
+	})
+
+	// Once the subnet is known, the problem is no longer reported, even
+	// when the config of other interfaces changes.
+	_, err = s.State.AddSubnet(network.SubnetInfo{CIDR: "0.20.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	args.Config = args.Config[:1]
+	result, err = s.machiner.SetObservedNetworkConfigChanges(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Validations, gc.HasLen, 0)
+}
+
+func (s *machinerSuite) TestSetBootIDs(c *gc.C) {
+	args := params.SetMachineBootIDs{Args: []params.MachineBootID{
+		{Tag: "machine-1", BootID: "boot-1"},
+		{Tag: "machine-0", BootID: "boot-1"},
+		{Tag: "machine-42", BootID: "boot-1"},
+	}}
+	result, err := s.machiner.SetBootIDs(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.BoolResults{
+		Results: []params.BoolResult{
+			{Result: false},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	err = s.machine1.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine1.BootID(), gc.Equals, "boot-1")
+
+	// The machine has been rebooted, but not by Juju.
+	result, err = s.machiner.SetBootIDs(params.SetMachineBootIDs{Args: []params.MachineBootID{
+		{Tag: "machine-1", BootID: "boot-2"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.BoolResults{
+		Results: []params.BoolResult{{Result: true}},
+	})
+}
+
+func (s *machinerSuite) TestSetHostnames(c *gc.C) {
+	args := params.SetMachineHostnames{Args: []params.MachineHostname{
+		{Tag: "machine-1", Hostname: "juju-1.example.com"},
+		{Tag: "machine-0", Hostname: "juju-0.example.com"},
+		{Tag: "machine-42", Hostname: "juju-42.example.com"},
+	}}
+	result, err := s.machiner.SetHostnames(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	err = s.machine1.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine1.Hostname(), gc.Equals, "juju-1.example.com")
+
+	hostnames, err := s.machiner.Hostnames(params.Entities{Entities: []params.Entity{
+		{Tag: "machine-1"},
+		{Tag: "machine-0"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hostnames, gc.DeepEquals, params.StringResults{
+		Results: []params.StringResult{
+			{Result: "juju-1.example.com"},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
+func (s *machinerSuite) TestSetShutdownProgress(c *gc.C) {
+	args := params.SetMachineShutdownProgress{Args: []params.MachineShutdownProgress{
+		{Tag: "machine-1", Progress: "flushing logs"},
+	}}
+	result, err := s.machiner.SetShutdownProgress(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Error, gc.ErrorMatches, `cannot set status "draining" while machine is alive`)
+
+	err = s.machine1.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	args.Args = append(args.Args,
+		params.MachineShutdownProgress{Tag: "machine-0", Progress: "flushing logs"},
+		params.MachineShutdownProgress{Tag: "machine-42", Progress: "flushing logs"},
+	)
+	result, err = s.machiner.SetShutdownProgress(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	statusInfo, err := s.machine1.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statusInfo.Status, gc.Equals, status.Draining)
+	c.Assert(statusInfo.Message, gc.Equals, "flushing logs")
+}
+
+func (s *machinerSuite) TestSetHostInfo(c *gc.C) {
+	arch := "amd64"
+	mem := uint64(4096)
+	err := s.machine1.SetProvisioned("i-1", "", "fake_nonce", &instance.HardwareCharacteristics{
+		Arch: &arch,
+		Mem:  &mem,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	newMem := uint64(8192)
+	args := params.SetMachinesHostInfo{Args: []params.MachineHostInfo{
+		{
+			Tag:           "machine-1",
+			KernelVersion: "4.15.0-58-generic",
+			Series:        "bionic",
+			Hardware:      &instance.HardwareCharacteristics{Mem: &newMem},
+		},
+		{Tag: "machine-0", KernelVersion: "4.15.0-58-generic"},
+		{Tag: "machine-42", KernelVersion: "4.15.0-58-generic"},
+	}}
+	result, err := s.machiner.SetHostInfo(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	err = s.machine1.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine1.KernelVersion(), gc.Equals, "4.15.0-58-generic")
+	c.Assert(s.machine1.Series(), gc.Equals, "bionic")
+	hc, err := s.machine1.HardwareCharacteristics()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*hc, jc.DeepEquals, instance.HardwareCharacteristics{
+		Arch: &arch,
+		Mem:  &newMem,
+	})
+}
+
+func (s *machinerSuite) TestCordoned(c *gc.C) {
+	err := s.machine1.SetCordoned(true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.machiner.Cordoned(params.Entities{Entities: []params.Entity{
+		{Tag: "machine-1"},
+		{Tag: "machine-0"},
+		{Tag: "machine-42"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.BoolResults{
+		Results: []params.BoolResult{
+			{Result: true},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
@@ -3,7 +3,11 @@
 
 package provisioner
 
-import "github.com/juju/juju/apiserver/params"
+import (
+	"github.com/juju/clock"
+
+	"github.com/juju/juju/apiserver/params"
+)
 
 func NewPrepareOrGetContext(result params.MachineNetworkConfigResults, maintain bool) *prepareOrGetContext {
 	return &prepareOrGetContext{result: result, maintain: maintain}
@@ -12,3 +16,7 @@ func NewPrepareOrGetContext(result params.MachineNetworkConfigResults, maintain
 func NewContainerProfileContext(result params.ContainerProfileResults, modelName string) *containerProfileContext {
 	return &containerProfileContext{result: result, modelName: modelName}
 }
+
+func SetClock(p *ProvisionerAPI, clock clock.Clock) {
+	p.clock = clock
+}
@@ -0,0 +1,76 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provisioner
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// networkBootSeedLifetime is how long the user-data published for a
+// machine that is provisioned by network boot can be fetched for.
+const networkBootSeedLifetime = time.Hour
+
+// PublishNetworkBootUserData publishes the rendered user-data of each of
+// the given machines, which are to be provisioned by network boot, and
+// returns the cloud-init seed from which the user-data can be fetched
+// once, without credentials, by the booting machine.
+func (p *ProvisionerAPI) PublishNetworkBootUserData(args params.NetworkBootUserDataArgs) (params.NetworkBootSeedResults, error) {
+	results := params.NetworkBootSeedResults{
+		Results: make([]params.NetworkBootSeedResult, len(args.Args)),
+	}
+	canAccess, err := p.getAuthFunc()
+	if err != nil {
+		return results, errors.Trace(err)
+	}
+	for i, arg := range args.Args {
+		seed, err := p.publishNetworkBootUserData(canAccess, arg)
+		results.Results[i].Result = seed
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (p *ProvisionerAPI) publishNetworkBootUserData(canAccess common.AuthFunc, arg params.NetworkBootUserDataArg) (*params.NetworkBootSeed, error) {
+	tag, err := names.ParseMachineTag(arg.Tag)
+	if err != nil {
+		return nil, common.ErrPerm
+	}
+	machine, err := p.getMachine(canAccess, tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	controllerConfig, err := p.st.ControllerConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	caCert, _ := controllerConfig.CACert()
+	addrs, err := p.APIAddresses()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(addrs.Result) == 0 {
+		return nil, errors.New("no suitable API server address to pick from")
+	}
+
+	expires := p.clock.Now().Add(networkBootSeedLifetime)
+	token, err := machine.PublishNetworkBootUserData(arg.UserData, expires)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &params.NetworkBootSeed{
+		URL:     fmt.Sprintf("https://%s/model/%s/network-boot/%s", addrs.Result[0], p.st.ModelUUID(), token),
+		CACert:  caCert,
+		Expires: expires,
+	}, nil
+}
+
+// PublishNetworkBootUserData isn't on the v9 API.
+func (p *ProvisionerAPIV9) PublishNetworkBootUserData(_, _ struct{}) {}
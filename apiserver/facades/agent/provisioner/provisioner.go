@@ -6,6 +6,7 @@ package provisioner
 import (
 	"sync"
 
+	"github.com/juju/clock"
 	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -64,6 +65,7 @@ type ProvisionerAPI struct {
 	getAuthFunc             common.GetAuthFunc
 	getCanModify            common.GetAuthFunc
 	providerCallContext     context.ProviderCallContext
+	clock                   clock.Clock
 
 	// Used for MaybeWriteLXDProfile()
 	mu sync.Mutex
@@ -151,6 +153,7 @@ func NewProvisionerAPI(st *state.State, resources facade.Resources, authorizer f
 		getAuthFunc:             getAuthFunc,
 		getCanModify:            getCanModify,
 		providerCallContext:     callCtx,
+		clock:                   clock.WallClock,
 	}
 	if isCaasModel {
 		return api, nil
@@ -190,6 +193,12 @@ type ProvisionerAPIV8 struct {
 // ProvisionerAPIV9 provides v9 of the provisioner facade.
 // Added SupportedContainers
 type ProvisionerAPIV9 struct {
+	*ProvisionerAPIV10
+}
+
+// ProvisionerAPIV10 provides v10 of the provisioner facade.
+// Added PublishNetworkBootUserData
+type ProvisionerAPIV10 struct {
 	*ProvisionerAPI
 }
 
@@ -240,13 +249,22 @@ func NewProvisionerAPIV8(st *state.State, resources facade.Resources, authorizer
 
 // NewProvisionerAPIV9 creates a new server-side Provisioner API facade.
 func NewProvisionerAPIV9(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*ProvisionerAPIV9, error) {
-	provisionerAPI, err := NewProvisionerAPI(st, resources, authorizer)
+	provisionerAPI, err := NewProvisionerAPIV10(st, resources, authorizer)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 	return &ProvisionerAPIV9{provisionerAPI}, nil
 }
 
+// NewProvisionerAPIV10 creates a new server-side Provisioner API facade.
+func NewProvisionerAPIV10(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*ProvisionerAPIV10, error) {
+	provisionerAPI, err := NewProvisionerAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ProvisionerAPIV10{provisionerAPI}, nil
+}
+
 func (p *ProvisionerAPI) getMachine(canAccess common.AuthFunc, tag names.MachineTag) (*state.Machine, error) {
 	if !canAccess(tag) {
 		return nil, common.ErrPerm
@@ -1024,9 +1042,9 @@ func (ctx *prepareOrGetContext) ProcessOneContainer(
 		return errors.Trace(err)
 	}
 
-	bridgePolicy := containerizer.BridgePolicy{
-		NetBondReconfigureDelay:   env.Config().NetBondReconfigureDelay(),
-		ContainerNetworkingMethod: env.Config().ContainerNetworkingMethod(),
+	bridgePolicy, err := newBridgePolicy(env.Config())
+	if err != nil {
+		return errors.Trace(err)
 	}
 
 	// TODO(jam): 2017-01-31 PopulateContainerLinkLayerDevices should really
@@ -1184,6 +1202,24 @@ func (p *ProvisionerAPI) prepareContainerAccessEnvironment() (environs.Environ,
 	return env, host, canAccess, nil
 }
 
+// newBridgePolicy returns the policy for bridging host devices for
+// containers set by the model config.
+func newBridgePolicy(cfg *config.Config) (containerizer.BridgePolicy, error) {
+	fanConfig, err := cfg.FanConfig()
+	if err != nil {
+		return containerizer.BridgePolicy{}, errors.Trace(err)
+	}
+	return containerizer.BridgePolicy{
+		NetBondReconfigureDelay:   cfg.NetBondReconfigureDelay(),
+		ContainerNetworkingMethod: cfg.ContainerNetworkingMethod(),
+		BridgePrefix:              cfg.ContainerBridgePrefix(),
+		BridgeMTU:                 cfg.ContainerBridgeMTU(),
+		BridgeInterfaces:          cfg.ContainerBridgeInterfaces(),
+		OpenvSwitch:               cfg.ContainerBridgeOpenvSwitch(),
+		FanConfig:                 fanConfig,
+	}, nil
+}
+
 type hostChangesContext struct {
 	result params.HostNetworkChangeResults
 }
@@ -1192,9 +1228,9 @@ type hostChangesContext struct {
 func (ctx *hostChangesContext) ProcessOneContainer(
 	env environs.Environ, callContext context.ProviderCallContext, idx int, host, guest Machine,
 ) error {
-	bridgePolicy := containerizer.BridgePolicy{
-		NetBondReconfigureDelay:   env.Config().NetBondReconfigureDelay(),
-		ContainerNetworkingMethod: env.Config().ContainerNetworkingMethod(),
+	bridgePolicy, err := newBridgePolicy(env.Config())
+	if err != nil {
+		return errors.Trace(err)
 	}
 	bridges, reconfigureDelay, err := bridgePolicy.FindMissingBridgesForContainer(host, guest)
 	if err != nil {
@@ -1209,6 +1245,8 @@ func (ctx *hostChangesContext) ProcessOneContainer(
 				HostDeviceName: bridgeInfo.DeviceName,
 				BridgeName:     bridgeInfo.BridgeName,
 				MACAddress:     bridgeInfo.MACAddress,
+				MTU:            bridgeInfo.MTU,
+				OpenvSwitch:    bridgeInfo.OpenvSwitch,
 			})
 	}
 	return nil
@@ -5,10 +5,12 @@ package provisioner_test
 
 import (
 	"fmt"
+	"regexp"
 	stdtesting "testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/juju/clock/testclock"
 	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"github.com/juju/proxy"
@@ -53,7 +55,7 @@ type provisionerSuite struct {
 
 	authorizer  apiservertesting.FakeAuthorizer
 	resources   *common.Resources
-	provisioner *provisioner.ProvisionerAPIV9
+	provisioner *provisioner.ProvisionerAPIV10
 }
 
 var _ = gc.Suite(&provisionerSuite{})
@@ -94,7 +96,7 @@ func (s *provisionerSuite) setUpTest(c *gc.C, withController bool) {
 	s.resources = common.NewResources()
 
 	// Create a provisioner API for the machine.
-	provisionerAPI, err := provisioner.NewProvisionerAPIV9(
+	provisionerAPI, err := provisioner.NewProvisionerAPIV10(
 		s.State,
 		s.resources,
 		s.authorizer,
@@ -2053,3 +2055,32 @@ type provisionerProfileMockSuite struct {
 	charm   *mocks.MockProfileCharm
 	machine *mocks.MockProfileMachine
 }
+
+func (s *withoutControllerSuite) TestPublishNetworkBootUserData(c *gc.C) {
+	now := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	provisioner.SetClock(s.provisioner.ProvisionerAPI, testclock.NewClock(now))
+	args := params.NetworkBootUserDataArgs{Args: []params.NetworkBootUserDataArg{
+		{Tag: s.machines[0].Tag().String(), UserData: []byte("#cloud-config\n")},
+		{Tag: "machine-42", UserData: []byte("#cloud-config\n")},
+		{Tag: "application-bar", UserData: []byte("#cloud-config\n")},
+	}}
+	results, err := s.provisioner.PublishNetworkBootUserData(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.DeepEquals, apiservertesting.NotFoundError("machine 42"))
+	c.Assert(results.Results[2].Error, gc.DeepEquals, apiservertesting.ErrUnauthorized)
+
+	seed := results.Results[0].Result
+	c.Assert(seed, gc.NotNil)
+	c.Assert(seed.CACert, gc.Equals, coretesting.CACert)
+	c.Assert(seed.Expires, gc.Equals, now.Add(time.Hour))
+	pattern := fmt.Sprintf(`https://[^/]+/model/%s/network-boot/([[:xdigit:]]+)`, s.State.ModelUUID())
+	c.Assert(seed.URL, gc.Matches, pattern)
+
+	token := regexp.MustCompile(pattern).FindStringSubmatch(seed.URL)[1]
+	data, err := s.State.ConsumeNetworkBootUserData(token)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data.MachineId, gc.Equals, s.machines[0].Id())
+	c.Assert(string(data.UserData), gc.Equals, "#cloud-config\n")
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/juju/juju/cloudconfig/instancecfg"
 	"github.com/juju/juju/core/lxdprofile"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/imagemetadata"
 	"github.com/juju/juju/environs/simplestreams"
 	"github.com/juju/juju/environs/tags"
@@ -102,6 +103,11 @@ func (p *ProvisionerAPI) getProvisioningInfo(m *state.Machine, env environs.Envi
 		return nil, errors.Annotate(err, "cannot get controller configuration")
 	}
 
+	enrollByIdentity, err := p.instanceIdentityEnrollment(m, env)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
 	return &params.ProvisioningInfo{
 		Constraints:       cons,
 		Series:            m.Series(),
@@ -116,9 +122,32 @@ func (p *ProvisionerAPI) getProvisioningInfo(m *state.Machine, env environs.Envi
 		ControllerConfig:  controllerCfg,
 		CloudInitUserData: env.Config().CloudInitUserData(),
 		CharmLXDProfiles:  pNames,
+
+		InstanceIdentityEnrollment: enrollByIdentity,
 	}, nil
 }
 
+// instanceIdentityEnrollment returns whether the machine's agent is to
+// enroll with the signed identity document of its instance rather than
+// with a provisioning password.
+func (p *ProvisionerAPI) instanceIdentityEnrollment(m *state.Machine, env environs.Environ) (bool, error) {
+	if env.Config().MachineEnrollment() != config.MachineEnrollmentInstanceIdentity {
+		return false, nil
+	}
+	// Containers have no cloud instance identity of their own, and
+	// controllers need a password for their database access.
+	if m.IsContainer() || m.IsManager() {
+		return false, nil
+	}
+	if _, ok := env.(environs.InstanceIdentityVerifier); !ok {
+		return false, errors.NotSupportedf(
+			"%s %q on %q", config.MachineEnrollmentKey,
+			config.MachineEnrollmentInstanceIdentity, env.Config().Type(),
+		)
+	}
+	return true, nil
+}
+
 // machineVolumeParams retrieves VolumeParams for the volumes that should be
 // provisioned with, and attached to, the machine. The client should ignore
 // parameters that it does not know how to handle.
@@ -327,6 +327,26 @@ func (s *withoutControllerSuite) TestProvisioningInfoWithLXDProfile(c *gc.C) {
 	c.Assert(result, jc.DeepEquals, expected)
 }
 
+func (s *withoutControllerSuite) TestProvisioningInfoInstanceIdentityEnrollmentNotSupported(c *gc.C) {
+	err := s.Model.UpdateModelConfig(map[string]interface{}{
+		"machine-enrollment": "instance-identity",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.AddOneMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: machine.Tag().String()},
+	}}
+	result, err := s.provisioner.ProvisioningInfo(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Error, gc.ErrorMatches, `machine-enrollment "instance-identity" on "dummy" not supported`)
+}
+
 func (s *withoutControllerSuite) TestStorageProviderFallbackToType(c *gc.C) {
 	template := state.MachineTemplate{
 		Series:    "quantal",
@@ -0,0 +1,111 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/state"
+)
+
+const (
+	settingsMetricsNamespace = "juju"
+	settingsMetricsSubsystem = "apiserver"
+
+	// settingsNearLimitPercent is the percentage of the size limit
+	// beyond which relation settings are considered near the limit.
+	settingsNearLimitPercent = 80
+)
+
+// settingsSizeMetrics is the collector of the metrics of the relation
+// settings writes that are near, or over, the size limit. It is shared
+// by all uniter facades, and exposed with the apiserver's metrics.
+var settingsSizeMetrics = newSettingsSizeCollector()
+
+// SettingsSizeCollector returns the prometheus.Collector for the metrics
+// of the relation settings writes that are near, or over, the size limit.
+func SettingsSizeCollector() prometheus.Collector {
+	return settingsSizeMetrics
+}
+
+type settingsSizeCollector struct {
+	nearLimit *prometheus.CounterVec
+	rejected  *prometheus.CounterVec
+}
+
+func newSettingsSizeCollector() *settingsSizeCollector {
+	return &settingsSizeCollector{
+		nearLimit: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: settingsMetricsNamespace,
+			Subsystem: settingsMetricsSubsystem,
+			Name:      "relation_settings_near_limit_total",
+			Help:      "Number of relation settings writes which left the settings near the size limit",
+		}, []string{"model_uuid", "relation"}),
+		rejected: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: settingsMetricsNamespace,
+			Subsystem: settingsMetricsSubsystem,
+			Name:      "relation_settings_rejected_total",
+			Help:      "Number of relation settings writes refused for exceeding the size limit",
+		}, []string{"model_uuid", "relation"}),
+	}
+}
+
+// Describe is part of the prometheus.Collector interface.
+func (c *settingsSizeCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.nearLimit.Describe(ch)
+	c.rejected.Describe(ch)
+}
+
+// Collect is part of the prometheus.Collector interface.
+func (c *settingsSizeCollector) Collect(ch chan<- prometheus.Metric) {
+	c.nearLimit.Collect(ch)
+	c.rejected.Collect(ch)
+}
+
+// settingsSize returns the size in bytes of the settings, counted as the
+// lengths of their keys and values.
+func settingsSize(settings map[string]interface{}) int {
+	size := 0
+	for k, v := range settings {
+		size += len(k)
+		if s, ok := v.(string); ok {
+			size += len(s)
+		} else {
+			size += len(fmt.Sprint(v))
+		}
+	}
+	return size
+}
+
+// checkSettingsSize returns a SettingsTooLargeError if the settings of
+// the unit in the relation have grown past the limit, so must not be
+// written. Settings which are already past the limit, as they may be if
+// it was lowered, can still be shrunk. Settings near the limit are
+// allowed, but logged and counted.
+func (u *UniterAPI) checkSettingsSize(
+	rel *state.Relation, unit names.UnitTag, oldSize int, settings map[string]interface{}, limit int,
+) error {
+	size := settingsSize(settings)
+	labels := prometheus.Labels{"model_uuid": u.m.UUID(), "relation": rel.String()}
+	if size > limit && size > oldSize {
+		settingsSizeMetrics.rejected.With(labels).Inc()
+		return &common.SettingsTooLargeError{
+			What:  fmt.Sprintf("settings of unit %q in relation %q", unit.Id(), rel.String()),
+			Size:  size,
+			Limit: limit,
+		}
+	}
+	if size*100 >= limit*settingsNearLimitPercent {
+		settingsSizeMetrics.nearLimit.With(labels).Inc()
+		logger.Warningf(
+			"settings of unit %q in relation %q are %d bytes, near the limit of %d bytes",
+			unit.Id(), rel.String(), size, limit,
+		)
+	}
+	return nil
+}
@@ -38,10 +38,8 @@ import (
 
 var logger = loggo.GetLogger("juju.apiserver.uniter")
 
-// UniterAPI implements the latest version (v12) of the Uniter API,
-// Removes the embedded LXDProfileAPI, which in turn removes the following;
-// RemoveUpgradeCharmProfileData, WatchUnitLXDProfileUpgradeNotifications
-// and WatchLXDProfileUpgradeNotifications
+// UniterAPI implements the latest version (v14) of the Uniter API,
+// which adds RelatedUnitAddresses.
 type UniterAPI struct {
 	*common.LifeGetter
 	*StatusAPI
@@ -51,6 +49,7 @@ type UniterAPI struct {
 	*common.ModelWatcher
 	*common.RebootRequester
 	*common.UpgradeSeriesAPI
+	*common.EgressAddressSetter
 	*leadershipapiserver.LeadershipSettingsAccessor
 	meterstatus.MeterStatus
 	m                   *state.Model
@@ -76,11 +75,19 @@ type UniterAPI struct {
 	cloudSpec       cloudspec.CloudSpecAPI
 }
 
-// UniterAPIV11 implements the latest version (v11) of the Uniter API,
+// UniterAPIV12 removes the embedded LXDProfileAPI, which in turn removes
+// the following; RemoveUpgradeCharmProfileData,
+// WatchUnitLXDProfileUpgradeNotifications and
+// WatchLXDProfileUpgradeNotifications
+type UniterAPIV12 struct {
+	UniterAPI
+}
+
+// UniterAPIV11 implements version (v11) of the Uniter API,
 // which adds CloudAPIVersion.
 type UniterAPIV11 struct {
 	*LXDProfileAPI
-	UniterAPI
+	UniterAPIV12
 }
 
 // UniterAPIV10 adds WatchUnitLXDProfileUpgradeNotifications and
@@ -191,6 +198,7 @@ func NewUniterAPI(context facade.Context) (*UniterAPI, error) {
 		ModelWatcher:               common.NewModelWatcher(m, resources, authorizer),
 		RebootRequester:            common.NewRebootRequester(st, accessMachine),
 		UpgradeSeriesAPI:           common.NewExternalUpgradeSeriesAPI(st, resources, authorizer, accessMachine, accessUnit, logger),
+		EgressAddressSetter:        common.NewEgressAddressSetter(st, accessUnit),
 		LeadershipSettingsAccessor: leadershipSettingsAccessorFactory(st, leadershipChecker, resources, authorizer),
 		MeterStatus:                msAPI,
 		// TODO(fwereade): so *every* unit should be allowed to get/set its
@@ -212,9 +220,20 @@ func NewUniterAPI(context facade.Context) (*UniterAPI, error) {
 	}, nil
 }
 
+// NewUniterAPIV12 creates an instance of the V12 uniter API.
+func NewUniterAPIV12(context facade.Context) (*UniterAPIV12, error) {
+	uniterAPI, err := NewUniterAPI(context)
+	if err != nil {
+		return nil, err
+	}
+	return &UniterAPIV12{
+		UniterAPI: *uniterAPI,
+	}, nil
+}
+
 // NewUniterAPIV11 creates an instance of the V11 uniter API.
 func NewUniterAPIV11(context facade.Context) (*UniterAPIV11, error) {
-	uniterAPI, err := NewUniterAPI(context)
+	uniterAPI, err := NewUniterAPIV12(context)
 	if err != nil {
 		return nil, err
 	}
@@ -224,7 +243,7 @@ func NewUniterAPIV11(context facade.Context) (*UniterAPIV11, error) {
 	accessUnit := unitAccessor(authorizer, st)
 	return &UniterAPIV11{
 		LXDProfileAPI: NewExternalLXDProfileAPI(st, resources, authorizer, accessUnit, logger),
-		UniterAPI:     *uniterAPI,
+		UniterAPIV12:  *uniterAPI,
 	}, nil
 }
 
@@ -903,7 +922,7 @@ func (u *UniterAPI) OpenPorts(args params.EntitiesPortRanges) (params.ErrorResul
 			var unit *state.Unit
 			unit, err = u.getUnit(tag)
 			if err == nil {
-				err = unit.OpenPorts(entity.Protocol, entity.FromPort, entity.ToPort)
+				err = unit.OpenPortsInHook(entity.Hook, entity.Protocol, entity.FromPort, entity.ToPort)
 			}
 		}
 		result.Results[i].Error = common.ServerError(err)
@@ -932,7 +951,7 @@ func (u *UniterAPI) ClosePorts(args params.EntitiesPortRanges) (params.ErrorResu
 			var unit *state.Unit
 			unit, err = u.getUnit(tag)
 			if err == nil {
-				err = unit.ClosePorts(entity.Protocol, entity.FromPort, entity.ToPort)
+				err = unit.ClosePortsInHook(entity.Hook, entity.Protocol, entity.FromPort, entity.ToPort)
 			}
 		}
 		result.Results[i].Error = common.ServerError(err)
@@ -1490,7 +1509,9 @@ func (u *UniterAPI) ReadRemoteSettings(args params.RelationUnitPairs) (params.Se
 
 // UpdateSettings persists all changes made to the local settings of
 // all given pairs of relation and unit. Keys with empty values are
-// considered a signal to delete these values.
+// considered a signal to delete these values. Changes which would grow
+// the settings past the controller's max-relation-settings-size are
+// refused with a CodeSettingsTooLarge error.
 func (u *UniterAPI) UpdateSettings(args params.RelationUnitsSettings) (params.ErrorResults, error) {
 	result := params.ErrorResults{
 		Results: make([]params.ErrorResult, len(args.RelationUnits)),
@@ -1499,6 +1520,11 @@ func (u *UniterAPI) UpdateSettings(args params.RelationUnitsSettings) (params.Er
 	if err != nil {
 		return params.ErrorResults{}, err
 	}
+	controllerConfig, err := u.st.ControllerConfig()
+	if err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	limit := controllerConfig.MaxRelationSettingsSize()
 	for i, arg := range args.RelationUnits {
 		unit, err := names.ParseUnitTag(arg.Unit)
 		if err != nil {
@@ -1510,6 +1536,7 @@ func (u *UniterAPI) UpdateSettings(args params.RelationUnitsSettings) (params.Er
 			var settings *state.Settings
 			settings, err = relUnit.Settings()
 			if err == nil {
+				oldSize := settingsSize(settings.Map())
 				for k, v := range arg.Settings {
 					if v == "" {
 						settings.Delete(k)
@@ -1517,7 +1544,10 @@ func (u *UniterAPI) UpdateSettings(args params.RelationUnitsSettings) (params.Er
 						settings.Set(k, v)
 					}
 				}
-				_, err = settings.Write()
+				err = u.checkSettingsSize(relUnit.Relation(), unit, oldSize, settings.Map(), limit)
+				if err == nil {
+					_, err = settings.Write()
+				}
 			}
 		}
 		result.Results[i].Error = common.ServerError(err)
@@ -2165,8 +2195,15 @@ func (u *UniterAPI) NetworkInfo(args params.NetworkInfoParams) (params.NetworkIn
 		}
 
 		// If there is no egress subnet explicitly defined for a given binding,
-		// default to the first ingress address. This matches the behaviour when
-		// there's a relation in place.
+		// use any egress addresses reported for the unit, such as those of a
+		// NAT gateway. Failing that, default to the first ingress address.
+		// This matches the behaviour when there's a relation in place.
+		if reported := unit.EgressAddressesForBinding(binding); len(info.EgressSubnets) == 0 && len(reported) > 0 {
+			info.EgressSubnets, err = network.FormatAsCIDR(reported)
+			if err != nil {
+				return result, errors.Trace(err)
+			}
+		}
 		if len(info.EgressSubnets) == 0 && len(info.IngressAddresses) > 0 {
 			info.EgressSubnets, err = network.FormatAsCIDR([]string{info.IngressAddresses[0]})
 			if err != nil {
@@ -2487,6 +2524,17 @@ func (u *UniterAPIV6) NetworkInfo(args params.NetworkInfoParams) (params.Network
 // SetPodSpec isn't on the v7 API.
 func (u *UniterAPIV7) SetPodSpec(_, _ struct{}) {}
 
+// Mask the SetEgressAddresses and RelatedUnitAddresses methods from the
+// v12 API. The API reflection code in rpc/rpcreflect/type.go:newMethod
+// skips 2-argument methods, so this removes the methods as far as the RPC
+// machinery is concerned.
+
+// SetEgressAddresses isn't on the v12 API.
+func (u *UniterAPIV12) SetEgressAddresses(_, _ struct{}) {}
+
+// RelatedUnitAddresses isn't on the v12 API.
+func (u *UniterAPIV12) RelatedUnitAddresses(_, _ struct{}) {}
+
 // SetPodSpec sets the pod specs for a set of applications.
 func (u *UniterAPI) SetPodSpec(args params.SetPodSpecParams) (params.ErrorResults, error) {
 	results := params.ErrorResults{
@@ -2734,6 +2782,134 @@ func (u *UniterAPI) goalStateUnits(app *state.Application, principalName string)
 	return unitsGoalState, nil
 }
 
+// RelatedUnitAddresses returns, for each unit and endpoint, the addresses
+// of the units of the applications related to the unit's application on
+// that endpoint. The related units are those known to state, so that
+// units just added are not missed; their addresses are read from the
+// model cache, and from state only for units not yet in the cache.
+func (u *UniterAPI) RelatedUnitAddresses(args params.UnitEndpoints) (params.RelatedUnitAddressesResults, error) {
+	result := params.RelatedUnitAddressesResults{
+		Results: make([]params.RelatedUnitAddressesResult, len(args.Entities)),
+	}
+
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.RelatedUnitAddressesResults{}, err
+	}
+	for i, arg := range args.Entities {
+		tag, err := names.ParseUnitTag(arg.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		if !canAccess(tag) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		unit, err := u.getUnit(tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Units, err = u.oneRelatedUnitAddresses(unit, arg.Endpoint)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+		}
+	}
+	return result, nil
+}
+
+// oneRelatedUnitAddresses returns the addresses of the units related to
+// the unit on the named endpoint.
+func (u *UniterAPI) oneRelatedUnitAddresses(unit *state.Unit, endpoint string) ([]params.RelatedUnitAddress, error) {
+	app, err := unit.Application()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	localEndpoint, err := app.Endpoint(endpoint)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	relations, err := app.Relations()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var result []params.RelatedUnitAddress
+	for _, rel := range relations {
+		if rel.Life() != state.Alive {
+			continue
+		}
+		ep, err := rel.Endpoint(app.Name())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if ep.Name != localEndpoint.Name {
+			continue
+		}
+		relatedEndpoints, err := rel.RelatedEndpoints(app.Name())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, relatedEndpoint := range relatedEndpoints {
+			relatedApp, err := u.st.Application(relatedEndpoint.ApplicationName)
+			if errors.IsNotFound(err) {
+				// The units of remote applications are
+				// not in this model.
+				logger.Debugf("application %q must be a remote application.", relatedEndpoint.ApplicationName)
+				continue
+			} else if err != nil {
+				return nil, errors.Trace(err)
+			}
+			units, err := relatedApp.AllUnits()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			for _, relatedUnit := range units {
+				if relatedUnit.Name() == unit.Name() || relatedUnit.Life() == state.Dead {
+					continue
+				}
+				if ep.Scope == charm.ScopeContainer && unitPrincipalName(relatedUnit) != unitPrincipalName(unit) {
+					continue
+				}
+				result = append(result, u.relatedUnitAddress(relatedUnit, rel.Id()))
+			}
+		}
+	}
+	return result, nil
+}
+
+// relatedUnitAddress returns the addresses of the related unit, preferring
+// those in the model cache.
+func (u *UniterAPI) relatedUnitAddress(unit *state.Unit, relationId int) params.RelatedUnitAddress {
+	result := params.RelatedUnitAddress{
+		Unit:        unit.Name(),
+		Application: unit.ApplicationName(),
+		RelationId:  relationId,
+	}
+	if cached, err := u.cacheModel.Unit(unit.Name()); err == nil {
+		result.PublicAddress = cached.PublicAddress()
+		result.PrivateAddress = cached.PrivateAddress()
+		return result
+	}
+	if addr, err := unit.PublicAddress(); err == nil {
+		result.PublicAddress = addr.Value
+	}
+	if addr, err := unit.PrivateAddress(); err == nil {
+		result.PrivateAddress = addr.Value
+	}
+	return result
+}
+
+// unitPrincipalName returns the name of the unit's principal, or of the unit
+// itself if it is a principal.
+func unitPrincipalName(unit *state.Unit) string {
+	if principal, ok := unit.PrincipalName(); ok {
+		return principal
+	}
+	return unit.Name()
+}
+
 // WatchConfigSettingsHash returns a StringsWatcher that yields a hash
 // of the config values every time the config changes. The uniter can
 // save this hash and use it to decide whether the config-changed hook
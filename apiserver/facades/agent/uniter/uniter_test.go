@@ -5,6 +5,7 @@ package uniter_test
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/juju/errors"
@@ -414,6 +415,33 @@ func (s *uniterSuite) TestLife(c *gc.C) {
 	})
 }
 
+func (s *uniterSuite) TestSetEgressAddresses(c *gc.C) {
+	bindings := map[string][]string{"db": {"203.0.113.7"}}
+	args := params.SetUnitsEgressAddresses{Args: []params.UnitEgressAddresses{
+		{Tag: "unit-mysql-0", Bindings: bindings},
+		{Tag: "unit-wordpress-0", Bindings: bindings},
+		{Tag: "unit-wordpress-0", Bindings: map[string][]string{"db": {"bad"}}},
+		{Tag: "unit-foo-42", Bindings: bindings},
+	}}
+	result, err := s.uniter.SetEgressAddresses(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+			{nil},
+			{&params.Error{Message: `egress address "bad" for unit "wordpress/0" not valid`}},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	err = s.wordpressUnit.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.wordpressUnit.EgressAddresses(), jc.DeepEquals, bindings)
+	err = s.mysqlUnit.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysqlUnit.EgressAddresses(), gc.IsNil)
+}
+
 func (s *uniterSuite) TestEnsureDead(c *gc.C) {
 	c.Assert(s.wordpressUnit.Life(), gc.Equals, state.Alive)
 	c.Assert(s.mysqlUnit.Life(), gc.Equals, state.Alive)
@@ -979,7 +1007,7 @@ func (s *uniterSuite) TestOpenPorts(c *gc.C) {
 
 	args := params.EntitiesPortRanges{Entities: []params.EntityPortRange{
 		{Tag: "unit-mysql-0", Protocol: "tcp", FromPort: 1234, ToPort: 1400},
-		{Tag: "unit-wordpress-0", Protocol: "udp", FromPort: 4321, ToPort: 5000},
+		{Tag: "unit-wordpress-0", Protocol: "udp", FromPort: 4321, ToPort: 5000, Hook: "install"},
 		{Tag: "unit-foo-42", Protocol: "tcp", FromPort: 42, ToPort: 42},
 	}}
 	result, err := s.uniter.OpenPorts(args)
@@ -998,6 +1026,14 @@ func (s *uniterSuite) TestOpenPorts(c *gc.C) {
 	c.Assert(openedPorts, gc.DeepEquals, []network.PortRange{
 		{Protocol: "udp", FromPort: 4321, ToPort: 5000},
 	})
+
+	// The hook the port was opened in is recorded in the history.
+	history, err := s.machine0.PortsHistory(0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 1)
+	c.Check(history[0].Ports.UnitName, gc.Equals, "wordpress/0")
+	c.Check(history[0].Opened, jc.IsTrue)
+	c.Check(history[0].Hook, gc.Equals, "install")
 }
 
 func (s *uniterSuite) TestClosePorts(c *gc.C) {
@@ -1856,6 +1892,59 @@ func (s *uniterSuite) TestProviderType(c *gc.C) {
 	c.Assert(result, gc.DeepEquals, params.StringResult{Result: cfg.Type()})
 }
 
+func (s *uniterSuite) TestRelatedUnitAddresses(c *gc.C) {
+	rel := s.addRelation(c, "wordpress", "mysql")
+	// A second wordpress unit is related to mysql, but the calling
+	// unit itself is not reported.
+	s.Factory.MakeUnit(c, &factory.UnitParams{
+		Application: s.wordpress,
+		Machine:     s.machine1,
+	})
+
+	args := params.UnitEndpoints{Entities: []params.UnitEndpoint{
+		{Tag: "unit-wordpress-0", Endpoint: "db"},
+		{Tag: "unit-wordpress-0", Endpoint: "url"},
+		{Tag: "unit-wordpress-0", Endpoint: "nonsense"},
+		{Tag: "unit-mysql-0", Endpoint: "server"},
+		{Tag: "application-wordpress", Endpoint: "db"},
+	}}
+	result, err := s.uniter.RelatedUnitAddresses(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.RelatedUnitAddressesResults{
+		Results: []params.RelatedUnitAddressesResult{
+			{Units: []params.RelatedUnitAddress{{
+				Unit:        "mysql/0",
+				Application: "mysql",
+				RelationId:  rel.Id(),
+			}}},
+			{},
+			{Error: &params.Error{
+				Message: `application "wordpress" has no "nonsense" relation`,
+			}},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	// Units of the related application are reported as soon
+	// as they are added to state.
+	s.Factory.MakeUnit(c, &factory.UnitParams{
+		Application: s.mysql,
+		Machine:     s.machine1,
+	})
+	result, err = s.uniter.RelatedUnitAddresses(params.UnitEndpoints{
+		Entities: args.Entities[:1],
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	var unitNames []string
+	for _, unit := range result.Results[0].Units {
+		unitNames = append(unitNames, unit.Unit)
+	}
+	c.Assert(unitNames, jc.SameContents, []string{"mysql/0", "mysql/1"})
+}
+
 func (s *uniterSuite) TestEnterScope(c *gc.C) {
 	// Set wordpressUnit's private address first.
 	err := s.machine0.SetProviderAddresses(
@@ -2400,6 +2489,38 @@ func (s *uniterSuite) TestUpdateSettings(c *gc.C) {
 	})
 }
 
+func (s *uniterSuite) TestUpdateSettingsTooLarge(c *gc.C) {
+	rel := s.addRelation(c, "wordpress", "mysql")
+	relUnit, err := rel.Unit(s.wordpressUnit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = relUnit.EnterScope(map[string]interface{}{"some": "settings"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The default limit is 1MB.
+	args := params.RelationUnitsSettings{RelationUnits: []params.RelationUnitSettings{{
+		Relation: rel.Tag().String(),
+		Unit:     "unit-wordpress-0",
+		Settings: params.Settings{"blob": strings.Repeat("x", 1024*1024)},
+	}}}
+	result, err := s.uniter.UpdateSettings(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	resultErr := result.Results[0].Error
+	c.Assert(resultErr, jc.Satisfies, params.IsCodeSettingsTooLarge)
+	c.Assert(resultErr, gc.ErrorMatches, `settings of unit "wordpress/0" in relation "wordpress:db mysql:server" would be 1048592 bytes, exceeding the limit of 1048576 bytes; .*`)
+	c.Assert(resultErr.Info, jc.DeepEquals, map[string]interface{}{
+		"size":  float64(1048592),
+		"limit": float64(1048576),
+	})
+
+	// The settings were not changed.
+	readSettings, err := relUnit.ReadSettings(s.wordpressUnit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(readSettings, gc.DeepEquals, map[string]interface{}{
+		"some": "settings",
+	})
+}
+
 func (s *uniterSuite) TestWatchRelationUnits(c *gc.C) {
 	// Add a relation between wordpress and mysql and enter scope with
 	// mysqlUnit.
@@ -3945,6 +4066,23 @@ func (s *uniterNetworkInfoSuite) TestNetworkInfoForImplicitlyBoundEndpoint(c *gc
 	})
 }
 
+func (s *uniterNetworkInfoSuite) TestNetworkInfoUsesReportedEgressAddresses(c *gc.C) {
+	s.setupUniterAPIForUnit(c, s.mysqlUnit)
+	err := s.mysqlUnit.SetEgressAddresses(map[string][]string{"server": {"203.0.113.7"}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.NetworkInfoParams{
+		Unit:     s.mysqlUnit.Tag().String(),
+		Bindings: []string{"server"},
+	}
+	result, err := s.uniter.NetworkInfo(args)
+	c.Assert(err, jc.ErrorIsNil)
+	info := result.Results["server"]
+	c.Assert(info.Error, gc.IsNil)
+	c.Check(info.IngressAddresses, jc.DeepEquals, []string{"192.168.1.20"})
+	c.Check(info.EgressSubnets, jc.DeepEquals, []string{"203.0.113.7/32"})
+}
+
 func (s *uniterNetworkInfoSuite) TestNetworkInfoUsesRelationAddressNonDefaultBinding(c *gc.C) {
 	// If a network info call is made in the context of a relation, and the
 	// endpoint of that relation is bound to the non default space, we
@@ -4,6 +4,9 @@
 package upgradesteps
 
 import (
+	"github.com/juju/version"
+	"gopkg.in/juju/names.v3"
+
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/state"
@@ -11,6 +14,7 @@ import (
 
 type UpgradeStepsState interface {
 	state.EntityFinder
+	SetUpgradeStepsCompleted(names.Tag, version.Number) error
 }
 
 // Machine represents point of use methods from the state machine object
@@ -9,6 +9,7 @@ import (
 	instance "github.com/juju/juju/core/instance"
 	status "github.com/juju/juju/core/status"
 	state "github.com/juju/juju/state"
+	version "github.com/juju/version"
 	names_v3 "gopkg.in/juju/names.v3"
 	reflect "reflect"
 )
@@ -49,6 +50,18 @@ func (mr *MockUpgradeStepsStateMockRecorder) FindEntity(arg0 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindEntity", reflect.TypeOf((*MockUpgradeStepsState)(nil).FindEntity), arg0)
 }
 
+// SetUpgradeStepsCompleted mocks base method
+func (m *MockUpgradeStepsState) SetUpgradeStepsCompleted(arg0 names_v3.Tag, arg1 version.Number) error {
+	ret := m.ctrl.Call(m, "SetUpgradeStepsCompleted", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUpgradeStepsCompleted indicates an expected call of SetUpgradeStepsCompleted
+func (mr *MockUpgradeStepsStateMockRecorder) SetUpgradeStepsCompleted(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUpgradeStepsCompleted", reflect.TypeOf((*MockUpgradeStepsState)(nil).SetUpgradeStepsCompleted), arg0, arg1)
+}
+
 // MockMachine is a mock of Machine interface
 type MockMachine struct {
 	ctrl     *gomock.Controller
@@ -20,6 +20,11 @@ import (
 
 var logger = loggo.GetLogger("juju.apiserver.upgradesteps")
 
+type UpgradeStepsV2 interface {
+	UpgradeStepsV1
+	SetUpgradeStepsCompleted(params.UpgradeStepsCompletedArgs) (params.ErrorResults, error)
+}
+
 type UpgradeStepsV1 interface {
 	ResetKVMMachineModificationStatusIdle(params.Entity) (params.ErrorResult, error)
 }
@@ -33,15 +38,31 @@ type UpgradeStepsAPI struct {
 
 // using apiserver/facades/client/cloud as an example.
 var (
-	_ UpgradeStepsV1 = (*UpgradeStepsAPI)(nil)
+	_ UpgradeStepsV2 = (*UpgradeStepsAPI)(nil)
+	_ UpgradeStepsV1 = (*UpgradeStepsAPIV1)(nil)
 )
 
-// NewFacadeV1 is used for API registration.
-func NewFacadeV1(ctx facade.Context) (*UpgradeStepsAPI, error) {
+// UpgradeStepsAPIV1 implements version 1 of the UpgradeSteps facade,
+// which doesn't have the SetUpgradeStepsCompleted method.
+type UpgradeStepsAPIV1 struct {
+	*UpgradeStepsAPI
+}
+
+// NewFacadeV2 is used for API registration.
+func NewFacadeV2(ctx facade.Context) (*UpgradeStepsAPI, error) {
 	st := &upgradeStepsStateShim{State: ctx.State()}
 	return NewUpgradeStepsAPI(st, ctx.Resources(), ctx.Auth())
 }
 
+// NewFacadeV1 is used for API registration.
+func NewFacadeV1(ctx facade.Context) (*UpgradeStepsAPIV1, error) {
+	api, err := NewFacadeV2(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &UpgradeStepsAPIV1{api}, nil
+}
+
 func NewUpgradeStepsAPI(st UpgradeStepsState,
 	resources facade.Resources,
 	authorizer facade.Authorizer,
@@ -97,6 +118,36 @@ func (api *UpgradeStepsAPI) ResetKVMMachineModificationStatusIdle(arg params.Ent
 	return result, nil
 }
 
+// SetUpgradeStepsCompleted records that machine agents have completed
+// their upgrade steps for the given versions, so that agents that are
+// stuck upgrading can be identified.
+func (api *UpgradeStepsAPI) SetUpgradeStepsCompleted(args params.UpgradeStepsCompletedArgs) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	canAccess, err := api.getAuthFunc()
+	if err != nil {
+		return results, errors.Trace(err)
+	}
+	for i, arg := range args.Args {
+		tag, err := names.ParseMachineTag(arg.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		if !canAccess(tag) {
+			results.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = api.st.SetUpgradeStepsCompleted(tag, arg.Version)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// SetUpgradeStepsCompleted isn't on the v1 API.
+func (api *UpgradeStepsAPIV1) SetUpgradeStepsCompleted(_, _ struct{}) {}
+
 func (api *UpgradeStepsAPI) getMachine(canAccess common.AuthFunc, tag names.MachineTag) (Machine, error) {
 	if !canAccess(tag) {
 		return nil, common.ErrPerm
@@ -8,6 +8,7 @@ import (
 	"github.com/juju/errors"
 	jujutesting "github.com/juju/juju/testing"
 	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v3"
 
@@ -106,6 +107,32 @@ func (s *upgradeStepsSuite) TestResetKVMMachineModificationStatusIdleLXD(c *gc.C
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *upgradeStepsSuite) TestSetUpgradeStepsCompleted(c *gc.C) {
+	defer s.setup(c).Finish()
+
+	s.expectAuthCalls()
+	vers := version.MustParse("2.7.0")
+	s.state.EXPECT().SetUpgradeStepsCompleted(s.tag, vers).Return(nil)
+
+	s.setupFacadeAPI(c)
+
+	result, err := s.api.SetUpgradeStepsCompleted(params.UpgradeStepsCompletedArgs{
+		Args: []params.UpgradeStepsCompleted{
+			{Tag: s.tag.String(), Version: vers},
+			{Tag: "machine-1-lxd-0", Version: vers},
+			{Tag: "unit-foo-0", Version: vers},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
+			{Error: &params.Error{Message: `"unit-foo-0" is not a valid machine tag`}},
+		},
+	})
+}
+
 func (s *upgradeStepsSuite) setup(c *gc.C) *gomock.Controller {
 	ctrl := gomock.NewController(c)
 
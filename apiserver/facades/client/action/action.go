@@ -4,6 +4,9 @@
 package action
 
 import (
+	"fmt"
+	"net/url"
+	"sort"
 	"strings"
 
 	"github.com/juju/errors"
@@ -12,6 +15,7 @@ import (
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
 )
@@ -37,6 +41,11 @@ type APIv3 struct {
 
 // APIv4 provides the Action API facade for version 4.
 type APIv4 struct {
+	*APIv5
+}
+
+// APIv5 provides the Action API facade for version 5.
+type APIv5 struct {
 	*ActionAPI
 }
 
@@ -60,13 +69,22 @@ func NewActionAPIV3(ctx facade.Context) (*APIv3, error) {
 
 // NewActionAPIV4 returns an initialized ActionAPI for version 4.
 func NewActionAPIV4(ctx facade.Context) (*APIv4, error) {
-	api, err := newActionAPI(ctx.State(), ctx.Resources(), ctx.Auth())
+	api, err := NewActionAPIV5(ctx)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 	return &APIv4{api}, nil
 }
 
+// NewActionAPIV5 returns an initialized ActionAPI for version 5.
+func NewActionAPIV5(ctx facade.Context) (*APIv5, error) {
+	api, err := newActionAPI(ctx.State(), ctx.Resources(), ctx.Auth())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIv5{api}, nil
+}
+
 func newActionAPI(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*ActionAPI, error) {
 	if !authorizer.AuthClient() {
 		return nil, common.ErrPerm
@@ -214,14 +232,39 @@ func (a *ActionAPI) FindActionsByNames(arg params.FindActionsByNames) (params.Ac
 	return response, nil
 }
 
+// Enqueue is not able to invoke webhooks or take idempotency keys in
+// version 4 of the facade.
+func (a *APIv4) Enqueue(arg params.Actions) (params.ActionResults, error) {
+	if arg.Webhook != nil {
+		return params.ActionResults{}, errors.NotSupportedf("action webhooks")
+	}
+	for _, action := range arg.Actions {
+		if action.IdempotencyKey != "" {
+			return params.ActionResults{}, errors.NotSupportedf("idempotency keys")
+		}
+	}
+	return a.APIv5.Enqueue(arg)
+}
+
 // Enqueue takes a list of Actions and queues them up to be executed by
 // the designated ActionReceiver, returning the params.Action for each
 // enqueued Action, or an error if there was a problem enqueueing the
-// Action.
+// Action. If a webhook is given, it is invoked once all of the enqueued
+// actions have completed, and the id of the operation they make up is
+// returned with the results; if the webhook can't be added, the actions
+// are still enqueued, and the error is returned with the results. An
+// action enqueued with an idempotency key is not enqueued again by a
+// retry with the same key; the action already enqueued is returned
+// instead.
 func (a *ActionAPI) Enqueue(arg params.Actions) (params.ActionResults, error) {
 	if err := a.checkCanWrite(); err != nil {
 		return params.ActionResults{}, errors.Trace(err)
 	}
+	if arg.Webhook != nil {
+		if err := validateWebhookURL(arg.Webhook.URL); err != nil {
+			return params.ActionResults{}, errors.Trace(err)
+		}
+	}
 
 	var leaders map[string]string
 	getLeader := func(appName string) (string, error) {
@@ -239,44 +282,164 @@ func (a *ActionAPI) Enqueue(arg params.Actions) (params.ActionResults, error) {
 	}
 
 	tagToActionReceiver := common.TagToActionReceiverFn(a.state.FindEntity)
-	response := params.ActionResults{Results: make([]params.ActionResult, len(arg.Actions))}
-	for i, action := range arg.Actions {
-		currentResult := &response.Results[i]
+	enqueue := func(action params.Action) (names.Tag, state.Action, error) {
 		actionReceiver := action.Receiver
 		if strings.HasSuffix(actionReceiver, "leader") {
 			app := strings.Split(actionReceiver, "/")[0]
 			receiverName, err := getLeader(app)
 			if err != nil {
-				currentResult.Error = common.ServerError(err)
-				continue
+				return nil, nil, err
 			}
 			actionReceiver = names.NewUnitTag(receiverName).String()
 		}
 		receiver, err := tagToActionReceiver(actionReceiver)
 		if err != nil {
-			currentResult.Error = common.ServerError(err)
-			continue
+			return nil, nil, err
 		}
 		enqueued, err := receiver.AddAction(action.Name, action.Parameters)
+		if err != nil {
+			return nil, nil, err
+		}
+		return receiver.Tag(), enqueued, nil
+	}
+
+	response := params.ActionResults{Results: make([]params.ActionResult, len(arg.Actions))}
+	var enqueuedIds []string
+	for i, action := range arg.Actions {
+		currentResult := &response.Results[i]
+		var (
+			receiverTag names.Tag
+			enqueued    state.Action
+		)
+		ids, replayed, err := common.CallIdempotently(a.state, a.authorizer.GetAuthTag(), "enqueue-action", action.IdempotencyKey, func() ([]string, error) {
+			var err error
+			receiverTag, enqueued, err = enqueue(action)
+			if err != nil {
+				return nil, err
+			}
+			return []string{enqueued.Id()}, nil
+		})
+		if err == nil && replayed {
+			receiverTag, enqueued, err = a.enqueuedAction(ids)
+		}
 		if err != nil {
 			currentResult.Error = common.ServerError(err)
 			continue
 		}
 
-		response.Results[i] = common.MakeActionResult(receiver.Tag(), enqueued)
+		response.Results[i] = common.MakeActionResult(receiverTag, enqueued)
+		if !replayed {
+			enqueuedIds = append(enqueuedIds, enqueued.Id())
+		}
+	}
+	if arg.Webhook != nil && len(enqueuedIds) > 0 {
+		operation, err := addActionWebhook(a.model, enqueuedIds, arg.Webhook.URL, arg.Webhook.Secret)
+		if err != nil {
+			response.WebhookError = common.ServerError(errors.Annotate(err, "actions enqueued without webhook"))
+		} else {
+			response.Operation = operation
+		}
 	}
 	return response, nil
 }
 
+var addActionWebhook = func(model *state.Model, actionIds []string, url, secret string) (string, error) {
+	return model.AddActionWebhook(actionIds, url, secret)
+}
+
+// enqueuedAction returns the action recorded by an earlier call to
+// Enqueue with the same idempotency key, and the tag of its receiver.
+func (a *ActionAPI) enqueuedAction(ids []string) (names.Tag, state.Action, error) {
+	if len(ids) != 1 {
+		return nil, nil, errors.Errorf("expected 1 enqueued action, got %d", len(ids))
+	}
+	action, err := a.model.Action(ids[0])
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	receiverTag, err := names.ActionReceiverTag(action.Receiver())
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	return receiverTag, action, nil
+}
+
+// validateWebhookURL checks that the URL of a webhook is an absolute
+// http or https URL, and that its host is not a local or private
+// address. Host names are resolved, and checked again, when the
+// webhook is invoked.
+func validateWebhookURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return errors.NotValidf("webhook URL %q", rawURL)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return errors.NotValidf("webhook URL %q", rawURL)
+	}
+	host := u.Hostname()
+	if strings.EqualFold(host, "localhost") {
+		return errors.NotValidf("webhook URL %q", rawURL)
+	}
+	if addr := network.NewAddress(host); addr.Type != network.HostName && addr.Scope != network.ScopePublic {
+		return errors.NotValidf("webhook URL %q", rawURL)
+	}
+	return nil
+}
+
 // ListAll takes a list of Entities representing ActionReceivers and
 // returns all of the Actions that have been enqueued or run by each of
-// those Entities.
-func (a *ActionAPI) ListAll(arg params.Entities) (params.ActionsByReceivers, error) {
+// those Entities. Version 4 of the facade can't page the results.
+func (a *APIv4) ListAll(arg params.Entities) (params.ActionsByReceivers, error) {
+	return a.APIv5.ListAll(params.ListActionsArgs{Entities: arg.Entities})
+}
+
+// ListAll takes a list of Entities representing ActionReceivers and
+// returns the Actions that have been enqueued or run by each of those
+// Entities. If a page is requested, the page spans the Actions of all
+// the Entities, and the Actions of each Entity are ordered by tag.
+func (a *ActionAPI) ListAll(args params.ListActionsArgs) (params.ActionsByReceivers, error) {
 	if err := a.checkCanRead(); err != nil {
 		return params.ActionsByReceivers{}, errors.Trace(err)
 	}
 
-	return a.internalList(arg, combine(pendingActions, runningActions, completedActions))
+	response, err := a.internalList(
+		params.Entities{Entities: args.Entities},
+		combine(pendingActions, runningActions, completedActions),
+	)
+	if err != nil || args.Page == (params.PageParams{}) {
+		return response, errors.Trace(err)
+	}
+
+	// Key each action by the index of its receiver in the request, so
+	// that a page is made up of the actions of one receiver after
+	// another, as long as the client asks for the same receivers
+	// each time.
+	type receiverAction struct {
+		receiver int
+		action   params.ActionResult
+	}
+	var keys []string
+	actions := make(map[string]receiverAction)
+	for i, receiver := range response.Actions {
+		for _, action := range receiver.Actions {
+			key := fmt.Sprintf("%08d/%s", i, action.Action.Tag)
+			keys = append(keys, key)
+			actions[key] = receiverAction{receiver: i, action: action}
+		}
+		response.Actions[i].Actions = nil
+	}
+	sort.Strings(keys)
+	start, end, page, err := common.Paginate(args.Page, keys)
+	if err != nil {
+		return params.ActionsByReceivers{}, errors.Trace(err)
+	}
+	for _, key := range keys[start:end] {
+		ra := actions[key]
+		result := &response.Actions[ra.receiver]
+		result.Actions = append(result.Actions, ra.action)
+	}
+	response.Page = page
+	return response, nil
 }
 
 // ListPending takes a list of Entities representing ActionReceivers
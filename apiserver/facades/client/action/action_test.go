@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v3"
@@ -269,6 +270,121 @@ func (s *actionSuite) TestEnqueue(c *gc.C) {
 	c.Assert(actions, gc.HasLen, 0)
 }
 
+func (s *actionSuite) TestEnqueueWithWebhook(c *gc.C) {
+	arg := params.Actions{
+		Actions: []params.Action{
+			{Receiver: s.wordpressUnit.Tag().String(), Name: "fakeaction"},
+			{Receiver: s.mysqlUnit.Tag().String(), Name: "fakeaction"},
+			// Not enqueued, so not part of the operation.
+			{Receiver: s.wordpress.Tag().String(), Name: "fakeaction"},
+		},
+		Webhook: &params.ActionWebhook{
+			URL:    "https://example.com/hook",
+			Secret: "s3cret",
+		},
+	}
+	res, err := s.action.Enqueue(arg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res.Results, gc.HasLen, 3)
+	c.Assert(res.Operation, gc.Not(gc.Equals), "")
+
+	model, err := s.State.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	webhook, err := model.ActionWebhook(res.Operation)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(webhook.URL, gc.Equals, "https://example.com/hook")
+	c.Assert(webhook.Secret, gc.Equals, "s3cret")
+	var ids []string
+	for _, result := range res.Results[:2] {
+		tag, err := names.ParseActionTag(result.Action.Tag)
+		c.Assert(err, jc.ErrorIsNil)
+		ids = append(ids, tag.Id())
+	}
+	c.Assert(webhook.ActionIds, jc.DeepEquals, ids)
+}
+
+func (s *actionSuite) TestEnqueueWebhookFails(c *gc.C) {
+	s.PatchValue(action.AddActionWebhook, func(*state.Model, []string, string, string) (string, error) {
+		return "", errors.New("boom")
+	})
+	arg := params.Actions{
+		Actions: []params.Action{
+			{Receiver: s.wordpressUnit.Tag().String(), Name: "fakeaction"},
+		},
+		Webhook: &params.ActionWebhook{URL: "https://example.com/hook"},
+	}
+	res, err := s.action.Enqueue(arg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res.Operation, gc.Equals, "")
+	c.Assert(res.WebhookError, gc.ErrorMatches, "actions enqueued without webhook: boom")
+	c.Assert(res.Results, gc.HasLen, 1)
+	c.Assert(res.Results[0].Error, gc.IsNil)
+	c.Assert(res.Results[0].Action, gc.NotNil)
+
+	actions, err := s.wordpressUnit.Actions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 1)
+}
+
+func (s *actionSuite) TestEnqueueIdempotencyKey(c *gc.C) {
+	arg := params.Actions{
+		Actions: []params.Action{{
+			Receiver:       s.wordpressUnit.Tag().String(),
+			Name:           "fakeaction",
+			IdempotencyKey: "key-1",
+		}},
+	}
+	res, err := s.action.Enqueue(arg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res.Results, gc.HasLen, 1)
+	c.Assert(res.Results[0].Error, gc.IsNil)
+
+	// A retry returns the action already enqueued.
+	again, err := s.action.Enqueue(arg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(again.Results, gc.HasLen, 1)
+	c.Assert(again.Results[0].Error, gc.IsNil)
+	c.Assert(again.Results[0].Action, jc.DeepEquals, res.Results[0].Action)
+
+	actions, err := s.wordpressUnit.Actions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 1)
+}
+
+func (s *actionSuite) TestEnqueueIdempotencyKeyV4(c *gc.C) {
+	api := &action.APIv4{&action.APIv5{s.action}}
+	_, err := api.Enqueue(params.Actions{
+		Actions: []params.Action{{
+			Receiver:       s.wordpressUnit.Tag().String(),
+			Name:           "fakeaction",
+			IdempotencyKey: "key-1",
+		}},
+	})
+	c.Assert(err, gc.ErrorMatches, "idempotency keys not supported")
+	actions, err := s.wordpressUnit.Actions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 0)
+}
+
+func (s *actionSuite) TestEnqueueInvalidWebhook(c *gc.C) {
+	for _, rawURL := range []string{
+		"", "ftp://example.com", "/hook", "http://",
+		"http://localhost:8080/hook", "http://127.0.0.1/hook", "http://[::1]/hook",
+		"http://169.254.169.254/latest/meta-data", "https://10.1.2.3/hook", "http://192.168.0.1/hook",
+	} {
+		_, err := s.action.Enqueue(params.Actions{
+			Actions: []params.Action{
+				{Receiver: s.wordpressUnit.Tag().String(), Name: "fakeaction"},
+			},
+			Webhook: &params.ActionWebhook{URL: rawURL},
+		})
+		c.Check(err, gc.ErrorMatches, `webhook URL ".*" not valid`)
+	}
+	actions, err := s.wordpressUnit.Actions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 0)
+}
+
 type testCaseAction struct {
 	Name       string
 	Parameters map[string]interface{}
@@ -367,12 +483,64 @@ func (s *actionSuite) TestListAll(c *gc.C) {
 		}
 
 		// validate assumptions.
-		actionList, err := s.action.ListAll(arg)
+		actionList, err := s.action.ListAll(params.ListActionsArgs{Entities: arg.Entities})
 		c.Assert(err, jc.ErrorIsNil)
 		assertSame(c, actionList, expected)
 	}
 }
 
+func (s *actionSuite) TestListAllPaged(c *gc.C) {
+	for _, unit := range []*state.Unit{s.wordpressUnit, s.mysqlUnit} {
+		for i := 0; i < 3; i++ {
+			_, err := unit.AddAction("fakeaction", nil)
+			c.Assert(err, jc.ErrorIsNil)
+		}
+	}
+	entities := []params.Entity{
+		{Tag: s.wordpressUnit.Tag().String()},
+		{Tag: s.mysqlUnit.Tag().String()},
+	}
+
+	var pages [][]int
+	seen := make(map[string]bool)
+	page := params.PageParams{Limit: 4}
+	for {
+		result, err := s.action.ListAll(params.ListActionsArgs{Entities: entities, Page: page})
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(result.Actions, gc.HasLen, 2)
+		c.Assert(result.Page, gc.NotNil)
+		var counts []int
+		for _, receiver := range result.Actions {
+			counts = append(counts, len(receiver.Actions))
+			for _, action := range receiver.Actions {
+				c.Assert(seen[action.Action.Tag], jc.IsFalse)
+				seen[action.Action.Tag] = true
+			}
+		}
+		pages = append(pages, counts)
+		if result.Page.NextCursor == "" {
+			break
+		}
+		page.Cursor = result.Page.NextCursor
+	}
+	c.Assert(pages, jc.DeepEquals, [][]int{{3, 1}, {0, 2}})
+	c.Assert(seen, gc.HasLen, 6)
+}
+
+func (s *actionSuite) TestListAllV4(c *gc.C) {
+	_, err := s.wordpressUnit.AddAction("fakeaction", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	api := &action.APIv4{&action.APIv5{s.action}}
+	result, err := api.ListAll(params.Entities{Entities: []params.Entity{
+		{Tag: s.wordpressUnit.Tag().String()},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Page, gc.IsNil)
+	c.Assert(result.Actions, gc.HasLen, 1)
+	c.Assert(result.Actions[0].Actions, gc.HasLen, 1)
+}
+
 func (s *actionSuite) TestListPending(c *gc.C) {
 	for _, testCase := range testCases {
 		// set up query args
@@ -613,7 +781,7 @@ func (s *actionSuite) TestCancel(c *gc.C) {
 		{Tag: s.wordpressUnit.Tag().String()},
 		{Tag: s.mysqlUnit.Tag().String()},
 	}}
-	obtained, err := s.action.ListAll(tags)
+	obtained, err := s.action.ListAll(params.ListActionsArgs{Entities: tags.Entities})
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(obtained.Actions, gc.HasLen, 2)
 
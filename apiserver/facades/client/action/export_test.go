@@ -4,7 +4,8 @@
 package action
 
 var (
-	GetAllUnitNames = getAllUnitNames
-	QueueActions    = &queueActions
-	NewActionAPI    = newActionAPI
+	GetAllUnitNames  = getAllUnitNames
+	QueueActions     = &queueActions
+	AddActionWebhook = &addActionWebhook
+	NewActionAPI     = newActionAPI
 )
@@ -0,0 +1,201 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package apikeymanager provides the controller facade for issuing,
+// listing and revoking model-scoped API keys.
+package apikeymanager
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state"
+)
+
+// APIKeyManagerAPI implements the APIKeyManager facade.
+type APIKeyManagerAPI struct {
+	state      *state.State
+	authorizer facade.Authorizer
+	check      *common.BlockChecker
+	apiUser    names.UserTag
+}
+
+// NewAPIKeyManagerAPI provides the signature required for facade registration.
+func NewAPIKeyManagerAPI(
+	st *state.State,
+	resources facade.Resources,
+	authorizer facade.Authorizer,
+) (*APIKeyManagerAPI, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	apiUser, _ := authorizer.GetAuthTag().(names.UserTag)
+	return &APIKeyManagerAPI{
+		state:      st,
+		authorizer: authorizer,
+		check:      common.NewBlockChecker(st),
+		apiUser:    apiUser,
+	}, nil
+}
+
+// isModelAdmin reports whether the authenticated user may manage API
+// keys for the specified model.
+func (api *APIKeyManagerAPI) isModelAdmin(modelTag names.ModelTag) (bool, error) {
+	isSuperUser, err := api.authorizer.HasPermission(permission.SuperuserAccess, api.state.ControllerTag())
+	if err != nil && !errors.IsNotFound(err) {
+		return false, errors.Trace(err)
+	}
+	if isSuperUser {
+		return true, nil
+	}
+	isAdmin, err := api.authorizer.HasPermission(permission.AdminAccess, modelTag)
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	return isAdmin, errors.Trace(err)
+}
+
+// IssueAPIKeys issues new API keys owned by the authenticated user. The
+// user must be an administrator of each model a key is issued for.
+func (api *APIKeyManagerAPI) IssueAPIKeys(args params.IssueAPIKeysArgs) (params.IssueAPIKeyResults, error) {
+	result := params.IssueAPIKeyResults{
+		Results: make([]params.IssueAPIKeyResult, len(args.Keys)),
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, arg := range args.Keys {
+		key, token, err := api.issueOne(arg)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		info := apiKeyInfo(key)
+		result.Results[i].Key = &info
+		result.Results[i].Token = token
+	}
+	return result, nil
+}
+
+func (api *APIKeyManagerAPI) issueOne(arg params.IssueAPIKeyArg) (*state.APIKey, string, error) {
+	modelTag, err := names.ParseModelTag(arg.ModelTag)
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	isAdmin, err := api.isModelAdmin(modelTag)
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	if !isAdmin {
+		return nil, "", common.ErrPerm
+	}
+	var expires time.Time
+	if arg.Expires != nil {
+		expires = *arg.Expires
+	}
+	key, secret, err := api.state.AddAPIKey(state.AddAPIKeyArgs{
+		Model:   modelTag,
+		Owner:   api.apiUser,
+		Access:  permission.Access(arg.Access),
+		Expires: expires,
+	})
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	return key, state.APIKeyToken(key.Id(), secret), nil
+}
+
+// ListAPIKeys returns the API keys issued for each of the specified
+// models. Secrets are never returned.
+func (api *APIKeyManagerAPI) ListAPIKeys(args params.Entities) (params.ListAPIKeysResults, error) {
+	result := params.ListAPIKeysResults{
+		Results: make([]params.ListAPIKeysResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		keys, err := api.listOne(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Keys = keys
+	}
+	return result, nil
+}
+
+func (api *APIKeyManagerAPI) listOne(tag string) ([]params.APIKeyInfo, error) {
+	modelTag, err := names.ParseModelTag(tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	isAdmin, err := api.isModelAdmin(modelTag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !isAdmin {
+		return nil, common.ErrPerm
+	}
+	keys, err := api.state.ModelAPIKeys(modelTag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	infos := make([]params.APIKeyInfo, len(keys))
+	for i, key := range keys {
+		infos[i] = apiKeyInfo(key)
+	}
+	return infos, nil
+}
+
+// RevokeAPIKeys revokes the specified API keys. Keys may be revoked by
+// their owner or by an administrator of the key's model.
+func (api *APIKeyManagerAPI) RevokeAPIKeys(args params.RevokeAPIKeysArgs) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Ids)),
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, id := range args.Ids {
+		result.Results[i].Error = common.ServerError(api.revokeOne(id))
+	}
+	return result, nil
+}
+
+func (api *APIKeyManagerAPI) revokeOne(id string) error {
+	key, err := api.state.APIKey(id)
+	if errors.IsNotFound(err) {
+		return common.ErrPerm
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if key.Owner() != api.apiUser {
+		isAdmin, err := api.isModelAdmin(key.ModelTag())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !isAdmin {
+			return common.ErrPerm
+		}
+	}
+	return errors.Trace(key.Revoke())
+}
+
+func apiKeyInfo(key *state.APIKey) params.APIKeyInfo {
+	info := params.APIKeyInfo{
+		Id:       key.Id(),
+		ModelTag: key.ModelTag().String(),
+		OwnerTag: key.Owner().String(),
+		Access:   string(key.Access()),
+		Created:  key.Created(),
+		Revoked:  key.Revoked(),
+	}
+	if expires := key.Expires(); !expires.IsZero() {
+		info.Expires = &expires
+	}
+	return info
+}
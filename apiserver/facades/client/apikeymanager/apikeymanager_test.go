@@ -0,0 +1,168 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apikeymanager_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facades/client/apikeymanager"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type apiKeyManagerSuite struct {
+	jujutesting.JujuConnSuite
+
+	api        *apikeymanager.APIKeyManagerAPI
+	authorizer apiservertesting.FakeAuthorizer
+	resources  *common.Resources
+}
+
+var _ = gc.Suite(&apiKeyManagerSuite{})
+
+func (s *apiKeyManagerSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+
+	s.resources = common.NewResources()
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	}
+	var err error
+	s.api, err = apikeymanager.NewAPIKeyManagerAPI(s.State, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *apiKeyManagerSuite) TestNewAPIRefusesNonClient(c *gc.C) {
+	authorizer := s.authorizer
+	authorizer.Tag = names.NewMachineTag("1")
+	_, err := apikeymanager.NewAPIKeyManagerAPI(s.State, s.resources, authorizer)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *apiKeyManagerSuite) TestIssueAPIKeys(c *gc.C) {
+	expires := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	results, err := s.api.IssueAPIKeys(params.IssueAPIKeysArgs{
+		Keys: []params.IssueAPIKeyArg{{
+			ModelTag: s.Model.ModelTag().String(),
+			Access:   "write",
+			Expires:  &expires,
+		}, {
+			ModelTag: s.Model.ModelTag().String(),
+			Access:   "superuser",
+		}, {
+			ModelTag: "machine-0",
+			Access:   "read",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+
+	result := results.Results[0]
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.Key.ModelTag, gc.Equals, s.Model.ModelTag().String())
+	c.Assert(result.Key.OwnerTag, gc.Equals, s.AdminUserTag(c).String())
+	c.Assert(result.Key.Access, gc.Equals, "write")
+	c.Assert(*result.Key.Expires, gc.Equals, expires)
+
+	id, secret, err := state.ParseAPIKeyToken(result.Token)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, gc.Equals, result.Key.Id)
+	key, err := s.State.APIKey(id)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(key.SecretValid(secret), jc.IsTrue)
+
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `cannot add API key: "superuser" model access not valid`)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `"machine-0" is not a valid model tag`)
+}
+
+func (s *apiKeyManagerSuite) TestIssueAPIKeysRequiresModelAdmin(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{NoModelUser: true})
+	s.Factory.MakeModelUser(c, &factory.ModelUserParams{
+		User:   user.Name(),
+		Access: permission.WriteAccess,
+	})
+	s.authorizer.Tag = user.UserTag()
+	api, err := apikeymanager.NewAPIKeyManagerAPI(s.State, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := api.IssueAPIKeys(params.IssueAPIKeysArgs{
+		Keys: []params.IssueAPIKeyArg{{
+			ModelTag: s.Model.ModelTag().String(),
+			Access:   "read",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, "permission denied")
+}
+
+func (s *apiKeyManagerSuite) addKey(c *gc.C, owner names.UserTag) *state.APIKey {
+	key, _, err := s.State.AddAPIKey(state.AddAPIKeyArgs{
+		Model:  s.Model.ModelTag(),
+		Owner:  owner,
+		Access: permission.ReadAccess,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return key
+}
+
+func (s *apiKeyManagerSuite) TestListAPIKeys(c *gc.C) {
+	key := s.addKey(c, s.AdminUserTag(c))
+	results, err := s.api.ListAPIKeys(params.Entities{
+		Entities: []params.Entity{{Tag: s.Model.ModelTag().String()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.ListAPIKeysResult{{
+		Keys: []params.APIKeyInfo{{
+			Id:       key.Id(),
+			ModelTag: s.Model.ModelTag().String(),
+			OwnerTag: s.AdminUserTag(c).String(),
+			Access:   "read",
+			Created:  key.Created(),
+		}},
+	}})
+}
+
+func (s *apiKeyManagerSuite) TestRevokeAPIKeys(c *gc.C) {
+	key := s.addKey(c, s.AdminUserTag(c))
+	results, err := s.api.RevokeAPIKeys(params.RevokeAPIKeysArgs{
+		Ids: []string{key.Id(), "missing"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, "permission denied")
+
+	err = key.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(key.Revoked(), jc.IsTrue)
+}
+
+func (s *apiKeyManagerSuite) TestRevokeOwnAPIKey(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{NoModelUser: true})
+	s.Factory.MakeModelUser(c, &factory.ModelUserParams{
+		User:   user.Name(),
+		Access: permission.ReadAccess,
+	})
+	own := s.addKey(c, user.UserTag())
+	other := s.addKey(c, s.AdminUserTag(c))
+
+	s.authorizer.Tag = user.UserTag()
+	api, err := apikeymanager.NewAPIKeyManagerAPI(s.State, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := api.RevokeAPIKeys(params.RevokeAPIKeysArgs{
+		Ids: []string{own.Id(), other.Id()},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, "permission denied")
+}
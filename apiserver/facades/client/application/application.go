@@ -23,6 +23,7 @@ import (
 	goyaml "gopkg.in/yaml.v2"
 
 	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/common/networkingcommon"
 	"github.com/juju/juju/apiserver/common/storagecommon"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/facades/controller/caasoperatorprovisioner"
@@ -81,6 +82,14 @@ type APIv9 struct {
 // APIv10 provides the Application API facade for version 10.
 // It adds --force and --max-wait parameters to remove-saas.
 type APIv10 struct {
+	*APIv11
+}
+
+// APIv11 provides the Application API facade for version 11.
+// It adds idempotency keys to Deploy and AddUnits, per-endpoint expose
+// settings to Expose, and the SetEgressRules, EgressRules,
+// RotateUnitPasswords and SetSubordinatePolicies methods.
+type APIv11 struct {
 	*APIBase
 }
 
@@ -171,13 +180,21 @@ func NewFacadeV9(ctx facade.Context) (*APIv9, error) {
 }
 
 func NewFacadeV10(ctx facade.Context) (*APIv10, error) {
-	api, err := newFacadeBase(ctx)
+	api, err := NewFacadeV11(ctx)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 	return &APIv10{api}, nil
 }
 
+func NewFacadeV11(ctx facade.Context) (*APIv11, error) {
+	api, err := newFacadeBase(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIv11{api}, nil
+}
+
 func newFacadeBase(ctx facade.Context) (*APIBase, error) {
 	facadeModel, err := ctx.State().Model()
 	if err != nil {
@@ -352,8 +369,21 @@ func (api *APIv6) Deploy(args params.ApplicationsDeployV6) (params.ErrorResults,
 	return api.APIBase.Deploy(newArgs)
 }
 
+// Deploy is not able to take idempotency keys in version 10 of the
+// facade.
+func (api *APIv10) Deploy(args params.ApplicationsDeploy) (params.ErrorResults, error) {
+	for _, arg := range args.Applications {
+		if arg.IdempotencyKey != "" {
+			return params.ErrorResults{}, errors.NotSupportedf("idempotency keys")
+		}
+	}
+	return api.APIv11.Deploy(args)
+}
+
 // Deploy fetches the charms from the charm store and deploys them
-// using the specified placement directives.
+// using the specified placement directives. An application deployed
+// with an idempotency key is not deployed again by a retry with the
+// same key.
 func (api *APIBase) Deploy(args params.ApplicationsDeploy) (params.ErrorResults, error) {
 	if err := api.checkCanWrite(); err != nil {
 		return params.ErrorResults{}, errors.Trace(err)
@@ -366,7 +396,10 @@ func (api *APIBase) Deploy(args params.ApplicationsDeploy) (params.ErrorResults,
 	}
 
 	for i, arg := range args.Applications {
-		err := deployApplication(api.backend, api.model, api.stateCharm, arg, api.deployApplicationFunc, api.storagePoolManager, api.registry, api.storageValidator)
+		_, _, err := common.CallIdempotently(api.backend, api.authorizer.GetAuthTag(), "deploy", arg.IdempotencyKey, func() ([]string, error) {
+			err := deployApplication(api.backend, api.model, api.stateCharm, arg, api.deployApplicationFunc, api.storagePoolManager, api.registry, api.storageValidator)
+			return nil, errors.Trace(err)
+		})
 		result.Results[i].Error = common.ServerError(err)
 
 		if err != nil && len(arg.Resources) != 0 {
@@ -510,28 +543,6 @@ func deployApplication(
 		if err != nil {
 			return errors.Trace(err)
 		}
-		storageClassName, _ := cfg.AllAttrs()[k8s.OperatorStorageKey].(string)
-		if storageClassName == "" {
-			return errors.New(
-				"deploying a Kubernetes application requires a suitable storage class.\n" +
-					"None have been configured. Set the operator-storage model config to " +
-					"specify which storage class should be used to allocate operator storage.\n" +
-					"See https://discourse.jujucharms.com/t/getting-started/152.",
-			)
-		}
-		sp, err := caasoperatorprovisioner.CharmStorageParams("", storageClassName, cfg, "", storagePoolManager, registry)
-		if err != nil {
-			return errors.Annotatef(err, "getting operator storage params for %q", args.ApplicationName)
-		}
-		if sp.Provider != string(k8s.K8s_ProviderType) {
-			poolName := cfg.AllAttrs()[k8s.OperatorStorageKey]
-			return errors.Errorf(
-				"the %q storage pool requires a provider type of %q, not %q", poolName, k8s.K8s_ProviderType, sp.Provider)
-		}
-		if err := storageValidator.ValidateStorageClass(sp.Attributes); err != nil {
-			return errors.Trace(err)
-		}
-
 		workloadStorageClass, _ := cfg.AllAttrs()[k8s.WorkloadStorageKey].(string)
 		for storageName, cons := range args.Storage {
 			if cons.Pool == "" && workloadStorageClass == "" {
@@ -549,6 +560,14 @@ func deployApplication(
 	if err := checkMachinePlacement(backend, args); err != nil {
 		return errors.Trace(err)
 	}
+	if modelType == state.ModelTypeIAAS {
+		// Check the bound spaces can be used where the units' machines
+		// will be started, rather than leave them failing to provision.
+		zones := networkingcommon.PlacementZones(args.Constraints.Zones, args.Placement)
+		if err := networkingcommon.ValidateBindingsFeasible(backend, args.EndpointBindings, zones); err != nil {
+			return errors.Annotatef(err, "cannot deploy %q", args.ApplicationName)
+		}
+	}
 
 	// Try to find the charm URL in state first.
 	ch, err := backend.Charm(curl)
@@ -594,6 +613,14 @@ func deployApplication(
 	if err != nil {
 		return errors.Trace(err)
 	}
+	// Applications whose charm runs alongside the workload have no operator.
+	provisioningMode := applicationConfig.Attributes().GetString(k8s.ProvisioningModeConfigKey, "")
+	if modelType == state.ModelTypeCAAS && provisioningMode != string(caas.ProvisioningModeSidecar) {
+		operatorStorage := applicationConfig.Attributes().GetString(k8s.OperatorStorageConfigKey, "")
+		if err := checkOperatorStorage(model, args.ApplicationName, operatorStorage, storagePoolManager, registry, storageValidator); err != nil {
+			return errors.Trace(err)
+		}
+	}
 
 	var settings = make(charm.Settings)
 	if len(charmYamlConfig) > 0 {
@@ -646,6 +673,62 @@ func deployApplication(
 	return errors.Trace(err)
 }
 
+// checkOperatorStorage checks that the operator of a Kubernetes
+// application can be given storage, using the given storage class or
+// pool if there is one, and the model's operator storage otherwise.
+func checkOperatorStorage(
+	model Model,
+	appName string,
+	storageClassName string,
+	storagePoolManager poolmanager.PoolManager,
+	registry storage.ProviderRegistry,
+	storageValidator caas.StorageValidator,
+) error {
+	cfg, err := model.ModelConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if storageClassName == "" {
+		storageClassName, _ = cfg.AllAttrs()[k8s.OperatorStorageKey].(string)
+	}
+	if storageClassName == "" {
+		return errors.New(
+			"deploying a Kubernetes application requires a suitable storage class.\n" +
+				"None have been configured. Set the operator-storage model config to " +
+				"specify which storage class should be used to allocate operator storage.\n" +
+				"See https://discourse.jujucharms.com/t/getting-started/152.",
+		)
+	}
+	sp, err := caasoperatorprovisioner.CharmStorageParams("", storageClassName, cfg, "", storagePoolManager, registry)
+	if err != nil {
+		return errors.Annotatef(err, "getting operator storage params for %q", appName)
+	}
+	if sp.Provider != string(k8s.K8s_ProviderType) {
+		return errors.Errorf(
+			"the %q storage pool requires a provider type of %q, not %q", storageClassName, k8s.K8s_ProviderType, sp.Provider)
+	}
+	return errors.Trace(storageValidator.ValidateStorageClass(sp.Attributes))
+}
+
+// checkProvisioningModeUnchanged returns an error if the given changes
+// to an application's config change where its charm runs, which can
+// only be chosen when the application is deployed.
+func checkProvisioningModeUnchanged(app Application, changes map[string]interface{}) error {
+	mode, ok := changes[k8s.ProvisioningModeConfigKey]
+	if !ok {
+		return nil
+	}
+	appConfig, err := app.ApplicationConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	currentMode := appConfig.GetString(k8s.ProvisioningModeConfigKey, string(caas.ProvisioningModeOperator))
+	if mode != currentMode {
+		return errors.NotValidf("changing %s of a deployed application", k8s.ProvisioningModeConfigKey)
+	}
+	return nil
+}
+
 // checkMachinePlacement does a non-exhaustive validation of any supplied
 // placement directives.
 // If the placement scope is for a machine, ensure that the machine exists.
@@ -1177,8 +1260,17 @@ func (api *APIBase) CharmRelations(p params.ApplicationCharmRelations) (params.A
 	return results, nil
 }
 
+// Expose on version 10 and earlier always exposes the whole
+// application.
+func (api *APIv10) Expose(args params.ApplicationExpose) error {
+	args.ExposedEndpoints = nil
+	return api.APIv11.Expose(args)
+}
+
 // Expose changes the juju-managed firewall to expose any ports that
-// were also explicitly marked by units as open.
+// were also explicitly marked by units as open. If expose settings
+// are given for specific endpoints, they are merged into any existing
+// settings.
 func (api *APIBase) Expose(args params.ApplicationExpose) error {
 	if err := api.checkCanWrite(); err != nil {
 		return errors.Trace(err)
@@ -1191,6 +1283,9 @@ func (api *APIBase) Expose(args params.ApplicationExpose) error {
 		return errors.Trace(err)
 	}
 	if api.modelType == state.ModelTypeCAAS {
+		if len(args.ExposedEndpoints) > 0 {
+			return errors.NotSupportedf("exposing endpoints on a kubernetes model")
+		}
 		appConfig, err := app.ApplicationConfig()
 		if err != nil {
 			return errors.Trace(err)
@@ -1201,7 +1296,43 @@ func (api *APIBase) Expose(args params.ApplicationExpose) error {
 					"juju config %s %s=<value>", caas.JujuExternalHostNameKey, args.ApplicationName, caas.JujuExternalHostNameKey)
 		}
 	}
-	return app.SetExposed()
+	if len(args.ExposedEndpoints) == 0 {
+		return app.SetExposed()
+	}
+	exposedEndpoints, err := api.exposedEndpointsFromParams(args.ExposedEndpoints)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return app.MergeExposeSettings(exposedEndpoints)
+}
+
+// exposedEndpointsFromParams converts the given expose settings to their
+// state form, mapping space names to space IDs.
+func (api *APIBase) exposedEndpointsFromParams(in map[string]params.ExposedEndpoint) (map[string]state.ExposedEndpoint, error) {
+	spaces, err := api.backend.AllSpaces()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	spaceIDs := make(map[string]string, len(spaces))
+	for _, space := range spaces {
+		spaceIDs[space.Name()] = space.Id()
+	}
+	out := make(map[string]state.ExposedEndpoint, len(in))
+	for endpoint, exposed := range in {
+		var ids []string
+		for _, name := range exposed.ExposeToSpaces {
+			id, ok := spaceIDs[name]
+			if !ok {
+				return nil, errors.NotFoundf("space %q", name)
+			}
+			ids = append(ids, id)
+		}
+		out[endpoint] = state.ExposedEndpoint{
+			ExposeToSpaceIDs: ids,
+			ExposeToCIDRs:    exposed.ExposeToCIDRs,
+		}
+	}
+	return out, nil
 }
 
 // Unexpose changes the juju-managed firewall to unexpose any ports that
@@ -1232,7 +1363,18 @@ func (api *APIv5) AddUnits(args params.AddApplicationUnitsV5) (params.AddApplica
 	})
 }
 
-// AddUnits adds a given number of units to an application.
+// AddUnits is not able to take idempotency keys in version 10 of the
+// facade.
+func (api *APIv10) AddUnits(args params.AddApplicationUnits) (params.AddApplicationUnitsResults, error) {
+	if args.IdempotencyKey != "" {
+		return params.AddApplicationUnitsResults{}, errors.NotSupportedf("idempotency keys")
+	}
+	return api.APIv11.AddUnits(args)
+}
+
+// AddUnits adds a given number of units to an application. If an
+// idempotency key is given, a retry with the same key returns the
+// units already added instead of adding more.
 func (api *APIBase) AddUnits(args params.AddApplicationUnits) (params.AddApplicationUnitsResults, error) {
 	if api.modelType == state.ModelTypeCAAS {
 		return params.AddApplicationUnitsResults{}, errors.NotSupportedf("adding units on a non-container model")
@@ -1243,14 +1385,20 @@ func (api *APIBase) AddUnits(args params.AddApplicationUnits) (params.AddApplica
 	if err := api.check.ChangeAllowed(); err != nil {
 		return params.AddApplicationUnitsResults{}, errors.Trace(err)
 	}
-	units, err := addApplicationUnits(api.backend, api.modelType, args)
+	unitNames, _, err := common.CallIdempotently(api.backend, api.authorizer.GetAuthTag(), "add-unit", args.IdempotencyKey, func() ([]string, error) {
+		units, err := addApplicationUnits(api.backend, api.modelType, args)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		unitNames := make([]string, len(units))
+		for i, unit := range units {
+			unitNames[i] = unit.UnitTag().Id()
+		}
+		return unitNames, nil
+	})
 	if err != nil {
 		return params.AddApplicationUnitsResults{}, errors.Trace(err)
 	}
-	unitNames := make([]string, len(units))
-	for i, unit := range units {
-		unitNames[i] = unit.UnitTag().Id()
-	}
 	return params.AddApplicationUnitsResults{Units: unitNames}, nil
 }
 
@@ -2164,6 +2312,9 @@ func (api *APIBase) setApplicationConfig(arg params.ApplicationConfigSet) error
 	}
 
 	if len(appConfigAttrs) > 0 {
+		if err := checkProvisioningModeUnchanged(app, appConfigAttrs); err != nil {
+			return errors.Trace(err)
+		}
 		if err := app.UpdateApplicationConfig(appConfigAttrs, nil, configSchema, defaults); err != nil {
 			return errors.Annotate(err, "updating application config values")
 		}
@@ -2249,6 +2400,16 @@ func (api *APIBase) unsetApplicationConfig(arg params.ApplicationUnset) error {
 	}
 
 	if len(appConfigKeys) > 0 {
+		// Unsetting the provisioning mode reverts it to the default.
+		reset := make(map[string]interface{})
+		for _, key := range appConfigKeys {
+			if key == k8s.ProvisioningModeConfigKey {
+				reset[key] = string(caas.ProvisioningModeOperator)
+			}
+		}
+		if err := checkProvisioningModeUnchanged(app, reset); err != nil {
+			return errors.Trace(err)
+		}
 		if err := app.UpdateApplicationConfig(nil, appConfigKeys, configSchema, defaults); err != nil {
 			return errors.Annotate(err, "updating application config values")
 		}
@@ -2311,6 +2472,40 @@ func (api *APIBase) ResolveUnitErrors(p params.UnitsResolved) (params.ErrorResul
 	return result, nil
 }
 
+// RotateUnitPasswords isn't on the v10 API.
+func (u *APIv10) RotateUnitPasswords(_, _ struct{}) {}
+
+// RotateUnitPasswords requests that the agents of the given units be
+// given new passwords. The deployer responsible for each unit writes
+// the new password to the unit agent's configuration, sets it on the
+// controller and restarts the agent.
+func (api *APIBase) RotateUnitPasswords(args params.Entities) (params.ErrorResults, error) {
+	var result params.ErrorResults
+	if err := api.checkCanWrite(); err != nil {
+		return result, errors.Trace(err)
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+
+	result.Results = make([]params.ErrorResult, len(args.Entities))
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		unit, err := api.backend.Unit(tag.Id())
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		err = unit.RequestPasswordRotation()
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
 // ApplicationInfo isn't on the v8 API.
 func (u *APIv8) ApplicationInfo(_, _ struct{}) {}
 
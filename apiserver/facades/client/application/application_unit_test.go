@@ -19,6 +19,7 @@ import (
 
 	apitesting "github.com/juju/juju/api/testing"
 	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/common/networkingcommon"
 	"github.com/juju/juju/apiserver/facades/client/application"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
@@ -32,6 +33,7 @@ import (
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/environs"
+	jujunetwork "github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/storage"
 	"github.com/juju/juju/storage/provider"
@@ -54,7 +56,7 @@ type ApplicationSuite struct {
 	env              environs.Environ
 	blockChecker     mockBlockChecker
 	authorizer       apiservertesting.FakeAuthorizer
-	api              *application.APIv10
+	api              *application.APIv11
 	deployParams     map[string]application.DeployApplicationParams
 }
 
@@ -84,7 +86,7 @@ func (s *ApplicationSuite) setAPIUser(c *gc.C, user names.UserTag) {
 		s.storageValidator,
 	)
 	c.Assert(err, jc.ErrorIsNil)
-	s.api = &application.APIv10{api}
+	s.api = &application.APIv11{api}
 }
 
 func (s *ApplicationSuite) SetUpTest(c *gc.C) {
@@ -701,6 +703,81 @@ func (s *ApplicationSuite) TestDeployAttachStorage(c *gc.C) {
 	c.Assert(results.Results[2].Error, gc.ErrorMatches, `"volume-baz-0" is not a valid volume tag`)
 }
 
+func (s *ApplicationSuite) TestDeployBindingsFeasibility(c *gc.C) {
+	s.backend.spaces = []networkingcommon.BackingSpace{&apiservertesting.FakeSpace{
+		SpaceId:   "1",
+		SpaceName: "db",
+		SubnetIds: []string{"10.0.1.0/24"},
+		NextErr:   func() error { return nil },
+	}}
+	args := params.ApplicationsDeploy{
+		Applications: []params.ApplicationDeploy{{
+			ApplicationName:  "foo",
+			CharmURL:         "local:foo-0",
+			NumUnits:         1,
+			Constraints:      constraints.MustParse("zones=bar"),
+			EndpointBindings: map[string]string{"db": "db"},
+		}, {
+			ApplicationName:  "bar",
+			CharmURL:         "local:bar-1",
+			NumUnits:         1,
+			Constraints:      constraints.MustParse("zones=bar"),
+			Placement:        []*instance.Placement{{Scope: coretesting.ModelTag.Id(), Directive: "zone=foo"}},
+			EndpointBindings: map[string]string{"db": "db"},
+		}, {
+			ApplicationName:  "baz",
+			CharmURL:         "local:baz-2",
+			NumUnits:         1,
+			EndpointBindings: map[string]string{"db": "dmz"},
+		}},
+	}
+	results, err := s.api.Deploy(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `cannot deploy "bar": endpoint bindings cannot be satisfied: `+
+		`endpoint "db" bound to space "db": space has no subnets in availability zone "foo"; choose other zones or bind to another space`)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `cannot deploy "baz": endpoint bindings cannot be satisfied: `+
+		`endpoint "db" bound to space "dmz": space not found`)
+	c.Assert(s.deployParams, gc.HasLen, 1)
+}
+
+func (s *ApplicationSuite) TestDeployIdempotencyKey(c *gc.C) {
+	args := params.ApplicationsDeploy{
+		Applications: []params.ApplicationDeploy{{
+			ApplicationName: "foo",
+			CharmURL:        "local:foo-0",
+			NumUnits:        1,
+			IdempotencyKey:  "key-1",
+		}},
+	}
+	results, err := s.api.Deploy(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), jc.ErrorIsNil)
+	c.Assert(s.deployParams, gc.HasLen, 1)
+
+	// A retry succeeds without deploying the application again.
+	delete(s.deployParams, "foo")
+	results, err = s.api.Deploy(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), jc.ErrorIsNil)
+	c.Assert(s.deployParams, gc.HasLen, 0)
+}
+
+func (s *ApplicationSuite) TestDeployIdempotencyKeyV10(c *gc.C) {
+	api := &application.APIv10{s.api}
+	_, err := api.Deploy(params.ApplicationsDeploy{
+		Applications: []params.ApplicationDeploy{{
+			ApplicationName: "foo",
+			CharmURL:        "local:foo-0",
+			NumUnits:        1,
+			IdempotencyKey:  "key-1",
+		}},
+	})
+	c.Assert(err, gc.ErrorMatches, "idempotency keys not supported")
+	c.Assert(s.deployParams, gc.HasLen, 0)
+}
+
 func (s *ApplicationSuite) TestDeployCAASModel(c *gc.C) {
 	s.model.modelType = state.ModelTypeCAAS
 	s.backend.charm = &mockCharm{
@@ -803,6 +880,58 @@ func (s *ApplicationSuite) TestDeployCAASModelWrongOperatorStorageType(c *gc.C)
 	c.Assert(strings.Replace(msg, "\n", "", -1), gc.Matches, `the "k8s-operator-storage" storage pool requires a provider type of "kubernetes", not "rootfs"`)
 }
 
+func (s *ApplicationSuite) TestDeployCAASModelApplicationOperatorStorage(c *gc.C) {
+	s.model.modelType = state.ModelTypeCAAS
+	delete(s.model.cfg, "operator-storage")
+	args := params.ApplicationsDeploy{
+		Applications: []params.ApplicationDeploy{{
+			ApplicationName: "foo",
+			CharmURL:        "local:foo-0",
+			NumUnits:        1,
+			Config:          map[string]string{"kubernetes-operator-storage": "fast"},
+		}},
+	}
+	result, err := s.api.Deploy(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), jc.ErrorIsNil)
+	s.storagePoolManager.CheckCall(c, 0, "Get", "fast")
+	c.Assert(s.deployParams["foo"].ApplicationConfig.Attributes()["kubernetes-operator-storage"], gc.Equals, "fast")
+}
+
+func (s *ApplicationSuite) TestDeployCAASModelSidecarNoOperatorStorage(c *gc.C) {
+	s.model.modelType = state.ModelTypeCAAS
+	delete(s.model.cfg, "operator-storage")
+	args := params.ApplicationsDeploy{
+		Applications: []params.ApplicationDeploy{{
+			ApplicationName: "foo",
+			CharmURL:        "local:foo-0",
+			NumUnits:        1,
+			Config:          map[string]string{"kubernetes-provisioning-mode": "sidecar"},
+		}},
+	}
+	result, err := s.api.Deploy(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), jc.ErrorIsNil)
+	s.storagePoolManager.CheckNoCalls(c)
+	c.Assert(s.deployParams["foo"].ApplicationConfig.Attributes()["kubernetes-provisioning-mode"], gc.Equals, "sidecar")
+}
+
+func (s *ApplicationSuite) TestDeployCAASModelWrongApplicationOperatorStorageType(c *gc.C) {
+	s.model.modelType = state.ModelTypeCAAS
+	s.storagePoolManager.storageType = provider.RootfsProviderType
+	args := params.ApplicationsDeploy{
+		Applications: []params.ApplicationDeploy{{
+			ApplicationName: "foo",
+			CharmURL:        "local:foo-0",
+			NumUnits:        1,
+			Config:          map[string]string{"kubernetes-operator-storage": "fast"},
+		}},
+	}
+	result, err := s.api.Deploy(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), gc.ErrorMatches, `the "fast" storage pool requires a provider type of "kubernetes", not "rootfs"`)
+}
+
 func (s *ApplicationSuite) TestDeployCAASModelInvalidStorage(c *gc.C) {
 	s.storageValidator.SetErrors(errors.NotFoundf("storage class"))
 	s.model.modelType = state.ModelTypeCAAS
@@ -854,6 +983,36 @@ func (s *ApplicationSuite) TestAddUnits(c *gc.C) {
 	app.addedUnit.CheckCall(c, 0, "AssignWithPolicy", state.AssignCleanEmpty)
 }
 
+func (s *ApplicationSuite) TestAddUnitsIdempotencyKey(c *gc.C) {
+	args := params.AddApplicationUnits{
+		ApplicationName: "postgresql",
+		NumUnits:        1,
+		IdempotencyKey:  "key-1",
+	}
+	results, err := s.api.AddUnits(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Units, jc.DeepEquals, []string{"postgresql/99"})
+
+	// A retry returns the unit already added.
+	results, err = s.api.AddUnits(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Units, jc.DeepEquals, []string{"postgresql/99"})
+	app := s.backend.applications["postgresql"]
+	app.CheckCallNames(c, "AddUnit")
+}
+
+func (s *ApplicationSuite) TestAddUnitsIdempotencyKeyV10(c *gc.C) {
+	api := &application.APIv10{s.api}
+	_, err := api.AddUnits(params.AddApplicationUnits{
+		ApplicationName: "postgresql",
+		NumUnits:        1,
+		IdempotencyKey:  "key-1",
+	})
+	c.Assert(err, gc.ErrorMatches, "idempotency keys not supported")
+	app := s.backend.applications["postgresql"]
+	app.CheckNoCalls(c)
+}
+
 func (s *ApplicationSuite) TestAddUnitsCAASModel(c *gc.C) {
 	application.SetModelType(s.api, state.ModelTypeCAAS)
 	_, err := s.api.AddUnits(params.AddApplicationUnits{
@@ -1535,6 +1694,36 @@ func (s *ApplicationSuite) TestSetApplicationConfigBranch(c *gc.C) {
 	s.backend.generation.CheckCall(c, 0, "AssignApplication", "postgresql")
 }
 
+func (s *ApplicationSuite) TestSetApplicationConfigProvisioningMode(c *gc.C) {
+	application.SetModelType(s.api, state.ModelTypeCAAS)
+	result, err := s.api.SetApplicationsConfig(params.ApplicationConfigSetArgs{
+		Args: []params.ApplicationConfigSet{{
+			ApplicationName: "postgresql",
+			Config: map[string]string{
+				"kubernetes-provisioning-mode": "sidecar",
+			},
+		}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), gc.ErrorMatches, "changing kubernetes-provisioning-mode of a deployed application not valid")
+	app := s.backend.applications["postgresql"]
+	app.CheckCallNames(c, "ApplicationConfig")
+}
+
+func (s *ApplicationSuite) TestSetApplicationConfigProvisioningModeUnchanged(c *gc.C) {
+	application.SetModelType(s.api, state.ModelTypeCAAS)
+	result, err := s.api.SetApplicationsConfig(params.ApplicationConfigSetArgs{
+		Args: []params.ApplicationConfigSet{{
+			ApplicationName: "postgresql",
+			Config: map[string]string{
+				"kubernetes-provisioning-mode": "operator",
+			},
+		}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), jc.ErrorIsNil)
+	app := s.backend.applications["postgresql"]
+	app.CheckCallNames(c, "ApplicationConfig", "UpdateApplicationConfig")
+}
+
 func (s *ApplicationSuite) TestBlockSetApplicationConfig(c *gc.C) {
 	s.blockChecker.SetErrors(errors.New("blocked"))
 	_, err := s.api.SetApplicationsConfig(params.ApplicationConfigSetArgs{})
@@ -1579,6 +1768,20 @@ func (s *ApplicationSuite) TestUnsetApplicationConfig(c *gc.C) {
 	app.CheckCall(c, 1, "UpdateCharmConfig", "new-branch", charm.Settings{"stringVal": nil})
 }
 
+func (s *ApplicationSuite) TestUnsetApplicationConfigProvisioningMode(c *gc.C) {
+	application.SetModelType(s.api, state.ModelTypeCAAS)
+	app := s.backend.applications["postgresql"]
+	app.config = coreapplication.ConfigAttributes{"kubernetes-provisioning-mode": "sidecar"}
+	result, err := s.api.UnsetApplicationsConfig(params.ApplicationConfigUnsetArgs{
+		Args: []params.ApplicationUnset{{
+			ApplicationName: "postgresql",
+			Options:         []string{"kubernetes-provisioning-mode"},
+		}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), gc.ErrorMatches, "changing kubernetes-provisioning-mode of a deployed application not valid")
+	app.CheckCallNames(c, "ApplicationConfig")
+}
+
 func (s *ApplicationSuite) TestBlockUnsetApplicationConfig(c *gc.C) {
 	s.blockChecker.SetErrors(errors.New("blocked"))
 	_, err := s.api.UnsetApplicationsConfig(params.ApplicationConfigUnsetArgs{})
@@ -1617,6 +1820,39 @@ func (s *ApplicationSuite) TestResolveUnitErrors(c *gc.C) {
 	}
 }
 
+func (s *ApplicationSuite) TestRotateUnitPasswords(c *gc.C) {
+	result, err := s.api.RotateUnitPasswords(params.Entities{
+		Entities: []params.Entity{{Tag: "unit-postgresql-0"}, {Tag: "application-postgresql"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 2)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, `"application-postgresql" is not a valid unit tag`)
+
+	unit := s.backend.applications["postgresql"].units[0]
+	unit.CheckCallNames(c, "RequestPasswordRotation")
+	s.backend.applications["postgresql"].units[1].CheckNoCalls(c)
+}
+
+func (s *ApplicationSuite) TestBlockRotateUnitPasswords(c *gc.C) {
+	s.blockChecker.SetErrors(errors.New("blocked"))
+	_, err := s.api.RotateUnitPasswords(params.Entities{
+		Entities: []params.Entity{{Tag: "unit-postgresql-0"}},
+	})
+	c.Assert(err, gc.ErrorMatches, "blocked")
+	s.blockChecker.CheckCallNames(c, "ChangeAllowed")
+	s.backend.applications["postgresql"].units[0].CheckNoCalls(c)
+}
+
+func (s *ApplicationSuite) TestRotateUnitPasswordsPermissionDenied(c *gc.C) {
+	s.setAPIUser(c, names.NewUserTag("fred"))
+	_, err := s.api.RotateUnitPasswords(params.Entities{
+		Entities: []params.Entity{{Tag: "unit-postgresql-0"}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	s.backend.applications["postgresql"].units[0].CheckNoCalls(c)
+}
+
 func (s *ApplicationSuite) TestResolveUnitErrorsAll(c *gc.C) {
 	p := params.UnitsResolved{
 		All:   true,
@@ -1674,6 +1910,146 @@ func (s *ApplicationSuite) TestCAASExposeWithHostname(c *gc.C) {
 	app.CheckCallNames(c, "ApplicationConfig", "SetExposed")
 }
 
+func (s *ApplicationSuite) TestExposeEndpoints(c *gc.C) {
+	s.backend.spaces = []networkingcommon.BackingSpace{&apiservertesting.FakeSpace{
+		SpaceId:   "1",
+		SpaceName: "db",
+		NextErr:   func() error { return nil },
+	}}
+	err := s.api.Expose(params.ApplicationExpose{
+		ApplicationName: "postgresql",
+		ExposedEndpoints: map[string]params.ExposedEndpoint{
+			"db": {
+				ExposeToSpaces: []string{"db"},
+				ExposeToCIDRs:  []string{"10.0.0.0/24"},
+			},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	app := s.backend.applications["postgresql"]
+	app.CheckCallNames(c, "MergeExposeSettings")
+	app.CheckCall(c, 0, "MergeExposeSettings", map[string]state.ExposedEndpoint{
+		"db": {
+			ExposeToSpaceIDs: []string{"1"},
+			ExposeToCIDRs:    []string{"10.0.0.0/24"},
+		},
+	})
+}
+
+func (s *ApplicationSuite) TestExposeEndpointsUnknownSpace(c *gc.C) {
+	err := s.api.Expose(params.ApplicationExpose{
+		ApplicationName: "postgresql",
+		ExposedEndpoints: map[string]params.ExposedEndpoint{
+			"db": {ExposeToSpaces: []string{"missing"}},
+		},
+	})
+	c.Assert(err, gc.ErrorMatches, `space "missing" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	s.backend.applications["postgresql"].CheckNoCalls(c)
+}
+
+func (s *ApplicationSuite) TestCAASExposeEndpoints(c *gc.C) {
+	application.SetModelType(s.api, state.ModelTypeCAAS)
+	err := s.api.Expose(params.ApplicationExpose{
+		ApplicationName: "postgresql",
+		ExposedEndpoints: map[string]params.ExposedEndpoint{
+			"": {ExposeToCIDRs: []string{"10.0.0.0/24"}},
+		},
+	})
+	c.Assert(err, gc.ErrorMatches, "exposing endpoints on a kubernetes model not supported")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *ApplicationSuite) TestExposeV10IgnoresEndpoints(c *gc.C) {
+	api := &application.APIv10{s.api}
+	err := api.Expose(params.ApplicationExpose{
+		ApplicationName: "postgresql",
+		ExposedEndpoints: map[string]params.ExposedEndpoint{
+			"": {ExposeToCIDRs: []string{"10.0.0.0/24"}},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.backend.applications["postgresql"].CheckCallNames(c, "SetExposed")
+}
+
+func (s *ApplicationSuite) TestSetEgressRules(c *gc.C) {
+	results, err := s.api.SetEgressRules(params.ApplicationEgressRulesArgs{
+		Args: []params.ApplicationEgressRules{{
+			ApplicationTag: "application-postgresql",
+			Rules: []params.EgressRule{{
+				PortRange:        params.PortRange{FromPort: 443, ToPort: 443, Protocol: "tcp"},
+				DestinationCIDRs: []string{"10.0.0.0/8"},
+			}},
+		}, {
+			ApplicationTag: "application-redis",
+			Rules: []params.EgressRule{{
+				PortRange:        params.PortRange{FromPort: 443, ToPort: 443, Protocol: "tcp"},
+				DestinationCIDRs: []string{"10.0.0/8"},
+			}},
+		}, {
+			ApplicationTag: "application-wordpress",
+		}, {
+			ApplicationTag: "unit-postgresql-0",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 4)
+	c.Check(results.Results[0].Error, gc.IsNil)
+	c.Check(results.Results[1].Error, gc.ErrorMatches, "invalid CIDR address: 10.0.0/8")
+	c.Check(results.Results[2].Error, gc.ErrorMatches, `application "wordpress" not found`)
+	c.Check(results.Results[3].Error, gc.ErrorMatches, `"unit-postgresql-0" is not a valid application tag`)
+
+	app := s.backend.applications["postgresql"]
+	app.CheckCall(c, 0, "SetEgressRules", []jujunetwork.EgressRule{
+		jujunetwork.MustNewEgressRule("tcp", 443, 443, "10.0.0.0/8"),
+	})
+	s.backend.applications["redis"].CheckNoCalls(c)
+}
+
+func (s *ApplicationSuite) TestSetEgressRulesCAASModel(c *gc.C) {
+	application.SetModelType(s.api, state.ModelTypeCAAS)
+	results, err := s.api.SetEgressRules(params.ApplicationEgressRulesArgs{
+		Args: []params.ApplicationEgressRules{{ApplicationTag: "application-postgresql"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, "egress rules on a kubernetes model not supported")
+	s.backend.applications["postgresql"].CheckNoCalls(c)
+}
+
+func (s *ApplicationSuite) TestBlockSetEgressRules(c *gc.C) {
+	s.blockChecker.SetErrors(errors.New("blocked"))
+	_, err := s.api.SetEgressRules(params.ApplicationEgressRulesArgs{})
+	c.Assert(err, gc.ErrorMatches, "blocked")
+	s.blockChecker.CheckCallNames(c, "ChangeAllowed")
+}
+
+func (s *ApplicationSuite) TestSetEgressRulesPermissionDenied(c *gc.C) {
+	s.setAPIUser(c, names.NewUserTag("fred"))
+	_, err := s.api.SetEgressRules(params.ApplicationEgressRulesArgs{
+		Args: []params.ApplicationEgressRules{{ApplicationTag: "application-postgresql"}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	s.backend.applications["postgresql"].CheckNoCalls(c)
+}
+
+func (s *ApplicationSuite) TestEgressRules(c *gc.C) {
+	s.backend.applications["postgresql"].egressRules = []jujunetwork.EgressRule{
+		jujunetwork.MustNewEgressRule("udp", 53, 53, "10.0.0.2/32"),
+	}
+	results, err := s.api.EgressRules(params.Entities{
+		Entities: []params.Entity{{Tag: "application-postgresql"}, {Tag: "application-redis"}, {Tag: "application-wordpress"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.ApplicationEgressRulesResult{{
+		Rules: []params.EgressRule{{
+			PortRange:        params.PortRange{FromPort: 53, ToPort: 53, Protocol: "udp"},
+			DestinationCIDRs: []string{"10.0.0.2/32"},
+		}},
+	}, {}, {
+		Error: &params.Error{Code: params.CodeNotFound, Message: `application "wordpress" not found`},
+	}})
+}
+
 func (s *ApplicationSuite) TestApplicationsInfoOne(c *gc.C) {
 	entities := []params.Entity{{Tag: "application-postgresql"}}
 	result, err := s.api.ApplicationsInfo(params.Entities{entities})
@@ -1744,3 +2120,51 @@ func (s *ApplicationSuite) TestApplicationsInfoMany(c *gc.C) {
 	app := s.backend.applications["postgresql"]
 	app.CheckCallNames(c, "CharmConfig", "Charm", "ApplicationConfig", "IsPrincipal", "Constraints", "Series", "Channel", "EndpointBindings", "IsPrincipal", "IsExposed", "IsRemote")
 }
+
+func (s *ApplicationSuite) TestSetSubordinatePolicies(c *gc.C) {
+	results, err := s.api.SetSubordinatePolicies(params.ApplicationSubordinatePolicies{
+		Policies: []params.ApplicationSubordinatePolicy{{
+			ApplicationTag:  "application-postgresql",
+			Principals:      []string{"wordpress"},
+			MachineSelector: map[string]string{"logging": "enabled"},
+			MaxPerMachine:   1,
+		}, {
+			ApplicationTag: "application-redis",
+			MaxPerMachine:  -1,
+		}, {
+			ApplicationTag: "application-wordpress",
+		}, {
+			ApplicationTag: "unit-postgresql-0",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 4)
+	c.Check(results.Results[0].Error, gc.IsNil)
+	c.Check(results.Results[1].Error, gc.ErrorMatches, "negative max per machine -1 not valid")
+	c.Check(results.Results[2].Error, gc.ErrorMatches, `application "wordpress" not found`)
+	c.Check(results.Results[3].Error, gc.ErrorMatches, `"unit-postgresql-0" is not a valid application tag`)
+
+	app := s.backend.applications["postgresql"]
+	app.CheckCall(c, 0, "SetSubordinatePolicy", state.SubordinatePolicy{
+		Principals:      []string{"wordpress"},
+		MachineSelector: map[string]string{"logging": "enabled"},
+		MaxPerMachine:   1,
+	})
+	s.backend.applications["redis"].CheckNoCalls(c)
+}
+
+func (s *ApplicationSuite) TestBlockSetSubordinatePolicies(c *gc.C) {
+	s.blockChecker.SetErrors(errors.New("blocked"))
+	_, err := s.api.SetSubordinatePolicies(params.ApplicationSubordinatePolicies{})
+	c.Assert(err, gc.ErrorMatches, "blocked")
+	s.blockChecker.CheckCallNames(c, "ChangeAllowed")
+}
+
+func (s *ApplicationSuite) TestSetSubordinatePoliciesPermissionDenied(c *gc.C) {
+	s.setAPIUser(c, names.NewUserTag("fred"))
+	_, err := s.api.SetSubordinatePolicies(params.ApplicationSubordinatePolicies{
+		Policies: []params.ApplicationSubordinatePolicy{{ApplicationTag: "application-postgresql"}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	s.backend.applications["postgresql"].CheckNoCalls(c)
+}
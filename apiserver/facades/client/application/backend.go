@@ -6,6 +6,7 @@ package application
 import (
 	"time"
 
+	"github.com/juju/errors"
 	"github.com/juju/schema"
 	"github.com/juju/version"
 	"gopkg.in/juju/charm.v6"
@@ -13,6 +14,7 @@ import (
 	"gopkg.in/juju/environschema.v1"
 	"gopkg.in/juju/names.v3"
 
+	"github.com/juju/juju/apiserver/common/networkingcommon"
 	"github.com/juju/juju/apiserver/common/storagecommon"
 	"github.com/juju/juju/core/application"
 	"github.com/juju/juju/core/constraints"
@@ -21,6 +23,7 @@ import (
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/environs/config"
+	jujunetwork "github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/tools"
 )
@@ -49,6 +52,13 @@ type Backend interface {
 	OfferConnectionForRelation(string) (OfferConnection, error)
 	SaveEgressNetworks(relationKey string, cidrs []string) (state.RelationNetworks, error)
 	Branch(string) (Generation, error)
+	StartIdempotentCall(user, operation, key string) (state.IdempotentCall, error)
+	CompleteIdempotentCall(user, key string, started time.Time, results []string) error
+	AbortIdempotentCall(user, key string, started time.Time) error
+
+	// AllSpaces returns the model's spaces, for checking that endpoint
+	// bindings can be satisfied before deploying.
+	AllSpaces() ([]networkingcommon.BackingSpace, error)
 }
 
 // BlockChecker defines the block-checking functionality required by
@@ -75,14 +85,17 @@ type Application interface {
 	Constraints() (constraints.Value, error)
 	Destroy() error
 	DestroyOperation() *state.DestroyApplicationOperation
+	EgressRules() []jujunetwork.EgressRule
 	EndpointBindings() (map[string]string, error)
 	Endpoints() ([]state.Endpoint, error)
 	IsExposed() bool
 	IsPrincipal() bool
 	IsRemote() bool
+	MergeExposeSettings(map[string]state.ExposedEndpoint) error
 	Series() string
 	SetCharm(state.SetCharmConfig) error
 	SetConstraints(constraints.Value) error
+	SetEgressRules([]jujunetwork.EgressRule) error
 	SetExposed() error
 	SetMetricCredentials([]byte) error
 	SetMinUnits(int) error
@@ -90,6 +103,7 @@ type Application interface {
 	UpdateCharmConfig(string, charm.Settings) error
 	UpdateApplicationConfig(application.ConfigAttributes, []string, environschema.Fields, schema.Defaults) error
 	SetScale(int, int64, bool) error
+	SetSubordinatePolicy(state.SubordinatePolicy) error
 	ChangeScale(int) (int, error)
 	AgentTools() (*tools.Tools, error)
 }
@@ -139,6 +153,7 @@ type Unit interface {
 	IsPrincipal() bool
 	Life() state.Life
 	Resolve(retryHooks bool) error
+	RequestPasswordRotation() error
 	AgentTools() (*tools.Tools, error)
 
 	AssignedMachineId() (string, error)
@@ -177,6 +192,14 @@ type stateShim struct {
 
 type ExternalController state.ExternalController
 
+func (s stateShim) AllSpaces() ([]networkingcommon.BackingSpace, error) {
+	backing, err := networkingcommon.NewStateShim(s.State)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return backing.AllSpaces()
+}
+
 func (s stateShim) SaveController(controllerInfo crossmodel.ControllerInfo, modelUUID string) (ExternalController, error) {
 	api := state.NewExternalControllers(s.State)
 	return api.Save(controllerInfo, modelUUID)
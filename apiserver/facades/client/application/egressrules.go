@@ -0,0 +1,103 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	jujunetwork "github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+)
+
+// SetEgressRules is not available before version 11.
+func (u *APIv10) SetEgressRules(_, _ struct{}) {}
+
+// EgressRules is not available before version 11.
+func (u *APIv10) EgressRules(_, _ struct{}) {}
+
+// SetEgressRules replaces the egress rules of the given applications.
+// The rules limit the outgoing traffic allowed from the instances
+// hosting the applications' units, on providers supporting egress
+// firewall rules.
+func (api *APIBase) SetEgressRules(args params.ApplicationEgressRulesArgs) (params.ErrorResults, error) {
+	if err := api.checkCanWrite(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	for i, arg := range args.Args {
+		err := api.setEgressRules(arg)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (api *APIBase) setEgressRules(arg params.ApplicationEgressRules) error {
+	if api.modelType == state.ModelTypeCAAS {
+		return errors.NotSupportedf("egress rules on a kubernetes model")
+	}
+	tag, err := names.ParseApplicationTag(arg.ApplicationTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	rules := make([]jujunetwork.EgressRule, len(arg.Rules))
+	for i, rule := range arg.Rules {
+		rules[i], err = jujunetwork.NewEgressRule(
+			rule.PortRange.Protocol, rule.PortRange.FromPort, rule.PortRange.ToPort, rule.DestinationCIDRs...)
+		if err != nil {
+			return errors.NewNotValid(err, "")
+		}
+	}
+	app, err := api.backend.Application(tag.Id())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return app.SetEgressRules(rules)
+}
+
+// EgressRules returns the egress rules of the given applications.
+func (api *APIBase) EgressRules(args params.Entities) (params.ApplicationEgressRulesResults, error) {
+	if err := api.checkCanRead(); err != nil {
+		return params.ApplicationEgressRulesResults{}, errors.Trace(err)
+	}
+	results := params.ApplicationEgressRulesResults{
+		Results: make([]params.ApplicationEgressRulesResult, len(args.Entities)),
+	}
+	for i, arg := range args.Entities {
+		tag, err := names.ParseApplicationTag(arg.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		app, err := api.backend.Application(tag.Id())
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].Rules = egressRulesToParams(app.EgressRules())
+	}
+	return results, nil
+}
+
+// egressRulesToParams converts egress rules to their API representation.
+func egressRulesToParams(rules []jujunetwork.EgressRule) []params.EgressRule {
+	if len(rules) == 0 {
+		return nil
+	}
+	result := make([]params.EgressRule, len(rules))
+	for i, rule := range rules {
+		result[i] = params.EgressRule{
+			PortRange:        params.FromNetworkPortRange(rule.PortRange),
+			DestinationCIDRs: rule.DestinationCIDRs,
+		}
+	}
+	return result
+}
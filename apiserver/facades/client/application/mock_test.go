@@ -21,6 +21,7 @@ import (
 	"gopkg.in/juju/names.v3"
 	"gopkg.in/macaroon.v2-unstable"
 
+	"github.com/juju/juju/apiserver/common/networkingcommon"
 	"github.com/juju/juju/apiserver/common/storagecommon"
 	"github.com/juju/juju/apiserver/facades/client/application"
 	"github.com/juju/juju/caas"
@@ -32,6 +33,7 @@ import (
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
+	jujunetwork "github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	statestorage "github.com/juju/juju/state/storage"
 	"github.com/juju/juju/storage"
@@ -102,6 +104,7 @@ type mockApplication struct {
 	exposed     bool
 	remote      bool
 	agentTools  *tools.Tools
+	egressRules []jujunetwork.EgressRule
 }
 
 func (m *mockApplication) Name() string {
@@ -237,11 +240,35 @@ func (a *mockApplication) SetExposed() error {
 	return a.NextErr()
 }
 
+func (a *mockApplication) MergeExposeSettings(exposedEndpoints map[string]state.ExposedEndpoint) error {
+	a.MethodCall(a, "MergeExposeSettings", exposedEndpoints)
+	return a.NextErr()
+}
+
 func (a *mockApplication) IsExposed() bool {
 	a.MethodCall(a, "IsExposed")
 	return a.exposed
 }
 
+func (a *mockApplication) SetSubordinatePolicy(policy state.SubordinatePolicy) error {
+	a.MethodCall(a, "SetSubordinatePolicy", policy)
+	return a.NextErr()
+}
+
+func (a *mockApplication) SetEgressRules(rules []jujunetwork.EgressRule) error {
+	a.MethodCall(a, "SetEgressRules", rules)
+	if err := a.NextErr(); err != nil {
+		return err
+	}
+	a.egressRules = rules
+	return nil
+}
+
+func (a *mockApplication) EgressRules() []jujunetwork.EgressRule {
+	a.MethodCall(a, "EgressRules")
+	return a.egressRules
+}
+
 func (a *mockApplication) IsRemote() bool {
 	a.MethodCall(a, "IsRemote")
 	return a.remote
@@ -343,6 +370,13 @@ type mockBackend struct {
 	controllers                map[string]crossmodel.ControllerInfo
 	machines                   map[string]*mockMachine
 	generation                 *mockGeneration
+	idempotentCalls            map[string]state.IdempotentCall
+	spaces                     []networkingcommon.BackingSpace
+}
+
+func (m *mockBackend) AllSpaces() ([]networkingcommon.BackingSpace, error) {
+	m.MethodCall(m, "AllSpaces")
+	return m.spaces, m.NextErr()
 }
 
 type mockFilesystemAccess struct {
@@ -637,6 +671,33 @@ func (m *mockBackend) Application(name string) (application.Application, error)
 	return app, nil
 }
 
+func (m *mockBackend) StartIdempotentCall(user, operation, key string) (state.IdempotentCall, error) {
+	m.MethodCall(m, "StartIdempotentCall", user, operation, key)
+	if call, ok := m.idempotentCalls[user+"#"+key]; ok {
+		return call, errors.AlreadyExistsf("idempotency key %q", key)
+	}
+	if m.idempotentCalls == nil {
+		m.idempotentCalls = make(map[string]state.IdempotentCall)
+	}
+	m.idempotentCalls[user+"#"+key] = state.IdempotentCall{User: user, Key: key, Operation: operation}
+	return m.idempotentCalls[user+"#"+key], nil
+}
+
+func (m *mockBackend) CompleteIdempotentCall(user, key string, started time.Time, results []string) error {
+	m.MethodCall(m, "CompleteIdempotentCall", user, key, started, results)
+	call := m.idempotentCalls[user+"#"+key]
+	call.Completed = true
+	call.Results = results
+	m.idempotentCalls[user+"#"+key] = call
+	return nil
+}
+
+func (m *mockBackend) AbortIdempotentCall(user, key string, started time.Time) error {
+	m.MethodCall(m, "AbortIdempotentCall", user, key, started)
+	delete(m.idempotentCalls, user+"#"+key)
+	return nil
+}
+
 func (m *mockBackend) ApplyOperation(op state.ModelOperation) error {
 	m.MethodCall(m, "ApplyOperation", op)
 	return m.NextErr()
@@ -776,6 +837,11 @@ func (u *mockUnit) Resolve(retryHooks bool) error {
 	return u.NextErr()
 }
 
+func (u *mockUnit) RequestPasswordRotation() error {
+	u.MethodCall(u, "RequestPasswordRotation")
+	return u.NextErr()
+}
+
 func (u *mockUnit) AssignedMachineId() (string, error) {
 	u.MethodCall(u, "AssignedMachineId")
 	return u.machineId, u.NextErr()
@@ -0,0 +1,57 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// SetSubordinatePolicies is not available before version 11.
+func (u *APIv10) SetSubordinatePolicies(_, _ struct{}) {}
+
+// SetSubordinatePolicies replaces the placement policies of the given
+// subordinate applications. A policy with no restrictions removes the
+// application's policy. Policies only affect subordinate units created
+// after they are set.
+func (api *APIBase) SetSubordinatePolicies(args params.ApplicationSubordinatePolicies) (params.ErrorResults, error) {
+	if err := api.checkCanWrite(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Policies)),
+	}
+	for i, arg := range args.Policies {
+		err := api.setSubordinatePolicy(arg)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (api *APIBase) setSubordinatePolicy(arg params.ApplicationSubordinatePolicy) error {
+	tag, err := names.ParseApplicationTag(arg.ApplicationTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	policy := state.SubordinatePolicy{
+		Principals:      arg.Principals,
+		MachineSelector: arg.MachineSelector,
+		MaxPerMachine:   arg.MaxPerMachine,
+	}
+	if err := policy.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	app, err := api.backend.Application(tag.Id())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return app.SetSubordinatePolicy(policy)
+}
@@ -0,0 +1,266 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package autoscaler implements the API used by external autoscalers to
+// plan and apply changes to the number of units of applications.
+package autoscaler
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.autoscaler")
+
+// API implements the Autoscaler facade.
+type API struct {
+	st         *state.State
+	model      *state.Model
+	authorizer facade.Authorizer
+	check      *common.BlockChecker
+}
+
+// NewAPI returns a new Autoscaler API facade.
+func NewAPI(
+	st *state.State,
+	resources facade.Resources,
+	authorizer facade.Authorizer,
+) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	model, err := st.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &API{
+		st:         st,
+		model:      model,
+		authorizer: authorizer,
+		check:      common.NewBlockChecker(st),
+	}, nil
+}
+
+func (api *API) checkPermission(access permission.Access) error {
+	ok, err := api.authorizer.HasPermission(access, api.model.ModelTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !ok {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+func (api *API) checkModelType() error {
+	if api.model.Type() != state.ModelTypeIAAS {
+		return errors.NotSupportedf("autoscaling applications on a %s model", api.model.Type())
+	}
+	return nil
+}
+
+// Plan returns, for each of the given applications, the units to add or
+// remove to bring it to the requested number of units. Nothing is
+// changed; the plans can be applied with Apply.
+func (api *API) Plan(args params.ScaleApplicationsParams) (params.ScalePlanResults, error) {
+	if err := api.checkPermission(permission.ReadAccess); err != nil {
+		return params.ScalePlanResults{}, errors.Trace(err)
+	}
+	if err := api.checkModelType(); err != nil {
+		return params.ScalePlanResults{}, errors.Trace(err)
+	}
+	results := params.ScalePlanResults{
+		Results: make([]params.ScalePlanResult, len(args.Applications)),
+	}
+	for i, arg := range args.Applications {
+		plan, err := api.plan(arg)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].Result = plan
+	}
+	return results, nil
+}
+
+func (api *API) plan(arg params.ScaleApplicationParams) (*params.ScalePlan, error) {
+	if arg.Scale < 0 {
+		return nil, errors.NotValidf("scale %d", arg.Scale)
+	}
+	tag, err := names.ParseApplicationTag(arg.ApplicationTag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	units, err := api.aliveUnits(tag.Id())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	plan := &params.ScalePlan{
+		ApplicationTag: arg.ApplicationTag,
+		CurrentScale:   len(units),
+		Scale:          arg.Scale,
+	}
+	if arg.Scale > len(units) {
+		plan.AddUnits = arg.Scale - len(units)
+	}
+	// The most recently added units are removed first.
+	for i := len(units) - 1; i >= arg.Scale; i-- {
+		plan.RemoveUnits = append(plan.RemoveUnits, units[i].Name())
+	}
+	return plan, nil
+}
+
+// aliveUnits returns the alive units of the named application, ordered
+// by unit number.
+func (api *API) aliveUnits(appName string) ([]*state.Unit, error) {
+	app, err := api.st.Application(appName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !app.IsPrincipal() {
+		return nil, errors.NotSupportedf("scaling subordinate application %q", appName)
+	}
+	all, err := app.AllUnits()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var units []*state.Unit
+	for _, unit := range all {
+		if unit.Life() == state.Alive {
+			units = append(units, unit)
+		}
+	}
+	sort.Slice(units, func(i, j int) bool {
+		return unitNumber(units[i].Name()) < unitNumber(units[j].Name())
+	})
+	return units, nil
+}
+
+func unitNumber(unitName string) int {
+	n, _ := strconv.Atoi(unitName[strings.LastIndex(unitName, "/")+1:])
+	return n
+}
+
+// Apply applies the given plans, as returned by Plan. No plan is applied
+// if any of the applications no longer has the number of units it had
+// when the plans were made, or any unit to be removed is no longer
+// alive; the plans should then be made again.
+//
+// If a token is given, and plans have already been applied with it,
+// they are not applied again; the units added and removed then are
+// returned instead. This allows an autoscaler to retry safely.
+func (api *API) Apply(args params.ApplyScalePlansParams) (params.ApplyScalePlansResult, error) {
+	if err := api.checkPermission(permission.WriteAccess); err != nil {
+		return params.ApplyScalePlansResult{}, errors.Trace(err)
+	}
+	if err := api.checkModelType(); err != nil {
+		return params.ApplyScalePlansResult{}, errors.Trace(err)
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return params.ApplyScalePlansResult{}, errors.Trace(err)
+	}
+	for _, plan := range args.Plans {
+		if len(plan.RemoveUnits) > 0 {
+			if err := api.check.RemoveAllowed(); err != nil {
+				return params.ApplyScalePlansResult{}, errors.Trace(err)
+			}
+			break
+		}
+	}
+
+	if args.Token != "" {
+		op, err := api.st.StartScaleOperation(args.Token)
+		if errors.IsAlreadyExists(err) {
+			if !op.Completed {
+				return params.ApplyScalePlansResult{}, errors.Errorf("scale plans with token %q are being applied", args.Token)
+			}
+			return params.ApplyScalePlansResult{
+				AddedUnits:     op.AddedUnits,
+				RemovedUnits:   op.RemovedUnits,
+				AlreadyApplied: true,
+			}, nil
+		} else if err != nil {
+			return params.ApplyScalePlansResult{}, errors.Trace(err)
+		}
+	}
+
+	result, err := api.apply(args.Plans)
+	if args.Token == "" {
+		return result, errors.Trace(err)
+	}
+	if err != nil {
+		if abortErr := api.st.AbortScaleOperation(args.Token); abortErr != nil {
+			logger.Errorf("%v", abortErr)
+		}
+		return result, errors.Trace(err)
+	}
+	err = api.st.CompleteScaleOperation(args.Token, result.AddedUnits, result.RemovedUnits)
+	return result, errors.Trace(err)
+}
+
+func (api *API) apply(plans []params.ScalePlan) (params.ApplyScalePlansResult, error) {
+	var result params.ApplyScalePlansResult
+	apps := make([]*state.Application, len(plans))
+	removeUnits := make([][]*state.Unit, len(plans))
+	for i, plan := range plans {
+		tag, err := names.ParseApplicationTag(plan.ApplicationTag)
+		if err != nil {
+			return result, errors.Trace(err)
+		}
+		units, err := api.aliveUnits(tag.Id())
+		if err != nil {
+			return result, errors.Trace(err)
+		}
+		if len(units) != plan.CurrentScale {
+			return result, errors.Errorf(
+				"application %q has %d units, not %d as planned",
+				tag.Id(), len(units), plan.CurrentScale,
+			)
+		}
+		byName := make(map[string]*state.Unit)
+		for _, unit := range units {
+			byName[unit.Name()] = unit
+		}
+		for _, name := range plan.RemoveUnits {
+			unit, ok := byName[name]
+			if !ok {
+				return result, errors.Errorf("unit %q of application %q is not alive", name, tag.Id())
+			}
+			removeUnits[i] = append(removeUnits[i], unit)
+		}
+		if apps[i], err = api.st.Application(tag.Id()); err != nil {
+			return result, errors.Trace(err)
+		}
+	}
+
+	for i, plan := range plans {
+		for n := 0; n < plan.AddUnits; n++ {
+			unit, err := apps[i].AddUnit(state.AddUnitParams{})
+			if err != nil {
+				return result, errors.Annotatef(err, "cannot add unit to application %q", apps[i].Name())
+			}
+			result.AddedUnits = append(result.AddedUnits, unit.Name())
+			if err := api.st.AssignUnit(unit, state.AssignCleanEmpty); err != nil {
+				return result, errors.Trace(err)
+			}
+		}
+		for _, unit := range removeUnits[i] {
+			if err := unit.Destroy(); err != nil {
+				return result, errors.Annotatef(err, "cannot remove unit %q", unit.Name())
+			}
+			result.RemovedUnits = append(result.RemovedUnits, unit.Name())
+		}
+	}
+	return result, nil
+}
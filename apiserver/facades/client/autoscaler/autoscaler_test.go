@@ -0,0 +1,161 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package autoscaler_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/facades/client/autoscaler"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type autoscalerSuite struct {
+	jujutesting.JujuConnSuite
+
+	api *autoscaler.API
+	app *state.Application
+}
+
+var _ = gc.Suite(&autoscalerSuite{})
+
+func (s *autoscalerSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	authorizer := apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	}
+	var err error
+	s.api, err = autoscaler.NewAPI(s.State, nil, authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	s.app = s.Factory.MakeApplication(c, nil)
+}
+
+func (s *autoscalerSuite) addUnits(c *gc.C, n int) {
+	for i := 0; i < n; i++ {
+		s.Factory.MakeUnit(c, &factory.UnitParams{Application: s.app})
+	}
+}
+
+func (s *autoscalerSuite) plan(c *gc.C, scale int) params.ScalePlan {
+	results, err := s.api.Plan(params.ScaleApplicationsParams{
+		Applications: []params.ScaleApplicationParams{{
+			ApplicationTag: s.app.Tag().String(),
+			Scale:          scale,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	return *results.Results[0].Result
+}
+
+func (s *autoscalerSuite) TestPlanScaleUp(c *gc.C) {
+	s.addUnits(c, 1)
+	plan := s.plan(c, 3)
+	c.Assert(plan, jc.DeepEquals, params.ScalePlan{
+		ApplicationTag: s.app.Tag().String(),
+		CurrentScale:   1,
+		Scale:          3,
+		AddUnits:       2,
+	})
+}
+
+func (s *autoscalerSuite) TestPlanScaleDown(c *gc.C) {
+	s.addUnits(c, 3)
+	plan := s.plan(c, 1)
+	c.Assert(plan, jc.DeepEquals, params.ScalePlan{
+		ApplicationTag: s.app.Tag().String(),
+		CurrentScale:   3,
+		Scale:          1,
+		RemoveUnits:    []string{s.app.Name() + "/2", s.app.Name() + "/1"},
+	})
+}
+
+func (s *autoscalerSuite) TestPlanErrors(c *gc.C) {
+	results, err := s.api.Plan(params.ScaleApplicationsParams{
+		Applications: []params.ScaleApplicationParams{
+			{ApplicationTag: "application-foo", Scale: 1},
+			{ApplicationTag: s.app.Tag().String(), Scale: -1},
+			{ApplicationTag: "machine-0", Scale: 1},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Check(results.Results[0].Error, gc.ErrorMatches, `application "foo" not found`)
+	c.Check(results.Results[1].Error, gc.ErrorMatches, `scale -1 not valid`)
+	c.Check(results.Results[2].Error, gc.ErrorMatches, `"machine-0" is not a valid application tag`)
+}
+
+func (s *autoscalerSuite) TestApplyWithToken(c *gc.C) {
+	s.addUnits(c, 1)
+	args := params.ApplyScalePlansParams{
+		Token: "scale-1",
+		Plans: []params.ScalePlan{s.plan(c, 3)},
+	}
+	result, err := s.api.Apply(args)
+	c.Assert(err, jc.ErrorIsNil)
+	expectAdded := []string{s.app.Name() + "/1", s.app.Name() + "/2"}
+	c.Assert(result, jc.DeepEquals, params.ApplyScalePlansResult{
+		AddedUnits: expectAdded,
+	})
+	c.Assert(s.plan(c, 3).CurrentScale, gc.Equals, 3)
+
+	// Applying the plans again with the same token changes nothing.
+	result, err = s.api.Apply(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ApplyScalePlansResult{
+		AddedUnits:     expectAdded,
+		AlreadyApplied: true,
+	})
+	c.Assert(s.plan(c, 3).CurrentScale, gc.Equals, 3)
+}
+
+func (s *autoscalerSuite) TestApplyScaleDown(c *gc.C) {
+	s.addUnits(c, 3)
+	result, err := s.api.Apply(params.ApplyScalePlansParams{
+		Plans: []params.ScalePlan{s.plan(c, 1)},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ApplyScalePlansResult{
+		RemovedUnits: []string{s.app.Name() + "/2", s.app.Name() + "/1"},
+	})
+	c.Assert(s.plan(c, 1).CurrentScale, gc.Equals, 1)
+}
+
+func (s *autoscalerSuite) TestApplyStalePlan(c *gc.C) {
+	s.addUnits(c, 1)
+	plan := s.plan(c, 2)
+	// Another autoscaler gets there first.
+	s.addUnits(c, 1)
+
+	_, err := s.api.Apply(params.ApplyScalePlansParams{
+		Token: "scale-1",
+		Plans: []params.ScalePlan{plan},
+	})
+	c.Assert(err, gc.ErrorMatches, `application ".*" has 2 units, not 1 as planned`)
+	c.Assert(s.plan(c, 2).CurrentScale, gc.Equals, 2)
+
+	// The token can be used again with a fresh plan.
+	_, err = s.api.Apply(params.ApplyScalePlansParams{
+		Token: "scale-1",
+		Plans: []params.ScalePlan{s.plan(c, 3)},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.plan(c, 3).CurrentScale, gc.Equals, 3)
+}
+
+func (s *autoscalerSuite) TestApplyReadOnly(c *gc.C) {
+	authorizer := apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag("read"),
+	}
+	api, err := autoscaler.NewAPI(s.State, nil, authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = api.Apply(params.ApplyScalePlansParams{})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
@@ -9,8 +9,10 @@ import (
 
 // Filtering exports
 var (
-	MatchPortRanges = matchPortRanges
-	MatchSubnet     = matchSubnet
+	MatchPortRanges      = matchPortRanges
+	MatchSubnet          = matchSubnet
+	MatchApplicationName = matchApplicationName
+	ParseMachineRange    = parseMachineRange
 )
 
 func SetNewEnviron(c *Client, newEnviron func() (environs.BootstrapEnviron, error)) {
@@ -8,6 +8,7 @@ import (
 	"net"
 	"path"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/juju/errors"
@@ -73,6 +74,7 @@ func UnitChainPredicateFn(
 // BuildPredicate returns a Predicate which will evaluate a machine,
 // service, or unit against the given patterns.
 func BuildPredicateFor(patterns []string) Predicate {
+	kinds := classifyPatterns(patterns)
 
 	or := func(predicates ...closurePredicate) (bool, error) {
 		// Differentiate between a valid format that eliminated all
@@ -107,17 +109,40 @@ func BuildPredicateFor(patterns []string) Predicate {
 			}
 			return or(shims...)
 		case *state.Unit:
-			return or(buildUnitMatcherShims(i.(*state.Unit), patterns)...)
+			return or(buildUnitMatcherShims(i.(*state.Unit), patterns, kinds)...)
 		case *state.Application:
-			shims, err := buildApplicationMatcherShims(i.(*state.Application), patterns...)
-			if err != nil {
-				return false, err
-			}
-			return or(shims...)
+			return or(buildApplicationMatcherShims(i.(*state.Application), patterns, kinds, or)...)
 		}
 	}
 }
 
+// patternKinds records which unit attributes a set of patterns could
+// possibly match. Filtering a large model by name is then not slowed
+// down by looking up the status, ports and application of every unit.
+type patternKinds struct {
+	agentStatus    bool
+	workloadStatus bool
+	exposure       bool
+	ports          bool
+}
+
+func classifyPatterns(patterns []string) patternKinds {
+	var kinds patternKinds
+	for _, p := range patterns {
+		ps := status.Status(p)
+		kinds.agentStatus = kinds.agentStatus || ps.KnownAgentStatus()
+		kinds.workloadStatus = kinds.workloadStatus || ps.KnownWorkloadStatus()
+		// Port ranges are matched by prefix, and are either numeric
+		// or "icmp".
+		if p == "" || (p[0] >= '0' && p[0] <= '9') || strings.HasPrefix("icmp", p) {
+			kinds.ports = true
+		}
+	}
+	kinds.exposure = len(patterns) >= 1 && patterns[0] == "exposed" ||
+		len(patterns) >= 2 && patterns[0] == "not" && patterns[1] == "exposed"
+	return kinds
+}
+
 // Predicate is a function that when given a unit, machine, or
 // service, will determine whether the unit meets some criteria.
 type Predicate func(interface{}) (matches bool, _ error)
@@ -127,9 +152,41 @@ type Predicate func(interface{}) (matches bool, _ error)
 // matches some criteria.
 type closurePredicate func() (matches bool, formatOK bool, _ error)
 
+// machineRangePattern matches an inclusive range of top level machine
+// numbers, such as "3-7".
+var machineRangePattern = regexp.MustCompile(`^(\d+)-(\d+)$`)
+
+// parseMachineRange returns the bounds of the machine range pattern p,
+// and whether p is a valid machine range.
+func parseMachineRange(p string) (from, to int, ok bool) {
+	parts := machineRangePattern.FindStringSubmatch(p)
+	if parts == nil {
+		return 0, 0, false
+	}
+	from, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, false
+	}
+	to, err = strconv.Atoi(parts[2])
+	if err != nil || to < from {
+		return 0, 0, false
+	}
+	return from, to, true
+}
+
 func matchMachineId(m *state.Machine, patterns []string) (bool, bool, error) {
 	var anyValid bool
 	for _, p := range patterns {
+		if from, to, ok := parseMachineRange(p); ok {
+			anyValid = true
+			// The range matches top level machines, and so any
+			// containers they host.
+			n, err := strconv.Atoi(state.TopParentId(m.Id()))
+			if err == nil && n >= from && n <= to {
+				return true, true, nil
+			}
+			continue
+		}
 		if !names.IsValidMachine(p) {
 			continue
 		}
@@ -195,45 +252,63 @@ func unitMatchPort(u *state.Unit, patterns []string) (bool, bool, error) {
 }
 
 // buildApplicationMatcherShims adds matchers for application name, application units and
-// whether the application is exposed.
-func buildApplicationMatcherShims(a *state.Application, patterns ...string) (shims []closurePredicate, _ error) {
-	// Match on name.
-	shims = append(shims, func() (bool, bool, error) {
-		for _, p := range patterns {
-			if strings.ToLower(a.Name()) == strings.ToLower(p) {
-				return true, true, nil
-			}
-		}
-		return false, true, nil
-	})
+// whether the application is exposed. The application's units are only
+// fetched if neither its name nor its exposure match.
+func buildApplicationMatcherShims(
+	a *state.Application,
+	patterns []string,
+	kinds patternKinds,
+	or func(...closurePredicate) (bool, error),
+) []closurePredicate {
+	return []closurePredicate{
+		// Match on name, which may contain wildcards.
+		func() (bool, bool, error) { return matchApplicationName(patterns, a.Name()) },
 
-	// Match on exposure.
-	shims = append(shims, func() (bool, bool, error) { return matchExposure(patterns, a) })
+		// Match on exposure.
+		func() (bool, bool, error) { return matchExposure(patterns, a) },
 
-	// If the service has an unit instance that matches any of the
-	// given criteria, consider the service a match as well.
-	unitShims, err := buildShimsForUnit(a.AllUnits, patterns...)
-	if err != nil {
-		return nil, err
+		// If the service has an unit instance that matches any of the
+		// given criteria, consider the service a match as well.
+		func() (bool, bool, error) {
+			shims, err := buildShimsForUnit(a.AllUnits, patterns, kinds)
+			if err != nil {
+				return false, false, err
+			}
+			// Units may be able to match the pattern. Ultimately defer to
+			// that logic, and guard against breaking the predicate-chain.
+			if len(shims) <= 0 {
+				return false, true, nil
+			}
+			matches, err := or(shims...)
+			if err == InvalidFormatErr {
+				return false, false, nil
+			}
+			return matches, true, err
+		},
 	}
-	shims = append(shims, unitShims...)
+}
 
-	// Units may be able to match the pattern. Ultimately defer to
-	// that logic, and guard against breaking the predicate-chain.
-	if len(unitShims) <= 0 {
-		shims = append(shims, func() (bool, bool, error) { return false, true, nil })
+func matchApplicationName(patterns []string, name string) (bool, bool, error) {
+	name = strings.ToLower(name)
+	for _, p := range patterns {
+		if strings.Contains(p, "/") {
+			// Unit patterns are matched against the units.
+			continue
+		}
+		if ok, err := path.Match(strings.ToLower(p), name); err == nil && ok {
+			return true, true, nil
+		}
 	}
-
-	return shims, nil
+	return false, true, nil
 }
 
-func buildShimsForUnit(unitsFn func() ([]*state.Unit, error), patterns ...string) (shims []closurePredicate, _ error) {
+func buildShimsForUnit(unitsFn func() ([]*state.Unit, error), patterns []string, kinds patternKinds) (shims []closurePredicate, _ error) {
 	units, err := unitsFn()
 	if err != nil {
 		return nil, err
 	}
 	for _, u := range units {
-		shims = append(shims, buildUnitMatcherShims(u, patterns)...)
+		shims = append(shims, buildUnitMatcherShims(u, patterns, kinds)...)
 	}
 	return shims, nil
 }
@@ -266,17 +341,28 @@ func buildMachineMatcherShims(m *state.Machine, patterns []string) (shims []clos
 	return
 }
 
-func buildUnitMatcherShims(u *state.Unit, patterns []string) []closurePredicate {
+func buildUnitMatcherShims(u *state.Unit, patterns []string, kinds patternKinds) []closurePredicate {
 	closeOver := func(f func(*state.Unit, []string) (bool, bool, error)) closurePredicate {
 		return func() (bool, bool, error) { return f(u, patterns) }
 	}
-	return []closurePredicate{
-		closeOver(unitMatchUnitName),
-		closeOver(unitMatchAgentStatus),
-		closeOver(unitMatchWorkloadStatus),
-		closeOver(unitMatchExposure),
-		closeOver(unitMatchPort),
+	shims := []closurePredicate{closeOver(unitMatchUnitName)}
+	if kinds.agentStatus {
+		shims = append(shims, closeOver(unitMatchAgentStatus))
+	}
+	if kinds.workloadStatus {
+		shims = append(shims, closeOver(unitMatchWorkloadStatus))
+	}
+	if kinds.exposure {
+		shims = append(shims, closeOver(unitMatchExposure))
+	}
+	if kinds.ports {
+		shims = append(shims, closeOver(unitMatchPort))
+	} else {
+		// No pattern can match a port range, but ports are always
+		// a valid format to match against.
+		shims = append(shims, func() (bool, bool, error) { return false, true, nil })
 	}
+	return shims
 }
 
 func matchPortRanges(patterns []string, portRanges ...network.PortRange) (bool, bool, error) {
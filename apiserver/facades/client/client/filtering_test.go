@@ -57,3 +57,48 @@ func (s *filteringUnitTests) TestMatchSubnet(c *gc.C) {
 	c.Check(ok, jc.IsTrue)
 	c.Check(match, jc.IsTrue)
 }
+
+func (s *filteringUnitTests) TestMatchApplicationName(c *gc.C) {
+	match, ok, err := client.MatchApplicationName([]string{"nova-*"}, "nova-compute")
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(ok, jc.IsTrue)
+	c.Check(match, jc.IsTrue)
+
+	match, ok, err = client.MatchApplicationName([]string{"MySQL"}, "mysql")
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(ok, jc.IsTrue)
+	c.Check(match, jc.IsTrue)
+
+	match, ok, err = client.MatchApplicationName([]string{"nova-*"}, "neutron-api")
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(ok, jc.IsTrue)
+	c.Check(match, jc.IsFalse)
+
+	// Unit patterns are left to the units.
+	match, ok, err = client.MatchApplicationName([]string{"nova-*/*"}, "nova-compute")
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(ok, jc.IsTrue)
+	c.Check(match, jc.IsFalse)
+}
+
+func (s *filteringUnitTests) TestParseMachineRange(c *gc.C) {
+	for i, t := range []struct {
+		pattern  string
+		from, to int
+		ok       bool
+	}{
+		{pattern: "3-7", from: 3, to: 7, ok: true},
+		{pattern: "0-0", from: 0, to: 0, ok: true},
+		{pattern: "7-3"},
+		{pattern: "3"},
+		{pattern: "3-"},
+		{pattern: "0/lxd/0-2"},
+		{pattern: "nova-1"},
+	} {
+		c.Logf("test %d: %q", i, t.pattern)
+		from, to, ok := client.ParseMachineRange(t.pattern)
+		c.Check(ok, gc.Equals, t.ok)
+		c.Check(from, gc.Equals, t.from)
+		c.Check(to, gc.Equals, t.to)
+	}
+}
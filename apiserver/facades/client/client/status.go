@@ -4,6 +4,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strings"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/juju/collections/set"
 	"github.com/juju/errors"
+	"go.opencensus.io/trace"
 	"gopkg.in/juju/charm.v6"
 	"gopkg.in/juju/names.v3"
 
@@ -164,8 +166,30 @@ func (c *Client) StatusHistory(request params.StatusHistoryRequests) params.Stat
 	return results
 }
 
+// statusSpans records a trace span for each phase of fetching the
+// model's status, so that a slow status can be broken down.
+type statusSpans struct {
+	ctx  context.Context
+	span *trace.Span
+}
+
+// next ends the current phase's span, if any, and starts the span of
+// the named phase.
+func (s *statusSpans) next(phase string) {
+	s.end()
+	_, s.span = trace.StartSpan(s.ctx, "status."+phase)
+}
+
+// end ends the current phase's span, if any.
+func (s *statusSpans) end() {
+	if s.span != nil {
+		s.span.End()
+		s.span = nil
+	}
+}
+
 // FullStatus gives the information needed for juju status over the api
-func (c *Client) FullStatus(args params.StatusParams) (params.FullStatus, error) {
+func (c *Client) FullStatus(ctx context.Context, args params.StatusParams) (params.FullStatus, error) {
 	if err := c.checkCanRead(); err != nil {
 		return params.FullStatus{}, err
 	}
@@ -173,6 +197,10 @@ func (c *Client) FullStatus(args params.StatusParams) (params.FullStatus, error)
 	var noStatus params.FullStatus
 	var context statusContext
 
+	spans := statusSpans{ctx: ctx}
+	defer spans.end()
+
+	spans.next("model")
 	m, err := c.api.stateAccessor.Model()
 	if err != nil {
 		return noStatus, errors.Annotate(err, "cannot get model")
@@ -190,44 +218,55 @@ func (c *Client) FullStatus(args params.StatusParams) (params.FullStatus, error)
 	if context.status, err = context.model.LoadModelStatus(); err != nil {
 		return noStatus, errors.Annotate(err, "could not load model status values")
 	}
+	spans.next("applications")
 	if context.allAppsUnitsCharmBindings, err =
 		fetchAllApplicationsAndUnits(c.api.stateAccessor, context.model); err != nil {
 		return noStatus, errors.Annotate(err, "could not fetch applications and units")
 	}
+	spans.next("remote-applications")
 	if context.consumerRemoteApplications, err =
 		fetchConsumerRemoteApplications(c.api.stateAccessor); err != nil {
 		return noStatus, errors.Annotate(err, "could not fetch remote applications")
 	}
 	// Only admins can see offer details.
 	if err := c.checkIsAdmin(); err == nil {
+		spans.next("offers")
 		if context.offers, err =
 			fetchOffers(c.api.stateAccessor, context.allAppsUnitsCharmBindings.applications); err != nil {
 			return noStatus, errors.Annotate(err, "could not fetch application offers")
 		}
 	}
+	spans.next("machines")
 	if context.machines, err = fetchMachines(c.api.stateAccessor, nil); err != nil {
 		return noStatus, errors.Annotate(err, "could not fetch machines")
 	}
+	spans.next("controller-nodes")
 	if context.controllerNodes, err = fetchControllerNodes(c.api.stateAccessor); err != nil {
 		return noStatus, errors.Annotate(err, "could not fetch controller nodes")
 	}
 	// These may be empty when machines have not finished deployment.
+	spans.next("network-interfaces")
 	if context.ipAddresses, context.spaces, context.linkLayerDevices, err =
 		fetchNetworkInterfaces(c.api.stateAccessor); err != nil {
 		return noStatus, errors.Annotate(err, "could not fetch IP addresses and link layer devices")
 	}
+	spans.next("relations")
 	if context.relations, context.relationsById, err = fetchRelations(c.api.stateAccessor); err != nil {
 		return noStatus, errors.Annotate(err, "could not fetch relations")
 	}
 	if len(context.allAppsUnitsCharmBindings.applications) > 0 {
+		spans.next("leaders")
 		if context.leaders, err = c.api.leadershipReader.Leaders(); err != nil {
 			return noStatus, errors.Annotate(err, "could not fetch leaders")
 		}
 	}
+	spans.next("controller-timestamp")
 	if context.controllerTimestamp, err = c.api.stateAccessor.ControllerTimestamp(); err != nil {
 		return noStatus, errors.Annotate(err, "could not fetch controller timestamp")
 	}
+	spans.next("branches")
 	context.branches = fetchBranches(c.api.modelCache)
+	spans.end()
 
 	logger.Tracef("Applications: %v", context.allAppsUnitsCharmBindings.applications)
 	logger.Tracef("Remote applications: %v", context.consumerRemoteApplications)
@@ -341,17 +380,19 @@ func (c *Client) FullStatus(args params.StatusParams) (params.FullStatus, error)
 		}
 		// TODO(wallyworld) - filter remote applications
 
-		// Filter machines
+		// Filter machines. Each list holds a top level machine and
+		// all of its containers, so the machines hosting matched
+		// containers are found without going back to the database.
 		for status, machineList := range context.machines {
-			matched := make([]*state.Machine, 0, len(machineList))
+			hosting := set.NewStrings()
 			for _, m := range machineList {
-				machineContainers, err := m.Containers()
-				if err != nil {
-					return noStatus, err
+				if parentId, ok := m.ParentId(); ok && matchedMachines.Contains(m.Id()) {
+					hosting.Add(parentId)
 				}
-				machineContainersSet := set.NewStrings(machineContainers...)
-
-				if matchedMachines.Contains(m.Id()) || !matchedMachines.Intersection(machineContainersSet).IsEmpty() {
+			}
+			matched := make([]*state.Machine, 0, len(machineList))
+			for _, m := range machineList {
+				if matchedMachines.Contains(m.Id()) || hosting.Contains(m.Id()) {
 					// The machine is matched directly, or contains a unit
 					// or container that matches.
 					logger.Tracef("machine %s is hosting something.", m.Id())
@@ -366,6 +407,11 @@ func (c *Client) FullStatus(args params.StatusParams) (params.FullStatus, error)
 		context.branches = filterBranches(context.branches, matchedApps, matchedUnits.Union(set.NewStrings(args.Patterns...)))
 	}
 
+	page, err := context.paginate(args.Page)
+	if err != nil {
+		return noStatus, errors.Trace(err)
+	}
+
 	modelStatus, err := c.modelStatus()
 	if err != nil {
 		return noStatus, errors.Annotate(err, "cannot determine model status")
@@ -379,9 +425,70 @@ func (c *Client) FullStatus(args params.StatusParams) (params.FullStatus, error)
 		Relations:           context.processRelations(),
 		ControllerTimestamp: context.controllerTimestamp,
 		Branches:            context.processBranches(),
+		Page:                page,
 	}, nil
 }
 
+// paginate removes the applications, machines, offers and remote
+// applications outside the requested page from the context, along with
+// the relations of the applications removed. A relation is kept if any
+// of its applications are in the page, so it may appear in more than
+// one page.
+func (context *statusContext) paginate(page params.PageParams) (*params.PageInfo, error) {
+	const (
+		applicationPrefix       = "application/"
+		machinePrefix           = "machine/"
+		offerPrefix             = "offer/"
+		remoteApplicationPrefix = "remote-application/"
+	)
+	var keys []string
+	for name := range context.allAppsUnitsCharmBindings.applications {
+		keys = append(keys, applicationPrefix+name)
+	}
+	for id := range context.machines {
+		keys = append(keys, machinePrefix+id)
+	}
+	for name := range context.offers {
+		keys = append(keys, offerPrefix+name)
+	}
+	for name := range context.consumerRemoteApplications {
+		keys = append(keys, remoteApplicationPrefix+name)
+	}
+	sort.Strings(keys)
+	start, end, info, err := common.Paginate(page, keys)
+	if err != nil || info == nil {
+		return info, errors.Trace(err)
+	}
+
+	inPage := set.NewStrings(keys[start:end]...)
+	for name := range context.allAppsUnitsCharmBindings.applications {
+		if !inPage.Contains(applicationPrefix + name) {
+			delete(context.allAppsUnitsCharmBindings.applications, name)
+		}
+	}
+	for id := range context.machines {
+		if !inPage.Contains(machinePrefix + id) {
+			delete(context.machines, id)
+		}
+	}
+	for name := range context.offers {
+		if !inPage.Contains(offerPrefix + name) {
+			delete(context.offers, name)
+		}
+	}
+	for name := range context.consumerRemoteApplications {
+		if !inPage.Contains(remoteApplicationPrefix + name) {
+			delete(context.consumerRemoteApplications, name)
+		}
+	}
+	for name := range context.relations {
+		if !inPage.Contains(applicationPrefix+name) && !inPage.Contains(remoteApplicationPrefix+name) {
+			delete(context.relations, name)
+		}
+	}
+	return info, nil
+}
+
 func filterBranches(ctxBranches map[string]cache.Branch, matchedApps, matchedForBranches set.Strings) map[string]cache.Branch {
 	// Filter branches based on matchedApps which contains
 	// the application name if matching on application or unit.
@@ -883,6 +990,7 @@ func (c *statusContext) makeMachineStatus(machine *state.Machine, appStatusInfo
 	status.AgentStatus = agentStatus
 
 	status.Series = machine.Series()
+	status.Hostname = machine.Hostname()
 	status.Jobs = paramsJobsFromJobs(machine.Jobs())
 	node, wantsVote := c.controllerNodes[machineID]
 	status.WantsVote = wantsVote
@@ -1172,6 +1280,7 @@ func (context *statusContext) processApplication(application *state.Application)
 			sort.Sort(bySinceDescending(versions))
 			processedStatus.WorkloadVersion = versions[0].Message
 		}
+		processedStatus.WorkloadVersions, processedStatus.WorkloadVersionSummary = rollupWorkloadVersions(versions)
 	} else {
 		// We'll punt on using the docker image name.
 		caasModel, err := context.model.CAASModel()
@@ -1342,6 +1451,11 @@ func (context *statusContext) processUnit(unit *state.Unit, applicationCharm str
 	} else {
 		logger.Debugf("error fetching workload version: %v", err)
 	}
+	if versions, err := unit.WorkloadVersions(); err == nil {
+		result.WorkloadVersionHistory = processWorkloadVersionHistory(versions)
+	} else {
+		logger.Debugf("error fetching workload version history: %v", err)
+	}
 
 	result.AgentStatus, result.WorkloadStatus = context.processUnitAndAgentStatus(unit, expectWorkload)
 
@@ -1541,6 +1655,57 @@ func processLife(entity lifer) string {
 	return ""
 }
 
+// rollupWorkloadVersions counts the units running each of the given
+// workload versions, and summarises them, e.g. "3 units on 1.2.3, 2 on
+// 1.2.4". Nothing is returned unless the units report different versions.
+func rollupWorkloadVersions(versions []status.StatusInfo) (map[string]int, string) {
+	counts := make(map[string]int)
+	for _, version := range versions {
+		if version.Message != "" {
+			counts[version.Message]++
+		}
+	}
+	if len(counts) < 2 {
+		return nil, ""
+	}
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%d on %s", counts[name], name)
+	}
+	units := "units"
+	if counts[names[0]] == 1 {
+		units = "unit"
+	}
+	parts[0] = fmt.Sprintf("%d %s on %s", counts[names[0]], units, names[0])
+	return counts, strings.Join(parts, ", ")
+}
+
+// processWorkloadVersionHistory returns the non-empty workload versions
+// in the given history.
+func processWorkloadVersionHistory(versions []status.StatusInfo) []params.WorkloadVersionRecord {
+	var result []params.WorkloadVersionRecord
+	for _, version := range versions {
+		if version.Message == "" {
+			continue
+		}
+		result = append(result, params.WorkloadVersionRecord{
+			Version: version.Message,
+			Since:   version.Since,
+		})
+	}
+	return result
+}
+
 type bySinceDescending []status.StatusInfo
 
 // Len implements sort.Interface.
@@ -4,6 +4,7 @@
 package client_test
 
 import (
+	"context"
 	"time"
 
 	jc "github.com/juju/testing/checkers"
@@ -321,6 +322,46 @@ func (s *statusUnitTestSuite) TestWorkloadVersionLastWins(c *gc.C) {
 	checkUnitVersion(c, appStatus, unit3, "zarkon")
 }
 
+func (s *statusUnitTestSuite) TestWorkloadVersionRollup(c *gc.C) {
+	application := s.Factory.MakeApplication(c, nil)
+	addUnitWithVersion(c, application, "1.2.4")
+	addUnitWithVersion(c, application, "1.2.3")
+	addUnitWithVersion(c, application, "1.2.3")
+	addUnitWithVersion(c, application, "")
+
+	appStatus := s.checkAppVersion(c, application, "")
+	c.Check(appStatus.WorkloadVersions, jc.DeepEquals, map[string]int{
+		"1.2.3": 2,
+		"1.2.4": 1,
+	})
+	c.Check(appStatus.WorkloadVersionSummary, gc.Equals, "2 units on 1.2.3, 1 on 1.2.4")
+}
+
+func (s *statusUnitTestSuite) TestWorkloadVersionNoRollupWhenSame(c *gc.C) {
+	application := s.Factory.MakeApplication(c, nil)
+	addUnitWithVersion(c, application, "voltron")
+	addUnitWithVersion(c, application, "voltron")
+
+	appStatus := s.checkAppVersion(c, application, "voltron")
+	c.Check(appStatus.WorkloadVersions, gc.IsNil)
+	c.Check(appStatus.WorkloadVersionSummary, gc.Equals, "")
+}
+
+func (s *statusUnitTestSuite) TestWorkloadVersionHistory(c *gc.C) {
+	application := s.Factory.MakeApplication(c, nil)
+	unit := addUnitWithVersion(c, application, "voltron")
+	time.Sleep(time.Millisecond * 1)
+	err := unit.SetWorkloadVersion("zarkon")
+	c.Assert(err, jc.ErrorIsNil)
+
+	appStatus := s.checkAppVersion(c, application, "zarkon")
+	history := appStatus.Units[unit.Name()].WorkloadVersionHistory
+	c.Assert(history, gc.HasLen, 2)
+	c.Check(history[0].Version, gc.Equals, "zarkon")
+	c.Check(history[1].Version, gc.Equals, "voltron")
+	c.Check(history[0].Since, gc.NotNil)
+}
+
 func (s *statusUnitTestSuite) TestWorkloadVersionSimple(c *gc.C) {
 	application := s.Factory.MakeApplication(c, nil)
 	unit1 := addUnitWithVersion(c, application, "voltron")
@@ -896,7 +937,7 @@ func (s *filteringBranchesSuite) TestFullStatusBranchNoFilter(c *gc.C) {
 
 	client := s.clientForTest(c)
 
-	status, err := client.FullStatus(params.StatusParams{})
+	status, err := client.FullStatus(context.Background(), params.StatusParams{})
 	c.Assert(err, jc.ErrorIsNil)
 	c.Logf("%#v", status.Branches)
 	b, ok := status.Branches["apple"]
@@ -912,7 +953,7 @@ func (s *filteringBranchesSuite) TestFullStatusBranchFilterUnit(c *gc.C) {
 
 	client := s.clientForTest(c)
 
-	status, err := client.FullStatus(params.StatusParams{
+	status, err := client.FullStatus(context.Background(), params.StatusParams{
 		Patterns: []string{s.appA + "/0"},
 	})
 	c.Assert(err, jc.ErrorIsNil)
@@ -930,7 +971,7 @@ func (s *filteringBranchesSuite) TestFullStatusBranchFilterApplication(c *gc.C)
 
 	client := s.clientForTest(c)
 
-	status, err := client.FullStatus(params.StatusParams{
+	status, err := client.FullStatus(context.Background(), params.StatusParams{
 		Patterns: []string{s.appB},
 	})
 	c.Assert(err, jc.ErrorIsNil)
@@ -949,7 +990,7 @@ func (s *filteringBranchesSuite) TestFullStatusBranchFilterSubordinateUnit(c *gc
 
 	client := s.clientForTest(c)
 
-	status, err := client.FullStatus(params.StatusParams{
+	status, err := client.FullStatus(context.Background(), params.StatusParams{
 		Patterns: []string{s.subB + "/0"},
 	})
 	c.Assert(err, jc.ErrorIsNil)
@@ -968,7 +1009,7 @@ func (s *filteringBranchesSuite) TestFullStatusBranchFilterTwoBranchesSubordinat
 
 	client := s.clientForTest(c)
 
-	status, err := client.FullStatus(params.StatusParams{
+	status, err := client.FullStatus(context.Background(), params.StatusParams{
 		Patterns: []string{s.appB + "/0"},
 	})
 	c.Assert(err, jc.ErrorIsNil)
@@ -982,6 +1023,44 @@ func (s *filteringBranchesSuite) TestFullStatusBranchFilterTwoBranchesSubordinat
 	c.Assert(status.Applications, gc.HasLen, 2)
 }
 
+func (s *filteringBranchesSuite) TestFullStatusPaged(c *gc.C) {
+	client := s.clientForTest(c)
+	all, err := client.FullStatus(context.Background(), params.StatusParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all.Page, gc.IsNil)
+	c.Assert(all.Applications, gc.HasLen, 3)
+	c.Assert(all.Relations, gc.HasLen, 1)
+
+	applications := make(map[string]params.ApplicationStatus)
+	machines := make(map[string]params.MachineStatus)
+	relations := make(map[int]params.RelationStatus)
+	args := params.StatusParams{Page: params.PageParams{Limit: 2}}
+	for pages := 1; ; pages++ {
+		c.Assert(pages <= len(all.Applications)+len(all.Machines), jc.IsTrue)
+		status, err := client.FullStatus(context.Background(), args)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(status.Page, gc.NotNil)
+		c.Assert(len(status.Applications)+len(status.Machines) <= 2, jc.IsTrue)
+		c.Assert(status.Model, jc.DeepEquals, all.Model)
+		for name, app := range status.Applications {
+			applications[name] = app
+		}
+		for id, m := range status.Machines {
+			machines[id] = m
+		}
+		for _, r := range status.Relations {
+			relations[r.Id] = r
+		}
+		if status.Page.NextCursor == "" {
+			break
+		}
+		args.Page.Cursor = status.Page.NextCursor
+	}
+	c.Assert(applications, jc.DeepEquals, all.Applications)
+	c.Assert(machines, jc.DeepEquals, all.Machines)
+	c.Assert(relations, gc.HasLen, 1)
+}
+
 func (s *filteringBranchesSuite) clientForTest(c *gc.C) *client.Client {
 	s.State.StartSync()
 	s.WaitForModelWatchersIdle(c, s.State.ModelUUID())
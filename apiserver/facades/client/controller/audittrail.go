@@ -0,0 +1,77 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// maxAuditRecords is the maximum number of audit records returned by a
+// single call to AuditRecords.
+const maxAuditRecords = 1000
+
+// AuditRecords returns the most recent API calls recorded in the audit
+// trail that match the given filter, newest first. At most 1000 records
+// are returned.
+func (c *ControllerAPI) AuditRecords(args params.AuditRecordsFilter) (params.AuditRecordsResult, error) {
+	if err := c.checkHasAdmin(); err != nil {
+		return params.AuditRecordsResult{}, errors.Trace(err)
+	}
+	filter := state.AuditRecordFilter{
+		From:  args.From,
+		To:    args.To,
+		Limit: args.Limit,
+	}
+	if filter.Limit <= 0 || filter.Limit > maxAuditRecords {
+		filter.Limit = maxAuditRecords
+	}
+	if args.UserTag != "" {
+		tag, err := names.ParseTag(args.UserTag)
+		if err != nil {
+			return params.AuditRecordsResult{}, errors.Trace(err)
+		}
+		filter.User = tag.String()
+	}
+	if args.ModelTag != "" {
+		tag, err := names.ParseModelTag(args.ModelTag)
+		if err != nil {
+			return params.AuditRecordsResult{}, errors.Trace(err)
+		}
+		filter.ModelUUID = tag.Id()
+	}
+	records, err := c.state.AuditRecords(filter)
+	if err != nil {
+		return params.AuditRecordsResult{}, errors.Trace(err)
+	}
+	result := params.AuditRecordsResult{
+		Records: make([]params.AuditRecord, len(records)),
+	}
+	for i, record := range records {
+		var modelTag string
+		if record.ModelUUID != "" {
+			modelTag = names.NewModelTag(record.ModelUUID).String()
+		}
+		result.Records[i] = params.AuditRecord{
+			ModelTag:     modelTag,
+			UserTag:      record.User,
+			ConnectionID: record.ConnectionID,
+			Facade:       record.Facade,
+			Version:      record.Version,
+			Method:       record.Method,
+			Args:         record.Args,
+			ErrorCode:    record.ErrorCode,
+			Error:        record.Error,
+			Time:         record.Time,
+			Duration:     record.Duration,
+		}
+	}
+	return result, nil
+}
+
+// AuditRecords isn't on the v8 API.
+func (c *ControllerAPIv8) AuditRecords(_, _ struct{}) {}
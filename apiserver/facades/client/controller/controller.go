@@ -10,6 +10,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/juju/clock"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/txn"
@@ -49,12 +50,21 @@ type ControllerAPI struct {
 	resources  facade.Resources
 	presence   facade.Presence
 	hub        facade.Hub
+	clock      clock.Clock
+}
+
+// ControllerAPIv8 provides the v8 Controller API. The only difference
+// between this and v9 is that v8 doesn't have the WatchModelSummaries,
+// ModelFeatures, UpdateModelFeatures, ModelStats, AuditRecords,
+// UpgradePreChecks and UpgradeStragglers methods.
+type ControllerAPIv8 struct {
+	*ControllerAPI
 }
 
 // ControllerAPIv7 provides the v7 Controller API. The only difference
 // between this and v8 is that v7 doesn't have the ControllerVersion method.
 type ControllerAPIv7 struct {
-	*ControllerAPI
+	*ControllerAPIv8
 }
 
 // ControllerAPIv6 provides the v6 Controller API. The only difference
@@ -81,8 +91,8 @@ type ControllerAPIv3 struct {
 	*ControllerAPIv4
 }
 
-// NewControllerAPIv8 creates a new ControllerAPIv7.
-func NewControllerAPIv8(ctx facade.Context) (*ControllerAPI, error) {
+// NewControllerAPIv9 creates a new ControllerAPIv9.
+func NewControllerAPIv9(ctx facade.Context) (*ControllerAPI, error) {
 	st := ctx.State()
 	authorizer := ctx.Auth()
 	pool := ctx.StatePool()
@@ -97,9 +107,19 @@ func NewControllerAPIv8(ctx facade.Context) (*ControllerAPI, error) {
 		resources,
 		presence,
 		hub,
+		clock.WallClock,
 	)
 }
 
+// NewControllerAPIv8 creates a new ControllerAPIv8.
+func NewControllerAPIv8(ctx facade.Context) (*ControllerAPIv8, error) {
+	v9, err := NewControllerAPIv9(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ControllerAPIv8{v9}, nil
+}
+
 // NewControllerAPIv7 creates a new ControllerAPIv7.
 func NewControllerAPIv7(ctx facade.Context) (*ControllerAPIv7, error) {
 	v8, err := NewControllerAPIv8(ctx)
@@ -154,6 +174,7 @@ func NewControllerAPI(
 	resources facade.Resources,
 	presence facade.Presence,
 	hub facade.Hub,
+	clock clock.Clock,
 ) (*ControllerAPI, error) {
 	if !authorizer.AuthClient() {
 		return nil, errors.Trace(common.ErrPerm)
@@ -187,6 +208,7 @@ func NewControllerAPI(
 		resources:  resources,
 		presence:   presence,
 		hub:        hub,
+		clock:      clock,
 	}, nil
 }
 
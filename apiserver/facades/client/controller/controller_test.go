@@ -16,6 +16,7 @@ import (
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v3"
 	"gopkg.in/macaroon.v2-unstable"
+	"gopkg.in/mgo.v2/bson"
 
 	"github.com/juju/juju/apiserver"
 	"github.com/juju/juju/apiserver/common"
@@ -64,7 +65,7 @@ func (s *controllerSuite) SetUpTest(c *gc.C) {
 	}
 	s.hub = pubsub.NewStructuredHub(nil)
 
-	controller, err := controller.NewControllerAPIv8(
+	controller, err := controller.NewControllerAPIv9(
 		facadetest.Context{
 			State_:     s.State,
 			StatePool_: s.StatePool,
@@ -372,6 +373,59 @@ func (s *controllerSuite) TestWatchAllModels(c *gc.C) {
 	}
 }
 
+func (s *controllerSuite) TestWatchModelSummaries(c *gc.C) {
+	result, err := s.controller.WatchModelSummaries(params.WatchModelSummariesArgs{
+		UserTag: s.Owner.String(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	w, ok := s.resources.Get(result.ModelSummaryWatcherId).(controller.ModelSummaryWatcher)
+	c.Assert(ok, jc.IsTrue)
+
+	select {
+	case next, ok := <-w.Changes():
+		c.Assert(ok, jc.IsTrue)
+		c.Assert(next.PeerErrors, gc.HasLen, 0)
+		c.Assert(next.Changes, gc.HasLen, 1)
+		change := next.Changes[0]
+		c.Check(change.ControllerTag, gc.Equals, s.State.ControllerTag().String())
+		c.Check(change.ModelTag, gc.Equals, s.Model.ModelTag().String())
+		c.Check(change.Summary.UUID, gc.Equals, s.State.ModelUUID())
+	case <-time.After(testing.LongWait):
+		c.Fatal("timed out")
+	}
+}
+
+func (s *controllerSuite) TestWatchModelSummariesOtherUserNonAdmin(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{NoModelUser: true})
+	anAuthoriser := apiservertesting.FakeAuthorizer{
+		Tag: user.Tag(),
+	}
+	endpoint, err := controller.NewControllerAPIv9(
+		facadetest.Context{
+			State_:     s.State,
+			StatePool_: s.StatePool,
+			Resources_: s.resources,
+			Auth_:      anAuthoriser,
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = endpoint.WatchModelSummaries(params.WatchModelSummariesArgs{
+		UserTag: s.Owner.String(),
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+}
+
+func (s *controllerSuite) TestWatchModelSummariesUnknownPeer(c *gc.C) {
+	_, err := s.controller.WatchModelSummaries(params.WatchModelSummariesArgs{
+		UserTag: s.Owner.String(),
+		Peers: []params.PeerControllerAuth{{
+			ControllerTag: names.NewControllerTag("deadbeef-2bad-500d-9000-4b1d0d06f00d").String(),
+		}},
+	})
+	c.Assert(err, gc.ErrorMatches, `peer controller "deadbeef-2bad-500d-9000-4b1d0d06f00d": .* not found`)
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+}
+
 func (s *controllerSuite) TestInitiateMigration(c *gc.C) {
 	// Create two hosted models to migrate.
 	st1 := s.Factory.MakeModel(c, nil)
@@ -982,6 +1036,292 @@ func (s *controllerSuite) TestConfigSetPublishesEvent(c *gc.C) {
 	c.Assert(config.Features().SortedValues(), jc.DeepEquals, []string{"bar", "foo"})
 }
 
+func (s *controllerSuite) TestUpdateModelFeatures(c *gc.C) {
+	done := make(chan struct{})
+	var msg pscontroller.ModelFeaturesChangedMessage
+	s.hub.Subscribe(pscontroller.ModelFeaturesChanged, func(topic string, data pscontroller.ModelFeaturesChangedMessage, err error) {
+		c.Check(err, jc.ErrorIsNil)
+		msg = data
+		close(done)
+	})
+
+	modelTag := s.Model.ModelTag().String()
+	results, err := s.controller.UpdateModelFeatures(params.UpdateModelFeaturesArgs{
+		Args: []params.UpdateModelFeaturesArg{
+			{ModelTag: modelTag, Enable: []string{"foo", "bar"}},
+			{ModelTag: "model-deadbeef-0bad-400d-8000-4b1d0d06f00d", Enable: []string{"foo"}},
+			{ModelTag: modelTag, Enable: []string{"baz"}, Disable: []string{"baz"}},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.NotNil)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `enabling and disabling feature flags \[baz\] not valid`)
+
+	select {
+	case <-done:
+	case <-time.After(testing.LongWait):
+		c.Fatal("no event sent")
+	}
+	c.Assert(msg, jc.DeepEquals, pscontroller.ModelFeaturesChangedMessage{
+		ModelUUID: s.Model.UUID(),
+		Features:  []string{"bar", "foo"},
+	})
+
+	features, err := s.controller.ModelFeatures(params.Entities{
+		Entities: []params.Entity{{Tag: modelTag}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(features, jc.DeepEquals, params.StringsResults{
+		Results: []params.StringsResult{{Result: []string{"bar", "foo"}}},
+	})
+}
+
+func (s *controllerSuite) TestUpdateModelFeaturesRequiresSuperUser(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{NoModelUser: true})
+	anAuthoriser := apiservertesting.FakeAuthorizer{
+		Tag: user.Tag(),
+	}
+	endpoint, err := controller.NewControllerAPIv9(
+		facadetest.Context{
+			State_:     s.State,
+			StatePool_: s.StatePool,
+			Resources_: s.resources,
+			Auth_:      anAuthoriser,
+			Hub_:       s.hub,
+		})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = endpoint.UpdateModelFeatures(params.UpdateModelFeaturesArgs{
+		Args: []params.UpdateModelFeaturesArg{{
+			ModelTag: s.Model.ModelTag().String(),
+			Enable:   []string{"foo"},
+		}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	_, err = endpoint.ModelFeatures(params.Entities{})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *controllerSuite) TestModelStats(c *gc.C) {
+	err := s.State.RecordModelStats(map[string][]state.CollectionStats{
+		s.Model.UUID(): {
+			{Collection: "units", Count: 3, Size: 1500},
+			{Collection: "machines", Count: 1, Size: 400},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	modelTag := s.Model.ModelTag().String()
+	results, err := s.controller.ModelStats(params.Entities{
+		Entities: []params.Entity{
+			{Tag: modelTag},
+			{Tag: "model-deadbeef-0bad-400d-8000-4b1d0d06f00d"},
+			{Tag: "machine-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	stats := results.Results[0].Result
+	c.Assert(stats, gc.NotNil)
+	c.Check(stats.ModelTag, gc.Equals, modelTag)
+	c.Check(stats.Updated.IsZero(), jc.IsFalse)
+	c.Check(stats.Collections, jc.DeepEquals, []params.CollectionStats{
+		{Collection: "machines", Count: 1, Size: 400},
+		{Collection: "units", Count: 3, Size: 1500},
+	})
+	c.Assert(results.Results[1].Error, jc.Satisfies, params.IsCodeNotFound)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `"machine-0" is not a valid model tag`)
+}
+
+func (s *controllerSuite) TestModelStatsRequiresSuperUser(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{NoModelUser: true})
+	anAuthoriser := apiservertesting.FakeAuthorizer{
+		Tag: user.Tag(),
+	}
+	endpoint, err := controller.NewControllerAPIv9(
+		facadetest.Context{
+			State_:     s.State,
+			StatePool_: s.StatePool,
+			Resources_: s.resources,
+			Auth_:      anAuthoriser,
+			Hub_:       s.hub,
+		})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = endpoint.ModelStats(params.Entities{})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *controllerSuite) TestAuditRecords(c *gc.C) {
+	start := time.Date(2019, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i, user := range []string{"user-bob", "user-mary"} {
+		err := s.State.AddAuditRecord(state.AuditRecord{
+			ModelUUID: s.Model.UUID(),
+			User:      user,
+			Facade:    "Application",
+			Version:   9,
+			Method:    "Deploy",
+			Time:      start.Add(time.Duration(i) * time.Minute),
+			Duration:  time.Second,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	result, err := s.controller.AuditRecords(params.AuditRecordsFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Records, gc.HasLen, 2)
+	c.Check(result.Records[0], jc.DeepEquals, params.AuditRecord{
+		ModelTag: s.Model.ModelTag().String(),
+		UserTag:  "user-mary",
+		Facade:   "Application",
+		Version:  9,
+		Method:   "Deploy",
+		Time:     start.Add(time.Minute),
+		Duration: time.Second,
+	})
+
+	result, err = s.controller.AuditRecords(params.AuditRecordsFilter{
+		UserTag:  "user-bob",
+		ModelTag: s.Model.ModelTag().String(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Records, gc.HasLen, 1)
+	c.Check(result.Records[0].UserTag, gc.Equals, "user-bob")
+
+	result, err = s.controller.AuditRecords(params.AuditRecordsFilter{
+		To: start.Add(time.Minute),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Records, gc.HasLen, 1)
+	c.Check(result.Records[0].UserTag, gc.Equals, "user-bob")
+}
+
+func (s *controllerSuite) TestAuditRecordsInvalidFilter(c *gc.C) {
+	_, err := s.controller.AuditRecords(params.AuditRecordsFilter{UserTag: "bob"})
+	c.Assert(err, gc.ErrorMatches, `"bob" is not a valid tag`)
+	_, err = s.controller.AuditRecords(params.AuditRecordsFilter{ModelTag: "machine-0"})
+	c.Assert(err, gc.ErrorMatches, `"machine-0" is not a valid model tag`)
+}
+
+func (s *controllerSuite) TestAuditRecordsRequiresSuperUser(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{NoModelUser: true})
+	anAuthoriser := apiservertesting.FakeAuthorizer{
+		Tag: user.Tag(),
+	}
+	endpoint, err := controller.NewControllerAPIv9(
+		facadetest.Context{
+			State_:     s.State,
+			StatePool_: s.StatePool,
+			Resources_: s.resources,
+			Auth_:      anAuthoriser,
+			Hub_:       s.hub,
+		})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = endpoint.AuditRecords(params.AuditRecordsFilter{})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *controllerSuite) TestUpgradePreChecks(c *gc.C) {
+	result, err := s.controller.UpgradePreChecks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Problems, gc.HasLen, 0)
+
+	annotations := s.State.MongoSession().DB("juju").C("annotations")
+	err = annotations.Insert(bson.M{
+		"_id":        "deadbeef:m#0",
+		"model-uuid": "deadbeef",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err = s.controller.UpgradePreChecks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Problems, jc.DeepEquals, []params.UpgradePreCheckProblem{{
+		Check:   "orphaned-docs",
+		Message: `1 documents in "annotations" belong to models that no longer exist`,
+	}})
+}
+
+func (s *controllerSuite) TestUpgradePreChecksRequiresSuperUser(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{NoModelUser: true})
+	anAuthoriser := apiservertesting.FakeAuthorizer{
+		Tag: user.Tag(),
+	}
+	endpoint, err := controller.NewControllerAPIv9(
+		facadetest.Context{
+			State_:     s.State,
+			StatePool_: s.StatePool,
+			Resources_: s.resources,
+			Auth_:      anAuthoriser,
+			Hub_:       s.hub,
+		})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = endpoint.UpgradePreChecks()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *controllerSuite) TestUpgradeStragglers(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	f := factory.NewFactory(st, s.StatePool)
+	m0 := f.MakeMachine(c, nil)
+	m1 := f.MakeMachine(c, nil)
+	m2 := f.MakeMachine(c, nil)
+
+	model, err := st.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	target, err := model.AgentVersion()
+	c.Assert(err, jc.ErrorIsNil)
+	err = st.SetUpgradeStepsCompleted(m0.Tag(), target)
+	c.Assert(err, jc.ErrorIsNil)
+	previous := target
+	previous.Minor--
+	err = st.SetUpgradeStepsCompleted(m1.Tag(), previous)
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.controller.UpgradeStragglers(params.Entities{
+		Entities: []params.Entity{
+			{Tag: model.ModelTag().String()},
+			{Tag: "machine-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	result := results.Results[0]
+	c.Assert(result.Error, gc.IsNil)
+	c.Check(result.ModelTag, gc.Equals, model.ModelTag().String())
+	c.Check(result.TargetVersion, gc.Equals, target)
+	c.Assert(result.Stragglers, gc.HasLen, 2)
+	c.Check(result.Stragglers[0].Tag, gc.Equals, m1.Tag().String())
+	c.Check(result.Stragglers[0].Version, gc.Equals, previous)
+	c.Check(result.Stragglers[0].Completed, gc.NotNil)
+	c.Check(result.Stragglers[1], jc.DeepEquals, params.UpgradeStraggler{Tag: m2.Tag().String()})
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `"machine-0" is not a valid model tag`)
+}
+
+func (s *controllerSuite) TestUpgradeStragglersRequiresSuperUser(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{NoModelUser: true})
+	anAuthoriser := apiservertesting.FakeAuthorizer{
+		Tag: user.Tag(),
+	}
+	endpoint, err := controller.NewControllerAPIv9(
+		facadetest.Context{
+			State_:     s.State,
+			StatePool_: s.StatePool,
+			Resources_: s.resources,
+			Auth_:      anAuthoriser,
+			Hub_:       s.hub,
+		})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = endpoint.UpgradeStragglers(params.Entities{})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
 func (s *controllerSuite) TestMongoVersion(c *gc.C) {
 	result, err := s.controller.MongoVersion()
 	c.Assert(err, jc.ErrorIsNil)
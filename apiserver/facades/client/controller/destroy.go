@@ -4,11 +4,13 @@
 package controller
 
 import (
+	"github.com/juju/clock"
 	"github.com/juju/errors"
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
+	corecontroller "github.com/juju/juju/controller"
 	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
 )
@@ -26,7 +28,7 @@ func (c *ControllerAPIv3) DestroyController(args params.DestroyControllerArgs) e
 	}
 	destroyStorage := true
 	args.DestroyStorage = &destroyStorage
-	return destroyController(c.state, c.statePool, c.authorizer, args)
+	return destroyController(c.state, c.statePool, c.authorizer, c.clock, args)
 }
 
 // DestroyController destroys the controller.
@@ -36,13 +38,14 @@ func (c *ControllerAPIv3) DestroyController(args params.DestroyControllerArgs) e
 // non-Dead hosted models, then an error with the code
 // params.CodeHasHostedModels will be transmitted.
 func (c *ControllerAPI) DestroyController(args params.DestroyControllerArgs) error {
-	return destroyController(c.state, c.statePool, c.authorizer, args)
+	return destroyController(c.state, c.statePool, c.authorizer, c.clock, args)
 }
 
 func destroyController(
 	st *state.State,
 	pool *state.StatePool,
 	authorizer facade.Authorizer,
+	clock clock.Clock,
 	args params.DestroyControllerArgs,
 ) error {
 	hasPermission, err := authorizer.HasPermission(permission.SuperuserAccess, st.ControllerTag())
@@ -52,6 +55,13 @@ func destroyController(
 	if !hasPermission {
 		return errors.Trace(common.ErrPerm)
 	}
+	cfg, err := st.ControllerConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := common.CheckOperationStepUpAuth(authorizer, cfg, corecontroller.StepUpDestroyController, clock.Now()); err != nil {
+		return errors.Trace(err)
+	}
 	if err := ensureNotBlocked(st); err != nil {
 		return errors.Trace(err)
 	}
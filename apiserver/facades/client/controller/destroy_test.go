@@ -4,6 +4,9 @@
 package controller_test
 
 import (
+	"time"
+
+	"github.com/juju/clock/testclock"
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -15,6 +18,7 @@ import (
 	"github.com/juju/juju/apiserver/facades/client/controller"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
+	corecontroller "github.com/juju/juju/controller"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing"
@@ -55,7 +59,7 @@ func (s *destroyControllerSuite) SetUpTest(c *gc.C) {
 	s.authorizer = apiservertesting.FakeAuthorizer{
 		Tag: s.AdminUserTag(c),
 	}
-	testController, err := controller.NewControllerAPIv8(
+	testController, err := controller.NewControllerAPIv9(
 		facadetest.Context{
 			State_:     s.State,
 			StatePool_: s.StatePool,
@@ -156,6 +160,78 @@ func (s *destroyControllerSuite) TestDestroyControllerNoHostedModels(c *gc.C) {
 	c.Assert(model.Life(), gc.Equals, state.Dying)
 }
 
+func (s *destroyControllerSuite) TestDestroyControllerRequiresSecondFactor(c *gc.C) {
+	err := s.State.UpdateControllerConfig(map[string]interface{}{
+		corecontroller.StepUpAuthMaxAge:     "5m",
+		corecontroller.StepUpAuthOperations: []string{corecontroller.StepUpDestroyController},
+		corecontroller.StepUpAuthRPID:       "juju.example.com",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.controller.DestroyController(params.DestroyControllerArgs{DestroyModels: true})
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrSecondFactorRequired)
+
+	model, err := s.State.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(model.Life(), gc.Equals, state.Alive)
+}
+
+func (s *destroyControllerSuite) TestDestroyControllerWithSecondFactor(c *gc.C) {
+	err := s.State.UpdateControllerConfig(map[string]interface{}{
+		corecontroller.StepUpAuthMaxAge:     "5m",
+		corecontroller.StepUpAuthOperations: []string{corecontroller.StepUpDestroyController},
+		corecontroller.StepUpAuthRPID:       "juju.example.com",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	authorizer := &apiservertesting.FakeStepUpAuthorizer{
+		FakeAuthorizer: s.authorizer,
+		AssertedAt:     time.Now(),
+	}
+	api, err := controller.NewControllerAPIv9(
+		facadetest.Context{
+			State_:     s.State,
+			StatePool_: s.StatePool,
+			Resources_: s.resources,
+			Auth_:      authorizer,
+		})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = api.DestroyController(params.DestroyControllerArgs{DestroyModels: true})
+	c.Assert(err, jc.ErrorIsNil)
+
+	model, err := s.State.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(model.Life(), gc.Equals, state.Dying)
+}
+
+func (s *destroyControllerSuite) TestDestroyControllerWithExpiredSecondFactor(c *gc.C) {
+	err := s.State.UpdateControllerConfig(map[string]interface{}{
+		corecontroller.StepUpAuthMaxAge:     "5m",
+		corecontroller.StepUpAuthOperations: []string{corecontroller.StepUpDestroyController},
+		corecontroller.StepUpAuthRPID:       "juju.example.com",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	clock := testclock.NewClock(time.Now())
+	authorizer := &apiservertesting.FakeStepUpAuthorizer{
+		FakeAuthorizer: s.authorizer,
+		AssertedAt:     clock.Now(),
+	}
+	api, err := controller.NewControllerAPI(
+		s.State, s.StatePool, authorizer, s.resources, nil, nil, clock,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	clock.Advance(10 * time.Minute)
+	err = api.DestroyController(params.DestroyControllerArgs{DestroyModels: true})
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrSecondFactorRequired)
+
+	model, err := s.State.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(model.Life(), gc.Equals, state.Alive)
+}
+
 func (s *destroyControllerSuite) TestDestroyControllerErrsOnNoHostedModelsWithBlock(c *gc.C) {
 	err := common.DestroyModel(common.NewModelManagerBackend(s.otherModel, s.StatePool), nil, nil, nil)
 	c.Assert(err, jc.ErrorIsNil)
@@ -0,0 +1,94 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/pubsub/controller"
+)
+
+// ModelFeatures returns the feature flags enabled for each of the
+// specified models, in addition to those enabled for the whole
+// controller.
+func (c *ControllerAPI) ModelFeatures(args params.Entities) (params.StringsResults, error) {
+	if err := c.checkHasAdmin(); err != nil {
+		return params.StringsResults{}, errors.Trace(err)
+	}
+	results := params.StringsResults{
+		Results: make([]params.StringsResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		features, err := c.modelFeatures(entity.Tag)
+		results.Results[i].Result = features
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (c *ControllerAPI) modelFeatures(tag string) ([]string, error) {
+	modelTag, err := names.ParseModelTag(tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	model, ph, err := c.statePool.GetModel(modelTag.Id())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer ph.Release()
+	return model.Features(), nil
+}
+
+// UpdateModelFeatures enables and disables feature flags for each of the
+// specified models, so that new behaviour can be rolled out to some
+// models before the whole controller. API servers are told of the
+// changes as they are made.
+func (c *ControllerAPI) UpdateModelFeatures(args params.UpdateModelFeaturesArgs) (params.ErrorResults, error) {
+	if err := c.checkHasAdmin(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	for i, arg := range args.Args {
+		err := c.updateModelFeatures(arg)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (c *ControllerAPI) updateModelFeatures(arg params.UpdateModelFeaturesArg) error {
+	modelTag, err := names.ParseModelTag(arg.ModelTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	model, ph, err := c.statePool.GetModel(modelTag.Id())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer ph.Release()
+	if err := model.UpdateFeatures(arg.Enable, arg.Disable); err != nil {
+		return errors.Trace(err)
+	}
+	logger.Infof(
+		"%s enabled features %v and disabled features %v of model %q",
+		c.apiUser.Id(), arg.Enable, arg.Disable, model.UUID(),
+	)
+	_, err = c.hub.Publish(controller.ModelFeaturesChanged, controller.ModelFeaturesChangedMessage{
+		ModelUUID: model.UUID(),
+		Features:  model.Features(),
+	})
+	return errors.Trace(err)
+}
+
+// Mask the new methods from the v9 API.
+
+// ModelFeatures isn't on the v8 API.
+func (c *ControllerAPIv8) ModelFeatures(_, _ struct{}) {}
+
+// UpdateModelFeatures isn't on the v8 API.
+func (c *ControllerAPIv8) UpdateModelFeatures(_, _ struct{}) {}
@@ -0,0 +1,58 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// ModelStats returns the number and approximate size of the documents
+// each of the specified models has in each collection, as last
+// collected by the controller, so that models approaching practical
+// database limits can be identified.
+func (c *ControllerAPI) ModelStats(args params.Entities) (params.ModelStatsResults, error) {
+	if err := c.checkHasAdmin(); err != nil {
+		return params.ModelStatsResults{}, errors.Trace(err)
+	}
+	results := params.ModelStatsResults{
+		Results: make([]params.ModelStatsResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		stats, err := c.modelStats(entity.Tag)
+		results.Results[i].Result = stats
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (c *ControllerAPI) modelStats(tag string) (*params.ModelStats, error) {
+	modelTag, err := names.ParseModelTag(tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	stats, err := c.state.ModelStats(modelTag.Id())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := &params.ModelStats{
+		ModelTag:    modelTag.String(),
+		Collections: make([]params.CollectionStats, len(stats.Collections)),
+		Updated:     stats.Updated,
+	}
+	for i, coll := range stats.Collections {
+		result.Collections[i] = params.CollectionStats{
+			Collection: coll.Collection,
+			Count:      coll.Count,
+			Size:       coll.Size,
+		}
+	}
+	return result, nil
+}
+
+// ModelStats isn't on the v8 API.
+func (c *ControllerAPIv8) ModelStats(_, _ struct{}) {}
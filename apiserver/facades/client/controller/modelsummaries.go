@@ -0,0 +1,382 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+	"gopkg.in/tomb.v2"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// ModelSummaryWatcher watches the summaries of the models that a user can
+// see, on the controller and its peers.
+type ModelSummaryWatcher interface {
+	Stop() error
+	Err() error
+	Changes() <-chan params.ModelSummaryWatcherNextResults
+}
+
+// summaryPollInterval is the period between fetches of the model
+// summaries by a model summary watcher.
+var summaryPollInterval = 10 * time.Second
+
+// openPeerAPI opens an API connection to a peer controller.
+var openPeerAPI = func(info *api.Info) (api.Connection, error) {
+	return api.Open(info, api.DefaultDialOpts())
+}
+
+// WatchModelSummaries starts watching the summaries of the models that
+// the given user can see on this controller and, on the user's behalf,
+// on the given peer controllers. The returned ModelSummaryWatcherId
+// should be used with Next on the ModelSummaryWatcher endpoint to
+// receive the changes. Controller admins can watch the models of any
+// user; other users can only watch their own.
+func (c *ControllerAPI) WatchModelSummaries(args params.WatchModelSummariesArgs) (params.ModelSummaryWatcherId, error) {
+	result := params.ModelSummaryWatcherId{}
+	userTag, err := names.ParseUserTag(args.UserTag)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	if userTag != c.apiUser {
+		if err := c.checkHasAdmin(); err != nil {
+			return result, errors.Trace(err)
+		}
+	}
+
+	local := &localSummaries{st: c.state, user: userTag, all: args.All}
+	peers := make([]summarySource, len(args.Peers))
+	externalControllers := state.NewExternalControllers(c.state)
+	for i, peer := range args.Peers {
+		source, err := newPeerSummaries(externalControllers, peer, args.All)
+		if err != nil {
+			return result, errors.Trace(err)
+		}
+		peers[i] = source
+	}
+	w := newModelSummaryWatcher(clock.WallClock, local, peers)
+	result.ModelSummaryWatcherId = c.resources.Register(w)
+	return result, nil
+}
+
+// WatchModelSummaries isn't on the v8 API.
+func (c *ControllerAPIv8) WatchModelSummaries(_, _ struct{}) {}
+
+// summarySource fetches the summaries of the models that a user can see
+// on one controller.
+type summarySource interface {
+	controllerTag() names.ControllerTag
+	fetch() ([]params.ModelSummary, error)
+	close()
+}
+
+// localSummaries is a summarySource for the models on this controller.
+type localSummaries struct {
+	st   *state.State
+	user names.UserTag
+	all  bool
+}
+
+func (s *localSummaries) controllerTag() names.ControllerTag {
+	return s.st.ControllerTag()
+}
+
+func (s *localSummaries) fetch() ([]params.ModelSummary, error) {
+	modelInfos, err := s.st.ModelSummariesForUser(s.user, s.all)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]params.ModelSummary, len(modelInfos))
+	for i, mi := range modelInfos {
+		result[i] = *common.ModelSummaryFromState(mi)
+	}
+	return result, nil
+}
+
+func (s *localSummaries) close() {}
+
+// peerSummaries is a summarySource for the models on a peer controller,
+// which it fetches with the credentials delegated to it by the user. The
+// connection to the peer is reopened after any error.
+type peerSummaries struct {
+	tag  names.ControllerTag
+	info *api.Info
+	all  bool
+	conn api.Connection
+}
+
+func newPeerSummaries(externalControllers state.ExternalControllers, peer params.PeerControllerAuth, all bool) (*peerSummaries, error) {
+	controllerTag, err := names.ParseControllerTag(peer.ControllerTag)
+	if err != nil {
+		return nil, errors.Annotate(err, "controller tag")
+	}
+	ec, err := externalControllers.Controller(controllerTag.Id())
+	if err != nil {
+		return nil, errors.Annotatef(err, "peer controller %q", controllerTag.Id())
+	}
+	controllerInfo := ec.ControllerInfo()
+	info := &api.Info{
+		Addrs:    controllerInfo.Addrs,
+		CACert:   controllerInfo.CACert,
+		Password: peer.Password,
+	}
+	if peer.AuthTag != "" {
+		authTag, err := names.ParseUserTag(peer.AuthTag)
+		if err != nil {
+			return nil, errors.Annotate(err, "auth tag")
+		}
+		info.Tag = authTag
+	}
+	if peer.Macaroons != "" {
+		if err := json.Unmarshal([]byte(peer.Macaroons), &info.Macaroons); err != nil {
+			return nil, errors.Annotate(err, "invalid macaroons")
+		}
+	}
+	return &peerSummaries{
+		tag:  controllerTag,
+		info: info,
+		all:  all,
+	}, nil
+}
+
+func (s *peerSummaries) controllerTag() names.ControllerTag {
+	return s.tag
+}
+
+func (s *peerSummaries) fetch() ([]params.ModelSummary, error) {
+	if s.conn == nil {
+		conn, err := openPeerAPI(s.info)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		s.conn = conn
+	}
+	summaries, err := s.listModelSummaries()
+	if err != nil {
+		s.close()
+		return nil, errors.Trace(err)
+	}
+	return summaries, nil
+}
+
+func (s *peerSummaries) listModelSummaries() ([]params.ModelSummary, error) {
+	// The user may be known to the peer by another name, such as
+	// when logging in with macaroons, so ask for the models of the
+	// user that the peer has authenticated.
+	userTag, ok := s.conn.AuthTag().(names.UserTag)
+	if !ok {
+		return nil, errors.Errorf("expected user login to peer controller, got %v", s.conn.AuthTag())
+	}
+	var results params.ModelSummaryResults
+	caller := base.NewFacadeCaller(s.conn, "ModelManager")
+	err := caller.FacadeCall("ListModelSummaries", params.ModelSummariesRequest{
+		UserTag: userTag.String(),
+		All:     s.all,
+	}, &results)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	summaries := make([]params.ModelSummary, len(results.Results))
+	for i, r := range results.Results {
+		if r.Error != nil {
+			return nil, errors.Trace(r.Error)
+		}
+		summaries[i] = *r.Result
+	}
+	return summaries, nil
+}
+
+func (s *peerSummaries) close() {
+	if s.conn == nil {
+		return
+	}
+	if err := s.conn.Close(); err != nil {
+		logger.Debugf("closing connection to peer controller %q: %v", s.tag.Id(), err)
+	}
+	s.conn = nil
+}
+
+// modelSummaryWatcher is a ModelSummaryWatcher which polls the local
+// controller and its peers for the model summaries. The summaries of
+// the models on a peer are left as they were last seen while the peer
+// cannot be reached.
+type modelSummaryWatcher struct {
+	tomb  tomb.Tomb
+	clock clock.Clock
+	local summarySource
+	peers []summarySource
+	out   chan params.ModelSummaryWatcherNextResults
+
+	// known holds the summaries last seen, keyed by controller tag
+	// then model UUID.
+	known map[string]map[string]params.ModelSummary
+
+	// peerErrors holds the last error reported for each peer.
+	peerErrors map[string]string
+
+	// pending holds the changes, keyed by controller tag and model
+	// UUID, and the errors, that are yet to be delivered.
+	pending           map[string]params.ModelSummaryChange
+	pendingPeerErrors []params.PeerControllerError
+}
+
+func newModelSummaryWatcher(clock clock.Clock, local summarySource, peers []summarySource) *modelSummaryWatcher {
+	w := &modelSummaryWatcher{
+		clock:      clock,
+		local:      local,
+		peers:      peers,
+		out:        make(chan params.ModelSummaryWatcherNextResults),
+		known:      make(map[string]map[string]params.ModelSummary),
+		peerErrors: make(map[string]string),
+		pending:    make(map[string]params.ModelSummaryChange),
+	}
+	w.tomb.Go(func() error {
+		defer close(w.out)
+		defer w.closeSources()
+		return w.loop()
+	})
+	return w
+}
+
+// Stop stops the watcher, and returns any error encountered while running
+// or shutting down.
+func (w *modelSummaryWatcher) Stop() error {
+	w.tomb.Kill(nil)
+	return w.tomb.Wait()
+}
+
+// Err returns any error encountered while running or shutting down, or
+// tomb.ErrStillAlive if the watcher is still running.
+func (w *modelSummaryWatcher) Err() error {
+	return w.tomb.Err()
+}
+
+// Changes returns the event channel for the modelSummaryWatcher.
+func (w *modelSummaryWatcher) Changes() <-chan params.ModelSummaryWatcherNextResults {
+	return w.out
+}
+
+func (w *modelSummaryWatcher) loop() error {
+	// The first event holds all of the summaries, even if there
+	// are none.
+	if err := w.poll(); err != nil {
+		return errors.Trace(err)
+	}
+	out := w.out
+	next := w.nextResults()
+	poll := w.clock.After(summaryPollInterval)
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-poll:
+			if err := w.poll(); err != nil {
+				return errors.Trace(err)
+			}
+			if len(w.pending) > 0 || len(w.pendingPeerErrors) > 0 {
+				next = w.nextResults()
+				out = w.out
+			}
+			poll = w.clock.After(summaryPollInterval)
+		case out <- next:
+			w.pending = make(map[string]params.ModelSummaryChange)
+			w.pendingPeerErrors = nil
+			out = nil
+		}
+	}
+}
+
+// poll fetches the summaries from each source, and records the changes
+// to them. An error fetching the local summaries is returned; errors
+// fetching those of peers are recorded.
+func (w *modelSummaryWatcher) poll() error {
+	summaries, err := w.local.fetch()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	w.update(w.local.controllerTag().String(), summaries)
+	for _, peer := range w.peers {
+		tag := peer.controllerTag().String()
+		summaries, err := peer.fetch()
+		if err != nil {
+			if w.peerErrors[tag] != err.Error() {
+				w.peerErrors[tag] = err.Error()
+				w.pendingPeerErrors = append(w.pendingPeerErrors, params.PeerControllerError{
+					ControllerTag: tag,
+					Error:         common.ServerError(err),
+				})
+			}
+			continue
+		}
+		delete(w.peerErrors, tag)
+		w.update(tag, summaries)
+	}
+	return nil
+}
+
+// update records the changes between the summaries last seen from the
+// controller and those given.
+func (w *modelSummaryWatcher) update(controllerTag string, summaries []params.ModelSummary) {
+	known := w.known[controllerTag]
+	seen := make(map[string]params.ModelSummary, len(summaries))
+	for _, summary := range summaries {
+		seen[summary.UUID] = summary
+		if last, ok := known[summary.UUID]; ok && reflect.DeepEqual(last, summary) {
+			continue
+		}
+		summary := summary
+		w.pending[controllerTag+":"+summary.UUID] = params.ModelSummaryChange{
+			ControllerTag: controllerTag,
+			ModelTag:      names.NewModelTag(summary.UUID).String(),
+			Summary:       &summary,
+		}
+	}
+	for uuid := range known {
+		if _, ok := seen[uuid]; ok {
+			continue
+		}
+		w.pending[controllerTag+":"+uuid] = params.ModelSummaryChange{
+			ControllerTag: controllerTag,
+			ModelTag:      names.NewModelTag(uuid).String(),
+			Removed:       true,
+		}
+	}
+	w.known[controllerTag] = seen
+}
+
+// nextResults returns the pending changes, ordered by controller and
+// model, and errors.
+func (w *modelSummaryWatcher) nextResults() params.ModelSummaryWatcherNextResults {
+	keys := make([]string, 0, len(w.pending))
+	for key := range w.pending {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	changes := make([]params.ModelSummaryChange, len(keys))
+	for i, key := range keys {
+		changes[i] = w.pending[key]
+	}
+	return params.ModelSummaryWatcherNextResults{
+		Changes:    changes,
+		PeerErrors: w.pendingPeerErrors,
+	}
+}
+
+func (w *modelSummaryWatcher) closeSources() {
+	w.local.close()
+	for _, peer := range w.peers {
+		peer.close()
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/status"
+	coretesting "github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&modelSummaryWatcherSuite{})
+
+type modelSummaryWatcherSuite struct {
+	testing.IsolationSuite
+
+	clock *testclock.Clock
+	local *fakeSummarySource
+	peer  *fakeSummarySource
+}
+
+func (s *modelSummaryWatcherSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.clock = testclock.NewClock(time.Time{})
+	s.local = &fakeSummarySource{tag: coretesting.ControllerTag}
+	s.peer = &fakeSummarySource{tag: names.NewControllerTag("deadbeef-2bad-500d-9000-4b1d0d06f00d")}
+}
+
+func (s *modelSummaryWatcherSuite) newWatcher(c *gc.C) *modelSummaryWatcher {
+	w := newModelSummaryWatcher(s.clock, s.local, []summarySource{s.peer})
+	s.AddCleanup(func(*gc.C) { w.Stop() })
+	return w
+}
+
+func (s *modelSummaryWatcherSuite) advance(c *gc.C) {
+	err := s.clock.WaitAdvance(summaryPollInterval, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *modelSummaryWatcherSuite) next(c *gc.C, w *modelSummaryWatcher) params.ModelSummaryWatcherNextResults {
+	select {
+	case result, ok := <-w.Changes():
+		c.Assert(ok, jc.IsTrue)
+		return result
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for model summaries")
+	}
+	panic("unreachable")
+}
+
+func (s *modelSummaryWatcherSuite) assertNoChange(c *gc.C, w *modelSummaryWatcher) {
+	select {
+	case result := <-w.Changes():
+		c.Fatalf("unexpected change: %#v", result)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func summary(uuid, modelStatus string) params.ModelSummary {
+	return params.ModelSummary{
+		UUID:   uuid,
+		Name:   "model-" + uuid,
+		Status: params.EntityStatus{Status: status.Status(modelStatus)},
+	}
+}
+
+func (s *modelSummaryWatcherSuite) TestInitialEvent(c *gc.C) {
+	local := summary("a", "available")
+	peer := summary("b", "available")
+	s.local.set([]params.ModelSummary{local}, nil)
+	s.peer.set([]params.ModelSummary{peer}, nil)
+
+	w := s.newWatcher(c)
+	result := s.next(c, w)
+	c.Check(result.PeerErrors, gc.HasLen, 0)
+	c.Check(result.Changes, jc.DeepEquals, []params.ModelSummaryChange{{
+		ControllerTag: s.local.tag.String(),
+		ModelTag:      "model-a",
+		Summary:       &local,
+	}, {
+		ControllerTag: s.peer.tag.String(),
+		ModelTag:      "model-b",
+		Summary:       &peer,
+	}})
+}
+
+func (s *modelSummaryWatcherSuite) TestInitialEventNoModels(c *gc.C) {
+	w := s.newWatcher(c)
+	result := s.next(c, w)
+	c.Check(result.Changes, gc.HasLen, 0)
+	c.Check(result.PeerErrors, gc.HasLen, 0)
+}
+
+func (s *modelSummaryWatcherSuite) TestChangesAndRemovals(c *gc.C) {
+	s.local.set([]params.ModelSummary{summary("a", "available"), summary("b", "available")}, nil)
+	w := s.newWatcher(c)
+	s.next(c, w)
+
+	s.advance(c)
+	s.assertNoChange(c, w)
+
+	changed := summary("a", "busy")
+	s.local.set([]params.ModelSummary{changed}, nil)
+	s.advance(c)
+	result := s.next(c, w)
+	c.Check(result.Changes, jc.DeepEquals, []params.ModelSummaryChange{{
+		ControllerTag: s.local.tag.String(),
+		ModelTag:      "model-a",
+		Summary:       &changed,
+	}, {
+		ControllerTag: s.local.tag.String(),
+		ModelTag:      "model-b",
+		Removed:       true,
+	}})
+}
+
+func (s *modelSummaryWatcherSuite) TestPeerErrorReportedOnce(c *gc.C) {
+	s.peer.set([]params.ModelSummary{summary("b", "available")}, nil)
+	w := s.newWatcher(c)
+	s.next(c, w)
+
+	s.peer.set(nil, errors.New("connection refused"))
+	s.advance(c)
+	result := s.next(c, w)
+	c.Check(result.Changes, gc.HasLen, 0)
+	c.Assert(result.PeerErrors, gc.HasLen, 1)
+	c.Check(result.PeerErrors[0].ControllerTag, gc.Equals, s.peer.tag.String())
+	c.Check(result.PeerErrors[0].Error, gc.ErrorMatches, "connection refused")
+
+	// The peer's models are kept while it cannot be reached, and the
+	// error is not reported again.
+	s.advance(c)
+	s.assertNoChange(c, w)
+}
+
+func (s *modelSummaryWatcherSuite) TestLocalErrorKillsWatcher(c *gc.C) {
+	s.local.set(nil, errors.New("boom"))
+	w := s.newWatcher(c)
+	select {
+	case _, ok := <-w.Changes():
+		c.Assert(ok, jc.IsFalse)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for watcher to die")
+	}
+	c.Assert(w.Err(), gc.ErrorMatches, "boom")
+}
+
+func (s *modelSummaryWatcherSuite) TestStopClosesSources(c *gc.C) {
+	w := s.newWatcher(c)
+	s.next(c, w)
+	c.Assert(w.Stop(), jc.ErrorIsNil)
+	c.Check(s.local.isClosed(), jc.IsTrue)
+	c.Check(s.peer.isClosed(), jc.IsTrue)
+}
+
+type fakeSummarySource struct {
+	mu        sync.Mutex
+	tag       names.ControllerTag
+	summaries []params.ModelSummary
+	err       error
+	closed    bool
+}
+
+func (f *fakeSummarySource) set(summaries []params.ModelSummary, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.summaries = summaries
+	f.err = err
+}
+
+func (f *fakeSummarySource) isClosed() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.closed
+}
+
+func (f *fakeSummarySource) controllerTag() names.ControllerTag {
+	return f.tag
+}
+
+func (f *fakeSummarySource) fetch() ([]params.ModelSummary, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.summaries, f.err
+}
+
+func (f *fakeSummarySource) close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+}
@@ -0,0 +1,45 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/upgrades"
+)
+
+// UpgradePreChecks evaluates the checks that are run before the
+// controller is upgraded, returning the problems found. It doesn't
+// change anything, so that clients can report what would block an
+// upgrade before requesting one.
+func (c *ControllerAPI) UpgradePreChecks() (params.UpgradePreCheckResults, error) {
+	if err := c.checkHasAdmin(); err != nil {
+		return params.UpgradePreCheckResults{}, errors.Trace(err)
+	}
+	ctx := upgrades.PreCheckContext{
+		State: c.statePool.SystemState(),
+	}
+	// Free disk space is only checked on the controller serving the
+	// request; each controller checks its own before upgrading.
+	if dataDir, ok := c.resources.Get("dataDir").(common.StringResource); ok {
+		ctx.DataDir = dataDir.String()
+	}
+	problems := upgrades.RunPreChecks(ctx)
+	result := params.UpgradePreCheckResults{
+		Problems: make([]params.UpgradePreCheckProblem, len(problems)),
+	}
+	for i, problem := range problems {
+		result.Problems[i] = params.UpgradePreCheckProblem{
+			Check:    problem.Check,
+			Message:  problem.Message,
+			Blocking: problem.Blocking,
+		}
+	}
+	return result, nil
+}
+
+// UpgradePreChecks isn't on the v8 API.
+func (c *ControllerAPIv8) UpgradePreChecks(_, _ struct{}) {}
@@ -0,0 +1,78 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// UpgradeStragglers returns the machine agents in each of the specified
+// models that haven't completed their upgrade steps for the model's
+// agent version, so that agents stuck upgrading can be identified
+// during a rollout.
+func (c *ControllerAPI) UpgradeStragglers(args params.Entities) (params.UpgradeStragglersResults, error) {
+	if err := c.checkHasAdmin(); err != nil {
+		return params.UpgradeStragglersResults{}, errors.Trace(err)
+	}
+	results := params.UpgradeStragglersResults{
+		Results: make([]params.UpgradeStragglersResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		result, err := c.upgradeStragglers(entity.Tag)
+		if err != nil {
+			results.Results[i].ModelTag = entity.Tag
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i] = result
+	}
+	return results, nil
+}
+
+func (c *ControllerAPI) upgradeStragglers(tag string) (params.UpgradeStragglersResult, error) {
+	var result params.UpgradeStragglersResult
+	modelTag, err := names.ParseModelTag(tag)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	st, err := c.statePool.Get(modelTag.Id())
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	defer st.Release()
+
+	model, err := st.Model()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	target, err := model.AgentVersion()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	stragglers, err := st.UpgradeStragglers(target)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	result.ModelTag = modelTag.String()
+	result.TargetVersion = target
+	for _, straggler := range stragglers {
+		agent := params.UpgradeStraggler{
+			Tag:     straggler.Tag.String(),
+			Version: straggler.Version,
+		}
+		if !straggler.Completed.IsZero() {
+			completed := straggler.Completed
+			agent.Completed = &completed
+		}
+		result.Stragglers = append(result.Stragglers, agent)
+	}
+	return result, nil
+}
+
+// UpgradeStragglers isn't on the v8 API.
+func (c *ControllerAPIv8) UpgradeStragglers(_, _ struct{}) {}
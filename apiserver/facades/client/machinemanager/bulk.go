@@ -0,0 +1,169 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinemanager
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/status"
+)
+
+// RebootMachines flags each of the specified machines to be rebooted by
+// its machine agent.
+func (mm *MachineManagerAPI) RebootMachines(args params.Entities) (params.ErrorResults, error) {
+	return mm.bulkMachineOp(args.Entities, func(machine Machine) error {
+		return machine.SetRebootFlag(true)
+	})
+}
+
+// UpgradeSeriesPrepareMachines prepares each of the specified machines
+// for an OS series upgrade, as UpgradeSeriesPrepare does for one machine.
+func (mm *MachineManagerAPI) UpgradeSeriesPrepareMachines(args params.UpdateSeriesArgs) (params.ErrorResults, error) {
+	if err := mm.checkCanWrite(); err != nil {
+		return params.ErrorResults{}, err
+	}
+	if err := mm.check.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, err
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	for i, arg := range args.Args {
+		err := mm.upgradeSeriesPrepare(arg)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// SetMachinesAnnotations sets the supplied annotations on each of the
+// specified machines.
+func (mm *MachineManagerAPI) SetMachinesAnnotations(args params.MachinesAnnotations) (params.ErrorResults, error) {
+	if err := mm.checkCanWrite(); err != nil {
+		return params.ErrorResults{}, err
+	}
+	if err := mm.check.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, err
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Machines)),
+	}
+	for i, entity := range args.Machines {
+		tag, err := mm.parseMachineTag(entity.Tag)
+		if err == nil {
+			err = mm.st.SetMachineAnnotations(tag.Id(), args.Annotations)
+		}
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// RetryProvisioningMachines marks the provisioning errors of each of the
+// specified machines as transient, so that the provisioner will try to
+// provision them again.
+func (mm *MachineManagerAPI) RetryProvisioningMachines(args params.Entities) (params.ErrorResults, error) {
+	return mm.bulkMachineOp(args.Entities, mm.retryProvisioning)
+}
+
+func (mm *MachineManagerAPI) retryProvisioning(machine Machine) error {
+	statusInfo, err := machine.InstanceStatus()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if statusInfo.Status != status.Error && statusInfo.Status != status.ProvisioningError {
+		return errors.New("machine is not in an error state")
+	}
+	data := make(map[string]interface{})
+	for k, v := range statusInfo.Data {
+		data[k] = v
+	}
+	data["transient"] = true
+	now := mm.clock.Now()
+	return machine.SetInstanceStatus(status.StatusInfo{
+		Status:  statusInfo.Status,
+		Message: statusInfo.Message,
+		Data:    data,
+		Since:   &now,
+	})
+}
+
+// RollbackLXDProfiles requests that the lxd profiles of each of the
+// specified machines be rolled back to those applied before their charm
+// profiles were last changed. The instance mutater worker applies the
+// rollback when it next checks the machine's profiles, and holds the
+// machine to it until the machine's charm profiles next change.
+func (mm *MachineManagerAPI) RollbackLXDProfiles(args params.Entities) (params.ErrorResults, error) {
+	return mm.bulkMachineOp(args.Entities, func(machine Machine) error {
+		return machine.RequestCharmProfilesRollback()
+	})
+}
+
+// CordonMachines cordons each of the specified machines, so that no new
+// units are assigned to them or to new containers on them. The units
+// already on the machines are left where they are.
+func (mm *MachineManagerAPI) CordonMachines(args params.Entities) (params.ErrorResults, error) {
+	return mm.bulkMachineOp(args.Entities, func(machine Machine) error {
+		return machine.SetCordoned(true)
+	})
+}
+
+// UncordonMachines uncordons each of the specified machines, so that
+// units may be assigned to them again.
+func (mm *MachineManagerAPI) UncordonMachines(args params.Entities) (params.ErrorResults, error) {
+	return mm.bulkMachineOp(args.Entities, func(machine Machine) error {
+		return machine.SetCordoned(false)
+	})
+}
+
+// bulkMachineOp checks that the caller may change the model, and then
+// applies op to each of the specified machines, reporting the outcome of
+// each application separately.
+func (mm *MachineManagerAPI) bulkMachineOp(entities []params.Entity, op func(Machine) error) (params.ErrorResults, error) {
+	if err := mm.checkCanWrite(); err != nil {
+		return params.ErrorResults{}, err
+	}
+	if err := mm.check.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, err
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(entities)),
+	}
+	for i, entity := range entities {
+		machine, err := mm.machineFromTag(entity.Tag)
+		if err == nil {
+			err = op(machine)
+		}
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// Mask the new methods from the V6 API. The API reflection code in
+// rpc/rpcreflect/type.go:newMethod skips 2-argument methods, so this
+// removes the method as far as the RPC machinery is concerned.
+
+// RebootMachines isn't on the V6 API.
+func (*MachineManagerAPIV6) RebootMachines(_, _ struct{}) {}
+
+// UpgradeSeriesPrepareMachines isn't on the V6 API.
+func (*MachineManagerAPIV6) UpgradeSeriesPrepareMachines(_, _ struct{}) {}
+
+// SetMachinesAnnotations isn't on the V6 API.
+func (*MachineManagerAPIV6) SetMachinesAnnotations(_, _ struct{}) {}
+
+// RetryProvisioningMachines isn't on the V6 API.
+func (*MachineManagerAPIV6) RetryProvisioningMachines(_, _ struct{}) {}
+
+// RollbackLXDProfiles isn't on the V6 API.
+func (*MachineManagerAPIV6) RollbackLXDProfiles(_, _ struct{}) {}
+
+// CordonMachines isn't on the V6 API.
+func (*MachineManagerAPIV6) CordonMachines(_, _ struct{}) {}
+
+// UncordonMachines isn't on the V6 API.
+func (*MachineManagerAPIV6) UncordonMachines(_, _ struct{}) {}
+
+// PortsHistory isn't on the V6 API.
+func (*MachineManagerAPIV6) PortsHistory(_, _ struct{}) {}
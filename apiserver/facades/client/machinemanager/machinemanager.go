@@ -5,8 +5,10 @@ package machinemanager
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/juju/clock"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/os"
@@ -17,6 +19,7 @@ import (
 	"github.com/juju/juju/apiserver/common/storagecommon"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/controller"
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/environs/config"
@@ -38,6 +41,7 @@ type MachineManagerAPI struct {
 
 	modelTag    names.ModelTag
 	callContext context.ProviderCallContext
+	clock       clock.Clock
 }
 
 // NewFacade create a new server-side MachineManager API facade. This
@@ -54,7 +58,7 @@ func NewFacade(ctx facade.Context) (*MachineManagerAPI, error) {
 		return nil, errors.Trace(err)
 	}
 	pool := &poolShim{ctx.StatePool()}
-	return NewMachineManagerAPI(backend, storageAccess, pool, ctx.Auth(), model.ModelTag(), state.CallContext(st), ctx.Resources())
+	return NewMachineManagerAPI(backend, storageAccess, pool, ctx.Auth(), model.ModelTag(), state.CallContext(st), ctx.Resources(), clock.WallClock)
 }
 
 // Version 4 of MachineManagerAPI
@@ -71,6 +75,15 @@ type MachineManagerAPIV5 struct {
 // Version 6 of Machine Manager API.
 // Changes input parameters to DestroyMachineWithParams and ForceDestroyMachine.
 type MachineManagerAPIV6 struct {
+	*MachineManagerAPIV7
+}
+
+// Version 7 of Machine Manager API.
+// Adds RebootMachines, UpgradeSeriesPrepareMachines, SetMachinesAnnotations,
+// RetryProvisioningMachines, RollbackLXDProfiles, CordonMachines,
+// UncordonMachines, PortsHistory, RequestReboot and RebootStatus, and
+// idempotency keys to AddMachines.
+type MachineManagerAPIV7 struct {
 	*MachineManagerAPI
 }
 
@@ -94,11 +107,20 @@ func NewFacadeV5(ctx facade.Context) (*MachineManagerAPIV5, error) {
 
 // NewFacadeV6 creates a new server-side MachineManager API facade.
 func NewFacadeV6(ctx facade.Context) (*MachineManagerAPIV6, error) {
+	machineManagerAPIv7, err := NewFacadeV7(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &MachineManagerAPIV6{machineManagerAPIv7}, nil
+}
+
+// NewFacadeV7 creates a new server-side MachineManager API facade.
+func NewFacadeV7(ctx facade.Context) (*MachineManagerAPIV7, error) {
 	machineManagerAPI, err := NewFacade(ctx)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	return &MachineManagerAPIV6{machineManagerAPI}, nil
+	return &MachineManagerAPIV7{machineManagerAPI}, nil
 }
 
 // NewMachineManagerAPI creates a new server-side MachineManager API facade.
@@ -110,6 +132,7 @@ func NewMachineManagerAPI(
 	modelTag names.ModelTag,
 	callCtx context.ProviderCallContext,
 	resources facade.Resources,
+	clock clock.Clock,
 ) (*MachineManagerAPI, error) {
 	if !auth.AuthClient() {
 		return nil, common.ErrPerm
@@ -123,6 +146,7 @@ func NewMachineManagerAPI(
 		modelTag:      modelTag,
 		callContext:   callCtx,
 		resources:     resources,
+		clock:         clock,
 	}, nil
 }
 
@@ -145,7 +169,20 @@ func (mm *MachineManagerAPI) checkAccess(access permission.Access) error {
 	return nil
 }
 
-// AddMachines adds new machines with the supplied parameters.
+// AddMachines is not able to take idempotency keys in version 6 of the
+// facade.
+func (mm *MachineManagerAPIV6) AddMachines(args params.AddMachines) (params.AddMachinesResults, error) {
+	for _, p := range args.MachineParams {
+		if p.IdempotencyKey != "" {
+			return params.AddMachinesResults{}, errors.NotSupportedf("idempotency keys")
+		}
+	}
+	return mm.MachineManagerAPIV7.AddMachines(args)
+}
+
+// AddMachines adds new machines with the supplied parameters. A machine
+// added with an idempotency key is not added again by a retry with the
+// same key; the machine already added is returned instead.
 func (mm *MachineManagerAPI) AddMachines(args params.AddMachines) (params.AddMachinesResults, error) {
 	results := params.AddMachinesResults{
 		Machines: make([]params.AddMachinesResult, len(args.MachineParams)),
@@ -157,10 +194,16 @@ func (mm *MachineManagerAPI) AddMachines(args params.AddMachines) (params.AddMac
 		return results, errors.Trace(err)
 	}
 	for i, p := range args.MachineParams {
-		m, err := mm.addOneMachine(p)
+		ids, _, err := common.CallIdempotently(mm.st, mm.authorizer.GetAuthTag(), "add-machine", p.IdempotencyKey, func() ([]string, error) {
+			m, err := mm.addOneMachine(p)
+			if err != nil {
+				return nil, err
+			}
+			return []string{m.Id()}, nil
+		})
 		results.Machines[i].Error = common.ServerError(err)
-		if err == nil {
-			results.Machines[i].Machine = m.Id()
+		if err == nil && len(ids) == 1 {
+			results.Machines[i].Machine = ids[0]
 		}
 	}
 	return results, nil
@@ -299,6 +342,17 @@ func (mm *MachineManagerAPI) destroyMachine(args params.Entities, force, keep bo
 	if err := mm.check.RemoveAllowed(); err != nil {
 		return params.DestroyMachineResults{}, err
 	}
+	if force {
+		// Forced removal skips the checks that protect running
+		// workloads, so the controller may require step-up auth.
+		cfg, err := mm.st.ControllerConfig()
+		if err != nil {
+			return params.DestroyMachineResults{}, errors.Trace(err)
+		}
+		if err := common.CheckOperationStepUpAuth(mm.authorizer, cfg, controller.StepUpForceRemoveMachine, mm.clock.Now()); err != nil {
+			return params.DestroyMachineResults{}, errors.Trace(err)
+		}
+	}
 	destroyMachine := func(entity params.Entity) params.DestroyMachineResult {
 		result := params.DestroyMachineResult{}
 		fail := func(e error) params.DestroyMachineResult {
@@ -306,7 +360,7 @@ func (mm *MachineManagerAPI) destroyMachine(args params.Entities, force, keep bo
 			return result
 		}
 
-		machineTag, err := names.ParseMachineTag(entity.Tag)
+		machineTag, err := mm.parseMachineTag(entity.Tag)
 		if err != nil {
 			return fail(err)
 		}
@@ -462,7 +516,7 @@ func (mm *MachineManagerAPI) upgradeSeriesPrepare(arg params.UpdateSeriesArg) er
 			Code:    params.CodeBadRequest,
 		}
 	}
-	machineTag, err := names.ParseMachineTag(arg.Entity.Tag)
+	machineTag, err := mm.parseMachineTag(arg.Entity.Tag)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -593,8 +647,29 @@ func (mm *MachineManagerAPI) GetUpgradeSeriesMessages(args params.UpgradeSeriesN
 	return results, nil
 }
 
-func (mm *MachineManagerAPI) machineFromTag(tag string) (Machine, error) {
+// parseMachineTag parses the given machine tag, which may hold the
+// machine's alias in place of its id, and returns the tag with the
+// machine's id.
+func (mm *MachineManagerAPI) parseMachineTag(tag string) (names.MachineTag, error) {
 	machineTag, err := names.ParseMachineTag(tag)
+	if err == nil {
+		return machineTag, nil
+	}
+	prefix := names.MachineTagKind + "-"
+	if !strings.HasPrefix(tag, prefix) {
+		return names.MachineTag{}, errors.Trace(err)
+	}
+	id, resolveErr := mm.st.ResolveMachineId(strings.TrimPrefix(tag, prefix))
+	if errors.IsNotValid(resolveErr) {
+		return names.MachineTag{}, errors.Trace(err)
+	} else if resolveErr != nil {
+		return names.MachineTag{}, errors.Trace(resolveErr)
+	}
+	return names.NewMachineTag(id), nil
+}
+
+func (mm *MachineManagerAPI) machineFromTag(tag string) (Machine, error) {
+	machineTag, err := mm.parseMachineTag(tag)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/juju/clock/testclock"
 	"github.com/juju/errors"
 	"github.com/juju/os/series"
 	jtesting "github.com/juju/testing"
@@ -21,6 +22,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/controller"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/environs/context"
 	"github.com/juju/juju/state"
@@ -39,11 +41,12 @@ type MachineManagerSuite struct {
 	api        *machinemanager.MachineManagerAPI
 
 	callContext context.ProviderCallContext
+	clock       *testclock.Clock
 }
 
 func (s *MachineManagerSuite) setAPIUser(c *gc.C, user names.UserTag) {
 	s.authorizer.Tag = user
-	mm, err := machinemanager.NewMachineManagerAPI(s.st, s.st, s.pool, s.authorizer, s.st.ModelTag(), s.callContext, common.NewResources())
+	mm, err := machinemanager.NewMachineManagerAPI(s.st, s.st, s.pool, s.authorizer, s.st.ModelTag(), s.callContext, common.NewResources(), s.clock)
 	c.Assert(err, jc.ErrorIsNil)
 	s.api = mm
 }
@@ -65,8 +68,9 @@ func (s *MachineManagerSuite) SetUpTest(c *gc.C) {
 	s.pool = &mockPool{}
 	s.authorizer = &apiservertesting.FakeAuthorizer{Tag: names.NewUserTag("admin")}
 	s.callContext = context.NewCloudCallContext()
+	s.clock = testclock.NewClock(time.Now())
 	var err error
-	s.api, err = machinemanager.NewMachineManagerAPI(s.st, s.st, s.pool, s.authorizer, s.st.ModelTag(), s.callContext, common.NewResources())
+	s.api, err = machinemanager.NewMachineManagerAPI(s.st, s.st, s.pool, s.authorizer, s.st.ModelTag(), s.callContext, common.NewResources(), s.clock)
 	c.Assert(err, jc.ErrorIsNil)
 }
 
@@ -120,10 +124,62 @@ func (s *MachineManagerSuite) TestAddMachines(c *gc.C) {
 	})
 }
 
+func (s *MachineManagerSuite) TestAddMachinesIdempotencyKey(c *gc.C) {
+	args := params.AddMachines{
+		MachineParams: []params.AddMachineParams{{
+			Series:         "trusty",
+			Jobs:           []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+			IdempotencyKey: "key-1",
+		}},
+	}
+	results, err := s.api.AddMachines(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Machines, gc.HasLen, 1)
+	c.Assert(results.Machines[0].Error, gc.IsNil)
+
+	// A retry returns the machine already added.
+	again, err := s.api.AddMachines(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(again, jc.DeepEquals, results)
+	c.Assert(s.st.calls, gc.Equals, 1)
+}
+
+func (s *MachineManagerSuite) TestAddMachinesIdempotencyKeyFailure(c *gc.C) {
+	s.st.err = errors.New("boom")
+	args := params.AddMachines{
+		MachineParams: []params.AddMachineParams{{
+			Series:         "trusty",
+			IdempotencyKey: "key-1",
+		}},
+	}
+	results, err := s.api.AddMachines(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Machines[0].Error, gc.ErrorMatches, "boom")
+
+	// The key may be used again after a failure.
+	s.st.err = nil
+	results, err = s.api.AddMachines(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Machines[0].Error, gc.IsNil)
+	c.Assert(s.st.calls, gc.Equals, 2)
+}
+
+func (s *MachineManagerSuite) TestAddMachinesIdempotencyKeyV6(c *gc.C) {
+	api := &machinemanager.MachineManagerAPIV6{&machinemanager.MachineManagerAPIV7{s.api}}
+	_, err := api.AddMachines(params.AddMachines{
+		MachineParams: []params.AddMachineParams{{
+			Series:         "trusty",
+			IdempotencyKey: "key-1",
+		}},
+	})
+	c.Assert(err, gc.ErrorMatches, "idempotency keys not supported")
+	c.Assert(s.st.calls, gc.Equals, 0)
+}
+
 func (s *MachineManagerSuite) TestNewMachineManagerAPINonClient(c *gc.C) {
 	tag := names.NewUnitTag("mysql/0")
 	s.authorizer = &apiservertesting.FakeAuthorizer{Tag: tag}
-	_, err := machinemanager.NewMachineManagerAPI(nil, nil, nil, s.authorizer, names.ModelTag{}, s.callContext, common.NewResources())
+	_, err := machinemanager.NewMachineManagerAPI(nil, nil, nil, s.authorizer, names.ModelTag{}, s.callContext, common.NewResources(), s.clock)
 	c.Assert(err, gc.ErrorMatches, "permission denied")
 }
 
@@ -168,6 +224,20 @@ func (s *MachineManagerSuite) TestDestroyMachine(c *gc.C) {
 	})
 }
 
+func (s *MachineManagerSuite) TestDestroyMachineByAlias(c *gc.C) {
+	s.st.machines["0"] = &mockMachine{}
+	results, err := s.api.DestroyMachine(params.Entities{
+		Entities: []params.Entity{{Tag: "machine-deadbeef:0"}, {Tag: "machine-cafebabe:0"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Info, gc.NotNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `machine "cafebabe:0" not found`)
+	s.st.CheckCall(c, 3, "ResolveMachineId", "deadbeef:0")
+	s.st.CheckCall(c, 4, "Machine", "0")
+}
+
 func (s *MachineManagerSuite) TestForceDestroyMachine(c *gc.C) {
 	s.st.machines["0"] = &mockMachine{}
 	results, err := s.api.ForceDestroyMachine(params.Entities{
@@ -193,6 +263,91 @@ func (s *MachineManagerSuite) TestForceDestroyMachine(c *gc.C) {
 	})
 }
 
+func (s *MachineManagerSuite) TestForceDestroyMachineRequiresSecondFactor(c *gc.C) {
+	s.st.machines["0"] = &mockMachine{}
+	s.st.controllerConfig = controller.Config{
+		controller.StepUpAuthMaxAge:     time.Minute,
+		controller.StepUpAuthOperations: []interface{}{controller.StepUpForceRemoveMachine},
+		controller.StepUpAuthRPID:       "juju.example.com",
+	}
+	_, err := s.api.ForceDestroyMachine(params.Entities{
+		Entities: []params.Entity{{Tag: "machine-0"}},
+	})
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrSecondFactorRequired)
+	s.st.CheckCallNames(c, "ModelTag", "GetBlockForType", "GetBlockForType", "ControllerConfig")
+}
+
+func (s *MachineManagerSuite) TestForceDestroyMachineSecondFactorNotListed(c *gc.C) {
+	s.st.machines["0"] = &mockMachine{}
+	s.st.controllerConfig = controller.Config{
+		controller.StepUpAuthMaxAge:     time.Minute,
+		controller.StepUpAuthOperations: []interface{}{controller.StepUpDestroyController},
+		controller.StepUpAuthRPID:       "juju.example.com",
+	}
+	results, err := s.api.ForceDestroyMachine(params.Entities{
+		Entities: []params.Entity{{Tag: "machine-0"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+}
+
+func (s *MachineManagerSuite) TestForceDestroyMachineWithSecondFactor(c *gc.C) {
+	s.st.machines["0"] = &mockMachine{}
+	s.st.controllerConfig = controller.Config{
+		controller.StepUpAuthMaxAge:     time.Minute,
+		controller.StepUpAuthOperations: []interface{}{controller.StepUpForceRemoveMachine},
+		controller.StepUpAuthRPID:       "juju.example.com",
+	}
+	authorizer := &apiservertesting.FakeStepUpAuthorizer{
+		FakeAuthorizer: *s.authorizer,
+		AssertedAt:     s.clock.Now(),
+	}
+	api, err := machinemanager.NewMachineManagerAPI(s.st, s.st, s.pool, authorizer, s.st.ModelTag(), s.callContext, common.NewResources(), s.clock)
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := api.ForceDestroyMachine(params.Entities{
+		Entities: []params.Entity{{Tag: "machine-0"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+}
+
+func (s *MachineManagerSuite) TestForceDestroyMachineWithExpiredSecondFactor(c *gc.C) {
+	s.st.machines["0"] = &mockMachine{}
+	s.st.controllerConfig = controller.Config{
+		controller.StepUpAuthMaxAge:     time.Minute,
+		controller.StepUpAuthOperations: []interface{}{controller.StepUpForceRemoveMachine},
+		controller.StepUpAuthRPID:       "juju.example.com",
+	}
+	authorizer := &apiservertesting.FakeStepUpAuthorizer{
+		FakeAuthorizer: *s.authorizer,
+		AssertedAt:     s.clock.Now(),
+	}
+	api, err := machinemanager.NewMachineManagerAPI(s.st, s.st, s.pool, authorizer, s.st.ModelTag(), s.callContext, common.NewResources(), s.clock)
+	c.Assert(err, jc.ErrorIsNil)
+	s.clock.Advance(2 * time.Minute)
+	_, err = api.ForceDestroyMachine(params.Entities{
+		Entities: []params.Entity{{Tag: "machine-0"}},
+	})
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrSecondFactorRequired)
+}
+
+func (s *MachineManagerSuite) TestDestroyMachineNotForcedIgnoresSecondFactor(c *gc.C) {
+	s.st.machines["0"] = &mockMachine{}
+	s.st.controllerConfig = controller.Config{
+		controller.StepUpAuthMaxAge:     time.Minute,
+		controller.StepUpAuthOperations: []interface{}{controller.StepUpForceRemoveMachine},
+		controller.StepUpAuthRPID:       "juju.example.com",
+	}
+	results, err := s.api.DestroyMachine(params.Entities{
+		Entities: []params.Entity{{Tag: "machine-0"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+}
+
 func (s *MachineManagerSuite) assertMachinesDestroyed(c *gc.C, in []params.Entity, out params.DestroyMachineResults, expectedCalls ...string) {
 	results, err := s.api.DestroyMachine(params.Entities{in})
 	c.Assert(err, jc.ErrorIsNil)
@@ -450,7 +605,9 @@ func (s *MachineManagerSuite) setupUpgradeSeries(c *gc.C) {
 }
 
 func (s *MachineManagerSuite) apiV5() machinemanager.MachineManagerAPIV5 {
-	return machinemanager.MachineManagerAPIV5{MachineManagerAPIV6: &machinemanager.MachineManagerAPIV6{s.api}}
+	return machinemanager.MachineManagerAPIV5{MachineManagerAPIV6: &machinemanager.MachineManagerAPIV6{
+		&machinemanager.MachineManagerAPIV7{s.api},
+	}}
 }
 
 func (s *MachineManagerSuite) TestUpgradeSeriesValidateOK(c *gc.C) {
@@ -781,6 +938,289 @@ func (s *MachineManagerSuite) TestIsSeriesLessThan(c *gc.C) {
 	}
 }
 
+func (s *MachineManagerSuite) TestRebootMachines(c *gc.C) {
+	s.st.machines["0"] = &mockMachine{}
+	s.st.machines["1"] = &mockMachine{}
+	results, err := s.api.RebootMachines(params.Entities{
+		Entities: []params.Entity{
+			{Tag: "machine-0"},
+			{Tag: "machine-1"},
+			{Tag: "machine-42"},
+			{Tag: "application-foo"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 4)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.IsNil)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, "machine 42 not found")
+	c.Assert(results.Results[3].Error, gc.ErrorMatches, `"application-foo" is not a valid machine tag`)
+	c.Assert(s.st.machines["0"].rebootFlag, jc.IsTrue)
+	c.Assert(s.st.machines["1"].rebootFlag, jc.IsTrue)
+}
+
+func (s *MachineManagerSuite) TestRebootMachinesBlockedChanges(c *gc.C) {
+	s.st.machines["0"] = &mockMachine{}
+	s.st.blockMsg = "TestRebootMachinesBlockedChanges"
+	s.st.block = state.ChangeBlock
+	_, err := s.api.RebootMachines(params.Entities{
+		Entities: []params.Entity{{Tag: "machine-0"}},
+	})
+	c.Assert(params.IsCodeOperationBlocked(err), jc.IsTrue, gc.Commentf("error: %#v", err))
+	c.Assert(s.st.machines["0"].rebootFlag, jc.IsFalse)
+}
+
+func (s *MachineManagerSuite) TestRebootMachinesPermissionDenied(c *gc.C) {
+	user := names.NewUserTag("fred")
+	s.setAPIUser(c, user)
+	_, err := s.api.RebootMachines(params.Entities{
+		Entities: []params.Entity{{Tag: "machine-0"}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *MachineManagerSuite) TestRollbackLXDProfiles(c *gc.C) {
+	s.st.machines["0"] = &mockMachine{}
+	s.st.machines["1"] = &mockMachine{}
+	s.st.machines["1"].SetErrors(errors.NotFoundf("previous charm profiles for machine 1"))
+	results, err := s.api.RollbackLXDProfiles(params.Entities{
+		Entities: []params.Entity{
+			{Tag: "machine-0"},
+			{Tag: "machine-1"},
+			{Tag: "machine-42"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, "previous charm profiles for machine 1 not found")
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, "machine 42 not found")
+	s.st.machines["0"].CheckCallNames(c, "RequestCharmProfilesRollback")
+}
+
+func (s *MachineManagerSuite) TestRollbackLXDProfilesPermissionDenied(c *gc.C) {
+	user := names.NewUserTag("fred")
+	s.setAPIUser(c, user)
+	_, err := s.api.RollbackLXDProfiles(params.Entities{
+		Entities: []params.Entity{{Tag: "machine-0"}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *MachineManagerSuite) TestCordonMachines(c *gc.C) {
+	s.st.machines["0"] = &mockMachine{}
+	results, err := s.api.CordonMachines(params.Entities{
+		Entities: []params.Entity{
+			{Tag: "machine-0"},
+			{Tag: "machine-42"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, "machine 42 not found")
+	s.st.machines["0"].CheckCall(c, 0, "SetCordoned", true)
+}
+
+func (s *MachineManagerSuite) TestUncordonMachines(c *gc.C) {
+	s.st.machines["0"] = &mockMachine{}
+	results, err := s.api.UncordonMachines(params.Entities{
+		Entities: []params.Entity{{Tag: "machine-0"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	s.st.machines["0"].CheckCall(c, 0, "SetCordoned", false)
+}
+
+func (s *MachineManagerSuite) TestCordonMachinesPermissionDenied(c *gc.C) {
+	user := names.NewUserTag("fred")
+	s.setAPIUser(c, user)
+	_, err := s.api.CordonMachines(params.Entities{
+		Entities: []params.Entity{{Tag: "machine-0"}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *MachineManagerSuite) TestPortsHistory(c *gc.C) {
+	now := time.Now()
+	s.st.machines["0"] = &mockMachine{
+		portsHistory: []state.PortChange{{
+			MachineID: "0",
+			Ports: state.PortRange{
+				UnitName: "mysql/0",
+				FromPort: 22,
+				ToPort:   22,
+				Protocol: "tcp",
+			},
+			Opened: true,
+			Hook:   "install",
+			Time:   now,
+		}},
+	}
+	results, err := s.api.PortsHistory(params.PortsHistoryArgs{
+		Args: []params.PortsHistoryArg{
+			{Tag: "machine-0", Size: 10},
+			{Tag: "machine-42"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].History, jc.DeepEquals, []params.PortChange{{
+		UnitTag:  "unit-mysql-0",
+		Hook:     "install",
+		Protocol: "tcp",
+		FromPort: 22,
+		ToPort:   22,
+		Opened:   true,
+		Time:     now,
+	}})
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, "machine 42 not found")
+	s.st.machines["0"].CheckCall(c, 0, "PortsHistory", 10)
+}
+
+func (s *MachineManagerSuite) TestRequestReboot(c *gc.C) {
+	s.st.machines["0"] = &mockMachine{}
+	s.st.machines["1"] = &mockMachine{}
+	s.st.machines["1"].SetErrors(errors.AlreadyExistsf("reboot of machine 1"))
+	results, err := s.api.RequestReboot(params.Entities{
+		Entities: []params.Entity{
+			{Tag: "machine-0"},
+			{Tag: "machine-1"},
+			{Tag: "machine-42"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, jc.Satisfies, params.IsCodeAlreadyExists)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, "machine 42 not found")
+	s.st.machines["0"].CheckCallNames(c, "RequestReboot")
+}
+
+func (s *MachineManagerSuite) TestRequestRebootPermissionDenied(c *gc.C) {
+	user := names.NewUserTag("fred")
+	s.setAPIUser(c, user)
+	_, err := s.api.RequestReboot(params.Entities{
+		Entities: []params.Entity{{Tag: "machine-0"}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *MachineManagerSuite) TestRebootStatus(c *gc.C) {
+	requested := time.Now()
+	s.st.machines["0"] = &mockMachine{
+		rebootStatus: state.RebootStatus{
+			State:     state.RebootRequested,
+			Requested: requested,
+		},
+	}
+	s.st.machines["1"] = &mockMachine{
+		rebootStatus: state.RebootStatus{State: state.RebootNone},
+	}
+	results, err := s.api.RebootStatus(params.Entities{
+		Entities: []params.Entity{
+			{Tag: "machine-0"},
+			{Tag: "machine-1"},
+			{Tag: "machine-42"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0], jc.DeepEquals, params.RebootStatusResult{
+		State:     "requested",
+		Requested: &requested,
+	})
+	c.Assert(results.Results[1], jc.DeepEquals, params.RebootStatusResult{
+		State: "none",
+	})
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, "machine 42 not found")
+}
+
+func (s *MachineManagerSuite) TestUpgradeSeriesPrepareMachines(c *gc.C) {
+	s.setupUpgradeSeries(c)
+	s.st.machines["0"].unitAgentState = status.Idle
+	s.st.machines["1"].unitAgentState = status.Idle
+
+	results, err := s.api.UpgradeSeriesPrepareMachines(params.UpdateSeriesArgs{
+		Args: []params.UpdateSeriesArg{{
+			Entity: params.Entity{Tag: "machine-0"},
+			Series: "xenial",
+		}, {
+			Entity: params.Entity{Tag: "machine-1"},
+			Series: "xenial",
+		}, {
+			Entity: params.Entity{Tag: "machine-76"},
+			Series: "xenial",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.IsNil)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, "machine 76 not found")
+	s.st.machines["0"].CheckCall(c, 2, "CreateUpgradeSeriesLock", []string{"foo/0", "test/0"}, "xenial")
+	s.st.machines["1"].CheckCall(c, 2, "CreateUpgradeSeriesLock", []string{"foo/1", "test/1"}, "xenial")
+}
+
+func (s *MachineManagerSuite) TestSetMachinesAnnotations(c *gc.C) {
+	s.st.machines["0"] = &mockMachine{}
+	s.st.machines["5"] = &mockMachine{}
+	annotations := map[string]string{"owner": "ops"}
+	results, err := s.api.SetMachinesAnnotations(params.MachinesAnnotations{
+		Machines: []params.Entity{
+			{Tag: "machine-0"},
+			{Tag: "machine-5"},
+			{Tag: "machine-6"},
+		},
+		Annotations: annotations,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.IsNil)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, "machine 6 not found")
+	c.Assert(s.st.annotations, jc.DeepEquals, map[string]map[string]string{
+		"0": annotations,
+		"5": annotations,
+	})
+}
+
+func (s *MachineManagerSuite) TestRetryProvisioningMachines(c *gc.C) {
+	s.st.machines["0"] = &mockMachine{
+		instanceStatus: status.StatusInfo{
+			Status:  status.ProvisioningError,
+			Message: "no matching tools",
+			Data:    map[string]interface{}{"foo": "bar"},
+		},
+	}
+	s.st.machines["1"] = &mockMachine{
+		instanceStatus: status.StatusInfo{Status: status.Running},
+	}
+	results, err := s.api.RetryProvisioningMachines(params.Entities{
+		Entities: []params.Entity{
+			{Tag: "machine-0"},
+			{Tag: "machine-1"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, "machine is not in an error state")
+
+	sInfo := s.st.machines["0"].instanceStatus
+	c.Assert(sInfo.Status, gc.Equals, status.ProvisioningError)
+	c.Assert(sInfo.Message, gc.Equals, "no matching tools")
+	c.Assert(sInfo.Data, jc.DeepEquals, map[string]interface{}{
+		"foo":       "bar",
+		"transient": true,
+	})
+	c.Assert(sInfo.Since, gc.NotNil)
+	c.Assert(*sInfo.Since, gc.Equals, s.clock.Now())
+	s.st.machines["1"].CheckCallNames(c, "InstanceStatus")
+}
+
 type mockState struct {
 	jtesting.Stub
 	machinemanager.Backend
@@ -791,7 +1231,10 @@ type mockState struct {
 	blockMsg         string
 	block            state.BlockType
 
+	annotations             map[string]map[string]string
+	controllerConfig        controller.Config
 	unitStorageAttachmentsF func(tag names.UnitTag) ([]state.StorageAttachment, error)
+	idempotentCalls         map[string]state.IdempotentCall
 }
 
 type mockVolumeAccess struct {
@@ -840,6 +1283,33 @@ func (st *mockState) AddOneMachine(template state.MachineTemplate) (*state.Machi
 	return &m, st.err
 }
 
+func (st *mockState) StartIdempotentCall(user, operation, key string) (state.IdempotentCall, error) {
+	st.MethodCall(st, "StartIdempotentCall", user, operation, key)
+	if call, ok := st.idempotentCalls[user+"#"+key]; ok {
+		return call, errors.AlreadyExistsf("idempotency key %q", key)
+	}
+	if st.idempotentCalls == nil {
+		st.idempotentCalls = make(map[string]state.IdempotentCall)
+	}
+	st.idempotentCalls[user+"#"+key] = state.IdempotentCall{User: user, Key: key, Operation: operation}
+	return st.idempotentCalls[user+"#"+key], nil
+}
+
+func (st *mockState) CompleteIdempotentCall(user, key string, started time.Time, results []string) error {
+	st.MethodCall(st, "CompleteIdempotentCall", user, key, started, results)
+	call := st.idempotentCalls[user+"#"+key]
+	call.Completed = true
+	call.Results = results
+	st.idempotentCalls[user+"#"+key] = call
+	return nil
+}
+
+func (st *mockState) AbortIdempotentCall(user, key string, started time.Time) error {
+	st.MethodCall(st, "AbortIdempotentCall", user, key, started)
+	delete(st.idempotentCalls, user+"#"+key)
+	return nil
+}
+
 func (st *mockState) GetBlockForType(t state.BlockType) (state.Block, bool, error) {
 	st.MethodCall(st, "GetBlockForType", t)
 	if st.block == t {
@@ -869,6 +1339,19 @@ func (st *mockState) Cloud(s string) (cloud.Cloud, error) {
 	return cloud.Cloud{}, nil
 }
 
+func (st *mockState) ControllerConfig() (controller.Config, error) {
+	st.MethodCall(st, "ControllerConfig")
+	return st.controllerConfig, nil
+}
+
+func (st *mockState) ResolveMachineId(idOrAlias string) (string, error) {
+	st.MethodCall(st, "ResolveMachineId", idOrAlias)
+	if strings.HasPrefix(idOrAlias, "deadbeef:") {
+		return strings.TrimPrefix(idOrAlias, "deadbeef:"), nil
+	}
+	return "", errors.NotFoundf("machine %q", idOrAlias)
+}
+
 func (st *mockState) Machine(id string) (machinemanager.Machine, error) {
 	st.MethodCall(st, "Machine", id)
 	if m, ok := st.machines[id]; !ok {
@@ -878,6 +1361,18 @@ func (st *mockState) Machine(id string) (machinemanager.Machine, error) {
 	}
 }
 
+func (st *mockState) SetMachineAnnotations(id string, annotations map[string]string) error {
+	st.MethodCall(st, "SetMachineAnnotations", id, annotations)
+	if _, ok := st.machines[id]; !ok {
+		return errors.NotFoundf("machine %v", id)
+	}
+	if st.annotations == nil {
+		st.annotations = make(map[string]map[string]string)
+	}
+	st.annotations[id] = annotations
+	return nil
+}
+
 func (st *mockState) StorageInstance(tag names.StorageTag) (state.StorageInstance, error) {
 	st.MethodCall(st, "StorageInstance", tag)
 	return &mockStorage{
@@ -927,6 +1422,10 @@ type mockMachine struct {
 	unitAgentState status.Status
 	unitState      status.Status
 	isManager      bool
+	rebootFlag     bool
+	instanceStatus status.StatusInfo
+	portsHistory   []state.PortChange
+	rebootStatus   state.RebootStatus
 
 	unitsF func() ([]machinemanager.Unit, error)
 }
@@ -1002,6 +1501,48 @@ func (m *mockMachine) IsManager() bool {
 	return m.isManager
 }
 
+func (m *mockMachine) SetRebootFlag(flag bool) error {
+	m.MethodCall(m, "SetRebootFlag", flag)
+	m.rebootFlag = flag
+	return m.NextErr()
+}
+
+func (m *mockMachine) RequestReboot() error {
+	m.MethodCall(m, "RequestReboot")
+	return m.NextErr()
+}
+
+func (m *mockMachine) RebootStatus() (state.RebootStatus, error) {
+	m.MethodCall(m, "RebootStatus")
+	return m.rebootStatus, m.NextErr()
+}
+
+func (m *mockMachine) RequestCharmProfilesRollback() error {
+	m.MethodCall(m, "RequestCharmProfilesRollback")
+	return m.NextErr()
+}
+
+func (m *mockMachine) SetCordoned(cordoned bool) error {
+	m.MethodCall(m, "SetCordoned", cordoned)
+	return m.NextErr()
+}
+
+func (m *mockMachine) PortsHistory(size int) ([]state.PortChange, error) {
+	m.MethodCall(m, "PortsHistory", size)
+	return m.portsHistory, m.NextErr()
+}
+
+func (m *mockMachine) InstanceStatus() (status.StatusInfo, error) {
+	m.MethodCall(m, "InstanceStatus")
+	return m.instanceStatus, m.NextErr()
+}
+
+func (m *mockMachine) SetInstanceStatus(sInfo status.StatusInfo) error {
+	m.MethodCall(m, "SetInstanceStatus", sInfo)
+	m.instanceStatus = sInfo
+	return m.NextErr()
+}
+
 type mockUnit struct {
 	tag         names.UnitTag
 	agentStatus status.Status
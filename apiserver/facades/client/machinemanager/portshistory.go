@@ -0,0 +1,55 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinemanager
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// PortsHistory returns the most recent port ranges opened and closed on
+// each of the specified machines, newest first, along with the units
+// and hooks that changed them.
+func (mm *MachineManagerAPI) PortsHistory(args params.PortsHistoryArgs) (params.PortsHistoryResults, error) {
+	if err := mm.checkCanRead(); err != nil {
+		return params.PortsHistoryResults{}, err
+	}
+	results := params.PortsHistoryResults{
+		Results: make([]params.PortsHistoryResult, len(args.Args)),
+	}
+	for i, arg := range args.Args {
+		history, err := mm.portsHistory(arg)
+		results.Results[i].History = history
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (mm *MachineManagerAPI) portsHistory(arg params.PortsHistoryArg) ([]params.PortChange, error) {
+	machine, err := mm.machineFromTag(arg.Tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	changes, err := machine.PortsHistory(arg.Size)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	history := make([]params.PortChange, len(changes))
+	for i, change := range changes {
+		history[i] = params.PortChange{
+			UnitTag:  names.NewUnitTag(change.Ports.UnitName).String(),
+			Hook:     change.Hook,
+			SubnetID: change.SubnetID,
+			Protocol: change.Ports.Protocol,
+			FromPort: change.Ports.FromPort,
+			ToPort:   change.Ports.ToPort,
+			Opened:   change.Opened,
+			Time:     change.Time,
+		}
+	}
+	return history, nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinemanager
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// RequestReboot asks the machine agent of each of the specified
+// machines to reboot it. The machine agent waits for the machine's
+// units to finish running any current hook before rebooting, and the
+// progress of the reboot may be followed with RebootStatus. Unlike
+// RebootMachines, a request for a machine that is already due to reboot
+// is refused.
+func (mm *MachineManagerAPI) RequestReboot(args params.Entities) (params.ErrorResults, error) {
+	return mm.bulkMachineOp(args.Entities, func(machine Machine) error {
+		return machine.RequestReboot()
+	})
+}
+
+// RebootStatus returns the progress of the most recent reboot of each
+// of the specified machines requested with RequestReboot.
+func (mm *MachineManagerAPI) RebootStatus(args params.Entities) (params.RebootStatusResults, error) {
+	if err := mm.checkCanRead(); err != nil {
+		return params.RebootStatusResults{}, err
+	}
+	results := params.RebootStatusResults{
+		Results: make([]params.RebootStatusResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		result, err := mm.rebootStatus(entity.Tag)
+		if err != nil {
+			result.Error = common.ServerError(err)
+		}
+		results.Results[i] = result
+	}
+	return results, nil
+}
+
+func (mm *MachineManagerAPI) rebootStatus(tag string) (params.RebootStatusResult, error) {
+	machine, err := mm.machineFromTag(tag)
+	if err != nil {
+		return params.RebootStatusResult{}, errors.Trace(err)
+	}
+	status, err := machine.RebootStatus()
+	if err != nil {
+		return params.RebootStatusResult{}, errors.Trace(err)
+	}
+	result := params.RebootStatusResult{State: string(status.State)}
+	if !status.Requested.IsZero() {
+		result.Requested = &status.Requested
+	}
+	if !status.Completed.IsZero() {
+		result.Completed = &status.Completed
+	}
+	return result, nil
+}
+
+// Mask the new methods from the V6 API.
+
+// RequestReboot isn't on the V6 API.
+func (*MachineManagerAPIV6) RequestReboot(_, _ struct{}) {}
+
+// RebootStatus isn't on the V6 API.
+func (*MachineManagerAPIV6) RebootStatus(_, _ struct{}) {}
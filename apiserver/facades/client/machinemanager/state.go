@@ -10,6 +10,7 @@ import (
 	"gopkg.in/juju/names.v3"
 
 	"github.com/juju/juju/apiserver/common/storagecommon"
+	"github.com/juju/juju/controller"
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/environs/config"
@@ -19,12 +20,18 @@ import (
 type Backend interface {
 	state.CloudAccessor
 
+	ControllerConfig() (controller.Config, error)
 	Machine(string) (Machine, error)
+	ResolveMachineId(idOrAlias string) (string, error)
 	Model() (Model, error)
 	GetBlockForType(t state.BlockType) (state.Block, bool, error)
 	AddOneMachine(template state.MachineTemplate) (*state.Machine, error)
 	AddMachineInsideNewMachine(template, parentTemplate state.MachineTemplate, containerType instance.ContainerType) (*state.Machine, error)
 	AddMachineInsideMachine(template state.MachineTemplate, parentId string, containerType instance.ContainerType) (*state.Machine, error)
+	SetMachineAnnotations(id string, annotations map[string]string) error
+	StartIdempotentCall(user, operation, key string) (state.IdempotentCall, error)
+	CompleteIdempotentCall(user, key string, started time.Time, results []string) error
+	AbortIdempotentCall(user, key string, started time.Time) error
 }
 
 type Pool interface {
@@ -54,6 +61,14 @@ type Machine interface {
 	WatchUpgradeSeriesNotifications() (state.NotifyWatcher, error)
 	GetUpgradeSeriesMessages() ([]string, bool, error)
 	IsManager() bool
+	SetRebootFlag(bool) error
+	RequestReboot() error
+	RebootStatus() (state.RebootStatus, error)
+	RequestCharmProfilesRollback() error
+	SetCordoned(bool) error
+	PortsHistory(int) ([]state.PortChange, error)
+	InstanceStatus() (status.StatusInfo, error)
+	SetInstanceStatus(status.StatusInfo) error
 }
 
 type stateShim struct {
@@ -72,6 +87,18 @@ func (s stateShim) Model() (Model, error) {
 	return s.State.Model()
 }
 
+func (s stateShim) SetMachineAnnotations(id string, annotations map[string]string) error {
+	m, err := s.State.Machine(id)
+	if err != nil {
+		return err
+	}
+	model, err := s.State.Model()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return model.SetAnnotations(m, annotations)
+}
+
 type poolShim struct {
 	pool *state.StatePool
 }
@@ -108,6 +108,7 @@ func (s *modelInfoSuite) SetUpTest(c *gc.C) {
 		tag:            coretesting.ModelTag,
 		controllerUUID: s.st.controllerUUID,
 		isController:   false,
+		alias:          "deadbeef",
 		life:           state.Dying,
 		status: status.StatusInfo{
 			Status: status.Destroying,
@@ -205,6 +206,7 @@ func (s *modelInfoSuite) expectedModelInfo(c *gc.C, credentialValidity *bool) pa
 	info := params.ModelInfo{
 		Name:               "testmodel",
 		UUID:               s.st.model.cfg.UUID(),
+		Alias:              "deadbeef",
 		Type:               string(s.st.model.Type()),
 		ControllerUUID:     "deadbeef-1bad-500d-9000-4b1d0d06f00d",
 		IsController:       false,
@@ -282,6 +284,7 @@ func (s *modelInfoSuite) assertModelInfo(c *gc.C, got, expected params.ModelInfo
 		{"Name", nil},
 		{"Type", nil},
 		{"UUID", nil},
+		{"Alias", nil},
 		{"ControllerUUID", nil},
 		{"UUID", nil},
 		{"Owner", nil},
@@ -338,6 +341,22 @@ func (s *modelInfoSuite) getModelInfo(c *gc.C, modelUUID string) params.ModelInf
 	return *results.Results[0].Result
 }
 
+func (s *modelInfoSuite) TestModelInfoByAlias(c *gc.C) {
+	results, err := s.modelmanager.ModelInfo(params.Entities{
+		Entities: []params.Entity{{"model-deadbeef"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Result.UUID, gc.Equals, s.st.model.cfg.UUID())
+	c.Assert(results.Results[0].Result.Alias, gc.Equals, "deadbeef")
+	s.st.CheckCall(c, 2, "ResolveModelUUID", "deadbeef")
+}
+
+func (s *modelInfoSuite) TestModelInfoErrorUnknownAlias(c *gc.C) {
+	s.testModelInfoError(c, "model-cafebabe", `"model-cafebabe" is not a valid model tag`)
+}
+
 func (s *modelInfoSuite) TestModelInfoErrorInvalidTag(c *gc.C) {
 	s.testModelInfoError(c, "user-bob", `"user-bob" is not a valid model tag`)
 }
@@ -650,6 +669,17 @@ func (st *mockState) ModelUUID() string {
 	return st.model.UUID()
 }
 
+func (st *mockState) ResolveModelUUID(uuidOrAlias string) (string, error) {
+	st.MethodCall(st, "ResolveModelUUID", uuidOrAlias)
+	if err := st.NextErr(); err != nil {
+		return "", err
+	}
+	if uuidOrAlias == st.model.alias {
+		return st.model.UUID(), nil
+	}
+	return "", errors.NotFoundf("model %q", uuidOrAlias)
+}
+
 func (st *mockState) Name() string {
 	st.MethodCall(st, "Name")
 	return "test-model"
@@ -1037,6 +1067,7 @@ type mockModel struct {
 	migrationStatus     state.MigrationMode
 	controllerUUID      string
 	isController        bool
+	alias               string
 	setCloudCredentialF func(tag names.CloudCredentialTag) (bool, error)
 }
 
@@ -1134,6 +1165,11 @@ func (m *mockModel) Name() string {
 	return m.cfg.Name()
 }
 
+func (m *mockModel) Alias() string {
+	m.MethodCall(m, "Alias")
+	return m.alias
+}
+
 func (m *mockModel) MigrationMode() state.MigrationMode {
 	m.MethodCall(m, "MigrationMode")
 	return m.migrationStatus
@@ -10,6 +10,7 @@ package modelmanager
 import (
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/juju/description"
@@ -305,6 +306,26 @@ func NewModelManagerAPI(
 	}, nil
 }
 
+// parseModelTag parses the given model tag, which may hold the model's
+// alias in place of its UUID, and returns the tag with the model's UUID.
+func (m *ModelManagerAPI) parseModelTag(tag string) (names.ModelTag, error) {
+	modelTag, err := names.ParseModelTag(tag)
+	if err == nil {
+		return modelTag, nil
+	}
+	prefix := names.ModelTagKind + "-"
+	if !strings.HasPrefix(tag, prefix) {
+		return names.ModelTag{}, errors.Trace(err)
+	}
+	uuid, resolveErr := m.state.ResolveModelUUID(strings.TrimPrefix(tag, prefix))
+	if errors.IsNotFound(resolveErr) {
+		return names.ModelTag{}, errors.Trace(err)
+	} else if resolveErr != nil {
+		return names.ModelTag{}, errors.Trace(resolveErr)
+	}
+	return names.NewModelTag(uuid), nil
+}
+
 // authCheck checks if the user is acting on their own behalf, or if they
 // are an administrator acting on behalf of another user.
 func (m *ModelManagerAPI) authCheck(user names.UserTag) error {
@@ -881,67 +902,7 @@ func (m *ModelManagerAPI) ListModelSummaries(req params.ModelSummariesRequest) (
 	}
 
 	for _, mi := range modelInfos {
-		summary := &params.ModelSummary{
-			Name:           mi.Name,
-			UUID:           mi.UUID,
-			Type:           string(mi.Type),
-			OwnerTag:       names.NewUserTag(mi.Owner).String(),
-			ControllerUUID: mi.ControllerUUID,
-			IsController:   mi.IsController,
-			Life:           params.Life(mi.Life.String()),
-
-			CloudTag:    mi.CloudTag,
-			CloudRegion: mi.CloudRegion,
-
-			CloudCredentialTag: mi.CloudCredentialTag,
-
-			SLA: &params.ModelSLAInfo{
-				Level: mi.SLALevel,
-				Owner: mi.Owner,
-			},
-
-			DefaultSeries: mi.DefaultSeries,
-			ProviderType:  mi.ProviderType,
-			AgentVersion:  mi.AgentVersion,
-
-			Status:             common.EntityStatusFromState(mi.Status),
-			Counts:             []params.ModelEntityCount{},
-			UserLastConnection: mi.UserLastConnection,
-		}
-
-		if mi.MachineCount > 0 {
-			summary.Counts = append(summary.Counts, params.ModelEntityCount{params.Machines, mi.MachineCount})
-		}
-
-		if mi.CoreCount > 0 {
-			summary.Counts = append(summary.Counts, params.ModelEntityCount{params.Cores, mi.CoreCount})
-		}
-
-		if mi.UnitCount > 0 {
-			summary.Counts = append(summary.Counts, params.ModelEntityCount{params.Units, mi.UnitCount})
-		}
-
-		access, err := common.StateToParamsUserAccessPermission(mi.Access)
-		if err == nil {
-			summary.UserAccess = access
-		}
-		if mi.Migration != nil {
-			migration := mi.Migration
-			startTime := migration.StartTime()
-			endTime := new(time.Time)
-			*endTime = migration.EndTime()
-			var zero time.Time
-			if *endTime == zero {
-				endTime = nil
-			}
-
-			summary.Migration = &params.ModelMigrationStatus{
-				Status: migration.StatusMessage(),
-				Start:  &startTime,
-				End:    endTime,
-			}
-		}
-
+		summary := common.ModelSummaryFromState(mi)
 		result.Results = append(result.Results, params.ModelSummaryResult{Result: summary})
 	}
 	return result, nil
@@ -981,6 +942,7 @@ func (m *ModelManagerAPI) ListModels(user params.Entity) (params.UserModelList,
 			Model: params.Model{
 				Name:     mi.Name,
 				UUID:     mi.UUID,
+				Alias:    mi.Alias,
 				Type:     string(mi.Type),
 				OwnerTag: ownerTag.String(),
 			},
@@ -1043,7 +1005,7 @@ func (m *ModelManagerAPI) DestroyModels(args params.DestroyModelsParams) (params
 	}
 
 	for i, arg := range args.Models {
-		tag, err := names.ParseModelTag(arg.ModelTag)
+		tag, err := m.parseModelTag(arg.ModelTag)
 		if err != nil {
 			results.Results[i].Error = common.ServerError(err)
 			continue
@@ -1072,7 +1034,7 @@ func (m *ModelManagerAPI) internalModelInfo(args params.Entities, includeCredent
 	}
 
 	getModelInfo := func(arg params.Entity) (params.ModelInfo, error) {
-		tag, err := names.ParseModelTag(arg.Tag)
+		tag, err := m.parseModelTag(arg.Tag)
 		if err != nil {
 			return params.ModelInfo{}, errors.Trace(err)
 		}
@@ -1126,6 +1088,7 @@ func (m *ModelManagerAPI) getModelInfo(tag names.ModelTag) (params.ModelInfo, er
 		Name:           model.Name(),
 		Type:           string(model.Type()),
 		UUID:           model.UUID(),
+		Alias:          model.Alias(),
 		ControllerUUID: model.ControllerUUID(),
 		IsController:   st.IsController(),
 		OwnerTag:       model.Owner().String(),
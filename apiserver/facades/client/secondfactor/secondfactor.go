@@ -0,0 +1,240 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package secondfactor provides the SecondFactor facade, with which a
+// user registers a hardware authenticator, such as a yubikey, and
+// asserts it on an API connection in order to be permitted the
+// destructive operations that the controller guards with step-up
+// authentication.
+package secondfactor
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/permission"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.secondfactor")
+
+// challengeLen is the number of random bytes in a challenge.
+const challengeLen = 32
+
+// enrollmentTokenLen is the number of random bytes in an enrollment
+// token.
+const enrollmentTokenLen = 32
+
+// API implements the SecondFactor facade.
+type API struct {
+	backend Backend
+	auth    facade.Authorizer
+	session facade.StepUpSession
+	userTag names.UserTag
+	clock   clock.Clock
+}
+
+// NewFacade creates a new SecondFactor facade.
+func NewFacade(ctx facade.Context) (*API, error) {
+	return NewAPI(stateShim{ctx.State()}, ctx.Auth(), clock.WallClock)
+}
+
+// NewAPI creates a new SecondFactor API for the local user authorized
+// by auth.
+func NewAPI(backend Backend, auth facade.Authorizer, clock clock.Clock) (*API, error) {
+	if !auth.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	userTag, ok := auth.GetAuthTag().(names.UserTag)
+	if !ok || !userTag.IsLocal() {
+		return nil, common.ErrPerm
+	}
+	session, ok := auth.(facade.StepUpSession)
+	if !ok {
+		return nil, errors.NotSupportedf("second factor authentication on this connection")
+	}
+	return &API{
+		backend: backend,
+		auth:    auth,
+		session: session,
+		userTag: userTag,
+		clock:   clock,
+	}, nil
+}
+
+// Challenge issues a new challenge on the connection, to be signed by
+// the user's authenticator and returned to Assert. Any challenge issued
+// previously is invalidated.
+func (api *API) Challenge() (params.SecondFactorChallenge, error) {
+	rpID, err := api.relyingPartyID()
+	if err != nil {
+		return params.SecondFactorChallenge{}, errors.Trace(err)
+	}
+	buf := make([]byte, challengeLen)
+	if _, err := rand.Read(buf); err != nil {
+		return params.SecondFactorChallenge{}, errors.Annotate(err, "generating challenge")
+	}
+	challenge := base64.RawURLEncoding.EncodeToString(buf)
+	api.session.SetSecondFactorChallenge(challenge)
+	return params.SecondFactorChallenge{
+		Challenge:      challenge,
+		RelyingPartyID: rpID,
+	}, nil
+}
+
+// Assert verifies an assertion made by the user's authenticator in
+// response to the challenge last issued on the connection and, if it is
+// valid, records that a second factor has been asserted on the
+// connection. A challenge may only be asserted once, whether or not the
+// assertion succeeds.
+func (api *API) Assert(args params.SecondFactorAssertion) error {
+	challenge := api.session.SecondFactorChallenge()
+	if challenge == "" {
+		return errors.New("no second factor challenge has been issued")
+	}
+	api.session.SetSecondFactorChallenge("")
+
+	rpID, err := api.relyingPartyID()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	user, err := api.backend.User(api.userTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	key := user.SecondFactorKey()
+	if key == nil {
+		return errors.NotFoundf("second factor key for user %q", api.userTag.Id())
+	}
+	signCount, err := verifyAssertion(key, rpID, challenge, args)
+	if err == nil {
+		last := user.SecondFactorSignCount()
+		if err = checkSignCount(last, signCount); err == nil {
+			err = user.UpdateSecondFactorSignCount(last, signCount)
+		}
+	}
+	if err != nil {
+		logger.Debugf("second factor assertion by %q rejected: %v", api.userTag.Id(), err)
+		return common.ErrPerm
+	}
+	api.session.SetSecondFactorAssertedAt(api.clock.Now())
+	return nil
+}
+
+// SetKey registers the public key of the user's authenticator. The
+// first key requires the enrollment token issued to the user by a
+// controller administrator, which is then consumed. Replacing a key
+// that is already registered requires instead that the old one has been
+// asserted recently on the connection.
+func (api *API) SetKey(args params.SecondFactorKey) error {
+	if _, err := parsePublicKey(args.PublicKey); err != nil {
+		return errors.Trace(err)
+	}
+	user, err := api.backend.User(api.userTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if user.SecondFactorKey() == nil {
+		return errors.Trace(api.enroll(user, args))
+	}
+	cfg, err := api.backend.ControllerConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := common.CheckStepUpAuth(api.auth, cfg, api.clock.Now()); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(user.SetSecondFactorKey(args.PublicKey))
+}
+
+// enroll registers the user's first key, if the enrollment token given
+// is the one issued to the user.
+func (api *API) enroll(user User, args params.SecondFactorKey) error {
+	issued := user.SecondFactorEnrollmentHash()
+	hash := enrollmentTokenHash(args.EnrollmentToken)
+	if args.EnrollmentToken == "" || issued == "" ||
+		subtle.ConstantTimeCompare([]byte(hash), []byte(issued)) != 1 {
+		logger.Debugf("second factor enrollment by %q rejected: no valid enrollment token", api.userTag.Id())
+		return common.ErrPerm
+	}
+	if err := user.EnrollSecondFactorKey(args.PublicKey, hash); err != nil {
+		logger.Debugf("second factor enrollment by %q rejected: %v", api.userTag.Id(), err)
+		return common.ErrPerm
+	}
+	return nil
+}
+
+// IssueEnrollmentTokens issues each of the given local users a one-time
+// token with which to register their first authenticator, replacing any
+// token issued previously. The tokens are to be passed to the users out
+// of band. Only controller superusers may issue tokens.
+func (api *API) IssueEnrollmentTokens(args params.Entities) (params.StringResults, error) {
+	isAdmin, err := api.auth.HasPermission(permission.SuperuserAccess, api.backend.ControllerTag())
+	if err != nil {
+		return params.StringResults{}, errors.Trace(err)
+	}
+	if !isAdmin {
+		return params.StringResults{}, common.ErrPerm
+	}
+	results := params.StringResults{
+		Results: make([]params.StringResult, len(args.Entities)),
+	}
+	for i, arg := range args.Entities {
+		token, err := api.issueEnrollmentToken(arg.Tag)
+		results.Results[i].Result = token
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (api *API) issueEnrollmentToken(tagString string) (string, error) {
+	tag, err := names.ParseUserTag(tagString)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if !tag.IsLocal() {
+		return "", errors.NotValidf("external user %q", tag.Id())
+	}
+	user, err := api.backend.User(tag)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	buf := make([]byte, enrollmentTokenLen)
+	if _, err := rand.Read(buf); err != nil {
+		return "", errors.Annotate(err, "generating enrollment token")
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	if err := user.SetSecondFactorEnrollmentHash(enrollmentTokenHash(token)); err != nil {
+		return "", errors.Trace(err)
+	}
+	return token, nil
+}
+
+// enrollmentTokenHash returns the hash of an enrollment token, which is
+// stored in its place.
+func enrollmentTokenHash(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+func (api *API) relyingPartyID() (string, error) {
+	cfg, err := api.backend.ControllerConfig()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	rpID := cfg.StepUpAuthRPID()
+	if rpID == "" {
+		return "", errors.NotSupportedf("second factor authentication without a relying party ID")
+	}
+	return rpID, nil
+}
@@ -0,0 +1,444 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package secondfactor_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facades/client/secondfactor"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/controller"
+	coretesting "github.com/juju/juju/testing"
+)
+
+const rpID = "juju.example.com"
+
+type secondFactorSuite struct {
+	coretesting.BaseSuite
+
+	clock     *testclock.Clock
+	key       *ecdsa.PrivateKey
+	signCount uint32
+	user      *mockUser
+	backend   *mockBackend
+	auth      *apiservertesting.FakeStepUpAuthorizer
+	api       *secondfactor.API
+}
+
+var _ = gc.Suite(&secondFactorSuite{})
+
+func (s *secondFactorSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.clock = testclock.NewClock(time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC))
+	s.key = newKey(c)
+	s.signCount = 1
+	s.user = &mockUser{key: marshalKey(c, s.key)}
+	s.backend = &mockBackend{
+		config: controller.Config{
+			controller.StepUpAuthMaxAge: time.Minute,
+			controller.StepUpAuthRPID:   rpID,
+		},
+		user: s.user,
+	}
+	s.auth = &apiservertesting.FakeStepUpAuthorizer{
+		FakeAuthorizer: apiservertesting.FakeAuthorizer{Tag: names.NewUserTag("bob")},
+	}
+	var err error
+	s.api, err = secondfactor.NewAPI(s.backend, s.auth, s.clock)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *secondFactorSuite) TestNewAPIRequiresLocalUser(c *gc.C) {
+	for _, tag := range []names.Tag{
+		names.NewUserTag("bob@external"),
+		names.NewMachineTag("0"),
+	} {
+		auth := &apiservertesting.FakeStepUpAuthorizer{
+			FakeAuthorizer: apiservertesting.FakeAuthorizer{Tag: tag},
+		}
+		_, err := secondfactor.NewAPI(s.backend, auth, s.clock)
+		c.Check(err, gc.Equals, common.ErrPerm)
+	}
+}
+
+func (s *secondFactorSuite) TestNewAPIRequiresStepUpSession(c *gc.C) {
+	auth := apiservertesting.FakeAuthorizer{Tag: names.NewUserTag("bob")}
+	_, err := secondfactor.NewAPI(s.backend, auth, s.clock)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *secondFactorSuite) TestChallenge(c *gc.C) {
+	result, err := s.api.Challenge()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.RelyingPartyID, gc.Equals, rpID)
+	c.Assert(result.Challenge, gc.Not(gc.Equals), "")
+	c.Assert(s.auth.Challenge, gc.Equals, result.Challenge)
+
+	again, err := s.api.Challenge()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(again.Challenge, gc.Not(gc.Equals), result.Challenge)
+}
+
+func (s *secondFactorSuite) TestChallengeNoRelyingPartyID(c *gc.C) {
+	s.backend.config = controller.Config{}
+	_, err := s.api.Challenge()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *secondFactorSuite) TestAssert(c *gc.C) {
+	challenge, err := s.api.Challenge()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.api.Assert(s.assertion(c, s.key, "webauthn.get", challenge.Challenge, rpID, 0x01))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.auth.AssertedAt, gc.Equals, s.clock.Now())
+	c.Assert(s.auth.Challenge, gc.Equals, "")
+	c.Assert(s.user.signCount, gc.Equals, uint32(1))
+}
+
+func (s *secondFactorSuite) TestAssertSignCountMustIncrease(c *gc.C) {
+	s.user.signCount = 5
+	for _, count := range []uint32{0, 4, 5} {
+		s.signCount = count
+		challenge, err := s.api.Challenge()
+		c.Assert(err, jc.ErrorIsNil)
+		err = s.api.Assert(s.assertion(c, s.key, "webauthn.get", challenge.Challenge, rpID, 0x01))
+		c.Check(err, gc.Equals, common.ErrPerm)
+		c.Check(s.auth.AssertedAt.IsZero(), jc.IsTrue)
+		c.Check(s.user.signCount, gc.Equals, uint32(5))
+	}
+
+	s.signCount = 6
+	challenge, err := s.api.Challenge()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.api.Assert(s.assertion(c, s.key, "webauthn.get", challenge.Challenge, rpID, 0x01))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.user.signCount, gc.Equals, uint32(6))
+}
+
+func (s *secondFactorSuite) TestAssertWithoutSignCount(c *gc.C) {
+	// Authenticators without a counter always report zero.
+	s.signCount = 0
+	for i := 0; i < 2; i++ {
+		challenge, err := s.api.Challenge()
+		c.Assert(err, jc.ErrorIsNil)
+		err = s.api.Assert(s.assertion(c, s.key, "webauthn.get", challenge.Challenge, rpID, 0x01))
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *secondFactorSuite) TestAssertSignCountChanged(c *gc.C) {
+	s.user.updateErr = errors.New("sign count changed")
+	challenge, err := s.api.Challenge()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.api.Assert(s.assertion(c, s.key, "webauthn.get", challenge.Challenge, rpID, 0x01))
+	c.Assert(err, gc.Equals, common.ErrPerm)
+	c.Assert(s.auth.AssertedAt.IsZero(), jc.IsTrue)
+}
+
+func (s *secondFactorSuite) TestAssertNoChallenge(c *gc.C) {
+	err := s.api.Assert(s.assertion(c, s.key, "webauthn.get", "challenge", rpID, 0x01))
+	c.Assert(err, gc.ErrorMatches, "no second factor challenge has been issued")
+}
+
+func (s *secondFactorSuite) TestAssertNoKey(c *gc.C) {
+	s.user.key = nil
+	challenge, err := s.api.Challenge()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.api.Assert(s.assertion(c, s.key, "webauthn.get", challenge.Challenge, rpID, 0x01))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *secondFactorSuite) TestAssertInvalid(c *gc.C) {
+	for i, t := range []struct {
+		about     string
+		key       *ecdsa.PrivateKey
+		typ       string
+		challenge string
+		rpID      string
+		flags     byte
+	}{{
+		about: "wrong key",
+		key:   newKey(c),
+		typ:   "webauthn.get",
+		rpID:  rpID,
+		flags: 0x01,
+	}, {
+		about: "wrong type",
+		key:   s.key,
+		typ:   "webauthn.create",
+		rpID:  rpID,
+		flags: 0x01,
+	}, {
+		about:     "wrong challenge",
+		key:       s.key,
+		typ:       "webauthn.get",
+		challenge: "other",
+		rpID:      rpID,
+		flags:     0x01,
+	}, {
+		about: "wrong relying party",
+		key:   s.key,
+		typ:   "webauthn.get",
+		rpID:  "evil.example.com",
+		flags: 0x01,
+	}, {
+		about: "user not present",
+		key:   s.key,
+		typ:   "webauthn.get",
+		rpID:  rpID,
+		flags: 0x04,
+	}} {
+		c.Logf("test %d: %s", i, t.about)
+		challenge, err := s.api.Challenge()
+		c.Assert(err, jc.ErrorIsNil)
+		if t.challenge == "" {
+			t.challenge = challenge.Challenge
+		}
+		err = s.api.Assert(s.assertion(c, t.key, t.typ, t.challenge, t.rpID, t.flags))
+		c.Check(err, gc.Equals, common.ErrPerm)
+		c.Check(s.auth.AssertedAt.IsZero(), jc.IsTrue)
+		c.Check(s.auth.Challenge, gc.Equals, "")
+	}
+}
+
+func (s *secondFactorSuite) TestAssertChallengeSingleUse(c *gc.C) {
+	challenge, err := s.api.Challenge()
+	c.Assert(err, jc.ErrorIsNil)
+	assertion := s.assertion(c, s.key, "webauthn.get", challenge.Challenge, rpID, 0x01)
+	err = s.api.Assert(assertion)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.api.Assert(assertion)
+	c.Assert(err, gc.ErrorMatches, "no second factor challenge has been issued")
+}
+
+func (s *secondFactorSuite) TestSetKey(c *gc.C) {
+	s.user.key = nil
+	token := s.issueEnrollmentToken(c)
+	key := marshalKey(c, s.key)
+	err := s.api.SetKey(params.SecondFactorKey{PublicKey: key, EnrollmentToken: token})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.user.key, jc.DeepEquals, key)
+	c.Assert(s.user.enrollmentHash, gc.Equals, "")
+}
+
+func (s *secondFactorSuite) TestSetKeyRequiresEnrollmentToken(c *gc.C) {
+	s.user.key = nil
+	key := marshalKey(c, s.key)
+	err := s.api.SetKey(params.SecondFactorKey{PublicKey: key})
+	c.Assert(err, gc.Equals, common.ErrPerm)
+
+	s.issueEnrollmentToken(c)
+	err = s.api.SetKey(params.SecondFactorKey{PublicKey: key})
+	c.Assert(err, gc.Equals, common.ErrPerm)
+	err = s.api.SetKey(params.SecondFactorKey{PublicKey: key, EnrollmentToken: "guess"})
+	c.Assert(err, gc.Equals, common.ErrPerm)
+	c.Assert(s.user.key, gc.IsNil)
+}
+
+func (s *secondFactorSuite) TestSetKeyEnrollmentTokenSingleUse(c *gc.C) {
+	s.user.key = nil
+	token := s.issueEnrollmentToken(c)
+	err := s.api.SetKey(params.SecondFactorKey{PublicKey: marshalKey(c, s.key), EnrollmentToken: token})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Replacing the key needs the old one asserted, not the token.
+	replacement := marshalKey(c, newKey(c))
+	err = s.api.SetKey(params.SecondFactorKey{PublicKey: replacement, EnrollmentToken: token})
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrSecondFactorRequired)
+}
+
+func (s *secondFactorSuite) TestIssueEnrollmentTokens(c *gc.C) {
+	admin := &apiservertesting.FakeStepUpAuthorizer{
+		FakeAuthorizer: apiservertesting.FakeAuthorizer{
+			Tag:      names.NewUserTag("admin"),
+			AdminTag: names.NewUserTag("admin"),
+		},
+	}
+	api, err := secondfactor.NewAPI(s.backend, admin, s.clock)
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := api.IssueEnrollmentTokens(params.Entities{Entities: []params.Entity{
+		{Tag: "user-bob"},
+		{Tag: "user-bob@external"},
+		{Tag: "machine-0"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Result, gc.Not(gc.Equals), "")
+	c.Assert(s.user.enrollmentHash, gc.Not(gc.Equals), "")
+	c.Assert(s.user.enrollmentHash, gc.Not(gc.Equals), results.Results[0].Result)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `external user "bob@external" not valid`)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `"machine-0" is not a valid user tag`)
+}
+
+func (s *secondFactorSuite) TestIssueEnrollmentTokensRequiresSuperuser(c *gc.C) {
+	_, err := s.api.IssueEnrollmentTokens(params.Entities{Entities: []params.Entity{{Tag: "user-bob"}}})
+	c.Assert(err, gc.Equals, common.ErrPerm)
+	c.Assert(s.user.enrollmentHash, gc.Equals, "")
+}
+
+// issueEnrollmentToken issues bob an enrollment token as a controller
+// superuser, and returns it.
+func (s *secondFactorSuite) issueEnrollmentToken(c *gc.C) string {
+	admin := &apiservertesting.FakeStepUpAuthorizer{
+		FakeAuthorizer: apiservertesting.FakeAuthorizer{
+			Tag:      names.NewUserTag("admin"),
+			AdminTag: names.NewUserTag("admin"),
+		},
+	}
+	api, err := secondfactor.NewAPI(s.backend, admin, s.clock)
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := api.IssueEnrollmentTokens(params.Entities{Entities: []params.Entity{{Tag: "user-bob"}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	return results.Results[0].Result
+}
+
+func (s *secondFactorSuite) TestSetKeyInvalid(c *gc.C) {
+	s.user.key = nil
+	err := s.api.SetKey(params.SecondFactorKey{PublicKey: []byte("rubbish")})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.api.SetKey(params.SecondFactorKey{PublicKey: marshalKey(c, p384Key)})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(s.user.key, gc.IsNil)
+}
+
+func (s *secondFactorSuite) TestSetKeyReplaceRequiresAssertion(c *gc.C) {
+	replacement := marshalKey(c, newKey(c))
+	err := s.api.SetKey(params.SecondFactorKey{PublicKey: replacement})
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrSecondFactorRequired)
+
+	s.auth.AssertedAt = s.clock.Now()
+	err = s.api.SetKey(params.SecondFactorKey{PublicKey: replacement})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.user.key, jc.DeepEquals, replacement)
+}
+
+// assertion returns a WebAuthn assertion over the given client data
+// type, challenge, relying party and flags, signed with key.
+func (s *secondFactorSuite) assertion(
+	c *gc.C, key *ecdsa.PrivateKey, typ, challenge, rpID string, flags byte,
+) params.SecondFactorAssertion {
+	clientData, err := json.Marshal(map[string]string{
+		"type":      typ,
+		"challenge": challenge,
+		"origin":    "https://" + rpID,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	authData := append(rpIDHash[:], flags, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(authData[len(authData)-4:], s.signCount)
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	r, ss, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	c.Assert(err, jc.ErrorIsNil)
+	signature, err := asn1.Marshal(struct{ R, S *big.Int }{r, ss})
+	c.Assert(err, jc.ErrorIsNil)
+	return params.SecondFactorAssertion{
+		ClientDataJSON:    clientData,
+		AuthenticatorData: authData,
+		Signature:         signature,
+	}
+}
+
+func newKey(c *gc.C) *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	c.Assert(err, jc.ErrorIsNil)
+	return key
+}
+
+func marshalKey(c *gc.C, key *ecdsa.PrivateKey) []byte {
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	c.Assert(err, jc.ErrorIsNil)
+	return der
+}
+
+type mockBackend struct {
+	config controller.Config
+	user   *mockUser
+}
+
+func (b *mockBackend) ControllerConfig() (controller.Config, error) {
+	return b.config, nil
+}
+
+func (b *mockBackend) ControllerTag() names.ControllerTag {
+	return coretesting.ControllerTag
+}
+
+func (b *mockBackend) User(tag names.UserTag) (secondfactor.User, error) {
+	return b.user, nil
+}
+
+type mockUser struct {
+	key            []byte
+	enrollmentHash string
+	signCount      uint32
+	updateErr      error
+}
+
+func (u *mockUser) SecondFactorKey() []byte {
+	return u.key
+}
+
+func (u *mockUser) SetSecondFactorKey(key []byte) error {
+	u.key = key
+	u.signCount = 0
+	return nil
+}
+
+func (u *mockUser) SecondFactorEnrollmentHash() string {
+	return u.enrollmentHash
+}
+
+func (u *mockUser) SetSecondFactorEnrollmentHash(hash string) error {
+	u.enrollmentHash = hash
+	return nil
+}
+
+func (u *mockUser) EnrollSecondFactorKey(key []byte, hash string) error {
+	if u.key != nil || hash != u.enrollmentHash {
+		return errors.New("key already registered or token not valid")
+	}
+	u.key = key
+	u.enrollmentHash = ""
+	u.signCount = 0
+	return nil
+}
+
+func (u *mockUser) SecondFactorSignCount() uint32 {
+	return u.signCount
+}
+
+func (u *mockUser) UpdateSecondFactorSignCount(old, new uint32) error {
+	if u.updateErr != nil {
+		return u.updateErr
+	}
+	if old != u.signCount {
+		return errors.New("sign count changed")
+	}
+	u.signCount = new
+	return nil
+}
@@ -0,0 +1,38 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package secondfactor
+
+import (
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/state"
+)
+
+// Backend provides the state methods required by the SecondFactor facade.
+type Backend interface {
+	ControllerConfig() (controller.Config, error)
+	ControllerTag() names.ControllerTag
+	User(names.UserTag) (User, error)
+}
+
+// User provides the state methods required by the SecondFactor facade
+// to manage a user's second-factor authenticator.
+type User interface {
+	SecondFactorKey() []byte
+	SetSecondFactorKey([]byte) error
+	SecondFactorEnrollmentHash() string
+	SetSecondFactorEnrollmentHash(string) error
+	EnrollSecondFactorKey([]byte, string) error
+	SecondFactorSignCount() uint32
+	UpdateSecondFactorSignCount(old, new uint32) error
+}
+
+type stateShim struct {
+	*state.State
+}
+
+func (s stateShim) User(tag names.UserTag) (User, error) {
+	return s.State.User(tag)
+}
@@ -0,0 +1,117 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package secondfactor
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+const (
+	// assertionType is the client data type of a WebAuthn assertion.
+	assertionType = "webauthn.get"
+
+	// flagUserPresent is the authenticator data flag set when the user
+	// was present, i.e. touched the authenticator, for the assertion.
+	flagUserPresent = 0x01
+
+	// minAuthenticatorDataLen is the length of the rpIdHash, flags and
+	// signCount fields that start all authenticator data.
+	minAuthenticatorDataLen = sha256.Size + 1 + 4
+)
+
+// clientData holds the fields of WebAuthn client data that the
+// controller verifies.
+type clientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+}
+
+// parsePublicKey parses a PKIX, DER-encoded ECDSA P-256 public key, the
+// only kind of key the controller accepts for second-factor
+// authenticators.
+func parsePublicKey(der []byte) (*ecdsa.PublicKey, error) {
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, errors.NewNotValid(err, "public key")
+	}
+	ecKey, ok := key.(*ecdsa.PublicKey)
+	if !ok || ecKey.Curve != elliptic.P256() {
+		return nil, errors.NotValidf("public key of type %T (only ECDSA P-256 keys are supported)", key)
+	}
+	return ecKey, nil
+}
+
+// verifyAssertion verifies that the supplied WebAuthn assertion was made,
+// with the user present, by the authenticator holding the private half of
+// publicKey, in response to the given challenge for the given relying
+// party. It returns the signature counter reported by the authenticator.
+func verifyAssertion(publicKey []byte, rpID, challenge string, assertion params.SecondFactorAssertion) (uint32, error) {
+	key, err := parsePublicKey(publicKey)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+
+	var data clientData
+	if err := json.Unmarshal(assertion.ClientDataJSON, &data); err != nil {
+		return 0, errors.NewNotValid(err, "client data")
+	}
+	if data.Type != assertionType {
+		return 0, errors.NotValidf("client data type %q", data.Type)
+	}
+	if subtle.ConstantTimeCompare([]byte(data.Challenge), []byte(challenge)) != 1 {
+		return 0, errors.New("challenge mismatch")
+	}
+
+	authData := assertion.AuthenticatorData
+	if len(authData) < minAuthenticatorDataLen {
+		return 0, errors.NotValidf("authenticator data of length %d", len(authData))
+	}
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	if !bytes.Equal(authData[:sha256.Size], rpIDHash[:]) {
+		return 0, errors.New("relying party ID mismatch")
+	}
+	if authData[sha256.Size]&flagUserPresent == 0 {
+		return 0, errors.New("user presence not asserted")
+	}
+
+	var sig struct {
+		R, S *big.Int
+	}
+	if rest, err := asn1.Unmarshal(assertion.Signature, &sig); err != nil || len(rest) != 0 {
+		return 0, errors.NotValidf("signature")
+	}
+	clientDataHash := sha256.Sum256(assertion.ClientDataJSON)
+	signed := make([]byte, 0, len(authData)+len(clientDataHash))
+	signed = append(signed, authData...)
+	signed = append(signed, clientDataHash[:]...)
+	digest := sha256.Sum256(signed)
+	if !ecdsa.Verify(key, digest[:], sig.R, sig.S) {
+		return 0, errors.New("signature verification failed")
+	}
+	return binary.BigEndian.Uint32(authData[sha256.Size+1:]), nil
+}
+
+// checkSignCount returns an error if an assertion's signature counter
+// doesn't exceed the last one recorded, which suggests that the
+// authenticator has been cloned. Authenticators that don't implement a
+// counter always report zero.
+func checkSignCount(last, count uint32) error {
+	if (last != 0 || count != 0) && count <= last {
+		return errors.Errorf("sign count %d does not exceed %d", count, last)
+	}
+	return nil
+}
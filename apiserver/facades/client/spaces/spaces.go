@@ -39,10 +39,15 @@ type APIv3 struct {
 
 // APIv4 provides the spaces API facade for version 4.
 type APIv4 struct {
+	*APIv5
+}
+
+// APIv5 provides the spaces API facade for version 5.
+type APIv5 struct {
 	*API
 }
 
-// API provides the spaces API facade for version 5.
+// API provides the spaces API facade for version 6.
 type API struct {
 	backing    networkingcommon.NetworkBacking
 	resources  facade.Resources
@@ -72,13 +77,22 @@ func NewAPIv3(st *state.State, res facade.Resources, auth facade.Authorizer) (*A
 
 // NewAPIv4 is a wrapper that creates a V4 spaces API.
 func NewAPIv4(st *state.State, res facade.Resources, auth facade.Authorizer) (*APIv4, error) {
-	api, err := NewAPI(st, res, auth)
+	api, err := NewAPIv5(st, res, auth)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 	return &APIv4{api}, nil
 }
 
+// NewAPIv5 is a wrapper that creates a V5 spaces API.
+func NewAPIv5(st *state.State, res facade.Resources, auth facade.Authorizer) (*APIv5, error) {
+	api, err := NewAPI(st, res, auth)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIv5{api}, nil
+}
+
 // NewAPI creates a new Space API server-side facade with a
 // state.State backing.
 func NewAPI(st *state.State, res facade.Resources, auth facade.Authorizer) (*API, error) {
@@ -249,3 +263,92 @@ func (api *API) ReloadSpaces() error {
 	}
 	return errors.Trace(api.backing.ReloadSpaces(env))
 }
+
+// RenameSpace is not available via the V5 API.
+func (u *APIv5) RenameSpace(_, _ struct{}) {}
+
+// RenameSpace renames spaces, updating the constraints, endpoint bindings
+// and controller config settings that refer to them.
+func (api *API) RenameSpace(args params.RenameSpacesParams) (params.ErrorResults, error) {
+	isAdmin, err := api.authorizer.HasPermission(permission.AdminAccess, api.backing.ModelTag())
+	if err != nil && !errors.IsNotFound(err) {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	if !isAdmin {
+		return params.ErrorResults{}, common.ServerError(common.ErrPerm)
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	if err := networkingcommon.SupportsSpaces(api.backing, api.context); err != nil {
+		return params.ErrorResults{}, common.ServerError(errors.Trace(err))
+	}
+	return networkingcommon.RenameSpaces(api.backing, args), nil
+}
+
+// MoveSubnets is not available via the V5 API.
+func (u *APIv5) MoveSubnets(_, _ struct{}) {}
+
+// MoveSubnets moves subnets into other spaces. A move that would leave an
+// endpoint binding or a machine's space requirements unsatisfiable is
+// refused, and its result reports each violation.
+func (api *API) MoveSubnets(args params.MoveSubnetsParams) (params.MoveSubnetsResults, error) {
+	isAdmin, err := api.authorizer.HasPermission(permission.AdminAccess, api.backing.ModelTag())
+	if err != nil && !errors.IsNotFound(err) {
+		return params.MoveSubnetsResults{}, errors.Trace(err)
+	}
+	if !isAdmin {
+		return params.MoveSubnetsResults{}, common.ServerError(common.ErrPerm)
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return params.MoveSubnetsResults{}, errors.Trace(err)
+	}
+	if err := networkingcommon.SupportsSpaces(api.backing, api.context); err != nil {
+		return params.MoveSubnetsResults{}, common.ServerError(errors.Trace(err))
+	}
+	return networkingcommon.MoveSubnets(api.backing, args), nil
+}
+
+// RemoveSpace is not available via the V5 API.
+func (u *APIv5) RemoveSpace(_, _ struct{}) {}
+
+// RemoveSpace removes spaces, moving their subnets to the default space.
+// Unless forced, a space still used by constraints, endpoint bindings or
+// controller settings is not removed.
+func (api *API) RemoveSpace(args params.RemoveSpaceParams) (params.ErrorResults, error) {
+	isAdmin, err := api.authorizer.HasPermission(permission.AdminAccess, api.backing.ModelTag())
+	if err != nil && !errors.IsNotFound(err) {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	if !isAdmin {
+		return params.ErrorResults{}, common.ServerError(common.ErrPerm)
+	}
+	if err := api.check.RemoveAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	if err := networkingcommon.SupportsSpaces(api.backing, api.context); err != nil {
+		return params.ErrorResults{}, common.ServerError(errors.Trace(err))
+	}
+	return networkingcommon.RemoveSpaces(api.backing, args), nil
+}
+
+// NetworkTopology is not available via the V5 API.
+func (u *APIv5) NetworkTopology(_, _ struct{}) {}
+
+// NetworkTopology returns the model's spaces and subnets, the network
+// devices and addresses of its machines, and the ports opened on each
+// subnet, in a single document.
+func (api *API) NetworkTopology() (params.NetworkTopology, error) {
+	canRead, err := api.authorizer.HasPermission(permission.ReadAccess, api.backing.ModelTag())
+	if err != nil && !errors.IsNotFound(err) {
+		return params.NetworkTopology{}, errors.Trace(err)
+	}
+	if !canRead {
+		return params.NetworkTopology{}, common.ServerError(common.ErrPerm)
+	}
+	if err := networkingcommon.SupportsSpaces(api.backing, api.context); err != nil {
+		return params.NetworkTopology{}, common.ServerError(errors.Trace(err))
+	}
+	topology, err := networkingcommon.NetworkTopology(api.backing)
+	return topology, errors.Trace(err)
+}
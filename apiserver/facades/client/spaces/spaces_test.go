@@ -13,11 +13,13 @@ import (
 	"gopkg.in/juju/names.v3"
 
 	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/common/networkingcommon"
 	"github.com/juju/juju/apiserver/facades/client/spaces"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
 )
 
@@ -148,6 +150,13 @@ func (s *SpacesSuite) checkAddSpaces(c *gc.C, p checkAddSpacesParams) {
 		apiservertesting.ZonedNetworkingEnvironCall("SupportsSpaces", s.callContext),
 	}
 
+	if len(p.Subnets) > 0 && (p.Error == "" || p.MakesCall) {
+		baseCalls = append(baseCalls,
+			apiservertesting.BackingCall("ModelConfig"),
+			apiservertesting.BackingCall("AllSubnets"),
+		)
+	}
+
 	// AddSpace from the api always uses an empty ProviderId.
 	addSpaceCalls := append(
 		baseCalls, apiservertesting.BackingCall("AddSpace", p.Name, network.Id(""), p.Subnets, p.Public),
@@ -191,6 +200,8 @@ func (s *SpacesSuite) TestAddSpacesAPIError(c *gc.C) {
 		nil,                                // Backing.CloudSpec()
 		nil,                                // Provider.Open()
 		nil,                                // ZonedNetworkingEnviron.SupportsSpaces()
+		nil,                                // Backing.ModelConfig()
+		nil,                                // Backing.AllSubnets()
 		errors.AlreadyExistsf("space-foo"), // Backing.AddSpace()
 	)
 	p := checkAddSpacesParams{
@@ -412,7 +423,7 @@ func (s *SpacesSuite) TestCreateSpacesBlocked(c *gc.C) {
 }
 
 func (s *SpacesSuite) TestCreateSpacesAPIv4(c *gc.C) {
-	apiV4 := &spaces.APIv4{s.facade}
+	apiV4 := &spaces.APIv4{&spaces.APIv5{s.facade}}
 	results, err := apiV4.CreateSpaces(params.CreateSpacesParamsV4{
 		Spaces: []params.CreateSpaceParamsV4{
 			{
@@ -427,7 +438,7 @@ func (s *SpacesSuite) TestCreateSpacesAPIv4(c *gc.C) {
 }
 
 func (s *SpacesSuite) TestCreateSpacesAPIv4FailCIDR(c *gc.C) {
-	apiV4 := &spaces.APIv4{s.facade}
+	apiV4 := &spaces.APIv4{&spaces.APIv5{s.facade}}
 	results, err := apiV4.CreateSpaces(params.CreateSpacesParamsV4{
 		Spaces: []params.CreateSpaceParamsV4{
 			{
@@ -442,7 +453,7 @@ func (s *SpacesSuite) TestCreateSpacesAPIv4FailCIDR(c *gc.C) {
 }
 
 func (s *SpacesSuite) TestCreateSpacesAPIv4FailTag(c *gc.C) {
-	apiV4 := &spaces.APIv4{s.facade}
+	apiV4 := &spaces.APIv4{&spaces.APIv5{s.facade}}
 	results, err := apiV4.CreateSpaces(params.CreateSpacesParamsV4{
 		Spaces: []params.CreateSpaceParamsV4{
 			{
@@ -471,6 +482,235 @@ func (s *SpacesSuite) TestReloadSpacesUserDenied(c *gc.C) {
 	apiservertesting.CheckMethodCalls(c, apiservertesting.SharedStub)
 }
 
+func (s *SpacesSuite) TestRenameSpace(c *gc.C) {
+	results, err := s.facade.RenameSpace(params.RenameSpacesParams{
+		Changes: []params.RenameSpaceParams{{
+			FromSpaceTag: "space-dmz",
+			ToSpaceTag:   "space-perimeter",
+		}, {
+			FromSpaceTag: "space-missing",
+			ToSpaceTag:   "space-found",
+		}, {
+			FromSpaceTag: "space-private",
+			ToSpaceTag:   "invalid",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Check(results.Results[0].Error, gc.IsNil)
+	c.Check(results.Results[1].Error, gc.ErrorMatches, `space "missing" not found`)
+	c.Check(results.Results[2].Error, gc.ErrorMatches, `"invalid" is not a valid tag`)
+
+	spaces, err := apiservertesting.BackingInstance.AllSpaces()
+	c.Assert(err, jc.ErrorIsNil)
+	var spaceNames []string
+	for _, space := range spaces {
+		spaceNames = append(spaceNames, space.Name())
+	}
+	c.Check(spaceNames, jc.SameContents, []string{"default", "perimeter", "private"})
+}
+
+func (s *SpacesSuite) TestRenameSpaceBlocked(c *gc.C) {
+	s.blockChecker.SetErrors(common.ServerError(common.OperationBlockedError("test block")))
+	_, err := s.facade.RenameSpace(params.RenameSpacesParams{})
+	c.Assert(err, gc.ErrorMatches, "test block")
+	c.Assert(err, jc.Satisfies, params.IsCodeOperationBlocked)
+}
+
+func (s *SpacesSuite) TestRenameSpaceUserDenied(c *gc.C) {
+	agentAuthorizer := s.authorizer
+	agentAuthorizer.Tag = names.NewUserTag("regular")
+	facade, err := spaces.NewAPIWithBacking(
+		apiservertesting.BackingInstance,
+		&s.blockChecker,
+		context.NewCloudCallContext(),
+		s.resources, agentAuthorizer,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = facade.RenameSpace(params.RenameSpacesParams{})
+	c.Check(err, gc.ErrorMatches, "permission denied")
+	apiservertesting.CheckMethodCalls(c, apiservertesting.SharedStub)
+}
+
+func (s *SpacesSuite) TestMoveSubnets(c *gc.C) {
+	apiservertesting.SharedStub.SetErrors(
+		nil, // Backing.ModelConfig()
+		nil, // Backing.CloudSpec()
+		nil, // Provider.Open()
+		nil, // ZonedNetworkingEnviron.SupportsSpaces()
+		nil, // Backing.MoveSubnets()
+		&state.MoveSubnetsViolationsError{
+			Violations: []state.MoveSubnetsViolation{{
+				Tag:    "application-mysql",
+				Reason: "endpoint \"server\" is bound to space \"db\"",
+			}},
+		}, // Backing.MoveSubnets()
+	)
+
+	results, err := s.facade.MoveSubnets(params.MoveSubnetsParams{
+		Args: []params.MoveSubnetsParam{{
+			SpaceTag: "space-dmz",
+			CIDRs:    []string{"192.168.1.0/24"},
+		}, {
+			SpaceTag: "space-private",
+			CIDRs:    []string{"192.168.2.0/24"},
+		}, {
+			SpaceTag: "space-private",
+			CIDRs:    []string{"bad"},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Check(results.Results[0], jc.DeepEquals, params.MoveSubnetsResult{})
+	c.Check(results.Results[1].Violations, jc.DeepEquals, []params.MoveSubnetsViolation{{
+		Tag:    "application-mysql",
+		Reason: "endpoint \"server\" is bound to space \"db\"",
+	}})
+	c.Check(results.Results[1].Error, gc.ErrorMatches, "moving subnets would break network requirements: .*")
+	c.Check(results.Results[2].Error, gc.ErrorMatches, `CIDR "bad" not valid`)
+
+	apiservertesting.SharedStub.CheckCall(c, 4, "MoveSubnets", "dmz", []string{"192.168.1.0/24"})
+	apiservertesting.SharedStub.CheckCall(c, 5, "MoveSubnets", "private", []string{"192.168.2.0/24"})
+}
+
+func (s *SpacesSuite) TestMoveSubnetsBlocked(c *gc.C) {
+	s.blockChecker.SetErrors(common.ServerError(common.OperationBlockedError("test block")))
+	_, err := s.facade.MoveSubnets(params.MoveSubnetsParams{})
+	c.Assert(err, gc.ErrorMatches, "test block")
+	c.Assert(err, jc.Satisfies, params.IsCodeOperationBlocked)
+}
+
+func (s *SpacesSuite) TestRemoveSpace(c *gc.C) {
+	results, err := s.facade.RemoveSpace(params.RemoveSpaceParams{
+		Args: []params.RemoveSpaceParam{{
+			SpaceTag: "space-dmz",
+			Force:    true,
+		}, {
+			SpaceTag: "space-missing",
+		}, {
+			SpaceTag: "invalid",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Check(results.Results[0].Error, gc.IsNil)
+	c.Check(results.Results[1].Error, gc.ErrorMatches, `space "missing" not found`)
+	c.Check(results.Results[2].Error, gc.ErrorMatches, `"invalid" is not a valid tag`)
+
+	apiservertesting.SharedStub.CheckCall(c, 4, "RemoveSpace", "dmz", true)
+	apiservertesting.SharedStub.CheckCall(c, 5, "RemoveSpace", "missing", false)
+}
+
+func (s *SpacesSuite) TestRemoveSpaceBlocked(c *gc.C) {
+	s.blockChecker.SetErrors(common.ServerError(common.OperationBlockedError("test block")))
+	_, err := s.facade.RemoveSpace(params.RemoveSpaceParams{})
+	c.Assert(err, gc.ErrorMatches, "test block")
+	c.Assert(err, jc.Satisfies, params.IsCodeOperationBlocked)
+	s.blockChecker.CheckCallNames(c, "RemoveAllowed")
+}
+
+func (s *SpacesSuite) TestNetworkTopology(c *gc.C) {
+	apiservertesting.BackingInstance.MachineNetworks = []networkingcommon.BackingMachineNetwork{{
+		MachineId: "0",
+		Devices: []networkingcommon.BackingDevice{{
+			Name:       "eth0",
+			Type:       "ethernet",
+			MACAddress: "aa:bb:cc:dd:ee:f0",
+			Addresses: []networkingcommon.BackingAddress{{
+				Value:      "192.168.1.5",
+				SubnetCIDR: "192.168.1.0/24",
+			}},
+		}},
+		OpenedPorts: map[string][]networkingcommon.BackingPortRange{
+			"192.168.1.0/24": {{
+				UnitName:  "mysql/0",
+				PortRange: network.MustParsePortRange("3306/tcp"),
+			}, {
+				UnitName:  "haproxy/0",
+				PortRange: network.MustParsePortRange("80/tcp"),
+			}},
+			"": {{
+				UnitName:  "mysql/0",
+				PortRange: network.MustParsePortRange("22/tcp"),
+			}},
+		},
+	}}
+
+	topology, err := s.facade.NetworkTopology()
+	c.Assert(err, jc.ErrorIsNil)
+
+	var spaceNames []string
+	for _, space := range topology.Spaces {
+		spaceNames = append(spaceNames, space.Name)
+	}
+	c.Check(spaceNames, jc.DeepEquals, []string{"default", "dmz", "private", "private"})
+	c.Check(topology.Spaces[1].Subnets, gc.HasLen, 1)
+	c.Check(topology.Spaces[1].Subnets[0].CIDR, gc.Equals, "192.168.1.0/24")
+
+	c.Check(topology.Machines, jc.DeepEquals, []params.MachineNetworkTopology{{
+		MachineTag: "machine-0",
+		Devices: []params.NetworkDeviceTopology{{
+			Name:       "eth0",
+			Type:       "ethernet",
+			MACAddress: "aa:bb:cc:dd:ee:f0",
+			Addresses: []params.NetworkAddressTopology{{
+				Value:      "192.168.1.5",
+				SubnetCIDR: "192.168.1.0/24",
+			}},
+		}},
+	}})
+	c.Check(topology.OpenedPorts, jc.DeepEquals, []params.SubnetOpenedPorts{{
+		Ports: []params.OpenedPortRange{{
+			MachineTag: "machine-0",
+			UnitTag:    "unit-mysql-0",
+			PortRange:  params.PortRange{FromPort: 22, ToPort: 22, Protocol: "tcp"},
+		}},
+	}, {
+		SubnetCIDR: "192.168.1.0/24",
+		Ports: []params.OpenedPortRange{{
+			MachineTag: "machine-0",
+			UnitTag:    "unit-haproxy-0",
+			PortRange:  params.PortRange{FromPort: 80, ToPort: 80, Protocol: "tcp"},
+		}, {
+			MachineTag: "machine-0",
+			UnitTag:    "unit-mysql-0",
+			PortRange:  params.PortRange{FromPort: 3306, ToPort: 3306, Protocol: "tcp"},
+		}},
+	}})
+}
+
+func (s *SpacesSuite) TestNetworkTopologyMachinesError(c *gc.C) {
+	apiservertesting.SharedStub.SetErrors(
+		nil,                // Backing.ModelConfig()
+		nil,                // Backing.CloudSpec()
+		nil,                // Provider.Open()
+		nil,                // ZonedNetworkingEnviron.SupportsSpaces()
+		nil,                // Backing.AllSpaces()
+		nil,                // Space.Subnets()
+		nil,                // Space.Subnets()
+		nil,                // Space.Subnets()
+		nil,                // Space.Subnets()
+		errors.New("boom"), // Backing.AllMachineNetworks()
+	)
+	_, err := s.facade.NetworkTopology()
+	c.Assert(err, gc.ErrorMatches, "getting machine networks: boom")
+}
+
+func (s *SpacesSuite) TestNetworkTopologyUserDenied(c *gc.C) {
+	agentAuthorizer := s.authorizer
+	agentAuthorizer.Tag = names.NewUserTag("regular")
+	facade, err := spaces.NewAPIWithBacking(
+		apiservertesting.BackingInstance,
+		&s.blockChecker,
+		context.NewCloudCallContext(),
+		s.resources, agentAuthorizer,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = facade.NetworkTopology()
+	c.Check(err, gc.ErrorMatches, "permission denied")
+	apiservertesting.CheckMethodCalls(c, apiservertesting.SharedStub)
+}
+
 type mockBlockChecker struct {
 	jtesting.Stub
 }
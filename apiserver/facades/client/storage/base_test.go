@@ -75,7 +75,9 @@ func (s *baseStorageSuite) SetUpTest(c *gc.C) {
 	s.apiv3 = &storage.StorageAPIv3{
 		StorageAPIv4: storage.StorageAPIv4{
 			StorageAPIv5: storage.StorageAPIv5{
-				StorageAPI: *newAPI,
+				StorageAPIv6: storage.StorageAPIv6{
+					StorageAPI: *newAPI,
+				},
 			},
 		},
 	}
@@ -112,6 +114,7 @@ const (
 	destroyStorageInstanceCall              = "destroyStorageInstance"
 	releaseStorageInstanceCall              = "releaseStorageInstance"
 	addExistingFilesystemCall               = "addExistingFilesystem"
+	addExistingVolumeCall                   = "addExistingVolume"
 )
 
 func (s *baseStorageSuite) constructState() *mockState {
@@ -320,6 +323,10 @@ func (s *baseStorageSuite) constructStorageAccessor() *mockStorageAccessor {
 			s.stub.AddCall(addExistingFilesystemCall, f, v, storageName)
 			return s.storageTag, s.stub.NextErr()
 		},
+		addExistingVolume: func(v state.VolumeInfo, storageName string) (names.StorageTag, error) {
+			s.stub.AddCall(addExistingVolumeCall, v, storageName)
+			return s.storageTag, s.stub.NextErr()
+		},
 	}
 }
 
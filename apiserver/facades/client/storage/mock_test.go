@@ -71,6 +71,7 @@ type mockStorageAccessor struct {
 	attachStorage                       func(names.StorageTag, names.UnitTag) error
 	detachStorage                       func(names.StorageTag, names.UnitTag, bool) error
 	addExistingFilesystem               func(state.FilesystemInfo, *state.VolumeInfo, string) (names.StorageTag, error)
+	addExistingVolume                   func(state.VolumeInfo, string) (names.StorageTag, error)
 }
 
 func (st *mockStorageAccessor) VolumeAccess() storage.StorageVolume {
@@ -189,6 +190,10 @@ func (st *mockStorageAccessor) AddExistingFilesystem(f state.FilesystemInfo, v *
 	return st.addExistingFilesystem(f, v, s)
 }
 
+func (st *mockStorageAccessor) AddExistingVolume(v state.VolumeInfo, s string) (names.StorageTag, error) {
+	return st.addExistingVolume(v, s)
+}
+
 type mockVolume struct {
 	state.Volume
 	tag     names.VolumeTag
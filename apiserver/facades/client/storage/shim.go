@@ -82,6 +82,9 @@ type storageVolume interface {
 
 	// AddExistingFilesystem imports an existing filesystem into the model.
 	AddExistingFilesystem(f state.FilesystemInfo, v *state.VolumeInfo, storageName string) (names.StorageTag, error)
+
+	// AddExistingVolume imports an existing volume into the model.
+	AddExistingVolume(v state.VolumeInfo, storageName string) (names.StorageTag, error)
 }
 
 type storageFile interface {
@@ -6,6 +6,7 @@
 package storage
 
 import (
+	"sort"
 	"time"
 
 	"github.com/juju/collections/set"
@@ -29,7 +30,7 @@ import (
 	"github.com/juju/juju/storage/poolmanager"
 )
 
-// StorageAPI implements the latest version (v6) of the Storage API.
+// StorageAPI implements the latest version (v7) of the Storage API.
 type StorageAPI struct {
 	backend       backend
 	storageAccess storageAccess
@@ -40,9 +41,14 @@ type StorageAPI struct {
 	modelType     state.ModelType
 }
 
+// StorageAPIv6 implements the storage v6 API.
+type StorageAPIv6 struct {
+	StorageAPI
+}
+
 // APIv5 implements the storage v5 API.
 type StorageAPIv5 struct {
-	StorageAPI
+	StorageAPIv6
 }
 
 // APIv4 implements the storage v4 API adding AddToUnit, Import and Remove (replacing Destroy)
@@ -103,14 +109,25 @@ func newStorageAPI(
 	}
 }
 
+// NewStorageAPIV6 returns a new storage v6 API facade.
+func NewStorageAPIV6(context facade.Context) (*StorageAPIv6, error) {
+	storageAPI, err := NewStorageAPI(context)
+	if err != nil {
+		return nil, err
+	}
+	return &StorageAPIv6{
+		StorageAPI: *storageAPI,
+	}, nil
+}
+
 // NewStorageAPIV5 returns a new storage v5 API facade.
 func NewStorageAPIV5(context facade.Context) (*StorageAPIv5, error) {
-	storageAPI, err := NewStorageAPI(context)
+	storageAPI, err := NewStorageAPIV6(context)
 	if err != nil {
 		return nil, err
 	}
 	return &StorageAPIv5{
-		StorageAPI: *storageAPI,
+		StorageAPIv6: *storageAPI,
 	}, nil
 }
 
@@ -194,7 +211,19 @@ func (a *StorageAPI) StorageDetails(entities params.Entities) (params.StorageDet
 	return params.StorageDetailsResults{Results: results}, nil
 }
 
-// ListStorageDetails returns storage matching a filter.
+// ListStorageDetails returns storage matching a filter. Version 6 of
+// the facade can't page the results.
+func (a *StorageAPIv6) ListStorageDetails(filters params.StorageFilters) (params.StorageDetailsListResults, error) {
+	for _, filter := range filters.Filters {
+		if filter.Page != (params.PageParams{}) {
+			return params.StorageDetailsListResults{}, errors.NotSupportedf("paging storage details")
+		}
+	}
+	return a.StorageAPI.ListStorageDetails(filters)
+}
+
+// ListStorageDetails returns storage matching a filter, a page at a
+// time if the filter requests a page.
 func (a *StorageAPI) ListStorageDetails(filters params.StorageFilters) (params.StorageDetailsListResults, error) {
 	if err := a.checkCanRead(); err != nil {
 		return params.StorageDetailsListResults{}, errors.Trace(err)
@@ -203,39 +232,55 @@ func (a *StorageAPI) ListStorageDetails(filters params.StorageFilters) (params.S
 		Results: make([]params.StorageDetailsListResult, len(filters.Filters)),
 	}
 	for i, filter := range filters.Filters {
-		list, err := a.listStorageDetails(filter)
+		list, page, err := a.listStorageDetails(filter)
 		if err != nil {
 			results.Results[i].Error = common.ServerError(err)
 			continue
 		}
 		results.Results[i].Result = list
+		results.Results[i].Page = page
 	}
 	return results, nil
 }
 
-func (a *StorageAPI) listStorageDetails(filter params.StorageFilter) ([]params.StorageDetails, error) {
-	if filter != (params.StorageFilter{}) {
-		// StorageFilter has no fields at the time of writing, but
-		// check that no fields are set in case we forget to update
+func (a *StorageAPI) listStorageDetails(filter params.StorageFilter) ([]params.StorageDetails, *params.PageInfo, error) {
+	terms := filter
+	terms.Page = params.PageParams{}
+	if terms != (params.StorageFilter{}) {
+		// StorageFilter has no filter terms at the time of writing,
+		// but check that none are set in case we forget to update
 		// this code.
-		return nil, errors.NotSupportedf("storage filters")
+		return nil, nil, errors.NotSupportedf("storage filters")
 	}
 	stateInstances, err := a.storageAccess.AllStorageInstances()
 	if err != nil {
-		return nil, common.ServerError(err)
-	}
-	results := make([]params.StorageDetails, len(stateInstances))
+		return nil, nil, common.ServerError(err)
+	}
+	// Only the details of the storage instances in the page are
+	// fetched, as that's where the time goes.
+	sort.Slice(stateInstances, func(i, j int) bool {
+		return stateInstances[i].Tag().String() < stateInstances[j].Tag().String()
+	})
+	keys := make([]string, len(stateInstances))
 	for i, stateInstance := range stateInstances {
+		keys[i] = stateInstance.Tag().String()
+	}
+	start, end, page, err := common.Paginate(filter.Page, keys)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	results := make([]params.StorageDetails, 0, end-start)
+	for _, stateInstance := range stateInstances[start:end] {
 		details, err := createStorageDetails(a.backend, a.storageAccess, stateInstance)
 		if err != nil {
-			return nil, errors.Annotatef(
+			return nil, nil, errors.Annotatef(
 				err, "getting details for %s",
 				names.ReadableString(stateInstance.Tag()),
 			)
 		}
-		results[i] = *details
+		results = append(results, *details)
 	}
-	return results, nil
+	return results, page, nil
 }
 
 func createStorageDetails(
@@ -1078,8 +1123,7 @@ func (a *StorageAPI) Import(args params.BulkImportStorageParams) (params.ImportS
 }
 
 func (a *StorageAPI) importStorage(arg params.ImportStorageParams) (*params.ImportStorageDetails, error) {
-	if arg.Kind != params.StorageKindFilesystem {
-		// TODO(axw) implement support for volumes.
+	if arg.Kind != params.StorageKindFilesystem && arg.Kind != params.StorageKindBlock {
 		return nil, errors.NotSupportedf("storage kind %q", arg.Kind.String())
 	}
 	if !storage.IsValidPoolName(arg.Pool) {
@@ -1103,18 +1147,85 @@ func (a *StorageAPI) importStorage(arg params.ImportStorageParams) (*params.Impo
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	if arg.Kind == params.StorageKindBlock {
+		return a.importVolume(arg, provider, cfg)
+	}
 	return a.importFilesystem(arg, provider, cfg)
 }
 
-func (a *StorageAPI) importFilesystem(
+// importResourceTags returns the tags to set on imported storage, so
+// that it is seen as being managed by this controller and model.
+func (a *StorageAPI) importResourceTags() map[string]string {
+	return map[string]string{
+		tags.JujuModel:      a.backend.ModelTag().Id(),
+		tags.JujuController: a.backend.ControllerTag().Id(),
+	}
+}
+
+func (a *StorageAPI) importVolume(
 	arg params.ImportStorageParams,
 	provider storage.Provider,
 	cfg *storage.Config,
 ) (*params.ImportStorageDetails, error) {
-	resourceTags := map[string]string{
-		tags.JujuModel:      a.backend.ModelTag().Id(),
-		tags.JujuController: a.backend.ControllerTag().Id(),
+	if !provider.Supports(storage.StorageKindBlock) {
+		return nil, errors.NotSupportedf(
+			"importing volume with storage provider %q",
+			cfg.Provider(),
+		)
+	}
+	volumeInfo, err := a.importProviderVolume(arg, provider, cfg)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	storageTag, err := a.storageAccess.VolumeAccess().AddExistingVolume(*volumeInfo, arg.StorageName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &params.ImportStorageDetails{
+		StorageTag: storageTag.String(),
+	}, nil
+}
+
+// importProviderVolume tags the volume with the given provider ID as
+// being managed by this model, returning the volume information to
+// store in the model. The storage provider is responsible for checking
+// that the volume is compatible with the model, e.g. that it is in a
+// usable availability zone.
+func (a *StorageAPI) importProviderVolume(
+	arg params.ImportStorageParams,
+	provider storage.Provider,
+	cfg *storage.Config,
+) (*state.VolumeInfo, error) {
+	volumeSource, err := provider.VolumeSource(cfg)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	volumeImporter, ok := volumeSource.(storage.VolumeImporter)
+	if !ok {
+		return nil, errors.NotSupportedf(
+			"importing volume with storage provider %q",
+			cfg.Provider(),
+		)
 	}
+	info, err := volumeImporter.ImportVolume(a.callContext, arg.ProviderId, a.importResourceTags())
+	if err != nil {
+		return nil, errors.Annotate(err, "importing volume")
+	}
+	return &state.VolumeInfo{
+		HardwareId: info.HardwareId,
+		WWN:        info.WWN,
+		Size:       info.Size,
+		Pool:       arg.Pool,
+		VolumeId:   info.VolumeId,
+		Persistent: info.Persistent,
+	}, nil
+}
+
+func (a *StorageAPI) importFilesystem(
+	arg params.ImportStorageParams,
+	provider storage.Provider,
+	cfg *storage.Config,
+) (*params.ImportStorageDetails, error) {
 	var volumeInfo *state.VolumeInfo
 	filesystemInfo := state.FilesystemInfo{Pool: arg.Pool}
 
@@ -1132,37 +1243,19 @@ func (a *StorageAPI) importFilesystem(
 				cfg.Provider(),
 			)
 		}
-		info, err := filesystemImporter.ImportFilesystem(a.callContext, arg.ProviderId, resourceTags)
+		info, err := filesystemImporter.ImportFilesystem(a.callContext, arg.ProviderId, a.importResourceTags())
 		if err != nil {
 			return nil, errors.Annotate(err, "importing filesystem")
 		}
 		filesystemInfo.FilesystemId = arg.ProviderId
 		filesystemInfo.Size = info.Size
 	} else {
-		volumeSource, err := provider.VolumeSource(cfg)
+		var err error
+		volumeInfo, err = a.importProviderVolume(arg, provider, cfg)
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
-		volumeImporter, ok := volumeSource.(storage.VolumeImporter)
-		if !ok {
-			return nil, errors.NotSupportedf(
-				"importing volume with storage provider %q",
-				cfg.Provider(),
-			)
-		}
-		info, err := volumeImporter.ImportVolume(a.callContext, arg.ProviderId, resourceTags)
-		if err != nil {
-			return nil, errors.Annotate(err, "importing volume")
-		}
-		volumeInfo = &state.VolumeInfo{
-			HardwareId: info.HardwareId,
-			WWN:        info.WWN,
-			Size:       info.Size,
-			Pool:       arg.Pool,
-			VolumeId:   info.VolumeId,
-			Persistent: info.Persistent,
-		}
-		filesystemInfo.Size = info.Size
+		filesystemInfo.Size = volumeInfo.Size
 	}
 
 	storageTag, err := a.storageAccess.FilesystemAccess().AddExistingFilesystem(filesystemInfo, volumeInfo, arg.StorageName)
@@ -47,6 +47,50 @@ func (s *storageSuite) TestStorageListEmpty(c *gc.C) {
 	s.assertCalls(c, []string{allStorageInstancesCall})
 }
 
+func (s *storageSuite) TestStorageListPaged(c *gc.C) {
+	otherInstance := &mockStorageInstance{
+		kind:       state.StorageKindFilesystem,
+		owner:      s.unitTag,
+		storageTag: names.NewStorageTag("data/1"),
+		life:       state.Alive,
+	}
+	s.storageAccessor.allStorageInstances = func() ([]state.StorageInstance, error) {
+		s.stub.AddCall(allStorageInstancesCall)
+		return []state.StorageInstance{otherInstance, s.storageInstance}, nil
+	}
+
+	found, err := s.api.ListStorageDetails(params.StorageFilters{[]params.StorageFilter{{
+		Page: params.PageParams{Limit: 1},
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found.Results, gc.HasLen, 1)
+	c.Assert(found.Results[0].Error, gc.IsNil)
+	// The details of storage outside the page are not fetched.
+	c.Assert(found.Results[0].Result, gc.HasLen, 1)
+	c.Assert(found.Results[0].Result[0].StorageTag, gc.Equals, s.storageTag.String())
+	c.Assert(found.Results[0].Page, gc.NotNil)
+	c.Assert(found.Results[0].Page.NextCursor, gc.Not(gc.Equals), "")
+}
+
+func (s *storageSuite) TestStorageListInvalidPage(c *gc.C) {
+	found, err := s.api.ListStorageDetails(params.StorageFilters{[]params.StorageFilter{{
+		Page: params.PageParams{Cursor: "!!!"},
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found.Results, gc.HasLen, 1)
+	c.Assert(found.Results[0].Error, gc.ErrorMatches, `invalid page cursor "!!!"`)
+}
+
+func (s *storageSuite) TestStorageListPagedV6(c *gc.C) {
+	apiv6 := &facadestorage.StorageAPIv6{
+		StorageAPI: *s.api,
+	}
+	_, err := apiv6.ListStorageDetails(params.StorageFilters{[]params.StorageFilter{{
+		Page: params.PageParams{Limit: 1},
+	}}})
+	c.Assert(err, gc.ErrorMatches, "paging storage details not supported")
+}
+
 func (s *storageSuite) TestStorageListFilesystem(c *gc.C) {
 	found, err := s.api.ListStorageDetails(
 		params.StorageFilters{[]params.StorageFilter{{}}},
@@ -371,7 +415,9 @@ func (s *storageSuite) TestDestroyV3(c *gc.C) {
 
 func (s *storageSuite) TestDetachV5(c *gc.C) {
 	apiv5 := &facadestorage.StorageAPIv5{
-		StorageAPI: *s.api,
+		StorageAPIv6: facadestorage.StorageAPIv6{
+			StorageAPI: *s.api,
+		},
 	}
 	results, err := apiv5.Detach(params.StorageAttachmentIds{[]params.StorageAttachmentId{
 		{StorageTag: "storage-data-0", UnitTag: "unit-mysql-0"},
@@ -442,7 +488,9 @@ func (s *storageSuite) TestDetachV6(c *gc.C) {
 
 func (s *storageSuite) TestDetachSpecifiedNotFound(c *gc.C) {
 	apiv5 := &facadestorage.StorageAPIv5{
-		StorageAPI: *s.api,
+		StorageAPIv6: facadestorage.StorageAPIv6{
+			StorageAPI: *s.api,
+		},
 	}
 	results, err := apiv5.Detach(params.StorageAttachmentIds{[]params.StorageAttachmentId{
 		{StorageTag: "storage-data-0", UnitTag: "unit-foo-42"},
@@ -512,7 +560,9 @@ func (s *storageSuite) TestDetachAttachmentNotFoundConcurrentv5(c *gc.C) {
 		)
 	}
 	apiv5 := &facadestorage.StorageAPIv5{
-		StorageAPI: *s.api,
+		StorageAPIv6: facadestorage.StorageAPIv6{
+			StorageAPI: *s.api,
+		},
 	}
 	results, err := apiv5.Detach(params.StorageAttachmentIds{[]params.StorageAttachmentId{
 		{StorageTag: "storage-data-0"},
@@ -568,7 +618,9 @@ func (s *storageSuite) TestDetachAttachmentNotFoundConcurrentV6(c *gc.C) {
 
 func (s *storageSuite) TestDetachNoAttachmentsStorageNotFoundv5(c *gc.C) {
 	apiv5 := &facadestorage.StorageAPIv5{
-		StorageAPI: *s.api,
+		StorageAPIv6: facadestorage.StorageAPIv6{
+			StorageAPI: *s.api,
+		},
 	}
 	results, err := apiv5.Detach(params.StorageAttachmentIds{[]params.StorageAttachmentId{
 		{StorageTag: "storage-foo-42"},
@@ -814,12 +866,119 @@ func (s *storageSuite) TestImportFilesystemVolumeBackedNotSupported(c *gc.C) {
 	s.stub.CheckCallNames(c, getBlockForTypeCall)
 }
 
-func (s *storageSuite) TestImportValidationErrors(c *gc.C) {
+func (s *storageSuite) TestImportVolume(c *gc.C) {
+	s.state.modelTag = coretesting.ModelTag
+	volumeSource := volumeImporter{&dummy.VolumeSource{}}
+	dummyStorageProvider := &dummy.StorageProvider{
+		StorageScope: storage.ScopeEnviron,
+		IsDynamic:    true,
+		SupportsFunc: func(kind storage.StorageKind) bool {
+			return kind == storage.StorageKindBlock
+		},
+		VolumeSourceFunc: func(*storage.Config) (storage.VolumeSource, error) {
+			return volumeSource, nil
+		},
+	}
+	s.registry.Providers["radiance"] = dummyStorageProvider
+
+	results, err := s.api.Import(params.BulkImportStorageParams{[]params.ImportStorageParams{{
+		Kind:        params.StorageKindBlock,
+		Pool:        "radiance",
+		ProviderId:  "foo",
+		StorageName: "pgdata",
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.ImportStorageResult{{
+		Result: &params.ImportStorageDetails{
+			StorageTag: "storage-data-0",
+		},
+	}})
+	volumeSource.CheckCalls(c, []testing.StubCall{
+		{"ImportVolume", []interface{}{
+			s.callContext,
+			"foo", map[string]string{
+				"juju-model-uuid":      "deadbeef-0bad-400d-8000-4b1d0d06f00d",
+				"juju-controller-uuid": "deadbeef-1bad-500d-9000-4b1d0d06f00d",
+			},
+		}},
+	})
+	s.stub.CheckCalls(c, []testing.StubCall{
+		{getBlockForTypeCall, []interface{}{state.ChangeBlock}},
+		{addExistingVolumeCall, []interface{}{
+			state.VolumeInfo{
+				VolumeId:   "foo",
+				Pool:       "radiance",
+				Size:       123,
+				HardwareId: "hw",
+			},
+			"pgdata",
+		}},
+	})
+}
+
+func (s *storageSuite) TestImportVolumeError(c *gc.C) {
+	volumeSource := volumeImporter{&dummy.VolumeSource{}}
+	dummyStorageProvider := &dummy.StorageProvider{
+		StorageScope: storage.ScopeEnviron,
+		IsDynamic:    true,
+		SupportsFunc: func(kind storage.StorageKind) bool {
+			return kind == storage.StorageKindBlock
+		},
+		VolumeSourceFunc: func(*storage.Config) (storage.VolumeSource, error) {
+			return volumeSource, nil
+		},
+	}
+	s.registry.Providers["radiance"] = dummyStorageProvider
+
+	volumeSource.SetErrors(errors.New(`cannot import volume "foo" in unknown availability zone "az9"`))
 	results, err := s.api.Import(params.BulkImportStorageParams{[]params.ImportStorageParams{{
 		Kind:        params.StorageKindBlock,
 		Pool:        "radiance",
 		ProviderId:  "foo",
 		StorageName: "pgdata",
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.ImportStorageResult{
+		{Error: &params.Error{Message: `importing volume: cannot import volume "foo" in unknown availability zone "az9"`}},
+	})
+	volumeSource.CheckCallNames(c, "ImportVolume")
+	s.stub.CheckCallNames(c, getBlockForTypeCall)
+}
+
+func (s *storageSuite) TestImportVolumeFilesystemProvider(c *gc.C) {
+	filesystemSource := filesystemImporter{&dummy.FilesystemSource{}}
+	dummyStorageProvider := &dummy.StorageProvider{
+		StorageScope: storage.ScopeEnviron,
+		IsDynamic:    true,
+		FilesystemSourceFunc: func(*storage.Config) (storage.FilesystemSource, error) {
+			return filesystemSource, nil
+		},
+	}
+	s.registry.Providers["radiance"] = dummyStorageProvider
+
+	results, err := s.api.Import(params.BulkImportStorageParams{[]params.ImportStorageParams{{
+		Kind:        params.StorageKindBlock,
+		Pool:        "radiance",
+		ProviderId:  "foo",
+		StorageName: "pgdata",
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.ImportStorageResult{
+		{Error: &params.Error{
+			Message: `importing volume with storage provider "radiance" not supported`,
+			Code:    "not supported",
+		}},
+	})
+	filesystemSource.CheckNoCalls(c)
+	s.stub.CheckCallNames(c, getBlockForTypeCall)
+}
+
+func (s *storageSuite) TestImportValidationErrors(c *gc.C) {
+	results, err := s.api.Import(params.BulkImportStorageParams{[]params.ImportStorageParams{{
+		Kind:        params.StorageKindUnknown,
+		Pool:        "radiance",
+		ProviderId:  "foo",
+		StorageName: "pgdata",
 	}, {
 		Kind:        params.StorageKindFilesystem,
 		Pool:        "123",
@@ -828,7 +987,7 @@ func (s *storageSuite) TestImportValidationErrors(c *gc.C) {
 	}}})
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(results.Results, jc.DeepEquals, []params.ImportStorageResult{
-		{Error: &params.Error{Message: `storage kind "block" not supported`, Code: "not supported"}},
+		{Error: &params.Error{Message: `storage kind "unknown" not supported`, Code: "not supported"}},
 		{Error: &params.Error{Message: `pool name "123" not valid`}},
 	})
 }
@@ -15,18 +15,32 @@ import (
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
 )
 
+// FanBacking describes the model methods used to read and replace
+// its FAN overlay configuration. It is implemented by *state.Model.
+type FanBacking interface {
+	FanConfig() (network.FanConfig, error)
+	SetFanConfig(network.FanConfig) error
+}
+
 // APIv2 provides the subnets API facade for versions < 3.
 type APIv2 struct {
+	*APIv3
+}
+
+// APIv3 provides the subnets API facade for version 3.
+type APIv3 struct {
 	*API
 }
 
-// API provides the subnets API facade for version 3.
+// API provides the subnets API facade for version 4.
 type API struct {
 	backing    networkingcommon.NetworkBacking
+	fans       FanBacking
 	resources  facade.Resources
 	authorizer facade.Authorizer
 	context    context.ProviderCallContext
@@ -34,13 +48,22 @@ type API struct {
 
 // NewAPIv2 is a wrapper that creates a V2 subnets API.
 func NewAPIv2(st *state.State, res facade.Resources, auth facade.Authorizer) (*APIv2, error) {
-	api, err := NewAPI(st, res, auth)
+	api, err := NewAPIv3(st, res, auth)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 	return &APIv2{api}, nil
 }
 
+// NewAPIv3 is a wrapper that creates a V3 subnets API.
+func NewAPIv3(st *state.State, res facade.Resources, auth facade.Authorizer) (*APIv3, error) {
+	api, err := NewAPI(st, res, auth)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIv3{api}, nil
+}
+
 // NewAPI creates a new Subnets API server-side facade with a
 // state.State backing.
 func NewAPI(st *state.State, res facade.Resources, auth facade.Authorizer) (*API, error) {
@@ -48,7 +71,11 @@ func NewAPI(st *state.State, res facade.Resources, auth facade.Authorizer) (*API
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	return newAPIWithBacking(stateshim, state.CallContext(st), res, auth)
+	model, err := st.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return newAPIWithBacking(stateshim, model, state.CallContext(st), res, auth)
 }
 
 func (api *API) checkCanRead() error {
@@ -62,6 +89,17 @@ func (api *API) checkCanRead() error {
 	return nil
 }
 
+func (api *API) checkCanAdmin() error {
+	canAdmin, err := api.authorizer.HasPermission(permission.AdminAccess, api.backing.ModelTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !canAdmin {
+		return common.ServerError(common.ErrPerm)
+	}
+	return nil
+}
+
 func (api *API) checkCanWrite() error {
 	canWrite, err := api.authorizer.HasPermission(permission.WriteAccess, api.backing.ModelTag())
 	if err != nil {
@@ -75,13 +113,14 @@ func (api *API) checkCanWrite() error {
 
 // newAPIWithBacking creates a new server-side Subnets API facade with
 // a common.NetworkBacking
-func newAPIWithBacking(backing networkingcommon.NetworkBacking, ctx context.ProviderCallContext, resources facade.Resources, authorizer facade.Authorizer) (*API, error) {
+func newAPIWithBacking(backing networkingcommon.NetworkBacking, fans FanBacking, ctx context.ProviderCallContext, resources facade.Resources, authorizer facade.Authorizer) (*API, error) {
 	// Only clients can access the Subnets facade.
 	if !authorizer.AuthClient() {
 		return nil, common.ErrPerm
 	}
 	return &API{
 		backing:    backing,
+		fans:       fans,
 		resources:  resources,
 		authorizer: authorizer,
 		context:    ctx,
@@ -155,6 +194,37 @@ func (api *API) ListSubnets(args params.SubnetsFilters) (results params.ListSubn
 	return networkingcommon.ListSubnets(api.backing, args)
 }
 
+// FanConfig returns the FAN overlays configured for the model.
+func (api *API) FanConfig() (params.FanConfigResult, error) {
+	if err := api.checkCanRead(); err != nil {
+		return params.FanConfigResult{}, err
+	}
+	fans, err := api.fans.FanConfig()
+	if err != nil {
+		return params.FanConfigResult{}, errors.Trace(err)
+	}
+	return networkingcommon.FanConfigToFanConfigResult(fans), nil
+}
+
+// SetFanConfig replaces the FAN overlays configured for the model,
+// after checking them against the model's subnets.
+func (api *API) SetFanConfig(args params.SetFanConfigParams) (params.ErrorResult, error) {
+	if err := api.checkCanAdmin(); err != nil {
+		return params.ErrorResult{}, err
+	}
+	fans, err := networkingcommon.FanConfigResultToFanConfig(params.FanConfigResult{Fans: args.Fans})
+	if err == nil {
+		err = api.fans.SetFanConfig(fans)
+	}
+	return params.ErrorResult{Error: common.ServerError(err)}, nil
+}
+
+// FanConfig is not available via the V3 API.
+func (api *APIv3) FanConfig(_, _ struct{}) {}
+
+// SetFanConfig is not available via the V3 API.
+func (api *APIv3) SetFanConfig(_, _ struct{}) {}
+
 func convertToAddSubnetsParams(old params.AddSubnetsParamsV2) (params.AddSubnetsParams, int, error) {
 	new := params.AddSubnetsParams{
 		Subnets: make([]params.AddSubnetParams, len(old.Subnets)),
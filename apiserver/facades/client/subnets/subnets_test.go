@@ -6,6 +6,7 @@ package subnets_test
 import (
 	"github.com/juju/collections/set"
 	"github.com/juju/errors"
+	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v3"
@@ -18,6 +19,7 @@ import (
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/environs/context"
+	jujunetwork "github.com/juju/juju/network"
 	providercommon "github.com/juju/juju/provider/common"
 	coretesting "github.com/juju/juju/testing"
 )
@@ -29,12 +31,32 @@ type SubnetsSuite struct {
 	resources  *common.Resources
 	authorizer apiservertesting.FakeAuthorizer
 	facade     *subnets.API
+	fans       fakeFanBacking
 
 	callContext context.ProviderCallContext
 }
 
 var _ = gc.Suite(&SubnetsSuite{})
 
+type fakeFanBacking struct {
+	testing.Stub
+	fans jujunetwork.FanConfig
+}
+
+func (f *fakeFanBacking) FanConfig() (jujunetwork.FanConfig, error) {
+	f.AddCall("FanConfig")
+	return f.fans, f.NextErr()
+}
+
+func (f *fakeFanBacking) SetFanConfig(fans jujunetwork.FanConfig) error {
+	f.AddCall("SetFanConfig", fans)
+	if err := f.NextErr(); err != nil {
+		return err
+	}
+	f.fans = fans
+	return nil
+}
+
 func (s *SubnetsSuite) SetUpSuite(c *gc.C) {
 	s.StubNetwork.SetUpSuite(c)
 	s.BaseSuite.SetUpSuite(c)
@@ -55,9 +77,11 @@ func (s *SubnetsSuite) SetUpTest(c *gc.C) {
 	}
 
 	s.callContext = context.NewCloudCallContext()
+	s.fans = fakeFanBacking{}
 	var err error
 	s.facade, err = subnets.NewAPIWithBacking(
 		apiservertesting.BackingInstance,
+		&s.fans,
 		s.callContext,
 		s.resources, s.authorizer,
 	)
@@ -102,6 +126,7 @@ func (s *SubnetsSuite) TestNewAPIWithBacking(c *gc.C) {
 	// Clients are allowed.
 	facade, err := subnets.NewAPIWithBacking(
 		apiservertesting.BackingInstance,
+		&s.fans,
 		s.callContext,
 		s.resources, s.authorizer,
 	)
@@ -115,6 +140,7 @@ func (s *SubnetsSuite) TestNewAPIWithBacking(c *gc.C) {
 	agentAuthorizer.Tag = names.NewMachineTag("42")
 	facade, err = subnets.NewAPIWithBacking(
 		apiservertesting.BackingInstance,
+		&s.fans,
 		s.callContext,
 		s.resources, agentAuthorizer,
 	)
@@ -509,7 +535,7 @@ func (s *SubnetsSuite) TestAddSubnetAPI(c *gc.C) {
 func (s *SubnetsSuite) TestAddSubnetAPIv2(c *gc.C) {
 	apiservertesting.BackingInstance.SetUp(c, apiservertesting.StubNetworkingEnvironName,
 		apiservertesting.WithZones, apiservertesting.WithSpaces, apiservertesting.WithSubnets)
-	apiV2 := &subnets.APIv2{s.facade}
+	apiV2 := &subnets.APIv2{&subnets.APIv3{s.facade}}
 	results, err := apiV2.AddSubnets(params.AddSubnetsParamsV2{
 		Subnets: []params.AddSubnetParamsV2{
 			{
@@ -583,3 +609,59 @@ func (s *SubnetsSuite) TestListSubnetsAllSubnetError(c *gc.C) {
 	_, err := s.facade.ListSubnets(params.SubnetsFilters{})
 	c.Assert(err, gc.ErrorMatches, "no subnets for you")
 }
+
+func (s *SubnetsSuite) TestFanConfig(c *gc.C) {
+	fans, err := jujunetwork.ParseFanConfig("172.31.0.0/16=252.0.0.0/8")
+	c.Assert(err, jc.ErrorIsNil)
+	s.fans.fans = fans
+
+	result, err := s.facade.FanConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.FanConfigResult{
+		Fans: []params.FanConfigEntry{{Underlay: "172.31.0.0/16", Overlay: "252.0.0.0/8"}},
+	})
+	s.fans.CheckCallNames(c, "FanConfig")
+}
+
+func (s *SubnetsSuite) TestSetFanConfig(c *gc.C) {
+	result, err := s.facade.SetFanConfig(params.SetFanConfigParams{
+		Fans: []params.FanConfigEntry{{Underlay: "172.31.0.0/16", Overlay: "252.0.0.0/8"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+	s.fans.CheckCallNames(c, "SetFanConfig")
+	c.Check(s.fans.fans.String(), gc.Equals, "172.31.0.0/16=252.0.0.0/8")
+}
+
+func (s *SubnetsSuite) TestSetFanConfigInvalidCIDR(c *gc.C) {
+	result, err := s.facade.SetFanConfig(params.SetFanConfigParams{
+		Fans: []params.FanConfigEntry{{Underlay: "172.31.0.0/16", Overlay: "bad"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.ErrorMatches, "invalid CIDR address: bad")
+	s.fans.CheckNoCalls(c)
+}
+
+func (s *SubnetsSuite) TestSetFanConfigRejected(c *gc.C) {
+	s.fans.SetErrors(errors.NotValidf("FAN overlay"))
+	result, err := s.facade.SetFanConfig(params.SetFanConfigParams{
+		Fans: []params.FanConfigEntry{{Underlay: "172.31.0.0/16", Overlay: "252.0.0.0/8"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.ErrorMatches, "FAN overlay not valid")
+}
+
+func (s *SubnetsSuite) TestSetFanConfigRequiresAdmin(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("write")
+	facade, err := subnets.NewAPIWithBacking(
+		apiservertesting.BackingInstance,
+		&s.fans,
+		s.callContext,
+		s.resources, s.authorizer,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = facade.SetFanConfig(params.SetFanConfigParams{})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	s.fans.CheckNoCalls(c)
+}
@@ -4,6 +4,8 @@
 package actionpruner
 
 import (
+	"github.com/juju/errors"
+
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
@@ -35,5 +37,11 @@ func (api *API) Prune(p params.ActionPruneArgs) error {
 		return common.ErrPerm
 	}
 
-	return state.PruneActions(api.st, p.MaxHistoryTime, p.MaxHistoryMB)
+	if err := state.PruneActions(api.st, p.MaxHistoryTime, p.MaxHistoryMB); err != nil {
+		return errors.Trace(err)
+	}
+	// The calls recorded under the idempotency keys of enqueued actions,
+	// and of the model's other mutating calls, expire on their own
+	// schedule, and are pruned along with the actions.
+	return errors.Trace(state.PruneIdempotencyKeys(api.st))
 }
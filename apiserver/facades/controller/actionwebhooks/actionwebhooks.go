@@ -0,0 +1,115 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package actionwebhooks implements the API used by the worker that
+// invokes the webhooks of operations whose actions have completed.
+package actionwebhooks
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+)
+
+// API implements the ActionWebhooks facade.
+type API struct {
+	backend   Backend
+	resources facade.Resources
+}
+
+// NewFacade creates a new ActionWebhooks facade.
+func NewFacade(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*API, error) {
+	model, err := st.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return NewAPI(model, resources, authorizer)
+}
+
+// NewAPI creates a new ActionWebhooks facade backed by the given
+// Backend.
+func NewAPI(backend Backend, resources facade.Resources, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthController() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		backend:   backend,
+		resources: resources,
+	}, nil
+}
+
+// WatchActionWebhooks returns a StringsWatcher that notifies of the ids
+// of operations whose webhooks have been added, changed or removed.
+func (api *API) WatchActionWebhooks() (params.StringsWatchResult, error) {
+	watch := api.backend.WatchActionWebhooks()
+	if changes, ok := <-watch.Changes(); ok {
+		return params.StringsWatchResult{
+			StringsWatcherId: api.resources.Register(watch),
+			Changes:          changes,
+		}, nil
+	}
+	return params.StringsWatchResult{
+		Error: common.ServerError(watcher.EnsureErr(watch)),
+	}, nil
+}
+
+// ActionWebhooks returns the webhooks of the given operations, along
+// with the results of their actions for operations that have completed.
+func (api *API) ActionWebhooks(args params.ActionWebhookOperations) (params.ActionWebhookResults, error) {
+	results := params.ActionWebhookResults{
+		Results: make([]params.ActionWebhookResult, len(args.Operations)),
+	}
+	for i, operation := range args.Operations {
+		result, err := api.actionWebhook(operation)
+		if err != nil {
+			result.Error = common.ServerError(err)
+		}
+		result.Operation = operation
+		results.Results[i] = result
+	}
+	return results, nil
+}
+
+func (api *API) actionWebhook(operation string) (params.ActionWebhookResult, error) {
+	webhook, err := api.backend.ActionWebhook(operation)
+	if err != nil {
+		return params.ActionWebhookResult{}, errors.Trace(err)
+	}
+	result := params.ActionWebhookResult{
+		URL:       webhook.URL,
+		Secret:    webhook.Secret,
+		Completed: webhook.Completed(),
+	}
+	if !result.Completed {
+		return result, nil
+	}
+	for _, id := range webhook.ActionIds {
+		action, err := api.backend.Action(id)
+		if err != nil {
+			return params.ActionWebhookResult{}, errors.Trace(err)
+		}
+		receiverTag, err := names.ActionReceiverTag(action.Receiver())
+		if err != nil {
+			return params.ActionWebhookResult{}, errors.Trace(err)
+		}
+		result.Actions = append(result.Actions, common.MakeActionResult(receiverTag, action))
+	}
+	return result, nil
+}
+
+// RemoveActionWebhooks removes the webhooks of the given operations,
+// once they have been invoked.
+func (api *API) RemoveActionWebhooks(args params.ActionWebhookOperations) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Operations)),
+	}
+	for i, operation := range args.Operations {
+		results.Results[i].Error = common.ServerError(api.backend.RemoveActionWebhook(operation))
+	}
+	return results, nil
+}
@@ -0,0 +1,130 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actionwebhooks_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facades/controller/actionwebhooks"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ActionWebhooksSuite struct {
+	coretesting.BaseSuite
+
+	backend   *mockBackend
+	resources *common.Resources
+	api       *actionwebhooks.API
+}
+
+var _ = gc.Suite(&ActionWebhooksSuite{})
+
+func (s *ActionWebhooksSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.backend = &mockBackend{
+		webhooks: map[string]state.ActionWebhook{
+			"op-1": {
+				Operation: "op-1",
+				URL:       "https://example.com/hook",
+				Secret:    "s3cret",
+				ActionIds: []string{"mysql-1", "mysql-2"},
+				Pending:   []string{"mysql-2"},
+			},
+			"op-2": {
+				Operation: "op-2",
+				URL:       "https://example.com/hook",
+				ActionIds: []string{"mysql-3"},
+			},
+		},
+		actions: map[string]state.Action{
+			"mysql-3": &mockAction{
+				id:       "mysql-3",
+				receiver: "mysql/0",
+				status:   state.ActionCompleted,
+				message:  "done",
+				output:   map[string]interface{}{"size": "1G"},
+			},
+		},
+		changes: make(chan []string, 1),
+	}
+	s.resources = common.NewResources()
+	s.AddCleanup(func(*gc.C) { s.resources.StopAll() })
+
+	var err error
+	s.api, err = actionwebhooks.NewAPI(s.backend, s.resources, apiservertesting.FakeAuthorizer{
+		Controller: true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ActionWebhooksSuite) TestNewAPIRequiresController(c *gc.C) {
+	_, err := actionwebhooks.NewAPI(s.backend, s.resources, apiservertesting.FakeAuthorizer{})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *ActionWebhooksSuite) TestWatchActionWebhooks(c *gc.C) {
+	s.backend.changes <- []string{"op-1", "op-2"}
+	result, err := s.api.WatchActionWebhooks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.StringsWatchResult{
+		StringsWatcherId: "1",
+		Changes:          []string{"op-1", "op-2"},
+	})
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+}
+
+func (s *ActionWebhooksSuite) TestActionWebhooks(c *gc.C) {
+	s.backend.SetErrors(nil, nil, nil, errors.NotFoundf("webhook for operation %q", "op-3"))
+	results, err := s.api.ActionWebhooks(params.ActionWebhookOperations{
+		Operations: []string{"op-1", "op-2", "op-3"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ActionWebhookResults{
+		Results: []params.ActionWebhookResult{{
+			Operation: "op-1",
+			URL:       "https://example.com/hook",
+			Secret:    "s3cret",
+		}, {
+			Operation: "op-2",
+			URL:       "https://example.com/hook",
+			Completed: true,
+			Actions: []params.ActionResult{{
+				Action: &params.Action{
+					Tag:      "action-mysql-3",
+					Receiver: "unit-mysql-0",
+					Name:     "backup",
+				},
+				Status:  "completed",
+				Message: "done",
+				Output:  map[string]interface{}{"size": "1G"},
+			}},
+		}, {
+			Operation: "op-3",
+			Error: &params.Error{
+				Code:    params.CodeNotFound,
+				Message: `webhook for operation "op-3" not found`,
+			},
+		}},
+	})
+	s.backend.CheckCallNames(c, "ActionWebhook", "ActionWebhook", "Action", "ActionWebhook")
+}
+
+func (s *ActionWebhooksSuite) TestRemoveActionWebhooks(c *gc.C) {
+	s.backend.SetErrors(nil, errors.New("boom"))
+	results, err := s.api.RemoveActionWebhooks(params.ActionWebhookOperations{
+		Operations: []string{"op-1", "op-2"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{}, {Error: &params.Error{Message: "boom"}}},
+	})
+	s.backend.CheckCall(c, 0, "RemoveActionWebhook", "op-1")
+	s.backend.CheckCall(c, 1, "RemoveActionWebhook", "op-2")
+}
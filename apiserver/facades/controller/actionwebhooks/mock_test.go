@@ -0,0 +1,69 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actionwebhooks_test
+
+import (
+	"time"
+
+	"github.com/juju/testing"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type mockBackend struct {
+	testing.Stub
+	webhooks map[string]state.ActionWebhook
+	actions  map[string]state.Action
+	changes  chan []string
+}
+
+func (b *mockBackend) WatchActionWebhooks() state.StringsWatcher {
+	b.MethodCall(b, "WatchActionWebhooks")
+	return statetesting.NewMockStringsWatcher(b.changes)
+}
+
+func (b *mockBackend) ActionWebhook(operation string) (state.ActionWebhook, error) {
+	b.MethodCall(b, "ActionWebhook", operation)
+	if err := b.NextErr(); err != nil {
+		return state.ActionWebhook{}, err
+	}
+	return b.webhooks[operation], nil
+}
+
+func (b *mockBackend) RemoveActionWebhook(operation string) error {
+	b.MethodCall(b, "RemoveActionWebhook", operation)
+	return b.NextErr()
+}
+
+func (b *mockBackend) Action(id string) (state.Action, error) {
+	b.MethodCall(b, "Action", id)
+	if err := b.NextErr(); err != nil {
+		return nil, err
+	}
+	return b.actions[id], nil
+}
+
+type mockAction struct {
+	state.Action
+	id       string
+	receiver string
+	status   state.ActionStatus
+	message  string
+	output   map[string]interface{}
+}
+
+func (a *mockAction) Receiver() string                   { return a.receiver }
+func (a *mockAction) ActionTag() names.ActionTag         { return names.NewActionTag(a.id) }
+func (a *mockAction) Name() string                       { return "backup" }
+func (a *mockAction) Parameters() map[string]interface{} { return nil }
+func (a *mockAction) Status() state.ActionStatus         { return a.status }
+func (a *mockAction) Enqueued() time.Time                { return time.Time{} }
+func (a *mockAction) Started() time.Time                 { return time.Time{} }
+func (a *mockAction) Completed() time.Time               { return time.Time{} }
+
+func (a *mockAction) Results() (map[string]interface{}, string) {
+	return a.output, a.message
+}
@@ -0,0 +1,15 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actionwebhooks
+
+import "github.com/juju/juju/state"
+
+// Backend defines the state functionality used by the ActionWebhooks
+// facade.
+type Backend interface {
+	WatchActionWebhooks() state.StringsWatcher
+	ActionWebhook(operation string) (state.ActionWebhook, error)
+	RemoveActionWebhook(operation string) error
+	Action(id string) (state.Action, error)
+}
@@ -0,0 +1,133 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package annotationtagger implements the API used by the worker that
+// mirrors machine and unit annotations onto the provider tags of
+// machine instances.
+package annotationtagger
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// API implements the AnnotationTagger facade.
+type API struct {
+	backend Backend
+}
+
+// NewFacade creates a new AnnotationTagger facade.
+func NewFacade(st *state.State, _ facade.Resources, authorizer facade.Authorizer) (*API, error) {
+	model, err := st.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return NewAPI(backendShim{st: st, model: model}, authorizer)
+}
+
+// NewAPI creates a new AnnotationTagger facade backed by the given
+// Backend.
+func NewAPI(backend Backend, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthController() {
+		return nil, common.ErrPerm
+	}
+	return &API{backend: backend}, nil
+}
+
+// InstanceTags returns, for each provisioned machine in the model that
+// is not a container or manually provisioned, the annotations of the
+// machine and of its units whose keys have the prefix set by the
+// annotation-tag-prefix model config. Machine annotations take
+// precedence over those of its units. No results are returned if the
+// prefix is empty.
+func (api *API) InstanceTags() (params.InstanceTagsResults, error) {
+	var results params.InstanceTagsResults
+	cfg, err := api.backend.ModelConfig()
+	if err != nil {
+		return results, errors.Trace(err)
+	}
+	prefix := cfg.AnnotationTagPrefix()
+	if prefix == "" {
+		return results, nil
+	}
+	machines, err := api.backend.AllMachines()
+	if err != nil {
+		return results, errors.Trace(err)
+	}
+	for _, m := range machines {
+		if result, ok := api.machineInstanceTags(m, prefix); ok {
+			results.Results = append(results.Results, result)
+		}
+	}
+	return results, nil
+}
+
+// machineInstanceTags returns the tags to set on the instance of the
+// given machine, and false if the machine has no instance to tag.
+func (api *API) machineInstanceTags(m Machine, prefix string) (params.InstanceTagsResult, bool) {
+	result := params.InstanceTagsResult{MachineTag: m.Tag().String()}
+	if m.Life() == state.Dead || m.ContainerType() != "" {
+		return result, false
+	}
+	manual, err := m.IsManual()
+	if err != nil {
+		result.Error = common.ServerError(err)
+		return result, true
+	}
+	if manual {
+		return result, false
+	}
+	instId, err := m.InstanceId()
+	if errors.IsNotProvisioned(err) {
+		return result, false
+	} else if err != nil {
+		result.Error = common.ServerError(err)
+		return result, true
+	}
+	result.InstanceId = string(instId)
+	result.Tags, err = api.instanceTags(m, prefix)
+	if err != nil {
+		result.Error = common.ServerError(err)
+	}
+	return result, true
+}
+
+func (api *API) instanceTags(m Machine, prefix string) (map[string]string, error) {
+	unitTags, err := m.UnitTags()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	sort.Slice(unitTags, func(i, j int) bool {
+		return unitTags[i].Id() < unitTags[j].Id()
+	})
+	tags := make(map[string]string)
+	// The machine's annotations are added last so that they take
+	// precedence over those of its units.
+	entities := make([]names.Tag, 0, len(unitTags)+1)
+	for _, tag := range unitTags {
+		entities = append(entities, tag)
+	}
+	entities = append(entities, m.Tag())
+	for _, tag := range entities {
+		annotations, err := api.backend.Annotations(tag)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Annotatef(err, "getting annotations of %s", names.ReadableString(tag))
+		}
+		for key, value := range annotations {
+			if strings.HasPrefix(key, prefix) {
+				tags[key] = value
+			}
+		}
+	}
+	return tags, nil
+}
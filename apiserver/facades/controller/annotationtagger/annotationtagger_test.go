@@ -0,0 +1,98 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package annotationtagger_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/facades/controller/annotationtagger"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type AnnotationTaggerSuite struct {
+	coretesting.BaseSuite
+
+	backend *mockBackend
+	api     *annotationtagger.API
+}
+
+var _ = gc.Suite(&AnnotationTaggerSuite{})
+
+func (s *AnnotationTaggerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.backend = &mockBackend{
+		cfg: coretesting.CustomModelConfig(c, coretesting.Attrs{
+			"annotation-tag-prefix": "cost-",
+		}),
+		machines: []annotationtagger.Machine{
+			&mockMachine{id: "0", instanceId: "inst-0", units: []string{"mysql/1", "mysql/0"}},
+			&mockMachine{id: "0/lxd/0", instanceId: "juju-lxd-0", containerType: instance.LXD},
+			&mockMachine{id: "1", instanceId: "manual:10.0.0.1", manual: true},
+			&mockMachine{id: "2"},
+			&mockMachine{id: "3", instanceId: "inst-3", life: state.Dead},
+			&mockMachine{id: "4", instanceId: "inst-4"},
+		},
+		annotations: map[string]map[string]string{
+			"machine-0": {"cost-centre": "ops", "notes": "rack 4"},
+			"unit-mysql-0": {
+				"cost-centre":  "db",
+				"cost-project": "billing",
+			},
+			"unit-mysql-1": {"cost-project": "payroll"},
+		},
+	}
+
+	var err error
+	s.api, err = annotationtagger.NewAPI(s.backend, apiservertesting.FakeAuthorizer{
+		Controller: true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *AnnotationTaggerSuite) TestNewAPIRequiresController(c *gc.C) {
+	_, err := annotationtagger.NewAPI(s.backend, apiservertesting.FakeAuthorizer{})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *AnnotationTaggerSuite) TestInstanceTags(c *gc.C) {
+	results, err := s.api.InstanceTags()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.InstanceTagsResults{
+		Results: []params.InstanceTagsResult{{
+			MachineTag: "machine-0",
+			InstanceId: "inst-0",
+			Tags: map[string]string{
+				"cost-centre":  "ops",
+				"cost-project": "payroll",
+			},
+		}, {
+			MachineTag: "machine-4",
+			InstanceId: "inst-4",
+			Tags:       map[string]string{},
+		}},
+	})
+}
+
+func (s *AnnotationTaggerSuite) TestInstanceTagsNoPrefix(c *gc.C) {
+	s.backend.cfg = coretesting.ModelConfig(c)
+	results, err := s.api.InstanceTags()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 0)
+	s.backend.CheckCallNames(c, "ModelConfig")
+}
+
+func (s *AnnotationTaggerSuite) TestInstanceTagsAnnotationsError(c *gc.C) {
+	s.backend.SetErrors(nil, nil, errors.New("boom"))
+	results, err := s.api.InstanceTags()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, "getting annotations of unit mysql/0: boom")
+	c.Assert(results.Results[1].Error, gc.IsNil)
+}
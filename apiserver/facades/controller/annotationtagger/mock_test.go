@@ -0,0 +1,84 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package annotationtagger_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/facades/controller/annotationtagger"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+)
+
+type mockBackend struct {
+	testing.Stub
+	cfg         *config.Config
+	machines    []annotationtagger.Machine
+	annotations map[string]map[string]string
+}
+
+func (b *mockBackend) ModelConfig() (*config.Config, error) {
+	b.MethodCall(b, "ModelConfig")
+	return b.cfg, b.NextErr()
+}
+
+func (b *mockBackend) AllMachines() ([]annotationtagger.Machine, error) {
+	b.MethodCall(b, "AllMachines")
+	return b.machines, b.NextErr()
+}
+
+func (b *mockBackend) Annotations(tag names.Tag) (map[string]string, error) {
+	b.MethodCall(b, "Annotations", tag)
+	if err := b.NextErr(); err != nil {
+		return nil, err
+	}
+	annotations, ok := b.annotations[tag.String()]
+	if !ok {
+		return nil, errors.NotFoundf("%s", names.ReadableString(tag))
+	}
+	return annotations, nil
+}
+
+type mockMachine struct {
+	id            string
+	life          state.Life
+	containerType instance.ContainerType
+	manual        bool
+	instanceId    instance.Id
+	units         []string
+}
+
+func (m *mockMachine) Tag() names.Tag {
+	return names.NewMachineTag(m.id)
+}
+
+func (m *mockMachine) Life() state.Life {
+	return m.life
+}
+
+func (m *mockMachine) ContainerType() instance.ContainerType {
+	return m.containerType
+}
+
+func (m *mockMachine) IsManual() (bool, error) {
+	return m.manual, nil
+}
+
+func (m *mockMachine) InstanceId() (instance.Id, error) {
+	if m.instanceId == "" {
+		return "", errors.NotProvisionedf("machine %v", m.id)
+	}
+	return m.instanceId, nil
+}
+
+func (m *mockMachine) UnitTags() ([]names.UnitTag, error) {
+	tags := make([]names.UnitTag, len(m.units))
+	for i, name := range m.units {
+		tags[i] = names.NewUnitTag(name)
+	}
+	return tags, nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package annotationtagger
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+)
+
+// Backend defines the state functionality used by the AnnotationTagger
+// facade.
+type Backend interface {
+	ModelConfig() (*config.Config, error)
+	AllMachines() ([]Machine, error)
+	Annotations(tag names.Tag) (map[string]string, error)
+}
+
+// Machine defines the machine functionality used by the
+// AnnotationTagger facade.
+type Machine interface {
+	Tag() names.Tag
+	Life() state.Life
+	ContainerType() instance.ContainerType
+	IsManual() (bool, error)
+	InstanceId() (instance.Id, error)
+	UnitTags() ([]names.UnitTag, error)
+}
+
+type backendShim struct {
+	st    *state.State
+	model *state.Model
+}
+
+// ModelConfig is part of the Backend interface.
+func (b backendShim) ModelConfig() (*config.Config, error) {
+	return b.model.ModelConfig()
+}
+
+// AllMachines is part of the Backend interface.
+func (b backendShim) AllMachines() ([]Machine, error) {
+	machines, err := b.st.AllMachines()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]Machine, len(machines))
+	for i, m := range machines {
+		result[i] = machineShim{m}
+	}
+	return result, nil
+}
+
+// Annotations is part of the Backend interface.
+func (b backendShim) Annotations(tag names.Tag) (map[string]string, error) {
+	entity, err := b.st.FindEntity(tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	annotated, ok := entity.(state.GlobalEntity)
+	if !ok {
+		return nil, errors.NotSupportedf("annotations on %s", names.ReadableString(tag))
+	}
+	return b.model.Annotations(annotated)
+}
+
+type machineShim struct {
+	*state.Machine
+}
+
+// UnitTags is part of the Machine interface.
+func (m machineShim) UnitTags() ([]names.UnitTag, error) {
+	units, err := m.Units()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	tags := make([]names.UnitTag, len(units))
+	for i, u := range units {
+		tags[i] = u.UnitTag()
+	}
+	return tags, nil
+}
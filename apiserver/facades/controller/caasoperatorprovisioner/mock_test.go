@@ -13,12 +13,15 @@ import (
 	"github.com/juju/juju/apiserver/facades/controller/caasoperatorprovisioner"
 	"github.com/juju/juju/caas/kubernetes/provider"
 	"github.com/juju/juju/controller"
+	"github.com/juju/juju/core/application"
 	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/storage"
 	"github.com/juju/juju/storage/poolmanager"
 	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/tools"
 )
 
 type mockState struct {
@@ -26,22 +29,43 @@ type mockState struct {
 	common.AddressAndCertGetter
 	model              *mockModel
 	applicationWatcher *mockStringsWatcher
+	configWatcher      *mockNotifyWatcher
 	app                *mockApplication
 	operatorRepo       string
+	controllerAttrs    map[string]interface{}
 }
 
 func newMockState() *mockState {
 	return &mockState{
 		applicationWatcher: newMockStringsWatcher(),
+		configWatcher:      newMockNotifyWatcher(),
 		model:              &mockModel{},
 	}
 }
 
+func (st *mockState) WatchForModelConfigChanges() state.NotifyWatcher {
+	st.MethodCall(st, "WatchForModelConfigChanges")
+	return st.configWatcher
+}
+
+func (st *mockState) ModelConfig() (*config.Config, error) {
+	st.MethodCall(st, "ModelConfig")
+	return st.model.ModelConfig()
+}
+
 func (st *mockState) WatchApplications() state.StringsWatcher {
 	st.MethodCall(st, "WatchApplications")
 	return st.applicationWatcher
 }
 
+func (st *mockState) Application(name string) (caasoperatorprovisioner.Application, error) {
+	st.MethodCall(st, "Application", name)
+	if st.app == nil || st.app.tag.Id() != name {
+		return nil, errors.NotFoundf("application %q", name)
+	}
+	return st.app, nil
+}
+
 func (st *mockState) FindEntity(tag names.Tag) (state.Entity, error) {
 	if st.app.tag == tag {
 		return st.app, nil
@@ -52,6 +76,9 @@ func (st *mockState) FindEntity(tag names.Tag) (state.Entity, error) {
 func (st *mockState) ControllerConfig() (controller.Config, error) {
 	cfg := coretesting.FakeControllerConfig()
 	cfg[controller.CAASImageRepo] = st.operatorRepo
+	for k, v := range st.controllerAttrs {
+		cfg[k] = v
+	}
 	return cfg, nil
 }
 
@@ -93,6 +120,7 @@ func (m *mockStoragePoolManager) Get(name string) (*storage.Config, error) {
 
 type mockModel struct {
 	testing.Stub
+	attrs coretesting.Attrs
 }
 
 func (m *mockModel) UUID() string {
@@ -105,13 +133,19 @@ func (m *mockModel) ModelConfig() (*config.Config, error) {
 	attrs := coretesting.FakeConfig()
 	attrs["operator-storage"] = "k8s-storage"
 	attrs["agent-version"] = "2.6-beta3"
+	for k, v := range m.attrs {
+		attrs[k] = v
+	}
 	return config.New(config.UseDefaults, attrs)
 }
 
 type mockApplication struct {
+	testing.Stub
 	state.Authenticator
 	tag      names.Tag
 	password string
+	config   application.ConfigAttributes
+	tools    *tools.Tools
 }
 
 func (m *mockApplication) Tag() names.Tag {
@@ -127,6 +161,27 @@ func (a *mockApplication) Life() state.Life {
 	return state.Alive
 }
 
+func (a *mockApplication) ApplicationConfig() (application.ConfigAttributes, error) {
+	a.MethodCall(a, "ApplicationConfig")
+	return a.config, a.NextErr()
+}
+
+func (a *mockApplication) SetOperatorStatus(info status.StatusInfo) error {
+	a.MethodCall(a, "SetOperatorStatus", info)
+	return a.NextErr()
+}
+
+func (a *mockApplication) AgentTools() (*tools.Tools, error) {
+	a.MethodCall(a, "AgentTools")
+	if err := a.NextErr(); err != nil {
+		return nil, err
+	}
+	if a.tools == nil {
+		return nil, errors.NotFoundf("operator image metadata for application %q", a.tag.Id())
+	}
+	return a.tools, nil
+}
+
 type mockWatcher struct {
 	testing.Stub
 	tomb.Tomb
@@ -164,3 +219,22 @@ func (w *mockStringsWatcher) Changes() <-chan []string {
 	w.MethodCall(w, "Changes")
 	return w.changes
 }
+
+type mockNotifyWatcher struct {
+	mockWatcher
+	changes chan struct{}
+}
+
+func newMockNotifyWatcher() *mockNotifyWatcher {
+	w := &mockNotifyWatcher{changes: make(chan struct{}, 1)}
+	w.Tomb.Go(func() error {
+		<-w.Tomb.Dying()
+		return nil
+	})
+	return w
+}
+
+func (w *mockNotifyWatcher) Changes() <-chan struct{} {
+	w.MethodCall(w, "Changes")
+	return w.changes
+}
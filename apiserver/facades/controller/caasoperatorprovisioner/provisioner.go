@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/juju/errors"
+	"github.com/juju/schema"
 	"gopkg.in/juju/names.v3"
 
 	"github.com/juju/juju/apiserver/common"
@@ -15,6 +16,7 @@ import (
 	"github.com/juju/juju/caas"
 	"github.com/juju/juju/caas/kubernetes/provider"
 	"github.com/juju/juju/cloudconfig/podcfg"
+	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/tags"
 	"github.com/juju/juju/state"
@@ -28,6 +30,7 @@ type API struct {
 	*common.PasswordChanger
 	*common.LifeGetter
 	*common.APIAddresser
+	*common.ModelWatcher
 
 	auth      facade.Authorizer
 	resources facade.Resources
@@ -35,6 +38,29 @@ type API struct {
 	state              CAASOperatorProvisionerState
 	storagePoolManager poolmanager.PoolManager
 	registry           storage.ProviderRegistry
+
+	// operatorClaims is whether the controller can arbitrate between
+	// the replicas of an operator. If not, every replica would act as
+	// the operator, so only one is provisioned.
+	operatorClaims bool
+}
+
+// APIV1 provides the V1 CAAS operator provisioner API facade, which
+// can't set operator status, watch the model config or report operator
+// versions, and which provides the same operator provisioning info for
+// all applications.
+type APIV1 struct {
+	*API
+}
+
+// NewStateCAASOperatorProvisionerAPIV1 provides the signature required
+// for V1 facade registration.
+func NewStateCAASOperatorProvisionerAPIV1(ctx facade.Context) (*APIV1, error) {
+	api, err := NewStateCAASOperatorProvisionerAPI(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIV1{api}, nil
 }
 
 // NewStateCAASOperatorProvisionerAPI provides the signature required for facade registration.
@@ -50,7 +76,17 @@ func NewStateCAASOperatorProvisionerAPI(ctx facade.Context) (*API, error) {
 	registry := stateenvirons.NewStorageProviderRegistry(broker)
 	pm := poolmanager.New(state.NewStateSettings(ctx.State()), registry)
 
-	return NewCAASOperatorProvisionerAPI(resources, authorizer, stateShim{ctx.State()}, pm, registry)
+	model, err := ctx.State().Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	operatorClaims := true
+	if _, err := ctx.OperatorClaimer(); errors.IsNotSupported(err) {
+		operatorClaims = false
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return NewCAASOperatorProvisionerAPI(resources, authorizer, stateShim{State: ctx.State(), model: model}, pm, registry, operatorClaims)
 }
 
 // NewCAASOperatorProvisionerAPI returns a new CAAS operator provisioner API facade.
@@ -60,6 +96,7 @@ func NewCAASOperatorProvisionerAPI(
 	st CAASOperatorProvisionerState,
 	storagePoolManager poolmanager.PoolManager,
 	registry storage.ProviderRegistry,
+	operatorClaims bool,
 ) (*API, error) {
 	if !authorizer.AuthController() {
 		return nil, common.ErrPerm
@@ -68,11 +105,13 @@ func NewCAASOperatorProvisionerAPI(
 		PasswordChanger:    common.NewPasswordChanger(st, common.AuthFuncForTagKind(names.ApplicationTagKind)),
 		LifeGetter:         common.NewLifeGetter(st, common.AuthFuncForTagKind(names.ApplicationTagKind)),
 		APIAddresser:       common.NewAPIAddresser(st, resources),
+		ModelWatcher:       common.NewModelWatcher(st, resources, authorizer),
 		auth:               authorizer,
 		resources:          resources,
 		state:              st,
 		storagePoolManager: storagePoolManager,
 		registry:           registry,
+		operatorClaims:     operatorClaims,
 	}, nil
 }
 
@@ -90,8 +129,130 @@ func (a *API) WatchApplications() (params.StringsWatchResult, error) {
 	return params.StringsWatchResult{}, watcher.EnsureErr(watch)
 }
 
-// OperatorProvisioningInfo returns the info needed to provision an operator.
-func (a *API) OperatorProvisioningInfo() (params.OperatorProvisioningInfo, error) {
+// SetOperatorStatus sets the operator status of each given application,
+// so that problems provisioning an operator are reported in the status
+// of its application.
+func (a *API) SetOperatorStatus(args params.SetStatus) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	for i, arg := range args.Entities {
+		tag, err := names.ParseApplicationTag(arg.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		info := status.StatusInfo{
+			Status:  status.Status(arg.Status),
+			Message: arg.Info,
+			Data:    arg.Data,
+		}
+		results.Results[i].Error = common.ServerError(a.setOperatorStatus(tag, info))
+	}
+	return results, nil
+}
+
+func (a *API) setOperatorStatus(tag names.ApplicationTag, info status.StatusInfo) error {
+	app, err := a.state.Application(tag.Id())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return app.SetOperatorStatus(info)
+}
+
+// SetOperatorStatus isn't on the V1 API.
+func (*APIV1) SetOperatorStatus(_, _ struct{}) {}
+
+// OperatorVersions returns the agent version reported by the operator
+// of each given application. The version is omitted for operators which
+// haven't reported one yet.
+func (a *API) OperatorVersions(args params.Entities) (params.VersionResults, error) {
+	results := params.VersionResults{
+		Results: make([]params.VersionResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseApplicationTag(entity.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		app, err := a.state.Application(tag.Id())
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		agentTools, err := app.AgentTools()
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].Version = &agentTools.Version.Number
+	}
+	return results, nil
+}
+
+// OperatorVersions isn't on the V1 API.
+func (*APIV1) OperatorVersions(_, _ struct{}) {}
+
+// WatchForModelConfigChanges isn't on the V1 API.
+func (*APIV1) WatchForModelConfigChanges(_, _ struct{}) {}
+
+// ModelConfig isn't on the V1 API.
+func (*APIV1) ModelConfig(_, _ struct{}) {}
+
+// OperatorProvisioningInfo returns the info needed to provision an
+// operator, using the model's operator storage.
+func (a *APIV1) OperatorProvisioningInfo() (params.OperatorProvisioningInfo, error) {
+	return a.operatorProvisioningInfo(caas.ProvisioningModeOperator, "")
+}
+
+// OperatorProvisioningInfo returns the info needed to provision the
+// operators of the given applications. An application's operator
+// storage may be set when it is deployed, overriding the model's.
+func (a *API) OperatorProvisioningInfo(args params.Entities) (params.OperatorProvisioningInfoResults, error) {
+	results := params.OperatorProvisioningInfoResults{
+		Results: make([]params.OperatorProvisioningInfoResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseApplicationTag(entity.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		info, err := a.applicationOperatorProvisioningInfo(tag.Id())
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].Result = &info
+	}
+	return results, nil
+}
+
+func (a *API) applicationOperatorProvisioningInfo(appName string) (params.OperatorProvisioningInfo, error) {
+	app, err := a.state.Application(appName)
+	if err != nil {
+		return params.OperatorProvisioningInfo{}, errors.Trace(err)
+	}
+	appConfig, err := app.ApplicationConfig()
+	if err != nil {
+		return params.OperatorProvisioningInfo{}, errors.Trace(err)
+	}
+	mode := caas.ProvisioningModeOperator
+	if v, _ := appConfig[provider.ProvisioningModeConfigKey].(string); v != "" {
+		mode = caas.ProvisioningMode(v)
+	}
+	storageClassName, _ := appConfig[provider.OperatorStorageConfigKey].(string)
+	return a.operatorProvisioningInfo(mode, storageClassName)
+}
+
+// operatorProvisioningInfo returns the info needed to provision an
+// operator whose charm storage uses the given storage class or pool,
+// or the model's operator storage if none is given. Charms running in
+// sidecar mode have no charm storage.
+func (a *API) operatorProvisioningInfo(mode caas.ProvisioningMode, storageClassName string) (params.OperatorProvisioningInfo, error) {
 	cfg, err := a.state.ControllerConfig()
 	if err != nil {
 		return params.OperatorProvisioningInfo{}, err
@@ -115,13 +276,18 @@ func (a *API) OperatorProvisioningInfo() (params.OperatorProvisioningInfo, error
 	vers.Build = 0
 
 	imagePath := podcfg.GetJujuOCIImagePath(cfg, vers)
-	storageClassName, _ := modelConfig.AllAttrs()[provider.OperatorStorageKey].(string)
-	if storageClassName == "" {
-		return params.OperatorProvisioningInfo{}, errors.New("no operator storage class defined")
-	}
-	charmStorageParams, err := CharmStorageParams(cfg.ControllerUUID(), storageClassName, modelConfig, "", a.storagePoolManager, a.registry)
-	if err != nil {
-		return params.OperatorProvisioningInfo{}, errors.Annotatef(err, "getting operator storage parameters")
+	var charmStorageParams params.KubernetesFilesystemParams
+	if mode != caas.ProvisioningModeSidecar {
+		if storageClassName == "" {
+			storageClassName, _ = modelConfig.AllAttrs()[provider.OperatorStorageKey].(string)
+		}
+		if storageClassName == "" {
+			return params.OperatorProvisioningInfo{}, errors.New("no operator storage class defined")
+		}
+		charmStorageParams, err = CharmStorageParams(cfg.ControllerUUID(), storageClassName, modelConfig, "", a.storagePoolManager, a.registry)
+		if err != nil {
+			return params.OperatorProvisioningInfo{}, errors.Annotatef(err, "getting operator storage parameters")
+		}
 	}
 	apiAddresses, err := a.APIAddresses()
 	if err == nil && apiAddresses.Error != nil {
@@ -136,14 +302,43 @@ func (a *API) OperatorProvisioningInfo() (params.OperatorProvisioningInfo, error
 		names.NewControllerTag(cfg.ControllerUUID()),
 		modelConfig,
 	)
-	charmStorageParams.Tags = resourceTags
+	if mode != caas.ProvisioningModeSidecar {
+		charmStorageParams.Tags = resourceTags
+	}
+
+	attrs := modelConfig.AllAttrs()
+	cpu, _ := attrs[provider.OperatorCPUKey].(string)
+	memory, _ := attrs[provider.OperatorMemoryKey].(string)
+	nodeSelector, _ := attrs[provider.OperatorNodeSelectorKey].(string)
+	nodeAffinity, _ := attrs[provider.OperatorNodeAffinityKey].(string)
+	tolerations, _ := attrs[provider.OperatorTolerationsKey].(string)
+	// Provider attributes are not coerced when read from state, so
+	// the replica count may not be an int.
+	var replicas int
+	if v, err := schema.ForceInt().Coerce(attrs[provider.OperatorReplicasKey], nil); err == nil {
+		replicas = v.(int)
+	}
+	if replicas > 1 && !a.operatorClaims {
+		// Without operator leases, the replicas can't tell which
+		// of them is active, so they would all run the units.
+		replicas = 1
+	}
 
 	return params.OperatorProvisioningInfo{
-		ImagePath:    imagePath,
-		Version:      vers,
-		APIAddresses: apiAddresses.Result,
-		CharmStorage: charmStorageParams,
-		Tags:         resourceTags,
+		ProvisioningMode: string(mode),
+		ImagePath:        imagePath,
+		ImageUsername:    cfg.CAASImageRepoUsername(),
+		ImagePassword:    cfg.CAASImageRepoPassword(),
+		Version:          vers,
+		APIAddresses:     apiAddresses.Result,
+		CharmStorage:     charmStorageParams,
+		Tags:             resourceTags,
+		CPU:              cpu,
+		Memory:           memory,
+		NodeSelector:     provider.SplitConfigList(nodeSelector),
+		NodeAffinity:     provider.SplitConfigList(nodeAffinity),
+		Tolerations:      provider.SplitConfigList(tolerations),
+		Replicas:         replicas,
 	}, nil
 }
 
@@ -14,8 +14,11 @@ import (
 	"github.com/juju/juju/apiserver/facades/controller/caasoperatorprovisioner"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/core/application"
+	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/tools"
 )
 
 var _ = gc.Suite(&CAASProvisionerSuite{})
@@ -26,6 +29,7 @@ type CAASProvisionerSuite struct {
 	resources          *common.Resources
 	authorizer         *apiservertesting.FakeAuthorizer
 	api                *caasoperatorprovisioner.API
+	apiV1              *caasoperatorprovisioner.APIV1
 	st                 *mockState
 	storagePoolManager *mockStoragePoolManager
 	registry           *mockStorageRegistry
@@ -45,16 +49,17 @@ func (s *CAASProvisionerSuite) SetUpTest(c *gc.C) {
 	s.st = newMockState()
 	s.storagePoolManager = &mockStoragePoolManager{}
 	s.registry = &mockStorageRegistry{}
-	api, err := caasoperatorprovisioner.NewCAASOperatorProvisionerAPI(s.resources, s.authorizer, s.st, s.storagePoolManager, s.registry)
+	api, err := caasoperatorprovisioner.NewCAASOperatorProvisionerAPI(s.resources, s.authorizer, s.st, s.storagePoolManager, s.registry, true)
 	c.Assert(err, jc.ErrorIsNil)
 	s.api = api
+	s.apiV1 = &caasoperatorprovisioner.APIV1{api}
 }
 
 func (s *CAASProvisionerSuite) TestPermission(c *gc.C) {
 	s.authorizer = &apiservertesting.FakeAuthorizer{
 		Tag: names.NewMachineTag("0"),
 	}
-	_, err := caasoperatorprovisioner.NewCAASOperatorProvisionerAPI(s.resources, s.authorizer, s.st, s.storagePoolManager, s.registry)
+	_, err := caasoperatorprovisioner.NewCAASOperatorProvisionerAPI(s.resources, s.authorizer, s.st, s.storagePoolManager, s.registry, true)
 	c.Assert(err, gc.ErrorMatches, "permission denied")
 }
 
@@ -119,13 +124,96 @@ func (s *CAASProvisionerSuite) TestLife(c *gc.C) {
 	})
 }
 
+func (s *CAASProvisionerSuite) TestSetOperatorStatus(c *gc.C) {
+	s.st.app = &mockApplication{
+		tag: names.NewApplicationTag("app"),
+	}
+	results, err := s.api.SetOperatorStatus(params.SetStatus{
+		Entities: []params.EntityStatusArgs{{
+			Tag:    "application-app",
+			Status: "error",
+			Info:   "failed to start operator",
+			Data:   map[string]interface{}{"foo": "bar"},
+		}, {
+			Tag:    "application-another",
+			Status: "error",
+		}, {
+			Tag:    "machine-0",
+			Status: "error",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{&params.Error{Message: `application "another" not found`, Code: "not found"}},
+			{&params.Error{Message: "permission denied", Code: "unauthorized access"}},
+		},
+	})
+	s.st.app.CheckCallNames(c, "SetOperatorStatus")
+	s.st.app.CheckCall(c, 0, "SetOperatorStatus", status.StatusInfo{
+		Status:  status.Error,
+		Message: "failed to start operator",
+		Data:    map[string]interface{}{"foo": "bar"},
+	})
+}
+
+func (s *CAASProvisionerSuite) TestWatchForModelConfigChanges(c *gc.C) {
+	s.st.configWatcher.changes <- struct{}{}
+	result, err := s.api.WatchForModelConfigChanges()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.NotifyWatcherId, gc.Equals, "1")
+
+	resource := s.resources.Get("1")
+	c.Assert(resource, gc.NotNil)
+	c.Assert(resource, gc.Implements, new(state.NotifyWatcher))
+}
+
+func (s *CAASProvisionerSuite) TestOperatorVersions(c *gc.C) {
+	s.st.app = &mockApplication{
+		tag:   names.NewApplicationTag("app"),
+		tools: &tools.Tools{Version: version.MustParseBinary("2.6.1-bionic-amd64")},
+	}
+	results, err := s.api.OperatorVersions(params.Entities{
+		Entities: []params.Entity{
+			{Tag: "application-app"},
+			{Tag: "application-another"},
+			{Tag: "machine-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	vers := version.MustParse("2.6.1")
+	c.Assert(results, jc.DeepEquals, params.VersionResults{
+		Results: []params.VersionResult{
+			{Version: &vers},
+			{Error: &params.Error{Message: `application "another" not found`, Code: "not found"}},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
+		},
+	})
+}
+
+func (s *CAASProvisionerSuite) TestOperatorVersionsNotReported(c *gc.C) {
+	s.st.app = &mockApplication{
+		tag: names.NewApplicationTag("app"),
+	}
+	results, err := s.api.OperatorVersions(params.Entities{
+		Entities: []params.Entity{{Tag: "application-app"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.VersionResults{
+		Results: []params.VersionResult{{}},
+	})
+}
+
 func (s *CAASProvisionerSuite) TestOperatorProvisioningInfoDefault(c *gc.C) {
-	result, err := s.api.OperatorProvisioningInfo()
+	result, err := s.apiV1.OperatorProvisioningInfo()
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(result, jc.DeepEquals, params.OperatorProvisioningInfo{
-		ImagePath:    "jujusolutions/jujud-operator:2.6-beta3",
-		Version:      version.MustParse("2.6-beta3"),
-		APIAddresses: []string{"10.0.0.1:1"},
+		ProvisioningMode: "operator",
+		ImagePath:        "jujusolutions/jujud-operator:2.6-beta3",
+		Version:          version.MustParse("2.6-beta3"),
+		APIAddresses:     []string{"10.0.0.1:1"},
 		Tags: map[string]string{
 			"juju-model-uuid":      coretesting.ModelTag.Id(),
 			"juju-controller-uuid": coretesting.ControllerTag.Id()},
@@ -146,12 +234,13 @@ func (s *CAASProvisionerSuite) TestOperatorProvisioningInfoDefault(c *gc.C) {
 
 func (s *CAASProvisionerSuite) TestOperatorProvisioningInfo(c *gc.C) {
 	s.st.operatorRepo = "somerepo"
-	result, err := s.api.OperatorProvisioningInfo()
+	result, err := s.apiV1.OperatorProvisioningInfo()
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(result, jc.DeepEquals, params.OperatorProvisioningInfo{
-		ImagePath:    s.st.operatorRepo + "/jujud-operator:" + "2.6-beta3",
-		Version:      version.MustParse("2.6-beta3"),
-		APIAddresses: []string{"10.0.0.1:1"},
+		ProvisioningMode: "operator",
+		ImagePath:        s.st.operatorRepo + "/jujud-operator:" + "2.6-beta3",
+		Version:          version.MustParse("2.6-beta3"),
+		APIAddresses:     []string{"10.0.0.1:1"},
 		Tags: map[string]string{
 			"juju-model-uuid":      coretesting.ModelTag.Id(),
 			"juju-controller-uuid": coretesting.ControllerTag.Id()},
@@ -170,15 +259,73 @@ func (s *CAASProvisionerSuite) TestOperatorProvisioningInfo(c *gc.C) {
 	})
 }
 
+func (s *CAASProvisionerSuite) TestOperatorProvisioningInfoResources(c *gc.C) {
+	s.st.model.attrs = coretesting.Attrs{
+		"operator-cpu":    "500m",
+		"operator-memory": "256Mi",
+	}
+	result, err := s.apiV1.OperatorProvisioningInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.CPU, gc.Equals, "500m")
+	c.Assert(result.Memory, gc.Equals, "256Mi")
+}
+
+func (s *CAASProvisionerSuite) TestOperatorProvisioningInfoScheduling(c *gc.C) {
+	s.st.model.attrs = coretesting.Attrs{
+		"operator-node-selector": "pool=controllers",
+		"operator-node-affinity": "zone=a|b, ^gpu=true",
+		"operator-tolerations":   "dedicated=juju:NoSchedule,maintenance",
+	}
+	result, err := s.apiV1.OperatorProvisioningInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.NodeSelector, jc.DeepEquals, []string{"pool=controllers"})
+	c.Assert(result.NodeAffinity, jc.DeepEquals, []string{"zone=a|b", "^gpu=true"})
+	c.Assert(result.Tolerations, jc.DeepEquals, []string{"dedicated=juju:NoSchedule", "maintenance"})
+}
+
+func (s *CAASProvisionerSuite) TestOperatorProvisioningInfoReplicas(c *gc.C) {
+	s.st.model.attrs = coretesting.Attrs{
+		"operator-replicas": int64(3),
+	}
+	result, err := s.apiV1.OperatorProvisioningInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Replicas, gc.Equals, 3)
+}
+
+func (s *CAASProvisionerSuite) TestOperatorProvisioningInfoReplicasWithoutOperatorClaims(c *gc.C) {
+	api, err := caasoperatorprovisioner.NewCAASOperatorProvisionerAPI(s.resources, s.authorizer, s.st, s.storagePoolManager, s.registry, false)
+	c.Assert(err, jc.ErrorIsNil)
+	s.st.model.attrs = coretesting.Attrs{
+		"operator-replicas": int64(3),
+	}
+	result, err := (&caasoperatorprovisioner.APIV1{api}).OperatorProvisioningInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Replicas, gc.Equals, 1)
+}
+
+func (s *CAASProvisionerSuite) TestOperatorProvisioningInfoImageCredentials(c *gc.C) {
+	s.st.operatorRepo = "registry.foo.com/me"
+	s.st.controllerAttrs = map[string]interface{}{
+		"caas-image-repo-username": "fred",
+		"caas-image-repo-password": "secret",
+	}
+	result, err := s.apiV1.OperatorProvisioningInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.ImagePath, gc.Equals, "registry.foo.com/me/jujud-operator:2.6-beta3")
+	c.Assert(result.ImageUsername, gc.Equals, "fred")
+	c.Assert(result.ImagePassword, gc.Equals, "secret")
+}
+
 func (s *CAASProvisionerSuite) TestOperatorProvisioningInfoNoStoragePool(c *gc.C) {
 	s.storagePoolManager.SetErrors(errors.NotFoundf("pool"))
 	s.st.operatorRepo = "somerepo"
-	result, err := s.api.OperatorProvisioningInfo()
+	result, err := s.apiV1.OperatorProvisioningInfo()
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(result, jc.DeepEquals, params.OperatorProvisioningInfo{
-		ImagePath:    s.st.operatorRepo + "/jujud-operator:" + "2.6-beta3",
-		Version:      version.MustParse("2.6-beta3"),
-		APIAddresses: []string{"10.0.0.1:1"},
+		ProvisioningMode: "operator",
+		ImagePath:        s.st.operatorRepo + "/jujud-operator:" + "2.6-beta3",
+		Version:          version.MustParse("2.6-beta3"),
+		APIAddresses:     []string{"10.0.0.1:1"},
 		Tags: map[string]string{
 			"juju-model-uuid":      coretesting.ModelTag.Id(),
 			"juju-controller-uuid": coretesting.ControllerTag.Id()},
@@ -196,6 +343,71 @@ func (s *CAASProvisionerSuite) TestOperatorProvisioningInfoNoStoragePool(c *gc.C
 	})
 }
 
+func (s *CAASProvisionerSuite) TestOperatorProvisioningInfoForApplications(c *gc.C) {
+	s.st.app = &mockApplication{
+		tag:    names.NewApplicationTag("gitlab"),
+		config: application.ConfigAttributes{"kubernetes-operator-storage": "fast"},
+	}
+	results, err := s.api.OperatorProvisioningInfo(params.Entities{
+		Entities: []params.Entity{
+			{Tag: "application-gitlab"},
+			{Tag: "application-mysql"},
+			{Tag: "machine-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Result.CharmStorage.Attributes, jc.DeepEquals, map[string]interface{}{
+		"storage-class": "fast",
+		"foo":           "bar",
+	})
+	c.Assert(results.Results[1].Error, jc.DeepEquals, &params.Error{
+		Code:    params.CodeNotFound,
+		Message: `application "mysql" not found`,
+	})
+	c.Assert(results.Results[2].Error, jc.DeepEquals, &params.Error{
+		Code:    params.CodeUnauthorized,
+		Message: "permission denied",
+	})
+	s.storagePoolManager.CheckCall(c, 0, "Get", "fast")
+}
+
+func (s *CAASProvisionerSuite) TestOperatorProvisioningInfoForApplicationDefault(c *gc.C) {
+	s.st.app = &mockApplication{
+		tag: names.NewApplicationTag("gitlab"),
+	}
+	results, err := s.api.OperatorProvisioningInfo(params.Entities{
+		Entities: []params.Entity{{Tag: "application-gitlab"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Result.CharmStorage.Attributes, jc.DeepEquals, map[string]interface{}{
+		"storage-class": "k8s-storage",
+		"foo":           "bar",
+	})
+	s.storagePoolManager.CheckCall(c, 0, "Get", "k8s-storage")
+}
+
+func (s *CAASProvisionerSuite) TestOperatorProvisioningInfoForSidecarApplication(c *gc.C) {
+	s.st.app = &mockApplication{
+		tag:    names.NewApplicationTag("gitlab"),
+		config: application.ConfigAttributes{"kubernetes-provisioning-mode": "sidecar"},
+	}
+	results, err := s.api.OperatorProvisioningInfo(params.Entities{
+		Entities: []params.Entity{{Tag: "application-gitlab"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	result := results.Results[0].Result
+	c.Assert(result.ProvisioningMode, gc.Equals, "sidecar")
+	c.Assert(result.ImagePath, gc.Equals, "jujusolutions/jujud-operator:2.6-beta3")
+	c.Assert(result.CharmStorage, jc.DeepEquals, params.KubernetesFilesystemParams{})
+	s.storagePoolManager.CheckNoCalls(c)
+}
+
 func (s *CAASProvisionerSuite) TestAddresses(c *gc.C) {
 	_, err := s.api.APIAddresses()
 	c.Assert(err, jc.ErrorIsNil)
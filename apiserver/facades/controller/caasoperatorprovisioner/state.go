@@ -7,9 +7,12 @@ import (
 	"gopkg.in/juju/names.v3"
 
 	"github.com/juju/juju/controller"
+	"github.com/juju/juju/core/application"
 	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/tools"
 )
 
 // CAASOperatorProvisionerState provides the subset of global state
@@ -17,12 +20,15 @@ import (
 type CAASOperatorProvisionerState interface {
 	ControllerConfig() (controller.Config, error)
 	WatchApplications() state.StringsWatcher
+	Application(string) (Application, error)
 	FindEntity(tag names.Tag) (state.Entity, error)
 	Addresses() ([]string, error)
 	ModelUUID() string
 	Model() (Model, error)
 	APIHostPortsForAgents() ([][]network.HostPort, error)
 	WatchAPIHostPortsForAgents() state.NotifyWatcher
+	WatchForModelConfigChanges() state.NotifyWatcher
+	ModelConfig() (*config.Config, error)
 }
 
 type Model interface {
@@ -30,8 +36,25 @@ type Model interface {
 	ModelConfig() (*config.Config, error)
 }
 
+// Application provides the subset of application state
+// required by the CAAS operator provisioner facade.
+type Application interface {
+	ApplicationConfig() (application.ConfigAttributes, error)
+	SetOperatorStatus(status.StatusInfo) error
+	AgentTools() (*tools.Tools, error)
+}
+
 type stateShim struct {
 	*state.State
+	model *state.Model
+}
+
+func (s stateShim) ModelConfig() (*config.Config, error) {
+	return s.model.ModelConfig()
+}
+
+func (s stateShim) WatchForModelConfigChanges() state.NotifyWatcher {
+	return s.model.WatchForModelConfigChanges()
 }
 
 func (s stateShim) Model() (Model, error) {
@@ -41,3 +64,11 @@ func (s stateShim) Model() (Model, error) {
 	}
 	return model.CAASModel()
 }
+
+func (s stateShim) Application(name string) (Application, error) {
+	app, err := s.State.Application(name)
+	if err != nil {
+		return nil, err
+	}
+	return app, nil
+}
@@ -49,6 +49,8 @@ func NewCAASOperatorUpgraderAPI(
 }
 
 // UpgradeOperator upgrades the operator for the specified agents.
+// Application operators are upgraded a few at a time by the controller's
+// operator provisioner, so their own requests are accepted but ignored.
 func (api *API) UpgradeOperator(arg params.KubernetesUpgradeArg) (params.ErrorResult, error) {
 	serverErr := func(err error) params.ErrorResult {
 		return params.ErrorResult{common.ServerError(err)}
@@ -61,6 +63,10 @@ func (api *API) UpgradeOperator(arg params.KubernetesUpgradeArg) (params.ErrorRe
 		return serverErr(common.ErrPerm), nil
 	}
 	appName := tag.Id()
+	if tag.Kind() == names.ApplicationTagKind {
+		logger.Debugf("upgrade of caas app %v to %v is rolled out by the controller", appName, arg.Version)
+		return params.ErrorResult{}, nil
+	}
 
 	// Machines representing controllers really mean the controller operator.
 	if tag.Kind() == names.MachineTagKind {
@@ -55,7 +55,8 @@ func (s *CAASProvisionerSuite) TestUpgradeOperator(c *gc.C) {
 	})
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(result.Error, gc.IsNil)
-	s.broker.CheckCall(c, 0, "Upgrade", "app", vers)
+	// The operator provisioner rolls out operator upgrades.
+	s.broker.CheckNoCalls(c)
 }
 
 func (s *CAASProvisionerSuite) TestUpgradeController(c *gc.C) {
@@ -88,13 +88,14 @@ type mockModel struct {
 	testing.Stub
 	podSpecWatcher *statetesting.MockNotifyWatcher
 	containers     []state.CloudContainer
+	attrs          coretesting.Attrs
 }
 
 func (m *mockModel) ModelConfig() (*config.Config, error) {
 	m.MethodCall(m, "ModelConfig")
 	attrs := coretesting.FakeConfig()
 	attrs["workload-storage"] = "k8s-storage"
-	return config.New(config.UseDefaults, attrs)
+	return config.New(config.UseDefaults, attrs.Merge(m.attrs))
 }
 
 func (m *mockModel) PodSpec(tag names.ApplicationTag) (string, error) {
@@ -130,6 +131,7 @@ type mockApplication struct {
 	providerId string
 	addresses  []network.Address
 	charm      *mockCharm
+	config     application.ConfigAttributes
 }
 
 func (a *mockApplication) Tag() names.Tag {
@@ -196,7 +198,7 @@ func (a *mockApplication) GetPlacement() string {
 
 func (a *mockApplication) ApplicationConfig() (application.ConfigAttributes, error) {
 	a.MethodCall(a, "ApplicationConfig")
-	return application.ConfigAttributes{"foo": "bar"}, a.NextErr()
+	return a.config, a.NextErr()
 }
 
 func (m *mockApplication) AllUnits() (units []caasunitprovisioner.Unit, err error) {
@@ -21,6 +21,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/caas"
 	"github.com/juju/juju/caas/kubernetes/provider"
+	"github.com/juju/juju/cloudconfig/podcfg"
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/environs/config"
@@ -311,6 +312,50 @@ func (f *Facade) provisioningInfo(model Model, tagString string) (*params.Kubern
 			ServiceType:    string(deployInfo.ServiceType),
 		}
 	}
+	info.Sidecar, err = sidecarInfo(app, controllerCfg, modelConfig, filesystemParams)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return info, nil
+}
+
+// sidecarInfo returns the info needed to run the charm of an application
+// in sidecar mode, or nil if the charm runs in an operator. The charm
+// container mounts the application's filesystems where the workload does.
+func sidecarInfo(
+	app Application,
+	controllerCfg controller.Config,
+	modelConfig *config.Config,
+	filesystems []params.KubernetesFilesystemParams,
+) (*params.KubernetesSidecarInfo, error) {
+	appConfig, err := app.ApplicationConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	mode := appConfig.GetString(provider.ProvisioningModeConfigKey, string(caas.ProvisioningModeOperator))
+	if caas.ProvisioningMode(mode) != caas.ProvisioningModeSidecar {
+		return nil, nil
+	}
+	vers, ok := modelConfig.AgentVersion()
+	if !ok {
+		return nil, errors.NotValidf("agent version missing from model config %q", modelConfig.Name())
+	}
+	vers.Build = 0
+	info := &params.KubernetesSidecarInfo{
+		ImagePath:     podcfg.GetJujuOCIImagePath(controllerCfg, vers),
+		ImageUsername: controllerCfg.CAASImageRepoUsername(),
+		ImagePassword: controllerCfg.CAASImageRepoPassword(),
+	}
+	for _, fs := range filesystems {
+		if fs.Attachment == nil {
+			continue
+		}
+		info.Mounts = append(info.Mounts, params.KubernetesSidecarMount{
+			StorageName: fs.StorageName,
+			MountPoint:  fs.Attachment.MountPoint,
+			ReadOnly:    fs.Attachment.ReadOnly,
+		})
+	}
 	return info, nil
 }
 
@@ -19,6 +19,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/caas/kubernetes/provider"
+	"github.com/juju/juju/core/application"
 	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/status"
@@ -60,6 +61,7 @@ func (s *CAASProvisionerSuite) SetUpTest(c *gc.C) {
 			life:         state.Alive,
 			scaleWatcher: statetesting.NewMockNotifyWatcher(s.scaleChanges),
 			scale:        5,
+			config:       application.ConfigAttributes{"foo": "bar"},
 		},
 		applicationsWatcher: statetesting.NewMockStringsWatcher(s.applicationsChanges),
 		model: mockModel{
@@ -258,11 +260,49 @@ func (s *CAASProvisionerSuite) TestProvisioningInfo(c *gc.C) {
 			Message: `"unit-gitlab-0" is not a valid application tag`,
 		},
 	})
+	c.Assert(obtained.Sidecar, gc.IsNil)
 	s.st.CheckCallNames(c, "Model", "Application", "ControllerConfig", "ResolveConstraints")
 	s.st.CheckCall(c, 3, "ResolveConstraints", constraints.MustParse("mem=64G"))
 	s.storagePoolManager.CheckCallNames(c, "Get", "Get")
 }
 
+func (s *CAASProvisionerSuite) TestProvisioningInfoSidecar(c *gc.C) {
+	s.st.application.config = application.ConfigAttributes{"kubernetes-provisioning-mode": "sidecar"}
+	s.st.model.attrs = coretesting.Attrs{"agent-version": "2.6.1.2"}
+	s.st.application.charm = &mockCharm{
+		meta: charm.Meta{
+			Storage: map[string]charm.Storage{
+				"data": {
+					Name:     "data",
+					Type:     charm.StorageFilesystem,
+					ReadOnly: true,
+				},
+				"logs": {
+					Name: "logs",
+					Type: charm.StorageFilesystem,
+				},
+			},
+		},
+	}
+
+	results, err := s.facade.ProvisioningInfo(params.Entities{
+		Entities: []params.Entity{{Tag: "application-gitlab"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Result.Sidecar, jc.DeepEquals, &params.KubernetesSidecarInfo{
+		ImagePath: "jujusolutions/jujud-operator:2.6.1",
+		Mounts: []params.KubernetesSidecarMount{{
+			StorageName: "data",
+			MountPoint:  "/var/lib/juju/storage/data/0",
+			ReadOnly:    true,
+		}, {
+			StorageName: "logs",
+			MountPoint:  "/var/lib/juju/storage/logs/0",
+		}},
+	})
+}
+
 func (s *CAASProvisionerSuite) TestApplicationScale(c *gc.C) {
 	results, err := s.facade.ApplicationsScale(params.Entities{
 		Entities: []params.Entity{
@@ -23,6 +23,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/core/crossmodel"
+	"github.com/juju/juju/core/relation"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
@@ -147,8 +148,14 @@ func (s *crossmodelRelationsSuite) assertPublishRelationsChanges(c *gc.C, life p
 			c.Assert(rel.message, gc.Equals, suspendedReason)
 		}
 	} else {
-		c.Assert(rel.status, gc.Equals, status.Status(""))
-		c.Assert(rel.message, gc.Equals, "")
+		c.Assert(rel.status, gc.Equals, status.Broken)
+		c.Assert(rel.message, gc.Equals, `application "db2" departed`)
+		reason, ok := relation.ParseStatusReason(rel.data)
+		c.Assert(ok, jc.IsTrue)
+		c.Assert(reason, jc.DeepEquals, relation.StatusReason{
+			Reason:              relation.ReasonDeparted,
+			DepartedApplication: "db2",
+		})
 		expected = append(expected, testing.StubCall{
 			"RemoteApplication", []interface{}{"db2"},
 		})
@@ -324,6 +324,7 @@ type mockRelation struct {
 	suspendedReason string
 	status          status.Status
 	message         string
+	data            map[string]interface{}
 	units           map[string]commoncrossmodel.RelationUnit
 }
 
@@ -357,6 +358,7 @@ func (r *mockRelation) SetStatus(statusInfo status.StatusInfo) error {
 	r.MethodCall(r, "SetStatus")
 	r.status = statusInfo.Status
 	r.message = statusInfo.Message
+	r.data = statusInfo.Data
 	return nil
 }
 
@@ -14,6 +14,7 @@ import (
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/core/relation"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/watcher"
@@ -51,6 +52,11 @@ type FirewallerAPIV5 struct {
 	*FirewallerAPIV4
 }
 
+// FirewallerAPIV6 provides access to the Firewaller v6 API facade.
+type FirewallerAPIV6 struct {
+	*FirewallerAPIV5
+}
+
 // NewStateFirewallerAPIV3 creates a new server-side FirewallerAPIV3 facade.
 func NewStateFirewallerAPIV3(context facade.Context) (*FirewallerAPIV3, error) {
 	st := context.State()
@@ -92,6 +98,17 @@ func NewStateFirewallerAPIV5(context facade.Context) (*FirewallerAPIV5, error) {
 	}, nil
 }
 
+// NewStateFirewallerAPIV6 creates a new server-side FirewallerAPIV6 facade.
+func NewStateFirewallerAPIV6(context facade.Context) (*FirewallerAPIV6, error) {
+	facadev5, err := NewStateFirewallerAPIV5(context)
+	if err != nil {
+		return nil, err
+	}
+	return &FirewallerAPIV6{
+		FirewallerAPIV5: facadev5,
+	}, nil
+}
+
 // NewFirewallerAPI creates a new server-side FirewallerAPIV3 facade.
 func NewFirewallerAPI(
 	st State,
@@ -460,7 +477,9 @@ func (f *FirewallerAPIV4) MacaroonForRelations(args params.Entities) (params.Mac
 	return result, nil
 }
 
-// SetRelationsStatus sets the status for the specified relations.
+// SetRelationsStatus sets the status for the specified relations. The
+// firewaller sets relations in error when the ingress they require is
+// refused, so that is recorded as the reason for the error.
 func (f *FirewallerAPIV4) SetRelationsStatus(args params.SetStatus) (params.ErrorResults, error) {
 	var result params.ErrorResults
 	result.Results = make([]params.ErrorResult, len(args.Entities))
@@ -475,10 +494,17 @@ func (f *FirewallerAPIV4) SetRelationsStatus(args params.SetStatus) (params.Erro
 			result.Results[i].Error = common.ServerError(err)
 			continue
 		}
-		err = rel.SetStatus(status.StatusInfo{
+		statusInfo := status.StatusInfo{
 			Status:  status.Status(entity.Status),
 			Message: entity.Info,
-		})
+		}
+		if statusInfo.Status == status.Error {
+			statusInfo.Data = relation.StatusReason{
+				Reason: relation.ReasonIngressError,
+				Error:  entity.Info,
+			}.Data()
+		}
+		err = rel.SetStatus(statusInfo)
 		result.Results[i].Error = common.ServerError(err)
 	}
 	return result, nil
@@ -527,3 +553,112 @@ func (f *FirewallerAPIV5) AreManuallyProvisioned(args params.Entities) (params.B
 	}
 	return result, nil
 }
+
+// GetEgressRules returns the egress rules of each given application.
+func (f *FirewallerAPIV6) GetEgressRules(args params.Entities) (params.ApplicationEgressRulesResults, error) {
+	result := params.ApplicationEgressRulesResults{
+		Results: make([]params.ApplicationEgressRulesResult, len(args.Entities)),
+	}
+	canAccess, err := f.accessApplication()
+	if err != nil {
+		return params.ApplicationEgressRulesResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseApplicationTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		application, err := f.getApplication(canAccess, tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		for _, rule := range application.EgressRules() {
+			result.Results[i].Rules = append(result.Results[i].Rules, params.EgressRule{
+				PortRange:        params.FromNetworkPortRange(rule.PortRange),
+				DestinationCIDRs: rule.DestinationCIDRs,
+			})
+		}
+	}
+	return result, nil
+}
+
+// WatchFirewallRules returns a NotifyWatcher which notifies when the
+// model's firewall rules change.
+func (f *FirewallerAPIV6) WatchFirewallRules() (params.NotifyWatchResult, error) {
+	watch := f.st.WatchFirewallRules()
+	// Consume the initial event.
+	if _, ok := <-watch.Changes(); ok {
+		return params.NotifyWatchResult{
+			NotifyWatcherId: f.resources.Register(watch),
+		}, nil
+	}
+	return params.NotifyWatchResult{}, watcher.EnsureErr(watch)
+}
+
+// GetExposeInfo returns the expose flag and per-endpoint expose settings
+// of each given application. The spaces each endpoint is exposed to are
+// resolved to the CIDRs of their subnets, which are included with the
+// endpoint's own CIDRs.
+func (f *FirewallerAPIV6) GetExposeInfo(args params.Entities) (params.ExposeInfoResults, error) {
+	result := params.ExposeInfoResults{
+		Results: make([]params.ExposeInfoResult, len(args.Entities)),
+	}
+	canAccess, err := f.accessApplication()
+	if err != nil {
+		return params.ExposeInfoResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseApplicationTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		application, err := f.getApplication(canAccess, tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		exposedEndpoints, err := f.exposedEndpoints(application.ExposedEndpoints())
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Exposed = application.IsExposed()
+		result.Results[i].ExposedEndpoints = exposedEndpoints
+	}
+	return result, nil
+}
+
+func (f *FirewallerAPIV6) exposedEndpoints(in map[string]state.ExposedEndpoint) (map[string]params.ExposedEndpoint, error) {
+	if len(in) == 0 {
+		return nil, nil
+	}
+	out := make(map[string]params.ExposedEndpoint, len(in))
+	for endpoint, exposed := range in {
+		var (
+			spaceNames []string
+			cidrs      []string
+		)
+		for _, spaceID := range exposed.ExposeToSpaceIDs {
+			space, err := f.st.SpaceByID(spaceID)
+			if err != nil {
+				return nil, errors.Annotatef(err, "endpoint %q", endpoint)
+			}
+			subnets, err := space.Subnets()
+			if err != nil {
+				return nil, errors.Annotatef(err, "endpoint %q", endpoint)
+			}
+			spaceNames = append(spaceNames, space.Name())
+			for _, subnet := range subnets {
+				cidrs = append(cidrs, subnet.CIDR())
+			}
+		}
+		out[endpoint] = params.ExposedEndpoint{
+			ExposeToSpaces: spaceNames,
+			ExposeToCIDRs:  append(cidrs, exposed.ExposeToCIDRs...),
+		}
+	}
+	return out, nil
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/core/crossmodel"
+	"github.com/juju/juju/core/relation"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
@@ -122,6 +123,41 @@ func (s *RemoteFirewallerSuite) TestSetRelationStatus(c *gc.C) {
 	c.Assert(db2Relation.status, jc.DeepEquals, status.StatusInfo{Status: status.Suspended, Message: "a message"})
 }
 
+func (s *RemoteFirewallerSuite) TestSetRelationStatusIngressError(c *gc.C) {
+	db2Relation := newMockRelation(123)
+	s.st.relations["remote-db2:db django:db"] = db2Relation
+	entity := names.NewRelationTag("remote-db2:db django:db")
+	result, err := s.api.SetRelationsStatus(
+		params.SetStatus{Entities: []params.EntityStatusArgs{{
+			Tag:    entity.String(),
+			Status: "error",
+			Info:   "ingress 0.0.0.0/0 forbidden",
+		}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(db2Relation.status, jc.DeepEquals, status.StatusInfo{
+		Status:  status.Error,
+		Message: "ingress 0.0.0.0/0 forbidden",
+		Data: relation.StatusReason{
+			Reason: relation.ReasonIngressError,
+			Error:  "ingress 0.0.0.0/0 forbidden",
+		}.Data(),
+	})
+}
+
+func (s *RemoteFirewallerSuite) TestWatchFirewallRules(c *gc.C) {
+	api := &firewaller.FirewallerAPIV6{&firewaller.FirewallerAPIV5{s.api}}
+	result, err := api.WatchFirewallRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.NotifyWatcherId, gc.Equals, "1")
+
+	resource := s.resources.Get("1")
+	c.Assert(resource, gc.Equals, s.st.rulesWatcher)
+	s.st.CheckCallNames(c, "WatchFirewallRules")
+}
+
 func (s *RemoteFirewallerSuite) TestFirewallRules(c *gc.C) {
 	s.st.firewallRules[state.JujuApplicationOfferRule] = &state.FirewallRule{
 		WellKnownService: state.JujuApplicationOfferRule,
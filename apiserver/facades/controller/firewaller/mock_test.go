@@ -40,6 +40,7 @@ type mockState struct {
 	firewallRules  map[state.WellKnownServiceType]*state.FirewallRule
 	subnetsWatcher *mockStringsWatcher
 	modelWatcher   *mockNotifyWatcher
+	rulesWatcher   *mockNotifyWatcher
 	configAttrs    map[string]interface{}
 }
 
@@ -53,6 +54,7 @@ func newMockState(modelUUID string) *mockState {
 		firewallRules:  make(map[state.WellKnownServiceType]*state.FirewallRule),
 		subnetsWatcher: newMockStringsWatcher(),
 		modelWatcher:   newMockNotifyWatcher(),
+		rulesWatcher:   newMockNotifyWatcher(),
 		configAttrs:    coretesting.FakeConfig(),
 	}
 }
@@ -118,6 +120,11 @@ func (st *mockState) FirewallRule(service state.WellKnownServiceType) (*state.Fi
 	return r, nil
 }
 
+func (st *mockState) WatchFirewallRules() state.NotifyWatcher {
+	st.MethodCall(st, "WatchFirewallRules")
+	return st.rulesWatcher
+}
+
 func (st *mockState) Subnet(cidr string) (firewaller.Subnet, error) {
 	return nil, errors.NotImplementedf("Subnet")
 }
@@ -126,6 +133,10 @@ func (st *mockState) SubnetByID(id string) (firewaller.Subnet, error) {
 	return nil, errors.NotImplementedf("SubnetByID")
 }
 
+func (st *mockState) SpaceByID(id string) (firewaller.Space, error) {
+	return nil, errors.NotImplementedf("SpaceByID")
+}
+
 type mockWatcher struct {
 	testing.Stub
 	tomb.Tomb
@@ -26,9 +26,13 @@ type State interface {
 
 	FirewallRule(service state.WellKnownServiceType) (*state.FirewallRule, error)
 
+	WatchFirewallRules() state.NotifyWatcher
+
 	SubnetByID(id string) (Subnet, error)
 
 	Subnet(cidr string) (Subnet, error)
+
+	SpaceByID(id string) (Space, error)
 }
 
 // TODO(wallyworld) - for tests, remove when remaining firewaller tests become unit tests.
@@ -63,6 +67,10 @@ func (s stateShim) FirewallRule(service state.WellKnownServiceType) (*state.Fire
 	return api.Rule(service)
 }
 
+func (s stateShim) WatchFirewallRules() state.NotifyWatcher {
+	return s.st.WatchFirewallRules()
+}
+
 type Subnet interface {
 	ID() string
 	CIDR() string
@@ -75,3 +83,12 @@ func (s stateShim) SubnetByID(id string) (Subnet, error) {
 func (s stateShim) Subnet(cidr string) (Subnet, error) {
 	return s.st.Subnet(cidr)
 }
+
+type Space interface {
+	Name() string
+	Subnets() ([]*state.Subnet, error)
+}
+
+func (s stateShim) SpaceByID(id string) (Space, error) {
+	return s.st.SpaceByID(id)
+}
@@ -0,0 +1,76 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package importvalidator defines the API facade with which a model
+// description can be checked for import into the controller before a
+// migration or import is attempted. The model is checked against the
+// controller, its charm store and the target cloud; it is not imported.
+package importvalidator
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/migration"
+	"github.com/juju/juju/permission"
+)
+
+// API implements the ImportValidator facade.
+type API struct {
+	backend migration.ImportCheckBackend
+	target  migration.ImportCheckTarget
+}
+
+// NewFacade is used for API registration.
+func NewFacade(ctx facade.Context) (*API, error) {
+	st := ctx.StatePool().SystemState()
+	return NewAPI(st, importTarget{st}, ctx.Auth(), st.ControllerTag())
+}
+
+// NewAPI returns a new ImportValidator API for the controller with the
+// given tag.
+func NewAPI(
+	backend migration.ImportCheckBackend,
+	target migration.ImportCheckTarget,
+	authorizer facade.Authorizer, controllerTag names.ControllerTag) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	// The facade is only accessible to controller administrators.
+	isAdmin, err := authorizer.HasPermission(permission.SuperuserAccess, controllerTag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !isAdmin {
+		return nil, common.ErrPerm
+	}
+	return &API{backend: backend, target: target}, nil
+}
+
+// ValidateImport checks, without importing it, whether the serialized
+// model could be imported into the controller. It reports any problems
+// found with the model's schema, its cloud and credential, the
+// availability of its charm store charms, and the mapping of its spaces
+// and subnets onto the target cloud.
+func (api *API) ValidateImport(serialized params.SerializedModel) (params.ImportValidationReport, error) {
+	report, err := migration.CheckImport(api.backend, api.target, serialized.Bytes)
+	if err != nil {
+		return params.ImportValidationReport{}, errors.Trace(err)
+	}
+	result := params.ImportValidationReport{
+		ModelUUID: report.ModelUUID,
+		ModelName: report.ModelName,
+		Valid:     report.Valid(),
+	}
+	for _, issue := range report.Issues {
+		result.Issues = append(result.Issues, params.ImportValidationIssue{
+			Kind:    issue.Kind,
+			Entity:  issue.Entity,
+			Message: issue.Message,
+		})
+	}
+	return result, nil
+}
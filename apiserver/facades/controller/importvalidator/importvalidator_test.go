@@ -0,0 +1,188 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package importvalidator_test
+
+import (
+	"github.com/juju/description"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facades/controller/importvalidator"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/cloud"
+	corenetwork "github.com/juju/juju/core/network"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type importValidatorSuite struct {
+	testing.IsolationSuite
+
+	backend    *mockBackend
+	target     *mockTarget
+	authorizer apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&importValidatorSuite{})
+
+func (s *importValidatorSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.backend = &mockBackend{
+		clouds: map[string]cloud.Cloud{
+			"dummy": {
+				Name:    "dummy",
+				Regions: []cloud.Region{{Name: "dummy-region"}},
+			},
+		},
+	}
+	s.target = &mockTarget{}
+	owner := names.NewUserTag("admin")
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag:      owner,
+		AdminTag: owner,
+	}
+}
+
+func (s *importValidatorSuite) newAPI(c *gc.C) *importvalidator.API {
+	api, err := importvalidator.NewAPI(s.backend, s.target, s.authorizer, coretesting.ControllerTag)
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *importValidatorSuite) TestNotUser(c *gc.C) {
+	s.authorizer.Tag = names.NewMachineTag("0")
+	_, err := importvalidator.NewAPI(s.backend, s.target, s.authorizer, coretesting.ControllerTag)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *importValidatorSuite) TestNotControllerAdmin(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("jrandomuser")
+	_, err := importvalidator.NewAPI(s.backend, s.target, s.authorizer, coretesting.ControllerTag)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *importValidatorSuite) TestValidateImport(c *gc.C) {
+	report, err := s.newAPI(c).ValidateImport(params.SerializedModel{
+		Bytes: s.serialize(c, "dummy", "dummy-region"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(report.ModelUUID, gc.Equals, coretesting.ModelTag.Id())
+	c.Check(report.ModelName, gc.Equals, "testmodel")
+	c.Check(report.Valid, gc.Equals, len(report.Issues) == 0)
+	c.Check(issuesOfKind(report, "cloud"), gc.HasLen, 0)
+	c.Check(issuesOfKind(report, "model"), gc.HasLen, 0)
+	s.backend.CheckCallNames(c, "ModelExists", "Cloud")
+}
+
+func (s *importValidatorSuite) TestValidateImportReportsIssues(c *gc.C) {
+	s.backend.exists = true
+	report, err := s.newAPI(c).ValidateImport(params.SerializedModel{
+		Bytes: s.serialize(c, "dummy", "elsewhere"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(report.Valid, jc.IsFalse)
+	c.Check(issuesOfKind(report, "model"), jc.DeepEquals, []params.ImportValidationIssue{{
+		Kind:    "model",
+		Message: "model " + coretesting.ModelTag.Id() + " already exists",
+	}})
+	c.Check(issuesOfKind(report, "cloud"), jc.DeepEquals, []params.ImportValidationIssue{{
+		Kind:    "cloud",
+		Entity:  "dummy",
+		Message: `region "elsewhere" not found in cloud "dummy"`,
+	}})
+}
+
+func (s *importValidatorSuite) TestValidateImportBadBytes(c *gc.C) {
+	report, err := s.newAPI(c).ValidateImport(params.SerializedModel{Bytes: []byte("not a model")})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(report.Valid, jc.IsFalse)
+	c.Assert(report.Issues, gc.HasLen, 1)
+	c.Check(report.Issues[0].Kind, gc.Equals, "schema")
+}
+
+func (s *importValidatorSuite) TestValidateImportBackendError(c *gc.C) {
+	s.backend.SetErrors(errors.New("boom"))
+	_, err := s.newAPI(c).ValidateImport(params.SerializedModel{
+		Bytes: s.serialize(c, "dummy", "dummy-region"),
+	})
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *importValidatorSuite) serialize(c *gc.C, cloudName, region string) []byte {
+	model := description.NewModel(description.ModelArgs{
+		Type:  description.IAAS,
+		Owner: names.NewUserTag("admin"),
+		Config: map[string]interface{}{
+			"name": "testmodel",
+			"uuid": coretesting.ModelTag.Id(),
+		},
+		Cloud:       cloudName,
+		CloudRegion: region,
+	})
+	model.SetStatus(description.StatusArgs{Value: "available"})
+	bytes, err := description.Serialize(model)
+	c.Assert(err, jc.ErrorIsNil)
+	return bytes
+}
+
+func issuesOfKind(report params.ImportValidationReport, kind string) []params.ImportValidationIssue {
+	var issues []params.ImportValidationIssue
+	for _, issue := range report.Issues {
+		if issue.Kind == kind {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+type mockBackend struct {
+	testing.Stub
+	exists bool
+	clouds map[string]cloud.Cloud
+}
+
+func (b *mockBackend) ModelExists(uuid string) (bool, error) {
+	b.MethodCall(b, "ModelExists", uuid)
+	return b.exists, b.NextErr()
+}
+
+func (b *mockBackend) Cloud(name string) (cloud.Cloud, error) {
+	b.MethodCall(b, "Cloud", name)
+	if err := b.NextErr(); err != nil {
+		return cloud.Cloud{}, err
+	}
+	c, ok := b.clouds[name]
+	if !ok {
+		return cloud.Cloud{}, errors.NotFoundf("cloud %q", name)
+	}
+	return c, nil
+}
+
+func (b *mockBackend) CloudCredential(tag names.CloudCredentialTag) (state.Credential, error) {
+	b.MethodCall(b, "CloudCredential", tag)
+	if err := b.NextErr(); err != nil {
+		return state.Credential{}, err
+	}
+	return state.Credential{}, errors.NotFoundf("cloud credential %q", tag.Id())
+}
+
+type mockTarget struct {
+	testing.Stub
+}
+
+func (t *mockTarget) CharmsAvailable(curls []*charm.URL) ([]error, error) {
+	t.MethodCall(t, "CharmsAvailable", curls)
+	return make([]error, len(curls)), t.NextErr()
+}
+
+func (t *mockTarget) Subnets(model description.Model) ([]corenetwork.SubnetInfo, error) {
+	t.MethodCall(t, "Subnets", model)
+	return nil, t.NextErr()
+}
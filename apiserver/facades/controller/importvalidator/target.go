@@ -0,0 +1,88 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package importvalidator
+
+import (
+	"github.com/juju/description"
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v6"
+
+	"github.com/juju/juju/charmstore"
+	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/core/instance"
+	corenetwork "github.com/juju/juju/core/network"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/state"
+)
+
+// importTarget implements migration.ImportCheckTarget using the charm
+// store configured for the controller and the cloud of the model being
+// checked.
+type importTarget struct {
+	st *state.State
+}
+
+// CharmsAvailable is part of the migration.ImportCheckTarget interface.
+func (t importTarget) CharmsAvailable(curls []*charm.URL) ([]error, error) {
+	controllerCfg, err := t.st.ControllerConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	client, err := charmstore.NewCachingClient(state.MacaroonCache{t.st}, controllerCfg.CharmStoreURL())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ids := make([]charmstore.CharmID, len(curls))
+	for i, curl := range curls {
+		ids[i] = charmstore.CharmID{URL: curl}
+	}
+	revisions, err := client.LatestRevisions(ids, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	results := make([]error, len(revisions))
+	for i, revision := range revisions {
+		results[i] = revision.Err
+	}
+	return results, nil
+}
+
+// Subnets is part of the migration.ImportCheckTarget interface. The
+// environ is opened with the model's own config and credential, as
+// the imported model would be.
+func (t importTarget) Subnets(model description.Model) ([]corenetwork.SubnetInfo, error) {
+	targetCloud, err := t.st.Cloud(model.Cloud())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var credential *cloud.Credential
+	if creds := model.CloudCredential(); creds != nil {
+		cred := cloud.NewCredential(cloud.AuthType(creds.AuthType()), creds.Attributes())
+		credential = &cred
+	}
+	spec, err := environs.MakeCloudSpec(targetCloud, model.CloudRegion(), credential)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cfg, err := config.New(config.NoDefaults, model.Config())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	env, err := environs.New(environs.OpenParams{
+		ControllerUUID: t.st.ControllerUUID(),
+		Cloud:          spec,
+		Config:         cfg,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	netEnv, ok := environs.SupportsNetworking(env)
+	if !ok {
+		return nil, errors.NotSupportedf("networking in cloud %q", model.Cloud())
+	}
+	subnets, err := netEnv.Subnets(context.NewCloudCallContext(), instance.UnknownId, nil)
+	return subnets, errors.Trace(err)
+}
@@ -24,6 +24,7 @@ type InstancePollerAPI struct {
 	*common.ModelMachinesWatcher
 	*common.InstanceIdGetter
 	*common.StatusGetter
+	*common.EgressAddressSetter
 
 	st            StateInterface
 	resources     facade.Resources
@@ -32,6 +33,25 @@ type InstancePollerAPI struct {
 	clock         clock.Clock
 }
 
+// InstancePollerAPIV3 implements version 3 of the InstancePoller API,
+// which lacks SetEgressAddresses.
+type InstancePollerAPIV3 struct {
+	*InstancePollerAPI
+}
+
+// NewFacadeV3 creates a new version 3 InstancePoller API facade.
+func NewFacadeV3(
+	st *state.State,
+	resources facade.Resources,
+	authorizer facade.Authorizer,
+) (*InstancePollerAPIV3, error) {
+	api, err := NewFacade(st, resources, authorizer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &InstancePollerAPIV3{api}, nil
+}
+
 // NewFacade wraps NewInstancePollerAPI for facade registration.
 func NewFacade(
 	st *state.State,
@@ -90,6 +110,12 @@ func NewInstancePollerAPI(
 		sti,
 		accessMachine,
 	)
+	// SetEgressAddresses() is supported for units, so that the
+	// provider can report the addresses of NAT gateways.
+	egressAddressSetter := common.NewEgressAddressSetter(
+		sti,
+		common.AuthFuncForTagKind(names.UnitTagKind),
+	)
 
 	return &InstancePollerAPI{
 		LifeGetter:           lifeGetter,
@@ -97,6 +123,7 @@ func NewInstancePollerAPI(
 		ModelMachinesWatcher: machinesWatcher,
 		InstanceIdGetter:     instanceIdGetter,
 		StatusGetter:         statusGetter,
+		EgressAddressSetter:  egressAddressSetter,
 		st:                   sti,
 		resources:            resources,
 		authorizer:           authorizer,
@@ -245,3 +272,10 @@ func (a *InstancePollerAPI) AreManuallyProvisioned(args params.Entities) (params
 	}
 	return result, nil
 }
+
+// Mask the SetEgressAddresses method from the v3 API. The API reflection
+// code in rpc/rpcreflect/type.go:newMethod skips 2-argument methods, so
+// this removes the method as far as the RPC machinery is concerned.
+
+// SetEgressAddresses isn't on the v3 API.
+func (*InstancePollerAPIV3) SetEgressAddresses(_, _ struct{}) {}
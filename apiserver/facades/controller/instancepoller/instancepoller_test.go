@@ -340,6 +340,31 @@ func (s *InstancePollerSuite) TestInstanceIdFailure(c *gc.C) {
 	s.st.CheckFindEntityCall(c, 3, "3")
 }
 
+func (s *InstancePollerSuite) TestSetEgressAddresses(c *gc.C) {
+	unit := &mockUnit{stub: s.st.Stub}
+	s.st.units["mysql/0"] = unit
+	bindings := map[string][]string{"": {"203.0.113.7"}}
+
+	result, err := s.api.SetEgressAddresses(params.SetUnitsEgressAddresses{
+		Args: []params.UnitEgressAddresses{
+			{Tag: "unit-mysql-0", Bindings: bindings},
+			{Tag: "unit-mysql-1", Bindings: bindings},
+			{Tag: "machine-1", Bindings: bindings},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{Error: nil},
+			{Error: apiservertesting.NotFoundError("unit mysql/1")},
+			{Error: apiservertesting.ErrUnauthorized},
+		}},
+	)
+	c.Assert(unit.egressAddresses, jc.DeepEquals, bindings)
+
+	s.st.CheckCallNames(c, "FindEntity", "SetEgressAddresses", "FindEntity")
+}
+
 func (s *InstancePollerSuite) TestStatusSuccess(c *gc.C) {
 	now := time.Now()
 	s1 := status.StatusInfo{
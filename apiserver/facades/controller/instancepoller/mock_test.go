@@ -32,12 +32,14 @@ type mockState struct {
 
 	config   *config.Config
 	machines map[string]*mockMachine
+	units    map[string]*mockUnit
 }
 
 func NewMockState() *mockState {
 	return &mockState{
 		Stub:     &testing.Stub{},
 		machines: make(map[string]*mockMachine),
+		units:    make(map[string]*mockUnit),
 	}
 }
 
@@ -130,6 +132,13 @@ func (m *mockState) FindEntity(tag names.Tag) (state.Entity, error) {
 	if tag == nil {
 		return nil, errors.NotValidf("nil tag is") // +" not valid"
 	}
+	if tag.Kind() == names.UnitTagKind {
+		unit, found := m.units[tag.Id()]
+		if !found {
+			return nil, errors.NotFoundf("unit %s", tag.Id())
+		}
+		return unit, nil
+	}
 	machine, found := m.machines[tag.Id()]
 	if !found {
 		return nil, errors.NotFoundf("machine %s", tag.Id())
@@ -137,6 +146,26 @@ func (m *mockState) FindEntity(tag names.Tag) (state.Entity, error) {
 	return machine, nil
 }
 
+// mockUnit implements the parts of state.Unit required to record its
+// egress addresses.
+type mockUnit struct {
+	state.Entity
+	stub *testing.Stub
+
+	egressAddresses map[string][]string
+}
+
+// SetEgressAddresses implements the interface required by
+// common.EgressAddressSetter.
+func (u *mockUnit) SetEgressAddresses(bindings map[string][]string) error {
+	u.stub.MethodCall(u, "SetEgressAddresses", bindings)
+	if err := u.stub.NextErr(); err != nil {
+		return err
+	}
+	u.egressAddresses = bindings
+	return nil
+}
+
 // SetMachineInfo adds a new or updates existing mockMachine info.
 // Triggers any created mock machines watchers to return a change.
 func (m *mockState) SetMachineInfo(c *gc.C, args machineInfo) {
@@ -20,6 +20,16 @@ func NewFacade(st *state.State, _ facade.Resources, auth facade.Authorizer) (*Lo
 	return NewLogForwardingAPI(&stateAdapter{st}, auth)
 }
 
+// NewFacadeV1 creates a new LogForwardingAPIV1. It is used for API
+// registration.
+func NewFacadeV1(st *state.State, resources facade.Resources, auth facade.Authorizer) (*LogForwardingAPIV1, error) {
+	api, err := NewFacade(st, resources, auth)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &LogForwardingAPIV1{api}, nil
+}
+
 // LastSentTracker exposes the functionality of state.LastSentTracker.
 type LastSentTracker interface {
 	io.Closer
@@ -29,6 +39,12 @@ type LastSentTracker interface {
 
 	// Set records the record ID and timestamp.
 	Set(recID int64, recTimestamp int64) error
+
+	// AddCounts adds to the numbers of records sent and dropped.
+	AddCounts(sent, dropped int64) error
+
+	// Counts retrieves the numbers of records sent and dropped.
+	Counts() (sent int64, dropped int64, err error)
 }
 
 // LogForwardingState supports interacting with state for the
@@ -44,6 +60,12 @@ type LogForwardingAPI struct {
 	state LogForwardingState
 }
 
+// LogForwardingAPIV1 is version 1 of the api end point, which does not
+// count the records sent and dropped.
+type LogForwardingAPIV1 struct {
+	*LogForwardingAPI
+}
+
 // NewLogForwardingAPI creates a new server-side logger API end point.
 func NewLogForwardingAPI(st LogForwardingState, auth facade.Authorizer) (*LogForwardingAPI, error) {
 	if !auth.AuthController() {
@@ -112,6 +134,70 @@ func (api *LogForwardingAPI) set(arg params.LogForwardingSetLastSentParam) *para
 	return common.ServerError(err)
 }
 
+// AddCounts is a bulk call that adds to the numbers of log records
+// sent to, and dropped instead of being sent to, each requested target.
+func (api *LogForwardingAPI) AddCounts(args params.LogForwardingAddCountsParams) params.ErrorResults {
+	results := make([]params.ErrorResult, len(args.Params))
+	for i, arg := range args.Params {
+		results[i].Error = api.addCounts(arg)
+	}
+	return params.ErrorResults{
+		Results: results,
+	}
+}
+
+func (api *LogForwardingAPI) addCounts(arg params.LogForwardingAddCountsParam) *params.Error {
+	if arg.Sent < 0 || arg.Dropped < 0 {
+		return common.ServerError(errors.NotValidf("negative counts"))
+	}
+	lst, err := api.newLastSentTracker(arg.LogForwardingID)
+	if err != nil {
+		return common.ServerError(err)
+	}
+	defer lst.Close()
+
+	err = lst.AddCounts(arg.Sent, arg.Dropped)
+	return common.ServerError(err)
+}
+
+// GetCounts is a bulk call that gets the numbers of log records sent
+// to, and dropped instead of being sent to, each requested target.
+// Targets to which nothing has been forwarded have zero counts.
+func (api *LogForwardingAPI) GetCounts(args params.LogForwardingIDs) params.LogForwardingGetCountsResults {
+	results := make([]params.LogForwardingGetCountsResult, len(args.IDs))
+	for i, id := range args.IDs {
+		results[i] = api.getCounts(id)
+	}
+	return params.LogForwardingGetCountsResults{
+		Results: results,
+	}
+}
+
+func (api *LogForwardingAPI) getCounts(id params.LogForwardingID) params.LogForwardingGetCountsResult {
+	var res params.LogForwardingGetCountsResult
+	lst, err := api.newLastSentTracker(id)
+	if err != nil {
+		res.Error = common.ServerError(err)
+		return res
+	}
+	defer lst.Close()
+
+	sent, dropped, err := lst.Counts()
+	if err != nil && errors.Cause(err) != state.ErrNeverForwarded {
+		res.Error = common.ServerError(err)
+		return res
+	}
+	res.Sent = sent
+	res.Dropped = dropped
+	return res
+}
+
+// AddCounts isn't on the V1 API.
+func (*LogForwardingAPIV1) AddCounts(_, _ struct{}) {}
+
+// GetCounts isn't on the V1 API.
+func (*LogForwardingAPIV1) GetCounts(_, _ struct{}) {}
+
 func (api *LogForwardingAPI) newLastSentTracker(id params.LogForwardingID) (LastSentTracker, error) {
 	tag, err := names.ParseModelTag(id.ModelTag)
 	if err != nil {
@@ -89,7 +89,7 @@ func (s *LastSentSuite) TestGetLastSentBulk(c *gc.C) {
 	trackerEggs := s.state.addTracker()
 	trackerEggs.ReturnGet = 20
 	s.state.addTracker() // ham
-	s.stub.SetErrors(nil, nil, nil, nil, state.ErrNeverForwarded)
+	s.stub.SetErrors(nil, nil, state.ErrNeverForwarded)
 	api, err := logfwd.NewLogForwardingAPI(s.state, s.authorizer)
 	c.Assert(err, jc.ErrorIsNil)
 	model := "deadbeef-2f18-4fd2-967d-db9663db7bea"
@@ -218,6 +218,74 @@ func (s *LastSentSuite) TestSetLastSentBulk(c *gc.C) {
 	s.stub.CheckCall(c, 7, "Set", int64(15), int64(150))
 }
 
+func (s *LastSentSuite) TestAddCounts(c *gc.C) {
+	s.state.addTracker()
+	api, err := logfwd.NewLogForwardingAPI(s.state, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	modelTag := names.NewModelTag("deadbeef-2f18-4fd2-967d-db9663db7bea")
+
+	res := api.AddCounts(params.LogForwardingAddCountsParams{
+		Params: []params.LogForwardingAddCountsParam{{
+			LogForwardingID: params.LogForwardingID{
+				ModelTag: modelTag.String(),
+				Sink:     "spam",
+			},
+			Sent:    10,
+			Dropped: 2,
+		}, {
+			LogForwardingID: params.LogForwardingID{
+				ModelTag: modelTag.String(),
+				Sink:     "eggs",
+			},
+			Sent: -1,
+		}},
+	})
+
+	c.Check(res, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{
+			Error: nil,
+		}, {
+			Error: &params.Error{
+				Message: "negative counts not valid",
+			},
+		}},
+	})
+	s.stub.CheckCallNames(c, "NewLastSentTracker", "AddCounts", "Close")
+	s.stub.CheckCall(c, 0, "NewLastSentTracker", modelTag, "spam")
+	s.stub.CheckCall(c, 1, "AddCounts", int64(10), int64(2))
+}
+
+func (s *LastSentSuite) TestGetCounts(c *gc.C) {
+	tracker := s.state.addTracker() // spam
+	tracker.ReturnGet = 10
+	s.state.addTracker() // eggs
+	s.stub.SetErrors(nil, nil, state.ErrNeverForwarded)
+	api, err := logfwd.NewLogForwardingAPI(s.state, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	modelTag := names.NewModelTag("deadbeef-2f18-4fd2-967d-db9663db7bea")
+
+	res := api.GetCounts(params.LogForwardingIDs{
+		IDs: []params.LogForwardingID{{
+			ModelTag: modelTag.String(),
+			Sink:     "spam",
+		}, {
+			ModelTag: modelTag.String(),
+			Sink:     "eggs",
+		}},
+	})
+
+	c.Check(res, jc.DeepEquals, params.LogForwardingGetCountsResults{
+		Results: []params.LogForwardingGetCountsResult{{
+			Sent:    10,
+			Dropped: 1,
+		}, {}},
+	})
+	s.stub.CheckCallNames(c,
+		"NewLastSentTracker", "Counts", "Close",
+		"NewLastSentTracker", "Counts", "Close",
+	)
+}
+
 type stubState struct {
 	stub *testing.Stub
 
@@ -264,6 +332,24 @@ func (s *stubTracker) Set(recID int64, recTimestamp int64) error {
 	return nil
 }
 
+func (s *stubTracker) AddCounts(sent, dropped int64) error {
+	s.stub.AddCall("AddCounts", sent, dropped)
+	if err := s.stub.NextErr(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *stubTracker) Counts() (int64, int64, error) {
+	s.stub.AddCall("Counts")
+	if err := s.stub.NextErr(); err != nil {
+		return 0, 0, err
+	}
+
+	return s.ReturnGet, s.ReturnGet / 10, nil
+}
+
 func (s *stubTracker) Close() error {
 	s.stub.AddCall("Close")
 	if err := s.stub.NextErr(); err != nil {
@@ -0,0 +1,72 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package networkreconciler_test
+
+import (
+	"github.com/juju/testing"
+
+	"github.com/juju/juju/apiserver/facades/controller/networkreconciler"
+	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/state"
+)
+
+type mockBackend struct {
+	testing.Stub
+	subnets []networkreconciler.Subnet
+	spaces  []networkreconciler.Space
+	status  status.StatusInfo
+}
+
+func (b *mockBackend) AllSubnets() ([]networkreconciler.Subnet, error) {
+	b.MethodCall(b, "AllSubnets")
+	return b.subnets, b.NextErr()
+}
+
+func (b *mockBackend) AllSpaces() ([]networkreconciler.Space, error) {
+	b.MethodCall(b, "AllSpaces")
+	return b.spaces, b.NextErr()
+}
+
+func (b *mockBackend) ModelStatus() (status.StatusInfo, error) {
+	b.MethodCall(b, "ModelStatus")
+	return b.status, b.NextErr()
+}
+
+func (b *mockBackend) SetModelStatus(info status.StatusInfo) error {
+	b.MethodCall(b, "SetModelStatus", info)
+	if err := b.NextErr(); err != nil {
+		return err
+	}
+	b.status = info
+	return nil
+}
+
+type mockSubnet struct {
+	cidr       string
+	providerId network.Id
+	vlanTag    int
+	zones      []string
+	spaceId    string
+	underlay   string
+}
+
+func (s *mockSubnet) CIDR() string                  { return s.cidr }
+func (s *mockSubnet) ProviderId() network.Id        { return s.providerId }
+func (s *mockSubnet) ProviderNetworkId() network.Id { return "" }
+func (s *mockSubnet) VLANTag() int                  { return s.vlanTag }
+func (s *mockSubnet) AvailabilityZones() []string   { return s.zones }
+func (s *mockSubnet) SpaceID() string               { return s.spaceId }
+func (s *mockSubnet) FanLocalUnderlay() string      { return s.underlay }
+func (s *mockSubnet) Life() state.Life              { return state.Alive }
+
+type mockSpace struct {
+	id         string
+	name       string
+	providerId network.Id
+}
+
+func (s *mockSpace) Id() string             { return s.id }
+func (s *mockSpace) Name() string           { return s.name }
+func (s *mockSpace) ProviderId() network.Id { return s.providerId }
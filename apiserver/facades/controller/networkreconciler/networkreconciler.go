@@ -0,0 +1,180 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package networkreconciler implements the API used by the worker that
+// compares the subnets recorded for a model with those reported by its
+// provider.
+package networkreconciler
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/state"
+)
+
+// driftDataKey is the key of the model status data holding a
+// description of each difference between the model's and the
+// provider's subnets.
+const driftDataKey = "network-drift"
+
+// API implements the NetworkReconciler facade.
+type API struct {
+	backend Backend
+}
+
+// NewFacade creates a new NetworkReconciler facade.
+func NewFacade(st *state.State, _ facade.Resources, authorizer facade.Authorizer) (*API, error) {
+	model, err := st.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return NewAPI(backendShim{st: st, model: model}, authorizer)
+}
+
+// NewAPI creates a new NetworkReconciler facade backed by the given
+// Backend.
+func NewAPI(backend Backend, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthController() {
+		return nil, common.ErrPerm
+	}
+	return &API{backend: backend}, nil
+}
+
+// ModelSubnets returns the subnets recorded for the model, excluding
+// FAN overlays, which are derived by Juju rather than discovered from
+// the provider.
+func (api *API) ModelSubnets() (params.ListSubnetsResults, error) {
+	var results params.ListSubnetsResults
+	spaces, err := api.backend.AllSpaces()
+	if err != nil {
+		return results, errors.Trace(err)
+	}
+	spacesById := make(map[string]Space, len(spaces))
+	for _, space := range spaces {
+		spacesById[space.Id()] = space
+	}
+
+	subnets, err := api.backend.AllSubnets()
+	if err != nil {
+		return results, errors.Trace(err)
+	}
+	for _, subnet := range subnets {
+		if subnet.FanLocalUnderlay() != "" {
+			continue
+		}
+		result := params.Subnet{
+			CIDR:              subnet.CIDR(),
+			ProviderId:        string(subnet.ProviderId()),
+			ProviderNetworkId: string(subnet.ProviderNetworkId()),
+			VLANTag:           subnet.VLANTag(),
+			Life:              params.Life(subnet.Life().String()),
+			Zones:             subnet.AvailabilityZones(),
+		}
+		spaceId := subnet.SpaceID()
+		if spaceId == "" {
+			spaceId = network.DefaultSpaceId
+		}
+		if space, ok := spacesById[spaceId]; ok {
+			result.ProviderSpaceId = string(space.ProviderId())
+			if space.Name() != network.DefaultSpaceName {
+				result.SpaceTag = names.NewSpaceTag(space.Name()).String()
+			}
+		}
+		results.Results = append(results.Results, result)
+	}
+	return results, nil
+}
+
+// SetNetworkDrift records the differences between the model's and the
+// provider's subnets in the model status. The drift is only reported
+// while the model is otherwise available, so that it does not mask a
+// more important status; it is cleared once the drift is resolved.
+func (api *API) SetNetworkDrift(args params.NetworkDrift) error {
+	info, err := api.backend.ModelStatus()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if info.Status != status.Available {
+		return nil
+	}
+
+	lines := driftLines(args)
+	if stringsEqual(lines, reportedDrift(info.Data)) {
+		return nil
+	}
+
+	newInfo := status.StatusInfo{Status: status.Available}
+	if len(lines) > 0 {
+		newInfo.Message = fmt.Sprintf(
+			"provider network drift: %d subnets added, %d removed, %d changed",
+			len(args.Added), len(args.Removed), len(args.Changed),
+		)
+		newInfo.Data = map[string]interface{}{driftDataKey: lines}
+	}
+	return errors.Trace(api.backend.SetModelStatus(newInfo))
+}
+
+// driftLines returns a sorted, human readable description of each
+// difference in the given drift.
+func driftLines(drift params.NetworkDrift) []string {
+	var lines []string
+	for _, subnet := range drift.Added {
+		lines = append(lines, fmt.Sprintf("subnet %s added by provider", subnetName(subnet)))
+	}
+	for _, subnet := range drift.Removed {
+		lines = append(lines, fmt.Sprintf("subnet %s removed by provider", subnetName(subnet)))
+	}
+	for _, change := range drift.Changed {
+		lines = append(lines, fmt.Sprintf(
+			"subnet %s %s changed from %q to %q",
+			change.CIDR, change.Attribute, change.Model, change.Provider,
+		))
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+func subnetName(subnet params.Subnet) string {
+	if subnet.ProviderId == "" {
+		return subnet.CIDR
+	}
+	return fmt.Sprintf("%s (%s)", subnet.CIDR, subnet.ProviderId)
+}
+
+// reportedDrift returns the drift previously recorded in the model
+// status data. Once round-tripped through the database the lines are
+// held as a []interface{}.
+func reportedDrift(data map[string]interface{}) []string {
+	switch lines := data[driftDataKey].(type) {
+	case []string:
+		return lines
+	case []interface{}:
+		result := make([]string, len(lines))
+		for i, line := range lines {
+			result[i] = fmt.Sprint(line)
+		}
+		return result
+	}
+	return nil
+}
+
+func stringsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
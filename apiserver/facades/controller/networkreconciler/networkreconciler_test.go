@@ -0,0 +1,140 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package networkreconciler_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/facades/controller/networkreconciler"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/core/status"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type NetworkReconcilerSuite struct {
+	coretesting.BaseSuite
+
+	backend *mockBackend
+	api     *networkreconciler.API
+}
+
+var _ = gc.Suite(&NetworkReconcilerSuite{})
+
+func (s *NetworkReconcilerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.backend = &mockBackend{
+		spaces: []networkreconciler.Space{
+			&mockSpace{id: "0", name: "alpha"},
+			&mockSpace{id: "1", name: "db", providerId: "sp-db"},
+		},
+		subnets: []networkreconciler.Subnet{
+			&mockSubnet{cidr: "10.0.0.0/24", providerId: "subnet-0", zones: []string{"az1"}},
+			&mockSubnet{cidr: "10.0.1.0/24", providerId: "subnet-1", vlanTag: 42, spaceId: "1"},
+			&mockSubnet{cidr: "252.1.0.0/16", spaceId: "1", underlay: "10.0.1.0/24"},
+		},
+		status: status.StatusInfo{Status: status.Available},
+	}
+
+	var err error
+	s.api, err = networkreconciler.NewAPI(s.backend, apiservertesting.FakeAuthorizer{
+		Controller: true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *NetworkReconcilerSuite) TestNewAPIRequiresController(c *gc.C) {
+	_, err := networkreconciler.NewAPI(s.backend, apiservertesting.FakeAuthorizer{})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *NetworkReconcilerSuite) TestModelSubnets(c *gc.C) {
+	results, err := s.api.ModelSubnets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ListSubnetsResults{
+		Results: []params.Subnet{{
+			CIDR:       "10.0.0.0/24",
+			ProviderId: "subnet-0",
+			Life:       params.Alive,
+			Zones:      []string{"az1"},
+		}, {
+			CIDR:            "10.0.1.0/24",
+			ProviderId:      "subnet-1",
+			ProviderSpaceId: "sp-db",
+			VLANTag:         42,
+			Life:            params.Alive,
+			SpaceTag:        "space-db",
+		}},
+	})
+}
+
+func (s *NetworkReconcilerSuite) TestModelSubnetsError(c *gc.C) {
+	s.backend.SetErrors(nil, errors.New("boom"))
+	_, err := s.api.ModelSubnets()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *NetworkReconcilerSuite) TestSetNetworkDrift(c *gc.C) {
+	err := s.api.SetNetworkDrift(params.NetworkDrift{
+		Added:   []params.Subnet{{CIDR: "10.0.2.0/24", ProviderId: "subnet-2"}},
+		Removed: []params.Subnet{{CIDR: "10.0.0.0/24"}},
+		Changed: []params.SubnetDrift{{
+			CIDR:      "10.0.1.0/24",
+			Attribute: "vlan-tag",
+			Model:     "42",
+			Provider:  "43",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.backend.status, jc.DeepEquals, status.StatusInfo{
+		Status:  status.Available,
+		Message: "provider network drift: 1 subnets added, 1 removed, 1 changed",
+		Data: map[string]interface{}{
+			"network-drift": []string{
+				"subnet 10.0.0.0/24 removed by provider",
+				`subnet 10.0.1.0/24 vlan-tag changed from "42" to "43"`,
+				"subnet 10.0.2.0/24 (subnet-2) added by provider",
+			},
+		},
+	})
+}
+
+func (s *NetworkReconcilerSuite) TestSetNetworkDriftUnchanged(c *gc.C) {
+	s.backend.status = status.StatusInfo{
+		Status:  status.Available,
+		Message: "provider network drift: 0 subnets added, 1 removed, 0 changed",
+		Data: map[string]interface{}{
+			"network-drift": []interface{}{"subnet 10.0.0.0/24 removed by provider"},
+		},
+	}
+	err := s.api.SetNetworkDrift(params.NetworkDrift{
+		Removed: []params.Subnet{{CIDR: "10.0.0.0/24"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.backend.CheckCallNames(c, "ModelStatus")
+}
+
+func (s *NetworkReconcilerSuite) TestSetNetworkDriftClears(c *gc.C) {
+	s.backend.status = status.StatusInfo{
+		Status:  status.Available,
+		Message: "provider network drift: 0 subnets added, 1 removed, 0 changed",
+		Data: map[string]interface{}{
+			"network-drift": []interface{}{"subnet 10.0.0.0/24 removed by provider"},
+		},
+	}
+	err := s.api.SetNetworkDrift(params.NetworkDrift{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.backend.status, jc.DeepEquals, status.StatusInfo{Status: status.Available})
+}
+
+func (s *NetworkReconcilerSuite) TestSetNetworkDriftModelNotAvailable(c *gc.C) {
+	s.backend.status = status.StatusInfo{Status: status.Suspended, Message: "invalid credential"}
+	err := s.api.SetNetworkDrift(params.NetworkDrift{
+		Removed: []params.Subnet{{CIDR: "10.0.0.0/24"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.backend.CheckCallNames(c, "ModelStatus")
+}
@@ -0,0 +1,83 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package networkreconciler
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/state"
+)
+
+// Backend defines the state functionality used by the NetworkReconciler
+// facade.
+type Backend interface {
+	AllSubnets() ([]Subnet, error)
+	AllSpaces() ([]Space, error)
+	ModelStatus() (status.StatusInfo, error)
+	SetModelStatus(status.StatusInfo) error
+}
+
+// Subnet defines the subnet functionality used by the NetworkReconciler
+// facade.
+type Subnet interface {
+	CIDR() string
+	ProviderId() network.Id
+	ProviderNetworkId() network.Id
+	VLANTag() int
+	AvailabilityZones() []string
+	SpaceID() string
+	FanLocalUnderlay() string
+	Life() state.Life
+}
+
+// Space defines the space functionality used by the NetworkReconciler
+// facade.
+type Space interface {
+	Id() string
+	Name() string
+	ProviderId() network.Id
+}
+
+type backendShim struct {
+	st    *state.State
+	model *state.Model
+}
+
+// AllSubnets is part of the Backend interface.
+func (b backendShim) AllSubnets() ([]Subnet, error) {
+	subnets, err := b.st.AllSubnets()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]Subnet, len(subnets))
+	for i, subnet := range subnets {
+		result[i] = subnet
+	}
+	return result, nil
+}
+
+// AllSpaces is part of the Backend interface.
+func (b backendShim) AllSpaces() ([]Space, error) {
+	spaces, err := b.st.AllSpaces()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]Space, len(spaces))
+	for i, space := range spaces {
+		result[i] = space
+	}
+	return result, nil
+}
+
+// ModelStatus is part of the Backend interface.
+func (b backendShim) ModelStatus() (status.StatusInfo, error) {
+	return b.model.Status()
+}
+
+// SetModelStatus is part of the Backend interface.
+func (b backendShim) SetModelStatus(info status.StatusInfo) error {
+	return b.model.SetStatus(info)
+}
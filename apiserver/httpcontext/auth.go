@@ -17,6 +17,7 @@ import (
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/permission"
 )
 
 var logger = loggo.GetLogger("juju.apiserver.httpcontext")
@@ -93,6 +94,11 @@ type AuthInfo struct {
 	// Controller reports whether or not the authenticated
 	// entity is a controller agent.
 	Controller bool
+
+	// AccessLimit, if non-empty, is the maximum model access the
+	// authenticated entity may exercise, regardless of the access
+	// it has been granted. It is set for logins using API keys.
+	AccessLimit permission.Access
 }
 
 // BasicAuthHandler is an http.Handler that authenticates requests that
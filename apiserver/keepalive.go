@@ -0,0 +1,82 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"time"
+
+	gorillaws "github.com/gorilla/websocket"
+	"github.com/juju/clock"
+
+	"github.com/juju/juju/apiserver/websocket"
+	jujucontroller "github.com/juju/juju/controller"
+)
+
+// keepAliveConfig holds the websocket keepalive settings for API
+// connections.
+type keepAliveConfig struct {
+	// PingInterval is how often clients are pinged. If it is zero,
+	// clients aren't pinged and connections never time out.
+	PingInterval time.Duration
+
+	// PongTimeout is how long a client has to answer a ping before
+	// its connection is considered dead.
+	PongTimeout time.Duration
+}
+
+func keepAliveConfigFrom(cfg jujucontroller.Config) keepAliveConfig {
+	return keepAliveConfig{
+		PingInterval: cfg.APIWebsocketPingInterval(),
+		PongTimeout:  cfg.APIWebsocketPongTimeout(),
+	}
+}
+
+// startKeepAlive starts pinging the client of the given API connection
+// at the websocket level, as configured in the controller config, until
+// stop is closed. If the client doesn't answer with a pong in time,
+// reading from the connection fails, so that half-open connections are
+// closed rather than held open forever.
+//
+// startKeepAlive must be called before the connection is first read.
+func startKeepAlive(clock clock.Clock, conn *websocket.Conn, shared *sharedServerContext, stop <-chan struct{}) {
+	conn.SetPongHandler(func(string) error {
+		cfg, _ := shared.keepAliveConfig()
+		if cfg.PingInterval == 0 {
+			// A late pong for a ping sent before pings were disabled.
+			return conn.SetReadDeadline(time.Time{})
+		}
+		return conn.SetReadDeadline(time.Now().Add(cfg.PongTimeout))
+	})
+	go keepAlive(clock, conn, shared, stop)
+}
+
+func keepAlive(clock clock.Clock, conn *websocket.Conn, shared *sharedServerContext, stop <-chan struct{}) {
+	for {
+		cfg, changed := shared.keepAliveConfig()
+		var ping <-chan time.Time
+		if cfg.PingInterval > 0 {
+			conn.SetReadDeadline(time.Now().Add(cfg.PongTimeout))
+			ping = clock.After(cfg.PingInterval)
+		} else {
+			conn.SetReadDeadline(time.Time{})
+		}
+	waitForPing:
+		for {
+			select {
+			case <-stop:
+				return
+			case <-changed:
+				break waitForPing
+			case <-ping:
+				deadline := time.Now().Add(websocket.WriteWait)
+				if err := conn.WriteControl(gorillaws.PingMessage, []byte{}, deadline); err != nil {
+					// This error is expected if the other end goes away.
+					logger.Debugf("failed to write ping: %v", err)
+					return
+				}
+				ping = clock.After(cfg.PingInterval)
+			}
+		}
+	}
+}
@@ -11,12 +11,14 @@ import (
 	"time"
 
 	"github.com/juju/clock"
+	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/version"
 
 	"github.com/juju/juju/apiserver/logsink"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/controller"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/logdb"
 )
@@ -29,6 +31,7 @@ const (
 type agentLoggingStrategy struct {
 	dbloggers  *dbloggers
 	fileLogger io.Writer
+	targets    func() set.Strings
 
 	dblogger   recordLogger
 	releaser   func()
@@ -104,16 +107,19 @@ func (b *bufferedDbLogger) Close() error {
 
 // newAgentLogWriteCloserFunc returns a function that will create a
 // logsink.LoggingStrategy given an *http.Request, that writes log
-// messages to the given writer and also to the state database.
+// messages to the given writer and to the state database, as chosen
+// by the logsink targets returned by the given function.
 func newAgentLogWriteCloserFunc(
 	ctxt httpContext,
 	fileLogger io.Writer,
 	dbloggers *dbloggers,
+	targets func() set.Strings,
 ) logsink.NewLogWriteCloserFunc {
 	return func(req *http.Request) (logsink.LogWriteCloser, error) {
 		strategy := &agentLoggingStrategy{
 			dbloggers:  dbloggers,
 			fileLogger: fileLogger,
+			targets:    targets,
 		}
 		if err := strategy.init(ctxt, req); err != nil {
 			return nil, errors.Annotate(err, "initialising agent logsink session")
@@ -164,22 +170,30 @@ func (s *agentLoggingStrategy) Close() error {
 
 // WriteLog is part of the logsink.LogWriteCloser interface.
 func (s *agentLoggingStrategy) WriteLog(m params.LogRecord) error {
-	level, _ := loggo.ParseLevel(m.Level)
-	dbErr := errors.Annotate(s.dblogger.Log([]state.LogRecord{{
-		Time:     m.Time,
-		Entity:   s.entity,
-		Version:  s.version,
-		Module:   m.Module,
-		Location: m.Location,
-		Level:    level,
-		Message:  m.Message,
-	}}), "logging to DB failed")
-
-	m.Entity = s.entity
-	fileErr := errors.Annotate(
-		logToFile(s.fileLogger, s.filePrefix, m),
-		"logging to logsink.log failed",
-	)
+	// The targets are checked for each record, so that changes to
+	// them take effect without agents having to reconnect.
+	targets := s.targets()
+	var dbErr, fileErr error
+	if targets.Contains(controller.LogSinkDatabase) {
+		level, _ := loggo.ParseLevel(m.Level)
+		dbErr = errors.Annotate(s.dblogger.Log([]state.LogRecord{{
+			Time:     m.Time,
+			Entity:   s.entity,
+			Version:  s.version,
+			Module:   m.Module,
+			Location: m.Location,
+			Level:    level,
+			Message:  m.Message,
+		}}), "logging to DB failed")
+	}
+
+	if targets.Contains(controller.LogSinkFile) {
+		m.Entity = s.entity
+		fileErr = errors.Annotate(
+			logToFile(s.fileLogger, s.filePrefix, m),
+			"logging to logsink.log failed",
+		)
+	}
 	err := dbErr
 	if err == nil {
 		err = fileErr
@@ -6,26 +6,63 @@ package logsink
 import (
 	"io"
 	"os"
+	"sync"
 
 	"github.com/juju/errors"
 	"github.com/juju/utils"
 	"gopkg.in/natefinch/lumberjack.v2"
 )
 
-// NewFileWriter returns an io.WriteCloser that will write log messages to disk.
-func NewFileWriter(logPath string) (io.WriteCloser, error) {
+// FileWriter is an io.WriteCloser that writes log messages to disk,
+// rotating the log file when it grows too large.
+type FileWriter struct {
+	mu     sync.Mutex
+	logger *lumberjack.Logger
+}
+
+var _ io.WriteCloser = (*FileWriter)(nil)
+
+// NewFileWriter returns a FileWriter that will write log messages to
+// disk. See SetLimits for changing when the log file is rotated.
+func NewFileWriter(logPath string) (*FileWriter, error) {
 	if err := primeLogFile(logPath); err != nil {
 		// This isn't a fatal error so log and continue if priming fails.
 		logger.Warningf("Unable to prime %s (proceeding anyway): %v", logPath, err)
 	}
-	return &lumberjack.Logger{
-		Filename:   logPath,
-		MaxSize:    300, // MB
-		MaxBackups: 2,
-		Compress:   true,
+	return &FileWriter{
+		logger: &lumberjack.Logger{
+			Filename:   logPath,
+			MaxSize:    300, // MB
+			MaxBackups: 2,
+			Compress:   true,
+		},
 	}, nil
 }
 
+// Write is part of the io.Writer interface.
+func (w *FileWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.logger.Write(p)
+}
+
+// Close is part of the io.Closer interface.
+func (w *FileWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.logger.Close()
+}
+
+// SetLimits changes the size at which the log file is rotated, and the
+// number of rotated files kept. The new limits apply from the next
+// write.
+func (w *FileWriter) SetLimits(maxSizeMB, maxBackups int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.logger.MaxSize = maxSizeMB
+	w.logger.MaxBackups = maxBackups
+}
+
 // primeLogFile ensures the logsink log file is created with the
 // correct mode and ownership.
 func primeLogFile(path string) error {
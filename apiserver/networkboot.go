@@ -0,0 +1,98 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/state"
+)
+
+// networkBootHandler is an http.Handler for the
+// "/model/:modeluuid/network-boot/:token/:artifact" endpoint, which serves
+// the cloud-init nocloud-net seed of a machine that is provisioned by
+// network boot. The booting machine has no credentials of its own; the
+// token, which is issued to the provisioner, grants access to the seed of
+// a single machine, and its user-data can be fetched only once.
+//
+// Only the seed's "meta-data" and "user-data" are served. The controller
+// does not build seed ISOs or serve kernels, initrds or PXE configuration;
+// the provider's own network boot infrastructure boots the machine.
+type networkBootHandler struct {
+	ctxt httpContext
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (h *networkBootHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != "GET" {
+		if err := sendError(w, errors.MethodNotAllowedf("unsupported method: %q", req.Method)); err != nil {
+			logger.Errorf("%v", err)
+		}
+		return
+	}
+	st, err := h.ctxt.stateForRequestUnauthenticated(req)
+	if err != nil {
+		if err := sendError(w, err); err != nil {
+			logger.Errorf("%v", err)
+		}
+		return
+	}
+	defer st.Release()
+
+	data, err := h.artifact(req, st.State)
+	if err != nil {
+		if err := sendError(w, err); err != nil {
+			logger.Errorf("%v", err)
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(data); err != nil {
+		logger.Errorf("failed to write network boot artifact: %v", err)
+	}
+}
+
+// artifact returns the content of the requested seed artifact. Fetching
+// the user-data consumes it.
+func (h *networkBootHandler) artifact(req *http.Request, st *state.State) ([]byte, error) {
+	token := req.URL.Query().Get(":token")
+	switch artifact := req.URL.Query().Get(":artifact"); artifact {
+	case "meta-data":
+		data, err := st.NetworkBootUserData(token)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return networkBootMetaData(st.ModelUUID(), data.MachineId)
+	case "user-data":
+		data, err := st.ConsumeNetworkBootUserData(token)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		logger.Infof("network boot user-data for machine %s fetched by %s", data.MachineId, req.RemoteAddr)
+		return data.UserData, nil
+	default:
+		return nil, errors.NotFoundf("network boot artifact %q", artifact)
+	}
+}
+
+// networkBootMetaData returns the nocloud meta-data for the machine, which
+// identifies the instance to cloud-init by the machine's hostname.
+func networkBootMetaData(modelUUID, machineId string) ([]byte, error) {
+	namespace, err := instance.NewNamespace(modelUUID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	hostname, err := namespace.Hostname(machineId)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return []byte(fmt.Sprintf("instance-id: %s\nlocal-hostname: %s\n", hostname, hostname)), nil
+}
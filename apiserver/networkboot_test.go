@@ -0,0 +1,93 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/testing/httptesting"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type networkBootSuite struct {
+	apiserverBaseSuite
+	token string
+}
+
+var _ = gc.Suite(&networkBootSuite{})
+
+func (s *networkBootSuite) SetUpTest(c *gc.C) {
+	s.apiserverBaseSuite.SetUpTest(c)
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	s.token, err = machine.PublishNetworkBootUserData([]byte("#cloud-config\n"), s.Clock.Now().Add(time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *networkBootSuite) seedURL(artifact string) string {
+	return s.URL("/model/"+s.State.ModelUUID()+"/network-boot/"+s.token+"/"+artifact, nil).String()
+}
+
+func (s *networkBootSuite) get(c *gc.C, url string) (int, string) {
+	resp := httptesting.Do(c, httptesting.DoRequestParams{
+		Do:     utils.GetNonValidatingHTTPClient().Do,
+		URL:    url,
+		Method: "GET",
+	})
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	c.Assert(err, jc.ErrorIsNil)
+	return resp.StatusCode, string(body)
+}
+
+func (s *networkBootSuite) TestMetaData(c *gc.C) {
+	hostname := "juju-" + s.State.ModelUUID()[30:] + "-0"
+	for i := 0; i < 2; i++ {
+		status, body := s.get(c, s.seedURL("meta-data"))
+		c.Assert(status, gc.Equals, http.StatusOK)
+		c.Assert(body, gc.Equals, "instance-id: "+hostname+"\nlocal-hostname: "+hostname+"\n")
+	}
+}
+
+func (s *networkBootSuite) TestUserDataFetchedOnce(c *gc.C) {
+	status, body := s.get(c, s.seedURL("user-data"))
+	c.Assert(status, gc.Equals, http.StatusOK)
+	c.Assert(body, gc.Equals, "#cloud-config\n")
+
+	status, _ = s.get(c, s.seedURL("user-data"))
+	c.Assert(status, gc.Equals, http.StatusNotFound)
+	status, _ = s.get(c, s.seedURL("meta-data"))
+	c.Assert(status, gc.Equals, http.StatusNotFound)
+}
+
+func (s *networkBootSuite) TestUnknownToken(c *gc.C) {
+	s.token = "deadbeef"
+	status, _ := s.get(c, s.seedURL("user-data"))
+	c.Assert(status, gc.Equals, http.StatusNotFound)
+}
+
+func (s *networkBootSuite) TestUnknownArtifact(c *gc.C) {
+	status, _ := s.get(c, s.seedURL("vendor-data"))
+	c.Assert(status, gc.Equals, http.StatusNotFound)
+
+	// The user-data is not consumed.
+	status, _ = s.get(c, s.seedURL("user-data"))
+	c.Assert(status, gc.Equals, http.StatusOK)
+}
+
+func (s *networkBootSuite) TestMethodNotAllowed(c *gc.C) {
+	resp := httptesting.Do(c, httptesting.DoRequestParams{
+		Do:     utils.GetNonValidatingHTTPClient().Do,
+		URL:    s.seedURL("user-data"),
+		Method: "POST",
+	})
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusMethodNotAllowed)
+}
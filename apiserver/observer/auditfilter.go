@@ -140,9 +140,12 @@ var readonlyMethods = set.NewStrings(
 	"Client.GetModelConstraints",
 	"Client.StatusHistory",
 	"Controller.AllModels",
+	"Controller.AuditRecords",
 	"Controller.ControllerConfig",
 	"Controller.GetControllerAccess",
 	"Controller.ModelConfig",
+	"Controller.ModelFeatures",
+	"Controller.ModelStats",
 	"Controller.ModelStatus",
 	"MetricsDebug.GetMetrics",
 	"ModelConfig.ModelGet",
@@ -0,0 +1,182 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditobserver
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/observer"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/state"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.observer.auditobserver")
+
+// Store records the audit trail of API calls.
+type Store interface {
+	// AddAuditRecord adds a record of an API call to the audit trail.
+	AddAuditRecord(state.AuditRecord) error
+}
+
+// Config contains the configuration for an Observer.
+type Config struct {
+	// Clock is the clock to use for all time-related operations.
+	Clock clock.Clock
+
+	// Sink accepts the records of API calls to be written to the
+	// audit trail.
+	Sink Sink
+
+	// IncludeAgents determines whether calls made by agents, and calls
+	// on watchers, are recorded. They make up most of the traffic to
+	// a controller, and are left out by default.
+	IncludeAgents bool
+}
+
+// Validate validates the observer factory configuration.
+func (cfg Config) Validate() error {
+	if cfg.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if cfg.Sink == nil {
+		return errors.NotValidf("nil Sink")
+	}
+	return nil
+}
+
+// NewObserverFactory returns a function that, when called, returns a
+// new Observer, which records every API call made on a connection.
+func NewObserverFactory(config Config) (observer.ObserverFactory, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Annotate(err, "validating config")
+	}
+	return func() observer.Observer {
+		return &Observer{
+			clock:         config.Clock,
+			sink:          config.Sink,
+			includeAgents: config.IncludeAgents,
+		}
+	}, nil
+}
+
+// Observer is an API server observer that records an audit trail of
+// the API calls made on a single connection. Calls to the Pinger facade
+// are not recorded, as they carry no information and would quickly
+// crowd out everything else; nor, unless configured otherwise, are
+// calls made by agents or calls on watchers.
+type Observer struct {
+	clock         clock.Clock
+	sink          Sink
+	includeAgents bool
+
+	mu           sync.Mutex
+	connectionID uint64
+	entity       string
+	isAgent      bool
+	modelUUID    string
+}
+
+// Login is part of the observer.Observer interface.
+func (o *Observer) Login(entity names.Tag, model names.ModelTag, _ bool, _ string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if entity != nil {
+		o.entity = entity.String()
+		o.isAgent = entity.Kind() != names.UserTagKind
+	}
+	o.modelUUID = model.Id()
+}
+
+// Join is part of the observer.Observer interface.
+func (o *Observer) Join(_ *http.Request, connectionID uint64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.connectionID = connectionID
+}
+
+// Leave is part of the observer.Observer interface.
+func (*Observer) Leave() {}
+
+// RPCObserver is part of the observer.Observer interface.
+func (o *Observer) RPCObserver() rpc.Observer {
+	return &rpcObserver{observer: o}
+}
+
+// newRecord returns an audit record holding the details of the
+// connection, as they are currently known, and whether the connection
+// is an agent's.
+func (o *Observer) newRecord() (state.AuditRecord, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return state.AuditRecord{
+		ModelUUID:    o.modelUUID,
+		User:         o.entity,
+		ConnectionID: o.connectionID,
+	}, o.isAgent
+}
+
+// ignored reports whether calls to the given method are never
+// recorded: pings always, and watcher calls unless agent traffic is
+// included.
+func (o *Observer) ignored(req rpc.Request) bool {
+	if req.Type == "Pinger" {
+		return true
+	}
+	return !o.includeAgents && isWatcherCall(req)
+}
+
+// isWatcherCall reports whether the request starts or drives a watcher.
+func isWatcherCall(req rpc.Request) bool {
+	return strings.HasSuffix(req.Type, "Watcher") || strings.HasPrefix(req.Action, "Watch")
+}
+
+type rpcObserver struct {
+	observer *Observer
+	start    time.Time
+	args     string
+}
+
+// ServerRequest is part of the rpc.Observer interface.
+func (o *rpcObserver) ServerRequest(hdr *rpc.Header, body interface{}) {
+	o.start = o.observer.clock.Now()
+	if o.observer.ignored(hdr.Request) || body == nil {
+		return
+	}
+	args, err := RedactArgs(body)
+	if err != nil {
+		logger.Warningf("cannot record arguments of %s.%s: %v", hdr.Request.Type, hdr.Request.Action, err)
+		return
+	}
+	o.args = args
+}
+
+// ServerReply is part of the rpc.Observer interface.
+func (o *rpcObserver) ServerReply(req rpc.Request, hdr *rpc.Header, _ interface{}) {
+	if o.observer.ignored(req) {
+		return
+	}
+	// The record is only made now, so that login calls are recorded
+	// against the entity that logged in.
+	record, isAgent := o.observer.newRecord()
+	if isAgent && !o.observer.includeAgents {
+		return
+	}
+	record.Facade = req.Type
+	record.Version = req.Version
+	record.Method = req.Action
+	record.Args = o.args
+	record.ErrorCode = hdr.ErrorCode
+	record.Error = hdr.Error
+	record.Time = o.start
+	record.Duration = o.observer.clock.Now().Sub(o.start)
+	o.observer.sink.Record(record)
+}
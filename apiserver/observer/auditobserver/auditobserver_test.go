@@ -0,0 +1,160 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditobserver_test
+
+import (
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/observer/auditobserver"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type observerSuite struct {
+	testing.IsolationSuite
+	clock *testclock.Clock
+	sink  *fakeSink
+}
+
+var _ = gc.Suite(&observerSuite{})
+
+func (s *observerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.clock = testclock.NewClock(time.Date(2019, 5, 1, 12, 0, 0, 0, time.UTC))
+	s.sink = &fakeSink{}
+}
+
+func (s *observerSuite) newObserver(c *gc.C) *auditobserver.Observer {
+	return s.newObserverWithAgents(c, false)
+}
+
+func (s *observerSuite) newObserverWithAgents(c *gc.C, includeAgents bool) *auditobserver.Observer {
+	factory, err := auditobserver.NewObserverFactory(auditobserver.Config{
+		Clock:         s.clock,
+		Sink:          s.sink,
+		IncludeAgents: includeAgents,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	o := factory()
+	c.Assert(o, gc.FitsTypeOf, &auditobserver.Observer{})
+	return o.(*auditobserver.Observer)
+}
+
+func (s *observerSuite) TestNewObserverFactoryInvalidConfig(c *gc.C) {
+	_, err := auditobserver.NewObserverFactory(auditobserver.Config{})
+	c.Assert(err, gc.ErrorMatches, "validating config: nil Clock not valid")
+	_, err = auditobserver.NewObserverFactory(auditobserver.Config{Clock: s.clock})
+	c.Assert(err, gc.ErrorMatches, "validating config: nil Sink not valid")
+}
+
+func (s *observerSuite) TestRecordsLogin(c *gc.C) {
+	o := s.newObserver(c)
+	o.Join(nil, 42)
+
+	login := rpc.Request{Type: "Admin", Version: 3, Action: "Login"}
+	rpcObserver := o.RPCObserver()
+	rpcObserver.ServerRequest(&rpc.Header{Request: login}, params.LoginRequest{
+		AuthTag:     "user-bob",
+		Credentials: "hunter2",
+	})
+	o.Login(names.NewUserTag("bob"), coretesting.ModelTag, false, "")
+	s.clock.Advance(time.Second)
+	rpcObserver.ServerReply(login, &rpc.Header{}, params.LoginResult{})
+
+	c.Assert(s.sink.records, jc.DeepEquals, []state.AuditRecord{{
+		ModelUUID:    coretesting.ModelTag.Id(),
+		User:         "user-bob",
+		ConnectionID: 42,
+		Facade:       "Admin",
+		Version:      3,
+		Method:       "Login",
+		Args:         `{"auth-tag":"user-bob","credentials":"[redacted]","macaroons":"[redacted]","nonce":"[redacted]","user-data":""}`,
+		Time:         time.Date(2019, 5, 1, 12, 0, 0, 0, time.UTC),
+		Duration:     time.Second,
+	}})
+}
+
+func (s *observerSuite) TestRecordsErrors(c *gc.C) {
+	o := s.newObserver(c)
+	o.Login(names.NewUserTag("bob"), coretesting.ModelTag, false, "")
+
+	req := rpc.Request{Type: "Application", Version: 9, Action: "Destroy"}
+	rpcObserver := o.RPCObserver()
+	rpcObserver.ServerRequest(&rpc.Header{Request: req}, params.Entities{
+		Entities: []params.Entity{{Tag: "application-mysql"}},
+	})
+	rpcObserver.ServerReply(req, &rpc.Header{
+		ErrorCode: params.CodeUnauthorized,
+		Error:     "permission denied",
+	}, struct{}{})
+
+	c.Assert(s.sink.records, gc.HasLen, 1)
+	record := s.sink.records[0]
+	c.Check(record.Method, gc.Equals, "Destroy")
+	c.Check(record.Args, gc.Equals, `{"entities":[{"tag":"application-mysql"}]}`)
+	c.Check(record.ErrorCode, gc.Equals, params.CodeUnauthorized)
+	c.Check(record.Error, gc.Equals, "permission denied")
+}
+
+func (s *observerSuite) TestIgnoresPings(c *gc.C) {
+	o := s.newObserver(c)
+	req := rpc.Request{Type: "Pinger", Version: 1, Action: "Ping"}
+	rpcObserver := o.RPCObserver()
+	rpcObserver.ServerRequest(&rpc.Header{Request: req}, struct{}{})
+	rpcObserver.ServerReply(req, &rpc.Header{}, struct{}{})
+	c.Assert(s.sink.records, gc.HasLen, 0)
+}
+
+func (s *observerSuite) TestIgnoresAgentsAndWatchers(c *gc.C) {
+	o := s.newObserver(c)
+	o.Login(names.NewUnitTag("mysql/0"), coretesting.ModelTag, false, "")
+	req := rpc.Request{Type: "Uniter", Version: 12, Action: "Life"}
+	rpcObserver := o.RPCObserver()
+	rpcObserver.ServerRequest(&rpc.Header{Request: req}, params.Entities{})
+	rpcObserver.ServerReply(req, &rpc.Header{}, params.LifeResults{})
+
+	o = s.newObserver(c)
+	o.Login(names.NewUserTag("bob"), coretesting.ModelTag, false, "")
+	for _, req := range []rpc.Request{
+		{Type: "Client", Version: 2, Action: "WatchAll"},
+		{Type: "AllWatcher", Version: 1, Action: "Next"},
+	} {
+		rpcObserver := o.RPCObserver()
+		rpcObserver.ServerRequest(&rpc.Header{Request: req}, struct{}{})
+		rpcObserver.ServerReply(req, &rpc.Header{}, struct{}{})
+	}
+	c.Assert(s.sink.records, gc.HasLen, 0)
+}
+
+func (s *observerSuite) TestIncludeAgents(c *gc.C) {
+	o := s.newObserverWithAgents(c, true)
+	o.Login(names.NewUnitTag("mysql/0"), coretesting.ModelTag, false, "")
+	for _, req := range []rpc.Request{
+		{Type: "Uniter", Version: 12, Action: "Life"},
+		{Type: "NotifyWatcher", Version: 1, Action: "Next"},
+	} {
+		rpcObserver := o.RPCObserver()
+		rpcObserver.ServerRequest(&rpc.Header{Request: req}, struct{}{})
+		rpcObserver.ServerReply(req, &rpc.Header{}, struct{}{})
+	}
+	c.Assert(s.sink.records, gc.HasLen, 2)
+	c.Check(s.sink.records[0].User, gc.Equals, "unit-mysql-0")
+	c.Check(s.sink.records[1].Facade, gc.Equals, "NotifyWatcher")
+}
+
+type fakeSink struct {
+	records []state.AuditRecord
+}
+
+func (s *fakeSink) Record(record state.AuditRecord) {
+	s.records = append(s.records, record)
+}
@@ -0,0 +1,7 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package auditobserver provides an implementation
+// of apiserver/observer.ObserverFactory that records
+// an audit trail of every API call made to the controller.
+package auditobserver
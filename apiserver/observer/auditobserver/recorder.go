@@ -0,0 +1,94 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditobserver
+
+import (
+	"sync/atomic"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/worker.v1/catacomb"
+
+	"github.com/juju/juju/state"
+)
+
+// DefaultBufferSize is the number of audit records a Recorder holds
+// while waiting for them to be written.
+const DefaultBufferSize = 1000
+
+// Sink accepts audit records to be written to the audit trail.
+type Sink interface {
+	// Record queues the record to be written. It must not block.
+	Record(state.AuditRecord)
+}
+
+// Recorder is a worker that writes audit records to a Store in the
+// background, so that API calls never wait on the audit trail. When
+// the store can't keep up and the buffer fills, records are dropped,
+// and the number dropped is logged once the store catches up.
+type Recorder struct {
+	// dropped is accessed atomically, and is kept first for alignment.
+	dropped int64
+
+	catacomb catacomb.Catacomb
+	store    Store
+	records  chan state.AuditRecord
+}
+
+// NewRecorder returns a new Recorder which holds up to bufferSize
+// records while writing them to the store.
+func NewRecorder(store Store, bufferSize int) (*Recorder, error) {
+	if store == nil {
+		return nil, errors.NotValidf("nil Store")
+	}
+	if bufferSize <= 0 {
+		return nil, errors.NotValidf("buffer size %d", bufferSize)
+	}
+	r := &Recorder{
+		store:   store,
+		records: make(chan state.AuditRecord, bufferSize),
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &r.catacomb,
+		Work: r.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return r, nil
+}
+
+// Record is part of the Sink interface.
+func (r *Recorder) Record(record state.AuditRecord) {
+	select {
+	case r.records <- record:
+	default:
+		atomic.AddInt64(&r.dropped, 1)
+	}
+}
+
+// Kill is part of the worker.Worker interface.
+func (r *Recorder) Kill() {
+	r.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (r *Recorder) Wait() error {
+	return r.catacomb.Wait()
+}
+
+func (r *Recorder) loop() error {
+	for {
+		select {
+		case <-r.catacomb.Dying():
+			return r.catacomb.ErrDying()
+		case record := <-r.records:
+			if err := r.store.AddAuditRecord(record); err != nil {
+				logger.Warningf("cannot record call to %s.%s: %v", record.Facade, record.Method, err)
+			}
+			if dropped := atomic.SwapInt64(&r.dropped, 0); dropped > 0 {
+				logger.Warningf("dropped %d audit records while the audit trail was busy", dropped)
+			}
+		}
+	}
+}
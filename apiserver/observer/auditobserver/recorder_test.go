@@ -0,0 +1,121 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditobserver_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/worker.v1/workertest"
+
+	"github.com/juju/juju/apiserver/observer/auditobserver"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type recorderSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&recorderSuite{})
+
+func (s *recorderSuite) TestInvalidArgs(c *gc.C) {
+	_, err := auditobserver.NewRecorder(nil, 1)
+	c.Assert(err, gc.ErrorMatches, "nil Store not valid")
+	_, err = auditobserver.NewRecorder(&blockingStore{}, 0)
+	c.Assert(err, gc.ErrorMatches, "buffer size 0 not valid")
+}
+
+func (s *recorderSuite) TestRecordsInBackground(c *gc.C) {
+	store := newBlockingStore()
+	r, err := auditobserver.NewRecorder(store, 10)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, r)
+
+	r.Record(state.AuditRecord{Method: "One"})
+	r.Record(state.AuditRecord{Method: "Two"})
+	c.Assert(store.next(c).Method, gc.Equals, "One")
+	c.Assert(store.next(c).Method, gc.Equals, "Two")
+}
+
+func (s *recorderSuite) TestDropsWhenFull(c *gc.C) {
+	store := newBlockingStore()
+	r, err := auditobserver.NewRecorder(store, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, r)
+
+	// The first record is taken by the recorder, which then blocks
+	// writing it; the second fills the buffer, and the rest are
+	// dropped without blocking the caller.
+	r.Record(state.AuditRecord{Method: "One"})
+	store.waitForWrite(c)
+	for i := 0; i < 5; i++ {
+		r.Record(state.AuditRecord{Method: "Two"})
+	}
+	c.Assert(store.next(c).Method, gc.Equals, "One")
+	c.Assert(store.next(c).Method, gc.Equals, "Two")
+	store.checkNoWrite(c)
+}
+
+func (s *recorderSuite) TestStoreErrorIgnored(c *gc.C) {
+	store := newBlockingStore()
+	store.err = errors.New("boom")
+	r, err := auditobserver.NewRecorder(store, 10)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, r)
+
+	r.Record(state.AuditRecord{Method: "One"})
+	r.Record(state.AuditRecord{Method: "Two"})
+	c.Assert(store.next(c).Method, gc.Equals, "One")
+	c.Assert(store.next(c).Method, gc.Equals, "Two")
+}
+
+// blockingStore holds each write until the test takes it.
+type blockingStore struct {
+	writing chan struct{}
+	records chan state.AuditRecord
+	err     error
+}
+
+func newBlockingStore() *blockingStore {
+	return &blockingStore{
+		writing: make(chan struct{}, 10),
+		records: make(chan state.AuditRecord),
+	}
+}
+
+func (s *blockingStore) AddAuditRecord(record state.AuditRecord) error {
+	s.writing <- struct{}{}
+	s.records <- record
+	return s.err
+}
+
+func (s *blockingStore) waitForWrite(c *gc.C) {
+	select {
+	case <-s.writing:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for write")
+	}
+}
+
+func (s *blockingStore) next(c *gc.C) state.AuditRecord {
+	select {
+	case record := <-s.records:
+		return record
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for record")
+	}
+	panic("unreachable")
+}
+
+func (s *blockingStore) checkNoWrite(c *gc.C) {
+	select {
+	case record := <-s.records:
+		c.Fatalf("unexpected record %+v", record)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
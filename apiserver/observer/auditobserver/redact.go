@@ -0,0 +1,111 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditobserver
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+const (
+	// Redacted replaces the values of secret arguments.
+	Redacted = "[redacted]"
+
+	// maxArgsSize is the size above which recorded arguments are
+	// truncated, so that large requests don't crowd out the rest of
+	// the audit trail.
+	maxArgsSize = 16 * 1024
+)
+
+// secretKeys holds the names of arguments whose values are always
+// secret, such as the credentials used to log in or the attributes of
+// a cloud credential.
+var secretKeys = map[string]bool{
+	"api-key":     true,
+	"credential":  true,
+	"credentials": true,
+	"macaroon":    true,
+	"macaroons":   true,
+	"nonce":       true,
+}
+
+// opaqueKeys holds the names of arguments holding charm config,
+// relation settings and action parameters. Their contents are defined
+// by charms, which may store secrets under any name, so they are
+// redacted wholesale.
+var opaqueKeys = map[string]bool{
+	"config":               true,
+	"config-settings":      true,
+	"config-settings-yaml": true,
+	"config-yaml":          true,
+	"options":              true,
+	"parameters":           true,
+	"settings":             true,
+	"settings-yaml":        true,
+}
+
+// secretKeyParts holds parts of argument names that mark their values
+// as secret.
+var secretKeyParts = []string{
+	"password",
+	"secret",
+	"token",
+	"private-key",
+}
+
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	if secretKeys[key] || opaqueKeys[key] {
+		return true
+	}
+	for _, part := range secretKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactArgs returns the JSON serialisation of the given API call
+// arguments, with the values of any secret arguments, and of any charm
+// config, relation settings or action parameters, replaced by
+// Redacted. Arguments larger than 16KiB are truncated.
+func RedactArgs(args interface{}) (string, error) {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return "", errors.Trace(err)
+	}
+	data, err = json.Marshal(redact(value))
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if len(data) > maxArgsSize {
+		return string(data[:maxArgsSize]) + "...", nil
+	}
+	return string(data), nil
+}
+
+func redact(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, v := range value {
+			if isSecretKey(key) {
+				value[key] = Redacted
+			} else {
+				value[key] = redact(v)
+			}
+		}
+	case []interface{}:
+		for i, v := range value {
+			value[i] = redact(v)
+		}
+	}
+	return value
+}
@@ -0,0 +1,93 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditobserver_test
+
+import (
+	"strings"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/observer/auditobserver"
+	"github.com/juju/juju/apiserver/params"
+)
+
+type redactSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&redactSuite{})
+
+func (*redactSuite) TestRedactLogin(c *gc.C) {
+	args, err := auditobserver.RedactArgs(params.LoginRequest{
+		AuthTag:     "user-bob",
+		Credentials: "hunter2",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(args, jc.Contains, `"auth-tag":"user-bob"`)
+	c.Assert(args, jc.Contains, `"credentials":"[redacted]"`)
+	c.Assert(args, gc.Not(jc.Contains), "hunter2")
+}
+
+func (*redactSuite) TestRedactNested(c *gc.C) {
+	args, err := auditobserver.RedactArgs(params.TaggedCredential{
+		Tag: "cloudcred-aws_bob_default",
+		Credential: params.CloudCredential{
+			AuthType:   "access-key",
+			Attributes: map[string]string{"access-key": "abc"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(args, gc.Equals, `{"credential":"[redacted]","tag":"cloudcred-aws_bob_default"}`)
+
+	args, err = auditobserver.RedactArgs(map[string]interface{}{
+		"entities": []interface{}{
+			map[string]interface{}{
+				"tag":             "user-bob",
+				"password":        "hunter2",
+				"auth-token":      "abc",
+				"ssh-private-key": "key",
+			},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(args, gc.Equals,
+		`{"entities":[{"auth-token":"[redacted]","password":"[redacted]","ssh-private-key":"[redacted]","tag":"user-bob"}]}`)
+}
+
+func (*redactSuite) TestRedactCharmDefinedValues(c *gc.C) {
+	args, err := auditobserver.RedactArgs(params.ApplicationSet{
+		ApplicationName: "mysql",
+		Options:         map[string]string{"root-pw": "hunter2"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(args, gc.Equals, `{"application":"mysql","branch":"","options":"[redacted]"}`)
+
+	args, err = auditobserver.RedactArgs(params.RelationUnitSettings{
+		Relation: "relation-wordpress.db#mysql.server",
+		Unit:     "unit-mysql-0",
+		Settings: params.Settings{"pw": "hunter2"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(args, gc.Equals, `{"relation":"relation-wordpress.db#mysql.server","settings":"[redacted]","unit":"unit-mysql-0"}`)
+
+	args, err = auditobserver.RedactArgs(params.Action{
+		Receiver:   "unit-mysql-0",
+		Name:       "backup",
+		Parameters: map[string]interface{}{"target": "s3://bucket"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(args, jc.Contains, `"parameters":"[redacted]"`)
+	c.Assert(args, gc.Not(jc.Contains), "s3://bucket")
+}
+
+func (*redactSuite) TestRedactTruncates(c *gc.C) {
+	args, err := auditobserver.RedactArgs(map[string]string{
+		"data": strings.Repeat("x", 20*1024),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(args, gc.HasLen, 16*1024+3)
+	c.Assert(strings.HasSuffix(args, "..."), jc.IsTrue)
+}
@@ -17,7 +17,8 @@ import (
 	"github.com/juju/juju/rpc"
 )
 
-// MetricLabels used for setting labels for the Counter and Summary vectors.
+// MetricLabels used for setting labels for the Counter, Summary and
+// Histogram vectors.
 const (
 	MetricLabelFacade    = "facade"
 	MetricLabelVersion   = "version"
@@ -34,6 +35,14 @@ var MetricLabelNames = []string{
 	MetricLabelErrorCode,
 }
 
+// MetricRequestLabelNames holds the names of the labels for the per-facade
+// request count and latency metrics, which are not split by error code.
+var MetricRequestLabelNames = []string{
+	MetricLabelFacade,
+	MetricLabelVersion,
+	MetricLabelMethod,
+}
+
 // CounterVec is a Collector that bundles a set of Counters that all share the
 // same description.
 type CounterVec interface {
@@ -48,15 +57,34 @@ type SummaryVec interface {
 	With(prometheus.Labels) prometheus.Observer
 }
 
+// HistogramVec is a Collector that bundles a set of Histograms that all share
+// the same description.
+type HistogramVec interface {
+	// With returns a Histogram for a given labels slice
+	With(prometheus.Labels) prometheus.Observer
+}
+
 // MetricsCollector represents a bundle of metrics that is used by the observer
 // factory.
-//go:generate mockgen -package mocks -destination mocks/metrics_collector_mock.go github.com/juju/juju/apiserver/observer/metricobserver MetricsCollector,CounterVec,SummaryVec
+//go:generate mockgen -package mocks -destination mocks/metrics_collector_mock.go github.com/juju/juju/apiserver/observer/metricobserver MetricsCollector,CounterVec,SummaryVec,HistogramVec
 //go:generate mockgen -package mocks -destination mocks/metrics_mock.go github.com/prometheus/client_golang/prometheus Counter,Summary
 type MetricsCollector interface {
 	// APIRequestDuration returns a SummaryVec for updating the duration of
 	// api request duration.
 	APIRequestDuration() SummaryVec
 
+	// APIRequestsTotal returns a CounterVec for updating the number of api
+	// requests served, by facade, version and method.
+	APIRequestsTotal() CounterVec
+
+	// APIRequestErrors returns a CounterVec for updating the number of api
+	// requests that failed, by facade, version, method and error code.
+	APIRequestErrors() CounterVec
+
+	// APIRequestLatency returns a HistogramVec for updating the latency of
+	// api requests, by facade, version and method.
+	APIRequestLatency() HistogramVec
+
 	// DeprecatedAPIRequestsTotal returns a CounterVec for updating the number of
 	// api requests total.
 	// The following is obsolete and should be removed for 2.6 release
@@ -103,6 +131,9 @@ func NewObserverFactory(config Config) (observer.ObserverFactory, error) {
 		clock: config.Clock,
 		metrics: metrics{
 			apiRequestDuration:           config.MetricsCollector.APIRequestDuration(),
+			apiRequestsTotal:             config.MetricsCollector.APIRequestsTotal(),
+			apiRequestErrors:             config.MetricsCollector.APIRequestErrors(),
+			apiRequestLatency:            config.MetricsCollector.APIRequestLatency(),
 			deprecatedAPIRequestsTotal:   config.MetricsCollector.DeprecatedAPIRequestsTotal(),
 			deprecatedAPIRequestDuration: config.MetricsCollector.DeprecatedAPIRequestDuration(),
 		},
@@ -120,6 +151,9 @@ type Observer struct {
 
 type metrics struct {
 	apiRequestDuration           SummaryVec
+	apiRequestsTotal             CounterVec
+	apiRequestErrors             CounterVec
+	apiRequestLatency            HistogramVec
 	deprecatedAPIRequestDuration SummaryVec
 	deprecatedAPIRequestsTotal   CounterVec
 }
@@ -163,6 +197,17 @@ func (o *rpcObserver) ServerReply(req rpc.Request, hdr *rpc.Header, body interfa
 	duration := o.clock.Now().Sub(o.requestStart)
 	o.metrics.apiRequestDuration.With(labels).Observe(duration.Seconds())
 
+	requestLabels := prometheus.Labels{
+		MetricLabelFacade:  req.Type,
+		MetricLabelVersion: strconv.Itoa(req.Version),
+		MetricLabelMethod:  req.Action,
+	}
+	o.metrics.apiRequestsTotal.With(requestLabels).Inc()
+	o.metrics.apiRequestLatency.With(requestLabels).Observe(duration.Seconds())
+	if hdr.Error != "" {
+		o.metrics.apiRequestErrors.With(labels).Inc()
+	}
+
 	// The following is obsolete and should be removed for 2.6 release
 	o.metrics.deprecatedAPIRequestDuration.With(labels).Observe(duration.Seconds())
 	o.metrics.deprecatedAPIRequestsTotal.With(labels).Inc()
@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/juju/juju/apiserver/observer/metricobserver (interfaces: MetricsCollector,CounterVec,SummaryVec)
+// Source: github.com/juju/juju/apiserver/observer/metricobserver (interfaces: MetricsCollector,CounterVec,SummaryVec,HistogramVec)
 
 // Package mocks is a generated GoMock package.
 package mocks
@@ -46,6 +46,42 @@ func (mr *MockMetricsCollectorMockRecorder) APIRequestDuration() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "APIRequestDuration", reflect.TypeOf((*MockMetricsCollector)(nil).APIRequestDuration))
 }
 
+// APIRequestErrors mocks base method
+func (m *MockMetricsCollector) APIRequestErrors() metricobserver.CounterVec {
+	ret := m.ctrl.Call(m, "APIRequestErrors")
+	ret0, _ := ret[0].(metricobserver.CounterVec)
+	return ret0
+}
+
+// APIRequestErrors indicates an expected call of APIRequestErrors
+func (mr *MockMetricsCollectorMockRecorder) APIRequestErrors() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "APIRequestErrors", reflect.TypeOf((*MockMetricsCollector)(nil).APIRequestErrors))
+}
+
+// APIRequestLatency mocks base method
+func (m *MockMetricsCollector) APIRequestLatency() metricobserver.HistogramVec {
+	ret := m.ctrl.Call(m, "APIRequestLatency")
+	ret0, _ := ret[0].(metricobserver.HistogramVec)
+	return ret0
+}
+
+// APIRequestLatency indicates an expected call of APIRequestLatency
+func (mr *MockMetricsCollectorMockRecorder) APIRequestLatency() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "APIRequestLatency", reflect.TypeOf((*MockMetricsCollector)(nil).APIRequestLatency))
+}
+
+// APIRequestsTotal mocks base method
+func (m *MockMetricsCollector) APIRequestsTotal() metricobserver.CounterVec {
+	ret := m.ctrl.Call(m, "APIRequestsTotal")
+	ret0, _ := ret[0].(metricobserver.CounterVec)
+	return ret0
+}
+
+// APIRequestsTotal indicates an expected call of APIRequestsTotal
+func (mr *MockMetricsCollectorMockRecorder) APIRequestsTotal() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "APIRequestsTotal", reflect.TypeOf((*MockMetricsCollector)(nil).APIRequestsTotal))
+}
+
 // DeprecatedAPIRequestDuration mocks base method
 func (m *MockMetricsCollector) DeprecatedAPIRequestDuration() metricobserver.SummaryVec {
 	ret := m.ctrl.Call(m, "DeprecatedAPIRequestDuration")
@@ -139,3 +175,38 @@ func (m *MockSummaryVec) With(arg0 prometheus.Labels) prometheus.Observer {
 func (mr *MockSummaryVecMockRecorder) With(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "With", reflect.TypeOf((*MockSummaryVec)(nil).With), arg0)
 }
+
+// MockHistogramVec is a mock of HistogramVec interface
+type MockHistogramVec struct {
+	ctrl     *gomock.Controller
+	recorder *MockHistogramVecMockRecorder
+}
+
+// MockHistogramVecMockRecorder is the mock recorder for MockHistogramVec
+type MockHistogramVecMockRecorder struct {
+	mock *MockHistogramVec
+}
+
+// NewMockHistogramVec creates a new mock instance
+func NewMockHistogramVec(ctrl *gomock.Controller) *MockHistogramVec {
+	mock := &MockHistogramVec{ctrl: ctrl}
+	mock.recorder = &MockHistogramVecMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockHistogramVec) EXPECT() *MockHistogramVecMockRecorder {
+	return m.recorder
+}
+
+// With mocks base method
+func (m *MockHistogramVec) With(arg0 prometheus.Labels) prometheus.Observer {
+	ret := m.ctrl.Call(m, "With", arg0)
+	ret0, _ := ret[0].(prometheus.Observer)
+	return ret0
+}
+
+// With indicates an expected call of With
+func (mr *MockHistogramVecMockRecorder) With(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "With", reflect.TypeOf((*MockHistogramVec)(nil).With), arg0)
+}
@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/golang/mock/gomock"
 	"github.com/juju/clock/testclock"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/juju/juju/apiserver/observer"
 	"github.com/juju/juju/apiserver/observer/metricobserver"
+	"github.com/juju/juju/apiserver/observer/metricobserver/mocks"
 	"github.com/juju/juju/rpc"
 )
 
@@ -58,16 +60,86 @@ func (s *observerSuite) TestRPCObserver(c *gc.C) {
 		}
 		o.ServerRequest(&rpc.Header{Request: req}, nil)
 		s.clock.Advance(latency)
-		o.ServerReply(req, &rpc.Header{ErrorCode: "badness"}, nil)
+		o.ServerReply(req, &rpc.Header{Error: "bad", ErrorCode: "badness"}, nil)
 	}
 }
 
+func (s *observerSuite) TestRPCObserverCountsRequestsAndErrors(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	requestLabels := prometheus.Labels{
+		metricobserver.MetricLabelFacade:  "api-facade",
+		metricobserver.MetricLabelVersion: "1",
+		metricobserver.MetricLabelMethod:  "api-method",
+	}
+	errorLabels := prometheus.Labels{
+		metricobserver.MetricLabelFacade:    "api-facade",
+		metricobserver.MetricLabelVersion:   "1",
+		metricobserver.MetricLabelMethod:    "api-method",
+		metricobserver.MetricLabelErrorCode: "not found",
+	}
+
+	summary := mocks.NewMockSummary(ctrl)
+	summary.EXPECT().Observe(gomock.Any()).AnyTimes()
+	summaryVec := mocks.NewMockSummaryVec(ctrl)
+	summaryVec.EXPECT().With(gomock.Any()).Return(summary).AnyTimes()
+	deprecatedCounter := mocks.NewMockCounter(ctrl)
+	deprecatedCounter.EXPECT().Inc().AnyTimes()
+	deprecatedCounterVec := mocks.NewMockCounterVec(ctrl)
+	deprecatedCounterVec.EXPECT().With(gomock.Any()).Return(deprecatedCounter).AnyTimes()
+
+	// Two requests are served, only one of which fails.
+	requests := mocks.NewMockCounter(ctrl)
+	requests.EXPECT().Inc().Times(2)
+	requestsVec := mocks.NewMockCounterVec(ctrl)
+	requestsVec.EXPECT().With(requestLabels).Return(requests).Times(2)
+
+	errs := mocks.NewMockCounter(ctrl)
+	errs.EXPECT().Inc().Times(1)
+	errorsVec := mocks.NewMockCounterVec(ctrl)
+	errorsVec.EXPECT().With(errorLabels).Return(errs).Times(1)
+
+	latency := mocks.NewMockSummary(ctrl)
+	latency.EXPECT().Observe(float64(1)).Times(2)
+	latencyVec := mocks.NewMockHistogramVec(ctrl)
+	latencyVec.EXPECT().With(requestLabels).Return(latency).Times(2)
+
+	metricsCollector := mocks.NewMockMetricsCollector(ctrl)
+	metricsCollector.EXPECT().APIRequestDuration().Return(summaryVec)
+	metricsCollector.EXPECT().APIRequestsTotal().Return(requestsVec)
+	metricsCollector.EXPECT().APIRequestErrors().Return(errorsVec)
+	metricsCollector.EXPECT().APIRequestLatency().Return(latencyVec)
+	metricsCollector.EXPECT().DeprecatedAPIRequestsTotal().Return(deprecatedCounterVec)
+	metricsCollector.EXPECT().DeprecatedAPIRequestDuration().Return(summaryVec)
+
+	factory, err := metricobserver.NewObserverFactory(metricobserver.Config{
+		Clock:            s.clock,
+		MetricsCollector: metricsCollector,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	o := factory().RPCObserver()
+
+	req := rpc.Request{Type: "api-facade", Version: 1, Action: "api-method"}
+	o.ServerRequest(&rpc.Header{Request: req}, nil)
+	s.clock.Advance(time.Second)
+	o.ServerReply(req, &rpc.Header{}, nil)
+
+	o.ServerRequest(&rpc.Header{Request: req}, nil)
+	s.clock.Advance(time.Second)
+	o.ServerReply(req, &rpc.Header{Error: "no such thing", ErrorCode: "not found"}, nil)
+}
+
 func (s *observerSuite) createFactory(c *gc.C) (observer.ObserverFactory, func()) {
 	metricsCollector, finish := createMockMetrics(c, prometheus.Labels{
 		metricobserver.MetricLabelFacade:    "api-facade",
 		metricobserver.MetricLabelVersion:   strconv.Itoa(42),
 		metricobserver.MetricLabelMethod:    "api-method",
 		metricobserver.MetricLabelErrorCode: "badness",
+	}, prometheus.Labels{
+		metricobserver.MetricLabelFacade:  "api-facade",
+		metricobserver.MetricLabelVersion: strconv.Itoa(42),
+		metricobserver.MetricLabelMethod:  "api-method",
 	})
 
 	factory, err := metricobserver.NewObserverFactory(metricobserver.Config{
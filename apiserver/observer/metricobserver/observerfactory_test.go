@@ -34,7 +34,10 @@ func (*observerFactorySuite) TestNewObserverFactoryInvalidConfig(c *gc.C) {
 }
 
 func (s *observerFactorySuite) TestNewObserverFactoryRegister(c *gc.C) {
-	metricsCollector, finish := createMockMetrics(c, gomock.AssignableToTypeOf(prometheus.Labels{}))
+	metricsCollector, finish := createMockMetrics(c,
+		gomock.AssignableToTypeOf(prometheus.Labels{}),
+		gomock.AssignableToTypeOf(prometheus.Labels{}),
+	)
 	defer finish()
 
 	f, err := metricobserver.NewObserverFactory(metricobserver.Config{
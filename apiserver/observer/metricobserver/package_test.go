@@ -16,7 +16,7 @@ func Test(t *testing.T) {
 	gc.TestingT(t)
 }
 
-func createMockMetrics(c *gc.C, labels interface{}) (*mocks.MockMetricsCollector, func()) {
+func createMockMetrics(c *gc.C, labels, requestLabels interface{}) (*mocks.MockMetricsCollector, func()) {
 	ctrl := gomock.NewController(c)
 
 	counter := mocks.NewMockCounter(ctrl)
@@ -31,8 +31,17 @@ func createMockMetrics(c *gc.C, labels interface{}) (*mocks.MockMetricsCollector
 	summaryVec := mocks.NewMockSummaryVec(ctrl)
 	summaryVec.EXPECT().With(labels).Return(summary).AnyTimes()
 
+	requestCounterVec := mocks.NewMockCounterVec(ctrl)
+	requestCounterVec.EXPECT().With(requestLabels).Return(counter).AnyTimes()
+
+	histogramVec := mocks.NewMockHistogramVec(ctrl)
+	histogramVec.EXPECT().With(requestLabels).Return(summary).AnyTimes()
+
 	metricsCollector := mocks.NewMockMetricsCollector(ctrl)
 	metricsCollector.EXPECT().APIRequestDuration().Return(summaryVec).AnyTimes()
+	metricsCollector.EXPECT().APIRequestsTotal().Return(requestCounterVec).AnyTimes()
+	metricsCollector.EXPECT().APIRequestErrors().Return(counterVec).AnyTimes()
+	metricsCollector.EXPECT().APIRequestLatency().Return(histogramVec).AnyTimes()
 
 	metricsCollector.EXPECT().DeprecatedAPIRequestsTotal().Return(counterVec).AnyTimes()
 	metricsCollector.EXPECT().DeprecatedAPIRequestDuration().Return(summaryVec).AnyTimes()
@@ -30,6 +30,19 @@ const (
 // Actions is a slice of Action for bulk requests.
 type Actions struct {
 	Actions []Action `json:"actions,omitempty"`
+
+	// Webhook, if set, is invoked once all of the actions have
+	// completed.
+	Webhook *ActionWebhook `json:"webhook,omitempty"`
+}
+
+// ActionWebhook describes a URL that is posted a summary of the results
+// of an operation's actions once they have all completed. The URL must
+// be of a public host. If Secret is set, the payload and the time of
+// the delivery are signed with it.
+type ActionWebhook struct {
+	URL    string `json:"url"`
+	Secret string `json:"secret,omitempty"`
 }
 
 // Action describes an Action that will be or has been queued up.
@@ -38,11 +51,45 @@ type Action struct {
 	Receiver   string                 `json:"receiver"`
 	Name       string                 `json:"name"`
 	Parameters map[string]interface{} `json:"parameters,omitempty"`
+
+	// IdempotencyKey, if set when enqueueing, ensures that a retry of
+	// the call with the same key does not enqueue the action again.
+	IdempotencyKey string `json:"idempotency-key,omitempty"`
+}
+
+// ActionWebhookOperations holds the ids of operations with webhooks.
+type ActionWebhookOperations struct {
+	Operations []string `json:"operations"`
+}
+
+// ActionWebhookResult holds the webhook of an operation and, once all
+// of the operation's actions have completed, their results.
+type ActionWebhookResult struct {
+	Operation string         `json:"operation"`
+	URL       string         `json:"url,omitempty"`
+	Secret    string         `json:"secret,omitempty"`
+	Completed bool           `json:"completed"`
+	Actions   []ActionResult `json:"actions,omitempty"`
+	Error     *Error         `json:"error,omitempty"`
+}
+
+// ActionWebhookResults holds the webhooks of a number of operations.
+type ActionWebhookResults struct {
+	Results []ActionWebhookResult `json:"results"`
 }
 
 // ActionResults is a slice of ActionResult for bulk requests.
 type ActionResults struct {
 	Results []ActionResult `json:"results,omitempty"`
+
+	// Operation identifies the operation the enqueued actions make up,
+	// if a webhook was requested for them.
+	Operation string `json:"operation,omitempty"`
+
+	// WebhookError is set if a webhook was requested for the enqueued
+	// actions, but could not be added. The actions are enqueued
+	// regardless.
+	WebhookError *Error `json:"webhook-error,omitempty"`
 }
 
 // ActionResult describes an Action that will be or has been completed.
@@ -57,9 +104,20 @@ type ActionResult struct {
 	Error     *Error                 `json:"error,omitempty"`
 }
 
+// ListActionsArgs holds the arguments for listing the Actions of
+// several ActionReceivers a page at a time.
+type ListActionsArgs struct {
+	Entities []Entity   `json:"entities"`
+	Page     PageParams `json:"page"`
+}
+
 // ActionsByReceivers wrap a slice of Actions for API calls.
 type ActionsByReceivers struct {
 	Actions []ActionsByReceiver `json:"actions,omitempty"`
+
+	// Page describes the page of Actions returned, if a page was
+	// requested.
+	Page *PageInfo `json:"page,omitempty"`
 }
 
 // ActionsByReceiver is a bulk API call wrapper containing Actions,
@@ -25,3 +25,18 @@ type EntityAnnotations struct {
 	EntityTag   string            `json:"entity"`
 	Annotations map[string]string `json:"annotations"`
 }
+
+// InstanceTagsResults holds the provider tags to set on the instances
+// of a model's machines.
+type InstanceTagsResults struct {
+	Results []InstanceTagsResult `json:"results"`
+}
+
+// InstanceTagsResult holds the provider tags mirrored from the
+// annotations of a machine and its units.
+type InstanceTagsResult struct {
+	MachineTag string            `json:"machine-tag"`
+	InstanceId string            `json:"instance-id,omitempty"`
+	Tags       map[string]string `json:"tags,omitempty"`
+	Error      *Error            `json:"error,omitempty"`
+}
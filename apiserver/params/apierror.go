@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -112,6 +113,47 @@ func (e RedirectErrorInfo) AsMap() map[string]interface{} {
 	return serializeToMap(e)
 }
 
+// SettingsTooLargeErrorInfo provides additional information for
+// SettingsTooLarge errors.
+type SettingsTooLargeErrorInfo struct {
+	// Size holds the size in bytes that the settings would have had.
+	Size int `json:"size"`
+
+	// Limit holds the maximum size in bytes of the settings.
+	Limit int `json:"limit"`
+}
+
+// AsMap encodes the error info as a map that can be attached to an Error.
+func (e SettingsTooLargeErrorInfo) AsMap() map[string]interface{} {
+	return serializeToMap(e)
+}
+
+// RateLimitExceededErrorInfo provides additional information for
+// RateLimitExceeded errors.
+type RateLimitExceededErrorInfo struct {
+	// RetryAfter holds how long the client should wait before
+	// making another request.
+	RetryAfter time.Duration `json:"retry-after"`
+}
+
+// AsMap encodes the error info as a map that can be attached to an Error.
+func (e RateLimitExceededErrorInfo) AsMap() map[string]interface{} {
+	return serializeToMap(e)
+}
+
+// ServerDrainingErrorInfo provides additional information for
+// ServerDraining errors.
+type ServerDrainingErrorInfo struct {
+	// RetryAfter holds how long the client should wait before
+	// reconnecting to the same API server.
+	RetryAfter time.Duration `json:"retry-after"`
+}
+
+// AsMap encodes the error info as a map that can be attached to an Error.
+func (e ServerDrainingErrorInfo) AsMap() map[string]interface{} {
+	return serializeToMap(e)
+}
+
 // serializeToMap is a convenience function for marshaling v into a
 // map[string]interface{}. It works by marshalling v into json and then
 // unmarshaling back to a map.
@@ -174,6 +216,10 @@ const (
 	CodeIncompatibleSeries        = "incompatible series"
 	CodeCloudRegionRequired       = "cloud region required"
 	CodeIncompatibleClouds        = "incompatible clouds"
+	CodeSecondFactorRequired      = "second factor required"
+	CodeSettingsTooLarge          = "settings too large"
+	CodeRateLimitExceeded         = "rate limit exceeded"
+	CodeServerDraining            = "server draining"
 )
 
 // ErrCode returns the error code associated with
@@ -296,6 +342,33 @@ func IsCodeTryAgain(err error) bool {
 	return ErrCode(err) == CodeTryAgain
 }
 
+// IsCodeSecondFactorRequired returns true if the error indicates that a
+// recent second-factor assertion is needed before the operation may be
+// performed.
+func IsCodeSecondFactorRequired(err error) bool {
+	return ErrCode(err) == CodeSecondFactorRequired
+}
+
+// IsCodeSettingsTooLarge returns true if the error indicates that
+// settings were refused because they would exceed the size limit.
+func IsCodeSettingsTooLarge(err error) bool {
+	return ErrCode(err) == CodeSettingsTooLarge
+}
+
+// IsCodeRateLimitExceeded returns true if the error indicates that the
+// request was refused because the client has made too many requests, and
+// should try again later.
+func IsCodeRateLimitExceeded(err error) bool {
+	return ErrCode(err) == CodeRateLimitExceeded
+}
+
+// IsCodeServerDraining returns true if the error indicates that the
+// request was refused because the API server is restarting, and the
+// client should reconnect, to another API server if there is one.
+func IsCodeServerDraining(err error) bool {
+	return ErrCode(err) == CodeServerDraining
+}
+
 func IsCodeNotImplemented(err error) bool {
 	return ErrCode(err) == CodeNotImplemented
 }
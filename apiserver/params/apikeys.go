@@ -0,0 +1,67 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import (
+	"time"
+)
+
+// IssueAPIKeysArgs holds the parameters for issuing API keys.
+type IssueAPIKeysArgs struct {
+	Keys []IssueAPIKeyArg `json:"keys"`
+}
+
+// IssueAPIKeyArg holds the parameters for issuing a single API key.
+type IssueAPIKeyArg struct {
+	// ModelTag is the model the key grants access to.
+	ModelTag string `json:"model-tag"`
+
+	// Access is the maximum model access level granted by the key;
+	// one of "read", "write" or "admin".
+	Access string `json:"access"`
+
+	// Expires, if set, is the time after which the key is rejected.
+	Expires *time.Time `json:"expires,omitempty"`
+}
+
+// IssueAPIKeyResults holds the results of the bulk IssueAPIKeys call.
+type IssueAPIKeyResults struct {
+	Results []IssueAPIKeyResult `json:"results"`
+}
+
+// IssueAPIKeyResult holds a newly issued API key. Token is the value
+// clients pass as APIKey in their LoginRequest; it is only returned
+// once and cannot be retrieved later.
+type IssueAPIKeyResult struct {
+	Key   *APIKeyInfo `json:"key,omitempty"`
+	Token string      `json:"token,omitempty"`
+	Error *Error      `json:"error,omitempty"`
+}
+
+// APIKeyInfo describes an issued API key, without its secret.
+type APIKeyInfo struct {
+	Id       string     `json:"id"`
+	ModelTag string     `json:"model-tag"`
+	OwnerTag string     `json:"owner-tag"`
+	Access   string     `json:"access"`
+	Created  time.Time  `json:"created"`
+	Expires  *time.Time `json:"expires,omitempty"`
+	Revoked  bool       `json:"revoked"`
+}
+
+// ListAPIKeysResults holds the results of the bulk ListAPIKeys call.
+type ListAPIKeysResults struct {
+	Results []ListAPIKeysResult `json:"results"`
+}
+
+// ListAPIKeysResult holds the API keys issued for a single model.
+type ListAPIKeysResult struct {
+	Keys  []APIKeyInfo `json:"keys,omitempty"`
+	Error *Error       `json:"error,omitempty"`
+}
+
+// RevokeAPIKeysArgs holds the IDs of API keys to revoke.
+type RevokeAPIKeysArgs struct {
+	Ids []string `json:"ids"`
+}
@@ -34,6 +34,7 @@ type ApplicationDeploy struct {
 	AttachStorage    []string                       `json:"attach-storage,omitempty"`
 	EndpointBindings map[string]string              `json:"endpoint-bindings,omitempty"`
 	Resources        map[string]string              `json:"resources,omitempty"`
+	IdempotencyKey   string                         `json:"idempotency-key,omitempty"`
 }
 
 // ApplicationsDeployV5 holds the parameters for deploying one or more applications.
@@ -149,6 +150,24 @@ type ApplicationSetCharm struct {
 // ApplicationExpose holds the parameters for making the application Expose call.
 type ApplicationExpose struct {
 	ApplicationName string `json:"application"`
+
+	// ExposedEndpoints optionally limits the sources allowed to reach
+	// each named endpoint. An empty endpoint name refers to every
+	// endpoint of the application. If no endpoints are specified, the
+	// whole application is exposed.
+	ExposedEndpoints map[string]ExposedEndpoint `json:"exposed-endpoints,omitempty"`
+}
+
+// ExposedEndpoint describes the sources allowed to reach the ports
+// opened for an exposed application endpoint. If neither spaces nor
+// CIDRs are specified, the endpoint may be reached from anywhere.
+type ExposedEndpoint struct {
+	// ExposeToSpaces holds the names of the spaces whose subnets
+	// may reach the endpoint.
+	ExposeToSpaces []string `json:"expose-to-spaces,omitempty"`
+
+	// ExposeToCIDRs holds the CIDRs which may reach the endpoint.
+	ExposeToCIDRs []string `json:"expose-to-cidrs,omitempty"`
 }
 
 // ApplicationSet holds the parameters for an application Set
@@ -416,6 +435,63 @@ type ScaleApplicationInfo struct {
 	Scale int `json:"num-units"`
 }
 
+// ScalePlan holds the changes needed to bring an application to the
+// number of units requested of the Autoscaler.Plan call.
+type ScalePlan struct {
+	// ApplicationTag holds the tag of the application to scale.
+	ApplicationTag string `json:"application-tag"`
+
+	// CurrentScale is the number of alive units the application had
+	// when the plan was made. The plan is only applied if the
+	// application still has that many.
+	CurrentScale int `json:"current-scale"`
+
+	// Scale is the number of units which should be running.
+	Scale int `json:"scale"`
+
+	// AddUnits is the number of units to add.
+	AddUnits int `json:"add-units,omitempty"`
+
+	// RemoveUnits holds the names of the units to remove.
+	RemoveUnits []string `json:"remove-units,omitempty"`
+}
+
+// ScalePlanResult holds a scale plan or an error.
+type ScalePlanResult struct {
+	Result *ScalePlan `json:"result,omitempty"`
+	Error  *Error     `json:"error,omitempty"`
+}
+
+// ScalePlanResults holds the results of an Autoscaler.Plan call.
+type ScalePlanResults struct {
+	Results []ScalePlanResult `json:"results"`
+}
+
+// ApplyScalePlansParams holds the parameters for the Autoscaler.Apply
+// call.
+type ApplyScalePlansParams struct {
+	// Token, if set, identifies this application of the plans. Plans
+	// applied with a token already used are not applied again.
+	Token string `json:"token,omitempty"`
+
+	// Plans holds the plans to apply, as returned by Autoscaler.Plan.
+	Plans []ScalePlan `json:"plans"`
+}
+
+// ApplyScalePlansResult holds the result of an Autoscaler.Apply call.
+type ApplyScalePlansResult struct {
+	// AddedUnits holds the names of the units added.
+	AddedUnits []string `json:"added-units,omitempty"`
+
+	// RemovedUnits holds the names of the units removed.
+	RemovedUnits []string `json:"removed-units,omitempty"`
+
+	// AlreadyApplied reports whether the plans had already been
+	// applied with the same token, in which case the units are those
+	// added and removed then.
+	AlreadyApplied bool `json:"already-applied,omitempty"`
+}
+
 // ApplicationInfo holds an application info.
 type ApplicationInfo struct {
 	Tag              string            `json:"tag"`
@@ -439,3 +515,30 @@ type ApplicationInfoResult struct {
 type ApplicationInfoResults struct {
 	Results []ApplicationInfoResult `json:"results"`
 }
+
+// ApplicationSubordinatePolicy holds the placement policy of a
+// subordinate application, which restricts the principal units it
+// attaches to.
+type ApplicationSubordinatePolicy struct {
+	// ApplicationTag identifies the subordinate application.
+	ApplicationTag string `json:"application-tag"`
+
+	// Principals, if not empty, holds the names of the only principal
+	// applications whose units the subordinate attaches to.
+	Principals []string `json:"principals,omitempty"`
+
+	// MachineSelector, if not empty, holds annotations that the machine
+	// hosting a principal unit must carry for the subordinate to
+	// attach to it.
+	MachineSelector map[string]string `json:"machine-selector,omitempty"`
+
+	// MaxPerMachine, if positive, limits the number of the
+	// subordinate's units on any one machine.
+	MaxPerMachine int `json:"max-per-machine,omitempty"`
+}
+
+// ApplicationSubordinatePolicies holds the parameters for setting the
+// placement policies of one or more subordinate applications.
+type ApplicationSubordinatePolicies struct {
+	Policies []ApplicationSubordinatePolicy `json:"policies"`
+}
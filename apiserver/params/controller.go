@@ -3,6 +3,12 @@
 
 package params
 
+import (
+	"time"
+
+	"github.com/juju/version"
+)
+
 // DestroyControllerArgs holds the arguments for destroying a controller.
 type DestroyControllerArgs struct {
 	// DestroyModels specifies whether or not the hosted models
@@ -98,6 +104,80 @@ type ControllerConfigSet struct {
 	Config map[string]interface{} `json:"config"`
 }
 
+// UpdateModelFeaturesArgs holds the parameters for
+// Controller.UpdateModelFeatures.
+type UpdateModelFeaturesArgs struct {
+	Args []UpdateModelFeaturesArg `json:"args"`
+}
+
+// UpdateModelFeaturesArg holds the feature flags to enable and disable
+// for a model. Flags that are not mentioned are left as they are.
+type UpdateModelFeaturesArg struct {
+	ModelTag string   `json:"model-tag"`
+	Enable   []string `json:"enable,omitempty"`
+	Disable  []string `json:"disable,omitempty"`
+}
+
+// ModelStatsResults holds the results of Controller.ModelStats.
+type ModelStatsResults struct {
+	Results []ModelStatsResult `json:"results"`
+}
+
+// ModelStatsResult holds the document statistics of a model, or an
+// error.
+type ModelStatsResult struct {
+	Result *ModelStats `json:"result,omitempty"`
+	Error  *Error      `json:"error,omitempty"`
+}
+
+// ModelStats holds the number and approximate size of the documents a
+// model has in each collection, as last collected by the controller.
+type ModelStats struct {
+	ModelTag    string            `json:"model-tag"`
+	Collections []CollectionStats `json:"collections"`
+	Updated     time.Time         `json:"updated"`
+}
+
+// CollectionStats holds the number and approximate total size in bytes
+// of the documents a model has in a collection.
+type CollectionStats struct {
+	Collection string `json:"collection"`
+	Count      int    `json:"count"`
+	Size       int64  `json:"size"`
+}
+
+// AuditRecordsFilter selects the audit records returned by
+// Controller.AuditRecords. Empty fields match all records.
+type AuditRecordsFilter struct {
+	UserTag  string    `json:"user-tag,omitempty"`
+	ModelTag string    `json:"model-tag,omitempty"`
+	From     time.Time `json:"from,omitempty"`
+	To       time.Time `json:"to,omitempty"`
+	Limit    int       `json:"limit,omitempty"`
+}
+
+// AuditRecordsResult holds the results of Controller.AuditRecords.
+type AuditRecordsResult struct {
+	Records []AuditRecord `json:"records"`
+}
+
+// AuditRecord records a single API call made to the controller. The
+// arguments of the call are serialised as JSON, with any secrets
+// redacted.
+type AuditRecord struct {
+	ModelTag     string        `json:"model-tag,omitempty"`
+	UserTag      string        `json:"user-tag,omitempty"`
+	ConnectionID uint64        `json:"connection-id"`
+	Facade       string        `json:"facade"`
+	Version      int           `json:"version"`
+	Method       string        `json:"method"`
+	Args         string        `json:"args,omitempty"`
+	ErrorCode    string        `json:"error-code,omitempty"`
+	Error        string        `json:"error,omitempty"`
+	Time         time.Time     `json:"time"`
+	Duration     time.Duration `json:"duration"`
+}
+
 // ControllerAction is an action that can be performed on a model.
 type ControllerAction string
 
@@ -113,3 +193,40 @@ type ControllerVersionResults struct {
 	Version   string `json:"version"`
 	GitCommit string `json:"git-commit"`
 }
+
+// UpgradePreCheckResults holds the results of Controller.UpgradePreChecks.
+type UpgradePreCheckResults struct {
+	Problems []UpgradePreCheckProblem `json:"problems,omitempty"`
+}
+
+// UpgradePreCheckProblem describes a problem found by a pre-upgrade
+// check. Blocking problems prevent the controller from upgrading.
+type UpgradePreCheckProblem struct {
+	Check    string `json:"check"`
+	Message  string `json:"message"`
+	Blocking bool   `json:"blocking"`
+}
+
+// UpgradeStragglersResults holds the results of
+// Controller.UpgradeStragglers.
+type UpgradeStragglersResults struct {
+	Results []UpgradeStragglersResult `json:"results"`
+}
+
+// UpgradeStragglersResult holds the machine agents in a model that
+// haven't completed their upgrade steps for the model's agent version.
+type UpgradeStragglersResult struct {
+	ModelTag      string             `json:"model-tag"`
+	TargetVersion version.Number     `json:"target-version"`
+	Stragglers    []UpgradeStraggler `json:"stragglers,omitempty"`
+	Error         *Error             `json:"error,omitempty"`
+}
+
+// UpgradeStraggler describes an agent that hasn't completed its upgrade
+// steps. Version is the version for which it last completed them, and
+// is zero if it never reported completing any.
+type UpgradeStraggler struct {
+	Tag       string         `json:"tag"`
+	Version   version.Number `json:"version"`
+	Completed *time.Time     `json:"completed,omitempty"`
+}
@@ -50,13 +50,76 @@ const (
 
 	// JujuApplicationOfferRule is a rule for connections to a Juju offer.
 	JujuApplicationOfferRule KnownServiceValue = "juju-application-offer"
+
+	// JujuExposedApplicationRule is a rule for connections to the
+	// ports of exposed applications.
+	JujuExposedApplicationRule KnownServiceValue = "juju-exposed-application"
 )
 
 // Validate returns an error if the service value is not valid.
 func (v KnownServiceValue) Validate() error {
 	switch v {
-	case SSHRule, JujuControllerRule, JujuApplicationOfferRule:
+	case SSHRule, JujuControllerRule, JujuApplicationOfferRule, JujuExposedApplicationRule:
 		return nil
 	}
 	return errors.NotValidf("known service %q", v)
 }
+
+// EgressRule is a rule allowing outgoing traffic from an
+// application's units through a firewall.
+type EgressRule struct {
+	// PortRange is the range of destination ports allowed.
+	PortRange PortRange `json:"port-range"`
+
+	// DestinationCIDRs is the list of subnets traffic may be sent to.
+	// No CIDRs means any destination.
+	DestinationCIDRs []string `json:"destination-cidrs,omitempty"`
+}
+
+// ExposeInfoResult holds the expose settings of an application, as
+// seen by the firewaller.
+type ExposeInfoResult struct {
+	// Exposed is true if the application is exposed.
+	Exposed bool `json:"exposed,omitempty"`
+
+	// ExposedEndpoints holds the per-endpoint expose settings of the
+	// application. The CIDRs of each endpoint include those of the
+	// subnets in the spaces it is exposed to.
+	ExposedEndpoints map[string]ExposedEndpoint `json:"exposed-endpoints,omitempty"`
+
+	Error *Error `json:"error,omitempty"`
+}
+
+// ExposeInfoResults holds the results of a GetExposeInfo call.
+type ExposeInfoResults struct {
+	Results []ExposeInfoResult `json:"results"`
+}
+
+// ApplicationEgressRules holds the egress rules of an application.
+type ApplicationEgressRules struct {
+	// ApplicationTag identifies the application.
+	ApplicationTag string `json:"application-tag"`
+
+	// Rules replaces the egress rules of the application. No rules
+	// removes any egress restriction.
+	Rules []EgressRule `json:"rules"`
+}
+
+// ApplicationEgressRulesArgs holds the parameters for setting
+// the egress rules of one or more applications.
+type ApplicationEgressRulesArgs struct {
+	Args []ApplicationEgressRules `json:"args"`
+}
+
+// ApplicationEgressRulesResult holds the egress rules of an
+// application, or an error.
+type ApplicationEgressRulesResult struct {
+	Rules []EgressRule `json:"rules,omitempty"`
+	Error *Error       `json:"error,omitempty"`
+}
+
+// ApplicationEgressRulesResults holds the results of retrieving the
+// egress rules of one or more applications.
+type ApplicationEgressRulesResults struct {
+	Results []ApplicationEgressRulesResult `json:"results"`
+}
@@ -150,6 +150,7 @@ type ModelCreateArgs struct {
 type Model struct {
 	Name     string `json:"name"`
 	UUID     string `json:"uuid"`
+	Alias    string `json:"alias,omitempty"`
 	Type     string `json:"type"`
 	OwnerTag string `json:"owner-tag"`
 }
@@ -717,6 +718,11 @@ type ProvisioningInfo struct {
 	ControllerConfig  map[string]interface{}    `json:"controller-config,omitempty"`
 	CloudInitUserData map[string]interface{}    `json:"cloudinit-userdata,omitempty"`
 	CharmLXDProfiles  []string                  `json:"charm-lxd-profiles,omitempty"`
+
+	// InstanceIdentityEnrollment is true if the machine's agent is to
+	// enroll with the signed identity document of its instance, and
+	// so must not be given a password.
+	InstanceIdentityEnrollment bool `json:"instance-identity-enrollment,omitempty"`
 }
 
 // ProvisioningInfoResult holds machine provisioning info or an error.
@@ -730,6 +736,91 @@ type ProvisioningInfoResults struct {
 	Results []ProvisioningInfoResult `json:"results"`
 }
 
+// MachineBootID holds a machine tag and the boot id of its kernel.
+type MachineBootID struct {
+	Tag    string `json:"tag"`
+	BootID string `json:"boot-id"`
+}
+
+// SetMachineBootIDs holds the parameters for recording the boot ids of
+// machines.
+type SetMachineBootIDs struct {
+	Args []MachineBootID `json:"args"`
+}
+
+// MachineHostname holds a machine tag and the hostname of the machine.
+type MachineHostname struct {
+	Tag      string `json:"tag"`
+	Hostname string `json:"hostname"`
+}
+
+// SetMachineHostnames holds the parameters for recording the hostnames
+// of machines.
+type SetMachineHostnames struct {
+	Args []MachineHostname `json:"args"`
+}
+
+// MachineShutdownProgress holds a machine tag and a description of the
+// shutdown task its agent is running.
+type MachineShutdownProgress struct {
+	Tag      string `json:"tag"`
+	Progress string `json:"progress"`
+}
+
+// SetMachineShutdownProgress holds the parameters for recording the
+// shutdown progress of dying machines.
+type SetMachineShutdownProgress struct {
+	Args []MachineShutdownProgress `json:"args"`
+}
+
+// MachineHostInfo holds a machine tag and the details of the machine's
+// operating system and hardware, as observed by its machine agent.
+type MachineHostInfo struct {
+	Tag           string                            `json:"tag"`
+	KernelVersion string                            `json:"kernel-version,omitempty"`
+	Series        string                            `json:"series,omitempty"`
+	Hardware      *instance.HardwareCharacteristics `json:"hardware,omitempty"`
+}
+
+// SetMachinesHostInfo holds the parameters for recording the operating
+// system and hardware details of machines.
+type SetMachinesHostInfo struct {
+	Args []MachineHostInfo `json:"args"`
+}
+
+// NetworkBootUserDataArg holds the rendered user-data to publish for a
+// machine that is provisioned by network boot.
+type NetworkBootUserDataArg struct {
+	Tag      string `json:"tag"`
+	UserData []byte `json:"user-data"`
+}
+
+// NetworkBootUserDataArgs holds the arguments for publishing the network
+// boot user-data of machines.
+type NetworkBootUserDataArgs struct {
+	Args []NetworkBootUserDataArg `json:"args"`
+}
+
+// NetworkBootSeed describes the cloud-init seed, served by the controller,
+// from which a machine that is provisioned by network boot can fetch its
+// user-data.
+type NetworkBootSeed struct {
+	URL     string    `json:"url"`
+	CACert  string    `json:"ca-cert"`
+	Expires time.Time `json:"expires"`
+}
+
+// NetworkBootSeedResult holds a network boot seed or an error.
+type NetworkBootSeedResult struct {
+	Error  *Error           `json:"error,omitempty"`
+	Result *NetworkBootSeed `json:"result,omitempty"`
+}
+
+// NetworkBootSeedResults holds multiple network boot seed results.
+type NetworkBootSeedResults struct {
+	Results []NetworkBootSeedResult `json:"results"`
+}
+
 // Metric holds a single metric.
 type Metric struct {
 	Key    string            `json:"key"`
@@ -793,6 +884,19 @@ type SingularClaims struct {
 	Claims []SingularClaim `json:"claims"`
 }
 
+// OperatorClaim represents a request by one of the pods running a CAAS
+// application's operator to be the pod that runs it.
+type OperatorClaim struct {
+	ApplicationTag string        `json:"application-tag"`
+	Holder         string        `json:"holder"`
+	Duration       time.Duration `json:"duration"`
+}
+
+// OperatorClaims holds any number of OperatorClaim~s.
+type OperatorClaims struct {
+	Claims []OperatorClaim `json:"claims"`
+}
+
 // GUIArchiveVersion holds information on a specific GUI archive version.
 type GUIArchiveVersion struct {
 	// Version holds the Juju GUI version number.
@@ -891,8 +995,56 @@ type GoalState struct {
 	Relations map[string]UnitsGoalState `json:"relations"`
 }
 
+// UnitEndpoints holds the arguments for requesting the addresses of the
+// units related to units on the given endpoints.
+type UnitEndpoints struct {
+	Entities []UnitEndpoint `json:"entities"`
+}
+
+// UnitEndpoint identifies an endpoint of a unit's application.
+type UnitEndpoint struct {
+	Tag      string `json:"tag"`
+	Endpoint string `json:"endpoint"`
+}
+
+// RelatedUnitAddressesResults holds the results of a
+// RelatedUnitAddresses API call.
+type RelatedUnitAddressesResults struct {
+	Results []RelatedUnitAddressesResult `json:"results"`
+}
+
+// RelatedUnitAddressesResult holds the addresses of the units related
+// to a unit on an endpoint, or an error.
+type RelatedUnitAddressesResult struct {
+	Units []RelatedUnitAddress `json:"units,omitempty"`
+	Error *Error               `json:"error,omitempty"`
+}
+
+// RelatedUnitAddress holds the addresses of a unit related to another
+// unit on one of its endpoints.
+type RelatedUnitAddress struct {
+	Unit           string `json:"unit"`
+	Application    string `json:"application"`
+	RelationId     int    `json:"relation-id"`
+	PublicAddress  string `json:"public-address,omitempty"`
+	PrivateAddress string `json:"private-address,omitempty"`
+}
+
 // ContainerTypeResult holds the result of a machine's ContainerType.
 type ContainerTypeResult struct {
 	Type  instance.ContainerType `json:"container-type"`
 	Error *Error                 `json:"error"`
 }
+
+// UpgradeStepsCompleted records that an agent has completed its upgrade
+// steps for a version.
+type UpgradeStepsCompleted struct {
+	Tag     string         `json:"tag"`
+	Version version.Number `json:"version"`
+}
+
+// UpgradeStepsCompletedArgs holds the arguments to
+// UpgradeSteps.SetUpgradeStepsCompleted.
+type UpgradeStepsCompletedArgs struct {
+	Args []UpgradeStepsCompleted `json:"args"`
+}
@@ -24,6 +24,24 @@ type KubernetesProvisioningInfo struct {
 	Filesystems    []KubernetesFilesystemParams `json:"filesystems,omitempty"`
 	Volumes        []KubernetesVolumeParams     `json:"volumes,omitempty"`
 	Devices        []KubernetesDeviceParams     `json:"devices,omitempty"`
+	Sidecar        *KubernetesSidecarInfo       `json:"sidecar,omitempty"`
+}
+
+// KubernetesSidecarInfo holds the info needed to run the charm of an
+// application in sidecar mode, alongside the workload in each pod.
+type KubernetesSidecarInfo struct {
+	ImagePath     string                   `json:"image-path"`
+	ImageUsername string                   `json:"image-username,omitempty"`
+	ImagePassword string                   `json:"image-password,omitempty"`
+	Mounts        []KubernetesSidecarMount `json:"mounts,omitempty"`
+}
+
+// KubernetesSidecarMount holds where an application filesystem is
+// mounted in the charm container of an application in sidecar mode.
+type KubernetesSidecarMount struct {
+	StorageName string `json:"storage-name"`
+	MountPoint  string `json:"mount-point"`
+	ReadOnly    bool   `json:"read-only,omitempty"`
 }
 
 // KubernetesProvisioningInfoResult holds unit provisioning info or an error.
@@ -70,3 +70,55 @@ type LogForwardingSetLastSentParam struct {
 	// RecordTimestamp identifies the record timestamp to set for the given ID.
 	RecordTimestamp int64 `json:"record-timestamp"`
 }
+
+// LogForwardingAddCountsParams holds the arguments for a call to the
+// AddCounts method of the LogForwarding facade.
+type LogForwardingAddCountsParams struct {
+	// Params holds the list of individual counts to add.
+	Params []LogForwardingAddCountsParam `json:"params"`
+}
+
+// LogForwardingAddCountsParam holds the numbers of log records sent
+// to, and dropped instead of being sent to, a log forwarding target
+// since the counts were last added.
+type LogForwardingAddCountsParam struct {
+	LogForwardingID
+
+	// Sent is the number of records sent.
+	Sent int64 `json:"sent"`
+
+	// Dropped is the number of records dropped.
+	Dropped int64 `json:"dropped"`
+}
+
+// LogForwardingIDs holds the arguments for a call to the GetCounts
+// method of the LogForwarding facade.
+type LogForwardingIDs struct {
+	// IDs holds the list of IDs for which individual counts should be
+	// returned (in the same order).
+	IDs []LogForwardingID `json:"ids"`
+}
+
+// LogForwardingGetCountsResults holds the results of a call to the
+// GetCounts method of the LogForwarding facade.
+type LogForwardingGetCountsResults struct {
+	// Results holds the list of results that correspond to the IDs
+	// sent in a GetCounts call.
+	Results []LogForwardingGetCountsResult `json:"results"`
+}
+
+// LogForwardingGetCountsResult holds a single result from a call to
+// the GetCounts method of the LogForwarding facade.
+type LogForwardingGetCountsResult struct {
+	// Sent is the total number of log records forwarded for a given
+	// model and sink.
+	Sent int64 `json:"sent"`
+
+	// Dropped is the total number of log records that were dropped
+	// instead of being forwarded for a given model and sink.
+	Dropped int64 `json:"dropped"`
+
+	// Error holds the error, if any, that resulted while handling the
+	// request for a specific ID.
+	Error *Error `json:"err,omitempty"`
+}
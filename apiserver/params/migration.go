@@ -74,6 +74,23 @@ type SerializedModel struct {
 	Resources []SerializedModelResource `json:"resources"`
 }
 
+// ImportValidationReport holds the result of checking whether a
+// serialized model could be imported into a controller.
+type ImportValidationReport struct {
+	ModelUUID string                  `json:"model-uuid"`
+	ModelName string                  `json:"model-name"`
+	Valid     bool                    `json:"valid"`
+	Issues    []ImportValidationIssue `json:"issues,omitempty"`
+}
+
+// ImportValidationIssue describes a problem that would prevent a model
+// from being imported.
+type ImportValidationIssue struct {
+	Kind    string `json:"kind"`
+	Entity  string `json:"entity,omitempty"`
+	Message string `json:"message"`
+}
+
 // SerializedModelTools holds the version and URI for a given tools
 // version.
 type SerializedModelTools struct {
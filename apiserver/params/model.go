@@ -139,6 +139,7 @@ type ModelInfo struct {
 	Name               string `json:"name"`
 	Type               string `json:"type"`
 	UUID               string `json:"uuid"`
+	Alias              string `json:"alias,omitempty"`
 	ControllerUUID     string `json:"controller-uuid"`
 	IsController       bool   `json:"is-controller"`
 	ProviderType       string `json:"provider-type,omitempty"`
@@ -184,6 +185,7 @@ type ModelInfo struct {
 type ModelSummary struct {
 	Name               string `json:"name"`
 	UUID               string `json:"uuid"`
+	Alias              string `json:"alias,omitempty"`
 	Type               string `json:"type"`
 	ControllerUUID     string `json:"controller-uuid"`
 	IsController       bool   `json:"is-controller"`
@@ -263,6 +265,70 @@ type ModelSummariesRequest struct {
 	All     bool   `json:"all,omitempty"`
 }
 
+// WatchModelSummariesArgs holds the arguments for a WatchModelSummaries
+// call.
+type WatchModelSummariesArgs struct {
+	// UserTag identifies the user whose model summaries are watched.
+	UserTag string `json:"user-tag"`
+
+	// All, if true, has the summaries of all models watched, as for
+	// ModelSummariesRequest.
+	All bool `json:"all,omitempty"`
+
+	// Peers holds the credentials with which the summaries of the
+	// user's models are fetched, on the user's behalf, from peer
+	// controllers. Each peer must be registered as an external
+	// controller.
+	Peers []PeerControllerAuth `json:"peers,omitempty"`
+}
+
+// PeerControllerAuth holds the credentials with which a controller
+// connects to a peer controller on behalf of a user.
+type PeerControllerAuth struct {
+	ControllerTag string `json:"controller-tag"`
+	AuthTag       string `json:"auth-tag,omitempty"`
+	Password      string `json:"password,omitempty"`
+
+	// Macaroons holds the JSON-encoded macaroons, discharged by the
+	// user, with which to log in to the peer controller.
+	Macaroons string `json:"macaroons,omitempty"`
+}
+
+// ModelSummaryWatcherId holds the id of a model summary watcher.
+type ModelSummaryWatcherId struct {
+	ModelSummaryWatcherId string `json:"watcher-id"`
+}
+
+// ModelSummaryChange holds a change to the summary of a model hosted by
+// the controller or one of its peers.
+type ModelSummaryChange struct {
+	ControllerTag string `json:"controller-tag"`
+	ModelTag      string `json:"model-tag"`
+
+	// Removed is true if the model has been removed, or is no longer
+	// visible to the user.
+	Removed bool `json:"removed,omitempty"`
+
+	// Summary holds the model's summary, unless it has been removed.
+	Summary *ModelSummary `json:"summary,omitempty"`
+}
+
+// PeerControllerError holds an error fetching model summaries from a
+// peer controller.
+type PeerControllerError struct {
+	ControllerTag string `json:"controller-tag"`
+	Error         *Error `json:"error"`
+}
+
+// ModelSummaryWatcherNextResults holds the changes to the model
+// summaries since the previous call to ModelSummaryWatcher.Next(), and
+// the errors fetching them from peer controllers, whose models are left
+// as they were last seen.
+type ModelSummaryWatcherNextResults struct {
+	Changes    []ModelSummaryChange  `json:"changes"`
+	PeerErrors []PeerControllerError `json:"peer-errors,omitempty"`
+}
+
 // ModelInfoResult holds the result of a ModelInfo call.
 type ModelInfoResult struct {
 	Result *ModelInfo `json:"result,omitempty"`
@@ -4,6 +4,8 @@
 package params
 
 import (
+	"time"
+
 	"github.com/juju/juju/core/network"
 )
 
@@ -167,6 +169,8 @@ type DeviceBridgeInfo struct {
 	HostDeviceName string `json:"host-device-name"`
 	BridgeName     string `json:"bridge-name"`
 	MACAddress     string `json:"mac-address"`
+	MTU            int    `json:"mtu,omitempty"`
+	OpenvSwitch    bool   `json:"openvswitch,omitempty"`
 }
 
 // ProviderInterfaceInfoResults holds the results of a
@@ -269,6 +273,10 @@ type EntityPortRange struct {
 	Protocol string `json:"protocol"`
 	FromPort int    `json:"from-port"`
 	ToPort   int    `json:"to-port"`
+
+	// Hook holds the name of the hook the ports are being changed
+	// in, which is recorded in the machine's ports history.
+	Hook string `json:"hook,omitempty"`
 }
 
 // EntitiesPortRanges holds the parameters for making an OpenPorts or
@@ -277,6 +285,45 @@ type EntitiesPortRanges struct {
 	Entities []EntityPortRange `json:"entities"`
 }
 
+// PortsHistoryArgs holds the parameters for requesting the ports
+// history of some machines.
+type PortsHistoryArgs struct {
+	Args []PortsHistoryArg `json:"args"`
+}
+
+// PortsHistoryArg holds a machine's tag and the number of its most
+// recent port changes to return; if Size is zero, all of the recorded
+// changes are returned.
+type PortsHistoryArg struct {
+	Tag  string `json:"tag"`
+	Size int    `json:"size,omitempty"`
+}
+
+// PortChange describes a port range being opened or closed on a
+// machine by a unit.
+type PortChange struct {
+	UnitTag  string    `json:"unit-tag"`
+	Hook     string    `json:"hook,omitempty"`
+	SubnetID string    `json:"subnet-id,omitempty"`
+	Protocol string    `json:"protocol"`
+	FromPort int       `json:"from-port"`
+	ToPort   int       `json:"to-port"`
+	Opened   bool      `json:"opened"`
+	Time     time.Time `json:"time"`
+}
+
+// PortsHistoryResult holds the port changes of a machine, newest
+// first, or an error.
+type PortsHistoryResult struct {
+	History []PortChange `json:"history,omitempty"`
+	Error   *Error       `json:"error,omitempty"`
+}
+
+// PortsHistoryResults holds the results of a PortsHistory call.
+type PortsHistoryResults struct {
+	Results []PortsHistoryResult `json:"results"`
+}
+
 // Address represents the location of a machine, including metadata
 // about what kind of location the address describes. It's used in
 // the API requests/responses. See also network.Address, from/to
@@ -425,6 +472,22 @@ type SetMachineNetworkConfig struct {
 	Config []NetworkConfig `json:"config"`
 }
 
+// NetworkConfigValidation describes a problem with the network config
+// of a machine's device which the controller cannot reconcile with the
+// model's subnets.
+type NetworkConfigValidation struct {
+	InterfaceName string `json:"interface-name"`
+	Address       string `json:"address"`
+	CIDR          string `json:"cidr"`
+	Message       string `json:"message"`
+}
+
+// SetMachineNetworkConfigResult holds the problems found with a machine's
+// network config once it has been set.
+type SetMachineNetworkConfigResult struct {
+	Validations []NetworkConfigValidation `json:"validations,omitempty"`
+}
+
 // MachineAddressesResult holds a list of machine addresses or an
 // error.
 type MachineAddressesResult struct {
@@ -574,6 +637,31 @@ type SpaceResults struct {
 	Results []SpaceResult `json:"results"`
 }
 
+// NetworkDrift describes how the subnets recorded for a model differ
+// from those reported by its provider.
+type NetworkDrift struct {
+	// Added holds the subnets reported by the provider that are not
+	// recorded for the model.
+	Added []Subnet `json:"added,omitempty"`
+
+	// Removed holds the subnets recorded for the model that the
+	// provider no longer reports.
+	Removed []Subnet `json:"removed,omitempty"`
+
+	// Changed holds the attributes of subnets known to both whose
+	// values differ.
+	Changed []SubnetDrift `json:"changed,omitempty"`
+}
+
+// SubnetDrift describes a subnet attribute whose recorded value differs
+// from the one reported by the provider.
+type SubnetDrift struct {
+	CIDR      string `json:"cidr"`
+	Attribute string `json:"attribute"`
+	Model     string `json:"model"`
+	Provider  string `json:"provider"`
+}
+
 // ListSubnetsResults holds the result of a ListSubnets API call.
 type ListSubnetsResults struct {
 	Results []Subnet `json:"results"`
@@ -665,6 +753,62 @@ type CreateSpaceParams struct {
 	ProviderId string   `json:"provider-id,omitempty"`
 }
 
+// RenameSpacesParams holds the arguments of the RenameSpace API call.
+type RenameSpacesParams struct {
+	Changes []RenameSpaceParams `json:"changes"`
+}
+
+// RenameSpaceParams holds the tag of an existing space and the tag
+// of the space it should be renamed to.
+type RenameSpaceParams struct {
+	FromSpaceTag string `json:"from-space-tag"`
+	ToSpaceTag   string `json:"to-space-tag"`
+}
+
+// MoveSubnetsParams holds the arguments of the MoveSubnets API call.
+type MoveSubnetsParams struct {
+	Args []MoveSubnetsParam `json:"args"`
+}
+
+// MoveSubnetsParam holds the tag of the space to move subnets into,
+// and the CIDRs of the subnets to move.
+type MoveSubnetsParam struct {
+	SpaceTag string   `json:"space-tag"`
+	CIDRs    []string `json:"cidrs"`
+}
+
+// MoveSubnetsResults holds the results of the MoveSubnets API call.
+type MoveSubnetsResults struct {
+	Results []MoveSubnetsResult `json:"results"`
+}
+
+// MoveSubnetsResult holds the result of moving subnets into one space.
+// When the move was refused because it would break network requirements,
+// Violations describes each of them.
+type MoveSubnetsResult struct {
+	Violations []MoveSubnetsViolation `json:"violations,omitempty"`
+	Error      *Error                 `json:"error,omitempty"`
+}
+
+// MoveSubnetsViolation describes a machine or application whose network
+// requirements would no longer be satisfied by a subnet move.
+type MoveSubnetsViolation struct {
+	Tag    string `json:"tag"`
+	Reason string `json:"reason"`
+}
+
+// RemoveSpaceParams holds the arguments of the RemoveSpace API call.
+type RemoveSpaceParams struct {
+	Args []RemoveSpaceParam `json:"args"`
+}
+
+// RemoveSpaceParam holds the tag of a space to remove. If Force is true,
+// anything still using the space is rewritten to use the default space.
+type RemoveSpaceParam struct {
+	SpaceTag string `json:"space-tag"`
+	Force    bool   `json:"force,omitempty"`
+}
+
 // ListSpacesResults holds the list of all available spaces.
 type ListSpacesResults struct {
 	Results []Space `json:"results"`
@@ -678,6 +822,51 @@ type Space struct {
 	Error   *Error   `json:"error,omitempty"`
 }
 
+// NetworkTopology holds the complete network topology of a model: its
+// spaces and subnets, the network devices of its machines, and the ports
+// opened on each subnet.
+type NetworkTopology struct {
+	Spaces      []Space                  `json:"spaces"`
+	Machines    []MachineNetworkTopology `json:"machines"`
+	OpenedPorts []SubnetOpenedPorts      `json:"opened-ports"`
+}
+
+// MachineNetworkTopology holds the network devices of a machine.
+type MachineNetworkTopology struct {
+	MachineTag string                  `json:"machine-tag"`
+	Devices    []NetworkDeviceTopology `json:"devices"`
+}
+
+// NetworkDeviceTopology describes a link-layer device of a machine and
+// the addresses assigned to it.
+type NetworkDeviceTopology struct {
+	Name       string                   `json:"name"`
+	Type       string                   `json:"type"`
+	MACAddress string                   `json:"mac-address,omitempty"`
+	ParentName string                   `json:"parent-name,omitempty"`
+	Addresses  []NetworkAddressTopology `json:"addresses,omitempty"`
+}
+
+// NetworkAddressTopology describes an address of a network device.
+type NetworkAddressTopology struct {
+	Value      string `json:"value"`
+	SubnetCIDR string `json:"subnet-cidr,omitempty"`
+}
+
+// SubnetOpenedPorts holds the port ranges opened on a subnet. An empty
+// SubnetCIDR holds those opened on all the subnets of their machines.
+type SubnetOpenedPorts struct {
+	SubnetCIDR string            `json:"subnet-cidr,omitempty"`
+	Ports      []OpenedPortRange `json:"ports"`
+}
+
+// OpenedPortRange describes a port range opened by a unit on a machine.
+type OpenedPortRange struct {
+	MachineTag string    `json:"machine-tag"`
+	UnitTag    string    `json:"unit-tag"`
+	PortRange  PortRange `json:"port-range"`
+}
+
 // ProviderSpace holds the information about a single space and its associated subnets.
 type ProviderSpace struct {
 	Name       string   `json:"name"`
@@ -776,6 +965,20 @@ type NetworkInfoParams struct {
 	Bindings   []string `json:"bindings"`
 }
 
+// UnitEgressAddresses holds the effective egress addresses of a unit,
+// keyed on endpoint binding name. Addresses keyed on the empty binding
+// apply to all endpoints without addresses of their own.
+type UnitEgressAddresses struct {
+	Tag      string              `json:"tag"`
+	Bindings map[string][]string `json:"bindings"`
+}
+
+// SetUnitsEgressAddresses holds the egress addresses to record for a
+// number of units.
+type SetUnitsEgressAddresses struct {
+	Args []UnitEgressAddresses `json:"args"`
+}
+
 // FanConfigEntry holds configuration for a single fan.
 type FanConfigEntry struct {
 	Underlay string `json:"underlay"`
@@ -786,3 +989,9 @@ type FanConfigEntry struct {
 type FanConfigResult struct {
 	Fans []FanConfigEntry `json:"fans"`
 }
+
+// SetFanConfigParams holds the fans to configure for a model,
+// replacing any configured previously.
+type SetFanConfigParams struct {
+	Fans []FanConfigEntry `json:"fans"`
+}
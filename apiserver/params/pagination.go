@@ -0,0 +1,25 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+// PageParams requests a single page of the results of a list-style
+// API call, so that very long lists can be fetched in several calls.
+// The zero value requests all the results at once.
+type PageParams struct {
+	// Cursor holds the NextCursor of the previous page, or is empty
+	// to request the first page.
+	Cursor string `json:"cursor,omitempty"`
+
+	// Limit holds the maximum number of results in the page. If it is
+	// zero and Cursor is set, the server's default page size is used.
+	Limit int `json:"limit,omitempty"`
+}
+
+// PageInfo describes the page of results returned by a list-style API
+// call.
+type PageInfo struct {
+	// NextCursor holds the cursor to pass in PageParams to fetch the
+	// next page. It is empty if this is the last page.
+	NextCursor string `json:"next-cursor,omitempty"`
+}
@@ -222,6 +222,11 @@ type AddMachineParams struct {
 	Nonce                   string                           `json:"nonce"`
 	HardwareCharacteristics instance.HardwareCharacteristics `json:"hardware-characteristics"`
 	Addrs                   []Address                        `json:"addresses"`
+
+	// If IdempotencyKey is non-empty, a retry of the call with the
+	// same key returns the machine already added instead of adding
+	// another.
+	IdempotencyKey string `json:"idempotency-key,omitempty"`
 }
 
 // AddMachines holds the parameters for making the AddMachines call.
@@ -279,6 +284,14 @@ type UpdateSeriesArgs struct {
 	Args []UpdateSeriesArg `json:"args"`
 }
 
+// MachinesAnnotations holds the annotations to set on each of a set of
+// machines, in a single MachineManager SetMachinesAnnotations call. Only
+// known by MachineManager facade version 7 and greater.
+type MachinesAnnotations struct {
+	Machines    []Entity          `json:"machines"`
+	Annotations map[string]string `json:"annotations"`
+}
+
 // LXDProfileUpgrade holds the parameters for an application
 // lxd profile machines
 type LXDProfileUpgrade struct {
@@ -307,11 +320,33 @@ type ConfigResult struct {
 
 // OperatorProvisioningInfo holds info need to provision an operator.
 type OperatorProvisioningInfo struct {
-	ImagePath    string                     `json:"image-path"`
-	Version      version.Number             `json:"version"`
-	APIAddresses []string                   `json:"api-addresses"`
-	Tags         map[string]string          `json:"tags,omitempty"`
-	CharmStorage KubernetesFilesystemParams `json:"charm-storage"`
+	ProvisioningMode string                     `json:"provisioning-mode,omitempty"`
+	ImagePath        string                     `json:"image-path"`
+	ImageUsername    string                     `json:"image-username,omitempty"`
+	ImagePassword    string                     `json:"image-password,omitempty"`
+	Version          version.Number             `json:"version"`
+	APIAddresses     []string                   `json:"api-addresses"`
+	Tags             map[string]string          `json:"tags,omitempty"`
+	CharmStorage     KubernetesFilesystemParams `json:"charm-storage"`
+	CPU              string                     `json:"cpu,omitempty"`
+	Memory           string                     `json:"memory,omitempty"`
+	NodeSelector     []string                   `json:"node-selector,omitempty"`
+	NodeAffinity     []string                   `json:"node-affinity,omitempty"`
+	Tolerations      []string                   `json:"tolerations,omitempty"`
+	Replicas         int                        `json:"replicas,omitempty"`
+}
+
+// OperatorProvisioningInfoResult holds the info needed to provision
+// the operator of an application, or an error.
+type OperatorProvisioningInfoResult struct {
+	Result *OperatorProvisioningInfo `json:"result,omitempty"`
+	Error  *Error                    `json:"error,omitempty"`
+}
+
+// OperatorProvisioningInfoResults holds the results of the
+// OperatorProvisioningInfo call.
+type OperatorProvisioningInfoResults struct {
+	Results []OperatorProvisioningInfoResult `json:"results"`
 }
 
 // PublicAddress holds parameters for the PublicAddress call.
@@ -367,6 +402,7 @@ type AddApplicationUnits struct {
 	Placement       []*instance.Placement `json:"placement"`
 	Policy          string                `json:"policy,omitempty"`
 	AttachStorage   []string              `json:"attach-storage,omitempty"`
+	IdempotencyKey  string                `json:"idempotency-key,omitempty"`
 }
 
 // AddApplicationUnitsV5 holds parameters for the AddUnits call.
@@ -457,6 +493,17 @@ type LoginRequest struct {
 	Macaroons   []macaroon.Slice `json:"macaroons"`
 	CLIArgs     string           `json:"cli-args,omitempty"`
 	UserData    string           `json:"user-data"`
+
+	// APIKey, if set, is a token issued by the APIKeyManager facade.
+	// It is used in place of AuthTag and Credentials to log in with
+	// access limited to a single model.
+	APIKey string `json:"api-key,omitempty"`
+
+	// InstanceIdentity, if set, is the signed identity document of
+	// the cloud instance a machine agent is running on. A machine that
+	// was provisioned without a password uses it in place of
+	// Credentials to log in for the first time.
+	InstanceIdentity string `json:"instance-identity,omitempty"`
 }
 
 // LoginRequestCompat holds credentials for identifying an entity to the Login v1
@@ -665,6 +712,14 @@ type FacadeVersions struct {
 	Versions []int  `json:"versions"`
 }
 
+// DeprecatedFacade describes a facade version that is deprecated, and
+// will be removed in the next major release.
+type DeprecatedFacade struct {
+	Name    string `json:"name"`
+	Version int    `json:"version"`
+	Message string `json:"message"`
+}
+
 // RedirectInfoResult holds the result of a RedirectInfo call.
 type RedirectInfoResult struct {
 	// Servers holds an entry for each server that holds the
@@ -743,6 +798,10 @@ type LoginResult struct {
 	// ServerVersion is the string representation of the server version
 	// if the server supports it.
 	ServerVersion string `json:"server-version,omitempty"`
+
+	// DeprecatedFacades describes the facade versions available to
+	// the authenticated client that are deprecated.
+	DeprecatedFacades []DeprecatedFacade `json:"deprecated-facades,omitempty"`
 }
 
 // ControllersServersSpec contains arguments for
@@ -853,6 +912,21 @@ type RebootActionResult struct {
 	Error  *Error       `json:"error,omitempty"`
 }
 
+// RebootStatusResults holds the results of a RebootStatus call.
+type RebootStatusResults struct {
+	Results []RebootStatusResult `json:"results"`
+}
+
+// RebootStatusResult holds the progress of the most recent reboot of a
+// machine requested with RequestReboot, or an error. State is one of
+// "none", "requested", "rebooting" and "completed".
+type RebootStatusResult struct {
+	State     string     `json:"state,omitempty"`
+	Requested *time.Time `json:"requested,omitempty"`
+	Completed *time.Time `json:"completed,omitempty"`
+	Error     *Error     `json:"error,omitempty"`
+}
+
 // LogRecord is used to transmit log messages to the logsink API
 // endpoint.  Single character field names are used for serialisation
 // to keep the size down. These messages are going to be sent a lot.
@@ -1180,9 +1254,10 @@ type GenerationResults struct {
 // CharmProfilingInfoResult contains the result based on ProfileInfoArg values
 // to update profiles on a machine.
 type CharmProfilingInfoResult struct {
-	InstanceId      instance.Id         `json:"instance-id"`
-	ModelName       string              `json:"model-name"`
-	ProfileChanges  []ProfileInfoResult `json:"profile-changes"`
-	CurrentProfiles []string            `json:"current-profiles"`
-	Error           *Error              `json:"error"`
+	InstanceId       instance.Id         `json:"instance-id"`
+	ModelName        string              `json:"model-name"`
+	ProfileChanges   []ProfileInfoResult `json:"profile-changes"`
+	CurrentProfiles  []string            `json:"current-profiles"`
+	RollbackProfiles []string            `json:"rollback-profiles,omitempty"`
+	Error            *Error              `json:"error"`
 }
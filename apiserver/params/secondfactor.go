@@ -0,0 +1,37 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+// SecondFactorChallenge holds a challenge to be signed by the user's
+// second-factor authenticator, in the manner of a WebAuthn assertion.
+type SecondFactorChallenge struct {
+	// Challenge is the base64url-encoded challenge which must appear
+	// in the client data of the assertion.
+	Challenge string `json:"challenge"`
+
+	// RelyingPartyID is the WebAuthn relying party ID against which
+	// the assertion must be made.
+	RelyingPartyID string `json:"rp-id"`
+}
+
+// SecondFactorAssertion holds a WebAuthn assertion made by the user's
+// second-factor authenticator in response to a SecondFactorChallenge.
+type SecondFactorAssertion struct {
+	ClientDataJSON    []byte `json:"client-data-json"`
+	AuthenticatorData []byte `json:"authenticator-data"`
+	Signature         []byte `json:"signature"`
+}
+
+// SecondFactorKey holds the public key of a user's second-factor
+// authenticator.
+type SecondFactorKey struct {
+	// PublicKey is the PKIX, DER-encoded ECDSA P-256 public key of
+	// the authenticator.
+	PublicKey []byte `json:"public-key"`
+
+	// EnrollmentToken is the one-time token, issued by a controller
+	// administrator, that is required to register the user's first
+	// authenticator.
+	EnrollmentToken string `json:"enrollment-token,omitempty"`
+}
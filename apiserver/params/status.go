@@ -16,6 +16,11 @@ import (
 // StatusParams holds parameters for the Status call.
 type StatusParams struct {
 	Patterns []string `json:"patterns"`
+
+	// Page requests a single page of the status. Applications,
+	// machines, offers and remote applications are paged together;
+	// the model status and branches are included in every page.
+	Page PageParams `json:"page"`
 }
 
 // TODO(ericsnow) Add FullStatusResult.
@@ -30,6 +35,10 @@ type FullStatus struct {
 	Relations           []RelationStatus                   `json:"relations"`
 	ControllerTimestamp *time.Time                         `json:"controller-timestamp"`
 	Branches            map[string]BranchStatus            `json:"branches"`
+
+	// Page describes the page of the status returned, if a page was
+	// requested.
+	Page *PageInfo `json:"page,omitempty"`
 }
 
 // IsEmpty checks all collections on FullStatus to determine if the status is empty.
@@ -94,6 +103,10 @@ type MachineStatus struct {
 	// DisplayName is a human-readable name for this machine.
 	DisplayName string `json:"display-name"`
 
+	// Hostname holds the hostname of the machine, as reported by its
+	// machine agent.
+	Hostname string `json:"hostname,omitempty"`
+
 	// Series holds the name of the operating system release installed on
 	// this machine.
 	Series string `json:"series"`
@@ -150,6 +163,12 @@ type ApplicationStatus struct {
 	CharmProfile     string                 `json:"charm-profile"`
 	EndpointBindings map[string]string      `json:"endpoint-bindings"`
 
+	// WorkloadVersions holds the number of units running each
+	// workload version, and WorkloadVersionSummary describes them,
+	// when the units of the application report different versions.
+	WorkloadVersions       map[string]int `json:"workload-versions,omitempty"`
+	WorkloadVersionSummary string         `json:"workload-version-summary,omitempty"`
+
 	// The following are for CAAS models.
 	Scale         int    `json:"int,omitempty"`
 	ProviderId    string `json:"provider-id,omitempty"`
@@ -193,6 +212,10 @@ type UnitStatus struct {
 	WorkloadStatus  DetailedStatus `json:"workload-status"`
 	WorkloadVersion string         `json:"workload-version"`
 
+	// WorkloadVersionHistory holds the workload versions most recently
+	// reported by the unit, newest first.
+	WorkloadVersionHistory []WorkloadVersionRecord `json:"workload-version-history,omitempty"`
+
 	Machine       string                `json:"machine"`
 	OpenedPorts   []string              `json:"opened-ports"`
 	PublicAddress string                `json:"public-address"`
@@ -205,6 +228,13 @@ type UnitStatus struct {
 	Address    string `json:"address,omitempty"`
 }
 
+// WorkloadVersionRecord holds a workload version reported by a unit, and
+// when it was last reported.
+type WorkloadVersionRecord struct {
+	Version string     `json:"version"`
+	Since   *time.Time `json:"since,omitempty"`
+}
+
 // RelationStatus holds status info about a relation.
 type RelationStatus struct {
 	Id        int              `json:"id"`
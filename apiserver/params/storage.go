@@ -538,8 +538,9 @@ type StorageDetails struct {
 
 // StorageFilter holds filter terms for listing storage details.
 type StorageFilter struct {
-	// We don't currently implement any filters. This exists to get the
-	// API structure right, and so we can add filters later as necessary.
+	// Page requests a single page of the storage details, ordered by
+	// storage tag.
+	Page PageParams `json:"page"`
 }
 
 // StorageFilters holds a set of storage filters.
@@ -562,6 +563,7 @@ type StorageDetailsResults struct {
 // StorageDetailsListResult holds a collection of storage details.
 type StorageDetailsListResult struct {
 	Result []StorageDetails `json:"result,omitempty"`
+	Page   *PageInfo        `json:"page,omitempty"`
 	Error  *Error           `json:"error,omitempty"`
 }
 
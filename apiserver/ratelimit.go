@@ -0,0 +1,135 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/ratelimit"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/common"
+)
+
+// bucketSweepInterval is how often the buckets of idle entities are
+// discarded, so that entities that have stopped making requests don't
+// hold on to a bucket for the lifetime of the server.
+const bucketSweepInterval = 5 * time.Minute
+
+// entityRateLimiter limits the rate of API requests made by each
+// authenticated entity, using a token bucket per entity.
+type entityRateLimiter struct {
+	clock clock.Clock
+
+	mu        sync.Mutex
+	rate      float64
+	burst     int
+	buckets   map[string]*entityBucket
+	lastSweep time.Time
+}
+
+// entityBucket holds the token bucket of an entity, and when the
+// entity last made a request.
+type entityBucket struct {
+	*ratelimit.Bucket
+	lastUsed time.Time
+}
+
+func newEntityRateLimiter(clock clock.Clock, rate float64, burst int) *entityRateLimiter {
+	return &entityRateLimiter{
+		clock:     clock,
+		rate:      rate,
+		burst:     burst,
+		buckets:   make(map[string]*entityBucket),
+		lastSweep: clock.Now(),
+	}
+}
+
+// setLimits changes the rate and burst of requests allowed for each
+// entity, and reports whether they changed. Entities start again with
+// full buckets when the limits change.
+func (l *entityRateLimiter) setLimits(rate float64, burst int) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if rate == l.rate && burst == l.burst {
+		return false
+	}
+	l.rate = rate
+	l.burst = burst
+	l.buckets = make(map[string]*entityBucket)
+	return true
+}
+
+// check takes a token from the bucket of the given entity, returning a
+// *common.RateLimitExceededError if there are none left.
+func (l *entityRateLimiter) check(tag names.Tag) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.rate <= 0 {
+		return nil
+	}
+	l.maybeSweep()
+	bucket, ok := l.buckets[tag.String()]
+	if !ok {
+		bucket = &entityBucket{
+			Bucket: ratelimit.NewBucketWithRateAndClock(l.rate, int64(l.burst), ratelimitClock{l.clock}),
+		}
+		l.buckets[tag.String()] = bucket
+	}
+	bucket.lastUsed = l.clock.Now()
+	if bucket.TakeAvailable(1) == 1 {
+		return nil
+	}
+	// The bucket is empty, so the next token is at most one
+	// refill interval away.
+	return &common.RateLimitExceededError{
+		Entity:     tag,
+		RetryAfter: time.Duration(float64(time.Second) / l.rate),
+	}
+}
+
+// maybeSweep discards the buckets of entities that have been idle for
+// long enough for their buckets to refill, if it has not done so for
+// bucketSweepInterval. A full bucket holds no history, so discarding it
+// changes nothing for its entity. It must be called with l.mu held.
+func (l *entityRateLimiter) maybeSweep() {
+	now := l.clock.Now()
+	if now.Sub(l.lastSweep) < bucketSweepInterval {
+		return
+	}
+	l.lastSweep = now
+	refill := time.Duration(float64(l.burst) / l.rate * float64(time.Second))
+	for key, bucket := range l.buckets {
+		if now.Sub(bucket.lastUsed) >= refill {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// rateLimitedMethods returns a restrictRoot check function that limits
+// the rate of requests made by the given entity. Pings are never
+// limited, so that limited connections are not dropped; nor are calls
+// on watchers, whose Next calls block until there are changes, and
+// which would otherwise miss them.
+func rateLimitedMethods(shared *sharedServerContext, tag names.Tag) func(string, string) error {
+	return func(facadeName, _ string) error {
+		if facadeName == "Pinger" || strings.HasSuffix(facadeName, "Watcher") {
+			return nil
+		}
+		return shared.checkRateLimit(tag)
+	}
+}
+
+// ratelimitClock adapts clock.Clock to ratelimit.Clock.
+type ratelimitClock struct {
+	clock.Clock
+}
+
+// Sleep is defined by the ratelimit.Clock interface.
+func (c ratelimitClock) Sleep(d time.Duration) {
+	<-c.Clock.After(d)
+}
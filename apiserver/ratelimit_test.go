@@ -0,0 +1,105 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"time"
+
+	"github.com/juju/clock/testclock"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/testing"
+)
+
+type entityRateLimiterSuite struct {
+	testing.BaseSuite
+
+	clock *testclock.Clock
+}
+
+var _ = gc.Suite(&entityRateLimiterSuite{})
+
+func (s *entityRateLimiterSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.clock = testclock.NewClock(time.Now())
+}
+
+func (s *entityRateLimiterSuite) TestDisabled(c *gc.C) {
+	limiter := newEntityRateLimiter(s.clock, 0, 1)
+	tag := names.NewUserTag("bob")
+	for i := 0; i < 10; i++ {
+		c.Assert(limiter.check(tag), jc.ErrorIsNil)
+	}
+}
+
+func (s *entityRateLimiterSuite) TestLimitsEachEntity(c *gc.C) {
+	limiter := newEntityRateLimiter(s.clock, 2, 2)
+	bob := names.NewUserTag("bob")
+	unit := names.NewUnitTag("mysql/0")
+
+	c.Assert(limiter.check(bob), jc.ErrorIsNil)
+	c.Assert(limiter.check(bob), jc.ErrorIsNil)
+	err := limiter.check(bob)
+	c.Assert(err, gc.FitsTypeOf, &common.RateLimitExceededError{})
+	c.Check(err.(*common.RateLimitExceededError).Entity, gc.Equals, bob)
+	c.Check(err.(*common.RateLimitExceededError).RetryAfter, gc.Equals, 500*time.Millisecond)
+
+	// Other entities have their own buckets.
+	c.Assert(limiter.check(unit), jc.ErrorIsNil)
+
+	s.clock.Advance(500 * time.Millisecond)
+	c.Assert(limiter.check(bob), jc.ErrorIsNil)
+	c.Assert(limiter.check(bob), jc.Satisfies, common.IsRateLimitExceededError)
+}
+
+func (s *entityRateLimiterSuite) TestSetLimits(c *gc.C) {
+	limiter := newEntityRateLimiter(s.clock, 1, 1)
+	tag := names.NewUserTag("bob")
+	c.Assert(limiter.check(tag), jc.ErrorIsNil)
+	c.Assert(limiter.check(tag), jc.Satisfies, common.IsRateLimitExceededError)
+
+	c.Check(limiter.setLimits(1, 1), jc.IsFalse)
+	c.Assert(limiter.check(tag), jc.Satisfies, common.IsRateLimitExceededError)
+
+	c.Check(limiter.setLimits(1, 3), jc.IsTrue)
+	for i := 0; i < 3; i++ {
+		c.Assert(limiter.check(tag), jc.ErrorIsNil)
+	}
+	c.Assert(limiter.check(tag), jc.Satisfies, common.IsRateLimitExceededError)
+
+	c.Check(limiter.setLimits(0, 3), jc.IsTrue)
+	c.Assert(limiter.check(tag), jc.ErrorIsNil)
+}
+
+func (s *entityRateLimiterSuite) TestRateLimitedMethodsAllowsPings(c *gc.C) {
+	shared := &sharedServerContext{
+		rateLimiter: newEntityRateLimiter(s.clock, 1, 1),
+	}
+	check := rateLimitedMethods(shared, names.NewUserTag("bob"))
+	c.Assert(check("Client", "FullStatus"), jc.ErrorIsNil)
+	c.Assert(check("Client", "FullStatus"), jc.Satisfies, common.IsRateLimitExceededError)
+	c.Assert(check("Pinger", "Ping"), jc.ErrorIsNil)
+	c.Assert(check("AllWatcher", "Next"), jc.ErrorIsNil)
+	c.Assert(check("NotifyWatcher", "Next"), jc.ErrorIsNil)
+}
+
+func (s *entityRateLimiterSuite) TestSweepsRefilledBuckets(c *gc.C) {
+	limiter := newEntityRateLimiter(s.clock, 1, 2)
+	bob := names.NewUserTag("bob")
+	alice := names.NewUserTag("alice")
+	c.Assert(limiter.check(bob), jc.ErrorIsNil)
+	c.Assert(limiter.check(alice), jc.ErrorIsNil)
+	c.Assert(limiter.buckets, gc.HasLen, 2)
+
+	// Once the sweep interval has passed, both entities have been idle
+	// long enough for their buckets to refill, and only the bucket of
+	// the entity making a request is kept.
+	s.clock.Advance(bucketSweepInterval)
+	c.Assert(limiter.check(bob), jc.ErrorIsNil)
+	c.Assert(limiter.buckets, gc.HasLen, 1)
+	c.Assert(limiter.buckets, jc.HasKey, bob.String())
+}
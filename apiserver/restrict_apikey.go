@@ -0,0 +1,31 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"fmt"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+)
+
+// apiKeyDeniedFacadeNames are the root names that can't be accessed
+// using an API key login. A key mustn't be able to issue keys which
+// outlive it or grant more access than it has.
+var apiKeyDeniedFacadeNames = set.NewStrings(
+	"APIKeyManager",
+)
+
+func apiKeyFacadesOnly(facadeName, _ string) error {
+	if !IsAPIKeyFacade(facadeName) {
+		return errors.NewNotSupported(nil, fmt.Sprintf("facade %q not supported for API key connections", facadeName))
+	}
+	return nil
+}
+
+// IsAPIKeyFacade reports whether the given facade name can be accessed
+// using an API key login.
+func IsAPIKeyFacade(facadeName string) bool {
+	return !apiKeyDeniedFacadeNames.Contains(facadeName)
+}
@@ -0,0 +1,34 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/testing"
+)
+
+type restrictAPIKeySuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&restrictAPIKeySuite{})
+
+func (s *restrictAPIKeySuite) TestAllowed(c *gc.C) {
+	root := apiserver.TestingAPIKeyRoot()
+	caller, err := root.FindMethod("Client", 1, "FullStatus")
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(caller, gc.NotNil)
+}
+
+func (s *restrictAPIKeySuite) TestNotAllowed(c *gc.C) {
+	root := apiserver.TestingAPIKeyRoot()
+	caller, err := root.FindMethod("APIKeyManager", 1, "IssueAPIKeys")
+	c.Assert(err, gc.ErrorMatches, `facade "APIKeyManager" not supported for API key connections`)
+	c.Assert(errors.IsNotSupported(err), jc.IsTrue)
+	c.Assert(caller, gc.IsNil)
+}
@@ -15,6 +15,7 @@ import (
 var commonModelFacadeNames = set.NewStrings(
 	"Action",
 	"ActionPruner",
+	"ActionWebhooks",
 	"AllWatcher",
 	"Agent",
 	"Annotations",
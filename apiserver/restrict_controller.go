@@ -15,12 +15,15 @@ import (
 // independently of individual models.
 var controllerFacadeNames = set.NewStrings(
 	"AllModelWatcher",
+	"APIKeyManager",
 	"ApplicationOffers",
 	"Cloud",
 	"Controller",
 	"CrossController",
+	"ImportValidator",
 	"MigrationTarget",
 	"ModelManager",
+	"ModelSummaryWatcher",
 	"UserManager",
 )
 
@@ -41,6 +44,11 @@ var commonFacadeNames = set.NewStrings(
 
 	// ModelConfig may be used for letting controller commands access provider, for example, juju add-k8s.
 	"ModelConfig",
+
+	// SecondFactor records second-factor assertions against the
+	// connection on which they are made, so must be available on
+	// whichever connection the destructive operation is made.
+	"SecondFactor",
 )
 
 func controllerFacadesOnly(facadeName, _ string) error {
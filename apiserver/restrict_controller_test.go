@@ -28,12 +28,14 @@ func (s *restrictControllerSuite) SetUpSuite(c *gc.C) {
 func (s *restrictControllerSuite) TestAllowed(c *gc.C) {
 	s.assertMethod(c, "AllModelWatcher", 2, "Next")
 	s.assertMethod(c, "AllModelWatcher", 2, "Stop")
+	s.assertMethod(c, "APIKeyManager", 1, "IssueAPIKeys")
 	s.assertMethod(c, "ModelManager", 2, "CreateModel")
 	s.assertMethod(c, "ModelManager", 2, "ListModels")
 	s.assertMethod(c, "Pinger", 1, "Ping")
 	s.assertMethod(c, "Bundle", 1, "GetChanges")
 	s.assertMethod(c, "HighAvailability", 2, "EnableHA")
 	s.assertMethod(c, "ApplicationOffers", 1, "ApplicationOffers")
+	s.assertMethod(c, "ModelSummaryWatcher", 1, "Next")
 }
 
 func (s *restrictControllerSuite) TestNotAllowed(c *gc.C) {
@@ -53,6 +53,10 @@ type apiHandler struct {
 	shared    *sharedServerContext
 	entity    state.Entity
 
+	// accessLimit, if non-empty, caps the model access of the
+	// authenticated entity; it is set for API key logins.
+	accessLimit permission.Access
+
 	// An empty modelUUID means that the user has logged in through the
 	// root of the API server rather than the /model/:model-uuid/api
 	// path, logins processed with v2 or later will only offer the
@@ -66,10 +70,18 @@ type apiHandler struct {
 	// serverHost is the host:port of the API server that the client
 	// connected to.
 	serverHost string
+
+	// stepUpMu guards the second-factor state of the connection, used
+	// for step-up authentication of destructive operations.
+	stepUpMu              sync.Mutex
+	secondFactorChallenge string
+	secondFactorAt        time.Time
 }
 
 var _ = (*apiHandler)(nil)
 
+var _ facade.StepUpSession = (*apiHandler)(nil)
+
 // newAPIHandler returns a new apiHandler.
 func newAPIHandler(srv *Server, st *state.State, rpcConn *rpc.Conn, modelUUID string, connectionID uint64, serverHost string) (*apiHandler, error) {
 	m, err := st.Model()
@@ -236,6 +248,9 @@ func restrictAPIRoot(
 			apiRoot = restrictRoot(apiRoot, caasModelFacadesOnly)
 		}
 	}
+	if auth.apiKeyLogin {
+		apiRoot = restrictRoot(apiRoot, apiKeyFacadesOnly)
+	}
 	return apiRoot, nil
 }
 
@@ -378,6 +393,18 @@ func (r *apiRoot) lookupMethod(rootName string, version int, methodName string)
 		}
 		return nil, noMethod, err
 	}
+	// Facades that are being rolled out to some models first are
+	// treated as unknown by all other models.
+	feature, err := r.facades.GetFeature(rootName, version)
+	if err != nil {
+		return nil, noMethod, err
+	}
+	if feature != "" && !r.modelFeatureEnabled(feature) {
+		return nil, noMethod, &rpcreflect.CallNotImplementedError{
+			RootMethod: rootName,
+			Version:    version,
+		}
+	}
 	rpcType := rpcreflect.ObjTypeOf(goType)
 	objMethod, err := rpcType.Method(methodName)
 	if err != nil {
@@ -393,6 +420,15 @@ func (r *apiRoot) lookupMethod(rootName string, version int, methodName string)
 	return goType, objMethod, nil
 }
 
+// modelFeatureEnabled reports whether the feature flag is enabled for
+// the root's model.
+func (r *apiRoot) modelFeatureEnabled(flag string) bool {
+	if r.state == nil || r.shared == nil {
+		return false
+	}
+	return r.shared.modelFeatureEnabled(r.state.ModelUUID(), flag)
+}
+
 func (r *apiRoot) dispose(key objectKey) {
 	r.objectMutex.Lock()
 	defer r.objectMutex.Unlock()
@@ -482,6 +518,11 @@ func (ctx *facadeContext) StatePool() *state.StatePool {
 	return ctx.r.shared.statePool
 }
 
+// FeatureEnabled is part of the facade.Context interface.
+func (ctx *facadeContext) FeatureEnabled(flag string) bool {
+	return ctx.r.shared.modelFeatureEnabled(ctx.State().ModelUUID(), flag)
+}
+
 // ID is part of of the facade.Context interface.
 func (ctx *facadeContext) ID() string {
 	return ctx.key.objId
@@ -568,6 +609,18 @@ func (ctx *facadeContext) SingularClaimer() (lease.Claimer, error) {
 	)
 }
 
+// OperatorClaimer is part of the facade.Context interface.
+// Operator leases are only available with the Raft leases implementation.
+func (ctx *facadeContext) OperatorClaimer() (lease.Claimer, error) {
+	if ctx.r.shared.featureEnabled(feature.LegacyLeases) {
+		return nil, errors.NotSupportedf("operator leases with the legacy lease manager")
+	}
+	return ctx.r.shared.leaseManager.Claimer(
+		lease.ApplicationOperatorNamespace,
+		ctx.State().ModelUUID(),
+	)
+}
+
 // adminRoot dispatches API calls to those available to an anonymous connection
 // which has not logged in, which here is the admin facade.
 type adminRoot struct {
@@ -661,9 +714,53 @@ func (r *apiHandler) ConnectedModel() string {
 
 // HasPermission returns true if the logged in user can perform <operation> on <target>.
 func (r *apiHandler) HasPermission(operation permission.Access, target names.Tag) (bool, error) {
+	if r.accessLimit != "" && !r.withinAccessLimit(operation, target) {
+		return false, nil
+	}
 	return common.HasPermission(r.state.UserPermission, r.entity.Tag(), operation, target)
 }
 
+// withinAccessLimit reports whether the operation on target is allowed
+// by the connection's access limit. Only operations on the connected
+// model, up to the limit, and controller logins are allowed.
+func (r *apiHandler) withinAccessLimit(operation permission.Access, target names.Tag) bool {
+	switch target.Kind() {
+	case names.ModelTagKind:
+		return target.Id() == r.model.UUID() && r.accessLimit.EqualOrGreaterModelAccessThan(operation)
+	case names.ControllerTagKind:
+		return operation == permission.LoginAccess
+	}
+	return false
+}
+
+// SecondFactorChallenge is part of the facade.StepUpSession interface.
+func (r *apiHandler) SecondFactorChallenge() string {
+	r.stepUpMu.Lock()
+	defer r.stepUpMu.Unlock()
+	return r.secondFactorChallenge
+}
+
+// SetSecondFactorChallenge is part of the facade.StepUpSession interface.
+func (r *apiHandler) SetSecondFactorChallenge(challenge string) {
+	r.stepUpMu.Lock()
+	defer r.stepUpMu.Unlock()
+	r.secondFactorChallenge = challenge
+}
+
+// SecondFactorAssertedAt is part of the facade.StepUpSession interface.
+func (r *apiHandler) SecondFactorAssertedAt() time.Time {
+	r.stepUpMu.Lock()
+	defer r.stepUpMu.Unlock()
+	return r.secondFactorAt
+}
+
+// SetSecondFactorAssertedAt is part of the facade.StepUpSession interface.
+func (r *apiHandler) SetSecondFactorAssertedAt(at time.Time) {
+	r.stepUpMu.Lock()
+	defer r.stepUpMu.Unlock()
+	r.secondFactorAt = at
+}
+
 // UserHasPermission returns true if the passed in user can perform <operation> on <target>.
 func (r *apiHandler) UserHasPermission(user names.UserTag, operation permission.Access, target names.Tag) (bool, error) {
 	return common.HasPermission(r.state.UserPermission, user, operation, target)
@@ -365,6 +365,28 @@ func (r *rootSuite) TestFindMethodHandlesInterfaceTypes(c *gc.C) {
 	c.Check(caller, gc.IsNil)
 }
 
+func (r *rootSuite) TestFindMethodCanaryFacade(c *gc.C) {
+	myGoodFacade := func(facade.Context) (*testingType, error) {
+		return &testingType{}, nil
+	}
+	registry := new(facade.Registry)
+	err := registry.RegisterStandard("my-testing-facade", 0, myGoodFacade)
+	c.Assert(err, jc.ErrorIsNil)
+	err = registry.RegisterCanary("my-testing-facade", 1, "testing-canary", myGoodFacade)
+	c.Assert(err, jc.ErrorIsNil)
+	srvRoot := apiserver.TestingAPIRoot(registry)
+
+	caller, err := srvRoot.FindMethod("my-testing-facade", 0, "Exposed")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(caller, gc.NotNil)
+
+	// The root has no model with the feature flag enabled.
+	caller, err = srvRoot.FindMethod("my-testing-facade", 1, "Exposed")
+	c.Check(caller, gc.IsNil)
+	c.Check(err, gc.FitsTypeOf, (*rpcreflect.CallNotImplementedError)(nil))
+	c.Check(err, gc.ErrorMatches, `unknown version \(1\) of interface "my-testing-facade"`)
+}
+
 func (r *rootSuite) TestDescribeFacades(c *gc.C) {
 	facades := apiserver.DescribeFacades(apiserver.AllFacades())
 	c.Check(facades, gc.Not(gc.HasLen), 0)
@@ -4,17 +4,21 @@
 package apiserver
 
 import (
+	"reflect"
 	"sync"
 	"time"
 
+	"github.com/juju/clock"
 	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"gopkg.in/juju/names.v3"
 
 	jujucontroller "github.com/juju/juju/controller"
 	"github.com/juju/juju/core/cache"
 	"github.com/juju/juju/core/lease"
 	"github.com/juju/juju/core/presence"
+	"github.com/juju/juju/core/tracing"
 	"github.com/juju/juju/feature"
 	"github.com/juju/juju/pubsub/apiserver"
 	"github.com/juju/juju/pubsub/controller"
@@ -47,8 +51,51 @@ type sharedServerContext struct {
 	configMutex      sync.RWMutex
 	controllerConfig jujucontroller.Config
 	features         set.Strings
+	// modelFeatures holds the feature flags enabled for individual
+	// models, keyed by model UUID. It is filled in lazily.
+	modelFeatures map[string]set.Strings
 
-	unsubscribe func()
+	// rateLimiter limits the rate of API requests made by each
+	// authenticated user, as set in the controller config.
+	rateLimiter *entityRateLimiter
+
+	// agentRateLimiter limits the rate of API requests made by each
+	// agent other than controller machines.
+	agentRateLimiter *entityRateLimiter
+
+	// keepAliveChanged is closed, and replaced, when the websocket
+	// keepalive settings for API connections change.
+	keepAliveChanged chan struct{}
+
+	// debugLogChanged is closed, and replaced, when the maximum
+	// duration of debug-log sessions changes.
+	debugLogChanged chan struct{}
+
+	// configHooks holds the hooks registered by subsystems to apply
+	// changes to the controller config keys they depend on.
+	configHooks []configHook
+
+	unsubscribe              func()
+	unsubscribeModelFeatures func()
+	unsubscribeLoggingConfig func()
+}
+
+// configHook holds a function to call with the new controller config
+// when the value of any of the given keys changes.
+type configHook struct {
+	keys    []string
+	changed func(jujucontroller.Config)
+}
+
+// appliesTo reports whether the value of any of the hook's keys differs
+// between the given controller configs.
+func (h configHook) appliesTo(oldConfig, newConfig jujucontroller.Config) bool {
+	for _, key := range h.keys {
+		if !reflect.DeepEqual(oldConfig[key], newConfig[key]) {
+			return true
+		}
+	}
+	return false
 }
 
 type sharedServerConfig struct {
@@ -58,6 +105,7 @@ type sharedServerConfig struct {
 	presence     presence.Recorder
 	leaseManager lease.Manager
 	logger       loggo.Logger
+	clock        clock.Clock
 }
 
 func (c *sharedServerConfig) validate() error {
@@ -76,6 +124,9 @@ func (c *sharedServerConfig) validate() error {
 	if c.leaseManager == nil {
 		return errors.NotValidf("nil leaseManager")
 	}
+	if c.clock == nil {
+		return errors.NotValidf("nil clock")
+	}
 	return nil
 }
 
@@ -95,8 +146,32 @@ func newSharedServerContex(config sharedServerConfig) (*sharedServerContext, err
 		leaseManager:     config.leaseManager,
 		logger:           config.logger,
 		controllerConfig: controllerConfig,
+		modelFeatures:    make(map[string]set.Strings),
+		keepAliveChanged: make(chan struct{}),
+		debugLogChanged:  make(chan struct{}),
+		rateLimiter: newEntityRateLimiter(
+			config.clock,
+			controllerConfig.APIRateLimit(),
+			controllerConfig.APIRateLimitBurst(),
+		),
+		agentRateLimiter: newEntityRateLimiter(
+			config.clock,
+			controllerConfig.APIAgentRateLimit(),
+			controllerConfig.APIAgentRateLimitBurst(),
+		),
 	}
 	ctx.features = controllerConfig.Features()
+	tracing.RegisterLogExporter()
+	tracing.SetSampleRate(controllerConfig.APITraceSampleRate())
+	ctx.watchConfig(ctx.updateRateLimit,
+		jujucontroller.APIRateLimit, jujucontroller.APIRateLimitBurst,
+		jujucontroller.APIAgentRateLimit, jujucontroller.APIAgentRateLimitBurst,
+	)
+	ctx.watchConfig(ctx.updateKeepAlive, jujucontroller.APIWebsocketPingInterval, jujucontroller.APIWebsocketPongTimeout)
+	ctx.watchConfig(ctx.updateTraceSampleRate, jujucontroller.APITraceSampleRate)
+	ctx.watchConfig(ctx.updateLoggingConfig, jujucontroller.ControllerLoggingConfig)
+	ctx.watchConfig(ctx.updateLogSinkTargets, jujucontroller.LogSinkTargets)
+	ctx.watchConfig(ctx.updateMaxDebugLogDuration, jujucontroller.MaxDebugLogDuration)
 	// We are able to get the current controller config before subscribing to changes
 	// because the changes are only ever published in response to an API call, and
 	// this function is called in the newServer call to create the API server,
@@ -106,11 +181,29 @@ func newSharedServerContex(config sharedServerConfig) (*sharedServerContext, err
 		ctx.logger.Criticalf("programming error in subscribe function: %v", err)
 		return nil, errors.Trace(err)
 	}
+	ctx.unsubscribeModelFeatures, err = ctx.centralHub.Subscribe(controller.ModelFeaturesChanged, ctx.onModelFeaturesChanged)
+	if err != nil {
+		ctx.unsubscribe()
+		ctx.logger.Criticalf("programming error in subscribe function: %v", err)
+		return nil, errors.Trace(err)
+	}
+	ctx.unsubscribeLoggingConfig, err = ctx.centralHub.Subscribe(controller.LoggingConfigRequest, ctx.onLoggingConfigRequest)
+	if err != nil {
+		ctx.unsubscribe()
+		ctx.unsubscribeModelFeatures()
+		ctx.logger.Criticalf("programming error in subscribe function: %v", err)
+		return nil, errors.Trace(err)
+	}
+	// Workers that started before the API server have already asked
+	// for the logging config.
+	ctx.publishLoggingConfig(controllerConfig.ControllerLoggingConfig())
 	return ctx, nil
 }
 
 func (c *sharedServerContext) Close() {
 	c.unsubscribe()
+	c.unsubscribeModelFeatures()
+	c.unsubscribeLoggingConfig()
 }
 
 func (c *sharedServerContext) onConfigChanged(topic string, data controller.ConfigChangedMessage, err error) {
@@ -122,16 +215,26 @@ func (c *sharedServerContext) onConfigChanged(topic string, data controller.Conf
 	features := data.Config.Features()
 
 	c.configMutex.Lock()
+	oldConfig := c.controllerConfig
 	c.controllerConfig = data.Config
 	removed := c.features.Difference(features)
 	added := features.Difference(c.features)
 	c.features = features
 	values := features.SortedValues()
+	var hooks []func(jujucontroller.Config)
+	for _, hook := range c.configHooks {
+		if hook.appliesTo(oldConfig, data.Config) {
+			hooks = append(hooks, hook.changed)
+		}
+	}
 	c.configMutex.Unlock()
 
 	if removed.Size() != 0 || added.Size() != 0 {
 		c.logger.Infof("updating features to %v", values)
 	}
+	for _, hook := range hooks {
+		hook(data.Config)
+	}
 	// If the presence implementation changes we need to restart
 	// the apiserver. So if the old presence feature flag is in either
 	// added or removed, we need to publish the restart message.
@@ -145,14 +248,183 @@ func (c *sharedServerContext) onConfigChanged(topic string, data controller.Conf
 	}
 }
 
+// watchConfig registers a hook, called with the new controller config
+// whenever the value of any of the given keys changes, so that the
+// change takes effect without restarting the API server. It returns
+// the controller config current when the hook was registered, which
+// the caller should apply itself.
+func (c *sharedServerContext) watchConfig(changed func(jujucontroller.Config), keys ...string) jujucontroller.Config {
+	c.configMutex.Lock()
+	defer c.configMutex.Unlock()
+	c.configHooks = append(c.configHooks, configHook{
+		keys:    keys,
+		changed: changed,
+	})
+	return c.controllerConfig
+}
+
+func (c *sharedServerContext) updateRateLimit(cfg jujucontroller.Config) {
+	rate, burst := cfg.APIRateLimit(), cfg.APIRateLimitBurst()
+	if c.rateLimiter.setLimits(rate, burst) {
+		c.logger.Infof("updating API rate limit to %v requests per second, burst %d", rate, burst)
+	}
+	rate, burst = cfg.APIAgentRateLimit(), cfg.APIAgentRateLimitBurst()
+	if c.agentRateLimiter.setLimits(rate, burst) {
+		c.logger.Infof("updating agent API rate limit to %v requests per second, burst %d", rate, burst)
+	}
+}
+
+func (c *sharedServerContext) updateKeepAlive(cfg jujucontroller.Config) {
+	keepAlive := keepAliveConfigFrom(cfg)
+	c.configMutex.Lock()
+	close(c.keepAliveChanged)
+	c.keepAliveChanged = make(chan struct{})
+	c.configMutex.Unlock()
+	c.logger.Infof("updating API websocket keepalive to ping every %v, with pong timeout %v",
+		keepAlive.PingInterval, keepAlive.PongTimeout)
+}
+
+func (c *sharedServerContext) updateTraceSampleRate(cfg jujucontroller.Config) {
+	sampleRate := cfg.APITraceSampleRate()
+	c.logger.Infof("updating API trace sample rate to %v", sampleRate)
+	tracing.SetSampleRate(sampleRate)
+}
+
+func (c *sharedServerContext) updateLoggingConfig(cfg jujucontroller.Config) {
+	loggingConfig := cfg.ControllerLoggingConfig()
+	c.logger.Infof("updating controller logging config to %q", loggingConfig)
+	c.publishLoggingConfig(loggingConfig)
+}
+
+func (c *sharedServerContext) updateLogSinkTargets(cfg jujucontroller.Config) {
+	// The logsink handlers check the targets for every record.
+	c.logger.Infof("updating logsink targets to %v", cfg.LogSinkTargets().SortedValues())
+}
+
+func (c *sharedServerContext) updateMaxDebugLogDuration(cfg jujucontroller.Config) {
+	c.configMutex.Lock()
+	close(c.debugLogChanged)
+	c.debugLogChanged = make(chan struct{})
+	c.configMutex.Unlock()
+	c.logger.Infof("updating maximum debug-log duration to %v", cfg.MaxDebugLogDuration())
+}
+
+func (c *sharedServerContext) onModelFeaturesChanged(topic string, data controller.ModelFeaturesChangedMessage, err error) {
+	if err != nil {
+		c.logger.Criticalf("programming error in %s message data: %v", topic, err)
+		return
+	}
+	c.configMutex.Lock()
+	c.modelFeatures[data.ModelUUID] = set.NewStrings(data.Features...)
+	c.configMutex.Unlock()
+	c.logger.Infof("updating features of model %q to %v", data.ModelUUID, data.Features)
+}
+
+func (c *sharedServerContext) onLoggingConfigRequest(topic string, data controller.LoggingConfigRequestMessage, err error) {
+	if err != nil {
+		c.logger.Criticalf("programming error in %s message data: %v", topic, err)
+		return
+	}
+	c.configMutex.RLock()
+	loggingConfig := c.controllerConfig.ControllerLoggingConfig()
+	c.configMutex.RUnlock()
+	c.publishLoggingConfig(loggingConfig)
+}
+
+// publishLoggingConfig publishes the controller logging config for the
+// logger workers of the local agent to adopt.
+func (c *sharedServerContext) publishLoggingConfig(loggingConfig string) {
+	_, err := c.centralHub.Publish(controller.LoggingConfigChanged, controller.LoggingConfigMessage{
+		Config:    loggingConfig,
+		LocalOnly: true,
+	})
+	if err != nil {
+		c.logger.Errorf("unable to publish logging config: %v", err)
+	}
+}
+
 func (c *sharedServerContext) featureEnabled(flag string) bool {
 	c.configMutex.RLock()
 	defer c.configMutex.RUnlock()
 	return c.features.Contains(flag)
 }
 
-func (c *sharedServerContext) maxDebugLogDuration() time.Duration {
+// modelFeatureEnabled reports whether the feature flag is enabled for
+// the model with the given UUID, either for the whole controller or for
+// that model alone.
+func (c *sharedServerContext) modelFeatureEnabled(modelUUID, flag string) bool {
+	if c.featureEnabled(flag) {
+		return true
+	}
+	features, err := c.featuresForModel(modelUUID)
+	if err != nil {
+		c.logger.Warningf("cannot get features of model %q: %v", modelUUID, err)
+		return false
+	}
+	return features.Contains(flag)
+}
+
+// featuresForModel returns the feature flags enabled for the model with
+// the given UUID alone, reading them from state the first time they are
+// needed. Later changes are published on the hub.
+func (c *sharedServerContext) featuresForModel(modelUUID string) (set.Strings, error) {
+	c.configMutex.RLock()
+	features, ok := c.modelFeatures[modelUUID]
+	c.configMutex.RUnlock()
+	if ok {
+		return features, nil
+	}
+
+	st, err := c.statePool.Get(modelUUID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer st.Release()
+	model, err := st.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	features = set.NewStrings(model.Features()...)
+
+	c.configMutex.Lock()
+	defer c.configMutex.Unlock()
+	// Any change published while the features were being read
+	// is at least as recent as what was read.
+	if current, ok := c.modelFeatures[modelUUID]; ok {
+		return current, nil
+	}
+	c.modelFeatures[modelUUID] = features
+	return features, nil
+}
+
+// checkRateLimit returns a *common.RateLimitExceededError if the entity
+// with the given tag has made too many API requests.
+func (c *sharedServerContext) checkRateLimit(tag names.Tag) error {
+	if tag.Kind() == names.UserTagKind {
+		return c.rateLimiter.check(tag)
+	}
+	return c.agentRateLimiter.check(tag)
+}
+
+// keepAliveConfig returns the websocket keepalive settings for API
+// connections, and a channel that is closed when they change.
+func (c *sharedServerContext) keepAliveConfig() (keepAliveConfig, <-chan struct{}) {
+	c.configMutex.RLock()
+	defer c.configMutex.RUnlock()
+	return keepAliveConfigFrom(c.controllerConfig), c.keepAliveChanged
+}
+
+// logSinkTargets returns where the logs sent by agents are written.
+func (c *sharedServerContext) logSinkTargets() set.Strings {
+	c.configMutex.RLock()
+	defer c.configMutex.RUnlock()
+	return c.controllerConfig.LogSinkTargets()
+}
+
+// maxDebugLogDuration returns the maximum duration of debug-log
+// sessions, and a channel that is closed when it changes.
+func (c *sharedServerContext) maxDebugLogDuration() (time.Duration, <-chan struct{}) {
 	c.configMutex.RLock()
 	defer c.configMutex.RUnlock()
-	return c.controllerConfig.MaxDebugLogDuration()
+	return c.controllerConfig.MaxDebugLogDuration(), c.debugLogChanged
 }
@@ -4,22 +4,29 @@
 package apiserver
 
 import (
+	"reflect"
 	"time"
 
 	"github.com/juju/clock"
+	"github.com/juju/clock/testclock"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/pubsub"
 	jc "github.com/juju/testing/checkers"
 	"github.com/prometheus/client_golang/prometheus"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
 	"gopkg.in/juju/worker.v1/workertest"
 
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
 	corecontroller "github.com/juju/juju/controller"
 	"github.com/juju/juju/core/cache"
 	"github.com/juju/juju/core/presence"
 	"github.com/juju/juju/feature"
 	"github.com/juju/juju/pubsub/controller"
+	"github.com/juju/juju/rpc/rpcreflect"
 	statetesting "github.com/juju/juju/state/testing"
 	"github.com/juju/juju/testing"
 	"github.com/juju/juju/worker/gate"
@@ -63,6 +70,7 @@ func (s *sharedServerContextSuite) SetUpTest(c *gc.C) {
 		presence:     presence.New(clock.WallClock),
 		leaseManager: &lease.Manager{},
 		logger:       loggo.GetLogger("test"),
+		clock:        testclock.NewClock(time.Time{}),
 	}
 }
 
@@ -94,6 +102,13 @@ func (s *sharedServerContextSuite) TestConfigNoLeaseManager(c *gc.C) {
 	c.Check(err, gc.ErrorMatches, "nil leaseManager not valid")
 }
 
+func (s *sharedServerContextSuite) TestConfigNoClock(c *gc.C) {
+	s.config.clock = nil
+	err := s.config.validate()
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+	c.Check(err, gc.ErrorMatches, "nil clock not valid")
+}
+
 func (s *sharedServerContextSuite) TestNewCallsConfigValidate(c *gc.C) {
 	s.config.statePool = nil
 	ctx, err := newSharedServerContex(s.config)
@@ -151,7 +166,8 @@ func (s *sharedServerContextSuite) TestControllerConfigChanged(c *gc.C) {
 	c.Check(ctx.featureEnabled("foo"), jc.IsTrue)
 	c.Check(ctx.featureEnabled("bar"), jc.IsTrue)
 	c.Check(ctx.featureEnabled("baz"), jc.IsFalse)
-	c.Check(stub.published, gc.HasLen, 0)
+	// Only the logging config is published, when the context starts.
+	c.Check(stub.published, jc.DeepEquals, []string{"controller.logging-config-changed"})
 }
 
 func (s *sharedServerContextSuite) TestAddingOldPresenceFeature(c *gc.C) {
@@ -175,7 +191,7 @@ func (s *sharedServerContextSuite) TestAddingOldPresenceFeature(c *gc.C) {
 		c.Fatalf("handler didn't")
 	}
 
-	c.Check(stub.published, jc.DeepEquals, []string{"apiserver.restart"})
+	c.Check(stub.published, jc.DeepEquals, []string{"controller.logging-config-changed", "apiserver.restart"})
 }
 
 func (s *sharedServerContextSuite) TestRemovingOldPresenceFeature(c *gc.C) {
@@ -203,7 +219,322 @@ func (s *sharedServerContextSuite) TestRemovingOldPresenceFeature(c *gc.C) {
 		c.Fatalf("handler didn't")
 	}
 
-	c.Check(stub.published, jc.DeepEquals, []string{"apiserver.restart"})
+	c.Check(stub.published, jc.DeepEquals, []string{"controller.logging-config-changed", "apiserver.restart"})
+}
+
+func (s *sharedServerContextSuite) TestModelFeatureEnabled(c *gc.C) {
+	err := s.Model.UpdateFeatures([]string{"foo"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := s.newContext(c)
+
+	c.Check(ctx.modelFeatureEnabled(s.Model.UUID(), "foo"), jc.IsTrue)
+	c.Check(ctx.modelFeatureEnabled(s.Model.UUID(), "bar"), jc.IsFalse)
+	c.Check(ctx.featureEnabled("foo"), jc.IsFalse)
+
+	msg := controller.ModelFeaturesChangedMessage{
+		ModelUUID: s.Model.UUID(),
+		Features:  []string{"bar"},
+	}
+	done, err := s.hub.Publish(controller.ModelFeaturesChanged, msg)
+	c.Assert(err, jc.ErrorIsNil)
+
+	select {
+	case <-done:
+	case <-time.After(testing.LongWait):
+		c.Fatalf("handler didn't")
+	}
+
+	c.Check(ctx.modelFeatureEnabled(s.Model.UUID(), "foo"), jc.IsFalse)
+	c.Check(ctx.modelFeatureEnabled(s.Model.UUID(), "bar"), jc.IsTrue)
+}
+
+func (s *sharedServerContextSuite) TestModelFeatureEnabledControllerWide(c *gc.C) {
+	err := s.State.UpdateControllerConfig(map[string]interface{}{
+		"features": []string{"foo"},
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := s.newContext(c)
+
+	c.Check(ctx.modelFeatureEnabled(s.Model.UUID(), "foo"), jc.IsTrue)
+}
+
+func (s *sharedServerContextSuite) TestCanaryFacades(c *gc.C) {
+	newFacade := func(facade.Context) (*canaryFacade, error) {
+		return &canaryFacade{}, nil
+	}
+	registry := new(facade.Registry)
+	err := registry.RegisterStandard("Canary", 1, newFacade)
+	c.Assert(err, jc.ErrorIsNil)
+	err = registry.RegisterCanary("Canary", 2, "canary", newFacade)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx := s.newContext(c)
+	root := &apiRoot{
+		clock:       clock.WallClock,
+		state:       s.State,
+		shared:      ctx,
+		facades:     registry,
+		resources:   common.NewResources(),
+		objectCache: make(map[objectKey]reflect.Value),
+	}
+	featureEnabled := func(flag string) bool {
+		return ctx.modelFeatureEnabled(s.Model.UUID(), flag)
+	}
+
+	c.Check(withoutCanaryVersions(registry, DescribeFacades(registry), featureEnabled), jc.DeepEquals, []params.FacadeVersions{
+		{Name: "Canary", Versions: []int{1}},
+	})
+	_, err = root.FindMethod("Canary", 1, "Exposed")
+	c.Check(err, jc.ErrorIsNil)
+	_, err = root.FindMethod("Canary", 2, "Exposed")
+	c.Check(err, gc.FitsTypeOf, (*rpcreflect.CallNotImplementedError)(nil))
+
+	msg := controller.ModelFeaturesChangedMessage{
+		ModelUUID: s.Model.UUID(),
+		Features:  []string{"canary"},
+	}
+	done, err := s.hub.Publish(controller.ModelFeaturesChanged, msg)
+	c.Assert(err, jc.ErrorIsNil)
+
+	select {
+	case <-done:
+	case <-time.After(testing.LongWait):
+		c.Fatalf("handler didn't")
+	}
+
+	c.Check(withoutCanaryVersions(registry, DescribeFacades(registry), featureEnabled), jc.DeepEquals, []params.FacadeVersions{
+		{Name: "Canary", Versions: []int{1, 2}},
+	})
+	_, err = root.FindMethod("Canary", 2, "Exposed")
+	c.Check(err, jc.ErrorIsNil)
+}
+
+type canaryFacade struct{}
+
+func (canaryFacade) Exposed() error {
+	return nil
+}
+
+func (s *sharedServerContextSuite) TestRateLimitConfigChanged(c *gc.C) {
+	ctx := s.newContext(c)
+	tag := names.NewUserTag("bob")
+	for i := 0; i < 5; i++ {
+		c.Assert(ctx.checkRateLimit(tag), jc.ErrorIsNil)
+	}
+
+	msg := controller.ConfigChangedMessage{
+		Config: corecontroller.Config{
+			corecontroller.APIRateLimit:      1.0,
+			corecontroller.APIRateLimitBurst: 2,
+		},
+	}
+	done, err := s.hub.Publish(controller.ConfigChanged, msg)
+	c.Assert(err, jc.ErrorIsNil)
+
+	select {
+	case <-done:
+	case <-time.After(testing.LongWait):
+		c.Fatalf("handler didn't")
+	}
+
+	c.Check(ctx.checkRateLimit(tag), jc.ErrorIsNil)
+	c.Check(ctx.checkRateLimit(tag), jc.ErrorIsNil)
+	err = ctx.checkRateLimit(tag)
+	c.Check(err, jc.Satisfies, common.IsRateLimitExceededError)
+	c.Check(err, gc.ErrorMatches, `rate limit exceeded for bob, try again in 1s`)
+}
+
+func (s *sharedServerContextSuite) TestAgentRateLimitDefaultsToUserLimit(c *gc.C) {
+	ctx := s.newContext(c)
+	s.publishConfig(c, corecontroller.Config{
+		corecontroller.APIRateLimit:      1.0,
+		corecontroller.APIRateLimitBurst: 2,
+	})
+
+	tag := names.NewMachineTag("1")
+	c.Check(ctx.checkRateLimit(tag), jc.ErrorIsNil)
+	c.Check(ctx.checkRateLimit(tag), jc.ErrorIsNil)
+	err := ctx.checkRateLimit(tag)
+	c.Check(err, jc.Satisfies, common.IsRateLimitExceededError)
+	c.Check(err, gc.ErrorMatches, `rate limit exceeded for machine 1, try again in 1s`)
+}
+
+func (s *sharedServerContextSuite) TestAgentRateLimitConfigChanged(c *gc.C) {
+	ctx := s.newContext(c)
+	s.publishConfig(c, corecontroller.Config{
+		corecontroller.APIRateLimit:           1.0,
+		corecontroller.APIRateLimitBurst:      1,
+		corecontroller.APIAgentRateLimit:      2.0,
+		corecontroller.APIAgentRateLimitBurst: 3,
+	})
+
+	user := names.NewUserTag("bob")
+	c.Check(ctx.checkRateLimit(user), jc.ErrorIsNil)
+	c.Check(ctx.checkRateLimit(user), jc.Satisfies, common.IsRateLimitExceededError)
+
+	agent := names.NewUnitTag("mysql/0")
+	for i := 0; i < 3; i++ {
+		c.Check(ctx.checkRateLimit(agent), jc.ErrorIsNil)
+	}
+	err := ctx.checkRateLimit(agent)
+	c.Check(err, jc.Satisfies, common.IsRateLimitExceededError)
+	c.Check(err, gc.ErrorMatches, `rate limit exceeded for unit mysql/0, try again in 500ms`)
+}
+
+func (s *sharedServerContextSuite) publishConfig(c *gc.C, config corecontroller.Config) {
+	done, err := s.hub.Publish(controller.ConfigChanged, controller.ConfigChangedMessage{Config: config})
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case <-done:
+	case <-time.After(testing.LongWait):
+		c.Fatalf("handler didn't")
+	}
+}
+
+func (s *sharedServerContextSuite) TestLoggingConfigPublished(c *gc.C) {
+	received := make(chan controller.LoggingConfigMessage, 10)
+	unsubscribe, err := s.hub.Subscribe(controller.LoggingConfigChanged,
+		func(_ string, msg controller.LoggingConfigMessage, err error) {
+			c.Check(err, jc.ErrorIsNil)
+			received <- msg
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	defer unsubscribe()
+	next := func() controller.LoggingConfigMessage {
+		select {
+		case msg := <-received:
+			return msg
+		case <-time.After(testing.LongWait):
+			c.Fatalf("logging config not published")
+		}
+		panic("unreachable")
+	}
+
+	ctx := s.newContext(c)
+	c.Assert(next(), jc.DeepEquals, controller.LoggingConfigMessage{LocalOnly: true})
+
+	done, err := s.hub.Publish(controller.ConfigChanged, controller.ConfigChangedMessage{
+		Config: corecontroller.Config{
+			corecontroller.ControllerLoggingConfig: "juju.apiserver=DEBUG",
+			corecontroller.LogSinkTargets:          []interface{}{"file"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case <-done:
+	case <-time.After(testing.LongWait):
+		c.Fatalf("handler didn't")
+	}
+	c.Assert(next(), jc.DeepEquals, controller.LoggingConfigMessage{
+		Config:    "juju.apiserver=DEBUG",
+		LocalOnly: true,
+	})
+	c.Assert(ctx.logSinkTargets().SortedValues(), jc.DeepEquals, []string{"file"})
+
+	// Workers starting later ask for the config.
+	_, err = s.hub.Publish(controller.LoggingConfigRequest, controller.LoggingConfigRequestMessage{
+		Requester: "test",
+		LocalOnly: true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(next().Config, gc.Equals, "juju.apiserver=DEBUG")
+}
+
+func (s *sharedServerContextSuite) TestKeepAliveConfigChanged(c *gc.C) {
+	ctx := s.newContext(c)
+	cfg, changed := ctx.keepAliveConfig()
+	c.Assert(cfg, gc.Equals, keepAliveConfig{
+		PingInterval: corecontroller.DefaultAPIWebsocketPingInterval,
+		PongTimeout:  corecontroller.DefaultAPIWebsocketPongTimeout,
+	})
+
+	publish := func(config corecontroller.Config) {
+		done, err := s.hub.Publish(controller.ConfigChanged, controller.ConfigChangedMessage{Config: config})
+		c.Assert(err, jc.ErrorIsNil)
+		select {
+		case <-done:
+		case <-time.After(testing.LongWait):
+			c.Fatalf("handler didn't")
+		}
+	}
+
+	// Unrelated changes leave the keepalive alone.
+	publish(corecontroller.Config{corecontroller.APIRateLimit: 1.0})
+	select {
+	case <-changed:
+		c.Fatalf("keepalive changed unexpectedly")
+	default:
+	}
+
+	publish(corecontroller.Config{
+		corecontroller.APIWebsocketPingInterval: 10 * time.Second,
+		corecontroller.APIWebsocketPongTimeout:  15 * time.Second,
+	})
+	select {
+	case <-changed:
+	default:
+		c.Fatalf("keepalive not changed")
+	}
+	cfg, _ = ctx.keepAliveConfig()
+	c.Assert(cfg, gc.Equals, keepAliveConfig{
+		PingInterval: 10 * time.Second,
+		PongTimeout:  15 * time.Second,
+	})
+}
+
+func (s *sharedServerContextSuite) TestWatchConfig(c *gc.C) {
+	ctx := s.newContext(c)
+	changes := make(chan corecontroller.Config, 10)
+	current := ctx.watchConfig(func(cfg corecontroller.Config) {
+		changes <- cfg
+	}, corecontroller.AgentLogfileMaxSize, corecontroller.AgentLogfileMaxBackups)
+	c.Assert(current.AgentLogfileMaxSizeMB(), gc.Equals, corecontroller.DefaultAgentLogfileMaxSizeMB)
+
+	publish := func(config corecontroller.Config) {
+		done, err := s.hub.Publish(controller.ConfigChanged, controller.ConfigChangedMessage{Config: config})
+		c.Assert(err, jc.ErrorIsNil)
+		select {
+		case <-done:
+		case <-time.After(testing.LongWait):
+			c.Fatalf("handler didn't")
+		}
+	}
+
+	// Changes to other keys don't call the hook.
+	publish(corecontroller.Config{corecontroller.APIRateLimit: 1.0})
+	c.Assert(changes, gc.HasLen, 0)
+
+	publish(corecontroller.Config{
+		corecontroller.APIRateLimit:           1.0,
+		corecontroller.AgentLogfileMaxBackups: 5,
+	})
+	c.Assert(changes, gc.HasLen, 1)
+	c.Assert((<-changes).AgentLogfileMaxBackups(), gc.Equals, 5)
+}
+
+func (s *sharedServerContextSuite) TestMaxDebugLogDurationChanged(c *gc.C) {
+	ctx := s.newContext(c)
+	duration, changed := ctx.maxDebugLogDuration()
+	c.Assert(duration, gc.Equals, corecontroller.DefaultMaxDebugLogDuration)
+
+	done, err := s.hub.Publish(controller.ConfigChanged, controller.ConfigChangedMessage{
+		Config: corecontroller.Config{
+			corecontroller.MaxDebugLogDuration: time.Hour,
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case <-done:
+	case <-time.After(testing.LongWait):
+		c.Fatalf("handler didn't")
+	}
+	select {
+	case <-changed:
+	default:
+		c.Fatalf("max debug-log duration not changed")
+	}
+	duration, _ = ctx.maxDebugLogDuration()
+	c.Assert(duration, gc.Equals, time.Hour)
 }
 
 type noopRegisterer struct {
@@ -20,7 +20,9 @@ import (
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/httpcontext"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/stateenvirons"
 )
 
 // AgentTags are those used by any Juju agent.
@@ -47,6 +49,9 @@ func NewAuthenticator(statePool *state.StatePool, clock clock.Clock) (*Authentic
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	authContext.agentAuth.VerifyInstanceIdentity = newInstanceIdentityVerifier(
+		statePool, stateenvirons.GetNewEnvironFunc(environs.New),
+	)
 	return &Authenticator{
 		statePool:   statePool,
 		authContext: authContext,
@@ -103,6 +108,14 @@ func (a *Authenticator) AuthenticateLoginRequest(
 	modelUUID string,
 	req params.LoginRequest,
 ) (httpcontext.AuthInfo, error) {
+	if req.APIKey != "" {
+		authInfo, err := a.authenticateAPIKey(modelUUID, req)
+		if err != nil {
+			return httpcontext.AuthInfo{}, errors.NewUnauthorized(err, "")
+		}
+		return authInfo, nil
+	}
+
 	var authTag names.Tag
 	if req.AuthTag != "" {
 		tag, err := names.ParseTag(req.AuthTag)
@@ -145,6 +158,52 @@ func (a *Authenticator) AuthenticateLoginRequest(
 	return authInfo, nil
 }
 
+// authenticateAPIKey authenticates a login request carrying an API key,
+// which acts on behalf of its owner with access limited to the key's
+// model and access level.
+func (a *Authenticator) authenticateAPIKey(modelUUID string, req params.LoginRequest) (httpcontext.AuthInfo, error) {
+	if req.AuthTag != "" || req.Credentials != "" || len(req.Macaroons) > 0 {
+		return httpcontext.AuthInfo{}, errors.New("API key logins must not supply other credentials")
+	}
+	id, secret, err := state.ParseAPIKeyToken(req.APIKey)
+	if err != nil {
+		return httpcontext.AuthInfo{}, errors.Trace(err)
+	}
+	key, err := a.statePool.SystemState().APIKey(id)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Don't reveal which keys exist.
+			return httpcontext.AuthInfo{}, common.ErrBadCreds
+		}
+		return httpcontext.AuthInfo{}, errors.Trace(err)
+	}
+	if !key.SecretValid(secret) {
+		return httpcontext.AuthInfo{}, common.ErrBadCreds
+	}
+	if key.Expired(a.authContext.clock.Now()) {
+		return httpcontext.AuthInfo{}, errors.Errorf("API key %q has expired", id)
+	}
+	if key.ModelTag().Id() != modelUUID {
+		return httpcontext.AuthInfo{}, errors.Errorf("API key %q is not valid for model %q", id, modelUUID)
+	}
+
+	st, err := a.statePool.Get(modelUUID)
+	if err != nil {
+		return httpcontext.AuthInfo{}, errors.Trace(err)
+	}
+	defer st.Release()
+
+	// The key's owner must still have access to the model.
+	entity, err := modelUserEntityFinder{st.State}.FindEntity(key.Owner())
+	if err != nil {
+		return httpcontext.AuthInfo{}, errors.Trace(err)
+	}
+	return httpcontext.AuthInfo{
+		Entity:      entity,
+		AccessLimit: key.Access(),
+	}, nil
+}
+
 func (a *Authenticator) checkCreds(
 	st *state.State,
 	req params.LoginRequest,
@@ -8,8 +8,17 @@ import (
 	"gopkg.in/macaroon.v2-unstable"
 
 	"github.com/juju/juju/apiserver/authentication"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/stateenvirons"
 )
 
+func NewInstanceIdentityVerifier(
+	statePool *state.StatePool,
+	newEnviron stateenvirons.NewEnvironFunc,
+) authentication.InstanceIdentityVerifier {
+	return newInstanceIdentityVerifier(statePool, newEnviron)
+}
+
 // TODO update the tests moved from apiserver to test via the public
 // interface, and then get rid of these.
 func EntityAuthenticator(authenticator *Authenticator, tag names.Tag) (authentication.EntityAuthenticator, error) {
@@ -0,0 +1,54 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package stateauthenticator
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/authentication"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/stateenvirons"
+)
+
+// newInstanceIdentityVerifier returns an
+// authentication.InstanceIdentityVerifier that verifies documents
+// with the environ of the machine's model, provided that model
+// enrolls machines by instance identity.
+func newInstanceIdentityVerifier(
+	statePool *state.StatePool,
+	newEnviron stateenvirons.NewEnvironFunc,
+) authentication.InstanceIdentityVerifier {
+	return func(machine *state.Machine, document, audience string) (instance.Id, error) {
+		st, err := statePool.Get(machine.ModelUUID())
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		defer st.Release()
+
+		model, err := st.Model()
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		cfg, err := model.ModelConfig()
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		if cfg.MachineEnrollment() != config.MachineEnrollmentInstanceIdentity {
+			return "", errors.Errorf("model %q does not enroll machines by instance identity", model.Name())
+		}
+		env, err := newEnviron(st.State)
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		verifier, ok := env.(environs.InstanceIdentityVerifier)
+		if !ok {
+			return "", errors.NotSupportedf("instance identity verification on %q", cfg.Type())
+		}
+		instId, err := verifier.VerifyInstanceIdentity(state.CallContext(st.State), document, audience)
+		return instId, errors.Trace(err)
+	}
+}
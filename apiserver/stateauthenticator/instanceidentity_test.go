@@ -0,0 +1,83 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package stateauthenticator_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/stateauthenticator"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type instanceIdentitySuite struct {
+	statetesting.StateSuite
+	machine *state.Machine
+}
+
+var _ = gc.Suite(&instanceIdentitySuite{})
+
+func (s *instanceIdentitySuite) SetUpTest(c *gc.C) {
+	s.StateSuite.SetUpTest(c)
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	s.machine = machine
+}
+
+func (s *instanceIdentitySuite) enableEnrollment(c *gc.C) {
+	err := s.Model.UpdateModelConfig(map[string]interface{}{
+		"machine-enrollment": "instance-identity",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *instanceIdentitySuite) TestVerify(c *gc.C) {
+	s.enableEnrollment(c)
+	env := &verifyingEnviron{instId: "i-123"}
+	verify := stateauthenticator.NewInstanceIdentityVerifier(s.StatePool, func(*state.State) (environs.Environ, error) {
+		return env, nil
+	})
+	instId, err := verify(s.machine, "document", "audience")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instId, gc.Equals, instance.Id("i-123"))
+	c.Assert(env.args, jc.DeepEquals, []string{"document", "audience"})
+}
+
+func (s *instanceIdentitySuite) TestVerifyEnrollmentNotEnabled(c *gc.C) {
+	verify := stateauthenticator.NewInstanceIdentityVerifier(s.StatePool, func(*state.State) (environs.Environ, error) {
+		c.Fatalf("unexpected call")
+		return nil, nil
+	})
+	_, err := verify(s.machine, "document", "audience")
+	c.Assert(err, gc.ErrorMatches, `model "testmodel" does not enroll machines by instance identity`)
+}
+
+func (s *instanceIdentitySuite) TestVerifyNotSupported(c *gc.C) {
+	s.enableEnrollment(c)
+	verify := stateauthenticator.NewInstanceIdentityVerifier(s.StatePool, func(*state.State) (environs.Environ, error) {
+		return &nonVerifyingEnviron{}, nil
+	})
+	_, err := verify(s.machine, "document", "audience")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+type nonVerifyingEnviron struct {
+	environs.Environ
+}
+
+type verifyingEnviron struct {
+	environs.Environ
+	instId instance.Id
+	args   []string
+}
+
+func (e *verifyingEnviron) VerifyInstanceIdentity(ctx context.ProviderCallContext, document, audience string) (instance.Id, error) {
+	e.args = append(e.args, document, audience)
+	return e.instId, nil
+}
@@ -5,6 +5,7 @@ package testing
 
 import (
 	"strings"
+	"time"
 
 	"gopkg.in/juju/names.v3"
 
@@ -142,3 +143,31 @@ func (fa FakeAuthorizer) UserHasPermission(user names.UserTag, operation permiss
 	}
 	return false, nil
 }
+
+// FakeStepUpAuthorizer is a FakeAuthorizer that also implements the
+// facade.StepUpSession interface.
+type FakeStepUpAuthorizer struct {
+	FakeAuthorizer
+	Challenge  string
+	AssertedAt time.Time
+}
+
+// SecondFactorChallenge is part of the facade.StepUpSession interface.
+func (fa *FakeStepUpAuthorizer) SecondFactorChallenge() string {
+	return fa.Challenge
+}
+
+// SetSecondFactorChallenge is part of the facade.StepUpSession interface.
+func (fa *FakeStepUpAuthorizer) SetSecondFactorChallenge(challenge string) {
+	fa.Challenge = challenge
+}
+
+// SecondFactorAssertedAt is part of the facade.StepUpSession interface.
+func (fa *FakeStepUpAuthorizer) SecondFactorAssertedAt() time.Time {
+	return fa.AssertedAt
+}
+
+// SetSecondFactorAssertedAt is part of the facade.StepUpSession interface.
+func (fa *FakeStepUpAuthorizer) SetSecondFactorAssertedAt(at time.Time) {
+	fa.AssertedAt = at
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/juju/collections/set"
+	"github.com/juju/errors"
 	"github.com/juju/testing"
 	"github.com/juju/utils"
 	gc "gopkg.in/check.v1"
@@ -340,6 +341,10 @@ func (f *FakeSubnet) SpaceID() string {
 	return f.Info.SpaceID
 }
 
+func (f *FakeSubnet) FanLocalUnderlay() string {
+	return f.Info.FanLocalUnderlay
+}
+
 func (f *FakeSubnet) Life() life.Value {
 	return life.Value(f.Info.Life)
 }
@@ -357,9 +362,10 @@ type StubBacking struct {
 	EnvConfig *config.Config
 	Cloud     environs.CloudSpec
 
-	Zones   []providercommon.AvailabilityZone
-	Spaces  []networkingcommon.BackingSpace
-	Subnets []networkingcommon.BackingSubnet
+	Zones           []providercommon.AvailabilityZone
+	Spaces          []networkingcommon.BackingSpace
+	Subnets         []networkingcommon.BackingSubnet
+	MachineNetworks []networkingcommon.BackingMachineNetwork
 }
 
 var _ networkingcommon.NetworkBacking = (*StubBacking)(nil)
@@ -451,6 +457,7 @@ func (sb *StubBacking) SetUp(c *gc.C, envName string, withZones, withSpaces, wit
 			&FakeSubnet{info1},
 		}
 	}
+	sb.MachineNetworks = nil
 }
 
 func (sb *StubBacking) ModelConfig() (*config.Config, error) {
@@ -524,6 +531,14 @@ func (sb *StubBacking) AllSubnets() ([]networkingcommon.BackingSubnet, error) {
 	return output, nil
 }
 
+func (sb *StubBacking) AllMachineNetworks() ([]networkingcommon.BackingMachineNetwork, error) {
+	sb.MethodCall(sb, "AllMachineNetworks")
+	if err := sb.NextErr(); err != nil {
+		return nil, err
+	}
+	return sb.MachineNetworks, nil
+}
+
 func (sb *StubBacking) AddSubnet(subnetInfo networkingcommon.BackingSubnetInfo) (networkingcommon.BackingSubnet, error) {
 	sb.MethodCall(sb, "AddSubnet", subnetInfo)
 	if err := sb.NextErr(); err != nil {
@@ -544,6 +559,39 @@ func (sb *StubBacking) AddSpace(name string, providerId network.Id, subnets []st
 	return nil
 }
 
+func (sb *StubBacking) RenameSpace(fromName, toName string) error {
+	sb.MethodCall(sb, "RenameSpace", fromName, toName)
+	if err := sb.NextErr(); err != nil {
+		return err
+	}
+	for _, space := range sb.Spaces {
+		if fs, ok := space.(*FakeSpace); ok && fs.SpaceName == fromName {
+			fs.SpaceName = toName
+			return nil
+		}
+	}
+	return errors.NotFoundf("space %q", fromName)
+}
+
+func (sb *StubBacking) RemoveSpace(name string, force bool) error {
+	sb.MethodCall(sb, "RemoveSpace", name, force)
+	if err := sb.NextErr(); err != nil {
+		return err
+	}
+	for i, space := range sb.Spaces {
+		if space.Name() == name {
+			sb.Spaces = append(sb.Spaces[:i], sb.Spaces[i+1:]...)
+			return nil
+		}
+	}
+	return errors.NotFoundf("space %q", name)
+}
+
+func (sb *StubBacking) MoveSubnets(spaceName string, cidrs []string) error {
+	sb.MethodCall(sb, "MoveSubnets", spaceName, cidrs)
+	return sb.NextErr()
+}
+
 func (sb *StubBacking) ReloadSpaces(environ environs.BootstrapEnviron) error {
 	sb.MethodCall(sb, "ReloadSpaces", environ)
 	if err := sb.NextErr(); err != nil {
@@ -10,6 +10,7 @@ import (
 	"github.com/juju/juju/apiserver/common/crossmodel"
 	"github.com/juju/juju/apiserver/common/storagecommon"
 	"github.com/juju/juju/apiserver/facade"
+	controllerfacade "github.com/juju/juju/apiserver/facades/client/controller"
 	"github.com/juju/juju/apiserver/facades/controller/crossmodelrelations"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/controller"
@@ -597,3 +598,46 @@ var getControllerCACert = func(st migrationBackend) (string, error) {
 	}
 	return cacert, nil
 }
+
+func newModelSummaryWatcher(context facade.Context) (facade.Facade, error) {
+	id := context.ID()
+	auth := context.Auth()
+	resources := context.Resources()
+
+	if !auth.AuthClient() {
+		// The permission checks are made when the watcher is
+		// created, by WatchModelSummaries.
+		return nil, common.ErrPerm
+	}
+	watcher, ok := resources.Get(id).(controllerfacade.ModelSummaryWatcher)
+	if !ok {
+		return nil, common.ErrUnknownWatcher
+	}
+	return &srvModelSummaryWatcher{
+		watcherCommon: newWatcherCommon(context),
+		watcher:       watcher,
+	}, nil
+}
+
+// srvModelSummaryWatcher defines the API wrapping a
+// controller.ModelSummaryWatcher, which watches the summaries of the
+// models that a user can see on the controller and its peers.
+type srvModelSummaryWatcher struct {
+	watcherCommon
+	watcher controllerfacade.ModelSummaryWatcher
+}
+
+// Next returns when the summaries of the models have changed since the
+// most recent call to Next or the WatchModelSummaries call that created
+// the watcher, or when there are errors fetching them from peer
+// controllers.
+func (w *srvModelSummaryWatcher) Next() (params.ModelSummaryWatcherNextResults, error) {
+	if results, ok := <-w.watcher.Changes(); ok {
+		return results, nil
+	}
+	err := w.watcher.Err()
+	if err == nil {
+		err = common.ErrStoppedWatcher
+	}
+	return params.ModelSummaryWatcherNextResults{}, err
+}
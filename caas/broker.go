@@ -87,6 +87,19 @@ const (
 	ServiceExternal     ServiceType = "external"
 )
 
+// ProvisioningMode defines where the charm of an application runs.
+type ProvisioningMode string
+
+const (
+	// ProvisioningModeOperator runs the charm in an operator pod
+	// separate from the workload pods.
+	ProvisioningModeOperator ProvisioningMode = "operator"
+
+	// ProvisioningModeSidecar runs the charm in a container alongside
+	// the workload in each unit's pod.
+	ProvisioningModeSidecar ProvisioningMode = "sidecar"
+)
+
 // DeploymentParams defines parameters for specifying how a service is deployed.
 type DeploymentParams struct {
 	DeploymentType DeploymentType
@@ -113,6 +126,45 @@ type ServiceParams struct {
 
 	// Devices is a set of parameters for Devices that is required.
 	Devices []devices.KubernetesDeviceParams
+
+	// Sidecar, if set, is the charm container to run alongside the
+	// workload in each pod of an application in sidecar mode.
+	Sidecar *SidecarParams
+}
+
+// SidecarParams defines the charm container run in each unit pod
+// of an application in sidecar mode.
+type SidecarParams struct {
+	// ImageDetails defines the image the charm container runs.
+	ImageDetails specs.ImageDetails
+
+	// Mounts are the application filesystems the charm container
+	// mounts as well as the workload.
+	Mounts []SidecarMount
+}
+
+// SidecarMount defines where an application filesystem is mounted
+// in the charm container.
+type SidecarMount struct {
+	// StorageName is the name of the charm storage.
+	StorageName string
+
+	// MountPoint is the path at which the filesystem is mounted.
+	MountPoint string
+
+	// ReadOnly is true if the filesystem is mounted read-only.
+	ReadOnly bool
+}
+
+// SidecarConfig is the config to use when preparing the charm
+// containers of an application in sidecar mode.
+type SidecarConfig struct {
+	// AgentConf is the contents of the agent.conf file the charm
+	// containers use. If it is nil, the existing agent.conf is kept.
+	AgentConf []byte
+
+	// ResourceTags is a set of tags to set on the created resources.
+	ResourceTags map[string]string
 }
 
 // OperatorState is returned by the OperatorExists call.
@@ -143,6 +195,20 @@ type Broker interface {
 	// DeleteOperator deletes the specified operator.
 	DeleteOperator(appName string) error
 
+	// ForceDeleteOperator deletes the specified operator without
+	// waiting for it to terminate gracefully, removing anything, such
+	// as finalizers, that is keeping it from being deleted.
+	ForceDeleteOperator(appName string) error
+
+	// EnsureSidecar creates or updates the agent config used by the
+	// charm containers of the specified application in sidecar mode.
+	// The containers themselves are added to the pods by EnsureService.
+	EnsureSidecar(appName string, config *SidecarConfig) error
+
+	// SidecarExists indicates if the agent config used by the charm
+	// containers of the specified application exists.
+	SidecarExists(appName string) (bool, error)
+
 	// WatchUnits returns a watcher which notifies when there
 	// are changes to units of the specified application.
 	WatchUnits(appName string) (watcher.NotifyWatcher, error)
@@ -192,6 +258,15 @@ type Broker interface {
 	Upgrader
 }
 
+// HealthChecker is implemented by brokers that can cheaply check
+// their connection to the substrate.
+type HealthChecker interface {
+	// CheckHealth returns an error if the substrate API cannot be
+	// reached, or if the broker's credential no longer grants
+	// access to the resources it manages.
+	CheckHealth() error
+}
+
 // Upgrader provides the API to perform upgrades.
 type Upgrader interface {
 	// Upgrade sets the OCI image for the app to the specified version.
@@ -325,6 +400,11 @@ type OperatorConfig struct {
 	// OperatorImagePath is the docker registry URL for the image.
 	OperatorImagePath string
 
+	// OperatorImageUsername and OperatorImagePassword, if set, are the
+	// credentials used to pull the image from a private registry.
+	OperatorImageUsername string
+	OperatorImagePassword string
+
 	// Version is the Juju version of the operator image.
 	Version version.Number
 
@@ -337,4 +417,31 @@ type OperatorConfig struct {
 
 	// ResourceTags is a set of tags to set on the operator pod.
 	ResourceTags map[string]string
+
+	// CPU, if set, is the CPU to request for, and limit the operator
+	// pod to, as a quantity understood by the broker (eg "500m").
+	CPU string
+
+	// Memory, if set, is the memory to request for, and limit the
+	// operator pod to, as a quantity understood by the broker
+	// (eg "256Mi").
+	Memory string
+
+	// NodeSelector holds key=value node labels that nodes must have
+	// to run the operator.
+	NodeSelector []string
+
+	// NodeAffinity holds key=value1|value2 node labels that nodes must,
+	// or if the key is prefixed with ^ must not, have to run the
+	// operator.
+	NodeAffinity []string
+
+	// Tolerations holds the node taints, of the form
+	// key[=value][:effect], that the operator tolerates.
+	Tolerations []string
+
+	// Replicas is the number of operator pods to run. Only one of
+	// them, holding the operator lease, runs the operator at a time.
+	// If it is zero, one pod is run.
+	Replicas int
 }
@@ -7,6 +7,8 @@ import (
 	"github.com/juju/schema"
 	"gopkg.in/juju/environschema.v1"
 	core "k8s.io/api/core/v1"
+
+	"github.com/juju/juju/caas"
 )
 
 const (
@@ -28,6 +30,17 @@ const (
 	ingressSSLRedirectKey    = "kubernetes-ingress-ssl-redirect"
 	ingressSSLPassthroughKey = "kubernetes-ingress-ssl-passthrough"
 	ingressAllowHTTPKey      = "kubernetes-ingress-allow-http"
+
+	// OperatorStorageConfigKey overrides the model's operator-storage
+	// for the operator of a single application. It is only used when
+	// the operator is first deployed.
+	OperatorStorageConfigKey = "kubernetes-operator-storage"
+
+	// ProvisioningModeConfigKey determines whether the charm of an
+	// application runs in an operator pod or as a sidecar in each of
+	// the application's pods. It can only be set when the application
+	// is deployed.
+	ProvisioningModeConfigKey = "kubernetes-provisioning-mode"
 )
 
 var configFields = environschema.Fields{
@@ -86,15 +99,31 @@ var configFields = environschema.Fields{
 		Type:        environschema.Tbool,
 		Group:       environschema.ProviderGroup,
 	},
+	OperatorStorageConfigKey: {
+		Description: "storage class or pool for the operator's charm storage",
+		Type:        environschema.Tstring,
+		Group:       environschema.ProviderGroup,
+	},
+	ProvisioningModeConfigKey: {
+		Description: "whether the charm runs in an operator pod or alongside the workload in each pod",
+		Type:        environschema.Tstring,
+		Group:       environschema.ProviderGroup,
+		Values: []interface{}{
+			string(caas.ProvisioningModeOperator),
+			string(caas.ProvisioningModeSidecar),
+		},
+	},
 }
 
 var schemaDefaults = schema.Defaults{
-	ServiceTypeConfigKey:     schema.Omit,
-	serviceAnnotationsKey:    schema.Omit,
-	ingressClassKey:          defaultIngressClass,
-	ingressSSLRedirectKey:    defaultIngressSSLRedirect,
-	ingressSSLPassthroughKey: defaultIngressSSLPassthrough,
-	ingressAllowHTTPKey:      defaultIngressAllowHTTPKey,
+	ServiceTypeConfigKey:      schema.Omit,
+	serviceAnnotationsKey:     schema.Omit,
+	ingressClassKey:           defaultIngressClass,
+	ingressSSLRedirectKey:     defaultIngressSSLRedirect,
+	ingressSSLPassthroughKey:  defaultIngressSSLPassthrough,
+	ingressAllowHTTPKey:       defaultIngressAllowHTTPKey,
+	OperatorStorageConfigKey:  schema.Omit,
+	ProvisioningModeConfigKey: schema.Omit,
 }
 
 // ConfigSchema returns the configuration schema for
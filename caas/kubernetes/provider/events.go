@@ -4,6 +4,7 @@
 package provider
 
 import (
+	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 
 	core "k8s.io/api/core/v1"
@@ -27,6 +28,9 @@ const (
 	BackOffStartContainer   = "BackOff"
 	ExceededGracePeriod     = "ExceededGracePeriod"
 
+	// Probe event reason list
+	UnhealthyContainer = "Unhealthy"
+
 	// Pod event reason list
 	FailedToKillPod                = "FailedKillPod"
 	FailedToCreatePodContainer     = "FailedCreatePodContainer"
@@ -42,6 +46,32 @@ const (
 	BackOffPullImage        = "BackOff"
 )
 
+// Container state reasons below are copied from "k8s.io/kubernetes/pkg/kubelet"
+// for the same reason as the event reasons above.
+const (
+	ContainerErrImagePull             = "ErrImagePull"
+	ContainerImagePullBackOff         = "ImagePullBackOff"
+	ContainerInvalidImageName         = "InvalidImageName"
+	ContainerErrImageNeverPull        = "ErrImageNeverPull"
+	ContainerCrashLoopBackOff         = "CrashLoopBackOff"
+	ContainerCreateContainerConfigErr = "CreateContainerConfigError"
+	ContainerCreateContainerErr       = "CreateContainerError"
+	ContainerOOMKilled                = "OOMKilled"
+)
+
+// containerFailureReasons are the reasons for a container to be
+// waiting that indicate it is failing to run, rather than just
+// being slow to start.
+var containerFailureReasons = set.NewStrings(
+	ContainerErrImagePull,
+	ContainerImagePullBackOff,
+	ContainerInvalidImageName,
+	ContainerErrImageNeverPull,
+	ContainerCrashLoopBackOff,
+	ContainerCreateContainerConfigErr,
+	ContainerCreateContainerErr,
+)
+
 func (k *kubernetesClient) getEvents(objName string, objKind string) ([]core.Event, error) {
 	selector := fields.AndSelectors(
 		fields.OneTermEqualSelector("involvedObject.name", objName),
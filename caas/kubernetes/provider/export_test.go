@@ -22,6 +22,8 @@ import (
 var (
 	PrepareWorkloadSpec      = prepareWorkloadSpec
 	OperatorPod              = operatorPod
+	OperatorResources        = operatorResources
+	OperatorScheduling       = operatorScheduling
 	ExtractRegistryURL       = extractRegistryURL
 	CreateDockerConfigJSON   = createDockerConfigJSON
 	NewStorageConfig         = newStorageConfig
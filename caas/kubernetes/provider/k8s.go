@@ -73,6 +73,14 @@ const (
 
 	gpuAffinityNodeSelectorKey = "gpu"
 
+	// operatorContainerName is the name of the container running the
+	// operator in the operator pod.
+	operatorContainerName = "juju-operator"
+
+	// charmContainerName is the name of the container running the
+	// charm in each unit pod of an application in sidecar mode.
+	charmContainerName = "juju-charm"
+
 	annotationPrefix = "juju.io"
 
 	// OperatorPodIPEnvName is the environment name for operator pod IP.
@@ -97,6 +105,8 @@ var (
 	annotationControllerIsControllerKey = annotationPrefix + "/" + "is-controller"
 )
 
+var _ caas.HealthChecker = (*kubernetesClient)(nil)
+
 type kubernetesClient struct {
 	clock jujuclock.Clock
 
@@ -465,6 +475,20 @@ func (k *kubernetesClient) APIVersion() (string, error) {
 	return version, nil
 }
 
+// CheckHealth is part of the caas.HealthChecker interface.
+// It checks the cluster API server is reachable and that the
+// credential in use can still list pods in the model namespace.
+func (k *kubernetesClient) CheckHealth() error {
+	if _, err := k.APIVersion(); err != nil {
+		return errors.Annotate(err, "cannot reach cluster API server")
+	}
+	_, err := k.client().CoreV1().Pods(k.namespace).List(v1.ListOptions{Limit: 1})
+	if k8serrors.IsForbidden(err) || k8serrors.IsUnauthorized(err) {
+		return errors.Unauthorizedf("cannot list pods in namespace %q: %v", k.namespace, err)
+	}
+	return errors.Annotatef(err, "cannot list pods in namespace %q", k.namespace)
+}
+
 // ensureOCIImageSecret ensures a secret exists for use with retrieving images from private registries
 func (k *kubernetesClient) ensureOCIImageSecret(
 	imageSecretName,
@@ -633,6 +657,19 @@ func (k *kubernetesClient) EnsureOperator(appName, agentPath string, config *caa
 	annotations := resourceTagsToAnnotations(config.ResourceTags).
 		Add(labelVersion, config.Version.String())
 
+	var imageSecretName string
+	if config.OperatorImagePassword != "" {
+		imageSecretName = operatorImageSecretName(appName, operatorName)
+		imageDetails := &specs.ImageDetails{
+			ImagePath: config.OperatorImagePath,
+			Username:  config.OperatorImageUsername,
+			Password:  config.OperatorImagePassword,
+		}
+		if err := k.ensureOCIImageSecret(imageSecretName, appName, imageDetails, annotations.Copy()); err != nil {
+			return errors.Annotatef(err, "creating or updating image pull secret for %v operator", appName)
+		}
+	}
+
 	// Set up the parameters for creating charm storage.
 	operatorVolumeClaim := "charm"
 	if isLegacyName(operatorName) {
@@ -683,10 +720,23 @@ func (k *kubernetesClient) EnsureOperator(appName, agentPath string, config *caa
 	if err != nil {
 		return errors.Annotate(err, "generating operator podspec")
 	}
+	pod.Spec.Containers[0].Resources, err = operatorResources(config.CPU, config.Memory)
+	if err != nil {
+		return errors.Annotatef(err, "invalid resources for %v operator", appName)
+	}
+	if err := operatorScheduling(&pod.Spec, config); err != nil {
+		return errors.Annotatef(err, "invalid scheduling for %v operator", appName)
+	}
+	if imageSecretName != "" {
+		pod.Spec.ImagePullSecrets = []core.LocalObjectReference{{Name: imageSecretName}}
+	}
 	// Take a copy for use with statefulset.
 	podWithoutStorage := pod
 
 	numPods := int32(1)
+	if config.Replicas > 1 {
+		numPods = int32(config.Replicas)
+	}
 	logger.Debugf("using persistent volume claim for operator %s: %+v", appName, pvc)
 	statefulset := &apps.StatefulSet{
 		ObjectMeta: v1.ObjectMeta{
@@ -942,6 +992,87 @@ func (k *kubernetesClient) DeleteOperator(appName string) (err error) {
 	return errors.Trace(k.deleteDeployment(operatorName))
 }
 
+// ForceDeleteOperator deletes the specified operator's statefulset and
+// pods immediately, clearing any finalizers which would otherwise keep
+// them from being removed. It is used when a DeleteOperator call has
+// not removed the operator in good time.
+func (k *kubernetesClient) ForceDeleteOperator(appName string) error {
+	logger.Debugf("force deleting %s operator", appName)
+
+	operatorName := k.operatorName(appName)
+	gracePeriod := int64(0)
+	deleteOptions := &v1.DeleteOptions{
+		GracePeriodSeconds: &gracePeriod,
+		PropagationPolicy:  &defaultPropagationPolicy,
+	}
+
+	statefulSets := k.client().AppsV1().StatefulSets(k.namespace)
+	statefulSet, err := statefulSets.Get(operatorName, v1.GetOptions{IncludeUninitialized: true})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	if err == nil {
+		if len(statefulSet.Finalizers) > 0 {
+			statefulSet.Finalizers = nil
+			if _, err := statefulSets.Update(statefulSet); err != nil && !k8serrors.IsNotFound(err) {
+				return errors.Annotatef(err, "removing finalizers of %s operator", appName)
+			}
+		}
+		if err := statefulSets.Delete(operatorName, deleteOptions); err != nil && !k8serrors.IsNotFound(err) {
+			return errors.Trace(err)
+		}
+	}
+
+	pods := k.client().CoreV1().Pods(k.namespace)
+	podsList, err := pods.List(v1.ListOptions{
+		LabelSelector: operatorSelector(appName),
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, p := range podsList.Items {
+		if len(p.Finalizers) > 0 {
+			pod := p
+			pod.Finalizers = nil
+			if _, err := pods.Update(&pod); err != nil && !k8serrors.IsNotFound(err) {
+				return errors.Annotatef(err, "removing finalizers of %s operator pod %s", appName, p.Name)
+			}
+		}
+		if err := pods.Delete(p.Name, deleteOptions); err != nil && !k8serrors.IsNotFound(err) {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// EnsureSidecar creates or updates the agent config used by the charm
+// containers of the specified application in sidecar mode.
+func (k *kubernetesClient) EnsureSidecar(appName string, config *caas.SidecarConfig) error {
+	logger.Debugf("creating/updating %s charm container config", appName)
+
+	configMapName := charmConfigMapName(appName)
+	if config.AgentConf == nil {
+		// We expect that the config map already exists,
+		// so make sure it does.
+		_, err := k.getConfigMap(configMapName)
+		return errors.Annotatef(err, "config map for %q should already exist", appName)
+	}
+	err := k.ensureConfigMap(charmConfigMap(appName, configMapName, config))
+	return errors.Annotate(err, "creating or updating ConfigMap")
+}
+
+// SidecarExists indicates if the agent config used by the charm
+// containers of the specified application exists.
+func (k *kubernetesClient) SidecarExists(appName string) (bool, error) {
+	_, err := k.getConfigMap(charmConfigMapName(appName))
+	if errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Trace(err)
+	}
+	return true, nil
+}
+
 func getLoadBalancerAddress(svc *core.Service) string {
 	// different cloud providers have a different way to report back the Load Balancer address.
 	// This covers the cases we know about so far.
@@ -1094,6 +1225,9 @@ func (k *kubernetesClient) DeleteService(appName string) (err error) {
 	if err := k.deleteSecrets(appName); err != nil {
 		return errors.Trace(err)
 	}
+	if err := k.deleteConfigMap(charmConfigMapName(appName)); err != nil {
+		return errors.Trace(err)
+	}
 	if err := k.deleteServiceAccountsRolesBindings(appName); err != nil {
 		return errors.Trace(err)
 	}
@@ -1164,6 +1298,56 @@ func resourceTagsToAnnotations(in map[string]string) k8sannotations.Annotation {
 	return out
 }
 
+// nodeAffinityTerm returns a node selector term requiring nodes to have
+// labels matching the given key=value1|value2 pairs, or not to have them
+// if the key is prefixed with ^.
+func nodeAffinityTerm(affinityLabels []string) (core.NodeSelectorTerm, error) {
+	var (
+		affinityTags     = make(map[string]string)
+		antiAffinityTags = make(map[string]string)
+	)
+	for _, labelPair := range affinityLabels {
+		parts := strings.Split(labelPair, "=")
+		if len(parts) != 2 {
+			return core.NodeSelectorTerm{}, errors.Errorf("invalid node affinity constraints: %v", affinityLabels)
+		}
+		key := strings.Trim(parts[0], " ")
+		value := strings.Trim(parts[1], " ")
+		if strings.HasPrefix(key, "^") {
+			if len(key) == 1 {
+				return core.NodeSelectorTerm{}, errors.Errorf("invalid node affinity constraints: %v", affinityLabels)
+			}
+			antiAffinityTags[key[1:]] = value
+		} else {
+			affinityTags[key] = value
+		}
+	}
+
+	updateSelectorTerms := func(nodeSelectorTerm *core.NodeSelectorTerm, tags map[string]string, op core.NodeSelectorOperator) {
+		// Sort for stable ordering.
+		var keys []string
+		for k := range tags {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, tag := range keys {
+			allValues := strings.Split(tags[tag], "|")
+			for i, v := range allValues {
+				allValues[i] = strings.Trim(v, " ")
+			}
+			nodeSelectorTerm.MatchExpressions = append(nodeSelectorTerm.MatchExpressions, core.NodeSelectorRequirement{
+				Key:      tag,
+				Operator: op,
+				Values:   allValues,
+			})
+		}
+	}
+	var nodeSelectorTerm core.NodeSelectorTerm
+	updateSelectorTerms(&nodeSelectorTerm, affinityTags, core.NodeSelectorOpIn)
+	updateSelectorTerms(&nodeSelectorTerm, antiAffinityTags, core.NodeSelectorOpNotIn)
+	return nodeSelectorTerm, nil
+}
+
 func processConstraints(pod *core.PodSpec, appName string, cons constraints.Value) error {
 	// TODO(caas): Allow constraints to be set at the container level.
 	if mem := cons.Mem; mem != nil {
@@ -1179,50 +1363,10 @@ func processConstraints(pod *core.PodSpec, appName string, cons constraints.Valu
 
 	// Translate tags to node affinity.
 	if cons.Tags != nil {
-		affinityLabels := *cons.Tags
-		var (
-			affinityTags     = make(map[string]string)
-			antiAffinityTags = make(map[string]string)
-		)
-		for _, labelPair := range affinityLabels {
-			parts := strings.Split(labelPair, "=")
-			if len(parts) != 2 {
-				return errors.Errorf("invalid node affinity constraints: %v", affinityLabels)
-			}
-			key := strings.Trim(parts[0], " ")
-			value := strings.Trim(parts[1], " ")
-			if strings.HasPrefix(key, "^") {
-				if len(key) == 1 {
-					return errors.Errorf("invalid node affinity constraints: %v", affinityLabels)
-				}
-				antiAffinityTags[key[1:]] = value
-			} else {
-				affinityTags[key] = value
-			}
-		}
-
-		updateSelectorTerms := func(nodeSelectorTerm *core.NodeSelectorTerm, tags map[string]string, op core.NodeSelectorOperator) {
-			// Sort for stable ordering.
-			var keys []string
-			for k := range tags {
-				keys = append(keys, k)
-			}
-			sort.Strings(keys)
-			for _, tag := range keys {
-				allValues := strings.Split(tags[tag], "|")
-				for i, v := range allValues {
-					allValues[i] = strings.Trim(v, " ")
-				}
-				nodeSelectorTerm.MatchExpressions = append(nodeSelectorTerm.MatchExpressions, core.NodeSelectorRequirement{
-					Key:      tag,
-					Operator: op,
-					Values:   allValues,
-				})
-			}
+		nodeSelectorTerm, err := nodeAffinityTerm(*cons.Tags)
+		if err != nil {
+			return errors.Trace(err)
 		}
-		var nodeSelectorTerm core.NodeSelectorTerm
-		updateSelectorTerms(&nodeSelectorTerm, affinityTags, core.NodeSelectorOpIn)
-		updateSelectorTerms(&nodeSelectorTerm, antiAffinityTags, core.NodeSelectorOpNotIn)
 		pod.Affinity = &core.Affinity{
 			NodeAffinity: &core.NodeAffinity{
 				RequiredDuringSchedulingIgnoredDuringExecution: &core.NodeSelector{
@@ -1327,6 +1471,21 @@ func (k *kubernetesClient) EnsureService(
 
 	annotations := resourceTagsToAnnotations(params.ResourceTags)
 
+	if params.Sidecar != nil {
+		if err := addCharmContainer(&workloadSpec.Pod, appName, params.Sidecar); err != nil {
+			return errors.Annotatef(err, "adding charm container for %s", appName)
+		}
+		workloadSpec.CharmMounts = params.Sidecar.Mounts
+		if params.Sidecar.ImageDetails.Password != "" {
+			imageSecretName := appSecretName(deploymentName, charmContainerName)
+			if err := k.ensureOCIImageSecret(imageSecretName, appName, &params.Sidecar.ImageDetails, annotations.Copy()); err != nil {
+				return errors.Annotate(err, "creating secrets for charm container")
+			}
+			cleanups = append(cleanups, func() { k.deleteSecret(imageSecretName) })
+			workloadSpec.Pod.ImagePullSecrets = append(workloadSpec.Pod.ImagePullSecrets, core.LocalObjectReference{Name: imageSecretName})
+		}
+	}
+
 	for _, c := range params.PodSpec.Containers {
 		if c.ImageDetails.Password == "" {
 			continue
@@ -1511,6 +1670,7 @@ func (k *kubernetesClient) configureStorage(
 	randPrefix string,
 	legacy bool,
 	filesystems []storage.KubernetesFilesystemParams,
+	charmMounts []caas.SidecarMount,
 ) error {
 	baseDir, err := paths.StorageDir(CAASProviderType)
 	if err != nil {
@@ -1560,6 +1720,7 @@ func (k *kubernetesClient) configureStorage(
 				Name:      volName,
 				MountPath: mountPath,
 			})
+			mountCharmStorage(podSpec, charmMounts, volName, mountPath)
 			podSpec.Volumes = append(podSpec.Volumes, core.Volume{
 				Name:         volName,
 				VolumeSource: *volumeSource,
@@ -1598,10 +1759,32 @@ func (k *kubernetesClient) configureStorage(
 			Name:      pvc.Name,
 			MountPath: mountPath,
 		})
+		mountCharmStorage(podSpec, charmMounts, pvc.Name, mountPath)
 	}
 	return nil
 }
 
+// mountCharmStorage mounts the named volume in the charm container of
+// a pod in sidecar mode if the container mounts the filesystem mounted
+// at mountPath in the workload.
+func mountCharmStorage(podSpec *core.PodSpec, charmMounts []caas.SidecarMount, volName, mountPath string) {
+	for i, c := range podSpec.Containers {
+		if c.Name != charmContainerName {
+			continue
+		}
+		for _, m := range charmMounts {
+			if m.MountPoint != mountPath {
+				continue
+			}
+			podSpec.Containers[i].VolumeMounts = append(podSpec.Containers[i].VolumeMounts, core.VolumeMount{
+				Name:      volName,
+				MountPath: m.MountPoint,
+				ReadOnly:  m.ReadOnly,
+			})
+		}
+	}
+}
+
 func (k *kubernetesClient) configureDevices(unitSpec *workloadSpec, devices []devices.KubernetesDeviceParams) error {
 	for i := range unitSpec.Pod.Containers {
 		resources := unitSpec.Pod.Containers[i].Resources
@@ -1769,7 +1952,7 @@ func (k *kubernetesClient) configureStatefulSet(
 
 	// Create a new stateful set with the necessary storage config.
 	legacy := isLegacyName(deploymentName)
-	if err := k.configureStorage(&podSpec, &statefulset.Spec, appName, randPrefix, legacy, filesystems); err != nil {
+	if err := k.configureStorage(&podSpec, &statefulset.Spec, appName, randPrefix, legacy, filesystems, workloadSpec.CharmMounts); err != nil {
 		return errors.Annotatef(err, "configuring storage for %s", appName)
 	}
 	statefulset.Spec.Template.Spec = podSpec
@@ -2111,7 +2294,8 @@ func (k *kubernetesClient) WatchService(appName string) (watcher.NotifyWatcher,
 }
 
 // WatchOperator returns a watcher which notifies when there
-// are changes to the operator of the specified application.
+// are changes to the operator of the specified application,
+// or events are recorded against its pod.
 func (k *kubernetesClient) WatchOperator(appName string) (watcher.NotifyWatcher, error) {
 	pods := k.client().CoreV1().Pods(k.namespace)
 	w, err := pods.Watch(v1.ListOptions{
@@ -2121,7 +2305,21 @@ func (k *kubernetesClient) WatchOperator(appName string) (watcher.NotifyWatcher,
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	return k.newWatcher(w, appName, k.clock)
+	podWatcher, err := k.newWatcher(w, appName, k.clock)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// Failing probes and image pulls are recorded as events without
+	// necessarily changing the pod, so watch those as well. The operator
+	// is a single replica stateful set so its pod name is known.
+	podName := k.operatorName(appName) + "-0"
+	eventWatcher, err := k.watchEvents(podName, "Pod")
+	if err != nil {
+		podWatcher.Kill()
+		return nil, errors.Trace(err)
+	}
+	return watcher.NewMultiNotifyWatcher(podWatcher, eventWatcher), nil
 }
 
 // legacyJujuPVNameRegexp matches how Juju labels persistent volumes.
@@ -2370,6 +2568,18 @@ func (k *kubernetesClient) Operator(appName string) (*caas.Operator, error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	if !terminated {
+		// A failing operator container leaves the pod pending or running,
+		// which would otherwise be reported without any explanation.
+		problem, err := k.operatorPodProblem(opPod)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if problem != "" {
+			opStatus = status.Error
+			statusMessage = problem
+		}
+	}
 	return &caas.Operator{
 		Id:    string(opPod.UID),
 		Dying: terminated,
@@ -2413,6 +2623,55 @@ func (k *kubernetesClient) getPODStatus(pod core.Pod, now time.Time) (string, st
 	return statusMessage, jujuStatus, since, nil
 }
 
+// operatorPodProblem returns a message describing why the operator pod is
+// unable to run properly, or an empty string if there is no such problem.
+// Images that can't be pulled, containers that keep crashing or being
+// killed for using too much memory, and failing probes are reported.
+func (k *kubernetesClient) operatorPodProblem(pod core.Pod) (string, error) {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if waiting := cs.State.Waiting; waiting != nil && containerFailureReasons.Contains(waiting.Reason) {
+			// For a crash loop, why the container last stopped is
+			// more useful than the back-off message.
+			if last := cs.LastTerminationState.Terminated; last != nil && last.Reason != "" {
+				return fmt.Sprintf("%s: last terminated with %s (exit code %d)", waiting.Reason, last.Reason, last.ExitCode), nil
+			}
+			if waiting.Message != "" {
+				return fmt.Sprintf("%s: %s", waiting.Reason, waiting.Message), nil
+			}
+			return waiting.Reason, nil
+		}
+		if terminated := cs.State.Terminated; terminated != nil && terminated.Reason == ContainerOOMKilled {
+			return fmt.Sprintf("%s (exit code %d)", terminated.Reason, terminated.ExitCode), nil
+		}
+	}
+
+	if pod.Status.Phase != core.PodRunning || podReady(pod) {
+		return "", nil
+	}
+	// The containers are running but the pod isn't ready, so check
+	// whether that is down to a failing probe.
+	eventList, err := k.getEvents(pod.Name, "Pod")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if count := len(eventList); count > 0 {
+		evt := eventList[count-1]
+		if evt.Type == core.EventTypeWarning && evt.Reason == UnhealthyContainer {
+			return evt.Message, nil
+		}
+	}
+	return "", nil
+}
+
+func podReady(pod core.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == core.PodReady {
+			return cond.Status == core.ConditionTrue
+		}
+	}
+	return false
+}
+
 func (k *kubernetesClient) getStatefulSetStatus(ss *apps.StatefulSet) (string, status.Status, error) {
 	terminated := ss.DeletionTimestamp != nil
 	jujuStatus := status.Waiting
@@ -2579,7 +2838,7 @@ func operatorPod(podName, appName, operatorServiceIP, agentPath, operatorImagePa
 		},
 		Spec: core.PodSpec{
 			Containers: []core.Container{{
-				Name:            "juju-operator",
+				Name:            operatorContainerName,
 				ImagePullPolicy: core.PullIfNotPresent,
 				Image:           operatorImagePath,
 				WorkingDir:      jujuDataDir,
@@ -2631,6 +2890,118 @@ func operatorPod(podName, appName, operatorServiceIP, agentPath, operatorImagePa
 	}, nil
 }
 
+// operatorResources returns the resources to request for, and limit,
+// the operator container to. Either of cpu and memory may be empty, in
+// which case no request or limit is set for that resource.
+func operatorResources(cpu, memory string) (core.ResourceRequirements, error) {
+	var result core.ResourceRequirements
+	for name, value := range map[core.ResourceName]string{
+		core.ResourceCPU:    cpu,
+		core.ResourceMemory: memory,
+	} {
+		if value == "" {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return core.ResourceRequirements{}, errors.NotValidf("%s %q", name, value)
+		}
+		if result.Requests == nil {
+			result.Requests = core.ResourceList{}
+			result.Limits = core.ResourceList{}
+		}
+		result.Requests[name] = quantity
+		result.Limits[name] = quantity
+	}
+	return result, nil
+}
+
+// operatorScheduling constrains the nodes the operator pod may be
+// scheduled on as specified in the operator config.
+func operatorScheduling(pod *core.PodSpec, config *caas.OperatorConfig) (err error) {
+	if pod.NodeSelector, err = operatorNodeSelector(config.NodeSelector); err != nil {
+		return errors.Trace(err)
+	}
+	if pod.Affinity, err = operatorAffinity(config.NodeAffinity); err != nil {
+		return errors.Trace(err)
+	}
+	if pod.Tolerations, err = operatorTolerations(config.Tolerations); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// operatorNodeSelector returns the node selector for the given
+// key=value node labels.
+func operatorNodeSelector(labels []string) (map[string]string, error) {
+	if len(labels) == 0 {
+		return nil, nil
+	}
+	selector := make(map[string]string)
+	for _, label := range labels {
+		parts := strings.SplitN(label, "=", 2)
+		key := strings.TrimSpace(parts[0])
+		if len(parts) != 2 || key == "" {
+			return nil, errors.NotValidf("node selector %q", label)
+		}
+		selector[key] = strings.TrimSpace(parts[1])
+	}
+	return selector, nil
+}
+
+// operatorAffinity returns the node affinity for the given node labels,
+// which are interpreted as for the tags constraint.
+func operatorAffinity(labels []string) (*core.Affinity, error) {
+	if len(labels) == 0 {
+		return nil, nil
+	}
+	nodeSelectorTerm, err := nodeAffinityTerm(labels)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &core.Affinity{
+		NodeAffinity: &core.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &core.NodeSelector{
+				NodeSelectorTerms: []core.NodeSelectorTerm{nodeSelectorTerm},
+			},
+		},
+	}, nil
+}
+
+// operatorTolerations returns the tolerations for the given node taints,
+// each of the form key[=value][:effect]. A taint without a value is
+// tolerated whatever its value, and one without an effect is tolerated
+// whatever its effect.
+func operatorTolerations(taints []string) ([]core.Toleration, error) {
+	var tolerations []core.Toleration
+	for _, taint := range taints {
+		var toleration core.Toleration
+		spec := taint
+		if i := strings.LastIndex(spec, ":"); i >= 0 {
+			toleration.Effect = core.TaintEffect(strings.TrimSpace(spec[i+1:]))
+			spec = spec[:i]
+		}
+		switch toleration.Effect {
+		case "", core.TaintEffectNoSchedule, core.TaintEffectPreferNoSchedule, core.TaintEffectNoExecute:
+		default:
+			return nil, errors.NotValidf("toleration %q effect", taint)
+		}
+		parts := strings.SplitN(spec, "=", 2)
+		toleration.Key = strings.TrimSpace(parts[0])
+		if toleration.Key == "" {
+			return nil, errors.NotValidf("toleration %q without key", taint)
+		}
+		if len(parts) == 2 {
+			toleration.Operator = core.TolerationOpEqual
+			toleration.Value = strings.TrimSpace(parts[1])
+		} else {
+			toleration.Operator = core.TolerationOpExists
+		}
+		tolerations = append(tolerations, toleration)
+	}
+	return tolerations, nil
+}
+
 // operatorConfigMap returns a *core.ConfigMap for the operator pod
 // of the specified application, with the specified configuration.
 func operatorConfigMap(appName, operatorName string, config *caas.OperatorConfig) *core.ConfigMap {
@@ -2645,12 +3016,103 @@ func operatorConfigMap(appName, operatorName string, config *caas.OperatorConfig
 	}
 }
 
+// charmConfigMap returns the config map holding the agent config used
+// by the charm containers of an application in sidecar mode.
+func charmConfigMap(appName, configMapName string, config *caas.SidecarConfig) *core.ConfigMap {
+	return &core.ConfigMap{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        configMapName,
+			Labels:      map[string]string{labelApplication: appName},
+			Annotations: resourceTagsToAnnotations(config.ResourceTags).ToMap(),
+		},
+		Data: map[string]string{
+			appName + "-agent.conf": string(config.AgentConf),
+		},
+	}
+}
+
+// addCharmContainer adds the container running the charm of an
+// application in sidecar mode, and the volumes it uses, to a pod.
+func addCharmContainer(pod *core.PodSpec, appName string, sidecar *caas.SidecarParams) error {
+	configVolName := charmConfigMapName(appName)
+	dataVolName := charmContainerName + "-data"
+
+	appTag := names.NewApplicationTag(appName)
+	jujudCmd := fmt.Sprintf("$JUJU_TOOLS_DIR/jujud caasoperator --application-name=%s --debug", appName)
+	jujuDataDir, err := paths.DataDir("kubernetes")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	pod.Containers = append(pod.Containers, core.Container{
+		Name:            charmContainerName,
+		ImagePullPolicy: core.PullIfNotPresent,
+		Image:           sidecar.ImageDetails.ImagePath,
+		WorkingDir:      jujuDataDir,
+		Command: []string{
+			"/bin/sh",
+		},
+		Args: []string{
+			"-c",
+			fmt.Sprintf(
+				caas.JujudStartUpSh,
+				jujuDataDir,
+				"tools",
+				jujudCmd,
+			),
+		},
+		Env: []core.EnvVar{
+			{Name: "JUJU_APPLICATION", Value: appName},
+			{
+				Name: OperatorPodIPEnvName,
+				ValueFrom: &core.EnvVarSource{
+					FieldRef: &core.ObjectFieldSelector{
+						FieldPath: "status.podIP",
+					},
+				},
+			},
+		},
+		VolumeMounts: []core.VolumeMount{{
+			Name:      dataVolName,
+			MountPath: agent.BaseDir(jujuDataDir),
+		}, {
+			Name:      configVolName,
+			MountPath: filepath.Join(agent.Dir(jujuDataDir, appTag), TemplateFileNameAgentConf),
+			SubPath:   TemplateFileNameAgentConf,
+		}},
+	})
+	// The charm's state lives as long as the pod does.
+	pod.Volumes = append(pod.Volumes, core.Volume{
+		Name: dataVolName,
+		VolumeSource: core.VolumeSource{
+			EmptyDir: &core.EmptyDirVolumeSource{},
+		},
+	}, core.Volume{
+		Name: configVolName,
+		VolumeSource: core.VolumeSource{
+			ConfigMap: &core.ConfigMapVolumeSource{
+				LocalObjectReference: core.LocalObjectReference{
+					Name: configVolName,
+				},
+				Items: []core.KeyToPath{{
+					Key:  appName + "-agent.conf",
+					Path: TemplateFileNameAgentConf,
+				}},
+			},
+		},
+	})
+	return nil
+}
+
 type workloadSpec struct {
 	Pod     core.PodSpec `json:"pod"`
 	Service *specs.ServiceSpec
 
 	ServiceAccount            *specs.ServiceAccountSpec
 	CustomResourceDefinitions map[string]apiextensionsv1beta1.CustomResourceDefinitionSpec
+
+	// CharmMounts are the filesystems mounted in the charm
+	// container of a pod in sidecar mode.
+	CharmMounts []caas.SidecarMount
 }
 
 func processContainers(deploymentName string, podSpec *specs.PodSpec, spec *core.PodSpec) error {
@@ -2834,6 +3296,10 @@ func operatorConfigMapName(operatorName string) string {
 	return operatorName + "-config"
 }
 
+func charmConfigMapName(appName string) string {
+	return appName + "-" + charmContainerName + "-config"
+}
+
 func applicationConfigMapName(deploymentName, fileSetName string) string {
 	return fmt.Sprintf("%v-%v-config", deploymentName, fileSetName)
 }
@@ -2843,6 +3309,17 @@ func appSecretName(deploymentName, containerName string) string {
 	return deploymentName + "-" + containerName + "-secret"
 }
 
+// operatorImageSecretName returns the name of the secret used to pull
+// the operator image. It is named for the operator container, so that
+// it is removed along with the operator.
+func operatorImageSecretName(appName, operatorName string) string {
+	deploymentName := appName
+	if isLegacyName(operatorName) {
+		deploymentName = "juju-" + appName
+	}
+	return appSecretName(deploymentName, operatorContainerName)
+}
+
 func qualifiedStorageClassName(namespace, storageClass string) string {
 	if namespace == "" {
 		return storageClass
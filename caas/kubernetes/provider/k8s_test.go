@@ -473,6 +473,82 @@ func (s *K8sSuite) TestOperatorPodConfig(c *gc.C) {
 	c.Assert(podEnv["JUJU_OPERATOR_SERVICE_IP"], gc.Equals, "10666")
 }
 
+func (s *K8sSuite) TestOperatorResources(c *gc.C) {
+	resources, err := provider.OperatorResources("", "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resources, jc.DeepEquals, core.ResourceRequirements{})
+
+	resources, err = provider.OperatorResources("500m", "256Mi")
+	c.Assert(err, jc.ErrorIsNil)
+	expected := core.ResourceList{
+		core.ResourceCPU:    resource.MustParse("500m"),
+		core.ResourceMemory: resource.MustParse("256Mi"),
+	}
+	c.Assert(resources, jc.DeepEquals, core.ResourceRequirements{
+		Requests: expected,
+		Limits:   expected,
+	})
+
+	_, err = provider.OperatorResources("", "lots")
+	c.Assert(err, gc.ErrorMatches, `memory "lots" not valid`)
+}
+
+func (s *K8sSuite) TestOperatorScheduling(c *gc.C) {
+	var pod core.PodSpec
+	err := provider.OperatorScheduling(&pod, &caas.OperatorConfig{
+		NodeSelector: []string{"pool=controllers"},
+		NodeAffinity: []string{"zone=a|b", "^gpu=true"},
+		Tolerations:  []string{"dedicated=juju:NoSchedule", "maintenance"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pod.NodeSelector, jc.DeepEquals, map[string]string{"pool": "controllers"})
+	c.Assert(pod.Affinity, jc.DeepEquals, &core.Affinity{
+		NodeAffinity: &core.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &core.NodeSelector{
+				NodeSelectorTerms: []core.NodeSelectorTerm{{
+					MatchExpressions: []core.NodeSelectorRequirement{{
+						Key:      "zone",
+						Operator: core.NodeSelectorOpIn,
+						Values:   []string{"a", "b"},
+					}, {
+						Key:      "gpu",
+						Operator: core.NodeSelectorOpNotIn,
+						Values:   []string{"true"},
+					}},
+				}},
+			},
+		},
+	})
+	c.Assert(pod.Tolerations, jc.DeepEquals, []core.Toleration{{
+		Key:      "dedicated",
+		Operator: core.TolerationOpEqual,
+		Value:    "juju",
+		Effect:   core.TaintEffectNoSchedule,
+	}, {
+		Key:      "maintenance",
+		Operator: core.TolerationOpExists,
+	}})
+}
+
+func (s *K8sSuite) TestOperatorSchedulingNone(c *gc.C) {
+	var pod core.PodSpec
+	err := provider.OperatorScheduling(&pod, &caas.OperatorConfig{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pod, jc.DeepEquals, core.PodSpec{})
+}
+
+func (s *K8sSuite) TestOperatorSchedulingInvalid(c *gc.C) {
+	var pod core.PodSpec
+	err := provider.OperatorScheduling(&pod, &caas.OperatorConfig{
+		NodeSelector: []string{"pool"},
+	})
+	c.Assert(err, gc.ErrorMatches, `node selector "pool" not valid`)
+	err = provider.OperatorScheduling(&pod, &caas.OperatorConfig{
+		Tolerations: []string{"dedicated=juju:Sometimes"},
+	})
+	c.Assert(err, gc.ErrorMatches, `toleration "dedicated=juju:Sometimes" effect not valid`)
+}
+
 type K8sBrokerSuite struct {
 	BaseSuite
 }
@@ -491,6 +567,17 @@ func (s *K8sBrokerSuite) TestAPIVersion(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `get /path/version: unsupported protocol scheme ""`)
 }
 
+func (s *K8sBrokerSuite) TestCheckHealthUnreachable(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	r := rest.NewRequest(nil, "get", &url.URL{Path: "/path/"}, "", rest.ContentConfig{}, rest.Serializers{}, nil, nil, 0)
+	s.mockRestClient.EXPECT().Get().Times(1).Return(r)
+
+	err := s.broker.CheckHealth()
+	c.Assert(err, gc.ErrorMatches, `cannot reach cluster API server: get /path/version: unsupported protocol scheme ""`)
+}
+
 func (s *K8sBrokerSuite) TestConfig(c *gc.C) {
 	ctrl := s.setupController(c)
 	defer ctrl.Finish()
@@ -570,6 +657,59 @@ func (s *K8sBrokerSuite) setupOperatorStorageConfig(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *K8sBrokerSuite) TestSetConfigOperatorResources(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	cfg, err := s.broker.Config().Apply(map[string]interface{}{
+		"operator-cpu":    "500m",
+		"operator-memory": "256Mi",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.broker.SetConfig(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+
+	cfg, err = s.broker.Config().Apply(map[string]interface{}{"operator-memory": "lots"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.broker.SetConfig(cfg)
+	c.Assert(err, gc.ErrorMatches, `operator-memory "lots" not valid`)
+}
+
+func (s *K8sBrokerSuite) TestSetConfigOperatorScheduling(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	cfg, err := s.broker.Config().Apply(map[string]interface{}{
+		"operator-node-selector": "pool=controllers",
+		"operator-node-affinity": "zone=a|b, ^gpu=true",
+		"operator-tolerations":   "dedicated=juju:NoSchedule",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.broker.SetConfig(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+
+	cfg, err = s.broker.Config().Apply(map[string]interface{}{"operator-tolerations": ":NoSchedule"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.broker.SetConfig(cfg)
+	c.Assert(err, gc.ErrorMatches, `invalid operator-tolerations: toleration ":NoSchedule" without key not valid`)
+}
+
+func (s *K8sBrokerSuite) TestSetConfigOperatorReplicas(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	c.Assert(s.broker.Config().AllAttrs()["operator-replicas"], gc.Equals, 1)
+	cfg, err := s.broker.Config().Apply(map[string]interface{}{"operator-replicas": 3})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.broker.SetConfig(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+
+	cfg, err = s.broker.Config().Apply(map[string]interface{}{"operator-replicas": 0})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.broker.SetConfig(cfg)
+	c.Assert(err, gc.ErrorMatches, `operator-replicas 0 not valid`)
+}
+
 func (s *K8sBrokerSuite) TestPrepareForBootstrap(c *gc.C) {
 	ctrl := s.setupController(c)
 	defer ctrl.Finish()
@@ -822,6 +962,53 @@ func (s *K8sBrokerSuite) TestDeleteOperator(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *K8sBrokerSuite) TestForceDeleteOperator(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	gracePeriod := int64(0)
+	forceDeleteOptions := s.deleteOptions(v1.DeletePropagationForeground, nil)
+	forceDeleteOptions.GracePeriodSeconds = &gracePeriod
+
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: v1.ObjectMeta{
+			Name:       "test-operator",
+			Finalizers: []string{"example.com/stuck"},
+		},
+	}
+	unstuckStatefulSet := &appsv1.StatefulSet{
+		ObjectMeta: v1.ObjectMeta{Name: "test-operator"},
+	}
+	pod := core.Pod{
+		ObjectMeta: v1.ObjectMeta{
+			Name:       "test-operator-0",
+			Finalizers: []string{"example.com/stuck"},
+		},
+	}
+	unstuckPod := core.Pod{
+		ObjectMeta: v1.ObjectMeta{Name: "test-operator-0"},
+	}
+	gomock.InOrder(
+		s.mockStatefulSets.EXPECT().Get("juju-operator-test", v1.GetOptions{IncludeUninitialized: true}).Times(1).
+			Return(nil, s.k8sNotFoundError()),
+		s.mockStatefulSets.EXPECT().Get("test-operator", v1.GetOptions{IncludeUninitialized: true}).Times(1).
+			Return(statefulSet, nil),
+		s.mockStatefulSets.EXPECT().Update(unstuckStatefulSet).Times(1).
+			Return(unstuckStatefulSet, nil),
+		s.mockStatefulSets.EXPECT().Delete("test-operator", forceDeleteOptions).Times(1).
+			Return(nil),
+		s.mockPods.EXPECT().List(v1.ListOptions{LabelSelector: "juju-operator==test"}).
+			Return(&core.PodList{Items: []core.Pod{pod}}, nil),
+		s.mockPods.EXPECT().Update(&unstuckPod).Times(1).
+			Return(&unstuckPod, nil),
+		s.mockPods.EXPECT().Delete("test-operator-0", forceDeleteOptions).Times(1).
+			Return(s.k8sNotFoundError()),
+	)
+
+	err := s.broker.ForceDeleteOperator("test")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func operatorStatefulSetArg(numUnits int32, scName string) *appsv1.StatefulSet {
 	return &appsv1.StatefulSet{
 		ObjectMeta: v1.ObjectMeta{
@@ -968,6 +1155,79 @@ func (s *K8sBrokerSuite) TestEnsureOperator(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *K8sBrokerSuite) TestEnsureOperatorImagePullSecret(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	imagePath := "registry.foo.com/me/jujud-operator:2.99.0"
+	secretData, err := provider.CreateDockerConfigJSON(&specs.ImageDetails{
+		ImagePath: imagePath,
+		Username:  "fred",
+		Password:  "secret",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	secretArg := &core.Secret{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "test-juju-operator-secret",
+			Namespace: "test",
+			Labels:    map[string]string{"juju-app": "test"},
+			Annotations: map[string]string{
+				"juju-version": "2.99.0",
+				"fred":         "mary",
+			},
+		},
+		Type: "kubernetes.io/dockerconfigjson",
+		Data: map[string][]byte{".dockerconfigjson": secretData},
+	}
+
+	podSpec := operatorPodspec
+	podSpec.Containers = []core.Container{operatorPodspec.Containers[0]}
+	podSpec.Containers[0].Image = imagePath
+	podSpec.ImagePullSecrets = []core.LocalObjectReference{{Name: "test-juju-operator-secret"}}
+	statefulSetArg := operatorStatefulSetArg(1, "test-operator-storage")
+	statefulSetArg.Spec.Template.Spec = podSpec
+
+	gomock.InOrder(
+		s.mockStatefulSets.EXPECT().Get("juju-operator-test", v1.GetOptions{IncludeUninitialized: true}).Times(1).
+			Return(nil, s.k8sNotFoundError()),
+		s.mockServices.EXPECT().Get("test-operator", v1.GetOptions{IncludeUninitialized: true}).Times(1).
+			Return(nil, s.k8sNotFoundError()),
+		s.mockServices.EXPECT().Update(operatorServiceArg).Times(1).
+			Return(nil, s.k8sNotFoundError()),
+		s.mockServices.EXPECT().Create(operatorServiceArg).Times(1).
+			Return(nil, nil),
+		s.mockServices.EXPECT().Get("test-operator", v1.GetOptions{IncludeUninitialized: false}).Times(1).
+			Return(&core.Service{Spec: core.ServiceSpec{ClusterIP: "10.1.2.3"}}, nil),
+		s.mockConfigMaps.EXPECT().Get("test-operator-config", v1.GetOptions{IncludeUninitialized: true}).Times(1).
+			Return(nil, nil),
+		s.mockSecrets.EXPECT().Update(secretArg).Times(1).
+			Return(nil, s.k8sNotFoundError()),
+		s.mockSecrets.EXPECT().Create(secretArg).Times(1).
+			Return(nil, nil),
+		s.mockStorageClass.EXPECT().Get("test-operator-storage", v1.GetOptions{IncludeUninitialized: false}).Times(1).
+			Return(&storagev1.StorageClass{ObjectMeta: v1.ObjectMeta{Name: "test-operator-storage"}}, nil),
+		s.mockStatefulSets.EXPECT().Update(statefulSetArg).Times(1).
+			Return(nil, s.k8sNotFoundError()),
+		s.mockStatefulSets.EXPECT().Create(statefulSetArg).Times(1).
+			Return(nil, nil),
+	)
+
+	err = s.broker.EnsureOperator("test", "path/to/agent", &caas.OperatorConfig{
+		OperatorImagePath:     imagePath,
+		OperatorImageUsername: "fred",
+		OperatorImagePassword: "secret",
+		Version:               version.MustParse("2.99.0"),
+		ResourceTags:          map[string]string{"fred": "mary"},
+		CharmStorage: caas.CharmStorageParams{
+			Size:         uint64(10),
+			Provider:     "kubernetes",
+			Attributes:   map[string]interface{}{"storage-class": "operator-storage"},
+			ResourceTags: map[string]string{"foo": "bar"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *K8sBrokerSuite) TestEnsureOperatorNoAgentConfig(c *gc.C) {
 	ctrl := s.setupController(c)
 	defer ctrl.Finish()
@@ -1008,6 +1268,47 @@ func (s *K8sBrokerSuite) TestEnsureOperatorNoAgentConfig(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *K8sBrokerSuite) TestEnsureOperatorReplicas(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	statefulSetArg := operatorStatefulSetArg(3, "test-operator-storage")
+	gomock.InOrder(
+		s.mockStatefulSets.EXPECT().Get("juju-operator-test", v1.GetOptions{IncludeUninitialized: true}).Times(1).
+			Return(nil, s.k8sNotFoundError()),
+		s.mockServices.EXPECT().Get("test-operator", v1.GetOptions{IncludeUninitialized: true}).Times(1).
+			Return(nil, s.k8sNotFoundError()),
+		s.mockServices.EXPECT().Update(operatorServiceArg).Times(1).
+			Return(nil, s.k8sNotFoundError()),
+		s.mockServices.EXPECT().Create(operatorServiceArg).Times(1).
+			Return(nil, nil),
+		s.mockServices.EXPECT().Get("test-operator", v1.GetOptions{IncludeUninitialized: false}).Times(1).
+			Return(&core.Service{Spec: core.ServiceSpec{ClusterIP: "10.1.2.3"}}, nil),
+		s.mockConfigMaps.EXPECT().Get("test-operator-config", v1.GetOptions{IncludeUninitialized: true}).Times(1).
+			Return(nil, nil),
+		s.mockStorageClass.EXPECT().Get("test-operator-storage", v1.GetOptions{IncludeUninitialized: false}).Times(1).
+			Return(&storagev1.StorageClass{ObjectMeta: v1.ObjectMeta{Name: "test-operator-storage"}}, nil),
+		s.mockStatefulSets.EXPECT().Update(statefulSetArg).Times(1).
+			Return(nil, s.k8sNotFoundError()),
+		s.mockStatefulSets.EXPECT().Create(statefulSetArg).Times(1).
+			Return(nil, nil),
+	)
+
+	err := s.broker.EnsureOperator("test", "path/to/agent", &caas.OperatorConfig{
+		OperatorImagePath: "/path/to/image",
+		Version:           version.MustParse("2.99.0"),
+		ResourceTags:      map[string]string{"fred": "mary"},
+		CharmStorage: caas.CharmStorageParams{
+			Size:         uint64(10),
+			Provider:     "kubernetes",
+			Attributes:   map[string]interface{}{"storage-class": "operator-storage"},
+			ResourceTags: map[string]string{"foo": "bar"},
+		},
+		Replicas: 3,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *K8sBrokerSuite) TestEnsureOperatorNoAgentConfigMissingConfigMap(c *gc.C) {
 	ctrl := s.setupController(c)
 	defer ctrl.Finish()
@@ -1040,6 +1341,67 @@ func (s *K8sBrokerSuite) TestEnsureOperatorNoAgentConfigMissingConfigMap(c *gc.C
 	c.Assert(err, gc.ErrorMatches, `config map for "test" should already exist:  "test" not found`)
 }
 
+func (s *K8sBrokerSuite) TestEnsureSidecar(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	configMapArg := &core.ConfigMap{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        "test-juju-charm-config",
+			Labels:      map[string]string{"juju-app": "test"},
+			Annotations: map[string]string{"fred": "mary"},
+		},
+		Data: map[string]string{
+			"test-agent.conf": "agent-conf-data",
+		},
+	}
+	gomock.InOrder(
+		s.mockConfigMaps.EXPECT().Update(configMapArg).Times(1).
+			Return(nil, s.k8sNotFoundError()),
+		s.mockConfigMaps.EXPECT().Create(configMapArg).Times(1).
+			Return(configMapArg, nil),
+	)
+
+	err := s.broker.EnsureSidecar("test", &caas.SidecarConfig{
+		AgentConf:    []byte("agent-conf-data"),
+		ResourceTags: map[string]string{"fred": "mary"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *K8sBrokerSuite) TestEnsureSidecarNoAgentConfMissingConfigMap(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	gomock.InOrder(
+		s.mockConfigMaps.EXPECT().Get("test-juju-charm-config", v1.GetOptions{IncludeUninitialized: true}).Times(1).
+			Return(nil, s.k8sNotFoundError()),
+	)
+
+	err := s.broker.EnsureSidecar("test", &caas.SidecarConfig{})
+	c.Assert(err, gc.ErrorMatches, `config map for "test" should already exist: configmap "test-juju-charm-config" not found`)
+}
+
+func (s *K8sBrokerSuite) TestSidecarExists(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	gomock.InOrder(
+		s.mockConfigMaps.EXPECT().Get("test-juju-charm-config", v1.GetOptions{IncludeUninitialized: true}).Times(1).
+			Return(&core.ConfigMap{}, nil),
+		s.mockConfigMaps.EXPECT().Get("test-juju-charm-config", v1.GetOptions{IncludeUninitialized: true}).Times(1).
+			Return(nil, s.k8sNotFoundError()),
+	)
+
+	exists, err := s.broker.SidecarExists("test")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(exists, jc.IsTrue)
+
+	exists, err = s.broker.SidecarExists("test")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(exists, jc.IsFalse)
+}
+
 func (s *K8sBrokerSuite) TestDeleteServiceForApplication(c *gc.C) {
 	ctrl := s.setupController(c)
 	defer ctrl.Finish()
@@ -1062,6 +1424,8 @@ func (s *K8sBrokerSuite) TestDeleteServiceForApplication(c *gc.C) {
 			}}}, nil),
 		s.mockSecrets.EXPECT().Delete("secret", s.deleteOptions(v1.DeletePropagationForeground, nil)).Times(1).
 			Return(s.k8sNotFoundError()),
+		s.mockConfigMaps.EXPECT().Delete("test-juju-charm-config", s.deleteOptions(v1.DeletePropagationForeground, nil)).Times(1).
+			Return(s.k8sNotFoundError()),
 		s.mockRoleBindings.EXPECT().DeleteCollection(
 			s.deleteOptions(v1.DeletePropagationForeground, nil),
 			v1.ListOptions{LabelSelector: "juju-app==test,juju-model==test", IncludeUninitialized: true},
@@ -2696,6 +3060,155 @@ func (s *K8sBrokerSuite) TestOperator(c *gc.C) {
 	c.Assert(operator.Status.Message, gc.Equals, "test message.")
 }
 
+func (s *K8sBrokerSuite) TestOperatorImagePullBackOff(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	opPod := core.Pod{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "test-operator-0",
+		},
+		Status: core.PodStatus{
+			Phase: core.PodPending,
+			ContainerStatuses: []core.ContainerStatus{{
+				Name: "juju-operator",
+				State: core.ContainerState{
+					Waiting: &core.ContainerStateWaiting{
+						Reason:  "ImagePullBackOff",
+						Message: `Back-off pulling image "jujud-operator:2.7"`,
+					},
+				},
+			}},
+		},
+	}
+	gomock.InOrder(
+		s.mockPods.EXPECT().List(v1.ListOptions{LabelSelector: "juju-operator==test"}).Times(1).
+			Return(&core.PodList{Items: []core.Pod{opPod}}, nil),
+		s.mockEvents.EXPECT().List(v1.ListOptions{
+			IncludeUninitialized: true,
+			FieldSelector:        "involvedObject.name=test-operator-0,involvedObject.kind=Pod",
+		}).Times(1).
+			Return(&core.EventList{}, nil),
+	)
+
+	operator, err := s.broker.Operator("test")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(operator.Status.Status, gc.Equals, status.Error)
+	c.Assert(operator.Status.Message, gc.Equals, `ImagePullBackOff: Back-off pulling image "jujud-operator:2.7"`)
+}
+
+func (s *K8sBrokerSuite) TestOperatorCrashLoopOOMKilled(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	opPod := core.Pod{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "test-operator-0",
+		},
+		Status: core.PodStatus{
+			Phase:   core.PodRunning,
+			Message: "running",
+			ContainerStatuses: []core.ContainerStatus{{
+				Name: "juju-operator",
+				State: core.ContainerState{
+					Waiting: &core.ContainerStateWaiting{
+						Reason:  "CrashLoopBackOff",
+						Message: "Back-off 5m0s restarting failed container",
+					},
+				},
+				LastTerminationState: core.ContainerState{
+					Terminated: &core.ContainerStateTerminated{
+						Reason:   "OOMKilled",
+						ExitCode: 137,
+					},
+				},
+			}},
+		},
+	}
+	gomock.InOrder(
+		s.mockPods.EXPECT().List(v1.ListOptions{LabelSelector: "juju-operator==test"}).Times(1).
+			Return(&core.PodList{Items: []core.Pod{opPod}}, nil),
+	)
+
+	operator, err := s.broker.Operator("test")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(operator.Status.Status, gc.Equals, status.Error)
+	c.Assert(operator.Status.Message, gc.Equals, "CrashLoopBackOff: last terminated with OOMKilled (exit code 137)")
+}
+
+func (s *K8sBrokerSuite) TestOperatorProbeFailure(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	opPod := core.Pod{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "test-operator-0",
+		},
+		Status: core.PodStatus{
+			Phase:   core.PodRunning,
+			Message: "running",
+			Conditions: []core.PodCondition{{
+				Type:   core.PodReady,
+				Status: core.ConditionFalse,
+			}},
+		},
+	}
+	gomock.InOrder(
+		s.mockPods.EXPECT().List(v1.ListOptions{LabelSelector: "juju-operator==test"}).Times(1).
+			Return(&core.PodList{Items: []core.Pod{opPod}}, nil),
+		s.mockEvents.EXPECT().List(v1.ListOptions{
+			IncludeUninitialized: true,
+			FieldSelector:        "involvedObject.name=test-operator-0,involvedObject.kind=Pod",
+		}).Times(1).
+			Return(&core.EventList{Items: []core.Event{{
+				Type:    core.EventTypeNormal,
+				Reason:  provider.StartedContainer,
+				Message: "Started container",
+			}, {
+				Type:    core.EventTypeWarning,
+				Reason:  provider.UnhealthyContainer,
+				Message: "Liveness probe failed: connection refused",
+			}}}, nil),
+	)
+
+	operator, err := s.broker.Operator("test")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(operator.Status.Status, gc.Equals, status.Error)
+	c.Assert(operator.Status.Message, gc.Equals, "Liveness probe failed: connection refused")
+}
+
+func (s *K8sBrokerSuite) TestOperatorRunningReady(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	opPod := core.Pod{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "test-operator-0",
+		},
+		Status: core.PodStatus{
+			Phase:   core.PodRunning,
+			Message: "running",
+			Conditions: []core.PodCondition{{
+				Type:   core.PodReady,
+				Status: core.ConditionTrue,
+			}},
+		},
+	}
+	gomock.InOrder(
+		s.mockPods.EXPECT().List(v1.ListOptions{LabelSelector: "juju-operator==test"}).Times(1).
+			Return(&core.PodList{Items: []core.Pod{opPod}}, nil),
+	)
+
+	operator, err := s.broker.Operator("test")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(operator.Status.Status, gc.Equals, status.Running)
+	c.Assert(operator.Status.Message, gc.Equals, "running")
+}
+
 func (s *K8sBrokerSuite) TestOperatorNoPodFound(c *gc.C) {
 	ctrl := s.setupController(c)
 	defer ctrl.Finish()
@@ -2747,6 +3260,45 @@ func (s *K8sBrokerSuite) TestWatchService(c *gc.C) {
 	}
 }
 
+func (s *K8sBrokerSuite) TestWatchOperator(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	podWatcher := watch.NewRaceFreeFake()
+	eventWatcher := watch.NewRaceFreeFake()
+
+	gomock.InOrder(
+		s.mockPods.EXPECT().Watch(v1.ListOptions{
+			LabelSelector: "juju-operator==test",
+			Watch:         true,
+		}).Return(podWatcher, nil),
+		s.mockStatefulSets.EXPECT().Get("juju-operator-test", v1.GetOptions{IncludeUninitialized: true}).
+			Return(nil, s.k8sNotFoundError()),
+		s.mockEvents.EXPECT().Watch(v1.ListOptions{
+			FieldSelector: "involvedObject.name=test-operator-0,involvedObject.kind=Pod",
+			Watch:         true,
+		}).Return(eventWatcher, nil),
+	)
+
+	w, err := s.broker.WatchOperator("test")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// An event recorded against the operator pod fires the watcher.
+	go func(w *watch.RaceFreeFakeWatcher, clk *testclock.Clock) {
+		if !w.IsStopped() {
+			clk.WaitAdvance(time.Second, testing.ShortWait, 1)
+			w.Action(provider.UnhealthyContainer, nil)
+		}
+	}(eventWatcher, s.clock)
+
+	select {
+	case _, ok := <-w.Changes():
+		c.Assert(ok, jc.IsTrue)
+	case <-time.After(testing.LongWait):
+		c.Fatal("timed out waiting for event")
+	}
+}
+
 func (s *K8sBrokerSuite) TestUpgradeController(c *gc.C) {
 	ctrl := s.setupController(c)
 	defer ctrl.Finish()
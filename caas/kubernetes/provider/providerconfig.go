@@ -5,9 +5,12 @@ package provider
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/juju/errors"
 	"github.com/juju/schema"
 	"gopkg.in/juju/environschema.v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	"github.com/juju/juju/environs/config"
 )
@@ -15,6 +18,14 @@ import (
 const (
 	WorkloadStorageKey = "workload-storage"
 	OperatorStorageKey = "operator-storage"
+	OperatorCPUKey     = "operator-cpu"
+	OperatorMemoryKey  = "operator-memory"
+
+	OperatorNodeSelectorKey = "operator-node-selector"
+	OperatorNodeAffinityKey = "operator-node-affinity"
+	OperatorTolerationsKey  = "operator-tolerations"
+
+	OperatorReplicasKey = "operator-replicas"
 )
 
 var configSchema = environschema.Fields{
@@ -29,6 +40,36 @@ var configSchema = environschema.Fields{
 		Group:       environschema.AccountGroup,
 		Immutable:   true,
 	},
+	OperatorCPUKey: {
+		Description: "The CPU requested for, and available to, each operator pod, eg 500m.",
+		Type:        environschema.Tstring,
+		Group:       environschema.AccountGroup,
+	},
+	OperatorMemoryKey: {
+		Description: "The memory requested for, and available to, each operator pod, eg 256Mi.",
+		Type:        environschema.Tstring,
+		Group:       environschema.AccountGroup,
+	},
+	OperatorNodeSelectorKey: {
+		Description: "A comma separated list of key=value node labels that nodes must have to run operator pods.",
+		Type:        environschema.Tstring,
+		Group:       environschema.AccountGroup,
+	},
+	OperatorNodeAffinityKey: {
+		Description: "A comma separated list of key=value1|value2 node labels that nodes must (or, if the key is prefixed with ^, must not) have to run operator pods.",
+		Type:        environschema.Tstring,
+		Group:       environschema.AccountGroup,
+	},
+	OperatorTolerationsKey: {
+		Description: "A comma separated list of key[=value][:effect] node taints that operator pods tolerate.",
+		Type:        environschema.Tstring,
+		Group:       environschema.AccountGroup,
+	},
+	OperatorReplicasKey: {
+		Description: "The number of operator pods to run for each application. One of them, elected by the controller, runs the application's hooks; the others take over if it fails. Only one is run if the controller uses legacy leases.",
+		Type:        environschema.Tint,
+		Group:       environschema.AccountGroup,
+	},
 }
 
 var providerConfigFields = func() schema.Fields {
@@ -42,6 +83,14 @@ var providerConfigFields = func() schema.Fields {
 var providerConfigDefaults = schema.Defaults{
 	WorkloadStorageKey: "",
 	OperatorStorageKey: "",
+	OperatorCPUKey:     "",
+	OperatorMemoryKey:  "",
+
+	OperatorNodeSelectorKey: "",
+	OperatorNodeAffinityKey: "",
+	OperatorTolerationsKey:  "",
+
+	OperatorReplicasKey: 1,
 }
 
 type brokerConfig struct {
@@ -94,6 +143,36 @@ func (p kubernetesEnvironProvider) ConfigDefaults() schema.Defaults {
 	return providerConfigDefaults
 }
 
+// SplitConfigList returns the trimmed, non-empty items of the comma
+// separated list held in a config value.
+func SplitConfigList(value string) []string {
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// validateOperatorScheduling checks that the operator pod scheduling
+// config values can be interpreted.
+func validateOperatorScheduling(attrs map[string]interface{}) error {
+	value, _ := attrs[OperatorNodeSelectorKey].(string)
+	if _, err := operatorNodeSelector(SplitConfigList(value)); err != nil {
+		return errors.Annotatef(err, "invalid %s", OperatorNodeSelectorKey)
+	}
+	value, _ = attrs[OperatorNodeAffinityKey].(string)
+	if _, err := operatorAffinity(SplitConfigList(value)); err != nil {
+		return errors.Annotatef(err, "invalid %s", OperatorNodeAffinityKey)
+	}
+	value, _ = attrs[OperatorTolerationsKey].(string)
+	if _, err := operatorTolerations(SplitConfigList(value)); err != nil {
+		return errors.Annotatef(err, "invalid %s", OperatorTolerationsKey)
+	}
+	return nil
+}
+
 func validateConfig(cfg, old *config.Config) (*brokerConfig, error) {
 	// Check for valid changes for the base config values.
 	if err := config.Validate(cfg, old); err != nil {
@@ -104,6 +183,22 @@ func validateConfig(cfg, old *config.Config) (*brokerConfig, error) {
 		return nil, err
 	}
 
+	for _, key := range []string{OperatorCPUKey, OperatorMemoryKey} {
+		value, _ := validated[key].(string)
+		if value == "" {
+			continue
+		}
+		if _, err := resource.ParseQuantity(value); err != nil {
+			return nil, errors.NotValidf("%s %q", key, value)
+		}
+	}
+	if err := validateOperatorScheduling(validated); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if replicas, _ := validated[OperatorReplicasKey].(int); replicas < 1 {
+		return nil, errors.NotValidf("%s %d", OperatorReplicasKey, replicas)
+	}
+
 	bcfg := &brokerConfig{cfg, validated}
 	return bcfg, nil
 }
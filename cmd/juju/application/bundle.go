@@ -1039,7 +1039,7 @@ func (h *bundleHandler) exposeApplication(change *bundlechanges.ExposeChange) er
 	}
 
 	application := resolve(change.Params.Application, h.results)
-	if err := h.api.Expose(application); err != nil {
+	if err := h.api.Expose(application, nil); err != nil {
 		return errors.Annotatef(err, "cannot expose application %s", application)
 	}
 	return nil
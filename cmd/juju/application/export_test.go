@@ -159,6 +159,22 @@ func NewResolvedCommandForTest(applicationResolveAPI applicationResolveAPI, clie
 	return modelcmd.Wrap(cmd)
 }
 
+// NewRotateUnitPasswordCommandForTest returns a rotateUnitPasswordCommand
+// with the api provided as specified.
+func NewRotateUnitPasswordCommandForTest(api rotateUnitPasswordAPI, store jujuclient.ClientStore) modelcmd.ModelCommand {
+	cmd := &rotateUnitPasswordCommand{api: api}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+// NewSetSubordinatePolicyCommandForTest returns a
+// setSubordinatePolicyCommand with the api provided as specified.
+func NewSetSubordinatePolicyCommandForTest(api setSubordinatePolicyAPI, store jujuclient.ClientStore) modelcmd.ModelCommand {
+	cmd := &setSubordinatePolicyCommand{api: api}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
 // NewAddUnitCommandForTest returns an AddUnitCommand with the api provided as specified.
 func NewAddUnitCommandForTest(api applicationAddUnitAPI, store jujuclient.ClientStore) modelcmd.ModelCommand {
 	cmd := &addUnitCommand{api: api}
@@ -4,10 +4,14 @@
 package application
 
 import (
+	"net"
+
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
 
 	"github.com/juju/juju/api/application"
+	"github.com/juju/juju/apiserver/params"
 	jujucmd "github.com/juju/juju/cmd"
 	"github.com/juju/juju/cmd/juju/block"
 	"github.com/juju/juju/cmd/modelcmd"
@@ -20,8 +24,17 @@ var usageExposeDetails = `
 Adjusts the firewall rules and any relevant security mechanisms of the
 cloud to allow public access to the application.
 
+By default, every endpoint of the application may be reached from
+anywhere, or from the model's ingress whitelist if one is set. The
+--to-spaces and --to-cidrs options instead limit access to the
+subnets of the given spaces and to the given CIDRs. Use --endpoints
+to apply these settings to specific endpoints only; settings for
+other endpoints are left unchanged.
+
 Examples:
     juju expose wordpress
+    juju expose wordpress --to-cidrs 10.0.0.0/24,192.168.1.0/24
+    juju expose mysql --endpoints db --to-spaces internal
 
 See also: 
     unexpose`[1:]
@@ -35,6 +48,10 @@ func NewExposeCommand() modelcmd.ModelCommand {
 type exposeCommand struct {
 	modelcmd.ModelCommandBase
 	ApplicationName string
+
+	Endpoints []string
+	ToSpaces  []string
+	ToCIDRs   []string
 }
 
 func (c *exposeCommand) Info() *cmd.Info {
@@ -46,17 +63,52 @@ func (c *exposeCommand) Info() *cmd.Info {
 	})
 }
 
+func (c *exposeCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.Var(cmd.NewStringsValue(nil, &c.Endpoints), "endpoints", "Expose only the given comma-separated endpoints")
+	f.Var(cmd.NewStringsValue(nil, &c.ToSpaces), "to-spaces", "Allow access from the subnets of the given comma-separated spaces")
+	f.Var(cmd.NewStringsValue(nil, &c.ToCIDRs), "to-cidrs", "Allow access from the given comma-separated CIDRs")
+}
+
 func (c *exposeCommand) Init(args []string) error {
 	if len(args) == 0 {
 		return errors.New("no application name specified")
 	}
 	c.ApplicationName = args[0]
+	for _, cidr := range c.ToCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return errors.NotValidf("CIDR %q", cidr)
+		}
+	}
 	return cmd.CheckEmpty(args[1:])
 }
 
+// exposedEndpoints returns the expose settings requested on the
+// command line, or nil if the whole application should be exposed
+// with the default settings.
+func (c *exposeCommand) exposedEndpoints() map[string]params.ExposedEndpoint {
+	if len(c.Endpoints) == 0 && len(c.ToSpaces) == 0 && len(c.ToCIDRs) == 0 {
+		return nil
+	}
+	exposed := params.ExposedEndpoint{
+		ExposeToSpaces: c.ToSpaces,
+		ExposeToCIDRs:  c.ToCIDRs,
+	}
+	endpoints := c.Endpoints
+	if len(endpoints) == 0 {
+		// The empty endpoint name refers to every endpoint.
+		endpoints = []string{""}
+	}
+	result := make(map[string]params.ExposedEndpoint, len(endpoints))
+	for _, endpoint := range endpoints {
+		result[endpoint] = exposed
+	}
+	return result
+}
+
 type applicationExposeAPI interface {
 	Close() error
-	Expose(applicationName string) error
+	Expose(applicationName string, exposedEndpoints map[string]params.ExposedEndpoint) error
 	Unexpose(applicationName string) error
 }
 
@@ -76,5 +128,5 @@ func (c *exposeCommand) Run(_ *cmd.Context) error {
 		return err
 	}
 	defer client.Close()
-	return block.ProcessBlockedError(client.Expose(c.ApplicationName), block.BlockChange)
+	return block.ProcessBlockedError(client.Expose(c.ApplicationName, c.exposedEndpoints()), block.BlockChange)
 }
@@ -0,0 +1,95 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/api/application"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+const rotateUnitPasswordDoc = `
+The agents of the specified units are given new passwords. The machine
+agent hosting each unit writes the new password to the unit agent's
+configuration, sets it on the controller, and restarts the unit agent.
+The rotation happens asynchronously, and is retried until it succeeds.
+
+Examples:
+
+    juju rotate-unit-password mysql/0
+    juju rotate-unit-password mysql/0 wordpress/1
+
+See also:
+    remove-unit
+`
+
+// NewRotateUnitPasswordCommand returns a command which requests that
+// unit agents be given new passwords.
+func NewRotateUnitPasswordCommand() cmd.Command {
+	return modelcmd.Wrap(&rotateUnitPasswordCommand{})
+}
+
+// rotateUnitPasswordCommand requests that unit agents be given new
+// passwords.
+type rotateUnitPasswordCommand struct {
+	modelcmd.ModelCommandBase
+	api rotateUnitPasswordAPI
+
+	UnitNames []string
+}
+
+type rotateUnitPasswordAPI interface {
+	Close() error
+	RotateUnitPasswords(units ...string) error
+}
+
+// Info implements cmd.Command.
+func (c *rotateUnitPasswordCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "rotate-unit-password",
+		Args:    "<unit> [...]",
+		Purpose: "Gives unit agents new passwords.",
+		Doc:     rotateUnitPasswordDoc,
+	})
+}
+
+// Init implements cmd.Command.
+func (c *rotateUnitPasswordCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.Errorf("no unit specified")
+	}
+	for _, u := range args {
+		if !names.IsValidUnit(u) {
+			return errors.NotValidf("unit name %q", u)
+		}
+	}
+	c.UnitNames = args
+	return nil
+}
+
+func (c *rotateUnitPasswordCommand) getAPI() (rotateUnitPasswordAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return application.NewClient(root), nil
+}
+
+// Run implements cmd.Command.
+func (c *rotateUnitPasswordCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+	return block.ProcessBlockedError(api.RotateUnitPasswords(c.UnitNames...), block.BlockChange)
+}
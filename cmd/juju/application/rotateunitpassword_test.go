@@ -0,0 +1,70 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application_test
+
+import (
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/application"
+	"github.com/juju/juju/jujuclient/jujuclienttesting"
+)
+
+type RotateUnitPasswordSuite struct {
+	testing.IsolationSuite
+
+	mockAPI *mockRotateUnitPasswordAPI
+}
+
+var _ = gc.Suite(&RotateUnitPasswordSuite{})
+
+func (s *RotateUnitPasswordSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.mockAPI = &mockRotateUnitPasswordAPI{}
+}
+
+func (s *RotateUnitPasswordSuite) run(c *gc.C, args ...string) error {
+	store := jujuclienttesting.MinimalStore()
+	cmd := application.NewRotateUnitPasswordCommandForTest(s.mockAPI, store)
+	_, err := cmdtesting.RunCommand(c, cmd, args...)
+	return err
+}
+
+func (s *RotateUnitPasswordSuite) TestInit(c *gc.C) {
+	err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, "no unit specified")
+	err = s.run(c, "mysql")
+	c.Assert(err, gc.ErrorMatches, `unit name "mysql" not valid`)
+	s.mockAPI.CheckNoCalls(c)
+}
+
+func (s *RotateUnitPasswordSuite) TestRotate(c *gc.C) {
+	err := s.run(c, "mysql/0", "wordpress/1")
+	c.Assert(err, jc.ErrorIsNil)
+	s.mockAPI.CheckCallNames(c, "RotateUnitPasswords", "Close")
+	s.mockAPI.CheckCall(c, 0, "RotateUnitPasswords", []string{"mysql/0", "wordpress/1"})
+}
+
+func (s *RotateUnitPasswordSuite) TestRotateError(c *gc.C) {
+	s.mockAPI.SetErrors(errors.New("boom"))
+	err := s.run(c, "mysql/0")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+type mockRotateUnitPasswordAPI struct {
+	testing.Stub
+}
+
+func (m *mockRotateUnitPasswordAPI) Close() error {
+	m.MethodCall(m, "Close")
+	return nil
+}
+
+func (m *mockRotateUnitPasswordAPI) RotateUnitPasswords(units ...string) error {
+	m.MethodCall(m, "RotateUnitPasswords", units)
+	return m.NextErr()
+}
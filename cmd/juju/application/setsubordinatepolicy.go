@@ -0,0 +1,130 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/utils/keyvalues"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/api/application"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+const setSubordinatePolicyDoc = `
+The placement policy of a subordinate application restricts which
+principal units it attaches to when a container-scoped relation is
+established. Only subordinate units created after the policy is set
+are affected; existing subordinate units are left in place.
+
+Running the command without any options removes the policy, so that
+the subordinate attaches to every related principal unit.
+
+Examples:
+
+    juju set-subordinate-policy logging --principals mysql,wordpress
+    juju set-subordinate-policy logging --machine-selector logging=enabled
+    juju set-subordinate-policy logging --max-per-machine 1
+    juju set-subordinate-policy logging
+
+See also:
+    add-relation
+    set-annotations
+`
+
+// NewSetSubordinatePolicyCommand returns a command which sets the
+// placement policy of a subordinate application.
+func NewSetSubordinatePolicyCommand() cmd.Command {
+	return modelcmd.Wrap(&setSubordinatePolicyCommand{})
+}
+
+// setSubordinatePolicyCommand sets the placement policy of a
+// subordinate application.
+type setSubordinatePolicyCommand struct {
+	modelcmd.ModelCommandBase
+	api setSubordinatePolicyAPI
+
+	ApplicationName string
+	Principals      []string
+	MachineSelector map[string]string
+	MaxPerMachine   int
+
+	selector []string
+}
+
+type setSubordinatePolicyAPI interface {
+	Close() error
+	SetSubordinatePolicy(application string, principals []string, machineSelector map[string]string, maxPerMachine int) error
+}
+
+// Info implements cmd.Command.
+func (c *setSubordinatePolicyCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "set-subordinate-policy",
+		Args:    "<application>",
+		Purpose: "Restricts the principal units a subordinate application attaches to.",
+		Doc:     setSubordinatePolicyDoc,
+	})
+}
+
+// SetFlags implements cmd.Command.
+func (c *setSubordinatePolicyCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.Var(cmd.NewStringsValue(nil, &c.Principals), "principals", "Attach only to units of the given comma-separated applications")
+	f.Var(cmd.NewStringsValue(nil, &c.selector), "machine-selector", "Attach only on machines with the given comma-separated key=value annotations")
+	f.IntVar(&c.MaxPerMachine, "max-per-machine", 0, "Attach at most this many units to the principals on any one machine")
+}
+
+// Init implements cmd.Command.
+func (c *setSubordinatePolicyCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no application name specified")
+	}
+	if !names.IsValidApplication(args[0]) {
+		return errors.NotValidf("application name %q", args[0])
+	}
+	c.ApplicationName = args[0]
+	for _, name := range c.Principals {
+		if !names.IsValidApplication(name) {
+			return errors.NotValidf("principal application name %q", name)
+		}
+	}
+	if len(c.selector) > 0 {
+		selector, err := keyvalues.Parse(c.selector, false)
+		if err != nil {
+			return errors.Annotate(err, "invalid machine selector")
+		}
+		c.MachineSelector = selector
+	}
+	if c.MaxPerMachine < 0 {
+		return errors.NotValidf("negative max per machine %d", c.MaxPerMachine)
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *setSubordinatePolicyCommand) getAPI() (setSubordinatePolicyAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return application.NewClient(root), nil
+}
+
+// Run implements cmd.Command.
+func (c *setSubordinatePolicyCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+	err = api.SetSubordinatePolicy(c.ApplicationName, c.Principals, c.MachineSelector, c.MaxPerMachine)
+	return block.ProcessBlockedError(err, block.BlockChange)
+}
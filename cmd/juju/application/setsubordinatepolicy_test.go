@@ -0,0 +1,105 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application_test
+
+import (
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/application"
+	"github.com/juju/juju/jujuclient/jujuclienttesting"
+)
+
+type SetSubordinatePolicySuite struct {
+	testing.IsolationSuite
+
+	mockAPI *mockSetSubordinatePolicyAPI
+}
+
+var _ = gc.Suite(&SetSubordinatePolicySuite{})
+
+func (s *SetSubordinatePolicySuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.mockAPI = &mockSetSubordinatePolicyAPI{}
+}
+
+func (s *SetSubordinatePolicySuite) run(c *gc.C, args ...string) error {
+	store := jujuclienttesting.MinimalStore()
+	cmd := application.NewSetSubordinatePolicyCommandForTest(s.mockAPI, store)
+	_, err := cmdtesting.RunCommand(c, cmd, args...)
+	return err
+}
+
+func (s *SetSubordinatePolicySuite) TestInit(c *gc.C) {
+	for _, t := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "no application name specified",
+	}, {
+		args: []string{"Bad_Name"},
+		err:  `application name "Bad_Name" not valid`,
+	}, {
+		args: []string{"logging", "--principals", "mysql,Bad_Name"},
+		err:  `principal application name "Bad_Name" not valid`,
+	}, {
+		args: []string{"logging", "--machine-selector", "nokey"},
+		err:  `invalid machine selector: .*`,
+	}, {
+		args: []string{"logging", "--max-per-machine", "-1"},
+		err:  `negative max per machine -1 not valid`,
+	}, {
+		args: []string{"logging", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("%v", t.args)
+		err := s.run(c, t.args...)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+	s.mockAPI.CheckNoCalls(c)
+}
+
+func (s *SetSubordinatePolicySuite) TestSetPolicy(c *gc.C) {
+	err := s.run(c, "logging",
+		"--principals", "mysql,wordpress",
+		"--machine-selector", "logging=enabled",
+		"--max-per-machine", "1",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	s.mockAPI.CheckCallNames(c, "SetSubordinatePolicy", "Close")
+	s.mockAPI.CheckCall(c, 0, "SetSubordinatePolicy",
+		"logging", []string{"mysql", "wordpress"}, map[string]string{"logging": "enabled"}, 1,
+	)
+}
+
+func (s *SetSubordinatePolicySuite) TestRemovePolicy(c *gc.C) {
+	err := s.run(c, "logging")
+	c.Assert(err, jc.ErrorIsNil)
+	s.mockAPI.CheckCall(c, 0, "SetSubordinatePolicy",
+		"logging", []string(nil), map[string]string(nil), 0,
+	)
+}
+
+func (s *SetSubordinatePolicySuite) TestSetPolicyError(c *gc.C) {
+	s.mockAPI.SetErrors(errors.New("boom"))
+	err := s.run(c, "logging", "--max-per-machine", "1")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+type mockSetSubordinatePolicyAPI struct {
+	testing.Stub
+}
+
+func (m *mockSetSubordinatePolicyAPI) Close() error {
+	m.MethodCall(m, "Close")
+	return nil
+}
+
+func (m *mockSetSubordinatePolicyAPI) SetSubordinatePolicy(application string, principals []string, machineSelector map[string]string, maxPerMachine int) error {
+	m.MethodCall(m, "SetSubordinatePolicy", application, principals, machineSelector, maxPerMachine)
+	return m.NextErr()
+}
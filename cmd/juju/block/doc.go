@@ -41,6 +41,7 @@ Commands that can be disabled are grouped based on logical operations as follows
     expose
     import-filesystem
     import-ssh-key
+    import-volume
     model-defaults
     model-config
     reload-spaces
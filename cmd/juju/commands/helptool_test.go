@@ -54,6 +54,7 @@ Currently available charm hook tools are:
     close-port               ensure a port or range is always closed
     config-get               print application configuration
     credential-get           access cloud credentials
+    endpoint-addresses       print the addresses of the units related on an endpoint
     goal-state               print the status of the charm's peers and related units
     is-leader                print application leadership status
     juju-log                 write a message to the juju log
@@ -95,6 +96,7 @@ var expectedCommands = []string{
 	"close-port",
 	"config-get",
 	"credential-get",
+	"endpoint-addresses",
 	"goal-state",
 	"is-leader",
 	"juju-log",
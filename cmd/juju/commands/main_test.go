@@ -496,6 +496,7 @@ var commandNames = []string{
 	"hook-tools",
 	"import-filesystem",
 	"import-ssh-key",
+	"import-volume",
 	"kill-controller",
 	"list-actions",
 	"list-agreements",
@@ -562,6 +563,7 @@ var commandNames = []string{
 	"retry-provisioning",
 	"revoke",
 	"revoke-cloud",
+	"rotate-unit-password",
 	"run",
 	"scale-application",
 	"scp",
@@ -574,6 +576,7 @@ var commandNames = []string{
 	"set-model-constraints",
 	"set-plan",
 	"set-series",
+	"set-subordinate-policy",
 	"set-wallet",
 	"show-action",
 	"show-application",
@@ -5,6 +5,7 @@ package commands
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
@@ -13,6 +14,7 @@ import (
 	"gopkg.in/juju/names.v3"
 
 	"github.com/juju/juju/api/modelconfig"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cloudconfig/podcfg"
 	jujucmd "github.com/juju/juju/cmd"
 	"github.com/juju/juju/cmd/modelcmd"
@@ -46,6 +48,10 @@ The command will abort if an upgrade is in progress. It will also abort if
 a previous upgrade was not fully completed (e.g.: if one of the
 controllers in a high availability model failed to upgrade).
 
+With '--dry-run', the controller's pre-upgrade checks are run and any
+problems that would block the upgrade, such as insufficient disk space
+or an unsupported version of MongoDB, are reported. Nothing is changed.
+
 Examples:
     juju upgrade-controller --dry-run
     juju upgrade-controller --agent-version 2.0.1
@@ -64,9 +70,15 @@ type upgradeControllerCommand struct {
 	baseUpgradeCommand
 
 	upgradeJujuAPI upgradeJujuAPI
+	preCheckAPI    preUpgradeCheckAPI
 	rawArgs        []string
 }
 
+type preUpgradeCheckAPI interface {
+	UpgradePreChecks() ([]params.UpgradePreCheckProblem, error)
+	Close() error
+}
+
 func (c *upgradeControllerCommand) Info() *cmd.Info {
 	return jujucmd.Info(&cmd.Info{
 		Name:    "upgrade-controller",
@@ -112,6 +124,14 @@ func (c *upgradeControllerCommand) getControllerAPI() (controllerAPI, error) {
 	return c.NewControllerAPIClient()
 }
 
+func (c *upgradeControllerCommand) getPreUpgradeCheckAPI() (preUpgradeCheckAPI, error) {
+	if c.preCheckAPI != nil {
+		return c.preCheckAPI, nil
+	}
+
+	return c.NewControllerAPIClient()
+}
+
 func (c *upgradeControllerCommand) Run(ctx *cmd.Context) (err error) {
 	controllerName, err := c.ControllerName()
 	if err != nil {
@@ -127,12 +147,55 @@ func (c *upgradeControllerCommand) Run(ctx *cmd.Context) (err error) {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if c.DryRun {
+		if err := c.reportPreUpgradeChecks(ctx); err != nil {
+			return errors.Trace(err)
+		}
+	}
 	if details.ModelType == model.CAAS {
 		return c.upgradeCAASController(ctx)
 	}
 	return c.upgradeIAASController(ctx)
 }
 
+// reportPreUpgradeChecks reports the problems found by the checks the
+// controller runs before upgrading. Blocking problems will cause the
+// upgrade to be aborted.
+func (c *upgradeControllerCommand) reportPreUpgradeChecks(ctx *cmd.Context) error {
+	client, err := c.getPreUpgradeCheckAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+	problems, err := client.UpgradePreChecks()
+	if errors.IsNotSupported(err) {
+		ctx.Verbosef("pre-upgrade checks not supported by this controller")
+		return nil
+	} else if err != nil {
+		return errors.Annotate(err, "running pre-upgrade checks")
+	}
+	if len(problems) == 0 {
+		ctx.Verbosef("no problems found by pre-upgrade checks")
+		return nil
+	}
+	var blockers, warnings []string
+	for _, problem := range problems {
+		line := fmt.Sprintf("    %s: %s\n", problem.Check, problem.Message)
+		if problem.Blocking {
+			blockers = append(blockers, line)
+		} else {
+			warnings = append(warnings, line)
+		}
+	}
+	if len(blockers) > 0 {
+		fmt.Fprintf(ctx.Stderr, "upgrade blocked by:\n%s", strings.Join(blockers, ""))
+	}
+	if len(warnings) > 0 {
+		fmt.Fprintf(ctx.Stderr, "upgrade warnings:\n%s", strings.Join(warnings, ""))
+	}
+	return nil
+}
+
 func (c *upgradeControllerCommand) upgradeCAASController(ctx *cmd.Context) error {
 	if c.BuildAgent {
 		return errors.NotSupportedf("--build-agent for k8s controller upgrades")
@@ -17,6 +17,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/cmd/modelcmd"
 	"github.com/juju/juju/core/model"
@@ -166,6 +167,53 @@ func (s *UpgradeIAASControllerSuite) TestUpgradeDryRun(c *gc.C) {
 	s.assertUpgradeDryRun(c, "upgrade-controller", s.upgradeControllerCommand)
 }
 
+func (s *UpgradeIAASControllerSuite) TestUpgradeDryRunReportsPreCheckProblems(c *gc.C) {
+	s.setUpEnvAndTools(c, "2.0.0-quantal-amd64", "2.0.0", []string{"2.1.3-quantal-amd64"})
+	preCheckAPI := &fakePreUpgradeCheckAPI{
+		problems: []params.UpgradePreCheckProblem{{
+			Check:    "mongo-version",
+			Message:  "mongo 2.4.6 is too old, at least 3.2 is required",
+			Blocking: true,
+		}, {
+			Check:   "deprecated-config",
+			Message: `controller config "max-prune-txn-passes" is deprecated`,
+		}},
+	}
+	cmd := &upgradeControllerCommand{
+		baseUpgradeCommand: baseUpgradeCommand{minMajorUpgradeVersion: minMajorUpgradeVersion},
+		preCheckAPI:        preCheckAPI,
+	}
+	cmd.SetClientStore(s.ControllerStore)
+
+	ctx, err := cmdtesting.RunCommand(c, modelcmd.WrapController(cmd), "--dry-run")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, `
+upgrade blocked by:
+    mongo-version: mongo 2.4.6 is too old, at least 3.2 is required
+upgrade warnings:
+    deprecated-config: controller config "max-prune-txn-passes" is deprecated
+best version:
+    2.1.3
+upgrade to this version by running
+    juju upgrade-controller
+`[1:])
+	c.Assert(preCheckAPI.closed, jc.IsTrue)
+}
+
+type fakePreUpgradeCheckAPI struct {
+	problems []params.UpgradePreCheckProblem
+	closed   bool
+}
+
+func (a *fakePreUpgradeCheckAPI) UpgradePreChecks() ([]params.UpgradePreCheckProblem, error) {
+	return a.problems, nil
+}
+
+func (a *fakePreUpgradeCheckAPI) Close() error {
+	a.closed = true
+	return nil
+}
+
 type UpgradeCAASControllerSuite struct {
 	UpgradeBaseSuite
 }
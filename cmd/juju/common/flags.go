@@ -6,11 +6,13 @@ package common
 import (
 	"fmt"
 	"io/ioutil"
+	"strconv"
 	"strings"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"github.com/juju/utils"
+	"gopkg.in/juju/names.v3"
 	"gopkg.in/yaml.v2"
 
 	"github.com/juju/juju/core/constraints"
@@ -115,6 +117,92 @@ func (f *ConfigFlag) String() string {
 	return strings.Join(strs, " ")
 }
 
+// MachinesFlag records the machine IDs selected by a comma separated
+// list of machine IDs and ranges of top level machine IDs, for example
+// "1,2,5-10,3/lxd/0".
+type MachinesFlag struct {
+	spec     string
+	machines []string
+}
+
+// Set implements gnuflag.Value.Set.
+func (f *MachinesFlag) Set(s string) error {
+	machines, err := ParseMachineRanges(s)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	f.spec = s
+	f.machines = machines
+	return nil
+}
+
+// String implements gnuflag.Value.String.
+func (f *MachinesFlag) String() string {
+	return f.spec
+}
+
+// Machines returns the selected machine IDs, in the order in which they
+// were specified.
+func (f *MachinesFlag) Machines() []string {
+	return f.machines
+}
+
+// MaxMachineRangeIDs is the largest number of machine IDs that
+// ParseMachineRanges will select.
+const MaxMachineRangeIDs = 1000
+
+// ParseMachineRanges parses a comma separated list of machine IDs and
+// inclusive ranges of top level machine IDs, such as "1,2,5-10", and
+// returns the selected machine IDs with duplicates removed. At most
+// MaxMachineRangeIDs machines may be selected.
+func ParseMachineRanges(s string) ([]string, error) {
+	if s == "" {
+		return nil, errors.NotValidf("empty machine list")
+	}
+	var machines []string
+	seen := make(map[string]bool)
+	add := func(id string) {
+		if !seen[id] {
+			seen[id] = true
+			machines = append(machines, id)
+		}
+	}
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if names.IsValidMachine(item) {
+			add(item)
+			continue
+		}
+		bounds := strings.SplitN(item, "-", 2)
+		if len(bounds) != 2 {
+			return nil, errors.NotValidf("machine %q", item)
+		}
+		lo, loErr := strconv.Atoi(bounds[0])
+		hi, hiErr := strconv.Atoi(bounds[1])
+		if loErr != nil || hiErr != nil || lo < 0 || !names.IsValidMachine(bounds[0]) || !names.IsValidMachine(bounds[1]) {
+			return nil, errors.NotValidf("machine range %q", item)
+		}
+		if lo > hi {
+			return nil, errors.NotValidf("machine range %q (start after end)", item)
+		}
+		if hi-lo >= MaxMachineRangeIDs {
+			return nil, errors.NotValidf("machine range %q (more than %d machines)", item, MaxMachineRangeIDs)
+		}
+		// Counting up from lo, rather than comparing with hi, can't
+		// overflow when hi is the largest int.
+		for n := 0; n <= hi-lo; n++ {
+			add(strconv.Itoa(lo + n))
+		}
+		if len(machines) > MaxMachineRangeIDs {
+			break
+		}
+	}
+	if len(machines) > MaxMachineRangeIDs {
+		return nil, errors.NotValidf("machine list %q (more than %d machines)", s, MaxMachineRangeIDs)
+	}
+	return machines, nil
+}
+
 // WarnConstraintAliases shows a warning to the user that they have used an
 // alias for a constraint that might go away sometime.
 func WarnConstraintAliases(ctx *cmd.Context, aliases map[string]string) {
@@ -127,6 +127,57 @@ func (*FlagsSuite) TestAbsoluteFilenames(c *gc.C) {
 	})
 }
 
+func (*FlagsSuite) TestParseMachineRanges(c *gc.C) {
+	for i, test := range []struct {
+		spec   string
+		expect []string
+	}{
+		{"0", []string{"0"}},
+		{"1,2,5-7", []string{"1", "2", "5", "6", "7"}},
+		{"3, 0/lxd/1", []string{"3", "0/lxd/1"}},
+		{"4-4", []string{"4"}},
+		{"2,1-3,2", []string{"2", "1", "3"}},
+		{"9223372036854775806-9223372036854775807", []string{"9223372036854775806", "9223372036854775807"}},
+	} {
+		c.Logf("test %d: %q", i, test.spec)
+		machines, err := ParseMachineRanges(test.spec)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(machines, jc.DeepEquals, test.expect)
+	}
+}
+
+func (*FlagsSuite) TestParseMachineRangesErrors(c *gc.C) {
+	for i, test := range []struct {
+		spec string
+		err  string
+	}{
+		{"", "empty machine list not valid"},
+		{"1,,2", `machine "" not valid`},
+		{"foo", `machine "foo" not valid`},
+		{"1-x", `machine range "1-x" not valid`},
+		{"01-3", `machine range "01-3" not valid`},
+		{"0/lxd/0-0/lxd/3", `machine range "0/lxd/0-0/lxd/3" not valid`},
+		{"10-5", `machine range "10-5" \(start after end\) not valid`},
+		{"0-1000", `machine range "0-1000" \(more than 1000 machines\) not valid`},
+		{"0-9223372036854775807", `machine range "0-9223372036854775807" \(more than 1000 machines\) not valid`},
+		{"0-999,1000", `machine list "0-999,1000" \(more than 1000 machines\) not valid`},
+		{"0-600,1000-1600", `machine list "0-600,1000-1600" \(more than 1000 machines\) not valid`},
+	} {
+		c.Logf("test %d: %q", i, test.spec)
+		_, err := ParseMachineRanges(test.spec)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (*FlagsSuite) TestMachinesFlag(c *gc.C) {
+	var f MachinesFlag
+	c.Assert(f.Set("1,3-4"), jc.ErrorIsNil)
+	c.Assert(f.Machines(), jc.DeepEquals, []string{"1", "3", "4"})
+	c.Assert(f.String(), gc.Equals, "1,3-4")
+	c.Assert(f.Set("4-3"), gc.NotNil)
+	c.Assert(f.Machines(), jc.DeepEquals, []string{"1", "3", "4"})
+}
+
 func assertConfigFlag(c *gc.C, f ConfigFlag, files []string, attrs map[string]interface{}) {
 	c.Assert(f.files, jc.DeepEquals, files)
 	c.Assert(f.attrs, jc.DeepEquals, attrs)
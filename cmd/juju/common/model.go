@@ -24,6 +24,7 @@ type ModelInfo struct {
 	// ShortName is un-qualified model name.
 	ShortName      string                      `json:"short-name" yaml:"short-name"`
 	UUID           string                      `json:"model-uuid" yaml:"model-uuid"`
+	Alias          string                      `json:"model-alias,omitempty" yaml:"model-alias,omitempty"`
 	Type           model.ModelType             `json:"model-type" yaml:"model-type"`
 	ControllerUUID string                      `json:"controller-uuid" yaml:"controller-uuid"`
 	ControllerName string                      `json:"controller-name" yaml:"controller-name"`
@@ -99,6 +100,7 @@ func ModelInfoFromParams(info params.ModelInfo, now time.Time) (ModelInfo, error
 		Name:           jujuclient.JoinOwnerModelName(ownerTag, info.Name),
 		Type:           model.ModelType(info.Type),
 		UUID:           info.UUID,
+		Alias:          info.Alias,
 		ControllerUUID: info.ControllerUUID,
 		IsController:   info.IsController,
 		Owner:          ownerTag.Id(),
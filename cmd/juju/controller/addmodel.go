@@ -252,8 +252,9 @@ func (c *addModelCommand) Run(ctx *cmd.Context) error {
 	messageArgs := []interface{}{c.Name}
 
 	details := jujuclient.ModelDetails{
-		ModelUUID: model.UUID,
-		ModelType: model.Type,
+		ModelUUID:  model.UUID,
+		ModelAlias: model.Alias,
+		ModelType:  model.Type,
 	}
 	if featureflag.Enabled(feature.Generations) {
 		// Default target is the master branch.
@@ -187,7 +187,7 @@ func (c *modelsCommand) getModelSummaries(ctx *cmd.Context, client ModelManagerA
 		}
 		model.ControllerName = c.runVars.controllerName
 		summaries = append(summaries, model)
-		modelsToStore[model.Name] = jujuclient.ModelDetails{ModelUUID: model.UUID, ModelType: model.Type}
+		modelsToStore[model.Name] = jujuclient.ModelDetails{ModelUUID: model.UUID, ModelAlias: model.Alias, ModelType: model.Type}
 	}
 	found := len(summaries) > 0
 
@@ -228,6 +228,7 @@ type ModelSummary struct {
 	// ShortName is un-qualified model name.
 	ShortName string          `json:"short-name" yaml:"short-name"`
 	UUID      string          `json:"model-uuid" yaml:"model-uuid"`
+	Alias     string          `json:"model-alias,omitempty" yaml:"model-alias,omitempty"`
 	Type      model.ModelType `json:"model-type" yaml:"model-type"`
 
 	ControllerUUID     string                  `json:"controller-uuid" yaml:"controller-uuid"`
@@ -262,6 +263,7 @@ func (c *modelsCommand) modelSummaryFromParams(apiSummary base.UserModelSummary,
 		ShortName:      apiSummary.Name,
 		Name:           jujuclient.JoinOwnerModelName(names.NewUserTag(apiSummary.Owner), apiSummary.Name),
 		UUID:           apiSummary.UUID,
+		Alias:          apiSummary.Alias,
 		Type:           apiSummary.Type,
 		ControllerUUID: apiSummary.ControllerUUID,
 		IsController:   apiSummary.IsController,
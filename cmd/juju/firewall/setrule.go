@@ -33,6 +33,7 @@ Examples:
     juju set-firewall-rule ssh --whitelist 192.168.1.0/16
     juju set-firewall-rule juju-controller --whitelist 192.168.1.0/16
     juju set-firewall-rule juju-application-offer --whitelist 192.168.1.0/16
+    juju set-firewall-rule juju-exposed-application --whitelist 192.168.1.0/16
 
 See also: 
     list-firewall-rules`
@@ -67,6 +68,7 @@ func (c *setFirewallRuleCommand) Info() *cmd.Info {
 		" -" + string(params.SSHRule),
 		" -" + string(params.JujuControllerRule),
 		" -" + string(params.JujuApplicationOfferRule),
+		" -" + string(params.JujuExposedApplicationRule),
 	}
 	return jujucmd.Info(&cmd.Info{
 		Name:    "set-firewall-rule",
@@ -8,6 +8,7 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	params "github.com/juju/juju/apiserver/params"
 	watcher "github.com/juju/juju/core/watcher"
 )
 
@@ -95,6 +96,24 @@ func (mr *MockUpgradeMachineSeriesAPIMockRecorder) UpgradeSeriesPrepare(arg0, ar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpgradeSeriesPrepare", reflect.TypeOf((*MockUpgradeMachineSeriesAPI)(nil).UpgradeSeriesPrepare), arg0, arg1, arg2)
 }
 
+// UpgradeSeriesPrepareMachines mocks base method
+func (m *MockUpgradeMachineSeriesAPI) UpgradeSeriesPrepareMachines(arg0 string, arg1 bool, arg2 ...string) ([]params.ErrorResult, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpgradeSeriesPrepareMachines", varargs...)
+	ret0, _ := ret[0].([]params.ErrorResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpgradeSeriesPrepareMachines indicates an expected call of UpgradeSeriesPrepareMachines
+func (mr *MockUpgradeMachineSeriesAPIMockRecorder) UpgradeSeriesPrepareMachines(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpgradeSeriesPrepareMachines", reflect.TypeOf((*MockUpgradeMachineSeriesAPI)(nil).UpgradeSeriesPrepareMachines), varargs...)
+}
+
 // UpgradeSeriesValidate mocks base method
 func (m *MockUpgradeMachineSeriesAPI) UpgradeSeriesValidate(arg0, arg1 string) ([]string, error) {
 	ret := m.ctrl.Call(m, "UpgradeSeriesValidate", arg0, arg1)
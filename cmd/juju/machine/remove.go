@@ -16,7 +16,9 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	jujucmd "github.com/juju/juju/cmd"
 	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/juju/common"
 	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/core/model"
 )
 
 // NewRemoveCommand returns a command used to remove a specified machine.
@@ -30,6 +32,7 @@ type removeCommand struct {
 	apiRoot      api.Connection
 	machineAPI   RemoveMachineAPI
 	MachineIds   []string
+	machinesFlag common.MachinesFlag
 	Force        bool
 	KeepInstance bool
 	NoWait       bool
@@ -38,11 +41,15 @@ type removeCommand struct {
 
 const destroyMachineDoc = `
 Machines are specified by their numbers, which may be retrieved from the
-output of ` + "`juju status`." + `
+output of ` + "`juju status`" + `, or by their aliases, of the form
+<model alias>:<machine number>.
 
 It is possible to remove machine from Juju model without affecting
 the corresponding cloud instnace by using --keep-instance option.
 
+Machines may also be specified as a comma separated list of machine
+numbers and ranges of machine numbers with the --machines option.
+
 Machines responsible for the model cannot be removed.
 
 Machines running units or containers can be removed using the '--force'
@@ -60,6 +67,8 @@ Examples:
     juju remove-machine 6 --force
     juju remove-machine 6 --force --no-wait
     juju remove-machine 7 --keep-instance
+    juju remove-machine --machines 1,2,5-10
+    juju remove-machine 1a2b3c4d:8
 
 See also:
     add-machine
@@ -81,15 +90,22 @@ func (c *removeCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.Force, "force", false, "Completely remove a machine and all its dependencies")
 	f.BoolVar(&c.KeepInstance, "keep-instance", false, "Do not stop the running cloud instance")
 	f.BoolVar(&c.NoWait, "no-wait", false, "Rush through machine removal without waiting for each individual step to complete")
+	f.Var(&c.machinesFlag, "machines", "Comma separated machine numbers and ranges, e.g. 1,2,5-10")
 	c.fs = f
 }
 
 func (c *removeCommand) Init(args []string) error {
+	if machines := c.machinesFlag.Machines(); len(machines) > 0 {
+		if len(args) > 0 {
+			return errors.Errorf("cannot specify machines both as arguments and with --machines")
+		}
+		args = machines
+	}
 	if len(args) == 0 {
 		return errors.Errorf("no machines specified")
 	}
 	for _, id := range args {
-		if !names.IsValidMachine(id) {
+		if !names.IsValidMachine(id) && !model.IsValidMachineAlias(id) {
 			return errors.Errorf("invalid machine id %q", id)
 		}
 	}
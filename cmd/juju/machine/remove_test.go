@@ -73,6 +73,18 @@ func (s *RemoveMachineSuite) TestInit(c *gc.C) {
 		}, {
 			args:     []string{"1/lxd/2"},
 			machines: []string{"1/lxd/2"},
+		}, {
+			args:     []string{"1a2b3c4d:1/lxd/2"},
+			machines: []string{"1a2b3c4d:1/lxd/2"},
+		}, {
+			args:        []string{"mymodel:1"},
+			errorString: `invalid machine id "mymodel:1"`,
+		}, {
+			args:     []string{"--machines", "1,3-4"},
+			machines: []string{"1", "3", "4"},
+		}, {
+			args:        []string{"--machines", "1", "2"},
+			errorString: "cannot specify machines both as arguments and with --machines",
 		},
 	} {
 		c.Logf("test %d", i)
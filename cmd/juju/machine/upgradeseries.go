@@ -19,6 +19,8 @@ import (
 	"github.com/juju/juju/api/machinemanager"
 	"github.com/juju/juju/apiserver/params"
 	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/juju/common"
 	"github.com/juju/juju/cmd/modelcmd"
 	"github.com/juju/juju/core/watcher"
 )
@@ -51,6 +53,19 @@ When ready, run the following to complete the upgrade series process:
 
 juju upgrade-series %s complete`
 
+var upgradeSeriesMachinesConfirmationMsg = `
+WARNING: This command will mark machines %s as being upgraded to series %q.
+This operation cannot be reverted or canceled once started.
+Continue [y/N]?`[1:]
+
+const UpgradeSeriesPrepareMachinesFinishedMessage = `
+Juju is preparing the machines for the series to be updated.
+Once each machine is prepared, perform any manual steps required along with
+"do-release-upgrade", and then run the following to complete its upgrade
+series process:
+
+juju upgrade-series <machine> complete`
+
 const UpgradeSeriesCompleteFinishedMessage = `
 Upgrade series for machine %q has successfully completed`
 
@@ -66,6 +81,7 @@ type UpgradeMachineSeriesAPI interface {
 	Close() error
 	UpgradeSeriesValidate(string, string) ([]string, error)
 	UpgradeSeriesPrepare(string, string, bool) error
+	UpgradeSeriesPrepareMachines(string, bool, ...string) ([]params.ErrorResult, error)
 	UpgradeSeriesComplete(string) error
 	WatchUpgradeSeriesNotifications(string) (watcher.NotifyWatcher, string, error)
 	GetUpgradeSeriesMessages(string, string) ([]string, error)
@@ -78,11 +94,13 @@ type upgradeSeriesCommand struct {
 
 	upgradeMachineSeriesClient UpgradeMachineSeriesAPI
 
-	subCommand    string
-	force         bool
-	machineNumber string
-	series        string
-	yes           bool
+	subCommand     string
+	force          bool
+	machineNumber  string
+	machinesFlag   common.MachinesFlag
+	machineNumbers []string
+	series         string
+	yes            bool
 
 	catacomb catacomb.Catacomb
 	plan     catacomb.Plan
@@ -123,6 +141,11 @@ running units that do not support the target series:
 
 	juju upgrade-series 4 prepare cosmic --force
 
+Prepare machines 1, 2 and 5 to 10 for upgrade to series "bionic" in one
+call, without waiting for their units to be prepared:
+
+	juju upgrade-series prepare bionic --machines 1,2,5-10
+
 Complete upgrade of machine 5, indicating that all automatic and any
 necessary manual upgrade steps have completed successfully:
 
@@ -151,10 +174,14 @@ func (c *upgradeSeriesCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.yes, "y", false,
 		"Agree that the operation cannot be reverted or canceled once started without being prompted.")
 	f.BoolVar(&c.yes, "yes", false, "")
+	f.Var(&c.machinesFlag, "machines", "Prepare the comma separated machine IDs and ranges, e.g. 1,2,5-10")
 }
 
 // Init implements cmd.Command.
 func (c *upgradeSeriesCommand) Init(args []string) error {
+	if machines := c.machinesFlag.Machines(); len(machines) > 0 {
+		return c.initMachines(machines, args)
+	}
 	if len(args) < 2 {
 		return errors.Errorf("wrong number of arguments")
 	}
@@ -190,8 +217,31 @@ func (c *upgradeSeriesCommand) Init(args []string) error {
 	return nil
 }
 
+// initMachines initialises the command to prepare the machines selected
+// with --machines, for which the arguments are the prepare command and
+// the series.
+func (c *upgradeSeriesCommand) initMachines(machines, args []string) error {
+	if len(args) != 2 {
+		return errors.Errorf("wrong number of arguments")
+	}
+	if args[0] != PrepareCommand {
+		return errors.Errorf("--machines can only be used with the %q command", PrepareCommand)
+	}
+	s, err := checkSeries(series.SupportedSeries(), args[1])
+	if err != nil {
+		return err
+	}
+	c.subCommand = PrepareCommand
+	c.machineNumbers = machines
+	c.series = s
+	return nil
+}
+
 // Run implements cmd.Run.
 func (c *upgradeSeriesCommand) Run(ctx *cmd.Context) error {
+	if len(c.machineNumbers) > 0 {
+		return errors.Trace(c.upgradeSeriesPrepareMachines(ctx))
+	}
 	if c.subCommand == PrepareCommand {
 		err := c.UpgradeSeriesPrepare(ctx)
 		if err != nil {
@@ -242,6 +292,43 @@ func (c *upgradeSeriesCommand) UpgradeSeriesPrepare(ctx *cmd.Context) (err error
 	return nil
 }
 
+// upgradeSeriesPrepareMachines prepares each of the machines selected with
+// --machines for a series upgrade in a single API call. Unlike preparing
+// one machine, it doesn't wait for the machines' units to be prepared.
+func (c *upgradeSeriesCommand) upgradeSeriesPrepareMachines(ctx *cmd.Context) error {
+	apiRoot, err := c.ensureAPIClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if apiRoot != nil {
+		defer apiRoot.Close()
+	}
+
+	if !c.yes {
+		fmt.Fprintf(ctx.Stdout, upgradeSeriesMachinesConfirmationMsg, strings.Join(c.machineNumbers, ", "), c.series)
+		if err := jujucmd.UserConfirmYes(ctx); err != nil {
+			return errors.Annotate(err, "upgrade series")
+		}
+	}
+
+	results, err := c.upgradeMachineSeriesClient.UpgradeSeriesPrepareMachines(c.series, c.force, c.machineNumbers...)
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	failed := false
+	for i, result := range results {
+		if result.Error != nil {
+			failed = true
+			fmt.Fprintf(ctx.Stderr, "machine %s: %v\n", c.machineNumbers[i], result.Error)
+		}
+	}
+	if failed {
+		return cmd.ErrSilent
+	}
+	ctx.Infof(UpgradeSeriesPrepareMachinesFinishedMessage + "\n")
+	return nil
+}
+
 func (c *upgradeSeriesCommand) promptConfirmation(ctx *cmd.Context, affectedUnits []string) error {
 	if c.yes {
 		return nil
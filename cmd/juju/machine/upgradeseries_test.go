@@ -14,6 +14,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/juju/machine"
 	"github.com/juju/juju/cmd/juju/machine/mocks"
 	"github.com/juju/juju/testing"
@@ -167,6 +168,41 @@ func (s *UpgradeSeriesSuite) TestPrepareCommandShouldAcceptYesFlagAndNotPrompt(c
 	c.Assert(out, jc.Contains, fmt.Sprintf("juju upgrade-series %s complete", machineArg))
 }
 
+func (s *UpgradeSeriesSuite) runUpgradeSeriesPrepareMachines(
+	c *gc.C, results []params.ErrorResult, args ...string,
+) (*cmd.Context, error) {
+	mockController := gomock.NewController(c)
+	defer mockController.Finish()
+
+	mockUpgradeSeriesAPI := mocks.NewMockUpgradeMachineSeriesAPI(mockController)
+	mockUpgradeSeriesAPI.EXPECT().UpgradeSeriesPrepareMachines(seriesArg, false, "1", "2").Return(results, nil)
+
+	com := machine.NewUpgradeSeriesCommandForTest(mockUpgradeSeriesAPI)
+	return cmdtesting.RunCommand(c, com, args...)
+}
+
+func (s *UpgradeSeriesSuite) TestPrepareMachines(c *gc.C) {
+	ctx, err := s.runUpgradeSeriesPrepareMachines(c, make([]params.ErrorResult, 2),
+		machine.PrepareCommand, seriesArg, "--machines", "1-2", "--yes")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, machine.UpgradeSeriesPrepareMachinesFinishedMessage+"\n")
+}
+
+func (s *UpgradeSeriesSuite) TestPrepareMachinesReportsErrors(c *gc.C) {
+	results := []params.ErrorResult{{}, {Error: &params.Error{Message: "boom"}}}
+	ctx, err := s.runUpgradeSeriesPrepareMachines(c, results,
+		machine.PrepareCommand, seriesArg, "--machines", "1,2", "--yes")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "machine 2: boom\n")
+}
+
+func (s *UpgradeSeriesSuite) TestCompleteCommandDoesNotAcceptMachines(c *gc.C) {
+	err := s.runUpgradeSeriesCommand(c, machine.CompleteCommand, "--machines", "1,2")
+	c.Assert(err, gc.ErrorMatches, "wrong number of arguments")
+	err = s.runUpgradeSeriesCommand(c, machine.CompleteCommand, seriesArg, "--machines", "1,2")
+	c.Assert(err, gc.ErrorMatches, `--machines can only be used with the "prepare" command`)
+}
+
 type upgradeSeriesPrepareExpectation struct {
 	machineArg, seriesArg, force interface{}
 }
@@ -8,11 +8,13 @@ import (
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
 	"gopkg.in/juju/names.v3"
 
 	"github.com/juju/juju/apiserver/params"
 	jujucmd "github.com/juju/juju/cmd"
 	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/juju/common"
 	"github.com/juju/juju/cmd/modelcmd"
 )
 
@@ -25,8 +27,9 @@ func NewRetryProvisioningCommand() cmd.Command {
 type retryProvisioningCommand struct {
 	modelcmd.ModelCommandBase
 	modelcmd.IAASOnlyCommand
-	Machines []names.MachineTag
-	api      RetryProvisioningAPI
+	Machines     []names.MachineTag
+	machinesFlag common.MachinesFlag
+	api          RetryProvisioningAPI
 }
 
 // RetryProvisioningAPI defines methods on the client API
@@ -36,15 +39,37 @@ type RetryProvisioningAPI interface {
 	RetryProvisioning(machines ...names.MachineTag) ([]params.ErrorResult, error)
 }
 
+const retryProvisioningDoc = `
+Machines may be specified either as arguments, or as a comma separated
+list of machine IDs and ranges of machine IDs with the --machines option.
+
+Examples:
+    juju retry-provisioning 0 3
+    juju retry-provisioning --machines 1,2,5-10
+`
+
 func (c *retryProvisioningCommand) Info() *cmd.Info {
 	return jujucmd.Info(&cmd.Info{
 		Name:    "retry-provisioning",
 		Args:    "<machine> [...]",
 		Purpose: "Retries provisioning for failed machines.",
+		Doc:     retryProvisioningDoc,
 	})
 }
 
+// SetFlags implements part of the cmd.Command interface.
+func (c *retryProvisioningCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.Var(&c.machinesFlag, "machines", "Comma separated machine IDs and ranges, e.g. 1,2,5-10")
+}
+
 func (c *retryProvisioningCommand) Init(args []string) error {
+	if machines := c.machinesFlag.Machines(); len(machines) > 0 {
+		if len(args) > 0 {
+			return errors.Errorf("cannot specify machines both as arguments and with --machines")
+		}
+		args = machines
+	}
 	if len(args) == 0 {
 		return errors.Errorf("no machine specified")
 	}
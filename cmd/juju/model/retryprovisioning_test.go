@@ -117,6 +117,16 @@ var resolvedMachineTests = []struct {
 	}, {
 		args: []string{"0/lxd/0"},
 		err:  `invalid machine "0/lxd/0" retry-provisioning does not support containers`,
+	}, {
+		args: []string{"--machines", "0-1,42"},
+		stdErr: `machine 1 is not in an error state` +
+			`machine 42 not found`,
+	}, {
+		args: []string{"--machines", "0", "1"},
+		err:  `cannot specify machines both as arguments and with --machines`,
+	}, {
+		args: []string{"--machines", "2-1"},
+		err:  `invalid value "2-1" for flag --machines: machine range "2-1" \(start after end\) not valid`,
 	},
 }
 
@@ -12,8 +12,11 @@ import (
 
 	"github.com/gosuri/uitable"
 	"github.com/juju/cmd"
+	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"github.com/juju/gnuflag"
+	"github.com/juju/naturalsort"
+	"gopkg.in/juju/names.v3"
 
 	"github.com/juju/juju/apiserver/params"
 	jujucmd "github.com/juju/juju/cmd"
@@ -37,6 +40,8 @@ type ListCommand struct {
 const listCommandDoc = `
 Displays all defined spaces. By default both spaces and their subnets are displayed.
 Supplying the --short option will list just the space names.
+The summary format shows, for each subnet, the machines with addresses in
+it and the ports opened on it.
 The --output argument allows the command's output to be redirected to a file. 
 
 Examples:
@@ -49,6 +54,10 @@ List spaces:
 
 	juju spaces --short
 
+List spaces with their subnets, machines and opened ports:
+
+	juju spaces --format summary
+
 See also:
 	add-space
 	reload-spaces
@@ -58,7 +67,7 @@ See also:
 func (c *ListCommand) Info() *cmd.Info {
 	return jujucmd.Info(&cmd.Info{
 		Name:    "spaces",
-		Args:    "[--short] [--format yaml|json] [--output <path>]",
+		Args:    "[--short] [--format yaml|json|summary] [--output <path>]",
 		Purpose: "List known spaces, including associated subnets.",
 		Doc:     strings.TrimSpace(listCommandDoc),
 		Aliases: []string{"list-spaces"},
@@ -72,6 +81,7 @@ func (c *ListCommand) SetFlags(f *gnuflag.FlagSet) {
 		"yaml":    cmd.FormatYaml,
 		"json":    cmd.FormatJson,
 		"tabular": c.printTabular,
+		"summary": printSummary,
 	})
 	f.BoolVar(&c.Short, "short", false, "only display spaces.")
 }
@@ -90,6 +100,9 @@ func (c *ListCommand) Init(args []string) error {
 // Run implements Command.Run.
 func (c *ListCommand) Run(ctx *cmd.Context) error {
 	return c.RunWithAPI(ctx, func(api SpaceAPI, ctx *cmd.Context) error {
+		if c.out.Name() == "summary" {
+			return c.runSummary(api, ctx)
+		}
 		spaces, err := api.ListSpaces()
 		if err != nil {
 			if errors.IsNotSupported(err) {
@@ -233,6 +246,136 @@ func printTabularLong(writer io.Writer, value interface{}) error {
 	return nil
 }
 
+// runSummary writes the network topology of the model, as returned by
+// a single API call, grouped by space and subnet.
+func (c *ListCommand) runSummary(api SpaceAPI, ctx *cmd.Context) error {
+	topology, err := api.NetworkTopology()
+	if err != nil {
+		if errors.IsNotSupported(err) {
+			ctx.Infof("cannot list spaces: %v", err)
+		}
+		return errors.Annotate(err, "cannot list spaces")
+	}
+	if len(topology.Spaces) == 0 {
+		ctx.Infof("no spaces to display")
+		return nil
+	}
+	summary, err := summarizeTopology(topology)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return c.out.Write(ctx, summary)
+}
+
+// summarizeTopology arranges the machine addresses and opened ports in
+// the topology by the subnet they belong to. Ports opened without a
+// subnet apply to every subnet the machine has an address in.
+func summarizeTopology(topology params.NetworkTopology) (formattedSummary, error) {
+	// Machine addresses keyed by subnet CIDR and then machine ID.
+	addresses := make(map[string]map[string][]string)
+	// The subnet CIDRs each machine has an address in, keyed by tag.
+	machineSubnets := make(map[string][]string)
+	for _, m := range topology.Machines {
+		tag, err := names.ParseMachineTag(m.MachineTag)
+		if err != nil {
+			return formattedSummary{}, errors.Trace(err)
+		}
+		for _, dev := range m.Devices {
+			for _, addr := range dev.Addresses {
+				if addr.SubnetCIDR == "" {
+					continue
+				}
+				if addresses[addr.SubnetCIDR] == nil {
+					addresses[addr.SubnetCIDR] = make(map[string][]string)
+				}
+				if addresses[addr.SubnetCIDR][tag.Id()] == nil {
+					machineSubnets[m.MachineTag] = append(machineSubnets[m.MachineTag], addr.SubnetCIDR)
+				}
+				addresses[addr.SubnetCIDR][tag.Id()] = append(addresses[addr.SubnetCIDR][tag.Id()], addr.Value)
+			}
+		}
+	}
+
+	ports := make(map[string]set.Strings)
+	for _, opened := range topology.OpenedPorts {
+		for _, pr := range opened.Ports {
+			unit, err := names.ParseUnitTag(pr.UnitTag)
+			if err != nil {
+				return formattedSummary{}, errors.Trace(err)
+			}
+			cidrs := []string{opened.SubnetCIDR}
+			if opened.SubnetCIDR == "" {
+				cidrs = machineSubnets[pr.MachineTag]
+			}
+			for _, cidr := range cidrs {
+				if ports[cidr] == nil {
+					ports[cidr] = set.NewStrings()
+				}
+				ports[cidr].Add(fmt.Sprintf("%s %s", unit.Id(), pr.PortRange.NetworkPortRange()))
+			}
+		}
+	}
+
+	var summary formattedSummary
+	for _, space := range topology.Spaces {
+		fsp := formattedSpaceSummary{Name: space.Name}
+		for _, subnet := range space.Subnets {
+			fsn := formattedSubnetSummary{CIDR: subnet.CIDR}
+			machineIds := make([]string, 0, len(addresses[subnet.CIDR]))
+			for id := range addresses[subnet.CIDR] {
+				machineIds = append(machineIds, id)
+			}
+			naturalsort.Sort(machineIds)
+			for _, id := range machineIds {
+				fsn.Machines = append(fsn.Machines, fmt.Sprintf(
+					"%s (%s)", id, strings.Join(addresses[subnet.CIDR][id], ", ")))
+			}
+			if opened, ok := ports[subnet.CIDR]; ok {
+				fsn.OpenedPorts = opened.SortedValues()
+			}
+			fsp.Subnets = append(fsp.Subnets, fsn)
+		}
+		sort.Slice(fsp.Subnets, func(i, j int) bool {
+			return fsp.Subnets[i].CIDR < fsp.Subnets[j].CIDR
+		})
+		summary.Spaces = append(summary.Spaces, fsp)
+	}
+	return summary, nil
+}
+
+// printSummary prints the network topology summary in tabular format.
+func printSummary(writer io.Writer, value interface{}) error {
+	summary, ok := value.(formattedSummary)
+	if !ok {
+		return errors.New("unexpected value")
+	}
+
+	table := uitable.New()
+	table.MaxColWidth = 50
+	table.Wrap = true
+
+	table.AddRow("Space", "Subnet", "Machines", "Opened ports")
+	for _, s := range summary.Spaces {
+		if len(s.Subnets) == 0 {
+			table.AddRow(spaceName(s.Name), "", "", "")
+			continue
+		}
+		for i, subnet := range s.Subnets {
+			name := ""
+			if i == 0 {
+				name = spaceName(s.Name)
+			}
+			table.AddRow(name, subnet.CIDR,
+				strings.Join(subnet.Machines, ", "),
+				strings.Join(subnet.OpenedPorts, ", "))
+		}
+	}
+
+	table.AddRow("", "", "", "")
+	_, _ = fmt.Fprint(writer, table)
+	return nil
+}
+
 const (
 	typeUnknown = "unknown"
 	typeIPv4    = "ipv4"
@@ -259,6 +402,21 @@ type formattedList struct {
 	Spaces []formattedSpace `json:"spaces" yaml:"spaces"`
 }
 
+type formattedSubnetSummary struct {
+	CIDR        string
+	Machines    []string
+	OpenedPorts []string
+}
+
+type formattedSpaceSummary struct {
+	Name    string
+	Subnets []formattedSubnetSummary
+}
+
+type formattedSummary struct {
+	Spaces []formattedSpaceSummary
+}
+
 type formattedShortList struct {
 	Spaces []string `json:"spaces" yaml:"spaces"`
 }
@@ -14,6 +14,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/juju/space"
 )
 
@@ -58,6 +59,10 @@ func (s *ListSuite) TestInit(c *gc.C) {
 		about:        "yaml format",
 		args:         s.Strings("--format", "yaml"),
 		expectFormat: "yaml",
+	}, {
+		about:        "summary format",
+		args:         s.Strings("--format", "summary"),
+		expectFormat: "summary",
 	}, {
 		about:        "tabular format",
 		args:         s.Strings("--format", "tabular"),
@@ -303,6 +308,77 @@ space2
 	}
 }
 
+func (s *ListSuite) TestRunSummary(c *gc.C) {
+	s.api.Topology = params.NetworkTopology{
+		Spaces: []params.Space{{
+			Id: "0",
+		}, {
+			Id:   "1",
+			Name: "space1",
+			Subnets: []params.Subnet{
+				{CIDR: "10.1.3.0/24"},
+				{CIDR: "10.1.2.0/24"},
+			},
+		}},
+		Machines: []params.MachineNetworkTopology{{
+			MachineTag: "machine-0",
+			Devices: []params.NetworkDeviceTopology{{
+				Name: "eth0",
+				Addresses: []params.NetworkAddressTopology{
+					{Value: "10.1.2.5", SubnetCIDR: "10.1.2.0/24"},
+				},
+			}},
+		}, {
+			MachineTag: "machine-1",
+			Devices: []params.NetworkDeviceTopology{{
+				Name: "eth0",
+				Addresses: []params.NetworkAddressTopology{
+					{Value: "10.1.2.6", SubnetCIDR: "10.1.2.0/24"},
+				},
+			}, {
+				Name: "eth1",
+				Addresses: []params.NetworkAddressTopology{
+					{Value: "10.1.3.6", SubnetCIDR: "10.1.3.0/24"},
+				},
+			}},
+		}},
+		OpenedPorts: []params.SubnetOpenedPorts{{
+			Ports: []params.OpenedPortRange{{
+				MachineTag: "machine-1",
+				UnitTag:    "unit-mysql-0",
+				PortRange:  params.PortRange{FromPort: 22, ToPort: 22, Protocol: "tcp"},
+			}},
+		}, {
+			SubnetCIDR: "10.1.2.0/24",
+			Ports: []params.OpenedPortRange{{
+				MachineTag: "machine-0",
+				UnitTag:    "unit-haproxy-0",
+				PortRange:  params.PortRange{FromPort: 80, ToPort: 80, Protocol: "tcp"},
+			}},
+		}},
+	}
+
+	s.AssertRunSucceeds(c, "", `
+Space      Subnet       Machines                    Opened ports                    
+(default)                                                                           
+space1     10.1.2.0/24  0 (10.1.2.5), 1 (10.1.2.6)  haproxy/0 80/tcp, mysql/0 22/tcp
+           10.1.3.0/24  1 (10.1.3.6)                mysql/0 22/tcp                  
+                                                                                    
+`[1:], "--format", "summary")
+
+	s.api.CheckCallNames(c, "NetworkTopology", "Close")
+}
+
+func (s *ListSuite) TestRunSummaryNotSupported(c *gc.C) {
+	s.api.SetErrors(errors.NewNotSupported(nil, "Controller does not support network topology"))
+
+	err := s.AssertRunSpacesNotSupported(c,
+		"cannot list spaces: Controller does not support network topology", "--format", "summary")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+
+	s.api.CheckCallNames(c, "NetworkTopology", "Close")
+}
+
 func (s *ListSuite) TestRunWhenNoSpacesExistSucceeds(c *gc.C) {
 	s.api.Spaces = s.api.Spaces[0:0]
 
@@ -152,8 +152,9 @@ func (s *BaseSpaceSuite) Strings(values ...string) []string {
 type StubAPI struct {
 	*testing.Stub
 
-	Spaces  []params.Space
-	Subnets []params.Subnet
+	Spaces   []params.Space
+	Subnets  []params.Subnet
+	Topology params.NetworkTopology
 }
 
 var _ space.SpaceAPI = (*StubAPI)(nil)
@@ -227,8 +228,8 @@ func (sa *StubAPI) AddSpace(name string, subnetIds []string, public bool) error
 	return sa.NextErr()
 }
 
-func (sa *StubAPI) RemoveSpace(name string) error {
-	sa.MethodCall(sa, "RemoveSpace", name)
+func (sa *StubAPI) RemoveSpace(name string, force bool) error {
+	sa.MethodCall(sa, "RemoveSpace", name, force)
 	return sa.NextErr()
 }
 
@@ -246,3 +247,11 @@ func (sa *StubAPI) ReloadSpaces() error {
 	sa.MethodCall(sa, "ReloadSpaces")
 	return sa.NextErr()
 }
+
+func (sa *StubAPI) NetworkTopology() (params.NetworkTopology, error) {
+	sa.MethodCall(sa, "NetworkTopology")
+	if err := sa.NextErr(); err != nil {
+		return params.NetworkTopology{}, err
+	}
+	return sa.Topology, nil
+}
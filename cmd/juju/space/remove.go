@@ -8,6 +8,7 @@ import (
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
 	"gopkg.in/juju/names.v3"
 
 	jujucmd "github.com/juju/juju/cmd"
@@ -22,14 +23,26 @@ func NewRemoveCommand() modelcmd.ModelCommand {
 // RemoveCommand calls the API to remove an existing network space.
 type RemoveCommand struct {
 	SpaceCommandBase
-	name string
+	name  string
+	force bool
 }
 
 const removeCommandDoc = `
 Removes an existing Juju network space with the given name. Any subnets
 associated with the space will be transferred to the default space.
+
+A space that is still used by constraints, endpoint bindings or the
+juju-ha-space and juju-mgmt-space controller settings is not removed.
+With --force, those references are rewritten to use the default space
+instead.
 `
 
+// SetFlags is defined on the cmd.Command interface.
+func (c *RemoveCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.SpaceCommandBase.SetFlags(f)
+	f.BoolVar(&c.force, "force", false, "rebind anything still using the space to the default space")
+}
+
 // Info is defined on the cmd.Command interface.
 func (c *RemoveCommand) Info() *cmd.Info {
 	return jujucmd.Info(&cmd.Info{
@@ -62,7 +75,7 @@ func (c *RemoveCommand) Init(args []string) (err error) {
 func (c *RemoveCommand) Run(ctx *cmd.Context) error {
 	return c.RunWithAPI(ctx, func(api SpaceAPI, ctx *cmd.Context) error {
 		// Remove the space.
-		err := api.RemoveSpace(c.name)
+		err := api.RemoveSpace(c.name, c.force)
 		if err != nil {
 			return errors.Annotatef(err, "cannot remove space %q", c.name)
 		}
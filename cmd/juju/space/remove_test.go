@@ -70,7 +70,18 @@ func (s *RemoveSuite) TestRunWithValidSpaceSucceeds(c *gc.C) {
 	)
 
 	s.api.CheckCallNames(c, "RemoveSpace", "Close")
-	s.api.CheckCall(c, 0, "RemoveSpace", "myspace")
+	s.api.CheckCall(c, 0, "RemoveSpace", "myspace", false)
+}
+
+func (s *RemoveSuite) TestRunWithForce(c *gc.C) {
+	s.AssertRunSucceeds(c,
+		`removed space "myspace"\n`,
+		"", // no stdout, just stderr
+		"myspace", "--force",
+	)
+
+	s.api.CheckCallNames(c, "RemoveSpace", "Close")
+	s.api.CheckCall(c, 0, "RemoveSpace", "myspace", true)
 }
 
 func (s *RemoveSuite) TestRunWhenSpacesAPIFails(c *gc.C) {
@@ -82,5 +93,5 @@ func (s *RemoveSuite) TestRunWhenSpacesAPIFails(c *gc.C) {
 	)
 
 	s.api.CheckCallNames(c, "RemoveSpace", "Close")
-	s.api.CheckCall(c, 0, "RemoveSpace", "myspace")
+	s.api.CheckCall(c, 0, "RemoveSpace", "myspace", false)
 }
@@ -38,8 +38,10 @@ type SpaceAPI interface {
 	// yet.
 
 	// RemoveSpace removes an existing Juju network space, transferring
-	// any associated subnets to the default space.
-	RemoveSpace(name string) error
+	// any associated subnets to the default space. If force is true,
+	// constraints, endpoint bindings and controller settings still using
+	// the space are rewritten to use the default space.
+	RemoveSpace(name string, force bool) error
 
 	// UpdateSpace changes the associated subnets for an existing space with
 	// the given name. The list of subnets must contain at least one entry.
@@ -50,6 +52,10 @@ type SpaceAPI interface {
 
 	// ReloadSpaces fetches spaces and subnets from substrate
 	ReloadSpaces() error
+
+	// NetworkTopology returns the spaces, subnets, machine network
+	// devices and opened ports of the model in a single call.
+	NetworkTopology() (params.NetworkTopology, error)
 }
 
 var logger = loggo.GetLogger("juju.cmd.juju.space")
@@ -141,10 +147,22 @@ func (m *mvpAPIShim) ListSpaces() ([]params.Space, error) {
 	return m.facade.ListSpaces()
 }
 
+func (m *mvpAPIShim) RemoveSpace(name string, force bool) error {
+	return m.facade.RemoveSpace(name, force)
+}
+
+func (m *mvpAPIShim) RenameSpace(name, newName string) error {
+	return m.facade.RenameSpace(name, newName)
+}
+
 func (m *mvpAPIShim) ReloadSpaces() error {
 	return m.facade.ReloadSpaces()
 }
 
+func (m *mvpAPIShim) NetworkTopology() (params.NetworkTopology, error) {
+	return m.facade.NetworkTopology()
+}
+
 // NewAPI returns a SpaceAPI for the root api endpoint that the
 // environment command returns.
 func (c *SpaceCommandBase) NewAPI() (SpaceAPI, error) {
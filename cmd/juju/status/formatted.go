@@ -68,6 +68,7 @@ type machineStatus struct {
 	IPAddresses        []string                      `json:"ip-addresses,omitempty" yaml:"ip-addresses,omitempty"`
 	InstanceId         instance.Id                   `json:"instance-id,omitempty" yaml:"instance-id,omitempty"`
 	DisplayName        string                        `json:"display-name,omitempty" yaml:"display-name,omitempty"`
+	Hostname           string                        `json:"hostname,omitempty" yaml:"hostname,omitempty"`
 	MachineStatus      statusInfoContents            `json:"machine-status,omitempty" yaml:"machine-status,omitempty"`
 	ModificationStatus statusInfoContents            `json:"modification-status,omitempty" yaml:"modification-status,omitempty"`
 	Series             string                        `json:"series,omitempty" yaml:"series,omitempty"`
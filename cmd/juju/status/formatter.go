@@ -18,6 +18,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/juju/common"
 	"github.com/juju/juju/cmd/juju/storage"
+	"github.com/juju/juju/core/relation"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/state/multiwatcher"
 )
@@ -176,6 +177,7 @@ func (sf *statusFormatter) formatMachine(machine params.MachineStatus) machineSt
 		IPAddresses:        machine.IPAddresses,
 		InstanceId:         machine.InstanceId,
 		DisplayName:        machine.DisplayName,
+		Hostname:           machine.Hostname,
 		MachineStatus:      sf.getStatusInfoContents(machine.InstanceStatus),
 		ModificationStatus: sf.getStatusInfoContents(machine.ModificationStatus),
 		Series:             machine.Series,
@@ -336,6 +338,13 @@ func (sf *statusFormatter) formatRelation(rel params.RelationStatus) relationSta
 		Status:    rel.Status.Status,
 		Message:   rel.Status.Info,
 	}
+	if reason, ok := relation.ParseStatusReason(rel.Status.Data); ok && out.Message == "" {
+		// Explain why the relation has its status if nothing else
+		// does. A suspended relation's reason is already its message.
+		if reason.Reason != relation.ReasonSuspended {
+			out.Message = reason.String()
+		}
+	}
 	return out
 }
 
@@ -83,9 +83,14 @@ machines, applications, and units will also be displayed. If a subordinate unit
 is matched, then its principal unit will be displayed. If a principal unit is
 matched, then all of its subordinates will be displayed.
 
-Machine numbers may also be used as output filters. This will only display data
-in each section relevant to the specified machines. For example, application
-section will only contain the applications that have units on these machines, etc.
+Machine numbers may also be used as output filters, either individually or as
+an inclusive range such as 3-7. This will only display data in each section
+relevant to the specified machines. For example, application section will only
+contain the applications that have units on these machines, etc.
+
+Unit agent and workload statuses, such as "error" or "blocked", may also be used
+as output filters. All filtering is done by the controller, so only the matching
+entities are returned.
 
 The available output formats are:
 
@@ -114,6 +119,8 @@ Examples:
     juju show-status
     juju show-status mysql
     juju show-status nova-*
+    juju show-status 0-10
+    juju show-status blocked
     juju show-status --relations
     juju show-status --storage
 
@@ -33,6 +33,7 @@ import (
 	"github.com/juju/juju/core/migration"
 	"github.com/juju/juju/core/network"
 	corepresence "github.com/juju/juju/core/presence"
+	"github.com/juju/juju/core/relation"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/environs"
 	environscontext "github.com/juju/juju/environs/context"
@@ -5988,6 +5989,42 @@ func (s *StatusSuite) TestControllerTimestampInFullStatus(c *gc.C) {
 	})
 }
 
+func (s *StatusSuite) TestFormatRelationReason(c *gc.C) {
+	formatter := NewStatusFormatter(&params.FullStatus{}, true)
+	rel := params.RelationStatus{
+		Interface: "mysql",
+		Endpoints: []params.EndpointStatus{
+			{ApplicationName: "mysql", Name: "server", Role: "provider"},
+			{ApplicationName: "remote-wordpress", Name: "db", Role: "requirer"},
+		},
+		Status: params.DetailedStatus{
+			Status: "broken",
+			Data: relation.StatusReason{
+				Reason:              relation.ReasonDeparted,
+				DepartedApplication: "remote-wordpress",
+			}.Data(),
+		},
+	}
+	c.Check(formatter.formatRelation(rel), jc.DeepEquals, relationStatus{
+		Provider:  "mysql:server",
+		Requirer:  "remote-wordpress:db",
+		Interface: "mysql",
+		Type:      "regular",
+		Status:    "broken",
+		Message:   `application "remote-wordpress" departed`,
+	})
+
+	rel.Status = params.DetailedStatus{
+		Status: "suspended",
+		Info:   "for maintenance",
+		Data: relation.StatusReason{
+			Reason:          relation.ReasonSuspended,
+			SuspendedReason: "for maintenance",
+		}.Data(),
+	}
+	c.Check(formatter.formatRelation(rel).Message, gc.Equals, "for maintenance")
+}
+
 func (s *StatusSuite) TestTabularNoRelations(c *gc.C) {
 	ctx := s.FilteringTestSetup(c)
 	defer s.resetContext(c, ctx)
@@ -30,16 +30,54 @@ func NewImportFilesystemCommand(
 	newStorageImporter NewStorageImporterFunc,
 	store jujuclient.ClientStore,
 ) cmd.Command {
-	cmd := &importFilesystemCommand{}
-	cmd.newAPIFunc = newStorageImporter
+	return newImportStorageCommand(storage.StorageKindFilesystem, &cmd.Info{
+		Name:    "import-filesystem",
+		Purpose: "Imports a filesystem into the model.",
+		Doc:     importFilesystemCommandDoc,
+		Args:    importStorageCommandArgs,
+	}, newStorageImporter, store)
+}
+
+// NewImportVolumeCommand returns a command used to import a volume.
+//
+// newStorageImporter is the function to use to acquire a StorageImporter.
+// A non-nil function must be provided.
+//
+// store is an optional ClientStore to use for interacting with the client
+// model/controller storage. If nil, the default file-based store will be
+// used.
+func NewImportVolumeCommand(
+	newStorageImporter NewStorageImporterFunc,
+	store jujuclient.ClientStore,
+) cmd.Command {
+	return newImportStorageCommand(storage.StorageKindBlock, &cmd.Info{
+		Name:    "import-volume",
+		Purpose: "Imports a volume into the model.",
+		Doc:     importVolumeCommandDoc,
+		Args:    importStorageCommandArgs,
+	}, newStorageImporter, store)
+}
+
+func newImportStorageCommand(
+	kind storage.StorageKind,
+	info *cmd.Info,
+	newStorageImporter NewStorageImporterFunc,
+	store jujuclient.ClientStore,
+) cmd.Command {
+	c := &importStorageCommand{
+		kind: kind,
+		info: info,
+	}
+	c.newAPIFunc = newStorageImporter
 	if store != nil {
-		cmd.SetClientStore(store)
+		c.SetClientStore(store)
 	}
-	return modelcmd.Wrap(cmd)
+	return modelcmd.Wrap(c)
 }
 
 // NewStorageImporterFunc is the type of a function passed to
-// NewImportFilesystemCommand, in order to acquire a StorageImporter.
+// NewImportFilesystemCommand and NewImportVolumeCommand, in order
+// to acquire a StorageImporter.
 type NewStorageImporterFunc func(*StorageCommandBase) (StorageImporter, error)
 
 // NewStorageImporter returns a new StorageImporter,
@@ -74,26 +112,60 @@ Examples:
     # the volume and filesystem contained within.
     juju import-filesystem ebs vol-123456 pgdata
 `
-	importFilesystemCommandAgs = `
+	importVolumeCommandDoc = `
+Import an existing volume into the model. This will lead to the model
+taking ownership of the storage, so you must take care not to import storage
+that is in use by another Juju model.
+
+To import a volume, you must specify three things:
+
+ - the storage provider which manages the storage, and with
+   which the storage will be associated
+ - the storage provider ID for the volume
+ - the storage name to assign to the volume,
+   corresponding to the storage name used by a charm
+
+The volume must not be attached to any machine. The storage provider
+checks that the volume can be used by the model, for example that it
+is in one of the model's availability zones, before tagging it as
+belonging to the model.
+
+Once a volume is imported, Juju will create an associated storage
+instance using the given storage name.
+
+Examples:
+    # Import an existing Cinder volume, and assign it the
+    # "pgdata" storage name. Juju will associate a storage
+    # instance ID like "pgdata/0" with the volume.
+    juju import-volume cinder 8c6d8b8e-0fb1-4d0e-8b52-3e8c14b4e0c6 pgdata
+
+    # Import an existing Azure managed disk in the model's
+    # resource group.
+    juju import-volume azure pgdata-disk pgdata
+`
+	importStorageCommandArgs = `
 <storage-provider> <provider-id> <storage-name>
 `
 )
 
-// importFilesystemCommand imports filesystems into the model.
-type importFilesystemCommand struct {
+// importStorageCommand imports filesystems or volumes into the model.
+type importStorageCommand struct {
 	StorageCommandBase
 	modelcmd.IAASOnlyCommand
 	newAPIFunc NewStorageImporterFunc
 
+	kind storage.StorageKind
+	info *cmd.Info
+
 	storagePool       string
 	storageProviderId string
 	storageName       string
 }
 
 // Init implements Command.Init.
-func (c *importFilesystemCommand) Init(args []string) error {
+func (c *importStorageCommand) Init(args []string) error {
 	if len(args) < 3 {
-		return errors.New("import-filesystem requires a storage provider, provider ID, and storage name")
+		return errors.Errorf("%s requires a storage provider, provider ID, and storage name", c.info.Name)
 	}
 	c.storagePool = args[0]
 	c.storageProviderId = args[1]
@@ -114,17 +186,13 @@ func (c *importFilesystemCommand) Init(args []string) error {
 }
 
 // Info implements Command.Info.
-func (c *importFilesystemCommand) Info() *cmd.Info {
-	return jujucmd.Info(&cmd.Info{
-		Name:    "import-filesystem",
-		Purpose: "Imports a filesystem into the model.",
-		Doc:     importFilesystemCommandDoc,
-		Args:    importFilesystemCommandAgs,
-	})
+func (c *importStorageCommand) Info() *cmd.Info {
+	info := *c.info
+	return jujucmd.Info(&info)
 }
 
 // Run implements Command.Run.
-func (c *importFilesystemCommand) Run(ctx *cmd.Context) (err error) {
+func (c *importStorageCommand) Run(ctx *cmd.Context) (err error) {
 	api, err := c.newAPIFunc(&c.StorageCommandBase)
 	if err != nil {
 		return err
@@ -136,8 +204,7 @@ func (c *importFilesystemCommand) Run(ctx *cmd.Context) (err error) {
 		c.storageProviderId, c.storagePool, c.storageName,
 	)
 	storageTag, err := api.ImportStorage(
-		storage.StorageKindFilesystem,
-		c.storagePool, c.storageProviderId, c.storageName,
+		c.kind, c.storagePool, c.storageProviderId, c.storageName,
 	)
 	if err != nil {
 		return err
@@ -81,6 +81,39 @@ func (s *ImportFilesystemSuite) TestImportError(c *gc.C) {
 	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, `importing "bar" from storage pool "foo" as storage "baz"`+"\n")
 }
 
+func (s *ImportFilesystemSuite) TestImportVolume(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, storage.NewImportVolumeCommand(
+		func(*storage.StorageCommandBase) (storage.StorageImporter, error) {
+			return &s.importer, nil
+		},
+		s.store,
+	), "foo", "bar", "baz")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, `
+importing "bar" from storage pool "foo" as storage "baz"
+imported storage baz/0
+`[1:])
+
+	s.importer.CheckCalls(c, []testing.StubCall{
+		{"ImportStorage", []interface{}{
+			jujustorage.StorageKindBlock,
+			"foo", "bar", "baz",
+		}},
+		{"Close", nil},
+	})
+}
+
+func (s *ImportFilesystemSuite) TestImportVolumeInitError(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, storage.NewImportVolumeCommand(
+		func(*storage.StorageCommandBase) (storage.StorageImporter, error) {
+			return &s.importer, nil
+		},
+		s.store,
+	), "foo", "bar")
+	c.Assert(err, gc.ErrorMatches, "import-volume requires a storage provider, provider ID, and storage name")
+}
+
 func (s *ImportFilesystemSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
 	return cmdtesting.RunCommand(c, storage.NewImportFilesystemCommand(
 		func(*storage.StorageCommandBase) (storage.StorageImporter, error) {
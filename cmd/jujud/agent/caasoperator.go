@@ -221,21 +221,29 @@ func (op *CaasOperatorAgent) Workers() (worker.Worker, error) {
 	}
 
 	agentConfig := op.AgentConf.CurrentConfig()
+	// The hostname of a pod is its name.
+	podName, err := os.Hostname()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get pod name")
+	}
 	manifolds := CaasOperatorManifolds(caasoperator.ManifoldsConfig{
-		Agent:                agent.APIHostPortsSetter{op},
-		AgentConfigChanged:   op.configChangedVal,
-		Clock:                clock.WallClock,
-		LogSource:            op.bufferedLogger.Logs(),
-		UpdateLoggerConfig:   updateAgentConfLogging,
-		PrometheusRegisterer: op.prometheusRegistry,
-		LeadershipGuarantee:  15 * time.Second,
-		PreUpgradeSteps:      op.preUpgradeSteps,
-		UpgradeStepsLock:     op.upgradeComplete,
-		ValidateMigration:    op.validateMigration,
-		MachineLock:          op.machineLock,
-		PreviousAgentVersion: agentConfig.UpgradedToVersion(),
-		NewExecClient:        op.newExecClient,
-		RunListenerSocket:    op.runListenerSocket,
+		Agent:                 agent.APIHostPortsSetter{op},
+		AgentConfigChanged:    op.configChangedVal,
+		Clock:                 clock.WallClock,
+		LogSource:             op.bufferedLogger.Logs(),
+		UpdateLoggerConfig:    updateAgentConfLogging,
+		PrometheusRegisterer:  op.prometheusRegistry,
+		LeadershipGuarantee:   15 * time.Second,
+		PreUpgradeSteps:       op.preUpgradeSteps,
+		UpgradeStepsLock:      op.upgradeComplete,
+		ValidateMigration:     op.validateMigration,
+		MachineLock:           op.machineLock,
+		PreviousAgentVersion:  agentConfig.UpgradedToVersion(),
+		NewExecClient:         op.newExecClient,
+		RunListenerSocket:     op.runListenerSocket,
+		ApplicationName:       op.ApplicationName,
+		PodName:               podName,
+		OperatorLeaseDuration: 30 * time.Second,
 	})
 
 	engine, err := dependency.NewEngine(dependencyEngineConfig())
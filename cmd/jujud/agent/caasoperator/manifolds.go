@@ -14,6 +14,7 @@ import (
 	"github.com/juju/utils/voyeur"
 	"github.com/juju/version"
 	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/juju/names.v3"
 	"gopkg.in/juju/worker.v1"
 	"gopkg.in/juju/worker.v1/dependency"
 
@@ -39,6 +40,7 @@ import (
 	"github.com/juju/juju/worker/migrationflag"
 	"github.com/juju/juju/worker/migrationminion"
 	"github.com/juju/juju/worker/retrystrategy"
+	"github.com/juju/juju/worker/singular"
 	"github.com/juju/juju/worker/uniter"
 	"github.com/juju/juju/worker/upgradesteps"
 )
@@ -100,6 +102,19 @@ type ManifoldsConfig struct {
 
 	// RunListenerSocket returns a function to create a run listener socket.
 	RunListenerSocket func() (*sockets.Socket, error)
+
+	// ApplicationName is the name of the application whose operator
+	// this agent runs.
+	ApplicationName string
+
+	// PodName is the name of the pod this agent is running in. When
+	// the operator has several replicas, only the pod holding the
+	// operator lease runs the operator.
+	PodName string
+
+	// OperatorLeaseDuration controls how long the operator lease is
+	// claimed for at a time.
+	OperatorLeaseDuration time.Duration
 }
 
 // Manifolds returns a set of co-configured manifolds covering the various
@@ -238,12 +253,26 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 			NewWorker:     retrystrategy.NewRetryStrategyWorker,
 		})),
 
+		// The operator flag is set while this pod holds the
+		// application's operator lease, so that only one of the
+		// operator's replicas runs the operator at a time.
+		operatorFlagName: ifNotMigrating(singular.Manifold(singular.ManifoldConfig{
+			Clock:         config.Clock,
+			APICallerName: apiCallerName,
+			Duration:      config.OperatorLeaseDuration,
+			Entity:        names.NewApplicationTag(config.ApplicationName),
+			NewFacade: func(apiCaller base.APICaller, _, entity names.Tag) (singular.Facade, error) {
+				return newOperatorFlagFacade(caasoperatorapi.NewClient(apiCaller), entity.Id(), config.PodName), nil
+			},
+			NewWorker: singular.NewWorker,
+		})),
+
 		// The operator installs and deploys charm containers;
 		// manages the unit's presence in its relations;
 		// creates subordinate units; runs all the hooks;
 		// sends metrics; etc etc etc.
 
-		operatorName: ifNotMigrating(caasoperator.Manifold(caasoperator.ManifoldConfig{
+		operatorName: ifOperatorFlag(caasoperator.Manifold(caasoperator.ManifoldConfig{
 			AgentName:             agentName,
 			APICallerName:         apiCallerName,
 			ClockName:             clockName,
@@ -288,12 +317,21 @@ var ifNotMigrating = engine.Housing{
 	Occupy: migrationFortressName,
 }.Decorate
 
+var ifOperatorFlag = engine.Housing{
+	Flags: []string{
+		migrationInactiveFlagName,
+		operatorFlagName,
+	},
+	Occupy: migrationFortressName,
+}.Decorate
+
 const (
 	agentName            = "agent"
 	apiConfigWatcherName = "api-config-watcher"
 	apiCallerName        = "api-caller"
 	clockName            = "clock"
 	operatorName         = "operator"
+	operatorFlagName     = "operator-flag"
 	logSenderName        = "log-sender"
 
 	charmDirName          = "charm-dir"
@@ -43,6 +43,7 @@ func (s *ManifoldsSuite) TestManifoldNames(c *gc.C) {
 		"clock",
 		"hook-retry-strategy",
 		"operator",
+		"operator-flag",
 		"logging-config-updater",
 		"log-sender",
 		"migration-fortress",
@@ -208,6 +209,16 @@ var expectedOperatorManifoldsWithDependencies = map[string][]string{
 		"hook-retry-strategy",
 		"migration-fortress",
 		"migration-inactive-flag",
+		"operator-flag",
+		"upgrade-steps-flag",
+		"upgrade-steps-gate"},
+
+	"operator-flag": {
+		"agent",
+		"api-caller",
+		"api-config-watcher",
+		"migration-fortress",
+		"migration-inactive-flag",
 		"upgrade-steps-flag",
 		"upgrade-steps-gate"},
 }
@@ -0,0 +1,52 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasoperator
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/worker/singular"
+)
+
+// operatorClaimer exposes the operator lease capabilities of the
+// CAASOperator API.
+type operatorClaimer interface {
+	ClaimOperator(application, holder string, duration time.Duration) error
+	WaitOperatorReleased(application string) error
+}
+
+// operatorFlagFacade adapts an operatorClaimer to the singular.Facade
+// interface, claiming the operator lease of an application on behalf
+// of a pod.
+type operatorFlagFacade struct {
+	claimer     operatorClaimer
+	application string
+	holder      string
+}
+
+func newOperatorFlagFacade(claimer operatorClaimer, application, holder string) singular.Facade {
+	return &operatorFlagFacade{
+		claimer:     claimer,
+		application: application,
+		holder:      holder,
+	}
+}
+
+// Claim is part of the singular.Facade interface.
+func (f *operatorFlagFacade) Claim(duration time.Duration) error {
+	err := f.claimer.ClaimOperator(f.application, f.holder, duration)
+	if errors.IsNotSupported(err) {
+		// The controller cannot arbitrate between replicas, so
+		// it only provisions one; this is the only one.
+		return nil
+	}
+	return errors.Trace(err)
+}
+
+// Wait is part of the singular.Facade interface.
+func (f *operatorFlagFacade) Wait() error {
+	return errors.Trace(f.claimer.WaitOperatorReleased(f.application))
+}
@@ -0,0 +1,68 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasoperator_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/jujud/agent/caasoperator"
+	"github.com/juju/juju/core/lease"
+)
+
+type OperatorFlagSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&OperatorFlagSuite{})
+
+func (s *OperatorFlagSuite) TestClaim(c *gc.C) {
+	claimer := &fakeOperatorClaimer{}
+	claimer.SetErrors(nil, lease.ErrClaimDenied)
+	facade := caasoperator.NewOperatorFlagFacade(claimer, "gitlab", "gitlab-operator-0")
+
+	err := facade.Claim(time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	err = facade.Claim(time.Minute)
+	c.Assert(errors.Cause(err), gc.Equals, lease.ErrClaimDenied)
+	claimer.CheckCall(c, 0, "ClaimOperator", "gitlab", "gitlab-operator-0", time.Minute)
+}
+
+func (s *OperatorFlagSuite) TestClaimNotSupported(c *gc.C) {
+	claimer := &fakeOperatorClaimer{}
+	claimer.SetErrors(errors.NotSupportedf("operator claims"))
+	facade := caasoperator.NewOperatorFlagFacade(claimer, "gitlab", "gitlab-operator-0")
+
+	err := facade.Claim(time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *OperatorFlagSuite) TestWait(c *gc.C) {
+	claimer := &fakeOperatorClaimer{}
+	facade := caasoperator.NewOperatorFlagFacade(claimer, "gitlab", "gitlab-operator-0")
+
+	err := facade.Wait()
+	c.Assert(err, jc.ErrorIsNil)
+	claimer.CheckCalls(c, []testing.StubCall{
+		{"WaitOperatorReleased", []interface{}{"gitlab"}},
+	})
+}
+
+type fakeOperatorClaimer struct {
+	testing.Stub
+}
+
+func (f *fakeOperatorClaimer) ClaimOperator(application, holder string, duration time.Duration) error {
+	f.MethodCall(f, "ClaimOperator", application, holder, duration)
+	return f.NextErr()
+}
+
+func (f *fakeOperatorClaimer) WaitOperatorReleased(application string) error {
+	f.MethodCall(f, "WaitOperatorReleased", application)
+	return f.NextErr()
+}
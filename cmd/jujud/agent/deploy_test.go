@@ -46,6 +46,22 @@ func (ctx *fakeContext) RecallUnit(unitName string) error {
 	return nil
 }
 
+func (ctx *fakeContext) RepairUnit(unitName string) error {
+	return nil
+}
+
+func (ctx *fakeContext) UnitAgentRunning(unitName string) (bool, error) {
+	return true, nil
+}
+
+func (ctx *fakeContext) RestartUnit(unitName string) error {
+	return nil
+}
+
+func (ctx *fakeContext) UpdateUnitPassword(unitName, password string) error {
+	return nil
+}
+
 func (ctx *fakeContext) DeployedUnits() ([]string, error) {
 	ctx.mu.Lock()
 	defer ctx.mu.Unlock()
@@ -33,6 +33,7 @@ var (
 	}
 	requireValidCredentialModelWorkers = []string{
 		"action-pruner",          // tertiary dependency: will be inactive because migration workers will be inactive
+		"action-webhooks",        // tertiary dependency: will be inactive because migration workers will be inactive
 		"application-scaler",     // tertiary dependency: will be inactive because migration workers will be inactive
 		"charm-revision-updater", // tertiary dependency: will be inactive because migration workers will be inactive
 		"compute-provisioner",
@@ -46,6 +47,7 @@ var (
 		"migration-inactive-flag", // secondary dependency: will be inactive because depends on model-upgrader
 		"migration-master",        // secondary dependency: will be inactive because depends on model-upgrader
 		"model-upgrader",
+		"network-reconciler",    // tertiary dependency: will be inactive because migration workers will be inactive
 		"remote-relations",      // tertiary dependency: will be inactive because migration workers will be inactive
 		"state-cleaner",         // tertiary dependency: will be inactive because migration workers will be inactive
 		"status-history-pruner", // tertiary dependency: will be inactive because migration workers will be inactive
@@ -55,6 +57,7 @@ var (
 	}
 	aliveModelWorkers = []string{
 		"action-pruner",
+		"action-webhooks",
 		"application-scaler",
 		"charm-revision-updater",
 		"compute-provisioner",
@@ -68,6 +71,7 @@ var (
 		"migration-fortress",
 		"migration-inactive-flag",
 		"migration-master",
+		"network-reconciler",
 		"remote-relations",
 		"state-cleaner",
 		"status-history-pruner",
@@ -139,6 +143,7 @@ var (
 	}
 	notMigratingMachineWorkers = []string{
 		"api-address-updater",
+		"ca-trust-updater",
 		"disk-manager",
 		"fan-configurer",
 		// "host-key-reporter", not stable, exits when done
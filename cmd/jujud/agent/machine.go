@@ -580,6 +580,7 @@ func (a *MachineAgent) makeEngineCreator(agentName string, previousAgentVersion
 			Clock:                   clock.WallClock,
 			ValidateMigration:       a.validateMigration,
 			PrometheusRegisterer:    a.prometheusRegistry,
+			PrometheusGatherer:      a.prometheusRegistry,
 			CentralHub:              a.centralHub,
 			PubSubReporter:          pubsubReporter,
 			PresenceRecorder:        presenceRecorder,
@@ -1050,6 +1051,7 @@ func (a *MachineAgent) startModelWorkers(modelUUID string, modelType state.Model
 		NewEnvironFunc:              newEnvirons,
 		NewContainerBrokerFunc:      newCAASBroker,
 		NewMigrationMaster:          migrationmaster.NewWorker,
+		PrometheusRegisterer:        a.prometheusRegistry,
 	}
 	var manifolds dependency.Manifolds
 	if modelType == state.ModelTypeIAAS {
@@ -1276,6 +1278,6 @@ func (a *MachineAgent) removeJujudSymlinks() (errs []error) {
 // running the tests and (2) get access to the *State used internally, so that
 // tests can be run without waiting for the 5s watcher refresh time to which we would
 // otherwise be restricted.
-var newDeployContext = func(st *apideployer.State, agentConfig agent.Config) deployer.Context {
-	return deployer.NewSimpleContext(agentConfig, st)
+var newDeployContext = func(st *apideployer.State, agentConfig agent.Config) (deployer.Context, error) {
+	return deployer.NewContext(agentConfig, st)
 }
@@ -27,10 +27,13 @@ import (
 	"github.com/juju/juju/caas"
 	"github.com/juju/juju/cmd/jujud/agent/engine"
 	containerbroker "github.com/juju/juju/container/broker"
+	"github.com/juju/juju/container/kvm"
 	"github.com/juju/juju/container/lxd"
+	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/machinelock"
 	"github.com/juju/juju/core/presence"
 	"github.com/juju/juju/core/raftlease"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/feature"
 	"github.com/juju/juju/state"
 	proxyconfig "github.com/juju/juju/utils/proxy"
@@ -45,6 +48,7 @@ import (
 	"github.com/juju/juju/worker/auditconfigupdater"
 	"github.com/juju/juju/worker/authenticationworker"
 	"github.com/juju/juju/worker/caasupgrader"
+	"github.com/juju/juju/worker/catrustupdater"
 	"github.com/juju/juju/worker/centralhub"
 	"github.com/juju/juju/worker/certupdater"
 	"github.com/juju/juju/worker/common"
@@ -72,6 +76,7 @@ import (
 	"github.com/juju/juju/worker/migrationflag"
 	"github.com/juju/juju/worker/migrationminion"
 	"github.com/juju/juju/worker/modelcache"
+	"github.com/juju/juju/worker/modelstats"
 	"github.com/juju/juju/worker/modelworkermanager"
 	"github.com/juju/juju/worker/peergrouper"
 	prworker "github.com/juju/juju/worker/presence"
@@ -86,6 +91,7 @@ import (
 	"github.com/juju/juju/worker/reboot"
 	"github.com/juju/juju/worker/restorewatcher"
 	"github.com/juju/juju/worker/resumer"
+	"github.com/juju/juju/worker/saturationmonitor"
 	"github.com/juju/juju/worker/singular"
 	workerstate "github.com/juju/juju/worker/state"
 	"github.com/juju/juju/worker/stateconfigwatcher"
@@ -110,6 +116,18 @@ const (
 	// leaseRequestTopic is the pubsub topic that lease FSM updates
 	// will be published on.
 	leaseRequestTopic = "lease.request"
+
+	// modelStatsInterval is the interval between collections of the
+	// number and size of the documents of every model.
+	modelStatsInterval = 10 * time.Minute
+
+	// saturationMonitorInterval is the interval between evaluations
+	// of the controller saturation alert thresholds.
+	saturationMonitorInterval = time.Minute
+
+	// saturationWebhookTimeout is how long the saturation monitor
+	// waits for the alert webhook to respond.
+	saturationWebhookTimeout = 10 * time.Second
 )
 
 // ManifoldsConfig allows specialisation of the result of Manifolds.
@@ -179,11 +197,15 @@ type ManifoldsConfig struct {
 	// running the tests and (2) get access to the *State used internally, so that
 	// tests can be run without waiting for the 5s watcher refresh time to which we would
 	// otherwise be restricted.
-	NewDeployContext func(st *apideployer.State, agentConfig coreagent.Config) deployer.Context
+	NewDeployContext func(st *apideployer.State, agentConfig coreagent.Config) (deployer.Context, error)
 
 	// Clock supplies timekeeping services to various workers.
 	Clock clock.Clock
 
+	// MachineShutdownTasks are run by the machiner when the machine
+	// becomes Dying, before it is made Dead.
+	MachineShutdownTasks []machiner.ShutdownTask
+
 	// ValidateMigration is called by the migrationminion during the
 	// migration process to check that the agent will be ok when
 	// connected to the new target controller.
@@ -193,6 +215,10 @@ type ManifoldsConfig struct {
 	// by workers to register Prometheus metric collectors.
 	PrometheusRegisterer prometheus.Registerer
 
+	// PrometheusGatherer is a prometheus.Gatherer that may be used by
+	// workers to read the metrics registered with PrometheusRegisterer.
+	PrometheusGatherer prometheus.Gatherer
+
 	// CentralHub is the primary hub that exists in the apiserver.
 	CentralHub *pubsub.StructuredHub
 
@@ -559,6 +585,7 @@ func commonManifolds(config ManifoldsConfig) dependency.Manifolds {
 			LoggingContext:  loggo.DefaultContext(),
 			Logger:          loggo.GetLogger("juju.worker.logger"),
 			UpdateAgentFunc: config.UpdateLoggerConfig,
+			CentralHubName:  centralHubName,
 		})),
 
 		// The log sender is a leaf worker that sends log messages to some
@@ -604,6 +631,29 @@ func commonManifolds(config ManifoldsConfig) dependency.Manifolds {
 			},
 		))),
 
+		modelStatsName: ifNotMigrating(ifPrimaryController(modelstats.Manifold(
+			modelstats.ManifoldConfig{
+				ClockName:            clockName,
+				StateName:            stateName,
+				Interval:             modelStatsInterval,
+				PrometheusRegisterer: config.PrometheusRegisterer,
+				NewWorker:            modelstats.New,
+			},
+		))),
+
+		saturationMonitorName: ifController(saturationmonitor.Manifold(
+			saturationmonitor.ManifoldConfig{
+				AgentName:          agentName,
+				ClockName:          clockName,
+				HubName:            centralHubName,
+				StateName:          stateName,
+				Interval:           saturationMonitorInterval,
+				WebhookTimeout:     saturationWebhookTimeout,
+				PrometheusGatherer: config.PrometheusGatherer,
+				NewWorker:          saturationmonitor.New,
+			},
+		)),
+
 		httpServerArgsName: httpserverargs.Manifold(httpserverargs.ManifoldConfig{
 			ClockName:             clockName,
 			ControllerPortName:    controllerPortName,
@@ -817,6 +867,15 @@ func IAASManifolds(config ManifoldsConfig) dependency.Manifolds {
 			Clock:         config.Clock,
 		})),
 
+		caTrustUpdaterName: ifNotMigrating(catrustupdater.Manifold(catrustupdater.ManifoldConfig{
+			AgentName:        agentName,
+			APICallerName:    apiCallerName,
+			RootDir:          config.RootDir,
+			NewFacade:        catrustupdater.NewFacade,
+			NewWorker:        catrustupdater.NewWorker,
+			UpdateTrustStore: catrustupdater.UpdateCACertificates,
+		})),
+
 		certificateUpdaterName: ifFullyUpgraded(certupdater.Manifold(certupdater.ManifoldConfig{
 			AgentName:                agentName,
 			StateName:                stateName,
@@ -832,6 +891,8 @@ func IAASManifolds(config ManifoldsConfig) dependency.Manifolds {
 			AgentName:         agentName,
 			APICallerName:     apiCallerName,
 			FanConfigurerName: fanConfigurerName,
+			Clock:             config.Clock,
+			ShutdownTasks:     config.MachineShutdownTasks,
 		})),
 
 		// The diskmanager worker periodically lists block devices on the
@@ -945,6 +1006,11 @@ func IAASManifolds(config ManifoldsConfig) dependency.Manifolds {
 			Logger:        loggo.GetLogger("juju.worker.instancemutater"),
 			NewClient:     instancemutater.NewClient,
 			NewWorker:     instancemutater.NewContainerWorker,
+			ContainerBrokers: map[instance.ContainerType]environs.MutaterBroker{
+				instance.KVM: kvm.NewMutaterBroker(),
+			},
+
+			PrometheusRegisterer: config.PrometheusRegisterer,
 		})),
 	}
 
@@ -1075,6 +1141,7 @@ const (
 	machineActionName             = "machine-action-runner"
 	hostKeyReporterName           = "host-key-reporter"
 	fanConfigurerName             = "fan-configurer"
+	caTrustUpdaterName            = "ca-trust-updater"
 	externalControllerUpdaterName = "external-controller-updater"
 	globalClockUpdaterName        = "global-clock-updater"
 	leaseClockUpdaterName         = "lease-clock-updater"
@@ -1082,6 +1149,8 @@ const (
 	isControllerFlagName          = "is-controller-flag"
 	instanceMutaterName           = "instance-mutater"
 	txnPrunerName                 = "transaction-pruner"
+	modelStatsName                = "model-stats"
+	saturationMonitorName         = "saturation-monitor"
 	certificateWatcherName        = "certificate-watcher"
 	modelCacheName                = "model-cache"
 	modelCacheInitializedFlagName = "model-cache-initialized-flag"
@@ -66,6 +66,7 @@ func (ms *ManifoldsSuite) TestManifoldNamesIAAS(c *gc.C) {
 			"api-server",
 			"audit-config-updater",
 			"broker-tracker",
+			"ca-trust-updater",
 			"central-hub",
 			"certificate-updater",
 			"certificate-watcher",
@@ -95,6 +96,7 @@ func (ms *ManifoldsSuite) TestManifoldNamesIAAS(c *gc.C) {
 			"model-cache",
 			"model-cache-initialized-flag",
 			"model-cache-initialized-gate",
+			"model-stats",
 			"model-worker-manager",
 			"peer-grouper",
 			"presence",
@@ -108,6 +110,7 @@ func (ms *ManifoldsSuite) TestManifoldNamesIAAS(c *gc.C) {
 			"raft-transport",
 			"reboot-executor",
 			"restore-watcher",
+			"saturation-monitor",
 			"ssh-authkeys-updater",
 			"ssh-identity-writer",
 			"state",
@@ -162,6 +165,7 @@ func (ms *ManifoldsSuite) TestManifoldNamesCAAS(c *gc.C) {
 			"model-cache",
 			"model-cache-initialized-flag",
 			"model-cache-initialized-gate",
+			"model-stats",
 			"model-worker-manager",
 			"peer-grouper",
 			"presence",
@@ -173,6 +177,7 @@ func (ms *ManifoldsSuite) TestManifoldNamesCAAS(c *gc.C) {
 			"raft-leader-flag",
 			"raft-transport",
 			"restore-watcher",
+			"saturation-monitor",
 			"ssh-identity-writer",
 			"state",
 			"state-config-watcher",
@@ -239,6 +244,7 @@ func (ms *ManifoldsSuite) TestMigrationGuardsUsed(c *gc.C) {
 		"presence",
 		"pubsub-forwarder",
 		"restore-watcher",
+		"saturation-monitor",
 		"state",
 		"state-config-watcher",
 		"termination-signal-handler",
@@ -287,9 +293,11 @@ func (*ManifoldsSuite) TestSingularGuardsUsed(c *gc.C) {
 		"lease-manager",
 		"legacy-leases-flag",
 		"raft-transport",
+		"saturation-monitor",
 	)
 	primaryControllerWorkers := set.NewStrings(
 		"external-controller-updater",
+		"model-stats",
 		"transaction-pruner",
 	)
 	for name, manifold := range manifolds {
@@ -517,6 +525,18 @@ var expectedMachineManifoldsWithDependencies = map[string][]string{
 		"upgrade-steps-gate",
 	},
 
+	"ca-trust-updater": {
+		"agent",
+		"api-caller",
+		"api-config-watcher",
+		"migration-fortress",
+		"migration-inactive-flag",
+		"upgrade-check-flag",
+		"upgrade-check-gate",
+		"upgrade-steps-flag",
+		"upgrade-steps-gate",
+	},
+
 	"fan-configurer": {
 		"agent",
 		"api-caller",
@@ -655,8 +675,10 @@ var expectedMachineManifoldsWithDependencies = map[string][]string{
 		"agent",
 		"api-caller",
 		"api-config-watcher",
+		"central-hub",
 		"migration-fortress",
 		"migration-inactive-flag",
+		"state-config-watcher",
 		"upgrade-check-flag",
 		"upgrade-check-gate",
 		"upgrade-steps-flag",
@@ -747,6 +769,23 @@ var expectedMachineManifoldsWithDependencies = map[string][]string{
 		"state-config-watcher",
 	},
 
+	"model-stats": {
+		"agent",
+		"api-caller",
+		"api-config-watcher",
+		"clock",
+		"is-controller-flag",
+		"is-primary-controller-flag",
+		"migration-fortress",
+		"migration-inactive-flag",
+		"state",
+		"state-config-watcher",
+		"upgrade-check-flag",
+		"upgrade-check-gate",
+		"upgrade-steps-flag",
+		"upgrade-steps-gate",
+	},
+
 	"model-worker-manager": {
 		"agent",
 		"state",
@@ -901,6 +940,15 @@ var expectedMachineManifoldsWithDependencies = map[string][]string{
 
 	"restore-watcher": {"agent", "state", "state-config-watcher"},
 
+	"saturation-monitor": {
+		"agent",
+		"central-hub",
+		"clock",
+		"is-controller-flag",
+		"state",
+		"state-config-watcher",
+	},
+
 	"ssh-authkeys-updater": {
 		"agent",
 		"api-caller",
@@ -9,6 +9,7 @@ import (
 	"github.com/juju/clock"
 	"github.com/juju/loggo"
 	"github.com/juju/utils/voyeur"
+	"github.com/prometheus/client_golang/prometheus"
 	"gopkg.in/juju/worker.v1"
 	"gopkg.in/juju/worker.v1/dependency"
 
@@ -23,7 +24,9 @@ import (
 	"github.com/juju/juju/core/life"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/worker/actionpruner"
+	"github.com/juju/juju/worker/actionwebhooks"
 	"github.com/juju/juju/worker/agent"
+	"github.com/juju/juju/worker/annotationtagger"
 	"github.com/juju/juju/worker/apicaller"
 	"github.com/juju/juju/worker/apiconfigwatcher"
 	"github.com/juju/juju/worker/applicationscaler"
@@ -51,6 +54,7 @@ import (
 	"github.com/juju/juju/worker/migrationflag"
 	"github.com/juju/juju/worker/migrationmaster"
 	"github.com/juju/juju/worker/modelupgrader"
+	"github.com/juju/juju/worker/networkreconciler"
 	"github.com/juju/juju/worker/provisioner"
 	"github.com/juju/juju/worker/pruner"
 	"github.com/juju/juju/worker/remoterelations"
@@ -113,6 +117,10 @@ type ManifoldsConfig struct {
 	// NewMigrationMaster is called to create a new migrationmaster
 	// worker.
 	NewMigrationMaster func(migrationmaster.Config) (worker.Worker, error)
+
+	// PrometheusRegisterer is a prometheus.Registerer that may be used
+	// by workers to register Prometheus metric collectors.
+	PrometheusRegisterer prometheus.Registerer
 }
 
 // commonManifolds returns a set of interdependent dependency manifolds that will
@@ -264,11 +272,19 @@ func commonManifolds(config ManifoldsConfig) dependency.Manifolds {
 			NewFacade:     actionpruner.NewFacade,
 			PruneInterval: config.ActionPrunerInterval,
 		})),
+		actionWebhooksName: ifNotMigrating(actionwebhooks.Manifold(actionwebhooks.ManifoldConfig{
+			APICallerName: apiCallerName,
+			ClockName:     clockName,
+			Timeout:       actionWebhookTimeout,
+			RetryDelay:    actionWebhookRetryDelay,
+			NewFacade:     actionwebhooks.NewFacade,
+			NewWorker:     actionwebhooks.New,
+		})),
 		logForwarderName: ifNotDead(logforwarder.Manifold(logforwarder.ManifoldConfig{
 			APICallerName: apiCallerName,
 			Sinks: []logforwarder.LogSinkSpec{{
 				Name:   "juju-log-forward",
-				OpenFn: sinks.OpenSyslog,
+				OpenFn: sinks.Open,
 			}},
 		})),
 		// The environ upgrader runs on all controller agents, and
@@ -376,6 +392,24 @@ func IAASManifolds(config ManifoldsConfig) dependency.Manifolds {
 			Delay:                        config.InstPollerAggregationDelay,
 			NewCredentialValidatorFacade: common.NewCredentialInvalidatorFacade,
 		}))),
+		annotationTaggerName: ifNotMigrating(ifCredentialValid(annotationtagger.Manifold(annotationtagger.ManifoldConfig{
+			APICallerName:                apiCallerName,
+			EnvironName:                  environTrackerName,
+			ClockName:                    clockName,
+			Interval:                     annotationTaggerInterval,
+			NewFacade:                    annotationtagger.NewFacade,
+			NewWorker:                    annotationtagger.New,
+			NewCredentialValidatorFacade: common.NewCredentialInvalidatorFacade,
+		}))),
+		networkReconcilerName: ifNotMigrating(ifCredentialValid(networkreconciler.Manifold(networkreconciler.ManifoldConfig{
+			APICallerName:                apiCallerName,
+			EnvironName:                  environTrackerName,
+			ClockName:                    clockName,
+			Interval:                     networkReconcilerInterval,
+			NewFacade:                    networkreconciler.NewFacade,
+			NewWorker:                    networkreconciler.New,
+			NewCredentialValidatorFacade: common.NewCredentialInvalidatorFacade,
+		}))),
 		metricWorkerName: ifNotMigrating(metricworker.Manifold(metricworker.ManifoldConfig{
 			APICallerName: apiCallerName,
 		})),
@@ -402,6 +436,8 @@ func IAASManifolds(config ManifoldsConfig) dependency.Manifolds {
 			Logger:        loggo.GetLogger("juju.worker.instancemutater"),
 			NewClient:     instancemutater.NewClient,
 			NewWorker:     instancemutater.NewEnvironWorker,
+
+			PrometheusRegisterer: config.PrometheusRegisterer,
 		})),
 	}
 
@@ -431,6 +467,7 @@ func CAASManifolds(config ManifoldsConfig) dependency.Manifolds {
 
 		caasBrokerTrackerName: ifResponsible(caasbroker.Manifold(caasbroker.ManifoldConfig{
 			APICallerName:          apiCallerName,
+			ClockName:              clockName,
 			NewContainerBrokerFunc: config.NewContainerBrokerFunc,
 		})),
 		caasFirewallerName: ifNotMigrating(caasfirewaller.Manifold(
@@ -564,6 +601,24 @@ var (
 	}.Decorate
 )
 
+const (
+	// actionWebhookTimeout is how long the action webhook worker waits
+	// for a webhook to respond.
+	actionWebhookTimeout = 30 * time.Second
+
+	// actionWebhookRetryDelay is how long the action webhook worker
+	// waits before trying failed webhooks again.
+	actionWebhookRetryDelay = time.Minute
+
+	// annotationTaggerInterval is how often the annotation tagger
+	// reconciles instance tags with machine and unit annotations.
+	annotationTaggerInterval = 5 * time.Minute
+
+	// networkReconcilerInterval is how often the network reconciler
+	// compares the model's subnets with those of the provider.
+	networkReconcilerInterval = 10 * time.Minute
+)
+
 const (
 	agentName            = "agent"
 	clockName            = "clock"
@@ -590,11 +645,14 @@ const (
 	unitAssignerName         = "unit-assigner"
 	applicationScalerName    = "application-scaler"
 	instancePollerName       = "instance-poller"
+	annotationTaggerName     = "annotation-tagger"
+	networkReconcilerName    = "network-reconciler"
 	charmRevisionUpdaterName = "charm-revision-updater"
 	metricWorkerName         = "metric-worker"
 	stateCleanerName         = "state-cleaner"
 	statusHistoryPrunerName  = "status-history-pruner"
 	actionPrunerName         = "action-pruner"
+	actionWebhooksName       = "action-webhooks"
 	machineUndertakerName    = "machine-undertaker"
 	remoteRelationsName      = "remote-relations"
 	logForwarderName         = "log-forwarder"
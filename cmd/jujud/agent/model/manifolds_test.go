@@ -33,7 +33,9 @@ func (s *ManifoldsSuite) TestIAASNames(c *gc.C) {
 	// also fail. Search for 'ModelWorkers' to find affected vars.
 	c.Check(actual.SortedValues(), jc.DeepEquals, []string{
 		"action-pruner",
+		"action-webhooks",
 		"agent",
+		"annotation-tagger",
 		"api-caller",
 		"api-config-watcher",
 		"application-scaler",
@@ -54,6 +56,7 @@ func (s *ManifoldsSuite) TestIAASNames(c *gc.C) {
 		"model-upgrade-gate",
 		"model-upgraded-flag",
 		"model-upgrader",
+		"network-reconciler",
 		"not-alive-flag",
 		"not-dead-flag",
 		"remote-relations",
@@ -78,6 +81,7 @@ func (s *ManifoldsSuite) TestCAASNames(c *gc.C) {
 	// also fail. Search for 'ModelWorkers' to find affected vars.
 	c.Check(actual.SortedValues(), jc.DeepEquals, []string{
 		"action-pruner",
+		"action-webhooks",
 		"agent",
 		"api-caller",
 		"api-config-watcher",
@@ -200,18 +204,30 @@ var expectedCAASModelManifoldsWithDependencies = map[string][]string{
 		"model-upgraded-flag",
 		"not-dead-flag"},
 
+	"action-webhooks": {
+		"agent",
+		"api-caller",
+		"clock",
+		"is-responsible-flag",
+		"migration-fortress",
+		"migration-inactive-flag",
+		"model-upgrade-gate",
+		"model-upgraded-flag",
+		"not-dead-flag"},
+
 	"agent": {},
 
 	"api-caller": {"agent"},
 
 	"api-config-watcher": {"agent"},
 
-	"caas-broker-tracker": {"agent", "api-caller", "is-responsible-flag"},
+	"caas-broker-tracker": {"agent", "api-caller", "clock", "is-responsible-flag"},
 
 	"caas-firewaller": {
 		"agent",
 		"api-caller",
 		"caas-broker-tracker",
+		"clock",
 		"is-responsible-flag",
 		"migration-fortress",
 		"migration-inactive-flag",
@@ -248,6 +264,7 @@ var expectedCAASModelManifoldsWithDependencies = map[string][]string{
 		"agent",
 		"api-caller",
 		"caas-broker-tracker",
+		"clock",
 		"is-responsible-flag",
 		"migration-fortress",
 		"migration-inactive-flag",
@@ -346,6 +363,7 @@ var expectedCAASModelManifoldsWithDependencies = map[string][]string{
 		"agent",
 		"api-caller",
 		"caas-broker-tracker",
+		"clock",
 		"is-responsible-flag",
 		"model-upgrade-gate",
 		"model-upgraded-flag",
@@ -369,8 +387,34 @@ var expectedIAASModelManifoldsWithDependencies = map[string][]string{
 		"not-dead-flag",
 	},
 
+	"action-webhooks": {
+		"agent",
+		"api-caller",
+		"clock",
+		"is-responsible-flag",
+		"migration-fortress",
+		"migration-inactive-flag",
+		"model-upgrade-gate",
+		"model-upgraded-flag",
+		"not-dead-flag",
+	},
+
 	"agent": {},
 
+	"annotation-tagger": {
+		"agent",
+		"api-caller",
+		"clock",
+		"environ-tracker",
+		"is-responsible-flag",
+		"migration-fortress",
+		"migration-inactive-flag",
+		"model-upgrade-gate",
+		"model-upgraded-flag",
+		"not-dead-flag",
+		"valid-credential-flag",
+	},
+
 	"api-caller": {"agent"},
 
 	"api-config-watcher": {"agent"},
@@ -527,6 +571,20 @@ var expectedIAASModelManifoldsWithDependencies = map[string][]string{
 		"valid-credential-flag",
 	},
 
+	"network-reconciler": {
+		"agent",
+		"api-caller",
+		"clock",
+		"environ-tracker",
+		"is-responsible-flag",
+		"migration-fortress",
+		"migration-inactive-flag",
+		"model-upgrade-gate",
+		"model-upgraded-flag",
+		"not-dead-flag",
+		"valid-credential-flag",
+	},
+
 	"not-alive-flag": {"agent", "api-caller"},
 
 	"not-dead-flag": {"agent", "api-caller"},
@@ -234,11 +234,11 @@ func patchDeployContext(c *gc.C, st *state.State) (*fakeContext, func()) {
 		deployed: make(set.Strings),
 	}
 	orig := newDeployContext
-	newDeployContext = func(dst *apideployer.State, agentConfig agent.Config) deployer.Context {
+	newDeployContext = func(dst *apideployer.State, agentConfig agent.Config) (deployer.Context, error) {
 		ctx.st = st
 		ctx.agentConfig = agentConfig
 		ctx.inited.trigger()
-		return ctx
+		return ctx, nil
 	}
 	return ctx, func() { newDeployContext = orig }
 }
@@ -438,6 +438,7 @@ func (FakeAgentConfig) CheckArgs([]string) error { return nil }
 type minModelWorkersEnviron struct {
 	environs.Environ
 	environs.LXDProfiler
+	environs.Networking
 }
 
 func (e *minModelWorkersEnviron) Config() *config.Config {
@@ -469,6 +470,14 @@ func (env *minModelWorkersEnviron) LXDProfileNames(containerName string) ([]stri
 	return nil, nil
 }
 
+func (env *minModelWorkersEnviron) SupportsSpaceDiscovery(context.ProviderCallContext) (bool, error) {
+	return false, nil
+}
+
+func (env *minModelWorkersEnviron) Subnets(context.ProviderCallContext, instance.Id, []network.Id) ([]network.SubnetInfo, error) {
+	return nil, nil
+}
+
 func (env *minModelWorkersEnviron) AssignLXDProfiles(instId string, profilesNames []string, profilePosts []lxdprofile.ProfilePost) (current []string, err error) {
 	return profilesNames, nil
 }
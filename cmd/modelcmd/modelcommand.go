@@ -353,8 +353,8 @@ func (c *ModelCommandBase) modelDetails(controllerName, modelIdentifier string)
 }
 
 // modelFromStore attempts to retrieve details from the store, first under the
-// assumption that the input identifier is a model name, then using treating
-// the identifier as a full or partial model UUID.
+// assumption that the input identifier is a model name, then treating the
+// identifier as a model alias, and finally as a full or partial model UUID.
 // If a model is successfully located its name and details are returned.
 func (c *ModelCommandBase) modelFromStore(controllerName, modelIdentifier string) (
 	string, *jujuclient.ModelDetails, error,
@@ -369,14 +369,22 @@ func (c *ModelCommandBase) modelFromStore(controllerName, modelIdentifier string
 		return "", nil, errors.Trace(err)
 	}
 
+	models, allErr := c.store.AllModels(controllerName)
+	if allErr != nil && !errors.IsNotFound(allErr) {
+		return "", nil, errors.Trace(allErr)
+	}
+
+	// Model aliases are unique within a controller, so an alias
+	// match is preferred to a partial UUID match.
+	for name, details := range models {
+		if details.ModelAlias != "" && details.ModelAlias == modelIdentifier {
+			return name, &details, nil
+		}
+	}
+
 	// If the identifier is at 6 least characters long,
 	// attempt to match one of the stored model UUIDs.
 	if len(modelIdentifier) > 5 {
-		models, err := c.store.AllModels(controllerName)
-		if err != nil {
-			return "", nil, errors.Trace(err)
-		}
-
 		for name, details := range models {
 			if strings.HasPrefix(details.ModelUUID, modelIdentifier) {
 				return name, &details, nil
@@ -350,6 +350,20 @@ func (s *ModelCommandSuite) TestPartialModelUUIDTooShortError(c *gc.C) {
 	c.Assert(err, jc.Satisfies, errors.IsNotFound)
 }
 
+func (s *ModelCommandSuite) TestModelAliasSuccess(c *gc.C) {
+	s.setupIAASModel(c)
+	err := s.store.UpdateModel("foo", "bar/otherfoo",
+		jujuclient.ModelDetails{ModelUUID: "uuidfoo2", ModelAlias: "f2", ModelType: model.CAAS})
+	c.Assert(err, jc.ErrorIsNil)
+
+	cmd, err := runAllowedCAASCommand(c, s.store, "-m", "f2")
+	c.Assert(err, jc.ErrorIsNil)
+
+	modelType, err := cmd.ModelType()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(modelType, gc.Equals, model.CAAS)
+}
+
 func (s *ModelCommandSuite) setupIAASModel(c *gc.C) {
 	s.store.Controllers["foo"] = jujuclient.ControllerDetails{}
 	s.store.CurrentControllerName = "foo"
@@ -110,6 +110,7 @@ var statusColors = map[status.Status]*ansiterm.Context{
 	status.Unknown:     WarningHighlight,
 	status.Detaching:   WarningHighlight,
 	status.Detached:    WarningHighlight,
+	status.Draining:    WarningHighlight,
 	// bad
 	status.Blocked:    ErrorHighlight,
 	status.Down:       ErrorHighlight,
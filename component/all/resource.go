@@ -12,6 +12,7 @@ import (
 	"github.com/juju/juju/apiserver/facades/controller/charmrevisionupdater"
 	"github.com/juju/juju/resource"
 	internalclient "github.com/juju/juju/resource/api/private/client"
+	"github.com/juju/juju/resource/cache"
 	"github.com/juju/juju/resource/context"
 	contextcmd "github.com/juju/juju/resource/context/cmd"
 	"github.com/juju/juju/resource/resourceadapters"
@@ -69,8 +70,11 @@ func (r resources) registerHookContext() {
 			if err != nil {
 				return nil, errors.Trace(err)
 			}
-			// TODO(ericsnow) Pass the unit's tag through to the component?
-			return context.NewContextAPI(hctxClient, config.DataDir), nil
+			var resourceCache *cache.Cache
+			if config.ResourceCacheDir != "" {
+				resourceCache = cache.New(config.ResourceCacheDir)
+			}
+			return context.NewContextAPI(hctxClient, config.DataDir, config.UnitName, resourceCache), nil
 		},
 	)
 
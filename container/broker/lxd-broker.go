@@ -250,3 +250,30 @@ func (broker *lxdBroker) AssignLXDProfiles(instId string, profilesNames []string
 	}
 	return profileMgr.AssignLXDProfiles(instId, profilesNames, profilePosts)
 }
+
+// UnusedLXDProfileNames implements environs.LXDProfileCleaner.
+func (broker *lxdBroker) UnusedLXDProfileNames() ([]string, error) {
+	cleaner, ok := broker.manager.(environs.LXDProfileCleaner)
+	if !ok {
+		return []string{}, nil
+	}
+	return cleaner.UnusedLXDProfileNames()
+}
+
+// DeleteLXDProfile implements environs.LXDProfileCleaner.
+func (broker *lxdBroker) DeleteLXDProfile(name string) error {
+	cleaner, ok := broker.manager.(environs.LXDProfileCleaner)
+	if !ok {
+		return nil
+	}
+	return cleaner.DeleteLXDProfile(name)
+}
+
+// ValidateLXDProfile implements environs.LXDProfileValidator.
+func (broker *lxdBroker) ValidateLXDProfile(profile lxdprofile.Profile) error {
+	validator, ok := broker.manager.(environs.LXDProfileValidator)
+	if !ok {
+		return nil
+	}
+	return validator.ValidateLXDProfile(profile)
+}
@@ -0,0 +1,108 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"encoding/xml"
+	"regexp"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// pciAddressPattern matches a PCI address of the form used by lxd,
+// domain:bus:slot.function, where the domain is optional.
+var pciAddressPattern = regexp.MustCompile(`^(?:([[:xdigit:]]{4}):)?([[:xdigit:]]{2}):([[:xdigit:]]{2})\.([[:xdigit:]])$`)
+
+// HostDev is a device on the host which is passed through to a guest, as
+// declared by a charm for its kvm containers. It is attached to, and
+// detached from, running guests rather than being part of the domain
+// definition that we create.
+// See: https://libvirt.org/formatdomain.html#elementsHostDev
+type HostDev struct {
+	XMLName xml.Name      `xml:"hostdev"`
+	Mode    string        `xml:"mode,attr"`
+	Type    string        `xml:"type,attr"`
+	Managed string        `xml:"managed,attr,omitempty"`
+	Source  HostDevSource `xml:"source"`
+}
+
+// HostDevSource identifies the host device, by its vendor and product for
+// usb devices, or by its address for pci devices.
+// See: HostDev
+type HostDevSource struct {
+	Vendor  *HostDevID      `xml:"vendor,omitempty"`
+	Product *HostDevID      `xml:"product,omitempty"`
+	Address *HostDevAddress `xml:"address,omitempty"`
+}
+
+// HostDevID is a usb vendor or product id.
+// See: HostDevSource
+type HostDevID struct {
+	ID string `xml:"id,attr"`
+}
+
+// HostDevAddress is the address of a pci device on the host.
+// See: HostDevSource
+type HostDevAddress struct {
+	Domain   string `xml:"domain,attr"`
+	Bus      string `xml:"bus,attr"`
+	Slot     string `xml:"slot,attr"`
+	Function string `xml:"function,attr"`
+}
+
+// NewUSBHostDev returns a HostDev for the usb device on the host with the
+// given vendor and product ids, which are hexadecimal, as used by lxd.
+func NewUSBHostDev(vendorID, productID string) (HostDev, error) {
+	if vendorID == "" {
+		return HostDev{}, errors.NotValidf("empty usb vendor id")
+	}
+	dev := HostDev{
+		Mode:    "subsystem",
+		Type:    "usb",
+		Managed: "yes",
+		Source: HostDevSource{
+			Vendor: &HostDevID{ID: hexID(vendorID)},
+		},
+	}
+	if productID != "" {
+		dev.Source.Product = &HostDevID{ID: hexID(productID)}
+	}
+	return dev, nil
+}
+
+// NewPCIHostDev returns a HostDev for the pci device on the host at the
+// given address, which is in the form used by lxd, e.g. "0000:01:00.0".
+func NewPCIHostDev(address string) (HostDev, error) {
+	parts := pciAddressPattern.FindStringSubmatch(address)
+	if parts == nil {
+		return HostDev{}, errors.NotValidf("pci address %q", address)
+	}
+	domain := parts[1]
+	if domain == "" {
+		domain = "0000"
+	}
+	return HostDev{
+		Mode:    "subsystem",
+		Type:    "pci",
+		Managed: "yes",
+		Source: HostDevSource{
+			Address: &HostDevAddress{
+				Domain:   hexID(domain),
+				Bus:      hexID(parts[2]),
+				Slot:     hexID(parts[3]),
+				Function: hexID(parts[4]),
+			},
+		},
+	}, nil
+}
+
+// hexID returns the given hexadecimal id with the 0x prefix that libvirt
+// requires.
+func hexID(id string) string {
+	if strings.HasPrefix(id, "0x") {
+		return id
+	}
+	return "0x" + id
+}
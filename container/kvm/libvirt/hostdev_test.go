@@ -0,0 +1,61 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt_test
+
+import (
+	"encoding/xml"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	. "github.com/juju/juju/container/kvm/libvirt"
+)
+
+type hostDevSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&hostDevSuite{})
+
+func (hostDevSuite) TestNewUSBHostDev(c *gc.C) {
+	dev, err := NewUSBHostDev("046d", "0x082d")
+	c.Assert(err, jc.ErrorIsNil)
+	ml, err := xml.MarshalIndent(&dev, "", "    ")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(ml), gc.Equals, `
+<hostdev mode="subsystem" type="usb" managed="yes">
+    <source>
+        <vendor id="0x046d"></vendor>
+        <product id="0x082d"></product>
+    </source>
+</hostdev>`[1:])
+}
+
+func (hostDevSuite) TestNewUSBHostDevNoVendor(c *gc.C) {
+	_, err := NewUSBHostDev("", "082d")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (hostDevSuite) TestNewPCIHostDev(c *gc.C) {
+	for i, address := range []string{"0000:01:00.0", "01:00.0"} {
+		c.Logf("test %d: %s", i, address)
+		dev, err := NewPCIHostDev(address)
+		c.Assert(err, jc.ErrorIsNil)
+		ml, err := xml.MarshalIndent(&dev, "", "    ")
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(string(ml), gc.Equals, `
+<hostdev mode="subsystem" type="pci" managed="yes">
+    <source>
+        <address domain="0x0000" bus="0x01" slot="0x00" function="0x0"></address>
+    </source>
+</hostdev>`[1:])
+	}
+}
+
+func (hostDevSuite) TestNewPCIHostDevInvalidAddress(c *gc.C) {
+	_, err := NewPCIHostDev("01:00")
+	c.Assert(err, gc.ErrorMatches, `pci address "01:00" not valid`)
+}
@@ -0,0 +1,243 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package kvm
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/juju/errors"
+	"github.com/juju/os/series"
+	"github.com/juju/utils"
+	"gopkg.in/yaml.v2"
+
+	"github.com/juju/juju/container/kvm/libvirt"
+	"github.com/juju/juju/core/lxdprofile"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/juju/paths"
+)
+
+// profileDir is the directory, alongside the guests directory, in which
+// the charm profiles assigned to each guest are recorded.
+const profileDir = "profiles"
+
+// guestProfiles records the charm lxd profiles assigned to a guest, and the
+// host devices, as libvirt hostdev XML, attached to it for each profile.
+type guestProfiles struct {
+	Profiles []string            `yaml:"profiles"`
+	Devices  map[string][]string `yaml:"devices,omitempty"`
+}
+
+// NewMutaterBroker returns an environs.MutaterBroker which applies the
+// charm lxd profiles assigned to kvm guests by attaching the host devices
+// that they declare to the guests. Only usb devices, and gpu devices that
+// are identified by their pci address, can be attached; the profiles'
+// config is ignored, as it only has meaning to lxd.
+func NewMutaterBroker() environs.MutaterBroker {
+	return &mutaterBroker{
+		runCmd:     run,
+		pathfinder: paths.DataDir,
+	}
+}
+
+type mutaterBroker struct {
+	runCmd     runFunc
+	pathfinder func(string) (string, error)
+}
+
+var _ environs.LXDProfileValidator = (*mutaterBroker)(nil)
+
+// AssignLXDProfiles implements environs.MutaterBroker. The host devices
+// of the profiles being removed, or replaced, are detached from the guest
+// before those of the new profiles are attached.
+func (b *mutaterBroker) AssignLXDProfiles(
+	instId string, profilesNames []string, profilePosts []lxdprofile.ProfilePost,
+) (_ []string, err error) {
+	path, err := guestProfilesPath(b.pathfinder, instId)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	guest, err := readGuestProfiles(path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// Record the devices attached to the guest, even if we fail part way
+	// through, so that they can be detached later.
+	defer func() {
+		if writeErr := writeGuestProfiles(path, guest); writeErr != nil && err == nil {
+			err = errors.Trace(writeErr)
+		}
+	}()
+
+	for _, p := range profilePosts {
+		for len(guest.Devices[p.Name]) > 0 {
+			devs := guest.Devices[p.Name]
+			if err := b.runDeviceCmd("detach-device", instId, devs[0]); err != nil {
+				return nil, errors.Annotatef(err, "removing profile %q", p.Name)
+			}
+			guest.Devices[p.Name] = devs[1:]
+		}
+		delete(guest.Devices, p.Name)
+		if p.Profile == nil {
+			continue
+		}
+		devs, err := hostDevices(*p.Profile)
+		if err != nil {
+			return nil, errors.Annotatef(err, "profile %q", p.Name)
+		}
+		for _, dev := range devs {
+			if err := b.runDeviceCmd("attach-device", instId, dev); err != nil {
+				return nil, errors.Annotatef(err, "adding profile %q", p.Name)
+			}
+			guest.Devices[p.Name] = append(guest.Devices[p.Name], dev)
+		}
+	}
+	guest.Profiles = profilesNames
+	return profilesNames, nil
+}
+
+// LXDProfileNames implements environs.MutaterBroker.
+func (b *mutaterBroker) LXDProfileNames(instId string) ([]string, error) {
+	path, err := guestProfilesPath(b.pathfinder, instId)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	guest, err := readGuestProfiles(path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return guest.Profiles, nil
+}
+
+// ValidateLXDProfile implements environs.LXDProfileValidator, returning a
+// not supported error if the profile declares devices that cannot be
+// attached to a kvm guest.
+func (b *mutaterBroker) ValidateLXDProfile(profile lxdprofile.Profile) error {
+	_, err := hostDevices(profile)
+	return errors.Trace(err)
+}
+
+// runDeviceCmd runs the given virsh device command, attach-device or
+// detach-device, for the device XML and guest, changing both the running
+// guest, if any, and its persistent definition.
+func (b *mutaterBroker) runDeviceCmd(command, instId, device string) error {
+	dir, err := guestProfilesDir(b.pathfinder)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.Trace(err)
+	}
+	f, err := ioutil.TempFile(dir, instId+"-")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer os.Remove(f.Name())
+	_, err = f.WriteString(device)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	out, err := b.runCmd("", virsh, command, instId, f.Name(), "--persistent")
+	if err != nil {
+		return errors.Annotatef(err, "%s failed for %q", command, instId)
+	}
+	logger.Debugf("%s for %q: %s", command, instId, out)
+	return nil
+}
+
+// hostDevices returns the libvirt hostdev XML for each of the devices
+// declared by the profile, or a not supported error if one of them cannot
+// be attached to a kvm guest.
+func hostDevices(profile lxdprofile.Profile) ([]string, error) {
+	names := make([]string, 0, len(profile.Devices))
+	for name := range profile.Devices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var result []string
+	for _, name := range names {
+		device := profile.Devices[name]
+		var dev libvirt.HostDev
+		var err error
+		switch device["type"] {
+		case "usb":
+			dev, err = libvirt.NewUSBHostDev(device["vendorid"], device["productid"])
+		case "gpu":
+			if device["pci"] == "" {
+				return nil, errors.NotSupportedf("gpu device %q without a pci address on kvm", name)
+			}
+			dev, err = libvirt.NewPCIHostDev(device["pci"])
+		default:
+			return nil, errors.NotSupportedf("%s device %q on kvm", device["type"], name)
+		}
+		if err != nil {
+			return nil, errors.Annotatef(err, "device %q", name)
+		}
+		ml, err := xml.Marshal(&dev)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		result = append(result, string(ml))
+	}
+	return result, nil
+}
+
+// guestProfilesDir returns the path to the directory in which the charm
+// profiles assigned to guests are recorded.
+func guestProfilesDir(pathfinder func(string) (string, error)) (string, error) {
+	baseDir, err := pathfinder(series.MustHostSeries())
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return filepath.Join(baseDir, kvm, profileDir), nil
+}
+
+// guestProfilesPath returns the path to the file in which the charm
+// profiles assigned to the named guest are recorded.
+func guestProfilesPath(pathfinder func(string) (string, error), name string) (string, error) {
+	dir, err := guestProfilesDir(pathfinder)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return filepath.Join(dir, name+".yaml"), nil
+}
+
+// readGuestProfiles returns the profiles recorded in the given file; none
+// are recorded if it does not exist.
+func readGuestProfiles(path string) (*guestProfiles, error) {
+	guest := &guestProfiles{}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		guest.Devices = make(map[string][]string)
+		return guest, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := yaml.Unmarshal(data, guest); err != nil {
+		return nil, errors.Annotatef(err, "reading %q", path)
+	}
+	if guest.Devices == nil {
+		guest.Devices = make(map[string][]string)
+	}
+	return guest, nil
+}
+
+// writeGuestProfiles records the profiles in the given file.
+func writeGuestProfiles(path string, guest *guestProfiles) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.Trace(err)
+	}
+	data, err := yaml.Marshal(guest)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(utils.AtomicWriteFile(path, data, 0644))
+}
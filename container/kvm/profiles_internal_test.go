@@ -0,0 +1,124 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package kvm
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/lxdprofile"
+)
+
+type mutaterBrokerSuite struct {
+	testing.IsolationSuite
+
+	stub   *runStub
+	broker *mutaterBroker
+}
+
+var _ = gc.Suite(&mutaterBrokerSuite{})
+
+func (s *mutaterBrokerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	dir := c.MkDir()
+	s.stub = &runStub{}
+	s.broker = &mutaterBroker{
+		runCmd:     s.stub.Run,
+		pathfinder: func(string) (string, error) { return dir, nil },
+	}
+}
+
+var webcamProfile = &lxdprofile.Profile{
+	Config: map[string]string{"security.nesting": "true"},
+	Devices: map[string]map[string]string{
+		"webcam": {"type": "usb", "vendorid": "046d", "productid": "082d"},
+	},
+}
+
+func (s *mutaterBrokerSuite) TestAssignLXDProfiles(c *gc.C) {
+	names := []string{"default", "juju-model-app-1"}
+	result, err := s.broker.AssignLXDProfiles("juju-06f00d-0", names, []lxdprofile.ProfilePost{
+		{Name: "juju-model-app-1", Profile: webcamProfile},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result, jc.DeepEquals, names)
+
+	calls := s.stub.Calls()
+	c.Assert(calls, gc.HasLen, 1)
+	c.Check(calls[0], gc.Matches, ` virsh attach-device juju-06f00d-0 .*/kvm/profiles/juju-06f00d-0-\d+ --persistent`)
+
+	obtained, err := s.broker.LXDProfileNames("juju-06f00d-0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(obtained, jc.DeepEquals, names)
+}
+
+func (s *mutaterBrokerSuite) TestAssignLXDProfilesReplacesProfile(c *gc.C) {
+	_, err := s.broker.AssignLXDProfiles("juju-06f00d-0", []string{"default", "juju-model-app-1"}, []lxdprofile.ProfilePost{
+		{Name: "juju-model-app-1", Profile: webcamProfile},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	names := []string{"default", "juju-model-app-2"}
+	_, err = s.broker.AssignLXDProfiles("juju-06f00d-0", names, []lxdprofile.ProfilePost{
+		{Name: "juju-model-app-1"},
+		{Name: "juju-model-app-2", Profile: webcamProfile},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	calls := s.stub.Calls()
+	c.Assert(calls, gc.HasLen, 3)
+	c.Check(calls[1], gc.Matches, ` virsh detach-device juju-06f00d-0 .* --persistent`)
+	c.Check(calls[2], gc.Matches, ` virsh attach-device juju-06f00d-0 .* --persistent`)
+
+	obtained, err := s.broker.LXDProfileNames("juju-06f00d-0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(obtained, jc.DeepEquals, names)
+}
+
+func (s *mutaterBrokerSuite) TestAssignLXDProfilesAttachFails(c *gc.C) {
+	s.stub.err = errors.New("boom")
+	_, err := s.broker.AssignLXDProfiles("juju-06f00d-0", []string{"default", "juju-model-app-1"}, []lxdprofile.ProfilePost{
+		{Name: "juju-model-app-1", Profile: webcamProfile},
+	})
+	c.Assert(err, gc.ErrorMatches, `adding profile "juju-model-app-1": attach-device failed for "juju-06f00d-0": boom`)
+
+	obtained, err := s.broker.LXDProfileNames("juju-06f00d-0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(obtained, gc.HasLen, 0)
+}
+
+func (s *mutaterBrokerSuite) TestLXDProfileNamesNoneAssigned(c *gc.C) {
+	obtained, err := s.broker.LXDProfileNames("juju-06f00d-0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(obtained, gc.HasLen, 0)
+}
+
+func (s *mutaterBrokerSuite) TestValidateLXDProfile(c *gc.C) {
+	err := s.broker.ValidateLXDProfile(lxdprofile.Profile{
+		Devices: map[string]map[string]string{
+			"gpu":    {"type": "gpu", "pci": "0000:01:00.0"},
+			"webcam": {"type": "usb", "vendorid": "046d"},
+		},
+	})
+	c.Check(err, jc.ErrorIsNil)
+}
+
+func (s *mutaterBrokerSuite) TestValidateLXDProfileNotSupported(c *gc.C) {
+	err := s.broker.ValidateLXDProfile(lxdprofile.Profile{
+		Devices: map[string]map[string]string{
+			"kvm": {"type": "unix-char", "path": "/dev/kvm"},
+		},
+	})
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+	c.Check(err, gc.ErrorMatches, `unix-char device "kvm" on kvm not supported`)
+
+	err = s.broker.ValidateLXDProfile(lxdprofile.Profile{
+		Devices: map[string]map[string]string{
+			"gpu": {"type": "gpu", "vendorid": "10de"},
+		},
+	})
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+}
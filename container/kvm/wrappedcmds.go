@@ -267,6 +267,14 @@ func DestroyMachine(c *kvmContainer) error {
 	if err != nil {
 		logger.Errorf("failed to remove cloud-init data disk for %q: %s", c.Name(), err)
 	}
+	profilesPath, err := guestProfilesPath(c.pathfinder, c.Name())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = os.Remove(profilesPath)
+	if err != nil && !os.IsNotExist(err) {
+		logger.Errorf("failed to remove charm profiles record for %q: %s", c.Name(), err)
+	}
 
 	return nil
 }
@@ -324,6 +324,23 @@ func (m *containerManager) LXDProfileNames(containerName string) ([]string, erro
 	return m.server.GetContainerProfiles(containerName)
 }
 
+// UnusedLXDProfileNames implements environs.LXDProfileCleaner.
+func (m *containerManager) UnusedLXDProfileNames() ([]string, error) {
+	return m.server.UnusedCharmProfileNames()
+}
+
+// DeleteLXDProfile implements environs.LXDProfileCleaner.
+func (m *containerManager) DeleteLXDProfile(name string) error {
+	m.profileMutex.Lock()
+	defer m.profileMutex.Unlock()
+	return errors.Trace(m.server.DeleteProfile(name))
+}
+
+// ValidateLXDProfile implements environs.LXDProfileValidator.
+func (m *containerManager) ValidateLXDProfile(profile lxdprofile.Profile) error {
+	return errors.Trace(m.server.ValidateCharmProfile(profile))
+}
+
 // AssignLXDProfiles implements environs.LXDProfiler.
 func (m *containerManager) AssignLXDProfiles(instId string, profilesNames []string, profilePosts []lxdprofile.ProfilePost) (current []string, err error) {
 	report := func(err error) ([]string, error) {
@@ -4,6 +4,8 @@
 package lxd
 
 import (
+	"sort"
+
 	"github.com/juju/clock"
 	"github.com/juju/errors"
 	"github.com/juju/utils/arch"
@@ -11,6 +13,8 @@ import (
 	lxd "github.com/lxc/lxd/client"
 	"github.com/lxc/lxd/shared"
 	"github.com/lxc/lxd/shared/api"
+
+	"github.com/juju/juju/core/lxdprofile"
 )
 
 // osSupport is the list of operating system types for which Juju supports
@@ -45,6 +49,9 @@ type Server struct {
 	clusterAPISupport bool
 	storageAPISupport bool
 
+	gpuDeviceSupport bool
+	usbDeviceSupport bool
+
 	localBridgeName string
 
 	clock clock.Clock
@@ -120,6 +127,8 @@ func NewServer(svr lxd.ContainerServer) (*Server, error) {
 		networkAPISupport: shared.StringInSlice("network", apiExt),
 		clusterAPISupport: shared.StringInSlice("clustering", apiExt),
 		storageAPISupport: shared.StringInSlice("storage", apiExt),
+		gpuDeviceSupport:  shared.StringInSlice("gpu_devices", apiExt),
+		usbDeviceSupport:  shared.StringInSlice("usb_devices", apiExt),
 		serverVersion:     info.Environment.ServerVersion,
 		clock:             clock.WallClock,
 	}, nil
@@ -274,6 +283,53 @@ func (s *Server) HasProfile(name string) (bool, error) {
 	return false, nil
 }
 
+// UnusedCharmProfileNames returns the names of the charm profiles on the
+// server, those named as by lxdprofile.Name, which are not used by any
+// container.
+func (s *Server) UnusedCharmProfileNames() ([]string, error) {
+	profiles, err := s.GetProfiles()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var unused []string
+	for _, profile := range profiles {
+		if lxdprofile.IsValidName(profile.Name) && len(profile.UsedBy) == 0 {
+			unused = append(unused, profile.Name)
+		}
+	}
+	return unused, nil
+}
+
+// ValidateCharmProfile returns a not supported error if the server is
+// unable to apply the devices of the given charm profile. Unix character
+// and block devices are refused by clustered servers, as the host device
+// may be missing from the cluster member that runs a container.
+func (s *Server) ValidateCharmProfile(profile lxdprofile.Profile) error {
+	deviceNames := make([]string, 0, len(profile.Devices))
+	for name := range profile.Devices {
+		deviceNames = append(deviceNames, name)
+	}
+	sort.Strings(deviceNames)
+	for _, name := range deviceNames {
+		devType := profile.Devices[name]["type"]
+		switch devType {
+		case "unix-char", "unix-block":
+			if s.clustered {
+				return errors.NotSupportedf("%s device %q on clustered lxd server", devType, name)
+			}
+		case "gpu":
+			if !s.gpuDeviceSupport {
+				return errors.NotSupportedf("gpu device %q on lxd server %q", name, s.name)
+			}
+		case "usb":
+			if !s.usbDeviceSupport {
+				return errors.NotSupportedf("usb device %q on lxd server %q", name, s.name)
+			}
+		}
+	}
+	return nil
+}
+
 // CreateProfileWithConfig creates a new profile with the input name and config.
 func (s *Server) CreateProfileWithConfig(name string, cfg map[string]string) error {
 	req := api.ProfilesPost{
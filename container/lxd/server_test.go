@@ -5,12 +5,14 @@ package lxd_test
 
 import (
 	"github.com/golang/mock/gomock"
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/lxc/lxd/shared/api"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/container/lxd"
 	lxdtesting "github.com/juju/juju/container/lxd/testing"
+	"github.com/juju/juju/core/lxdprofile"
 )
 
 type serverSuite struct {
@@ -78,6 +80,69 @@ func (s *serverSuite) TestHasProfile(c *gc.C) {
 	c.Check(has, jc.IsFalse)
 }
 
+func (s *serverSuite) TestUnusedCharmProfileNames(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServer(ctrl)
+
+	cSvr.EXPECT().GetProfiles().Return([]api.Profile{
+		{Name: "default"},
+		{Name: "juju-default"},
+		{Name: "juju-default-mysql-1"},
+		{Name: "juju-default-mysql-2", UsedBy: []string{"/1.0/containers/juju-3ab4c1-0"}},
+		{Name: "juju-default-lxd-profile-5"},
+	}, nil)
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	unused, err := jujuSvr.UnusedCharmProfileNames()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(unused, jc.DeepEquals, []string{"juju-default-mysql-1", "juju-default-lxd-profile-5"})
+}
+
+func (s *serverSuite) TestValidateCharmProfile(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServerWithExtensions(ctrl, "gpu_devices")
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = jujuSvr.ValidateCharmProfile(lxdprofile.Profile{
+		Devices: map[string]map[string]string{
+			"gpu": {"type": "gpu"},
+			"tun": {"type": "unix-char", "path": "/dev/net/tun"},
+		},
+	})
+	c.Check(err, jc.ErrorIsNil)
+
+	err = jujuSvr.ValidateCharmProfile(lxdprofile.Profile{
+		Devices: map[string]map[string]string{
+			"bdisk": {"type": "usb"},
+		},
+	})
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+	c.Check(err, gc.ErrorMatches, `usb device "bdisk" on lxd server "none" not supported`)
+}
+
+func (s *serverSuite) TestValidateCharmProfileClustered(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServerClustered(ctrl, "cluster-1")
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = jujuSvr.ValidateCharmProfile(lxdprofile.Profile{
+		Devices: map[string]map[string]string{
+			"tun": {"type": "unix-char", "path": "/dev/net/tun"},
+		},
+	})
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+	c.Check(err, gc.ErrorMatches, `unix-char device "tun" on clustered lxd server not supported`)
+}
+
 func (s *serverSuite) TestCreateProfileWithConfig(c *gc.C) {
 	ctrl := gomock.NewController(c)
 	defer ctrl.Finish()
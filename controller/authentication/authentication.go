@@ -22,10 +22,15 @@ type TaggedPasswordChanger interface {
 	Tag() names.Tag
 }
 
-// AuthenticationProvider defines the single method that the provisioner
+// AuthenticationProvider defines the methods that the provisioner
 // task needs to set up authentication for a machine.
 type AuthenticationProvider interface {
 	SetupAuthentication(machine TaggedPasswordChanger) (*mongo.MongoInfo, *api.Info, error)
+
+	// SetupEnrollment returns the API info for a machine whose agent
+	// will prove its identity with the signed identity document of its
+	// instance, and so is given no password.
+	SetupEnrollment(machine TaggedPasswordChanger) (*api.Info, error)
 }
 
 // NewAPIAuthenticator gets the state and api info once from the
@@ -102,3 +107,13 @@ func (auth *simpleAuth) SetupAuthentication(machine TaggedPasswordChanger) (*mon
 	}
 	return stateInfo, apiInfo, nil
 }
+
+func (auth *simpleAuth) SetupEnrollment(machine TaggedPasswordChanger) (*api.Info, error) {
+	if auth.apiInfo == nil {
+		return nil, errors.New("no API info to enroll machine with")
+	}
+	apiInfo := *auth.apiInfo
+	apiInfo.Tag = machine.Tag()
+	apiInfo.Password = ""
+	return &apiInfo, nil
+}
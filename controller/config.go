@@ -11,6 +11,7 @@ import (
 
 	"github.com/juju/collections/set"
 	"github.com/juju/errors"
+	"github.com/juju/loggo"
 	"github.com/juju/romulus"
 	"github.com/juju/schema"
 	"github.com/juju/utils"
@@ -131,6 +132,101 @@ const (
 	// session. If the user needs more information, perhaps debug-log isn't the right source.
 	MaxDebugLogDuration = "max-debug-log-duration"
 
+	// StepUpAuthMaxAge is the maximum age of the second-factor assertion,
+	// such as one made with a yubikey using webauthn, that a client must
+	// have recorded against its API connection before it may perform one
+	// of the destructive operations listed in StepUpAuthOperations.
+	StepUpAuthMaxAge = "step-up-auth-max-age"
+
+	// StepUpAuthOperations lists the destructive operations, such as
+	// StepUpDestroyController, that require step-up authentication.
+	// Clients must assert a second factor on their API connection, with
+	// the SecondFactor facade, before making the listed calls. No
+	// operation requires it if the list is unset or empty.
+	StepUpAuthOperations = "step-up-auth-operations"
+
+	// StepUpAuthRPID is the webauthn relying party ID for which
+	// second-factor assertions must be made. It is required if
+	// StepUpAuthMaxAge is set.
+	StepUpAuthRPID = "step-up-auth-rp-id"
+
+	// SaturationLeaseClaimLatency is the 99th percentile latency of lease
+	// claims above which a controller reports that it is saturated.
+	// No alert is raised for lease claims if it is unset or zero.
+	SaturationLeaseClaimLatency = "saturation-lease-claim-latency"
+
+	// SaturationTxnRetryRate is the fraction of transaction operations,
+	// between 0 and 1, that may fail their assertions and be retried
+	// before a controller reports that it is saturated. No alert is
+	// raised for transactions if it is unset or zero.
+	SaturationTxnRetryRate = "saturation-txn-retry-rate"
+
+	// SaturationWatcherLag is how far the delivery of changes to
+	// watchers may fall behind the transactions that made them before
+	// a controller reports that it is saturated. No alert is raised
+	// for watchers if it is unset or zero.
+	SaturationWatcherLag = "saturation-watcher-lag"
+
+	// SaturationAlertWebhook is an optional URL to which controller
+	// saturation alerts are posted.
+	SaturationAlertWebhook = "saturation-alert-webhook"
+
+	// APIRateLimit is the number of API requests per second that each
+	// authenticated user or agent, other than controller machines, may
+	// make. Pings and watchers are not limited. Requests are not limited
+	// if it is unset or zero.
+	APIRateLimit = "api-rate-limit"
+
+	// APIRateLimitBurst is the number of API requests that each
+	// authenticated entity may make in a burst above APIRateLimit.
+	APIRateLimitBurst = "api-rate-limit-burst"
+
+	// APIAgentRateLimit overrides APIRateLimit for agents, so that
+	// they can be given a different budget from users. Agents use
+	// APIRateLimit if it is unset.
+	APIAgentRateLimit = "api-agent-rate-limit"
+
+	// APIAgentRateLimitBurst overrides APIRateLimitBurst for agents.
+	// Agents use APIRateLimitBurst if it is unset.
+	APIAgentRateLimitBurst = "api-agent-rate-limit-burst"
+
+	// APITraceSampleRate is the fraction, between 0 and 1, of API
+	// requests whose handling by the controller is traced when the
+	// client isn't tracing them itself. Requests are only traced at
+	// their clients' request if it is unset or zero.
+	APITraceSampleRate = "api-trace-sample-rate"
+
+	// APIWebsocketPingInterval is how often the controller pings the
+	// clients of its API connections at the websocket level, so that
+	// half-open connections are noticed. Zero disables the pings.
+	APIWebsocketPingInterval = "api-websocket-ping-interval"
+
+	// APIWebsocketPongTimeout is how long the controller waits for a
+	// pong from the client of an API connection before considering the
+	// connection dead and closing it.
+	APIWebsocketPongTimeout = "api-websocket-pong-timeout"
+
+	// ControllerLoggingConfig holds logging levels, in the same form as
+	// the logging-config model setting, for the agents of controller
+	// machines. They take precedence over the logging-config of the
+	// controller model, and changes take effect without restarting
+	// the agents.
+	ControllerLoggingConfig = "controller-logging-config"
+
+	// LogSinkTargets lists where the controller writes the logs sent
+	// to it by agents: "database", for debug-log, and "file", for
+	// logsink.log on the controller machine.
+	LogSinkTargets = "logsink-targets"
+
+	// AgentLogfileMaxSize is the size, eg "300M", at which the
+	// logsink.log file, holding the logs sent to the controller by
+	// agents, is rotated.
+	AgentLogfileMaxSize = "agent-logfile-max-size"
+
+	// AgentLogfileMaxBackups is the number of rotated logsink.log
+	// files to keep (compressed).
+	AgentLogfileMaxBackups = "agent-logfile-max-backups"
+
 	// TODO(thumper): remove max-logs-age and max-logs-size in 2.7 branch.
 
 	// MaxLogsAge is the maximum age for log entries, eg "72h"
@@ -147,6 +243,24 @@ const (
 	// MaxTxnLogSize is the maximum size the of capped txn log collection, eg "10M"
 	MaxTxnLogSize = "max-txn-log-size"
 
+	// MaxAuditTrailSize is the maximum size of the capped collection
+	// holding the audit trail of API calls, eg "100M".
+	MaxAuditTrailSize = "max-audit-trail-size"
+
+	// AuditTrailEnabled determines whether the controller records an
+	// audit trail of API calls.
+	AuditTrailEnabled = "audit-trail-enabled"
+
+	// AuditTrailIncludeAgents determines whether the audit trail
+	// records calls made by agents, and calls on watchers.
+	AuditTrailIncludeAgents = "audit-trail-include-agents"
+
+	// MaxRelationSettingsSize is the maximum size that the settings of a
+	// unit in a relation can grow to, eg "1M". Larger settings are
+	// refused by the uniter facade, so that they cannot grow the
+	// document holding them past mongo's limit.
+	MaxRelationSettingsSize = "max-relation-settings-size"
+
 	// MaxPruneTxnBatchSize (deprecated) is the maximum number of transactions
 	// we will evaluate in one go when pruning. Default is 1M transactions.
 	// A value <= 0 indicates to do all transactions at once.
@@ -171,6 +285,20 @@ const (
 	// to not sleep at all.
 	PruneTxnSleepTime = "prune-txn-sleep-time"
 
+	// UpgradeBatchSize is the number of documents processed in each
+	// batch by upgrade steps that rewrite whole collections.
+	UpgradeBatchSize = "upgrade-batch-size"
+
+	// UpgradeBatchSleepTime is the amount of time that upgrade steps
+	// rewriting whole collections sleep between batches, so that they
+	// don't starve other users of the database. Zero disables the sleep.
+	UpgradeBatchSleepTime = "upgrade-batch-sleep-time"
+
+	// UpgradeBatchMaxBackoff is the longest time that upgrade steps
+	// rewriting whole collections back off between batches when the
+	// database is slow to process them.
+	UpgradeBatchMaxBackoff = "upgrade-batch-max-backoff"
+
 	// Attribute Defaults
 
 	// DefaultAuditingEnabled contains the default value for the
@@ -181,6 +309,15 @@ const (
 	// AuditLogCaptureArgs setting (which is not to capture them).
 	DefaultAuditLogCaptureArgs = false
 
+	// DefaultAuditTrailEnabled is the default for the AuditTrailEnabled
+	// setting (which is not to record the audit trail).
+	DefaultAuditTrailEnabled = false
+
+	// DefaultAuditTrailIncludeAgents is the default for the
+	// AuditTrailIncludeAgents setting (which is to leave agent and
+	// watcher calls out of the audit trail).
+	DefaultAuditTrailIncludeAgents = false
+
 	// DefaultAuditLogMaxSizeMB is the default size in MB at which we
 	// roll the audit log file.
 	DefaultAuditLogMaxSizeMB = 300
@@ -210,6 +347,35 @@ const (
 	// can run before being terminated by the API server.
 	DefaultMaxDebugLogDuration = 24 * time.Hour
 
+	// DefaultAPIWebsocketPingInterval is the default interval between
+	// websocket pings of API clients.
+	DefaultAPIWebsocketPingInterval = time.Minute
+
+	// DefaultAPIWebsocketPongTimeout is the default time an API client
+	// has to answer a websocket ping before its connection is closed.
+	DefaultAPIWebsocketPongTimeout = 90 * time.Second
+
+	// LogSinkDatabase is the logsink target that writes agent logs to
+	// the database.
+	LogSinkDatabase = "database"
+
+	// LogSinkFile is the logsink target that writes agent logs to the
+	// logsink.log file.
+	LogSinkFile = "file"
+
+	// DefaultAgentLogfileMaxSizeMB is the default size in MB at which
+	// the logsink.log file is rotated.
+	DefaultAgentLogfileMaxSizeMB = 300
+
+	// DefaultAgentLogfileMaxBackups is the default number of rotated
+	// logsink.log files to keep.
+	DefaultAgentLogfileMaxBackups = 2
+
+	// DefaultAPIRateLimitBurst is the default number of API requests
+	// that an entity may make in a burst when API requests are rate
+	// limited.
+	DefaultAPIRateLimitBurst = 100
+
 	// TODO(thumper): remove DefaultMaxLogsAgeDays and DefaultMaxLogCollectionMB in 2.7 branch.
 
 	// DefaultMaxLogsAgeDays is the maximum age in days of log entries.
@@ -222,6 +388,14 @@ const (
 	// DefaultMaxTxnLogCollectionMB is the maximum size the txn log collection.
 	DefaultMaxTxnLogCollectionMB = 10 // 10 MB
 
+	// DefaultMaxAuditTrailCollectionMB is the maximum size of the audit
+	// trail collection.
+	DefaultMaxAuditTrailCollectionMB = 100 // 100 MB
+
+	// DefaultMaxRelationSettingsSizeMB is the maximum size of the
+	// settings of a unit in a relation.
+	DefaultMaxRelationSettingsSizeMB = 1 // 1 MB
+
 	// DefaultMaxPruneTxnBatchSize is the normal number of transaction we will prune in a given pass (1M) (deprecated)
 	DefaultMaxPruneTxnBatchSize = 1 * 1000 * 1000
 
@@ -242,6 +416,18 @@ const (
 	// other systems to operate concurrently.
 	DefaultPruneTxnSleepTime = "10ms"
 
+	// DefaultUpgradeBatchSize is the default number of documents
+	// processed in each batch by upgrade steps.
+	DefaultUpgradeBatchSize = 1000
+
+	// DefaultUpgradeBatchSleepTime is the default time upgrade steps
+	// sleep between batches.
+	DefaultUpgradeBatchSleepTime = 10 * time.Millisecond
+
+	// DefaultUpgradeBatchMaxBackoff is the default longest time upgrade
+	// steps back off between batches.
+	DefaultUpgradeBatchMaxBackoff = 5 * time.Second
+
 	// JujuHASpace is the network space within which the MongoDB replica-set
 	// should communicate.
 	JujuHASpace = "juju-ha-space"
@@ -259,6 +445,14 @@ const (
 	// for the jujud operator and mongo images.
 	CAASImageRepo = "caas-image-repo"
 
+	// CAASImageRepoUsername sets the username used to pull the
+	// jujud operator image from a private docker registry.
+	CAASImageRepoUsername = "caas-image-repo-username"
+
+	// CAASImageRepoPassword sets the password used to pull the
+	// jujud operator image from a private docker registry.
+	CAASImageRepoPassword = "caas-image-repo-password"
+
 	// Features allows a list of runtime changeable features to be updated.
 	Features = "features"
 
@@ -283,17 +477,42 @@ var (
 		IdentityURL,
 		SetNUMAControlPolicyKey,
 		StatePort,
+		StepUpAuthMaxAge,
+		StepUpAuthOperations,
+		StepUpAuthRPID,
+		SaturationLeaseClaimLatency,
+		SaturationTxnRetryRate,
+		SaturationWatcherLag,
+		SaturationAlertWebhook,
+		APIRateLimit,
+		APIRateLimitBurst,
+		APIAgentRateLimit,
+		APIAgentRateLimitBurst,
+		APITraceSampleRate,
+		APIWebsocketPingInterval,
+		APIWebsocketPongTimeout,
+		ControllerLoggingConfig,
+		LogSinkTargets,
+		AgentLogfileMaxSize,
+		AgentLogfileMaxBackups,
 		MongoMemoryProfile,
 		MaxDebugLogDuration,
 		// TODO(thumper): remove MaxLogsAge and MaxLogsSize in 2.7 branch.
 		MaxLogsSize,
 		MaxLogsAge,
 		MaxTxnLogSize,
+		MaxAuditTrailSize,
+		AuditTrailEnabled,
+		AuditTrailIncludeAgents,
 		MaxPruneTxnBatchSize,
 		MaxPruneTxnPasses,
+		MaxRelationSettingsSize,
 		ModelLogsSize,
 		PruneTxnQueryCount,
 		PruneTxnSleepTime,
+		UpgradeBatchSize,
+		UpgradeBatchSleepTime,
+		UpgradeBatchMaxBackoff,
 		JujuHASpace,
 		JujuManagementSpace,
 		AuditingEnabled,
@@ -303,6 +522,8 @@ var (
 		AuditLogExcludeMethods,
 		CAASOperatorImagePath,
 		CAASImageRepo,
+		CAASImageRepoUsername,
+		CAASImageRepoPassword,
 		Features,
 		MeteringURL,
 	}
@@ -324,15 +545,39 @@ var (
 		// TODO(thumper): remove MaxLogsAge and MaxLogsSize in 2.7 branch.
 		MaxLogsSize,
 		MaxLogsAge,
+		MaxRelationSettingsSize,
 		ModelLogsSize,
 		MongoMemoryProfile,
 		PruneTxnQueryCount,
 		PruneTxnSleepTime,
+		UpgradeBatchSize,
+		UpgradeBatchSleepTime,
+		UpgradeBatchMaxBackoff,
 		JujuHASpace,
 		JujuManagementSpace,
 		CAASOperatorImagePath,
 		CAASImageRepo,
+		CAASImageRepoUsername,
+		CAASImageRepoPassword,
 		Features,
+		StepUpAuthMaxAge,
+		StepUpAuthOperations,
+		StepUpAuthRPID,
+		SaturationLeaseClaimLatency,
+		SaturationTxnRetryRate,
+		SaturationWatcherLag,
+		SaturationAlertWebhook,
+		APIRateLimit,
+		APIRateLimitBurst,
+		APIAgentRateLimit,
+		APIAgentRateLimitBurst,
+		APITraceSampleRate,
+		APIWebsocketPingInterval,
+		APIWebsocketPongTimeout,
+		ControllerLoggingConfig,
+		LogSinkTargets,
+		AgentLogfileMaxSize,
+		AgentLogfileMaxBackups,
 	)
 
 	// DefaultAuditLogExcludeMethods is the default list of methods to
@@ -346,6 +591,19 @@ var (
 	methodNameRE = regexp.MustCompile(`[[:alpha:]][[:alnum:]]*\.[[:alpha:]][[:alnum:]]*`)
 )
 
+// The destructive operations that may be listed in StepUpAuthOperations.
+const (
+	// StepUpDestroyController is the operation of destroying the
+	// controller.
+	StepUpDestroyController = "destroy-controller"
+
+	// StepUpForceRemoveMachine is the operation of force removing a
+	// machine.
+	StepUpForceRemoveMachine = "force-remove-machine"
+)
+
+var stepUpOperations = set.NewStrings(StepUpDestroyController, StepUpForceRemoveMachine)
+
 // ControllerOnlyAttribute returns true if the specified attribute name
 // is only relevant for a controller.
 func ControllerOnlyAttribute(attr string) bool {
@@ -405,6 +663,22 @@ func (c Config) intOrDefault(name string, defaultVal int) int {
 	return defaultVal
 }
 
+// durationOrDefault returns the named duration attribute, which is a
+// string if it was updated after bootstrap, or defaultVal if it isn't
+// set.
+func (c Config) durationOrDefault(name string, defaultVal time.Duration) time.Duration {
+	switch value := c[name].(type) {
+	case time.Duration:
+		return value
+	case string:
+		// Value has already been validated.
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultVal
+}
+
 // asString is a private helper method to keep the ugly string casting
 // in once place. It returns the given named attribute as a string,
 // returning "" if it isn't found.
@@ -466,6 +740,24 @@ func (c Config) AuditingEnabled() bool {
 	return DefaultAuditingEnabled
 }
 
+// AuditTrailEnabled returns whether the controller records an audit
+// trail of API calls. The default is false.
+func (c Config) AuditTrailEnabled() bool {
+	if v, ok := c[AuditTrailEnabled]; ok {
+		return v.(bool)
+	}
+	return DefaultAuditTrailEnabled
+}
+
+// AuditTrailIncludeAgents returns whether the audit trail records
+// calls made by agents, and calls on watchers. The default is false.
+func (c Config) AuditTrailIncludeAgents() bool {
+	if v, ok := c[AuditTrailIncludeAgents]; ok {
+		return v.(bool)
+	}
+	return DefaultAuditTrailIncludeAgents
+}
+
 // AuditLogCaptureArgs returns whether audit logging should capture
 // the arguments to API methods. The default is false.
 func (c Config) AuditLogCaptureArgs() bool {
@@ -618,6 +910,161 @@ func (c Config) MaxDebugLogDuration() time.Duration {
 	return duration
 }
 
+// StepUpAuthMaxAge is the maximum age of a second-factor assertion that
+// allows destructive operations. Zero means that step-up authentication
+// is not required.
+func (c Config) StepUpAuthMaxAge() time.Duration {
+	duration, _ := c[StepUpAuthMaxAge].(time.Duration)
+	return duration
+}
+
+// StepUpAuthOperations returns the destructive operations that require
+// step-up authentication.
+func (c Config) StepUpAuthOperations() set.Strings {
+	operations := set.NewStrings()
+	if value, ok := c[StepUpAuthOperations]; ok {
+		for _, item := range value.([]interface{}) {
+			operations.Add(item.(string))
+		}
+	}
+	return operations
+}
+
+// StepUpAuthRPID is the webauthn relying party ID for which
+// second-factor assertions must be made.
+func (c Config) StepUpAuthRPID() string {
+	return c.asString(StepUpAuthRPID)
+}
+
+// SaturationLeaseClaimLatency is the lease claim latency above which the
+// controller is reported as saturated. Zero disables the alert.
+func (c Config) SaturationLeaseClaimLatency() time.Duration {
+	duration, _ := c[SaturationLeaseClaimLatency].(time.Duration)
+	return duration
+}
+
+// SaturationTxnRetryRate is the fraction of failed transaction operations
+// above which the controller is reported as saturated. Zero disables the
+// alert.
+func (c Config) SaturationTxnRetryRate() float64 {
+	rate, _ := c[SaturationTxnRetryRate].(float64)
+	return rate
+}
+
+// SaturationWatcherLag is the watcher delivery lag above which the
+// controller is reported as saturated. Zero disables the alert.
+func (c Config) SaturationWatcherLag() time.Duration {
+	duration, _ := c[SaturationWatcherLag].(time.Duration)
+	return duration
+}
+
+// SaturationAlertWebhook is the URL to which controller saturation
+// alerts are posted, if any.
+func (c Config) SaturationAlertWebhook() string {
+	return c.asString(SaturationAlertWebhook)
+}
+
+// APIRateLimit is the number of API requests per second that each
+// authenticated entity may make. Zero disables rate limiting.
+func (c Config) APIRateLimit() float64 {
+	rate, _ := c[APIRateLimit].(float64)
+	return rate
+}
+
+// APIRateLimitBurst is the number of API requests that each authenticated
+// entity may make in a burst when API requests are rate limited.
+func (c Config) APIRateLimitBurst() int {
+	if burst, ok := c[APIRateLimitBurst]; ok {
+		return burst.(int)
+	}
+	return DefaultAPIRateLimitBurst
+}
+
+// APIAgentRateLimit is the number of API requests per second that each
+// agent may make. Zero disables rate limiting of agents.
+func (c Config) APIAgentRateLimit() float64 {
+	if rate, ok := c[APIAgentRateLimit].(float64); ok {
+		return rate
+	}
+	return c.APIRateLimit()
+}
+
+// APIAgentRateLimitBurst is the number of API requests that each agent
+// may make in a burst when API requests are rate limited.
+func (c Config) APIAgentRateLimitBurst() int {
+	if burst, ok := c[APIAgentRateLimitBurst]; ok {
+		return burst.(int)
+	}
+	return c.APIRateLimitBurst()
+}
+
+// APITraceSampleRate is the fraction of API requests that are traced
+// when their clients aren't tracing them.
+func (c Config) APITraceSampleRate() float64 {
+	rate, _ := c[APITraceSampleRate].(float64)
+	return rate
+}
+
+// APIWebsocketPingInterval is how often API clients are pinged at the
+// websocket level. Zero means they aren't.
+func (c Config) APIWebsocketPingInterval() time.Duration {
+	interval, ok := c[APIWebsocketPingInterval].(time.Duration)
+	if !ok {
+		interval = DefaultAPIWebsocketPingInterval
+	}
+	return interval
+}
+
+// APIWebsocketPongTimeout is how long an API client has to answer a
+// websocket ping before its connection is closed.
+func (c Config) APIWebsocketPongTimeout() time.Duration {
+	timeout, ok := c[APIWebsocketPongTimeout].(time.Duration)
+	if !ok {
+		timeout = DefaultAPIWebsocketPongTimeout
+	}
+	return timeout
+}
+
+// ControllerLoggingConfig returns the logging levels for the agents of
+// controller machines, which take precedence over those of the
+// controller model. It is empty if there are none.
+func (c Config) ControllerLoggingConfig() string {
+	return c.asString(ControllerLoggingConfig)
+}
+
+// LogSinkTargets returns where the controller writes the logs sent to
+// it by agents. By default they are written to both the database and
+// the logsink.log file.
+func (c Config) LogSinkTargets() set.Strings {
+	value, ok := c[LogSinkTargets].([]interface{})
+	if !ok {
+		return set.NewStrings(LogSinkDatabase, LogSinkFile)
+	}
+	targets := set.NewStrings()
+	for _, item := range value {
+		targets.Add(item.(string))
+	}
+	return targets
+}
+
+// AgentLogfileMaxSizeMB returns the size in MB at which the logsink.log
+// file is rotated.
+func (c Config) AgentLogfileMaxSizeMB() int {
+	v, ok := c[AgentLogfileMaxSize].(string)
+	if !ok {
+		return DefaultAgentLogfileMaxSizeMB
+	}
+	// Value has already been validated.
+	size, _ := utils.ParseSize(v)
+	return int(size)
+}
+
+// AgentLogfileMaxBackups returns the number of rotated logsink.log
+// files to keep.
+func (c Config) AgentLogfileMaxBackups() int {
+	return c.intOrDefault(AgentLogfileMaxBackups, DefaultAgentLogfileMaxBackups)
+}
+
 // MaxTxnLogSizeMB is the maximum size in MiB of the txn log collection.
 func (c Config) MaxTxnLogSizeMB() int {
 	// Value has already been validated.
@@ -625,6 +1072,29 @@ func (c Config) MaxTxnLogSizeMB() int {
 	return int(val)
 }
 
+// MaxAuditTrailSizeMB is the maximum size in MiB of the audit trail
+// collection.
+func (c Config) MaxAuditTrailSizeMB() int {
+	if v, ok := c[MaxAuditTrailSize].(string); ok {
+		// Value has already been validated.
+		val, _ := utils.ParseSize(v)
+		return int(val)
+	}
+	return DefaultMaxAuditTrailCollectionMB
+}
+
+// MaxRelationSettingsSize is the maximum size in bytes of the settings
+// of a unit in a relation.
+func (c Config) MaxRelationSettingsSize() int {
+	mb := DefaultMaxRelationSettingsSizeMB
+	if v, ok := c[MaxRelationSettingsSize].(string); ok {
+		// Value has already been validated.
+		val, _ := utils.ParseSize(v)
+		mb = int(val)
+	}
+	return mb * 1024 * 1024
+}
+
 // MaxPruneTxnBatchSize is the maximum size of the txn log collection.
 func (c Config) MaxPruneTxnBatchSize() int {
 	return c.intOrDefault(MaxPruneTxnBatchSize, DefaultMaxPruneTxnBatchSize)
@@ -654,6 +1124,24 @@ func (c Config) PruneTxnSleepTime() time.Duration {
 	return val
 }
 
+// UpgradeBatchSize is the number of documents processed in each batch
+// by upgrade steps that rewrite whole collections.
+func (c Config) UpgradeBatchSize() int {
+	return c.intOrDefault(UpgradeBatchSize, DefaultUpgradeBatchSize)
+}
+
+// UpgradeBatchSleepTime is the amount of time upgrade steps sleep
+// between batches.
+func (c Config) UpgradeBatchSleepTime() time.Duration {
+	return c.durationOrDefault(UpgradeBatchSleepTime, DefaultUpgradeBatchSleepTime)
+}
+
+// UpgradeBatchMaxBackoff is the longest time upgrade steps back off
+// between batches when the database is slow.
+func (c Config) UpgradeBatchMaxBackoff() time.Duration {
+	return c.durationOrDefault(UpgradeBatchMaxBackoff, DefaultUpgradeBatchMaxBackoff)
+}
+
 // JujuHASpace is the network space within which the MongoDB replica-set
 // should communicate.
 func (c Config) JujuHASpace() string {
@@ -672,12 +1160,40 @@ func (c Config) CAASOperatorImagePath() string {
 	return c.asString(CAASOperatorImagePath)
 }
 
+// DeprecatedAttributes returns the deprecated attributes that are set,
+// to other than their default values, in the config.
+func (c Config) DeprecatedAttributes() []string {
+	var attrs []string
+	if c.CAASOperatorImagePath() != "" {
+		attrs = append(attrs, CAASOperatorImagePath)
+	}
+	if c.MaxPruneTxnBatchSize() != DefaultMaxPruneTxnBatchSize {
+		attrs = append(attrs, MaxPruneTxnBatchSize)
+	}
+	if c.MaxPruneTxnPasses() != DefaultMaxPruneTxnPasses {
+		attrs = append(attrs, MaxPruneTxnPasses)
+	}
+	return attrs
+}
+
 // CAASImageRepo sets the url of the docker repo
 // used for the jujud operator and mongo images.
 func (c Config) CAASImageRepo() string {
 	return c.asString(CAASImageRepo)
 }
 
+// CAASImageRepoUsername returns the username used to pull the jujud
+// operator image from a private docker registry.
+func (c Config) CAASImageRepoUsername() string {
+	return c.asString(CAASImageRepoUsername)
+}
+
+// CAASImageRepoPassword returns the password used to pull the jujud
+// operator image from a private docker registry.
+func (c Config) CAASImageRepoPassword() string {
+	return c.asString(CAASImageRepoPassword)
+}
+
 // MeteringURL returns the URL to use for metering api calls.
 func (c Config) MeteringURL() string {
 	url := c.asString(MeteringURL)
@@ -733,6 +1249,101 @@ func Validate(c Config) error {
 			return errors.Errorf("%s cannot be zero", MaxDebugLogDuration)
 		}
 	}
+
+	if v, ok := c[StepUpAuthMaxAge].(time.Duration); ok {
+		if v < 0 {
+			return errors.Errorf("%s cannot be negative", StepUpAuthMaxAge)
+		}
+		if v > 0 && c.asString(StepUpAuthRPID) == "" {
+			return errors.Errorf("%s requires %s", StepUpAuthMaxAge, StepUpAuthRPID)
+		}
+	}
+	if operations := c.StepUpAuthOperations(); !operations.IsEmpty() {
+		if unknown := operations.Difference(stepUpOperations); !unknown.IsEmpty() {
+			return errors.Errorf("%s: unknown operations %v", StepUpAuthOperations, unknown.SortedValues())
+		}
+		if c.StepUpAuthMaxAge() <= 0 {
+			return errors.Errorf("%s requires %s", StepUpAuthOperations, StepUpAuthMaxAge)
+		}
+	}
+
+	for _, name := range []string{SaturationLeaseClaimLatency, SaturationWatcherLag} {
+		if v, ok := c[name].(time.Duration); ok && v < 0 {
+			return errors.Errorf("%s cannot be negative", name)
+		}
+	}
+	if v, ok := c[SaturationTxnRetryRate].(float64); ok {
+		if v < 0 || v > 1 {
+			return errors.Errorf("%s must be between 0 and 1, got %v", SaturationTxnRetryRate, v)
+		}
+	}
+	if v, ok := c[SaturationAlertWebhook].(string); ok && v != "" {
+		u, err := url.Parse(v)
+		if err != nil {
+			return errors.Annotatef(err, "invalid %s", SaturationAlertWebhook)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return errors.Errorf("%s must be an http or https URL, got %q", SaturationAlertWebhook, v)
+		}
+	}
+
+	if v, ok := c[APIRateLimit].(float64); ok && v < 0 {
+		return errors.Errorf("%s cannot be negative", APIRateLimit)
+	}
+	if v, ok := c[APIRateLimitBurst].(int); ok && v < 1 {
+		return errors.Errorf("%s must be positive, got %d", APIRateLimitBurst, v)
+	}
+	if v, ok := c[APIAgentRateLimit].(float64); ok && v < 0 {
+		return errors.Errorf("%s cannot be negative", APIAgentRateLimit)
+	}
+	if v, ok := c[APIAgentRateLimitBurst].(int); ok && v < 1 {
+		return errors.Errorf("%s must be positive, got %d", APIAgentRateLimitBurst, v)
+	}
+	if v, ok := c[APITraceSampleRate].(float64); ok {
+		if v < 0 || v > 1 {
+			return errors.Errorf("%s must be between 0 and 1, got %v", APITraceSampleRate, v)
+		}
+	}
+	if v, ok := c[APIWebsocketPingInterval].(time.Duration); ok && v < 0 {
+		return errors.Errorf("%s cannot be negative", APIWebsocketPingInterval)
+	}
+	if v, ok := c[APIWebsocketPongTimeout].(time.Duration); ok && v <= 0 {
+		return errors.Errorf("%s must be positive", APIWebsocketPongTimeout)
+	}
+	if interval, timeout := c.APIWebsocketPingInterval(), c.APIWebsocketPongTimeout(); interval > 0 && timeout <= interval {
+		return errors.Errorf("%s (%v) must be greater than %s (%v)",
+			APIWebsocketPongTimeout, timeout, APIWebsocketPingInterval, interval)
+	}
+	if v, ok := c[ControllerLoggingConfig].(string); ok && v != "" {
+		if _, err := loggo.ParseConfigString(v); err != nil {
+			return errors.Annotatef(err, "invalid %s", ControllerLoggingConfig)
+		}
+	}
+	if _, ok := c[LogSinkTargets]; ok {
+		targets := c.LogSinkTargets()
+		if targets.IsEmpty() {
+			return errors.Errorf("%s cannot be empty", LogSinkTargets)
+		}
+		for _, target := range targets.SortedValues() {
+			if target != LogSinkDatabase && target != LogSinkFile {
+				return errors.Errorf("%s: unknown target %q, expected %q or %q",
+					LogSinkTargets, target, LogSinkDatabase, LogSinkFile)
+			}
+		}
+	}
+	if v, ok := c[AgentLogfileMaxSize].(string); ok {
+		size, err := utils.ParseSize(v)
+		if err != nil {
+			return errors.Annotatef(err, "invalid %s", AgentLogfileMaxSize)
+		}
+		if size == 0 {
+			return errors.Errorf("%s cannot be 0", AgentLogfileMaxSize)
+		}
+	}
+	if v, ok := c[AgentLogfileMaxBackups].(int); ok && v < 0 {
+		return errors.Errorf("%s cannot be negative", AgentLogfileMaxBackups)
+	}
+
 	// TODO(thumper): remove MaxLogsAge and MaxLogsSize validation in 2.7 branch.
 	if v, ok := c[MaxLogsAge].(string); ok {
 		if _, err := time.ParseDuration(v); err != nil {
@@ -762,12 +1373,47 @@ func Validate(c Config) error {
 		}
 	}
 
+	if v, ok := c[MaxAuditTrailSize].(string); ok {
+		mb, err := utils.ParseSize(v)
+		if err != nil {
+			return errors.Annotate(err, "invalid max audit trail size in configuration")
+		}
+		if mb < 1 {
+			return errors.NotValidf("max audit trail size less than 1 MB")
+		}
+	}
+
+	if v, ok := c[MaxRelationSettingsSize].(string); ok {
+		mb, err := utils.ParseSize(v)
+		if err != nil {
+			return errors.Annotate(err, "invalid max relation settings size in configuration")
+		}
+		if mb < 1 {
+			return errors.NotValidf("max relation settings size less than 1 MB")
+		}
+		// The settings are stored in a single document, which mongo
+		// limits to 16MB.
+		if mb >= 16 {
+			return errors.NotValidf("max relation settings size of 16 MB or more")
+		}
+	}
+
 	if v, ok := c[PruneTxnSleepTime].(string); ok {
 		if _, err := time.ParseDuration(v); err != nil {
 			return errors.Annotatef(err, `%s must be a valid duration (eg "10ms")`, PruneTxnSleepTime)
 		}
 	}
 
+	if v, ok := c[UpgradeBatchSize].(int); ok && v < 1 {
+		return errors.Errorf("%s must be positive, got %d", UpgradeBatchSize, v)
+	}
+	if v, ok := c[UpgradeBatchSleepTime].(time.Duration); ok && v < 0 {
+		return errors.Errorf("%s cannot be negative", UpgradeBatchSleepTime)
+	}
+	if v, ok := c[UpgradeBatchMaxBackoff].(time.Duration); ok && v < 0 {
+		return errors.Errorf("%s cannot be negative", UpgradeBatchMaxBackoff)
+	}
+
 	if err := c.validateSpaceConfig(JujuHASpace, "juju HA"); err != nil {
 		return errors.Trace(err)
 	}
@@ -788,6 +1434,15 @@ func Validate(c Config) error {
 		}
 	}
 
+	username, _ := c[CAASImageRepoUsername].(string)
+	password, _ := c[CAASImageRepoPassword].(string)
+	if username != "" && password == "" {
+		return errors.NotValidf("%s without %s", CAASImageRepoUsername, CAASImageRepoPassword)
+	}
+	if password != "" && username == "" {
+		return errors.NotValidf("%s without %s", CAASImageRepoPassword, CAASImageRepoUsername)
+	}
+
 	var auditLogMaxSize int
 	if v, ok := c[AuditLogMaxSize].(string); ok {
 		if size, err := utils.ParseSize(v); err != nil {
@@ -897,71 +1552,125 @@ func GenerateControllerCertAndKey(caCert, caKey string, hostAddresses []string)
 }
 
 var configChecker = schema.FieldMap(schema.Fields{
-	AuditingEnabled:         schema.Bool(),
-	AuditLogCaptureArgs:     schema.Bool(),
-	AuditLogMaxSize:         schema.String(),
-	AuditLogMaxBackups:      schema.ForceInt(),
-	AuditLogExcludeMethods:  schema.List(schema.String()),
-	APIPort:                 schema.ForceInt(),
-	APIPortOpenDelay:        schema.String(),
-	ControllerAPIPort:       schema.ForceInt(),
-	StatePort:               schema.ForceInt(),
-	IdentityURL:             schema.String(),
-	IdentityPublicKey:       schema.String(),
-	SetNUMAControlPolicyKey: schema.Bool(),
-	AutocertURLKey:          schema.String(),
-	AutocertDNSNameKey:      schema.String(),
-	AllowModelAccessKey:     schema.Bool(),
-	MongoMemoryProfile:      schema.String(),
-	MaxDebugLogDuration:     schema.TimeDuration(),
-	MaxLogsAge:              schema.String(),
-	MaxLogsSize:             schema.String(),
-	MaxTxnLogSize:           schema.String(),
-	MaxPruneTxnBatchSize:    schema.ForceInt(),
-	MaxPruneTxnPasses:       schema.ForceInt(),
-	ModelLogsSize:           schema.String(),
-	PruneTxnQueryCount:      schema.ForceInt(),
-	PruneTxnSleepTime:       schema.String(),
-	JujuHASpace:             schema.String(),
-	JujuManagementSpace:     schema.String(),
-	CAASOperatorImagePath:   schema.String(),
-	CAASImageRepo:           schema.String(),
-	Features:                schema.List(schema.String()),
-	CharmStoreURL:           schema.String(),
-	MeteringURL:             schema.String(),
+	AuditingEnabled:             schema.Bool(),
+	AuditLogCaptureArgs:         schema.Bool(),
+	AuditLogMaxSize:             schema.String(),
+	AuditLogMaxBackups:          schema.ForceInt(),
+	AuditLogExcludeMethods:      schema.List(schema.String()),
+	APIPort:                     schema.ForceInt(),
+	APIPortOpenDelay:            schema.String(),
+	ControllerAPIPort:           schema.ForceInt(),
+	StatePort:                   schema.ForceInt(),
+	IdentityURL:                 schema.String(),
+	IdentityPublicKey:           schema.String(),
+	SetNUMAControlPolicyKey:     schema.Bool(),
+	AutocertURLKey:              schema.String(),
+	AutocertDNSNameKey:          schema.String(),
+	AllowModelAccessKey:         schema.Bool(),
+	MongoMemoryProfile:          schema.String(),
+	MaxDebugLogDuration:         schema.TimeDuration(),
+	StepUpAuthMaxAge:            schema.TimeDuration(),
+	StepUpAuthOperations:        schema.List(schema.String()),
+	StepUpAuthRPID:              schema.String(),
+	SaturationLeaseClaimLatency: schema.TimeDuration(),
+	SaturationTxnRetryRate:      schema.Float(),
+	SaturationWatcherLag:        schema.TimeDuration(),
+	SaturationAlertWebhook:      schema.String(),
+	APIRateLimit:                schema.Float(),
+	APIRateLimitBurst:           schema.ForceInt(),
+	APIAgentRateLimit:           schema.Float(),
+	APIAgentRateLimitBurst:      schema.ForceInt(),
+	APITraceSampleRate:          schema.Float(),
+	APIWebsocketPingInterval:    schema.TimeDuration(),
+	APIWebsocketPongTimeout:     schema.TimeDuration(),
+	ControllerLoggingConfig:     schema.String(),
+	LogSinkTargets:              schema.List(schema.String()),
+	AgentLogfileMaxSize:         schema.String(),
+	AgentLogfileMaxBackups:      schema.ForceInt(),
+	MaxLogsAge:                  schema.String(),
+	MaxLogsSize:                 schema.String(),
+	MaxTxnLogSize:               schema.String(),
+	MaxAuditTrailSize:           schema.String(),
+	AuditTrailEnabled:           schema.Bool(),
+	AuditTrailIncludeAgents:     schema.Bool(),
+	MaxRelationSettingsSize:     schema.String(),
+	MaxPruneTxnBatchSize:        schema.ForceInt(),
+	MaxPruneTxnPasses:           schema.ForceInt(),
+	ModelLogsSize:               schema.String(),
+	PruneTxnQueryCount:          schema.ForceInt(),
+	PruneTxnSleepTime:           schema.String(),
+	UpgradeBatchSize:            schema.ForceInt(),
+	UpgradeBatchSleepTime:       schema.TimeDuration(),
+	UpgradeBatchMaxBackoff:      schema.TimeDuration(),
+	JujuHASpace:                 schema.String(),
+	JujuManagementSpace:         schema.String(),
+	CAASOperatorImagePath:       schema.String(),
+	CAASImageRepo:               schema.String(),
+	CAASImageRepoUsername:       schema.String(),
+	CAASImageRepoPassword:       schema.String(),
+	Features:                    schema.List(schema.String()),
+	CharmStoreURL:               schema.String(),
+	MeteringURL:                 schema.String(),
 }, schema.Defaults{
-	APIPort:                 DefaultAPIPort,
-	APIPortOpenDelay:        DefaultAPIPortOpenDelay,
-	ControllerAPIPort:       schema.Omit,
-	AuditingEnabled:         DefaultAuditingEnabled,
-	AuditLogCaptureArgs:     DefaultAuditLogCaptureArgs,
-	AuditLogMaxSize:         fmt.Sprintf("%vM", DefaultAuditLogMaxSizeMB),
-	AuditLogMaxBackups:      DefaultAuditLogMaxBackups,
-	AuditLogExcludeMethods:  DefaultAuditLogExcludeMethods,
-	StatePort:               DefaultStatePort,
-	IdentityURL:             schema.Omit,
-	IdentityPublicKey:       schema.Omit,
-	SetNUMAControlPolicyKey: DefaultNUMAControlPolicy,
-	AutocertURLKey:          schema.Omit,
-	AutocertDNSNameKey:      schema.Omit,
-	AllowModelAccessKey:     schema.Omit,
-	MongoMemoryProfile:      DefaultMongoMemoryProfile,
-	MaxDebugLogDuration:     DefaultMaxDebugLogDuration,
-	MaxLogsAge:              fmt.Sprintf("%vh", DefaultMaxLogsAgeDays*24),
-	MaxLogsSize:             fmt.Sprintf("%vM", DefaultMaxLogCollectionMB),
-	MaxTxnLogSize:           fmt.Sprintf("%vM", DefaultMaxTxnLogCollectionMB),
-	MaxPruneTxnBatchSize:    DefaultMaxPruneTxnBatchSize,
-	MaxPruneTxnPasses:       DefaultMaxPruneTxnPasses,
-	ModelLogsSize:           fmt.Sprintf("%vM", DefaultModelLogsSizeMB),
-	PruneTxnQueryCount:      DefaultPruneTxnQueryCount,
-	PruneTxnSleepTime:       DefaultPruneTxnSleepTime,
-	JujuHASpace:             schema.Omit,
-	JujuManagementSpace:     schema.Omit,
-	CAASOperatorImagePath:   schema.Omit,
-	CAASImageRepo:           schema.Omit,
-	Features:                schema.Omit,
-	CharmStoreURL:           csclient.ServerURL,
-	MeteringURL:             romulus.DefaultAPIRoot,
+	APIPort:                     DefaultAPIPort,
+	APIPortOpenDelay:            DefaultAPIPortOpenDelay,
+	ControllerAPIPort:           schema.Omit,
+	AuditingEnabled:             DefaultAuditingEnabled,
+	AuditLogCaptureArgs:         DefaultAuditLogCaptureArgs,
+	AuditLogMaxSize:             fmt.Sprintf("%vM", DefaultAuditLogMaxSizeMB),
+	AuditLogMaxBackups:          DefaultAuditLogMaxBackups,
+	AuditLogExcludeMethods:      DefaultAuditLogExcludeMethods,
+	StatePort:                   DefaultStatePort,
+	IdentityURL:                 schema.Omit,
+	IdentityPublicKey:           schema.Omit,
+	SetNUMAControlPolicyKey:     DefaultNUMAControlPolicy,
+	AutocertURLKey:              schema.Omit,
+	AutocertDNSNameKey:          schema.Omit,
+	AllowModelAccessKey:         schema.Omit,
+	MongoMemoryProfile:          DefaultMongoMemoryProfile,
+	MaxDebugLogDuration:         DefaultMaxDebugLogDuration,
+	StepUpAuthMaxAge:            schema.Omit,
+	StepUpAuthOperations:        schema.Omit,
+	StepUpAuthRPID:              schema.Omit,
+	SaturationLeaseClaimLatency: schema.Omit,
+	SaturationTxnRetryRate:      schema.Omit,
+	SaturationWatcherLag:        schema.Omit,
+	SaturationAlertWebhook:      schema.Omit,
+	APIRateLimit:                schema.Omit,
+	APIRateLimitBurst:           schema.Omit,
+	APIAgentRateLimit:           schema.Omit,
+	APIAgentRateLimitBurst:      schema.Omit,
+	APITraceSampleRate:          schema.Omit,
+	APIWebsocketPingInterval:    schema.Omit,
+	APIWebsocketPongTimeout:     schema.Omit,
+	ControllerLoggingConfig:     schema.Omit,
+	LogSinkTargets:              schema.Omit,
+	AgentLogfileMaxSize:         schema.Omit,
+	AgentLogfileMaxBackups:      schema.Omit,
+	MaxLogsAge:                  fmt.Sprintf("%vh", DefaultMaxLogsAgeDays*24),
+	MaxLogsSize:                 fmt.Sprintf("%vM", DefaultMaxLogCollectionMB),
+	MaxTxnLogSize:               fmt.Sprintf("%vM", DefaultMaxTxnLogCollectionMB),
+	MaxAuditTrailSize:           schema.Omit,
+	AuditTrailEnabled:           schema.Omit,
+	AuditTrailIncludeAgents:     schema.Omit,
+	MaxRelationSettingsSize:     schema.Omit,
+	MaxPruneTxnBatchSize:        DefaultMaxPruneTxnBatchSize,
+	MaxPruneTxnPasses:           DefaultMaxPruneTxnPasses,
+	ModelLogsSize:               fmt.Sprintf("%vM", DefaultModelLogsSizeMB),
+	PruneTxnQueryCount:          DefaultPruneTxnQueryCount,
+	PruneTxnSleepTime:           DefaultPruneTxnSleepTime,
+	UpgradeBatchSize:            schema.Omit,
+	UpgradeBatchSleepTime:       schema.Omit,
+	UpgradeBatchMaxBackoff:      schema.Omit,
+	JujuHASpace:                 schema.Omit,
+	JujuManagementSpace:         schema.Omit,
+	CAASOperatorImagePath:       schema.Omit,
+	CAASImageRepo:               schema.Omit,
+	CAASImageRepoUsername:       schema.Omit,
+	CAASImageRepoPassword:       schema.Omit,
+	Features:                    schema.Omit,
+	CharmStoreURL:               csclient.ServerURL,
+	MeteringURL:                 romulus.DefaultAPIRoot,
 })
 
 // ConfigSchema holds information on all the fields defined by
@@ -1044,6 +1753,80 @@ they don't have any access rights to the controller itself`,
 		Type:        environschema.Tstring,
 		Description: `The maximum amout of time a debug-log session is allowed to run`,
 	},
+	StepUpAuthMaxAge: {
+		Type: environschema.Tstring,
+		Description: `The maximum age of a second factor assertion, made on the
+same API connection, required for the operations in step-up-auth-operations`,
+	},
+	StepUpAuthOperations: {
+		Type: environschema.FieldType("list of strings"),
+		Description: `The destructive operations that require step-up authentication
+(destroy-controller, force-remove-machine)`,
+	},
+	StepUpAuthRPID: {
+		Type:        environschema.Tstring,
+		Description: `The webauthn relying party ID for second factor assertions`,
+	},
+	SaturationLeaseClaimLatency: {
+		Type:        environschema.Tstring,
+		Description: `The lease claim latency above which a controller reports it is saturated (disabled if unset)`,
+	},
+	SaturationTxnRetryRate: {
+		Type:        environschema.Tstring,
+		Description: `The fraction of failed transaction operations above which a controller reports it is saturated (disabled if unset)`,
+	},
+	SaturationWatcherLag: {
+		Type:        environschema.Tstring,
+		Description: `The watcher delivery lag above which a controller reports it is saturated (disabled if unset)`,
+	},
+	SaturationAlertWebhook: {
+		Type:        environschema.Tstring,
+		Description: `The URL to which controller saturation alerts are posted`,
+	},
+	APIRateLimit: {
+		Type:        environschema.Tstring,
+		Description: `The number of API requests per second each user or agent may make (disabled if unset)`,
+	},
+	APIRateLimitBurst: {
+		Type:        environschema.Tint,
+		Description: `The number of API requests each user or agent may make in a burst when rate limited`,
+	},
+	APIAgentRateLimit: {
+		Type:        environschema.Tstring,
+		Description: `The number of API requests per second each agent may make (api-rate-limit if unset)`,
+	},
+	APIAgentRateLimitBurst: {
+		Type:        environschema.Tint,
+		Description: `The number of API requests each agent may make in a burst when rate limited (api-rate-limit-burst if unset)`,
+	},
+	APITraceSampleRate: {
+		Type:        environschema.Tstring,
+		Description: `The fraction of API requests traced by the controller when their clients aren't tracing them (none if unset)`,
+	},
+	APIWebsocketPingInterval: {
+		Type:        environschema.Tstring,
+		Description: `How often the controller pings API clients to detect half-open connections (disabled if zero)`,
+	},
+	APIWebsocketPongTimeout: {
+		Type:        environschema.Tstring,
+		Description: `How long an API client has to answer a ping before its connection is closed`,
+	},
+	ControllerLoggingConfig: {
+		Type:        environschema.Tstring,
+		Description: `Logging levels for controller agents, overriding the logging-config of the controller model`,
+	},
+	LogSinkTargets: {
+		Type:        environschema.FieldType("list of strings"),
+		Description: `Where agent logs sent to the controller are written: "database", "file" or both (default both)`,
+	},
+	AgentLogfileMaxSize: {
+		Type:        environschema.Tstring,
+		Description: `The size at which the logsink.log file of agent logs is rotated (default 300M)`,
+	},
+	AgentLogfileMaxBackups: {
+		Type:        environschema.Tint,
+		Description: `The number of rotated logsink.log files to keep (default 2)`,
+	},
 	MaxLogsAge: {
 		Type:        environschema.Tstring,
 		Description: `The maximum age for log entries`,
@@ -1056,6 +1839,22 @@ they don't have any access rights to the controller itself`,
 		Type:        environschema.Tstring,
 		Description: `The maximum size the of capped txn log collection`,
 	},
+	MaxAuditTrailSize: {
+		Type:        environschema.Tstring,
+		Description: `The maximum size of the capped collection holding the audit trail of API calls`,
+	},
+	AuditTrailEnabled: {
+		Type:        environschema.Tbool,
+		Description: `Determines if the controller records an audit trail of API calls`,
+	},
+	AuditTrailIncludeAgents: {
+		Type:        environschema.Tbool,
+		Description: `Determines if the audit trail records calls made by agents, and calls on watchers`,
+	},
+	MaxRelationSettingsSize: {
+		Type:        environschema.Tstring,
+		Description: `The maximum size of the settings of a unit in a relation`,
+	},
 	MaxPruneTxnBatchSize: {
 		Type:        environschema.Tint,
 		Description: `(deprecated) The maximum number of transactions evaluated in one go when pruning`,
@@ -1076,6 +1875,18 @@ they don't have any access rights to the controller itself`,
 		Type:        environschema.Tstring,
 		Description: `The amount of time to sleep between processing each batch query`,
 	},
+	UpgradeBatchSize: {
+		Type:        environschema.Tint,
+		Description: `The number of documents processed in each batch by upgrade steps that rewrite whole collections`,
+	},
+	UpgradeBatchSleepTime: {
+		Type:        environschema.Tstring,
+		Description: `The amount of time upgrade steps sleep between batches (no sleep if zero)`,
+	},
+	UpgradeBatchMaxBackoff: {
+		Type:        environschema.Tstring,
+		Description: `The longest time upgrade steps back off between batches when the database is slow`,
+	},
 	JujuHASpace: {
 		Type:        environschema.Tstring,
 		Description: `The network space within which the MongoDB replica-set should communicate`,
@@ -1093,6 +1904,15 @@ Use "caas-image-repo" instead.`,
 		Type:        environschema.Tstring,
 		Description: `The docker repo to use for the jujud operator and mongo images`,
 	},
+	CAASImageRepoUsername: {
+		Type:        environschema.Tstring,
+		Description: `The username used to pull the jujud operator image from a private docker registry`,
+	},
+	CAASImageRepoPassword: {
+		Type:        environschema.Tstring,
+		Description: `The password used to pull the jujud operator image from a private docker registry`,
+		Secret:      true,
+	},
 	Features: {
 		Type:        environschema.FieldType("list of strings"),
 		Description: `A list of runtime changeable features to be updated`,
@@ -202,6 +202,20 @@ var validateTests = []struct {
 		controller.ModelLogsSize: "0",
 	},
 	expectError: "model logs size less than 1 MB not valid",
+}, {
+	about: "zero max relation settings size",
+	config: controller.Config{
+		controller.CACertKey:               testing.CACert,
+		controller.MaxRelationSettingsSize: "0",
+	},
+	expectError: "max relation settings size less than 1 MB not valid",
+}, {
+	about: "max relation settings size past mongo document limit",
+	config: controller.Config{
+		controller.CACertKey:               testing.CACert,
+		controller.MaxRelationSettingsSize: "16M",
+	},
+	expectError: "max relation settings size of 16 MB or more not valid",
 }, {
 	about: "invalid CAAS docker image repo",
 	config: controller.Config{
@@ -244,6 +258,20 @@ var validateTests = []struct {
 		controller.CAASImageRepo: "foo//bar",
 	},
 	expectError: `docker image path "foo//bar" not valid`,
+}, {
+	about: "CAAS docker image repo username without password",
+	config: controller.Config{
+		controller.CACertKey:             testing.CACert,
+		controller.CAASImageRepoUsername: "fred",
+	},
+	expectError: `caas-image-repo-username without caas-image-repo-password not valid`,
+}, {
+	about: "CAAS docker image repo password without username",
+	config: controller.Config{
+		controller.CACertKey:             testing.CACert,
+		controller.CAASImageRepoPassword: "secret",
+	},
+	expectError: `caas-image-repo-password without caas-image-repo-username not valid`,
 }, {
 	about: "negative controller-api-port",
 	config: controller.Config{
@@ -362,6 +390,48 @@ func (s *ConfigSuite) TestTxnLogConfigValue(c *gc.C) {
 	c.Assert(cfg.MaxTxnLogSizeMB(), gc.Equals, 8192)
 }
 
+func (s *ConfigSuite) TestAuditTrailConfigDefault(c *gc.C) {
+	cfg, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.MaxAuditTrailSizeMB(), gc.Equals, 100)
+	c.Assert(cfg.AuditTrailEnabled(), jc.IsFalse)
+	c.Assert(cfg.AuditTrailIncludeAgents(), jc.IsFalse)
+}
+
+func (s *ConfigSuite) TestAuditTrailConfigValue(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			"max-audit-trail-size":       "1G",
+			"audit-trail-enabled":        true,
+			"audit-trail-include-agents": true,
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.MaxAuditTrailSizeMB(), gc.Equals, 1024)
+	c.Assert(cfg.AuditTrailEnabled(), jc.IsTrue)
+	c.Assert(cfg.AuditTrailIncludeAgents(), jc.IsTrue)
+}
+
+func (s *ConfigSuite) TestMaxRelationSettingsSizeDefault(c *gc.C) {
+	cfg, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.MaxRelationSettingsSize(), gc.Equals, 1024*1024)
+}
+
+func (s *ConfigSuite) TestMaxRelationSettingsSizeValue(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			"max-relation-settings-size": "4M",
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.MaxRelationSettingsSize(), gc.Equals, 4*1024*1024)
+}
+
 func (s *ConfigSuite) TestMaxPruneTxnConfigDefault(c *gc.C) {
 	cfg, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, nil)
 	c.Assert(err, jc.ErrorIsNil)
@@ -397,6 +467,48 @@ func (s *ConfigSuite) TestPruneTxnQueryCount(c *gc.C) {
 	c.Check(cfg.PruneTxnSleepTime(), gc.Equals, 5*time.Millisecond)
 }
 
+func (s *ConfigSuite) TestUpgradeBatchThrottling(c *gc.C) {
+	cfg, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cfg.UpgradeBatchSize(), gc.Equals, controller.DefaultUpgradeBatchSize)
+	c.Check(cfg.UpgradeBatchSleepTime(), gc.Equals, controller.DefaultUpgradeBatchSleepTime)
+	c.Check(cfg.UpgradeBatchMaxBackoff(), gc.Equals, controller.DefaultUpgradeBatchMaxBackoff)
+
+	cfg, err = controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			"upgrade-batch-size":        "200",
+			"upgrade-batch-sleep-time":  "0s",
+			"upgrade-batch-max-backoff": "1m",
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cfg.UpgradeBatchSize(), gc.Equals, 200)
+	c.Check(cfg.UpgradeBatchSleepTime(), gc.Equals, time.Duration(0))
+	c.Check(cfg.UpgradeBatchMaxBackoff(), gc.Equals, time.Minute)
+}
+
+func (s *ConfigSuite) TestUpgradeBatchThrottlingInvalid(c *gc.C) {
+	for i, test := range []struct {
+		attrs  map[string]interface{}
+		expect string
+	}{{
+		attrs:  map[string]interface{}{"upgrade-batch-size": 0},
+		expect: "upgrade-batch-size must be positive, got 0",
+	}, {
+		attrs:  map[string]interface{}{"upgrade-batch-sleep-time": "-1s"},
+		expect: "upgrade-batch-sleep-time cannot be negative",
+	}, {
+		attrs:  map[string]interface{}{"upgrade-batch-max-backoff": "-1s"},
+		expect: "upgrade-batch-max-backoff cannot be negative",
+	}} {
+		c.Logf("test %d", i)
+		_, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, test.attrs)
+		c.Check(err, gc.ErrorMatches, test.expect)
+	}
+}
+
 func (s *ConfigSuite) TestNetworkSpaceConfigValues(c *gc.C) {
 	haSpace := "space1"
 	managementSpace := "space2"
@@ -549,6 +661,21 @@ func (s *ConfigSuite) TestCAASImageRepo(c *gc.C) {
 	}
 }
 
+func (s *ConfigSuite) TestCAASImageRepoCredentials(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			controller.CAASImageRepo:         "registry.foo.com/me",
+			controller.CAASImageRepoUsername: "fred",
+			controller.CAASImageRepoPassword: "secret",
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.CAASImageRepoUsername(), gc.Equals, "fred")
+	c.Assert(cfg.CAASImageRepoPassword(), gc.Equals, "secret")
+}
+
 func (s *ConfigSuite) TestCharmstoreURLDefault(c *gc.C) {
 	cfg, err := controller.NewConfig(
 		testing.ControllerTag.Id(),
@@ -618,6 +745,327 @@ func (s *ConfigSuite) TestMaxDebugLogDurationSchemaCoerce(c *gc.C) {
 	c.Assert(err.Error(), gc.Equals, "max-debug-log-duration: conversion to duration: time: missing unit in duration 12")
 }
 
+func (s *ConfigSuite) TestStepUpAuth(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			"step-up-auth-max-age": "5m",
+			"step-up-auth-rp-id":   "juju.example.com",
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.StepUpAuthMaxAge(), gc.Equals, 5*time.Minute)
+	c.Assert(cfg.StepUpAuthRPID(), gc.Equals, "juju.example.com")
+}
+
+func (s *ConfigSuite) TestStepUpAuthDefault(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.StepUpAuthMaxAge(), gc.Equals, time.Duration(0))
+}
+
+func (s *ConfigSuite) TestStepUpAuthRequiresRPID(c *gc.C) {
+	_, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			"step-up-auth-max-age": "5m",
+		},
+	)
+	c.Assert(err, gc.ErrorMatches, "step-up-auth-max-age requires step-up-auth-rp-id")
+}
+
+func (s *ConfigSuite) TestStepUpAuthOperations(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			"step-up-auth-max-age":    "5m",
+			"step-up-auth-rp-id":      "juju.example.com",
+			"step-up-auth-operations": []string{"destroy-controller"},
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.StepUpAuthOperations().SortedValues(), jc.DeepEquals, []string{"destroy-controller"})
+}
+
+func (s *ConfigSuite) TestStepUpAuthOperationsInvalid(c *gc.C) {
+	for i, t := range []struct {
+		attrs map[string]interface{}
+		err   string
+	}{{
+		attrs: map[string]interface{}{
+			"step-up-auth-operations": []string{"destroy-controller"},
+		},
+		err: "step-up-auth-operations requires step-up-auth-max-age",
+	}, {
+		attrs: map[string]interface{}{
+			"step-up-auth-max-age":    "5m",
+			"step-up-auth-rp-id":      "juju.example.com",
+			"step-up-auth-operations": []string{"remove-everything"},
+		},
+		err: `step-up-auth-operations: unknown operations \[remove-everything\]`,
+	}} {
+		c.Logf("test %d", i)
+		_, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, t.attrs)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+}
+
+func (s *ConfigSuite) TestSaturationThresholds(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			"saturation-lease-claim-latency": "2s",
+			"saturation-txn-retry-rate":      0.25,
+			"saturation-watcher-lag":         "30s",
+			"saturation-alert-webhook":       "https://alerts.example.com/juju",
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.SaturationLeaseClaimLatency(), gc.Equals, 2*time.Second)
+	c.Assert(cfg.SaturationTxnRetryRate(), gc.Equals, 0.25)
+	c.Assert(cfg.SaturationWatcherLag(), gc.Equals, 30*time.Second)
+	c.Assert(cfg.SaturationAlertWebhook(), gc.Equals, "https://alerts.example.com/juju")
+}
+
+func (s *ConfigSuite) TestSaturationThresholdsDefault(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.SaturationLeaseClaimLatency(), gc.Equals, time.Duration(0))
+	c.Assert(cfg.SaturationTxnRetryRate(), gc.Equals, 0.0)
+	c.Assert(cfg.SaturationWatcherLag(), gc.Equals, time.Duration(0))
+	c.Assert(cfg.SaturationAlertWebhook(), gc.Equals, "")
+}
+
+func (s *ConfigSuite) TestSaturationThresholdsInvalid(c *gc.C) {
+	for i, test := range []struct {
+		attrs  map[string]interface{}
+		expect string
+	}{{
+		attrs:  map[string]interface{}{"saturation-watcher-lag": "-1s"},
+		expect: "saturation-watcher-lag cannot be negative",
+	}, {
+		attrs:  map[string]interface{}{"saturation-txn-retry-rate": 1.5},
+		expect: "saturation-txn-retry-rate must be between 0 and 1, got 1.5",
+	}, {
+		attrs:  map[string]interface{}{"saturation-alert-webhook": "ftp://example.com"},
+		expect: `saturation-alert-webhook must be an http or https URL, got "ftp://example.com"`,
+	}} {
+		c.Logf("test %d", i)
+		_, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, test.attrs)
+		c.Check(err, gc.ErrorMatches, test.expect)
+	}
+}
+
+func (s *ConfigSuite) TestAPIRateLimit(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			"api-rate-limit":       2.5,
+			"api-rate-limit-burst": 10,
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.APIRateLimit(), gc.Equals, 2.5)
+	c.Assert(cfg.APIRateLimitBurst(), gc.Equals, 10)
+}
+
+func (s *ConfigSuite) TestAPIRateLimitDefault(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.APIRateLimit(), gc.Equals, 0.0)
+	c.Assert(cfg.APIRateLimitBurst(), gc.Equals, controller.DefaultAPIRateLimitBurst)
+	c.Assert(cfg.APIAgentRateLimit(), gc.Equals, 0.0)
+	c.Assert(cfg.APIAgentRateLimitBurst(), gc.Equals, controller.DefaultAPIRateLimitBurst)
+}
+
+func (s *ConfigSuite) TestAPIAgentRateLimit(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			"api-rate-limit":             2.5,
+			"api-rate-limit-burst":       10,
+			"api-agent-rate-limit":       20.0,
+			"api-agent-rate-limit-burst": 50,
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.APIAgentRateLimit(), gc.Equals, 20.0)
+	c.Assert(cfg.APIAgentRateLimitBurst(), gc.Equals, 50)
+}
+
+func (s *ConfigSuite) TestAPIAgentRateLimitDefaultsToUserLimit(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			"api-rate-limit":       2.5,
+			"api-rate-limit-burst": 10,
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.APIAgentRateLimit(), gc.Equals, 2.5)
+	c.Assert(cfg.APIAgentRateLimitBurst(), gc.Equals, 10)
+}
+
+func (s *ConfigSuite) TestAPIRateLimitInvalid(c *gc.C) {
+	for i, test := range []struct {
+		attrs  map[string]interface{}
+		expect string
+	}{{
+		attrs:  map[string]interface{}{"api-rate-limit": -1.0},
+		expect: "api-rate-limit cannot be negative",
+	}, {
+		attrs:  map[string]interface{}{"api-rate-limit-burst": 0},
+		expect: "api-rate-limit-burst must be positive, got 0",
+	}, {
+		attrs:  map[string]interface{}{"api-agent-rate-limit": -1.0},
+		expect: "api-agent-rate-limit cannot be negative",
+	}, {
+		attrs:  map[string]interface{}{"api-agent-rate-limit-burst": 0},
+		expect: "api-agent-rate-limit-burst must be positive, got 0",
+	}} {
+		c.Logf("test %d", i)
+		_, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, test.attrs)
+		c.Check(err, gc.ErrorMatches, test.expect)
+	}
+}
+
+func (s *ConfigSuite) TestAPITraceSampleRate(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.APITraceSampleRate(), gc.Equals, 0.0)
+
+	cfg, err = controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{"api-trace-sample-rate": 0.25},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.APITraceSampleRate(), gc.Equals, 0.25)
+
+	_, err = controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{"api-trace-sample-rate": 1.5},
+	)
+	c.Assert(err, gc.ErrorMatches, "api-trace-sample-rate must be between 0 and 1, got 1.5")
+}
+
+func (s *ConfigSuite) TestAPIWebsocketKeepAlive(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.APIWebsocketPingInterval(), gc.Equals, controller.DefaultAPIWebsocketPingInterval)
+	c.Assert(cfg.APIWebsocketPongTimeout(), gc.Equals, controller.DefaultAPIWebsocketPongTimeout)
+
+	cfg, err = controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			"api-websocket-ping-interval": "20s",
+			"api-websocket-pong-timeout":  "30s",
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.APIWebsocketPingInterval(), gc.Equals, 20*time.Second)
+	c.Assert(cfg.APIWebsocketPongTimeout(), gc.Equals, 30*time.Second)
+}
+
+func (s *ConfigSuite) TestAPIWebsocketKeepAliveInvalid(c *gc.C) {
+	for i, test := range []struct {
+		attrs  map[string]interface{}
+		expect string
+	}{{
+		attrs:  map[string]interface{}{"api-websocket-ping-interval": "-1s"},
+		expect: "api-websocket-ping-interval cannot be negative",
+	}, {
+		attrs:  map[string]interface{}{"api-websocket-pong-timeout": "0s"},
+		expect: "api-websocket-pong-timeout must be positive",
+	}, {
+		attrs:  map[string]interface{}{"api-websocket-pong-timeout": "30s"},
+		expect: `api-websocket-pong-timeout \(30s\) must be greater than api-websocket-ping-interval \(1m0s\)`,
+	}} {
+		c.Logf("test %d", i)
+		_, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, test.attrs)
+		c.Check(err, gc.ErrorMatches, test.expect)
+	}
+}
+
+func (s *ConfigSuite) TestControllerLogging(c *gc.C) {
+	cfg, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, map[string]interface{}{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.ControllerLoggingConfig(), gc.Equals, "")
+	c.Assert(cfg.LogSinkTargets().SortedValues(), jc.DeepEquals, []string{"database", "file"})
+	c.Assert(cfg.AgentLogfileMaxSizeMB(), gc.Equals, controller.DefaultAgentLogfileMaxSizeMB)
+	c.Assert(cfg.AgentLogfileMaxBackups(), gc.Equals, controller.DefaultAgentLogfileMaxBackups)
+
+	cfg, err = controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, map[string]interface{}{
+		"controller-logging-config": "juju.apiserver=DEBUG",
+		"logsink-targets":           []interface{}{"database"},
+		"agent-logfile-max-size":    "1G",
+		"agent-logfile-max-backups": 5,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.ControllerLoggingConfig(), gc.Equals, "juju.apiserver=DEBUG")
+	c.Assert(cfg.LogSinkTargets().SortedValues(), jc.DeepEquals, []string{"database"})
+	c.Assert(cfg.AgentLogfileMaxSizeMB(), gc.Equals, 1024)
+	c.Assert(cfg.AgentLogfileMaxBackups(), gc.Equals, 5)
+}
+
+func (s *ConfigSuite) TestControllerLoggingInvalid(c *gc.C) {
+	for i, test := range []struct {
+		attrs  map[string]interface{}
+		expect string
+	}{{
+		attrs:  map[string]interface{}{"controller-logging-config": "juju.apiserver=LOUD"},
+		expect: `invalid controller-logging-config: .*`,
+	}, {
+		attrs:  map[string]interface{}{"logsink-targets": []interface{}{}},
+		expect: "logsink-targets cannot be empty",
+	}, {
+		attrs:  map[string]interface{}{"logsink-targets": []interface{}{"database", "syslog"}},
+		expect: `logsink-targets: unknown target "syslog", expected "database" or "file"`,
+	}, {
+		attrs:  map[string]interface{}{"agent-logfile-max-size": "big"},
+		expect: `invalid agent-logfile-max-size: .*`,
+	}, {
+		attrs:  map[string]interface{}{"agent-logfile-max-size": "0"},
+		expect: "agent-logfile-max-size cannot be 0",
+	}, {
+		attrs:  map[string]interface{}{"agent-logfile-max-backups": -1},
+		expect: "agent-logfile-max-backups cannot be negative",
+	}} {
+		c.Logf("test %d", i)
+		_, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, test.attrs)
+		c.Check(err, gc.ErrorMatches, test.expect)
+	}
+}
+
 func (s *ConfigSuite) TestMaxDebugLogDurationDefault(c *gc.C) {
 	cfg, err := controller.NewConfig(
 		testing.ControllerTag.Id(),
@@ -627,3 +1075,27 @@ func (s *ConfigSuite) TestMaxDebugLogDurationDefault(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(cfg.MaxDebugLogDuration(), gc.Equals, controller.DefaultMaxDebugLogDuration)
 }
+
+func (s *ConfigSuite) TestDeprecatedAttributes(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cfg.DeprecatedAttributes(), gc.HasLen, 0)
+
+	cfg, err = controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			controller.CAASOperatorImagePath: "jujusolutions/jujud-operator",
+			controller.MaxPruneTxnPasses:     10,
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cfg.DeprecatedAttributes(), jc.DeepEquals, []string{
+		controller.CAASOperatorImagePath,
+		controller.MaxPruneTxnPasses,
+	})
+}
@@ -0,0 +1,219 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cache
+
+import (
+	"github.com/juju/collections/set"
+)
+
+// LXDProfileMachinesWatcher notifies with the ids of model machines that
+// host units of applications whose charms declare an LXD profile.
+// A machine id is sent the first time the machine is found to host such
+// a unit, and again when the machine is removed from the model.
+// Machines that later stop hosting such units are not reported, it is
+// up to the per machine watchers to deal with the profiles left behind.
+type LXDProfileMachinesWatcher struct {
+	*stringsWatcherBase
+
+	model *Model
+	fn    predicateFunc
+
+	initialized chan struct{}
+	// reported holds the ids of the machines already sent to the
+	// watcher's consumer. It is only accessed from the multiplexer's
+	// handlers once initialized, so needs no lock protection.
+	reported set.Strings
+}
+
+func newLXDProfileMachinesWatcher(model *Model, fn predicateFunc) *LXDProfileMachinesWatcher {
+	w := &LXDProfileMachinesWatcher{
+		// The initial event is only known once the watcher has
+		// subscribed to changes, so it is sent by init below.
+		stringsWatcherBase: &stringsWatcherBase{changes: make(chan []string, 1)},
+		model:              model,
+		fn:                 fn,
+		initialized:        make(chan struct{}),
+		reported:           set.NewStrings(),
+	}
+
+	deregister := model.registerWorker(w)
+	multi := model.hub.NewMultiplexer()
+	multi.Add(modelAddRemoveMachine, w.machineChange)
+	multi.Add(modelUnitAdd, w.addUnit)
+	multi.Add(applicationCharmURLChange, w.applicationCharmURLChange)
+
+	w.tomb.Go(func() error {
+		<-w.tomb.Dying()
+		multi.Unsubscribe()
+		deregister()
+		return nil
+	})
+
+	w.init()
+	close(w.initialized)
+	return w
+}
+
+// init sends the initial event with the ids of the machines currently
+// hosting units that need an LXD profile.
+func (w *LXDProfileMachinesWatcher) init() {
+	for id := range w.model.Machines() {
+		if w.fn(id) && w.needsLXDProfile(id) {
+			w.reported.Add(id)
+		}
+	}
+	w.changes <- w.reported.SortedValues()
+}
+
+// machineChange reports machines removed from the model that were
+// previously reported, and added machines which already host units
+// needing an LXD profile.
+func (w *LXDProfileMachinesWatcher) machineChange(_ string, value interface{}) {
+	if !w.waitInitialized() {
+		return
+	}
+	ids, ok := value.([]string)
+	if !ok {
+		logger.Errorf("programming error, value not of type []string")
+		return
+	}
+
+	changed := set.NewStrings()
+	for _, id := range ids {
+		if !w.fn(id) {
+			continue
+		}
+		if w.reported.Contains(id) {
+			// The same topic is used for additions and removals;
+			// a machine we already reported can only be going away.
+			w.reported.Remove(id)
+			changed.Add(id)
+			continue
+		}
+		if w.needsLXDProfile(id) {
+			w.reported.Add(id)
+			changed.Add(id)
+		}
+	}
+	w.notifyChanged(changed)
+}
+
+// addUnit reports the unit's machine if it has not already been
+// reported and now hosts a unit needing an LXD profile.
+func (w *LXDProfileMachinesWatcher) addUnit(_ string, value interface{}) {
+	if !w.waitInitialized() {
+		return
+	}
+	unit, ok := value.(Unit)
+	if !ok {
+		logger.Errorf("programming error, value not of type Unit")
+		return
+	}
+	w.notifyChanged(w.checkMachines(w.unitMachineId(unit)))
+}
+
+// applicationCharmURLChange reports the unreported machines hosting
+// units of the application, when the application's new charm declares
+// an LXD profile.
+func (w *LXDProfileMachinesWatcher) applicationCharmURLChange(_ string, value interface{}) {
+	if !w.waitInitialized() {
+		return
+	}
+	values, ok := value.(appCharmUrlChange)
+	if !ok {
+		logger.Errorf("programming error, value not of type appCharmUrlChange")
+		return
+	}
+	ch, err := w.model.Charm(values.chURL)
+	if err != nil {
+		logger.Debugf("charm %s for %s not yet cached: %v", values.chURL, values.appName, err)
+		return
+	}
+	if ch.LXDProfile().Empty() {
+		return
+	}
+
+	var ids []string
+	for _, unit := range w.model.Units() {
+		if unit.Application() == values.appName {
+			ids = append(ids, w.unitMachineId(unit))
+		}
+	}
+	w.notifyChanged(w.checkMachines(ids...))
+}
+
+// checkMachines returns those of the input machine ids that were not
+// yet reported and now need an LXD profile, marking them as reported.
+func (w *LXDProfileMachinesWatcher) checkMachines(ids ...string) set.Strings {
+	changed := set.NewStrings()
+	for _, id := range ids {
+		if id == "" || !w.fn(id) || w.reported.Contains(id) || changed.Contains(id) {
+			continue
+		}
+		if w.needsLXDProfile(id) {
+			w.reported.Add(id)
+			changed.Add(id)
+		}
+	}
+	return changed
+}
+
+// needsLXDProfile returns true if the machine with the input id hosts
+// a unit of an application whose charm declares an LXD profile.
+func (w *LXDProfileMachinesWatcher) needsLXDProfile(machineId string) bool {
+	machine, err := w.model.Machine(machineId)
+	if err != nil {
+		return false
+	}
+	// Units returns what it found along with any error about a
+	// missing principal, so just carry on with what we have.
+	units, _ := machine.Units()
+	for _, unit := range units {
+		curl := unit.CharmURL()
+		if app, err := w.model.Application(unit.Application()); err == nil && app.CharmURL() != "" {
+			curl = app.CharmURL()
+		}
+		if curl == "" {
+			continue
+		}
+		ch, err := w.model.Charm(curl)
+		if err != nil {
+			continue
+		}
+		if !ch.LXDProfile().Empty() {
+			return true
+		}
+	}
+	return false
+}
+
+// unitMachineId returns the id of the machine hosting the unit, or of
+// its principal if the unit is a subordinate.
+func (w *LXDProfileMachinesWatcher) unitMachineId(unit Unit) string {
+	if !unit.Subordinate() {
+		return unit.MachineId()
+	}
+	principal, err := w.model.Unit(unit.Principal())
+	if err != nil {
+		return ""
+	}
+	return principal.MachineId()
+}
+
+func (w *LXDProfileMachinesWatcher) notifyChanged(changed set.Strings) {
+	if !changed.IsEmpty() {
+		w.notify(changed.SortedValues())
+	}
+}
+
+// waitInitialized blocks until the initial event has been determined.
+// It returns false if the watcher is dying.
+func (w *LXDProfileMachinesWatcher) waitInitialized() bool {
+	select {
+	case <-w.initialized:
+		return true
+	case <-w.tomb.Dying():
+		return false
+	}
+}
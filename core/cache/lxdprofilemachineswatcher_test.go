@@ -0,0 +1,163 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cache_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/worker.v1/workertest"
+
+	"github.com/juju/juju/core/cache"
+)
+
+type lxdProfileMachinesWatcherSuite struct {
+	cache.EntitySuite
+
+	model *cache.Model
+}
+
+var _ = gc.Suite(&lxdProfileMachinesWatcherSuite{})
+
+func (s *lxdProfileMachinesWatcherSuite) SetUpTest(c *gc.C) {
+	s.EntitySuite.SetUpTest(c)
+
+	// Machine 0 hosts a unit of an application whose charm has an
+	// lxd profile, machine 1 hosts nothing, and machine 0/lxd/0 is
+	// a container hosting a unit with a profile.
+	s.model = s.NewModel(modelChange)
+	s.model.UpdateCharm(charmChange, s.Manager)
+	s.model.UpdateApplication(appChange, s.Manager)
+	s.model.UpdateMachine(machineChange, s.Manager)
+	s.model.UpdateUnit(unitChange, s.Manager)
+
+	mc := machineChange
+	mc.Id = "1"
+	s.model.UpdateMachine(mc, s.Manager)
+
+	mc.Id = "0/lxd/0"
+	s.model.UpdateMachine(mc, s.Manager)
+	uc := unitChange
+	uc.Name = "application-name/1"
+	uc.MachineId = mc.Id
+	s.model.UpdateUnit(uc, s.Manager)
+
+	// An application whose charm has no profile.
+	s.model.UpdateCharm(cache.CharmChange{
+		ModelUUID: "model-uuid",
+		CharmURL:  "cs:no-profile-1",
+	}, s.Manager)
+	ac := appChange
+	ac.Name = "no-profile"
+	ac.CharmURL = "cs:no-profile-1"
+	s.model.UpdateApplication(ac, s.Manager)
+}
+
+func (s *lxdProfileMachinesWatcherSuite) newWatcher(c *gc.C) (*cache.LXDProfileMachinesWatcher, cache.StringsWatcherC) {
+	w, err := s.model.WatchLXDProfileMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	wc := cache.NewStringsWatcherC(c, w)
+	// Sends initial event.
+	wc.AssertOneChange([]string{"0"})
+	return w, wc
+}
+
+func (s *lxdProfileMachinesWatcherSuite) addUnit(c *gc.C, app, name, machineId string) {
+	uc := unitChange
+	uc.Application = app
+	uc.CharmURL = ""
+	uc.Name = name
+	uc.MachineId = machineId
+	s.model.UpdateUnit(uc, s.Manager)
+	_, err := s.model.Unit(name)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *lxdProfileMachinesWatcherSuite) TestStops(c *gc.C) {
+	_, wc := s.newWatcher(c)
+
+	// The worker is the first and only resource (1).
+	resourceId := uint64(1)
+	s.AssertWorkerResource(c, s.model.Resident, resourceId, true)
+	wc.AssertStops()
+	s.AssertWorkerResource(c, s.model.Resident, resourceId, false)
+}
+
+func (s *lxdProfileMachinesWatcherSuite) TestAddUnitWithProfile(c *gc.C) {
+	w, wc := s.newWatcher(c)
+	defer workertest.CleanKill(c, w)
+
+	s.addUnit(c, appChange.Name, "application-name/2", "1")
+	wc.AssertOneChange([]string{"1"})
+
+	// A further unit on an already reported machine is not sent.
+	s.addUnit(c, appChange.Name, "application-name/3", "1")
+	wc.AssertNoChange()
+}
+
+func (s *lxdProfileMachinesWatcherSuite) TestAddUnitNoProfile(c *gc.C) {
+	w, wc := s.newWatcher(c)
+	defer workertest.CleanKill(c, w)
+
+	s.addUnit(c, "no-profile", "no-profile/0", "1")
+	wc.AssertNoChange()
+}
+
+func (s *lxdProfileMachinesWatcherSuite) TestAddMachineNoChange(c *gc.C) {
+	w, wc := s.newWatcher(c)
+	defer workertest.CleanKill(c, w)
+
+	mc := machineChange
+	mc.Id = "2"
+	s.model.UpdateMachine(mc, s.Manager)
+	wc.AssertNoChange()
+}
+
+func (s *lxdProfileMachinesWatcherSuite) TestRemoveReportedMachine(c *gc.C) {
+	w, wc := s.newWatcher(c)
+	defer workertest.CleanKill(c, w)
+
+	err := s.model.RemoveMachine(cache.RemoveMachine{
+		ModelUUID: "model-uuid",
+		Id:        "0",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange([]string{"0"})
+}
+
+func (s *lxdProfileMachinesWatcherSuite) TestRemoveUnreportedMachine(c *gc.C) {
+	w, wc := s.newWatcher(c)
+	defer workertest.CleanKill(c, w)
+
+	err := s.model.RemoveMachine(cache.RemoveMachine{
+		ModelUUID: "model-uuid",
+		Id:        "1",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+}
+
+func (s *lxdProfileMachinesWatcherSuite) TestApplicationCharmGainsProfile(c *gc.C) {
+	s.addUnit(c, "no-profile", "no-profile/0", "1")
+	w, wc := s.newWatcher(c)
+	defer workertest.CleanKill(c, w)
+
+	s.model.UpdateCharm(cache.CharmChange{
+		ModelUUID:  "model-uuid",
+		CharmURL:   "cs:no-profile-2",
+		LXDProfile: charmChange.LXDProfile,
+	}, s.Manager)
+	ac := appChange
+	ac.Name = "no-profile"
+	ac.CharmURL = "cs:no-profile-2"
+	s.model.UpdateApplication(ac, s.Manager)
+	wc.AssertOneChange([]string{"1"})
+}
+
+func (s *lxdProfileMachinesWatcherSuite) TestContainersExcluded(c *gc.C) {
+	w, wc := s.newWatcher(c)
+	defer workertest.CleanKill(c, w)
+
+	s.addUnit(c, appChange.Name, "application-name/2", "1/lxd/0")
+	wc.AssertNoChange()
+}
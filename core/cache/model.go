@@ -250,6 +250,20 @@ func (m *Model) WatchMachines() (*PredicateStringsWatcher, error) {
 	return w, nil
 }
 
+// WatchLXDProfileMachines returns a watcher to notify about the machines
+// in the model that host units of applications whose charms declare an
+// LXD profile. The initial event contains a slice of the ids of such
+// machines. Later events contain machines newly hosting such units and
+// previously notified machines that have been removed. Containers are
+// excluded.
+func (m *Model) WatchLXDProfileMachines() (*LXDProfileMachinesWatcher, error) {
+	compiled, err := m.machineRegexp()
+	if err != nil {
+		return nil, err
+	}
+	return newLXDProfileMachinesWatcher(m, regexpPredicate(compiled)), nil
+}
+
 // updateApplication adds or updates the application in the model.
 func (m *Model) updateApplication(ch ApplicationChange, rm *residentManager) {
 	m.mu.Lock()
@@ -65,6 +65,18 @@ func (u *Unit) CharmURL() string {
 	return u.details.CharmURL
 }
 
+// PublicAddress returns the public address of the unit, or an empty
+// string if it does not yet have one.
+func (u *Unit) PublicAddress() string {
+	return u.details.PublicAddress
+}
+
+// PrivateAddress returns the private address of the unit, or an empty
+// string if it does not yet have one.
+func (u *Unit) PrivateAddress() string {
+	return u.details.PrivateAddress
+}
+
 // Ports returns the exposed ports for the unit.
 func (u *Unit) Ports() []network.Port {
 	return u.details.Ports
@@ -35,6 +35,19 @@ func (s *UnitSuite) TestWatchCharmConfigNewWatcher(c *gc.C) {
 	workertest.CleanKill(c, w)
 }
 
+func (s *UnitSuite) TestAddresses(c *gc.C) {
+	m := s.NewModel(modelChange)
+	ch := unitChange
+	ch.PublicAddress = "10.0.0.1"
+	ch.PrivateAddress = "192.168.0.1"
+	m.UpdateUnit(ch, s.Manager)
+
+	u, err := m.Unit(unitChange.Name)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(u.PublicAddress(), gc.Equals, "10.0.0.1")
+	c.Check(u.PrivateAddress(), gc.Equals, "192.168.0.1")
+}
+
 func (s *UnitSuite) TestConfigSettingsNoBranch(c *gc.C) {
 	m := s.NewModel(modelChange)
 	m.UpdateCharm(charmChange, s.Manager)
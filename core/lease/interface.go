@@ -17,6 +17,11 @@ const (
 	// SingularControllerNamespace is the namespace used to manage
 	// controller leases.
 	SingularControllerNamespace = "singular-controller"
+
+	// ApplicationOperatorNamespace is the namespace used to manage
+	// which of the pods running a CAAS application's operator is
+	// active.
+	ApplicationOperatorNamespace = "application-operator"
 )
 
 // ErrClaimDenied indicates that a Claimer.Claim() has been denied.
@@ -0,0 +1,28 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model
+
+import (
+	"regexp"
+	"strings"
+
+	"gopkg.in/juju/names.v3"
+)
+
+// validModelAlias matches model aliases, which are prefixes of at least
+// 8 of the hex digits of the model's UUID.
+var validModelAlias = regexp.MustCompile("^[0-9a-f]{8,32}$")
+
+// IsValidModelAlias returns whether the given string is a valid model
+// alias.
+func IsValidModelAlias(alias string) bool {
+	return validModelAlias.MatchString(alias)
+}
+
+// IsValidMachineAlias returns whether the given string is a valid machine
+// alias, of the form <model-alias>:<machine-id>.
+func IsValidMachineAlias(alias string) bool {
+	parts := strings.SplitN(alias, ":", 2)
+	return len(parts) == 2 && IsValidModelAlias(parts[0]) && names.IsValidMachine(parts[1])
+}
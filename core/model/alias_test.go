@@ -0,0 +1,50 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model_test
+
+import (
+	"github.com/juju/testing"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/model"
+)
+
+type AliasSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&AliasSuite{})
+
+func (*AliasSuite) TestIsValidModelAlias(c *gc.C) {
+	for _, t := range []struct {
+		alias string
+		valid bool
+	}{
+		{"1a2b3c4d", true},
+		{"1a2b3c4d5", true},
+		{"1a2b3c4", false},
+		{"1A2B3C4D", false},
+		{"1a2b3c4d-5e6f", false},
+		{"mymodel", false},
+	} {
+		c.Check(model.IsValidModelAlias(t.alias), gc.Equals, t.valid, gc.Commentf("%q", t.alias))
+	}
+}
+
+func (*AliasSuite) TestIsValidMachineAlias(c *gc.C) {
+	for _, t := range []struct {
+		alias string
+		valid bool
+	}{
+		{"1a2b3c4d:0", true},
+		{"1a2b3c4d:0/lxd/1", true},
+		{"1a2b3c4d", false},
+		{"1a2b3c4d:", false},
+		{"1a2b3c4d:foo", false},
+		{"mymodel:0", false},
+		{"0", false},
+	} {
+		c.Check(model.IsValidMachineAlias(t.alias), gc.Equals, t.valid, gc.Commentf("%q", t.alias))
+	}
+}
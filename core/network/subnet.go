@@ -121,3 +121,28 @@ func IsValidCidr(cidr string) bool {
 	}
 	return false
 }
+
+// NormaliseCIDR returns cidr with its address in canonical form, so that
+// equivalent IPv6 spellings such as "2001:DB8:0::/32" and "2001:db8::/32"
+// compare equal. Values that are not network CIDRs are returned unchanged
+// for IsValidCidr to reject.
+func NormaliseCIDR(cidr string) string {
+	ip, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil || !ip.Equal(ipNet.IP) {
+		return cidr
+	}
+	return ipNet.String()
+}
+
+// CIDRAddressType returns the address family of the given CIDR,
+// either IPv4Address or IPv6Address.
+func CIDRAddressType(cidr string) (AddressType, error) {
+	ip, _, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if ip.To4() != nil {
+		return IPv4Address, nil
+	}
+	return IPv6Address, nil
+}
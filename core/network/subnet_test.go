@@ -0,0 +1,59 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package network_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/testing"
+)
+
+type SubnetSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&SubnetSuite{})
+
+func (s *SubnetSuite) TestIsValidCidr(c *gc.C) {
+	c.Check(network.IsValidCidr("10.0.0.0/24"), jc.IsTrue)
+	c.Check(network.IsValidCidr("2001:db8::/32"), jc.IsTrue)
+	c.Check(network.IsValidCidr("2001:DB8::/32"), jc.IsFalse)
+	c.Check(network.IsValidCidr("10.0.0.5/24"), jc.IsFalse)
+	c.Check(network.IsValidCidr("bad"), jc.IsFalse)
+}
+
+func (s *SubnetSuite) TestNormaliseCIDR(c *gc.C) {
+	for in, out := range map[string]string{
+		"10.0.0.0/24":       "10.0.0.0/24",
+		"2001:db8::/32":     "2001:db8::/32",
+		"2001:DB8:0:0::/32": "2001:db8::/32",
+		"fd00:0:0:1:0::/64": "fd00:0:0:1::/64",
+		"10.0.0.5/24":       "10.0.0.5/24",
+		"2001:db8::1/64":    "2001:db8::1/64",
+		"not-a-cidr":        "not-a-cidr",
+		"":                  "",
+	} {
+		c.Check(network.NormaliseCIDR(in), gc.Equals, out, gc.Commentf("input %q", in))
+	}
+	c.Check(network.IsValidCidr(network.NormaliseCIDR("2001:DB8::/32")), jc.IsTrue)
+}
+
+func (s *SubnetSuite) TestCIDRAddressType(c *gc.C) {
+	addrType, err := network.CIDRAddressType("10.0.0.0/24")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(addrType, gc.Equals, network.IPv4Address)
+
+	addrType, err = network.CIDRAddressType("2001:db8::/32")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(addrType, gc.Equals, network.IPv6Address)
+
+	addrType, err = network.CIDRAddressType("::/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(addrType, gc.Equals, network.IPv6Address)
+
+	_, err = network.CIDRAddressType("10.0.0.1")
+	c.Assert(err, gc.ErrorMatches, `invalid CIDR address: 10.0.0.1`)
+}
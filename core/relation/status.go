@@ -3,6 +3,8 @@
 
 package relation
 
+import "fmt"
+
 // Status describes the status of a relation.
 type Status string
 
@@ -24,3 +26,100 @@ const (
 	// Error is used to signify that the relation is in an error state.
 	Error Status = "error"
 )
+
+// Reason describes why a relation has the status it has, when that is
+// not simply the progress of its units through the relation.
+type Reason string
+
+const (
+	// ReasonDeparted is used when the application at one end of a
+	// relation has departed, breaking the relation.
+	ReasonDeparted Reason = "departed"
+
+	// ReasonIngressError is used when the ingress networks required by
+	// a cross model relation could not be resolved or were refused by
+	// the offering model.
+	ReasonIngressError Reason = "ingress-error"
+
+	// ReasonSuspended is used when a cross model relation has been
+	// suspended, on either side of the relation.
+	ReasonSuspended Reason = "suspended"
+)
+
+// Keys of the status data that hold a relation's StatusReason.
+const (
+	reasonKey              = "reason"
+	departedApplicationKey = "departed-application"
+	errorKey               = "error"
+	suspendedReasonKey     = "suspended-reason"
+)
+
+// StatusReason holds structured data explaining a relation's status,
+// so that clients can describe why a relation is broken. It is carried
+// in the relation's status data.
+type StatusReason struct {
+	// Reason is the kind of event that gave the relation its status.
+	Reason Reason
+
+	// DepartedApplication is the name of the application that
+	// departed, for ReasonDeparted.
+	DepartedApplication string
+
+	// Error holds the error that was encountered, for
+	// ReasonIngressError.
+	Error string
+
+	// SuspendedReason holds the reason given for suspending the
+	// relation, if any, for ReasonSuspended.
+	SuspendedReason string
+}
+
+// Data returns the status data that records the reason.
+func (r StatusReason) Data() map[string]interface{} {
+	data := map[string]interface{}{
+		reasonKey: string(r.Reason),
+	}
+	if r.DepartedApplication != "" {
+		data[departedApplicationKey] = r.DepartedApplication
+	}
+	if r.Error != "" {
+		data[errorKey] = r.Error
+	}
+	if r.SuspendedReason != "" {
+		data[suspendedReasonKey] = r.SuspendedReason
+	}
+	return data
+}
+
+// ParseStatusReason returns the reason recorded in the given relation
+// status data, and false if there is none.
+func ParseStatusReason(data map[string]interface{}) (StatusReason, bool) {
+	reason, _ := data[reasonKey].(string)
+	if reason == "" {
+		return StatusReason{}, false
+	}
+	result := StatusReason{Reason: Reason(reason)}
+	result.DepartedApplication, _ = data[departedApplicationKey].(string)
+	result.Error, _ = data[errorKey].(string)
+	result.SuspendedReason, _ = data[suspendedReasonKey].(string)
+	return result, true
+}
+
+// String returns a description of the reason, suitable for display.
+func (r StatusReason) String() string {
+	switch r.Reason {
+	case ReasonDeparted:
+		if r.DepartedApplication != "" {
+			return fmt.Sprintf("application %q departed", r.DepartedApplication)
+		}
+		return "remote application departed"
+	case ReasonIngressError:
+		return fmt.Sprintf("ingress networks not resolved: %s", r.Error)
+	case ReasonSuspended:
+		if r.SuspendedReason != "" {
+			return fmt.Sprintf("suspended: %s", r.SuspendedReason)
+		}
+		return "suspended"
+	}
+	return string(r.Reason)
+}
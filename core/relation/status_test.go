@@ -0,0 +1,62 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package relation_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/relation"
+)
+
+type statusReasonSuite struct{}
+
+var _ = gc.Suite(&statusReasonSuite{})
+
+func (s *statusReasonSuite) TestRoundTrip(c *gc.C) {
+	for i, reason := range []relation.StatusReason{{
+		Reason:              relation.ReasonDeparted,
+		DepartedApplication: "mysql",
+	}, {
+		Reason: relation.ReasonIngressError,
+		Error:  "ingress 10.0.0.0/8 not permitted",
+	}, {
+		Reason:          relation.ReasonSuspended,
+		SuspendedReason: "maintenance",
+	}} {
+		c.Logf("test %d", i)
+		parsed, ok := relation.ParseStatusReason(reason.Data())
+		c.Check(ok, jc.IsTrue)
+		c.Check(parsed, jc.DeepEquals, reason)
+	}
+}
+
+func (s *statusReasonSuite) TestParseNoReason(c *gc.C) {
+	_, ok := relation.ParseStatusReason(nil)
+	c.Check(ok, jc.IsFalse)
+	_, ok = relation.ParseStatusReason(map[string]interface{}{"foo": "bar"})
+	c.Check(ok, jc.IsFalse)
+}
+
+func (s *statusReasonSuite) TestString(c *gc.C) {
+	for i, test := range []struct {
+		reason relation.StatusReason
+		expect string
+	}{{
+		reason: relation.StatusReason{Reason: relation.ReasonDeparted, DepartedApplication: "mysql"},
+		expect: `application "mysql" departed`,
+	}, {
+		reason: relation.StatusReason{Reason: relation.ReasonIngressError, Error: "forbidden"},
+		expect: "ingress networks not resolved: forbidden",
+	}, {
+		reason: relation.StatusReason{Reason: relation.ReasonSuspended, SuspendedReason: "maintenance"},
+		expect: "suspended: maintenance",
+	}, {
+		reason: relation.StatusReason{Reason: relation.ReasonSuspended},
+		expect: "suspended",
+	}} {
+		c.Logf("test %d", i)
+		c.Check(test.reason.String(), gc.Equals, test.expect)
+	}
+}
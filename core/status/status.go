@@ -77,6 +77,11 @@ const (
 	// to set the unit to Dead at a suitable moment.
 	Stopped Status = "stopped"
 
+	// Draining is set when:
+	// The machine is dying, and its agent is running the shutdown tasks
+	// that must complete before the machine is set to Dead.
+	Draining Status = "draining"
+
 	// Down is set when:
 	// The machine ought to be signalling activity, but it cannot be
 	// detected.
@@ -0,0 +1,145 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package tracing provides the glue for tracing API requests across
+// the api client and the apiserver. Spans are created with OpenCensus,
+// whose span model and W3C trace context propagation are compatible
+// with OpenTelemetry.
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/juju/loggo"
+	"go.opencensus.io/trace"
+)
+
+var logger = loggo.GetLogger("juju.tracing")
+
+const (
+	// traceParentVersion is the only version of the W3C traceparent
+	// format that is understood.
+	traceParentVersion = "00"
+
+	// sampledFlag is the traceparent flag marking a span as sampled.
+	sampledFlag = 0x01
+)
+
+// FormatTraceParent returns the W3C traceparent representation of the
+// given span context, as propagated in the metadata of RPC requests.
+func FormatTraceParent(sc trace.SpanContext) string {
+	var flags byte
+	if sc.IsSampled() {
+		flags |= sampledFlag
+	}
+	return fmt.Sprintf("%s-%s-%s-%02x",
+		traceParentVersion,
+		hex.EncodeToString(sc.TraceID[:]),
+		hex.EncodeToString(sc.SpanID[:]),
+		flags,
+	)
+}
+
+// ParseTraceParent parses a W3C traceparent, as formatted by
+// FormatTraceParent. It returns false if the traceparent is empty
+// or invalid.
+func ParseTraceParent(traceParent string) (trace.SpanContext, bool) {
+	var sc trace.SpanContext
+	parts := strings.Split(traceParent, "-")
+	if len(parts) != 4 || parts[0] != traceParentVersion {
+		return sc, false
+	}
+	traceID, err := hex.DecodeString(parts[1])
+	if err != nil || len(traceID) != len(sc.TraceID) {
+		return sc, false
+	}
+	spanID, err := hex.DecodeString(parts[2])
+	if err != nil || len(spanID) != len(sc.SpanID) {
+		return sc, false
+	}
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil || len(flags) != 1 {
+		return sc, false
+	}
+	copy(sc.TraceID[:], traceID)
+	copy(sc.SpanID[:], spanID)
+	if sc.TraceID == (trace.TraceID{}) || sc.SpanID == (trace.SpanID{}) {
+		return trace.SpanContext{}, false
+	}
+	if flags[0]&sampledFlag != 0 {
+		sc.TraceOptions = 1
+	}
+	return sc, true
+}
+
+// TraceParentFromContext returns the W3C traceparent of the span in the
+// given context, or the empty string if there is none.
+func TraceParentFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	span := trace.FromContext(ctx)
+	if span == nil {
+		return ""
+	}
+	return FormatTraceParent(span.SpanContext())
+}
+
+// SetSampleRate sets the fraction of requests, between 0 and 1, that
+// are traced when their caller hasn't already decided whether they
+// should be. Requests whose callers are tracing them are always traced.
+func SetSampleRate(rate float64) {
+	trace.ApplyConfig(trace.Config{
+		DefaultSampler: trace.ProbabilitySampler(rate),
+	})
+}
+
+var registerOnce sync.Once
+
+// RegisterLogExporter arranges for sampled spans to be written to the
+// "juju.tracing" logger at DEBUG level, so that the spans of a request
+// can be found in the logs of the client and of the controller by
+// their trace ID. It is safe to call more than once.
+func RegisterLogExporter() {
+	registerOnce.Do(func() {
+		trace.RegisterExporter(logExporter{})
+	})
+}
+
+// logExporter implements trace.Exporter by logging spans.
+type logExporter struct{}
+
+// ExportSpan is part of the trace.Exporter interface.
+func (logExporter) ExportSpan(s *trace.SpanData) {
+	if !logger.IsDebugEnabled() {
+		return
+	}
+	logger.Debugf("%s", FormatSpan(s))
+}
+
+// FormatSpan returns a single line describing the given span.
+func FormatSpan(s *trace.SpanData) string {
+	var parent string
+	if s.ParentSpanID != (trace.SpanID{}) {
+		parent = s.ParentSpanID.String()
+	}
+	line := fmt.Sprintf("span %q trace=%s span=%s parent=%s duration=%v",
+		s.Name, s.TraceID, s.SpanID, parent, s.EndTime.Sub(s.StartTime))
+	if s.Status.Code != trace.StatusCodeOK {
+		line += fmt.Sprintf(" status=%d %q", s.Status.Code, s.Status.Message)
+	}
+	keys := make([]string, 0, len(s.Attributes))
+	for key := range s.Attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		line += fmt.Sprintf(" %s=%v", key, s.Attributes[key])
+	}
+	return line
+}
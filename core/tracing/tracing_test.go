@@ -0,0 +1,97 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package tracing_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"go.opencensus.io/trace"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/tracing"
+)
+
+type tracingSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&tracingSuite{})
+
+var spanContext = trace.SpanContext{
+	TraceID:      trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+	SpanID:       trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+	TraceOptions: 1,
+}
+
+func (*tracingSuite) TestFormatTraceParent(c *gc.C) {
+	c.Assert(tracing.FormatTraceParent(spanContext), gc.Equals,
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	unsampled := spanContext
+	unsampled.TraceOptions = 0
+	c.Assert(tracing.FormatTraceParent(unsampled), gc.Equals,
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+}
+
+func (*tracingSuite) TestParseTraceParent(c *gc.C) {
+	sc, ok := tracing.ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(sc, jc.DeepEquals, spanContext)
+	c.Assert(sc.IsSampled(), jc.IsTrue)
+
+	sc, ok = tracing.ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(sc.IsSampled(), jc.IsFalse)
+}
+
+func (*tracingSuite) TestParseTraceParentInvalid(c *gc.C) {
+	for i, traceParent := range []string{
+		"",
+		"garbage",
+		"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e47-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-1",
+		"00-xbf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+	} {
+		c.Logf("test %d: %q", i, traceParent)
+		_, ok := tracing.ParseTraceParent(traceParent)
+		c.Check(ok, jc.IsFalse)
+	}
+}
+
+func (*tracingSuite) TestTraceParentFromContext(c *gc.C) {
+	c.Assert(tracing.TraceParentFromContext(context.Background()), gc.Equals, "")
+
+	ctx, span := trace.StartSpan(context.Background(), "test", trace.WithSampler(trace.AlwaysSample()))
+	defer span.End()
+	traceParent := tracing.TraceParentFromContext(ctx)
+	sc, ok := tracing.ParseTraceParent(traceParent)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(sc, jc.DeepEquals, span.SpanContext())
+}
+
+func (*tracingSuite) TestFormatSpan(c *gc.C) {
+	start := time.Date(2019, 5, 1, 12, 0, 0, 0, time.UTC)
+	line := tracing.FormatSpan(&trace.SpanData{
+		SpanContext:  spanContext,
+		ParentSpanID: trace.SpanID{0, 0, 0, 0, 0, 0, 0, 1},
+		Name:         "Client.FullStatus",
+		StartTime:    start,
+		EndTime:      start.Add(1500 * time.Millisecond),
+		Attributes: map[string]interface{}{
+			"version": int64(2),
+			"model":   "deadbeef",
+		},
+		Status: trace.Status{Code: trace.StatusCodeUnknown, Message: "boom"},
+	})
+	c.Assert(line, gc.Equals, `span "Client.FullStatus" `+
+		`trace=4bf92f3577b34da6a3ce929d0e0e4736 span=00f067aa0ba902b7 parent=0000000000000001 `+
+		`duration=1.5s status=2 "boom" model=deadbeef version=2`)
+}
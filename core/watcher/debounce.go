@@ -0,0 +1,92 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package watcher
+
+import (
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"gopkg.in/juju/worker.v1"
+	"gopkg.in/juju/worker.v1/catacomb"
+)
+
+// DebouncedNotifyWatcher implements NotifyWatcher, coalescing the events
+// of another NotifyWatcher that arrive close together.
+type DebouncedNotifyWatcher struct {
+	catacomb catacomb.Catacomb
+	source   NotifyWatcher
+	clock    clock.Clock
+	delay    time.Duration
+	changes  chan struct{}
+}
+
+// NewDebouncedNotifyWatcher returns a NotifyWatcher that sends the initial
+// event of the source watcher straight away. Each subsequent event starts
+// a delay, and it and any others that arrive during the delay, or while
+// the event is waiting to be received, are sent as one event. The source
+// watcher is stopped when the returned watcher is.
+func NewDebouncedNotifyWatcher(source NotifyWatcher, clock clock.Clock, delay time.Duration) (*DebouncedNotifyWatcher, error) {
+	w := &DebouncedNotifyWatcher{
+		source:  source,
+		clock:   clock,
+		delay:   delay,
+		changes: make(chan struct{}),
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+		Init: []worker.Worker{source},
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+func (w *DebouncedNotifyWatcher) loop() error {
+	defer close(w.changes)
+	var (
+		initial = true
+		out     chan<- struct{}
+		timer   <-chan time.Time
+	)
+	in := w.source.Changes()
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case _, ok := <-in:
+			if !ok {
+				return errors.New("change channel closed")
+			}
+			if initial {
+				initial = false
+				out = w.changes
+			} else if out == nil && timer == nil {
+				timer = w.clock.After(w.delay)
+			}
+		case <-timer:
+			timer = nil
+			out = w.changes
+		case out <- struct{}{}:
+			out = nil
+		}
+	}
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *DebouncedNotifyWatcher) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *DebouncedNotifyWatcher) Wait() error {
+	return w.catacomb.Wait()
+}
+
+// Changes is part of the NotifyWatcher interface.
+func (w *DebouncedNotifyWatcher) Changes() NotifyChannel {
+	return w.changes
+}
@@ -0,0 +1,60 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package watcher_test
+
+import (
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/watcher"
+	"github.com/juju/juju/core/watcher/watchertest"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type debouncedNotifyWatcherSuite struct{}
+
+var _ = gc.Suite(&debouncedNotifyWatcherSuite{})
+
+func (*debouncedNotifyWatcherSuite) TestCoalescesEvents(c *gc.C) {
+	ch := make(chan struct{})
+	source := watchertest.NewMockNotifyWatcher(ch)
+	clock := testclock.NewClock(time.Time{})
+	w, err := watcher.NewDebouncedNotifyWatcher(source, clock, time.Second)
+	c.Assert(err, jc.ErrorIsNil)
+	wc := watchertest.NewNotifyWatcherC(c, w, nil)
+	defer wc.AssertKilled()
+
+	// The initial event is sent straight away.
+	ch <- struct{}{}
+	wc.AssertOneChange()
+
+	// Later events are sent as one, after the delay.
+	ch <- struct{}{}
+	ch <- struct{}{}
+	ch <- struct{}{}
+	wc.AssertNoChange()
+	c.Assert(clock.WaitAdvance(time.Second, coretesting.LongWait, 1), jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
+
+func (*debouncedNotifyWatcherSuite) TestStopsSource(c *gc.C) {
+	source := watchertest.NewMockNotifyWatcher(make(chan struct{}))
+	w, err := watcher.NewDebouncedNotifyWatcher(source, testclock.NewClock(time.Time{}), time.Second)
+	c.Assert(err, jc.ErrorIsNil)
+	wc := watchertest.NewNotifyWatcherC(c, w, nil)
+	wc.AssertKilled()
+	c.Assert(source.Wait(), jc.ErrorIsNil)
+}
+
+func (*debouncedNotifyWatcherSuite) TestSourceError(c *gc.C) {
+	source := watchertest.NewMockNotifyWatcher(make(chan struct{}))
+	w, err := watcher.NewDebouncedNotifyWatcher(source, testclock.NewClock(time.Time{}), time.Second)
+	c.Assert(err, jc.ErrorIsNil)
+	source.KillErr(errors.New("boom"))
+	c.Assert(w.Wait(), gc.ErrorMatches, "boom")
+}
@@ -102,6 +102,11 @@ type StartInstanceParams struct {
 	// the LXD container, if specified and an LXD container.  The profiles
 	// come from charms deployed on the machine.
 	CharmLXDProfiles []string
+
+	// PublishNetworkBoot, if non-nil, may be called by providers that
+	// provision machines by network boot to publish the instance's
+	// rendered user-data through the controller.
+	PublishNetworkBoot NetworkBootPublishFunc
 }
 
 // StartInstanceResult holds the result of an
@@ -172,17 +177,46 @@ type InstanceBroker interface {
 	MaintainInstance(ctx context.ProviderCallContext, args StartInstanceParams) error
 }
 
+// MutaterBroker defines an interface for applying the charm lxd profiles,
+// through which charms declare their host-level requirements, to the
+// instances of a particular virtualisation type. It is used by the
+// instance mutater worker.
+type MutaterBroker interface {
+	// AssignLXDProfiles assigns the given profile names to the instance
+	// provided.  The slice of ProfilePosts provides details for adding to
+	// and removing profiles from the instance's host.
+	AssignLXDProfiles(instId string, profilesNames []string, profilePosts []lxdprofile.ProfilePost) ([]string, error)
+
+	// LXDProfileNames returns all the profiles associated to an instance.
+	LXDProfileNames(containerName string) ([]string, error)
+}
+
 // LXDProfiler defines an interface for dealing with lxd profiles used to
 // deploy juju machines and containers.
 type LXDProfiler interface {
-	// AssignLXDProfiles assigns the given profile names to the lxd instance
-	// provided.  The slice of ProfilePosts provides details for adding to
-	// and removing profiles from the lxd server.
-	AssignLXDProfiles(instId string, profilesNames []string, profilePosts []lxdprofile.ProfilePost) ([]string, error)
+	MutaterBroker
 
 	// MaybeWriteLXDProfile, write given LXDProfile to if not already there.
 	MaybeWriteLXDProfile(pName string, put *charm.LXDProfile) error
+}
 
-	// LXDProfileNames returns all the profiles associated to a container name
-	LXDProfileNames(containerName string) ([]string, error)
+// LXDProfileCleaner defines an interface for removing the charm lxd
+// profiles which are no longer used by any machine or container. It is
+// optionally implemented by an LXDProfiler.
+type LXDProfileCleaner interface {
+	// UnusedLXDProfileNames returns the names of the charm lxd profiles
+	// on the lxd server which are not used by any instance.
+	UnusedLXDProfileNames() ([]string, error)
+
+	// DeleteLXDProfile removes the named profile from the lxd server.
+	DeleteLXDProfile(name string) error
+}
+
+// LXDProfileValidator defines an interface for checking, before a charm
+// lxd profile is written, that the instance's host is able to apply it.
+// It is optionally implemented by a MutaterBroker.
+type LXDProfileValidator interface {
+	// ValidateLXDProfile returns a not supported error if the host
+	// cannot apply the profile.
+	ValidateLXDProfile(profile lxdprofile.Profile) error
 }
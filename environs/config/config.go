@@ -4,9 +4,13 @@
 package config
 
 import (
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"net"
 	"os"
+	"path"
+	"regexp"
 	"strings"
 	"time"
 
@@ -173,7 +177,8 @@ const (
 	// LogForwardEnabled determines whether the log forward functionality is enabled.
 	LogForwardEnabled = "logforward-enabled"
 
-	// LogFwdSyslogHost sets the hostname:port of the syslog server.
+	// LogFwdSyslogHost sets the hostname:port of the syslog server, or
+	// the https URL to which log records are posted.
 	LogFwdSyslogHost = "syslog-host"
 
 	// LogFwdSyslogCACert sets the certificate of the CA that signed the syslog
@@ -239,6 +244,78 @@ const (
 	// list will be comma separated.
 	ContainerInheritPropertiesKey = "container-inherit-properties"
 
+	// HookSandboxKey is the key for whether the hooks of charms deployed
+	// into the model are run in a sandbox.
+	HookSandboxKey = "hook-sandbox"
+
+	// HookSandboxUserKey is the key for the user that sandboxed hooks
+	// are run as.
+	HookSandboxUserKey = "hook-sandbox-user"
+
+	// HookSandboxProfileKey is the key for the AppArmor profile that
+	// sandboxed hooks are run under.
+	HookSandboxProfileKey = "hook-sandbox-profile"
+
+	// LXDRequiredProfilesKey is the key to specify a list of lxd profiles,
+	// in addition to the default and model profiles, that are applied to
+	// all of the lxd containers that Juju manages in the model. The list
+	// will be comma separated.
+	LXDRequiredProfilesKey = "lxd-required-profiles"
+
+	// MachineAddressExclusionsKey is the key to specify a list of CIDRs,
+	// and globs matching network interface names, whose addresses machine
+	// agents do not report. The list will be comma separated.
+	MachineAddressExclusionsKey = "machine-address-exclusions"
+
+	// ContainerBridgePrefixKey is the key to specify the prefix of the
+	// names of the bridges created on hosts for containers. If empty,
+	// "br-" is used, or "b-" for long device names.
+	ContainerBridgePrefixKey = "container-bridge-prefix"
+
+	// ContainerBridgeMTUKey is the key to specify the MTU of the bridges
+	// created on hosts for containers. If zero, the bridge takes the MTU
+	// of the device it bridges.
+	ContainerBridgeMTUKey = "container-bridge-mtu"
+
+	// ContainerBridgeInterfacesKey is the key to specify a list of globs
+	// matching the names of the host network interfaces that may be
+	// bridged for containers. If empty, any interface may be bridged.
+	// The list will be comma separated.
+	ContainerBridgeInterfacesKey = "container-bridge-interfaces"
+
+	// ContainerBridgeOpenvSwitchKey is the key to specify whether the
+	// bridges created on hosts for containers are Open vSwitch bridges.
+	ContainerBridgeOpenvSwitchKey = "container-bridge-openvswitch"
+
+	// CATrustBundleKey is the key to specify a bundle of PEM encoded CA
+	// certificates that agents in the model add to the trust store of
+	// the machines they run on. To rotate a CA, set a bundle holding
+	// both the old and new certificates, then one holding only the new.
+	CATrustBundleKey = "ca-trust-bundle"
+
+	// OperatorUpgradeConcurrencyKey is the key to specify how many
+	// application operators in a Kubernetes model are upgraded at once
+	// when the model's agent version changes. If zero, the default of
+	// DefaultOperatorUpgradeConcurrency is used.
+	OperatorUpgradeConcurrencyKey = "operator-upgrade-concurrency"
+
+	// MachineEnrollmentKey is the key to specify how new machine agents
+	// in the model prove their identity to the controller the first
+	// time they connect. It is one of MachineEnrollmentPassword or
+	// MachineEnrollmentInstanceIdentity.
+	MachineEnrollmentKey = "machine-enrollment"
+
+	// AnnotationTagPrefixKey is the key to specify the prefix of the
+	// keys of machine and unit annotations that are mirrored onto the
+	// provider tags of machine instances. If empty, no annotations
+	// are mirrored.
+	AnnotationTagPrefixKey = "annotation-tag-prefix"
+
+	// AllowFanSubnetOverlapKey is the key to specify whether a space
+	// may be created with a FAN overlay subnet that overlaps its own
+	// underlay subnet in another space, or the other way around.
+	AllowFanSubnetOverlapKey = "allow-fan-subnet-overlap"
+
 	//
 	// Deprecated Settings Attributes
 	//
@@ -409,6 +486,25 @@ const (
 	DefaultActionResultsAge = "336h" // 2 weeks
 
 	DefaultActionResultsSize = "5G"
+
+	// DefaultOperatorUpgradeConcurrency is the default value for
+	// OperatorUpgradeConcurrency.
+	DefaultOperatorUpgradeConcurrency = 5
+)
+
+const (
+	// MachineEnrollmentPassword has the provisioner generate a
+	// password for each new machine and pass it to the machine agent
+	// through the instance's user data.
+	MachineEnrollmentPassword = "password"
+
+	// MachineEnrollmentInstanceIdentity has new machine agents present
+	// the signed identity document issued to their instance by the
+	// cloud, which the controller verifies before the agent chooses its
+	// own password. No password is passed through user data. Machines
+	// that cannot obtain such a document, such as containers and
+	// controllers, still use a password.
+	MachineEnrollmentInstanceIdentity = "instance-identity"
 )
 
 var defaultConfigValues = map[string]interface{}{
@@ -455,6 +551,20 @@ var defaultConfigValues = map[string]interface{}{
 	CloudInitUserDataKey:          "",
 	ContainerInheritPropertiesKey: "",
 	BackupDirKey:                  "",
+	HookSandboxKey:                false,
+	HookSandboxUserKey:            "",
+	HookSandboxProfileKey:         "",
+	LXDRequiredProfilesKey:        "",
+	MachineAddressExclusionsKey:   "",
+	ContainerBridgePrefixKey:      "",
+	ContainerBridgeMTUKey:         0,
+	ContainerBridgeInterfacesKey:  "",
+	ContainerBridgeOpenvSwitchKey: false,
+	CATrustBundleKey:              "",
+	OperatorUpgradeConcurrencyKey: 0,
+	MachineEnrollmentKey:          MachineEnrollmentPassword,
+	AnnotationTagPrefixKey:        "",
+	AllowFanSubnetOverlapKey:      false,
 
 	// Image and agent streams and URLs.
 	"image-stream":               "released",
@@ -727,6 +837,63 @@ func Validate(cfg, old *Config) error {
 		}
 	}
 
+	if v, ok := cfg.defined[HookSandboxUserKey].(string); ok && v != "" {
+		if !validHookSandboxUser.MatchString(v) {
+			return errors.NotValidf("%s %q", HookSandboxUserKey, v)
+		}
+	}
+	if v, ok := cfg.defined[HookSandboxProfileKey].(string); ok && v != "" {
+		if !validHookSandboxProfile.MatchString(v) {
+			return errors.NotValidf("%s %q", HookSandboxProfileKey, v)
+		}
+	}
+	if v, ok := cfg.defined[LXDRequiredProfilesKey].(string); ok && v != "" {
+		for _, name := range splitList(v) {
+			if !validLXDProfileName.MatchString(name) {
+				return errors.NotValidf("%s profile name %q", LXDRequiredProfilesKey, name)
+			}
+			if strings.HasPrefix(name, "juju-") {
+				return errors.Errorf("%s: profile name %q uses the reserved juju- prefix", LXDRequiredProfilesKey, name)
+			}
+		}
+	}
+	if v, ok := cfg.defined[MachineAddressExclusionsKey].(string); ok && v != "" {
+		for _, exclusion := range splitList(v) {
+			if strings.Contains(exclusion, "/") {
+				if _, _, err := net.ParseCIDR(exclusion); err != nil {
+					return errors.NotValidf("%s CIDR %q", MachineAddressExclusionsKey, exclusion)
+				}
+			} else if _, err := path.Match(exclusion, ""); err != nil {
+				return errors.NotValidf("%s interface name pattern %q", MachineAddressExclusionsKey, exclusion)
+			}
+		}
+	}
+	if v, ok := cfg.defined[ContainerBridgePrefixKey].(string); ok && v != "" {
+		if !validBridgePrefix.MatchString(v) {
+			return errors.NotValidf("%s %q", ContainerBridgePrefixKey, v)
+		}
+	}
+	if v, ok := cfg.defined[ContainerBridgeMTUKey].(int); ok && v != 0 {
+		if v < minBridgeMTU || v > maxBridgeMTU {
+			return errors.NotValidf("%s %d (must be between %d and %d, or 0)", ContainerBridgeMTUKey, v, minBridgeMTU, maxBridgeMTU)
+		}
+	}
+	if v, ok := cfg.defined[ContainerBridgeInterfacesKey].(string); ok && v != "" {
+		for _, pattern := range splitList(v) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return errors.NotValidf("%s interface name pattern %q", ContainerBridgeInterfacesKey, pattern)
+			}
+		}
+	}
+	if v, ok := cfg.defined[CATrustBundleKey].(string); ok && v != "" {
+		if err := validateCATrustBundle(v); err != nil {
+			return errors.Annotatef(err, "invalid %s", CATrustBundleKey)
+		}
+	}
+	if v, ok := cfg.defined[OperatorUpgradeConcurrencyKey].(int); ok && v < 0 {
+		return errors.NotValidf("negative %s %d", OperatorUpgradeConcurrencyKey, v)
+	}
+
 	// Check the immutable config values.  These can't change
 	if old != nil {
 		for _, attr := range immutableAttributes {
@@ -1176,6 +1343,133 @@ func (c *Config) AutomaticallyRetryHooks() bool {
 	}
 }
 
+// HookSandbox returns whether the hooks of charms deployed into the model
+// are run in a sandbox. By default they are not. The sandbox confines the
+// files that hooks use with AppArmor; it does not filter their system
+// calls with seccomp.
+func (c *Config) HookSandbox() bool {
+	value, _ := c.defined[HookSandboxKey].(bool)
+	return value
+}
+
+// HookSandboxUser returns the user that sandboxed hooks are run as. If
+// it is empty, hooks are run as the unit agent's user.
+func (c *Config) HookSandboxUser() string {
+	return c.asString(HookSandboxUserKey)
+}
+
+// HookSandboxProfile returns the AppArmor profile, already loaded on the
+// machine, that sandboxed hooks are run under. If it is empty, a profile
+// is generated for each unit from its charm's metadata.
+func (c *Config) HookSandboxProfile() string {
+	return c.asString(HookSandboxProfileKey)
+}
+
+// LXDRequiredProfiles returns the names of the lxd profiles, already
+// created on the lxd hosts, that are applied to all of the lxd containers
+// in the model, in addition to the default and model profiles.
+func (c *Config) LXDRequiredProfiles() []string {
+	return splitList(c.asString(LXDRequiredProfilesKey))
+}
+
+// MachineAddressExclusions returns the CIDRs, and globs matching network
+// interface names, whose addresses machine agents do not report.
+func (c *Config) MachineAddressExclusions() []string {
+	return splitList(c.asString(MachineAddressExclusionsKey))
+}
+
+// ContainerBridgePrefix returns the prefix of the names of the bridges
+// created on hosts for containers, or "" to use the default naming.
+func (c *Config) ContainerBridgePrefix() string {
+	return c.asString(ContainerBridgePrefixKey)
+}
+
+// ContainerBridgeMTU returns the MTU of the bridges created on hosts for
+// containers, or 0 if they take the MTU of the device they bridge.
+func (c *Config) ContainerBridgeMTU() int {
+	value, _ := c.defined[ContainerBridgeMTUKey].(int)
+	return value
+}
+
+// ContainerBridgeInterfaces returns the globs matching the names of the
+// host network interfaces that may be bridged for containers.
+func (c *Config) ContainerBridgeInterfaces() []string {
+	return splitList(c.asString(ContainerBridgeInterfacesKey))
+}
+
+// ContainerBridgeOpenvSwitch reports whether the bridges created on hosts
+// for containers are Open vSwitch bridges.
+func (c *Config) ContainerBridgeOpenvSwitch() bool {
+	value, _ := c.defined[ContainerBridgeOpenvSwitchKey].(bool)
+	return value
+}
+
+// CATrustBundle returns the PEM encoded CA certificates that agents add
+// to the trust store of the machines they run on, or the empty string.
+func (c *Config) CATrustBundle() string {
+	return c.asString(CATrustBundleKey)
+}
+
+// OperatorUpgradeConcurrency returns how many application operators are
+// upgraded at once when the model's agent version changes.
+func (c *Config) OperatorUpgradeConcurrency() int {
+	if value, _ := c.defined[OperatorUpgradeConcurrencyKey].(int); value > 0 {
+		return value
+	}
+	return DefaultOperatorUpgradeConcurrency
+}
+
+// MachineEnrollment returns how new machine agents prove their identity
+// to the controller.
+func (c *Config) MachineEnrollment() string {
+	if value := c.asString(MachineEnrollmentKey); value != "" {
+		return value
+	}
+	return MachineEnrollmentPassword
+}
+
+// AnnotationTagPrefix returns the prefix of the keys of machine and
+// unit annotations that are mirrored onto the provider tags of machine
+// instances, or "" if none are.
+func (c *Config) AnnotationTagPrefix() string {
+	return c.asString(AnnotationTagPrefixKey)
+}
+
+// AllowFanSubnetOverlap reports whether a FAN overlay subnet and its
+// underlay may overlap when they are in different spaces.
+func (c *Config) AllowFanSubnetOverlap() bool {
+	value, _ := c.defined[AllowFanSubnetOverlapKey].(bool)
+	return value
+}
+
+// validateCATrustBundle checks that the bundle holds only PEM encoded
+// certificates, and at least one of them.
+func validateCATrustBundle(bundle string) error {
+	rest := []byte(bundle)
+	count := 0
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return errors.Errorf("unexpected PEM block %q", block.Type)
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return errors.Annotatef(err, "certificate %d", count+1)
+		}
+		count++
+	}
+	if strings.TrimSpace(string(rest)) != "" {
+		return errors.New("trailing data after certificates")
+	}
+	if count == 0 {
+		return errors.New("no certificates found")
+	}
+	return nil
+}
+
 // TransmitVendorMetrics returns whether the controller sends charm-collected metrics
 // in this model for anonymized aggregate analytics. By default this should be true.
 func (c *Config) TransmitVendorMetrics() bool {
@@ -1506,6 +1800,20 @@ var alwaysOptional = schema.Defaults{
 	CloudInitUserDataKey:          schema.Omit,
 	ContainerInheritPropertiesKey: schema.Omit,
 	BackupDirKey:                  schema.Omit,
+	HookSandboxKey:                schema.Omit,
+	HookSandboxUserKey:            schema.Omit,
+	HookSandboxProfileKey:         schema.Omit,
+	LXDRequiredProfilesKey:        schema.Omit,
+	MachineAddressExclusionsKey:   schema.Omit,
+	ContainerBridgePrefixKey:      schema.Omit,
+	ContainerBridgeMTUKey:         schema.Omit,
+	ContainerBridgeInterfacesKey:  schema.Omit,
+	ContainerBridgeOpenvSwitchKey: schema.Omit,
+	CATrustBundleKey:              schema.Omit,
+	OperatorUpgradeConcurrencyKey: schema.Omit,
+	MachineEnrollmentKey:          schema.Omit,
+	AnnotationTagPrefixKey:        schema.Omit,
+	AllowFanSubnetOverlapKey:      schema.Omit,
 }
 
 func allowEmpty(attr string) bool {
@@ -1534,6 +1842,43 @@ func allDefaults() schema.Defaults {
 // immutableAttributes holds those attributes
 // which are not allowed to change in the lifetime
 // of an environment.
+var (
+	// validHookSandboxUser matches the names of users that sandboxed
+	// hooks may be run as.
+	validHookSandboxUser = regexp.MustCompile(`^[a-z_][a-z0-9_-]*$`)
+
+	// validHookSandboxProfile matches the names of AppArmor profiles
+	// that sandboxed hooks may be run under.
+	validHookSandboxProfile = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+	// validLXDProfileName matches the names of lxd profiles that may
+	// be required on all containers.
+	validLXDProfileName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+	// validBridgePrefix matches the prefixes that may be given to the
+	// names of container bridges. They are short enough that a prefix
+	// and a hash of the device name fit in a network interface name.
+	validBridgePrefix = regexp.MustCompile(`^[a-z][a-z0-9-]{0,7}$`)
+)
+
+// minBridgeMTU and maxBridgeMTU bound the MTU of container bridges.
+const (
+	minBridgeMTU = 576
+	maxBridgeMTU = 9216
+)
+
+// splitList returns the non-empty elements of the comma separated list,
+// with surrounding whitespace removed.
+func splitList(list string) []string {
+	var result []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
 var immutableAttributes = []string{
 	NameKey,
 	TypeKey,
@@ -1885,7 +2230,7 @@ global or per instance security groups.`,
 		Group:       environschema.EnvironGroup,
 	},
 	LogFwdSyslogHost: {
-		Description: `The hostname:port of the syslog server.`,
+		Description: `The hostname:port of the syslog server, or an https:// URL to which log records are posted.`,
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
@@ -2009,4 +2354,75 @@ data of the store. (default false)`,
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	HookSandboxKey: {
+		Description: "Whether charm hooks are run in a sandbox, confined by AppArmor to the paths used by the charm; system calls are not filtered",
+		Type:        environschema.Tbool,
+		Group:       environschema.EnvironGroup,
+	},
+	HookSandboxUserKey: {
+		Description: "The user that sandboxed charm hooks are run as; if empty, the unit agent's user",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	HookSandboxProfileKey: {
+		Description: "The AppArmor profile that sandboxed charm hooks are run under; if empty, one is generated from the charm's metadata",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	LXDRequiredProfilesKey: {
+		Description: "List of lxd profiles, which must already exist on the lxd hosts, to be applied to all lxd containers in this model (comma-separated)",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	MachineAddressExclusionsKey: {
+		Description: "List of CIDRs, and globs matching network interface names, whose addresses are not reported by machine agents (comma-separated)",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	ContainerBridgePrefixKey: {
+		Description: "The prefix of the names of bridges created on hosts for containers; if empty, br- is used, or b- for long device names",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	ContainerBridgeMTUKey: {
+		Description: "The MTU of bridges created on hosts for containers; if 0, a bridge has the MTU of the device it bridges",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	ContainerBridgeInterfacesKey: {
+		Description: "List of globs matching the names of host network interfaces that may be bridged for containers; if empty, any interface may be (comma-separated)",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	ContainerBridgeOpenvSwitchKey: {
+		Description: "Whether bridges created on hosts for containers are Open vSwitch bridges; requires netplan on the hosts",
+		Type:        environschema.Tbool,
+		Group:       environschema.EnvironGroup,
+	},
+	CATrustBundleKey: {
+		Description: "PEM encoded CA certificates that agents add to the trust store of the machines they run on",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	OperatorUpgradeConcurrencyKey: {
+		Description: "How many application operators in a Kubernetes model are upgraded at once when the agent version changes; if 0, 5 are",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	MachineEnrollmentKey: {
+		Description: "How new machine agents prove their identity to the controller: with a provisioning password, or with the signed identity document the cloud issues to their instance",
+		Type:        environschema.Tstring,
+		Values:      []interface{}{MachineEnrollmentPassword, MachineEnrollmentInstanceIdentity},
+		Group:       environschema.EnvironGroup,
+	},
+	AnnotationTagPrefixKey: {
+		Description: "The prefix of the keys of machine and unit annotations that are copied to the provider tags of machine instances; if empty, none are",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	AllowFanSubnetOverlapKey: {
+		Description: "Whether a space may hold a FAN overlay subnet that overlaps its underlay subnet in another space, or the other way around",
+		Type:        environschema.Tbool,
+		Group:       environschema.EnvironGroup,
+	},
 }
@@ -1114,6 +1114,228 @@ func (s *ConfigSuite) TestAutoHookRetryTrueEnv(c *gc.C) {
 	c.Assert(config.AutomaticallyRetryHooks(), gc.Equals, true)
 }
 
+func (s *ConfigSuite) TestHookSandboxDefault(c *gc.C) {
+	config := newTestConfig(c, testing.Attrs{})
+	c.Assert(config.HookSandbox(), jc.IsFalse)
+	c.Assert(config.HookSandboxUser(), gc.Equals, "")
+	c.Assert(config.HookSandboxProfile(), gc.Equals, "")
+}
+
+func (s *ConfigSuite) TestHookSandbox(c *gc.C) {
+	config := newTestConfig(c, testing.Attrs{
+		"hook-sandbox":         true,
+		"hook-sandbox-user":    "juju-hooks",
+		"hook-sandbox-profile": "charm-hooks",
+	})
+	c.Assert(config.HookSandbox(), jc.IsTrue)
+	c.Assert(config.HookSandboxUser(), gc.Equals, "juju-hooks")
+	c.Assert(config.HookSandboxProfile(), gc.Equals, "charm-hooks")
+}
+
+func (s *ConfigSuite) TestHookSandboxInvalid(c *gc.C) {
+	for _, test := range []struct {
+		attrs testing.Attrs
+		err   string
+	}{{
+		attrs: testing.Attrs{"hook-sandbox-user": "root; rm"},
+		err:   `hook-sandbox-user "root; rm" not valid`,
+	}, {
+		attrs: testing.Attrs{"hook-sandbox-profile": "-unconfined"},
+		err:   `hook-sandbox-profile "-unconfined" not valid`,
+	}} {
+		attrs := testing.Attrs{
+			"type": "my-type", "name": "my-name",
+			"uuid": testing.ModelTag.Id(),
+		}.Merge(test.attrs)
+		_, err := config.New(config.UseDefaults, attrs)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *ConfigSuite) TestLXDRequiredProfiles(c *gc.C) {
+	config := newTestConfig(c, testing.Attrs{})
+	c.Assert(config.LXDRequiredProfiles(), gc.HasLen, 0)
+
+	config = newTestConfig(c, testing.Attrs{
+		"lxd-required-profiles": "site-base, site.audit_1,",
+	})
+	c.Assert(config.LXDRequiredProfiles(), jc.DeepEquals, []string{"site-base", "site.audit_1"})
+}
+
+func (s *ConfigSuite) TestLXDRequiredProfilesInvalid(c *gc.C) {
+	for _, test := range []struct {
+		attrs testing.Attrs
+		err   string
+	}{{
+		attrs: testing.Attrs{"lxd-required-profiles": "site-base,site/base"},
+		err:   `lxd-required-profiles profile name "site/base" not valid`,
+	}, {
+		attrs: testing.Attrs{"lxd-required-profiles": "juju-site"},
+		err:   `lxd-required-profiles: profile name "juju-site" uses the reserved juju- prefix`,
+	}} {
+		attrs := testing.Attrs{
+			"type": "my-type", "name": "my-name",
+			"uuid": testing.ModelTag.Id(),
+		}.Merge(test.attrs)
+		_, err := config.New(config.UseDefaults, attrs)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *ConfigSuite) TestMachineAddressExclusions(c *gc.C) {
+	config := newTestConfig(c, testing.Attrs{})
+	c.Assert(config.MachineAddressExclusions(), gc.HasLen, 0)
+
+	config = newTestConfig(c, testing.Attrs{
+		"machine-address-exclusions": "docker0, virbr*, 10.20.0.0/16",
+	})
+	c.Assert(config.MachineAddressExclusions(), jc.DeepEquals, []string{"docker0", "virbr*", "10.20.0.0/16"})
+}
+
+func (s *ConfigSuite) TestMachineAddressExclusionsInvalid(c *gc.C) {
+	for _, test := range []struct {
+		attrs testing.Attrs
+		err   string
+	}{{
+		attrs: testing.Attrs{"machine-address-exclusions": "docker0,10.20.0.0/33"},
+		err:   `machine-address-exclusions CIDR "10.20.0.0/33" not valid`,
+	}, {
+		attrs: testing.Attrs{"machine-address-exclusions": "virbr["},
+		err:   `machine-address-exclusions interface name pattern "virbr\[" not valid`,
+	}} {
+		attrs := testing.Attrs{
+			"type": "my-type", "name": "my-name",
+			"uuid": testing.ModelTag.Id(),
+		}.Merge(test.attrs)
+		_, err := config.New(config.UseDefaults, attrs)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *ConfigSuite) TestContainerBridgePolicy(c *gc.C) {
+	config := newTestConfig(c, testing.Attrs{})
+	c.Assert(config.ContainerBridgePrefix(), gc.Equals, "")
+	c.Assert(config.ContainerBridgeMTU(), gc.Equals, 0)
+	c.Assert(config.ContainerBridgeInterfaces(), gc.HasLen, 0)
+	c.Assert(config.ContainerBridgeOpenvSwitch(), jc.IsFalse)
+
+	config = newTestConfig(c, testing.Attrs{
+		"container-bridge-prefix":      "juju-",
+		"container-bridge-mtu":         9000,
+		"container-bridge-interfaces":  "eno*, bond0",
+		"container-bridge-openvswitch": true,
+	})
+	c.Assert(config.ContainerBridgePrefix(), gc.Equals, "juju-")
+	c.Assert(config.ContainerBridgeMTU(), gc.Equals, 9000)
+	c.Assert(config.ContainerBridgeInterfaces(), jc.DeepEquals, []string{"eno*", "bond0"})
+	c.Assert(config.ContainerBridgeOpenvSwitch(), jc.IsTrue)
+}
+
+func (s *ConfigSuite) TestContainerBridgePolicyInvalid(c *gc.C) {
+	for _, test := range []struct {
+		attrs testing.Attrs
+		err   string
+	}{{
+		attrs: testing.Attrs{"container-bridge-prefix": "Br-"},
+		err:   `container-bridge-prefix "Br-" not valid`,
+	}, {
+		attrs: testing.Attrs{"container-bridge-prefix": "too-long-"},
+		err:   `container-bridge-prefix "too-long-" not valid`,
+	}, {
+		attrs: testing.Attrs{"container-bridge-mtu": 100},
+		err:   `container-bridge-mtu 100 \(must be between 576 and 9216, or 0\) not valid`,
+	}, {
+		attrs: testing.Attrs{"container-bridge-interfaces": "eth["},
+		err:   `container-bridge-interfaces interface name pattern "eth\[" not valid`,
+	}} {
+		attrs := testing.Attrs{
+			"type": "my-type", "name": "my-name",
+			"uuid": testing.ModelTag.Id(),
+		}.Merge(test.attrs)
+		_, err := config.New(config.UseDefaults, attrs)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *ConfigSuite) TestCATrustBundle(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.CATrustBundle(), gc.Equals, "")
+
+	bundle := testing.CACert + testing.OtherCACert
+	cfg = newTestConfig(c, testing.Attrs{"ca-trust-bundle": bundle})
+	c.Assert(cfg.CATrustBundle(), gc.Equals, bundle)
+}
+
+func (s *ConfigSuite) TestCATrustBundleInvalid(c *gc.C) {
+	for _, test := range []struct {
+		bundle string
+		err    string
+	}{{
+		bundle: "not a certificate",
+		err:    `invalid ca-trust-bundle: no certificates found`,
+	}, {
+		bundle: testing.CAKey,
+		err:    `invalid ca-trust-bundle: unexpected PEM block ".*PRIVATE KEY"`,
+	}, {
+		bundle: testing.CACert + "garbage",
+		err:    `invalid ca-trust-bundle: trailing data after certificates`,
+	}} {
+		attrs := testing.Attrs{
+			"type": "my-type", "name": "my-name",
+			"uuid":            testing.ModelTag.Id(),
+			"ca-trust-bundle": test.bundle,
+		}
+		_, err := config.New(config.UseDefaults, attrs)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *ConfigSuite) TestOperatorUpgradeConcurrency(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.OperatorUpgradeConcurrency(), gc.Equals, 5)
+
+	cfg = newTestConfig(c, testing.Attrs{"operator-upgrade-concurrency": 2})
+	c.Assert(cfg.OperatorUpgradeConcurrency(), gc.Equals, 2)
+
+	_, err := config.New(config.UseDefaults, testing.Attrs{
+		"type": "my-type", "name": "my-name",
+		"uuid":                         testing.ModelTag.Id(),
+		"operator-upgrade-concurrency": -1,
+	})
+	c.Assert(err, gc.ErrorMatches, `negative operator-upgrade-concurrency -1 not valid`)
+}
+
+func (s *ConfigSuite) TestMachineEnrollment(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.MachineEnrollment(), gc.Equals, config.MachineEnrollmentPassword)
+
+	cfg = newTestConfig(c, testing.Attrs{"machine-enrollment": "instance-identity"})
+	c.Assert(cfg.MachineEnrollment(), gc.Equals, config.MachineEnrollmentInstanceIdentity)
+
+	_, err := config.New(config.UseDefaults, testing.Attrs{
+		"type": "my-type", "name": "my-name",
+		"uuid":               testing.ModelTag.Id(),
+		"machine-enrollment": "token",
+	})
+	c.Assert(err, gc.ErrorMatches, `machine-enrollment: expected one of \[password instance-identity\], got "token"`)
+}
+
+func (s *ConfigSuite) TestAnnotationTagPrefix(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.AnnotationTagPrefix(), gc.Equals, "")
+
+	cfg = newTestConfig(c, testing.Attrs{"annotation-tag-prefix": "cost-"})
+	c.Assert(cfg.AnnotationTagPrefix(), gc.Equals, "cost-")
+}
+
+func (s *ConfigSuite) TestAllowFanSubnetOverlap(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.AllowFanSubnetOverlap(), jc.IsFalse)
+
+	cfg = newTestConfig(c, testing.Attrs{"allow-fan-subnet-overlap": true})
+	c.Assert(cfg.AllowFanSubnetOverlap(), jc.IsTrue)
+}
+
 func (s *ConfigSuite) TestNoBothProxy(c *gc.C) {
 	config := newTestConfig(c, testing.Attrs{
 		"http-proxy":  "http://user@10.0.0.1",
@@ -0,0 +1,30 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package context
+
+import (
+	stdcontext "context"
+
+	"go.opencensus.io/trace"
+)
+
+// StartSpan starts the span that covers the named call to the cloud.
+// Provider call contexts don't carry the context of the request on whose
+// behalf calls are made, so the span is recorded as a trace of its own.
+func StartSpan(name string) *trace.Span {
+	_, span := trace.StartSpan(stdcontext.Background(), name, trace.WithSpanKind(trace.SpanKindClient))
+	return span
+}
+
+// EndSpan ends the given span, recording the error, if any, that the call
+// it covers failed with.
+func EndSpan(span *trace.Span, err error) {
+	if err != nil {
+		span.SetStatus(trace.Status{
+			Code:    trace.StatusCodeUnknown,
+			Message: err.Error(),
+		})
+	}
+	span.End()
+}
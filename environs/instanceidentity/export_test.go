@@ -0,0 +1,9 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package instanceidentity
+
+var (
+	EC2IdentityURL = &ec2IdentityURL
+	GCEIdentityURL = &gceIdentityURL
+)
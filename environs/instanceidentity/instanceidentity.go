@@ -0,0 +1,105 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package instanceidentity provides the means for a machine agent to
+// obtain the signed identity document that its cloud issues to the
+// instance it runs on. The controller verifies such a document, using
+// the model's environs.InstanceIdentityVerifier, in place of a
+// provisioning password.
+package instanceidentity
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// EC2Document holds an EC2 instance identity document and its
+// base64 encoded RSA-SHA256 signature, as served by the EC2 instance
+// metadata service. An EC2 identity is presented to the controller as
+// the JSON encoding of an EC2Document.
+type EC2Document struct {
+	Document  string `json:"document"`
+	Signature string `json:"signature"`
+}
+
+var (
+	ec2IdentityURL = "http://169.254.169.254/latest/dynamic/instance-identity"
+	gceIdentityURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/identity"
+
+	httpClient = &http.Client{Timeout: 30 * time.Second}
+)
+
+// Fetch returns the signed identity document for the instance the
+// caller is running on, using the metadata service of the cloud with
+// the given provider type. Where the cloud supports it, the document
+// is issued for the given audience.
+func Fetch(providerType, audience string) (string, error) {
+	switch providerType {
+	case "ec2":
+		return fetchEC2()
+	case "gce":
+		return fetchGCE(audience)
+	}
+	return "", errors.NotSupportedf("instance identity documents on %q", providerType)
+}
+
+func fetchEC2() (string, error) {
+	document, err := get(ec2IdentityURL+"/document", nil)
+	if err != nil {
+		return "", errors.Annotate(err, "fetching EC2 identity document")
+	}
+	signature, err := get(ec2IdentityURL+"/signature", nil)
+	if err != nil {
+		return "", errors.Annotate(err, "fetching EC2 identity signature")
+	}
+	data, err := json.Marshal(EC2Document{
+		Document:  document,
+		Signature: signature,
+	})
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return string(data), nil
+}
+
+func fetchGCE(audience string) (string, error) {
+	query := url.Values{
+		"audience": {audience},
+		"format":   {"full"},
+	}
+	token, err := get(gceIdentityURL+"?"+query.Encode(), http.Header{
+		"Metadata-Flavor": {"Google"},
+	})
+	if err != nil {
+		return "", errors.Annotate(err, "fetching GCE identity token")
+	}
+	return token, nil
+}
+
+func get(rawURL string, header http.Header) (string, error) {
+	req, err := http.NewRequest("GET", rawURL, nil)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("metadata service returned %s", resp.Status)
+	}
+	return string(body), nil
+}
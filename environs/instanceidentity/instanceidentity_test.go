@@ -0,0 +1,79 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package instanceidentity_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/instanceidentity"
+	"github.com/juju/juju/testing"
+)
+
+type fetchSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&fetchSuite{})
+
+func (s *fetchSuite) TestFetchEC2(c *gc.C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/document":
+			w.Write([]byte(`{"instanceId":"i-123"}`))
+		case "/signature":
+			w.Write([]byte("c2lnbmF0dXJl"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	s.PatchValue(instanceidentity.EC2IdentityURL, server.URL)
+
+	document, err := instanceidentity.Fetch("ec2", "ignored")
+	c.Assert(err, jc.ErrorIsNil)
+	var doc instanceidentity.EC2Document
+	c.Assert(json.Unmarshal([]byte(document), &doc), jc.ErrorIsNil)
+	c.Assert(doc, jc.DeepEquals, instanceidentity.EC2Document{
+		Document:  `{"instanceId":"i-123"}`,
+		Signature: "c2lnbmF0dXJl",
+	})
+}
+
+func (s *fetchSuite) TestFetchGCE(c *gc.C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			http.Error(w, "missing header", http.StatusForbidden)
+			return
+		}
+		c.Check(r.URL.Query().Get("audience"), gc.Equals, "machine-0:nonce")
+		c.Check(r.URL.Query().Get("format"), gc.Equals, "full")
+		w.Write([]byte("token"))
+	}))
+	defer server.Close()
+	s.PatchValue(instanceidentity.GCEIdentityURL, server.URL)
+
+	document, err := instanceidentity.Fetch("gce", "machine-0:nonce")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(document, gc.Equals, "token")
+}
+
+func (s *fetchSuite) TestFetchError(c *gc.C) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	s.PatchValue(instanceidentity.GCEIdentityURL, server.URL)
+
+	_, err := instanceidentity.Fetch("gce", "audience")
+	c.Assert(err, gc.ErrorMatches, "fetching GCE identity token: metadata service returned 404 Not Found")
+}
+
+func (s *fetchSuite) TestFetchNotSupported(c *gc.C) {
+	_, err := instanceidentity.Fetch("azure", "audience")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
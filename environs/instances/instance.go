@@ -41,3 +41,21 @@ type InstanceFirewaller interface {
 	// address rules for that port range.
 	IngressRules(ctx context.ProviderCallContext, machineId string) ([]network.IngressRule, error)
 }
+
+// InstanceEgressFirewaller is implemented by instances whose provider
+// supports restricting outgoing traffic with egress security group rules.
+type InstanceEgressFirewaller interface {
+	// OpenEgress allows outgoing traffic matching the given rules from
+	// the instance, which should have been started with the given
+	// machine id.
+	OpenEgress(ctx context.ProviderCallContext, machineId string, rules []network.EgressRule) error
+
+	// CloseEgress removes the given egress rules from the instance,
+	// which should have been started with the given machine id.
+	CloseEgress(ctx context.ProviderCallContext, machineId string, rules []network.EgressRule) error
+
+	// EgressRules returns the set of egress rules for the instance,
+	// which should have been applied to the given machine id. The
+	// rules are returned as sorted by network.SortEgressRules().
+	EgressRules(ctx context.ProviderCallContext, machineId string) ([]network.EgressRule, error)
+}
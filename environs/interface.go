@@ -498,6 +498,18 @@ type InstanceTagger interface {
 	TagInstance(ctx context.ProviderCallContext, id instance.Id, tags map[string]string) error
 }
 
+// InstanceIdentityVerifier is an interface that may be implemented by an
+// Environ whose instances can obtain signed identity documents from the
+// cloud's metadata service. It is used to enroll machine agents without
+// handing them a provisioning password.
+type InstanceIdentityVerifier interface {
+	// VerifyInstanceIdentity checks the signature and contents of the
+	// given identity document, and returns the id of the instance it
+	// was issued to. If the cloud supports binding a document to an
+	// audience, the document must have been issued for the one given.
+	VerifyInstanceIdentity(ctx context.ProviderCallContext, document, audience string) (instance.Id, error)
+}
+
 // InstanceTypesFetcher is an interface that allows for instance information from
 // a provider to be obtained.
 type InstanceTypesFetcher interface {
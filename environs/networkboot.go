@@ -0,0 +1,47 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs
+
+import (
+	"strings"
+	"time"
+)
+
+// NetworkBootPublishFunc publishes the rendered user-data for an instance
+// through the controller, returning the cloud-init seed from which it can
+// be fetched. Providers that provision machines by network boot, such as
+// bare-metal providers, can use it to hand the user-data to the booting
+// machine rather than embedding it, and the credentials in it, in the boot
+// image.
+type NetworkBootPublishFunc func(userData []byte) (NetworkBootSeed, error)
+
+// NetworkBootSeed describes a cloud-init nocloud-net seed served by the
+// controller for a machine that is provisioned by network boot. Only the
+// seed's meta-data and user-data are served; no seed ISO or PXE artifacts
+// are, and the provider is responsible for booting the machine.
+//
+// The controller's certificate is signed by the controller's own CA, which
+// cloud-init does not trust, so a seed URL can only be handed to cloud-init
+// directly where that CA has been installed in the boot image. Otherwise
+// the user-data should be fetched with CACert pinned, as the MAAS provider
+// does.
+type NetworkBootSeed struct {
+	// URL is the location of the seed. The seed's meta-data can be
+	// fetched from URL/meta-data, and its user-data can be fetched,
+	// once, from URL/user-data.
+	URL string
+
+	// CACert holds the CA certificate that the controller's certificate
+	// is signed by, with which fetches from the seed can be verified.
+	CACert string
+
+	// Expires is the time after which the seed can no longer be fetched.
+	Expires time.Time
+}
+
+// KernelParameter returns the kernel command line parameter which directs
+// cloud-init to the seed, for inclusion in a PXE or iPXE configuration.
+func (s NetworkBootSeed) KernelParameter() string {
+	return "ds=nocloud-net;s=" + strings.TrimSuffix(s.URL, "/") + "/"
+}
@@ -64,3 +64,13 @@ const MultiCloud = "multi-cloud"
 
 // JujuV3 indicates that new CLI commands and behaviour for v3 should be enabled.
 const JujuV3 = "juju-v3"
+
+// LXDProfileCleanupDryRun tells the instancemutater workers to only report,
+// rather than remove, the charm lxd profiles no longer used by any instance.
+const LXDProfileCleanupDryRun = "lxd-profile-cleanup-dry-run"
+
+// APITracing tells the api client to trace every API call it makes,
+// sending the trace context with each request so that the controller
+// traces its handling of the requests too. The client's spans are
+// written to the "juju.tracing" logger.
+const APITracing = "api-tracing"
@@ -191,6 +191,15 @@ application-config:
     source: default
     type: bool
     value: false
+  kubernetes-operator-storage:
+    description: storage class or pool for the operator's charm storage
+    source: unset
+    type: string
+  kubernetes-provisioning-mode:
+    description: whether the charm runs in an operator pod or alongside the workload
+      in each pod
+    source: unset
+    type: string
   kubernetes-service-annotations:
     description: a space separated set of annotations to add to the service
     source: unset
@@ -73,6 +73,10 @@ type ModelDetails struct {
 	// ModelUUID is the unique ID for the model.
 	ModelUUID string `yaml:"uuid"`
 
+	// ModelAlias is the short alias of the model, which may be used
+	// in place of the model's UUID.
+	ModelAlias string `yaml:"alias,omitempty"`
+
 	// ModelType is the type of model.
 	ModelType model.ModelType `yaml:"type"`
 
@@ -0,0 +1,140 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package https
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/version"
+
+	"github.com/juju/juju/logfwd"
+	"github.com/juju/juju/logfwd/syslog"
+)
+
+// requestTimeout is how long we wait for the remote host to accept
+// a batch of records.
+const requestTimeout = 30 * time.Second
+
+// Doer sends http requests.
+type Doer interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// Client posts log records, as JSON, to a remote https endpoint.
+type Client struct {
+	// URL is the https URL to which records are posted.
+	URL string
+
+	// Doer sends the requests.
+	Doer Doer
+}
+
+// Open returns a client that posts records to the https URL in the
+// config, using the config's CA certificate to verify the remote host
+// and its client certificate to authenticate with it.
+func Open(cfg syslog.RawConfig) (*Client, error) {
+	if !cfg.IsHTTPS() {
+		return nil, errors.NotValidf("https URL %q", cfg.Host)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	tlsCfg, err := cfg.TLSConfig()
+	if err != nil {
+		return nil, errors.Annotate(err, "constructing TLS config")
+	}
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: tlsCfg,
+		},
+		Timeout: requestTimeout,
+	}
+	return &Client{
+		URL:  cfg.Host,
+		Doer: client,
+	}, nil
+}
+
+// Close releases any idle connections to the remote host.
+func (c *Client) Close() error {
+	if client, ok := c.Doer.(*http.Client); ok {
+		if transport, ok := client.Transport.(*http.Transport); ok {
+			transport.CloseIdleConnections()
+		}
+	}
+	return nil
+}
+
+// Send posts the records, in a single request, to the remote host.
+func (c *Client) Send(records []logfwd.Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+	docs := make([]recordDoc, len(records))
+	for i, rec := range records {
+		docs[i] = newRecordDoc(rec)
+	}
+	body, err := json.Marshal(docs)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	req, err := http.NewRequest("POST", c.URL, bytes.NewReader(body))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.Doer.Do(req)
+	if err != nil {
+		return errors.Annotate(err, "posting log records")
+	}
+	defer resp.Body.Close()
+	// Drain the body so that the connection can be reused.
+	io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("posting log records: %s", resp.Status)
+	}
+	return nil
+}
+
+// recordDoc is the JSON representation of a log record.
+type recordDoc struct {
+	ID              int64     `json:"id"`
+	Timestamp       time.Time `json:"timestamp"`
+	Level           string    `json:"level"`
+	ControllerUUID  string    `json:"controller-uuid"`
+	ModelUUID       string    `json:"model-uuid"`
+	Hostname        string    `json:"hostname,omitempty"`
+	Origin          string    `json:"origin,omitempty"`
+	Software        string    `json:"software,omitempty"`
+	SoftwareVersion string    `json:"software-version,omitempty"`
+	Module          string    `json:"module,omitempty"`
+	Location        string    `json:"location,omitempty"`
+	Message         string    `json:"message"`
+}
+
+func newRecordDoc(rec logfwd.Record) recordDoc {
+	doc := recordDoc{
+		ID:             rec.ID,
+		Timestamp:      rec.Timestamp.UTC(),
+		Level:          rec.Level.String(),
+		ControllerUUID: rec.Origin.ControllerUUID,
+		ModelUUID:      rec.Origin.ModelUUID,
+		Hostname:       rec.Origin.Hostname,
+		Origin:         rec.Origin.Name,
+		Software:       rec.Origin.Software.Name,
+		Module:         rec.Location.Module,
+		Location:       rec.Location.String(),
+		Message:        rec.Message,
+	}
+	if rec.Origin.Software.Version != version.Zero {
+		doc.SoftwareVersion = rec.Origin.Software.Version.String()
+	}
+	return doc
+}
@@ -0,0 +1,149 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package https_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/logfwd"
+	"github.com/juju/juju/logfwd/https"
+	"github.com/juju/juju/logfwd/syslog"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ClientSuite struct {
+	testing.IsolationSuite
+
+	doer *stubDoer
+}
+
+var _ = gc.Suite(&ClientSuite{})
+
+func (s *ClientSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.doer = &stubDoer{status: http.StatusOK}
+}
+
+func (s *ClientSuite) TestOpen(c *gc.C) {
+	client, err := https.Open(syslog.RawConfig{
+		Enabled:    true,
+		Host:       "https://logs.example.com/juju",
+		CACert:     coretesting.CACert,
+		ClientCert: coretesting.ServerCert,
+		ClientKey:  coretesting.ServerKey,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(client.URL, gc.Equals, "https://logs.example.com/juju")
+
+	httpClient, ok := client.Doer.(*http.Client)
+	c.Assert(ok, jc.IsTrue)
+	tlsConfig := httpClient.Transport.(*http.Transport).TLSClientConfig
+	c.Check(tlsConfig.Certificates, gc.HasLen, 1)
+	c.Check(tlsConfig.RootCAs, gc.NotNil)
+}
+
+func (s *ClientSuite) TestOpenNotHTTPS(c *gc.C) {
+	_, err := https.Open(syslog.RawConfig{
+		Enabled:    true,
+		Host:       "a.b.c:9876",
+		CACert:     coretesting.CACert,
+		ClientCert: coretesting.ServerCert,
+		ClientKey:  coretesting.ServerKey,
+	})
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *ClientSuite) TestSend(c *gc.C) {
+	client := &https.Client{URL: "https://logs.example.com/juju", Doer: s.doer}
+	origin := logfwd.Origin{
+		ControllerUUID: "9f484882-2f18-4fd2-967d-db9663db7bea",
+		ModelUUID:      "deadbeef-2f18-4fd2-967d-db9663db7bea",
+		Hostname:       "machine-99.deadbeef-2f18-4fd2-967d-db9663db7bea",
+		Type:           logfwd.OriginTypeMachine,
+		Name:           "99",
+		Software: logfwd.Software{
+			PrivateEnterpriseNumber: 28978,
+			Name:                    "jujud-machine-agent",
+			Version:                 version.MustParse("1.2.3"),
+		},
+	}
+	err := client.Send([]logfwd.Record{{
+		ID:        10,
+		Origin:    origin,
+		Timestamp: time.Date(2099, 6, 1, 23, 2, 1, 23, time.UTC),
+		Level:     loggo.ERROR,
+		Location: logfwd.SourceLocation{
+			Module:   "juju.x.y",
+			Filename: "x/y/spam.go",
+			Line:     42,
+		},
+		Message: "(╯°□°)╯︵ ┻━┻",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(s.doer.requests, gc.HasLen, 1)
+	req := s.doer.requests[0]
+	c.Check(req.Method, gc.Equals, "POST")
+	c.Check(req.URL.String(), gc.Equals, "https://logs.example.com/juju")
+	c.Check(req.Header.Get("Content-Type"), gc.Equals, "application/json")
+	c.Check(s.doer.bodies[0], jc.JSONEquals, []map[string]interface{}{{
+		"id":               10,
+		"timestamp":        "2099-06-01T23:02:01.000000023Z",
+		"level":            "ERROR",
+		"controller-uuid":  "9f484882-2f18-4fd2-967d-db9663db7bea",
+		"model-uuid":       "deadbeef-2f18-4fd2-967d-db9663db7bea",
+		"hostname":         "machine-99.deadbeef-2f18-4fd2-967d-db9663db7bea",
+		"origin":           "99",
+		"software":         "jujud-machine-agent",
+		"software-version": "1.2.3",
+		"module":           "juju.x.y",
+		"location":         "x/y/spam.go:42",
+		"message":          "(╯°□°)╯︵ ┻━┻",
+	}})
+}
+
+func (s *ClientSuite) TestSendEmpty(c *gc.C) {
+	client := &https.Client{URL: "https://logs.example.com/juju", Doer: s.doer}
+	err := client.Send(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.doer.requests, gc.HasLen, 0)
+}
+
+func (s *ClientSuite) TestSendRejected(c *gc.C) {
+	s.doer.status = http.StatusServiceUnavailable
+	client := &https.Client{URL: "https://logs.example.com/juju", Doer: s.doer}
+	err := client.Send([]logfwd.Record{{ID: 10, Message: "hello"}})
+	c.Check(err, gc.ErrorMatches, "posting log records: 503 Service Unavailable")
+}
+
+type stubDoer struct {
+	status   int
+	requests []*http.Request
+	bodies   []string
+}
+
+func (d *stubDoer) Do(req *http.Request) (*http.Response, error) {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	d.requests = append(d.requests, req)
+	d.bodies = append(d.bodies, string(body))
+	return &http.Response{
+		Status:     fmt.Sprintf("%d %s", d.status, http.StatusText(d.status)),
+		StatusCode: d.status,
+		Body:       ioutil.NopCloser(strings.NewReader("")),
+	}, nil
+}
@@ -0,0 +1,7 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// The https package holds the tools needed to perform log forwarding
+// from Juju to a remote https endpoint, authenticating with a client
+// certificate.
+package https
@@ -76,7 +76,7 @@ func OpenForSender(cfg RawConfig, opener SenderOpener) (*Client, error) {
 }
 
 func open(cfg RawConfig, opener SenderOpener) (Sender, error) {
-	tlsCfg, err := cfg.tlsConfig()
+	tlsCfg, err := cfg.TLSConfig()
 	if err != nil {
 		return nil, errors.Annotate(err, "constructing TLS config")
 	}
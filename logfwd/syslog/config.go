@@ -7,6 +7,8 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"net"
+	"net/url"
+	"strings"
 
 	"github.com/juju/errors"
 	"github.com/juju/utils/cert"
@@ -24,6 +26,9 @@ type RawConfig struct {
 	//
 	// If the port is not set then the default TLS port (6514) will
 	// be used.
+	//
+	// Alternatively Host may be an https URL, in which case records
+	// are posted to it as JSON rather than sent using RFC 5424.
 	Host string
 
 	// CACert is the TLS CA certificate (x.509, PEM-encoded) to use
@@ -46,14 +51,27 @@ func (cfg RawConfig) Validate() error {
 	}
 
 	if cfg.Enabled || cfg.ClientKey != "" || cfg.ClientCert != "" || cfg.CACert != "" {
-		if _, err := cfg.tlsConfig(); err != nil {
+		if _, err := cfg.TLSConfig(); err != nil {
 			return errors.Annotate(err, "validating TLS config")
 		}
 	}
 	return nil
 }
 
+// IsHTTPS returns whether records are to be posted to an https URL
+// rather than sent to a syslog host.
+func (cfg RawConfig) IsHTTPS() bool {
+	return strings.HasPrefix(cfg.Host, "https://")
+}
+
 func (cfg RawConfig) validateHost() error {
+	if cfg.IsHTTPS() {
+		u, err := url.Parse(cfg.Host)
+		if err != nil || u.Host == "" {
+			return errors.NotValidf("Host %q", cfg.Host)
+		}
+		return nil
+	}
 	host, _, err := net.SplitHostPort(cfg.Host)
 	if err != nil {
 		host = cfg.Host
@@ -64,7 +82,9 @@ func (cfg RawConfig) validateHost() error {
 	return nil
 }
 
-func (cfg RawConfig) tlsConfig() (*tls.Config, error) {
+// TLSConfig returns the TLS configuration used to connect to the
+// target, which authenticates both the target and the client.
+func (cfg RawConfig) TLSConfig() (*tls.Config, error) {
 	clientCert, err := tls.X509KeyPair([]byte(cfg.ClientCert), []byte(cfg.ClientKey))
 	if err != nil {
 		return nil, errors.Annotate(err, "parsing client key pair")
@@ -91,6 +91,35 @@ func (s *ConfigSuite) TestRawValidateMissingHostname(c *gc.C) {
 	c.Check(err, gc.ErrorMatches, `Host ":9876" not valid`)
 }
 
+func (s *ConfigSuite) TestRawValidateHTTPS(c *gc.C) {
+	cfg := syslog.RawConfig{
+		Enabled:    true,
+		Host:       "https://logs.example.com:8443/juju",
+		CACert:     coretesting.CACert,
+		ClientCert: coretesting.ServerCert,
+		ClientKey:  coretesting.ServerKey,
+	}
+
+	err := cfg.Validate()
+
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(cfg.IsHTTPS(), jc.IsTrue)
+}
+
+func (s *ConfigSuite) TestRawValidateHTTPSMissingHostname(c *gc.C) {
+	cfg := syslog.RawConfig{
+		Enabled:    true,
+		Host:       "https:///juju",
+		CACert:     coretesting.CACert,
+		ClientCert: coretesting.ServerCert,
+		ClientKey:  coretesting.ServerKey,
+	}
+
+	err := cfg.Validate()
+
+	c.Check(err, gc.ErrorMatches, `Host "https:///juju" not valid`)
+}
+
 func (s *ConfigSuite) TestRawValidateMissingCACert(c *gc.C) {
 	cfg := syslog.RawConfig{
 		Host:       "a.b.c:9876",
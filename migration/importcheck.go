@@ -0,0 +1,313 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package migration
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/description"
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v6"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/cloud"
+	corenetwork "github.com/juju/juju/core/network"
+	"github.com/juju/juju/state"
+)
+
+// The kinds of problem reported by CheckImport.
+const (
+	ImportIssueSchema  = "schema"
+	ImportIssueModel   = "model"
+	ImportIssueCloud   = "cloud"
+	ImportIssueCharm   = "charm"
+	ImportIssueNetwork = "network"
+)
+
+// ImportCheckBackend defines the interface to query the target
+// controller's state when checking a model for import.
+type ImportCheckBackend interface {
+	ModelExists(uuid string) (bool, error)
+	Cloud(name string) (cloud.Cloud, error)
+	CloudCredential(tag names.CloudCredentialTag) (state.Credential, error)
+}
+
+// ImportCheckTarget defines the interface to query the charm store and
+// the cloud of the target controller when checking a model for import.
+type ImportCheckTarget interface {
+	// CharmsAvailable returns, for each of the charm store charm URLs,
+	// an error if that charm cannot be fetched by the target
+	// controller, or nil if it can.
+	CharmsAvailable(curls []*charm.URL) ([]error, error)
+
+	// Subnets returns the subnets known to the target cloud for the
+	// model. A NotSupported error is returned if the cloud has no
+	// networking support.
+	Subnets(model description.Model) ([]corenetwork.SubnetInfo, error)
+}
+
+// ImportIssue describes a problem that would prevent a model from
+// being imported.
+type ImportIssue struct {
+	// Kind is the kind of problem, one of the ImportIssue constants.
+	Kind string
+
+	// Entity identifies the part of the model with the problem, if
+	// the problem is not with the model as a whole.
+	Entity string
+
+	// Message describes the problem.
+	Message string
+}
+
+// ImportReport holds the result of checking a model for import.
+type ImportReport struct {
+	ModelUUID string
+	ModelName string
+	Issues    []ImportIssue
+}
+
+// Valid returns whether no issues were found with the model.
+func (r *ImportReport) Valid() bool {
+	return len(r.Issues) == 0
+}
+
+func (r *ImportReport) addIssue(kind, entity, format string, args ...interface{}) {
+	r.Issues = append(r.Issues, ImportIssue{
+		Kind:    kind,
+		Entity:  entity,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// CheckImport deserializes a model description from the bytes and
+// checks, without writing anything, whether it could be imported into
+// the controller described by the backend and target. The model is not
+// imported, so problems that only arise while writing it to state are
+// not found. Problems with the model are reported as issues; an error
+// is only returned if the checks could not be made.
+func CheckImport(backend ImportCheckBackend, target ImportCheckTarget, bytes []byte) (*ImportReport, error) {
+	report := &ImportReport{}
+	model, err := description.Deserialize(bytes)
+	if err != nil {
+		report.addIssue(ImportIssueSchema, "", "cannot deserialize model: %v", err)
+		return report, nil
+	}
+	report.ModelUUID = model.Tag().Id()
+	if cfg := model.Config(); cfg != nil {
+		report.ModelName, _ = cfg["name"].(string)
+	}
+	if err := model.Validate(); err != nil {
+		report.addIssue(ImportIssueSchema, "", "%v", err)
+	}
+
+	checks := []func(ImportCheckBackend, ImportCheckTarget, description.Model, *ImportReport) error{
+		checkImportModel,
+		checkImportCloud,
+		checkImportCharms,
+		checkImportNetwork,
+	}
+	for _, check := range checks {
+		if err := check(backend, target, model, report); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return report, nil
+}
+
+func checkImportModel(backend ImportCheckBackend, _ ImportCheckTarget, model description.Model, report *ImportReport) error {
+	exists, err := backend.ModelExists(report.ModelUUID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if exists {
+		report.addIssue(ImportIssueModel, "", "model %s already exists", report.ModelUUID)
+	}
+	if model.Type() != "" {
+		if _, err := state.ParseModelType(model.Type()); err != nil {
+			report.addIssue(ImportIssueModel, "", "%v", err)
+		}
+	}
+	if len(model.RemoteApplications()) != 0 {
+		report.addIssue(ImportIssueModel, "", "can't import models with remote applications")
+	}
+	return nil
+}
+
+func checkImportCloud(backend ImportCheckBackend, _ ImportCheckTarget, model description.Model, report *ImportReport) error {
+	cloudName := model.Cloud()
+	targetCloud, err := backend.Cloud(cloudName)
+	if errors.IsNotFound(err) {
+		report.addIssue(ImportIssueCloud, cloudName, "cloud %q not found on the target controller", cloudName)
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if region := model.CloudRegion(); region != "" {
+		found := false
+		for _, r := range targetCloud.Regions {
+			if r.Name == region {
+				found = true
+				break
+			}
+		}
+		if !found {
+			report.addIssue(ImportIssueCloud, cloudName, "region %q not found in cloud %q", region, cloudName)
+		}
+	}
+
+	creds := model.CloudCredential()
+	if creds == nil {
+		return nil
+	}
+	credID := fmt.Sprintf("%s/%s/%s", creds.Cloud(), creds.Owner(), creds.Name())
+	if !names.IsValidCloudCredential(credID) {
+		report.addIssue(ImportIssueCloud, credID, "cloud credential ID %q not valid", credID)
+		return nil
+	}
+	existing, err := backend.CloudCredential(names.NewCloudCredentialTag(credID))
+	if errors.IsNotFound(err) {
+		// The credential is added by the import.
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if existing.AuthType != creds.AuthType() {
+		report.addIssue(ImportIssueCloud, credID, "credential auth type mismatch: %q != %q", existing.AuthType, creds.AuthType())
+	}
+	if !reflect.DeepEqual(existing.Attributes, creds.Attributes()) {
+		report.addIssue(ImportIssueCloud, credID, "credential attribute mismatch")
+	}
+	if existing.Revoked {
+		report.addIssue(ImportIssueCloud, credID, "credential %q is revoked", credID)
+	}
+	return nil
+}
+
+// checkImportCharms checks that the charm of every application is one
+// that the migration can carry to the target controller, and that the
+// charm store charms can be fetched by the target controller. Local
+// charms are only available from the source controller, so are not
+// checked against the target. Units are imported with the charm of
+// their application.
+func checkImportCharms(_ ImportCheckBackend, target ImportCheckTarget, model description.Model, report *ImportReport) error {
+	var (
+		storeCharms []*charm.URL
+		storeApps   []string
+	)
+	for _, app := range model.Applications() {
+		curl := checkImportCharmURL(app.Name(), app.CharmURL(), report)
+		if curl != nil && curl.Schema == "cs" {
+			storeCharms = append(storeCharms, curl)
+			storeApps = append(storeApps, app.Name())
+		}
+	}
+	if len(storeCharms) == 0 {
+		return nil
+	}
+	results, err := target.CharmsAvailable(storeCharms)
+	if err != nil {
+		return errors.Annotate(err, "checking charm availability")
+	}
+	for i, err := range results {
+		if err != nil {
+			report.addIssue(ImportIssueCharm, storeApps[i], "charm %q not available: %v", storeCharms[i], err)
+		}
+	}
+	return nil
+}
+
+// checkImportCharmURL checks the charm URL, returning the parsed URL
+// if it is valid.
+func checkImportCharmURL(entity, url string, report *ImportReport) *charm.URL {
+	curl, err := charm.ParseURL(url)
+	if err != nil {
+		report.addIssue(ImportIssueCharm, entity, "%v", err)
+		return nil
+	}
+	if curl.Schema != "cs" && curl.Schema != "local" {
+		report.addIssue(ImportIssueCharm, entity, "charm %q has unsupported schema %q", url, curl.Schema)
+		return nil
+	}
+	if curl.Revision < 0 {
+		report.addIssue(ImportIssueCharm, entity, "charm %q has no revision", url)
+		return nil
+	}
+	return curl
+}
+
+// checkImportNetwork checks that the subnets and endpoint bindings of
+// the model map onto the spaces it defines, and that the subnets map
+// onto subnets of the target cloud.
+func checkImportNetwork(_ ImportCheckBackend, target ImportCheckTarget, model description.Model, report *ImportReport) error {
+	spaceNames := set.NewStrings(corenetwork.DefaultSpaceName)
+	spaceIds := set.NewStrings(corenetwork.DefaultSpaceId)
+	for _, space := range model.Spaces() {
+		if spaceNames.Contains(space.Name()) {
+			report.addIssue(ImportIssueNetwork, space.Name(), "space %q defined more than once", space.Name())
+		}
+		spaceNames.Add(space.Name())
+		if space.Id() != "" {
+			spaceIds.Add(space.Id())
+		}
+	}
+
+	cidrs := set.NewStrings()
+	var cloudCIDRs []string
+	for _, subnet := range model.Subnets() {
+		cidr := subnet.CIDR()
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			report.addIssue(ImportIssueNetwork, cidr, "subnet CIDR %q not valid", cidr)
+		} else if subnet.FanLocalUnderlay() == "" {
+			if cidrs.Contains(cidr) {
+				report.addIssue(ImportIssueNetwork, cidr, "subnet %q defined more than once", cidr)
+			} else {
+				cloudCIDRs = append(cloudCIDRs, cidr)
+			}
+		}
+		cidrs.Add(cidr)
+		if id := subnet.SpaceID(); id != "" && !spaceIds.Contains(id) {
+			report.addIssue(ImportIssueNetwork, cidr, "subnet %q in unknown space with ID %q", cidr, id)
+		} else if name := subnet.SpaceName(); id == "" && !spaceNames.Contains(name) {
+			report.addIssue(ImportIssueNetwork, cidr, "subnet %q in unknown space %q", cidr, name)
+		}
+	}
+
+	for _, app := range model.Applications() {
+		for endpoint, space := range app.EndpointBindings() {
+			if !spaceNames.Contains(space) && !spaceIds.Contains(space) {
+				report.addIssue(ImportIssueNetwork, app.Name(), "endpoint %q bound to unknown space %q", endpoint, space)
+			}
+		}
+	}
+	checkImportCloudSubnets(target, model, cloudCIDRs, report)
+	return nil
+}
+
+// checkImportCloudSubnets checks that each of the model's subnets, other
+// than FAN overlays, is known to the target cloud.
+func checkImportCloudSubnets(target ImportCheckTarget, model description.Model, cidrs []string, report *ImportReport) {
+	if len(cidrs) == 0 {
+		return
+	}
+	subnets, err := target.Subnets(model)
+	if errors.IsNotSupported(err) {
+		return
+	} else if err != nil {
+		report.addIssue(ImportIssueNetwork, model.Cloud(), "cannot list subnets of cloud %q: %v", model.Cloud(), err)
+		return
+	}
+	known := set.NewStrings()
+	for _, subnet := range subnets {
+		known.Add(subnet.CIDR)
+	}
+	for _, cidr := range cidrs {
+		if !known.Contains(cidr) {
+			report.addIssue(ImportIssueNetwork, cidr, "subnet %q not found in cloud %q", cidr, model.Cloud())
+		}
+	}
+}
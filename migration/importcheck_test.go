@@ -0,0 +1,242 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package migration_test
+
+import (
+	"github.com/juju/description"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6"
+	"gopkg.in/juju/names.v3"
+
+	corenetwork "github.com/juju/juju/core/network"
+	"github.com/juju/juju/migration"
+	"github.com/juju/juju/provider/dummy"
+	statetesting "github.com/juju/juju/state/testing"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ImportCheckSuite struct {
+	statetesting.StateSuite
+
+	target *stubImportTarget
+}
+
+var _ = gc.Suite(&ImportCheckSuite{})
+
+func (s *ImportCheckSuite) SetUpTest(c *gc.C) {
+	s.InitialConfig = coretesting.CustomModelConfig(c, dummy.SampleConfig())
+	s.StateSuite.SetUpTest(c)
+	s.target = &stubImportTarget{
+		missing: map[string]bool{"cs:trusty/missing-1": true},
+		subnets: []corenetwork.SubnetInfo{{CIDR: "10.0.0.0/24"}},
+	}
+}
+
+// exportModel exports the suite's model, renamed and with a new UUID
+// unless keepUUID is set.
+func (s *ImportCheckSuite) exportModel(c *gc.C, keepUUID bool) description.Model {
+	model, err := s.State.Export()
+	c.Assert(err, jc.ErrorIsNil)
+	if !keepUUID {
+		model.UpdateConfig(map[string]interface{}{
+			"name": "new-model",
+			"uuid": utils.MustNewUUID().String(),
+		})
+	}
+	return model
+}
+
+func (s *ImportCheckSuite) checkImport(c *gc.C, model description.Model) *migration.ImportReport {
+	bytes, err := description.Serialize(model)
+	c.Assert(err, jc.ErrorIsNil)
+	report, err := migration.CheckImport(s.State, s.target, bytes)
+	c.Assert(err, jc.ErrorIsNil)
+	return report
+}
+
+func (s *ImportCheckSuite) TestValid(c *gc.C) {
+	model := s.exportModel(c, false)
+	report := s.checkImport(c, model)
+	c.Check(report.Issues, gc.HasLen, 0)
+	c.Check(report.Valid(), jc.IsTrue)
+	c.Check(report.ModelUUID, gc.Equals, model.Tag().Id())
+	c.Check(report.ModelName, gc.Equals, "new-model")
+}
+
+func (s *ImportCheckSuite) TestBadBytes(c *gc.C) {
+	report, err := migration.CheckImport(s.State, s.target, []byte("not a model"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(report.Issues, gc.HasLen, 1)
+	c.Check(report.Issues[0].Kind, gc.Equals, migration.ImportIssueSchema)
+	c.Check(report.Issues[0].Message, gc.Matches, "cannot deserialize model: yaml: unmarshal errors:\n.*")
+}
+
+func (s *ImportCheckSuite) TestModelExists(c *gc.C) {
+	report := s.checkImport(c, s.exportModel(c, true))
+	c.Check(report.Valid(), jc.IsFalse)
+	c.Check(issuesOfKind(report, migration.ImportIssueModel), jc.DeepEquals, []migration.ImportIssue{{
+		Kind:    migration.ImportIssueModel,
+		Message: "model " + s.State.ModelUUID() + " already exists",
+	}})
+}
+
+func (s *ImportCheckSuite) TestCharms(c *gc.C) {
+	model := s.exportModel(c, false)
+	for name, url := range map[string]string{
+		"norev":   "cs:trusty/ubuntu",
+		"badurl":  "cs:~/ubuntu",
+		"archive": "ch:trusty/ubuntu-1",
+	} {
+		app := model.AddApplication(description.ApplicationArgs{
+			Tag:      names.NewApplicationTag(name),
+			Series:   "trusty",
+			CharmURL: url,
+		})
+		app.SetStatus(description.StatusArgs{Value: "active"})
+	}
+	report := s.checkImport(c, model)
+	c.Check(report.Valid(), jc.IsFalse)
+
+	issues := issuesOfKind(report, migration.ImportIssueCharm)
+	c.Assert(issues, gc.HasLen, 3)
+	messages := make(map[string]string)
+	for _, issue := range issues {
+		messages[issue.Entity] = issue.Message
+	}
+	c.Check(messages["norev"], gc.Equals, `charm "cs:trusty/ubuntu" has no revision`)
+	c.Check(messages["badurl"], gc.Not(gc.Equals), "")
+	c.Check(messages["archive"], gc.Equals, `charm "ch:trusty/ubuntu-1" has unsupported schema "ch"`)
+}
+
+func (s *ImportCheckSuite) TestCharmsAvailable(c *gc.C) {
+	model := s.exportModel(c, false)
+	for name, url := range map[string]string{
+		"mysql":   "cs:trusty/mysql-1",
+		"missing": "cs:trusty/missing-1",
+		"local":   "local:trusty/local-1",
+	} {
+		app := model.AddApplication(description.ApplicationArgs{
+			Tag:      names.NewApplicationTag(name),
+			Series:   "trusty",
+			CharmURL: url,
+		})
+		app.SetStatus(description.StatusArgs{Value: "active"})
+	}
+	report := s.checkImport(c, model)
+	c.Check(issuesOfKind(report, migration.ImportIssueCharm), jc.DeepEquals, []migration.ImportIssue{{
+		Kind:    migration.ImportIssueCharm,
+		Entity:  "missing",
+		Message: `charm "cs:trusty/missing-1" not available: charm not found`,
+	}})
+	c.Check(s.target.checked, jc.SameContents, []string{"cs:trusty/mysql-1", "cs:trusty/missing-1"})
+}
+
+func (s *ImportCheckSuite) TestCharmsAvailableError(c *gc.C) {
+	s.target.err = errors.New("boom")
+	model := s.exportModel(c, false)
+	app := model.AddApplication(description.ApplicationArgs{
+		Tag:      names.NewApplicationTag("mysql"),
+		Series:   "trusty",
+		CharmURL: "cs:trusty/mysql-1",
+	})
+	app.SetStatus(description.StatusArgs{Value: "active"})
+	bytes, err := description.Serialize(model)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = migration.CheckImport(s.State, s.target, bytes)
+	c.Assert(err, gc.ErrorMatches, "checking charm availability: boom")
+}
+
+func (s *ImportCheckSuite) TestSubnetsNotSupported(c *gc.C) {
+	s.target.subnetsErr = errors.NotSupportedf("networking")
+	model := s.exportModel(c, false)
+	model.AddSubnet(description.SubnetArgs{CIDR: "10.0.1.0/24"})
+	report := s.checkImport(c, model)
+	c.Check(issuesOfKind(report, migration.ImportIssueNetwork), gc.HasLen, 0)
+}
+
+func (s *ImportCheckSuite) TestSubnetsError(c *gc.C) {
+	s.target.subnetsErr = errors.New("bad credential")
+	model := s.exportModel(c, false)
+	model.AddSubnet(description.SubnetArgs{CIDR: "10.0.1.0/24"})
+	report := s.checkImport(c, model)
+	c.Check(issuesOfKind(report, migration.ImportIssueNetwork), jc.DeepEquals, []migration.ImportIssue{{
+		Kind:    migration.ImportIssueNetwork,
+		Entity:  "dummy",
+		Message: `cannot list subnets of cloud "dummy": bad credential`,
+	}})
+}
+
+func (s *ImportCheckSuite) TestNetwork(c *gc.C) {
+	model := s.exportModel(c, false)
+	model.AddSpace(description.SpaceArgs{Id: "1", Name: "db"})
+	model.AddSubnet(description.SubnetArgs{CIDR: "10.0.0.0/24", SpaceID: "1"})
+	model.AddSubnet(description.SubnetArgs{CIDR: "10.0.1.0/24", SpaceID: "42"})
+	model.AddSubnet(description.SubnetArgs{CIDR: "bogus", SpaceID: "1"})
+	app := model.AddApplication(description.ApplicationArgs{
+		Tag:              names.NewApplicationTag("mysql"),
+		Series:           "trusty",
+		CharmURL:         "cs:trusty/mysql-1",
+		EndpointBindings: map[string]string{"server": "1", "cluster": "missing"},
+	})
+	app.SetStatus(description.StatusArgs{Value: "active"})
+
+	report := s.checkImport(c, model)
+	c.Check(report.Valid(), jc.IsFalse)
+	c.Check(issuesOfKind(report, migration.ImportIssueNetwork), jc.SameContents, []migration.ImportIssue{{
+		Kind:    migration.ImportIssueNetwork,
+		Entity:  "10.0.1.0/24",
+		Message: `subnet "10.0.1.0/24" in unknown space with ID "42"`,
+	}, {
+		Kind:    migration.ImportIssueNetwork,
+		Entity:  "10.0.1.0/24",
+		Message: `subnet "10.0.1.0/24" not found in cloud "dummy"`,
+	}, {
+		Kind:    migration.ImportIssueNetwork,
+		Entity:  "bogus",
+		Message: `subnet CIDR "bogus" not valid`,
+	}, {
+		Kind:    migration.ImportIssueNetwork,
+		Entity:  "mysql",
+		Message: `endpoint "cluster" bound to unknown space "missing"`,
+	}})
+}
+
+func issuesOfKind(report *migration.ImportReport, kind string) []migration.ImportIssue {
+	var issues []migration.ImportIssue
+	for _, issue := range report.Issues {
+		if issue.Kind == kind {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+type stubImportTarget struct {
+	missing    map[string]bool
+	checked    []string
+	err        error
+	subnets    []corenetwork.SubnetInfo
+	subnetsErr error
+}
+
+func (t *stubImportTarget) CharmsAvailable(curls []*charm.URL) ([]error, error) {
+	if t.err != nil {
+		return nil, t.err
+	}
+	results := make([]error, len(curls))
+	for i, curl := range curls {
+		t.checked = append(t.checked, curl.String())
+		if t.missing[curl.String()] {
+			results[i] = errors.New("charm not found")
+		}
+	}
+	return results, nil
+}
+
+func (t *stubImportTarget) Subnets(description.Model) ([]corenetwork.SubnetInfo, error) {
+	return t.subnets, t.subnetsErr
+}
@@ -15,6 +15,7 @@ import (
 	coremigration "github.com/juju/juju/core/migration"
 	"github.com/juju/juju/core/presence"
 	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/resource"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/tools"
@@ -75,6 +76,9 @@ type PrecheckApplication interface {
 	CharmURL() (*charm.URL, bool)
 	AllUnits() ([]PrecheckUnit, error)
 	MinUnits() int
+	SubordinatePolicy() state.SubordinatePolicy
+	EgressRules() []network.EgressRule
+	ExposedEndpoints() map[string]state.ExposedEndpoint
 }
 
 // PrecheckUnit describes state interface for a unit needed by
@@ -347,6 +351,20 @@ func (ctx *precheckContext) checkApplications() (map[string][]PrecheckUnit, erro
 		if app.Life() != state.Alive {
 			return nil, errors.Errorf("application %s is %s", app.Name(), app.Life())
 		}
+		// The model description has no place for subordinate
+		// policies, so they would be silently lost.
+		if !app.SubordinatePolicy().IsZero() {
+			return nil, errors.Errorf("application %s has a subordinate policy, which cannot be migrated", app.Name())
+		}
+		// Nor for egress rules.
+		if len(app.EgressRules()) != 0 {
+			return nil, errors.Errorf("application %s has egress rules, which cannot be migrated", app.Name())
+		}
+		// Nor for per-endpoint expose settings; dropping them would
+		// leave the application exposed to everywhere on the target.
+		if len(app.ExposedEndpoints()) != 0 {
+			return nil, errors.Errorf("application %s has per-endpoint expose settings, which cannot be migrated", app.Name())
+		}
 		units, err := app.AllUnits()
 		if err != nil {
 			return nil, errors.Annotatef(err, "retrieving units for %s", app.Name())
@@ -15,6 +15,7 @@ import (
 	"github.com/juju/juju/core/presence"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/migration"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/resource"
 	"github.com/juju/juju/resource/resourcetesting"
 	"github.com/juju/juju/state"
@@ -176,6 +177,49 @@ func (s *SourcePrecheckSuite) TestDyingApplication(c *gc.C) {
 	c.Assert(err.Error(), gc.Equals, "application foo is dying")
 }
 
+func (s *SourcePrecheckSuite) TestWithSubordinatePolicy(c *gc.C) {
+	backend := &fakeBackend{
+		apps: []migration.PrecheckApplication{
+			&fakeApp{
+				name:   "foo",
+				policy: state.SubordinatePolicy{MaxPerMachine: 1},
+			},
+		},
+	}
+	err := sourcePrecheck(backend)
+	c.Assert(err.Error(), gc.Equals, "application foo has a subordinate policy, which cannot be migrated")
+}
+
+func (s *SourcePrecheckSuite) TestWithEgressRules(c *gc.C) {
+	rule, err := network.NewEgressRule("tcp", 443, 443, "10.0.0.0/8")
+	c.Assert(err, jc.ErrorIsNil)
+	backend := &fakeBackend{
+		apps: []migration.PrecheckApplication{
+			&fakeApp{
+				name:   "foo",
+				egress: []network.EgressRule{rule},
+			},
+		},
+	}
+	err = sourcePrecheck(backend)
+	c.Assert(err.Error(), gc.Equals, "application foo has egress rules, which cannot be migrated")
+}
+
+func (s *SourcePrecheckSuite) TestWithExposedEndpoints(c *gc.C) {
+	backend := &fakeBackend{
+		apps: []migration.PrecheckApplication{
+			&fakeApp{
+				name: "foo",
+				exposedEndpoints: map[string]state.ExposedEndpoint{
+					"website": {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+				},
+			},
+		},
+	}
+	err := sourcePrecheck(backend)
+	c.Assert(err.Error(), gc.Equals, "application foo has per-endpoint expose settings, which cannot be migrated")
+}
+
 func (s *SourcePrecheckSuite) TestWithPendingMinUnits(c *gc.C) {
 	backend := &fakeBackend{
 		apps: []migration.PrecheckApplication{
@@ -965,6 +1009,10 @@ type fakeApp struct {
 	charmURL string
 	units    []migration.PrecheckUnit
 	minunits int
+	policy   state.SubordinatePolicy
+	egress   []network.EgressRule
+
+	exposedEndpoints map[string]state.ExposedEndpoint
 }
 
 func (a *fakeApp) Name() string {
@@ -991,6 +1039,18 @@ func (a *fakeApp) MinUnits() int {
 	return a.minunits
 }
 
+func (a *fakeApp) SubordinatePolicy() state.SubordinatePolicy {
+	return a.policy
+}
+
+func (a *fakeApp) EgressRules() []network.EgressRule {
+	return a.egress
+}
+
+func (a *fakeApp) ExposedEndpoints() map[string]state.ExposedEndpoint {
+	return a.exposedEndpoints
+}
+
 type fakeUnit struct {
 	name        string
 	version     version.Binary
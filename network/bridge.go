@@ -34,6 +34,12 @@ var _ Bridger = (*etcNetworkInterfacesBridger)(nil)
 func (b *etcNetworkInterfacesBridger) Bridge(devices []DeviceToBridge, reconfigureDelay int) error {
 	devicesMap := make(map[string]string)
 	for _, k := range devices {
+		if k.OpenvSwitch {
+			return errors.NotSupportedf("Open vSwitch bridge %q in %s", k.BridgeName, b.Filename)
+		}
+		if k.MTU != 0 {
+			logger.Warningf("ignoring MTU %d of bridge %q: not supported in %s", k.MTU, k.BridgeName, b.Filename)
+		}
 		devicesMap[k.DeviceName] = k.BridgeName
 	}
 	params := debinterfaces.ActivationParams{
@@ -97,3 +97,15 @@ func (*BridgeSuite) TestENIBridgerWithDryRun(c *gc.C) {
 	err := bridger.Bridge(devices, 0)
 	c.Assert(err, gc.IsNil)
 }
+
+func (*BridgeSuite) TestENIBridgerWithOpenvSwitch(c *gc.C) {
+	devices := []network.DeviceToBridge{
+		{
+			DeviceName:  "ens123",
+			BridgeName:  "br-ens123",
+			OpenvSwitch: true,
+		},
+	}
+	expected := `Open vSwitch bridge "br-ens123" in testdata/interfaces not supported`
+	assertENIBridgerError(c, devices, 0, clock.WallClock, "testdata/interfaces", true, 0, expected)
+}
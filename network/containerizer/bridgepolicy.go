@@ -6,6 +6,8 @@ package containerizer
 import (
 	"fmt"
 	"hash/crc32"
+	"net"
+	"path"
 	"sort"
 	"strings"
 
@@ -39,6 +41,22 @@ type BridgePolicy struct {
 	//  - provider
 	//  - local
 	ContainerNetworkingMethod string
+	// BridgePrefix is the prefix of the names of the bridges created for
+	// host devices. If empty, bridges are named by BridgeNameForDevice.
+	BridgePrefix string
+	// BridgeMTU is the MTU of the bridges created for host devices. If
+	// zero, a bridge has the MTU of the device it bridges.
+	BridgeMTU int
+	// BridgeInterfaces holds globs matching the names of the host devices
+	// that may be bridged. If empty, any device may be bridged.
+	BridgeInterfaces []string
+	// OpenvSwitch is true if the bridges created for host devices are
+	// Open vSwitch bridges.
+	OpenvSwitch bool
+	// FanConfig holds the FAN overlays configured for the model. Host
+	// bridges with an address in one of the overlays are treated as FAN
+	// devices, as well as those named by the fan tooling.
+	FanConfig network.FanConfig
 }
 
 // inferContainerSpaces tries to find a valid space for the container to be
@@ -144,6 +162,20 @@ func possibleBridgeTarget(dev LinkLayerDevice) (bool, error) {
 	return false, nil
 }
 
+// mayBridge reports whether the named host device matches one of the
+// policy's BridgeInterfaces, if it has any.
+func (p *BridgePolicy) mayBridge(deviceName string) bool {
+	if len(p.BridgeInterfaces) == 0 {
+		return true
+	}
+	for _, pattern := range p.BridgeInterfaces {
+		if matched, _ := path.Match(pattern, deviceName); matched {
+			return true
+		}
+	}
+	return false
+}
+
 func formatDeviceMap(spacesToDevices map[string][]LinkLayerDevice) string {
 	spaceNames := make([]string, len(spacesToDevices))
 	i := 0
@@ -197,6 +229,31 @@ func BridgeNameForDevice(device string) string {
 	}
 }
 
+// maxBridgeNameLength is the longest name a network interface may have.
+const maxBridgeNameLength = 15
+
+// BridgeNameForDeviceWithPrefix returns the name of the bridge for the
+// device, made of the prefix and the device name. If that is too long,
+// the device name is replaced by a 6-char hash of it followed, if there
+// is room, by '-' and the end of the name. As with BridgeNameForDevice,
+// '.' is replaced with '-'. If the prefix is empty, BridgeNameForDevice
+// is used.
+func BridgeNameForDeviceWithPrefix(prefix, device string) string {
+	if prefix == "" {
+		return BridgeNameForDevice(device)
+	}
+	device = strings.Replace(device, ".", "-", -1)
+	if len(prefix)+len(device) <= maxBridgeNameLength {
+		return prefix + device
+	}
+	hash := crc32.Checksum([]byte(device), crc32.IEEETable) & 0xffffff
+	name := fmt.Sprintf("%s%0.6x", prefix, hash)
+	if n := maxBridgeNameLength - len(name) - 1; n > 0 {
+		name += "-" + device[len(device)-n:]
+	}
+	return name
+}
+
 // FindMissingBridgesForContainer looks at the spaces that the container
 // wants to be in, and sees if there are any host devices that should be
 // bridged.
@@ -220,7 +277,11 @@ func (b *BridgePolicy) FindMissingBridgesForContainer(m Machine, containerMachin
 				if b.ContainerNetworkingMethod != "local" && skippedDeviceNames.Contains(device.Name()) {
 					continue
 				}
-				if strings.HasPrefix(device.Name(), "fan-") {
+				isFan, err := b.isFanDevice(device)
+				if err != nil {
+					return nil, 0, errors.Trace(err)
+				}
+				if isFan {
 					fanSpacesFound.Add(spaceName)
 				} else {
 					spacesFound.Add(spaceName)
@@ -252,7 +313,7 @@ func (b *BridgePolicy) FindMissingBridgesForContainer(m Machine, containerMachin
 			if err != nil {
 				return nil, 0, err
 			}
-			if !possible {
+			if !possible || !b.mayBridge(hostDevice.Name()) {
 				continue
 			}
 			hostDeviceNames = append(hostDeviceNames, hostDevice.Name())
@@ -304,14 +365,44 @@ func (b *BridgePolicy) FindMissingBridgesForContainer(m Machine, containerMachin
 	hostToBridge := make([]network.DeviceToBridge, 0, len(hostDeviceNamesToBridge))
 	for _, hostName := range network.NaturallySortDeviceNames(hostDeviceNamesToBridge...) {
 		hostToBridge = append(hostToBridge, network.DeviceToBridge{
-			DeviceName: hostName,
-			BridgeName: BridgeNameForDevice(hostName),
-			MACAddress: hostDeviceByName[hostName].MACAddress(),
+			DeviceName:  hostName,
+			BridgeName:  BridgeNameForDeviceWithPrefix(b.BridgePrefix, hostName),
+			MACAddress:  hostDeviceByName[hostName].MACAddress(),
+			MTU:         b.BridgeMTU,
+			OpenvSwitch: b.OpenvSwitch,
 		})
 	}
 	return hostToBridge, reconfigureDelay, nil
 }
 
+// isFanDevice reports whether the host device belongs to a FAN network,
+// either because it is named for the fan or because it has an address
+// within one of the configured overlays.
+func (p *BridgePolicy) isFanDevice(device LinkLayerDevice) (bool, error) {
+	if strings.HasPrefix(device.Name(), "fan-") {
+		return true, nil
+	}
+	if len(p.FanConfig) == 0 {
+		return false, nil
+	}
+	addresses, err := device.Addresses()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	for _, addr := range addresses {
+		ip := net.ParseIP(addr.Value())
+		if ip == nil {
+			continue
+		}
+		for _, fan := range p.FanConfig {
+			if fan.Overlay.Contains(ip) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
 // PopulateContainerLinkLayerDevices sets the link-layer devices of the given
 // containerMachine, setting each device linked to the corresponding
 // BridgeDevice of the host machine. It also records when one of the
@@ -339,7 +430,10 @@ func (p *BridgePolicy) PopulateContainerLinkLayerDevices(m Machine, containerMac
 
 	for spaceName, hostDevices := range devicesPerSpace {
 		for _, hostDevice := range hostDevices {
-			isFan := strings.HasPrefix(hostDevice.Name(), "fan-")
+			isFan, err := p.isFanDevice(hostDevice)
+			if err != nil {
+				return errors.Trace(err)
+			}
 			wantThisDevice := isFan == (p.ContainerNetworkingMethod == "fan")
 			deviceType, name := hostDevice.Type(), hostDevice.Name()
 			if wantThisDevice && deviceType == state.BridgeDevice && !skippedDeviceNames.Contains(name) {
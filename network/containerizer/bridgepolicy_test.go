@@ -1149,6 +1149,103 @@ func (s *bridgePolicyStateSuite) TestFindMissingBridgesForContainerNetworkingMet
 	c.Assert(err, gc.ErrorMatches, `host machine "0" has no available FAN devices in space\(s\) "default"`)
 }
 
+// setupFanOverlay adds a FAN overlay of 10.0.0.0/24 to the model and a
+// bridge on the host machine with an address in it, named so that only
+// the model's FAN configuration identifies it as a FAN device.
+func (s *bridgePolicyStateSuite) setupFanOverlay(c *gc.C) network.FanConfig {
+	s.setupTwoSpaces(c)
+	overlay := corenetwork.SubnetInfo{CIDR: "252.0.0.0/16"}
+	overlay.SetFan("10.0.0.0/24", "252.0.0.0/8")
+	_, err := s.State.AddSubnet(overlay)
+	c.Assert(err, jc.ErrorIsNil)
+	s.createBridgeWithIP(c, s.machine, "br-overlay", "252.0.0.20/16")
+
+	fanConfig, err := network.ParseFanConfig("10.0.0.0/16=252.0.0.0/8")
+	c.Assert(err, jc.ErrorIsNil)
+	return fanConfig
+}
+
+func (s *bridgePolicyStateSuite) TestFindMissingBridgesForContainerFANFromConfig(c *gc.C) {
+	fanConfig := s.setupFanOverlay(c)
+	s.addContainerMachine(c)
+	err := s.containerMachine.SetConstraints(constraints.Value{
+		Spaces: &[]string{"default"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	bridgePolicy := &containerizer.BridgePolicy{
+		ContainerNetworkingMethod: "fan",
+	}
+	_, _, err = bridgePolicy.FindMissingBridgesForContainer(s.machine, s.containerMachine)
+	c.Assert(err, gc.ErrorMatches, `host machine "0" has no available FAN devices in space\(s\) "default"`)
+
+	bridgePolicy.FanConfig = fanConfig
+	missing, reconfigureDelay, err := bridgePolicy.FindMissingBridgesForContainer(s.machine, s.containerMachine)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(missing, gc.HasLen, 0)
+	c.Check(reconfigureDelay, gc.Equals, 0)
+}
+
+func (s *bridgePolicyStateSuite) TestPopulateContainerLinkLayerDevicesFANFromConfig(c *gc.C) {
+	fanConfig := s.setupFanOverlay(c)
+	s.createNICAndBridgeWithIP(c, s.machine, "ens33", "br-ens33", "10.0.0.20/24")
+	s.addContainerMachine(c)
+	err := s.containerMachine.SetConstraints(constraints.Value{
+		Spaces: &[]string{"default"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	bridgePolicy := &containerizer.BridgePolicy{
+		ContainerNetworkingMethod: "fan",
+		FanConfig:                 fanConfig,
+	}
+	err = bridgePolicy.PopulateContainerLinkLayerDevices(s.machine, s.containerMachine)
+	c.Assert(err, jc.ErrorIsNil)
+
+	containerDevices, err := s.containerMachine.AllLinkLayerDevices()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(containerDevices, gc.HasLen, 1)
+	c.Check(containerDevices[0].Name(), gc.Equals, "eth0")
+	c.Check(containerDevices[0].ParentName(), gc.Equals, `m#0#d#br-overlay`)
+}
+
+func (s *bridgePolicyStateSuite) TestFindMissingBridgesForContainerBridgeSettings(c *gc.C) {
+	s.setupTwoSpaces(c)
+	s.createNICWithIP(c, s.machine, "ens3", "172.12.0.10/24")
+	s.createNICWithIP(c, s.machine, "ens4", "192.168.0.10/24")
+	s.createAllDefaultDevices(c, s.machine)
+	s.addContainerMachine(c)
+	bridgePolicy := &containerizer.BridgePolicy{
+		BridgePrefix:     "juju-",
+		BridgeMTU:        9000,
+		BridgeInterfaces: []string{"ens4"},
+		OpenvSwitch:      true,
+	}
+	missing, _, err := bridgePolicy.FindMissingBridgesForContainer(s.machine, s.containerMachine)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(missing, gc.DeepEquals, []network.DeviceToBridge{{
+		DeviceName:  "ens4",
+		BridgeName:  "juju-ens4",
+		MTU:         9000,
+		OpenvSwitch: true,
+	}})
+}
+
+func (s *bridgePolicyStateSuite) TestFindMissingBridgesForContainerNoEligibleDevice(c *gc.C) {
+	s.setupTwoSpaces(c)
+	s.createNICWithIP(c, s.machine, "eth0", "10.0.0.20/24")
+	s.addContainerMachine(c)
+	err := s.containerMachine.SetConstraints(constraints.Value{
+		Spaces: &[]string{"default"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	bridgePolicy := &containerizer.BridgePolicy{
+		BridgeInterfaces: []string{"bond*"},
+	}
+	_, _, err = bridgePolicy.FindMissingBridgesForContainer(s.machine, s.containerMachine)
+	c.Assert(err, gc.ErrorMatches, `host machine "0" has no available device in space\(s\) "default"`)
+}
+
 var bridgeNames = map[string]string{
 	"eno0":            "br-eno0",
 	"enovlan.123":     "br-enovlan-123",
@@ -1169,6 +1266,22 @@ func (s *bridgePolicyStateSuite) TestBridgeNameForDevice(c *gc.C) {
 	}
 }
 
+var prefixedBridgeNames = map[string]string{
+	"eno1":            "juju-eno1",
+	"eth0.100":        "juju-eth0-100",
+	"enp0s31f6":       "juju-enp0s31f6",
+	"enx00e07cc81e1d": "juju-094962-e1d",
+}
+
+func (s *bridgePolicyStateSuite) TestBridgeNameForDeviceWithPrefix(c *gc.C) {
+	for deviceName, bridgeName := range prefixedBridgeNames {
+		generatedBridgeName := containerizer.BridgeNameForDeviceWithPrefix("juju-", deviceName)
+		c.Check(generatedBridgeName, gc.Equals, bridgeName)
+	}
+	c.Check(containerizer.BridgeNameForDeviceWithPrefix("brdg123-", "fourteenchars1"), gc.Equals, "brdg123-5590a4")
+	c.Check(containerizer.BridgeNameForDeviceWithPrefix("", "eno0"), gc.Equals, "br-eno0")
+}
+
 // TODO(jam): 2017-01-31 Make sure KVM guests default to virbr0, and LXD guests use lxdbr0
 // Add tests for UseLocal = True, but we have named spaces
 // Add tests for UseLocal = True, but the host device is bridged
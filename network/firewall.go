@@ -27,7 +27,10 @@ type IngressRule struct {
 
 // NewIngressRule returns an IngressRule for the specified port
 // range. If no explicit source ranges are specified, there is no
-// restriction from where incoming traffic originates.
+// restriction from where incoming traffic originates. Source ranges
+// may be IPv4 or IPv6 CIDRs, although only the OpenStack provider
+// creates firewall rules for IPv6 sources; the other providers pass
+// them to their clouds as they would IPv4 ranges.
 func NewIngressRule(protocol string, from, to int, sourceCIDRs ...string) (IngressRule, error) {
 	rule := IngressRule{
 		PortRange: network.PortRange{
@@ -40,9 +43,9 @@ func NewIngressRule(protocol string, from, to int, sourceCIDRs ...string) (Ingre
 		if _, _, err := net.ParseCIDR(cidr); err != nil {
 			return IngressRule{}, errors.Trace(err)
 		}
-	}
-	if len(sourceCIDRs) > 0 {
-		rule.SourceCIDRs = sourceCIDRs
+		// IPv6 sources are kept in canonical form so that they
+		// compare equal to the rules reported by providers.
+		rule.SourceCIDRs = append(rule.SourceCIDRs, network.NormaliseCIDR(cidr))
 	}
 	return rule, nil
 }
@@ -109,3 +112,89 @@ func (p IngressRuleSlice) Less(i, j int) bool {
 func SortIngressRules(IngressRules []IngressRule) {
 	sort.Sort(IngressRuleSlice(IngressRules))
 }
+
+// EgressRule represents a range of ports and destinations
+// to which outgoing packets are allowed.
+type EgressRule struct {
+	// PortRange is the range of destination ports for which
+	// outgoing packets are allowed.
+	network.PortRange
+
+	// DestinationCIDRs is a list of IP address blocks expressed in
+	// CIDR format to which this rule applies.
+	DestinationCIDRs []string
+}
+
+// NewEgressRule returns an EgressRule for the specified port range.
+// If no explicit destination ranges are specified, outgoing traffic
+// may go anywhere. Destination ranges may be IPv4 or IPv6 CIDRs.
+func NewEgressRule(protocol string, from, to int, destinationCIDRs ...string) (EgressRule, error) {
+	rule := EgressRule{
+		PortRange: network.PortRange{
+			Protocol: protocol,
+			FromPort: from,
+			ToPort:   to,
+		},
+	}
+	for _, cidr := range destinationCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return EgressRule{}, errors.Trace(err)
+		}
+		rule.DestinationCIDRs = append(rule.DestinationCIDRs, network.NormaliseCIDR(cidr))
+	}
+	return rule, nil
+}
+
+// MustNewEgressRule returns an EgressRule for the specified port
+// range. The method will panic if there is an error.
+func MustNewEgressRule(protocol string, from, to int, destinationCIDRs ...string) EgressRule {
+	rule, err := NewEgressRule(protocol, from, to, destinationCIDRs...)
+	if err != nil {
+		panic(err)
+	}
+	return rule
+}
+
+// String is the string representation of EgressRule.
+func (r EgressRule) String() string {
+	destination := ""
+	to := strings.Join(r.DestinationCIDRs, ",")
+	if to != "" && to != "0.0.0.0/0" {
+		destination = " to " + to
+	}
+	if r.FromPort == r.ToPort {
+		return fmt.Sprintf("%d/%s%s", r.FromPort, strings.ToLower(r.Protocol), destination)
+	}
+	return fmt.Sprintf("%d-%d/%s%s", r.FromPort, r.ToPort, strings.ToLower(r.Protocol), destination)
+}
+
+// GoString is used to print values passed as an operand to a %#v format.
+func (r EgressRule) GoString() string {
+	return r.String()
+}
+
+type EgressRuleSlice []EgressRule
+
+func (p EgressRuleSlice) Len() int      { return len(p) }
+func (p EgressRuleSlice) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+func (p EgressRuleSlice) Less(i, j int) bool {
+	p1 := p[i]
+	p2 := p[j]
+	if p1.Protocol != p2.Protocol {
+		return p1.Protocol < p2.Protocol
+	}
+	if p1.FromPort != p2.FromPort {
+		return p1.FromPort < p2.FromPort
+	}
+	if p1.ToPort != p2.ToPort {
+		return p1.ToPort < p2.ToPort
+	}
+	d1 := strings.Join(p1.DestinationCIDRs, ",")
+	d2 := strings.Join(p2.DestinationCIDRs, ",")
+	return d1 < d2
+}
+
+// SortEgressRules sorts the given rules, first by protocol, then by ports.
+func SortEgressRules(egressRules []EgressRule) {
+	sort.Sort(EgressRuleSlice(egressRules))
+}
@@ -68,7 +68,40 @@ func (*FirewallSuite) TestNewIngressRule(c *gc.C) {
 	c.Assert(rule.SourceCIDRs, jc.DeepEquals, []string{"0.0.0.0/0", "192.168.1.0/24"})
 }
 
+func (*FirewallSuite) TestNewIngressRuleIPv6(c *gc.C) {
+	rule, err := network.NewIngressRule("tcp", 80, 100, "192.168.1.0/24", "2001:DB8:0::/32", "::/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rule.SourceCIDRs, jc.DeepEquals, []string{"192.168.1.0/24", "2001:db8::/32", "::/0"})
+	c.Assert(rule.String(), gc.Equals, "80-100/tcp from 192.168.1.0/24,2001:db8::/32,::/0")
+}
+
 func (*FirewallSuite) TestNewIngressRuleBadCIDR(c *gc.C) {
 	_, err := network.NewIngressRule("tcp", 80, 100, "0.0.0.0/0", "192.168.0/24")
 	c.Assert(err, gc.ErrorMatches, "invalid CIDR address: 192.168.0/24")
 }
+
+func (*FirewallSuite) TestEgressRuleStrings(c *gc.C) {
+	rule := network.MustNewEgressRule("tcp", 443, 443)
+	c.Assert(rule.String(), gc.Equals, "443/tcp")
+	c.Assert(rule.GoString(), gc.Equals, "443/tcp")
+
+	rule = network.MustNewEgressRule("udp", 5000, 5010, "10.0.0.0/8", "2001:DB8::/32")
+	c.Assert(rule.DestinationCIDRs, jc.DeepEquals, []string{"10.0.0.0/8", "2001:db8::/32"})
+	c.Assert(rule.String(), gc.Equals, "5000-5010/udp to 10.0.0.0/8,2001:db8::/32")
+}
+
+func (*FirewallSuite) TestSortEgressRules(c *gc.C) {
+	rule1 := network.MustNewEgressRule("udp", 53, 53)
+	rule2 := network.MustNewEgressRule("tcp", 443, 443, "10.0.0.0/8")
+	rule3 := network.MustNewEgressRule("tcp", 443, 443)
+	rule4 := network.MustNewEgressRule("tcp", 80, 80)
+
+	rules := []network.EgressRule{rule1, rule2, rule3, rule4}
+	network.SortEgressRules(rules)
+	c.Assert(rules, gc.DeepEquals, []network.EgressRule{rule4, rule3, rule2, rule1})
+}
+
+func (*FirewallSuite) TestNewEgressRuleBadCIDR(c *gc.C) {
+	_, err := network.NewEgressRule("tcp", 80, 100, "10.0.0/8")
+	c.Assert(err, gc.ErrorMatches, "invalid CIDR address: 10.0.0/8")
+}
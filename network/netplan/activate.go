@@ -74,6 +74,9 @@ func BridgeAndActivate(params ActivationParams) (*ActivationResult, error) {
 		default:
 			return nil, errors.Errorf("unable to create bridge for %q, unknown device type %q", deviceId, deviceType)
 		}
+		if err := netplan.ConfigureBridge(device.BridgeName, device.MTU, device.OpenvSwitch); err != nil {
+			return nil, errors.Trace(err)
+		}
 	}
 	_, err = netplan.Write("")
 	if err != nil {
@@ -77,10 +77,22 @@ type BridgeParameters struct {
 	STP          *bool          `yaml:"stp,omitempty"`
 }
 
+// OpenvSwitch defines the Open vSwitch settings of a device. A device
+// with (possibly empty) settings is managed by Open vSwitch.
+type OpenvSwitch struct {
+	ExternalIDs   map[string]string `yaml:"external-ids,omitempty"`
+	OtherConfig   map[string]string `yaml:"other-config,omitempty"`
+	FailMode      string            `yaml:"fail-mode,omitempty"`
+	MCastSnooping *bool             `yaml:"mcast-snooping,omitempty"`
+	RSTP          *bool             `yaml:"rstp,omitempty"`
+	Protocols     []string          `yaml:"protocols,omitempty,flow"`
+}
+
 type Bridge struct {
-	Interfaces []string `yaml:"interfaces,omitempty,flow"`
-	Interface  `yaml:",inline"`
-	Parameters BridgeParameters `yaml:"parameters,omitempty"`
+	Interfaces  []string `yaml:"interfaces,omitempty,flow"`
+	Interface   `yaml:",inline"`
+	Parameters  BridgeParameters `yaml:"parameters,omitempty"`
+	OpenvSwitch *OpenvSwitch     `yaml:"openvswitch,omitempty"`
 }
 
 type Route struct {
@@ -250,6 +262,23 @@ func (np *Netplan) BridgeBondById(deviceId string, bridgeName string) (err error
 	return nil
 }
 
+// ConfigureBridge sets the MTU of the named bridge, if mtu is not zero,
+// and makes it an Open vSwitch bridge if openvSwitch is true.
+func (np *Netplan) ConfigureBridge(bridgeName string, mtu int, openvSwitch bool) error {
+	bridge, ok := np.Network.Bridges[bridgeName]
+	if !ok {
+		return errors.NotFoundf("bridge %q", bridgeName)
+	}
+	if mtu != 0 {
+		bridge.MTU = mtu
+	}
+	if openvSwitch && bridge.OpenvSwitch == nil {
+		bridge.OpenvSwitch = &OpenvSwitch{}
+	}
+	np.Network.Bridges[bridgeName] = bridge
+	return nil
+}
+
 // shouldCreateBridge returns true only if it is clear the bridge doesn't already exist, and that the existing device
 // isn't in a different bridge.
 func (np *Netplan) shouldCreateBridge(deviceId string, bridgeName string) (bool, error) {
@@ -550,6 +550,56 @@ network:
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *NetplanSuite) TestConfigureBridge(c *gc.C) {
+	np := MustNetplanFromYaml(c, `
+network:
+  version: 2
+  ethernets:
+    id0:
+      match:
+        macaddress: "00:11:22:33:44:55"
+      addresses:
+      - 1.2.3.4/24
+`)
+	expected := `
+network:
+  version: 2
+  ethernets:
+    id0:
+      match:
+        macaddress: "00:11:22:33:44:55"
+  bridges:
+    juju-bridge:
+      interfaces: [id0]
+      addresses:
+      - 1.2.3.4/24
+      mtu: 9000
+      openvswitch: {}
+`[1:]
+	err := np.BridgeEthernetById("id0", "juju-bridge")
+	c.Assert(err, jc.ErrorIsNil)
+	err = np.ConfigureBridge("juju-bridge", 9000, true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	out, err := netplan.Marshal(np)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(out), gc.Equals, expected)
+	checkNetplanRoundTrips(c, expected)
+}
+
+func (s *NetplanSuite) TestConfigureBridgeMissing(c *gc.C) {
+	np := MustNetplanFromYaml(c, `
+network:
+  version: 2
+  ethernets:
+    id0:
+      dhcp4: true
+`)
+	err := np.ConfigureBridge("juju-bridge", 9000, false)
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+	c.Check(err, gc.ErrorMatches, `bridge "juju-bridge" not found`)
+}
+
 func (s *NetplanSuite) TestBridgerBridgeExists(c *gc.C) {
 	np := MustNetplanFromYaml(c, `
 network:
@@ -364,6 +364,14 @@ type DeviceToBridge struct {
 
 	// MACAddress is the MAC address of the device to be bridged
 	MACAddress string
+
+	// MTU is the MTU of the bridge. If zero, the bridge has the MTU of
+	// the device.
+	MTU int
+
+	// OpenvSwitch is true if the bridge should be an Open vSwitch bridge
+	// rather than a Linux bridge.
+	OpenvSwitch bool
 }
 
 // LXCNetDefaultConfig is the location of the default network config
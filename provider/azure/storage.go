@@ -545,6 +545,91 @@ func (v *azureVolumeSource) ValidateVolumeParams(params storage.VolumeParams) er
 	return nil
 }
 
+// ImportVolume is part of the storage.VolumeImporter interface.
+//
+// Only managed disks in the model's resource group may be imported.
+// The disk must be unattached, in the model's location, not pinned to
+// an availability zone, and no larger than the maximum disk size.
+func (v *azureVolumeSource) ImportVolume(ctx context.ProviderCallContext, volumeId string, resourceTags map[string]string) (storage.VolumeInfo, error) {
+	if v.maybeStorageClient != nil {
+		return storage.VolumeInfo{}, errors.NotSupportedf("importing volumes into a model with unmanaged disks")
+	}
+	diskClient := compute.DisksClient{v.env.disk}
+	sdkCtx := stdcontext.Background()
+	disk, err := diskClient.Get(sdkCtx, v.env.resourceGroup, volumeId)
+	if err != nil {
+		if isNotFoundResult(disk.Response) {
+			return storage.VolumeInfo{}, errors.NotFoundf("disk %s", volumeId)
+		}
+		return storage.VolumeInfo{}, errorutils.HandleCredentialError(errors.Annotatef(err, "getting disk %q", volumeId), ctx)
+	}
+	if err := validateImportDisk(volumeId, disk, v.env.location); err != nil {
+		return storage.VolumeInfo{}, errors.Trace(err)
+	}
+
+	// Updating tags replaces all of them, so the disk's
+	// existing tags must be included.
+	diskTags := make(map[string]*string)
+	for key, value := range disk.Tags {
+		diskTags[key] = value
+	}
+	for key, value := range resourceTags {
+		diskTags[key] = to.StringPtr(value)
+	}
+	future, err := diskClient.Update(sdkCtx, v.env.resourceGroup, volumeId, compute.DiskUpdate{Tags: diskTags})
+	if err != nil {
+		return storage.VolumeInfo{}, errorutils.HandleCredentialError(errors.Annotatef(err, "tagging disk %q", volumeId), ctx)
+	}
+	err = future.WaitForCompletionRef(sdkCtx, diskClient.Client)
+	if err != nil {
+		return storage.VolumeInfo{}, errorutils.HandleCredentialError(errors.Annotatef(err, "tagging disk %q", volumeId), ctx)
+	}
+	if _, err := future.Result(diskClient); err != nil {
+		return storage.VolumeInfo{}, errors.Annotatef(err, "tagging disk %q", volumeId)
+	}
+	return storage.VolumeInfo{
+		VolumeId:   volumeId,
+		Size:       gibToMib(uint64(to.Int32(disk.DiskSizeGB))),
+		Persistent: true,
+	}, nil
+}
+
+// validateImportDisk returns an error if the given managed disk cannot
+// be imported into a model in the given location.
+func validateImportDisk(volumeId string, disk compute.Disk, location string) error {
+	if managedBy := to.String(disk.ManagedBy); managedBy != "" {
+		return errors.Errorf("cannot import disk %q attached to %q", volumeId, managedBy)
+	}
+	if diskLocation := canonicalLocation(to.String(disk.Location)); diskLocation != location {
+		return errors.Errorf(
+			"cannot import disk %q in location %q into a model in location %q",
+			volumeId, diskLocation, location,
+		)
+	}
+	// Machines are placed in availability sets rather than zones, and
+	// a zonal disk can only be attached to a machine in the same zone.
+	if disk.Zones != nil && len(*disk.Zones) > 0 {
+		return errors.Errorf(
+			"cannot import disk %q in availability zone %q",
+			volumeId, strings.Join(*disk.Zones, ","),
+		)
+	}
+	var sizeInGib int32
+	if disk.DiskProperties != nil {
+		sizeInGib = to.Int32(disk.DiskSizeGB)
+	}
+	if sizeInGib <= 0 {
+		return errors.Errorf("cannot import disk %q with unknown size", volumeId)
+	}
+	if sizeInGib > volumeSizeMaxGiB {
+		return errors.Errorf(
+			"cannot import disk %q: %d GiB exceeds the maximum of %d GiB",
+			volumeId, sizeInGib, volumeSizeMaxGiB,
+		)
+	}
+	return nil
+}
+
 // AttachVolumes is specified on the storage.VolumeSource interface.
 func (v *azureVolumeSource) AttachVolumes(ctx context.ProviderCallContext, attachParams []storage.VolumeAttachmentParams) ([]storage.AttachVolumesResult, error) {
 	results := make([]storage.AttachVolumesResult, len(attachParams))
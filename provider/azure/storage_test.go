@@ -645,6 +645,108 @@ func (s *storageSuite) TestDescribeVolumesNotFound(c *gc.C) {
 	c.Assert(results[0].Error, gc.ErrorMatches, `disk volume-42 not found`)
 }
 
+func (s *storageSuite) TestImportVolume(c *gc.C) {
+	volumeSource := s.volumeSource(c, false)
+	c.Assert(volumeSource, gc.Implements, new(storage.VolumeImporter))
+
+	makeSender := func() *azuretesting.MockSender {
+		sender := azuretesting.NewSenderWithValue(&compute.Disk{
+			Name:     to.StringPtr("pgdata"),
+			Location: to.StringPtr("West US"),
+			Tags:     map[string]*string{"owner": to.StringPtr("dba")},
+			DiskProperties: &compute.DiskProperties{
+				DiskSizeGB: to.Int32Ptr(32),
+			},
+		})
+		sender.PathPattern = `.*/Microsoft\.Compute/disks/pgdata`
+		return sender
+	}
+	s.requests = nil
+	s.sender = azuretesting.Senders{
+		makeSender(),
+		makeSender(),
+		makeSender(), // future.Result call
+	}
+
+	info, err := volumeSource.(storage.VolumeImporter).ImportVolume(
+		s.cloudCallCtx, "pgdata", map[string]string{"juju-model-uuid": "foo"},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info, jc.DeepEquals, storage.VolumeInfo{
+		VolumeId:   "pgdata",
+		Size:       32 * 1024,
+		Persistent: true,
+	})
+
+	c.Assert(s.requests, gc.HasLen, 3)
+	c.Assert(s.requests[0].Method, gc.Equals, "GET")
+	c.Assert(s.requests[1].Method, gc.Equals, "PATCH")
+	c.Assert(s.requests[2].Method, gc.Equals, "GET") // future.Result call
+	assertRequestBody(c, s.requests[1], &compute.DiskUpdate{
+		Tags: map[string]*string{
+			"owner":           to.StringPtr("dba"),
+			"juju-model-uuid": to.StringPtr("foo"),
+		},
+	})
+}
+
+func (s *storageSuite) TestImportVolumeIncompatible(c *gc.C) {
+	volumeSource := s.volumeSource(c, false)
+
+	for i, test := range []struct {
+		disk   compute.Disk
+		expect string
+	}{{
+		disk: compute.Disk{
+			ManagedBy:      to.StringPtr("machine-0"),
+			Location:       to.StringPtr("westus"),
+			DiskProperties: &compute.DiskProperties{DiskSizeGB: to.Int32Ptr(32)},
+		},
+		expect: `cannot import disk "pgdata" attached to "machine-0"`,
+	}, {
+		disk: compute.Disk{
+			Location:       to.StringPtr("northeurope"),
+			DiskProperties: &compute.DiskProperties{DiskSizeGB: to.Int32Ptr(32)},
+		},
+		expect: `cannot import disk "pgdata" in location "northeurope" into a model in location "westus"`,
+	}, {
+		disk: compute.Disk{
+			Location:       to.StringPtr("westus"),
+			Zones:          to.StringSlicePtr([]string{"2"}),
+			DiskProperties: &compute.DiskProperties{DiskSizeGB: to.Int32Ptr(32)},
+		},
+		expect: `cannot import disk "pgdata" in availability zone "2"`,
+	}, {
+		disk: compute.Disk{
+			Location: to.StringPtr("westus"),
+		},
+		expect: `cannot import disk "pgdata" with unknown size`,
+	}, {
+		disk: compute.Disk{
+			Location:       to.StringPtr("westus"),
+			DiskProperties: &compute.DiskProperties{DiskSizeGB: to.Int32Ptr(2048)},
+		},
+		expect: `cannot import disk "pgdata": 2048 GiB exceeds the maximum of 1023 GiB`,
+	}} {
+		c.Logf("test %d", i)
+		sender := azuretesting.NewSenderWithValue(&test.disk)
+		sender.PathPattern = `.*/Microsoft\.Compute/disks/pgdata`
+		s.requests = nil
+		s.sender = azuretesting.Senders{sender}
+
+		_, err := volumeSource.(storage.VolumeImporter).ImportVolume(s.cloudCallCtx, "pgdata", nil)
+		c.Check(err, gc.ErrorMatches, test.expect)
+		// The disk must not be tagged.
+		c.Check(s.requests, gc.HasLen, 1)
+	}
+}
+
+func (s *storageSuite) TestImportVolumeLegacy(c *gc.C) {
+	volumeSource := s.volumeSource(c, true)
+	_, err := volumeSource.(storage.VolumeImporter).ImportVolume(s.cloudCallCtx, "volume-0", nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 func (s *storageSuite) TestDescribeVolumesLegacy(c *gc.C) {
 	blob0 := &azuretesting.MockStorageBlob{
 		Name_: "volume-0.vhd",
@@ -227,6 +227,20 @@ type OpClosePorts struct {
 	Rules      []network.IngressRule
 }
 
+type OpOpenEgress struct {
+	Env        string
+	MachineId  string
+	InstanceId instance.Id
+	Rules      []network.EgressRule
+}
+
+type OpCloseEgress struct {
+	Env        string
+	MachineId  string
+	InstanceId instance.Id
+	Rules      []network.EgressRule
+}
+
 type OpPutFile struct {
 	Env      string
 	FileName string
@@ -1709,6 +1723,7 @@ func (*environ) Provider() environs.EnvironProvider {
 type dummyInstance struct {
 	state        *environState
 	rules        network.IngressRuleSlice
+	egressRules  network.EgressRuleSlice
 	id           instance.Id
 	status       string
 	machineId    string
@@ -1886,6 +1901,94 @@ func (inst *dummyInstance) IngressRules(ctx context.ProviderCallContext, machine
 	return
 }
 
+func (inst *dummyInstance) OpenEgress(ctx context.ProviderCallContext, machineId string, rules []network.EgressRule) error {
+	defer delay()
+	logger.Infof("openEgress %s, %#v", machineId, rules)
+	if inst.firewallMode != config.FwInstance {
+		return fmt.Errorf("invalid firewall mode %q for opening egress on instance",
+			inst.firewallMode)
+	}
+	if inst.machineId != machineId {
+		panic(fmt.Errorf("OpenEgress with mismatched machine id, expected %q got %q", inst.machineId, machineId))
+	}
+	inst.state.mu.Lock()
+	defer inst.state.mu.Unlock()
+	if err := inst.checkBroken("OpenEgress"); err != nil {
+		return err
+	}
+	inst.state.ops <- OpOpenEgress{
+		Env:        inst.state.name,
+		MachineId:  machineId,
+		InstanceId: inst.Id(),
+		Rules:      rules,
+	}
+	for _, r := range rules {
+		found := false
+		for _, rule := range inst.egressRules {
+			if r.String() == rule.String() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			inst.egressRules = append(inst.egressRules, r)
+		}
+	}
+	return nil
+}
+
+func (inst *dummyInstance) CloseEgress(ctx context.ProviderCallContext, machineId string, rules []network.EgressRule) error {
+	defer delay()
+	if inst.firewallMode != config.FwInstance {
+		return fmt.Errorf("invalid firewall mode %q for closing egress on instance",
+			inst.firewallMode)
+	}
+	if inst.machineId != machineId {
+		panic(fmt.Errorf("CloseEgress with mismatched machine id, expected %s got %s", inst.machineId, machineId))
+	}
+	inst.state.mu.Lock()
+	defer inst.state.mu.Unlock()
+	if err := inst.checkBroken("CloseEgress"); err != nil {
+		return err
+	}
+	inst.state.ops <- OpCloseEgress{
+		Env:        inst.state.name,
+		MachineId:  machineId,
+		InstanceId: inst.Id(),
+		Rules:      rules,
+	}
+	for _, r := range rules {
+		for i, rule := range inst.egressRules {
+			if r.String() == rule.String() {
+				inst.egressRules = inst.egressRules[:i+copy(inst.egressRules[i:], inst.egressRules[i+1:])]
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func (inst *dummyInstance) EgressRules(ctx context.ProviderCallContext, machineId string) (rules []network.EgressRule, err error) {
+	defer delay()
+	if inst.firewallMode != config.FwInstance {
+		return nil, fmt.Errorf("invalid firewall mode %q for retrieving egress rules from instance",
+			inst.firewallMode)
+	}
+	if inst.machineId != machineId {
+		panic(fmt.Errorf("EgressRules with mismatched machine id, expected %q got %q", inst.machineId, machineId))
+	}
+	inst.state.mu.Lock()
+	defer inst.state.mu.Unlock()
+	if err := inst.checkBroken("EgressRules"); err != nil {
+		return nil, err
+	}
+	for _, r := range inst.egressRules {
+		rules = append(rules, r)
+	}
+	network.SortEgressRules(rules)
+	return
+}
+
 // providerDelay controls the delay before dummy responds.
 // non empty values in JUJU_DUMMY_DELAY will be parsed as
 // time.Durations into this value.
@@ -26,6 +26,11 @@ var configSchema = environschema.Fields{
 		Group:       environschema.AccountGroup,
 		Immutable:   true,
 	},
+	"instance-identity-certificate": {
+		Description: "The PEM encoded AWS public certificate for the model's region, used to verify instance identity documents when machine-enrollment is instance-identity.",
+		Type:        environschema.Tstring,
+		Group:       environschema.AccountGroup,
+	},
 }
 
 var configFields = func() schema.Fields {
@@ -37,8 +42,9 @@ var configFields = func() schema.Fields {
 }()
 
 var configDefaults = schema.Defaults{
-	"vpc-id":       "",
-	"vpc-id-force": false,
+	"vpc-id":                        "",
+	"vpc-id-force":                  false,
+	"instance-identity-certificate": "",
 }
 
 type environConfig struct {
@@ -54,6 +60,10 @@ func (c *environConfig) forceVPCID() bool {
 	return c.attrs["vpc-id-force"].(bool)
 }
 
+func (c *environConfig) instanceIdentityCertificate() string {
+	return c.attrs["instance-identity-certificate"].(string)
+}
+
 func (p environProvider) newConfig(cfg *config.Config) (*environConfig, error) {
 	valid, err := p.Validate(cfg, nil)
 	if err != nil {
@@ -100,6 +110,12 @@ func validateConfig(cfg, old *config.Config) (*environConfig, error) {
 		return nil, fmt.Errorf("cannot use vpc-id-force without specifying vpc-id as well")
 	}
 
+	if certPEM := ecfg.instanceIdentityCertificate(); certPEM != "" {
+		if _, err := parseCertificate(certPEM); err != nil {
+			return nil, fmt.Errorf("instance-identity-certificate: %v", err)
+		}
+	}
+
 	if old != nil {
 		attrs := old.UnknownAttrs()
 
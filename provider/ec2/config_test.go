@@ -237,6 +237,18 @@ var configTests = []configTest{
 		change:     attrs{},
 		vpcID:      "vpc-foo",
 		forceVPCID: true,
+	}, {
+		config: attrs{
+			"instance-identity-certificate": "not a certificate",
+		},
+		err: `.*instance-identity-certificate: no PEM encoded certificate found`,
+	}, {
+		config: attrs{
+			"instance-identity-certificate": testing.CACert,
+		},
+		expect: attrs{
+			"instance-identity-certificate": testing.CACert,
+		},
 	}, {
 		config:       attrs{},
 		firewallMode: config.FwInstance,
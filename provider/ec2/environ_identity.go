@@ -0,0 +1,80 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/environs/instanceidentity"
+)
+
+var _ environs.InstanceIdentityVerifier = (*environ)(nil)
+
+// VerifyInstanceIdentity implements environs.InstanceIdentityVerifier.
+// The document is the JSON encoding of an instanceidentity.EC2Document,
+// whose signature is checked with the AWS certificate configured with
+// instance-identity-certificate. EC2 identity documents are not issued
+// for an audience, so the audience is not checked; instead the agent
+// authenticator binds each document to the nonce it is first presented
+// with, and accepts it only once.
+func (e *environ) VerifyInstanceIdentity(ctx context.ProviderCallContext, document, audience string) (instance.Id, error) {
+	certPEM := e.ecfg().instanceIdentityCertificate()
+	if certPEM == "" {
+		return "", errors.New("no instance-identity-certificate configured")
+	}
+	cert, err := parseCertificate(certPEM)
+	if err != nil {
+		return "", errors.Annotate(err, "parsing instance-identity-certificate")
+	}
+
+	var doc instanceidentity.EC2Document
+	if err := json.Unmarshal([]byte(document), &doc); err != nil {
+		return "", errors.Annotate(err, "parsing identity document")
+	}
+	// The metadata service wraps the signature over several lines.
+	signature, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(doc.Signature), ""))
+	if err != nil {
+		return "", errors.Annotate(err, "decoding identity document signature")
+	}
+	if err := cert.CheckSignature(x509.SHA256WithRSA, []byte(doc.Document), signature); err != nil {
+		return "", errors.Annotate(err, "verifying identity document signature")
+	}
+
+	var identity struct {
+		InstanceId string `json:"instanceId"`
+		Region     string `json:"region"`
+	}
+	if err := json.Unmarshal([]byte(doc.Document), &identity); err != nil {
+		return "", errors.Annotate(err, "parsing identity document")
+	}
+	if identity.Region != e.cloud.Region {
+		return "", errors.Errorf("identity document issued to instance in region %q", identity.Region)
+	}
+	if identity.InstanceId == "" {
+		return "", errors.New("identity document has no instance id")
+	}
+	return instance.Id(identity.InstanceId), nil
+}
+
+// parseCertificate parses a single PEM encoded certificate.
+func parseCertificate(certPEM string) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(certPEM))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("no PEM encoded certificate found")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return cert, nil
+}
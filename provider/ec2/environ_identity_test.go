@@ -0,0 +1,101 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/environs/instanceidentity"
+	"github.com/juju/juju/testing"
+)
+
+type identitySuite struct {
+	testing.BaseSuite
+	callCtx context.ProviderCallContext
+}
+
+var _ = gc.Suite(&identitySuite{})
+
+func (s *identitySuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.callCtx = context.NewCloudCallContext()
+}
+
+func (s *identitySuite) newEnviron(c *gc.C, cert string) *environ {
+	credential := cloud.NewCredential(
+		cloud.AccessKeyAuthType,
+		map[string]string{
+			"access-key": "x",
+			"secret-key": "y",
+		},
+	)
+	attrs := testing.FakeConfig().Merge(testing.Attrs{
+		"type":                          "ec2",
+		"instance-identity-certificate": cert,
+	})
+	cfg, err := config.New(config.NoDefaults, attrs)
+	c.Assert(err, jc.ErrorIsNil)
+	env, err := environs.New(environs.OpenParams{
+		Cloud: environs.CloudSpec{
+			Type:       "ec2",
+			Name:       "ec2test",
+			Region:     "us-east-1",
+			Credential: &credential,
+		},
+		Config: cfg,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return env.(*environ)
+}
+
+func (s *identitySuite) document(c *gc.C, key *rsa.PrivateKey, region string) string {
+	document := `{"instanceId":"i-0123456789","region":"` + region + `"}`
+	digest := sha256.Sum256([]byte(document))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := json.Marshal(instanceidentity.EC2Document{
+		Document:  document,
+		Signature: base64.StdEncoding.EncodeToString(signature),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return string(data)
+}
+
+func (s *identitySuite) TestVerifyInstanceIdentity(c *gc.C) {
+	env := s.newEnviron(c, testing.CACert)
+	instId, err := env.VerifyInstanceIdentity(s.callCtx, s.document(c, testing.CAKeyRSA, "us-east-1"), "ignored")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instId, gc.Equals, instance.Id("i-0123456789"))
+}
+
+func (s *identitySuite) TestVerifyInstanceIdentityBadSignature(c *gc.C) {
+	env := s.newEnviron(c, testing.CACert)
+	_, err := env.VerifyInstanceIdentity(s.callCtx, s.document(c, testing.OtherCAKeyRSA, "us-east-1"), "ignored")
+	c.Assert(err, gc.ErrorMatches, "verifying identity document signature: .*")
+}
+
+func (s *identitySuite) TestVerifyInstanceIdentityOtherRegion(c *gc.C) {
+	env := s.newEnviron(c, testing.CACert)
+	_, err := env.VerifyInstanceIdentity(s.callCtx, s.document(c, testing.CAKeyRSA, "eu-west-1"), "ignored")
+	c.Assert(err, gc.ErrorMatches, `identity document issued to instance in region "eu-west-1"`)
+}
+
+func (s *identitySuite) TestVerifyInstanceIdentityNoCertificate(c *gc.C) {
+	env := s.newEnviron(c, "")
+	_, err := env.VerifyInstanceIdentity(s.callCtx, s.document(c, testing.CAKeyRSA, "us-east-1"), "ignored")
+	c.Assert(err, gc.ErrorMatches, "no instance-identity-certificate configured")
+}
@@ -72,10 +72,11 @@ type gceConnection interface {
 }
 
 type environ struct {
-	name  string
-	uuid  string
-	cloud environs.CloudSpec
-	gce   gceConnection
+	name      string
+	uuid      string
+	cloud     environs.CloudSpec
+	gce       gceConnection
+	projectID string
 
 	lock sync.Mutex // lock protects access to ecfg
 	ecfg *environConfig
@@ -86,6 +87,7 @@ type environ struct {
 
 var _ environs.Environ = (*environ)(nil)
 var _ environs.NetworkingEnviron = (*environ)(nil)
+var _ environs.InstanceIdentityVerifier = (*environ)(nil)
 
 // Function entry points defined as variables so they can be overridden
 // for testing purposes.
@@ -140,6 +142,7 @@ func newEnviron(cloud environs.CloudSpec, cfg *config.Config) (*environ, error)
 		cloud:     cloud,
 		ecfg:      ecfg,
 		gce:       conn,
+		projectID: credential.ProjectID,
 		namespace: namespace,
 	}, nil
 }
@@ -0,0 +1,105 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package gce
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/environs/context"
+)
+
+var (
+	// googleCertsURL is where the certificates Google signs instance
+	// identity tokens with are published, keyed by key id.
+	googleCertsURL = "https://www.googleapis.com/oauth2/v1/certs"
+
+	googleCertsClient = &http.Client{Timeout: 30 * time.Second}
+)
+
+// googleIssuers holds the issuers of valid instance identity tokens.
+var googleIssuers = []string{"https://accounts.google.com", "accounts.google.com"}
+
+// VerifyInstanceIdentity implements environs.InstanceIdentityVerifier.
+// The document is an identity token issued by the GCE metadata service
+// in the full format, which identifies the instance it was issued to.
+func (env *environ) VerifyInstanceIdentity(ctx context.ProviderCallContext, document, audience string) (instance.Id, error) {
+	keys, err := googleSigningKeys()
+	if err != nil {
+		return "", errors.Annotate(err, "getting Google signing keys")
+	}
+	token, err := jwt.Parse(document, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, errors.Errorf("unexpected signing method %v", token.Header["alg"])
+		}
+		kid, _ := token.Header["kid"].(string)
+		key, ok := keys[kid]
+		if !ok {
+			return nil, errors.Errorf("unknown signing key %q", kid)
+		}
+		return key, nil
+	})
+	if err != nil {
+		return "", errors.Annotate(err, "invalid identity token")
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return "", errors.New("invalid identity token claims")
+	}
+	if !claims.VerifyAudience(audience, true) {
+		return "", errors.Errorf("identity token not issued for %q", audience)
+	}
+	validIssuer := false
+	for _, issuer := range googleIssuers {
+		validIssuer = validIssuer || claims.VerifyIssuer(issuer, true)
+	}
+	if !validIssuer {
+		return "", errors.New("identity token not issued by Google")
+	}
+
+	// Tokens in the full format carry the details of the instance.
+	googleClaims, _ := claims["google"].(map[string]interface{})
+	computeEngine, _ := googleClaims["compute_engine"].(map[string]interface{})
+	projectID, _ := computeEngine["project_id"].(string)
+	instanceName, _ := computeEngine["instance_name"].(string)
+	if projectID != env.projectID {
+		return "", errors.Errorf("identity token issued to instance in project %q", projectID)
+	}
+	if instanceName == "" {
+		return "", errors.New("identity token has no instance details")
+	}
+	return instance.Id(instanceName), nil
+}
+
+// googleSigningKeys returns the public keys Google currently signs
+// identity tokens with, keyed by key id.
+func googleSigningKeys() (map[string]*rsa.PublicKey, error) {
+	resp, err := googleCertsClient.Get(googleCertsURL)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("%s returned %s", googleCertsURL, resp.Status)
+	}
+	var certs map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&certs); err != nil {
+		return nil, errors.Trace(err)
+	}
+	keys := make(map[string]*rsa.PublicKey)
+	for kid, cert := range certs {
+		key, err := jwt.ParseRSAPublicKeyFromPEM([]byte(cert))
+		if err != nil {
+			return nil, errors.Annotatef(err, "parsing certificate %q", kid)
+		}
+		keys[kid] = key
+	}
+	return keys, nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package gce_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/provider/gce"
+)
+
+type environIdentitySuite struct {
+	gce.BaseSuite
+	key *rsa.PrivateKey
+}
+
+var _ = gc.Suite(&environIdentitySuite{})
+
+func (s *environIdentitySuite) SetUpSuite(c *gc.C) {
+	s.BaseSuite.SetUpSuite(c)
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	s.key = key
+}
+
+func (s *environIdentitySuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "google"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &s.key.PublicKey, s.key)
+	c.Assert(err, jc.ErrorIsNil)
+	certs, err := json.Marshal(map[string]string{
+		"key-1": string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(certs)
+	}))
+	s.AddCleanup(func(*gc.C) { server.Close() })
+	s.PatchValue(gce.GoogleCertsURL, server.URL)
+}
+
+func (s *environIdentitySuite) token(c *gc.C, kid, audience, project string) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss": "https://accounts.google.com",
+		"aud": audience,
+		"exp": time.Now().Add(time.Hour).Unix(),
+		"google": map[string]interface{}{
+			"compute_engine": map[string]interface{}{
+				"project_id":    project,
+				"instance_name": "juju-123-machine-1",
+			},
+		},
+	})
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(s.key)
+	c.Assert(err, jc.ErrorIsNil)
+	return signed
+}
+
+func (s *environIdentitySuite) TestVerifyInstanceIdentity(c *gc.C) {
+	document := s.token(c, "key-1", "machine-1:nonce", gce.ProjectID)
+	instId, err := s.Env.VerifyInstanceIdentity(s.CallCtx, document, "machine-1:nonce")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instId, gc.Equals, instance.Id("juju-123-machine-1"))
+}
+
+func (s *environIdentitySuite) TestVerifyInstanceIdentityWrongAudience(c *gc.C) {
+	document := s.token(c, "key-1", "machine-2:nonce", gce.ProjectID)
+	_, err := s.Env.VerifyInstanceIdentity(s.CallCtx, document, "machine-1:nonce")
+	c.Assert(err, gc.ErrorMatches, `identity token not issued for "machine-1:nonce"`)
+}
+
+func (s *environIdentitySuite) TestVerifyInstanceIdentityOtherProject(c *gc.C) {
+	document := s.token(c, "key-1", "machine-1:nonce", "other-project")
+	_, err := s.Env.VerifyInstanceIdentity(s.CallCtx, document, "machine-1:nonce")
+	c.Assert(err, gc.ErrorMatches, `identity token issued to instance in project "other-project"`)
+}
+
+func (s *environIdentitySuite) TestVerifyInstanceIdentityUnknownKey(c *gc.C) {
+	document := s.token(c, "key-2", "machine-1:nonce", gce.ProjectID)
+	_, err := s.Env.VerifyInstanceIdentity(s.CallCtx, document, "machine-1:nonce")
+	c.Assert(err, gc.ErrorMatches, `invalid identity token: unknown signing key "key-2"`)
+}
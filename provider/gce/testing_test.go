@@ -146,8 +146,9 @@ func (s *BaseSuiteUnpatched) Prefix() string {
 
 func (s *BaseSuiteUnpatched) initEnv(c *gc.C) {
 	s.Env = &environ{
-		name:  "google",
-		cloud: MakeTestCloudSpec(),
+		name:      "google",
+		cloud:     MakeTestCloudSpec(),
+		projectID: ProjectID,
 	}
 	cfg := s.NewConfig(c, nil)
 	s.setConfig(c, cfg)
@@ -356,6 +356,23 @@ func (env *environ) LXDProfileNames(containerName string) ([]string, error) {
 	return env.server().GetContainerProfiles(containerName)
 }
 
+// UnusedLXDProfileNames implements environs.LXDProfileCleaner.
+func (env *environ) UnusedLXDProfileNames() ([]string, error) {
+	return env.server().UnusedCharmProfileNames()
+}
+
+// DeleteLXDProfile implements environs.LXDProfileCleaner.
+func (env *environ) DeleteLXDProfile(name string) error {
+	env.profileMutex.Lock()
+	defer env.profileMutex.Unlock()
+	return errors.Trace(env.server().DeleteProfile(name))
+}
+
+// ValidateLXDProfile implements environs.LXDProfileValidator.
+func (env *environ) ValidateLXDProfile(profile lxdprofile.Profile) error {
+	return errors.Trace(env.server().ValidateCharmProfile(profile))
+}
+
 // AssignLXDProfiles implements environs.LXDProfiler.
 func (env *environ) AssignLXDProfiles(instId string, profilesNames []string, profilePosts []lxdprofile.ProfilePost) (current []string, err error) {
 	report := func(err error) ([]string, error) {
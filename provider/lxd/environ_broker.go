@@ -168,6 +168,17 @@ func (env *environ) getImageSources() ([]lxd.ServerSpec, error) {
 	return remotes, nil
 }
 
+// instanceProfiles returns the profiles with which to create an instance
+// hosting units of charms with the given profiles. Those required by the
+// model config are applied after the default profile, and before the
+// model's own profile and the charms' profiles, which may override them.
+func (env *environ) instanceProfiles(charmProfiles []string) []string {
+	profiles := []string{"default"}
+	profiles = append(profiles, env.Config().LXDRequiredProfiles()...)
+	profiles = append(profiles, env.profileName())
+	return append(profiles, charmProfiles...)
+}
+
 // getContainerSpec builds a container spec from the input container image and
 // start-up parameters.
 // Cloud-init config is generated based on the network devices in the default
@@ -181,7 +192,7 @@ func (env *environ) getContainerSpec(
 	}
 	cSpec := lxd.ContainerSpec{
 		Name:     hostname,
-		Profiles: append([]string{"default", env.profileName()}, args.CharmLXDProfiles...),
+		Profiles: env.instanceProfiles(args.CharmLXDProfiles),
 		Image:    image,
 		Config:   make(map[string]string),
 	}
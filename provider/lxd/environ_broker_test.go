@@ -351,6 +351,46 @@ func (s *environBrokerSuite) TestStartInstanceWithCharmLXDProfile(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *environBrokerSuite) TestStartInstanceWithRequiredLXDProfiles(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	svr := lxd.NewMockServer(ctrl)
+
+	// Check that the required profiles come after the default profile and
+	// before the model and charm profiles.
+	check := func(spec containerlxd.ContainerSpec) bool {
+		expected := []string{"default", "site-base", "site-audit", "juju-", "juju-model-test-0"}
+		if len(spec.Profiles) != len(expected) {
+			return false
+		}
+		for i, name := range expected {
+			if spec.Profiles[i] != name {
+				return false
+			}
+		}
+		return true
+	}
+
+	exp := svr.EXPECT()
+	gomock.InOrder(
+		exp.HostArch().Return(arch.AMD64),
+		exp.FindImage("bionic", arch.AMD64, gomock.Any(), true, gomock.Any()).Return(containerlxd.SourcedImage{}, nil),
+		exp.ServerVersion().Return("3.10.0"),
+		exp.GetNICsFromProfile("default").Return(s.defaultProfile.Devices, nil),
+		exp.CreateContainerFromSpec(matchesContainerSpec(check)).Return(&containerlxd.Container{}, nil),
+		exp.HostArch().Return(arch.AMD64),
+	)
+
+	args := s.GetStartInstanceArgs(c, "bionic")
+	args.CharmLXDProfiles = []string{"juju-model-test-0"}
+
+	env := s.NewEnviron(c, svr, map[string]interface{}{
+		"lxd-required-profiles": "site-base,site-audit",
+	})
+	_, err := env.StartInstance(s.callCtx, args)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *environBrokerSuite) TestStartInstanceNoTools(c *gc.C) {
 	ctrl := gomock.NewController(c)
 	defer ctrl.Finish()
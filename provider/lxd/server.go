@@ -20,6 +20,7 @@ import (
 	"github.com/juju/utils"
 
 	"github.com/juju/juju/container/lxd"
+	"github.com/juju/juju/core/lxdprofile"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/utils/proxy"
@@ -56,6 +57,8 @@ type Server interface {
 	HasProfile(string) (bool, error)
 	CreateProfile(post lxdapi.ProfilesPost) (err error)
 	DeleteProfile(string) (err error)
+	UnusedCharmProfileNames() ([]string, error)
+	ValidateCharmProfile(lxdprofile.Profile) error
 	ReplaceOrAddContainerProfile(string, string, string) error
 	UpdateContainerProfiles(name string, profiles []string) error
 	VerifyNetworkDevice(*lxdapi.Profile, string) error
@@ -7,6 +7,7 @@ package lxd
 import (
 	gomock "github.com/golang/mock/gomock"
 	lxd "github.com/juju/juju/container/lxd"
+	lxdprofile "github.com/juju/juju/core/lxdprofile"
 	network "github.com/juju/juju/core/network"
 	environs "github.com/juju/juju/environs"
 	client "github.com/lxc/lxd/client"
@@ -532,6 +533,19 @@ func (mr *MockServerMockRecorder) UpdateContainerConfig(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateContainerConfig", reflect.TypeOf((*MockServer)(nil).UpdateContainerConfig), arg0, arg1)
 }
 
+// UnusedCharmProfileNames mocks base method
+func (m *MockServer) UnusedCharmProfileNames() ([]string, error) {
+	ret := m.ctrl.Call(m, "UnusedCharmProfileNames")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnusedCharmProfileNames indicates an expected call of UnusedCharmProfileNames
+func (mr *MockServerMockRecorder) UnusedCharmProfileNames() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnusedCharmProfileNames", reflect.TypeOf((*MockServer)(nil).UnusedCharmProfileNames))
+}
+
 // UpdateContainerProfiles mocks base method
 func (m *MockServer) UpdateContainerProfiles(arg0 string, arg1 []string) error {
 	ret := m.ctrl.Call(m, "UpdateContainerProfiles", arg0, arg1)
@@ -581,6 +595,18 @@ func (mr *MockServerMockRecorder) UseTargetServer(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseTargetServer", reflect.TypeOf((*MockServer)(nil).UseTargetServer), arg0)
 }
 
+// ValidateCharmProfile mocks base method
+func (m *MockServer) ValidateCharmProfile(arg0 lxdprofile.Profile) error {
+	ret := m.ctrl.Call(m, "ValidateCharmProfile", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateCharmProfile indicates an expected call of ValidateCharmProfile
+func (mr *MockServerMockRecorder) ValidateCharmProfile(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateCharmProfile", reflect.TypeOf((*MockServer)(nil).ValidateCharmProfile), arg0)
+}
+
 // VerifyNetworkDevice mocks base method
 func (m *MockServer) VerifyNetworkDevice(arg0 *api.Profile, arg1 string) error {
 	ret := m.ctrl.Call(m, "VerifyNetworkDevice", arg0, arg1)
@@ -26,6 +26,7 @@ import (
 	"github.com/juju/juju/cloudconfig/cloudinit"
 	"github.com/juju/juju/cloudconfig/instancecfg"
 	"github.com/juju/juju/cloudconfig/providerinit"
+	"github.com/juju/juju/cloudconfig/providerinit/renderers"
 	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/core/instance"
 	corenetwork "github.com/juju/juju/core/network"
@@ -1047,7 +1048,15 @@ func (env *maasEnviron) StartInstance(
 		return nil, common.ZoneIndependentError(err)
 	}
 
-	userdata, err := providerinit.ComposeUserData(args.InstanceConfig, cloudcfg, MAASRenderer{})
+	var renderer renderers.ProviderRenderer = MAASRenderer{}
+	if args.PublishNetworkBoot != nil {
+		renderer = networkBootRenderer{
+			series:         series,
+			authorizedKeys: args.InstanceConfig.AuthorizedKeys,
+			publish:        args.PublishNetworkBoot,
+		}
+	}
+	userdata, err := providerinit.ComposeUserData(args.InstanceConfig, cloudcfg, renderer)
 	if err != nil {
 		return nil, common.ZoneIndependentError(errors.Annotate(
 			err, "could not compose userdata for bootstrap node",
@@ -5,6 +5,7 @@ package maas
 
 import (
 	"github.com/juju/juju/cloudconfig/cloudinit"
+	"github.com/juju/juju/cloudconfig/providerinit/renderers"
 	"github.com/juju/juju/environs"
 )
 
@@ -15,3 +16,11 @@ var (
 func NewCloudinitConfig(env environs.Environ, hostname, series string) (cloudinit.CloudConfig, error) {
 	return env.(*maasEnviron).newCloudinitConfig(hostname, series)
 }
+
+func NewNetworkBootRenderer(series, authorizedKeys string, publish environs.NetworkBootPublishFunc) renderers.ProviderRenderer {
+	return networkBootRenderer{
+		series:         series,
+		authorizedKeys: authorizedKeys,
+		publish:        publish,
+	}
+}
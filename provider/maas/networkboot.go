@@ -0,0 +1,78 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package maas
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	jujuos "github.com/juju/os"
+	"github.com/juju/utils"
+
+	"github.com/juju/juju/cloudconfig/cloudinit"
+	"github.com/juju/juju/environs"
+)
+
+const (
+	// networkBootCACertPath is where the CA certificate with which the
+	// published user-data is verified is written.
+	networkBootCACertPath = "/var/lib/juju/network-boot/ca.pem"
+
+	// networkBootScriptPath is where the published user-data is
+	// written, for as long as it takes to run it.
+	networkBootScriptPath = "/var/lib/juju/network-boot/user-data"
+)
+
+// networkBootRenderer renders the user-data for a node as a script,
+// publishes it through the controller, and hands MAAS user-data which
+// fetches and runs the published script, so that the agent's credentials
+// are not held by MAAS.
+//
+// cloud-init fetches user-data over HTTPS using the system's trusted CAs,
+// which do not include the controller's CA, so the seed's user-data is
+// fetched by a command run by cloud-init, with the controller's CA
+// certificate pinned, rather than by cloud-init itself. The MAAS
+// user-data holds only that certificate, the SSH keys for the node, and
+// the URL of the seed, which can be fetched once.
+type networkBootRenderer struct {
+	series         string
+	authorizedKeys string
+	publish        environs.NetworkBootPublishFunc
+}
+
+// Render is part of the renderers.ProviderRenderer interface. Windows
+// user-data, and user-data for controllers which cannot publish it, is
+// rendered by MAASRenderer.
+func (r networkBootRenderer) Render(cfg cloudinit.CloudConfig, os jujuos.OSType) ([]byte, error) {
+	if os == jujuos.Windows {
+		return MAASRenderer{}.Render(cfg, os)
+	}
+	script, err := cfg.RenderScript()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	seed, err := r.publish([]byte(script))
+	if errors.IsNotSupported(err) {
+		logger.Debugf("cannot publish user-data, passing it to MAAS: %v", err)
+		return MAASRenderer{}.Render(cfg, os)
+	} else if err != nil {
+		return nil, errors.Annotate(err, "publishing user-data")
+	}
+
+	stage, err := cloudinit.New(r.series)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	stage.SetSSHAuthorizedKeys(r.authorizedKeys)
+	stage.AddRunTextFile(networkBootCACertPath, seed.CACert, 0644)
+	stage.AddRunCmd(fmt.Sprintf(
+		"(umask 077 && curl --fail --silent --show-error --retry 10 --cacert %s -o %s %s && bash %s; rm -f %s)",
+		networkBootCACertPath,
+		networkBootScriptPath,
+		utils.ShQuote(seed.URL+"/user-data"),
+		networkBootScriptPath,
+		networkBootScriptPath,
+	))
+	return MAASRenderer{}.Render(stage, os)
+}
@@ -6,7 +6,9 @@ package maas_test
 
 import (
 	"encoding/base64"
+	"strings"
 
+	"github.com/juju/errors"
 	"github.com/juju/os"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
@@ -14,6 +16,7 @@ import (
 
 	"github.com/juju/juju/cloudconfig/cloudinit/cloudinittest"
 	"github.com/juju/juju/cloudconfig/providerinit/renderers"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/provider/maas"
 	"github.com/juju/juju/testing"
 )
@@ -56,3 +59,55 @@ func (s *RenderersSuite) TestMAASUnknownOS(c *gc.C) {
 	c.Assert(result, gc.IsNil)
 	c.Assert(err, gc.ErrorMatches, "Cannot encode userdata for OS: GenericLinux")
 }
+
+func (s *RenderersSuite) TestNetworkBootPublishesScript(c *gc.C) {
+	var published []byte
+	publish := func(userData []byte) (environs.NetworkBootSeed, error) {
+		published = userData
+		return environs.NetworkBootSeed{
+			URL:    "https://controller:17070/model/uuid/network-boot/token",
+			CACert: "ca-cert",
+		}, nil
+	}
+	renderer := maas.NewNetworkBootRenderer("bionic", "ssh-rsa key", publish)
+	cloudcfg := &cloudinittest.CloudConfig{YAML: []byte("secret yaml"), Script: "secret script"}
+
+	result, err := renderer.Render(cloudcfg, os.Ubuntu)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(published), gc.Equals, "secret script")
+
+	gzipped, err := base64.StdEncoding.DecodeString(string(result))
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := utils.Gunzip(gzipped)
+	c.Assert(err, jc.ErrorIsNil)
+	userdata := string(data)
+	c.Assert(strings.Contains(userdata, "secret"), jc.IsFalse)
+	c.Assert(userdata, jc.Contains, "ssh-rsa key")
+	c.Assert(userdata, jc.Contains, "ca-cert")
+	c.Assert(userdata, jc.Contains, "--cacert /var/lib/juju/network-boot/ca.pem")
+	c.Assert(userdata, jc.Contains, "'https://controller:17070/model/uuid/network-boot/token/user-data'")
+}
+
+func (s *RenderersSuite) TestNetworkBootNotSupported(c *gc.C) {
+	publish := func([]byte) (environs.NetworkBootSeed, error) {
+		return environs.NetworkBootSeed{}, errors.NotSupportedf("network boot")
+	}
+	renderer := maas.NewNetworkBootRenderer("bionic", "", publish)
+	cloudcfg := &cloudinittest.CloudConfig{YAML: []byte("yaml")}
+
+	result, err := renderer.Render(cloudcfg, os.Ubuntu)
+	c.Assert(err, jc.ErrorIsNil)
+	expected := base64.StdEncoding.EncodeToString(utils.Gzip(cloudcfg.YAML))
+	c.Assert(string(result), gc.Equals, expected)
+}
+
+func (s *RenderersSuite) TestNetworkBootPublishError(c *gc.C) {
+	publish := func([]byte) (environs.NetworkBootSeed, error) {
+		return environs.NetworkBootSeed{}, errors.New("boom")
+	}
+	renderer := maas.NewNetworkBootRenderer("bionic", "", publish)
+	cloudcfg := &cloudinittest.CloudConfig{}
+
+	_, err := renderer.Render(cloudcfg, os.Ubuntu)
+	c.Assert(err, gc.ErrorMatches, "publishing user-data: boom")
+}
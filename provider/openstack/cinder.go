@@ -93,10 +93,11 @@ func (env *Environ) cinderProvider() (*cinderProvider, error) {
 		return nil, errors.Trace(err)
 	}
 	return &cinderProvider{
-		storageAdapter: storageAdapter,
-		envName:        env.name,
-		modelUUID:      env.uuid,
-		namespace:      env.namespace,
+		storageAdapter:    storageAdapter,
+		envName:           env.name,
+		modelUUID:         env.uuid,
+		namespace:         env.namespace,
+		availabilityZones: env.AvailabilityZones,
 	}, nil
 }
 
@@ -136,12 +137,17 @@ var newOpenstackStorage = func(env *Environ) (OpenstackStorage, error) {
 }
 
 type cinderProvider struct {
-	storageAdapter OpenstackStorage
-	envName        string
-	modelUUID      string
-	namespace      instance.Namespace
+	storageAdapter    OpenstackStorage
+	envName           string
+	modelUUID         string
+	namespace         instance.Namespace
+	availabilityZones availabilityZonesFunc
 }
 
+// availabilityZonesFunc returns the availability zones in which
+// the model's machines may be started.
+type availabilityZonesFunc func(context.ProviderCallContext) ([]common.AvailabilityZone, error)
+
 var _ storage.Provider = (*cinderProvider)(nil)
 
 var cinderAttempt = utils.AttemptStrategy{
@@ -155,10 +161,11 @@ func (p *cinderProvider) VolumeSource(providerConfig *storage.Config) (storage.V
 		return nil, err
 	}
 	source := &cinderVolumeSource{
-		storageAdapter: p.storageAdapter,
-		envName:        p.envName,
-		modelUUID:      p.modelUUID,
-		namespace:      p.namespace,
+		storageAdapter:    p.storageAdapter,
+		envName:           p.envName,
+		modelUUID:         p.modelUUID,
+		namespace:         p.namespace,
+		availabilityZones: p.availabilityZones,
 	}
 	return source, nil
 }
@@ -206,10 +213,11 @@ func (p *cinderProvider) DefaultPools() []*storage.Config {
 }
 
 type cinderVolumeSource struct {
-	storageAdapter OpenstackStorage
-	envName        string // non unique, informational only
-	modelUUID      string
-	namespace      instance.Namespace
+	storageAdapter    OpenstackStorage
+	envName           string // non unique, informational only
+	modelUUID         string
+	namespace         instance.Namespace
+	availabilityZones availabilityZonesFunc // may be nil
 }
 
 var _ storage.VolumeSource = (*cinderVolumeSource)(nil)
@@ -525,6 +533,12 @@ func (s *cinderVolumeSource) ImportVolume(ctx context.ProviderCallContext, volum
 			"cannot import volume %q with status %q", volumeId, volume.Status,
 		)
 	}
+	if volume.Size <= 0 {
+		return storage.VolumeInfo{}, errors.Errorf("cannot import volume %q with unknown size", volumeId)
+	}
+	if err := s.validateImportZone(ctx, volume); err != nil {
+		return storage.VolumeInfo{}, errors.Trace(err)
+	}
 	if _, err := s.storageAdapter.SetVolumeMetadata(volumeId, resourceTags); err != nil {
 		common.HandleCredentialError(IsAuthorisationFailure, err, ctx)
 		return storage.VolumeInfo{}, errors.Annotatef(err, "tagging volume %q", volumeId)
@@ -532,6 +546,37 @@ func (s *cinderVolumeSource) ImportVolume(ctx context.ProviderCallContext, volum
 	return cinderToJujuVolumeInfo(volume), nil
 }
 
+// validateImportZone returns an error if the volume is in an availability
+// zone in which the model's machines cannot be started, as the volume
+// could then never be attached.
+func (s *cinderVolumeSource) validateImportZone(ctx context.ProviderCallContext, volume *cinder.Volume) error {
+	if volume.AvailabilityZone == "" || s.availabilityZones == nil {
+		return nil
+	}
+	zones, err := s.availabilityZones(ctx)
+	if errors.IsNotImplemented(err) {
+		return nil
+	} else if err != nil {
+		return errors.Annotate(err, "getting availability zones")
+	}
+	for _, zone := range zones {
+		if zone.Name() != volume.AvailabilityZone {
+			continue
+		}
+		if !zone.Available() {
+			return errors.Errorf(
+				"cannot import volume %q in unavailable availability zone %q",
+				volume.ID, volume.AvailabilityZone,
+			)
+		}
+		return nil
+	}
+	return errors.Errorf(
+		"cannot import volume %q in unknown availability zone %q",
+		volume.ID, volume.AvailabilityZone,
+	)
+}
+
 func waitVolume(
 	storageAdapter OpenstackStorage,
 	volumeId string,
@@ -732,6 +732,48 @@ func (s *cinderVolumeSourceSuite) TestImportVolumeInUse(c *gc.C) {
 	})
 }
 
+func (s *cinderVolumeSourceSuite) TestImportVolumeAvailabilityZone(c *gc.C) {
+	zones := []nova.AvailabilityZone{{
+		Name:  "zone-1",
+		State: nova.AvailabilityZoneState{Available: true},
+	}, {
+		Name: "zone-2",
+	}}
+	for i, test := range []struct {
+		zone   string
+		expect string
+	}{{
+		zone: "zone-1",
+	}, {
+		zone:   "zone-2",
+		expect: `cannot import volume "0" in unavailable availability zone "zone-2"`,
+	}, {
+		zone:   "zone-3",
+		expect: `cannot import volume "0" in unknown availability zone "zone-3"`,
+	}} {
+		c.Logf("test %d: %s", i, test.zone)
+		mockAdapter := &mockAdapter{
+			getVolume: func(volumeId string) (*cinder.Volume, error) {
+				return &cinder.Volume{
+					ID:               volumeId,
+					Size:             mockVolSize / 1024,
+					Status:           "available",
+					AvailabilityZone: test.zone,
+				}, nil
+			},
+		}
+		volSource := openstack.NewCinderVolumeSourceWithZones(mockAdapter, zones)
+		_, err := volSource.(storage.VolumeImporter).ImportVolume(s.callCtx, mockVolId, nil)
+		if test.expect == "" {
+			c.Check(err, jc.ErrorIsNil)
+			mockAdapter.CheckCallNames(c, "GetVolume", "SetVolumeMetadata")
+		} else {
+			c.Check(err, gc.ErrorMatches, test.expect)
+			mockAdapter.CheckCallNames(c, "GetVolume")
+		}
+	}
+}
+
 func (s *cinderVolumeSourceSuite) TestImportVolumeInvalidCredential(c *gc.C) {
 	c.Assert(s.invalidCredential, jc.IsFalse)
 	mockAdapter := &mockAdapter{
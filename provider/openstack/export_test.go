@@ -16,6 +16,7 @@ import (
 	"github.com/juju/juju/environs/context"
 	"github.com/juju/juju/environs/instances"
 	envstorage "github.com/juju/juju/environs/storage"
+	"github.com/juju/juju/provider/common"
 	"github.com/juju/juju/storage"
 	"github.com/juju/juju/testing"
 )
@@ -54,6 +55,20 @@ func NewCinderVolumeSourceForModel(s OpenstackStorage, modelUUID string) storage
 	}
 }
 
+// NewCinderVolumeSourceWithZones returns a cinder volume source for a
+// model whose machines may be started in the given availability zones.
+func NewCinderVolumeSourceWithZones(s OpenstackStorage, zones []nova.AvailabilityZone) storage.VolumeSource {
+	source := NewCinderVolumeSource(s).(*cinderVolumeSource)
+	source.availabilityZones = func(context.ProviderCallContext) ([]common.AvailabilityZone, error) {
+		result := make([]common.AvailabilityZone, len(zones))
+		for i, zone := range zones {
+			result[i] = &openstackAvailabilityZone{zone}
+		}
+		return result, nil
+	}
+	return source
+}
+
 type fakeNamespace struct {
 	instance.Namespace
 }
@@ -991,6 +991,9 @@ func (c *neutronFirewaller) ingressRulesInGroup(ctx context.ProviderCallContext,
 		remotePrefix := p.RemoteIPPrefix
 		if remotePrefix == "" {
 			remotePrefix = "0.0.0.0/0"
+			if p.EthernetType == "IPv6" {
+				remotePrefix = "::/0"
+			}
 		}
 		sourceCIDRs, ok := portSourceCIDRs[portRange]
 		if !ok {
@@ -1889,6 +1889,16 @@ func jujuMachineFilter() *nova.Filter {
 	return filter
 }
 
+// ruleEthernetType returns the neutron ethertype for a rule allowing
+// ingress from the given CIDR. As with the rules of the global group,
+// it is left empty for IPv4, which neutron assumes by default.
+func ruleEthernetType(cidr string) string {
+	if addrType, err := corenetwork.CIDRAddressType(cidr); err == nil && addrType == corenetwork.IPv6Address {
+		return "IPv6"
+	}
+	return ""
+}
+
 // rulesToRuleInfo maps ingress rules to nova rules
 func rulesToRuleInfo(groupId string, rules []network.IngressRule) []neutron.RuleInfoV2 {
 	var result []neutron.RuleInfoV2
@@ -1906,6 +1916,7 @@ func rulesToRuleInfo(groupId string, rules []network.IngressRule) []neutron.Rule
 		}
 		for _, sr := range sourceCIDRs {
 			ruleInfo.RemoteIPPrefix = sr
+			ruleInfo.EthernetType = ruleEthernetType(sr)
 			result = append(result, ruleInfo)
 		}
 	}
@@ -263,6 +263,34 @@ func (*localTests) TestPortsToRuleInfo(c *gc.C) {
 			RemoteIPPrefix: "0.0.0.0/0",
 			ParentGroupId:  groupId,
 		}},
+	}, {
+		about: "dual-stack source ranges",
+		rules: []network.IngressRule{network.MustNewIngressRule(
+			"tcp", 443, 443, "192.168.1.0/24", "2001:db8::/32", "::/0")},
+		expected: []neutron.RuleInfoV2{{
+			Direction:      "ingress",
+			IPProtocol:     "tcp",
+			PortRangeMin:   443,
+			PortRangeMax:   443,
+			RemoteIPPrefix: "192.168.1.0/24",
+			ParentGroupId:  groupId,
+		}, {
+			Direction:      "ingress",
+			IPProtocol:     "tcp",
+			PortRangeMin:   443,
+			PortRangeMax:   443,
+			RemoteIPPrefix: "2001:db8::/32",
+			EthernetType:   "IPv6",
+			ParentGroupId:  groupId,
+		}, {
+			Direction:      "ingress",
+			IPProtocol:     "tcp",
+			PortRangeMin:   443,
+			PortRangeMax:   443,
+			RemoteIPPrefix: "::/0",
+			EthernetType:   "IPv6",
+			ParentGroupId:  groupId,
+		}},
 	}}
 
 	for i, t := range testCases {
@@ -370,6 +398,17 @@ func (*localTests) TestSecGroupMatchesIngressRule(c *gc.C) {
 			RemoteIPPrefix: "192.168.100.0/24",
 		},
 		expected: false,
+	}, {
+		about: "matching IPv6 RemoteIPPrefix",
+		rule:  network.MustNewIngressRule(proto_tcp, 80, 85, "2001:DB8:0::/32"),
+		secGroupRule: neutron.SecurityGroupRuleV2{
+			IPProtocol:     &proto_tcp,
+			PortRangeMin:   &port_80,
+			PortRangeMax:   &port_85,
+			RemoteIPPrefix: "2001:db8::/32",
+			EthernetType:   "IPv6",
+		},
+		expected: true,
 	}}
 	for i, t := range testCases {
 		c.Logf("test %d: %s", i, t.about)
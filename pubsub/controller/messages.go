@@ -22,3 +22,57 @@ type ConfigChangedMessage struct {
 	// different machines, and the forwarding of those messages cross each other.
 	// Adding a version could allow subscribers to ignore lower versioned messages.
 }
+
+// ModelFeaturesChanged messages are published by the apiserver client
+// controller facade whenever the feature flags of a model are updated.
+// data: `ModelFeaturesChangedMessage`
+const ModelFeaturesChanged = "controller.model-features-changed"
+
+// ModelFeaturesChangedMessage contains the feature flags enabled for a
+// model as they are after the update.
+type ModelFeaturesChangedMessage struct {
+	ModelUUID string
+	Features  []string
+}
+
+// SaturationAlertsChanged messages are published by the saturation
+// monitor of a controller machine whenever the alerts raised for that
+// controller change.
+// data: `SaturationAlertsMessage`
+const SaturationAlertsChanged = "controller.saturation-alerts-changed"
+
+// SaturationAlertsMessage holds the saturation alerts currently raised
+// for a controller machine. No alerts means that the controller has
+// recovered.
+type SaturationAlertsMessage struct {
+	MachineId string
+	Alerts    []string
+}
+
+// LoggingConfigChanged messages are published by the apiserver when it
+// starts, whenever the controller logging config changes, and in
+// response to LoggingConfigRequest messages.
+// data: `LoggingConfigMessage`
+const LoggingConfigChanged = "controller.logging-config-changed"
+
+// LoggingConfigMessage holds the logging levels that the agent of a
+// controller machine applies on top of the logging-config of the
+// controller model. It should always be LocalOnly, as each controller
+// publishes its own.
+type LoggingConfigMessage struct {
+	Config    string `yaml:"config"`
+	LocalOnly bool   `yaml:"local-only"`
+}
+
+// LoggingConfigRequest messages are published by the workers that
+// adopt the controller logging config when they start, to ask the
+// local apiserver for it.
+// data: `LoggingConfigRequestMessage`
+const LoggingConfigRequest = "controller.logging-config-request"
+
+// LoggingConfigRequestMessage indicates the worker asking for the
+// controller logging config. It should always be LocalOnly.
+type LoggingConfigRequestMessage struct {
+	Requester string `yaml:"requester"`
+	LocalOnly bool   `yaml:"local-only"`
+}
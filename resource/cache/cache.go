@@ -0,0 +1,224 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package cache provides the resource cache shared by the units on a
+// machine, so that units of the same application (or subordinates)
+// need only download a large resource once.
+//
+// Each cached resource is stored once, keyed by its fingerprint, along
+// with a reference for each unit that uses it:
+//
+//	<dir>/<fingerprint>/content
+//	<dir>/<fingerprint>/refs/<unit tag>
+//
+// Units are given hard links to the cached content where possible.
+// Content is removed from the cache once the last unit referring to it
+// has been released.
+package cache
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	charmresource "gopkg.in/juju/charm.v6/resource"
+	"gopkg.in/juju/names.v3"
+)
+
+var logger = loggo.GetLogger("juju.resource.cache")
+
+const (
+	contentFile = "content"
+	refsDir     = "refs"
+)
+
+// Dir returns the directory of the resource cache for the machine
+// with the given data directory.
+func Dir(dataDir string) string {
+	return filepath.Join(dataDir, "resource-cache")
+}
+
+// Cache is a resource cache shared by the units on a machine.
+type Cache struct {
+	dir string
+}
+
+// New returns a Cache that stores resources in the given directory.
+func New(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+func (c *Cache) entryDir(fp charmresource.Fingerprint) string {
+	return filepath.Join(c.dir, fp.String())
+}
+
+// Fetch places the cached content with the given fingerprint, if there
+// is any, at path on behalf of the named unit, and reports whether it
+// did so. Cached content that no longer matches its fingerprint is
+// discarded.
+func (c *Cache) Fetch(unitName string, fp charmresource.Fingerprint, path string) (bool, error) {
+	entry := c.entryDir(fp)
+	content := filepath.Join(entry, contentFile)
+	matches, err := fingerprintMatches(content, fp)
+	if os.IsNotExist(errors.Cause(err)) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Trace(err)
+	}
+	if !matches {
+		logger.Warningf("discarding cached resource %s: content does not match", fp)
+		return false, errors.Trace(os.RemoveAll(entry))
+	}
+	// Record the reference before linking, so the content cannot be
+	// released out from under the unit.
+	if err := addRef(entry, unitName); err != nil {
+		return false, errors.Trace(err)
+	}
+	if err := linkFile(content, path); os.IsNotExist(errors.Cause(err)) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Annotatef(err, "cannot fetch cached resource %s", fp)
+	}
+	return true, nil
+}
+
+// Add adds the content at path, which must match the given fingerprint,
+// to the cache on behalf of the named unit. If the content is already
+// cached, the unit's reference to it is recorded.
+func (c *Cache) Add(unitName string, fp charmresource.Fingerprint, path string) error {
+	entry := c.entryDir(fp)
+	if err := addRef(entry, unitName); err != nil {
+		return errors.Trace(err)
+	}
+	content := filepath.Join(entry, contentFile)
+	if _, err := os.Stat(content); err == nil {
+		return nil
+	} else if !os.IsNotExist(err) {
+		return errors.Trace(err)
+	}
+	if err := linkFile(path, content); err != nil {
+		return errors.Annotatef(err, "cannot cache resource %s", fp)
+	}
+	return nil
+}
+
+// Release removes the named unit's references to cached content, and
+// removes any content that is no longer referred to by any unit.
+func (c *Cache) Release(unitName string) error {
+	entries, err := ioutil.ReadDir(c.dir)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	ref := names.NewUnitTag(unitName).String()
+	for _, info := range entries {
+		if !info.IsDir() {
+			continue
+		}
+		entry := filepath.Join(c.dir, info.Name())
+		err := os.Remove(filepath.Join(entry, refsDir, ref))
+		if err != nil && !os.IsNotExist(err) {
+			return errors.Trace(err)
+		}
+		refs, err := ioutil.ReadDir(filepath.Join(entry, refsDir))
+		if err != nil && !os.IsNotExist(err) {
+			return errors.Trace(err)
+		}
+		if len(refs) > 0 {
+			continue
+		}
+		logger.Debugf("removing cached resource %s", info.Name())
+		if err := os.RemoveAll(entry); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// Units returns the names of the units referring to the cached content
+// with the given fingerprint.
+func (c *Cache) Units(fp charmresource.Fingerprint) ([]string, error) {
+	refs, err := ioutil.ReadDir(filepath.Join(c.entryDir(fp), refsDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var units []string
+	for _, ref := range refs {
+		tag, err := names.ParseUnitTag(ref.Name())
+		if err != nil {
+			logger.Warningf("ignoring unexpected resource cache reference %q", ref.Name())
+			continue
+		}
+		units = append(units, tag.Id())
+	}
+	return units, nil
+}
+
+func addRef(entry, unitName string) error {
+	dir := filepath.Join(entry, refsDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.Trace(err)
+	}
+	ref := filepath.Join(dir, names.NewUnitTag(unitName).String())
+	return errors.Trace(ioutil.WriteFile(ref, nil, 0644))
+}
+
+// linkFile places the file at source at target, replacing any existing
+// file there. A hard link is used where possible, and a copy otherwise.
+func linkFile(source, target string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return errors.Trace(err)
+	}
+	tmp := target + ".tmp"
+	if err := os.Remove(tmp); err != nil && !os.IsNotExist(err) {
+		return errors.Trace(err)
+	}
+	if err := os.Link(source, tmp); err != nil {
+		if _, statErr := os.Stat(source); statErr != nil {
+			return errors.Trace(statErr)
+		}
+		logger.Debugf("cannot link %q, copying instead: %v", source, err)
+		if err := copyFile(source, tmp); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return errors.Trace(os.Rename(tmp, target))
+}
+
+func copyFile(source, target string) (err error) {
+	in, err := os.Open(source)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer in.Close()
+	out, err := os.Create(target)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	_, err = io.Copy(out, in)
+	return errors.Trace(err)
+}
+
+func fingerprintMatches(path string, expected charmresource.Fingerprint) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	defer f.Close()
+	fp, err := charmresource.GenerateFingerprint(f)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return fp.String() == expected.String(), nil
+}
@@ -0,0 +1,129 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cache_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	charmresource "gopkg.in/juju/charm.v6/resource"
+
+	"github.com/juju/juju/resource/cache"
+)
+
+type CacheSuite struct {
+	testing.IsolationSuite
+
+	dir   string
+	cache *cache.Cache
+	fp    charmresource.Fingerprint
+}
+
+var _ = gc.Suite(&CacheSuite{})
+
+func (s *CacheSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.dir = cache.Dir(c.MkDir())
+	s.cache = cache.New(s.dir)
+	fp, err := charmresource.GenerateFingerprint(strings.NewReader("some data"))
+	c.Assert(err, jc.ErrorIsNil)
+	s.fp = fp
+}
+
+func (s *CacheSuite) writeResource(c *gc.C, data string) string {
+	path := filepath.Join(c.MkDir(), "spam", "eggs.tgz")
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(path, []byte(data), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	return path
+}
+
+func (s *CacheSuite) assertContent(c *gc.C, path, data string) {
+	content, err := ioutil.ReadFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(content), gc.Equals, data)
+}
+
+func (s *CacheSuite) TestFetchNotCached(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "eggs.tgz")
+	found, err := s.cache.Fetch("spam/0", s.fp, path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, jc.IsFalse)
+	_, err = os.Stat(path)
+	c.Assert(os.IsNotExist(err), jc.IsTrue)
+}
+
+func (s *CacheSuite) TestAddAndFetch(c *gc.C) {
+	err := s.cache.Add("spam/0", s.fp, s.writeResource(c, "some data"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	path := filepath.Join(c.MkDir(), "spam", "eggs.tgz")
+	found, err := s.cache.Fetch("spam/1", s.fp, path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, jc.IsTrue)
+	s.assertContent(c, path, "some data")
+
+	units, err := s.cache.Units(s.fp)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, jc.SameContents, []string{"spam/0", "spam/1"})
+}
+
+func (s *CacheSuite) TestFetchReplacesExisting(c *gc.C) {
+	err := s.cache.Add("spam/0", s.fp, s.writeResource(c, "some data"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	path := s.writeResource(c, "old data")
+	found, err := s.cache.Fetch("spam/1", s.fp, path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, jc.IsTrue)
+	s.assertContent(c, path, "some data")
+}
+
+func (s *CacheSuite) TestFetchDiscardsMismatchedContent(c *gc.C) {
+	err := s.cache.Add("spam/0", s.fp, s.writeResource(c, "other data"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	path := filepath.Join(c.MkDir(), "eggs.tgz")
+	found, err := s.cache.Fetch("spam/1", s.fp, path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, jc.IsFalse)
+
+	units, err := s.cache.Units(s.fp)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 0)
+}
+
+func (s *CacheSuite) TestReleaseRemovesUnreferencedContent(c *gc.C) {
+	err := s.cache.Add("spam/0", s.fp, s.writeResource(c, "some data"))
+	c.Assert(err, jc.ErrorIsNil)
+	path := filepath.Join(c.MkDir(), "eggs.tgz")
+	_, err = s.cache.Fetch("spam/1", s.fp, path)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.cache.Release("spam/0")
+	c.Assert(err, jc.ErrorIsNil)
+	units, err := s.cache.Units(s.fp)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, jc.DeepEquals, []string{"spam/1"})
+
+	err = s.cache.Release("spam/1")
+	c.Assert(err, jc.ErrorIsNil)
+	entries, err := ioutil.ReadDir(s.dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, gc.HasLen, 0)
+
+	// The units' own copies are left alone.
+	s.assertContent(c, path, "some data")
+}
+
+func (s *CacheSuite) TestReleaseNoCache(c *gc.C) {
+	err := s.cache.Release("spam/0")
+	c.Assert(err, jc.ErrorIsNil)
+}
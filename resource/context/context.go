@@ -14,6 +14,7 @@ import (
 	charmresource "gopkg.in/juju/charm.v6/resource"
 
 	"github.com/juju/juju/resource"
+	"github.com/juju/juju/resource/cache"
 	"github.com/juju/juju/resource/context/internal"
 )
 
@@ -38,13 +39,24 @@ type Context struct {
 	//
 	//   /var/lib/juju/agents/unit-spam-1/resources
 	dataDir string
+
+	// unitName is the name of the unit the resources are for.
+	unitName string
+
+	// cache is the resource cache shared by the units on the
+	// machine. If it is nil, resources are not cached.
+	cache *cache.Cache
 }
 
-// NewContextAPI returns a new Content for the given API client and data dir.
-func NewContextAPI(apiClient APIClient, dataDir string) *Context {
+// NewContextAPI returns a new Content for the given API client and data
+// dir. Resources are shared with the other units on the machine through
+// the given cache, if it is not nil.
+func NewContextAPI(apiClient APIClient, dataDir, unitName string, cache *cache.Cache) *Context {
 	return &Context{
 		apiClient: apiClient,
 		dataDir:   dataDir,
+		unitName:  unitName,
+		cache:     cache,
 	}
 }
 
@@ -63,6 +75,8 @@ func (c *Context) Download(name string) (string, error) {
 		APIClient: c.apiClient,
 		name:      name,
 		dataDir:   c.dataDir,
+		unitName:  c.unitName,
+		cache:     c.cache,
 	}
 	path, err := internal.ContextDownload(deps)
 	if err != nil {
@@ -75,8 +89,10 @@ func (c *Context) Download(name string) (string, error) {
 // of ContextDownload().
 type contextDeps struct {
 	APIClient
-	name    string
-	dataDir string
+	name     string
+	dataDir  string
+	unitName string
+	cache    *cache.Cache
 }
 
 func (deps *contextDeps) NewContextDirectorySpec() internal.ContextDirectorySpec {
@@ -91,6 +107,29 @@ func (deps *contextDeps) Download(target internal.DownloadTarget, remote interna
 	return internal.Download(target, remote)
 }
 
+func (deps *contextDeps) FetchCached(content internal.Content, path string) (bool, error) {
+	if deps.cache == nil {
+		return false, nil
+	}
+	found, err := deps.cache.Fetch(deps.unitName, content.Fingerprint, path)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if found {
+		logger.Debugf("using cached copy of resource %q", deps.name)
+	}
+	return found, nil
+}
+
+func (deps *contextDeps) CacheDownloaded(content internal.Content, path string) {
+	if deps.cache == nil {
+		return
+	}
+	if err := deps.cache.Add(deps.unitName, content.Fingerprint, path); err != nil {
+		logger.Warningf("cannot cache resource %q: %v", deps.name, err)
+	}
+}
+
 func (deps *contextDeps) WriteContent(target io.Writer, content internal.Content) error {
 	return internal.WriteContent(target, content, deps)
 }
@@ -37,9 +37,19 @@ func ContextDownload(deps ContextDownloadDeps) (path string, err error) {
 		return path, nil
 	}
 
+	// Another unit on the machine may have downloaded it already.
+	cached, err := deps.FetchCached(remote.Content(), path)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if cached {
+		return path, nil
+	}
+
 	if err := deps.Download(resDirSpec, remote); err != nil {
 		return "", errors.Trace(err)
 	}
+	deps.CacheDownloaded(remote.Content(), path)
 
 	return path, nil
 }
@@ -61,6 +71,14 @@ type ContextDownloadDeps interface {
 
 	// Download writes the remote to the target directory.
 	Download(DownloadTarget, ContextOpenedResource) error
+
+	// FetchCached places the machine's cached copy of the content, if
+	// there is one, at the given path and reports whether it did so.
+	FetchCached(Content, string) (bool, error)
+
+	// CacheDownloaded adds the content downloaded to the given path to
+	// the machine's resource cache, logging any failure to do so.
+	CacheDownloaded(Content, string)
 }
 
 // ContextDirectorySpec exposes the functionality of a resource dir spec
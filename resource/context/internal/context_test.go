@@ -56,7 +56,11 @@ func (s *ContextSuite) TestContextDownloadOutOfDate(c *gc.C) {
 		"Resolve",
 		"Content",
 		"IsUpToDate",
+		"Content",
+		"FetchCached",
 		"Download",
+		"Content",
+		"CacheDownloaded",
 		"CloseAndLog",
 	)
 	c.Check(path, gc.Equals, "/var/lib/juju/agents/unit-spam-1/resources/spam/eggs.tgz")
@@ -96,6 +100,43 @@ func (s *ContextSuite) TestContextDownloadUpToDate(c *gc.C) {
 	c.Check(path, gc.Equals, "/var/lib/juju/agents/unit-spam-1/resources/spam/eggs.tgz")
 }
 
+func (s *ContextSuite) TestContextDownloadCached(c *gc.C) {
+	info, reader := newResource(c, s.stub.Stub, "spam", "some data")
+	content := internal.Content{
+		Data:        reader,
+		Size:        info.Size,
+		Fingerprint: info.Fingerprint,
+	}
+	stub := &stubContext{
+		internalStub: s.stub,
+		StubCloser:   &filetesting.StubCloser{Stub: s.stub.Stub},
+	}
+	stub.ReturnNewContextDirectorySpec = stub
+	stub.ReturnOpenResource = stub
+	stub.ReturnResolve = "/var/lib/juju/agents/unit-spam-1/resources/spam/eggs.tgz"
+	stub.ReturnInfo = info
+	stub.ReturnContent = content
+	stub.ReturnFetchCached = true
+	deps := stub
+
+	path, err := internal.ContextDownload(deps)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.stub.CheckCallNames(c,
+		"NewContextDirectorySpec",
+		"OpenResource",
+		"Info",
+		"Resolve",
+		"Content",
+		"IsUpToDate",
+		"Content",
+		"FetchCached",
+		"CloseAndLog",
+	)
+	s.stub.CheckCall(c, 7, "FetchCached", content, "/var/lib/juju/agents/unit-spam-1/resources/spam/eggs.tgz")
+	c.Check(path, gc.Equals, "/var/lib/juju/agents/unit-spam-1/resources/spam/eggs.tgz")
+}
+
 type stubContext struct {
 	*internalStub
 	*filetesting.StubCloser
@@ -25,6 +25,7 @@ type internalStub struct {
 	ReturnNewChecker              internal.ContentChecker
 	ReturnCreateWriter            io.WriteCloser
 	ReturnFingerprintMatches      bool
+	ReturnFetchCached             bool
 }
 
 func newInternalStub() *internalStub {
@@ -68,6 +69,20 @@ func (s *internalStub) Download(target internal.DownloadTarget, remote internal.
 	return nil
 }
 
+func (s *internalStub) FetchCached(content internal.Content, path string) (bool, error) {
+	s.Stub.AddCall("FetchCached", content, path)
+	if err := s.Stub.NextErr(); err != nil {
+		return false, errors.Trace(err)
+	}
+
+	return s.ReturnFetchCached, nil
+}
+
+func (s *internalStub) CacheDownloaded(content internal.Content, path string) {
+	s.Stub.AddCall("CacheDownloaded", content, path)
+	s.Stub.NextErr() // Pop one off.
+}
+
 func (s *internalStub) DownloadDirect(target internal.DownloadTarget, remote internal.ContentSource) error {
 	s.Stub.AddCall("DownloadDirect", target, remote)
 	if err := s.Stub.NextErr(); err != nil {
@@ -4,11 +4,14 @@
 package rpc
 
 import (
+	"context"
 	"encoding/json"
 	"reflect"
 	"strings"
 
 	"github.com/juju/errors"
+
+	"github.com/juju/juju/core/tracing"
 )
 
 var ErrShutdown = errors.New("connection is shut down")
@@ -24,6 +27,10 @@ type Call struct {
 	Response interface{}
 	Error    error
 	Done     chan *Call
+
+	// TraceParent holds the W3C trace context of the span making
+	// the call, if any.
+	TraceParent string
 }
 
 // RequestError represents an error returned from an RPC request.
@@ -69,7 +76,9 @@ func (e *RequestError) UnmarshalInfo(to interface{}) error {
 	return nil
 }
 
-func (conn *Conn) send(call *Call) {
+// send registers and sends the given call, returning the id of the
+// request. The call is done with an error if it cannot be sent.
+func (conn *Conn) send(call *Call) uint64 {
 	conn.sending.Lock()
 	defer conn.sending.Unlock()
 
@@ -82,7 +91,7 @@ func (conn *Conn) send(call *Call) {
 		call.Error = ErrShutdown
 		conn.mutex.Unlock()
 		call.done()
-		return
+		return 0
 	}
 	conn.reqId++
 	reqId := conn.reqId
@@ -91,9 +100,10 @@ func (conn *Conn) send(call *Call) {
 
 	// Encode and send the request.
 	hdr := &Header{
-		RequestId: reqId,
-		Request:   call.Request,
-		Version:   1,
+		RequestId:   reqId,
+		Request:     call.Request,
+		Version:     1,
+		TraceParent: call.TraceParent,
 	}
 	params := call.Params
 	if params == nil {
@@ -110,6 +120,7 @@ func (conn *Conn) send(call *Call) {
 			call.done()
 		}
 	}
+	return reqId
 }
 
 func (conn *Conn) handleResponse(hdr *Header) error {
@@ -147,9 +158,29 @@ func (conn *Conn) handleResponse(hdr *Header) error {
 		err = conn.readBody(call.Response, false)
 		call.done()
 	}
+	if call != nil && hdr.Deprecation != "" {
+		conn.warnDeprecated(call.Request, hdr.Deprecation)
+	}
 	return errors.Annotate(err, "error handling response")
 }
 
+// warnDeprecated logs a warning the first time the server reports that
+// the facade version used by a request is deprecated.
+func (conn *Conn) warnDeprecated(req Request, message string) {
+	key := Request{Type: req.Type, Version: req.Version}
+	conn.mutex.Lock()
+	defer conn.mutex.Unlock()
+	if conn.deprecationsSeen[key] {
+		return
+	}
+	if conn.deprecationsSeen == nil {
+		conn.deprecationsSeen = make(map[Request]bool)
+	}
+	conn.deprecationsSeen[key] = true
+	logger.Warningf("%s(%d) is deprecated and will be removed in the next major release: %s",
+		req.Type, req.Version, message)
+}
+
 func (call *Call) done() {
 	select {
 	case call.Done <- call:
@@ -167,13 +198,39 @@ func (call *Call) done() {
 // The params value may be nil if no parameters are provided; the response value
 // may be nil to indicate that any result should be discarded.
 func (conn *Conn) Call(req Request, params, response interface{}) error {
+	return conn.CallContext(context.Background(), req, params, response)
+}
+
+// CallContext is like Call, but if the given context holds a trace
+// span, the span's trace context is sent with the request so that the
+// server can record its handling of the request as part of the trace.
+// If the context is done before the response arrives, CallContext
+// returns the context's error and any later response is discarded.
+func (conn *Conn) CallContext(ctx context.Context, req Request, params, response interface{}) error {
 	call := &Call{
-		Request:  req,
-		Params:   params,
-		Response: response,
-		Done:     make(chan *Call, 1),
-	}
-	conn.send(call)
-	result := <-call.Done
-	return errors.Trace(result.Error)
+		Request:     req,
+		Params:      params,
+		Response:    response,
+		Done:        make(chan *Call, 1),
+		TraceParent: tracing.TraceParentFromContext(ctx),
+	}
+	reqId := conn.send(call)
+	select {
+	case result := <-call.Done:
+		return errors.Trace(result.Error)
+	case <-ctx.Done():
+	}
+	conn.mutex.Lock()
+	pending := conn.clientPending[reqId] == call
+	if pending {
+		delete(conn.clientPending, reqId)
+	}
+	conn.mutex.Unlock()
+	if !pending {
+		// The response is already being handled, and may be
+		// written to the response value, so wait for it.
+		result := <-call.Done
+		return errors.Trace(result.Error)
+	}
+	return errors.Trace(ctx.Err())
 }
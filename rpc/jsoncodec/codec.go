@@ -73,6 +73,9 @@ type inMsgV1 struct {
 	ErrorCode string                 `json:"error-code"`
 	ErrorInfo map[string]interface{} `json:"error-info"`
 	Response  json.RawMessage        `json:"response"`
+
+	TraceParent string `json:"trace-parent"`
+	Deprecation string `json:"deprecation"`
 }
 
 // outMsg holds an outgoing message.
@@ -99,6 +102,9 @@ type outMsgV1 struct {
 	ErrorCode string                 `json:"error-code,omitempty"`
 	ErrorInfo map[string]interface{} `json:"error-info,omitempty"`
 	Response  interface{}            `json:"response,omitempty"`
+
+	TraceParent string `json:"trace-parent,omitempty"`
+	Deprecation string `json:"deprecation,omitempty"`
 }
 
 func (c *Codec) Close() error {
@@ -142,6 +148,8 @@ func (c *Codec) ReadHeader(hdr *rpc.Header) error {
 	hdr.Error = c.msg.Error
 	hdr.ErrorCode = c.msg.ErrorCode
 	hdr.ErrorInfo = c.msg.ErrorInfo
+	hdr.TraceParent = c.msg.TraceParent
+	hdr.Deprecation = c.msg.Deprecation
 	hdr.Version = version
 	return nil
 }
@@ -276,6 +284,9 @@ func newOutMsgV1(hdr *rpc.Header, body interface{}) outMsgV1 {
 		Error:     hdr.Error,
 		ErrorCode: hdr.ErrorCode,
 		ErrorInfo: hdr.ErrorInfo,
+
+		TraceParent: hdr.TraceParent,
+		Deprecation: hdr.Deprecation,
 	}
 	if hdr.IsRequest() {
 		result.Params = body
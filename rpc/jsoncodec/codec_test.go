@@ -108,6 +108,26 @@ func (*suite) TestRead(c *gc.C) {
 			Version: 1,
 		},
 		expectBody: new(map[string]interface{}),
+	}, {
+		msg: `{"request-id": 5, "type": "foo", "request": "frob", "trace-parent": "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"}`,
+		expectHdr: rpc.Header{
+			RequestId: 5,
+			Request: rpc.Request{
+				Type:   "foo",
+				Action: "frob",
+			},
+			TraceParent: "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+			Version:     1,
+		},
+		expectBody: &value{},
+	}, {
+		msg: `{"request-id": 6, "response": {"X": "result"}, "deprecation": "use foo v2"}`,
+		expectHdr: rpc.Header{
+			RequestId:   6,
+			Deprecation: "use foo v2",
+			Version:     1,
+		},
+		expectBody: &value{X: "result"},
 	}, {
 		msg: `{"request-id": 3, "response": {"X": "result"}}`,
 		expectHdr: rpc.Header{
@@ -258,6 +278,26 @@ func (*suite) TestWrite(c *gc.C) {
 		},
 		body:   &value{X: "result"},
 		expect: `{"request-id": 3, "response": {"X": "result"}}`,
+	}, {
+		hdr: &rpc.Header{
+			RequestId: 5,
+			Request: rpc.Request{
+				Type:   "foo",
+				Action: "frob",
+			},
+			TraceParent: "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+			Version:     1,
+		},
+		body:   &value{X: "param"},
+		expect: `{"request-id": 5, "type": "foo", "request": "frob", "params": {"X": "param"}, "trace-parent": "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"}`,
+	}, {
+		hdr: &rpc.Header{
+			RequestId:   6,
+			Deprecation: "use foo v2",
+			Version:     1,
+		},
+		body:   &value{X: "result"},
+		expect: `{"request-id": 6, "response": {"X": "result"}, "deprecation": "use foo v2"}`,
 	}, {
 		hdr: &rpc.Header{
 			RequestId: 4,
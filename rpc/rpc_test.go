@@ -10,6 +10,7 @@ import (
 	"net"
 	"reflect"
 	"regexp"
+	"strings"
 	"sync"
 	"time"
 
@@ -17,6 +18,7 @@ import (
 	"github.com/juju/loggo"
 	"github.com/juju/rpcreflect"
 	jc "github.com/juju/testing/checkers"
+	"go.opencensus.io/trace"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/params"
@@ -360,6 +362,33 @@ func (c customMethodCaller) Call(_ context.Context, objId string, arg reflect.Va
 func (cc *CustomRoot) Kill() {
 }
 
+// DeprecatedRoot is a CustomRoot where one version of MultiVersion
+// is deprecated.
+type DeprecatedRoot struct {
+	*CustomRoot
+	version int
+}
+
+func (dr *DeprecatedRoot) FindMethod(
+	rootMethodName string, version int, objMethodName string,
+) (
+	rpcreflect.MethodCaller, error,
+) {
+	caller, err := dr.CustomRoot.FindMethod(rootMethodName, version, objMethodName)
+	if err != nil || version != dr.version {
+		return caller, err
+	}
+	return deprecatedCaller{caller}, nil
+}
+
+type deprecatedCaller struct {
+	rpcreflect.MethodCaller
+}
+
+func (deprecatedCaller) Deprecation() string {
+	return "use MultiVersion v1"
+}
+
 func (cc *CustomRoot) FindMethod(
 	rootMethodName string, version int, objMethodName string,
 ) (
@@ -639,6 +668,30 @@ func (*rpcSuite) TestCustomRootV0(c *gc.C) {
 	})
 }
 
+func (*rpcSuite) TestDeprecatedMethodCaller(c *gc.C) {
+	root := &DeprecatedRoot{CustomRoot: &CustomRoot{SimpleRoot()}, version: 0}
+	client, _, srvDone, serverNotifier := newRPCClientServer(c, root, nil, false)
+	defer closeClient(c, client, srvDone)
+
+	var r stringVal
+	for i := 0; i < 2; i++ {
+		err := client.Call(rpc.Request{"MultiVersion", 0, "a99", "Call0r1"}, nil, &r)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	err := client.Call(rpc.Request{"MultiVersion", 1, "a99", "Call1r1"}, stringVal{"arg"}, &r)
+	c.Assert(err, jc.ErrorIsNil)
+
+	serverNotifier.mu.Lock()
+	replies := serverNotifier.serverReplies
+	serverNotifier.mu.Unlock()
+	c.Assert(replies, gc.HasLen, 3)
+	c.Check(replies[0].hdr.Deprecation, gc.Equals, "use MultiVersion v1")
+	c.Check(replies[2].hdr.Deprecation, gc.Equals, "")
+
+	// The client warns about each deprecated facade version once.
+	c.Check(strings.Count(c.GetTestLog(), "MultiVersion(0) is deprecated"), gc.Equals, 1)
+}
+
 func (*rpcSuite) TestCustomRootV1(c *gc.C) {
 	root := &CustomRoot{SimpleRoot()}
 	client, _, srvDone, serverNotifier := newRPCClientServer(c, root, nil, false)
@@ -743,6 +796,47 @@ func (*rpcSuite) TestConcurrentCalls(c *gc.C) {
 	chanRead(c, done2, "method 2 done")
 }
 
+func (*rpcSuite) TestCallContextCancelled(c *gc.C) {
+	ready := make(chan struct{})
+	start1 := make(chan string)
+	start2 := make(chan string, 1)
+	start2 <- "return 2"
+	root := &Root{
+		delayed: map[string]*DelayedMethods{
+			"1": {ready: ready, done: start1},
+			"2": {done: start2},
+		},
+	}
+
+	client, _, srvDone, _ := newRPCClientServer(c, root, nil, false)
+	defer closeClient(c, client, srvDone)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() {
+		var r stringVal
+		result <- client.CallContext(ctx, rpc.Request{"DelayedMethods", 0, "1", "Delay"}, nil, &r)
+	}()
+
+	// Cancel the call while the server is handling it.
+	chanRead(c, ready, "method ready")
+	cancel()
+	select {
+	case err := <-result:
+		c.Assert(errors.Cause(err), gc.Equals, context.Canceled)
+	case <-time.After(testing.LongWait):
+		c.Fatalf("timed out waiting for the call to return")
+	}
+
+	// The late response is discarded, and the connection is still
+	// usable.
+	start1 <- "return 1"
+	var r stringVal
+	err := client.Call(rpc.Request{"DelayedMethods", 0, "2", "Delay"}, nil, &r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(r.Val, gc.Equals, "return 2")
+}
+
 type codedError struct {
 	m    string
 	code string
@@ -1173,6 +1267,26 @@ func (*rpcSuite) TestRequestContext(c *gc.C) {
 	c.Assert(arg, gc.Equals, stringVal{"foo"})
 }
 
+func (*rpcSuite) TestRequestContextTraceParent(c *gc.C) {
+	root := &Root{}
+	root.contextInst = &ContextMethods{root: root}
+
+	client, _, srvDone, _ := newRPCClientServer(c, root, nil, false)
+	defer closeClient(c, client, srvDone)
+
+	ctx, clientSpan := trace.StartSpan(context.Background(), "client", trace.WithSampler(trace.AlwaysSample()))
+	defer clientSpan.End()
+	err := client.CallContext(ctx, rpc.Request{"ContextMethods", 0, "", "Call0"}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The request is served as part of the client's trace.
+	serverSpan := trace.FromContext(root.contextInst.callContext)
+	c.Assert(serverSpan, gc.NotNil)
+	c.Assert(serverSpan.SpanContext().TraceID, gc.Equals, clientSpan.SpanContext().TraceID)
+	c.Assert(serverSpan.SpanContext().SpanID, gc.Not(gc.Equals), clientSpan.SpanContext().SpanID)
+	c.Assert(serverSpan.SpanContext().IsSampled(), jc.IsTrue)
+}
+
 func (*rpcSuite) TestConnectionContextCloseClient(c *gc.C) {
 	root := &Root{}
 	root.contextInst = &ContextMethods{
@@ -1340,6 +1454,9 @@ func newRPCClientServer(
 		if custroot, ok := root.(*CustomRoot); ok {
 			rpcConn.ServeRoot(custroot, recorderFactory, tfErr)
 			custroot.root.conn = rpcConn
+		} else if deproot, ok := root.(*DeprecatedRoot); ok {
+			rpcConn.ServeRoot(deproot, recorderFactory, tfErr)
+			deproot.root.conn = rpcConn
 		} else {
 			rpcConn.Serve(root, recorderFactory, tfErr)
 		}
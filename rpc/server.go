@@ -70,6 +70,15 @@ type Header struct {
 
 	// Version defines the wire format of the request and response structure.
 	Version int
+
+	// TraceParent holds the W3C trace context of the client span that
+	// made the request, if any, so that the span serving the request
+	// can be recorded as its child.
+	TraceParent string
+
+	// Deprecation holds, for replies, the reason the facade version
+	// that served the request is deprecated, if it is.
+	Deprecation string
 }
 
 // Request represents an RPC to be performed, absent its parameters.
@@ -162,6 +171,10 @@ type Conn struct {
 	// terminate prematurely.  It is set before dead is closed.
 	inputLoopError error
 
+	// deprecationsSeen holds the deprecated facade versions that
+	// the client has been warned about.
+	deprecationsSeen map[Request]bool
+
 	recorderFactory RecorderFactory
 }
 
@@ -507,6 +520,18 @@ func (conn *Conn) writeErrorResponse(reqHdr *Header, err error, recorder Recorde
 
 // boundRequest represents an RPC request that is
 // bound to an actual implementation.
+// DeprecatedMethodCaller may be implemented by the method callers
+// returned by a Root for methods of deprecated facade versions, so
+// that clients are told about the deprecation in the replies to their
+// requests.
+type DeprecatedMethodCaller interface {
+	rpcreflect.MethodCaller
+
+	// Deprecation returns the reason the method's facade version
+	// is deprecated.
+	Deprecation() string
+}
+
 type boundRequest struct {
 	rpcreflect.MethodCaller
 	transformErrors func(error) error
@@ -568,7 +593,9 @@ func (conn *Conn) runRequest(
 	ctx, cancel := context.WithCancel(conn.context)
 	defer cancel()
 
+	ctx, span := startRequestSpan(ctx, &req.hdr)
 	rv, err := req.Call(ctx, req.hdr.Request.Id, arg)
+	endRequestSpan(span, err)
 	if err != nil {
 		err = conn.writeErrorResponse(&req.hdr, req.transformErrors(err), recorder)
 	} else {
@@ -576,6 +603,9 @@ func (conn *Conn) runRequest(
 			RequestId: req.hdr.RequestId,
 			Version:   version,
 		}
+		if deprecated, ok := req.MethodCaller.(DeprecatedMethodCaller); ok {
+			hdr.Deprecation = deprecated.Deprecation()
+		}
 		var rvi interface{}
 		if rv.IsValid() {
 			rvi = rv.Interface()
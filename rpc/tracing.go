@@ -0,0 +1,47 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+
+	"go.opencensus.io/trace"
+
+	"github.com/juju/juju/core/tracing"
+)
+
+// startRequestSpan starts the span that covers the serving of the
+// request with the given header. If the client sent its trace context
+// with the request, the span is recorded as the child of the client's
+// span, and is sampled if the client's span is.
+func startRequestSpan(ctx context.Context, hdr *Header) (context.Context, *trace.Span) {
+	name := fmt.Sprintf("%s.%s", hdr.Request.Type, hdr.Request.Action)
+	var span *trace.Span
+	if parent, ok := tracing.ParseTraceParent(hdr.TraceParent); ok {
+		ctx, span = trace.StartSpanWithRemoteParent(ctx, name, parent, trace.WithSpanKind(trace.SpanKindServer))
+	} else {
+		ctx, span = trace.StartSpan(ctx, name, trace.WithSpanKind(trace.SpanKindServer))
+	}
+	span.AddAttributes(
+		trace.Int64Attribute("version", int64(hdr.Request.Version)),
+		trace.Int64Attribute("request-id", int64(hdr.RequestId)),
+	)
+	if hdr.Request.Id != "" {
+		span.AddAttributes(trace.StringAttribute("id", hdr.Request.Id))
+	}
+	return ctx, span
+}
+
+// endRequestSpan ends the given request span, recording the error, if
+// any, that the request failed with.
+func endRequestSpan(span *trace.Span, err error) {
+	if err != nil {
+		span.SetStatus(trace.Status{
+			Code:    trace.StatusCodeUnknown,
+			Message: err.Error(),
+		})
+	}
+	span.End()
+}
@@ -105,6 +105,10 @@ type actionDoc struct {
 
 	// Results are the structured results from the action.
 	Results map[string]interface{} `bson:"results"`
+
+	// Operation identifies the operation, with a webhook to invoke on
+	// its completion, that the action is part of, if any.
+	Operation string `bson:"operation,omitempty"`
 }
 
 // action represents an instruction to do some "action" and is expected
@@ -226,8 +230,27 @@ func (a *action) removeAndLog(finalStatus ActionStatus, results map[string]inter
 		return nil, errors.Trace(err)
 	}
 
-	err = m.st.db().RunTransaction([]txn.Op{
-		{
+	actions, closer := m.st.db().GetCollection(actionsC)
+	defer closer()
+
+	buildTxn := func(int) ([]txn.Op, error) {
+		// The action may have been added to an operation since it was
+		// read, so the operation is read afresh and asserted on.
+		var doc actionDoc
+		if err := actions.FindId(a.doc.DocId).One(&doc); err == mgo.ErrNotFound {
+			return nil, errors.NotFoundf("action %q", a.Id())
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		switch doc.Status {
+		case ActionCompleted, ActionCancelled, ActionFailed:
+			return nil, txn.ErrAborted
+		}
+		operationAssert := bson.DocElem{Name: "operation", Value: doc.Operation}
+		if doc.Operation == "" {
+			operationAssert.Value = bson.D{{"$exists", false}}
+		}
+		ops := []txn.Op{{
 			C:  actionsC,
 			Id: a.doc.DocId,
 			Assert: bson.D{{"status", bson.D{
@@ -235,7 +258,7 @@ func (a *action) removeAndLog(finalStatus ActionStatus, results map[string]inter
 					ActionCompleted,
 					ActionCancelled,
 					ActionFailed,
-				}}}}},
+				}}}}, operationAssert},
 			Update: bson.D{{"$set", bson.D{
 				{"status", finalStatus},
 				{"message", message},
@@ -246,8 +269,17 @@ func (a *action) removeAndLog(finalStatus ActionStatus, results map[string]inter
 			C:      actionNotificationsC,
 			Id:     m.st.docID(ensureActionMarker(a.Receiver()) + a.Id()),
 			Remove: true,
-		}})
-	if err != nil {
+		}}
+		if doc.Operation != "" {
+			ops = append(ops, txn.Op{
+				C:      actionWebhooksC,
+				Id:     doc.Operation,
+				Update: bson.D{{"$pull", bson.D{{"pending", a.Id()}}}},
+			})
+		}
+		return ops, nil
+	}
+	if err := m.st.db().Run(buildTxn); err != nil {
 		return nil, err
 	}
 	return m.Action(a.Id())
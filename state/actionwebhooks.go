@@ -0,0 +1,169 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// ActionWebhook holds the webhook to invoke when all of the actions of
+// an operation have completed.
+type ActionWebhook struct {
+	// Operation identifies the operation.
+	Operation string
+
+	// URL is the URL the webhook is posted to.
+	URL string
+
+	// Secret is used to sign the webhook's payload.
+	Secret string
+
+	// ActionIds holds the ids of the operation's actions.
+	ActionIds []string
+
+	// Pending holds the ids of the operation's actions that have not
+	// yet completed.
+	Pending []string
+}
+
+// Completed reports whether all of the operation's actions have
+// completed.
+func (w ActionWebhook) Completed() bool {
+	return len(w.Pending) == 0
+}
+
+// actionWebhookDoc records the webhook to invoke when all of the actions
+// of an operation have completed. It is keyed by the operation id.
+type actionWebhookDoc struct {
+	DocID     string   `bson:"_id"`
+	ModelUUID string   `bson:"model-uuid"`
+	URL       string   `bson:"url"`
+	Secret    string   `bson:"secret,omitempty"`
+	ActionIds []string `bson:"action-ids"`
+	Pending   []string `bson:"pending"`
+}
+
+func (m *Model) newActionWebhook(doc *actionWebhookDoc) ActionWebhook {
+	return ActionWebhook{
+		Operation: m.localID(doc.DocID),
+		URL:       doc.URL,
+		Secret:    doc.Secret,
+		ActionIds: doc.ActionIds,
+		Pending:   doc.Pending,
+	}
+}
+
+// AddActionWebhook records a webhook to invoke when all of the actions
+// with the given ids have completed, returning the id of the operation
+// the actions now make up. An action may only be part of one operation.
+func (m *Model) AddActionWebhook(actionIds []string, url, secret string) (string, error) {
+	if len(actionIds) == 0 {
+		return "", errors.NotValidf("webhook without actions")
+	}
+	if url == "" {
+		return "", errors.NotValidf("empty webhook URL")
+	}
+	uuid, err := NewUUID()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	operation := uuid.String()
+
+	actions, closer := m.st.db().GetCollection(actionsC)
+	defer closer()
+
+	buildTxn := func(int) ([]txn.Op, error) {
+		var ops []txn.Op
+		pending := []string{}
+		for _, id := range actionIds {
+			var doc actionDoc
+			if err := actions.FindId(id).One(&doc); err == mgo.ErrNotFound {
+				return nil, errors.NotFoundf("action %q", id)
+			} else if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if doc.Operation != "" {
+				return nil, errors.Errorf("action %q is already part of an operation", id)
+			}
+			switch doc.Status {
+			case ActionCompleted, ActionCancelled, ActionFailed:
+				ops = append(ops, txn.Op{
+					C:      actionsC,
+					Id:     id,
+					Assert: bson.D{{"status", doc.Status}},
+					Update: bson.D{{"$set", bson.D{{"operation", operation}}}},
+				})
+			default:
+				pending = append(pending, id)
+				ops = append(ops, txn.Op{
+					C:  actionsC,
+					Id: id,
+					Assert: bson.D{
+						{"status", bson.D{{"$nin", []interface{}{
+							ActionCompleted,
+							ActionCancelled,
+							ActionFailed,
+						}}}},
+						{"operation", bson.D{{"$exists", false}}},
+					},
+					Update: bson.D{{"$set", bson.D{{"operation", operation}}}},
+				})
+			}
+		}
+		ops = append(ops, txn.Op{
+			C:      actionWebhooksC,
+			Id:     operation,
+			Assert: txn.DocMissing,
+			Insert: &actionWebhookDoc{
+				URL:       url,
+				Secret:    secret,
+				ActionIds: actionIds,
+				Pending:   pending,
+			},
+		})
+		return ops, nil
+	}
+	if err := m.st.db().Run(buildTxn); err != nil {
+		return "", errors.Annotate(err, "cannot add action webhook")
+	}
+	return operation, nil
+}
+
+// ActionWebhook returns the webhook of the operation with the given id.
+func (m *Model) ActionWebhook(operation string) (ActionWebhook, error) {
+	coll, closer := m.st.db().GetCollection(actionWebhooksC)
+	defer closer()
+
+	var doc actionWebhookDoc
+	if err := coll.FindId(operation).One(&doc); err == mgo.ErrNotFound {
+		return ActionWebhook{}, errors.NotFoundf("webhook for operation %q", operation)
+	} else if err != nil {
+		return ActionWebhook{}, errors.Annotatef(err, "cannot get webhook for operation %q", operation)
+	}
+	return m.newActionWebhook(&doc), nil
+}
+
+// RemoveActionWebhook removes the webhook of the operation with the
+// given id, once it has been invoked. Removing a webhook that does not
+// exist is not an error.
+func (m *Model) RemoveActionWebhook(operation string) error {
+	ops := []txn.Op{{
+		C:      actionWebhooksC,
+		Id:     operation,
+		Remove: true,
+	}}
+	if err := m.st.db().RunTransaction(ops); err != nil {
+		return errors.Annotatef(err, "cannot remove webhook for operation %q", operation)
+	}
+	return nil
+}
+
+// WatchActionWebhooks returns a StringsWatcher that notifies of the ids
+// of operations whose webhooks have been added, changed or removed.
+func (m *Model) WatchActionWebhooks() StringsWatcher {
+	return newCollectionWatcher(m.st, colWCfg{col: actionWebhooksC})
+}
@@ -0,0 +1,148 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type ActionWebhookSuite struct {
+	ConnSuite
+	unit  *state.Unit
+	model *state.Model
+}
+
+var _ = gc.Suite(&ActionWebhookSuite{})
+
+func (s *ActionWebhookSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	ch := s.AddTestingCharm(c, "dummy")
+	app := s.AddTestingApplication(c, "dummy", ch)
+	var err error
+	s.unit, err = app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.SetCharmURL(ch.URL())
+	c.Assert(err, jc.ErrorIsNil)
+	s.model, err = s.State.Model()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ActionWebhookSuite) addActions(c *gc.C, n int) []state.Action {
+	var actions []state.Action
+	for i := 0; i < n; i++ {
+		action, err := s.unit.AddAction("snapshot", nil)
+		c.Assert(err, jc.ErrorIsNil)
+		actions = append(actions, action)
+	}
+	return actions
+}
+
+func (s *ActionWebhookSuite) TestAddActionWebhook(c *gc.C) {
+	actions := s.addActions(c, 2)
+	ids := []string{actions[0].Id(), actions[1].Id()}
+	operation, err := s.model.AddActionWebhook(ids, "https://example.com/hook", "s3cret")
+	c.Assert(err, jc.ErrorIsNil)
+
+	webhook, err := s.model.ActionWebhook(operation)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(webhook, jc.DeepEquals, state.ActionWebhook{
+		Operation: operation,
+		URL:       "https://example.com/hook",
+		Secret:    "s3cret",
+		ActionIds: ids,
+		Pending:   ids,
+	})
+	c.Assert(webhook.Completed(), jc.IsFalse)
+}
+
+func (s *ActionWebhookSuite) TestFinishingActionsCompletesWebhook(c *gc.C) {
+	actions := s.addActions(c, 2)
+	ids := []string{actions[0].Id(), actions[1].Id()}
+	operation, err := s.model.AddActionWebhook(ids, "https://example.com/hook", "")
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = actions[0].Finish(state.ActionResults{Status: state.ActionCompleted})
+	c.Assert(err, jc.ErrorIsNil)
+	webhook, err := s.model.ActionWebhook(operation)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(webhook.Pending, jc.DeepEquals, ids[1:])
+
+	_, err = actions[1].Finish(state.ActionResults{Status: state.ActionFailed, Message: "oops"})
+	c.Assert(err, jc.ErrorIsNil)
+	webhook, err = s.model.ActionWebhook(operation)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(webhook.Pending, gc.HasLen, 0)
+	c.Assert(webhook.Completed(), jc.IsTrue)
+}
+
+func (s *ActionWebhookSuite) TestAddActionWebhookFinishedActions(c *gc.C) {
+	actions := s.addActions(c, 2)
+	_, err := actions[0].Finish(state.ActionResults{Status: state.ActionCompleted})
+	c.Assert(err, jc.ErrorIsNil)
+
+	ids := []string{actions[0].Id(), actions[1].Id()}
+	operation, err := s.model.AddActionWebhook(ids, "https://example.com/hook", "")
+	c.Assert(err, jc.ErrorIsNil)
+	webhook, err := s.model.ActionWebhook(operation)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(webhook.Pending, jc.DeepEquals, ids[1:])
+}
+
+func (s *ActionWebhookSuite) TestAddActionWebhookErrors(c *gc.C) {
+	actions := s.addActions(c, 1)
+	ids := []string{actions[0].Id()}
+
+	_, err := s.model.AddActionWebhook(nil, "https://example.com/hook", "")
+	c.Assert(err, gc.ErrorMatches, "webhook without actions not valid")
+	_, err = s.model.AddActionWebhook(ids, "", "")
+	c.Assert(err, gc.ErrorMatches, "empty webhook URL not valid")
+	_, err = s.model.AddActionWebhook([]string{"dummy-666"}, "https://example.com/hook", "")
+	c.Assert(err, gc.ErrorMatches, `cannot add action webhook: action "dummy-666" not found`)
+
+	_, err = s.model.AddActionWebhook(ids, "https://example.com/hook", "")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.model.AddActionWebhook(ids, "https://example.com/hook", "")
+	c.Assert(err, gc.ErrorMatches, `cannot add action webhook: action ".*" is already part of an operation`)
+}
+
+func (s *ActionWebhookSuite) TestRemoveActionWebhook(c *gc.C) {
+	actions := s.addActions(c, 1)
+	operation, err := s.model.AddActionWebhook([]string{actions[0].Id()}, "https://example.com/hook", "")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.model.RemoveActionWebhook(operation)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.model.ActionWebhook(operation)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// Removing it again is fine, as is finishing its action.
+	err = s.model.RemoveActionWebhook(operation)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = actions[0].Finish(state.ActionResults{Status: state.ActionCompleted})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ActionWebhookSuite) TestWatchActionWebhooks(c *gc.C) {
+	w := s.model.WatchActionWebhooks()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewStringsWatcherC(c, s.State, w)
+	wc.AssertChange()
+	wc.AssertNoChange()
+
+	actions := s.addActions(c, 1)
+	operation, err := s.model.AddActionWebhook([]string{actions[0].Id()}, "https://example.com/hook", "")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChange(operation)
+	wc.AssertNoChange()
+
+	_, err = actions[0].Finish(state.ActionResults{Status: state.ActionCompleted})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChange(operation)
+	wc.AssertNoChange()
+}
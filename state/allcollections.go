@@ -4,6 +4,8 @@
 package state
 
 import (
+	"sort"
+
 	"github.com/juju/juju/state/cloudimagemetadata"
 	"gopkg.in/mgo.v2"
 
@@ -18,6 +20,13 @@ var (
 	txnLogSizeTests = 1000000
 )
 
+// The capped collection used for the audit trail of API calls defaults
+// to 100MB, and is likewise tweaked in export_test.go.
+var (
+	auditTrailSize      = 100 * 1024 * 1024
+	auditTrailSizeTests = 1000000
+)
+
 // allCollections should be the single source of truth for information about
 // any collection we use. It's broken up into 4 main sections:
 //
@@ -97,6 +106,20 @@ func allCollections() CollectionSchema {
 		// upgrades and schema migrations.
 		upgradeInfoC: {global: true},
 
+		// This collection records how far long-running upgrade steps
+		// have got, so that they can resume if interrupted.
+		upgradeCheckpointsC: {
+			global:    true,
+			rawAccess: true,
+		},
+
+		// This collection records the upgrade snapshot restores in
+		// progress, so that interrupted ones are finished.
+		upgradeSnapshotRestoresC: {
+			global:    true,
+			rawAccess: true,
+		},
+
 		// This collection holds a convenient representation of the content of
 		// the simplestreams data source pointing to binaries required by juju.
 		//
@@ -120,6 +143,32 @@ func allCollections() CollectionSchema {
 		// destroy empty models.
 		modelEntityRefsC: {global: true},
 
+		// This collection holds the audit trail of API calls made to
+		// the controller, across all models. It is capped, so only
+		// the most recent calls are kept.
+		auditTrailC: {
+			global:    true,
+			rawAccess: true,
+			explicitCreate: &mgo.CollectionInfo{
+				Capped:   true,
+				MaxBytes: auditTrailSize,
+			},
+			indexes: []mgo.Index{{
+				Key: []string{"model-uuid", "-time"},
+			}, {
+				Key: []string{"user", "-time"},
+			}, {
+				Key: []string{"-time"},
+			}},
+		},
+
+		// This collection holds the number and size of the documents of
+		// each model in each model collection, as last collected.
+		modelStatsC: {
+			global:    true,
+			rawAccess: true,
+		},
+
 		// This collection is holds the parameters for model migrations.
 		migrationsC: {
 			global: true,
@@ -163,9 +212,22 @@ func allCollections() CollectionSchema {
 		// different models at a time.
 		usermodelnameC: {global: true},
 
+		// This collection is used as a unique key restraint on model
+		// aliases, and to find the model that an alias refers to.
+		modelAliasesC: {global: true},
+
 		// This collection holds cloud definitions.
 		cloudsC: {global: true},
 
+		// This collection holds model-scoped API keys issued to users
+		// for automation.
+		apiKeysC: {
+			global: true,
+			indexes: []mgo.Index{{
+				Key: []string{"model-uuid"},
+			}},
+		},
+
 		// This collection holds users' cloud credentials.
 		cloudCredentialsC: {
 			global: true,
@@ -331,10 +393,22 @@ func allCollections() CollectionSchema {
 		rebootC:      {},
 		sshHostKeysC: {},
 
+		// This collection holds the user-data published for machines
+		// that are provisioned by network boot, until it is fetched.
+		networkBootC: {
+			indexes: []mgo.Index{{
+				Key: []string{"model-uuid", "tokenhash"},
+			}},
+		},
+
 		// This collection contains information from removed machines
 		// that needs to be cleaned up in the provider.
 		machineRemovalsC: {},
 
+		// This collection records the version for which each machine
+		// agent last completed its upgrade steps.
+		upgradeStepsCompletedC: {},
+
 		// this collection contains machine update locks whose existence indicates
 		// that a particular machine in the process of performing a series upgrade.
 		machineUpgradeSeriesLocksC: {
@@ -399,7 +473,35 @@ func allCollections() CollectionSchema {
 			}},
 		},
 		endpointBindingsC: {},
-		openedPortsC:      {},
+
+		// This collection holds the ports opened by each unit on
+		// each subnet of its machine.
+		openedPortsC: {
+			indexes: []mgo.Index{{
+				Key: []string{"model-uuid", "machine-id", "subnet-id"},
+			}, {
+				Key: []string{"model-uuid", "unit-name"},
+			}},
+		},
+
+		// This collection records the port ranges opened and closed on
+		// machines, trimmed to the most recent changes of each machine.
+		// Changes are recorded and trimmed outside of transactions, but
+		// are removed along with their machine.
+		portsHistoryC: {
+			indexes: []mgo.Index{{
+				Key: []string{"model-uuid", "machine-id", "-updated"},
+			}},
+		},
+
+		// This collection records the scale plans applied to the model,
+		// by the idempotency token they were applied with.
+		scaleOperationsC: {},
+
+		// This collection records the mutating API calls made with
+		// client-supplied idempotency keys, so that retries of them
+		// are not applied twice.
+		idempotencyKeysC: {},
 
 		// -----
 
@@ -411,6 +513,10 @@ func allCollections() CollectionSchema {
 		},
 		actionNotificationsC: {},
 
+		// This collection holds the webhooks to invoke when all of the
+		// actions of an operation have completed.
+		actionWebhooksC: {},
+
 		// -----
 
 		// This collection holds information associated with charm payloads.
@@ -537,6 +643,17 @@ func allCollections() CollectionSchema {
 	return result
 }
 
+// CollectionNames returns the names of all the collections used by
+// state, sorted.
+func CollectionNames() []string {
+	var names []string
+	for name := range allCollections() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // These constants are used to avoid sprinkling the package with any more
 // magic strings. If a collection deserves documentation, please document
 // it in allCollections, above; and please keep this list sorted for easy
@@ -545,7 +662,10 @@ const (
 	actionNotificationsC       = "actionnotifications"
 	actionresultsC             = "actionresults"
 	actionsC                   = "actions"
+	actionWebhooksC            = "actionwebhooks"
 	annotationsC               = "annotations"
+	apiKeysC                   = "apikeys"
+	auditTrailC                = "audittrail"
 	autocertCacheC             = "autocertCache"
 	assignUnitC                = "assignUnits"
 	bakeryStorageItemsC        = "bakeryStorageItems"
@@ -571,6 +691,7 @@ const (
 	globalSettingsC            = "globalSettings"
 	guimetadataC               = "guimetadata"
 	guisettingsC               = "guisettings"
+	idempotencyKeysC           = "idempotencykeys"
 	instanceDataC              = "instanceData"
 	leasesC                    = "leases"
 	leaseHoldersC              = "leaseholders"
@@ -585,19 +706,24 @@ const (
 	migrationsC                = "migrations"
 	migrationsMinionSyncC      = "migrations.minionsync"
 	migrationsStatusC          = "migrations.status"
+	modelAliasesC              = "modelaliases"
 	modelUserLastConnectionC   = "modelUserLastConnection"
 	modelUsersC                = "modelusers"
 	modelsC                    = "models"
+	modelStatsC                = "modelstats"
 	modelEntityRefsC           = "modelEntityRefs"
+	networkBootC               = "networkboot"
 	openedPortsC               = "openedPorts"
 	payloadsC                  = "payloads"
 	permissionsC               = "permissions"
+	portsHistoryC              = "portshistory"
 	podSpecsC                  = "podSpecs"
 	providerIDsC               = "providerIDs"
 	rebootC                    = "reboot"
 	relationScopesC            = "relationscopes"
 	relationsC                 = "relations"
 	restoreInfoC               = "restoreInfo"
+	scaleOperationsC           = "scaleoperations"
 	sequenceC                  = "sequence"
 	applicationsC              = "applications"
 	endpointBindingsC          = "endpointbindings"
@@ -621,6 +747,9 @@ const (
 	txnsC                      = "txns"
 	unitsC                     = "units"
 	upgradeInfoC               = "upgradeInfo"
+	upgradeCheckpointsC        = "upgradeCheckpoints"
+	upgradeSnapshotRestoresC   = "upgradeSnapshotRestores"
+	upgradeStepsCompletedC     = "upgradeStepsCompleted"
 	userLastLoginC             = "userLastLogin"
 	usermodelnameC             = "usermodelname"
 	usersC                     = "users"
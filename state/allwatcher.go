@@ -5,6 +5,7 @@ package state
 
 import (
 	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/juju/errors"
@@ -842,6 +843,24 @@ func (r *backingRelation) updated(st *State, store *multiwatcherStore, id string
 		Id:        r.Id,
 		Endpoints: eps,
 	}
+	if oldInfo := store.Get(info.EntityId()); oldInfo != nil {
+		// The entry already exists, so preserve the current status.
+		info.Status = oldInfo.(*multiwatcher.RelationInfo).Status
+	} else {
+		key := relationGlobalScope(r.Id)
+		relationStatus, err := getStatus(st.db(), key, "relation")
+		if err != nil && !errors.IsNotFound(err) {
+			return errors.Annotatef(err, "reading relation status for key %s", key)
+		}
+		if err == nil {
+			info.Status = multiwatcher.StatusInfo{
+				Current: relationStatus.Status,
+				Message: relationStatus.Message,
+				Data:    normaliseStatusData(relationStatus.Data),
+				Since:   relationStatus.Since,
+			}
+		}
+	}
 	store.Update(info)
 	return nil
 }
@@ -927,6 +946,9 @@ func (s *backingStatus) toStatusInfo() multiwatcher.StatusInfo {
 }
 
 func (s *backingStatus) updated(st *State, store *multiwatcherStore, id string) error {
+	if strings.HasPrefix(id, "r#") {
+		return s.updatedRelationStatus(st, store, id)
+	}
 	parentID, ok := backingEntityIdForGlobalKey(st.ModelUUID(), id)
 	if !ok {
 		return nil
@@ -1040,6 +1062,38 @@ func (s *backingStatus) updatedUnitStatus(st *State, store *multiwatcherStore, i
 	return nil
 }
 
+// updatedRelationStatus updates the status of the relation with the
+// given global key. Relation status is keyed by relation id, whereas
+// relations are tracked by key, so the relation must be read to find
+// its entry.
+func (s *backingStatus) updatedRelationStatus(st *State, store *multiwatcherStore, id string) error {
+	relId, err := strconv.Atoi(strings.TrimPrefix(id, "r#"))
+	if err != nil {
+		return nil
+	}
+	rel, err := st.Relation(relId)
+	if errors.IsNotFound(err) {
+		// The relation has been removed, and will be removed
+		// from the store too.
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	info0 := store.Get((&multiwatcher.RelationInfo{
+		ModelUUID: st.ModelUUID(),
+		Key:       rel.String(),
+	}).EntityId())
+	info, ok := info0.(*multiwatcher.RelationInfo)
+	if !ok {
+		// The relation info doesn't exist. Ignore the status until it does.
+		return nil
+	}
+	newInfo := *info
+	newInfo.Status = s.toStatusInfo()
+	store.Update(&newInfo)
+	return nil
+}
+
 func (s *backingStatus) removed(*multiwatcherStore, string, string, *State) error {
 	// If the status is removed, the parent will follow not long after,
 	// so do nothing.
@@ -23,6 +23,7 @@ import (
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/core/network"
+	corerelation "github.com/juju/juju/core/relation"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/state/multiwatcher"
 	"github.com/juju/juju/state/watcher"
@@ -209,6 +210,11 @@ func (s *allWatcherBaseSuite) setUpScenario(c *gc.C, st *State, units int, inclu
 		Endpoints: []multiwatcher.Endpoint{
 			{ApplicationName: "logging", Relation: multiwatcher.CharmRelation{Name: "logging-directory", Role: "requirer", Interface: "logging", Optional: false, Limit: 1, Scope: "container"}},
 			{ApplicationName: "wordpress", Relation: multiwatcher.CharmRelation{Name: "logging-dir", Role: "provider", Interface: "logging", Optional: false, Limit: 0, Scope: "container"}}},
+		Status: multiwatcher.StatusInfo{
+			Current: "joining",
+			Data:    map[string]interface{}{},
+			Since:   &now,
+		},
 	})
 
 	for i := 0; i < units; i++ {
@@ -389,6 +395,11 @@ func (s *allWatcherBaseSuite) setUpScenario(c *gc.C, st *State, units int, inclu
 		Endpoints: []multiwatcher.Endpoint{
 			{ApplicationName: "mysql", Relation: multiwatcher.CharmRelation{Name: "server", Role: "provider", Interface: "mysql", Optional: false, Limit: 0, Scope: "global"}},
 			{ApplicationName: "remote-wordpress2", Relation: multiwatcher.CharmRelation{Name: "db", Role: "requirer", Interface: "mysql", Optional: false, Limit: 0, Scope: "global"}}},
+		Status: multiwatcher.StatusInfo{
+			Current: "joining",
+			Data:    map[string]interface{}{},
+			Since:   &now,
+		},
 	})
 
 	_, applicationOfferInfo, rel2 := addTestingApplicationOffer(
@@ -401,6 +412,11 @@ func (s *allWatcherBaseSuite) setUpScenario(c *gc.C, st *State, units int, inclu
 		Endpoints: []multiwatcher.Endpoint{
 			{ApplicationName: "mysql", Relation: multiwatcher.CharmRelation{Name: "server", Role: "provider", Interface: "mysql", Optional: false, Limit: 0, Scope: "global"}},
 			{ApplicationName: "remote-wordpress", Relation: multiwatcher.CharmRelation{Name: "db", Role: "requirer", Interface: "mysql", Optional: false, Limit: 0, Scope: "global"}}},
+		Status: multiwatcher.StatusInfo{
+			Current: "joining",
+			Data:    map[string]interface{}{},
+			Since:   &now,
+		},
 	})
 	if includeOffers {
 		add(&applicationOfferInfo)
@@ -824,7 +840,7 @@ func (s *allWatcherStateSuite) TestClosingPorts(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	err = b.Changed(all, watcher.Change{
 		C:  openedPortsC,
-		Id: s.state.docID("m#0#0.1.2.0/24"),
+		Id: s.state.docID("m#0#0.1.2.0/24#u#wordpress/0"),
 	})
 	c.Assert(err, jc.ErrorIsNil)
 	entities = all.All()
@@ -2584,6 +2600,7 @@ func testChangeRelations(c *gc.C, owner names.UserTag, runChangeTests func(*gc.C
 			c.Assert(err, jc.ErrorIsNil)
 			_, err = st.AddRelation(eps...)
 			c.Assert(err, jc.ErrorIsNil)
+			now := st.clock().Now()
 
 			return changeTestCase{
 				about: "relation is added if it's in backing but not in Store",
@@ -2598,6 +2615,57 @@ func testChangeRelations(c *gc.C, owner names.UserTag, runChangeTests func(*gc.C
 						Endpoints: []multiwatcher.Endpoint{
 							{ApplicationName: "logging", Relation: multiwatcher.CharmRelation{Name: "logging-directory", Role: "requirer", Interface: "logging", Optional: false, Limit: 1, Scope: "container"}},
 							{ApplicationName: "wordpress", Relation: multiwatcher.CharmRelation{Name: "logging-dir", Role: "provider", Interface: "logging", Optional: false, Limit: 0, Scope: "container"}}},
+						Status: multiwatcher.StatusInfo{
+							Current: "joining",
+							Data:    map[string]interface{}{},
+							Since:   &now,
+						},
+					}}}
+		},
+		func(c *gc.C, st *State) changeTestCase {
+			AddTestingApplication(c, st, "wordpress", AddTestingCharm(c, st, "wordpress"))
+			AddTestingApplication(c, st, "logging", AddTestingCharm(c, st, "logging"))
+			eps, err := st.InferEndpoints("logging", "wordpress")
+			c.Assert(err, jc.ErrorIsNil)
+			rel, err := st.AddRelation(eps...)
+			c.Assert(err, jc.ErrorIsNil)
+			reason := corerelation.StatusReason{
+				Reason:              corerelation.ReasonDeparted,
+				DepartedApplication: "logging",
+			}
+			err = rel.SetStatus(status.StatusInfo{
+				Status:  status.Broken,
+				Message: reason.String(),
+				Data:    reason.Data(),
+			})
+			c.Assert(err, jc.ErrorIsNil)
+			now := st.clock().Now()
+
+			return changeTestCase{
+				about: "status is changed if the relation exists in the store",
+				initialContents: []multiwatcher.EntityInfo{&multiwatcher.RelationInfo{
+					ModelUUID: st.ModelUUID(),
+					Key:       "logging:logging-directory wordpress:logging-dir",
+					Id:        rel.Id(),
+					Status: multiwatcher.StatusInfo{
+						Current: "joining",
+					},
+				}},
+				change: watcher.Change{
+					C:  "statuses",
+					Id: st.docID(fmt.Sprintf("r#%d", rel.Id())),
+				},
+				expectContents: []multiwatcher.EntityInfo{
+					&multiwatcher.RelationInfo{
+						ModelUUID: st.ModelUUID(),
+						Key:       "logging:logging-directory wordpress:logging-dir",
+						Id:        rel.Id(),
+						Status: multiwatcher.StatusInfo{
+							Current: "broken",
+							Message: `application "logging" departed`,
+							Data:    reason.Data(),
+							Since:   &now,
+						},
 					}}}
 		},
 	}
@@ -3242,7 +3310,7 @@ func testChangeUnits(c *gc.C, owner names.UserTag, runChangeTests func(*gc.C, []
 				},
 				change: watcher.Change{
 					C:  openedPortsC,
-					Id: st.docID("m#0#"),
+					Id: st.docID("m#0##u#wordpress/0"),
 				},
 				expectContents: []multiwatcher.EntityInfo{
 					&multiwatcher.UnitInfo{
@@ -0,0 +1,261 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"gopkg.in/juju/names.v3"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/permission"
+)
+
+// APIKey represents a revocable credential that grants scoped access
+// to a single model, on behalf of the user that issued it. API keys
+// are intended for automation such as CI systems, which should not
+// need full user accounts or shared passwords.
+type APIKey struct {
+	st  *State
+	doc apiKeyDoc
+}
+
+type apiKeyDoc struct {
+	DocID        string    `bson:"_id"`
+	ModelUUID    string    `bson:"model-uuid"`
+	Owner        string    `bson:"owner"`
+	Access       string    `bson:"access"`
+	PasswordHash string    `bson:"passwordhash"`
+	PasswordSalt string    `bson:"passwordsalt"`
+	Created      time.Time `bson:"created"`
+	Expires      time.Time `bson:"expires,omitempty"`
+	Revoked      bool      `bson:"revoked"`
+}
+
+// AddAPIKeyArgs holds the arguments for issuing a new API key.
+type AddAPIKeyArgs struct {
+	// Model is the model the key grants access to.
+	Model names.ModelTag
+
+	// Owner is the user on whose behalf the key acts. The key can
+	// never grant more access than the owner has on the model.
+	Owner names.UserTag
+
+	// Access is the maximum model access level granted by the key.
+	Access permission.Access
+
+	// Expires, if non-zero, is the time after which the key is no
+	// longer accepted.
+	Expires time.Time
+}
+
+// Validate checks that the arguments are sensible.
+func (args AddAPIKeyArgs) Validate() error {
+	if args.Model.Id() == "" {
+		return errors.NotValidf("empty model")
+	}
+	if args.Owner.Id() == "" {
+		return errors.NotValidf("empty owner")
+	}
+	if err := permission.ValidateModelAccess(args.Access); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// apiKeySeparator separates the key ID from the secret in the token
+// presented by clients.
+const apiKeySeparator = ":"
+
+// APIKeyToken returns the token a client presents to log in using the
+// API key with the given ID and secret.
+func APIKeyToken(id, secret string) string {
+	return id + apiKeySeparator + secret
+}
+
+// ParseAPIKeyToken splits a token returned by APIKeyToken into the key
+// ID and secret.
+func ParseAPIKeyToken(token string) (id, secret string, err error) {
+	parts := strings.SplitN(token, apiKeySeparator, 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.NotValidf("API key")
+	}
+	return parts[0], parts[1], nil
+}
+
+// AddAPIKey issues a new API key, returning the key along with the
+// secret which must be presented when logging in. The secret is not
+// stored and cannot be retrieved later.
+func (st *State) AddAPIKey(args AddAPIKeyArgs) (*APIKey, string, error) {
+	if err := args.Validate(); err != nil {
+		return nil, "", errors.Annotate(err, "cannot add API key")
+	}
+	uuid, err := utils.NewUUID()
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	secret, err := utils.RandomPassword()
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	salt, err := utils.RandomSalt()
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	doc := apiKeyDoc{
+		DocID:        uuid.String(),
+		ModelUUID:    args.Model.Id(),
+		Owner:        args.Owner.Id(),
+		Access:       string(args.Access),
+		PasswordHash: utils.UserPasswordHash(secret, salt),
+		PasswordSalt: salt,
+		Created:      st.clock().Now().UTC().Round(time.Second),
+	}
+	if !args.Expires.IsZero() {
+		doc.Expires = args.Expires.UTC().Round(time.Second)
+	}
+	ops := []txn.Op{{
+		C:      modelsC,
+		Id:     doc.ModelUUID,
+		Assert: isAliveDoc,
+	}, {
+		C:      apiKeysC,
+		Id:     doc.DocID,
+		Assert: txn.DocMissing,
+		Insert: &doc,
+	}}
+	if err := st.db().RunTransaction(ops); err != nil {
+		if err == txn.ErrAborted {
+			err = errors.Errorf("model %q not found or not alive", doc.ModelUUID)
+		}
+		return nil, "", errors.Annotate(err, "cannot add API key")
+	}
+	return &APIKey{st: st, doc: doc}, secret, nil
+}
+
+// APIKey returns the API key with the given ID.
+func (st *State) APIKey(id string) (*APIKey, error) {
+	coll, closer := st.db().GetCollection(apiKeysC)
+	defer closer()
+
+	var doc apiKeyDoc
+	err := coll.FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("API key %q", id)
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get API key %q", id)
+	}
+	return &APIKey{st: st, doc: doc}, nil
+}
+
+// ModelAPIKeys returns all API keys, including revoked and expired
+// ones, that were issued for the specified model.
+func (st *State) ModelAPIKeys(model names.ModelTag) ([]*APIKey, error) {
+	coll, closer := st.db().GetCollection(apiKeysC)
+	defer closer()
+
+	var docs []apiKeyDoc
+	if err := coll.Find(bson.D{{"model-uuid", model.Id()}}).All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get API keys for model %q", model.Id())
+	}
+	keys := make([]*APIKey, len(docs))
+	for i, doc := range docs {
+		keys[i] = &APIKey{st: st, doc: doc}
+	}
+	return keys, nil
+}
+
+// Id returns the unique identifier of the key.
+func (k *APIKey) Id() string {
+	return k.doc.DocID
+}
+
+// String returns a human readable description of the key.
+func (k *APIKey) String() string {
+	return fmt.Sprintf("API key %q", k.doc.DocID)
+}
+
+// ModelTag returns the tag of the model the key grants access to.
+func (k *APIKey) ModelTag() names.ModelTag {
+	return names.NewModelTag(k.doc.ModelUUID)
+}
+
+// Owner returns the user on whose behalf the key acts.
+func (k *APIKey) Owner() names.UserTag {
+	return names.NewUserTag(k.doc.Owner)
+}
+
+// Access returns the maximum model access level granted by the key.
+func (k *APIKey) Access() permission.Access {
+	return permission.Access(k.doc.Access)
+}
+
+// Created returns the time the key was issued.
+func (k *APIKey) Created() time.Time {
+	return k.doc.Created.UTC()
+}
+
+// Expires returns the time after which the key is no longer accepted.
+// The zero time means the key never expires.
+func (k *APIKey) Expires() time.Time {
+	if k.doc.Expires.IsZero() {
+		return time.Time{}
+	}
+	return k.doc.Expires.UTC()
+}
+
+// Expired reports whether the key has expired at the given time.
+func (k *APIKey) Expired(now time.Time) bool {
+	return !k.doc.Expires.IsZero() && !now.Before(k.doc.Expires)
+}
+
+// Revoked reports whether the key has been revoked.
+func (k *APIKey) Revoked() bool {
+	return k.doc.Revoked
+}
+
+// SecretValid reports whether the given secret matches the one issued
+// with the key, and the key has not been revoked.
+func (k *APIKey) SecretValid(secret string) bool {
+	if k.doc.Revoked || k.doc.PasswordSalt == "" {
+		return false
+	}
+	return utils.UserPasswordHash(secret, k.doc.PasswordSalt) == k.doc.PasswordHash
+}
+
+// Revoke permanently invalidates the key. Revoking a key that has
+// already been revoked is not an error.
+func (k *APIKey) Revoke() error {
+	ops := []txn.Op{{
+		C:      apiKeysC,
+		Id:     k.doc.DocID,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"revoked", true}}}},
+	}}
+	if err := k.st.db().RunTransaction(ops); err != nil {
+		if err == txn.ErrAborted {
+			err = errors.NotFoundf("API key %q", k.doc.DocID)
+		}
+		return errors.Annotatef(err, "cannot revoke API key %q", k.doc.DocID)
+	}
+	k.doc.Revoked = true
+	return nil
+}
+
+// Refresh reloads the key's details from state.
+func (k *APIKey) Refresh() error {
+	other, err := k.st.APIKey(k.doc.DocID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	k.doc = other.doc
+	return nil
+}
@@ -0,0 +1,155 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state"
+)
+
+type APIKeySuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&APIKeySuite{})
+
+func (s *APIKeySuite) addKey(c *gc.C, access permission.Access, expires time.Time) (*state.APIKey, string) {
+	key, secret, err := s.State.AddAPIKey(state.AddAPIKeyArgs{
+		Model:   s.Model.ModelTag(),
+		Owner:   s.Owner,
+		Access:  access,
+		Expires: expires,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return key, secret
+}
+
+func (s *APIKeySuite) TestAddAPIKey(c *gc.C) {
+	expires := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	key, secret := s.addKey(c, permission.WriteAccess, expires)
+	c.Assert(key.Id(), gc.Not(gc.Equals), "")
+	c.Assert(secret, gc.Not(gc.Equals), "")
+	c.Assert(key.ModelTag(), gc.Equals, s.Model.ModelTag())
+	c.Assert(key.Owner(), gc.Equals, s.Owner)
+	c.Assert(key.Access(), gc.Equals, permission.WriteAccess)
+	c.Assert(key.Expires(), gc.Equals, expires)
+	c.Assert(key.Revoked(), jc.IsFalse)
+	c.Assert(key.SecretValid(secret), jc.IsTrue)
+	c.Assert(key.SecretValid("wrong"), jc.IsFalse)
+
+	found, err := s.State.APIKey(key.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found.Owner(), gc.Equals, s.Owner)
+	c.Assert(found.Created(), gc.Equals, key.Created())
+	c.Assert(found.SecretValid(secret), jc.IsTrue)
+}
+
+func (s *APIKeySuite) TestAddAPIKeyInvalidAccess(c *gc.C) {
+	_, _, err := s.State.AddAPIKey(state.AddAPIKeyArgs{
+		Model:  s.Model.ModelTag(),
+		Owner:  s.Owner,
+		Access: permission.SuperuserAccess,
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot add API key: "superuser" model access not valid`)
+}
+
+func (s *APIKeySuite) TestAddAPIKeyUnknownModel(c *gc.C) {
+	_, _, err := s.State.AddAPIKey(state.AddAPIKeyArgs{
+		Model:  names.NewModelTag("deadbeef-0bad-400d-8000-4b1d0d06f00d"),
+		Owner:  s.Owner,
+		Access: permission.ReadAccess,
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot add API key: model "deadbeef-0bad-400d-8000-4b1d0d06f00d" not found or not alive`)
+}
+
+func (s *APIKeySuite) TestRemoveModelRemovesAPIKeys(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	model, err := st.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	_, _, err = s.State.AddAPIKey(state.AddAPIKeyArgs{
+		Model:  model.ModelTag(),
+		Owner:  model.Owner(),
+		Access: permission.ReadAccess,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	other, _ := s.addKey(c, permission.ReadAccess, time.Time{})
+
+	c.Assert(model.Destroy(state.DestroyModelParams{}), jc.ErrorIsNil)
+	c.Assert(st.RemoveDyingModel(), jc.ErrorIsNil)
+
+	keys, err := s.State.ModelAPIKeys(model.ModelTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keys, gc.HasLen, 0)
+
+	// Keys for other models are left alone.
+	keys, err = s.State.ModelAPIKeys(s.Model.ModelTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keys, gc.HasLen, 1)
+	c.Assert(keys[0].Id(), gc.Equals, other.Id())
+}
+
+func (s *APIKeySuite) TestAPIKeyNotFound(c *gc.C) {
+	_, err := s.State.APIKey("missing")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *APIKeySuite) TestModelAPIKeys(c *gc.C) {
+	key1, _ := s.addKey(c, permission.ReadAccess, time.Time{})
+	key2, _ := s.addKey(c, permission.AdminAccess, time.Time{})
+
+	keys, err := s.State.ModelAPIKeys(s.Model.ModelTag())
+	c.Assert(err, jc.ErrorIsNil)
+	var ids []string
+	for _, key := range keys {
+		ids = append(ids, key.Id())
+	}
+	c.Assert(ids, jc.SameContents, []string{key1.Id(), key2.Id()})
+}
+
+func (s *APIKeySuite) TestRevoke(c *gc.C) {
+	key, secret := s.addKey(c, permission.ReadAccess, time.Time{})
+	err := key.Revoke()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(key.Revoked(), jc.IsTrue)
+	c.Assert(key.SecretValid(secret), jc.IsFalse)
+
+	found, err := s.State.APIKey(key.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found.Revoked(), jc.IsTrue)
+
+	// Revoking twice is fine.
+	err = found.Revoke()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *APIKeySuite) TestExpired(c *gc.C) {
+	expires := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	key, _ := s.addKey(c, permission.ReadAccess, expires)
+	c.Assert(key.Expired(expires.Add(-time.Second)), jc.IsFalse)
+	c.Assert(key.Expired(expires), jc.IsTrue)
+
+	key, _ = s.addKey(c, permission.ReadAccess, time.Time{})
+	c.Assert(key.Expired(expires), jc.IsFalse)
+}
+
+func (s *APIKeySuite) TestAPIKeyToken(c *gc.C) {
+	token := state.APIKeyToken("id", "sec:ret")
+	id, secret, err := state.ParseAPIKeyToken(token)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, gc.Equals, "id")
+	c.Assert(secret, gc.Equals, "sec:ret")
+
+	for _, bad := range []string{"", "id", ":secret", "id:"} {
+		_, _, err = state.ParseAPIKeyToken(bad)
+		c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	}
+}
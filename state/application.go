@@ -6,6 +6,7 @@ package state
 import (
 	stderrors "errors"
 	"fmt"
+	"net"
 	"sort"
 	"strconv"
 	"strings"
@@ -69,6 +70,18 @@ type applicationDoc struct {
 	PasswordHash string `bson:"passwordhash"`
 	// Placement is the placement directive that should be used allocating units/pods.
 	Placement string `bson:"placement,omitempty"`
+
+	// SubordinatePolicy controls which principal units a subordinate
+	// application attaches to.
+	SubordinatePolicy *subordinatePolicyDoc `bson:"subordinate-policy,omitempty"`
+
+	// EgressRules holds the outgoing traffic allowed from the
+	// application's units.
+	EgressRules []egressRuleDoc `bson:"egress-rules,omitempty"`
+
+	// ExposedEndpoints holds the sources allowed to reach each
+	// exposed endpoint of the application, keyed by endpoint name.
+	ExposedEndpoints map[string]ExposedEndpoint `bson:"exposed-endpoints,omitempty"`
 }
 
 func newApplication(st *State, doc *applicationDoc) *Application {
@@ -595,26 +608,256 @@ func (a *Application) SetExposed() error {
 	return a.setExposed(true)
 }
 
-// ClearExposed removes the exposed flag from the application.
+// ClearExposed removes the exposed flag from the application, along
+// with any per-endpoint expose settings.
 // See SetExposed and IsExposed.
 func (a *Application) ClearExposed() error {
 	return a.setExposed(false)
 }
 
 func (a *Application) setExposed(exposed bool) (err error) {
+	update := bson.D{{"$set", bson.D{{"exposed", exposed}}}}
+	if !exposed {
+		update = append(update, bson.DocElem{"$unset", bson.D{{"exposed-endpoints", nil}}})
+	}
 	ops := []txn.Op{{
 		C:      applicationsC,
 		Id:     a.doc.DocID,
 		Assert: isAliveDoc,
-		Update: bson.D{{"$set", bson.D{{"exposed", exposed}}}},
+		Update: update,
 	}}
 	if err := a.st.db().RunTransaction(ops); err != nil {
 		return errors.Errorf("cannot set exposed flag for application %q to %v: %v", a, exposed, onAbort(err, applicationNotAliveErr))
 	}
 	a.doc.Exposed = exposed
+	if !exposed {
+		a.doc.ExposedEndpoints = nil
+	}
 	return nil
 }
 
+// WildcardEndpoint is the endpoint name used in expose settings to
+// refer to every endpoint of an application.
+const WildcardEndpoint = ""
+
+// ExposedEndpoint describes the sources which may reach the ports
+// opened by an exposed application through one of its endpoints.
+// If neither spaces nor CIDRs are specified, the endpoint may be
+// reached from anywhere the model's firewall rules allow.
+type ExposedEndpoint struct {
+	// ExposeToSpaceIDs holds the IDs of the spaces whose subnets
+	// may reach the endpoint.
+	ExposeToSpaceIDs []string `bson:"to-space-ids,omitempty"`
+
+	// ExposeToCIDRs holds the CIDRs which may reach the endpoint.
+	ExposeToCIDRs []string `bson:"to-cidrs,omitempty"`
+}
+
+// ExposedEndpoints returns the expose settings of the application,
+// keyed by endpoint name. No settings means that every endpoint is
+// exposed without restriction whenever the application is exposed.
+func (a *Application) ExposedEndpoints() map[string]ExposedEndpoint {
+	if len(a.doc.ExposedEndpoints) == 0 {
+		return nil
+	}
+	result := make(map[string]ExposedEndpoint, len(a.doc.ExposedEndpoints))
+	for name, exposed := range a.doc.ExposedEndpoints {
+		result[name] = exposed
+	}
+	return result
+}
+
+// MergeExposeSettings marks the application as exposed and merges the
+// given per-endpoint settings into any existing ones, replacing the
+// settings of endpoints which are specified in both.
+func (a *Application) MergeExposeSettings(exposedEndpoints map[string]ExposedEndpoint) error {
+	exposedEndpoints, err := a.validateExposeSettings(exposedEndpoints)
+	if err != nil {
+		return errors.Annotatef(err, "cannot expose application %q", a.doc.Name)
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := a.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if a.doc.Life != Alive {
+			return nil, applicationNotAliveErr
+		}
+		merged := a.ExposedEndpoints()
+		if merged == nil {
+			merged = make(map[string]ExposedEndpoint)
+		}
+		for name, exposed := range exposedEndpoints {
+			merged[name] = exposed
+		}
+		ops := []txn.Op{{
+			C:  applicationsC,
+			Id: a.doc.DocID,
+			Assert: append(isAliveDoc,
+				bson.DocElem{"txn-revno", a.doc.TxnRevno},
+			),
+			Update: bson.D{{"$set", bson.D{
+				{"exposed", true},
+				{"exposed-endpoints", merged},
+			}}},
+		}}
+		// The spaces exposed to must not be removed underneath us.
+		spaceOps, err := a.exposeSpacesAliveOps(exposedEndpoints)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, spaceOps...), nil
+	}
+	if err := a.st.db().Run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot expose application %q", a.doc.Name)
+	}
+	return errors.Trace(a.Refresh())
+}
+
+// validateExposeSettings checks that the given endpoints exist and that
+// the spaces they are exposed to exist. It returns a copy of the settings
+// with normalised CIDRs.
+func (a *Application) validateExposeSettings(exposedEndpoints map[string]ExposedEndpoint) (map[string]ExposedEndpoint, error) {
+	endpoints, err := a.Endpoints()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	known := set.NewStrings(WildcardEndpoint)
+	for _, ep := range endpoints {
+		known.Add(ep.Name)
+	}
+	result := make(map[string]ExposedEndpoint, len(exposedEndpoints))
+	for name, exposed := range exposedEndpoints {
+		if !known.Contains(name) {
+			return nil, errors.NotValidf("endpoint %q", name)
+		}
+		for _, spaceID := range exposed.ExposeToSpaceIDs {
+			if _, err := a.st.SpaceByID(spaceID); err != nil {
+				return nil, errors.Annotatef(err, "endpoint %q", name)
+			}
+		}
+		var cidrs []string
+		for _, cidr := range exposed.ExposeToCIDRs {
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				return nil, errors.NotValidf("CIDR %q for endpoint %q", cidr, name)
+			}
+			cidrs = append(cidrs, network.NormaliseCIDR(cidr))
+		}
+		result[name] = ExposedEndpoint{
+			ExposeToSpaceIDs: exposed.ExposeToSpaceIDs,
+			ExposeToCIDRs:    cidrs,
+		}
+	}
+	return result, nil
+}
+
+// exposeSpacesAliveOps returns operations asserting that every space
+// referred to by the given expose settings is alive.
+func (a *Application) exposeSpacesAliveOps(exposedEndpoints map[string]ExposedEndpoint) ([]txn.Op, error) {
+	seen := set.NewStrings()
+	var ops []txn.Op
+	for _, exposed := range exposedEndpoints {
+		for _, spaceID := range exposed.ExposeToSpaceIDs {
+			if seen.Contains(spaceID) {
+				continue
+			}
+			seen.Add(spaceID)
+			space, err := a.st.SpaceByID(spaceID)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if space.Life() != Alive {
+				return nil, errors.Errorf("space %q is not alive", space.Name())
+			}
+			ops = append(ops, txn.Op{
+				C:      spacesC,
+				Id:     space.doc.DocId,
+				Assert: isAliveDoc,
+			})
+		}
+	}
+	return ops, nil
+}
+
+// exposeSettingsReferencingSpace returns the documents of the
+// applications with at least one endpoint exposed to the space with
+// the given ID.
+func exposeSettingsReferencingSpace(st *State, spaceID string) ([]applicationDoc, error) {
+	applications, closer := st.db().GetCollection(applicationsC)
+	defer closer()
+
+	var docs []applicationDoc
+	if err := applications.Find(bson.D{
+		{"exposed-endpoints", bson.D{{"$exists", true}}},
+	}).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot read applications")
+	}
+
+	var result []applicationDoc
+	for _, doc := range docs {
+		if len(exposedEndpointsReferencingSpace(doc, spaceID)) > 0 {
+			result = append(result, doc)
+		}
+	}
+	return result, nil
+}
+
+// exposedEndpointsReferencingSpace returns the sorted names of the
+// endpoints of the application exposed to the space with the given ID.
+func exposedEndpointsReferencingSpace(doc applicationDoc, spaceID string) []string {
+	var endpoints []string
+	for name, exposed := range doc.ExposedEndpoints {
+		for _, id := range exposed.ExposeToSpaceIDs {
+			if id == spaceID {
+				endpoints = append(endpoints, name)
+				break
+			}
+		}
+	}
+	sort.Strings(endpoints)
+	return endpoints
+}
+
+// rewriteSpaceExposeSettingsOps returns the operations required to
+// expose every application endpoint exposed to the space with ID fromID
+// to the space with ID toID instead.
+func rewriteSpaceExposeSettingsOps(st *State, fromID, toID string) ([]txn.Op, error) {
+	docs, err := exposeSettingsReferencingSpace(st, fromID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var ops []txn.Op
+	for _, doc := range docs {
+		// The wildcard endpoint is keyed by the empty string, which
+		// cannot be addressed with a dotted path, so the whole map is
+		// replaced.
+		rewritten := make(map[string]ExposedEndpoint, len(doc.ExposedEndpoints))
+		for name, exposed := range doc.ExposedEndpoints {
+			spaceIDs := set.NewStrings()
+			for _, id := range exposed.ExposeToSpaceIDs {
+				if id == fromID {
+					id = toID
+				}
+				spaceIDs.Add(id)
+			}
+			exposed.ExposeToSpaceIDs = nil
+			if !spaceIDs.IsEmpty() {
+				exposed.ExposeToSpaceIDs = spaceIDs.SortedValues()
+			}
+			rewritten[name] = exposed
+		}
+		ops = append(ops, txn.Op{
+			C:      applicationsC,
+			Id:     doc.DocID,
+			Assert: bson.D{{"txn-revno", doc.TxnRevno}},
+			Update: bson.D{{"$set", bson.D{{"exposed-endpoints", rewritten}}}},
+		})
+	}
+	return ops, nil
+}
+
 // Charm returns the application's charm and whether units should upgrade to that
 // charm even if they are in an error state.
 func (a *Application) Charm() (ch *Charm, force bool, err error) {
@@ -2045,6 +2045,60 @@ func (s *ApplicationSuite) TestApplicationExposed(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, notAliveErr)
 }
 
+func (s *ApplicationSuite) TestMergeExposeSettings(c *gc.C) {
+	space, err := s.State.AddSpace("dmz", "", nil, true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.mysql.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"server": {ExposeToSpaceIDs: []string{space.Id()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.IsExposed(), jc.IsTrue)
+
+	// Settings for other endpoints are merged, those for the same
+	// endpoint are replaced.
+	err = s.mysql.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"server":               {ExposeToCIDRs: []string{"10.0.0.1/8"}},
+		state.WildcardEndpoint: {ExposeToCIDRs: []string{"192.168.0.0/16"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	expected := map[string]state.ExposedEndpoint{
+		"server":               {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+		state.WildcardEndpoint: {ExposeToCIDRs: []string{"192.168.0.0/16"}},
+	}
+	c.Assert(s.mysql.ExposedEndpoints(), jc.DeepEquals, expected)
+	app, err := s.State.Application(s.mysql.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(app.ExposedEndpoints(), jc.DeepEquals, expected)
+
+	// Clearing the exposed flag removes the settings.
+	err = s.mysql.ClearExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.ExposedEndpoints(), gc.HasLen, 0)
+}
+
+func (s *ApplicationSuite) TestMergeExposeSettingsInvalid(c *gc.C) {
+	err := s.mysql.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"bogus": {},
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err, gc.ErrorMatches, `cannot expose application "mysql": endpoint "bogus" not valid`)
+
+	err = s.mysql.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"server": {ExposeToSpaceIDs: []string{"42"}},
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.mysql.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"server": {ExposeToCIDRs: []string{"10.0.0"}},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot expose application "mysql": CIDR "10.0.0" for endpoint "server" not valid`)
+	c.Assert(s.mysql.IsExposed(), jc.IsFalse)
+}
+
 func (s *ApplicationSuite) TestAddUnit(c *gc.C) {
 	// Check that principal units can be added on their own.
 	c.Assert(s.mysql.UnitCount(), gc.Equals, 0)
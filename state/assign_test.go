@@ -291,6 +291,41 @@ func (s *AssignSuite) TestAssignMachineDifferentSeries(c *gc.C) {
 		`cannot assign unit "wordpress/0" to machine 0: series does not match`)
 }
 
+func (s *AssignSuite) TestAssignCordonedMachine(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetCordoned(true)
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, gc.ErrorMatches,
+		`cannot assign unit "wordpress/0" to machine 0: machine is cordoned`)
+
+	err = machine.SetCordoned(false)
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *AssignSuite) TestAssignWithPlacementCordonedMachine(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetCordoned(true)
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.AssignUnitWithPlacement(unit, &instance.Placement{
+		Scope: instance.MachineScope, Directive: machine.Id(),
+	})
+	c.Assert(err, gc.ErrorMatches, `machine "0" is cordoned`)
+	err = s.State.AssignUnitWithPlacement(unit, &instance.Placement{
+		Scope: string(instance.LXD), Directive: machine.Id(),
+	})
+	c.Assert(err, gc.ErrorMatches, `machine "0" is cordoned`)
+}
+
 func (s *AssignSuite) TestPrincipals(c *gc.C) {
 	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
 	c.Assert(err, jc.ErrorIsNil)
@@ -799,6 +834,25 @@ func (s *assignCleanSuite) TestAssignUnitTwiceFails(c *gc.C) {
 
 const eligibleMachinesInUse = "all eligible machines in use"
 
+func (s *assignCleanSuite) TestAssignUnitSkipsCordonedMachines(c *gc.C) {
+	hostMachine, _, cleanEmptyMachine := s.setupMachines(c)
+	// Cordoning the host machine also rules out its container.
+	err := hostMachine.SetCordoned(true)
+	c.Assert(err, jc.ErrorIsNil)
+	err = cleanEmptyMachine.SetCordoned(true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	unit, err := s.wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	m, err := s.assignUnit(unit)
+	c.Assert(m, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, eligibleMachinesInUse)
+
+	err = cleanEmptyMachine.SetCordoned(false)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertAssignUnit(c, cleanEmptyMachine)
+}
+
 func (s *assignCleanSuite) TestAssignToMachineNoneAvailable(c *gc.C) {
 	// Try to assign a unit to a clean (maybe empty) machine and check that we can't.
 	unit, err := s.wordpress.AddUnit(state.AddUnitParams{})
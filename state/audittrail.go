@@ -0,0 +1,150 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// AuditRecord records a single API call made to the controller.
+type AuditRecord struct {
+	// ModelUUID identifies the model the call was made on. It is empty
+	// for calls made on a controller-only connection.
+	ModelUUID string
+
+	// User holds the tag of the entity that made the call, or is empty
+	// if the call was made before logging in.
+	User string
+
+	// ConnectionID identifies the API connection the call was made on.
+	ConnectionID uint64
+
+	// Facade, Version and Method identify the method called.
+	Facade  string
+	Version int
+	Method  string
+
+	// Args holds the arguments of the call, serialised as JSON, with
+	// any secrets redacted.
+	Args string
+
+	// ErrorCode and Error hold the error the call failed with, if any.
+	ErrorCode string
+	Error     string
+
+	// Time records when the call was made.
+	Time time.Time
+
+	// Duration records how long the call took.
+	Duration time.Duration
+}
+
+// AuditRecordFilter selects the audit records returned by
+// State.AuditRecords. Empty fields match all records.
+type AuditRecordFilter struct {
+	// User, if set, selects the calls made by the entity with the
+	// given tag.
+	User string
+
+	// ModelUUID, if set, selects the calls made on the given model.
+	ModelUUID string
+
+	// From and To, if set, select the calls made at or after From, and
+	// before To.
+	From time.Time
+	To   time.Time
+
+	// Limit, if positive, is the maximum number of records returned.
+	Limit int
+}
+
+// auditRecordDoc records a single API call. Records are only ever
+// added, and the capped collection holding them discards the oldest.
+type auditRecordDoc struct {
+	ModelUUID    string `bson:"model-uuid"`
+	User         string `bson:"user"`
+	ConnectionID uint64 `bson:"connection-id"`
+	Facade       string `bson:"facade"`
+	Version      int    `bson:"version"`
+	Method       string `bson:"method"`
+	Args         string `bson:"args,omitempty"`
+	ErrorCode    string `bson:"error-code,omitempty"`
+	Error        string `bson:"error,omitempty"`
+	Time         int64  `bson:"time"`
+	Duration     int64  `bson:"duration"`
+}
+
+// AddAuditRecord adds a record of an API call to the audit trail.
+func (st *State) AddAuditRecord(record AuditRecord) error {
+	coll, closer := st.db().GetRawCollection(auditTrailC)
+	defer closer()
+
+	err := coll.Insert(&auditRecordDoc{
+		ModelUUID:    record.ModelUUID,
+		User:         record.User,
+		ConnectionID: record.ConnectionID,
+		Facade:       record.Facade,
+		Version:      record.Version,
+		Method:       record.Method,
+		Args:         record.Args,
+		ErrorCode:    record.ErrorCode,
+		Error:        record.Error,
+		Time:         record.Time.UnixNano(),
+		Duration:     int64(record.Duration),
+	})
+	return errors.Annotate(err, "cannot add audit record")
+}
+
+// AuditRecords returns the audit records that match the given filter,
+// newest first.
+func (st *State) AuditRecords(filter AuditRecordFilter) ([]AuditRecord, error) {
+	coll, closer := st.db().GetRawCollection(auditTrailC)
+	defer closer()
+
+	query := bson.M{}
+	if filter.User != "" {
+		query["user"] = filter.User
+	}
+	if filter.ModelUUID != "" {
+		query["model-uuid"] = filter.ModelUUID
+	}
+	timeQuery := bson.M{}
+	if !filter.From.IsZero() {
+		timeQuery["$gte"] = filter.From.UnixNano()
+	}
+	if !filter.To.IsZero() {
+		timeQuery["$lt"] = filter.To.UnixNano()
+	}
+	if len(timeQuery) > 0 {
+		query["time"] = timeQuery
+	}
+	q := coll.Find(query).Sort("-time", "-_id")
+	if filter.Limit > 0 {
+		q = q.Limit(filter.Limit)
+	}
+	var docs []auditRecordDoc
+	if err := q.All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get audit records")
+	}
+	records := make([]AuditRecord, len(docs))
+	for i, doc := range docs {
+		records[i] = AuditRecord{
+			ModelUUID:    doc.ModelUUID,
+			User:         doc.User,
+			ConnectionID: doc.ConnectionID,
+			Facade:       doc.Facade,
+			Version:      doc.Version,
+			Method:       doc.Method,
+			Args:         doc.Args,
+			ErrorCode:    doc.ErrorCode,
+			Error:        doc.Error,
+			Time:         time.Unix(0, doc.Time).UTC(),
+			Duration:     time.Duration(doc.Duration),
+		}
+	}
+	return records, nil
+}
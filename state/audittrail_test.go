@@ -0,0 +1,112 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type AuditTrailSuite struct {
+	ConnSuite
+	start time.Time
+}
+
+var _ = gc.Suite(&AuditTrailSuite{})
+
+func (s *AuditTrailSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.start = time.Date(2019, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i, user := range []string{"user-bob", "user-mary", "user-bob"} {
+		err := s.State.AddAuditRecord(state.AuditRecord{
+			ModelUUID:    s.State.ModelUUID(),
+			User:         user,
+			ConnectionID: uint64(i),
+			Facade:       "Application",
+			Version:      9,
+			Method:       "Deploy",
+			Args:         `{"password":"[redacted]"}`,
+			Time:         s.start.Add(time.Duration(i) * time.Minute),
+			Duration:     time.Second,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	err := s.State.AddAuditRecord(state.AuditRecord{
+		ModelUUID: "other-model",
+		User:      "user-bob",
+		Facade:    "Client",
+		Version:   2,
+		Method:    "FullStatus",
+		ErrorCode: "unauthorized access",
+		Error:     "permission denied",
+		Time:      s.start.Add(3 * time.Minute),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *AuditTrailSuite) TestAuditRecords(c *gc.C) {
+	records, err := s.State.AuditRecords(state.AuditRecordFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(records, gc.HasLen, 4)
+	c.Check(records[0], jc.DeepEquals, state.AuditRecord{
+		ModelUUID: "other-model",
+		User:      "user-bob",
+		Facade:    "Client",
+		Version:   2,
+		Method:    "FullStatus",
+		ErrorCode: "unauthorized access",
+		Error:     "permission denied",
+		Time:      s.start.Add(3 * time.Minute),
+	})
+	c.Check(records[3], jc.DeepEquals, state.AuditRecord{
+		ModelUUID: s.State.ModelUUID(),
+		User:      "user-bob",
+		Facade:    "Application",
+		Version:   9,
+		Method:    "Deploy",
+		Args:      `{"password":"[redacted]"}`,
+		Time:      s.start,
+		Duration:  time.Second,
+	})
+}
+
+func (s *AuditTrailSuite) TestAuditRecordsFilterUser(c *gc.C) {
+	records, err := s.State.AuditRecords(state.AuditRecordFilter{User: "user-mary"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(records, gc.HasLen, 1)
+	c.Check(records[0].ConnectionID, gc.Equals, uint64(1))
+}
+
+func (s *AuditTrailSuite) TestAuditRecordsFilterModel(c *gc.C) {
+	records, err := s.State.AuditRecords(state.AuditRecordFilter{
+		User:      "user-bob",
+		ModelUUID: s.State.ModelUUID(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(records, gc.HasLen, 2)
+	c.Check(records[0].ConnectionID, gc.Equals, uint64(2))
+	c.Check(records[1].ConnectionID, gc.Equals, uint64(0))
+}
+
+func (s *AuditTrailSuite) TestAuditRecordsFilterTime(c *gc.C) {
+	records, err := s.State.AuditRecords(state.AuditRecordFilter{
+		From: s.start.Add(time.Minute),
+		To:   s.start.Add(3 * time.Minute),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(records, gc.HasLen, 2)
+	c.Check(records[0].User, gc.Equals, "user-bob")
+	c.Check(records[1].User, gc.Equals, "user-mary")
+}
+
+func (s *AuditTrailSuite) TestAuditRecordsLimit(c *gc.C) {
+	records, err := s.State.AuditRecords(state.AuditRecordFilter{Limit: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(records, gc.HasLen, 1)
+	c.Check(records[0].ModelUUID, gc.Equals, "other-model")
+}
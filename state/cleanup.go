@@ -45,6 +45,7 @@ const (
 	cleanupAttachmentsForDyingVolume     cleanupKind = "volumeAttachments"
 	cleanupAttachmentsForDyingFilesystem cleanupKind = "filesystemAttachments"
 	cleanupModelsForDyingController      cleanupKind = "models"
+	cleanupDyingSubnet                   cleanupKind = "dyingSubnet"
 
 	// IAAS models require machines to be cleaned up.
 	cleanupMachinesForDyingModel cleanupKind = "modelMachines"
@@ -191,6 +192,8 @@ func (st *State) Cleanup() (err error) {
 			err = st.cleanupResourceBlob(doc.Prefix)
 		case cleanupStorageForDyingModel:
 			err = st.cleanupStorageForDyingModel(args)
+		case cleanupDyingSubnet:
+			err = st.cleanupDyingSubnet(doc.Prefix)
 		default:
 			err = errors.Errorf("unknown cleanup kind %q", doc.Kind)
 		}
@@ -1466,6 +1469,35 @@ func (st *State) cleanupAttachmentsForDyingVolume(volumeId string) (err error) {
 	return nil
 }
 
+// cleanupDyingSubnet sets the Dying subnet with the given ID to Dead and
+// removes it, once no machines, ports documents or endpoint bindings refer
+// to it. While references remain an error is returned, so the cleanup is
+// kept and retried later.
+func (st *State) cleanupDyingSubnet(subnetID string) error {
+	subnet, err := st.SubnetByID(subnetID)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if subnet.Life() == Alive {
+		return nil
+	}
+	if subnet.Life() == Dying {
+		refs, err := subnet.References()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if n := refs.Total(); n > 0 {
+			return errors.Errorf("subnet %q still has %d references", subnet, n)
+		}
+		if err := subnet.EnsureDead(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return errors.Trace(subnet.Remove())
+}
+
 // cleanupAttachmentsForDyingFilesystem sets all filesystem attachments related
 // to the specified filesystem to Dying, if they are not already Dying or
 // Dead. It's expected to be used when a filesystem is destroyed.
@@ -114,3 +114,60 @@ func writeConstraints(mb modelBackend, id string, cons constraints.Value) error
 	}
 	return nil
 }
+
+// spaceConstraintsDoc holds the space constraints of a constraints document.
+type spaceConstraintsDoc struct {
+	DocID  string   `bson:"_id"`
+	Spaces []string `bson:"spaces"`
+}
+
+// constraintsReferencingSpace returns the constraints documents that
+// include or exclude the named space.
+func constraintsReferencingSpace(mb modelBackend, name string) ([]spaceConstraintsDoc, error) {
+	constraintsCollection, closer := mb.db().GetCollection(constraintsC)
+	defer closer()
+
+	var docs []spaceConstraintsDoc
+	query := bson.D{{"spaces", bson.D{{"$in", []string{name, "^" + name}}}}}
+	if err := constraintsCollection.Find(query).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot read constraints")
+	}
+	return docs, nil
+}
+
+// rewriteSpaceConstraintsOps returns the operations required to replace
+// fromName with toName in every constraints document that includes or
+// excludes the space. If toName is empty, the references are dropped.
+func rewriteSpaceConstraintsOps(mb modelBackend, fromName, toName string) ([]txn.Op, error) {
+	docs, err := constraintsReferencingSpace(mb, fromName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	ops := make([]txn.Op, len(docs))
+	for i, doc := range docs {
+		var spaces []string
+		for _, space := range doc.Spaces {
+			switch {
+			case space != fromName && space != "^"+fromName:
+				spaces = append(spaces, space)
+			case toName == "":
+			case space == fromName:
+				spaces = append(spaces, toName)
+			default:
+				spaces = append(spaces, "^"+toName)
+			}
+		}
+		update := bson.D{{"$set", bson.D{{"spaces", spaces}}}}
+		if len(spaces) == 0 {
+			update = bson.D{{"$unset", bson.D{{"spaces", 1}}}}
+		}
+		ops[i] = txn.Op{
+			C:      constraintsC,
+			Id:     doc.DocID,
+			Assert: bson.D{{"spaces", doc.Spaces}},
+			Update: update,
+		}
+	}
+	return ops, nil
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/network"
 )
 
 // AutoConfigureContainerNetworking tries to set up best container networking available
@@ -96,3 +97,73 @@ func (m *Model) discoverFan(environ environs.BootstrapEnviron, modelConfig *conf
 	}
 	return false, nil
 }
+
+// FanConfig returns the FAN overlays configured for the model.
+func (m *Model) FanConfig() (network.FanConfig, error) {
+	modelConfig, err := m.ModelConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return modelConfig.FanConfig()
+}
+
+// SetFanConfig replaces the FAN overlays configured for the model.
+// Overlays must be IPv4 networks that overlap neither each other nor
+// any subnet known to the model, other than FAN segments derived from
+// an earlier configuration.
+func (m *Model) SetFanConfig(fans network.FanConfig) error {
+	subnets, err := m.st.AllSubnets()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := validateFanConfig(fans, subnets); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(m.UpdateModelConfig(map[string]interface{}{
+		config.FanConfig: fans.String(),
+	}, nil))
+}
+
+func validateFanConfig(fans network.FanConfig, subnets []*Subnet) error {
+	overlaps := func(a, b *net.IPNet) bool {
+		return a.Contains(b.IP) || b.Contains(a.IP)
+	}
+	for i, fan := range fans {
+		if fan.Underlay.IP.To4() == nil || fan.Overlay.IP.To4() == nil {
+			return errors.NewNotValid(nil, fmt.Sprintf(
+				"FAN %s=%s: only IPv4 networks are supported", fan.Underlay, fan.Overlay))
+		}
+		underlaySize, _ := fan.Underlay.Mask.Size()
+		overlaySize, _ := fan.Overlay.Mask.Size()
+		if underlaySize <= overlaySize {
+			return errors.NewNotValid(nil, fmt.Sprintf(
+				"FAN %s=%s: underlay mask must be larger than overlay", fan.Underlay, fan.Overlay))
+		}
+		for _, other := range fans[:i] {
+			if overlaps(fan.Overlay, other.Overlay) {
+				return errors.NewNotValid(nil, fmt.Sprintf(
+					"FAN overlay %q overlaps overlay %q", fan.Overlay, other.Overlay))
+			}
+		}
+		for _, other := range fans {
+			if overlaps(fan.Underlay, other.Overlay) {
+				return errors.NewNotValid(nil, fmt.Sprintf(
+					"FAN underlay %q overlaps overlay %q", fan.Underlay, other.Overlay))
+			}
+		}
+		for _, subnet := range subnets {
+			if subnet.FanLocalUnderlay() != "" || subnet.Life() == Dead {
+				continue
+			}
+			_, subnetNet, err := net.ParseCIDR(subnet.CIDR())
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if overlaps(fan.Overlay, subnetNet) {
+				return errors.NewNotValid(nil, fmt.Sprintf(
+					"FAN overlay %q overlaps subnet %q", fan.Overlay, subnet.CIDR()))
+			}
+		}
+	}
+	return nil
+}
@@ -4,12 +4,15 @@
 package state_test
 
 import (
+	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	corenetwork "github.com/juju/juju/core/network"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/network"
 )
 
 type containerTestNetworkLessEnviron struct {
@@ -142,3 +145,67 @@ func (s *ContainerNetworkingSuite) TestAutoConfigureContainerNetworkingIgnoresIP
 	c.Check(attrs["container-networking-method"], gc.Equals, "provider")
 	c.Check(attrs["fan-config"], gc.Equals, "172.31.0.0/16=252.0.0.0/8")
 }
+
+func (s *ContainerNetworkingSuite) setFanConfig(c *gc.C, line string) error {
+	fans, err := network.ParseFanConfig(line)
+	c.Assert(err, jc.ErrorIsNil)
+	return s.Model.SetFanConfig(fans)
+}
+
+func (s *ContainerNetworkingSuite) TestSetFanConfig(c *gc.C) {
+	_, err := s.State.AddSubnet(corenetwork.SubnetInfo{CIDR: "172.31.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.setFanConfig(c, "172.31.0.0/16=252.0.0.0/8 192.168.1.0/24=253.0.0.0/8")
+	c.Assert(err, jc.ErrorIsNil)
+
+	fans, err := s.Model.FanConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fans.String(), gc.Equals, "172.31.0.0/16=252.0.0.0/8 192.168.1.0/24=253.0.0.0/8")
+	config, err := s.Model.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(config.AllAttrs()["fan-config"], gc.Equals, "172.31.0.0/16=252.0.0.0/8 192.168.1.0/24=253.0.0.0/8")
+
+	err = s.Model.SetFanConfig(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	fans, err = s.Model.FanConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fans, gc.HasLen, 0)
+}
+
+func (s *ContainerNetworkingSuite) TestSetFanConfigIgnoresFanSegments(c *gc.C) {
+	_, err := s.State.AddSubnet(corenetwork.SubnetInfo{CIDR: "172.31.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	segment := corenetwork.SubnetInfo{CIDR: "252.0.0.0/16"}
+	segment.SetFan("172.31.0.0/24", "252.0.0.0/8")
+	_, err = s.State.AddSubnet(segment)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.setFanConfig(c, "172.31.0.0/16=252.0.0.0/8")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ContainerNetworkingSuite) TestSetFanConfigOverlayOverlapsSubnet(c *gc.C) {
+	_, err := s.State.AddSubnet(corenetwork.SubnetInfo{CIDR: "252.1.0.0/16"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.setFanConfig(c, "172.31.0.0/16=252.0.0.0/8")
+	c.Assert(err, gc.ErrorMatches, `FAN overlay "252.0.0.0/8" overlaps subnet "252.1.0.0/16"`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+
+	config, err := s.Model.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(config.AllAttrs()["fan-config"], gc.Equals, "")
+}
+
+func (s *ContainerNetworkingSuite) TestSetFanConfigOverlappingOverlays(c *gc.C) {
+	err := s.setFanConfig(c, "172.31.0.0/16=252.0.0.0/8 192.168.1.0/24=252.0.0.0/7")
+	c.Assert(err, gc.ErrorMatches, `FAN overlay "252.0.0.0/7" overlaps overlay "252.0.0.0/8"`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *ContainerNetworkingSuite) TestSetFanConfigUnderlayInOverlay(c *gc.C) {
+	err := s.setFanConfig(c, "172.31.0.0/16=252.0.0.0/8 252.1.0.0/16=253.0.0.0/8")
+	c.Assert(err, gc.ErrorMatches, `FAN underlay "252.1.0.0/16" overlaps overlay "252.0.0.0/8"`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
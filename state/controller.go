@@ -124,6 +124,31 @@ func (st *State) UpdateControllerConfig(updateAttrs map[string]interface{}, remo
 	return errors.Trace(settings.write(ops))
 }
 
+// rewriteSpaceControllerConfigOps returns the operations required to update
+// the controller config space settings that refer to fromName. If toName is
+// empty, the settings are removed.
+func rewriteSpaceControllerConfigOps(st *State, fromName, toName string) ([]txn.Op, error) {
+	settings, err := readSettings(st.db(), controllersC, controllerSettingsGlobalKey)
+	if err != nil {
+		return nil, errors.Annotatef(err, "controller %q", st.ControllerUUID())
+	}
+	for _, key := range []string{jujucontroller.JujuHASpace, jujucontroller.JujuManagementSpace} {
+		if value, ok := settings.Get(key); !ok || value != fromName {
+			continue
+		}
+		if toName == "" {
+			settings.Delete(key)
+		} else {
+			settings.Set(key, toName)
+		}
+	}
+	_, ops := settings.settingsUpdateOps()
+	if len(ops) == 0 {
+		return nil, nil
+	}
+	return append([]txn.Op{settings.assertUnchangedOp()}, ops...), nil
+}
+
 func (st *State) checkValidControllerConfig(updateAttrs map[string]interface{}, removeAttrs []string) error {
 	for k := range updateAttrs {
 		if err := checkUpdateControllerConfig(k); err != nil {
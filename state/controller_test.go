@@ -48,6 +48,8 @@ func (s *ControllerSuite) TestControllerAndModelConfigInitialisation(c *gc.C) {
 		controller.PruneTxnSleepTime,
 		controller.CAASOperatorImagePath,
 		controller.CAASImageRepo,
+		controller.CAASImageRepoUsername,
+		controller.CAASImageRepoPassword,
 		controller.CharmStoreURL,
 		controller.Features,
 		controller.MeteringURL,
@@ -4,15 +4,18 @@
 package state
 
 import (
+	"context"
 	"runtime/debug"
 	"strings"
 
 	"github.com/juju/clock"
+	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	jujutxn "github.com/juju/txn"
 	"github.com/juju/utils/featureflag"
 	"github.com/kr/pretty"
+	"go.opencensus.io/trace"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/txn"
 
@@ -196,6 +199,13 @@ func (schema CollectionSchema) Create(
 					spec.MaxBytes = maxSize * 1024 * 1024
 				}
 			}
+			if name == auditTrailC && settings != nil {
+				if _, ok := (*settings)[controller.MaxAuditTrailSize]; ok {
+					maxSize := settings.MaxAuditTrailSizeMB()
+					logger.Infof("overriding max audit trail collection size: %dM", maxSize)
+					spec.MaxBytes = maxSize * 1024 * 1024
+				}
+			}
 			if err := createCollection(rawCollection, spec); err != nil {
 				return mongo.MaybeUnauthorizedf(err, "cannot create collection %q", name)
 			}
@@ -380,23 +390,29 @@ func (db *database) TransactionRunner() (runner jujutxn.Runner, closer SessionCl
 }
 
 // RunTransaction is part of the Database interface.
-func (db *database) RunTransaction(ops []txn.Op) error {
+func (db *database) RunTransaction(ops []txn.Op) (err error) {
+	span := startTxnSpan("state.RunTransaction", db.modelUUID, ops)
+	defer func() { endTxnSpan(span, err) }()
 	runner, closer := db.TransactionRunner()
 	defer closer()
 	return runner.RunTransaction(&jujutxn.Transaction{Ops: ops})
 }
 
 // RunTransactionFor is part of the Database interface.
-func (db *database) RunTransactionFor(modelUUID string, ops []txn.Op) error {
+func (db *database) RunTransactionFor(modelUUID string, ops []txn.Op) (err error) {
 	newDB, dbcloser := db.CopyForModel(modelUUID)
 	defer dbcloser()
+	span := startTxnSpan("state.RunTransactionFor", modelUUID, ops)
+	defer func() { endTxnSpan(span, err) }()
 	runner, closer := newDB.TransactionRunner()
 	defer closer()
 	return runner.RunTransaction(&jujutxn.Transaction{Ops: ops})
 }
 
 // RunRawTransaction is part of the Database interface.
-func (db *database) RunRawTransaction(ops []txn.Op) error {
+func (db *database) RunRawTransaction(ops []txn.Op) (err error) {
+	span := startTxnSpan("state.RunRawTransaction", db.modelUUID, ops)
+	defer func() { endTxnSpan(span, err) }()
 	runner, closer := db.TransactionRunner()
 	defer closer()
 	if multiRunner, ok := runner.(*multiModelRunner); ok {
@@ -406,10 +422,48 @@ func (db *database) RunRawTransaction(ops []txn.Op) error {
 }
 
 // Run is part of the Database interface.
-func (db *database) Run(transactions jujutxn.TransactionSource) error {
+func (db *database) Run(transactions jujutxn.TransactionSource) (err error) {
+	span := startTxnSpan("state.Run", db.modelUUID, nil)
+	defer func() { endTxnSpan(span, err) }()
 	runner, closer := db.TransactionRunner()
 	defer closer()
-	return runner.Run(transactions)
+	return runner.Run(func(attempt int) ([]txn.Op, error) {
+		span.AddAttributes(trace.Int64Attribute("attempts", int64(attempt+1)))
+		return transactions(attempt)
+	})
+}
+
+// startTxnSpan starts the span that covers the running of a transaction
+// against the database. State methods don't take a context, so the span
+// can't be the child of the API request that caused the transaction; it
+// is recorded as a trace of its own, tagged with the model so that it
+// can be found alongside the request.
+func startTxnSpan(name, modelUUID string, ops []txn.Op) *trace.Span {
+	_, span := trace.StartSpan(context.Background(), name, trace.WithSpanKind(trace.SpanKindClient))
+	span.AddAttributes(trace.StringAttribute("model-uuid", modelUUID))
+	if len(ops) > 0 {
+		colls := set.NewStrings()
+		for _, op := range ops {
+			colls.Add(op.C)
+		}
+		span.AddAttributes(
+			trace.Int64Attribute("ops", int64(len(ops))),
+			trace.StringAttribute("collections", strings.Join(colls.SortedValues(), ",")),
+		)
+	}
+	return span
+}
+
+// endTxnSpan ends the given transaction span, recording the error, if
+// any, that the transaction failed with.
+func endTxnSpan(span *trace.Span, err error) {
+	if err != nil {
+		span.SetStatus(trace.Status{
+			Code:    trace.StatusCodeUnknown,
+			Message: err.Error(),
+		})
+	}
+	span.End()
 }
 
 // Schema is part of the Database interface.
@@ -0,0 +1,105 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/network"
+)
+
+// egressRuleDoc is the persistent form of a network.EgressRule.
+type egressRuleDoc struct {
+	Protocol         string   `bson:"protocol"`
+	FromPort         int      `bson:"from-port"`
+	ToPort           int      `bson:"to-port"`
+	DestinationCIDRs []string `bson:"destination-cidrs,omitempty"`
+}
+
+func newEgressRuleDocs(rules []network.EgressRule) ([]egressRuleDoc, error) {
+	var docs []egressRuleDoc
+	for _, rule := range rules {
+		if err := rule.PortRange.Validate(); err != nil {
+			return nil, errors.NewNotValid(err, "")
+		}
+		// Round-trip through NewEgressRule to validate and normalise
+		// the destination CIDRs.
+		normalised, err := network.NewEgressRule(
+			rule.Protocol, rule.FromPort, rule.ToPort, rule.DestinationCIDRs...)
+		if err != nil {
+			return nil, errors.NewNotValid(err, "")
+		}
+		docs = append(docs, egressRuleDoc{
+			Protocol:         normalised.Protocol,
+			FromPort:         normalised.FromPort,
+			ToPort:           normalised.ToPort,
+			DestinationCIDRs: normalised.DestinationCIDRs,
+		})
+	}
+	return docs, nil
+}
+
+func (doc egressRuleDoc) rule() network.EgressRule {
+	rule := network.EgressRule{DestinationCIDRs: doc.DestinationCIDRs}
+	rule.Protocol = doc.Protocol
+	rule.FromPort = doc.FromPort
+	rule.ToPort = doc.ToPort
+	return rule
+}
+
+// EgressRules returns the rules describing the outgoing traffic allowed
+// from the machines hosting the application's units, sorted by
+// network.SortEgressRules. No rules means egress is not restricted by
+// Juju.
+func (a *Application) EgressRules() []network.EgressRule {
+	if len(a.doc.EgressRules) == 0 {
+		return nil
+	}
+	rules := make([]network.EgressRule, len(a.doc.EgressRules))
+	for i, doc := range a.doc.EgressRules {
+		rules[i] = doc.rule()
+	}
+	network.SortEgressRules(rules)
+	return rules
+}
+
+// SetEgressRules replaces the egress rules of the application. The rules
+// are applied by the firewaller to the instances hosting the
+// application's units, when the provider supports egress rules. Setting
+// no rules removes any restriction.
+func (a *Application) SetEgressRules(rules []network.EgressRule) error {
+	docs, err := newEgressRuleDocs(rules)
+	if err != nil {
+		return errors.Annotate(err, "cannot set egress rules")
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			alive, err := isAlive(a.st, applicationsC, a.doc.DocID)
+			if err != nil {
+				return nil, errors.Trace(err)
+			} else if !alive {
+				return nil, applicationNotAliveErr
+			}
+		}
+		var update bson.D
+		if len(docs) == 0 {
+			update = bson.D{{"$unset", bson.D{{"egress-rules", nil}}}}
+		} else {
+			update = bson.D{{"$set", bson.D{{"egress-rules", docs}}}}
+		}
+		return []txn.Op{{
+			C:      applicationsC,
+			Id:     a.doc.DocID,
+			Assert: isAliveDoc,
+			Update: update,
+		}}, nil
+	}
+	if err := a.st.db().Run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot set egress rules for application %q", a.doc.Name)
+	}
+	a.doc.EgressRules = docs
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+)
+
+type EgressRulesSuite struct {
+	ConnSuite
+	mysql *state.Application
+}
+
+var _ = gc.Suite(&EgressRulesSuite{})
+
+func (s *EgressRulesSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.mysql = s.AddTestingApplication(c, "mysql", s.AddTestingCharm(c, "mysql"))
+}
+
+func (s *EgressRulesSuite) TestSetEgressRules(c *gc.C) {
+	c.Assert(s.mysql.EgressRules(), gc.HasLen, 0)
+
+	rules := []network.EgressRule{
+		network.MustNewEgressRule("udp", 53, 53, "10.0.0.2/32"),
+		network.MustNewEgressRule("tcp", 443, 443, "2001:DB8::/32", "10.1.0.0/16"),
+	}
+	err := s.mysql.SetEgressRules(rules)
+	c.Assert(err, jc.ErrorIsNil)
+
+	expected := []network.EgressRule{
+		network.MustNewEgressRule("tcp", 443, 443, "2001:db8::/32", "10.1.0.0/16"),
+		network.MustNewEgressRule("udp", 53, 53, "10.0.0.2/32"),
+	}
+	c.Assert(s.mysql.EgressRules(), jc.DeepEquals, expected)
+
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.EgressRules(), jc.DeepEquals, expected)
+}
+
+func (s *EgressRulesSuite) TestClearEgressRules(c *gc.C) {
+	err := s.mysql.SetEgressRules([]network.EgressRule{network.MustNewEgressRule("tcp", 443, 443)})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.mysql.SetEgressRules(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.EgressRules(), gc.HasLen, 0)
+}
+
+func (s *EgressRulesSuite) TestSetEgressRulesInvalid(c *gc.C) {
+	err := s.mysql.SetEgressRules([]network.EgressRule{{
+		PortRange:        network.MustNewEgressRule("tcp", 80, 80).PortRange,
+		DestinationCIDRs: []string{"10.0.0/8"},
+	}})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err, gc.ErrorMatches, `cannot set egress rules: invalid CIDR address: 10.0.0/8`)
+
+	err = s.mysql.SetEgressRules([]network.EgressRule{network.MustNewEgressRule("tcp", 90, 80)})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err, gc.ErrorMatches, `cannot set egress rules: invalid port range 90-80/tcp`)
+}
+
+func (s *EgressRulesSuite) TestSetEgressRulesNotAlive(c *gc.C) {
+	err := s.mysql.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.mysql.SetEgressRules([]network.EgressRule{network.MustNewEgressRule("tcp", 443, 443)})
+	c.Assert(err, gc.ErrorMatches, `cannot set egress rules for application "mysql": application is not found or not alive`)
+}
@@ -297,3 +297,56 @@ func DefaultEndpointBindingsForCharm(charmMeta *charm.Meta) map[string]string {
 	}
 	return bindings
 }
+
+// bindingsReferencingSpace returns the endpoint bindings documents
+// with at least one endpoint bound to the named space.
+func bindingsReferencingSpace(st *State, name string) ([]endpointBindingsDoc, error) {
+	endpointBindings, closer := st.db().GetCollection(endpointBindingsC)
+	defer closer()
+
+	var docs []endpointBindingsDoc
+	if err := endpointBindings.Find(nil).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot read endpoint bindings")
+	}
+
+	var result []endpointBindingsDoc
+	for _, doc := range docs {
+		for _, space := range doc.Bindings {
+			if space == name {
+				result = append(result, doc)
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+// rewriteSpaceEndpointBindingsOps returns the operations required to
+// rebind every application endpoint bound to fromName to toName instead.
+// An empty toName binds the endpoints to the default space.
+func rewriteSpaceEndpointBindingsOps(st *State, fromName, toName string) ([]txn.Op, error) {
+	docs, err := bindingsReferencingSpace(st, fromName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var ops []txn.Op
+	for _, doc := range docs {
+		// The default binding is keyed by the empty string, which cannot
+		// be addressed with a dotted path, so the whole map is replaced.
+		escaped := make(bson.M, len(doc.Bindings))
+		for endpoint, space := range doc.Bindings {
+			if space == fromName {
+				space = toName
+			}
+			escaped[utils.EscapeKey(endpoint)] = space
+		}
+		ops = append(ops, txn.Op{
+			C:      endpointBindingsC,
+			Id:     doc.DocID,
+			Assert: bson.D{{"txn-revno", doc.TxnRevno}},
+			Update: bson.M{"$set": bson.M{"bindings": escaped}},
+		})
+	}
+	return ops, nil
+}
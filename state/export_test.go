@@ -51,6 +51,7 @@ const (
 	GUISettingsC      = guisettingsC
 	GlobalSettingsC   = globalSettingsC
 	SettingsC         = settingsC
+	OpenedPortsC      = openedPortsC
 )
 
 var (
@@ -299,6 +300,7 @@ func addCharm(c *gc.C, st *State, series string, ch charm.Charm) *Charm {
 
 func init() {
 	txnLogSize = txnLogSizeTests
+	auditTrailSize = auditTrailSizeTests
 }
 
 // TxnRevno returns the txn-revno field of the document
@@ -958,3 +960,9 @@ func (st *State) SetClockForTesting(clock clock.Clock) error {
 	}
 	return nil
 }
+
+// ReserveModelAlias reserves the given model alias for the model with
+// the given UUID, as if that model existed.
+func ReserveModelAlias(st *State, alias, modelUUID string) error {
+	return st.db().RunTransaction([]txn.Op{createModelAliasOp(alias, modelUUID)})
+}
@@ -25,6 +25,7 @@ import (
 // - ssh
 // - juju-controller
 // - juju-application-offer
+// - juju-exposed-application
 type FirewallRule struct {
 	// WellKnownService is the known service for the firewall rules entity.
 	WellKnownService WellKnownServiceType
@@ -62,6 +63,10 @@ const (
 
 	// JujuApplicationOfferRule is a rule for connections to a Juju offer.
 	JujuApplicationOfferRule = WellKnownServiceType("juju-application-offer")
+
+	// JujuExposedApplicationRule is a rule for connections to the
+	// ports of exposed applications.
+	JujuExposedApplicationRule = WellKnownServiceType("juju-exposed-application")
 )
 
 // WellKnownServiceType defines a service for which firewall rules may be applied.
@@ -69,7 +74,7 @@ type WellKnownServiceType string
 
 func (v WellKnownServiceType) validate() error {
 	switch v {
-	case SSHRule, JujuControllerRule, JujuApplicationOfferRule:
+	case SSHRule, JujuControllerRule, JujuApplicationOfferRule, JujuExposedApplicationRule:
 		return nil
 	}
 	return errors.NotValidf("well known service type %q", v)
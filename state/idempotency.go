@@ -0,0 +1,224 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// IdempotencyKeyExpiry is how long the key a call was made with is
+// remembered for, and so how long a retry of the call is recognised.
+const IdempotencyKeyExpiry = time.Hour
+
+// IdempotentCallStaleAfter is how long a call made under an idempotency
+// key may stay in progress. A call that has not completed or failed by
+// then is taken to have been abandoned, for instance because the
+// controller handling it went down, and a retry with the same key is
+// allowed to make the call again.
+const IdempotentCallStaleAfter = 10 * time.Minute
+
+// IdempotentCall records a mutating API call made under a
+// client-supplied idempotency key.
+type IdempotentCall struct {
+	// User is the tag of the user that made the call. Keys are
+	// scoped to the user that chose them.
+	User string
+
+	// Key is the idempotency key the call was made with.
+	Key string
+
+	// Operation identifies the kind of call made, such as "deploy".
+	Operation string
+
+	// Completed reports whether the call succeeded.
+	Completed bool
+
+	// Results holds the identifiers of the entities created by the
+	// call, so that they can be returned to a retry.
+	Results []string
+
+	// Started is when the call was started. It identifies the call
+	// made under the key, since the key may be reused once the call
+	// goes stale.
+	Started time.Time
+}
+
+// idempotentCallDoc records a call made to a model under an idempotency
+// key. It is keyed by the user that made the call and the idempotency
+// key.
+type idempotentCallDoc struct {
+	DocID     string    `bson:"_id"`
+	ModelUUID string    `bson:"model-uuid"`
+	User      string    `bson:"user"`
+	Key       string    `bson:"key"`
+	Operation string    `bson:"operation"`
+	Completed bool      `bson:"completed"`
+	Results   []string  `bson:"results,omitempty"`
+	Started   time.Time `bson:"started"`
+	Expires   time.Time `bson:"expires"`
+}
+
+func (doc *idempotentCallDoc) call() IdempotentCall {
+	return IdempotentCall{
+		User:      doc.User,
+		Key:       doc.Key,
+		Operation: doc.Operation,
+		Completed: doc.Completed,
+		Results:   doc.Results,
+		Started:   doc.Started.UTC(),
+	}
+}
+
+// idempotencyKeyDocID returns the local id of the document recording
+// the call made by the given user under the given key.
+func idempotencyKeyDocID(user, key string) string {
+	return user + "#" + key
+}
+
+// StartIdempotentCall records that a call of the given operation is
+// being made by the given user under the given key. If the user has
+// used the key already, the key has not expired, and the call made with
+// it has not gone stale, an AlreadyExists error is returned along with
+// the call recorded for it. Expired keys are removed by
+// PruneIdempotencyKeys.
+func (st *State) StartIdempotentCall(user, operation, key string) (IdempotentCall, error) {
+	if key == "" {
+		return IdempotentCall{}, errors.NotValidf("empty idempotency key")
+	}
+	coll, closer := st.db().GetCollection(idempotencyKeysC)
+	defer closer()
+
+	docID := idempotencyKeyDocID(user, key)
+	var (
+		existing IdempotentCall
+		started  time.Time
+	)
+	buildTxn := func(int) ([]txn.Op, error) {
+		now := st.clock().Now()
+		started = now.UTC().Round(time.Second)
+		expires := now.Add(IdempotencyKeyExpiry).UTC().Round(time.Second)
+		var doc idempotentCallDoc
+		err := coll.FindId(docID).One(&doc)
+		switch {
+		case err == mgo.ErrNotFound:
+			return []txn.Op{{
+				C:      idempotencyKeysC,
+				Id:     docID,
+				Assert: txn.DocMissing,
+				Insert: &idempotentCallDoc{
+					User:      user,
+					Key:       key,
+					Operation: operation,
+					Started:   started,
+					Expires:   expires,
+				},
+			}}, nil
+		case err != nil:
+			return nil, errors.Trace(err)
+		case now.Before(doc.Expires) && (doc.Completed || now.Before(doc.Started.Add(IdempotentCallStaleAfter))):
+			existing = doc.call()
+			return nil, errors.AlreadyExistsf("idempotency key %q", key)
+		}
+		// The key has expired, or the call made with it was
+		// abandoned, so it can be used again.
+		return []txn.Op{{
+			C:  idempotencyKeysC,
+			Id: docID,
+			Assert: bson.D{
+				{"completed", doc.Completed},
+				{"started", doc.Started},
+				{"expires", doc.Expires},
+			},
+			Update: bson.D{
+				{"$set", bson.D{
+					{"operation", operation},
+					{"completed", false},
+					{"started", started},
+					{"expires", expires},
+				}},
+				{"$unset", bson.D{{"results", nil}}},
+			},
+		}}, nil
+	}
+	if err := st.db().Run(buildTxn); err != nil {
+		if errors.IsAlreadyExists(err) {
+			return existing, errors.Trace(err)
+		}
+		return IdempotentCall{}, errors.Annotatef(err, "cannot start call with idempotency key %q", key)
+	}
+	return IdempotentCall{User: user, Key: key, Operation: operation, Started: started}, nil
+}
+
+// CompleteIdempotentCall records the results of the call made by the
+// given user under the given key. The call is identified by when it was
+// started, as in AbortIdempotentCall, so that a call that went stale
+// cannot record its results over those of the retry that reused its
+// key; in that case, as when the key is unknown, a NotFound error is
+// returned.
+func (st *State) CompleteIdempotentCall(user, key string, started time.Time, results []string) error {
+	ops := []txn.Op{{
+		C:      idempotencyKeysC,
+		Id:     idempotencyKeyDocID(user, key),
+		Assert: bson.D{{"started", started}},
+		Update: bson.D{{"$set", bson.D{
+			{"completed", true},
+			{"results", results},
+		}}},
+	}}
+	if err := st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("idempotency key %q", key)
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot complete call with idempotency key %q", key)
+	}
+	return nil
+}
+
+// AbortIdempotentCall forgets the call made by the given user under the
+// given key, so that the key can be used again. It is used when the
+// call failed. The call is identified by when it was started, so that a
+// call that went stale, and whose key was then reused, does not forget
+// the call made by the retry; in that case nothing is done.
+func (st *State) AbortIdempotentCall(user, key string, started time.Time) error {
+	ops := []txn.Op{{
+		C:      idempotencyKeysC,
+		Id:     idempotencyKeyDocID(user, key),
+		Assert: bson.D{{"started", started}},
+		Remove: true,
+	}}
+	if err := st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return nil
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot abort call with idempotency key %q", key)
+	}
+	return nil
+}
+
+// PruneIdempotencyKeys removes the calls recorded under idempotency
+// keys that have expired.
+func PruneIdempotencyKeys(st *State) error {
+	coll, closer := st.db().GetRawCollection(idempotencyKeysC)
+	defer closer()
+
+	iter := coll.Find(bson.D{
+		{"model-uuid", st.ModelUUID()},
+		{"expires", bson.D{{"$lte", st.clock().Now()}}},
+	}).Select(bson.M{"_id": 1}).Iter()
+	defer iter.Close()
+
+	logTemplate := "idempotency key pruning: %d rows deleted"
+	deleted, err := deleteInBatches(coll, iter, logTemplate, loggo.DEBUG, noEarlyFinish)
+	if err != nil {
+		return errors.Annotate(err, "cannot prune idempotency keys")
+	}
+	if deleted > 0 {
+		logger.Debugf(logTemplate, deleted)
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type IdempotencySuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&IdempotencySuite{})
+
+func (s *IdempotencySuite) TestStartAndComplete(c *gc.C) {
+	started := s.Clock.Now().UTC().Round(time.Second)
+	call, err := s.State.StartIdempotentCall("user-bob", "deploy", "key-1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(call, jc.DeepEquals, state.IdempotentCall{User: "user-bob", Key: "key-1", Operation: "deploy", Started: started})
+
+	err = s.State.CompleteIdempotentCall("user-bob", "key-1", call.Started, []string{"mysql"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	call, err = s.State.StartIdempotentCall("user-bob", "deploy", "key-1")
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+	c.Assert(err, gc.ErrorMatches, `idempotency key "key-1" already exists`)
+	c.Assert(call, jc.DeepEquals, state.IdempotentCall{
+		User:      "user-bob",
+		Key:       "key-1",
+		Operation: "deploy",
+		Completed: true,
+		Results:   []string{"mysql"},
+		Started:   started,
+	})
+}
+
+func (s *IdempotencySuite) TestStartInProgress(c *gc.C) {
+	started := s.Clock.Now().UTC().Round(time.Second)
+	_, err := s.State.StartIdempotentCall("user-bob", "add-unit", "key-1")
+	c.Assert(err, jc.ErrorIsNil)
+
+	call, err := s.State.StartIdempotentCall("user-bob", "add-machine", "key-1")
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+	c.Assert(call, jc.DeepEquals, state.IdempotentCall{User: "user-bob", Key: "key-1", Operation: "add-unit", Started: started})
+}
+
+func (s *IdempotencySuite) TestStartEmptyKey(c *gc.C) {
+	_, err := s.State.StartIdempotentCall("user-bob", "deploy", "")
+	c.Assert(err, gc.ErrorMatches, "empty idempotency key not valid")
+}
+
+func (s *IdempotencySuite) TestAbort(c *gc.C) {
+	call, err := s.State.StartIdempotentCall("user-bob", "deploy", "key-1")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AbortIdempotentCall("user-bob", "key-1", call.Started)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.StartIdempotentCall("user-bob", "deploy", "key-1")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *IdempotencySuite) TestAbortStaleCall(c *gc.C) {
+	stale, err := s.State.StartIdempotentCall("user-bob", "deploy", "key-1")
+	c.Assert(err, jc.ErrorIsNil)
+	s.Clock.Advance(state.IdempotentCallStaleAfter + time.Second)
+	_, err = s.State.StartIdempotentCall("user-bob", "deploy", "key-1")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The stale call fails after the retry has reused the key; the
+	// retry's call must not be forgotten.
+	err = s.State.AbortIdempotentCall("user-bob", "key-1", stale.Started)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.StartIdempotentCall("user-bob", "deploy", "key-1")
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *IdempotencySuite) TestCompleteNotFound(c *gc.C) {
+	err := s.State.CompleteIdempotentCall("user-bob", "key-1", s.Clock.Now(), nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *IdempotencySuite) TestCompleteStaleCall(c *gc.C) {
+	stale, err := s.State.StartIdempotentCall("user-bob", "deploy", "key-1")
+	c.Assert(err, jc.ErrorIsNil)
+	s.Clock.Advance(state.IdempotentCallStaleAfter + time.Second)
+	retry, err := s.State.StartIdempotentCall("user-bob", "deploy", "key-1")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The stale call succeeds after the retry has reused the key; its
+	// results must not be recorded as the retry's.
+	err = s.State.CompleteIdempotentCall("user-bob", "key-1", stale.Started, []string{"mysql"})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	call, err := s.State.StartIdempotentCall("user-bob", "deploy", "key-1")
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+	c.Assert(call.Completed, jc.IsFalse)
+
+	err = s.State.CompleteIdempotentCall("user-bob", "key-1", retry.Started, []string{"mysql-1"})
+	c.Assert(err, jc.ErrorIsNil)
+	call, err = s.State.StartIdempotentCall("user-bob", "deploy", "key-1")
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+	c.Assert(call.Results, jc.DeepEquals, []string{"mysql-1"})
+}
+
+func (s *IdempotencySuite) TestExpired(c *gc.C) {
+	call, err := s.State.StartIdempotentCall("user-bob", "deploy", "key-1")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.CompleteIdempotentCall("user-bob", "key-1", call.Started, []string{"mysql"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.Clock.Advance(state.IdempotencyKeyExpiry + time.Second)
+
+	started := s.Clock.Now().UTC().Round(time.Second)
+	call, err := s.State.StartIdempotentCall("user-bob", "add-unit", "key-1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(call, jc.DeepEquals, state.IdempotentCall{User: "user-bob", Key: "key-1", Operation: "add-unit", Started: started})
+}
+
+func (s *IdempotencySuite) TestStaleInProgress(c *gc.C) {
+	_, err := s.State.StartIdempotentCall("user-bob", "deploy", "key-1")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The call is abandoned before completing; once it goes stale, a
+	// retry may make it again.
+	s.Clock.Advance(state.IdempotentCallStaleAfter + time.Second)
+	started := s.Clock.Now().UTC().Round(time.Second)
+	call, err := s.State.StartIdempotentCall("user-bob", "deploy", "key-1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(call, jc.DeepEquals, state.IdempotentCall{User: "user-bob", Key: "key-1", Operation: "deploy", Started: started})
+
+	// A completed call never goes stale.
+	err = s.State.CompleteIdempotentCall("user-bob", "key-1", call.Started, []string{"mysql"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.Clock.Advance(state.IdempotentCallStaleAfter + time.Second)
+	_, err = s.State.StartIdempotentCall("user-bob", "deploy", "key-1")
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *IdempotencySuite) TestKeysScopedToUser(c *gc.C) {
+	bobCall, err := s.State.StartIdempotentCall("user-bob", "deploy", "key-1")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.CompleteIdempotentCall("user-bob", "key-1", bobCall.Started, []string{"mysql"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	call, err := s.State.StartIdempotentCall("user-alice", "deploy", "key-1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(call, jc.DeepEquals, state.IdempotentCall{
+		User:      "user-alice",
+		Key:       "key-1",
+		Operation: "deploy",
+		Started:   s.Clock.Now().UTC().Round(time.Second),
+	})
+}
+
+func (s *IdempotencySuite) TestPrune(c *gc.C) {
+	call1, err := s.State.StartIdempotentCall("user-bob", "deploy", "key-1")
+	c.Assert(err, jc.ErrorIsNil)
+	s.Clock.Advance(state.IdempotencyKeyExpiry / 2)
+	call2, err := s.State.StartIdempotentCall("user-bob", "deploy", "key-2")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.Clock.Advance(state.IdempotencyKeyExpiry/2 + time.Second)
+	err = state.PruneIdempotencyKeys(s.State)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.CompleteIdempotentCall("user-bob", "key-1", call1.Started, nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = s.State.CompleteIdempotentCall("user-bob", "key-2", call2.Started, nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
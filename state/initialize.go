@@ -375,6 +375,10 @@ func (st *State) modelSetupOps(controllerUUID string, args ModelArgs, inherited
 	}
 	// Some values require marshalling before storage.
 	modelCfg = config.CoerceForStorage(modelCfg)
+	alias, aliasOp, err := st.newModelAlias(modelUUID)
+	if err != nil {
+		return nil, modelStatusDoc, errors.Trace(err)
+	}
 	ops = append(ops,
 		createSettingsOp(settingsC, modelGlobalKey, modelCfg),
 		createModelEntityRefsOp(modelUUID),
@@ -382,12 +386,13 @@ func (st *State) modelSetupOps(controllerUUID string, args ModelArgs, inherited
 			args.Type,
 			args.Owner,
 			args.Config.Name(),
-			modelUUID, controllerUUID,
+			modelUUID, alias, controllerUUID,
 			args.CloudName, args.CloudRegion, args.CloudCredential,
 			args.MigrationMode,
 			args.EnvironVersion,
 		),
 		createUniqueOwnerModelNameOp(args.Owner, args.Config.Name()),
+		aliasOp,
 		st.createDefaultSpaceOp(),
 	)
 	ops = append(ops, modelUserOps...)
@@ -244,6 +244,14 @@ type lastSentDoc struct {
 	// We record it but currently just use the timestamp when querying
 	// the log collection.
 	RecordID int64 `bson:"record-id"`
+
+	// Sent is the number of records forwarded to the log sink for the
+	// model.
+	Sent int64 `bson:"sent,omitempty"`
+
+	// Dropped is the number of records that were not forwarded to the
+	// log sink for the model, because the sink could not keep up.
+	Dropped int64 `bson:"dropped,omitempty"`
 }
 
 // LastSentLogTracker records and retrieves timestamps of the most recent
@@ -279,29 +287,70 @@ func (logger *LastSentLogTracker) Set(recID, recTimestamp int64) error {
 	collection := logger.session.DB(logsDB).C(forwardedC)
 	_, err := collection.UpsertId(
 		logger.id,
-		lastSentDoc{
-			ID:              logger.id,
-			ModelUUID:       logger.model,
-			Sink:            logger.sink,
-			RecordID:        recID,
-			RecordTimestamp: recTimestamp,
-		},
+		bson.D{{"$set", bson.D{
+			{"model-uuid", logger.model},
+			{"sink", logger.sink},
+			{"record-id", recID},
+			{"record-timestamp", recTimestamp},
+		}}},
 	)
 	return errors.Trace(err)
 }
 
 // Get retrieves the id and timestamp.
 func (logger *LastSentLogTracker) Get() (int64, int64, error) {
+	doc, err := logger.get()
+	if err != nil {
+		return 0, 0, errors.Trace(err)
+	}
+	if doc.RecordTimestamp == 0 {
+		// Only counts have been recorded so far.
+		return 0, 0, errors.Trace(ErrNeverForwarded)
+	}
+	return doc.RecordID, doc.RecordTimestamp, nil
+}
+
+// AddCounts adds to the numbers of records sent to, and dropped
+// instead of being sent to, the log sink.
+func (logger *LastSentLogTracker) AddCounts(sent, dropped int64) error {
+	collection := logger.session.DB(logsDB).C(forwardedC)
+	_, err := collection.UpsertId(
+		logger.id,
+		bson.D{
+			{"$set", bson.D{
+				{"model-uuid", logger.model},
+				{"sink", logger.sink},
+			}},
+			{"$inc", bson.D{
+				{"sent", sent},
+				{"dropped", dropped},
+			}},
+		},
+	)
+	return errors.Trace(err)
+}
+
+// Counts retrieves the numbers of records sent to, and dropped instead
+// of being sent to, the log sink.
+func (logger *LastSentLogTracker) Counts() (int64, int64, error) {
+	doc, err := logger.get()
+	if err != nil {
+		return 0, 0, errors.Trace(err)
+	}
+	return doc.Sent, doc.Dropped, nil
+}
+
+func (logger *LastSentLogTracker) get() (*lastSentDoc, error) {
 	collection := logger.session.DB(logsDB).C(forwardedC)
 	var doc lastSentDoc
 	err := collection.FindId(logger.id).One(&doc)
 	if err != nil {
 		if err == mgo.ErrNotFound {
-			return 0, 0, errors.Trace(ErrNeverForwarded)
+			return nil, errors.Trace(ErrNeverForwarded)
 		}
-		return 0, 0, errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
-	return doc.RecordID, doc.RecordTimestamp, nil
+	return &doc, nil
 }
 
 // logDoc describes log messages stored in MongoDB.
@@ -70,6 +70,41 @@ func (s *LogsSuite) TestLastSentLogTrackerGetNeverSet(c *gc.C) {
 	c.Check(err, gc.ErrorMatches, state.ErrNeverForwarded.Error())
 }
 
+func (s *LogsSuite) TestLastSentLogTrackerCounts(c *gc.C) {
+	tracker := state.NewLastSentLogTracker(s.State, s.State.ModelUUID(), "test-sink")
+	defer tracker.Close()
+
+	_, _, err := tracker.Counts()
+	c.Check(err, gc.ErrorMatches, state.ErrNeverForwarded.Error())
+
+	err = tracker.AddCounts(5, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	_, _, err = tracker.Get()
+	c.Check(err, gc.ErrorMatches, state.ErrNeverForwarded.Error())
+
+	err = tracker.Set(10, 100)
+	c.Assert(err, jc.ErrorIsNil)
+	err = tracker.AddCounts(3, 2)
+	c.Assert(err, jc.ErrorIsNil)
+
+	sent, dropped, err := tracker.Counts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(sent, gc.Equals, int64(8))
+	c.Check(dropped, gc.Equals, int64(3))
+
+	// Setting the last sent record keeps the counts.
+	err = tracker.Set(20, 200)
+	c.Assert(err, jc.ErrorIsNil)
+	sent, dropped, err = tracker.Counts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(sent, gc.Equals, int64(8))
+	c.Check(dropped, gc.Equals, int64(3))
+	id, ts, err := tracker.Get()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(id, gc.Equals, int64(20))
+	c.Check(ts, gc.Equals, int64(200))
+}
+
 func (s *LogsSuite) TestLastSentLogTrackerIndependentModels(c *gc.C) {
 	tracker0 := state.NewLastSentLogTracker(s.State, s.State.ModelUUID(), "test-sink")
 	defer tracker0.Close()
@@ -140,6 +140,38 @@ type machineDoc struct {
 	// StopMongoUntilVersion holds the version that must be checked to
 	// know if mongo must be stopped.
 	StopMongoUntilVersion string `bson:",omitempty"`
+
+	// BootID holds the boot id of the machine's kernel, as last
+	// reported by the machine agent.
+	BootID string `bson:"bootid,omitempty"`
+
+	// RebootExpected is true if the machine has been told to reboot,
+	// or shut down, by Juju since its boot id was last reported.
+	RebootExpected bool `bson:"rebootexpected,omitempty"`
+
+	// RebootRequested records when a reboot of the machine was last
+	// requested through the API, and RebootCompleted when the machine
+	// agent next started after that reboot. Both are in nanoseconds
+	// since the epoch.
+	RebootRequested int64 `bson:"rebootrequested,omitempty"`
+	RebootCompleted int64 `bson:"rebootcompleted,omitempty"`
+
+	// InstanceIdentityDigest holds the digest of the instance identity
+	// document, and the nonce it was presented with, with which the
+	// machine's agent enrolled.
+	InstanceIdentityDigest string `bson:"instanceidentitydigest,omitempty"`
+
+	// Hostname holds the hostname of the machine, as last reported by
+	// the machine agent.
+	Hostname string `bson:"hostname,omitempty"`
+
+	// KernelVersion holds the version of the machine's kernel, as last
+	// reported by the machine agent.
+	KernelVersion string `bson:"kernel-version,omitempty"`
+
+	// Cordoned is true if an operator has cordoned the machine, so that
+	// no new units are assigned to it.
+	Cordoned bool `bson:"cordoned,omitempty"`
 }
 
 func newMachine(st *State, doc *machineDoc) *Machine {
@@ -155,6 +187,11 @@ func (m *Machine) Id() string {
 	return m.doc.Id
 }
 
+// ModelUUID returns the UUID of the model the machine belongs to.
+func (m *Machine) ModelUUID() string {
+	return m.doc.ModelUUID
+}
+
 // Principals returns the principals for the machine.
 func (m *Machine) Principals() []string {
 	return m.doc.Principals
@@ -170,6 +207,114 @@ func (m *Machine) ContainerType() instance.ContainerType {
 	return instance.ContainerType(m.doc.ContainerType)
 }
 
+// Hostname returns the hostname of the machine, as last reported by the
+// machine agent. It is empty if none has been reported.
+func (m *Machine) Hostname() string {
+	return m.doc.Hostname
+}
+
+// SetHostname records the hostname of the machine, as observed by the
+// machine agent.
+func (m *Machine) SetHostname(hostname string) error {
+	if hostname == "" {
+		return errors.NotValidf("empty hostname")
+	}
+	if hostname == m.doc.Hostname {
+		return nil
+	}
+	ops := []txn.Op{{
+		C:      machinesC,
+		Id:     m.doc.DocID,
+		Assert: notDeadDoc,
+		Update: bson.D{{"$set", bson.D{{"hostname", hostname}}}},
+	}}
+	if err := m.st.db().RunTransaction(ops); err != nil {
+		return errors.Annotatef(onAbort(err, ErrDead), "cannot set hostname of machine %v", m)
+	}
+	m.doc.Hostname = hostname
+	return nil
+}
+
+// HostInfo describes the operating system and hardware of a machine, as
+// observed by its machine agent.
+type HostInfo struct {
+	// KernelVersion is the version of the machine's kernel.
+	KernelVersion string
+
+	// Mem, CpuCores and RootDisk, if not nil, hold the memory in MiB,
+	// the number of CPU cores and the root disk size in MiB of the
+	// machine.
+	Mem      *uint64
+	CpuCores *uint64
+	RootDisk *uint64
+}
+
+// KernelVersion returns the version of the machine's kernel, as last
+// reported by the machine agent. It is empty if none has been reported.
+func (m *Machine) KernelVersion() string {
+	return m.doc.KernelVersion
+}
+
+// SetHostInfo records the operating system and hardware of the machine,
+// as observed by the machine agent. Only the details that are set are
+// recorded; the hardware details replace those in the machine's
+// hardware characteristics.
+func (m *Machine) SetHostInfo(info HostInfo) error {
+	var hardware bson.D
+	if info.Mem != nil {
+		hardware = append(hardware, bson.DocElem{Name: "mem", Value: *info.Mem})
+	}
+	if info.CpuCores != nil {
+		hardware = append(hardware, bson.DocElem{Name: "cpucores", Value: *info.CpuCores})
+	}
+	if info.RootDisk != nil {
+		hardware = append(hardware, bson.DocElem{Name: "rootdisk", Value: *info.RootDisk})
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := m.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if m.doc.Life == Dead {
+			return nil, ErrDead
+		}
+		var ops []txn.Op
+		if info.KernelVersion != "" && info.KernelVersion != m.doc.KernelVersion {
+			ops = append(ops, txn.Op{
+				C:      machinesC,
+				Id:     m.doc.DocID,
+				Assert: notDeadDoc,
+				Update: bson.D{{"$set", bson.D{{"kernel-version", info.KernelVersion}}}},
+			})
+		}
+		if len(hardware) > 0 {
+			if _, err := getInstanceData(m.st, m.Id()); errors.IsNotFound(err) {
+				return nil, errors.NotProvisionedf("machine %v", m.Id())
+			} else if err != nil {
+				return nil, errors.Trace(err)
+			}
+			ops = append(ops, txn.Op{
+				C:      instanceDataC,
+				Id:     m.doc.DocID,
+				Assert: txn.DocExists,
+				Update: bson.D{{"$set", hardware}},
+			})
+		}
+		if len(ops) == 0 {
+			return nil, jujutxn.ErrNoOperations
+		}
+		return ops, nil
+	}
+	if err := m.st.db().Run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot set host info of machine %v", m)
+	}
+	if info.KernelVersion != "" {
+		m.doc.KernelVersion = info.KernelVersion
+	}
+	return nil
+}
+
 func (m *Machine) ModelName() string {
 	name, err := m.st.modelName()
 	if err != nil {
@@ -234,6 +379,14 @@ type instanceData struct {
 	// CharmProfiles contains the names of LXD profiles used by this machine.
 	// Profiles would have been defined in the charm deployed to this machine.
 	CharmProfiles []string `bson:"charm-profiles,omitempty"`
+
+	// PreviousCharmProfiles contains the names of the LXD profiles used
+	// by this machine before CharmProfiles were last changed.
+	PreviousCharmProfiles []string `bson:"previous-charm-profiles,omitempty"`
+
+	// CharmProfilesRollback is set to true when the machine's LXD
+	// profiles are to be rolled back to PreviousCharmProfiles.
+	CharmProfilesRollback bool `bson:"charm-profiles-rollback,omitempty"`
 }
 
 func hardwareCharacteristics(instData instanceData) *instance.HardwareCharacteristics {
@@ -325,6 +478,57 @@ func (m *Machine) KeepInstance() (bool, error) {
 	return instData.KeepInstance, nil
 }
 
+// IsCordoned reports whether the machine is cordoned, in which case no
+// new units are assigned to it or to new containers on it.
+func (m *Machine) IsCordoned() bool {
+	return m.doc.Cordoned
+}
+
+// SetCordoned cordons or uncordons the machine. Units already assigned
+// to a cordoned machine are left where they are; only the assignment of
+// new units is prevented.
+func (m *Machine) SetCordoned(cordoned bool) error {
+	if cordoned == m.doc.Cordoned {
+		return nil
+	}
+	var update bson.D
+	if cordoned {
+		update = bson.D{{"$set", bson.D{{"cordoned", true}}}}
+	} else {
+		update = bson.D{{"$unset", bson.D{{"cordoned", nil}}}}
+	}
+	ops := []txn.Op{{
+		C:      machinesC,
+		Id:     m.doc.DocID,
+		Assert: notDeadDoc,
+		Update: update,
+	}}
+	if err := m.st.db().RunTransaction(ops); err != nil {
+		return errors.Annotatef(onAbort(err, ErrDead), "cannot set cordoned of machine %v", m)
+	}
+	m.doc.Cordoned = cordoned
+	return nil
+}
+
+// cordonedMachineIds returns the ids of the model's cordoned machines.
+func (st *State) cordonedMachineIds() ([]string, error) {
+	machines, closer := st.db().GetCollection(machinesC)
+	defer closer()
+
+	var docs []struct {
+		Id string `bson:"machineid"`
+	}
+	query := bson.D{{"cordoned", true}}
+	if err := machines.Find(query).Select(bson.D{{"machineid", 1}}).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get cordoned machines")
+	}
+	ids := make([]string, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.Id
+	}
+	return ids, nil
+}
+
 // CharmProfiles returns the names of any LXD profiles used by the machine,
 // which were defined in the charm deployed to that machine.
 func (m *Machine) CharmProfiles() ([]string, error) {
@@ -338,8 +542,76 @@ func (m *Machine) CharmProfiles() ([]string, error) {
 	return instData.CharmProfiles, nil
 }
 
+// PreviousCharmProfiles returns the names of the LXD profiles used by the
+// machine before its charm profiles were last changed.
+func (m *Machine) PreviousCharmProfiles() ([]string, error) {
+	instData, err := getInstanceData(m.st, m.Id())
+	if errors.IsNotFound(err) {
+		err = errors.NotProvisionedf("machine %v", m.Id())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return instData.PreviousCharmProfiles, nil
+}
+
+// CharmProfilesRollback returns the names of the LXD profiles to which
+// the machine's profiles are to be rolled back, or nil if no rollback
+// has been requested.
+func (m *Machine) CharmProfilesRollback() ([]string, error) {
+	instData, err := getInstanceData(m.st, m.Id())
+	if errors.IsNotFound(err) {
+		err = errors.NotProvisionedf("machine %v", m.Id())
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !instData.CharmProfilesRollback {
+		return nil, nil
+	}
+	return instData.PreviousCharmProfiles, nil
+}
+
+// RequestCharmProfilesRollback requests that the machine's LXD profiles
+// be rolled back to those used before its charm profiles were last
+// changed. The request stands until the charm profiles are next changed.
+func (m *Machine) RequestCharmProfilesRollback() error {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := m.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		instData, err := getInstanceData(m.st, m.Id())
+		if errors.IsNotFound(err) {
+			return nil, errors.NotProvisionedf("machine %v", m.Id())
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(instData.PreviousCharmProfiles) == 0 {
+			return nil, errors.NotFoundf("previous charm profiles for machine %v", m.Id())
+		}
+		if instData.CharmProfilesRollback {
+			return nil, jujutxn.ErrNoOperations
+		}
+		return []txn.Op{{
+			C:  instanceDataC,
+			Id: m.doc.DocID,
+			Assert: bson.D{
+				{"charm-profiles", instData.CharmProfiles},
+				{"previous-charm-profiles", instData.PreviousCharmProfiles},
+			},
+			Update: bson.D{{"$set", bson.D{{"charm-profiles-rollback", true}}}},
+		}}, nil
+	}
+	err := m.st.db().Run(buildTxn)
+	return errors.Annotatef(err, "cannot roll back profiles for %q", m)
+}
+
 // SetCharmProfiles sets the names of the charm profiles used on a machine
-// in its instanceData.
+// in its instanceData. The profiles replaced are recorded as the
+// machine's previous charm profiles, and any requested rollback is
+// cancelled.
 func (m *Machine) SetCharmProfiles(profiles []string) error {
 	if len(profiles) == 0 {
 		return nil
@@ -363,8 +635,14 @@ func (m *Machine) SetCharmProfiles(profiles []string) error {
 		ops := []txn.Op{{
 			C:      instanceDataC,
 			Id:     m.doc.DocID,
-			Assert: txn.DocExists,
-			Update: bson.D{{"$set", bson.D{{"charm-profiles", profiles}}}},
+			Assert: bson.D{{"charm-profiles", mProfiles}},
+			Update: bson.D{
+				{"$set", bson.D{
+					{"charm-profiles", profiles},
+					{"previous-charm-profiles", mProfiles},
+				}},
+				{"$unset", bson.D{{"charm-profiles-rollback", nil}}},
+			},
 		}}
 
 		return ops, nil
@@ -524,6 +802,48 @@ func (m *Machine) PasswordValid(password string) bool {
 	return agentHash == m.doc.PasswordHash
 }
 
+// HasPassword returns whether an agent password has been set for the
+// machine. A machine enrolled by instance identity has none until its
+// agent first connects and chooses one.
+func (m *Machine) HasPassword() bool {
+	return m.doc.PasswordHash != ""
+}
+
+// ConsumeInstanceIdentity records that the machine's agent has enrolled
+// with an instance identity document, presented with the given nonce,
+// whose digest with the nonce is given. It fails if the nonce is not the
+// machine's, if the agent has already enrolled with another document,
+// or if the agent has set its password, so that a document, which some
+// clouds cannot issue for the nonce, is not accepted once the agent no
+// longer needs it. Until then the same document may be presented again,
+// should the agent fail to set its password.
+func (m *Machine) ConsumeInstanceIdentity(nonce, digest string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot consume instance identity for machine %v", m)
+	if nonce == "" || digest == "" {
+		return errors.New("nonce and digest cannot be empty")
+	}
+	ops := []txn.Op{{
+		C:  machinesC,
+		Id: m.doc.DocID,
+		Assert: append(bson.D{
+			{"nonce", nonce},
+			{"passwordhash", ""},
+			{"$or", []bson.D{
+				{{"instanceidentitydigest", bson.D{{"$exists", false}}}},
+				{{"instanceidentitydigest", digest}},
+			}},
+		}, notDeadDoc...),
+		Update: bson.D{{"$set", bson.D{{"instanceidentitydigest", digest}}}},
+	}}
+	if err := m.st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return errors.New("already enrolled, not provisioned with the nonce, or dead")
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	m.doc.InstanceIdentityDigest = digest
+	return nil
+}
+
 // Destroy sets the machine lifecycle to Dying if it is Alive. It does
 // nothing otherwise. Destroy will fail if the machine has principal
 // units assigned, or if the machine has JobManageModel.
@@ -993,6 +1313,8 @@ func (m *Machine) removeOps() ([]txn.Op, error) {
 		removeMachineBlockDevicesOp(m.Id()),
 		removeModelMachineRefOp(m.st, m.Id()),
 		removeSSHHostKeyOp(m.globalKey()),
+		removeNetworkBootOp(m.globalKey()),
+		removeUpgradeStepsCompletedOp(m.st, m.Tag()),
 	}
 	linkLayerDevicesOps, err := m.removeAllLinkLayerDevicesOps()
 	if err != nil {
@@ -1006,6 +1328,10 @@ func (m *Machine) removeOps() ([]txn.Op, error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	portsHistoryOps, err := removePortsHistoryOps(m.st, m.Id())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 
 	sb, err := NewStorageBackend(m.st)
 	if err != nil {
@@ -1024,6 +1350,7 @@ func (m *Machine) removeOps() ([]txn.Op, error) {
 	ops = append(ops, linkLayerDevicesOps...)
 	ops = append(ops, devicesAddressesOps...)
 	ops = append(ops, portsOps...)
+	ops = append(ops, portsHistoryOps...)
 	ops = append(ops, removeContainerRefOps(m.st, m.Id())...)
 	ops = append(ops, filesystemOps...)
 	ops = append(ops, volumeOps...)
@@ -1871,6 +2198,10 @@ func (m *Machine) Status() (status.StatusInfo, error) {
 func (m *Machine) SetStatus(statusInfo status.StatusInfo) error {
 	switch statusInfo.Status {
 	case status.Started, status.Stopped:
+	case status.Draining:
+		if m.doc.Life == Alive {
+			return errors.Errorf("cannot set status %q while machine is alive", statusInfo.Status)
+		}
 	case status.Error:
 		if statusInfo.Message == "" {
 			return errors.Errorf("cannot set status %q without info", statusInfo.Status)
@@ -126,6 +126,104 @@ func (s *MachineSuite) TestSetUnsetRebootFlag(c *gc.C) {
 	c.Assert(rebootFlag, jc.IsFalse)
 }
 
+func (s *MachineSuite) TestSetHostname(c *gc.C) {
+	c.Assert(s.machine.Hostname(), gc.Equals, "")
+	err := s.machine.SetHostname("juju-0.example.com")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.Hostname(), gc.Equals, "juju-0.example.com")
+
+	m, err := s.State.Machine(s.machine.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Hostname(), gc.Equals, "juju-0.example.com")
+}
+
+func (s *MachineSuite) TestSetHostnameEmpty(c *gc.C) {
+	err := s.machine.SetHostname("")
+	c.Assert(err, gc.ErrorMatches, "empty hostname not valid")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *MachineSuite) TestSetHostnameDeadMachine(c *gc.C) {
+	err := s.machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetHostname("juju-0.example.com")
+	c.Assert(err, gc.ErrorMatches, `cannot set hostname of machine 1: not found or dead`)
+}
+
+func (s *MachineSuite) TestSetCordoned(c *gc.C) {
+	c.Assert(s.machine.IsCordoned(), jc.IsFalse)
+	err := s.machine.SetCordoned(true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.IsCordoned(), jc.IsTrue)
+
+	m, err := s.State.Machine(s.machine.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.IsCordoned(), jc.IsTrue)
+
+	err = m.SetCordoned(false)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.IsCordoned(), jc.IsFalse)
+}
+
+func (s *MachineSuite) TestSetCordonedDeadMachine(c *gc.C) {
+	err := s.machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetCordoned(true)
+	c.Assert(err, gc.ErrorMatches, `cannot set cordoned of machine 1: not found or dead`)
+}
+
+func (s *MachineSuite) TestSetHostInfo(c *gc.C) {
+	arch := "amd64"
+	mem := uint64(4096)
+	err := s.machine.SetProvisioned("umbrella/0", "", "fake_nonce", &instance.HardwareCharacteristics{
+		Arch: &arch,
+		Mem:  &mem,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	newMem := uint64(8192)
+	rootDisk := uint64(20480)
+	err = s.machine.SetHostInfo(state.HostInfo{
+		KernelVersion: "4.15.0-58-generic",
+		Mem:           &newMem,
+		RootDisk:      &rootDisk,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.KernelVersion(), gc.Equals, "4.15.0-58-generic")
+
+	m, err := s.State.Machine(s.machine.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.KernelVersion(), gc.Equals, "4.15.0-58-generic")
+	hc, err := m.HardwareCharacteristics()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*hc, jc.DeepEquals, instance.HardwareCharacteristics{
+		Arch:     &arch,
+		Mem:      &newMem,
+		RootDisk: &rootDisk,
+	})
+}
+
+func (s *MachineSuite) TestSetHostInfoNotProvisioned(c *gc.C) {
+	mem := uint64(8192)
+	err := s.machine.SetHostInfo(state.HostInfo{Mem: &mem})
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+	c.Assert(err, gc.ErrorMatches, `cannot set host info of machine 1: machine 1 not provisioned`)
+
+	// The kernel version can be recorded without hardware details.
+	err = s.machine.SetHostInfo(state.HostInfo{KernelVersion: "4.15.0-58-generic"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.KernelVersion(), gc.Equals, "4.15.0-58-generic")
+}
+
+func (s *MachineSuite) TestSetHostInfoDeadMachine(c *gc.C) {
+	err := s.machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetHostInfo(state.HostInfo{KernelVersion: "4.15.0-58-generic"})
+	c.Assert(err, gc.ErrorMatches, `cannot set host info of machine 1: not found or dead`)
+}
+
 func (s *MachineSuite) TestSetKeepInstance(c *gc.C) {
 	err := s.machine.SetProvisioned("1234", "", "nonce", nil)
 	c.Assert(err, jc.ErrorIsNil)
@@ -139,6 +237,58 @@ func (s *MachineSuite) TestSetKeepInstance(c *gc.C) {
 	c.Assert(keep, jc.IsTrue)
 }
 
+func (s *MachineSuite) TestSetCharmProfilesRecordsPrevious(c *gc.C) {
+	err := s.machine.SetProvisioned("1234", "", "nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetCharmProfiles([]string{"default", "juju-default"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetCharmProfiles([]string{"default", "juju-default", "juju-default-app-1"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	profiles, err := s.machine.CharmProfiles()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(profiles, jc.DeepEquals, []string{"default", "juju-default", "juju-default-app-1"})
+	previous, err := s.machine.PreviousCharmProfiles()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(previous, jc.DeepEquals, []string{"default", "juju-default"})
+}
+
+func (s *MachineSuite) TestRequestCharmProfilesRollback(c *gc.C) {
+	err := s.machine.SetProvisioned("1234", "", "nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetCharmProfiles([]string{"default", "juju-default"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetCharmProfiles([]string{"default", "juju-default", "juju-default-app-1"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	rollback, err := s.machine.CharmProfilesRollback()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollback, gc.IsNil)
+
+	err = s.machine.RequestCharmProfilesRollback()
+	c.Assert(err, jc.ErrorIsNil)
+	rollback, err = s.machine.CharmProfilesRollback()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollback, jc.DeepEquals, []string{"default", "juju-default"})
+
+	// Changing the charm profiles cancels the rollback.
+	err = s.machine.SetCharmProfiles([]string{"default", "juju-default", "juju-default-app-2"})
+	c.Assert(err, jc.ErrorIsNil)
+	rollback, err = s.machine.CharmProfilesRollback()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollback, gc.IsNil)
+}
+
+func (s *MachineSuite) TestRequestCharmProfilesRollbackNoPrevious(c *gc.C) {
+	err := s.machine.RequestCharmProfilesRollback()
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+
+	err = s.machine.SetProvisioned("1234", "", "nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.RequestCharmProfilesRollback()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *MachineSuite) TestAddMachineInsideMachineModelDying(c *gc.C) {
 	model, err := s.State.Model()
 	c.Assert(err, jc.ErrorIsNil)
@@ -597,6 +747,17 @@ func (s *MachineSuite) TestTag(c *gc.C) {
 	c.Assert(s.machine.Tag(), gc.Equals, asTag)
 }
 
+func (s *MachineSuite) TestModelUUID(c *gc.C) {
+	c.Assert(s.machine.ModelUUID(), gc.Equals, s.State.ModelUUID())
+
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	machine, err := st.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.ModelUUID(), gc.Equals, st.ModelUUID())
+	c.Assert(machine.ModelUUID(), gc.Not(gc.Equals), s.State.ModelUUID())
+}
+
 func (s *MachineSuite) TestSetMongoPassword(c *gc.C) {
 	testSetMongoPassword(c, func(st *state.State, id string) (mongoPasswordSetter, error) {
 		return st.Machine("0")
@@ -696,6 +857,43 @@ func (s *MachineSuite) TestSetPassword(c *gc.C) {
 	})
 }
 
+func (s *MachineSuite) TestHasPassword(c *gc.C) {
+	c.Assert(s.machine.HasPassword(), jc.IsFalse)
+	err := s.machine.SetPassword("abcdefghijklmnopqrstuvwx")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.HasPassword(), jc.IsTrue)
+
+	machine, err := s.State.Machine(s.machine.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.HasPassword(), jc.IsTrue)
+}
+
+func (s *MachineSuite) TestConsumeInstanceIdentity(c *gc.C) {
+	err := s.machine.SetProvisioned("i-enrolling", "", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.machine.ConsumeInstanceIdentity("other_nonce", "digest")
+	c.Assert(err, gc.ErrorMatches, `cannot consume instance identity for machine 1: already enrolled, not provisioned with the nonce, or dead`)
+
+	err = s.machine.ConsumeInstanceIdentity("fake_nonce", "digest")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Until a password is set, the same document can be used again,
+	// but not another.
+	machine, err := s.State.Machine(s.machine.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.ConsumeInstanceIdentity("fake_nonce", "digest")
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.ConsumeInstanceIdentity("fake_nonce", "other-digest")
+	c.Assert(err, gc.ErrorMatches, `cannot consume instance identity for machine 1: already enrolled, not provisioned with the nonce, or dead`)
+
+	// Once a password is set, the document cannot be used again.
+	err = machine.SetPassword("machine-password-12345")
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.ConsumeInstanceIdentity("fake_nonce", "digest")
+	c.Assert(err, gc.ErrorMatches, `cannot consume instance identity for machine 1: already enrolled, not provisioned with the nonce, or dead`)
+}
+
 func (s *MachineSuite) TestMachineWaitAgentPresence(c *gc.C) {
 	alive, err := s.machine.AgentPresence()
 	c.Assert(err, jc.ErrorIsNil)
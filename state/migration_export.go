@@ -491,20 +491,28 @@ func (e *exporter) newMachine(exParent description.Machine, machine *Machine, in
 }
 
 func (e *exporter) openedPortsArgsForMachine(machineId string, portsData []portsDoc) []description.OpenedPortsArgs {
+	// Each unit's ports are held in a separate document, but are
+	// exported together for each subnet.
 	var result []description.OpenedPortsArgs
+	bySubnet := make(map[string]int)
 	for _, doc := range portsData {
 		// Don't bother including a subnet if there are no ports open on it.
-		if doc.MachineID == machineId && len(doc.Ports) > 0 {
-			args := description.OpenedPortsArgs{SubnetID: doc.SubnetID}
-			for _, p := range doc.Ports {
-				args.OpenedPorts = append(args.OpenedPorts, description.PortRangeArgs{
-					UnitName: p.UnitName,
-					FromPort: p.FromPort,
-					ToPort:   p.ToPort,
-					Protocol: p.Protocol,
-				})
-			}
-			result = append(result, args)
+		if doc.MachineID != machineId || len(doc.Ports) == 0 {
+			continue
+		}
+		i, ok := bySubnet[doc.SubnetID]
+		if !ok {
+			i = len(result)
+			bySubnet[doc.SubnetID] = i
+			result = append(result, description.OpenedPortsArgs{SubnetID: doc.SubnetID})
+		}
+		for _, p := range doc.Ports {
+			result[i].OpenedPorts = append(result[i].OpenedPorts, description.PortRangeArgs{
+				UnitName: p.UnitName,
+				FromPort: p.FromPort,
+				ToPort:   p.ToPort,
+				Protocol: p.Protocol,
+			})
 		}
 	}
 	return result
@@ -499,24 +499,36 @@ func (i *importer) machinePortsOps(m description.Machine) ([]txn.Op, error) {
 			}
 			subnetID = subnet.ID()
 		}
-		doc := &portsDoc{
-			MachineID: machineID,
-			SubnetID:  subnetID,
-		}
+		// Each unit's ports are held in a separate document.
+		var unitNames []string
+		unitDocs := make(map[string]*portsDoc)
 		for _, opened := range ports.OpenPorts() {
+			unitName := opened.UnitName()
+			doc, ok := unitDocs[unitName]
+			if !ok {
+				doc = &portsDoc{
+					MachineID: machineID,
+					SubnetID:  subnetID,
+					UnitName:  unitName,
+				}
+				unitDocs[unitName] = doc
+				unitNames = append(unitNames, unitName)
+			}
 			doc.Ports = append(doc.Ports, PortRange{
-				UnitName: opened.UnitName(),
+				UnitName: unitName,
 				FromPort: opened.FromPort(),
 				ToPort:   opened.ToPort(),
 				Protocol: opened.Protocol(),
 			})
 		}
-		result = append(result, txn.Op{
-			C:      openedPortsC,
-			Id:     portsGlobalKey(machineID, subnetID),
-			Assert: txn.DocMissing,
-			Insert: doc,
-		})
+		for _, unitName := range unitNames {
+			result = append(result, txn.Op{
+				C:      openedPortsC,
+				Id:     unitPortsGlobalKey(machineID, subnetID, unitName),
+				Assert: txn.DocMissing,
+				Insert: unitDocs[unitName],
+			})
+		}
 	}
 
 	return result, nil
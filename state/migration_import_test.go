@@ -457,10 +457,21 @@ func (s *MigrationImportSuite) testMachinePortOps(c *gc.C, setup, validate strin
 
 	ops, err := state.MachinePortOps(s.State, mockMachine)
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(ops, gc.HasLen, 1)
-	c.Assert(ops[0].Id, gc.Equals, fmt.Sprintf("m#3#%s", validate))
+	c.Assert(ops, gc.HasLen, 2)
+	c.Assert(ops[0].Id, gc.Equals, fmt.Sprintf("m#3#%s#u#wordpress/0", validate))
+	c.Assert(ops[1].Id, gc.Equals, fmt.Sprintf("m#3#%s#u#wordpress/1", validate))
 }
 
+type fakePortRange struct {
+	unitName string
+	port     int
+}
+
+func (p fakePortRange) UnitName() string { return p.unitName }
+func (p fakePortRange) FromPort() int    { return p.port }
+func (p fakePortRange) ToPort() int      { return p.port }
+func (p fakePortRange) Protocol() string { return "tcp" }
+
 //go:generate mockgen -package mocks -destination mocks/description_mock.go github.com/juju/description Machine,OpenedPorts
 func setupMockOpenedPorts(c *gc.C, mID, subnetID string) (*gomock.Controller, *mocks.MockMachine) {
 	ctrl := gomock.NewController(c)
@@ -473,7 +484,11 @@ func setupMockOpenedPorts(c *gc.C, mID, subnetID string) (*gomock.Controller, *m
 
 	opExp := mockOpenedPorts.EXPECT()
 	opExp.SubnetID().Return(subnetID)
-	opExp.OpenPorts().Return(nil)
+	opExp.OpenPorts().Return([]description.PortRange{
+		fakePortRange{"wordpress/0", 80},
+		fakePortRange{"wordpress/1", 8080},
+		fakePortRange{"wordpress/0", 443},
+	})
 
 	return ctrl, mockMachine
 }
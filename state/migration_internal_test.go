@@ -106,22 +106,31 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 		// Users aren't migrated.
 		usersC,
 		userLastLoginC,
+		// API keys are tied to users, which aren't migrated.
+		apiKeysC,
 		// Controller users contain extra data about users therefore
 		// are not migrated either.
 		controllerUsersC,
 		// userenvnameC is just to provide a unique key constraint.
 		usermodelnameC,
+		// Model aliases are recreated when the model is imported.
+		modelAliasesC,
 		// Metrics aren't migrated.
 		metricsC,
+		// The audit trail records calls made to this controller.
+		auditTrailC,
 		// Backup and restore information is not migrated.
 		restoreInfoC,
 		// reference counts are implementation details that should be
 		// reconstructed on the other side.
 		refcountsC,
 		globalRefcountsC,
-		// upgradeInfoC is used to coordinate upgrades and schema migrations,
-		// and aren't needed for model migrations.
+		// upgradeInfoC, upgradeCheckpointsC and upgradeSnapshotRestoresC
+		// are used to coordinate upgrades and schema migrations, and
+		// aren't needed for model migrations.
 		upgradeInfoC,
+		upgradeCheckpointsC,
+		upgradeSnapshotRestoresC,
 		// Not exported, but the tools will possibly need to be either bundled
 		// with the representation or sent separately.
 		toolsmetadataC,
@@ -167,6 +176,17 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 		// Recreated whilst migrating actions.
 		actionNotificationsC,
 
+		// Webhooks are only invoked by the controller the actions
+		// were enqueued on.
+		actionWebhooksC,
+
+		// The ports history is a record of changes made on the
+		// source controller, and is not migrated.
+		portsHistoryC,
+
+		// Model stats are collected afresh by each controller.
+		modelStatsC,
+
 		// Global settings store controller specific configuration settings
 		// and are not to be migrated.
 		globalSettingsC,
@@ -176,6 +196,22 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 		// migrate that information.
 		rebootC,
 
+		// Network boot user-data is only needed while a machine is
+		// being provisioned, which the precheck ensures isn't the case.
+		networkBootC,
+
+		// Upgrade step completion is only tracked while the model is
+		// upgrading, which the precheck ensures isn't the case.
+		upgradeStepsCompletedC,
+
+		// Scale operation tokens only guard against a scale plan being
+		// applied twice in quick succession, so they are not migrated.
+		scaleOperationsC,
+
+		// Idempotency keys only guard against retried API calls being
+		// applied twice, so they are not migrated either.
+		idempotencyKeysC,
+
 		// Charms are added into the migrated model during the binary transfer
 		// phase after the initial model migration.
 		charmsC,
@@ -251,6 +287,12 @@ func (s *MigrationSuite) TestModelDocFields(c *gc.C) {
 		// ControllerUUID is recreated when the new model is created
 		// in the new controller (yay name changes).
 		"ControllerUUID",
+		// Feature flags stage new behaviour on a particular
+		// controller, so are left to the target controller.
+		"Features",
+		// The alias is chosen again from the model's UUID when the
+		// model is created on the target controller.
+		"Alias",
 
 		"Type",
 		"MigrationMode",
@@ -320,6 +362,24 @@ func (s *MigrationSuite) TestMachineDocFields(c *gc.C) {
 		// Ignored at this stage, could be an issue if mongo 3.0 isn't
 		// available.
 		"StopMongoUntilVersion",
+		// The boot id is reported again by the machine agent when it
+		// starts after the migration.
+		"BootID",
+		"RebootExpected",
+		// Reboots are not carried across a migration, so nor is their
+		// progress.
+		"RebootRequested",
+		"RebootCompleted",
+		// The hostname and kernel version are likewise reported again
+		// by the machine agent.
+		"Hostname",
+		"KernelVersion",
+		// The model description has no record of cordoning, so
+		// machines must be cordoned again after a migration.
+		"Cordoned",
+		// The identity digest only matters until the agent sets its
+		// password, which it has done before the model is migrated.
+		"InstanceIdentityDigest",
 	)
 	migrated := set.NewStrings(
 		"Addresses",
@@ -348,6 +408,10 @@ func (s *MigrationSuite) TestInstanceDataFields(c *gc.C) {
 		// KeepInstance is only set when a machine is
 		// dying/dead (to be removed).
 		"KeepInstance",
+		// The previous charm profiles, and any rollback to
+		// them, belong to the source controller's workers.
+		"PreviousCharmProfiles",
+		"CharmProfilesRollback",
 	)
 	migrated := set.NewStrings(
 		// DocID is the model + machine id
@@ -388,6 +452,15 @@ func (s *MigrationSuite) TestApplicationDocFields(c *gc.C) {
 		// RelationCount is handled by the number of times the application name
 		// appears in relation endpoints.
 		"RelationCount",
+		// SubordinatePolicy is not yet supported by the model
+		// description; the migration precheck refuses models using it.
+		"SubordinatePolicy",
+		// EgressRules are not yet supported by the model
+		// description; the migration precheck refuses models using them.
+		"EgressRules",
+		// ExposedEndpoints are not yet supported by the model
+		// description; the migration precheck refuses models using them.
+		"ExposedEndpoints",
 	)
 	migrated := set.NewStrings(
 		"Name",
@@ -421,6 +494,12 @@ func (s *MigrationSuite) TestUnitDocFields(c *gc.C) {
 		"Series",
 		"CharmURL",
 		"TxnRevno",
+		// EgressAddresses are reported by agents and providers,
+		// and are not carried across a migration.
+		"EgressAddresses",
+		// A password rotation request is satisfied by the deployer
+		// in the source model, or made again after the migration.
+		"PasswordRotationRequested",
 	)
 	migrated := set.NewStrings(
 		"Name",
@@ -445,6 +524,8 @@ func (s *MigrationSuite) TestPortsDocFields(c *gc.C) {
 		"MachineID",
 		"SubnetID",
 		"Ports",
+		// UnitName is implicit in the migrated port ranges.
+		"UnitName",
 		// TxnRevno isn't migrated.
 		"TxnRevno",
 	)
@@ -710,6 +791,8 @@ func (s *MigrationSuite) TestSSHHostKeyDocFields(c *gc.C) {
 func (s *MigrationSuite) TestActionDocFields(c *gc.C) {
 	ignored := set.NewStrings(
 		"ModelUUID",
+		// Webhooks are not migrated.
+		"Operation",
 	)
 	migrated := set.NewStrings(
 		"DocId",
@@ -82,6 +82,7 @@ type modelDoc struct {
 	UUID           string        `bson:"_id"`
 	Name           string        `bson:"name"`
 	Type           ModelType     `bson:"type"`
+	Alias          string        `bson:"alias,omitempty"`
 	Life           Life          `bson:"life"`
 	Owner          string        `bson:"owner"`
 	ControllerUUID string        `bson:"controller-uuid"`
@@ -118,6 +119,10 @@ type modelDoc struct {
 	// this model. It only has any meaning when the model is dying or
 	// dead.
 	ForceDestroyed bool `bson:"force-destroyed,omitempty"`
+
+	// Features holds the feature flags enabled for this model, in
+	// addition to those enabled for the whole controller.
+	Features []string `bson:"features,omitempty"`
 }
 
 // slaLevel enumerates the support levels available to a model.
@@ -1558,11 +1563,11 @@ func removeModelEntityRefOp(mb modelBackend, entityField, entityId string) txn.O
 }
 
 // createModelOp returns the operation needed to create
-// an model document with the given name and UUID.
+// an model document with the given name, UUID and alias.
 func createModelOp(
 	modelType ModelType,
 	owner names.UserTag,
-	name, uuid, controllerUUID, cloudName, cloudRegion string,
+	name, uuid, alias, controllerUUID, cloudName, cloudRegion string,
 	cloudCredential names.CloudCredentialTag,
 	migrationMode MigrationMode,
 	environVersion int,
@@ -1570,6 +1575,7 @@ func createModelOp(
 	doc := &modelDoc{
 		Type:            modelType,
 		UUID:            uuid,
+		Alias:           alias,
 		Name:            name,
 		Life:            Alive,
 		Owner:           owner.Id(),
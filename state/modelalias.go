@@ -0,0 +1,149 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// minModelAliasLength is the length of the shortest model alias, that of
+// the first group of hex digits in a model UUID.
+const minModelAliasLength = 8
+
+// modelAliasDoc records the model that a model alias refers to. The
+// document's id, the alias, provides a unique key constraint across
+// the controller. The document is kept when the model is removed, so
+// that the alias is never given to another model.
+type modelAliasDoc struct {
+	Alias     string `bson:"_id"`
+	ModelUUID string `bson:"model-uuid"`
+	Removed   bool   `bson:"removed,omitempty"`
+}
+
+// newModelAlias returns the alias to use for the model with the given
+// UUID, and the operation that reserves it. The alias is the shortest
+// prefix of the UUID's hex digits, of at least minModelAliasLength
+// digits, that is not already in use. Model UUIDs being random, the
+// alias is almost always the first 8 digits, so a model keeps its alias
+// when it is migrated to another controller. An alias left by a removed
+// model is only used again for the same model, when it is migrated back.
+func (st *State) newModelAlias(modelUUID string) (string, txn.Op, error) {
+	digits := strings.Replace(modelUUID, "-", "", -1)
+	aliases, closer := st.db().GetCollection(modelAliasesC)
+	defer closer()
+	for n := minModelAliasLength; n <= len(digits); n++ {
+		alias := digits[:n]
+		var doc modelAliasDoc
+		err := aliases.FindId(alias).One(&doc)
+		if err == mgo.ErrNotFound {
+			return alias, createModelAliasOp(alias, modelUUID), nil
+		} else if err != nil {
+			return "", txn.Op{}, errors.Trace(err)
+		}
+		if doc.Removed && doc.ModelUUID == modelUUID {
+			return alias, restoreModelAliasOp(alias, modelUUID), nil
+		}
+	}
+	return "", txn.Op{}, errors.Errorf("no alias available for model %q", modelUUID)
+}
+
+// createModelAliasOp returns the operation needed to reserve the alias
+// for the model with the given UUID.
+func createModelAliasOp(alias, modelUUID string) txn.Op {
+	return txn.Op{
+		C:      modelAliasesC,
+		Id:     alias,
+		Assert: txn.DocMissing,
+		Insert: &modelAliasDoc{ModelUUID: modelUUID},
+	}
+}
+
+// restoreModelAliasOp returns the operation needed to reserve the alias
+// left by the removed model with the given UUID for the model again.
+func restoreModelAliasOp(alias, modelUUID string) txn.Op {
+	return txn.Op{
+		C:  modelAliasesC,
+		Id: alias,
+		Assert: bson.D{
+			{"model-uuid", modelUUID},
+			{"removed", true},
+		},
+		Update: bson.D{{"$unset", bson.D{{"removed", nil}}}},
+	}
+}
+
+// removeModelAliasOp returns the operation needed to record that the
+// model with the given alias has been removed. The alias stays reserved,
+// so that it never refers to another model.
+func removeModelAliasOp(alias string) txn.Op {
+	return txn.Op{
+		C:      modelAliasesC,
+		Id:     alias,
+		Update: bson.D{{"$set", bson.D{{"removed", true}}}},
+	}
+}
+
+// Alias returns the short, immutable alias of the model, which may be
+// used in place of the model's UUID. It is empty only for models created
+// before aliases were introduced, until the controller is upgraded.
+func (m *Model) Alias() string {
+	return m.doc.Alias
+}
+
+// MachineAlias returns the alias of the model's machine with the given
+// id, which is unique across controllers as long as the model's alias
+// is. It has the form <model-alias>:<machine-id>.
+func (m *Model) MachineAlias(machineId string) string {
+	if m.doc.Alias == "" {
+		return ""
+	}
+	return m.doc.Alias + ":" + machineId
+}
+
+// ResolveModelUUID returns the UUID of the model referred to by the
+// given model UUID or model alias. It returns a not found error if
+// there is no such alias, or its model has been removed; model UUIDs
+// are returned unchecked.
+func (st *State) ResolveModelUUID(uuidOrAlias string) (string, error) {
+	if names.IsValidModel(uuidOrAlias) {
+		return uuidOrAlias, nil
+	}
+	aliases, closer := st.db().GetCollection(modelAliasesC)
+	defer closer()
+	var doc modelAliasDoc
+	if err := aliases.FindId(uuidOrAlias).One(&doc); err == mgo.ErrNotFound || doc.Removed {
+		return "", errors.NotFoundf("model %q", uuidOrAlias)
+	} else if err != nil {
+		return "", errors.Trace(err)
+	}
+	return doc.ModelUUID, nil
+}
+
+// ResolveMachineId returns the id of the machine in the state's model
+// that is referred to by the given machine id or machine alias. It
+// returns a not found error if the alias refers to another model, or
+// to no model at all; machine ids are returned unchecked.
+func (st *State) ResolveMachineId(idOrAlias string) (string, error) {
+	if names.IsValidMachine(idOrAlias) {
+		return idOrAlias, nil
+	}
+	parts := strings.SplitN(idOrAlias, ":", 2)
+	if len(parts) != 2 || !names.IsValidMachine(parts[1]) {
+		return "", errors.NotValidf("machine id or alias %q", idOrAlias)
+	}
+	modelUUID, err := st.ResolveModelUUID(parts[0])
+	if err != nil && !errors.IsNotFound(err) {
+		return "", errors.Trace(err)
+	}
+	if err != nil || modelUUID != st.ModelUUID() {
+		return "", errors.NotFoundf("machine %q in model %q", idOrAlias, st.ModelUUID())
+	}
+	return parts[1], nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type ModelAliasSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&ModelAliasSuite{})
+
+const aliasTestModelUUID = "1a2b3c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d"
+
+func (s *ModelAliasSuite) makeModel(c *gc.C) (*state.State, *state.Model) {
+	st := s.Factory.MakeModel(c, &factory.ModelParams{
+		ConfigAttrs: map[string]interface{}{"uuid": aliasTestModelUUID},
+	})
+	s.AddCleanup(func(*gc.C) { st.Close() })
+	m, err := st.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	return st, m
+}
+
+func (s *ModelAliasSuite) TestNewModelAlias(c *gc.C) {
+	_, m := s.makeModel(c)
+	c.Assert(m.Alias(), gc.Equals, "1a2b3c4d")
+	c.Assert(m.MachineAlias("0/lxd/1"), gc.Equals, "1a2b3c4d:0/lxd/1")
+
+	uuid, err := s.State.ResolveModelUUID("1a2b3c4d")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(uuid, gc.Equals, aliasTestModelUUID)
+}
+
+func (s *ModelAliasSuite) TestNewModelAliasCollision(c *gc.C) {
+	err := state.ReserveModelAlias(s.State, "1a2b3c4d", "another-model-uuid")
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, m := s.makeModel(c)
+	c.Assert(m.Alias(), gc.Equals, "1a2b3c4d5")
+}
+
+func (s *ModelAliasSuite) TestResolveModelUUID(c *gc.C) {
+	uuid, err := s.State.ResolveModelUUID(aliasTestModelUUID)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(uuid, gc.Equals, aliasTestModelUUID)
+
+	_, err = s.State.ResolveModelUUID("deadbeef")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ModelAliasSuite) TestResolveMachineId(c *gc.C) {
+	st, _ := s.makeModel(c)
+	for i, t := range []struct {
+		idOrAlias string
+		id        string
+		err       string
+	}{
+		{idOrAlias: "0/lxd/1", id: "0/lxd/1"},
+		{idOrAlias: "1a2b3c4d:0/lxd/1", id: "0/lxd/1"},
+		{idOrAlias: aliasTestModelUUID + ":2", id: "2"},
+		{idOrAlias: "deadbeef:2", err: `machine "deadbeef:2" in model "` + aliasTestModelUUID + `" not found`},
+		{idOrAlias: "1a2b3c4d:foo", err: `machine id or alias "1a2b3c4d:foo" not valid`},
+	} {
+		c.Logf("test %d: %q", i, t.idOrAlias)
+		id, err := st.ResolveMachineId(t.idOrAlias)
+		if t.err != "" {
+			c.Check(err, gc.ErrorMatches, t.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(id, gc.Equals, t.id)
+	}
+
+	// Aliases of machines in other models are not resolved.
+	_, err := s.State.ResolveMachineId("1a2b3c4d:0")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ModelAliasSuite) TestRemovedModelAliasNotReused(c *gc.C) {
+	st, m := s.makeModel(c)
+	err := m.Destroy(state.DestroyModelParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.SetDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = st.RemoveDyingModel()
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.ResolveModelUUID("1a2b3c4d")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// Another model whose UUID has the same prefix does not take
+	// the removed model's alias.
+	other := s.Factory.MakeModel(c, &factory.ModelParams{
+		ConfigAttrs: map[string]interface{}{"uuid": "1a2b3c4d-0000-4a7b-8c9d-0e1f2a3b4c5d"},
+	})
+	defer other.Close()
+	otherModel, err := other.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(otherModel.Alias(), gc.Equals, "1a2b3c4d0")
+}
+
+func (s *ModelAliasSuite) TestRemovedModelAliasRestored(c *gc.C) {
+	st, m := s.makeModel(c)
+	err := m.Destroy(state.DestroyModelParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.SetDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = st.RemoveDyingModel()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The same model, migrated back, gets its alias again.
+	_, m = s.makeModel(c)
+	c.Assert(m.Alias(), gc.Equals, "1a2b3c4d")
+	uuid, err := s.State.ResolveModelUUID("1a2b3c4d")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(uuid, gc.Equals, aliasTestModelUUID)
+}
@@ -0,0 +1,77 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"strings"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// Features returns the feature flags enabled for the model, in addition
+// to those enabled for the whole controller, in sorted order.
+func (m *Model) Features() []string {
+	features := make([]string, len(m.doc.Features))
+	copy(features, m.doc.Features)
+	return features
+}
+
+// UpdateFeatures enables and disables feature flags for the model. Flags
+// that are not mentioned are left as they are; a flag may not be both
+// enabled and disabled at once.
+func (m *Model) UpdateFeatures(enable, disable []string) error {
+	enabled := set.NewStrings(enable...)
+	disabled := set.NewStrings(disable...)
+	for _, flag := range enabled.Union(disabled).Values() {
+		if strings.TrimSpace(flag) == "" || strings.ContainsAny(flag, " \t,") {
+			return errors.NotValidf("feature flag %q", flag)
+		}
+	}
+	if both := enabled.Intersection(disabled); !both.IsEmpty() {
+		return errors.NotValidf("enabling and disabling feature flags %v", both.SortedValues())
+	}
+
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := m.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if m.Life() != Alive {
+			return nil, errors.Errorf("model is no longer alive")
+		}
+		current := set.NewStrings(m.doc.Features...)
+		features := current.Union(enabled).Difference(disabled)
+		if features.Size() == current.Size() && features.Difference(current).IsEmpty() {
+			return nil, jujutxn.ErrNoOperations
+		}
+
+		assert := bson.D{{"life", Alive}}
+		if len(m.doc.Features) == 0 {
+			assert = append(assert, bson.DocElem{"features", bson.D{{"$exists", false}}})
+		} else {
+			assert = append(assert, bson.DocElem{"features", m.doc.Features})
+		}
+		var update bson.D
+		if features.IsEmpty() {
+			update = bson.D{{"$unset", bson.D{{"features", nil}}}}
+		} else {
+			update = bson.D{{"$set", bson.D{{"features", features.SortedValues()}}}}
+		}
+		return []txn.Op{{
+			C:      modelsC,
+			Id:     m.doc.UUID,
+			Assert: assert,
+			Update: update,
+		}}, nil
+	}
+	if err := m.st.db().Run(buildTxn); err != nil {
+		return errors.Annotate(err, "cannot update model features")
+	}
+	return m.Refresh()
+}
@@ -0,0 +1,62 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+)
+
+type ModelFeaturesSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&ModelFeaturesSuite{})
+
+func (s *ModelFeaturesSuite) TestUpdateFeatures(c *gc.C) {
+	c.Assert(s.Model.Features(), gc.HasLen, 0)
+
+	err := s.Model.UpdateFeatures([]string{"new-resolver", "enhanced-firewaller"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.Model.Features(), jc.DeepEquals, []string{"enhanced-firewaller", "new-resolver"})
+
+	err = s.Model.UpdateFeatures([]string{"other"}, []string{"new-resolver", "unknown"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.Model.Features(), jc.DeepEquals, []string{"enhanced-firewaller", "other"})
+
+	model, err := s.State.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(model.Features(), jc.DeepEquals, []string{"enhanced-firewaller", "other"})
+
+	err = s.Model.UpdateFeatures(nil, []string{"enhanced-firewaller", "other"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.Model.Features(), gc.HasLen, 0)
+}
+
+func (s *ModelFeaturesSuite) TestUpdateFeaturesNoChange(c *gc.C) {
+	err := s.Model.UpdateFeatures(nil, []string{"new-resolver"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.Model.Features(), gc.HasLen, 0)
+}
+
+func (s *ModelFeaturesSuite) TestUpdateFeaturesConcurrently(c *gc.C) {
+	model, err := s.State.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	err = model.UpdateFeatures([]string{"enhanced-firewaller"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// s.Model is now out of date, but the change is not lost.
+	err = s.Model.UpdateFeatures([]string{"new-resolver"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.Model.Features(), jc.DeepEquals, []string{"enhanced-firewaller", "new-resolver"})
+}
+
+func (s *ModelFeaturesSuite) TestUpdateFeaturesInvalid(c *gc.C) {
+	err := s.Model.UpdateFeatures([]string{""}, nil)
+	c.Assert(err, gc.ErrorMatches, `feature flag "" not valid`)
+	err = s.Model.UpdateFeatures([]string{"a,b"}, nil)
+	c.Assert(err, gc.ErrorMatches, `feature flag "a,b" not valid`)
+	err = s.Model.UpdateFeatures([]string{"a"}, []string{"a"})
+	c.Assert(err, gc.ErrorMatches, `enabling and disabling feature flags \[a\] not valid`)
+}
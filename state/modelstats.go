@@ -0,0 +1,206 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"sort"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// CollectionStats holds the number of documents a model has in a
+// collection, and their approximate total size in bytes.
+type CollectionStats struct {
+	Collection string
+	Count      int
+	Size       int64
+}
+
+// ModelStats holds the document statistics of a model, as last
+// collected.
+type ModelStats struct {
+	ModelUUID   string
+	Collections []CollectionStats
+	Updated     time.Time
+}
+
+// modelStatsDoc records the document statistics of a model. It is keyed
+// by the model's UUID.
+type modelStatsDoc struct {
+	DocID       string                        `bson:"_id"`
+	Collections map[string]collectionStatsDoc `bson:"collections"`
+	Updated     int64                         `bson:"updated"`
+}
+
+type collectionStatsDoc struct {
+	Count int   `bson:"count"`
+	Size  int64 `bson:"size"`
+}
+
+// CollectModelStats counts the documents each model has in each of the
+// model collections, returning the results keyed by model UUID. Sizes
+// are estimated from the average document size of each collection.
+func (st *State) CollectModelStats() (map[string][]CollectionStats, error) {
+	var names []string
+	for name, info := range st.db().Schema() {
+		if !info.global {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	result := make(map[string][]CollectionStats)
+	for _, name := range names {
+		if err := st.collectStats(name, result); err != nil {
+			return nil, errors.Annotatef(err, "cannot collect stats for %q", name)
+		}
+	}
+	return result, nil
+}
+
+func (st *State) collectStats(name string, result map[string][]CollectionStats) error {
+	coll, closer := st.db().GetRawCollection(name)
+	defer closer()
+
+	var counts []struct {
+		ModelUUID string `bson:"_id"`
+		Count     int    `bson:"count"`
+	}
+	err := coll.Pipe([]bson.M{{
+		"$group": bson.M{"_id": "$model-uuid", "count": bson.M{"$sum": 1}},
+	}}).All(&counts)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(counts) == 0 {
+		return nil
+	}
+	avgSize, err := averageDocSize(coll)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, count := range counts {
+		if count.ModelUUID == "" {
+			continue
+		}
+		result[count.ModelUUID] = append(result[count.ModelUUID], CollectionStats{
+			Collection: name,
+			Count:      count.Count,
+			Size:       int64(float64(count.Count) * avgSize),
+		})
+	}
+	return nil
+}
+
+// OrphanedDocCounts counts the documents in each of the model
+// collections that belong to models that no longer exist, returning the
+// counts keyed by collection name. Collections without any orphaned
+// documents are omitted.
+func (st *State) OrphanedDocCounts() (map[string]int, error) {
+	modelUUIDs, err := st.AllModelUUIDsIncludingDead()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get model UUIDs")
+	}
+	// Documents without a model UUID aren't orphans.
+	known := append(modelUUIDs, "")
+
+	result := make(map[string]int)
+	for name, info := range st.db().Schema() {
+		if info.global {
+			continue
+		}
+		coll, closer := st.db().GetRawCollection(name)
+		count, err := coll.Find(bson.D{{
+			"model-uuid", bson.D{{"$exists", true}, {"$nin", known}},
+		}}).Count()
+		closer()
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot count orphaned documents in %q", name)
+		}
+		if count > 0 {
+			result[name] = count
+		}
+	}
+	return result, nil
+}
+
+// averageDocSize returns the average size, in bytes, of the documents
+// in the collection.
+func averageDocSize(coll *mgo.Collection) (float64, error) {
+	var result bson.M
+	if err := coll.Database.Run(bson.D{{"collStats", coll.Name}}, &result); err != nil {
+		return 0, errors.Trace(err)
+	}
+	switch size := result["avgObjSize"].(type) {
+	case int:
+		return float64(size), nil
+	case int64:
+		return float64(size), nil
+	case float64:
+		return size, nil
+	}
+	return 0, nil
+}
+
+// RecordModelStats records the given document statistics, keyed by
+// model UUID, replacing any previously recorded. Statistics previously
+// recorded for models not included are removed.
+func (st *State) RecordModelStats(stats map[string][]CollectionStats) error {
+	coll, closer := st.db().GetRawCollection(modelStatsC)
+	defer closer()
+
+	updated := st.clock().Now().UnixNano()
+	modelUUIDs := make([]string, 0, len(stats))
+	for modelUUID, collections := range stats {
+		doc := modelStatsDoc{
+			DocID:       modelUUID,
+			Collections: make(map[string]collectionStatsDoc),
+			Updated:     updated,
+		}
+		for _, c := range collections {
+			doc.Collections[c.Collection] = collectionStatsDoc{
+				Count: c.Count,
+				Size:  c.Size,
+			}
+		}
+		if _, err := coll.UpsertId(modelUUID, &doc); err != nil {
+			return errors.Annotatef(err, "cannot record stats for model %q", modelUUID)
+		}
+		modelUUIDs = append(modelUUIDs, modelUUID)
+	}
+	_, err := coll.RemoveAll(bson.D{{"_id", bson.D{{"$nin", modelUUIDs}}}})
+	return errors.Annotate(err, "cannot remove stale model stats")
+}
+
+// ModelStats returns the document statistics last recorded for the
+// model with the given UUID.
+func (st *State) ModelStats(modelUUID string) (ModelStats, error) {
+	coll, closer := st.db().GetRawCollection(modelStatsC)
+	defer closer()
+
+	var doc modelStatsDoc
+	if err := coll.FindId(modelUUID).One(&doc); err == mgo.ErrNotFound {
+		return ModelStats{}, errors.NotFoundf("stats for model %q", modelUUID)
+	} else if err != nil {
+		return ModelStats{}, errors.Annotatef(err, "cannot get stats for model %q", modelUUID)
+	}
+	stats := ModelStats{
+		ModelUUID: doc.DocID,
+		Updated:   time.Unix(0, doc.Updated),
+	}
+	for name, c := range doc.Collections {
+		stats.Collections = append(stats.Collections, CollectionStats{
+			Collection: name,
+			Count:      c.Count,
+			Size:       c.Size,
+		})
+	}
+	sort.Slice(stats.Collections, func(i, j int) bool {
+		return stats.Collections[i].Collection < stats.Collections[j].Collection
+	})
+	return stats, nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/state"
+)
+
+type ModelStatsSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&ModelStatsSuite{})
+
+func (s *ModelStatsSuite) findStats(stats []state.CollectionStats, collection string) (state.CollectionStats, bool) {
+	for _, c := range stats {
+		if c.Collection == collection {
+			return c, true
+		}
+	}
+	return state.CollectionStats{}, false
+}
+
+func (s *ModelStatsSuite) TestCollectModelStats(c *gc.C) {
+	s.Factory.MakeMachine(c, nil)
+	s.Factory.MakeMachine(c, nil)
+	otherState := s.Factory.MakeModel(c, nil)
+	defer otherState.Close()
+
+	stats, err := s.State.CollectModelStats()
+	c.Assert(err, jc.ErrorIsNil)
+
+	machines, ok := s.findStats(stats[s.State.ModelUUID()], "machines")
+	c.Assert(ok, jc.IsTrue)
+	c.Check(machines.Count, gc.Equals, 2)
+	c.Check(machines.Size > 0, jc.IsTrue)
+
+	_, ok = s.findStats(stats[otherState.ModelUUID()], "machines")
+	c.Check(ok, jc.IsFalse)
+	_, ok = s.findStats(stats[otherState.ModelUUID()], "settings")
+	c.Check(ok, jc.IsTrue)
+}
+
+func (s *ModelStatsSuite) TestRecordModelStats(c *gc.C) {
+	s.Clock.Advance(time.Minute)
+	now := s.Clock.Now()
+	err := s.State.RecordModelStats(map[string][]state.CollectionStats{
+		"model-1": {{Collection: "machines", Count: 3, Size: 1500}},
+		"model-2": {{Collection: "units", Count: 1, Size: 200}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	stats, err := s.State.ModelStats("model-1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(stats.Updated.Equal(now), jc.IsTrue)
+	stats.Updated = time.Time{}
+	c.Check(stats, jc.DeepEquals, state.ModelStats{
+		ModelUUID:   "model-1",
+		Collections: []state.CollectionStats{{Collection: "machines", Count: 3, Size: 1500}},
+	})
+
+	// Stats of models not recorded again are removed.
+	err = s.State.RecordModelStats(map[string][]state.CollectionStats{
+		"model-1": {{Collection: "machines", Count: 4, Size: 2000}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	stats, err = s.State.ModelStats("model-1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(stats.Collections, jc.DeepEquals, []state.CollectionStats{{Collection: "machines", Count: 4, Size: 2000}})
+	_, err = s.State.ModelStats("model-2")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ModelStatsSuite) TestOrphanedDocCounts(c *gc.C) {
+	s.Factory.MakeMachine(c, nil)
+	counts, err := s.State.OrphanedDocCounts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(counts, gc.HasLen, 0)
+
+	annotations := s.State.MongoSession().DB("juju").C("annotations")
+	err = annotations.Insert(bson.M{
+		"_id":        "deadbeef:m#0",
+		"model-uuid": "deadbeef",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	counts, err = s.State.OrphanedDocCounts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(counts, jc.DeepEquals, map[string]int{"annotations": 1})
+}
@@ -40,6 +40,7 @@ type MachineModelInfo struct {
 type ModelSummary struct {
 	Name           string
 	UUID           string
+	Alias          string
 	Type           ModelType
 	Owner          string
 	ControllerUUID string
@@ -111,6 +112,7 @@ func newProcessorFromModelDocs(st *State, modelDocs []modelDoc, user names.UserT
 		p.summaries[i] = ModelSummary{
 			Name:               doc.Name,
 			UUID:               doc.UUID,
+			Alias:              doc.Alias,
 			Type:               doc.Type,
 			Life:               doc.Life,
 			Owner:              doc.Owner,
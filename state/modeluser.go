@@ -272,6 +272,7 @@ func (st *State) modelQueryForUser(user names.UserTag, isSuperuser bool) (mongo.
 type ModelAccessInfo struct {
 	Name           string    `bson:"name"`
 	UUID           string    `bson:"_id"`
+	Alias          string    `bson:"alias"`
 	Owner          string    `bson:"owner"`
 	Type           ModelType `bson:"type"`
 	LastConnection time.Time
@@ -289,7 +290,7 @@ func (st *State) ModelBasicInfoForUser(user names.UserTag) ([]ModelAccessInfo, e
 		return nil, errors.Trace(err)
 	}
 	defer closer1()
-	modelQuery.Select(bson.M{"_id": 1, "name": 1, "owner": 1, "type": 1})
+	modelQuery.Select(bson.M{"_id": 1, "name": 1, "alias": 1, "owner": 1, "type": 1})
 	var accessInfo []ModelAccessInfo
 	if err := modelQuery.All(&accessInfo); err != nil {
 		return nil, errors.Trace(err)
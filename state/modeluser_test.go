@@ -452,11 +452,13 @@ func (s *ModelUserSuite) TestModelBasicInfoForUser(c *gc.C) {
 			Name:  model.Name(),
 			Type:  model.Type(),
 			UUID:  model.UUID(),
+			Alias: model.Alias(),
 			Owner: "test-admin",
 		}, {
 			Name:  model2.Name(),
 			Type:  model2.Type(),
 			UUID:  model2.UUID(),
+			Alias: model2.Alias(),
 			Owner: "test-admin",
 		},
 	})
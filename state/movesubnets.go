@@ -0,0 +1,247 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/core/network"
+)
+
+// MoveSubnetsViolation describes a machine or application whose network
+// requirements would no longer be satisfied if subnets were moved.
+type MoveSubnetsViolation struct {
+	// Tag identifies the machine or application.
+	Tag string
+
+	// Reason describes the requirement that would be broken.
+	Reason string
+}
+
+// MoveSubnetsViolationsError is returned by MoveSubnets when the move
+// would leave endpoint bindings or machine space requirements
+// unsatisfiable.
+type MoveSubnetsViolationsError struct {
+	Violations []MoveSubnetsViolation
+}
+
+func (e *MoveSubnetsViolationsError) Error() string {
+	reasons := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		reasons[i] = fmt.Sprintf("%s: %s", v.Tag, v.Reason)
+	}
+	return fmt.Sprintf("moving subnets would break network requirements: %s", strings.Join(reasons, "; "))
+}
+
+// IsMoveSubnetsViolationsError returns whether the cause of err is a
+// *MoveSubnetsViolationsError.
+func IsMoveSubnetsViolationsError(err error) bool {
+	_, ok := errors.Cause(err).(*MoveSubnetsViolationsError)
+	return ok
+}
+
+// MoveSubnets reassigns the subnets with the given CIDRs to the named
+// space. FAN overlays follow their underlay subnets. The move is refused
+// with a *MoveSubnetsViolationsError if any machine would lose a space
+// required by its constraints, by the constraints of an application with
+// units on it, or by the endpoint bindings of such an application.
+func (st *State) MoveSubnets(spaceName string, cidrs []string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "moving subnets to space %q", spaceName)
+
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		space, err := st.Space(spaceName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if space.Life() != Alive {
+			return nil, spaceNotAliveErr
+		}
+
+		subnets, err := st.AllSubnets()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		moved, err := subnetsToMove(subnets, cidrs)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+
+		violations, err := st.moveSubnetsViolations(subnets, moved, space.Id())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(violations) > 0 {
+			return nil, &MoveSubnetsViolationsError{Violations: violations}
+		}
+
+		ops := []txn.Op{{
+			C:      spacesC,
+			Id:     space.doc.DocId,
+			Assert: isAliveDoc,
+		}}
+		for _, subnet := range moved {
+			ops = append(ops, txn.Op{
+				C:      subnetsC,
+				Id:     subnet.doc.DocID,
+				Assert: bson.D{{"txn-revno", subnet.doc.TxnRevno}},
+				Update: bson.D{{"$set", bson.D{{"space-id", space.Id()}}}},
+			})
+		}
+		return ops, nil
+	}
+	return errors.Trace(st.db().Run(buildTxn))
+}
+
+// subnetsToMove returns the subnets with the given CIDRs, keyed by CIDR.
+func subnetsToMove(subnets []*Subnet, cidrs []string) (map[string]*Subnet, error) {
+	byCIDR := make(map[string]*Subnet, len(subnets))
+	for _, subnet := range subnets {
+		byCIDR[subnet.CIDR()] = subnet
+	}
+
+	moved := make(map[string]*Subnet, len(cidrs))
+	for _, cidr := range cidrs {
+		cidr = network.NormaliseCIDR(cidr)
+		subnet, ok := byCIDR[cidr]
+		if !ok {
+			return nil, errors.NotFoundf("subnet %q", cidr)
+		}
+		if subnet.FanLocalUnderlay() != "" {
+			return nil, errors.Errorf(
+				"cannot set space for FAN subnet %q - it is always inherited from underlay", cidr)
+		}
+		moved[cidr] = subnet
+	}
+	return moved, nil
+}
+
+// moveSubnetsViolations returns the requirements that would be broken
+// by moving the given subnets to the space with toSpaceID.
+func (st *State) moveSubnetsViolations(
+	subnets []*Subnet, moved map[string]*Subnet, toSpaceID string,
+) ([]MoveSubnetsViolation, error) {
+	spaces, err := st.AllSpaces()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	spaceNames := make(map[string]string, len(spaces))
+	for _, space := range spaces {
+		spaceNames[space.Id()] = space.Name()
+	}
+
+	// Work out which space each subnet belongs to before and after the move.
+	before := make(map[string]string, len(subnets))
+	after := make(map[string]string, len(subnets))
+	for _, subnet := range subnets {
+		spaceID := subnet.SpaceID()
+		if spaceID == "" {
+			spaceID = network.DefaultSpaceId
+		}
+		before[subnet.CIDR()] = spaceID
+		after[subnet.CIDR()] = spaceID
+		if _, ok := moved[subnet.CIDR()]; ok {
+			after[subnet.CIDR()] = toSpaceID
+		}
+		if _, ok := moved[subnet.FanLocalUnderlay()]; ok {
+			after[subnet.CIDR()] = toSpaceID
+		}
+	}
+
+	machines, err := st.AllMachines()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var violations []MoveSubnetsViolation
+	reported := set.NewStrings()
+	report := func(tag, reason string) {
+		if key := tag + "\x00" + reason; !reported.Contains(key) {
+			reported.Add(key)
+			violations = append(violations, MoveSubnetsViolation{Tag: tag, Reason: reason})
+		}
+	}
+
+	for _, m := range machines {
+		addresses, err := m.AllAddresses()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		hadSpaces := set.NewStrings()
+		hasSpaces := set.NewStrings()
+		for _, addr := range addresses {
+			if spaceID, ok := before[addr.SubnetCIDR()]; ok {
+				hadSpaces.Add(spaceNames[spaceID])
+				hasSpaces.Add(spaceNames[after[addr.SubnetCIDR()]])
+			}
+		}
+		lost := hadSpaces.Difference(hasSpaces)
+		lost.Remove(network.DefaultSpaceName)
+		if lost.IsEmpty() {
+			continue
+		}
+
+		cons, err := m.Constraints()
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
+		for _, name := range cons.IncludeSpaces() {
+			if lost.Contains(name) {
+				report(m.Tag().String(), fmt.Sprintf("constraints require space %q", name))
+			}
+		}
+
+		units, err := m.Units()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, u := range units {
+			app, err := u.Application()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			appTag := app.Tag().String()
+
+			cons, err := app.Constraints()
+			if err != nil && !errors.IsNotFound(err) {
+				return nil, errors.Trace(err)
+			}
+			for _, name := range cons.IncludeSpaces() {
+				if lost.Contains(name) {
+					report(appTag, fmt.Sprintf(
+						"constraints require space %q, which unit %s on machine %s would lose",
+						name, u.Name(), m.Id()))
+				}
+			}
+
+			bindings, err := app.EndpointBindings()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			for _, endpoint := range sortedEndpoints(bindings) {
+				if name := bindings[endpoint]; lost.Contains(name) {
+					report(appTag, fmt.Sprintf(
+						"endpoint %q is bound to space %q, which unit %s on machine %s would lose",
+						endpoint, name, u.Name(), m.Id()))
+				}
+			}
+		}
+	}
+	return violations, nil
+}
+
+func sortedEndpoints(bindings map[string]string) []string {
+	endpoints := make([]string, 0, len(bindings))
+	for endpoint := range bindings {
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Strings(endpoints)
+	return endpoints
+}
@@ -0,0 +1,116 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/state"
+)
+
+type MoveSubnetsSuite struct {
+	ConnSuite
+
+	db      *state.Space
+	other   *state.Space
+	machine *state.Machine
+}
+
+var _ = gc.Suite(&MoveSubnetsSuite{})
+
+func (s *MoveSubnetsSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+
+	for _, cidr := range []string{"10.0.0.0/24", "10.0.1.0/24"} {
+		_, err := s.State.AddSubnet(network.SubnetInfo{CIDR: cidr})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	var err error
+	s.db, err = s.State.AddSpace("db", "", []string{"10.0.0.0/24", "10.0.1.0/24"}, false)
+	c.Assert(err, jc.ErrorIsNil)
+	s.other, err = s.State.AddSpace("other", "", nil, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.machine, err = s.State.AddOneMachine(state.MachineTemplate{
+		Series:      "quantal",
+		Jobs:        []state.MachineJob{state.JobHostUnits},
+		Constraints: constraints.MustParse("spaces=db"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetLinkLayerDevices(state.LinkLayerDeviceArgs{
+		Name: "eth0",
+		Type: state.EthernetDevice,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetDevicesAddresses(state.LinkLayerDeviceAddress{
+		DeviceName:   "eth0",
+		ConfigMethod: state.StaticAddress,
+		CIDRAddress:  "10.0.0.5/24",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	app := s.AddTestingApplicationWithBindings(c, "mysql", s.AddTestingCharm(c, "mysql"), map[string]string{
+		"server": "db",
+	})
+	unit, err := app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(s.machine)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *MoveSubnetsSuite) assertSubnetSpace(c *gc.C, cidr string, space *state.Space) {
+	subnet, err := s.State.Subnet(cidr)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnet.SpaceID(), gc.Equals, space.Id())
+}
+
+func (s *MoveSubnetsSuite) TestMoveUnusedSubnet(c *gc.C) {
+	err := s.State.MoveSubnets("other", []string{"10.0.1.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertSubnetSpace(c, "10.0.0.0/24", s.db)
+	s.assertSubnetSpace(c, "10.0.1.0/24", s.other)
+}
+
+func (s *MoveSubnetsSuite) TestMoveSubnetReportsViolations(c *gc.C) {
+	err := s.State.MoveSubnets("other", []string{"10.0.0.0/24"})
+	c.Assert(err, jc.Satisfies, state.IsMoveSubnetsViolationsError)
+
+	violations := err.(*state.MoveSubnetsViolationsError).Violations
+	c.Assert(violations, jc.DeepEquals, []state.MoveSubnetsViolation{{
+		Tag:    s.machine.Tag().String(),
+		Reason: `constraints require space "db"`,
+	}, {
+		Tag:    "application-mysql",
+		Reason: `endpoint "server" is bound to space "db", which unit mysql/0 on machine ` + s.machine.Id() + ` would lose`,
+	}})
+
+	s.assertSubnetSpace(c, "10.0.0.0/24", s.db)
+}
+
+func (s *MoveSubnetsSuite) TestMoveAllowedWhenSpaceStillReachable(c *gc.C) {
+	err := s.machine.SetDevicesAddresses(state.LinkLayerDeviceAddress{
+		DeviceName:   "eth0",
+		ConfigMethod: state.StaticAddress,
+		CIDRAddress:  "10.0.1.5/24",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.MoveSubnets("other", []string{"10.0.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertSubnetSpace(c, "10.0.0.0/24", s.other)
+}
+
+func (s *MoveSubnetsSuite) TestMoveUnknownSubnet(c *gc.C) {
+	err := s.State.MoveSubnets("other", []string{"192.168.0.0/24"})
+	c.Assert(err, gc.ErrorMatches, `moving subnets to space "other": subnet "192.168.0.0/24" not found`)
+}
+
+func (s *MoveSubnetsSuite) TestMoveToUnknownSpace(c *gc.C) {
+	err := s.State.MoveSubnets("missing", []string{"10.0.1.0/24"})
+	c.Assert(err, gc.ErrorMatches, `moving subnets to space "missing": space "missing" not found`)
+}
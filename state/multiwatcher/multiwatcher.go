@@ -358,6 +358,10 @@ type RelationInfo struct {
 	Key       string     `json:"key"`
 	Id        int        `json:"id"`
 	Endpoints []Endpoint `json:"endpoints"`
+	// Status holds the relation's status. Its data explains why the
+	// relation is broken, suspended or in error; see
+	// core/relation.StatusReason.
+	Status StatusInfo `json:"status"`
 }
 
 // CharmRelation is a mirror struct for charm.Relation.
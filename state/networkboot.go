@@ -0,0 +1,186 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"encoding/hex"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// networkBootTokenBytes is the number of random bytes in a network boot
+// token.
+const networkBootTokenBytes = 24
+
+// NetworkBootUserData holds the user-data published for a machine that is
+// provisioned by network boot.
+type NetworkBootUserData struct {
+	// MachineId is the id of the machine the user-data is for.
+	MachineId string
+
+	// UserData is the rendered cloud-init user-data for the machine.
+	UserData []byte
+
+	// Expires is the time after which the user-data can no longer be
+	// fetched.
+	Expires time.Time
+}
+
+// networkBootDoc holds the network boot user-data published for a
+// machine. There is at most one for each machine, which is keyed by the
+// machine's global key. Only a hash of the token which grants access to
+// the user-data is stored.
+type networkBootDoc struct {
+	DocID     string    `bson:"_id"`
+	ModelUUID string    `bson:"model-uuid"`
+	MachineId string    `bson:"machineid"`
+	TokenHash string    `bson:"tokenhash"`
+	UserData  []byte    `bson:"userdata"`
+	Expires   time.Time `bson:"expires"`
+}
+
+// PublishNetworkBootUserData records the rendered user-data for the
+// machine, so that it can be fetched once, by the holder of the returned
+// token, before the given expiry time. Any user-data previously published
+// for the machine, and not yet fetched, is replaced.
+func (m *Machine) PublishNetworkBootUserData(userData []byte, expires time.Time) (string, error) {
+	tokenBytes, err := utils.RandomBytes(networkBootTokenBytes)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	token := hex.EncodeToString(tokenBytes)
+	doc := networkBootDoc{
+		DocID:     m.globalKey(),
+		MachineId: m.Id(),
+		TokenHash: utils.AgentPasswordHash(token),
+		UserData:  userData,
+		Expires:   expires.UTC().Round(time.Second),
+	}
+
+	coll, closer := m.st.db().GetCollection(networkBootC)
+	defer closer()
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := m.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if m.doc.Life != Alive {
+			return nil, errors.Errorf("machine %s is not alive", m.Id())
+		}
+		ops := []txn.Op{{
+			C:      machinesC,
+			Id:     m.doc.DocID,
+			Assert: isAliveDoc,
+		}}
+		count, err := coll.FindId(doc.DocID).Count()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if count == 0 {
+			return append(ops, txn.Op{
+				C:      networkBootC,
+				Id:     doc.DocID,
+				Assert: txn.DocMissing,
+				Insert: &doc,
+			}), nil
+		}
+		return append(ops, txn.Op{
+			C:      networkBootC,
+			Id:     doc.DocID,
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{
+				{"tokenhash", doc.TokenHash},
+				{"userdata", doc.UserData},
+				{"expires", doc.Expires},
+			}}},
+		}), nil
+	}
+	if err := m.st.db().Run(buildTxn); err != nil {
+		return "", errors.Annotatef(err, "cannot publish network boot user-data for machine %s", m.Id())
+	}
+	return token, nil
+}
+
+// NetworkBootUserData returns the network boot user-data that the token
+// grants access to, without consuming it. A not found error is returned
+// if there is none, or if it has expired.
+func (st *State) NetworkBootUserData(token string) (NetworkBootUserData, error) {
+	doc, err := st.networkBootDoc(token)
+	if err != nil {
+		return NetworkBootUserData{}, errors.Trace(err)
+	}
+	return doc.userData(), nil
+}
+
+// ConsumeNetworkBootUserData returns the network boot user-data that the
+// token grants access to, and removes it so that it cannot be fetched
+// again. A not found error is returned if there is none, if it has
+// expired, or if it has already been consumed.
+func (st *State) ConsumeNetworkBootUserData(token string) (NetworkBootUserData, error) {
+	var doc *networkBootDoc
+	buildTxn := func(int) ([]txn.Op, error) {
+		var err error
+		doc, err = st.networkBootDoc(token)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return []txn.Op{{
+			C:      networkBootC,
+			Id:     doc.DocID,
+			Assert: bson.D{{"tokenhash", doc.TokenHash}},
+			Remove: true,
+		}}, nil
+	}
+	if err := st.db().Run(buildTxn); err != nil {
+		if errors.IsNotFound(err) {
+			return NetworkBootUserData{}, errors.Trace(err)
+		}
+		return NetworkBootUserData{}, errors.Annotate(err, "cannot consume network boot user-data")
+	}
+	return doc.userData(), nil
+}
+
+// networkBootDoc returns the unexpired network boot document that the
+// token grants access to.
+func (st *State) networkBootDoc(token string) (*networkBootDoc, error) {
+	coll, closer := st.db().GetCollection(networkBootC)
+	defer closer()
+
+	var doc networkBootDoc
+	err := coll.Find(bson.D{{"tokenhash", utils.AgentPasswordHash(token)}}).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("network boot user-data")
+	} else if err != nil {
+		return nil, errors.Annotate(err, "cannot get network boot user-data")
+	}
+	if !st.clock().Now().Before(doc.Expires) {
+		return nil, errors.NotFoundf("network boot user-data")
+	}
+	return &doc, nil
+}
+
+func (doc *networkBootDoc) userData() NetworkBootUserData {
+	return NetworkBootUserData{
+		MachineId: doc.MachineId,
+		UserData:  doc.UserData,
+		Expires:   doc.Expires,
+	}
+}
+
+// removeNetworkBootOp returns the operation needed to remove the network
+// boot user-data, if any, published for the machine with the given global
+// key.
+func removeNetworkBootOp(globalKey string) txn.Op {
+	return txn.Op{
+		C:      networkBootC,
+		Id:     globalKey,
+		Remove: true,
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type NetworkBootSuite struct {
+	ConnSuite
+	machine *state.Machine
+}
+
+var _ = gc.Suite(&NetworkBootSuite{})
+
+func (s *NetworkBootSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	var err error
+	s.machine, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *NetworkBootSuite) expires() time.Time {
+	return s.Clock.Now().Add(time.Hour).UTC().Round(time.Second)
+}
+
+func (s *NetworkBootSuite) TestPublishAndConsume(c *gc.C) {
+	expires := s.expires()
+	token, err := s.machine.PublishNetworkBootUserData([]byte("#cloud-config\n"), expires)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(token, gc.HasLen, 48)
+
+	expected := state.NetworkBootUserData{
+		MachineId: s.machine.Id(),
+		UserData:  []byte("#cloud-config\n"),
+		Expires:   expires,
+	}
+	data, err := s.State.NetworkBootUserData(token)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, jc.DeepEquals, expected)
+
+	data, err = s.State.ConsumeNetworkBootUserData(token)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, jc.DeepEquals, expected)
+
+	_, err = s.State.ConsumeNetworkBootUserData(token)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.State.NetworkBootUserData(token)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *NetworkBootSuite) TestPublishReplaces(c *gc.C) {
+	token1, err := s.machine.PublishNetworkBootUserData([]byte("one"), s.expires())
+	c.Assert(err, jc.ErrorIsNil)
+	token2, err := s.machine.PublishNetworkBootUserData([]byte("two"), s.expires())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(token2, gc.Not(gc.Equals), token1)
+
+	_, err = s.State.ConsumeNetworkBootUserData(token1)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	data, err := s.State.ConsumeNetworkBootUserData(token2)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data.UserData), gc.Equals, "two")
+}
+
+func (s *NetworkBootSuite) TestExpired(c *gc.C) {
+	token, err := s.machine.PublishNetworkBootUserData([]byte("#cloud-config\n"), s.expires())
+	c.Assert(err, jc.ErrorIsNil)
+	s.Clock.Advance(time.Hour + time.Second)
+
+	_, err = s.State.NetworkBootUserData(token)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.State.ConsumeNetworkBootUserData(token)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *NetworkBootSuite) TestUnknownToken(c *gc.C) {
+	_, err := s.State.ConsumeNetworkBootUserData("deadbeef")
+	c.Assert(err, gc.ErrorMatches, "network boot user-data not found")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *NetworkBootSuite) TestPublishMachineNotAlive(c *gc.C) {
+	err := s.machine.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.machine.PublishNetworkBootUserData([]byte("#cloud-config\n"), s.expires())
+	c.Assert(err, gc.ErrorMatches, `cannot publish network boot user-data for machine 0: machine 0 is not alive`)
+}
+
+func (s *NetworkBootSuite) TestRemovedWithMachine(c *gc.C) {
+	token, err := s.machine.PublishNetworkBootUserData([]byte("#cloud-config\n"), s.expires())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.NetworkBootUserData(token)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
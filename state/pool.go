@@ -415,6 +415,21 @@ func (p *StatePool) Close() error {
 	return errors.Annotate(lastErr, "at least one error closing a state")
 }
 
+// TxnWatcherDeliveryLag returns how far behind the transactions it
+// delivered the pool's txn watcher was when it last synced. It blocks
+// until the txn watcher is running, or abort is closed.
+func (p *StatePool) TxnWatcherDeliveryLag(abort <-chan struct{}) (time.Duration, error) {
+	w, err := p.watcherRunner.Worker(txnLogWorker, abort)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	txnWatcher, ok := w.(*watcher.TxnWatcher)
+	if !ok {
+		return 0, errors.Errorf("unexpected txn watcher type %T", w)
+	}
+	return txnWatcher.DeliveryLag(), nil
+}
+
 // IntrospectionReport produces the output for the introspection worker
 // in order to look inside the state pool.
 func (p *StatePool) IntrospectionReport() string {
@@ -6,12 +6,12 @@ package state
 import (
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/juju/errors"
 	statetxn "github.com/juju/txn"
 	"gopkg.in/juju/names.v3"
-	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 	"gopkg.in/mgo.v2/txn"
 
@@ -19,8 +19,9 @@ import (
 )
 
 // A regular expression for parsing ports document id into corresponding machine
-// and subnet ids.
-var portsIDRe = regexp.MustCompile(fmt.Sprintf("m#(?P<machine>%s)#(?P<subnet>.*)$", names.MachineSnippet))
+// and subnet ids, and unit name. The unit name is absent from the ids of
+// documents written before ports were held per unit.
+var portsIDRe = regexp.MustCompile(fmt.Sprintf("m#(?P<machine>%s)#(?P<subnet>[^#]*)(?:#u#(?P<unit>[^#]+))?$", names.MachineSnippet))
 
 type portIDPart int
 
@@ -28,6 +29,7 @@ const (
 	_ portIDPart = iota
 	machineIDPart
 	subnetIDPart
+	unitNamePart
 )
 
 // PortRange represents a single range of ports opened
@@ -143,22 +145,41 @@ func (p PortRange) String() string {
 	return fmt.Sprintf("%d-%d/%s (%q)", p.FromPort, p.ToPort, proto, p.UnitName)
 }
 
-// portsDoc represents the state of ports opened on machines for networks
+// portsDoc represents the state of ports opened by a unit on a machine
+// for a network. Each unit's ports are held in a separate document, so
+// that units sharing a machine don't contend for a single document.
 type portsDoc struct {
 	DocID     string      `bson:"_id"`
 	ModelUUID string      `bson:"model-uuid"`
 	MachineID string      `bson:"machine-id"`
 	SubnetID  string      `bson:"subnet-id"`
+	UnitName  string      `bson:"unit-name,omitempty"`
 	Ports     []PortRange `bson:"ports"`
 	TxnRevno  int64       `bson:"txn-revno"`
 }
 
-// Ports represents the state of ports on a machine.
+// Ports represents the state of ports opened on a machine for a
+// network, by all the units on the machine.
 type Ports struct {
-	st  *State
+	st *State
+	// doc holds the machine and subnet, and the ports opened by all
+	// the units.
 	doc portsDoc
-	// areNew is true for documents not in state yet.
-	areNew bool
+	// unitDocs holds the documents recording the ports opened by each
+	// unit, keyed by unit name.
+	unitDocs map[string]portsDoc
+}
+
+func newPorts(st *State, machineID, subnetID string) *Ports {
+	return &Ports{
+		st: st,
+		doc: portsDoc{
+			ModelUUID: st.ModelUUID(),
+			MachineID: machineID,
+			SubnetID:  subnetID,
+		},
+		unitDocs: make(map[string]portsDoc),
+	}
 }
 
 // String returns p as a user-readable string.
@@ -167,20 +188,75 @@ func (p *Ports) String() string {
 }
 
 // portsGlobalKey returns the global database key for the opened ports
-// document for the given machine and subnet.
+// document for the given machine and subnet. Such documents held the
+// ports of every unit on the machine before ports were held per unit.
 func portsGlobalKey(machineID, subnetID string) string {
 	return fmt.Sprintf("m#%s#%s", machineID, subnetID)
 }
 
+// unitPortsGlobalKey returns the global database key for the opened
+// ports document for the given unit on the given machine and subnet.
+func unitPortsGlobalKey(machineID, subnetID, unitName string) string {
+	return fmt.Sprintf("%s#u#%s", portsGlobalKey(machineID, subnetID), unitName)
+}
+
 // extractPortsIDParts parses the given ports global key and extracts
 // its parts.
 func extractPortsIDParts(globalKey string) ([]string, error) {
-	if parts := portsIDRe.FindStringSubmatch(globalKey); len(parts) == 3 {
+	if parts := portsIDRe.FindStringSubmatch(globalKey); len(parts) == 4 {
 		return parts, nil
 	}
 	return nil, errors.NotValidf("ports document key %q", globalKey)
 }
 
+// newUnitDoc returns a new document for the ports opened by the given
+// unit on the machine and subnet.
+func (p *Ports) newUnitDoc(unitName string) portsDoc {
+	key := unitPortsGlobalKey(p.doc.MachineID, p.doc.SubnetID, unitName)
+	return portsDoc{
+		DocID:     p.st.docID(key),
+		ModelUUID: p.st.ModelUUID(),
+		MachineID: p.doc.MachineID,
+		SubnetID:  p.doc.SubnetID,
+		UnitName:  unitName,
+	}
+}
+
+// setUnitDocs sets the documents recording the ports opened by each
+// unit, and the ports opened by all of them.
+func (p *Ports) setUnitDocs(unitDocs map[string]portsDoc) {
+	unitNames := make([]string, 0, len(unitDocs))
+	for unitName := range unitDocs {
+		unitNames = append(unitNames, unitName)
+	}
+	sort.Strings(unitNames)
+	p.unitDocs = unitDocs
+	p.doc.Ports = nil
+	for _, unitName := range unitNames {
+		p.doc.Ports = append(p.doc.Ports, unitDocs[unitName].Ports...)
+	}
+}
+
+// setUnitPorts records the ports opened by the given unit, after they
+// have been changed in state.
+func (p *Ports) setUnitPorts(unitName string, ports []PortRange) {
+	unitDocs := make(map[string]portsDoc, len(p.unitDocs)+1)
+	for name, doc := range p.unitDocs {
+		unitDocs[name] = doc
+	}
+	if len(ports) == 0 {
+		delete(unitDocs, unitName)
+	} else {
+		doc, ok := unitDocs[unitName]
+		if !ok {
+			doc = p.newUnitDoc(unitName)
+		}
+		doc.Ports = ports
+		unitDocs[unitName] = doc
+	}
+	p.setUnitDocs(unitDocs)
+}
+
 // SubnetID returns the subnet ID associated with this ports document.
 func (p *Ports) SubnetID() string {
 	return p.doc.SubnetID
@@ -189,14 +265,23 @@ func (p *Ports) SubnetID() string {
 // OpenPorts adds the specified port range to the list of ports
 // maintained by this document.
 func (p *Ports) OpenPorts(portRange PortRange) (err error) {
+	return p.openPorts(portRange, "")
+}
+
+// openPorts adds the specified port range to the list of ports
+// maintained by this document, and records the change in the ports
+// history as made in the given hook.
+func (p *Ports) openPorts(portRange PortRange, hook string) (err error) {
 	defer errors.DeferredAnnotatef(&err, "cannot open ports %s", portRange)
 
 	if err = portRange.Validate(); err != nil {
 		return errors.Trace(err)
 	}
-	ports := Ports{st: p.st, doc: p.doc, areNew: p.areNew}
+	ports := Ports{st: p.st, doc: p.doc, unitDocs: p.unitDocs}
+	changed := false
 
 	buildTxn := func(attempt int) ([]txn.Op, error) {
+		changed = false
 		if attempt > 0 {
 			if err := checkModelActive(p.st); err != nil {
 				return nil, errors.Trace(err)
@@ -204,21 +289,15 @@ func (p *Ports) OpenPorts(portRange PortRange) (err error) {
 			if err := p.verifySubnetAliveWhenSet(); err != nil {
 				return nil, errors.Trace(err)
 			}
-			if err = ports.Refresh(); errors.IsNotFound(err) {
-				// No longer exists, we'll create it.
-				if !ports.areNew {
-					ports.areNew = true
-				}
-			} else if err != nil {
+			// If no unit has ports open any more, the unit's
+			// document will be created.
+			if err := ports.Refresh(); err != nil && !errors.IsNotFound(err) {
 				return nil, errors.Trace(err)
-			} else if ports.areNew {
-				// Already created, we'll update it.
-				ports.areNew = false
 			}
 		}
 
-		// Check for conflicts with existing ports.
-		for _, existingPorts := range p.doc.Ports {
+		// Check for conflicts with ports opened by any unit.
+		for _, existingPorts := range ports.doc.Ports {
 			if err := existingPorts.CheckConflicts(portRange); err != nil {
 				return nil, errors.Trace(err)
 			} else if existingPorts == portRange {
@@ -233,27 +312,99 @@ func (p *Ports) OpenPorts(portRange PortRange) (err error) {
 		ops := []txn.Op{
 			assertModelActiveOp(p.st.ModelUUID()),
 		}
-		if ports.areNew {
-			// Create a new document.
-			assert := txn.DocMissing
-			ops = append(ops, addPortsDocOps(p.st, &ports.doc, assert, portRange)...)
+		// The ports of the other units were checked for conflicts, so
+		// make sure they haven't changed since.
+		otherUnitOps, err := ports.assertOtherUnitDocsOps(portRange.UnitName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, otherUnitOps...)
+		if unitDoc, ok := ports.unitDocs[portRange.UnitName]; !ok {
+			// Create a new document for the unit.
+			unitDoc = ports.newUnitDoc(portRange.UnitName)
+			ops = append(ops, addPortsDocOps(p.st, &unitDoc, txn.DocMissing, portRange)...)
 		} else {
-			// Update an existing document.
-			assert := bson.D{{"txn-revno", ports.doc.TxnRevno}}
-			ops = append(ops, updatePortsDocOps(p.st, ports.doc, assert, portRange)...)
+			// Update the unit's existing document.
+			assert := bson.D{{"txn-revno", unitDoc.TxnRevno}}
+			ops = append(ops, updatePortsDocOps(p.st, unitDoc, assert, portRange)...)
 		}
+		changed = true
 		return ops, nil
 	}
 	// Run the transaction using the state transaction runner.
 	if err = p.st.db().Run(buildTxn); err != nil {
 		return errors.Trace(err)
 	}
-	// Mark object as created.
-	p.areNew = false
-	p.doc.Ports = append(p.doc.Ports, portRange)
+	p.doc, p.unitDocs = ports.doc, ports.unitDocs
+	if changed {
+		// Any of the unit's ports still held in a legacy document
+		// stay there, so only the unit's own document is extended.
+		unitPorts := append([]PortRange(nil), p.unitDocs[portRange.UnitName].Ports...)
+		p.setUnitPorts(portRange.UnitName, append(unitPorts, portRange))
+		p.recordChange(portRange, true, hook)
+	}
 	return nil
 }
 
+// assertOtherUnitDocsOps returns the ops asserting that the ports opened
+// on the machine and subnet by units other than the given one have not
+// changed since they were read. The documents of the other units on the
+// machine that have no ports open are asserted to still be missing, so
+// that a conflicting port can't be opened by another unit concurrently.
+func (p *Ports) assertOtherUnitDocsOps(unitName string) ([]txn.Op, error) {
+	machine, err := p.st.Machine(p.doc.MachineID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	units, err := machine.Units()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	otherNames := make(map[string]bool)
+	for _, unit := range units {
+		otherNames[unit.Name()] = true
+	}
+	// Documents may be held for units no longer on the machine, and
+	// a legacy document is held under the empty unit name.
+	for name := range p.unitDocs {
+		otherNames[name] = true
+	}
+	delete(otherNames, unitName)
+
+	otherUnitNames := make([]string, 0, len(otherNames))
+	for name := range otherNames {
+		otherUnitNames = append(otherUnitNames, name)
+	}
+	sort.Strings(otherUnitNames)
+	var ops []txn.Op
+	for _, name := range otherUnitNames {
+		if doc, ok := p.unitDocs[name]; ok {
+			ops = append(ops, txn.Op{
+				C:      openedPortsC,
+				Id:     doc.DocID,
+				Assert: bson.D{{"txn-revno", doc.TxnRevno}},
+			})
+			continue
+		}
+		key := unitPortsGlobalKey(p.doc.MachineID, p.doc.SubnetID, name)
+		ops = append(ops, txn.Op{
+			C:      openedPortsC,
+			Id:     p.st.docID(key),
+			Assert: txn.DocMissing,
+		})
+	}
+	return ops, nil
+}
+
+// recordChange records a change to the ports maintained by this
+// document in the ports history. The change has already been made, so
+// failing to record it is logged rather than reported.
+func (p *Ports) recordChange(portRange PortRange, opened bool, hook string) {
+	if err := recordPortChange(p.st, p.doc, portRange, opened, hook); err != nil {
+		logger.Errorf("%s: %v", p, err)
+	}
+}
+
 func (p *Ports) verifySubnetAliveWhenSet() error {
 	if p.doc.SubnetID == "" {
 		return nil
@@ -268,20 +419,46 @@ func (p *Ports) verifySubnetAliveWhenSet() error {
 	return nil
 }
 
+// verifySubnetNotDeadWhenSet is like verifySubnetAliveWhenSet, but also
+// accepts a Dying subnet, so ports can still be closed while it drains.
+func (p *Ports) verifySubnetNotDeadWhenSet() error {
+	if p.doc.SubnetID == "" {
+		return nil
+	}
+
+	subnet, err := p.st.SubnetByID(p.doc.SubnetID)
+	if err != nil {
+		return errors.Trace(err)
+	} else if subnet.Life() == Dead {
+		return errors.Errorf("subnet %q is dead", subnet.CIDR())
+	}
+	return nil
+}
+
 // ClosePorts removes the specified port range from the list of ports
 // maintained by this document.
 func (p *Ports) ClosePorts(portRange PortRange) (err error) {
+	return p.closePorts(portRange, "")
+}
+
+// closePorts removes the specified port range from the list of ports
+// maintained by this document, and records the change in the ports
+// history as made in the given hook.
+func (p *Ports) closePorts(portRange PortRange, hook string) (err error) {
 	defer errors.DeferredAnnotatef(&err, "cannot close ports %s", portRange)
 
 	if err = portRange.Validate(); err != nil {
 		return errors.Trace(err)
 	}
 	var newPorts []PortRange
-	ports := Ports{st: p.st, doc: p.doc, areNew: p.areNew}
+	var docKey string
+	ports := Ports{st: p.st, doc: p.doc, unitDocs: p.unitDocs}
+	changed := false
 
 	buildTxn := func(attempt int) ([]txn.Op, error) {
+		changed = false
 		if attempt > 0 {
-			if err := p.verifySubnetAliveWhenSet(); err != nil {
+			if err := p.verifySubnetNotDeadWhenSet(); err != nil {
 				return nil, errors.Trace(err)
 			}
 			if err = ports.Refresh(); errors.IsNotFound(err) {
@@ -291,38 +468,79 @@ func (p *Ports) ClosePorts(portRange PortRange) (err error) {
 				return nil, errors.Trace(err)
 			}
 		}
-		newPorts = newPorts[0:0]
 
+		// Only the unit's own ports need to be considered.
+		docKey = portRange.UnitName
+		unitDoc, ok := ports.unitDocs[docKey]
 		found := false
-		for _, existingPortsDef := range ports.doc.Ports {
-			if existingPortsDef == portRange {
-				found = true
-				continue
-			}
-			err = existingPortsDef.CheckConflicts(portRange)
-			if existingPortsDef.UnitName == portRange.UnitName && err != nil {
+		if ok {
+			newPorts, found, err = closePortsInDoc(unitDoc, portRange)
+			if err != nil {
 				return nil, errors.Trace(err)
 			}
-			newPorts = append(newPorts, existingPortsDef)
 		}
 		if !found {
-			return nil, statetxn.ErrNoOperations
+			// Ports opened before they were held per unit stay in
+			// the machine's legacy document until it is split by
+			// the upgrade step, so look for the unit's ports there.
+			docKey = ""
+			unitDoc, ok = ports.unitDocs[docKey]
+			if !ok {
+				return nil, statetxn.ErrNoOperations
+			}
+			newPorts, found, err = closePortsInDoc(unitDoc, portRange)
+			if err != nil {
+				return nil, errors.Trace(err)
+			} else if !found {
+				return nil, statetxn.ErrNoOperations
+			}
 		}
+		changed = true
+		assert := bson.D{{"txn-revno", unitDoc.TxnRevno}}
 		if len(newPorts) == 0 {
-			// All ports closed, so remove the ports doc instead.
-			return p.removeOps(), nil
-		} else {
-			assert := bson.D{{"txn-revno", ports.doc.TxnRevno}}
-			return setPortsDocOps(p.st, ports.doc, assert, newPorts...), nil
+			// All the document's ports closed, so remove it instead,
+			// letting a Dying subnet know it lost a reference.
+			ops, err := dyingSubnetCleanupOps(p.st, unitDoc.SubnetID)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			return append(removePortsDocOps(unitDoc, assert), ops...), nil
 		}
+		return setPortsDocOps(p.st, unitDoc, assert, newPorts...), nil
 	}
 	if err = p.st.db().Run(buildTxn); err != nil {
 		return errors.Trace(err)
 	}
-	p.doc.Ports = newPorts
+	p.doc, p.unitDocs = ports.doc, ports.unitDocs
+	if changed {
+		p.setUnitPorts(docKey, newPorts)
+		p.recordChange(portRange, false, hook)
+	}
 	return nil
 }
 
+// closePortsInDoc returns the ports left in the given document once the
+// port range is closed, and whether the document held the port range.
+// Only the ports of the port range's unit are checked for conflicts, as
+// a legacy document holds the ports of other units too.
+func closePortsInDoc(doc portsDoc, portRange PortRange) ([]PortRange, bool, error) {
+	var newPorts []PortRange
+	found := false
+	for _, existingPortsDef := range doc.Ports {
+		if existingPortsDef == portRange {
+			found = true
+			continue
+		}
+		if existingPortsDef.UnitName == portRange.UnitName {
+			if err := existingPortsDef.CheckConflicts(portRange); err != nil {
+				return nil, false, errors.Trace(err)
+			}
+		}
+		newPorts = append(newPorts, existingPortsDef)
+	}
+	return newPorts, found, nil
+}
+
 // PortsForUnit returns the ports associated with specified unitName that are
 // maintained on this document (i.e. are open on this unit's assigned machine).
 func (p *Ports) PortsForUnit(unitName string) []PortRange {
@@ -335,17 +553,29 @@ func (p *Ports) PortsForUnit(unitName string) []PortRange {
 	return ports
 }
 
-// Refresh refreshes the port document from state.
+// Refresh refreshes the ports opened by each unit from state. A legacy
+// document holding the ports of every unit on the machine, which has not
+// yet been split by the upgrade step, is held under the empty unit name.
 func (p *Ports) Refresh() error {
 	openedPorts, closer := p.st.db().GetCollection(openedPortsC)
 	defer closer()
 
-	err := openedPorts.FindId(p.doc.DocID).One(&p.doc)
-	if err == mgo.ErrNotFound {
-		return errors.NotFoundf(p.String())
-	} else if err != nil {
+	var docs []portsDoc
+	err := openedPorts.Find(bson.D{
+		{"machine-id", p.doc.MachineID},
+		{"subnet-id", p.doc.SubnetID},
+	}).All(&docs)
+	if err != nil {
 		return errors.Annotatef(err, "cannot refresh %s", p)
 	}
+	unitDocs := make(map[string]portsDoc, len(docs))
+	for _, doc := range docs {
+		unitDocs[doc.UnitName] = doc
+	}
+	p.setUnitDocs(unitDocs)
+	if len(docs) == 0 {
+		return errors.NotFoundf(p.String())
+	}
 	return nil
 }
 
@@ -364,17 +594,17 @@ func (p *Ports) AllPortRanges() map[network.PortRange]string {
 	return result
 }
 
-// Remove removes the ports document from state.
+// Remove removes the ports documents of all the units from state.
 func (p *Ports) Remove() error {
 	ports := &Ports{st: p.st, doc: p.doc}
-	buildTxn := func(attempt int) ([]txn.Op, error) {
-		if attempt > 0 {
-			err := ports.Refresh()
-			if errors.IsNotFound(err) {
-				return nil, statetxn.ErrNoOperations
-			} else if err != nil {
-				return nil, errors.Trace(err)
-			}
+	buildTxn := func(int) ([]txn.Op, error) {
+		// The documents are always refreshed, as units may have
+		// opened ports since p was last refreshed.
+		err := ports.Refresh()
+		if errors.IsNotFound(err) {
+			return nil, statetxn.ErrNoOperations
+		} else if err != nil {
+			return nil, errors.Trace(err)
 		}
 		return ports.removeOps(), nil
 	}
@@ -397,26 +627,36 @@ func (m *Machine) AllPorts() ([]*Ports, error) {
 	defer closer()
 
 	docs := []portsDoc{}
-	err := openedPorts.Find(bson.D{{"machine-id", m.Id()}}).All(&docs)
+	err := openedPorts.Find(bson.D{{"machine-id", m.Id()}}).Sort("_id").All(&docs)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	results := make([]*Ports, len(docs))
-	for i, doc := range docs {
-		results[i] = &Ports{st: m.st, doc: doc}
+	var results []*Ports
+	bySubnet := make(map[string]*Ports)
+	for _, doc := range docs {
+		ports, ok := bySubnet[doc.SubnetID]
+		if !ok {
+			ports = newPorts(m.st, m.Id(), doc.SubnetID)
+			bySubnet[doc.SubnetID] = ports
+			results = append(results, ports)
+		}
+		ports.unitDocs[doc.UnitName] = doc
+	}
+	for _, ports := range results {
+		ports.setUnitDocs(ports.unitDocs)
 	}
 	return results, nil
 }
 
 // addPortsDocOps returns the ops for adding a number of port ranges
-// to a new ports document. portsAssert allows specifying an assert
+// to a new unit ports document. portsAssert allows specifying an assert
 // statement for on the openedPorts collection op.
 var addPortsDocOps = addPortsDocOpsFunc
 
 func addPortsDocOpsFunc(st *State, pDoc *portsDoc, portsAssert interface{}, ports ...PortRange) []txn.Op {
 	pDoc.Ports = ports
 
-	ops := assertMachineNotDeadAndSubnetNotDeadWhenSetOps(st, pDoc)
+	ops := assertMachineNotDeadAndSubnetWhenSetOps(st, pDoc, isAliveDoc)
 	return append(ops, txn.Op{
 		C:      openedPortsC,
 		Id:     pDoc.DocID,
@@ -425,7 +665,11 @@ func addPortsDocOpsFunc(st *State, pDoc *portsDoc, portsAssert interface{}, port
 	})
 }
 
-func assertMachineNotDeadAndSubnetNotDeadWhenSetOps(st *State, pDoc *portsDoc) []txn.Op {
+// assertMachineNotDeadAndSubnetWhenSetOps returns the ops asserting that the
+// ports document's machine is not Dead and, when set, that its subnet
+// matches subnetAssert. Opening ports requires an Alive subnet, while
+// closing them is allowed until the subnet is Dead.
+func assertMachineNotDeadAndSubnetWhenSetOps(st *State, pDoc *portsDoc, subnetAssert bson.D) []txn.Op {
 	ops := []txn.Op{{
 		C:      machinesC,
 		Id:     st.docID(pDoc.MachineID),
@@ -436,19 +680,19 @@ func assertMachineNotDeadAndSubnetNotDeadWhenSetOps(st *State, pDoc *portsDoc) [
 		ops = append(ops, txn.Op{
 			C:      subnetsC,
 			Id:     st.docID(pDoc.SubnetID),
-			Assert: notDeadDoc,
+			Assert: subnetAssert,
 		})
 	}
 	return ops
 }
 
 // updatePortsDocOps returns the ops for adding a port range to an
-// existing ports document. portsAssert allows specifying an assert
+// existing unit ports document. portsAssert allows specifying an assert
 // statement on the openedPorts collection op.
 var updatePortsDocOps = updatePortsDocOpsFunc
 
 func updatePortsDocOpsFunc(st *State, pDoc portsDoc, portsAssert interface{}, portRange PortRange) []txn.Op {
-	ops := assertMachineNotDeadAndSubnetNotDeadWhenSetOps(st, &pDoc)
+	ops := assertMachineNotDeadAndSubnetWhenSetOps(st, &pDoc, isAliveDoc)
 	return append(ops, []txn.Op{{
 		C:      unitsC,
 		Id:     st.docID(portRange.UnitName),
@@ -462,12 +706,12 @@ func updatePortsDocOpsFunc(st *State, pDoc portsDoc, portsAssert interface{}, po
 }
 
 // setPortsDocOps returns the ops for setting given port ranges to an
-// existing ports document. portsAssert allows specifying an assert
+// existing unit ports document. portsAssert allows specifying an assert
 // statement on the openedPorts collection op.
 var setPortsDocOps = setPortsDocOpsFunc
 
 func setPortsDocOpsFunc(st *State, pDoc portsDoc, portsAssert interface{}, ports ...PortRange) []txn.Op {
-	ops := assertMachineNotDeadAndSubnetNotDeadWhenSetOps(st, &pDoc)
+	ops := assertMachineNotDeadAndSubnetWhenSetOps(st, &pDoc, notDeadDoc)
 	return append(ops, txn.Op{
 		C:      openedPortsC,
 		Id:     pDoc.DocID,
@@ -476,96 +720,90 @@ func setPortsDocOpsFunc(st *State, pDoc portsDoc, portsAssert interface{}, ports
 	})
 }
 
-// removeOps returns the ops for removing the ports document from
-// state.
+// removeOps returns the ops for removing the ports documents of all
+// the units from state.
 func (p *Ports) removeOps() []txn.Op {
+	unitNames := make([]string, 0, len(p.unitDocs))
+	for unitName := range p.unitDocs {
+		unitNames = append(unitNames, unitName)
+	}
+	sort.Strings(unitNames)
+	var ops []txn.Op
+	for _, unitName := range unitNames {
+		ops = append(ops, removePortsDocOps(p.unitDocs[unitName], txn.DocExists)...)
+	}
+	return ops
+}
+
+// removePortsDocOps returns the ops for removing a unit ports document
+// from state. portsAssert allows specifying an assert statement on the
+// openedPorts collection op.
+func removePortsDocOps(pDoc portsDoc, portsAssert interface{}) []txn.Op {
 	return []txn.Op{{
 		C:      openedPortsC,
-		Id:     p.doc.DocID,
-		Assert: txn.DocExists,
+		Id:     pDoc.DocID,
+		Assert: portsAssert,
 		Remove: true,
 	}}
 }
 
 // removePortsForUnitOps returns the ops needed to remove all opened
-// ports for the given unit on its assigned machine.
+// ports for the given unit.
 func removePortsForUnitOps(st *State, unit *Unit) ([]txn.Op, error) {
-	machineId, err := unit.AssignedMachineId()
-	if err != nil {
-		// No assigned machine, so there won't be any ports.
-		return nil, nil
-	}
-	machine, err := st.Machine(machineId)
-	if errors.IsNotFound(err) {
-		// Machine is removed, so there won't be a ports doc for it.
-		return nil, nil
-	} else if err != nil {
-		return nil, errors.Trace(err)
-	}
-	allPorts, err := machine.AllPorts()
+	openedPorts, closer := st.db().GetCollection(openedPortsC)
+	defer closer()
+
+	var docs []portsDoc
+	err := openedPorts.Find(bson.D{{"$or", []bson.D{
+		{{"unit-name", unit.Name()}},
+		// Legacy documents hold the ports of every unit on the
+		// machine, and have no unit name.
+		{{"unit-name", bson.D{{"$exists", false}}}, {"ports.unitname", unit.Name()}},
+	}}}).All(&docs)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 	var ops []txn.Op
-	for _, ports := range allPorts {
-		allRanges := ports.AllPortRanges()
-		var keepPorts []PortRange
-		for portRange, unitName := range allRanges {
-			if unitName != unit.Name() {
-				unitRange := PortRange{
-					UnitName: unitName,
-					FromPort: portRange.FromPort,
-					ToPort:   portRange.ToPort,
-					Protocol: portRange.Protocol,
+	for _, doc := range docs {
+		if doc.UnitName == "" {
+			var otherPorts []PortRange
+			for _, portRange := range doc.Ports {
+				if portRange.UnitName != unit.Name() {
+					otherPorts = append(otherPorts, portRange)
 				}
-				keepPorts = append(keepPorts, unitRange)
+			}
+			if len(otherPorts) > 0 {
+				assert := bson.D{{"txn-revno", doc.TxnRevno}}
+				ops = append(ops, setPortsDocOps(st, doc, assert, otherPorts...)...)
+				continue
 			}
 		}
-		if len(keepPorts) > 0 {
-			assert := bson.D{{"txn-revno", ports.doc.TxnRevno}}
-			ops = append(ops, setPortsDocOps(st, ports.doc, assert, keepPorts...)...)
-		} else {
-			// No other ports left, remove the doc.
-			ops = append(ops, ports.removeOps()...)
+		ops = append(ops, removePortsDocOps(doc, txn.DocExists)...)
+		cleanupOps, err := dyingSubnetCleanupOps(st, doc.SubnetID)
+		if err != nil {
+			return nil, errors.Trace(err)
 		}
+		ops = append(ops, cleanupOps...)
 	}
 	return ops, nil
 }
 
-// getPorts returns the ports document for the specified machine and subnet.
+// getPorts returns the ports opened by all units on the specified
+// machine and subnet.
 func getPorts(st *State, machineID, subnetID string) (*Ports, error) {
-	openedPorts, closer := st.db().GetCollection(openedPortsC)
-	defer closer()
-
-	var doc portsDoc
-	key := portsGlobalKey(machineID, subnetID)
-	err := openedPorts.FindId(key).One(&doc)
-	if err != nil {
-		doc.MachineID = machineID
-		doc.SubnetID = subnetID
-		p := Ports{st, doc, false}
-		if err == mgo.ErrNotFound {
-			return nil, errors.NotFoundf(p.String())
-		}
-		return nil, errors.Annotatef(err, "cannot get %s", p.String())
+	ports := newPorts(st, machineID, subnetID)
+	if err := ports.Refresh(); err != nil {
+		return nil, errors.Trace(err)
 	}
-
-	return &Ports{st, doc, false}, nil
+	return ports, nil
 }
 
-// getOrCreatePorts attempts to retrieve a ports document and returns a newly
-// created one if it does not exist.
+// getOrCreatePorts attempts to retrieve the ports opened on a machine and
+// subnet, and returns new, empty ports if there are none.
 func getOrCreatePorts(st *State, machineID, subnetID string) (*Ports, error) {
 	ports, err := getPorts(st, machineID, subnetID)
 	if errors.IsNotFound(err) {
-		key := portsGlobalKey(machineID, subnetID)
-		doc := portsDoc{
-			DocID:     st.docID(key),
-			MachineID: machineID,
-			SubnetID:  subnetID,
-			ModelUUID: st.ModelUUID(),
-		}
-		ports = &Ports{st, doc, true}
+		ports = newPorts(st, machineID, subnetID)
 	} else if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -10,6 +10,7 @@ import (
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
 
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/state"
@@ -313,6 +314,50 @@ func (s *PortsDocSuite) TestAllPortRanges(c *gc.C) {
 	c.Assert(ranges[network.PortRange{100, 200, "TCP"}], gc.Equals, s.unit1.Name())
 }
 
+func (s *PortsDocSuite) TestPortsHeldPerUnit(c *gc.C) {
+	range1 := state.PortRange{
+		FromPort: 100,
+		ToPort:   200,
+		UnitName: s.unit1.Name(),
+		Protocol: "tcp",
+	}
+	range2 := state.PortRange{
+		FromPort: 300,
+		ToPort:   400,
+		UnitName: s.unit2.Name(),
+		Protocol: "tcp",
+	}
+	err := s.portsOnSubnet.OpenPorts(range1)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.portsOnSubnet.OpenPorts(range2)
+	c.Assert(err, jc.ErrorIsNil)
+
+	coll := s.State.MongoSession().DB("juju").C(state.OpenedPortsC)
+	unitNames := func() []string {
+		var docs []struct {
+			UnitName string `bson:"unit-name"`
+		}
+		err := coll.Find(bson.D{{"machine-id", s.machine.Id()}}).Sort("_id").All(&docs)
+		c.Assert(err, jc.ErrorIsNil)
+		var names []string
+		for _, doc := range docs {
+			names = append(names, doc.UnitName)
+		}
+		return names
+	}
+	c.Assert(unitNames(), jc.DeepEquals, []string{s.unit1.Name(), s.unit2.Name()})
+
+	// Closing the last of a unit's ports removes only its document.
+	err = s.portsOnSubnet.ClosePorts(range1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unitNames(), jc.DeepEquals, []string{s.unit2.Name()})
+
+	ports, err := state.GetPorts(s.State, s.machine.Id(), s.subnet.ID())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ports.PortsForUnit(s.unit1.Name()), gc.HasLen, 0)
+	c.Assert(ports.PortsForUnit(s.unit2.Name()), jc.DeepEquals, []state.PortRange{range2})
+}
+
 func (s *PortsDocSuite) TestICMP(c *gc.C) {
 	portRange := state.PortRange{
 		FromPort: -1,
@@ -361,6 +406,186 @@ func (s *PortsDocSuite) TestCloseInvalidRange(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `cannot close ports 150-200/tcp \("wordpress/0"\): port ranges 100-200/tcp \("wordpress/0"\) and 150-200/tcp \("wordpress/0"\) conflict`)
 }
 
+func (s *PortsDocSuite) TestDyingSubnetAllowsClosingPortsOnly(c *gc.C) {
+	portRange := state.PortRange{
+		FromPort: 100,
+		ToPort:   200,
+		UnitName: s.unit1.Name(),
+		Protocol: "TCP",
+	}
+	err := s.portsOnSubnet.OpenPorts(portRange)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.subnet.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	refs, err := s.subnet.References()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(refs.Ports, gc.Equals, 1)
+
+	// The ports still refer to the subnet, so it stays Dying.
+	c.Assert(s.State.Cleanup(), jc.ErrorIsNil)
+	err = s.subnet.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.subnet.Life(), gc.Equals, state.Dying)
+
+	err = s.portsOnSubnet.OpenPorts(state.PortRange{
+		FromPort: 300,
+		ToPort:   400,
+		UnitName: s.unit2.Name(),
+		Protocol: "TCP",
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot open ports 300-400/tcp \("wordpress/1"\): subnet "0.1.2.0/24" not alive`)
+
+	err = s.portsOnSubnet.ClosePorts(portRange)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.State.Cleanup(), jc.ErrorIsNil)
+	err = s.subnet.Refresh()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *PortsDocSuite) TestOpenPortsConflictingConcurrentOpen(c *gc.C) {
+	range1 := state.PortRange{
+		FromPort: 100,
+		ToPort:   200,
+		UnitName: s.unit1.Name(),
+		Protocol: "tcp",
+	}
+	defer state.SetBeforeHooks(c, s.State, func() {
+		ports, err := state.GetOrCreatePorts(s.State, s.machine.Id(), s.subnet.ID())
+		c.Assert(err, jc.ErrorIsNil)
+		err = ports.OpenPorts(range1)
+		c.Assert(err, jc.ErrorIsNil)
+	}).Check()
+
+	err := s.portsOnSubnet.OpenPorts(state.PortRange{
+		FromPort: 150,
+		ToPort:   250,
+		UnitName: s.unit2.Name(),
+		Protocol: "tcp",
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot open ports 150-250/tcp \("wordpress/1"\): port ranges 100-200/tcp \("wordpress/0"\) and 150-250/tcp \("wordpress/1"\) conflict`)
+
+	ports, err := state.GetPorts(s.State, s.machine.Id(), s.subnet.ID())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ports.PortsForUnit(s.unit1.Name()), jc.DeepEquals, []state.PortRange{range1})
+	c.Assert(ports.PortsForUnit(s.unit2.Name()), gc.HasLen, 0)
+}
+
+func (s *PortsDocSuite) TestOpenPortsConflictingConcurrentOpenExistingDoc(c *gc.C) {
+	range1 := state.PortRange{
+		FromPort: 500,
+		ToPort:   600,
+		UnitName: s.unit1.Name(),
+		Protocol: "tcp",
+	}
+	range2 := state.PortRange{
+		FromPort: 100,
+		ToPort:   200,
+		UnitName: s.unit1.Name(),
+		Protocol: "tcp",
+	}
+	err := s.portsOnSubnet.OpenPorts(range1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	defer state.SetBeforeHooks(c, s.State, func() {
+		ports, err := state.GetPorts(s.State, s.machine.Id(), s.subnet.ID())
+		c.Assert(err, jc.ErrorIsNil)
+		err = ports.OpenPorts(range2)
+		c.Assert(err, jc.ErrorIsNil)
+	}).Check()
+
+	err = s.portsOnSubnet.OpenPorts(state.PortRange{
+		FromPort: 150,
+		ToPort:   250,
+		UnitName: s.unit2.Name(),
+		Protocol: "tcp",
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot open ports 150-250/tcp \("wordpress/1"\): port ranges 100-200/tcp \("wordpress/0"\) and 150-250/tcp \("wordpress/1"\) conflict`)
+
+	ports, err := state.GetPorts(s.State, s.machine.Id(), s.subnet.ID())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ports.PortsForUnit(s.unit1.Name()), jc.DeepEquals, []state.PortRange{range1, range2})
+	c.Assert(ports.PortsForUnit(s.unit2.Name()), gc.HasLen, 0)
+}
+
+// addLegacyPortsDoc adds a document holding the given ports of all the
+// units on the machine and subnet, as written before ports were held per
+// unit.
+func (s *PortsDocSuite) addLegacyPortsDoc(c *gc.C, ports ...state.PortRange) {
+	coll := s.State.MongoSession().DB("juju").C(state.OpenedPortsC)
+	key := fmt.Sprintf("m#%s#%s", s.machine.Id(), s.subnet.ID())
+	err := coll.Insert(bson.M{
+		"_id":        state.DocID(s.State, key),
+		"model-uuid": s.State.ModelUUID(),
+		"machine-id": s.machine.Id(),
+		"subnet-id":  s.subnet.ID(),
+		"ports":      ports,
+		"txn-revno":  int64(1),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *PortsDocSuite) TestCloseLegacyPorts(c *gc.C) {
+	range1 := state.PortRange{
+		FromPort: 100,
+		ToPort:   200,
+		UnitName: s.unit1.Name(),
+		Protocol: "tcp",
+	}
+	range2 := state.PortRange{
+		FromPort: 300,
+		ToPort:   400,
+		UnitName: s.unit2.Name(),
+		Protocol: "tcp",
+	}
+	s.addLegacyPortsDoc(c, range1, range2)
+
+	ports, err := state.GetPorts(s.State, s.machine.Id(), s.subnet.ID())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ports.PortsForUnit(s.unit1.Name()), jc.DeepEquals, []state.PortRange{range1})
+
+	// Closing a unit's port in the legacy document leaves the ports
+	// of the other units there.
+	err = ports.ClosePorts(range1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ports.PortsForUnit(s.unit1.Name()), gc.HasLen, 0)
+
+	ports, err = state.GetPorts(s.State, s.machine.Id(), s.subnet.ID())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ports.PortsForUnit(s.unit1.Name()), gc.HasLen, 0)
+	c.Assert(ports.PortsForUnit(s.unit2.Name()), jc.DeepEquals, []state.PortRange{range2})
+
+	// Closing the last port removes the legacy document.
+	err = ports.ClosePorts(range2)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = state.GetPorts(s.State, s.machine.Id(), s.subnet.ID())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *PortsDocSuite) TestRemoveUnitRemovesLegacyPorts(c *gc.C) {
+	range1 := state.PortRange{
+		FromPort: 100,
+		ToPort:   200,
+		UnitName: s.unit1.Name(),
+		Protocol: "tcp",
+	}
+	range2 := state.PortRange{
+		FromPort: 300,
+		ToPort:   400,
+		UnitName: s.unit2.Name(),
+		Protocol: "tcp",
+	}
+	s.addLegacyPortsDoc(c, range1, range2)
+
+	c.Assert(s.unit1.EnsureDead(), jc.ErrorIsNil)
+	c.Assert(s.unit1.Remove(), jc.ErrorIsNil)
+
+	ports, err := state.GetPorts(s.State, s.machine.Id(), s.subnet.ID())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ports.PortsForUnit(s.unit1.Name()), gc.HasLen, 0)
+	c.Assert(ports.PortsForUnit(s.unit2.Name()), jc.DeepEquals, []state.PortRange{range2})
+}
+
 func (s *PortsDocSuite) TestRemovePortsDoc(c *gc.C) {
 	portRange := state.PortRange{
 		FromPort: 100,
@@ -0,0 +1,159 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// PortsHistoryLimit is the number of port changes that are kept for
+// each machine.
+const PortsHistoryLimit = 100
+
+// PortChange records a port range being opened or closed on a machine.
+type PortChange struct {
+	// MachineID identifies the machine the ports were changed on.
+	MachineID string
+
+	// SubnetID identifies the subnet the ports were changed on, if any.
+	SubnetID string
+
+	// Ports holds the port range that was changed, and the name of the
+	// unit that changed it.
+	Ports PortRange
+
+	// Opened is true if the ports were opened, and false if they were
+	// closed.
+	Opened bool
+
+	// Hook holds the name of the hook the unit changed the ports in,
+	// if it is known.
+	Hook string
+
+	// Time records when the ports were changed.
+	Time time.Time
+}
+
+// portChangeDoc records a single port change. Port changes are only
+// ever added, and are trimmed to the most recent PortsHistoryLimit for
+// each machine.
+type portChangeDoc struct {
+	ModelUUID string `bson:"model-uuid"`
+	MachineID string `bson:"machine-id"`
+	SubnetID  string `bson:"subnet-id,omitempty"`
+	UnitName  string `bson:"unitname"`
+	FromPort  int    `bson:"fromport"`
+	ToPort    int    `bson:"toport"`
+	Protocol  string `bson:"protocol"`
+	Opened    bool   `bson:"opened"`
+	Hook      string `bson:"hook,omitempty"`
+	Updated   int64  `bson:"updated"`
+}
+
+// recordPortChange adds a port change for the machine and subnet of
+// the given ports document to the ports history, and trims the history
+// of the machine to the most recent PortsHistoryLimit changes.
+func recordPortChange(st *State, pDoc portsDoc, portRange PortRange, opened bool, hook string) error {
+	history, closer := st.db().GetCollection(portsHistoryC)
+	defer closer()
+
+	historyW := history.Writeable()
+	err := historyW.Insert(&portChangeDoc{
+		MachineID: pDoc.MachineID,
+		SubnetID:  pDoc.SubnetID,
+		UnitName:  portRange.UnitName,
+		FromPort:  portRange.FromPort,
+		ToPort:    portRange.ToPort,
+		Protocol:  portRange.Protocol,
+		Opened:    opened,
+		Hook:      hook,
+		Updated:   st.clock().Now().UnixNano(),
+	})
+	if err != nil {
+		return errors.Annotate(err, "cannot record port change")
+	}
+
+	iter := history.Find(bson.D{{
+		"machine-id", pDoc.MachineID,
+	}}).Sort("-updated", "-_id").Skip(PortsHistoryLimit).Select(bson.M{"_id": 1}).Iter()
+	defer iter.Close()
+
+	logFormat := "trimmed %d port changes for " + fmt.Sprintf("machine %q", pDoc.MachineID)
+	deleted, err := deleteInBatches(
+		historyW.Underlying(), iter,
+		logFormat, loggo.DEBUG,
+		noEarlyFinish,
+	)
+	if err != nil {
+		return errors.Annotate(err, "cannot trim ports history")
+	}
+	if deleted > 0 {
+		logger.Debugf(logFormat, deleted)
+	}
+	return nil
+}
+
+// removePortsHistoryOps returns the operations that remove the ports
+// history of the machine with the given id. The history is trimmed to
+// PortsHistoryLimit changes, so there are never too many to remove in
+// a single transaction.
+func removePortsHistoryOps(st *State, machineID string) ([]txn.Op, error) {
+	history, closer := st.db().GetCollection(portsHistoryC)
+	defer closer()
+
+	var docs []bson.M
+	err := history.Find(bson.D{{"machine-id", machineID}}).Select(bson.M{"_id": 1}).All(&docs)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get ports history of machine %q", machineID)
+	}
+	ops := make([]txn.Op, len(docs))
+	for i, doc := range docs {
+		ops[i] = txn.Op{
+			C:      portsHistoryC,
+			Id:     doc["_id"],
+			Remove: true,
+		}
+	}
+	return ops, nil
+}
+
+// PortsHistory returns the most recent changes to the ports opened on
+// the machine, newest first. At most size changes are returned; if size
+// is not positive, all of the recorded changes are returned.
+func (m *Machine) PortsHistory(size int) ([]PortChange, error) {
+	history, closer := m.st.db().GetCollection(portsHistoryC)
+	defer closer()
+
+	query := history.Find(bson.D{{"machine-id", m.Id()}}).Sort("-updated", "-_id")
+	if size > 0 {
+		query = query.Limit(size)
+	}
+	var docs []portChangeDoc
+	if err := query.All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get ports history of machine %v", m)
+	}
+	changes := make([]PortChange, len(docs))
+	for i, doc := range docs {
+		changes[i] = PortChange{
+			MachineID: doc.MachineID,
+			SubnetID:  doc.SubnetID,
+			Ports: PortRange{
+				UnitName: doc.UnitName,
+				FromPort: doc.FromPort,
+				ToPort:   doc.ToPort,
+				Protocol: doc.Protocol,
+			},
+			Opened: doc.Opened,
+			Hook:   doc.Hook,
+			Time:   time.Unix(0, doc.Updated),
+		}
+	}
+	return changes, nil
+}
@@ -0,0 +1,123 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type PortsHistorySuite struct {
+	ConnSuite
+	machine *state.Machine
+	unit    *state.Unit
+}
+
+var _ = gc.Suite(&PortsHistorySuite{})
+
+func (s *PortsHistorySuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.machine = s.Factory.MakeMachine(c, &factory.MachineParams{Series: "quantal"})
+	s.unit = s.Factory.MakeUnit(c, &factory.UnitParams{Machine: s.machine})
+}
+
+func (s *PortsHistorySuite) TestPortsHistory(c *gc.C) {
+	s.Clock.Advance(time.Minute)
+	opened := s.Clock.Now()
+	err := s.unit.OpenPortsInHook("install", "tcp", 22, 22)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.Clock.Advance(time.Minute)
+	closed := s.Clock.Now()
+	err = s.unit.ClosePorts("tcp", 22, 22)
+	c.Assert(err, jc.ErrorIsNil)
+
+	history, err := s.machine.PortsHistory(0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 2)
+	ports := state.PortRange{
+		UnitName: s.unit.Name(),
+		FromPort: 22,
+		ToPort:   22,
+		Protocol: "tcp",
+	}
+	c.Check(history[0].Ports, jc.DeepEquals, ports)
+	c.Check(history[0].MachineID, gc.Equals, s.machine.Id())
+	c.Check(history[0].Opened, jc.IsFalse)
+	c.Check(history[0].Hook, gc.Equals, "")
+	c.Check(history[0].Time.Equal(closed), jc.IsTrue)
+	c.Check(history[1].Ports, jc.DeepEquals, ports)
+	c.Check(history[1].Opened, jc.IsTrue)
+	c.Check(history[1].Hook, gc.Equals, "install")
+	c.Check(history[1].Time.Equal(opened), jc.IsTrue)
+
+	history, err = s.machine.PortsHistory(1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 1)
+	c.Check(history[0].Opened, jc.IsFalse)
+}
+
+func (s *PortsHistorySuite) TestPortsHistoryIgnoresNoOps(c *gc.C) {
+	err := s.unit.OpenPorts("tcp", 80, 80)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.OpenPorts("tcp", 80, 80)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.ClosePorts("tcp", 443, 443)
+	c.Assert(err, jc.ErrorIsNil)
+
+	history, err := s.machine.PortsHistory(0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 1)
+	c.Check(history[0].Opened, jc.IsTrue)
+}
+
+func (s *PortsHistorySuite) TestPortsHistoryTrimmed(c *gc.C) {
+	limit := state.PortsHistoryLimit
+	for i := 0; i < limit+2; i++ {
+		s.Clock.Advance(time.Minute)
+		err := s.unit.OpenPorts("tcp", 1000+i, 1000+i)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	history, err := s.machine.PortsHistory(0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, limit)
+	c.Check(history[0].Ports.FromPort, gc.Equals, 1000+limit+1)
+	c.Check(history[limit-1].Ports.FromPort, gc.Equals, 1002)
+}
+
+func (s *PortsHistorySuite) TestPortsHistoryRemovedWithMachine(c *gc.C) {
+	err := s.unit.OpenPorts("tcp", 22, 22)
+	c.Assert(err, jc.ErrorIsNil)
+	other := s.Factory.MakeUnit(c, nil)
+	err = other.OpenPorts("tcp", 80, 80)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	history, err := s.machine.PortsHistory(0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 0)
+
+	// The history of other machines is left alone.
+	otherID, err := other.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	otherMachine, err := s.State.Machine(otherID)
+	c.Assert(err, jc.ErrorIsNil)
+	history, err = otherMachine.PortsHistory(0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 1)
+}
@@ -6,6 +6,7 @@ package state
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
 	"gopkg.in/mgo.v2"
@@ -87,10 +88,16 @@ func (m *Machine) clearFlag() error {
 	if err != nil {
 		return errors.Trace(err)
 	}
-	if count == 0 {
-		return nil
+	// The flag is cleared as the machine reboots, or shuts down because
+	// its parent is rebooting, so record that the next boot is expected.
+	ops := []txn.Op{{
+		C:      machinesC,
+		Id:     docID,
+		Update: bson.D{{"$set", bson.D{{"rebootexpected", true}}}},
+	}}
+	if count > 0 {
+		ops = append(ops, removeRebootDocOp(m.st, m.Id()))
 	}
-	ops := []txn.Op{removeRebootDocOp(m.st, m.Id())}
 	err = m.st.db().RunTransaction(ops)
 	if err != nil {
 		return errors.Errorf("failed to clear reboot flag: %v", err)
@@ -160,6 +167,170 @@ func (m *Machine) ShouldRebootOrShutdown() (RebootAction, error) {
 	return ShouldDoNothing, nil
 }
 
+// BootID returns the boot id of the machine's kernel, as last reported by
+// the machine agent. It is empty if none has been reported.
+func (m *Machine) BootID() string {
+	return m.doc.BootID
+}
+
+// SetBootID records the boot id of the machine's kernel, which the machine
+// agent reports each time it starts. It returns true if the machine has
+// been rebooted since the boot id was last recorded without Juju having
+// told it to reboot or shut down.
+func (m *Machine) SetBootID(bootID string) (bool, error) {
+	if bootID == "" {
+		return false, errors.NotValidf("empty boot id")
+	}
+	if bootID == m.doc.BootID {
+		return false, nil
+	}
+	unexpected := m.doc.BootID != "" && !m.doc.RebootExpected
+	set := bson.D{{"bootid", bootID}}
+	var completed int64
+	if m.doc.RebootExpected && m.doc.RebootRequested != 0 {
+		// The machine has come back from a reboot requested
+		// through the API.
+		completed = m.st.clock().Now().UnixNano()
+		set = append(set, bson.DocElem{"rebootcompleted", completed})
+	}
+	ops := []txn.Op{{
+		C:      machinesC,
+		Id:     m.doc.DocID,
+		Assert: notDeadDoc,
+		Update: bson.D{
+			{"$set", set},
+			{"$unset", bson.D{{"rebootexpected", nil}}},
+		},
+	}}
+	if err := m.st.db().RunTransaction(ops); err != nil {
+		return false, errors.Annotatef(onAbort(err, ErrDead), "cannot set boot id of machine %v", m)
+	}
+	m.doc.BootID = bootID
+	m.doc.RebootExpected = false
+	if completed != 0 {
+		m.doc.RebootCompleted = completed
+	}
+	return unexpected, nil
+}
+
+// RebootState describes the progress of a reboot requested through
+// the API.
+type RebootState string
+
+const (
+	// RebootNone means that no reboot of the machine has been
+	// requested.
+	RebootNone RebootState = "none"
+
+	// RebootRequested means that the machine has been asked to reboot,
+	// and its agent is waiting for the machine's units to finish any
+	// hooks they are running.
+	RebootRequested RebootState = "requested"
+
+	// RebootInProgress means that the machine agent has taken the
+	// machine lock and is rebooting the machine.
+	RebootInProgress RebootState = "rebooting"
+
+	// RebootCompleted means that the machine agent has started again
+	// since the machine was rebooted.
+	RebootCompleted RebootState = "completed"
+)
+
+// RebootStatus describes the progress of the most recent reboot of a
+// machine requested through the API.
+type RebootStatus struct {
+	State RebootState
+
+	// Requested records when the reboot was requested. It is zero if
+	// no reboot has been requested.
+	Requested time.Time
+
+	// Completed records when the machine agent started again after
+	// the reboot. It is zero unless State is RebootCompleted.
+	Completed time.Time
+}
+
+// RequestReboot asks the machine agent to reboot the machine once the
+// machine's units have finished running any current hooks, and records
+// the request so that its progress can be followed with RebootStatus.
+// It returns an AlreadyExists error if the machine is already due to
+// reboot.
+func (m *Machine) RequestReboot() error {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := checkModelActive(m.st); err != nil {
+				return nil, errors.Trace(err)
+			}
+			if err := m.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if m.Life() == Dead {
+			return nil, ErrDead
+		}
+		if m.doc.RebootExpected {
+			return nil, errors.AlreadyExistsf("reboot of machine %v", m)
+		}
+		flagged, err := m.GetRebootFlag()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if flagged {
+			return nil, errors.AlreadyExistsf("reboot of machine %v", m)
+		}
+		return []txn.Op{
+			assertModelActiveOp(m.st.ModelUUID()),
+			{
+				C:  machinesC,
+				Id: m.doc.DocID,
+				Assert: bson.D{
+					{"life", bson.D{{"$ne", Dead}}},
+					{"rebootexpected", bson.D{{"$ne", true}}},
+				},
+				Update: bson.D{
+					{"$set", bson.D{{"rebootrequested", m.st.clock().Now().UnixNano()}}},
+					{"$unset", bson.D{{"rebootcompleted", nil}}},
+				},
+			}, {
+				C:      rebootC,
+				Id:     m.doc.DocID,
+				Assert: txn.DocMissing,
+				Insert: &rebootDoc{Id: m.Id()},
+			},
+		}, nil
+	}
+	if err := m.st.db().Run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot request reboot of machine %v", m)
+	}
+	return m.Refresh()
+}
+
+// RebootStatus returns the progress of the most recent reboot of the
+// machine requested with RequestReboot.
+func (m *Machine) RebootStatus() (RebootStatus, error) {
+	if m.doc.RebootRequested == 0 {
+		return RebootStatus{State: RebootNone}, nil
+	}
+	status := RebootStatus{
+		Requested: time.Unix(0, m.doc.RebootRequested),
+	}
+	if m.doc.RebootCompleted != 0 {
+		status.State = RebootCompleted
+		status.Completed = time.Unix(0, m.doc.RebootCompleted)
+		return status, nil
+	}
+	flagged, err := m.GetRebootFlag()
+	if err != nil {
+		return RebootStatus{}, errors.Trace(err)
+	}
+	if flagged {
+		status.State = RebootRequested
+	} else {
+		status.State = RebootInProgress
+	}
+	return status, nil
+}
+
 type RebootFlagSetter interface {
 	SetRebootFlag(flag bool) error
 }
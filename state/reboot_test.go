@@ -5,6 +5,9 @@
 package state_test
 
 import (
+	"time"
+
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -224,3 +227,150 @@ func (s *RebootSuite) TestWatchRebootHappensOnContainer3(c *gc.C) {
 	statetesting.AssertStop(c, s.wC3)
 	s.wcC3.AssertClosed()
 }
+
+type BootIDSuite struct {
+	ConnSuite
+
+	machine *state.Machine
+	c1      *state.Machine
+}
+
+var _ = gc.Suite(&BootIDSuite{})
+
+func (s *BootIDSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	var err error
+	s.machine, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	s.c1, err = s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, s.machine.Id(), instance.LXD)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *BootIDSuite) TestSetBootID(c *gc.C) {
+	c.Assert(s.machine.BootID(), gc.Equals, "")
+
+	// The first boot id reported is not a reboot.
+	unexpected, err := s.machine.SetBootID("boot-1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unexpected, jc.IsFalse)
+
+	// Neither is the same boot id reported again.
+	unexpected, err = s.machine.SetBootID("boot-1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unexpected, jc.IsFalse)
+
+	err = s.machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.BootID(), gc.Equals, "boot-1")
+
+	// A new boot id, without Juju having rebooted the machine, is.
+	unexpected, err = s.machine.SetBootID("boot-2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unexpected, jc.IsTrue)
+}
+
+func (s *BootIDSuite) TestSetBootIDAfterReboot(c *gc.C) {
+	_, err := s.machine.SetBootID("boot-1")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetRebootFlag(true)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetRebootFlag(false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	unexpected, err := s.machine.SetBootID("boot-2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unexpected, jc.IsFalse)
+
+	// Only the one reboot was expected.
+	unexpected, err = s.machine.SetBootID("boot-3")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unexpected, jc.IsTrue)
+}
+
+func (s *BootIDSuite) TestSetBootIDAfterParentReboot(c *gc.C) {
+	_, err := s.c1.SetBootID("boot-1")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// A container which is shut down for its parent's reboot clears
+	// its flag, even though it has none set.
+	err = s.c1.SetRebootFlag(false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.c1.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	unexpected, err := s.c1.SetBootID("boot-2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unexpected, jc.IsFalse)
+}
+
+func (s *BootIDSuite) TestSetBootIDEmpty(c *gc.C) {
+	_, err := s.machine.SetBootID("")
+	c.Assert(err, gc.ErrorMatches, "empty boot id not valid")
+}
+
+func (s *BootIDSuite) TestRequestReboot(c *gc.C) {
+	_, err := s.machine.SetBootID("boot-1")
+	c.Assert(err, jc.ErrorIsNil)
+	status, err := s.machine.RebootStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, jc.DeepEquals, state.RebootStatus{State: state.RebootNone})
+
+	s.Clock.Advance(time.Minute)
+	requested := s.Clock.Now()
+	err = s.machine.RequestReboot()
+	c.Assert(err, jc.ErrorIsNil)
+	flag, err := s.machine.GetRebootFlag()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(flag, jc.IsTrue)
+	status, err = s.machine.RebootStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.State, gc.Equals, state.RebootRequested)
+	c.Assert(status.Requested.Equal(requested), jc.IsTrue)
+
+	// A second request is refused while the first is pending.
+	err = s.machine.RequestReboot()
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+
+	// The machine agent clears the flag as it reboots.
+	err = s.machine.SetRebootFlag(false)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	status, err = s.machine.RebootStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.State, gc.Equals, state.RebootInProgress)
+	err = s.machine.RequestReboot()
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+
+	// And reports a new boot id when it starts again.
+	s.Clock.Advance(time.Minute)
+	completed := s.Clock.Now()
+	unexpected, err := s.machine.SetBootID("boot-2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unexpected, jc.IsFalse)
+	status, err = s.machine.RebootStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.State, gc.Equals, state.RebootCompleted)
+	c.Assert(status.Requested.Equal(requested), jc.IsTrue)
+	c.Assert(status.Completed.Equal(completed), jc.IsTrue)
+
+	// The machine may be rebooted again once it has come back.
+	err = s.machine.RequestReboot()
+	c.Assert(err, jc.ErrorIsNil)
+	status, err = s.machine.RebootStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.State, gc.Equals, state.RebootRequested)
+	c.Assert(status.Completed.IsZero(), jc.IsTrue)
+}
+
+func (s *BootIDSuite) TestRequestRebootDeadMachine(c *gc.C) {
+	err := s.c1.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.c1.RequestReboot()
+	c.Assert(err, gc.ErrorMatches, `cannot request reboot of machine 0/lxd/0: not found or dead`)
+}
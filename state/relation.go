@@ -18,6 +18,7 @@ import (
 	"gopkg.in/mgo.v2/txn"
 
 	"github.com/juju/juju/core/leadership"
+	corerelation "github.com/juju/juju/core/relation"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/permission"
 )
@@ -122,7 +123,9 @@ func (r *Relation) Status() (status.StatusInfo, error) {
 	return rStatus, nil
 }
 
-// SetStatus sets the status of the relation.
+// SetStatus sets the status of the relation. If the relation is being
+// suspended and no status data is given, the data records the reason
+// the relation was suspended; see core/relation.StatusReason.
 func (r *Relation) SetStatus(statusInfo status.StatusInfo) error {
 	currentStatus, err := r.Status()
 	if err != nil {
@@ -151,12 +154,19 @@ func (r *Relation) SetStatus(statusInfo status.StatusInfo) error {
 				"cannot set status %q when relation has status %q", statusInfo.Status, currentStatus.Status))
 		}
 	}
+	data := statusInfo.Data
+	if data == nil && (statusInfo.Status == status.Suspending || statusInfo.Status == status.Suspended) {
+		data = corerelation.StatusReason{
+			Reason:          corerelation.ReasonSuspended,
+			SuspendedReason: r.doc.SuspendedReason,
+		}.Data()
+	}
 	return setStatus(r.st.db(), setStatusParams{
 		badge:     "relation",
 		globalKey: r.globalScope(),
 		status:    statusInfo.Status,
 		message:   statusInfo.Message,
-		rawData:   statusInfo.Data,
+		rawData:   data,
 		updated:   timeOrNow(statusInfo.Since, r.st.clock()),
 	})
 }
@@ -236,6 +246,7 @@ func (r *Relation) SetSuspended(suspended bool, suspendedReason string) error {
 	err := r.st.db().Run(buildTxn)
 	if err == nil {
 		r.doc.Suspended = suspended
+		r.doc.SuspendedReason = suspendedReason
 	}
 	return err
 }
@@ -14,6 +14,7 @@ import (
 	"gopkg.in/juju/names.v3"
 
 	"github.com/juju/juju/core/crossmodel"
+	corerelation "github.com/juju/juju/core/relation"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
@@ -721,10 +722,44 @@ func (s *RelationSuite) TestStatus(c *gc.C) {
 	c.Assert(relStatus, jc.DeepEquals, status.StatusInfo{
 		Status:  status.Suspended,
 		Message: "for a while",
-		Data:    map[string]interface{}{},
+		Data:    map[string]interface{}{"reason": "suspended"},
 	})
 }
 
+func (s *RelationSuite) TestStatusSuspendedReason(c *gc.C) {
+	rel := s.setupRelationStatus(c)
+	err := rel.SetSuspended(true, "for maintenance")
+	c.Assert(err, jc.ErrorIsNil)
+	err = rel.SetStatus(status.StatusInfo{Status: status.Suspending})
+	c.Assert(err, jc.ErrorIsNil)
+	relStatus, err := rel.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	reason, ok := corerelation.ParseStatusReason(relStatus.Data)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(reason, jc.DeepEquals, corerelation.StatusReason{
+		Reason:          corerelation.ReasonSuspended,
+		SuspendedReason: "for maintenance",
+	})
+}
+
+func (s *RelationSuite) TestStatusKeepsGivenData(c *gc.C) {
+	rel := s.setupRelationStatus(c)
+	reason := corerelation.StatusReason{
+		Reason:              corerelation.ReasonDeparted,
+		DepartedApplication: "remote-wordpress",
+	}
+	err := rel.SetStatus(status.StatusInfo{
+		Status: status.Broken,
+		Data:   reason.Data(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	relStatus, err := rel.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	parsed, ok := corerelation.ParseStatusReason(relStatus.Data)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(parsed, jc.DeepEquals, reason)
+}
+
 func (s *RelationSuite) TestInvalidStatus(c *gc.C) {
 	rel := s.setupRelationStatus(c)
 
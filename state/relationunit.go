@@ -143,13 +143,11 @@ func (ru *RelationUnit) EnterScope(settings map[string]interface{}) error {
 	})
 
 	// * If the unit should have a subordinate, and does not, create it.
-	var existingSubName string
-	if subOps, subName, err := ru.subordinateOps(); err != nil {
+	subOps, existingSubName, err := ru.subordinateOps()
+	if err != nil {
 		return err
-	} else {
-		existingSubName = subName
-		ops = append(ops, subOps...)
 	}
+	ops = append(ops, subOps...)
 
 	// Now run the complete transaction, or figure out why we can't.
 	if err := ru.st.db().RunTransaction(ops); err != txn.ErrAborted {
@@ -205,6 +203,13 @@ func (ru *RelationUnit) EnterScope(settings map[string]interface{}) error {
 		return fmt.Errorf(prefix + "concurrent settings change detected")
 	}
 
+	// A subordinate unit created under a placement policy asserts that
+	// no other subordinate of its application arrived on the machine
+	// meanwhile; if one did, the policy must be evaluated again.
+	if len(subOps) > 0 && existingSubName == "" {
+		return ErrCannotEnterScopeYet
+	}
+
 	// Apparently, all our assertions should have passed, but the txn was
 	// aborted: something is really seriously wrong.
 	return fmt.Errorf(prefix + "inconsistent state in EnterScope")
@@ -236,8 +241,21 @@ func (ru *RelationUnit) subordinateOps() ([]txn.Op, string, error) {
 		if err != nil {
 			return nil, "", err
 		}
+		allowed, policyOps, err := application.subordinateAllowed(ru.endpoint.ApplicationName, unitName)
+		if err != nil {
+			return nil, "", errors.Trace(err)
+		}
+		if !allowed {
+			// The principal still enters scope, but without a
+			// subordinate unit of its own.
+			logger.Debugf("subordinate policy of %q excludes unit %q", applicationname, unitName)
+			return nil, "", nil
+		}
 		_, ops, err := application.addUnitOps(unitName, AddUnitParams{}, nil)
-		return ops, "", err
+		if err != nil {
+			return nil, "", err
+		}
+		return append(ops, policyOps...), "", nil
 	} else if err != nil {
 		return nil, "", err
 	} else if lDoc.Life != Alive {
@@ -582,7 +600,8 @@ var PreferredAddressRetryArgs = func() retry.CallArgs {
 
 // NetworksForRelation returns the ingress and egress addresses for a relation and unit.
 // The ingress addresses depend on if the relation is cross model and whether the
-// relation endpoint is bound to a space.
+// relation endpoint is bound to a space. The egress addresses are those configured
+// for the relation, else the default egress, else any reported for the unit's binding.
 func NetworksForRelation(
 	binding string, unit *Unit, rel *Relation, defaultEgress []string, pollPublic bool,
 ) (boundSpace string, ingress []string, egress []string, _ error) {
@@ -597,6 +616,15 @@ func NetworksForRelation(
 	} else {
 		egress = defaultEgress
 	}
+	if reported := unit.EgressAddressesForBinding(binding); len(egress) == 0 && len(reported) > 0 {
+		// No egress subnets are configured, so use the addresses
+		// from which the unit's traffic is reported to originate,
+		// rather than assume it originates from the ingress address.
+		egress, err = network.FormatAsCIDR(reported)
+		if err != nil {
+			return "", nil, nil, errors.Trace(err)
+		}
+	}
 
 	boundSpace, err = unit.GetSpaceForBinding(binding)
 	if err != nil && !errors.IsNotValid(err) {
@@ -884,6 +884,33 @@ func (s *RelationUnitSuite) TestNetworksForRelation(c *gc.C) {
 	c.Assert(egress, gc.DeepEquals, []string{"1.2.3.4/32"})
 }
 
+func (s *RelationUnitSuite) TestNetworksForRelationReportedEgress(c *gc.C) {
+	prr := newProReqRelation(c, &s.ConnSuite, charm.ScopeGlobal)
+	err := prr.pu0.AssignToNewMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	id, err := prr.pu0.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.Machine(id)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = machine.SetProviderAddresses(
+		network.NewScopedAddress("1.2.3.4", network.ScopeCloudLocal),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	err = prr.pu0.SetEgressAddresses(map[string][]string{"": {"5.6.7.8"}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, ingress, egress, err := state.NetworksForRelation("", prr.pu0, prr.rel, nil, true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ingress, gc.DeepEquals, []string{"1.2.3.4"})
+	c.Assert(egress, gc.DeepEquals, []string{"5.6.7.8/32"})
+
+	// Configured egress subnets take precedence.
+	_, _, egress, err = state.NetworksForRelation("", prr.pu0, prr.rel, []string{"10.0.0.0/8"}, true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(egress, gc.DeepEquals, []string{"10.0.0.0/8"})
+}
+
 func (s *RelationUnitSuite) addDevicesWithAddresses(c *gc.C, machine *state.Machine, addresses ...string) {
 	for _, address := range addresses {
 		name := fmt.Sprintf("e%x", rand.Int31())
@@ -0,0 +1,164 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// ScaleOperationExpiry is how long the token a scale plan was applied
+// with is remembered for, and so how long it guards against the plan
+// being applied again.
+const ScaleOperationExpiry = 24 * time.Hour
+
+// ScaleOperation records a scale plan applied to the model under an
+// idempotency token.
+type ScaleOperation struct {
+	// Token is the idempotency token the plan was applied with.
+	Token string
+
+	// Completed reports whether all of the plan's changes were made.
+	Completed bool
+
+	// AddedUnits holds the names of the units added by the plan.
+	AddedUnits []string
+
+	// RemovedUnits holds the names of the units removed by the plan.
+	RemovedUnits []string
+}
+
+// scaleOperationDoc records a scale plan applied to a model. It is keyed
+// by the token the plan was applied with.
+type scaleOperationDoc struct {
+	DocID        string    `bson:"_id"`
+	ModelUUID    string    `bson:"model-uuid"`
+	Token        string    `bson:"token"`
+	Completed    bool      `bson:"completed"`
+	AddedUnits   []string  `bson:"added-units,omitempty"`
+	RemovedUnits []string  `bson:"removed-units,omitempty"`
+	Expires      time.Time `bson:"expires"`
+}
+
+func (doc *scaleOperationDoc) operation() ScaleOperation {
+	return ScaleOperation{
+		Token:        doc.Token,
+		Completed:    doc.Completed,
+		AddedUnits:   doc.AddedUnits,
+		RemovedUnits: doc.RemovedUnits,
+	}
+}
+
+// StartScaleOperation records that a scale plan is being applied under
+// the given token. If the token has been used already, and has not
+// expired, an AlreadyExists error is returned along with the operation
+// recorded for it. Expired operations are removed.
+func (st *State) StartScaleOperation(token string) (ScaleOperation, error) {
+	if token == "" {
+		return ScaleOperation{}, errors.NotValidf("empty scale operation token")
+	}
+	coll, closer := st.db().GetCollection(scaleOperationsC)
+	defer closer()
+
+	var existing ScaleOperation
+	buildTxn := func(int) ([]txn.Op, error) {
+		now := st.clock().Now()
+		expires := now.Add(ScaleOperationExpiry).UTC().Round(time.Second)
+		var ops []txn.Op
+		var doc scaleOperationDoc
+		err := coll.FindId(token).One(&doc)
+		switch {
+		case err == mgo.ErrNotFound:
+			ops = append(ops, txn.Op{
+				C:      scaleOperationsC,
+				Id:     token,
+				Assert: txn.DocMissing,
+				Insert: &scaleOperationDoc{
+					Token:   token,
+					Expires: expires,
+				},
+			})
+		case err != nil:
+			return nil, errors.Trace(err)
+		case now.Before(doc.Expires):
+			existing = doc.operation()
+			return nil, errors.AlreadyExistsf("scale operation %q", token)
+		default:
+			// The token has expired, so it can be used again.
+			ops = append(ops, txn.Op{
+				C:      scaleOperationsC,
+				Id:     token,
+				Assert: bson.D{{"expires", doc.Expires}},
+				Update: bson.D{
+					{"$set", bson.D{{"completed", false}, {"expires", expires}}},
+					{"$unset", bson.D{{"added-units", nil}, {"removed-units", nil}}},
+				},
+			})
+		}
+
+		var expired []scaleOperationDoc
+		err = coll.Find(bson.D{
+			{"expires", bson.D{{"$lte", now}}},
+			{"token", bson.D{{"$ne", token}}},
+		}).Select(bson.D{{"_id", 1}}).All(&expired)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, doc := range expired {
+			ops = append(ops, txn.Op{
+				C:      scaleOperationsC,
+				Id:     doc.DocID,
+				Remove: true,
+			})
+		}
+		return ops, nil
+	}
+	if err := st.db().Run(buildTxn); err != nil {
+		if errors.IsAlreadyExists(err) {
+			return existing, errors.Trace(err)
+		}
+		return ScaleOperation{}, errors.Annotatef(err, "cannot start scale operation %q", token)
+	}
+	return ScaleOperation{Token: token}, nil
+}
+
+// CompleteScaleOperation records the units added and removed by the
+// scale operation with the given token.
+func (st *State) CompleteScaleOperation(token string, addedUnits, removedUnits []string) error {
+	ops := []txn.Op{{
+		C:      scaleOperationsC,
+		Id:     token,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{
+			{"completed", true},
+			{"added-units", addedUnits},
+			{"removed-units", removedUnits},
+		}}},
+	}}
+	if err := st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("scale operation %q", token)
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot complete scale operation %q", token)
+	}
+	return nil
+}
+
+// AbortScaleOperation forgets the scale operation with the given token,
+// so that the token can be used again. It is used when a scale plan
+// could not be applied in full.
+func (st *State) AbortScaleOperation(token string) error {
+	ops := []txn.Op{{
+		C:      scaleOperationsC,
+		Id:     token,
+		Remove: true,
+	}}
+	if err := st.db().RunTransaction(ops); err != nil {
+		return errors.Annotatef(err, "cannot abort scale operation %q", token)
+	}
+	return nil
+}
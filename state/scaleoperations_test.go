@@ -0,0 +1,87 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type ScaleOperationSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&ScaleOperationSuite{})
+
+func (s *ScaleOperationSuite) TestStartAndComplete(c *gc.C) {
+	op, err := s.State.StartScaleOperation("token-1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op, jc.DeepEquals, state.ScaleOperation{Token: "token-1"})
+
+	err = s.State.CompleteScaleOperation("token-1", []string{"mysql/1"}, []string{"wordpress/0"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	op, err = s.State.StartScaleOperation("token-1")
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+	c.Assert(err, gc.ErrorMatches, `scale operation "token-1" already exists`)
+	c.Assert(op, jc.DeepEquals, state.ScaleOperation{
+		Token:        "token-1",
+		Completed:    true,
+		AddedUnits:   []string{"mysql/1"},
+		RemovedUnits: []string{"wordpress/0"},
+	})
+}
+
+func (s *ScaleOperationSuite) TestStartInProgress(c *gc.C) {
+	_, err := s.State.StartScaleOperation("token-1")
+	c.Assert(err, jc.ErrorIsNil)
+
+	op, err := s.State.StartScaleOperation("token-1")
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+	c.Assert(op, jc.DeepEquals, state.ScaleOperation{Token: "token-1"})
+}
+
+func (s *ScaleOperationSuite) TestStartEmptyToken(c *gc.C) {
+	_, err := s.State.StartScaleOperation("")
+	c.Assert(err, gc.ErrorMatches, "empty scale operation token not valid")
+}
+
+func (s *ScaleOperationSuite) TestAbort(c *gc.C) {
+	_, err := s.State.StartScaleOperation("token-1")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AbortScaleOperation("token-1")
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.StartScaleOperation("token-1")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ScaleOperationSuite) TestCompleteNotFound(c *gc.C) {
+	err := s.State.CompleteScaleOperation("token-1", nil, nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ScaleOperationSuite) TestExpired(c *gc.C) {
+	_, err := s.State.StartScaleOperation("token-1")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.CompleteScaleOperation("token-1", []string{"mysql/1"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.StartScaleOperation("token-2")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.Clock.Advance(state.ScaleOperationExpiry + time.Second)
+
+	op, err := s.State.StartScaleOperation("token-1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op, jc.DeepEquals, state.ScaleOperation{Token: "token-1"})
+
+	// Other expired operations are removed.
+	err = s.State.CompleteScaleOperation("token-2", nil, nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
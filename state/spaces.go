@@ -4,14 +4,19 @@
 package state
 
 import (
+	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
 	"gopkg.in/juju/names.v3"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 	"gopkg.in/mgo.v2/txn"
 
+	jujucontroller "github.com/juju/juju/controller"
 	"github.com/juju/juju/core/network"
 )
 
@@ -317,6 +322,246 @@ func (s *Space) Remove() (err error) {
 	return onAbort(txnErr, errors.New("not found or not dead"))
 }
 
+// Rename changes the name of the space to toName. The constraints and
+// endpoint bindings that refer to the space by name are updated in the same
+// transaction, as are the controller config space settings if the space
+// belongs to the controller model.
+func (s *Space) Rename(toName string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot rename space %q to %q", s, toName)
+
+	if s.doc.Id == network.DefaultSpaceId {
+		return errors.New("the default space cannot be renamed")
+	}
+	if !names.IsValidSpace(toName) {
+		return errors.NewNotValid(nil, "invalid space name")
+	}
+
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := s.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if s.doc.Life != Alive {
+			return nil, spaceNotAliveErr
+		}
+		if s.doc.Name == toName {
+			return nil, jujutxn.ErrNoOperations
+		}
+		if _, err := s.st.Space(toName); err == nil {
+			return nil, errors.AlreadyExistsf("space %q", toName)
+		} else if !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
+		ops, err := s.renameOps(toName)
+		return ops, errors.Trace(err)
+	}
+
+	if err := s.st.db().Run(buildTxn); err != nil {
+		return errors.Trace(err)
+	}
+	s.doc.Name = toName
+	return nil
+}
+
+// renameOps returns the transaction operations required to rename
+// the space, along with every reference to it by name.
+func (s *Space) renameOps(toName string) ([]txn.Op, error) {
+	fromName := s.doc.Name
+	ops := []txn.Op{{
+		C:      spacesC,
+		Id:     s.doc.DocId,
+		Assert: bson.D{{"name", fromName}, {"life", Alive}},
+		Update: bson.D{{"$set", bson.D{{"name", toName}}}},
+	}}
+
+	constraintsOps, err := rewriteSpaceConstraintsOps(s.st, fromName, toName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops = append(ops, constraintsOps...)
+
+	bindingsOps, err := rewriteSpaceEndpointBindingsOps(s.st, fromName, toName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops = append(ops, bindingsOps...)
+
+	if s.st.IsController() {
+		settingsOps, err := rewriteSpaceControllerConfigOps(s.st, fromName, toName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, settingsOps...)
+	}
+	return ops, nil
+}
+
+// RemoveSpace removes the named space, moving its subnets to the default
+// space. Removal is refused while constraints, endpoint bindings, expose
+// settings or controller config settings refer to the space, unless force
+// is true, in which case those references are rewritten to use the default
+// space.
+func (st *State) RemoveSpace(name string, force bool) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot remove space %q", name)
+
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		space, err := st.Space(name)
+		if errors.IsNotFound(err) && attempt > 0 {
+			return nil, jujutxn.ErrNoOperations
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if space.Id() == network.DefaultSpaceId {
+			return nil, errors.New("the default space cannot be removed")
+		}
+		if !force {
+			dependents, err := space.dependents()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if len(dependents) > 0 {
+				return nil, errors.Errorf("space is still used by %s", strings.Join(dependents, ", "))
+			}
+		}
+		ops, err := space.removeOps()
+		return ops, errors.Trace(err)
+	}
+	return errors.Trace(st.db().Run(buildTxn))
+}
+
+// dependents returns descriptions of the constraints, endpoint bindings
+// and controller config settings that refer to the space by name, and of
+// the expose settings that refer to it by ID.
+func (s *Space) dependents() ([]string, error) {
+	name := s.doc.Name
+	var dependents []string
+
+	consDocs, err := constraintsReferencingSpace(s.st, name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, doc := range consDocs {
+		dependents = append(dependents, fmt.Sprintf("%s constraints", dependentName(s.st.localID(doc.DocID))))
+	}
+
+	bindingsDocs, err := bindingsReferencingSpace(s.st, name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, doc := range bindingsDocs {
+		var endpoints []string
+		for endpoint, space := range doc.Bindings {
+			if space == name {
+				endpoints = append(endpoints, fmt.Sprintf("%q", endpoint))
+			}
+		}
+		sort.Strings(endpoints)
+		dependents = append(dependents, fmt.Sprintf("%s endpoint bindings (%s)",
+			dependentName(s.st.localID(doc.DocID)), strings.Join(endpoints, ", ")))
+	}
+
+	exposeDocs, err := exposeSettingsReferencingSpace(s.st, s.doc.Id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, doc := range exposeDocs {
+		var endpoints []string
+		for _, endpoint := range exposedEndpointsReferencingSpace(doc, s.doc.Id) {
+			endpoints = append(endpoints, fmt.Sprintf("%q", endpoint))
+		}
+		dependents = append(dependents, fmt.Sprintf("%s expose settings (%s)",
+			dependentName(applicationGlobalKey(doc.Name)), strings.Join(endpoints, ", ")))
+	}
+
+	if s.st.IsController() {
+		cfg, err := s.st.ControllerConfig()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if cfg.JujuHASpace() == name {
+			dependents = append(dependents, fmt.Sprintf("controller config %s", jujucontroller.JujuHASpace))
+		}
+		if cfg.JujuManagementSpace() == name {
+			dependents = append(dependents, fmt.Sprintf("controller config %s", jujucontroller.JujuManagementSpace))
+		}
+	}
+
+	sort.Strings(dependents)
+	return dependents, nil
+}
+
+// dependentName returns a tag-like name for the entity with the given
+// global key, or the key itself if it is not recognised.
+func dependentName(key string) string {
+	if key == modelGlobalKey {
+		return "model"
+	}
+	if tag, ok := tagForGlobalKey(key); ok {
+		return tag
+	}
+	return key
+}
+
+// removeOps returns the transaction operations required to remove the
+// space, moving its subnets to the default space and rewriting every
+// reference to it by name to use the default space.
+func (s *Space) removeOps() ([]txn.Op, error) {
+	name := s.doc.Name
+	ops := []txn.Op{{
+		C:      spacesC,
+		Id:     s.doc.DocId,
+		Assert: bson.D{{"name", name}},
+		Remove: true,
+	}}
+	if s.ProviderId() != "" {
+		ops = append(ops, s.st.networkEntityGlobalKeyRemoveOp("space", s.ProviderId()))
+	}
+
+	subnets, closer := s.st.db().GetCollection(subnetsC)
+	defer closer()
+
+	var subnetDocs []subnetDoc
+	if err := subnets.Find(bson.D{{"space-id", s.doc.Id}}).All(&subnetDocs); err != nil {
+		return nil, errors.Annotate(err, "cannot read subnets")
+	}
+	for _, doc := range subnetDocs {
+		ops = append(ops, txn.Op{
+			C:      subnetsC,
+			Id:     doc.DocID,
+			Assert: bson.D{{"txn-revno", doc.TxnRevno}},
+			Update: bson.D{{"$set", bson.D{{"space-id", network.DefaultSpaceId}}}},
+		})
+	}
+
+	constraintsOps, err := rewriteSpaceConstraintsOps(s.st, name, "")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops = append(ops, constraintsOps...)
+
+	bindingsOps, err := rewriteSpaceEndpointBindingsOps(s.st, name, network.DefaultSpaceName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops = append(ops, bindingsOps...)
+
+	exposeOps, err := rewriteSpaceExposeSettingsOps(s.st, s.doc.Id, network.DefaultSpaceId)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops = append(ops, exposeOps...)
+
+	if s.st.IsController() {
+		settingsOps, err := rewriteSpaceControllerConfigOps(s.st, name, "")
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, settingsOps...)
+	}
+	return ops, nil
+}
+
 // Refresh: refreshes the contents of the Space from the underlying state. It
 // returns an error that satisfies errors.IsNotFound if the Space has been
 // removed.
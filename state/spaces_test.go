@@ -11,6 +11,8 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/state"
 )
@@ -582,6 +584,174 @@ func (s *SpacesSuite) TestRefreshFailsWithNotFoundWhenRemoved(c *gc.C) {
 	s.assertSpaceNotFoundError(c, err, "soon-removed")
 }
 
+func (s *SpacesSuite) TestRenameUpdatesReferences(c *gc.C) {
+	space := s.addAliveSpace(c, "db")
+	s.addAliveSpace(c, "public")
+
+	err := s.State.SetModelConstraints(constraints.MustParse("spaces=db,public"))
+	c.Assert(err, jc.ErrorIsNil)
+	app := s.AddTestingApplicationWithBindings(c, "mysql", s.AddTestingCharm(c, "mysql"), map[string]string{
+		"":       "db",
+		"server": "db",
+	})
+	err = app.SetConstraints(constraints.MustParse("spaces=^db"))
+	c.Assert(err, jc.ErrorIsNil)
+	space, err := s.State.Space("db")
+	c.Assert(err, jc.ErrorIsNil)
+	err = app.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"server": {ExposeToSpaceIDs: []string{space.Id()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.UpdateControllerConfig(map[string]interface{}{
+		controller.JujuHASpace: "db",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = space.Rename("database")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(space.Name(), gc.Equals, "database")
+
+	s.assertSpaceNotFound(c, "db")
+	renamed, err := s.State.Space("database")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(renamed.Id(), gc.Equals, space.Id())
+
+	cons, err := s.State.ModelConstraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*cons.Spaces, jc.DeepEquals, []string{"database", "public"})
+	cons, err = app.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*cons.Spaces, jc.DeepEquals, []string{"^database"})
+
+	bindings, err := app.EndpointBindings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(bindings[""], gc.Equals, "database")
+	c.Assert(bindings["server"], gc.Equals, "database")
+
+	cfg, err := s.State.ControllerConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.JujuHASpace(), gc.Equals, "database")
+}
+
+func (s *SpacesSuite) TestRenameFailsWhenNameTaken(c *gc.C) {
+	space := s.addAliveSpace(c, "db")
+	s.addAliveSpace(c, "public")
+
+	err := space.Rename("public")
+	c.Assert(err, gc.ErrorMatches, `cannot rename space "db" to "public": space "public" already exists`)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *SpacesSuite) TestRenameFailsForInvalidName(c *gc.C) {
+	space := s.addAliveSpace(c, "db")
+
+	err := space.Rename("-bad-")
+	c.Assert(err, gc.ErrorMatches, `cannot rename space "db" to "-bad-": invalid space name`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *SpacesSuite) TestRenameFailsForDefaultSpace(c *gc.C) {
+	space, err := s.State.SpaceByID(network.DefaultSpaceId)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = space.Rename("default")
+	c.Assert(err, gc.ErrorMatches, `cannot rename space "" to "default": the default space cannot be renamed`)
+}
+
+func (s *SpacesSuite) TestRenameFailsWhenNotAlive(c *gc.C) {
+	space := s.addAliveSpace(c, "db")
+	s.ensureDeadAndAssertLifeIsDead(c, space)
+
+	err := space.Rename("database")
+	c.Assert(err, gc.ErrorMatches, `cannot rename space "db" to "database": space is not found or not alive`)
+}
+
+func (s *SpacesSuite) addSpaceDependents(c *gc.C) *state.Application {
+	err := s.State.SetModelConstraints(constraints.MustParse("spaces=db,public"))
+	c.Assert(err, jc.ErrorIsNil)
+	app := s.AddTestingApplicationWithBindings(c, "mysql", s.AddTestingCharm(c, "mysql"), map[string]string{
+		"":       "db",
+		"server": "db",
+	})
+	err = app.SetConstraints(constraints.MustParse("spaces=^db"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.UpdateControllerConfig(map[string]interface{}{
+		controller.JujuHASpace: "db",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	return app
+}
+
+func (s *SpacesSuite) TestRemoveSpaceMovesSubnetsToDefault(c *gc.C) {
+	_, err := s.addSpaceWithSubnets(c, addSpaceArgs{
+		Name:        "db",
+		SubnetCIDRs: []string{"10.0.0.0/24"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.RemoveSpace("db", false)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertSpaceNotFound(c, "db")
+
+	subnet, err := s.State.Subnet("10.0.0.0/24")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnet.SpaceID(), gc.Equals, network.DefaultSpaceId)
+}
+
+func (s *SpacesSuite) TestRemoveSpaceRefusedWhileInUse(c *gc.C) {
+	s.addAliveSpace(c, "db")
+	s.addAliveSpace(c, "public")
+	s.addSpaceDependents(c)
+
+	err := s.State.RemoveSpace("db", false)
+	c.Assert(err, gc.ErrorMatches, `cannot remove space "db": space is still used by `+
+		`application-mysql constraints, `+
+		`application-mysql endpoint bindings \("", "server"\), `+
+		`application-mysql expose settings \("server"\), `+
+		`controller config juju-ha-space, `+
+		`model constraints`)
+
+	_, err = s.State.Space("db")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *SpacesSuite) TestRemoveSpaceForceRewritesDependents(c *gc.C) {
+	s.addAliveSpace(c, "db")
+	s.addAliveSpace(c, "public")
+	app := s.addSpaceDependents(c)
+
+	err := s.State.RemoveSpace("db", true)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertSpaceNotFound(c, "db")
+
+	cons, err := s.State.ModelConstraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*cons.Spaces, jc.DeepEquals, []string{"public"})
+	cons, err = app.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cons.Spaces, gc.IsNil)
+
+	bindings, err := app.EndpointBindings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(bindings[""], gc.Equals, network.DefaultSpaceName)
+	c.Assert(bindings["server"], gc.Equals, network.DefaultSpaceName)
+
+	err = app.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(app.ExposedEndpoints(), jc.DeepEquals, map[string]state.ExposedEndpoint{
+		"server": {ExposeToSpaceIDs: []string{network.DefaultSpaceId}},
+	})
+
+	cfg, err := s.State.ControllerConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.JujuHASpace(), gc.Equals, "")
+}
+
+func (s *SpacesSuite) TestRemoveDefaultSpace(c *gc.C) {
+	err := s.State.RemoveSpace(network.DefaultSpaceName, true)
+	c.Assert(err, gc.ErrorMatches, `cannot remove space "": the default space cannot be removed`)
+}
+
 func (s *SpacesSuite) TestFanSubnetInheritsSpace(c *gc.C) {
 	args := addSpaceArgs{
 		Name:        "space1",
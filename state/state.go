@@ -337,6 +337,13 @@ func (st *State) removeAllModelDocs(modelAssertion bson.D) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	// Remove the API keys issued for the model, which are held in a
+	// global collection.
+	apiKeyOps, err := st.removeInCollectionOps(apiKeysC, bson.D{{"model-uuid", modelUUID}})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ops = append(ops, apiKeyOps...)
 	err = st.db().RunTransaction(ops)
 	if err != nil {
 		return errors.Trace(err)
@@ -363,6 +370,10 @@ func (st *State) removeAllModelDocs(modelAssertion bson.D) error {
 		Remove: true,
 	}}
 
+	if alias := model.Alias(); alias != "" {
+		ops = append(ops, removeModelAliasOp(alias))
+	}
+
 	// Decrement the model count for the cloud to which this model belongs.
 	decCloudRefOp, err := decCloudModelRefOp(st, model.Cloud())
 	if err != nil {
@@ -1816,6 +1827,18 @@ func (st *State) addMachineWithPlacement(unit *Unit, data *placementData) (*Mach
 		if locked {
 			return nil, errors.Errorf("machine hosting %q is locked for series upgrade", mId)
 		}
+		if machine.IsCordoned() {
+			return nil, errors.Errorf("machine %q is cordoned", mId)
+		}
+		if parentId, ok := machine.ParentId(); ok {
+			parent, err := st.Machine(parentId)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if parent.IsCordoned() {
+				return nil, errors.Errorf("machine hosting %q is cordoned", mId)
+			}
+		}
 	}
 
 	switch data.placementType() {
@@ -632,6 +632,32 @@ func eraseStatusHistory(mb modelBackend, globalKey string) error {
 	return nil
 }
 
+// trimStatusHistory removes all but the most recent keep status history
+// documents for the given global key.
+func trimStatusHistory(db Database, globalKey string, keep int) error {
+	history, closer := db.GetCollection(statusesHistoryC)
+	defer closer()
+
+	iter := history.Find(bson.D{{
+		globalKeyField, globalKey,
+	}}).Sort("-updated").Skip(keep).Select(bson.M{"_id": 1}).Iter()
+	defer iter.Close()
+
+	logFormat := "trimmed %d status history documents for " + fmt.Sprintf("%q", globalKey)
+	deleted, err := deleteInBatches(
+		history.Writeable().Underlying(), iter,
+		logFormat, loggo.DEBUG,
+		noEarlyFinish,
+	)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if deleted > 0 {
+		logger.Debugf(logFormat, deleted)
+	}
+	return nil
+}
+
 // statusHistoryArgs hold the arguments to call statusHistory.
 type statusHistoryArgs struct {
 	db        Database
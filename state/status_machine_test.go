@@ -76,6 +76,38 @@ func (s *MachineStatusSuite) TestSetUnknownStatus(c *gc.C) {
 	s.checkInitialStatus(c)
 }
 
+func (s *MachineStatusSuite) TestSetDrainingStatusAlive(c *gc.C) {
+	now := testing.ZeroTime()
+	sInfo := status.StatusInfo{
+		Status:  status.Draining,
+		Message: "flushing logs",
+		Since:   &now,
+	}
+	err := s.machine.SetStatus(sInfo)
+	c.Check(err, gc.ErrorMatches, `cannot set status "draining" while machine is alive`)
+
+	s.checkInitialStatus(c)
+}
+
+func (s *MachineStatusSuite) TestSetDrainingStatusDying(c *gc.C) {
+	err := s.machine.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	now := testing.ZeroTime()
+	sInfo := status.StatusInfo{
+		Status:  status.Draining,
+		Message: "flushing logs",
+		Since:   &now,
+	}
+	err = s.machine.SetStatus(sInfo)
+	c.Assert(err, jc.ErrorIsNil)
+
+	statusInfo, err := s.machine.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(statusInfo.Status, gc.Equals, status.Draining)
+	c.Check(statusInfo.Message, gc.Equals, "flushing logs")
+}
+
 func (s *MachineStatusSuite) TestSetOverwritesData(c *gc.C) {
 	now := testing.ZeroTime()
 	sInfo := status.StatusInfo{
@@ -74,8 +74,8 @@ func (s *Subnet) IsPublic() bool {
 	return s.doc.IsPublic
 }
 
-// EnsureDead sets the Life of the subnet to Dead, if it's Alive. If the subnet
-// is already Dead, no error is returned. When the subnet is no longer Alive or
+// EnsureDead sets the Life of the subnet to Dead, if it's Alive or Dying. If
+// the subnet is already Dead, no error is returned. When the subnet is
 // already removed, errNotAlive is returned.
 func (s *Subnet) EnsureDead() (err error) {
 	defer errors.DeferredAnnotatef(&err, "cannot set subnet %q to dead", s)
@@ -88,7 +88,7 @@ func (s *Subnet) EnsureDead() (err error) {
 		C:      subnetsC,
 		Id:     s.doc.DocID,
 		Update: bson.D{{"$set", bson.D{{"life", Dead}}}},
-		Assert: isAliveDoc,
+		Assert: notDeadDoc,
 	}}
 
 	txnErr := s.st.db().RunTransaction(ops)
@@ -127,6 +127,150 @@ func (s *Subnet) Remove() (err error) {
 	return onAbort(txnErr, errors.New("not found or not dead"))
 }
 
+// SubnetReferences counts the entities that still refer to a subnet and
+// so prevent a Dying subnet from becoming Dead.
+type SubnetReferences struct {
+	// Machines is the number of machines with addresses in the subnet.
+	Machines int
+
+	// Ports is the number of unit ports documents opened on the subnet.
+	Ports int
+
+	// Bindings is the number of endpoint bindings to the subnet's space,
+	// when the subnet is the last one alive in that space.
+	Bindings int
+}
+
+// Total returns the number of references of any kind.
+func (r SubnetReferences) Total() int {
+	return r.Machines + r.Ports + r.Bindings
+}
+
+// References returns the machines, ports documents and endpoint bindings
+// that currently refer to the subnet.
+func (s *Subnet) References() (_ SubnetReferences, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot get references to subnet %q", s)
+
+	var refs SubnetReferences
+
+	addresses, closer := s.st.db().GetCollection(ipAddressesC)
+	defer closer()
+	var machineIDs []string
+	if err := addresses.Find(bson.D{{"subnet-cidr", s.CIDR()}}).Distinct("machine-id", &machineIDs); err != nil {
+		return refs, errors.Trace(err)
+	}
+	refs.Machines = len(machineIDs)
+
+	openedPorts, closer := s.st.db().GetCollection(openedPortsC)
+	defer closer()
+	if refs.Ports, err = openedPorts.Find(bson.D{{"subnet-id", s.ID()}}).Count(); err != nil {
+		return refs, errors.Trace(err)
+	}
+
+	if refs.Bindings, err = s.bindingReferences(); err != nil {
+		return refs, errors.Trace(err)
+	}
+	return refs, nil
+}
+
+// bindingReferences returns the number of endpoints bound to the subnet's
+// space, if no other subnet in that space is alive. Endpoints bound to the
+// default space never depend on a single subnet.
+func (s *Subnet) bindingReferences() (int, error) {
+	if s.spaceID == "" || s.spaceID == network.DefaultSpaceId {
+		return 0, nil
+	}
+	space, err := s.st.SpaceByID(s.spaceID)
+	if errors.IsNotFound(err) {
+		return 0, nil
+	} else if err != nil {
+		return 0, errors.Trace(err)
+	}
+	subnets, err := space.Subnets()
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	for _, subnet := range subnets {
+		if subnet.ID() != s.ID() && subnet.Life() == Alive {
+			return 0, nil
+		}
+	}
+
+	docs, err := bindingsReferencingSpace(s.st, space.Name())
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	count := 0
+	for _, doc := range docs {
+		for _, name := range doc.Bindings {
+			if name == space.Name() {
+				count++
+			}
+		}
+	}
+	return count, nil
+}
+
+// Destroy sets the Life of the subnet to Dying, and schedules a cleanup
+// that sets it Dead and removes it once nothing refers to it any longer.
+// No ports can be opened on a Dying subnet, but existing ones can still
+// be closed. Destroying a Dying subnet schedules the cleanup again.
+func (s *Subnet) Destroy() (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot destroy subnet %q", s)
+
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := s.Refresh(); errors.IsNotFound(err) {
+				return nil, jujutxn.ErrNoOperations
+			} else if err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		switch s.doc.Life {
+		case Dead:
+			return nil, jujutxn.ErrNoOperations
+		case Dying:
+			return []txn.Op{{
+				C:      subnetsC,
+				Id:     s.doc.DocID,
+				Assert: isDyingDoc,
+			}, newCleanupOp(cleanupDyingSubnet, s.ID())}, nil
+		}
+		return []txn.Op{{
+			C:      subnetsC,
+			Id:     s.doc.DocID,
+			Assert: isAliveDoc,
+			Update: bson.D{{"$set", bson.D{{"life", Dying}}}},
+		}, newCleanupOp(cleanupDyingSubnet, s.ID())}, nil
+	}
+	if err := s.st.db().Run(buildTxn); err != nil {
+		return errors.Trace(err)
+	}
+	if s.doc.Life == Alive {
+		s.doc.Life = Dying
+	}
+	return nil
+}
+
+// dyingSubnetCleanupOps returns an op scheduling the removal of the subnet
+// with the given ID if it is Dying, so that dropping a reference to it
+// lets the subnet progress to Dead.
+func dyingSubnetCleanupOps(st *State, subnetID string) ([]txn.Op, error) {
+	if subnetID == "" {
+		return nil, nil
+	}
+	subnet, err := st.SubnetByID(subnetID)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if subnet.Life() != Dying {
+		return nil, nil
+	}
+	return []txn.Op{newCleanupOp(cleanupDyingSubnet, subnetID)}, nil
+}
+
 // ProviderId returns the provider-specific id of the subnet.
 func (s *Subnet) ProviderId() network.Id {
 	return network.Id(s.doc.ProviderId)
@@ -306,8 +450,11 @@ func (st *State) SubnetUpdate(args network.SubnetInfo) error {
 	return s.Update(args)
 }
 
-// AddSubnet creates and returns a new subnet.
+// AddSubnet creates and returns a new subnet. The CIDR is stored in
+// canonical form so that IPv6 subnets match the subnets recorded against
+// machine addresses, however the provider spells them.
 func (st *State) AddSubnet(args network.SubnetInfo) (subnet *Subnet, err error) {
+	args.CIDR = network.NormaliseCIDR(args.CIDR)
 	defer errors.DeferredAnnotatef(&err, "adding subnet %q", args.CIDR)
 
 	if err := args.Validate(); err != nil {
@@ -427,6 +574,7 @@ func (st *State) uniqueSubnet(cidr, providerID string) (bool, error) {
 //
 // Subnet returns the subnet specified by the cidr.
 func (st *State) Subnet(cidr string) (*Subnet, error) {
+	cidr = network.NormaliseCIDR(cidr)
 	return st.subnet(bson.M{"cidr": cidr}, cidr)
 }
 
@@ -105,6 +105,22 @@ func (s *SubnetSuite) TestAddSubnetFailsWithAlreadyExistsForDuplicateCIDRInSameM
 	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
 }
 
+func (s *SubnetSuite) TestAddSubnetNormalisesIPv6CIDR(c *gc.C) {
+	subnet, err := s.State.AddSubnet(network.SubnetInfo{CIDR: "2001:DB8:0:1:0::/64"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnet.CIDR(), gc.Equals, "2001:db8:0:1::/64")
+
+	for _, cidr := range []string{"2001:db8:0:1::/64", "2001:DB8:0:1::/64"} {
+		found, err := s.State.Subnet(cidr)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(found.ID(), gc.Equals, subnet.ID())
+	}
+
+	err = s.assertAddSubnetForInfoFailsWithSuffix(c, network.SubnetInfo{CIDR: "2001:db8:0:1::/64"},
+		`subnet "2001:db8:0:1::/64" already exists`)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
 func (s *SubnetSuite) TestAddSubnetSuccessForDuplicateCIDRDiffProviderIDInSameModel(c *gc.C) {
 	subnetInfo := network.SubnetInfo{CIDR: "192.168.0.1/24"}
 	subnet, err := s.State.AddSubnet(subnetInfo)
@@ -223,6 +239,80 @@ func (s *SubnetSuite) TestEnsureDeadSetsLifeToDeadWhenNotAlive(c *gc.C) {
 	s.ensureDeadAndAssertLifeIsDead(c, subnet)
 }
 
+func (s *SubnetSuite) TestEnsureDeadSetsLifeToDeadWhenDying(c *gc.C) {
+	subnet := s.addAliveSubnet(c, "192.168.0.1/24")
+	err := subnet.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnet.Life(), gc.Equals, state.Dying)
+
+	s.ensureDeadAndAssertLifeIsDead(c, subnet)
+	s.refreshAndAssertSubnetLifeIs(c, subnet, state.Dead)
+}
+
+func (s *SubnetSuite) TestDestroyUnreferencedSubnetIsRemovedByCleanup(c *gc.C) {
+	subnet := s.addAliveSubnet(c, "192.168.0.0/24")
+
+	err := subnet.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	s.refreshAndAssertSubnetLifeIs(c, subnet, state.Dying)
+
+	c.Assert(s.State.Cleanup(), jc.ErrorIsNil)
+	s.assertSubnetWithCIDRNotFound(c, "192.168.0.0/24")
+}
+
+func (s *SubnetSuite) TestDestroyReferencedSubnetStaysDying(c *gc.C) {
+	subnet := s.addAliveSubnet(c, "192.168.0.0/24")
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetLinkLayerDevices(state.LinkLayerDeviceArgs{
+		Name: "eth0",
+		Type: state.EthernetDevice,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetDevicesAddresses(state.LinkLayerDeviceAddress{
+		DeviceName:   "eth0",
+		ConfigMethod: state.StaticAddress,
+		CIDRAddress:  "192.168.0.5/24",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	refs, err := subnet.References()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(refs, jc.DeepEquals, state.SubnetReferences{Machines: 1})
+
+	err = subnet.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.State.Cleanup(), jc.ErrorIsNil)
+	s.refreshAndAssertSubnetLifeIs(c, subnet, state.Dying)
+
+	err = machine.RemoveAllAddresses()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.State.Cleanup(), jc.ErrorIsNil)
+	s.assertSubnetWithCIDRNotFound(c, "192.168.0.0/24")
+}
+
+func (s *SubnetSuite) TestReferencesCountsBindingsOnLastSubnetInSpace(c *gc.C) {
+	subnet := s.addAliveSubnet(c, "10.0.0.0/24")
+	other := s.addAliveSubnet(c, "10.0.1.0/24")
+	_, err := s.State.AddSpace("db", "", []string{"10.0.0.0/24", "10.0.1.0/24"}, false)
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddTestingApplicationWithBindings(c, "mysql", s.AddTestingCharm(c, "mysql"), map[string]string{
+		"server": "db",
+	})
+	err = subnet.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+
+	refs, err := subnet.References()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(refs.Bindings, gc.Equals, 0)
+
+	err = other.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	refs, err = subnet.References()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(refs.Bindings, gc.Equals, 1)
+}
+
 func (s *SubnetSuite) TestRemoveFailsIfStillAlive(c *gc.C) {
 	subnet := s.addAliveSubnet(c, "192.168.0.1/24")
 
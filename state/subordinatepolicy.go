@@ -0,0 +1,250 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// SubordinatePolicy controls which principal units a subordinate
+// application attaches to when its container-scoped relations are
+// entered. The zero value attaches to every principal unit.
+type SubordinatePolicy struct {
+	// Principals, if not empty, holds the names of the only principal
+	// applications whose units the subordinate attaches to.
+	Principals []string
+
+	// MachineSelector, if not empty, holds annotations that the machine
+	// hosting a principal unit must carry for the subordinate to
+	// attach to it.
+	MachineSelector map[string]string
+
+	// MaxPerMachine, if positive, limits the number of the subordinate's
+	// units on any one machine.
+	MaxPerMachine int
+}
+
+// IsZero reports whether the policy places no restrictions on the
+// subordinate.
+func (p SubordinatePolicy) IsZero() bool {
+	return len(p.Principals) == 0 && len(p.MachineSelector) == 0 && p.MaxPerMachine == 0
+}
+
+// Validate returns an error if the policy is not valid.
+func (p SubordinatePolicy) Validate() error {
+	for _, name := range p.Principals {
+		if !names.IsValidApplication(name) {
+			return errors.NotValidf("principal application name %q", name)
+		}
+	}
+	for key := range p.MachineSelector {
+		if key == "" || strings.Contains(key, "=") {
+			return errors.NotValidf("machine selector key %q", key)
+		}
+	}
+	if p.MaxPerMachine < 0 {
+		return errors.NotValidf("negative max per machine %d", p.MaxPerMachine)
+	}
+	return nil
+}
+
+// subordinatePolicyDoc is the persistent form of a SubordinatePolicy.
+// The machine selector is stored as sorted "key=value" pairs, since
+// annotation keys may contain characters that are not valid in
+// document field names.
+type subordinatePolicyDoc struct {
+	Principals      []string `bson:"principals,omitempty"`
+	MachineSelector []string `bson:"machine-selector,omitempty"`
+	MaxPerMachine   int      `bson:"max-per-machine,omitempty"`
+}
+
+func newSubordinatePolicyDoc(p SubordinatePolicy) *subordinatePolicyDoc {
+	if p.IsZero() {
+		return nil
+	}
+	doc := &subordinatePolicyDoc{
+		Principals:    set.NewStrings(p.Principals...).SortedValues(),
+		MaxPerMachine: p.MaxPerMachine,
+	}
+	for key, value := range p.MachineSelector {
+		doc.MachineSelector = append(doc.MachineSelector, key+"="+value)
+	}
+	sort.Strings(doc.MachineSelector)
+	return doc
+}
+
+func (doc *subordinatePolicyDoc) policy() SubordinatePolicy {
+	if doc == nil {
+		return SubordinatePolicy{}
+	}
+	p := SubordinatePolicy{
+		Principals:    doc.Principals,
+		MaxPerMachine: doc.MaxPerMachine,
+	}
+	if len(doc.MachineSelector) > 0 {
+		p.MachineSelector = make(map[string]string)
+		for _, pair := range doc.MachineSelector {
+			kv := strings.SplitN(pair, "=", 2)
+			p.MachineSelector[kv[0]] = kv[1]
+		}
+	}
+	return p
+}
+
+// SubordinatePolicy returns the placement policy of the subordinate
+// application.
+func (a *Application) SubordinatePolicy() SubordinatePolicy {
+	return a.doc.SubordinatePolicy.policy()
+}
+
+// SetSubordinatePolicy sets the placement policy of the subordinate
+// application. The policy is consulted only when a principal unit
+// enters a container-scoped relation with the application; existing
+// subordinate units are not affected.
+func (a *Application) SetSubordinatePolicy(policy SubordinatePolicy) error {
+	if !a.doc.Subordinate {
+		return errors.Errorf("cannot set subordinate policy: application %q is not a subordinate", a.doc.Name)
+	}
+	if err := policy.Validate(); err != nil {
+		return errors.Annotate(err, "cannot set subordinate policy")
+	}
+	doc := newSubordinatePolicyDoc(policy)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			alive, err := isAlive(a.st, applicationsC, a.doc.DocID)
+			if err != nil {
+				return nil, errors.Trace(err)
+			} else if !alive {
+				return nil, applicationNotAliveErr
+			}
+		}
+		var update bson.D
+		if doc == nil {
+			update = bson.D{{"$unset", bson.D{{"subordinate-policy", nil}}}}
+		} else {
+			update = bson.D{{"$set", bson.D{{"subordinate-policy", doc}}}}
+		}
+		return []txn.Op{{
+			C:      applicationsC,
+			Id:     a.doc.DocID,
+			Assert: isAliveDoc,
+			Update: update,
+		}}, nil
+	}
+	if err := a.st.db().Run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot set subordinate policy")
+	}
+	a.doc.SubordinatePolicy = doc
+	return nil
+}
+
+// subordinateAllowed reports whether the subordinate application's
+// placement policy allows a new subordinate unit to be attached to the
+// named principal unit of the named principal application. When the
+// policy limits the units per machine, the returned operations assert
+// that the count it was checked against has not changed.
+func (a *Application) subordinateAllowed(principalApp, principalUnit string) (bool, []txn.Op, error) {
+	policy := a.SubordinatePolicy()
+	if policy.IsZero() {
+		return true, nil, nil
+	}
+	if len(policy.Principals) > 0 && !set.NewStrings(policy.Principals...).Contains(principalApp) {
+		return false, nil, nil
+	}
+	if len(policy.MachineSelector) == 0 && policy.MaxPerMachine == 0 {
+		return true, nil, nil
+	}
+	unit, err := a.st.Unit(principalUnit)
+	if err != nil {
+		return false, nil, errors.Trace(err)
+	}
+	machineId, err := unit.AssignedMachineId()
+	if errors.IsNotAssigned(err) {
+		// Units without machines, such as those in CAAS models, are
+		// not subject to the machine-based restrictions.
+		return true, nil, nil
+	} else if err != nil {
+		return false, nil, errors.Trace(err)
+	}
+	machine, err := a.st.Machine(machineId)
+	if err != nil {
+		return false, nil, errors.Trace(err)
+	}
+	if len(policy.MachineSelector) > 0 {
+		model, err := a.st.Model()
+		if err != nil {
+			return false, nil, errors.Trace(err)
+		}
+		annotations, err := model.Annotations(machine)
+		if err != nil {
+			return false, nil, errors.Trace(err)
+		}
+		for key, value := range policy.MachineSelector {
+			if actual, ok := annotations[key]; !ok || actual != value {
+				return false, nil, nil
+			}
+		}
+	}
+	if policy.MaxPerMachine == 0 {
+		return true, nil, nil
+	}
+	return a.maxPerMachineOps(machine, principalUnit, policy.MaxPerMachine)
+}
+
+// maxPerMachineOps reports whether the machine has room for another
+// unit of the subordinate application, and returns operations that
+// assert no other principal unit on the machine gains one meanwhile.
+func (a *Application) maxPerMachineOps(machine *Machine, principalUnit string, max int) (bool, []txn.Op, error) {
+	units, closer := a.st.db().GetCollection(unitsC)
+	defer closer()
+	principals := machine.Principals()
+	var docs []struct {
+		Principal string `bson:"principal"`
+	}
+	err := units.Find(bson.D{
+		{"application", a.doc.Name},
+		{"principal", bson.D{{"$in", principals}}},
+		{"life", bson.D{{"$ne", Dead}}},
+	}).Select(bson.D{{"principal", 1}}).All(&docs)
+	if err != nil {
+		return false, nil, errors.Trace(err)
+	}
+	if len(docs) >= max {
+		return false, nil, nil
+	}
+	attached := set.NewStrings(principalUnit)
+	for _, doc := range docs {
+		attached.Add(doc.Principal)
+	}
+	ops := []txn.Op{{
+		C:  machinesC,
+		Id: machine.doc.DocID,
+		Assert: bson.D{{"principals", bson.D{
+			{"$size", len(principals)},
+			{"$all", principals},
+		}}},
+	}}
+	noSubordinate := bson.D{{"subordinates", bson.D{
+		{"$not", bson.RegEx{Pattern: "^" + regexp.QuoteMeta(a.doc.Name) + "/"}},
+	}}}
+	for _, name := range principals {
+		if attached.Contains(name) {
+			continue
+		}
+		ops = append(ops, txn.Op{
+			C:      unitsC,
+			Id:     a.st.docID(name),
+			Assert: noSubordinate,
+		})
+	}
+	return true, ops, nil
+}
@@ -0,0 +1,155 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type SubordinatePolicySuite struct {
+	ConnSuite
+	mysql   *state.Application
+	logging *state.Application
+	rel     *state.Relation
+}
+
+var _ = gc.Suite(&SubordinatePolicySuite{})
+
+func (s *SubordinatePolicySuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.mysql = s.AddTestingApplication(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	s.logging = s.AddTestingApplication(c, "logging", s.AddTestingCharm(c, "logging"))
+	eps, err := s.State.InferEndpoints("mysql", "logging")
+	c.Assert(err, jc.ErrorIsNil)
+	s.rel, err = s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *SubordinatePolicySuite) addPrincipal(c *gc.C, machine *state.Machine) *state.Unit {
+	unit, err := s.mysql.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+	return unit
+}
+
+func (s *SubordinatePolicySuite) enterScope(c *gc.C, unit *state.Unit) {
+	ru, err := s.rel.Unit(unit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ru.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	assertInScope(c, ru)
+}
+
+func (s *SubordinatePolicySuite) assertSubordinateCount(c *gc.C, expect int) {
+	units, err := s.logging.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, expect)
+}
+
+func (s *SubordinatePolicySuite) TestSetSubordinatePolicy(c *gc.C) {
+	c.Assert(s.logging.SubordinatePolicy().IsZero(), jc.IsTrue)
+	policy := state.SubordinatePolicy{
+		Principals:      []string{"mysql", "wordpress"},
+		MachineSelector: map[string]string{"logging.example.com/enabled": "true"},
+		MaxPerMachine:   1,
+	}
+	err := s.logging.SetSubordinatePolicy(policy)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.logging.SubordinatePolicy(), jc.DeepEquals, policy)
+
+	app, err := s.State.Application("logging")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(app.SubordinatePolicy(), jc.DeepEquals, policy)
+
+	err = app.SetSubordinatePolicy(state.SubordinatePolicy{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.logging.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.logging.SubordinatePolicy().IsZero(), jc.IsTrue)
+}
+
+func (s *SubordinatePolicySuite) TestSetSubordinatePolicyNotSubordinate(c *gc.C) {
+	err := s.mysql.SetSubordinatePolicy(state.SubordinatePolicy{MaxPerMachine: 1})
+	c.Assert(err, gc.ErrorMatches, `cannot set subordinate policy: application "mysql" is not a subordinate`)
+}
+
+func (s *SubordinatePolicySuite) TestSetSubordinatePolicyInvalid(c *gc.C) {
+	err := s.logging.SetSubordinatePolicy(state.SubordinatePolicy{MaxPerMachine: -1})
+	c.Assert(err, gc.ErrorMatches, `cannot set subordinate policy: negative max per machine -1 not valid`)
+	err = s.logging.SetSubordinatePolicy(state.SubordinatePolicy{Principals: []string{"Bad_Name"}})
+	c.Assert(err, gc.ErrorMatches, `cannot set subordinate policy: principal application name "Bad_Name" not valid`)
+	err = s.logging.SetSubordinatePolicy(state.SubordinatePolicy{MachineSelector: map[string]string{"": "x"}})
+	c.Assert(err, gc.ErrorMatches, `cannot set subordinate policy: machine selector key "" not valid`)
+}
+
+func (s *SubordinatePolicySuite) TestPrincipalsAllowList(c *gc.C) {
+	err := s.logging.SetSubordinatePolicy(state.SubordinatePolicy{Principals: []string{"wordpress"}})
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.mysql.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.enterScope(c, unit)
+	s.assertSubordinateCount(c, 0)
+}
+
+func (s *SubordinatePolicySuite) TestMachineSelector(c *gc.C) {
+	err := s.logging.SetSubordinatePolicy(state.SubordinatePolicy{
+		MachineSelector: map[string]string{"logging": "enabled"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	machine0 := s.Factory.MakeMachine(c, nil)
+	machine1 := s.Factory.MakeMachine(c, nil)
+	err = s.Model.SetAnnotations(machine1, map[string]string{"logging": "enabled"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.enterScope(c, s.addPrincipal(c, machine0))
+	s.assertSubordinateCount(c, 0)
+
+	s.enterScope(c, s.addPrincipal(c, machine1))
+	s.assertSubordinateCount(c, 1)
+}
+
+func (s *SubordinatePolicySuite) TestMaxPerMachine(c *gc.C) {
+	err := s.logging.SetSubordinatePolicy(state.SubordinatePolicy{MaxPerMachine: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	machine := s.Factory.MakeMachine(c, nil)
+
+	s.enterScope(c, s.addPrincipal(c, machine))
+	s.assertSubordinateCount(c, 1)
+
+	s.enterScope(c, s.addPrincipal(c, machine))
+	s.assertSubordinateCount(c, 1)
+}
+
+func (s *SubordinatePolicySuite) TestMaxPerMachineConcurrent(c *gc.C) {
+	err := s.logging.SetSubordinatePolicy(state.SubordinatePolicy{MaxPerMachine: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	machine := s.Factory.MakeMachine(c, nil)
+	unit0 := s.addPrincipal(c, machine)
+	unit1 := s.addPrincipal(c, machine)
+	ru1, err := s.rel.Unit(unit1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	defer state.SetBeforeHooks(c, s.State, func() {
+		s.enterScope(c, unit0)
+	}).Check()
+	err = ru1.EnterScope(nil)
+	c.Assert(err, gc.Equals, state.ErrCannotEnterScopeYet)
+	s.assertSubordinateCount(c, 1)
+
+	// Entering again evaluates the policy afresh.
+	s.enterScope(c, unit1)
+	s.assertSubordinateCount(c, 1)
+}
+
+func (s *SubordinatePolicySuite) TestNoPolicyAttachesToAll(c *gc.C) {
+	machine := s.Factory.MakeMachine(c, nil)
+	s.enterScope(c, s.addPrincipal(c, machine))
+	s.enterScope(c, s.addPrincipal(c, machine))
+	s.assertSubordinateCount(c, 2)
+}
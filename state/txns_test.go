@@ -5,9 +5,11 @@ package state
 
 import (
 	"errors"
+	"sync"
 
 	jc "github.com/juju/testing/checkers"
 	jujutxn "github.com/juju/txn"
+	"go.opencensus.io/trace"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/mgo.v2/bson"
 	"gopkg.in/mgo.v2/txn"
@@ -511,3 +513,83 @@ func txFromOps(ops []txn.Op) *jujutxn.Transaction {
 		Ops: ops,
 	}
 }
+
+type TxnSpanSuite struct {
+	testing.BaseSuite
+	db    *database
+	spans *spanRecorder
+}
+
+var _ = gc.Suite(&TxnSpanSuite{})
+
+func (s *TxnSpanSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.db = &database{
+		runner:    &recordingRunner{},
+		modelUUID: modelUUID,
+		schema: CollectionSchema{
+			"other": {global: true},
+			"raw":   {global: true},
+		},
+	}
+	s.spans = &spanRecorder{}
+	trace.RegisterExporter(s.spans)
+	trace.ApplyConfig(trace.Config{DefaultSampler: trace.AlwaysSample()})
+	s.AddCleanup(func(*gc.C) {
+		trace.UnregisterExporter(s.spans)
+		trace.ApplyConfig(trace.Config{DefaultSampler: trace.ProbabilitySampler(1e-4)})
+	})
+}
+
+func (s *TxnSpanSuite) TestRunTransactionSpan(c *gc.C) {
+	err := s.db.RunTransaction([]txn.Op{
+		{C: "other", Id: "a", Insert: bson.M{}},
+		{C: "raw", Id: "b", Insert: bson.M{}},
+		{C: "other", Id: "c", Insert: bson.M{}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	spans := s.spans.get()
+	c.Assert(spans, gc.HasLen, 1)
+	c.Assert(spans[0].Name, gc.Equals, "state.RunTransaction")
+	c.Assert(spans[0].Attributes, jc.DeepEquals, map[string]interface{}{
+		"model-uuid":  modelUUID,
+		"ops":         int64(3),
+		"collections": "other,raw",
+	})
+	c.Assert(spans[0].Status.Code, gc.Equals, int32(trace.StatusCodeOK))
+}
+
+func (s *TxnSpanSuite) TestRunSpanRecordsError(c *gc.C) {
+	err := s.db.Run(func(int) ([]txn.Op, error) {
+		return nil, errors.New("boom")
+	})
+	c.Assert(err, gc.ErrorMatches, "boom")
+
+	spans := s.spans.get()
+	c.Assert(spans, gc.HasLen, 1)
+	c.Assert(spans[0].Name, gc.Equals, "state.Run")
+	c.Assert(spans[0].Attributes["attempts"], gc.Equals, int64(testTxnAttempt+1))
+	c.Assert(spans[0].Status, jc.DeepEquals, trace.Status{
+		Code:    trace.StatusCodeUnknown,
+		Message: "boom",
+	})
+}
+
+// spanRecorder is a trace.Exporter which records the spans it is given.
+type spanRecorder struct {
+	mu    sync.Mutex
+	spans []*trace.SpanData
+}
+
+func (r *spanRecorder) ExportSpan(s *trace.SpanData) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans = append(r.spans, s)
+}
+
+func (r *spanRecorder) get() []*trace.SpanData {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.spans
+}
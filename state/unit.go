@@ -5,6 +5,7 @@ package state
 
 import (
 	"fmt"
+	"net"
 	"reflect"
 	"sort"
 	"strconv"
@@ -96,6 +97,23 @@ type unitDoc struct {
 	Life                   Life
 	TxnRevno               int64 `bson:"txn-revno"`
 	PasswordHash           string
+
+	// EgressAddresses holds the addresses, as reported by the unit's
+	// agent or the provider, from which the unit's traffic actually
+	// originates, such as those of a NAT gateway.
+	EgressAddresses []unitEgressDoc `bson:"egress-addresses,omitempty"`
+
+	// PasswordRotationRequested records that the unit's agent should
+	// be given a new password by the deployer that deployed it.
+	PasswordRotationRequested bool `bson:"password-rotation-requested,omitempty"`
+}
+
+// unitEgressDoc records the egress addresses reported for a unit's
+// endpoint binding. An empty binding applies to all endpoints for which
+// no addresses have been reported.
+type unitEgressDoc struct {
+	Binding   string   `bson:"binding,omitempty"`
+	Addresses []string `bson:"addresses"`
 }
 
 // Unit represents the state of an application unit.
@@ -238,13 +256,23 @@ func (u *Unit) SetWorkloadVersion(version string) error {
 	// want to avoid everything being an attr of the main docs to
 	// stop a swarm of watchers being notified for irrelevant changes.
 	now := u.st.clock().Now()
-	return setStatus(u.st.db(), setStatusParams{
+	err := setStatus(u.st.db(), setStatusParams{
 		badge:     "workload",
 		globalKey: u.globalWorkloadVersionKey(),
 		status:    status.Active,
 		message:   version,
 		updated:   &now,
 	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// Only the most recent workload versions are kept, independently of
+	// the model's status history pruning, so that a unit's version changes
+	// remain available for as long as it exists.
+	return errors.Annotate(
+		trimStatusHistory(u.st.db(), u.globalWorkloadVersionKey(), WorkloadVersionHistoryLimit),
+		"cannot trim workload version history",
+	)
 }
 
 // WorkloadVersionHistory returns a HistoryGetter which enables the
@@ -253,6 +281,21 @@ func (u *Unit) WorkloadVersionHistory() *HistoryGetter {
 	return &HistoryGetter{st: u.st, globalKey: u.globalWorkloadVersionKey()}
 }
 
+// WorkloadVersionHistoryLimit is the number of workload versions that are
+// kept for each unit.
+const WorkloadVersionHistoryLimit = 10
+
+// WorkloadVersions returns the workload versions most recently reported by
+// the unit, newest first, each with the time at which the unit last
+// reported it.
+func (u *Unit) WorkloadVersions() ([]status.StatusInfo, error) {
+	return statusHistory(&statusHistoryArgs{
+		db:        u.st.db(),
+		globalKey: u.globalWorkloadVersionKey(),
+		filter:    status.StatusHistoryFilter{Size: WorkloadVersionHistoryLimit},
+	})
+}
+
 // AgentTools returns the tools that the agent is currently running.
 // It an error that satisfies errors.IsNotFound if the tools have not
 // yet been set.
@@ -297,17 +340,119 @@ func (u *Unit) SetPassword(password string) error {
 // to the value supplied. This is split out from SetPassword to allow direct
 // manipulation in tests (to check for backwards compatibility).
 func (u *Unit) setPasswordHash(passwordHash string) error {
+	// Setting the password satisfies any request to rotate it.
 	ops := []txn.Op{{
 		C:      unitsC,
 		Id:     u.doc.DocID,
 		Assert: notDeadDoc,
-		Update: bson.D{{"$set", bson.D{{"passwordhash", passwordHash}}}},
+		Update: bson.D{
+			{"$set", bson.D{{"passwordhash", passwordHash}}},
+			{"$unset", bson.D{{"password-rotation-requested", nil}}},
+		},
 	}}
 	err := u.st.db().RunTransaction(ops)
 	if err != nil {
 		return fmt.Errorf("cannot set password of unit %q: %v", u, onAbort(err, ErrDead))
 	}
 	u.doc.PasswordHash = passwordHash
+	u.doc.PasswordRotationRequested = false
+	return nil
+}
+
+// RequestPasswordRotation records that the unit's agent should be given
+// a new password. The deployer responsible for the unit rotates it, and
+// the request is cleared when the new password is set.
+func (u *Unit) RequestPasswordRotation() error {
+	ops := []txn.Op{{
+		C:      unitsC,
+		Id:     u.doc.DocID,
+		Assert: notDeadDoc,
+		Update: bson.D{{"$set", bson.D{{"password-rotation-requested", true}}}},
+	}}
+	err := u.st.db().RunTransaction(ops)
+	if err != nil {
+		return errors.Annotatef(onAbort(err, ErrDead), "cannot request password rotation for unit %q", u)
+	}
+	u.doc.PasswordRotationRequested = true
+	return nil
+}
+
+// PasswordRotationRequested reports whether the unit's agent is waiting
+// to be given a new password.
+func (u *Unit) PasswordRotationRequested() bool {
+	return u.doc.PasswordRotationRequested
+}
+
+// EgressAddresses returns the egress addresses reported for the unit,
+// keyed on endpoint binding name. Addresses keyed on the empty binding
+// apply to all endpoints without addresses of their own.
+func (u *Unit) EgressAddresses() map[string][]string {
+	if len(u.doc.EgressAddresses) == 0 {
+		return nil
+	}
+	result := make(map[string][]string)
+	for _, egress := range u.doc.EgressAddresses {
+		result[egress.Binding] = append([]string(nil), egress.Addresses...)
+	}
+	return result
+}
+
+// EgressAddressesForBinding returns the egress addresses reported for
+// the unit's named endpoint binding, falling back to those reported for
+// all endpoints. It returns nil if no egress addresses apply.
+func (u *Unit) EgressAddressesForBinding(binding string) []string {
+	var fallback []string
+	for _, egress := range u.doc.EgressAddresses {
+		switch egress.Binding {
+		case binding:
+			return append([]string(nil), egress.Addresses...)
+		case "":
+			fallback = egress.Addresses
+		}
+	}
+	return append([]string(nil), fallback...)
+}
+
+// SetEgressAddresses records the effective egress addresses of the unit,
+// keyed on endpoint binding name, replacing any reported previously.
+// Each address must be an IP address or a CIDR. Addresses keyed on the
+// empty binding apply to all endpoints without addresses of their own.
+func (u *Unit) SetEgressAddresses(bindings map[string][]string) error {
+	var docs []unitEgressDoc
+	for binding, addresses := range bindings {
+		if len(addresses) == 0 {
+			continue
+		}
+		for _, addr := range addresses {
+			if net.ParseIP(addr) != nil {
+				continue
+			}
+			if _, _, err := net.ParseCIDR(addr); err != nil {
+				return errors.NotValidf("egress address %q for unit %q", addr, u)
+			}
+		}
+		docs = append(docs, unitEgressDoc{
+			Binding:   binding,
+			Addresses: append([]string(nil), addresses...),
+		})
+	}
+	sort.Slice(docs, func(i, j int) bool {
+		return docs[i].Binding < docs[j].Binding
+	})
+	update := bson.D{{"$set", bson.D{{"egress-addresses", docs}}}}
+	if len(docs) == 0 {
+		update = bson.D{{"$unset", bson.D{{"egress-addresses", nil}}}}
+	}
+	ops := []txn.Op{{
+		C:      unitsC,
+		Id:     u.doc.DocID,
+		Assert: notDeadDoc,
+		Update: update,
+	}}
+	if err := u.st.db().RunTransaction(ops); err != nil {
+		return errors.Annotatef(onAbort(err, ErrDead), "cannot set egress addresses of unit %q", u)
+	}
+	u.doc.EgressAddresses = docs
 	return nil
 }
 
@@ -1411,7 +1556,11 @@ func (u *Unit) SetStatus(unitStatus status.StatusInfo) error {
 // existing, alive subnet, otherwise an error is returned. Returns an error if
 // opening the requested range conflicts with another already opened range on
 // the same subnet and and the unit's assigned machine.
-func (u *Unit) OpenPortsOnSubnet(subnetID, protocol string, fromPort, toPort int) (err error) {
+func (u *Unit) OpenPortsOnSubnet(subnetID, protocol string, fromPort, toPort int) error {
+	return u.openPortsOnSubnet(subnetID, "", protocol, fromPort, toPort)
+}
+
+func (u *Unit) openPortsOnSubnet(subnetID, hook, protocol string, fromPort, toPort int) (err error) {
 	ports, err := NewPortRange(u.Name(), fromPort, toPort, protocol)
 	if err != nil {
 		return errors.Annotatef(err, "invalid port range %v-%v/%v", fromPort, toPort, protocol)
@@ -1432,31 +1581,59 @@ func (u *Unit) OpenPortsOnSubnet(subnetID, protocol string, fromPort, toPort int
 		return errors.Annotate(err, "cannot get or create ports")
 	}
 
-	return machinePorts.OpenPorts(ports)
+	return machinePorts.openPorts(ports, hook)
 }
 
 func (u *Unit) checkSubnetAliveWhenSet(subnetID string) error {
+	subnet, err := u.subnetWhenSet(subnetID)
+	if err != nil {
+		return errors.Trace(err)
+	} else if subnet != nil && subnet.Life() != Alive {
+		return errors.Errorf("subnet %q not found or not alive", subnetID)
+	}
+	return nil
+}
+
+// checkSubnetNotDeadWhenSet is like checkSubnetAliveWhenSet, but also accepts
+// a Dying subnet, so its ports can be closed while it is being removed.
+func (u *Unit) checkSubnetNotDeadWhenSet(subnetID string) error {
+	subnet, err := u.subnetWhenSet(subnetID)
+	if err != nil {
+		return errors.Trace(err)
+	} else if subnet != nil && subnet.Life() == Dead {
+		return errors.Errorf("subnet %q not found or not alive", subnetID)
+	}
+	return nil
+}
+
+// subnetWhenSet returns the subnet with the given ID, or nil if subnetID is
+// empty.
+func (u *Unit) subnetWhenSet(subnetID string) (*Subnet, error) {
 	if subnetID == "" {
-		return nil
+		return nil, nil
 	}
 
 	if _, err := strconv.Atoi(subnetID); err != nil {
-		return errors.Errorf("invalid subnet ID %q", subnetID)
+		return nil, errors.Errorf("invalid subnet ID %q", subnetID)
 	}
 
 	subnet, err := u.st.SubnetByID(subnetID)
-	if err != nil && !errors.IsNotFound(err) {
-		return errors.Annotatef(err, "getting subnet %q", subnetID)
-	} else if errors.IsNotFound(err) || subnet.Life() != Alive {
-		return errors.Errorf("subnet %q not found or not alive", subnetID)
+	if errors.IsNotFound(err) {
+		return nil, errors.Errorf("subnet %q not found or not alive", subnetID)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "getting subnet %q", subnetID)
 	}
-	return nil
+	return subnet, nil
 }
 
 // ClosePortsOnSubnet closes the given port range and protocol for the unit on
 // the given subnet, which can be empty. When non-empty, subnetID must refer to
-// an existing, alive subnet, otherwise an error is returned.
-func (u *Unit) ClosePortsOnSubnet(subnetID, protocol string, fromPort, toPort int) (err error) {
+// an existing subnet that is not Dead, otherwise an error is returned.
+func (u *Unit) ClosePortsOnSubnet(subnetID, protocol string, fromPort, toPort int) error {
+	return u.closePortsOnSubnet(subnetID, "", protocol, fromPort, toPort)
+}
+
+func (u *Unit) closePortsOnSubnet(subnetID, hook, protocol string, fromPort, toPort int) (err error) {
 	ports, err := NewPortRange(u.Name(), fromPort, toPort, protocol)
 	if err != nil {
 		return errors.Annotatef(err, "invalid port range %v-%v/%v", fromPort, toPort, protocol)
@@ -1468,7 +1645,7 @@ func (u *Unit) ClosePortsOnSubnet(subnetID, protocol string, fromPort, toPort in
 		return errors.Annotatef(err, "unit %q has no assigned machine", u)
 	}
 
-	if err := u.checkSubnetAliveWhenSet(subnetID); err != nil {
+	if err := u.checkSubnetNotDeadWhenSet(subnetID); err != nil {
 		return errors.Trace(err)
 	}
 
@@ -1477,7 +1654,7 @@ func (u *Unit) ClosePortsOnSubnet(subnetID, protocol string, fromPort, toPort in
 		return errors.Annotate(err, "cannot get or create ports")
 	}
 
-	return machinePorts.ClosePorts(ports)
+	return machinePorts.closePorts(ports, hook)
 }
 
 // OpenPorts opens the given port range and protocol for the unit, if it does
@@ -1498,6 +1675,20 @@ func (u *Unit) ClosePorts(protocol string, fromPort, toPort int) (err error) {
 	return u.ClosePortsOnSubnet("", protocol, fromPort, toPort)
 }
 
+// OpenPortsInHook opens the given port range and protocol for the unit, as
+// OpenPorts does, recording in the machine's ports history that the ports
+// were opened in the named hook.
+func (u *Unit) OpenPortsInHook(hook, protocol string, fromPort, toPort int) error {
+	return u.openPortsOnSubnet("", hook, protocol, fromPort, toPort)
+}
+
+// ClosePortsInHook closes the given port range and protocol for the unit, as
+// ClosePorts does, recording in the machine's ports history that the ports
+// were closed in the named hook.
+func (u *Unit) ClosePortsInHook(hook, protocol string, fromPort, toPort int) error {
+	return u.closePortsOnSubnet("", hook, protocol, fromPort, toPort)
+}
+
 // OpenPortOnSubnet opens the given port and protocol for the unit on the given
 // subnet, which can be empty. When non-empty, subnetID must refer to an
 // existing, alive subnet, otherwise an error is returned.
@@ -1806,6 +1997,7 @@ var (
 	machineNotCleanErr = errors.New("machine is dirty")
 	alreadyAssignedErr = errors.New("unit is already assigned to a machine")
 	inUseErr           = errors.New("machine is not unused")
+	machineCordonedErr = errors.New("machine is cordoned")
 )
 
 // assignToMachine is the internal version of AssignToMachine.
@@ -1839,6 +2031,7 @@ func (u *Unit) assignToMachine(m *Machine, unused bool) (err error) {
 // - unitNotAliveErr when the unit is not alive.
 // - alreadyAssignedErr when the unit has already been assigned
 // - inUseErr when the machine already has a unit assigned (if unused is true)
+// - machineCordonedErr when the machine is cordoned.
 func (u *Unit) assignToMachineOps(m *Machine, unused bool) ([]txn.Op, error) {
 	if u.Life() != Alive {
 		return nil, unitNotAliveErr
@@ -1852,6 +2045,9 @@ func (u *Unit) assignToMachineOps(m *Machine, unused bool) ([]txn.Op, error) {
 	if unused && !m.doc.Clean {
 		return nil, inUseErr
 	}
+	if m.doc.Cordoned {
+		return nil, machineCordonedErr
+	}
 	storageParams, err := u.storageParams()
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -1895,7 +2091,7 @@ func (u *Unit) assignToMachineOps(m *Machine, unused bool) ([]txn.Op, error) {
 			{{"machineid", m.Id()}},
 		},
 	}}...)
-	massert := isAliveDoc
+	massert := append(isAliveDoc, bson.DocElem{"cordoned", bson.D{{"$ne", true}}})
 	if unused {
 		massert = append(massert, bson.D{{"clean", bson.D{{"$ne", false}}}}...)
 	}
@@ -2160,11 +2356,14 @@ func (u *Unit) assignToNewMachineOps(
 		if len(containers) > 0 {
 			return nil, nil, machineNotCleanErr
 		}
+		if mparent.IsCordoned() {
+			return nil, nil, machineCordonedErr
+		}
 		parentDocId := u.st.docID(parentId)
 		ops = append(ops, txn.Op{
 			C:      machinesC,
 			Id:     parentDocId,
-			Assert: bson.D{{"clean", true}},
+			Assert: bson.D{{"clean", true}, {"cordoned", bson.D{{"$ne", true}}}},
 		}, txn.Op{
 			C:      containerRefsC,
 			Id:     parentDocId,
@@ -2636,6 +2835,24 @@ func (u *Unit) findCleanMachineQuery(requireEmpty bool, cons *constraints.Value)
 		omitMachineIds = append(omitMachineIds, cIds...)
 	}
 
+	// Exclude cordoned machines, and containers on them.
+	cordoned, err := u.st.cordonedMachineIds()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	omitMachineIds = append(omitMachineIds, cordoned...)
+	for _, id := range cordoned {
+		m, err := u.st.Machine(id)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		cIds, err := m.Containers()
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
+		omitMachineIds = append(omitMachineIds, cIds...)
+	}
+
 	terms := bson.D{
 		{"life", Alive},
 		{"series", u.doc.Series},
@@ -2860,7 +3077,7 @@ func (u *Unit) assignToCleanMaybeEmptyMachineOps(requireEmpty bool) (_ *Machine,
 			return m, ops, nil
 		}
 		switch errors.Cause(err) {
-		case inUseErr, machineNotAliveErr:
+		case inUseErr, machineNotAliveErr, machineCordonedErr:
 		default:
 			assignContextf(&err, u.Name(), context)
 			return failure(err)
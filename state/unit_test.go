@@ -1272,6 +1272,107 @@ func (s *UnitSuite) TestSetPassword(c *gc.C) {
 	})
 }
 
+func (s *UnitSuite) TestRequestPasswordRotation(c *gc.C) {
+	c.Assert(s.unit.PasswordRotationRequested(), jc.IsFalse)
+
+	err := s.unit.RequestPasswordRotation()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.unit.PasswordRotationRequested(), jc.IsTrue)
+
+	unit, err := s.State.Unit(s.unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unit.PasswordRotationRequested(), jc.IsTrue)
+
+	// Setting the password satisfies the request.
+	err = unit.SetPassword("new-password-0123456789")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unit.PasswordRotationRequested(), jc.IsFalse)
+	err = s.unit.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.unit.PasswordRotationRequested(), jc.IsFalse)
+}
+
+func (s *UnitSuite) TestRequestPasswordRotationDead(c *gc.C) {
+	preventUnitDestroyRemove(c, s.unit)
+	err := s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.unit.RequestPasswordRotation()
+	c.Assert(err, gc.ErrorMatches, `cannot request password rotation for unit "wordpress/0": not found or dead`)
+}
+
+func (s *UnitSuite) TestSetEgressAddresses(c *gc.C) {
+	c.Assert(s.unit.EgressAddresses(), gc.IsNil)
+	c.Assert(s.unit.EgressAddressesForBinding("db"), gc.HasLen, 0)
+
+	err := s.unit.SetEgressAddresses(map[string][]string{
+		"":   {"10.0.0.1"},
+		"db": {"10.0.0.0/24"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	unit, err := s.State.Unit(s.unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unit.EgressAddresses(), jc.DeepEquals, map[string][]string{
+		"":   {"10.0.0.1"},
+		"db": {"10.0.0.0/24"},
+	})
+	c.Assert(unit.EgressAddressesForBinding("db"), jc.DeepEquals, []string{"10.0.0.0/24"})
+	c.Assert(unit.EgressAddressesForBinding("url"), jc.DeepEquals, []string{"10.0.0.1"})
+
+	err = unit.SetEgressAddresses(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err = s.State.Unit(s.unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unit.EgressAddresses(), gc.IsNil)
+}
+
+func (s *UnitSuite) TestSetEgressAddressesInvalid(c *gc.C) {
+	err := s.unit.SetEgressAddresses(map[string][]string{"db": {"nat.example.com"}})
+	c.Assert(err, gc.ErrorMatches, `egress address "nat.example.com" for unit "wordpress/0" not valid`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *UnitSuite) TestSetEgressAddressesDead(c *gc.C) {
+	preventUnitDestroyRemove(c, s.unit)
+	err := s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.SetEgressAddresses(map[string][]string{"": {"10.0.0.1"}})
+	c.Assert(err, gc.ErrorMatches, `cannot set egress addresses of unit "wordpress/0": not found or dead`)
+}
+
+func (s *UnitSuite) TestWatchEgressAddressesHash(c *gc.C) {
+	w := s.unit.WatchEgressAddressesHash()
+	defer testing.AssertStop(c, w)
+	wc := testing.NewStringsWatcherC(c, s.State, w)
+	// This is the sha256 hash of no addresses.
+	wc.AssertChange("e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855")
+
+	// Change the unit: not reported.
+	err := s.unit.SetPassword("arble-farble-dying-yarble")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	err = s.unit.SetEgressAddresses(map[string][]string{"": {"10.0.0.1"}})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChange("db9daf881a93735383c58c7e8838f99d076cdfeee7583077e1ccf2be23b69c5e")
+
+	err = s.unit.SetEgressAddresses(map[string][]string{
+		"db": {"10.0.0.0/24"},
+		"":   {"10.0.0.1"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChange("4417a980ceafafc0c2769e73040bb6014c673574f97905a560ad86c83068e85d")
+
+	// Setting the same addresses: not reported.
+	err = s.unit.SetEgressAddresses(map[string][]string{
+		"":   {"10.0.0.1"},
+		"db": {"10.0.0.0/24"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+}
+
 func (s *UnitSuite) TestUnitSetAgentPresence(c *gc.C) {
 	alive, err := s.unit.AgentPresence()
 	c.Assert(err, jc.ErrorIsNil)
@@ -2219,6 +2320,32 @@ func (s *UnitSuite) TestWorkloadVersion(c *gc.C) {
 	c.Check(version, gc.Equals, "3.combined")
 }
 
+func (s *UnitSuite) TestWorkloadVersions(c *gc.C) {
+	limit := state.WorkloadVersionHistoryLimit
+	for i := 0; i < limit+2; i++ {
+		s.Clock.Advance(time.Minute)
+		err := s.unit.SetWorkloadVersion(fmt.Sprintf("v.%d", i))
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	// Reporting the current version again only updates its timestamp.
+	s.Clock.Advance(time.Minute)
+	err := s.unit.SetWorkloadVersion(fmt.Sprintf("v.%d", limit+1))
+	c.Assert(err, jc.ErrorIsNil)
+
+	versions, err := s.unit.WorkloadVersions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(versions, gc.HasLen, limit)
+	c.Check(versions[0].Message, gc.Equals, fmt.Sprintf("v.%d", limit+1))
+	c.Check(versions[0].Since.Equal(s.Clock.Now()), jc.IsTrue)
+	c.Check(versions[1].Message, gc.Equals, fmt.Sprintf("v.%d", limit))
+	c.Check(versions[limit-1].Message, gc.Equals, "v.2")
+
+	// The trimmed history is what status history reports too.
+	history, err := s.unit.WorkloadVersionHistory().StatusHistory(status.StatusHistoryFilter{Size: 100})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, limit)
+}
+
 func (s *UnitSuite) TestDestroyWithForceWorksOnDyingUnit(c *gc.C) {
 	// Ensure that a cleanup is scheduled if we force destroy a unit
 	// that's already dying.
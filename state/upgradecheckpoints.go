@@ -0,0 +1,105 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"regexp"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// The names under which the upgrade steps that resume where they left
+// off record their checkpoints.
+const (
+	AddSubnetIdToSubnetDocsCheckpoint     = "add-subnet-id-to-subnet-docs"
+	ReplacePortsDocSubnetIDCIDRCheckpoint = "replace-ports-doc-subnet-id-cidr"
+	SplitPortsDocsByUnitCheckpoint        = "split-ports-docs-by-unit"
+)
+
+// upgradeCheckpointDoc records the ID of the last document processed
+// by a long-running upgrade step. It is keyed by the step's checkpoint
+// key.
+type upgradeCheckpointDoc struct {
+	DocID   string `bson:"_id"`
+	LastID  string `bson:"last-id"`
+	Updated int64  `bson:"updated"`
+}
+
+// UpgradeCheckpoint returns the ID of the last document recorded as
+// processed by the upgrade step with the given checkpoint key, or the
+// empty string if the step hasn't recorded a checkpoint.
+func (st *State) UpgradeCheckpoint(key string) (string, error) {
+	coll, closer := st.db().GetRawCollection(upgradeCheckpointsC)
+	defer closer()
+
+	var doc upgradeCheckpointDoc
+	if err := coll.FindId(key).One(&doc); err == mgo.ErrNotFound {
+		return "", nil
+	} else if err != nil {
+		return "", errors.Annotatef(err, "cannot get upgrade checkpoint %q", key)
+	}
+	return doc.LastID, nil
+}
+
+// SetUpgradeCheckpoint records lastID as the ID of the last document
+// processed by the upgrade step with the given checkpoint key.
+func (st *State) SetUpgradeCheckpoint(key, lastID string) error {
+	coll, closer := st.db().GetRawCollection(upgradeCheckpointsC)
+	defer closer()
+
+	_, err := coll.UpsertId(key, &upgradeCheckpointDoc{
+		DocID:   key,
+		LastID:  lastID,
+		Updated: st.clock().Now().UnixNano(),
+	})
+	return errors.Annotatef(err, "cannot set upgrade checkpoint %q", key)
+}
+
+// RemoveUpgradeCheckpoints removes the checkpoints recorded by the
+// named upgrade step, once it has completed or been rolled back.
+func (st *State) RemoveUpgradeCheckpoints(step string) error {
+	coll, closer := st.db().GetRawCollection(upgradeCheckpointsC)
+	defer closer()
+
+	_, err := coll.RemoveAll(bson.D{{
+		"_id", bson.D{{"$regex", "^" + regexp.QuoteMeta(step+":")}},
+	}})
+	return errors.Annotatef(err, "cannot remove upgrade checkpoints for %q", step)
+}
+
+// upgradeCheckpointKey returns the checkpoint key of the named upgrade
+// step for the model with the given UUID.
+func upgradeCheckpointKey(step, modelUUID string) string {
+	return step + ":" + modelUUID
+}
+
+// runCheckpointed calls process with successive throttled batches of
+// the IDs of the model's documents in the named collection, in ID order
+// (see runThrottled). After each batch is processed the ID of its last
+// document is recorded as the model's checkpoint for the named upgrade
+// step, so that if the step is interrupted it resumes after the last
+// batch processed. The step must call RemoveUpgradeCheckpoints once it
+// has completed for every model.
+//
+// Documents inserted by process may be passed to it again, so it must
+// skip documents that have already been upgraded.
+func runCheckpointed(st *State, step, collName string, process func(ids []string) error) error {
+	key := upgradeCheckpointKey(step, st.ModelUUID())
+	lastID, err := st.UpgradeCheckpoint(key)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if lastID != "" {
+		upgradesLogger.Infof("resuming upgrade step %q for model %q after %q", step, st.ModelUUID(), lastID)
+	}
+
+	return errors.Trace(runThrottled(st, collName, lastID, func(ids []string) error {
+		if err := process(ids); err != nil {
+			return errors.Trace(err)
+		}
+		return errors.Trace(st.SetUpgradeCheckpoint(key, ids[len(ids)-1]))
+	}))
+}
@@ -0,0 +1,99 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/core/network"
+)
+
+type upgradeCheckpointsSuite struct {
+	internalStateSuite
+}
+
+var _ = gc.Suite(&upgradeCheckpointsSuite{})
+
+func (s *upgradeCheckpointsSuite) TestUpgradeCheckpoint(c *gc.C) {
+	lastID, err := s.state.UpgradeCheckpoint("step:model")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(lastID, gc.Equals, "")
+
+	for _, id := range []string{"a", "b"} {
+		err = s.state.SetUpgradeCheckpoint("step:model", id)
+		c.Assert(err, jc.ErrorIsNil)
+		lastID, err = s.state.UpgradeCheckpoint("step:model")
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(lastID, gc.Equals, id)
+	}
+
+	err = s.state.SetUpgradeCheckpoint("step.other:model", "c")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.state.RemoveUpgradeCheckpoints("step")
+	c.Assert(err, jc.ErrorIsNil)
+
+	lastID, err = s.state.UpgradeCheckpoint("step:model")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(lastID, gc.Equals, "")
+	lastID, err = s.state.UpgradeCheckpoint("step.other:model")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(lastID, gc.Equals, "c")
+}
+
+func (s *upgradeCheckpointsSuite) TestRunCheckpointedResumes(c *gc.C) {
+	err := s.state.UpdateControllerConfig(map[string]interface{}{
+		controller.UpgradeBatchSize:      2,
+		controller.UpgradeBatchSleepTime: "0s",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	var docIDs []string
+	for _, cidr := range []string{"10.0.0.0/24", "10.0.1.0/24", "10.0.2.0/24"} {
+		subnet, err := s.state.AddSubnet(network.SubnetInfo{CIDR: cidr})
+		c.Assert(err, jc.ErrorIsNil)
+		docIDs = append(docIDs, s.state.docID(subnet.ID()))
+	}
+
+	var processed [][]string
+	process := func(ids []string) error {
+		processed = append(processed, ids)
+		if len(processed) == 2 {
+			return errors.New("boom")
+		}
+		return nil
+	}
+	err = runCheckpointed(s.state, "step", subnetsC, process)
+	c.Assert(err, gc.ErrorMatches, "boom")
+	c.Check(processed, jc.DeepEquals, [][]string{docIDs[:2], docIDs[2:]})
+
+	key := upgradeCheckpointKey("step", s.state.ModelUUID())
+	lastID, err := s.state.UpgradeCheckpoint(key)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(lastID, gc.Equals, docIDs[1])
+
+	// Running again resumes after the last batch processed.
+	processed = nil
+	process = func(ids []string) error {
+		processed = append(processed, ids)
+		return nil
+	}
+	err = runCheckpointed(s.state, "step", subnetsC, process)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(processed, jc.DeepEquals, [][]string{docIDs[2:]})
+}
+
+func (s *upgradeCheckpointsSuite) TestCheckpointedStepRemovesCheckpoints(c *gc.C) {
+	_, err := s.state.AddSubnet(network.SubnetInfo{CIDR: "10.0.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = AddSubnetIdToSubnetDocs(s.pool)
+	c.Assert(err, jc.ErrorIsNil)
+
+	key := upgradeCheckpointKey("add-subnet-id-to-subnet-docs", s.state.ModelUUID())
+	lastID, err := s.state.UpgradeCheckpoint(key)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(lastID, gc.Equals, "")
+}
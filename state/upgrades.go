@@ -2438,110 +2438,210 @@ func ChangeSubnetSpaceNameToSpaceID(pool *StatePool) (err error) {
 }
 
 // AddSubnetIdToSubnetDocs ensures that every subnet document includes a
-// a sequentially generated ID.
+// a sequentially generated ID. Progress is checkpointed, so that the
+// step resumes where it left off if it is interrupted.
 func AddSubnetIdToSubnetDocs(pool *StatePool) (err error) {
-	return errors.Trace(runForAllModelStates(pool, func(st *State) error {
-		col, closer := st.db().GetCollection(subnetsC)
-		defer closer()
+	const step = AddSubnetIdToSubnetDocsCheckpoint
+	err = runForAllModelStates(pool, func(st *State) error {
+		return runCheckpointed(st, step, subnetsC, func(ids []string) error {
+			return addSubnetIdToSubnetDocs(st, ids)
+		})
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(pool.SystemState().RemoveUpgradeCheckpoints(step))
+}
 
-		var docs []subnetDoc
-		err := col.Find(nil).All(&docs)
+func addSubnetIdToSubnetDocs(st *State, ids []string) error {
+	col, closer := st.db().GetCollection(subnetsC)
+	defer closer()
+
+	var docs []subnetDoc
+	err := col.Find(bson.D{{"_id", bson.D{{"$in", ids}}}}).All(&docs)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	var ops []txn.Op
+	for _, oldDoc := range docs {
+		// A doc with a subnet ID has already been upgraded.
+		if oldDoc.ID != "" {
+			continue
+		}
+
+		// We cannot edit _id, so we need to delete and re-create each doc.
+		ops = append(ops, txn.Op{
+			C:      subnetsC,
+			Id:     oldDoc.DocID,
+			Assert: txn.DocExists,
+			Remove: true,
+		})
+
+		seq, err := sequence(st, "subnet")
 		if err != nil {
 			return errors.Trace(err)
 		}
+		id := strconv.Itoa(seq)
 
-		var ops []txn.Op
-		for _, oldDoc := range docs {
-			// A doc with a subnet ID has already been upgraded.
-			if oldDoc.ID != "" {
-				continue
-			}
+		newDoc := oldDoc
+		newDoc.TxnRevno = 0
+		newDoc.DocID = st.docID(id)
+		newDoc.ID = id
 
-			// We cannot edit _id, so we need to delete and re-create each doc.
-			ops = append(ops, txn.Op{
-				C:      subnetsC,
-				Id:     oldDoc.DocID,
-				Assert: txn.DocExists,
-				Remove: true,
-			})
+		ops = append(ops, txn.Op{
+			C:      subnetsC,
+			Id:     newDoc.DocID,
+			Insert: newDoc,
+		})
+	}
 
-			seq, err := sequence(st, "subnet")
-			if err != nil {
-				return errors.Trace(err)
-			}
-			id := strconv.Itoa(seq)
+	if len(ops) > 0 {
+		return errors.Trace(st.db().RunTransaction(ops))
+	}
+	return nil
+}
 
-			newDoc := oldDoc
-			newDoc.TxnRevno = 0
-			newDoc.DocID = st.docID(id)
-			newDoc.ID = id
+// ReplacePortsDocSubnetIDCIDR ensures that every ports document use an
+// ID rather than a CIDR for subnetID. Progress is checkpointed, so that
+// the step resumes where it left off if it is interrupted.
+func ReplacePortsDocSubnetIDCIDR(pool *StatePool) (err error) {
+	const step = ReplacePortsDocSubnetIDCIDRCheckpoint
+	err = runForAllModelStates(pool, func(st *State) error {
+		return runCheckpointed(st, step, openedPortsC, func(ids []string) error {
+			return replacePortsDocSubnetIDCIDR(st, ids)
+		})
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(pool.SystemState().RemoveUpgradeCheckpoints(step))
+}
 
-			ops = append(ops, txn.Op{
-				C:      subnetsC,
-				Id:     newDoc.DocID,
-				Insert: newDoc,
-			})
-		}
+func replacePortsDocSubnetIDCIDR(st *State, ids []string) error {
+	col, closer := st.db().GetCollection(openedPortsC)
+	defer closer()
 
-		if len(ops) > 0 {
-			return errors.Trace(st.db().RunTransaction(ops))
+	var docs []portsDoc
+	err := col.Find(bson.D{{"_id", bson.D{{"$in", ids}}}}).All(&docs)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	var ops []txn.Op
+	for _, oldDoc := range docs {
+		// A doc with a subnet ID has already been upgraded.
+		if !network.IsValidCidr(oldDoc.SubnetID) {
+			continue
 		}
-		return nil
-	}))
-}
 
-// ReplacePortsDocSubnetIDCIDR ensures that every ports document use an
-// ID rather than a CIDR for subnetID.
-func ReplacePortsDocSubnetIDCIDR(pool *StatePool) (err error) {
-	return errors.Trace(runForAllModelStates(pool, func(st *State) error {
-		col, closer := st.db().GetCollection(openedPortsC)
-		defer closer()
+		// We cannot edit _id, so we need to delete and re-create each doc.
+		ops = append(ops, txn.Op{
+			C:      openedPortsC,
+			Id:     oldDoc.DocID,
+			Assert: txn.DocExists,
+			Remove: true,
+		})
 
-		var docs []portsDoc
-		err := col.Find(nil).All(&docs)
+		// If we're upgrading from a model which has cidrs for
+		// subnetIDs, there can be only 1 of that cidr in the model.
+		subnet, err := st.Subnet(oldDoc.SubnetID)
 		if err != nil {
 			return errors.Trace(err)
 		}
 
-		var ops []txn.Op
-		for _, oldDoc := range docs {
-			// A doc with a subnet ID has already been upgraded.
-			if !network.IsValidCidr(oldDoc.SubnetID) {
-				continue
-			}
+		newDoc := oldDoc
+		newDoc.TxnRevno = 0
+		newDoc.DocID = portsGlobalKey(newDoc.MachineID, subnet.ID())
+		newDoc.SubnetID = subnet.ID()
 
-			// We cannot edit _id, so we need to delete and re-create each doc.
-			ops = append(ops, txn.Op{
-				C:      openedPortsC,
-				Id:     oldDoc.DocID,
-				Assert: txn.DocExists,
-				Remove: true,
-			})
+		ops = append(ops, txn.Op{
+			C:      openedPortsC,
+			Id:     newDoc.DocID,
+			Insert: newDoc,
+		})
+	}
 
-			// If we're upgrading from a model which has cidrs for
-			// subnetIDs, there can be only 1 of that cidr in the model.
-			subnet, err := st.Subnet(oldDoc.SubnetID)
-			if err != nil {
-				return errors.Trace(err)
-			}
+	if len(ops) > 0 {
+		return errors.Trace(st.db().RunTransaction(ops))
+	}
+	return nil
+}
+
+// SplitPortsDocsByUnit replaces each ports document holding the ports
+// opened by all the units on a machine and subnet with a document for
+// each unit, so that units sharing a machine don't contend for a single
+// document. The new documents are inserted in the same transaction that
+// removes the old one, so the opened ports watcher, which compares the
+// txn-revno of each document, reports each machine and subnet changed
+// once. Progress is checkpointed, so that the step resumes where it left
+// off if it is interrupted.
+func SplitPortsDocsByUnit(pool *StatePool) (err error) {
+	const step = SplitPortsDocsByUnitCheckpoint
+	err = runForAllModelStates(pool, func(st *State) error {
+		return runCheckpointed(st, step, openedPortsC, func(ids []string) error {
+			return splitPortsDocsByUnit(st, ids)
+		})
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(pool.SystemState().RemoveUpgradeCheckpoints(step))
+}
+
+func splitPortsDocsByUnit(st *State, ids []string) error {
+	col, closer := st.db().GetCollection(openedPortsC)
+	defer closer()
+
+	// A doc with a unit name has already been split.
+	var docs []portsDoc
+	err := col.Find(bson.D{
+		{"_id", bson.D{{"$in", ids}}},
+		{"unit-name", bson.D{{"$exists", false}}},
+	}).All(&docs)
+	if err != nil {
+		return errors.Trace(err)
+	}
 
-			newDoc := oldDoc
-			newDoc.TxnRevno = 0
-			newDoc.DocID = portsGlobalKey(newDoc.MachineID, subnet.ID())
-			newDoc.SubnetID = subnet.ID()
+	var ops []txn.Op
+	for _, oldDoc := range docs {
+		ops = append(ops, txn.Op{
+			C:      openedPortsC,
+			Id:     oldDoc.DocID,
+			Assert: txn.DocExists,
+			Remove: true,
+		})
 
+		var unitNames []string
+		unitPorts := make(map[string][]PortRange)
+		for _, portRange := range oldDoc.Ports {
+			if _, ok := unitPorts[portRange.UnitName]; !ok {
+				unitNames = append(unitNames, portRange.UnitName)
+			}
+			unitPorts[portRange.UnitName] = append(unitPorts[portRange.UnitName], portRange)
+		}
+		for _, unitName := range unitNames {
+			key := unitPortsGlobalKey(oldDoc.MachineID, oldDoc.SubnetID, unitName)
 			ops = append(ops, txn.Op{
 				C:      openedPortsC,
-				Id:     newDoc.DocID,
-				Insert: newDoc,
+				Id:     st.docID(key),
+				Assert: txn.DocMissing,
+				Insert: &portsDoc{
+					DocID:     st.docID(key),
+					ModelUUID: st.ModelUUID(),
+					MachineID: oldDoc.MachineID,
+					SubnetID:  oldDoc.SubnetID,
+					UnitName:  unitName,
+					Ports:     unitPorts[unitName],
+				},
 			})
 		}
+	}
 
-		if len(ops) > 0 {
-			return errors.Trace(st.db().RunTransaction(ops))
-		}
-		return nil
-	}))
+	if len(ops) > 0 {
+		return errors.Trace(st.db().RunTransaction(ops))
+	}
+	return nil
 }
 
 // EnsureRelationApplicationSettings creates an application settings
@@ -2589,3 +2689,84 @@ func EnsureRelationApplicationSettings(pool *StatePool) error {
 		return errors.Trace(st.db().RunTransaction(ops))
 	}))
 }
+
+// AddModelAliases gives each model without an alias, those created
+// before aliases were introduced, its alias.
+func AddModelAliases(pool *StatePool) error {
+	st := pool.SystemState()
+	models, closer := st.db().GetCollection(modelsC)
+	defer closer()
+
+	var docs []modelDoc
+	if err := models.Find(bson.D{{"alias", bson.D{{"$exists", false}}}}).All(&docs); err != nil {
+		return errors.Annotate(err, "failed to read models")
+	}
+	for _, doc := range docs {
+		// Each alias must be reserved before the next is chosen, so
+		// each model is updated in its own transaction.
+		alias, aliasOp, err := st.newModelAlias(doc.UUID)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		ops := []txn.Op{{
+			C:      modelsC,
+			Id:     doc.UUID,
+			Assert: bson.D{{"alias", bson.D{{"$exists", false}}}},
+			Update: bson.D{{"$set", bson.D{{"alias", alias}}}},
+		}, aliasOp}
+		if err := st.db().RunTransaction(ops); err != nil {
+			return errors.Annotatef(err, "model UUID %q", doc.UUID)
+		}
+	}
+	return nil
+}
+
+// NormaliseSubnetCIDRs rewrites the CIDRs of subnets, and of the
+// subnets recorded against machine addresses, in canonical form, so
+// that subnets added before CIDRs were normalised can be found however
+// their CIDRs are spelled.
+func NormaliseSubnetCIDRs(pool *StatePool) error {
+	return errors.Trace(runForAllModelStates(pool, func(st *State) error {
+		ops, err := normaliseCIDROps(st, subnetsC, "cidr")
+		if err != nil {
+			return errors.Trace(err)
+		}
+		addrOps, err := normaliseCIDROps(st, ipAddressesC, "subnet-cidr")
+		if err != nil {
+			return errors.Trace(err)
+		}
+		ops = append(ops, addrOps...)
+		if len(ops) > 0 {
+			return errors.Trace(st.db().RunTransaction(ops))
+		}
+		return nil
+	}))
+}
+
+// normaliseCIDROps returns operations rewriting the named CIDR field of
+// the model's documents in the named collection in canonical form.
+func normaliseCIDROps(st *State, collName, field string) ([]txn.Op, error) {
+	col, closer := st.db().GetCollection(collName)
+	defer closer()
+
+	var docs []bson.M
+	err := col.Find(bson.D{{field, bson.D{{"$exists", true}}}}).Select(bson.D{{field, 1}}).All(&docs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var ops []txn.Op
+	for _, doc := range docs {
+		cidr, _ := doc[field].(string)
+		normalised := network.NormaliseCIDR(cidr)
+		if normalised == cidr {
+			continue
+		}
+		ops = append(ops, txn.Op{
+			C:      collName,
+			Id:     doc["_id"],
+			Assert: bson.D{{field, cidr}},
+			Update: bson.D{{"$set", bson.D{{field, normalised}}}},
+		})
+	}
+	return ops, nil
+}
@@ -3638,6 +3638,55 @@ func (s *upgradesSuite) TestChangeSubnetAZtoSlice(c *gc.C) {
 	s.assertUpgradedData(c, ChangeSubnetAZtoSlice, upgradedData(col, expected))
 }
 
+func (s *upgradesSuite) TestNormaliseSubnetCIDRs(c *gc.C) {
+	subnetsCol, subnetsCloser := s.state.db().GetRawCollection(subnetsC)
+	defer subnetsCloser()
+	addressesCol, addressesCloser := s.state.db().GetRawCollection(ipAddressesC)
+	defer addressesCloser()
+
+	model1 := s.makeModel(c, "model-1", coretesting.Attrs{})
+	defer func() { _ = model1.Close() }()
+	uuid0 := s.state.ModelUUID()
+	uuid1 := model1.ModelUUID()
+
+	err := subnetsCol.Insert(bson.M{
+		"_id":        ensureModelUUID(uuid0, "0"),
+		"model-uuid": uuid0,
+		"cidr":       "2001:DB8:0::/32",
+	}, bson.M{
+		"_id":        ensureModelUUID(uuid1, "0"),
+		"model-uuid": uuid1,
+		"cidr":       "10.0.0.0/24",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = addressesCol.Insert(bson.M{
+		"_id":         ensureModelUUID(uuid0, "m#0#d#eth0#ip#2001:db8::1"),
+		"model-uuid":  uuid0,
+		"subnet-cidr": "2001:0db8::/32",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	expectedSubnets := bsonMById{{
+		"_id":        uuid0 + ":0",
+		"model-uuid": uuid0,
+		"cidr":       "2001:db8::/32",
+	}, {
+		"_id":        uuid1 + ":0",
+		"model-uuid": uuid1,
+		"cidr":       "10.0.0.0/24",
+	}}
+	sort.Sort(expectedSubnets)
+	expectedAddresses := bsonMById{{
+		"_id":         uuid0 + ":m#0#d#eth0#ip#2001:db8::1",
+		"model-uuid":  uuid0,
+		"subnet-cidr": "2001:db8::/32",
+	}}
+	s.assertUpgradedData(c, NormaliseSubnetCIDRs,
+		upgradedData(subnetsCol, expectedSubnets),
+		upgradedData(addressesCol, expectedAddresses),
+	)
+}
+
 func (s *upgradesSuite) TestChangeSubnetSpaceNameToSpaceID(c *gc.C) {
 	col, closer := s.state.db().GetRawCollection(subnetsC)
 	defer closer()
@@ -3801,6 +3850,87 @@ func (s *upgradesSuite) TestReplacePortsDocSubnetIDCIDR(c *gc.C) {
 	s.assertUpgradedData(c, ReplacePortsDocSubnetIDCIDR, upgradedData(col, expected))
 }
 
+func (s *upgradesSuite) TestSplitPortsDocsByUnit(c *gc.C) {
+	col, closer := s.state.db().GetRawCollection(openedPortsC)
+	defer closer()
+
+	model1 := s.makeModel(c, "model-1", coretesting.Attrs{})
+	model2 := s.makeModel(c, "model-2", coretesting.Attrs{})
+	defer func() {
+		_ = model1.Close()
+		_ = model2.Close()
+	}()
+
+	uuid1 := model1.ModelUUID()
+	uuid2 := model2.ModelUUID()
+
+	portRange := func(unitName string, port int) bson.M {
+		return bson.M{
+			"unitname": unitName,
+			"fromport": port,
+			"toport":   port,
+			"protocol": "tcp",
+		}
+	}
+	err := col.Insert(bson.M{
+		"_id":        ensureModelUUID(uuid1, "m#3#42"),
+		"model-uuid": uuid1,
+		"machine-id": "3",
+		"subnet-id":  "42",
+		"ports": []bson.M{
+			portRange("mysql/0", 3306),
+			portRange("wordpress/0", 80),
+			portRange("mysql/0", 33060),
+		},
+	}, bson.M{
+		"_id":        ensureModelUUID(uuid1, "m#4#"),
+		"model-uuid": uuid1,
+		"machine-id": "4",
+		"subnet-id":  "",
+		"ports":      []bson.M{},
+	}, bson.M{
+		"_id":        ensureModelUUID(uuid2, "m#4##u#wordpress/1"),
+		"model-uuid": uuid2,
+		"machine-id": "4",
+		"subnet-id":  "",
+		"unit-name":  "wordpress/1",
+		"ports":      []bson.M{portRange("wordpress/1", 443)},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	expected := bsonMById{
+		{
+			"_id":        uuid1 + ":m#3#42#u#mysql/0",
+			"model-uuid": uuid1,
+			"machine-id": "3",
+			"subnet-id":  "42",
+			"unit-name":  "mysql/0",
+			"ports": []interface{}{
+				portRange("mysql/0", 3306),
+				portRange("mysql/0", 33060),
+			},
+		}, {
+			"_id":        uuid1 + ":m#3#42#u#wordpress/0",
+			"model-uuid": uuid1,
+			"machine-id": "3",
+			"subnet-id":  "42",
+			"unit-name":  "wordpress/0",
+			"ports":      []interface{}{portRange("wordpress/0", 80)},
+		}, {
+			// Already split, so unchanged.
+			"_id":        uuid2 + ":m#4##u#wordpress/1",
+			"model-uuid": uuid2,
+			"machine-id": "4",
+			"subnet-id":  "",
+			"unit-name":  "wordpress/1",
+			"ports":      []interface{}{portRange("wordpress/1", 443)},
+		},
+	}
+
+	sort.Sort(expected)
+	s.assertUpgradedData(c, SplitPortsDocsByUnit, upgradedData(col, expected))
+}
+
 func (s *upgradesSuite) makeSpace(c *gc.C, uuid, name, id string) {
 	coll, closer := s.state.db().GetRawCollection(spacesC)
 	defer closer()
@@ -3818,3 +3948,148 @@ type docById []bson.M
 func (d docById) Len() int           { return len(d) }
 func (d docById) Swap(i, j int)      { d[i], d[j] = d[j], d[i] }
 func (d docById) Less(i, j int) bool { return d[i]["_id"].(string) < d[j]["_id"].(string) }
+
+func (s *upgradesSuite) TestAddModelAliases(c *gc.C) {
+	modelsCol, modelsCloser := s.state.db().GetRawCollection(modelsC)
+	defer modelsCloser()
+	aliasesCol, aliasesCloser := s.state.db().GetRawCollection(modelAliasesC)
+	defer aliasesCloser()
+
+	model1 := s.makeModel(c, "model-1", coretesting.Attrs{})
+	defer model1.Close()
+
+	// Simulate models created before aliases were introduced.
+	_, err := aliasesCol.RemoveAll(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = modelsCol.UpdateAll(nil, bson.M{"$unset": bson.M{"alias": 1}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	for i := 0; i < 2; i++ {
+		// The upgrade is idempotent.
+		err = AddModelAliases(s.pool)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	for _, uuid := range []string{s.state.ModelUUID(), model1.ModelUUID()} {
+		m, ph, err := s.pool.GetModel(uuid)
+		c.Assert(err, jc.ErrorIsNil)
+		ph.Release()
+		c.Check(m.Alias(), gc.Equals, uuid[:8])
+		resolved, err := s.state.ResolveModelUUID(m.Alias())
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(resolved, gc.Equals, uuid)
+	}
+	count, err := aliasesCol.Count()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(count, gc.Equals, 2)
+}
+
+func (s *upgradesSuite) TestCollectionSnapshotsRollBackUpgrade(c *gc.C) {
+	modelsCol, modelsCloser := s.state.db().GetRawCollection(modelsC)
+	defer modelsCloser()
+	aliasesCol, aliasesCloser := s.state.db().GetRawCollection(modelAliasesC)
+	defer aliasesCloser()
+
+	_, err := aliasesCol.RemoveAll(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = modelsCol.UpdateAll(nil, bson.M{"$unset": bson.M{"alias": 1}})
+	c.Assert(err, jc.ErrorIsNil)
+	var before []bson.M
+	err = modelsCol.Find(nil).Select(bson.M{"txn-queue": 0}).Sort("_id").All(&before)
+	c.Assert(err, jc.ErrorIsNil)
+
+	collNames := []string{modelsC, modelAliasesC}
+	err = SnapshotCollections(s.pool, "add-model-aliases", collNames)
+	c.Assert(err, jc.ErrorIsNil)
+	err = AddModelAliases(s.pool)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Taking the snapshot again keeps the one taken before the step.
+	err = SnapshotCollections(s.pool, "add-model-aliases", collNames)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = RestoreCollectionSnapshots(s.pool, "add-model-aliases", collNames)
+	c.Assert(err, jc.ErrorIsNil)
+	var after []bson.M
+	err = modelsCol.Find(nil).Select(bson.M{"txn-queue": 0}).Sort("_id").All(&after)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(after, jc.DeepEquals, before)
+	count, err := aliasesCol.Count()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(count, gc.Equals, 0)
+
+	// The restored documents can be updated again.
+	err = AddModelAliases(s.pool)
+	c.Assert(err, jc.ErrorIsNil)
+	m, err := s.state.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(m.Alias(), gc.Equals, s.state.ModelUUID()[:8])
+
+	// The snapshots were removed by the restore, so restoring again
+	// leaves the collections alone.
+	err = RestoreCollectionSnapshots(s.pool, "add-model-aliases", collNames)
+	c.Assert(err, jc.ErrorIsNil)
+	count, err = aliasesCol.Count()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(count, gc.Equals, 2)
+}
+
+func (s *upgradesSuite) TestResumeCollectionSnapshotRestores(c *gc.C) {
+	aliasesCol, aliasesCloser := s.state.db().GetRawCollection(modelAliasesC)
+	defer aliasesCloser()
+	restoresCol, restoresCloser := s.state.db().GetRawCollection(upgradeSnapshotRestoresC)
+	defer restoresCloser()
+
+	_, err := aliasesCol.RemoveAll(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	indexes, err := aliasesCol.Indexes()
+	c.Assert(err, jc.ErrorIsNil)
+
+	collNames := []string{modelsC, modelAliasesC}
+	err = SnapshotCollections(s.pool, "add-model-aliases", collNames)
+	c.Assert(err, jc.ErrorIsNil)
+	err = AddModelAliases(s.pool)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Nothing to resume.
+	err = ResumeCollectionSnapshotRestores(s.pool)
+	c.Assert(err, jc.ErrorIsNil)
+	count, err := aliasesCol.Count()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(count, gc.Equals, 2)
+
+	// Simulate a restore interrupted once it was recorded.
+	err = restoresCol.Insert(&upgradeSnapshotRestoreDoc{
+		DocID:       "add-model-aliases",
+		Collections: collNames,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = ResumeCollectionSnapshotRestores(s.pool)
+	c.Assert(err, jc.ErrorIsNil)
+	count, err = aliasesCol.Count()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(count, gc.Equals, 0)
+	count, err = restoresCol.Count()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(count, gc.Equals, 0)
+
+	// The restored collection keeps its indexes.
+	restored, err := aliasesCol.Indexes()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(restored, jc.DeepEquals, indexes)
+}
+
+func (s *upgradesSuite) TestDropCollectionSnapshots(c *gc.C) {
+	collNames := []string{modelsC}
+	err := SnapshotCollections(s.pool, "step", collNames)
+	c.Assert(err, jc.ErrorIsNil)
+	for i := 0; i < 2; i++ {
+		// Dropping is idempotent.
+		err = DropCollectionSnapshots(s.pool, "step", collNames)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	names, err := s.state.MongoSession().DB(jujuDB).CollectionNames()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(names, gc.Not(jc.Contains), upgradeSnapshotName("step", modelsC))
+}
@@ -0,0 +1,257 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// upgradeSnapshotBatchSize is the number of documents copied at a time
+// when taking or restoring an upgrade snapshot.
+const upgradeSnapshotBatchSize = 1000
+
+// upgradeSnapshotName returns the name of the collection holding the
+// snapshot of the named collection taken for the upgrade step with the
+// given key.
+func upgradeSnapshotName(key, collName string) string {
+	return "upgradeSnapshots." + key + "." + collName
+}
+
+// SnapshotCollections copies each of the named collections, so that the
+// upgrade step with the given key can be rolled back by
+// RestoreCollectionSnapshots. A collection that already has a snapshot
+// for the step is left alone: the step was interrupted, or its upgrade
+// not rolled back, and the existing snapshot holds the collection as it
+// was before the step first ran.
+func SnapshotCollections(pool *StatePool, key string, collNames []string) error {
+	st := pool.SystemState()
+	for _, collName := range collNames {
+		if err := snapshotCollection(st, key, collName); err != nil {
+			return errors.Annotatef(err, "cannot snapshot %q", collName)
+		}
+	}
+	return nil
+}
+
+func snapshotCollection(st *State, key, collName string) error {
+	coll, closer := st.db().GetRawCollection(collName)
+	defer closer()
+	db := coll.Database
+
+	names, err := db.CollectionNames()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	existing := set.NewStrings(names...)
+	snapshotName := upgradeSnapshotName(key, collName)
+	if existing.Contains(snapshotName) {
+		return nil
+	}
+
+	// The documents are copied to a temporary collection, which is
+	// renamed once complete, so that a snapshot is never partial.
+	tmp := db.C(snapshotName + ".tmp")
+	if existing.Contains(tmp.Name) {
+		if err := tmp.DropCollection(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if err := tmp.Create(&mgo.CollectionInfo{}); err != nil {
+		return errors.Trace(err)
+	}
+	if err := copyDocs(coll, tmp, nil); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(db.Session.DB("admin").Run(bson.D{
+		{"renameCollection", db.Name + "." + tmp.Name},
+		{"to", db.Name + "." + snapshotName},
+	}, nil))
+}
+
+// upgradeSnapshotRestoreDoc records that the snapshots taken for the
+// upgrade step with the given key are being restored.
+type upgradeSnapshotRestoreDoc struct {
+	DocID       string   `bson:"_id"`
+	Collections []string `bson:"collections"`
+}
+
+// RestoreCollectionSnapshots replaces the contents of each of the named
+// collections with the snapshot taken by SnapshotCollections for the
+// upgrade step with the given key, and removes the snapshots. A
+// collection without a snapshot was not changed by the step, or has
+// already been restored, and is left alone. Restored documents are given
+// empty transaction queues, since the transactions queued when the
+// snapshot was taken have since completed.
+//
+// The collections are replaced outside of mgo/txn, so watchers do not
+// see the restore; it is only made while the controller's workers are
+// stopped for an upgrade. The restore is recorded before it starts, so
+// that ResumeCollectionSnapshotRestores finishes it if it is
+// interrupted.
+func RestoreCollectionSnapshots(pool *StatePool, key string, collNames []string) error {
+	st := pool.SystemState()
+	restores, closer := st.db().GetRawCollection(upgradeSnapshotRestoresC)
+	defer closer()
+
+	_, err := restores.UpsertId(key, &upgradeSnapshotRestoreDoc{
+		DocID:       key,
+		Collections: collNames,
+	})
+	if err != nil {
+		return errors.Annotatef(err, "cannot record restore of snapshots %q", key)
+	}
+	return errors.Trace(restoreCollections(st, restores, key, collNames))
+}
+
+// ResumeCollectionSnapshotRestores finishes any restores started by
+// RestoreCollectionSnapshots that were interrupted. It must be called
+// before upgrade steps are run, so that they don't run against
+// collections that are partly restored, or take snapshots of them.
+func ResumeCollectionSnapshotRestores(pool *StatePool) error {
+	st := pool.SystemState()
+	restores, closer := st.db().GetRawCollection(upgradeSnapshotRestoresC)
+	defer closer()
+
+	var docs []upgradeSnapshotRestoreDoc
+	if err := restores.Find(nil).All(&docs); err != nil {
+		return errors.Annotate(err, "cannot read snapshot restores")
+	}
+	for _, doc := range docs {
+		upgradesLogger.Infof("resuming restore of upgrade snapshots %q", doc.DocID)
+		if err := restoreCollections(st, restores, doc.DocID, doc.Collections); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// restoreCollections restores the snapshots of the named collections
+// taken for the upgrade step with the given key, and removes the record
+// of the restore once all are restored.
+func restoreCollections(st *State, restores *mgo.Collection, key string, collNames []string) error {
+	for _, collName := range collNames {
+		if err := restoreCollection(st, key, collName); err != nil {
+			return errors.Annotatef(err, "cannot restore %q", collName)
+		}
+	}
+	if err := restores.RemoveId(key); err != nil && err != mgo.ErrNotFound {
+		return errors.Annotatef(err, "cannot remove record of restore of snapshots %q", key)
+	}
+	return nil
+}
+
+func restoreCollection(st *State, key, collName string) error {
+	coll, closer := st.db().GetRawCollection(collName)
+	defer closer()
+	db := coll.Database
+
+	names, err := db.CollectionNames()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	existing := set.NewStrings(names...)
+	snapshotName := upgradeSnapshotName(key, collName)
+	if !existing.Contains(snapshotName) {
+		return nil
+	}
+	snapshot := db.C(snapshotName)
+
+	// The documents are copied to a temporary collection, with the
+	// indexes of the collection being restored, which then replaces
+	// it, so that the collection is never partly restored.
+	tmp := db.C(snapshotName + ".restore")
+	if existing.Contains(tmp.Name) {
+		if err := tmp.DropCollection(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if err := tmp.Create(&mgo.CollectionInfo{}); err != nil {
+		return errors.Trace(err)
+	}
+	err = copyDocs(snapshot, tmp, func(doc bson.M) {
+		delete(doc, "txn-queue")
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if existing.Contains(collName) {
+		indexes, err := coll.Indexes()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, index := range indexes {
+			if index.Name == "_id_" {
+				continue
+			}
+			if err := tmp.EnsureIndex(index); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+	err = db.Session.DB("admin").Run(bson.D{
+		{"renameCollection", db.Name + "." + tmp.Name},
+		{"to", db.Name + "." + collName},
+		{"dropTarget", true},
+	}, nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(snapshot.DropCollection())
+}
+
+// DropCollectionSnapshots removes the snapshots of the named collections
+// taken for the upgrade step with the given key, once the upgrade has
+// completed.
+func DropCollectionSnapshots(pool *StatePool, key string, collNames []string) error {
+	st := pool.SystemState()
+	for _, collName := range collNames {
+		coll, closer := st.db().GetRawCollection(collName)
+		names, err := coll.Database.CollectionNames()
+		if err == nil {
+			snapshotName := upgradeSnapshotName(key, collName)
+			if set.NewStrings(names...).Contains(snapshotName) {
+				err = coll.Database.C(snapshotName).DropCollection()
+			}
+		}
+		closer()
+		if err != nil {
+			return errors.Annotatef(err, "cannot drop snapshot of %q", collName)
+		}
+	}
+	return nil
+}
+
+// copyDocs inserts every document in src into dst, in batches, after
+// passing it to modify if that is not nil.
+func copyDocs(src, dst *mgo.Collection, modify func(bson.M)) error {
+	iter := src.Find(nil).Iter()
+	var (
+		doc   bson.M
+		batch []interface{}
+	)
+	for iter.Next(&doc) {
+		if modify != nil {
+			modify(doc)
+		}
+		batch = append(batch, doc)
+		doc = nil
+		if len(batch) == upgradeSnapshotBatchSize {
+			if err := dst.Insert(batch...); err != nil {
+				iter.Close()
+				return errors.Trace(err)
+			}
+			batch = nil
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	if len(batch) > 0 {
+		return errors.Trace(dst.Insert(batch...))
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/version"
+	"gopkg.in/juju/names.v3"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// upgradeStepsCompletedDoc records the version for which an agent last
+// completed its upgrade steps.
+type upgradeStepsCompletedDoc struct {
+	DocID     string `bson:"_id"`
+	ModelUUID string `bson:"model-uuid"`
+	Agent     string `bson:"agent"`
+	Version   string `bson:"version"`
+	Completed int64  `bson:"completed"`
+}
+
+// AgentUpgradeSteps describes the upgrade steps last completed by an
+// agent.
+type AgentUpgradeSteps struct {
+	// Tag identifies the agent.
+	Tag names.Tag
+
+	// Version is the version for which the agent last completed its
+	// upgrade steps. It is zero if the agent hasn't reported
+	// completing any.
+	Version version.Number
+
+	// Completed is when the agent completed the upgrade steps.
+	Completed time.Time
+}
+
+// SetUpgradeStepsCompleted records that the agent with the given tag
+// has completed its upgrade steps for the given version.
+func (st *State) SetUpgradeStepsCompleted(tag names.Tag, vers version.Number) error {
+	key := tag.String()
+	completed := st.clock().Now().UnixNano()
+	buildTxn := func(int) ([]txn.Op, error) {
+		coll, closer := st.db().GetCollection(upgradeStepsCompletedC)
+		defer closer()
+		n, err := coll.FindId(key).Count()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if n == 0 {
+			return []txn.Op{{
+				C:      upgradeStepsCompletedC,
+				Id:     st.docID(key),
+				Assert: txn.DocMissing,
+				Insert: &upgradeStepsCompletedDoc{
+					DocID:     st.docID(key),
+					ModelUUID: st.ModelUUID(),
+					Agent:     key,
+					Version:   vers.String(),
+					Completed: completed,
+				},
+			}}, nil
+		}
+		return []txn.Op{{
+			C:      upgradeStepsCompletedC,
+			Id:     st.docID(key),
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{
+				{"version", vers.String()},
+				{"completed", completed},
+			}}},
+		}}, nil
+	}
+	if err := st.db().Run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot record upgrade steps completed by %s", names.ReadableString(tag))
+	}
+	return nil
+}
+
+// UpgradeStepsCompleted returns the upgrade steps last completed by the
+// agent with the given tag.
+func (st *State) UpgradeStepsCompleted(tag names.Tag) (AgentUpgradeSteps, error) {
+	coll, closer := st.db().GetCollection(upgradeStepsCompletedC)
+	defer closer()
+
+	var doc upgradeStepsCompletedDoc
+	err := coll.FindId(tag.String()).One(&doc)
+	if err == mgo.ErrNotFound {
+		return AgentUpgradeSteps{Tag: tag}, nil
+	} else if err != nil {
+		return AgentUpgradeSteps{}, errors.Annotatef(err, "cannot get upgrade steps completed by %s", names.ReadableString(tag))
+	}
+	return doc.agentUpgradeSteps(tag)
+}
+
+// UpgradeStragglers returns the machine agents in the model that
+// haven't completed their upgrade steps for the given version. Build
+// numbers are irrelevant to upgrade steps, and are ignored.
+func (st *State) UpgradeStragglers(target version.Number) ([]AgentUpgradeSteps, error) {
+	coll, closer := st.db().GetCollection(upgradeStepsCompletedC)
+	defer closer()
+
+	var docs []upgradeStepsCompletedDoc
+	if err := coll.Find(nil).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get upgrade steps completed")
+	}
+	byAgent := make(map[string]upgradeStepsCompletedDoc, len(docs))
+	for _, doc := range docs {
+		byAgent[doc.Agent] = doc
+	}
+
+	machines, err := st.AllMachines()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	target.Build = 0
+	var stragglers []AgentUpgradeSteps
+	for _, m := range machines {
+		if m.Life() == Dead {
+			continue
+		}
+		steps := AgentUpgradeSteps{Tag: m.Tag()}
+		if doc, ok := byAgent[m.Tag().String()]; ok {
+			if steps, err = doc.agentUpgradeSteps(m.Tag()); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		completed := steps.Version
+		completed.Build = 0
+		if completed.Compare(target) < 0 {
+			stragglers = append(stragglers, steps)
+		}
+	}
+	return stragglers, nil
+}
+
+func (doc upgradeStepsCompletedDoc) agentUpgradeSteps(tag names.Tag) (AgentUpgradeSteps, error) {
+	vers, err := version.Parse(doc.Version)
+	if err != nil {
+		return AgentUpgradeSteps{}, errors.Annotatef(err, "invalid upgrade steps version for %s", names.ReadableString(tag))
+	}
+	return AgentUpgradeSteps{
+		Tag:       tag,
+		Version:   vers,
+		Completed: time.Unix(0, doc.Completed).UTC(),
+	}, nil
+}
+
+func removeUpgradeStepsCompletedOp(st *State, tag names.Tag) txn.Op {
+	return txn.Op{
+		C:      upgradeStepsCompletedC,
+		Id:     st.docID(tag.String()),
+		Remove: true,
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type UpgradeStepsCompletedSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&UpgradeStepsCompletedSuite{})
+
+func (s *UpgradeStepsCompletedSuite) TestSetUpgradeStepsCompleted(c *gc.C) {
+	m := s.Factory.MakeMachine(c, nil)
+
+	steps, err := s.State.UpgradeStepsCompleted(m.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(steps, jc.DeepEquals, state.AgentUpgradeSteps{Tag: m.Tag()})
+
+	for _, vers := range []string{"2.6.10", "2.7.0"} {
+		err = s.State.SetUpgradeStepsCompleted(m.Tag(), version.MustParse(vers))
+		c.Assert(err, jc.ErrorIsNil)
+		steps, err = s.State.UpgradeStepsCompleted(m.Tag())
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(steps.Tag, gc.Equals, m.Tag())
+		c.Check(steps.Version, gc.Equals, version.MustParse(vers))
+		c.Check(steps.Completed.IsZero(), jc.IsFalse)
+	}
+}
+
+func (s *UpgradeStepsCompletedSuite) TestUpgradeStragglers(c *gc.C) {
+	m0 := s.Factory.MakeMachine(c, nil)
+	m1 := s.Factory.MakeMachine(c, nil)
+	m2 := s.Factory.MakeMachine(c, nil)
+
+	err := s.State.SetUpgradeStepsCompleted(m0.Tag(), version.MustParse("2.7.0.1"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetUpgradeStepsCompleted(m1.Tag(), version.MustParse("2.6.10"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	stragglers, err := s.State.UpgradeStragglers(version.MustParse("2.7.0.2"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stragglers, gc.HasLen, 2)
+	c.Check(stragglers[0].Tag, gc.Equals, m1.Tag())
+	c.Check(stragglers[0].Version, gc.Equals, version.MustParse("2.6.10"))
+	c.Check(stragglers[1], jc.DeepEquals, state.AgentUpgradeSteps{Tag: m2.Tag()})
+}
+
+func (s *UpgradeStepsCompletedSuite) TestRemovingMachineRemovesUpgradeSteps(c *gc.C) {
+	m := s.Factory.MakeMachine(c, nil)
+	err := s.State.SetUpgradeStepsCompleted(m.Tag(), version.MustParse("2.7.0"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = m.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	steps, err := s.State.UpgradeStepsCompleted(m.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(steps, jc.DeepEquals, state.AgentUpgradeSteps{Tag: m.Tag()})
+}
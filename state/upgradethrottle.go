@@ -0,0 +1,120 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// slowUpgradeBatch is how long processing a batch of documents may take
+// before the database is considered to be under load, and the upgrade
+// step backs off.
+var slowUpgradeBatch = time.Second
+
+// minUpgradeBackoff is the delay after the first slow batch.
+const minUpgradeBackoff = 100 * time.Millisecond
+
+// upgradeThrottle paces the batches processed by heavyweight upgrade
+// steps, so that bulk collection rewrites don't starve other users of
+// the database.
+type upgradeThrottle struct {
+	clock      clock.Clock
+	batchSize  int
+	sleepTime  time.Duration
+	maxBackoff time.Duration
+
+	// backoff is the delay after the last batch if it was slow,
+	// doubling each slow batch up to maxBackoff.
+	backoff time.Duration
+}
+
+// newUpgradeThrottle returns an upgradeThrottle configured by the
+// controller config.
+func newUpgradeThrottle(st *State) (*upgradeThrottle, error) {
+	cfg, err := st.ControllerConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &upgradeThrottle{
+		clock:      st.clock(),
+		batchSize:  cfg.UpgradeBatchSize(),
+		sleepTime:  cfg.UpgradeBatchSleepTime(),
+		maxBackoff: cfg.UpgradeBatchMaxBackoff(),
+	}, nil
+}
+
+// delay returns how long to wait before the next batch, given how long
+// the last batch took to process.
+func (t *upgradeThrottle) delay(took time.Duration) time.Duration {
+	if took < slowUpgradeBatch {
+		t.backoff = 0
+		return t.sleepTime
+	}
+	if t.backoff == 0 {
+		t.backoff = minUpgradeBackoff
+	} else {
+		t.backoff *= 2
+	}
+	if t.backoff > t.maxBackoff {
+		t.backoff = t.maxBackoff
+	}
+	if t.backoff < t.sleepTime {
+		return t.sleepTime
+	}
+	return t.backoff
+}
+
+// runThrottled calls process with successive batches of the IDs of the
+// model's documents in the named collection, in ID order, starting
+// after afterID if it isn't empty. Batches are sized and paced by the
+// controller config, backing off while the database is slow.
+func runThrottled(st *State, collName, afterID string, process func(ids []string) error) error {
+	throttle, err := newUpgradeThrottle(st)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	coll, closer := st.db().GetCollection(collName)
+	defer closer()
+	for {
+		query := bson.D{}
+		if afterID != "" {
+			query = bson.D{{"_id", bson.D{{"$gt", afterID}}}}
+		}
+		var docs []struct {
+			DocID string `bson:"_id"`
+		}
+		start := throttle.clock.Now()
+		err := coll.Find(query).Select(bson.D{{"_id", 1}}).Sort("_id").Limit(throttle.batchSize).All(&docs)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if len(docs) == 0 {
+			return nil
+		}
+		ids := make([]string, len(docs))
+		for i, doc := range docs {
+			ids[i] = doc.DocID
+		}
+		if err := process(ids); err != nil {
+			return errors.Trace(err)
+		}
+		if len(docs) < throttle.batchSize {
+			return nil
+		}
+		afterID = ids[len(ids)-1]
+
+		delay := throttle.delay(throttle.clock.Now().Sub(start))
+		if delay > throttle.sleepTime {
+			upgradesLogger.Debugf("backing off %v before next batch of %q", delay, collName)
+		}
+		if delay > 0 {
+			<-throttle.clock.After(delay)
+		}
+	}
+}
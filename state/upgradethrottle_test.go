@@ -0,0 +1,74 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/core/network"
+)
+
+type upgradeThrottleSuite struct {
+	internalStateSuite
+}
+
+var _ = gc.Suite(&upgradeThrottleSuite{})
+
+func (s *upgradeThrottleSuite) TestNewUpgradeThrottle(c *gc.C) {
+	err := s.state.UpdateControllerConfig(map[string]interface{}{
+		controller.UpgradeBatchSize:       50,
+		controller.UpgradeBatchSleepTime:  "20ms",
+		controller.UpgradeBatchMaxBackoff: "1s",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	throttle, err := newUpgradeThrottle(s.state)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(throttle.batchSize, gc.Equals, 50)
+	c.Check(throttle.sleepTime, gc.Equals, 20*time.Millisecond)
+	c.Check(throttle.maxBackoff, gc.Equals, time.Second)
+}
+
+func (s *upgradeThrottleSuite) TestDelayBacksOffWhileSlow(c *gc.C) {
+	throttle := &upgradeThrottle{
+		sleepTime:  10 * time.Millisecond,
+		maxBackoff: 300 * time.Millisecond,
+	}
+	fast := slowUpgradeBatch / 2
+	slow := slowUpgradeBatch
+
+	c.Check(throttle.delay(fast), gc.Equals, 10*time.Millisecond)
+	c.Check(throttle.delay(slow), gc.Equals, 100*time.Millisecond)
+	c.Check(throttle.delay(slow), gc.Equals, 200*time.Millisecond)
+	c.Check(throttle.delay(slow), gc.Equals, 300*time.Millisecond)
+	c.Check(throttle.delay(slow), gc.Equals, 300*time.Millisecond)
+	c.Check(throttle.delay(fast), gc.Equals, 10*time.Millisecond)
+	c.Check(throttle.delay(slow), gc.Equals, 100*time.Millisecond)
+}
+
+func (s *upgradeThrottleSuite) TestRunThrottled(c *gc.C) {
+	err := s.state.UpdateControllerConfig(map[string]interface{}{
+		controller.UpgradeBatchSize:      2,
+		controller.UpgradeBatchSleepTime: "0s",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	var docIDs []string
+	for _, cidr := range []string{"10.0.0.0/24", "10.0.1.0/24", "10.0.2.0/24", "10.0.3.0/24"} {
+		subnet, err := s.state.AddSubnet(network.SubnetInfo{CIDR: cidr})
+		c.Assert(err, jc.ErrorIsNil)
+		docIDs = append(docIDs, s.state.docID(subnet.ID()))
+	}
+
+	var processed [][]string
+	err = runThrottled(s.state, subnetsC, docIDs[0], func(ids []string) error {
+		processed = append(processed, ids)
+		return nil
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(processed, jc.DeepEquals, [][]string{docIDs[1:3], docIDs[3:]})
+}
@@ -284,6 +284,20 @@ type userDoc struct {
 	PasswordSalt string    `bson:"passwordsalt"`
 	CreatedBy    string    `bson:"createdby"`
 	DateCreated  time.Time `bson:"datecreated"`
+
+	// SecondFactorKey is the DER-encoded public key of the
+	// authenticator, such as a yubikey, with which the user makes
+	// second-factor assertions.
+	SecondFactorKey []byte `bson:"second-factor-key,omitempty"`
+
+	// SecondFactorSignCount is the signature counter reported by the
+	// authenticator in the last assertion it made.
+	SecondFactorSignCount int64 `bson:"second-factor-sign-count,omitempty"`
+
+	// SecondFactorEnrollmentHash is the hash of the one-time token,
+	// issued by a controller administrator, with which the user
+	// registers their first authenticator.
+	SecondFactorEnrollmentHash string `bson:"second-factor-enrollment-hash,omitempty"`
 }
 
 type userLastLoginDoc struct {
@@ -400,6 +414,136 @@ func (u *User) SecretKey() []byte {
 	return u.doc.SecretKey
 }
 
+// SecondFactorKey returns the DER-encoded public key of the user's
+// second-factor authenticator, if one has been registered.
+func (u *User) SecondFactorKey() []byte {
+	return u.doc.SecondFactorKey
+}
+
+// SetSecondFactorKey registers the DER-encoded public key of the user's
+// second-factor authenticator, replacing any registered previously, and
+// resets its signature counter. A nil key removes the registration.
+func (u *User) SetSecondFactorKey(key []byte) error {
+	if err := u.ensureNotDeleted(); err != nil {
+		return errors.Annotate(err, "cannot set second factor key")
+	}
+	update := bson.D{
+		{"$set", bson.D{{"second-factor-key", key}}},
+		{"$unset", bson.D{{"second-factor-sign-count", ""}}},
+	}
+	if key == nil {
+		update = bson.D{{"$unset", bson.D{
+			{"second-factor-key", ""},
+			{"second-factor-sign-count", ""},
+		}}}
+	}
+	ops := []txn.Op{{
+		C:      usersC,
+		Id:     strings.ToLower(u.Name()),
+		Assert: txn.DocExists,
+		Update: update,
+	}}
+	if err := u.st.db().RunTransaction(ops); err != nil {
+		return errors.Annotatef(err, "cannot set second factor key of user %q", u.Name())
+	}
+	u.doc.SecondFactorKey = key
+	u.doc.SecondFactorSignCount = 0
+	return nil
+}
+
+// SecondFactorEnrollmentHash returns the hash of the user's outstanding
+// second-factor enrollment token, if one has been issued.
+func (u *User) SecondFactorEnrollmentHash() string {
+	return u.doc.SecondFactorEnrollmentHash
+}
+
+// SetSecondFactorEnrollmentHash records the hash of a one-time token
+// with which the user may register their first second-factor
+// authenticator, replacing any issued previously.
+func (u *User) SetSecondFactorEnrollmentHash(hash string) error {
+	if err := u.ensureNotDeleted(); err != nil {
+		return errors.Annotate(err, "cannot set second factor enrollment token")
+	}
+	ops := []txn.Op{{
+		C:      usersC,
+		Id:     strings.ToLower(u.Name()),
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"second-factor-enrollment-hash", hash}}}},
+	}}
+	if err := u.st.db().RunTransaction(ops); err != nil {
+		return errors.Annotatef(err, "cannot set second factor enrollment token of user %q", u.Name())
+	}
+	u.doc.SecondFactorEnrollmentHash = hash
+	return nil
+}
+
+// EnrollSecondFactorKey registers the DER-encoded public key of the
+// user's first second-factor authenticator, consuming the enrollment
+// token with the given hash. It fails if the user already has a key, or
+// if the token has been consumed or replaced.
+func (u *User) EnrollSecondFactorKey(key []byte, enrollmentHash string) error {
+	if err := u.ensureNotDeleted(); err != nil {
+		return errors.Annotate(err, "cannot enroll second factor key")
+	}
+	if enrollmentHash == "" {
+		return errors.NotValidf("empty enrollment token hash")
+	}
+	ops := []txn.Op{{
+		C:  usersC,
+		Id: strings.ToLower(u.Name()),
+		Assert: bson.D{
+			{"second-factor-key", bson.D{{"$exists", false}}},
+			{"second-factor-enrollment-hash", enrollmentHash},
+		},
+		Update: bson.D{
+			{"$set", bson.D{{"second-factor-key", key}}},
+			{"$unset", bson.D{
+				{"second-factor-enrollment-hash", ""},
+				{"second-factor-sign-count", ""},
+			}},
+		},
+	}}
+	if err := u.st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return errors.Errorf("cannot enroll second factor key of user %q: key already registered or token not valid", u.Name())
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot enroll second factor key of user %q", u.Name())
+	}
+	u.doc.SecondFactorKey = key
+	u.doc.SecondFactorEnrollmentHash = ""
+	u.doc.SecondFactorSignCount = 0
+	return nil
+}
+
+// SecondFactorSignCount returns the signature counter reported by the
+// user's authenticator in its last accepted assertion.
+func (u *User) SecondFactorSignCount() uint32 {
+	return uint32(u.doc.SecondFactorSignCount)
+}
+
+// UpdateSecondFactorSignCount records the signature counter reported by
+// the user's authenticator in an accepted assertion. It fails if the
+// recorded counter is no longer old, so that an assertion replayed
+// concurrently is accepted at most once.
+func (u *User) UpdateSecondFactorSignCount(old, new uint32) error {
+	assert := bson.D{{"second-factor-sign-count", int64(old)}}
+	if old == 0 {
+		assert = bson.D{{"second-factor-sign-count", bson.D{{"$in", []interface{}{nil, int64(0)}}}}}
+	}
+	ops := []txn.Op{{
+		C:      usersC,
+		Id:     strings.ToLower(u.Name()),
+		Assert: assert,
+		Update: bson.D{{"$set", bson.D{{"second-factor-sign-count", int64(new)}}}},
+	}}
+	if err := u.st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return errors.Errorf("cannot update second factor sign count of user %q: sign count changed", u.Name())
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot update second factor sign count of user %q", u.Name())
+	}
+	u.doc.SecondFactorSignCount = int64(new)
+	return nil
+}
+
 // SetPassword sets the password associated with the User.
 func (u *User) SetPassword(password string) error {
 	if err := u.ensureNotDeleted(); err != nil {
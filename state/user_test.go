@@ -115,6 +115,83 @@ func (s *UserSuite) TestSetPassword(c *gc.C) {
 	})
 }
 
+func (s *UserSuite) TestSetSecondFactorKey(c *gc.C) {
+	user := s.Factory.MakeUser(c, nil)
+	c.Assert(user.SecondFactorKey(), gc.IsNil)
+
+	err := user.SetSecondFactorKey([]byte("public-key"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.SecondFactorKey(), jc.DeepEquals, []byte("public-key"))
+
+	user, err = s.State.User(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.SecondFactorKey(), jc.DeepEquals, []byte("public-key"))
+
+	err = user.SetSecondFactorKey(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	user, err = s.State.User(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.SecondFactorKey(), gc.IsNil)
+}
+
+func (s *UserSuite) TestEnrollSecondFactorKey(c *gc.C) {
+	user := s.Factory.MakeUser(c, nil)
+	err := user.EnrollSecondFactorKey([]byte("public-key"), "hash")
+	c.Assert(err, gc.ErrorMatches, `cannot enroll second factor key of user ".*": key already registered or token not valid`)
+
+	err = user.SetSecondFactorEnrollmentHash("hash")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.SecondFactorEnrollmentHash(), gc.Equals, "hash")
+	err = user.EnrollSecondFactorKey([]byte("public-key"), "other")
+	c.Assert(err, gc.ErrorMatches, `cannot enroll second factor key of user ".*": key already registered or token not valid`)
+
+	err = user.EnrollSecondFactorKey([]byte("public-key"), "hash")
+	c.Assert(err, jc.ErrorIsNil)
+	user, err = s.State.User(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.SecondFactorKey(), jc.DeepEquals, []byte("public-key"))
+	c.Assert(user.SecondFactorEnrollmentHash(), gc.Equals, "")
+
+	// The token may only be used once, and only for the first key.
+	err = user.SetSecondFactorEnrollmentHash("hash")
+	c.Assert(err, jc.ErrorIsNil)
+	err = user.EnrollSecondFactorKey([]byte("another-key"), "hash")
+	c.Assert(err, gc.ErrorMatches, `cannot enroll second factor key of user ".*": key already registered or token not valid`)
+}
+
+func (s *UserSuite) TestUpdateSecondFactorSignCount(c *gc.C) {
+	user := s.Factory.MakeUser(c, nil)
+	err := user.SetSecondFactorKey([]byte("public-key"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.SecondFactorSignCount(), gc.Equals, uint32(0))
+
+	err = user.UpdateSecondFactorSignCount(0, 5)
+	c.Assert(err, jc.ErrorIsNil)
+	user, err = s.State.User(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.SecondFactorSignCount(), gc.Equals, uint32(5))
+
+	err = user.UpdateSecondFactorSignCount(0, 6)
+	c.Assert(err, gc.ErrorMatches, `cannot update second factor sign count of user ".*": sign count changed`)
+	err = user.UpdateSecondFactorSignCount(5, 6)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Replacing the key resets the count.
+	err = user.SetSecondFactorKey([]byte("new-key"))
+	c.Assert(err, jc.ErrorIsNil)
+	user, err = s.State.User(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.SecondFactorSignCount(), gc.Equals, uint32(0))
+}
+
+func (s *UserSuite) TestSetSecondFactorKeyDeletedUser(c *gc.C) {
+	user := s.Factory.MakeUser(c, nil)
+	err := s.State.RemoveUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = user.SetSecondFactorKey([]byte("public-key"))
+	c.Assert(err, gc.ErrorMatches, `cannot set second factor key: user "`+user.Name()+`" is permanently deleted`)
+}
+
 func (s *UserSuite) TestAddUserSetsSalt(c *gc.C) {
 	user := s.Factory.MakeUser(c, &factory.UserParams{Password: "a-password"})
 	salt, hash := state.GetUserPasswordSaltAndHash(user)
@@ -1007,6 +1007,70 @@ func newVolumeName(mb modelBackend, hostId string) (string, error) {
 	return id, nil
 }
 
+// AddExistingVolume imports an existing, already-provisioned
+// volume into the model. The volume will start out with
+// the status "detached". The volume will be associated with
+// the given storage name, with the allocated storage tag
+// being returned.
+func (sb *storageBackend) AddExistingVolume(
+	info VolumeInfo,
+	storageName string,
+) (_ names.StorageTag, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add existing volume")
+	if err := validateAddExistingVolume(info, storageName); err != nil {
+		return names.StorageTag{}, errors.Trace(err)
+	}
+	storageId, err := newStorageInstanceId(sb.mb, storageName)
+	if err != nil {
+		return names.StorageTag{}, errors.Trace(err)
+	}
+	storageTag := names.NewStorageTag(storageId)
+	volumeOps, _, err := sb.addVolumeOps(
+		VolumeParams{
+			Pool:       info.Pool,
+			Size:       info.Size,
+			volumeInfo: &info,
+			storage:    storageTag,
+		},
+		"", // no machine ID
+	)
+	if err != nil {
+		return names.StorageTag{}, errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:      storageInstancesC,
+		Id:     storageId,
+		Assert: txn.DocMissing,
+		Insert: &storageInstanceDoc{
+			Id:          storageId,
+			Kind:        StorageKindBlock,
+			StorageName: storageName,
+			Constraints: storageInstanceConstraints{
+				Pool: info.Pool,
+				Size: info.Size,
+			},
+		},
+	}}
+	ops = append(ops, volumeOps...)
+	if err := sb.mb.db().RunTransaction(ops); err != nil {
+		return names.StorageTag{}, errors.Trace(err)
+	}
+	return storageTag, nil
+}
+
+func validateAddExistingVolume(info VolumeInfo, storageName string) error {
+	if !storage.IsValidPoolName(info.Pool) {
+		return errors.NotValidf("pool name %q", info.Pool)
+	}
+	if !storageNameRE.MatchString(storageName) {
+		return errors.NotValidf("storage name %q", storageName)
+	}
+	if info.VolumeId == "" {
+		return errors.NotValidf("empty volume ID")
+	}
+	return nil
+}
+
 // addVolumeOps returns txn.Ops to create a new volume with the specified
 // parameters. If the supplied host ID is non-empty, and the storage
 // provider is machine-scoped, then the volume will be scoped to that
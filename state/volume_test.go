@@ -11,6 +11,7 @@ import (
 
 	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/provider/dummy"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/testing"
@@ -948,6 +949,50 @@ func (s *VolumeStateSuite) TestVolumeBindingStorage(c *gc.C) {
 	c.Assert(volume.Life(), gc.Equals, state.Dying)
 }
 
+func (s *VolumeStateSuite) TestAddExistingVolume(c *gc.C) {
+	volInfoIn := state.VolumeInfo{
+		Pool:       "modelscoped",
+		Size:       123,
+		VolumeId:   "foo",
+		Persistent: true,
+	}
+	storageTag, err := s.storageBackend.AddExistingVolume(volInfoIn, "pgdata")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(storageTag, gc.Equals, names.NewStorageTag("pgdata/0"))
+
+	storageInstance, err := s.storageBackend.StorageInstance(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(storageInstance.Kind(), gc.Equals, state.StorageKindBlock)
+
+	volume := s.storageInstanceVolume(c, storageTag)
+	volInfoOut, err := volume.Info()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volInfoOut, jc.DeepEquals, volInfoIn)
+
+	volStatus, err := volume.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volStatus.Status, gc.Equals, status.Detached)
+}
+
+func (s *VolumeStateSuite) TestAddExistingVolumeEmptyVolumeId(c *gc.C) {
+	volInfoIn := state.VolumeInfo{
+		Pool: "modelscoped",
+		Size: 123,
+	}
+	_, err := s.storageBackend.AddExistingVolume(volInfoIn, "pgdata")
+	c.Assert(err, gc.ErrorMatches, "cannot add existing volume: empty volume ID not valid")
+}
+
+func (s *VolumeStateSuite) TestAddExistingVolumeFilesystemPool(c *gc.C) {
+	volInfoIn := state.VolumeInfo{
+		Pool:     "rootfs",
+		Size:     123,
+		VolumeId: "foo",
+	}
+	_, err := s.storageBackend.AddExistingVolume(volInfoIn, "pgdata")
+	c.Assert(err, gc.ErrorMatches, `cannot add existing volume: validating volume params: "rootfs" provider does not support "block" storage`)
+}
+
 func (s *VolumeStateSuite) setupStorageVolumeAttachment(c *gc.C) (state.Volume, *state.Machine, *state.Unit) {
 	_, u, storageTag := s.setupSingleStorage(c, "block", "modelscoped")
 	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
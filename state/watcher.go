@@ -2421,6 +2421,12 @@ func (st *State) WatchCleanups() NotifyWatcher {
 	return newNotifyCollWatcher(st, cleanupsC, isLocalID(st))
 }
 
+// WatchFirewallRules returns a NotifyWatcher that notifies when the
+// firewall rules of the model are saved.
+func (st *State) WatchFirewallRules() NotifyWatcher {
+	return newNotifyCollWatcher(st, firewallRulesC, isLocalID(st))
+}
+
 // actionStatusWatcher is a StringsWatcher that filters notifications
 // to Action Id's that match the ActionReceiver and ActionStatus set
 // provided.
@@ -2996,9 +3002,11 @@ func (w *openedPortsWatcher) Changes() <-chan []string {
 }
 
 // transformId converts a global key for a ports document (e.g.
-// "m#42#0.1.2.0/24") into a colon-separated string with the machine and subnet
-// IDs (e.g. "42:0.1.2.0/24"). Subnet ID (a.k.a. CIDR) can be empty for
-// backwards-compatibility.
+// "m#42#0.1.2.0/24#u#mysql/0") into a colon-separated string with the machine
+// and subnet IDs (e.g. "42:0.1.2.0/24"). Subnet ID (a.k.a. CIDR) can be empty for
+// backwards-compatibility. As each unit's ports are held in a separate
+// document, changes to the documents of units on the same machine and subnet
+// are reported as a single change.
 func (w *openedPortsWatcher) transformID(globalKey string) (string, error) {
 	parts, err := extractPortsIDParts(globalKey)
 	if err != nil {
@@ -3803,6 +3811,42 @@ func hashMachineAddresses(m *Machine) (string, error) {
 	return fmt.Sprintf("%x", hash.Sum(nil)), nil
 }
 
+// WatchEgressAddressesHash returns a StringsWatcher that emits a hash
+// of the unit's reported egress addresses whenever they change.
+func (u *Unit) WatchEgressAddressesHash() StringsWatcher {
+	uCopy := &Unit{
+		st:        u.st,
+		doc:       u.doc,
+		modelType: u.modelType,
+	}
+	w := &hashWatcher{
+		commonWatcher: newCommonWatcher(u.st),
+		out:           make(chan []string),
+		collection:    unitsC,
+		id:            u.doc.DocID,
+		hash: func() (string, error) {
+			return hashUnitEgressAddresses(uCopy)
+		},
+	}
+	w.start()
+	return w
+}
+
+func hashUnitEgressAddresses(u *Unit) (string, error) {
+	if err := u.Refresh(); err != nil {
+		return "", errors.Trace(err)
+	}
+	// SetEgressAddresses stores the bindings in sorted order.
+	hash := sha256.New()
+	for _, egress := range u.doc.EgressAddresses {
+		hash.Write([]byte(egress.Binding + "\x00"))
+		for _, addr := range egress.Addresses {
+			hash.Write([]byte(addr + "\x00"))
+		}
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
 // WatchServiceAddressesHash returns a StringsWatcher that emits a
 // hash of the unit's container address whenever it changes.
 func (a *Application) WatchServiceAddressesHash() StringsWatcher {
@@ -4,6 +4,7 @@
 package watcher
 
 import (
+	"sync/atomic"
 	"time"
 
 	"github.com/juju/errors"
@@ -99,6 +100,11 @@ type TxnWatcher struct {
 
 	// lastId is the most recent transaction id observed by a sync.
 	lastId interface{}
+
+	// deliveryLag holds, in nanoseconds, how long before the last sync
+	// the oldest transaction it found was made. It is accessed
+	// atomically, as it is read from outside the loop.
+	deliveryLag int64
 }
 
 // TxnWatcherConfig contains the configuration parameters required
@@ -213,6 +219,14 @@ func (w *TxnWatcher) Report() map[string]interface{} {
 	}
 }
 
+// DeliveryLag returns how long before the last sync the oldest of the
+// transactions it delivered was made, or zero if it delivered none.
+// Transaction times are only known to the second, so the lag may be
+// overstated by up to a second.
+func (w *TxnWatcher) DeliveryLag() time.Duration {
+	return time.Duration(atomic.LoadInt64(&w.deliveryLag))
+}
+
 // loop implements the main watcher loop.
 // period is the delay between each sync.
 func (w *TxnWatcher) loop() error {
@@ -256,6 +270,8 @@ func (w *TxnWatcher) loop() error {
 				// How many database records have we read. note: because we have to iterate until we get to lastId,
 				// this is often a bit bigger than total-sync-events
 				"iterator-step-count": w.iteratorStepCount,
+				// How far behind the transactions were we when we last delivered changes
+				"delivery-lag": w.DeliveryLag().String(),
 			}
 			select {
 			case <-w.tomb.Dying():
@@ -326,6 +342,7 @@ func (w *TxnWatcher) sync() (bool, error) {
 	seen := make(map[watchKey]bool)
 	first := true
 	lastId := w.lastId
+	var oldest time.Time
 	var entry bson.D
 	for iter.Next(&entry) {
 		w.iteratorStepCount++
@@ -344,6 +361,9 @@ func (w *TxnWatcher) sync() (bool, error) {
 		if id.Value == lastId {
 			break
 		}
+		if oid, ok := id.Value.(bson.ObjectId); ok && oid.Valid() {
+			oldest = oid.Time()
+		}
 		w.logger.Tracef("%p step %d got changelog document: %#v", w, w.iteratorStepCount, entry)
 		for _, c := range entry[1:] {
 			// See txn's Runner.ChangeLog for the structure of log entries.
@@ -388,5 +408,10 @@ func (w *TxnWatcher) sync() (bool, error) {
 	if err := iter.Close(); err != nil {
 		return false, errors.Annotate(err, "watcher iteration error")
 	}
+	var lag time.Duration
+	if !oldest.IsZero() {
+		lag = w.clock.Now().Sub(oldest)
+	}
+	atomic.StoreInt64(&w.deliveryLag, int64(lag))
 	return added, nil
 }
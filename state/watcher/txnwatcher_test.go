@@ -175,6 +175,22 @@ func (s *TxnWatcherSuite) TestInsert(c *gc.C) {
 	})
 }
 
+func (s *TxnWatcherSuite) TestDeliveryLag(c *gc.C) {
+	w, hub := s.newWatcher(c, 1)
+	c.Assert(w.DeliveryLag(), gc.Equals, time.Duration(0))
+
+	s.insert(c, "test", "a")
+
+	// The transaction was made at about the time the clock started,
+	// so it is delivered about a minute late.
+	s.advanceTime(c, time.Minute, 1)
+	hub.waitForExpected(c)
+
+	lag := w.DeliveryLag()
+	c.Assert(lag >= 50*time.Second, jc.IsTrue, gc.Commentf("lag %v", lag))
+	c.Assert(lag <= 62*time.Second, jc.IsTrue, gc.Commentf("lag %v", lag))
+}
+
 func (s *TxnWatcherSuite) TestUpdate(c *gc.C) {
 	s.insert(c, "test", "a")
 
@@ -207,8 +207,10 @@ type VolumeImporter interface {
 	// in the model.
 	//
 	// Implementations of ImportVolume should validate that the
-	// volume is not in use before allowing the import to proceed.
-	// Once it is imported, it is assumed to be in a detached state.
+	// volume is not in use, and that it can be attached to the
+	// model's machines (e.g. that its size and availability zone
+	// are compatible), before tagging it. Once it is imported, it
+	// is assumed to be in a detached state.
 	ImportVolume(
 		ctx context.ProviderCallContext,
 		volumeId string,
@@ -72,6 +72,15 @@ type StateBackend interface {
 	AddSubnetIdToSubnetDocs() error
 	ReplacePortsDocSubnetIDCIDR() error
 	EnsureRelationApplicationSettings() error
+	AddModelAliases() error
+	SplitPortsDocsByUnit() error
+	NormaliseSubnetCIDRs() error
+
+	SnapshotCollections(key string, collNames []string) error
+	RestoreCollectionSnapshots(key string, collNames []string) error
+	ResumeCollectionSnapshotRestores() error
+	DropCollectionSnapshots(key string, collNames []string) error
+	RemoveUpgradeCheckpoints(step string) error
 }
 
 // Model is an interface providing access to the details of a model within the
@@ -289,3 +298,35 @@ func (s stateBackend) ReplacePortsDocSubnetIDCIDR() error {
 func (s stateBackend) EnsureRelationApplicationSettings() error {
 	return state.EnsureRelationApplicationSettings(s.pool)
 }
+
+func (s stateBackend) AddModelAliases() error {
+	return state.AddModelAliases(s.pool)
+}
+
+func (s stateBackend) SplitPortsDocsByUnit() error {
+	return state.SplitPortsDocsByUnit(s.pool)
+}
+
+func (s stateBackend) NormaliseSubnetCIDRs() error {
+	return state.NormaliseSubnetCIDRs(s.pool)
+}
+
+func (s stateBackend) SnapshotCollections(key string, collNames []string) error {
+	return state.SnapshotCollections(s.pool, key, collNames)
+}
+
+func (s stateBackend) RestoreCollectionSnapshots(key string, collNames []string) error {
+	return state.RestoreCollectionSnapshots(s.pool, key, collNames)
+}
+
+func (s stateBackend) ResumeCollectionSnapshotRestores() error {
+	return state.ResumeCollectionSnapshotRestores(s.pool)
+}
+
+func (s stateBackend) DropCollectionSnapshots(key string, collNames []string) error {
+	return state.DropCollectionSnapshots(s.pool, key, collNames)
+}
+
+func (s stateBackend) RemoveUpgradeCheckpoints(step string) error {
+	return s.pool.SystemState().RemoveUpgradeCheckpoints(step)
+}
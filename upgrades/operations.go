@@ -39,6 +39,7 @@ var stateUpgradeOperations = func() []Operation {
 		upgradeToVersion{version.MustParse("2.6.3"), stateStepsFor263()},
 		upgradeToVersion{version.MustParse("2.6.5"), stateStepsFor265()},
 		upgradeToVersion{version.MustParse("2.7.0"), stateStepsFor27()},
+		upgradeToVersion{version.MustParse("2.8.0"), stateStepsFor28()},
 	}
 	return steps
 }
@@ -0,0 +1,175 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgrades
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/mongo"
+	"github.com/juju/juju/state"
+)
+
+// PreCheckBackend provides the access to the controller that
+// pre-upgrade checks need. None of its methods change anything.
+type PreCheckBackend interface {
+	MongoVersion() (string, error)
+	ControllerConfig() (controller.Config, error)
+	OrphanedDocCounts() (map[string]int, error)
+}
+
+// PreCheckContext holds what pre-upgrade checks inspect.
+type PreCheckContext struct {
+	// State is the controller's state.
+	State PreCheckBackend
+
+	// DataDir is the data directory of the controller agent. If it is
+	// empty, free disk space isn't checked.
+	DataDir string
+}
+
+// PreCheck is a read-only check, evaluated before any upgrade steps
+// run, for problems that would cause an upgrade to fail or that should
+// be resolved before upgrading.
+type PreCheck struct {
+	// Name identifies the check.
+	Name string
+
+	// Blocking is true if problems found by the check prevent the
+	// upgrade, rather than being reported as warnings.
+	Blocking bool
+
+	// Run returns a description of each problem found.
+	Run func(PreCheckContext) ([]string, error)
+}
+
+// PreCheckProblem describes a problem found by a pre-upgrade check.
+type PreCheckProblem struct {
+	Check    string
+	Message  string
+	Blocking bool
+}
+
+// preChecks returns the checks evaluated before upgrading a controller.
+var preChecks = func() []PreCheck {
+	return []PreCheck{
+		{Name: "upgrade-registry", Blocking: true, Run: checkUpgradeRegistry},
+		{Name: "disk-space", Blocking: true, Run: checkDiskSpace},
+		{Name: "mongo-version", Blocking: true, Run: checkMongoVersion},
+		{Name: "orphaned-docs", Run: checkOrphanedDocs},
+		{Name: "deprecated-config", Run: checkDeprecatedConfig},
+	}
+}
+
+// MinMongoVersion is the oldest version of mongo that can be
+// upgraded from.
+var MinMongoVersion = mongo.Mongo32wt
+
+// RunPreChecks evaluates all the pre-upgrade checks, returning the
+// problems found. A check that cannot be evaluated is reported as a
+// blocking problem.
+func RunPreChecks(ctx PreCheckContext) []PreCheckProblem {
+	var problems []PreCheckProblem
+	for _, check := range preChecks() {
+		messages, err := check.Run(ctx)
+		if err != nil {
+			logger.Errorf("pre-upgrade check %q failed: %v", check.Name, err)
+			problems = append(problems, PreCheckProblem{
+				Check:    check.Name,
+				Message:  fmt.Sprintf("cannot run check: %v", err),
+				Blocking: true,
+			})
+			continue
+		}
+		for _, message := range messages {
+			problems = append(problems, PreCheckProblem{
+				Check:    check.Name,
+				Message:  message,
+				Blocking: check.Blocking,
+			})
+		}
+	}
+	return problems
+}
+
+// CheckPreUpgrade evaluates all the pre-upgrade checks, logging any
+// warnings, and returns an error describing the blocking problems
+// found, if any.
+func CheckPreUpgrade(ctx PreCheckContext) error {
+	var blockers []string
+	for _, problem := range RunPreChecks(ctx) {
+		if !problem.Blocking {
+			logger.Warningf("pre-upgrade check %q: %s", problem.Check, problem.Message)
+			continue
+		}
+		blockers = append(blockers, fmt.Sprintf("%s: %s", problem.Check, problem.Message))
+	}
+	if len(blockers) > 0 {
+		return errors.Errorf("upgrade blocked by pre-upgrade checks:\n    %s", strings.Join(blockers, "\n    "))
+	}
+	return nil
+}
+
+func checkUpgradeRegistry(PreCheckContext) ([]string, error) {
+	return VerifyRegistry(state.CollectionNames()), nil
+}
+
+func checkDiskSpace(ctx PreCheckContext) ([]string, error) {
+	if ctx.DataDir == "" {
+		return nil, nil
+	}
+	if err := CheckFreeDiskSpace(ctx.DataDir, MinDiskSpaceMib); err != nil {
+		return []string{err.Error()}, nil
+	}
+	return nil, nil
+}
+
+func checkMongoVersion(ctx PreCheckContext) ([]string, error) {
+	v, err := ctx.State.MongoVersion()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	vers, err := mongo.NewVersion(v)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot parse mongo version %q", v)
+	}
+	if vers.NewerThan(MinMongoVersion) < 0 {
+		return []string{fmt.Sprintf(
+			"mongo %s is too old, at least %d.%d is required",
+			v, MinMongoVersion.Major, MinMongoVersion.Minor,
+		)}, nil
+	}
+	return nil, nil
+}
+
+func checkOrphanedDocs(ctx PreCheckContext) ([]string, error) {
+	counts, err := ctx.State.OrphanedDocCounts()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var messages []string
+	for name, count := range counts {
+		messages = append(messages, fmt.Sprintf(
+			"%d documents in %q belong to models that no longer exist", count, name,
+		))
+	}
+	sort.Strings(messages)
+	return messages, nil
+}
+
+func checkDeprecatedConfig(ctx PreCheckContext) ([]string, error) {
+	cfg, err := ctx.State.ControllerConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var messages []string
+	for _, attr := range cfg.DeprecatedAttributes() {
+		messages = append(messages, fmt.Sprintf("controller config %q is deprecated", attr))
+	}
+	return messages, nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgrades_test
+
+import (
+	"github.com/dustin/go-humanize"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/upgrades"
+)
+
+type precheckSuite struct {
+	testing.BaseSuite
+
+	backend *fakePreCheckBackend
+}
+
+var _ = gc.Suite(&precheckSuite{})
+
+func (s *precheckSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.backend = &fakePreCheckBackend{
+		mongoVersion: "3.6.8",
+		config:       testing.FakeControllerConfig(),
+	}
+}
+
+func (s *precheckSuite) TestRunPreChecksNoProblems(c *gc.C) {
+	s.PatchValue(&upgrades.MinDiskSpaceMib, uint64(0))
+	problems := upgrades.RunPreChecks(upgrades.PreCheckContext{
+		State:   s.backend,
+		DataDir: c.MkDir(),
+	})
+	c.Assert(problems, gc.HasLen, 0)
+	c.Assert(upgrades.CheckPreUpgrade(upgrades.PreCheckContext{State: s.backend}), jc.ErrorIsNil)
+}
+
+func (s *precheckSuite) TestRunPreChecks(c *gc.C) {
+	s.PatchValue(&upgrades.MinDiskSpaceMib, uint64(humanize.PiByte/humanize.MiByte))
+	s.backend.mongoVersion = "2.4.6"
+	s.backend.orphans = map[string]int{"units": 2, "machines": 1}
+	s.backend.config[controller.CAASOperatorImagePath] = "jujusolutions/jujud-operator"
+
+	problems := upgrades.RunPreChecks(upgrades.PreCheckContext{
+		State:   s.backend,
+		DataDir: "/",
+	})
+	c.Assert(problems, gc.HasLen, 5)
+	c.Check(problems[0].Check, gc.Equals, "disk-space")
+	c.Check(problems[0].Message, gc.Matches, `not enough free disk space on "/" for upgrade: .*`)
+	c.Check(problems[0].Blocking, jc.IsTrue)
+	c.Check(problems[1:], jc.DeepEquals, []upgrades.PreCheckProblem{{
+		Check:    "mongo-version",
+		Message:  "mongo 2.4.6 is too old, at least 3.2 is required",
+		Blocking: true,
+	}, {
+		Check:   "orphaned-docs",
+		Message: `1 documents in "machines" belong to models that no longer exist`,
+	}, {
+		Check:   "orphaned-docs",
+		Message: `2 documents in "units" belong to models that no longer exist`,
+	}, {
+		Check:   "deprecated-config",
+		Message: `controller config "caas-operator-image-path" is deprecated`,
+	}})
+}
+
+func (s *precheckSuite) TestRunPreChecksError(c *gc.C) {
+	s.backend.err = errors.New("boom")
+	problems := upgrades.RunPreChecks(upgrades.PreCheckContext{State: s.backend})
+	c.Assert(problems, jc.DeepEquals, []upgrades.PreCheckProblem{{
+		Check:    "mongo-version",
+		Message:  "cannot run check: boom",
+		Blocking: true,
+	}, {
+		Check:    "orphaned-docs",
+		Message:  "cannot run check: boom",
+		Blocking: true,
+	}, {
+		Check:    "deprecated-config",
+		Message:  "cannot run check: boom",
+		Blocking: true,
+	}})
+}
+
+func (s *precheckSuite) TestCheckPreUpgrade(c *gc.C) {
+	s.backend.mongoVersion = "2.4.6"
+	s.backend.orphans = map[string]int{"units": 2}
+	err := upgrades.CheckPreUpgrade(upgrades.PreCheckContext{State: s.backend})
+	c.Assert(err, gc.ErrorMatches, "upgrade blocked by pre-upgrade checks:\n"+
+		"    mongo-version: mongo 2.4.6 is too old, at least 3.2 is required")
+}
+
+type fakePreCheckBackend struct {
+	mongoVersion string
+	config       controller.Config
+	orphans      map[string]int
+	err          error
+}
+
+func (b *fakePreCheckBackend) MongoVersion() (string, error) {
+	return b.mongoVersion, b.err
+}
+
+func (b *fakePreCheckBackend) ControllerConfig() (controller.Config, error) {
+	return b.config, b.err
+}
+
+func (b *fakePreCheckBackend) OrphanedDocCounts() (map[string]int, error) {
+	return b.orphans, b.err
+}
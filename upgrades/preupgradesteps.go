@@ -21,7 +21,14 @@ type PreUpgradeStepsFunc func(_ *state.StatePool, _ agent.Config, isController,
 
 // PreUpgradeSteps runs various checks and prepares for performing an upgrade.
 // If any check fails, an error is returned which aborts the upgrade.
-func PreUpgradeSteps(_ *state.StatePool, agentConf agent.Config, isController, isMaster, isCaas bool) error {
+func PreUpgradeSteps(pool *state.StatePool, agentConf agent.Config, isController, isMaster, isCaas bool) error {
+	if isMaster {
+		// The disk space of each machine is checked below, so only
+		// the checks of the controller's state are needed here.
+		if err := CheckPreUpgrade(PreCheckContext{State: pool.SystemState()}); err != nil {
+			return errors.Trace(err)
+		}
+	}
 	if isCaas {
 		logger.Debugf("skipping disk space checks for k8s controllers")
 		return nil
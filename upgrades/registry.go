@@ -0,0 +1,58 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgrades
+
+import (
+	"fmt"
+
+	"github.com/juju/collections/set"
+)
+
+// VerifyRegistry checks the registered state and API upgrade operations,
+// returning a description of each problem found. The problems checked
+// for are steps with the same description in an operation, operations
+// that aren't in increasing version order, and steps that depend on
+// collections that aren't in the given collections in use.
+func VerifyRegistry(collections []string) []string {
+	known := set.NewStrings(collections...)
+	problems := verifyOperations("state", stateUpgradeOperations(), known)
+	return append(problems, verifyOperations("API", upgradeOperations(), known)...)
+}
+
+func verifyOperations(kind string, ops []Operation, collections set.Strings) []string {
+	var problems []string
+	for i, op := range ops {
+		vers := op.TargetVersion()
+		if i > 0 {
+			if prev := ops[i-1].TargetVersion(); vers.Compare(prev) <= 0 {
+				problems = append(problems, fmt.Sprintf(
+					"%s upgrade operation for %s follows the operation for %s", kind, vers, prev,
+				))
+			}
+		}
+		descriptions := set.NewStrings()
+		for _, step := range op.Steps() {
+			description := step.Description()
+			if descriptions.Contains(description) {
+				problems = append(problems, fmt.Sprintf(
+					"%s upgrade step %q for %s is duplicated", kind, description, vers,
+				))
+			}
+			descriptions.Add(description)
+
+			independent, ok := step.(IndependentStep)
+			if !ok {
+				continue
+			}
+			for _, dep := range independent.Dependencies() {
+				if !collections.Contains(dep) {
+					problems = append(problems, fmt.Sprintf(
+						"%s upgrade step %q for %s depends on unknown collection %q", kind, description, vers, dep,
+					))
+				}
+			}
+		}
+	}
+	return problems
+}
@@ -0,0 +1,80 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgrades_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/upgrades"
+)
+
+type registrySuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&registrySuite{})
+
+func (s *registrySuite) TestVerifyRegistry(c *gc.C) {
+	problems := upgrades.VerifyRegistry(state.CollectionNames())
+	c.Assert(problems, gc.HasLen, 0)
+}
+
+func (s *registrySuite) patchBrokenRegistry() {
+	s.PatchValue(upgrades.StateUpgradeOperations, func() []upgrades.Operation {
+		return []upgrades.Operation{
+			&mockUpgradeOperation{
+				targetVersion: version.MustParse("2.7.0"),
+				steps: []upgrades.Step{
+					newUpgradeStep("step 1", upgrades.DatabaseMaster),
+					&mockIndependentStep{
+						msg:          "step 2",
+						dependencies: []string{"machines", "gone"},
+					},
+					newUpgradeStep("step 1", upgrades.DatabaseMaster),
+				},
+			},
+			&mockUpgradeOperation{
+				targetVersion: version.MustParse("2.6.0"),
+				steps:         []upgrades.Step{newUpgradeStep("step 1", upgrades.DatabaseMaster)},
+			},
+		}
+	})
+	s.PatchValue(upgrades.UpgradeOperations, func() []upgrades.Operation {
+		return []upgrades.Operation{
+			&mockUpgradeOperation{targetVersion: version.MustParse("2.6.0")},
+			&mockUpgradeOperation{targetVersion: version.MustParse("2.6.0")},
+		}
+	})
+}
+
+func (s *registrySuite) TestVerifyRegistryProblems(c *gc.C) {
+	s.patchBrokenRegistry()
+	problems := upgrades.VerifyRegistry([]string{"machines"})
+	c.Assert(problems, jc.DeepEquals, []string{
+		`state upgrade step "step 2" for 2.7.0 depends on unknown collection "gone"`,
+		`state upgrade step "step 1" for 2.7.0 is duplicated`,
+		`state upgrade operation for 2.6.0 follows the operation for 2.7.0`,
+		`API upgrade operation for 2.6.0 follows the operation for 2.6.0`,
+	})
+}
+
+func (s *registrySuite) TestRegistryPreCheck(c *gc.C) {
+	s.patchBrokenRegistry()
+	problems := upgrades.RunPreChecks(upgrades.PreCheckContext{
+		State: &fakePreCheckBackend{
+			mongoVersion: "3.6.8",
+			config:       testing.FakeControllerConfig(),
+		},
+	})
+	c.Assert(problems, gc.HasLen, 4)
+	c.Assert(problems[0], jc.DeepEquals, upgrades.PreCheckProblem{
+		Check:    "upgrade-registry",
+		Message:  `state upgrade step "step 2" for 2.7.0 depends on unknown collection "gone"`,
+		Blocking: true,
+	})
+}
@@ -3,56 +3,119 @@
 
 package upgrades
 
+import "github.com/juju/juju/state"
+
 // stateStepsFor27 returns upgrade steps for Juju 2.7.0.
 func stateStepsFor27() []Step {
 	return []Step{
-		&upgradeStep{
-			description: "add controller node docs",
-			targets:     []Target{DatabaseMaster},
-			run: func(context Context) error {
-				return context.State().AddControllerNodeDocs()
+		&snapshotUpgradeStep{
+			independentUpgradeStep: independentUpgradeStep{
+				upgradeStep: upgradeStep{
+					description: "add controller node docs",
+					targets:     []Target{DatabaseMaster},
+					run: func(context Context) error {
+						return context.State().AddControllerNodeDocs()
+					},
+				},
+				dependencies: []string{"machines", "controllerNodes", "controllers"},
+			},
+		},
+		&snapshotUpgradeStep{
+			independentUpgradeStep: independentUpgradeStep{
+				upgradeStep: upgradeStep{
+					description: "recreate spaces with IDs",
+					targets:     []Target{DatabaseMaster},
+					run: func(context Context) error {
+						return context.State().AddSpaceIdToSpaceDocs()
+					},
+				},
+				dependencies: []string{"spaces", "sequence"},
+			},
+		},
+		&snapshotUpgradeStep{
+			independentUpgradeStep: independentUpgradeStep{
+				upgradeStep: upgradeStep{
+					description: "change subnet AvailabilityZone to AvailabilityZones",
+					targets:     []Target{DatabaseMaster},
+					run: func(context Context) error {
+						return context.State().ChangeSubnetAZtoSlice()
+					},
+				},
+				dependencies: []string{"subnets"},
 			},
 		},
-		&upgradeStep{
-			description: "recreate spaces with IDs",
-			targets:     []Target{DatabaseMaster},
-			run: func(context Context) error {
-				return context.State().AddSpaceIdToSpaceDocs()
+		&snapshotUpgradeStep{
+			independentUpgradeStep: independentUpgradeStep{
+				upgradeStep: upgradeStep{
+					description: "change subnet SpaceName to SpaceID",
+					targets:     []Target{DatabaseMaster},
+					run: func(context Context) error {
+						return context.State().ChangeSubnetSpaceNameToSpaceID()
+					},
+				},
+				dependencies: []string{"subnets", "spaces"},
 			},
 		},
-		&upgradeStep{
-			description: "change subnet AvailabilityZone to AvailabilityZones",
-			targets:     []Target{DatabaseMaster},
-			run: func(context Context) error {
-				return context.State().ChangeSubnetAZtoSlice()
+		&snapshotUpgradeStep{
+			independentUpgradeStep: independentUpgradeStep{
+				upgradeStep: upgradeStep{
+					description: "recreate subnets with IDs",
+					targets:     []Target{DatabaseMaster},
+					run: func(context Context) error {
+						return context.State().AddSubnetIdToSubnetDocs()
+					},
+				},
+				dependencies: []string{"subnets", "sequence"},
 			},
+			checkpoint: state.AddSubnetIdToSubnetDocsCheckpoint,
 		},
-		&upgradeStep{
-			description: "change subnet SpaceName to SpaceID",
-			targets:     []Target{DatabaseMaster},
-			run: func(context Context) error {
-				return context.State().ChangeSubnetSpaceNameToSpaceID()
+		&snapshotUpgradeStep{
+			independentUpgradeStep: independentUpgradeStep{
+				upgradeStep: upgradeStep{
+					description: "normalise subnet CIDRs",
+					targets:     []Target{DatabaseMaster},
+					run: func(context Context) error {
+						return context.State().NormaliseSubnetCIDRs()
+					},
+				},
+				dependencies: []string{"subnets", "ip.addresses"},
 			},
 		},
-		&upgradeStep{
-			description: "recreate subnets with IDs",
-			targets:     []Target{DatabaseMaster},
-			run: func(context Context) error {
-				return context.State().AddSubnetIdToSubnetDocs()
+		&snapshotUpgradeStep{
+			independentUpgradeStep: independentUpgradeStep{
+				upgradeStep: upgradeStep{
+					description: "replace portsDoc.SubnetID as a CIDR with an ID.",
+					targets:     []Target{DatabaseMaster},
+					run: func(context Context) error {
+						return context.State().ReplacePortsDocSubnetIDCIDR()
+					},
+				},
+				dependencies: []string{"openedPorts", "subnets"},
 			},
+			checkpoint: state.ReplacePortsDocSubnetIDCIDRCheckpoint,
 		},
-		&upgradeStep{
-			description: "replace portsDoc.SubnetID as a CIDR with an ID.",
-			targets:     []Target{DatabaseMaster},
-			run: func(context Context) error {
-				return context.State().ReplacePortsDocSubnetIDCIDR()
+		&snapshotUpgradeStep{
+			independentUpgradeStep: independentUpgradeStep{
+				upgradeStep: upgradeStep{
+					description: "ensure application settings exist for all relations",
+					targets:     []Target{DatabaseMaster},
+					run: func(context Context) error {
+						return context.State().EnsureRelationApplicationSettings()
+					},
+				},
+				dependencies: []string{"relations", "settings"},
 			},
 		},
-		&upgradeStep{
-			description: "ensure application settings exist for all relations",
-			targets:     []Target{DatabaseMaster},
-			run: func(context Context) error {
-				return context.State().EnsureRelationApplicationSettings()
+		&snapshotUpgradeStep{
+			independentUpgradeStep: independentUpgradeStep{
+				upgradeStep: upgradeStep{
+					description: "add model aliases",
+					targets:     []Target{DatabaseMaster},
+					run: func(context Context) error {
+						return context.State().AddModelAliases()
+					},
+				},
+				dependencies: []string{"models", "modelaliases"},
 			},
 		},
 	}
@@ -20,6 +20,18 @@ type steps27Suite struct {
 
 var _ = gc.Suite(&steps27Suite{})
 
+func (s *steps27Suite) TestStepsReversible(c *gc.C) {
+	for _, op := range (*upgrades.StateUpgradeOperations)() {
+		if op.TargetVersion() != v27 {
+			continue
+		}
+		for _, step := range op.Steps() {
+			_, ok := step.(upgrades.ReversibleStep)
+			c.Check(ok, jc.IsTrue, gc.Commentf("%q", step.Description()))
+		}
+	}
+}
+
 func (s *steps27Suite) TestCreateControllerNodes(c *gc.C) {
 	step := findStateStep(c, v27, `add controller node docs`)
 	// Logic for step itself is tested in state package.
@@ -50,6 +62,12 @@ func (s *steps27Suite) TestAddSubnetIdToSubnetDocs(c *gc.C) {
 	c.Assert(step.Targets(), jc.DeepEquals, []upgrades.Target{upgrades.DatabaseMaster})
 }
 
+func (s *steps27Suite) TestNormaliseSubnetCIDRs(c *gc.C) {
+	step := findStateStep(c, v27, `normalise subnet CIDRs`)
+	// Logic for step itself is tested in state package.
+	c.Assert(step.Targets(), jc.DeepEquals, []upgrades.Target{upgrades.DatabaseMaster})
+}
+
 func (s *steps27Suite) TestReplacePortsDocSubnetIDCIDR(c *gc.C) {
 	step := findStateStep(c, v27, `replace portsDoc.SubnetID as a CIDR with an ID.`)
 	c.Assert(step.Targets(), jc.DeepEquals, []upgrades.Target{upgrades.DatabaseMaster})
@@ -59,3 +77,9 @@ func (s *steps27Suite) TestEnsureRelationApplicationSettings(c *gc.C) {
 	step := findStateStep(c, v27, `ensure application settings exist for all relations`)
 	c.Assert(step.Targets(), jc.DeepEquals, []upgrades.Target{upgrades.DatabaseMaster})
 }
+
+func (s *steps27Suite) TestAddModelAliases(c *gc.C) {
+	step := findStateStep(c, v27, `add model aliases`)
+	// Logic for step itself is tested in state package.
+	c.Assert(step.Targets(), jc.DeepEquals, []upgrades.Target{upgrades.DatabaseMaster})
+}
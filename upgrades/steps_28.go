@@ -0,0 +1,25 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgrades
+
+import "github.com/juju/juju/state"
+
+// stateStepsFor28 returns upgrade steps for Juju 2.8.0.
+func stateStepsFor28() []Step {
+	return []Step{
+		&snapshotUpgradeStep{
+			independentUpgradeStep: independentUpgradeStep{
+				upgradeStep: upgradeStep{
+					description: "split ports docs into a doc per unit",
+					targets:     []Target{DatabaseMaster},
+					run: func(context Context) error {
+						return context.State().SplitPortsDocsByUnit()
+					},
+				},
+				dependencies: []string{"openedPorts"},
+			},
+			checkpoint: state.SplitPortsDocsByUnitCheckpoint,
+		},
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgrades_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/upgrades"
+)
+
+var v28 = version.MustParse("2.8.0")
+
+type steps28Suite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&steps28Suite{})
+
+func (s *steps28Suite) TestStepsReversible(c *gc.C) {
+	for _, op := range (*upgrades.StateUpgradeOperations)() {
+		if op.TargetVersion() != v28 {
+			continue
+		}
+		for _, step := range op.Steps() {
+			_, ok := step.(upgrades.ReversibleStep)
+			c.Check(ok, jc.IsTrue, gc.Commentf("%q", step.Description()))
+		}
+	}
+}
+
+func (s *steps28Suite) TestSplitPortsDocsByUnit(c *gc.C) {
+	step := findStateStep(c, v28, `split ports docs into a doc per unit`)
+	// Logic for step itself is tested in state package.
+	c.Assert(step.Targets(), jc.DeepEquals, []upgrades.Target{upgrades.DatabaseMaster})
+}
@@ -4,8 +4,11 @@
 package upgrades
 
 import (
+	"crypto/sha1"
 	"fmt"
+	"sync"
 
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/version"
 )
@@ -25,6 +28,33 @@ type Step interface {
 	Run(Context) error
 }
 
+// ReversibleStep is a Step that can undo its changes. If a database
+// master upgrade step fails, it and the steps completed before it are
+// rolled back, most recent first, so that the database isn't left
+// partly upgraded.
+type ReversibleStep interface {
+	Step
+
+	// Rollback undoes the changes made by Run, whether or not Run
+	// succeeded.
+	Rollback(Context) error
+}
+
+// IndependentStep is a Step that declares what it depends on. A run of
+// consecutive database master steps that are all independent steps,
+// and that share no dependencies, may be run concurrently.
+type IndependentStep interface {
+	Step
+
+	// Dependencies returns the names of the database collections
+	// that the step reads or writes.
+	Dependencies() []string
+}
+
+// MaxConcurrentSteps is the greatest number of independent database
+// master upgrade steps run at once.
+var MaxConcurrentSteps = 4
+
 // Operation defines what steps to perform to upgrade to a target version.
 type Operation interface {
 	// The Juju version for which this operation is applicable.
@@ -89,17 +119,49 @@ func (e *upgradeError) Error() string {
 	return fmt.Sprintf("%s: %v", e.description, e.err)
 }
 
+// RolledBackError records that a failed upgrade step, and the steps
+// completed before it, have been rolled back.
+type RolledBackError struct {
+	Err error
+}
+
+func (e *RolledBackError) Error() string {
+	return fmt.Sprintf("%v (steps rolled back)", e.Err)
+}
+
+// IsRolledBack returns true if the error was returned by PerformUpgrade
+// after a database master upgrade step failed, and that step and all
+// the steps completed before it were rolled back. The database then
+// holds the data as it was before the upgrade started.
+func IsRolledBack(err error) bool {
+	_, ok := errors.Cause(err).(*RolledBackError)
+	return ok
+}
+
 // PerformUpgrade runs the business logic needed to upgrade the current "from" version to this
 // version of Juju on the "target" type of machine.
 func PerformUpgrade(from version.Number, targets []Target, context Context) error {
 	if hasStateTarget(targets) {
 		ops := newStateUpgradeOpsIterator(from)
-		if err := runUpgradeSteps(ops, targets, context.StateContext()); err != nil {
+		stateContext := context.StateContext()
+		if hasTarget(targets, DatabaseMaster) {
+			// A rollback of an earlier attempt may have been
+			// interrupted, leaving collections partly restored.
+			if err := stateContext.State().ResumeCollectionSnapshotRestores(); err != nil {
+				return errors.Annotate(err, "finishing interrupted rollback")
+			}
+		}
+		completed, failed, err := runUpgradeSteps(ops, targets, stateContext)
+		if err != nil {
+			if hasTarget(targets, DatabaseMaster) {
+				return rollbackSteps(completed, failed, stateContext, err)
+			}
 			return err
 		}
+		discardSnapshots(completed, stateContext)
 	}
 	ops := newUpgradeOpsIterator(from)
-	if err := runUpgradeSteps(ops, targets, context.APIContext()); err != nil {
+	if _, _, err := runUpgradeSteps(ops, targets, context.APIContext()); err != nil {
 		return err
 	}
 	logger.Infof("All upgrade steps completed successfully")
@@ -107,8 +169,12 @@ func PerformUpgrade(from version.Number, targets []Target, context Context) erro
 }
 
 func hasStateTarget(targets []Target) bool {
-	for _, target := range targets {
-		if target == Controller || target == DatabaseMaster {
+	return hasTarget(targets, Controller) || hasTarget(targets, DatabaseMaster)
+}
+
+func hasTarget(targets []Target, target Target) bool {
+	for _, t := range targets {
+		if t == target {
 			return true
 		}
 	}
@@ -116,30 +182,163 @@ func hasStateTarget(targets []Target) bool {
 }
 
 // runUpgradeSteps finds all the upgrade operations relevant to
-// the targets given and runs the associated upgrade steps.
+// the targets given and runs the associated upgrade steps, returning
+// the steps completed and those that failed.
 //
 // As soon as any error is encountered, the operation is aborted since
 // subsequent steps may required successful completion of earlier
 // ones. The steps must be idempotent so that the entire upgrade
 // operation can be retried.
-func runUpgradeSteps(ops *opsIterator, targets []Target, context Context) error {
+func runUpgradeSteps(ops *opsIterator, targets []Target, context Context) ([]Step, []Step, error) {
+	var steps []Step
 	for ops.Next() {
 		for _, step := range ops.Get().Steps() {
 			if targetsMatch(targets, step.Targets()) {
-				logger.Infof("running upgrade step: %v", step.Description())
-				if err := step.Run(context); err != nil {
-					logger.Errorf("upgrade step %q failed: %v", step.Description(), err)
-					return &upgradeError{
-						description: step.Description(),
-						err:         err,
-					}
-				}
+				steps = append(steps, step)
 			}
 		}
 	}
+	concurrent := hasTarget(targets, DatabaseMaster)
+
+	var completed []Step
+	for len(steps) > 0 {
+		batch := steps[:1]
+		if concurrent {
+			batch = independentSteps(steps)
+		}
+		steps = steps[len(batch):]
+		done, failed, err := runConcurrently(batch, context)
+		completed = append(completed, done...)
+		if err != nil {
+			return completed, failed, err
+		}
+	}
+	return completed, nil, nil
+}
+
+// independentSteps returns the longest run of steps, from the first,
+// that can be run concurrently: independent database master steps that
+// share no dependencies. It always includes the first step.
+func independentSteps(steps []Step) []Step {
+	used := make(map[string]bool)
+	for i, step := range steps {
+		independent, ok := step.(IndependentStep)
+		if !ok || !hasTarget(step.Targets(), DatabaseMaster) {
+			if i == 0 {
+				return steps[:1]
+			}
+			return steps[:i]
+		}
+		deps := independent.Dependencies()
+		for _, dep := range deps {
+			if used[dep] {
+				return steps[:i]
+			}
+		}
+		for _, dep := range deps {
+			used[dep] = true
+		}
+	}
+	return steps
+}
+
+// runConcurrently runs the steps, at most MaxConcurrentSteps at a time,
+// returning those completed, those that failed and the error from the
+// first step to fail, if any. No more steps are started once one has
+// failed.
+func runConcurrently(steps []Step, context Context) ([]Step, []Step, error) {
+	if len(steps) == 1 {
+		if err := runStep(steps[0], context); err != nil {
+			return nil, steps, err
+		}
+		return steps, nil, nil
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed bool
+	)
+	errs := make([]error, len(steps))
+	started := make([]bool, len(steps))
+	limit := make(chan struct{}, MaxConcurrentSteps)
+	for i, step := range steps {
+		limit <- struct{}{}
+		mu.Lock()
+		stop := failed
+		mu.Unlock()
+		if stop {
+			<-limit
+			break
+		}
+		started[i] = true
+		wg.Add(1)
+		go func(i int, step Step) {
+			defer wg.Done()
+			defer func() { <-limit }()
+			if err := runStep(step, context); err != nil {
+				mu.Lock()
+				errs[i], failed = err, true
+				mu.Unlock()
+			}
+		}(i, step)
+	}
+	wg.Wait()
+
+	var completed, failed []Step
+	var firstErr error
+	for i, step := range steps {
+		switch {
+		case !started[i]:
+		case errs[i] != nil:
+			failed = append(failed, step)
+			if firstErr == nil {
+				firstErr = errs[i]
+			}
+		default:
+			completed = append(completed, step)
+		}
+	}
+	return completed, failed, firstErr
+}
+
+func runStep(step Step, context Context) error {
+	logger.Infof("running upgrade step: %v", step.Description())
+	if err := step.Run(context); err != nil {
+		logger.Errorf("upgrade step %q failed: %v", step.Description(), err)
+		return &upgradeError{
+			description: step.Description(),
+			err:         err,
+		}
+	}
 	return nil
 }
 
+// rollbackSteps rolls back the failed steps, which may have made some
+// of their changes, and then the completed steps, most recent first,
+// after the upgrade failed with err. Rolling back stops at the first
+// step that isn't a ReversibleStep, or that fails to roll back, since
+// the steps before it may be needed to undo it; err is then returned
+// unchanged. If every step is rolled back, the returned error
+// satisfies IsRolledBack.
+func rollbackSteps(completed, failed []Step, context Context, err error) error {
+	steps := append(append([]Step(nil), completed...), failed...)
+	for i := len(steps) - 1; i >= 0; i-- {
+		step := steps[i]
+		reversible, ok := step.(ReversibleStep)
+		if !ok {
+			logger.Errorf("cannot roll back upgrade step %q", step.Description())
+			return err
+		}
+		logger.Infof("rolling back upgrade step: %v", step.Description())
+		if rollbackErr := reversible.Rollback(context); rollbackErr != nil {
+			logger.Errorf("rolling back upgrade step %q failed: %v", step.Description(), rollbackErr)
+			return err
+		}
+	}
+	return &RolledBackError{Err: err}
+}
+
 // targetsMatch returns true if any machineTargets match any of
 // stepTargets.
 func targetsMatch(machineTargets []Target, stepTargets []Target) bool {
@@ -176,3 +375,95 @@ func (step *upgradeStep) Targets() []Target {
 func (step *upgradeStep) Run(context Context) error {
 	return step.run(context)
 }
+
+// reversibleUpgradeStep is a default ReversibleStep implementation.
+type reversibleUpgradeStep struct {
+	upgradeStep
+	rollback func(Context) error
+}
+
+var _ ReversibleStep = (*reversibleUpgradeStep)(nil)
+
+// Rollback is defined on the ReversibleStep interface.
+func (step *reversibleUpgradeStep) Rollback(context Context) error {
+	return step.rollback(context)
+}
+
+// independentUpgradeStep is a default IndependentStep implementation.
+type independentUpgradeStep struct {
+	upgradeStep
+	dependencies []string
+}
+
+var _ IndependentStep = (*independentUpgradeStep)(nil)
+
+// Dependencies is defined on the IndependentStep interface.
+func (step *independentUpgradeStep) Dependencies() []string {
+	return step.dependencies
+}
+
+// snapshotUpgradeStep is an independent database master step that is
+// made reversible by snapshotting the collections it depends on before
+// it runs. Its dependencies must name every collection it writes.
+//
+// A rollback replaces the collections wholesale, outside of mgo/txn:
+// restored docs keep the txn-revno they had when snapshotted while
+// their txn-queue is dropped, so watchers are not told of the restore.
+// Steps must only be rolled back while no agents are watching, as is
+// the case while the controller is upgrading.
+type snapshotUpgradeStep struct {
+	independentUpgradeStep
+
+	// checkpoint names the checkpoints recorded by the step, if it
+	// resumes where it left off when interrupted. They are removed
+	// when the step is rolled back, so that it starts again from the
+	// beginning of the restored collections.
+	checkpoint string
+}
+
+var _ ReversibleStep = (*snapshotUpgradeStep)(nil)
+
+// snapshotKey distinguishes the step's snapshots from those of other
+// steps depending on the same collections.
+func (step *snapshotUpgradeStep) snapshotKey() string {
+	return fmt.Sprintf("%x", sha1.Sum([]byte(step.description)))[:8]
+}
+
+// Run is defined on the Step interface.
+func (step *snapshotUpgradeStep) Run(context Context) error {
+	err := context.State().SnapshotCollections(step.snapshotKey(), step.dependencies)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return step.run(context)
+}
+
+// Rollback is defined on the ReversibleStep interface.
+func (step *snapshotUpgradeStep) Rollback(context Context) error {
+	err := context.State().RestoreCollectionSnapshots(step.snapshotKey(), step.dependencies)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if step.checkpoint == "" {
+		return nil
+	}
+	return context.State().RemoveUpgradeCheckpoints(step.checkpoint)
+}
+
+// discardSnapshot removes the step's snapshots once the upgrade has
+// completed.
+func (step *snapshotUpgradeStep) discardSnapshot(context Context) error {
+	return context.State().DropCollectionSnapshots(step.snapshotKey(), step.dependencies)
+}
+
+// discardSnapshots removes the snapshots taken by the completed steps.
+// Failing to remove them doesn't fail the upgrade.
+func discardSnapshots(completed []Step, context Context) {
+	for _, step := range completed {
+		if step, ok := step.(*snapshotUpgradeStep); ok {
+			if err := step.discardSnapshot(context); err != nil {
+				logger.Warningf("cannot discard snapshots of upgrade step %q: %v", step.Description(), err)
+			}
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"strings"
 	stdtesting "testing"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/testing"
@@ -23,6 +24,7 @@ import (
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/mongo"
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/multiwatcher"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/upgrades"
@@ -219,6 +221,11 @@ type mockStateBackend struct {
 	models []upgrades.Model
 }
 
+func (mock *mockStateBackend) ResumeCollectionSnapshotRestores() error {
+	mock.MethodCall(mock, "ResumeCollectionSnapshotRestores")
+	return mock.NextErr()
+}
+
 func (mock *mockStateBackend) ControllerUUID() string {
 	mock.MethodCall(mock, "ControllerUUID")
 	return "a-b-c-d"
@@ -524,6 +531,367 @@ func (s *upgradeSuite) TestPerformUpgrade(c *gc.C) {
 	}
 }
 
+type mockReversibleStep struct {
+	*mockUpgradeStep
+	rollbackErr error
+}
+
+func (u *mockReversibleStep) Rollback(ctx upgrades.Context) error {
+	if u.rollbackErr != nil {
+		return u.rollbackErr
+	}
+	context := ctx.(*mockContext)
+	context.messages = append(context.messages, "rollback "+u.msg)
+	return nil
+}
+
+func newReversibleStep(msg string, targets ...upgrades.Target) *mockReversibleStep {
+	return &mockReversibleStep{mockUpgradeStep: newUpgradeStep(msg, targets...)}
+}
+
+func (s *upgradeSuite) performUpgradeWithStateSteps(targets []upgrades.Target, steps ...upgrades.Step) ([]string, error) {
+	s.PatchValue(upgrades.StateUpgradeOperations, func() []upgrades.Operation {
+		return []upgrades.Operation{
+			&mockUpgradeOperation{
+				targetVersion: version.MustParse("1.21.0"),
+				steps:         steps[:1],
+			},
+			&mockUpgradeOperation{
+				targetVersion: version.MustParse("1.22.0"),
+				steps:         steps[1:],
+			},
+		}
+	})
+	s.PatchValue(upgrades.UpgradeOperations, func() []upgrades.Operation { return nil })
+	s.PatchValue(&jujuversion.Current, version.MustParse("1.22.0"))
+
+	ctx := &mockContext{state: &mockStateBackend{}}
+	err := upgrades.PerformUpgrade(version.MustParse("1.20.0"), targets, ctx)
+	return ctx.messages, err
+}
+
+func (s *upgradeSuite) TestFailedDatabaseMasterStepRollsBack(c *gc.C) {
+	messages, err := s.performUpgradeWithStateSteps(
+		targets(upgrades.DatabaseMaster, upgrades.Controller),
+		newReversibleStep("step 1", upgrades.DatabaseMaster),
+		newReversibleStep("step 2", upgrades.Controller),
+		newUpgradeStep("step 3", upgrades.HostMachine),
+		newReversibleStep("step 4 error", upgrades.DatabaseMaster),
+	)
+	c.Assert(err, gc.ErrorMatches, `step 4 error: upgrade error occurred \(steps rolled back\)`)
+	c.Assert(err, jc.Satisfies, upgrades.IsRolledBack)
+	c.Assert(messages, jc.DeepEquals, []string{
+		"step 1", "step 2", "rollback step 4 error", "rollback step 2", "rollback step 1",
+	})
+}
+
+func (s *upgradeSuite) TestRollbackStopsAtIrreversibleFailedStep(c *gc.C) {
+	messages, err := s.performUpgradeWithStateSteps(
+		targets(upgrades.DatabaseMaster),
+		newReversibleStep("step 1", upgrades.DatabaseMaster),
+		newUpgradeStep("step 2 error", upgrades.DatabaseMaster),
+	)
+	c.Assert(err, gc.ErrorMatches, "step 2 error: upgrade error occurred")
+	c.Assert(err, gc.Not(jc.Satisfies), upgrades.IsRolledBack)
+	c.Assert(messages, jc.DeepEquals, []string{"step 1"})
+}
+
+func (s *upgradeSuite) TestRollbackStopsAtIrreversibleStep(c *gc.C) {
+	messages, err := s.performUpgradeWithStateSteps(
+		targets(upgrades.DatabaseMaster),
+		newReversibleStep("step 1", upgrades.DatabaseMaster),
+		newUpgradeStep("step 2", upgrades.DatabaseMaster),
+		newReversibleStep("step 3", upgrades.DatabaseMaster),
+		newReversibleStep("step 4 error", upgrades.DatabaseMaster),
+	)
+	c.Assert(err, gc.ErrorMatches, "step 4 error: upgrade error occurred")
+	c.Assert(err, gc.Not(jc.Satisfies), upgrades.IsRolledBack)
+	c.Assert(messages, jc.DeepEquals, []string{
+		"step 1", "step 2", "step 3", "rollback step 4 error", "rollback step 3",
+	})
+}
+
+func (s *upgradeSuite) TestRollbackStopsAtFailedRollback(c *gc.C) {
+	step2 := newReversibleStep("step 2", upgrades.DatabaseMaster)
+	step2.rollbackErr = errors.New("rollback failed")
+	messages, err := s.performUpgradeWithStateSteps(
+		targets(upgrades.DatabaseMaster),
+		newReversibleStep("step 1", upgrades.DatabaseMaster),
+		step2,
+		newReversibleStep("step 3 error", upgrades.DatabaseMaster),
+	)
+	c.Assert(err, gc.ErrorMatches, "step 3 error: upgrade error occurred")
+	c.Assert(err, gc.Not(jc.Satisfies), upgrades.IsRolledBack)
+	c.Assert(messages, jc.DeepEquals, []string{"step 1", "step 2", "rollback step 3 error"})
+}
+
+// snapshotStateBackend records the calls made by a snapshot step.
+type snapshotStateBackend struct {
+	upgrades.StateBackend
+	testing.Stub
+}
+
+func (b *snapshotStateBackend) AddModelAliases() error {
+	b.MethodCall(b, "AddModelAliases")
+	return b.NextErr()
+}
+
+func (b *snapshotStateBackend) SnapshotCollections(key string, collNames []string) error {
+	b.MethodCall(b, "SnapshotCollections", key, collNames)
+	return b.NextErr()
+}
+
+func (b *snapshotStateBackend) ResumeCollectionSnapshotRestores() error {
+	b.MethodCall(b, "ResumeCollectionSnapshotRestores")
+	return b.NextErr()
+}
+
+func (b *snapshotStateBackend) RestoreCollectionSnapshots(key string, collNames []string) error {
+	b.MethodCall(b, "RestoreCollectionSnapshots", key, collNames)
+	return b.NextErr()
+}
+
+func (b *snapshotStateBackend) SplitPortsDocsByUnit() error {
+	b.MethodCall(b, "SplitPortsDocsByUnit")
+	return b.NextErr()
+}
+
+func (b *snapshotStateBackend) RemoveUpgradeCheckpoints(step string) error {
+	b.MethodCall(b, "RemoveUpgradeCheckpoints", step)
+	return b.NextErr()
+}
+
+func (b *snapshotStateBackend) DropCollectionSnapshots(key string, collNames []string) error {
+	b.MethodCall(b, "DropCollectionSnapshots", key, collNames)
+	return b.NextErr()
+}
+
+func (s *upgradeSuite) performSnapshotUpgrade(c *gc.C, steps ...upgrades.Step) (*snapshotStateBackend, error) {
+	aliases := findStateStep(c, version.MustParse("2.7.0"), "add model aliases")
+	s.PatchValue(upgrades.StateUpgradeOperations, func() []upgrades.Operation {
+		return []upgrades.Operation{
+			&mockUpgradeOperation{
+				targetVersion: version.MustParse("1.22.0"),
+				steps:         append([]upgrades.Step{aliases}, steps...),
+			},
+		}
+	})
+	s.PatchValue(upgrades.UpgradeOperations, func() []upgrades.Operation { return nil })
+	s.PatchValue(&jujuversion.Current, version.MustParse("1.22.0"))
+
+	backend := &snapshotStateBackend{}
+	ctx := &mockContext{state: backend}
+	err := upgrades.PerformUpgrade(version.MustParse("1.20.0"), targets(upgrades.DatabaseMaster), ctx)
+	return backend, err
+}
+
+func (s *upgradeSuite) TestSnapshotStepRollsBack(c *gc.C) {
+	backend, err := s.performSnapshotUpgrade(c, newUpgradeStep("step error", upgrades.DatabaseMaster))
+	c.Assert(err, jc.Satisfies, upgrades.IsRolledBack)
+	backend.CheckCallNames(c, "ResumeCollectionSnapshotRestores", "SnapshotCollections", "AddModelAliases", "RestoreCollectionSnapshots")
+	key := backend.Calls()[1].Args[0]
+	backend.CheckCall(c, 1, "SnapshotCollections", key, []string{"models", "modelaliases"})
+	backend.CheckCall(c, 3, "RestoreCollectionSnapshots", key, []string{"models", "modelaliases"})
+}
+
+func (s *upgradeSuite) TestFailedSnapshotStepRollsBack(c *gc.C) {
+	split := findStateStep(c, version.MustParse("2.8.0"), "split ports docs into a doc per unit")
+	backend := &snapshotStateBackend{}
+	backend.SetErrors(nil, nil, errors.New("boom"))
+	s.PatchValue(upgrades.StateUpgradeOperations, func() []upgrades.Operation {
+		return []upgrades.Operation{
+			&mockUpgradeOperation{
+				targetVersion: version.MustParse("1.22.0"),
+				steps:         []upgrades.Step{split},
+			},
+		}
+	})
+	s.PatchValue(upgrades.UpgradeOperations, func() []upgrades.Operation { return nil })
+	s.PatchValue(&jujuversion.Current, version.MustParse("1.22.0"))
+
+	err := upgrades.PerformUpgrade(version.MustParse("1.20.0"), targets(upgrades.DatabaseMaster), &mockContext{state: backend})
+	c.Assert(err, jc.Satisfies, upgrades.IsRolledBack)
+	backend.CheckCallNames(c, "ResumeCollectionSnapshotRestores", "SnapshotCollections", "SplitPortsDocsByUnit", "RestoreCollectionSnapshots", "RemoveUpgradeCheckpoints")
+	key := backend.Calls()[1].Args[0]
+	backend.CheckCall(c, 3, "RestoreCollectionSnapshots", key, []string{"openedPorts"})
+	backend.CheckCall(c, 4, "RemoveUpgradeCheckpoints", state.SplitPortsDocsByUnitCheckpoint)
+}
+
+func (s *upgradeSuite) TestSnapshotStepDiscardsSnapshot(c *gc.C) {
+	backend, err := s.performSnapshotUpgrade(c)
+	c.Assert(err, jc.ErrorIsNil)
+	backend.CheckCallNames(c, "ResumeCollectionSnapshotRestores", "SnapshotCollections", "AddModelAliases", "DropCollectionSnapshots")
+	key := backend.Calls()[1].Args[0]
+	backend.CheckCall(c, 3, "DropCollectionSnapshots", key, []string{"models", "modelaliases"})
+}
+
+func (s *upgradeSuite) TestSnapshotStepSnapshotFailure(c *gc.C) {
+	aliases := findStateStep(c, version.MustParse("2.7.0"), "add model aliases")
+	backend := &snapshotStateBackend{}
+	backend.SetErrors(errors.New("disk full"))
+	err := aliases.Run(&mockContext{state: backend})
+	c.Assert(err, gc.ErrorMatches, "disk full")
+	backend.CheckCallNames(c, "SnapshotCollections")
+}
+
+func (s *upgradeSuite) TestInterruptedRollbackFinishedFirst(c *gc.C) {
+	backend := &snapshotStateBackend{}
+	backend.SetErrors(errors.New("boom"))
+	s.PatchValue(upgrades.StateUpgradeOperations, func() []upgrades.Operation {
+		return []upgrades.Operation{
+			&mockUpgradeOperation{
+				targetVersion: version.MustParse("1.22.0"),
+				steps:         []upgrades.Step{newUpgradeStep("step", upgrades.DatabaseMaster)},
+			},
+		}
+	})
+	s.PatchValue(&jujuversion.Current, version.MustParse("1.22.0"))
+
+	ctx := &mockContext{state: backend}
+	err := upgrades.PerformUpgrade(version.MustParse("1.20.0"), targets(upgrades.DatabaseMaster), ctx)
+	c.Assert(err, gc.ErrorMatches, "finishing interrupted rollback: boom")
+	backend.CheckCallNames(c, "ResumeCollectionSnapshotRestores")
+	c.Assert(ctx.messages, gc.HasLen, 0)
+}
+
+func (s *upgradeSuite) TestFailedControllerStepNotRolledBack(c *gc.C) {
+	messages, err := s.performUpgradeWithStateSteps(
+		targets(upgrades.Controller),
+		newReversibleStep("step 1", upgrades.Controller),
+		newUpgradeStep("step 2 error", upgrades.Controller),
+	)
+	c.Assert(err, gc.ErrorMatches, "step 2 error: upgrade error occurred")
+	c.Assert(err, gc.Not(jc.Satisfies), upgrades.IsRolledBack)
+	c.Assert(messages, jc.DeepEquals, []string{"step 1"})
+}
+
+type mockIndependentStep struct {
+	msg          string
+	dependencies []string
+	run          func() error
+}
+
+func (u *mockIndependentStep) Description() string {
+	return u.msg
+}
+
+func (u *mockIndependentStep) Targets() []upgrades.Target {
+	return []upgrades.Target{upgrades.DatabaseMaster}
+}
+
+func (u *mockIndependentStep) Dependencies() []string {
+	return u.dependencies
+}
+
+func (u *mockIndependentStep) Run(upgrades.Context) error {
+	return u.run()
+}
+
+// blockingSteps returns independent steps with the given dependencies
+// that report when they start and then block until released.
+func blockingSteps(started chan<- string, release <-chan struct{}, dependencies ...string) []upgrades.Step {
+	steps := make([]upgrades.Step, len(dependencies))
+	for i, dep := range dependencies {
+		msg := fmt.Sprintf("step %d", i+1)
+		steps[i] = &mockIndependentStep{
+			msg:          msg,
+			dependencies: []string{dep},
+			run: func() error {
+				started <- msg
+				<-release
+				return nil
+			},
+		}
+	}
+	return steps
+}
+
+func (s *upgradeSuite) assertStarted(c *gc.C, started <-chan string, expect ...string) {
+	var msgs []string
+	for range expect {
+		select {
+		case msg := <-started:
+			msgs = append(msgs, msg)
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for steps to start, started %v", msgs)
+		}
+	}
+	c.Assert(msgs, jc.SameContents, expect)
+	select {
+	case msg := <-started:
+		c.Fatalf("unexpected step %q started", msg)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *upgradeSuite) TestIndependentStepsRunConcurrently(c *gc.C) {
+	started := make(chan string, 3)
+	release := make(chan struct{})
+	steps := blockingSteps(started, release, "machines", "units", "machines")
+	done := make(chan error)
+	go func() {
+		_, err := s.performUpgradeWithStateSteps(targets(upgrades.DatabaseMaster), steps...)
+		done <- err
+	}()
+
+	// The third step shares a dependency with the first, so it waits
+	// for the first two to complete.
+	s.assertStarted(c, started, "step 1", "step 2")
+	close(release)
+	s.assertStarted(c, started, "step 3")
+	select {
+	case err := <-done:
+		c.Assert(err, jc.ErrorIsNil)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for upgrade")
+	}
+}
+
+func (s *upgradeSuite) TestIndependentStepsBounded(c *gc.C) {
+	s.PatchValue(&upgrades.MaxConcurrentSteps, 1)
+	started := make(chan string, 2)
+	release := make(chan struct{})
+	steps := blockingSteps(started, release, "machines", "units")
+	done := make(chan error)
+	go func() {
+		_, err := s.performUpgradeWithStateSteps(targets(upgrades.DatabaseMaster), steps...)
+		done <- err
+	}()
+
+	s.assertStarted(c, started, "step 1")
+	close(release)
+	s.assertStarted(c, started, "step 2")
+	select {
+	case err := <-done:
+		c.Assert(err, jc.ErrorIsNil)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for upgrade")
+	}
+}
+
+func (s *upgradeSuite) TestIndependentStepFailure(c *gc.C) {
+	var ran []string
+	step := func(msg string, err error) upgrades.Step {
+		return &mockIndependentStep{
+			msg:          msg,
+			dependencies: []string{msg},
+			run: func() error {
+				ran = append(ran, msg)
+				return err
+			},
+		}
+	}
+	s.PatchValue(&upgrades.MaxConcurrentSteps, 1)
+	_, err := s.performUpgradeWithStateSteps(
+		targets(upgrades.DatabaseMaster),
+		step("step 1", nil),
+		step("step 2", errors.New("boom")),
+		step("step 3", nil),
+	)
+	c.Assert(err, gc.ErrorMatches, "step 2: boom")
+	c.Assert(ran, jc.DeepEquals, []string{"step 1", "step 2"})
+}
+
 type contextStep struct {
 	useAPI bool
 }
@@ -617,7 +985,7 @@ func (s *upgradeSuite) TestStateStepsNotAttemptedWhenNoStateTarget(c *gc.C) {
 	}
 
 	check(upgrades.Controller, 1, nil)
-	check(upgrades.DatabaseMaster, 1, nil)
+	check(upgrades.DatabaseMaster, 1, []string{"ResumeCollectionSnapshotRestores"})
 	check(upgrades.AllMachines, 0, nil)
 	check(upgrades.HostMachine, 0, nil)
 }
@@ -656,6 +1024,7 @@ func (s *upgradeSuite) TestStateUpgradeOperationsVersions(c *gc.C) {
 		"2.6.3",
 		"2.6.5",
 		"2.7.0",
+		"2.8.0",
 	})
 }
 
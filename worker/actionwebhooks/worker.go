@@ -0,0 +1,300 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actionwebhooks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"gopkg.in/juju/worker.v1"
+	"gopkg.in/juju/worker.v1/catacomb"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/core/watcher"
+)
+
+var logger = loggo.GetLogger("juju.worker.actionwebhooks")
+
+const (
+	// SignatureHeader is the header holding the HMAC-SHA256 signature
+	// of a webhook's timestamp and payload, made with the webhook's
+	// secret.
+	SignatureHeader = "X-Juju-Signature"
+
+	// TimestampHeader is the header holding the time, in seconds since
+	// the Unix epoch, at which a webhook was invoked. The timestamp is
+	// covered by the signature, so receivers can reject replays of old
+	// deliveries.
+	TimestampHeader = "X-Juju-Timestamp"
+
+	// OperationHeader is the header holding the id of the operation a
+	// webhook is invoked for.
+	OperationHeader = "X-Juju-Operation"
+
+	// MaxAttempts is the number of times the worker tries to invoke a
+	// webhook before giving up on it.
+	MaxAttempts = 5
+)
+
+// Facade exposes controller functionality to a Worker.
+type Facade interface {
+	WatchActionWebhooks() (watcher.StringsWatcher, error)
+	ActionWebhook(operation string) (params.ActionWebhookResult, error)
+	RemoveActionWebhook(operation string) error
+}
+
+// HTTPClient posts webhook payloads.
+type HTTPClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// Config defines the parameters of the actionwebhooks worker.
+type Config struct {
+	Facade     Facade
+	HTTPClient HTTPClient
+	Clock      clock.Clock
+
+	// RetryDelay is how long the worker waits before trying again to
+	// invoke webhooks that failed.
+	RetryDelay time.Duration
+}
+
+// Validate returns an error if Config cannot drive an actionwebhooks
+// worker.
+func (config Config) Validate() error {
+	if config.Facade == nil {
+		return errors.NotValidf("nil Facade")
+	}
+	if config.HTTPClient == nil {
+		return errors.NotValidf("nil HTTPClient")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if config.RetryDelay <= 0 {
+		return errors.NotValidf("non-positive RetryDelay")
+	}
+	return nil
+}
+
+// Payload is the JSON document posted to a webhook once all of the
+// actions of its operation have completed.
+type Payload struct {
+	// Operation identifies the operation.
+	Operation string `json:"operation"`
+
+	// Status is "completed" if all of the operation's actions
+	// completed, and "failed" otherwise.
+	Status string `json:"status"`
+
+	// Summary counts the operation's actions by their status.
+	Summary map[string]int `json:"summary"`
+
+	// Actions holds the results of the operation's actions.
+	Actions []params.ActionResult `json:"actions"`
+}
+
+// Sign returns the value of the signature header for the given
+// timestamp header value and payload, signed with the given secret.
+// The signed message is the timestamp, a ".", and the payload.
+func Sign(timestamp string, payload []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewHTTPClient returns an HTTP client for invoking webhooks. The
+// client only connects to public addresses, checked after the host
+// name is resolved, so webhooks cannot reach the controller's own
+// network; it does not use a proxy and it does not follow redirects.
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: checkDialAddress,
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return errors.Errorf("webhook redirected to %q", req.URL)
+		},
+	}
+}
+
+// checkDialAddress refuses connections to addresses that are not
+// public, such as loopback, link-local and private addresses.
+func checkDialAddress(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if network.NewAddress(host).Scope != network.ScopePublic {
+		return errors.Errorf("webhook address %q is not public", host)
+	}
+	return nil
+}
+
+// New returns a Worker that invokes the webhooks of operations whose
+// actions have all completed.
+func New(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &actionWebhooksWorker{
+		config:   config,
+		attempts: make(map[string]int),
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+type actionWebhooksWorker struct {
+	catacomb catacomb.Catacomb
+	config   Config
+
+	// attempts records the number of failed attempts to invoke the
+	// webhooks of operations, which are retried later.
+	attempts map[string]int
+}
+
+// Kill implements worker.Worker.
+func (w *actionWebhooksWorker) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait implements worker.Worker.
+func (w *actionWebhooksWorker) Wait() error {
+	return w.catacomb.Wait()
+}
+
+func (w *actionWebhooksWorker) loop() error {
+	webhookWatcher, err := w.config.Facade.WatchActionWebhooks()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := w.catacomb.Add(webhookWatcher); err != nil {
+		return errors.Trace(err)
+	}
+
+	var retry <-chan time.Time
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case operations, ok := <-webhookWatcher.Changes():
+			if !ok {
+				return errors.New("action webhook watcher closed")
+			}
+			for _, operation := range operations {
+				if err := w.handle(operation); err != nil {
+					return errors.Trace(err)
+				}
+			}
+		case <-retry:
+			retry = nil
+			for operation := range w.attempts {
+				if err := w.handle(operation); err != nil {
+					return errors.Trace(err)
+				}
+			}
+		}
+		if retry == nil && len(w.attempts) > 0 {
+			retry = w.config.Clock.After(w.config.RetryDelay)
+		}
+	}
+}
+
+// handle invokes the webhook of the given operation if all of its
+// actions have completed, and removes the webhook once it has been
+// invoked or too many attempts to invoke it have failed.
+func (w *actionWebhooksWorker) handle(operation string) error {
+	webhook, err := w.config.Facade.ActionWebhook(operation)
+	if params.IsCodeNotFound(err) {
+		delete(w.attempts, operation)
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if !webhook.Completed {
+		return nil
+	}
+
+	if err := w.invoke(webhook); err != nil {
+		w.attempts[operation]++
+		if w.attempts[operation] < MaxAttempts {
+			logger.Warningf("cannot invoke webhook of operation %q (attempt %d): %v", operation, w.attempts[operation], err)
+			return nil
+		}
+		logger.Errorf("giving up invoking webhook of operation %q: %v", operation, err)
+	}
+	delete(w.attempts, operation)
+	return errors.Trace(w.config.Facade.RemoveActionWebhook(operation))
+}
+
+// invoke posts the results of the operation's actions to its webhook.
+func (w *actionWebhooksWorker) invoke(webhook params.ActionWebhookResult) error {
+	payload := Payload{
+		Operation: webhook.Operation,
+		Status:    params.ActionCompleted,
+		Summary:   make(map[string]int),
+		Actions:   webhook.Actions,
+	}
+	for _, action := range webhook.Actions {
+		payload.Summary[action.Status]++
+		if action.Status != params.ActionCompleted {
+			payload.Status = params.ActionFailed
+		}
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	req, err := http.NewRequest("POST", webhook.URL, bytes.NewReader(data))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(OperationHeader, webhook.Operation)
+	timestamp := strconv.FormatInt(w.config.Clock.Now().Unix(), 10)
+	req.Header.Set(TimestampHeader, timestamp)
+	if webhook.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(timestamp, data, webhook.Secret))
+	}
+	resp, err := w.config.HTTPClient.Do(req)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("webhook returned %s", resp.Status)
+	}
+	logger.Debugf("invoked webhook of operation %q", webhook.Operation)
+	return nil
+}
@@ -0,0 +1,266 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actionwebhooks_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/worker.v1/workertest"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/watcher"
+	"github.com/juju/juju/core/watcher/watchertest"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/actionwebhooks"
+)
+
+type WorkerSuite struct {
+	jujutesting.IsolationSuite
+
+	clock  *testclock.Clock
+	facade *fakeFacade
+	client *fakeHTTPClient
+	config actionwebhooks.Config
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.clock = testclock.NewClock(time.Now())
+	s.facade = &fakeFacade{
+		changes: make(chan []string),
+		removed: make(chan string, 10),
+		webhooks: map[string]params.ActionWebhookResult{
+			"op-1": {
+				Operation: "op-1",
+				URL:       "https://example.com/hook",
+				Secret:    "s3cret",
+				Completed: true,
+				Actions: []params.ActionResult{{
+					Action: &params.Action{Tag: "action-mysql-1", Receiver: "unit-mysql-0", Name: "backup"},
+					Status: "completed",
+				}, {
+					Action:  &params.Action{Tag: "action-mysql-2", Receiver: "unit-mysql-1", Name: "backup"},
+					Status:  "failed",
+					Message: "disk full",
+				}},
+			},
+			"op-2": {
+				Operation: "op-2",
+				URL:       "https://example.com/other",
+			},
+		},
+	}
+	s.client = &fakeHTTPClient{requests: make(chan *http.Request, 10)}
+	s.config = actionwebhooks.Config{
+		Facade:     s.facade,
+		HTTPClient: s.client,
+		Clock:      s.clock,
+		RetryDelay: time.Minute,
+	}
+}
+
+func (s *WorkerSuite) startWorker(c *gc.C) {
+	w, err := actionwebhooks.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(c *gc.C) { workertest.CleanKill(c, w) })
+}
+
+func (s *WorkerSuite) sendChange(c *gc.C, operations ...string) {
+	select {
+	case s.facade.changes <- operations:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out sending change")
+	}
+}
+
+func (s *WorkerSuite) waitForRequest(c *gc.C) *http.Request {
+	select {
+	case req := <-s.client.requests:
+		return req
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for webhook request")
+	}
+	panic("unreachable")
+}
+
+func (s *WorkerSuite) waitForRemoved(c *gc.C, operation string) {
+	select {
+	case removed := <-s.facade.removed:
+		c.Assert(removed, gc.Equals, operation)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for webhook removal")
+	}
+}
+
+func (s *WorkerSuite) TestInvalidConfig(c *gc.C) {
+	s.config.RetryDelay = 0
+	_, err := actionwebhooks.New(s.config)
+	c.Assert(err, gc.ErrorMatches, "non-positive RetryDelay not valid")
+}
+
+func (s *WorkerSuite) TestInvokesCompletedWebhook(c *gc.C) {
+	s.startWorker(c)
+	s.sendChange(c, "op-1")
+
+	req := s.waitForRequest(c)
+	c.Assert(req.Method, gc.Equals, "POST")
+	c.Assert(req.URL.String(), gc.Equals, "https://example.com/hook")
+	c.Assert(req.Header.Get("Content-Type"), gc.Equals, "application/json")
+	c.Assert(req.Header.Get(actionwebhooks.OperationHeader), gc.Equals, "op-1")
+
+	timestamp := req.Header.Get(actionwebhooks.TimestampHeader)
+	c.Assert(timestamp, gc.Equals, strconv.FormatInt(s.clock.Now().Unix(), 10))
+	body, err := ioutil.ReadAll(req.Body)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(req.Header.Get(actionwebhooks.SignatureHeader), gc.Equals, actionwebhooks.Sign(timestamp, body, "s3cret"))
+	var payload actionwebhooks.Payload
+	err = json.Unmarshal(body, &payload)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(payload.Operation, gc.Equals, "op-1")
+	c.Assert(payload.Status, gc.Equals, "failed")
+	c.Assert(payload.Summary, jc.DeepEquals, map[string]int{"completed": 1, "failed": 1})
+	c.Assert(payload.Actions, gc.HasLen, 2)
+	c.Assert(payload.Actions[1].Message, gc.Equals, "disk full")
+
+	s.waitForRemoved(c, "op-1")
+}
+
+func (s *WorkerSuite) TestIgnoresIncompleteWebhook(c *gc.C) {
+	s.startWorker(c)
+	s.sendChange(c, "op-2", "op-3", "op-1")
+
+	// Only the completed operation's webhook is invoked; op-3 has
+	// no webhook at all.
+	req := s.waitForRequest(c)
+	c.Assert(req.Header.Get(actionwebhooks.OperationHeader), gc.Equals, "op-1")
+	s.waitForRemoved(c, "op-1")
+	select {
+	case <-s.client.requests:
+		c.Fatalf("unexpected webhook request")
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *WorkerSuite) TestRetriesFailedWebhook(c *gc.C) {
+	s.client.setStatus(http.StatusInternalServerError)
+	s.startWorker(c)
+	s.sendChange(c, "op-1")
+	s.waitForRequest(c)
+
+	s.client.setStatus(http.StatusOK)
+	err := s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	s.waitForRequest(c)
+	s.waitForRemoved(c, "op-1")
+}
+
+func (s *WorkerSuite) TestGivesUpOnFailingWebhook(c *gc.C) {
+	s.client.setStatus(http.StatusInternalServerError)
+	s.startWorker(c)
+	s.sendChange(c, "op-1")
+	s.waitForRequest(c)
+
+	for i := 1; i < actionwebhooks.MaxAttempts; i++ {
+		err := s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1)
+		c.Assert(err, jc.ErrorIsNil)
+		s.waitForRequest(c)
+	}
+	s.waitForRemoved(c, "op-1")
+}
+
+func (s *WorkerSuite) TestSign(c *gc.C) {
+	c.Assert(actionwebhooks.Sign("1", []byte("{}"), "s3cret"), gc.Matches, "sha256=[0-9a-f]{64}")
+	c.Assert(actionwebhooks.Sign("1", []byte("{}"), "s3cret"), gc.Not(gc.Equals), actionwebhooks.Sign("1", []byte("{}"), "other"))
+	c.Assert(actionwebhooks.Sign("1", []byte("{}"), "s3cret"), gc.Not(gc.Equals), actionwebhooks.Sign("2", []byte("{}"), "s3cret"))
+}
+
+func (s *WorkerSuite) TestHTTPClientRefusesLocalAddresses(c *gc.C) {
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		c.Errorf("webhook reached a loopback address")
+	}))
+	defer server.Close()
+
+	client := actionwebhooks.NewHTTPClient(coretesting.LongWait)
+	for _, url := range []string{server.URL, "http://169.254.169.254/latest/meta-data", "http://10.0.0.1/hook"} {
+		_, err := client.Post(url, "application/json", nil)
+		c.Check(err, gc.ErrorMatches, `.*webhook address ".*" is not public`)
+	}
+}
+
+func (s *WorkerSuite) TestHTTPClientRefusesRedirects(c *gc.C) {
+	client := actionwebhooks.NewHTTPClient(coretesting.LongWait)
+	req, err := http.NewRequest("GET", "https://example.com/elsewhere", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = client.CheckRedirect(req, nil)
+	c.Assert(err, gc.ErrorMatches, `webhook redirected to "https://example.com/elsewhere"`)
+}
+
+type fakeFacade struct {
+	changes  chan []string
+	removed  chan string
+	webhooks map[string]params.ActionWebhookResult
+}
+
+func (f *fakeFacade) WatchActionWebhooks() (watcher.StringsWatcher, error) {
+	return watchertest.NewMockStringsWatcher(f.changes), nil
+}
+
+func (f *fakeFacade) ActionWebhook(operation string) (params.ActionWebhookResult, error) {
+	webhook, ok := f.webhooks[operation]
+	if !ok {
+		return params.ActionWebhookResult{}, &params.Error{Code: params.CodeNotFound}
+	}
+	return webhook, nil
+}
+
+func (f *fakeFacade) RemoveActionWebhook(operation string) error {
+	f.removed <- operation
+	return nil
+}
+
+type fakeHTTPClient struct {
+	mu       sync.Mutex
+	status   int
+	requests chan *http.Request
+}
+
+func (f *fakeHTTPClient) setStatus(status int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.status = status
+}
+
+func (f *fakeHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	f.mu.Lock()
+	status := f.status
+	f.mu.Unlock()
+	if status == 0 {
+		status = http.StatusOK
+	}
+	// Keep the body readable by the test.
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	f.requests <- req
+	return &http.Response{
+		Status:     http.StatusText(status),
+		StatusCode: status,
+		Body:       ioutil.NopCloser(&bytes.Buffer{}),
+	}, nil
+}
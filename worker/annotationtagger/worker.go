@@ -0,0 +1,179 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package annotationtagger provides a worker that mirrors machine and
+// unit annotations onto the provider tags of machine instances, so
+// that cloud inventories reflect Juju metadata.
+package annotationtagger
+
+import (
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"gopkg.in/juju/worker.v1"
+	"gopkg.in/juju/worker.v1/catacomb"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/worker/common"
+)
+
+var logger = loggo.GetLogger("juju.worker.annotationtagger")
+
+// Facade exposes controller functionality to a Worker.
+type Facade interface {
+	InstanceTags() ([]params.InstanceTagsResult, error)
+}
+
+// Config defines the parameters of the annotationtagger worker.
+type Config struct {
+	Facade        Facade
+	Tagger        environs.InstanceTagger
+	CredentialAPI common.CredentialAPI
+	Clock         clock.Clock
+
+	// Interval is how often the worker reconciles instance tags with
+	// annotations.
+	Interval time.Duration
+}
+
+// Validate returns an error if Config cannot drive an annotationtagger
+// worker.
+func (config Config) Validate() error {
+	if config.Facade == nil {
+		return errors.NotValidf("nil Facade")
+	}
+	if config.Tagger == nil {
+		return errors.NotValidf("nil Tagger")
+	}
+	if config.CredentialAPI == nil {
+		return errors.NotValidf("nil CredentialAPI")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if config.Interval <= 0 {
+		return errors.NotValidf("non-positive Interval")
+	}
+	return nil
+}
+
+// New returns a Worker that periodically sets the provider tags of
+// machine instances from the annotations of their machines and units.
+func New(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &annotationTagger{
+		config:  config,
+		applied: make(map[instance.Id]map[string]string),
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+type annotationTagger struct {
+	catacomb    catacomb.Catacomb
+	config      Config
+	callContext context.ProviderCallContext
+
+	// applied holds the tags last set on each instance.
+	applied map[instance.Id]map[string]string
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *annotationTagger) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *annotationTagger) Wait() error {
+	return w.catacomb.Wait()
+}
+
+func (w *annotationTagger) loop() error {
+	w.callContext = common.NewCloudCallContext(w.config.CredentialAPI, w.catacomb.Dying)
+	for {
+		if err := w.reconcile(); err != nil {
+			return errors.Trace(err)
+		}
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case <-w.config.Clock.After(w.config.Interval):
+		}
+	}
+}
+
+// reconcile sets the tags of each instance that differ from those last
+// set on it. Tags mirrored from annotations that have since been
+// removed are set to the empty string, because providers only replace
+// tags.
+func (w *annotationTagger) reconcile() error {
+	results, err := w.config.Facade.InstanceTags()
+	if err != nil {
+		return errors.Annotate(err, "getting instance tags")
+	}
+	seen := make(map[instance.Id]bool)
+	for _, result := range results {
+		instId := instance.Id(result.InstanceId)
+		if instId != "" {
+			seen[instId] = true
+		}
+		if result.Error != nil {
+			logger.Warningf("cannot get tags for %s: %v", result.MachineTag, result.Error)
+			continue
+		}
+		changes := changedTags(w.applied[instId], result.Tags)
+		if len(changes) == 0 {
+			continue
+		}
+		logger.Debugf("setting tags %v on instance %s of %s", changes, instId, result.MachineTag)
+		if err := w.config.Tagger.TagInstance(w.callContext, instId, changes); err != nil {
+			logger.Warningf("cannot tag instance %s of %s: %v", instId, result.MachineTag, err)
+			continue
+		}
+		w.applied[instId] = result.Tags
+	}
+	// Forget instances that are gone, or no longer have tags mirrored
+	// onto them because annotation tagging was disabled.
+	for instId, tags := range w.applied {
+		if seen[instId] {
+			continue
+		}
+		if changes := changedTags(tags, nil); len(changes) > 0 {
+			if err := w.config.Tagger.TagInstance(w.callContext, instId, changes); err != nil {
+				logger.Debugf("cannot clear tags of instance %s: %v", instId, err)
+			}
+		}
+		delete(w.applied, instId)
+	}
+	return nil
+}
+
+// changedTags returns the tags that must be set to change an instance's
+// tags from applied to desired.
+func changedTags(applied, desired map[string]string) map[string]string {
+	changes := make(map[string]string)
+	for key, value := range desired {
+		if current, ok := applied[key]; !ok || current != value {
+			changes[key] = value
+		}
+	}
+	for key := range applied {
+		if _, ok := desired[key]; !ok {
+			changes[key] = ""
+		}
+	}
+	return changes
+}
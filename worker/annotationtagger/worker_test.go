@@ -0,0 +1,196 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package annotationtagger_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/worker.v1/workertest"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/environs/context"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/annotationtagger"
+)
+
+type WorkerSuite struct {
+	jujutesting.IsolationSuite
+
+	clock  *testclock.Clock
+	facade *fakeFacade
+	tagger *fakeTagger
+	config annotationtagger.Config
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.clock = testclock.NewClock(time.Now())
+	s.facade = &fakeFacade{}
+	s.facade.setResults([]params.InstanceTagsResult{{
+		MachineTag: "machine-0",
+		InstanceId: "inst-0",
+		Tags:       map[string]string{"cost-centre": "ops", "cost-project": "billing"},
+	}, {
+		MachineTag: "machine-1",
+		InstanceId: "inst-1",
+	}, {
+		MachineTag: "machine-2",
+		Error:      &params.Error{Message: "boom"},
+	}})
+	s.tagger = &fakeTagger{tagged: make(chan taggedInstance, 10)}
+	s.config = annotationtagger.Config{
+		Facade:        s.facade,
+		Tagger:        s.tagger,
+		CredentialAPI: fakeCredentialAPI{},
+		Clock:         s.clock,
+		Interval:      time.Minute,
+	}
+}
+
+func (s *WorkerSuite) startWorker(c *gc.C) {
+	w, err := annotationtagger.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(c *gc.C) { workertest.CleanKill(c, w) })
+}
+
+func (s *WorkerSuite) waitForTagged(c *gc.C, id instance.Id, tags map[string]string) {
+	select {
+	case tagged := <-s.tagger.tagged:
+		c.Assert(tagged.id, gc.Equals, id)
+		c.Assert(tagged.tags, jc.DeepEquals, tags)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for instance to be tagged")
+	}
+}
+
+func (s *WorkerSuite) assertNotTagged(c *gc.C) {
+	select {
+	case tagged := <-s.tagger.tagged:
+		c.Fatalf("unexpected tagging of %s with %v", tagged.id, tagged.tags)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *WorkerSuite) TestInvalidConfig(c *gc.C) {
+	s.config.Interval = 0
+	_, err := annotationtagger.New(s.config)
+	c.Assert(err, gc.ErrorMatches, "non-positive Interval not valid")
+}
+
+func (s *WorkerSuite) TestTagsInstances(c *gc.C) {
+	s.startWorker(c)
+	s.waitForTagged(c, "inst-0", map[string]string{"cost-centre": "ops", "cost-project": "billing"})
+	s.assertNotTagged(c)
+}
+
+func (s *WorkerSuite) TestSetsOnlyChangedTags(c *gc.C) {
+	s.startWorker(c)
+	s.waitForTagged(c, "inst-0", map[string]string{"cost-centre": "ops", "cost-project": "billing"})
+
+	s.facade.setResults([]params.InstanceTagsResult{{
+		MachineTag: "machine-0",
+		InstanceId: "inst-0",
+		Tags:       map[string]string{"cost-centre": "db"},
+	}})
+	err := s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	s.waitForTagged(c, "inst-0", map[string]string{"cost-centre": "db", "cost-project": ""})
+
+	// Nothing has changed, so the instance is not tagged again.
+	err = s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertNotTagged(c)
+}
+
+func (s *WorkerSuite) TestClearsTagsWhenDisabled(c *gc.C) {
+	s.startWorker(c)
+	s.waitForTagged(c, "inst-0", map[string]string{"cost-centre": "ops", "cost-project": "billing"})
+
+	s.facade.setResults(nil)
+	err := s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	s.waitForTagged(c, "inst-0", map[string]string{"cost-centre": "", "cost-project": ""})
+}
+
+func (s *WorkerSuite) TestRetriesFailedTagging(c *gc.C) {
+	s.tagger.setError(errors.New("throttled"))
+	s.startWorker(c)
+	s.waitForTagged(c, "inst-0", map[string]string{"cost-centre": "ops", "cost-project": "billing"})
+
+	s.tagger.setError(nil)
+	err := s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	s.waitForTagged(c, "inst-0", map[string]string{"cost-centre": "ops", "cost-project": "billing"})
+}
+
+func (s *WorkerSuite) TestFacadeErrorStopsWorker(c *gc.C) {
+	s.facade.setError(errors.New("boom"))
+	w, err := annotationtagger.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	err = workertest.CheckKilled(c, w)
+	c.Assert(err, gc.ErrorMatches, "getting instance tags: boom")
+}
+
+type fakeFacade struct {
+	mu      sync.Mutex
+	results []params.InstanceTagsResult
+	err     error
+}
+
+func (f *fakeFacade) setResults(results []params.InstanceTagsResult) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.results = results
+}
+
+func (f *fakeFacade) setError(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+func (f *fakeFacade) InstanceTags() ([]params.InstanceTagsResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.results, f.err
+}
+
+type taggedInstance struct {
+	id   instance.Id
+	tags map[string]string
+}
+
+type fakeTagger struct {
+	mu     sync.Mutex
+	err    error
+	tagged chan taggedInstance
+}
+
+func (f *fakeTagger) setError(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+func (f *fakeTagger) TagInstance(_ context.ProviderCallContext, id instance.Id, tags map[string]string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tagged <- taggedInstance{id: id, tags: tags}
+	return f.err
+}
+
+type fakeCredentialAPI struct{}
+
+func (fakeCredentialAPI) InvalidateModelCredential(string) error {
+	return nil
+}
@@ -15,6 +15,7 @@ import (
 	apiagent "github.com/juju/juju/api/agent"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/instanceidentity"
 )
 
 var (
@@ -33,6 +34,10 @@ var (
 	// be explicitly configured without export_test hackery
 	newConnFacade = apiagent.NewConnFacade
 
+	// fetchInstanceIdentity is patched out in tests, which don't run
+	// on cloud instances.
+	fetchInstanceIdentity = instanceidentity.Fetch
+
 	// errAgentEntityDead is an internal error returned by getEntity.
 	errAgentEntityDead = errors.New("agent entity is dead")
 
@@ -52,6 +57,10 @@ func OnlyConnect(a agent.Agent, apiOpen api.OpenFunc) (api.Connection, error) {
 	if !ok {
 		return nil, errors.New("API info not available")
 	}
+	info, err := withInstanceIdentity(agentConfig, info)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	conn, _, err := connectFallback(apiOpen, info, agentConfig.OldPassword())
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -162,6 +171,27 @@ func connectFallback(
 	return conn, didFallback, nil
 }
 
+// withInstanceIdentity returns the supplied info, or, if the agent was
+// provisioned without any password and is to enroll with the signed
+// identity document of its cloud instance instead, a copy of the info
+// holding that document. The document is issued for the machine nonce,
+// which the controller checks it against where the cloud supports it.
+func withInstanceIdentity(agentConfig agent.Config, info *api.Info) (*api.Info, error) {
+	if info.Password != "" || agentConfig.OldPassword() != "" {
+		return info, nil
+	}
+	if agentConfig.Value(agent.InstanceIdentityEnrollment) != "true" {
+		return info, nil
+	}
+	document, err := fetchInstanceIdentity(agentConfig.Value(agent.ProviderType), info.Nonce)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get instance identity")
+	}
+	infoCopy := *info
+	infoCopy.InstanceIdentity = document
+	return &infoCopy, nil
+}
+
 func shortModelUUID(model names.ModelTag) string {
 	uuid := model.Id()
 	if len(uuid) > 6 {
@@ -190,6 +220,10 @@ func ScaryConnect(a agent.Agent, apiOpen api.OpenFunc) (_ api.Connection, err er
 		return nil, errors.New("API info not available")
 	}
 	oldPassword := agentConfig.OldPassword()
+	info, err = withInstanceIdentity(agentConfig, info)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 
 	defer func() {
 		cause := errors.Cause(err)
@@ -218,6 +218,60 @@ func (*ScaryConnectSuite) assertChangePasswordSuccess(c *gc.C, stub *testing.Stu
 	checkSaneChange(c, stub.Calls()[2:5])
 }
 
+func (s *ScaryConnectSuite) TestEnrollWithInstanceIdentity(c *gc.C) {
+	s.PatchValue(apicaller.FetchInstanceIdentity, func(providerType, audience string) (string, error) {
+		c.Check(providerType, gc.Equals, "ec2")
+		c.Check(audience, gc.Equals, "machine-0:nonce")
+		return "signed-document", nil
+	})
+	stub := &testing.Stub{}
+	expectConn := &mockConn{stub: stub}
+	apiOpen := func(info *api.Info, opts api.DialOpts) (api.Connection, error) {
+		c.Check(info.Password, gc.Equals, "")
+		c.Check(info.InstanceIdentity, gc.Equals, "signed-document")
+		return expectConn, nil
+	}
+
+	entity := names.NewMachineTag("0")
+	connect := func() (api.Connection, error) {
+		return apicaller.ScaryConnect(&enrollingAgent{mockAgent{
+			stub:   stub,
+			model:  coretesting.ModelTag,
+			entity: entity,
+		}}, apiOpen)
+	}
+
+	conn, err := lifeTest(c, stub, apiagent.Alive, connect)
+	c.Check(conn, gc.IsNil)
+	c.Check(err, gc.Equals, apicaller.ErrChangedPassword)
+	stub.CheckCallNames(c,
+		"Life", "ChangeConfig",
+		"SetPassword", "SetOldPassword", "SetPassword",
+		"Close",
+	)
+	calls := stub.Calls()
+	c.Check(calls[3].Args, jc.DeepEquals, []interface{}{""})
+	c.Check(calls[4].Args, jc.DeepEquals, []interface{}{entity, calls[2].Args[0]})
+}
+
+func (s *ScaryConnectSuite) TestEnrollWithInstanceIdentityFetchError(c *gc.C) {
+	s.PatchValue(apicaller.FetchInstanceIdentity, func(string, string) (string, error) {
+		return "", errors.New("no metadata service")
+	})
+	stub := &testing.Stub{}
+	apiOpen := func(info *api.Info, opts api.DialOpts) (api.Connection, error) {
+		c.Fatalf("unexpected connection attempt")
+		return nil, nil
+	}
+	conn, err := apicaller.ScaryConnect(&enrollingAgent{mockAgent{
+		stub:   stub,
+		model:  coretesting.ModelTag,
+		entity: names.NewMachineTag("0"),
+	}}, apiOpen)
+	c.Check(conn, gc.IsNil)
+	c.Check(err, gc.ErrorMatches, "cannot get instance identity: no metadata service")
+}
+
 func createUnauthorisedStub(errs ...error) *testing.Stub {
 	return createPasswordCheckStub(&params.Error{Code: params.CodeUnauthorized}, errs...)
 }
@@ -11,3 +11,7 @@ var Strategy = &checkProvisionedStrategy
 // NewConnFacade is a dirty hack; should be explicit config; not
 // currently convenient.
 var NewConnFacade = &newConnFacade
+
+// FetchInstanceIdentity is patched out because tests don't run on
+// cloud instances.
+var FetchInstanceIdentity = &fetchInstanceIdentity
@@ -73,6 +73,42 @@ func (dummy dummyConfig) OldPassword() string {
 	return "old"
 }
 
+// enrollingAgent is a mockAgent for a machine provisioned without a
+// password, which enrolls with its instance identity.
+type enrollingAgent struct {
+	mockAgent
+}
+
+func (mock *enrollingAgent) CurrentConfig() agent.Config {
+	return enrollingConfig{dummyConfig{
+		entity: mock.entity,
+		model:  mock.model,
+	}}
+}
+
+type enrollingConfig struct {
+	dummyConfig
+}
+
+func (enrolling enrollingConfig) APIInfo() (*api.Info, bool) {
+	return &api.Info{
+		ModelTag: enrolling.model,
+		Tag:      enrolling.entity,
+		Nonce:    "machine-0:nonce",
+	}, true
+}
+
+func (enrolling enrollingConfig) OldPassword() string {
+	return ""
+}
+
+func (enrolling enrollingConfig) Value(key string) string {
+	return map[string]string{
+		agent.InstanceIdentityEnrollment: "true",
+		agent.ProviderType:               "ec2",
+	}[key]
+}
+
 type mockSetter struct {
 	stub *testing.Stub
 	agent.ConfigSetter
@@ -12,6 +12,7 @@ import (
 	"github.com/juju/juju/agent"
 	"github.com/juju/juju/apiserver"
 	"github.com/juju/juju/apiserver/observer"
+	"github.com/juju/juju/apiserver/observer/auditobserver"
 	"github.com/juju/juju/apiserver/observer/metricobserver"
 	"github.com/juju/juju/controller"
 )
@@ -22,6 +23,7 @@ func newObserverFn(
 	clock clock.Clock,
 	hub *pubsub.StructuredHub,
 	metricsCollector *apiserver.Collector,
+	auditSink auditobserver.Sink,
 ) (observer.ObserverFactory, error) {
 
 	var observerFactories []observer.ObserverFactory
@@ -47,6 +49,19 @@ func newObserverFn(
 	}
 	observerFactories = append(observerFactories, metricObserver)
 
+	// Audit trail observer, only when the audit trail is enabled.
+	if auditSink != nil {
+		auditObserver, err := auditobserver.NewObserverFactory(auditobserver.Config{
+			Clock:         clock,
+			Sink:          auditSink,
+			IncludeAgents: controllerConfig.AuditTrailIncludeAgents(),
+		})
+		if err != nil {
+			return nil, errors.Annotate(err, "creating audit trail observer factory")
+		}
+		observerFactories = append(observerFactories, auditObserver)
+	}
+
 	return observer.ObserverFactoryMultiplexer(observerFactories...), nil
 }
 
@@ -58,6 +73,18 @@ func (o metricCollectorWrapper) APIRequestDuration() metricobserver.SummaryVec {
 	return o.collector.APIRequestDuration
 }
 
+func (o metricCollectorWrapper) APIRequestsTotal() metricobserver.CounterVec {
+	return o.collector.APIRequestsTotal
+}
+
+func (o metricCollectorWrapper) APIRequestErrors() metricobserver.CounterVec {
+	return o.collector.APIRequestErrors
+}
+
+func (o metricCollectorWrapper) APIRequestLatency() metricobserver.HistogramVec {
+	return o.collector.APIRequestLatency
+}
+
 // TODO (stickupkid): Remove this in 2.6+ as DeprecatedAPIRequestsTotal will become
 // obsolete
 func (o metricCollectorWrapper) DeprecatedAPIRequestsTotal() metricobserver.CounterVec {
@@ -16,11 +16,13 @@ import (
 	"github.com/juju/juju/apiserver"
 	"github.com/juju/juju/apiserver/apiserverhttp"
 	"github.com/juju/juju/apiserver/httpcontext"
+	"github.com/juju/juju/apiserver/observer/auditobserver"
 	"github.com/juju/juju/core/auditlog"
 	"github.com/juju/juju/core/cache"
 	"github.com/juju/juju/core/lease"
 	"github.com/juju/juju/core/presence"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker/common"
 )
 
 var logger = loggo.GetLogger("juju.worker.apiserver")
@@ -116,14 +118,37 @@ func NewWorker(config Config) (worker.Worker, error) {
 		return nil, errors.Annotate(err, "cannot fetch the controller config")
 	}
 
+	// The audit trail is written in the background, by a recorder
+	// that lives as long as the server.
+	var auditRecorder *auditobserver.Recorder
+	var auditSink auditobserver.Sink
+	if controllerConfig.AuditTrailEnabled() {
+		auditRecorder, err = auditobserver.NewRecorder(
+			config.StatePool.SystemState(), auditobserver.DefaultBufferSize,
+		)
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot start audit trail recorder")
+		}
+		auditSink = auditRecorder
+	}
+	stopAuditRecorder := func() {
+		if auditRecorder != nil {
+			if err := worker.Stop(auditRecorder); err != nil {
+				logger.Errorf("stopping audit trail recorder: %v", err)
+			}
+		}
+	}
+
 	observerFactory, err := newObserverFn(
 		config.AgentConfig,
 		controllerConfig,
 		config.Clock,
 		config.Hub,
 		config.MetricsCollector,
+		auditSink,
 	)
 	if err != nil {
+		stopAuditRecorder()
 		return nil, errors.Annotate(err, "cannot create RPC observer factory")
 	}
 
@@ -150,7 +175,12 @@ func NewWorker(config Config) (worker.Worker, error) {
 		GetAuditConfig:                config.GetAuditConfig,
 		LeaseManager:                  config.LeaseManager,
 	}
-	return config.NewServer(serverConfig)
+	server, err := config.NewServer(serverConfig)
+	if err != nil {
+		stopAuditRecorder()
+		return nil, errors.Trace(err)
+	}
+	return common.NewCleanupWorker(server, stopAuditRecorder), nil
 }
 
 func newServerShim(config apiserver.ServerConfig) (worker.Worker, error) {
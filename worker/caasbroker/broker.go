@@ -5,10 +5,13 @@ package caasbroker
 
 import (
 	"reflect"
+	"time"
 
+	"github.com/juju/clock"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"gopkg.in/juju/worker.v1/catacomb"
+	"gopkg.in/juju/worker.v1/dependency"
 
 	"github.com/juju/juju/caas"
 	"github.com/juju/juju/controller"
@@ -19,6 +22,18 @@ import (
 
 var logger = loggo.GetLogger("juju.worker.caas")
 
+const (
+	// DefaultHealthCheckInterval is how often the connection of a
+	// healthy broker is checked.
+	DefaultHealthCheckInterval = time.Minute
+
+	// initialHealthCheckRetryDelay is how long to wait before checking
+	// a broker again after its first failed health check. The delay
+	// doubles with each subsequent failure, up to maxHealthCheckRetryDelay.
+	initialHealthCheckRetryDelay = 5 * time.Second
+	maxHealthCheckRetryDelay     = 5 * time.Minute
+)
+
 // ConfigAPI exposes a model configuration and a watch constructor
 // that allows clients to be informed of changes to the configuration.
 type ConfigAPI interface {
@@ -36,6 +51,12 @@ type ConfigAPI interface {
 type Config struct {
 	ConfigAPI              ConfigAPI
 	NewContainerBrokerFunc caas.NewContainerBrokerFunc
+	Clock                  clock.Clock
+
+	// HealthCheckInterval is how often the broker's connection is
+	// checked while it is healthy. DefaultHealthCheckInterval is
+	// used if it is zero.
+	HealthCheckInterval time.Duration
 }
 
 // Validate returns an error if the config cannot be used to start a Tracker.
@@ -46,6 +67,12 @@ func (config Config) Validate() error {
 	if config.NewContainerBrokerFunc == nil {
 		return errors.NotValidf("nil NewContainerBrokerFunc")
 	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if config.HealthCheckInterval < 0 {
+		return errors.NotValidf("negative HealthCheckInterval")
+	}
 	return nil
 }
 
@@ -103,6 +130,13 @@ func NewTracker(config Config) (*Tracker, error) {
 	return t, nil
 }
 
+func (t *Tracker) healthCheckInterval() time.Duration {
+	if t.config.HealthCheckInterval == 0 {
+		return DefaultHealthCheckInterval
+	}
+	return t.config.HealthCheckInterval
+}
+
 // Broker returns the encapsulated Broker. It will continue to be updated in
 // the background for as long as the Tracker continues to run.
 func (t *Tracker) Broker() caas.Broker {
@@ -138,6 +172,20 @@ func (t *Tracker) loop() error {
 		cloudWatcherChanges = cloudWatcher.Changes()
 	}
 
+	// Brokers that support it have their connection checked
+	// periodically. Once a broker recovers from a failed check,
+	// the tracker is bounced so that it, and the workers using
+	// it, start again with a fresh connection.
+	var (
+		healthCheck    <-chan time.Time
+		healthFailures int
+		retryDelay     time.Duration
+	)
+	healthChecker, ok := t.broker.(caas.HealthChecker)
+	if ok {
+		healthCheck = t.config.Clock.After(t.healthCheckInterval())
+	}
+
 	for {
 		logger.Debugf("waiting for config and credential notifications")
 		select {
@@ -171,6 +219,26 @@ func (t *Tracker) loop() error {
 				return errors.Annotate(err, "cannot update broker cloud spec")
 			}
 			t.currentCloudSpec = cloudSpec
+		case <-healthCheck:
+			err := healthChecker.CheckHealth()
+			if err == nil {
+				if healthFailures > 0 {
+					logger.Infof("caas broker healthy after %d failed checks, reconnecting", healthFailures)
+					return dependency.ErrBounce
+				}
+				healthCheck = t.config.Clock.After(t.healthCheckInterval())
+				continue
+			}
+			healthFailures++
+			retryDelay *= 2
+			if retryDelay == 0 {
+				retryDelay = initialHealthCheckRetryDelay
+			}
+			if retryDelay > maxHealthCheckRetryDelay {
+				retryDelay = maxHealthCheckRetryDelay
+			}
+			logger.Warningf("caas broker health check failed, checking again in %v: %v", retryDelay, err)
+			healthCheck = t.config.Clock.After(retryDelay)
 		}
 	}
 }
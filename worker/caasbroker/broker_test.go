@@ -6,10 +6,12 @@ package caasbroker_test
 import (
 	"time"
 
+	"github.com/juju/clock/testclock"
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/worker.v1/dependency"
 	"gopkg.in/juju/worker.v1/workertest"
 
 	"github.com/juju/juju/caas"
@@ -42,6 +44,17 @@ func (s *TrackerSuite) TestValidateNewBrokerFunc(c *gc.C) {
 	})
 }
 
+func (s *TrackerSuite) TestValidateClock(c *gc.C) {
+	config := caasbroker.Config{
+		ConfigAPI:              &runContext{},
+		NewContainerBrokerFunc: newMockBroker,
+	}
+	s.testValidate(c, config, func(err error) {
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+		c.Check(err, gc.ErrorMatches, "nil Clock not valid")
+	})
+}
+
 func (s *TrackerSuite) testValidate(c *gc.C, config caasbroker.Config, check func(err error)) {
 	err := config.Validate()
 	check(err)
@@ -60,6 +73,7 @@ func (s *TrackerSuite) TestCloudSpecFails(c *gc.C) {
 	fix.Run(c, func(context *runContext) {
 		tracker, err := caasbroker.NewTracker(caasbroker.Config{
 			ConfigAPI:              context,
+			Clock:                  testclock.NewClock(time.Time{}),
 			NewContainerBrokerFunc: newMockBroker,
 		})
 		c.Check(err, gc.ErrorMatches, "cannot get cloud information: no you")
@@ -85,6 +99,7 @@ func (s *TrackerSuite) TestSuccess(c *gc.C) {
 	fix.Run(c, func(context *runContext) {
 		tracker, err := caasbroker.NewTracker(caasbroker.Config{
 			ConfigAPI:              context,
+			Clock:                  testclock.NewClock(time.Time{}),
 			NewContainerBrokerFunc: newMockBroker,
 		})
 		c.Assert(err, jc.ErrorIsNil)
@@ -100,6 +115,7 @@ func (s *TrackerSuite) TestInitialise(c *gc.C) {
 	fix.Run(c, func(context *runContext) {
 		tracker, err := caasbroker.NewTracker(caasbroker.Config{
 			ConfigAPI: context,
+			Clock:     testclock.NewClock(time.Time{}),
 			NewContainerBrokerFunc: func(args environs.OpenParams) (caas.Broker, error) {
 				c.Assert(args.Cloud, jc.DeepEquals, fix.initialSpec)
 				c.Assert(args.Config.Name(), jc.DeepEquals, "testmodel")
@@ -122,6 +138,7 @@ func (s *TrackerSuite) TestModelConfigFails(c *gc.C) {
 	fix.Run(c, func(context *runContext) {
 		tracker, err := caasbroker.NewTracker(caasbroker.Config{
 			ConfigAPI:              context,
+			Clock:                  testclock.NewClock(time.Time{}),
 			NewContainerBrokerFunc: newMockBroker,
 		})
 		c.Check(err, gc.ErrorMatches, "no you")
@@ -135,6 +152,7 @@ func (s *TrackerSuite) TestModelConfigInvalid(c *gc.C) {
 	fix.Run(c, func(context *runContext) {
 		tracker, err := caasbroker.NewTracker(caasbroker.Config{
 			ConfigAPI: context,
+			Clock:     testclock.NewClock(time.Time{}),
 			NewContainerBrokerFunc: func(environs.OpenParams) (caas.Broker, error) {
 				return nil, errors.NotValidf("config")
 			},
@@ -154,6 +172,7 @@ func (s *TrackerSuite) TestModelConfigValid(c *gc.C) {
 	fix.Run(c, func(context *runContext) {
 		tracker, err := caasbroker.NewTracker(caasbroker.Config{
 			ConfigAPI:              context,
+			Clock:                  testclock.NewClock(time.Time{}),
 			NewContainerBrokerFunc: newMockBroker,
 		})
 		c.Assert(err, jc.ErrorIsNil)
@@ -175,6 +194,7 @@ func (s *TrackerSuite) TestCloudSpecInvalid(c *gc.C) {
 	fix.Run(c, func(context *runContext) {
 		tracker, err := caasbroker.NewTracker(caasbroker.Config{
 			ConfigAPI: context,
+			Clock:     testclock.NewClock(time.Time{}),
 			NewContainerBrokerFunc: func(args environs.OpenParams) (caas.Broker, error) {
 				c.Assert(args.Cloud, jc.DeepEquals, cloudSpec)
 				return nil, errors.NotValidf("cloud spec")
@@ -195,6 +215,7 @@ func (s *TrackerSuite) TestWatchFails(c *gc.C) {
 	fix.Run(c, func(context *runContext) {
 		tracker, err := caasbroker.NewTracker(caasbroker.Config{
 			ConfigAPI:              context,
+			Clock:                  testclock.NewClock(time.Time{}),
 			NewContainerBrokerFunc: newMockBroker,
 		})
 		c.Assert(err, jc.ErrorIsNil)
@@ -211,6 +232,7 @@ func (s *TrackerSuite) TestModelConfigWatchCloses(c *gc.C) {
 	fix.Run(c, func(context *runContext) {
 		tracker, err := caasbroker.NewTracker(caasbroker.Config{
 			ConfigAPI:              context,
+			Clock:                  testclock.NewClock(time.Time{}),
 			NewContainerBrokerFunc: newMockBroker,
 		})
 		c.Assert(err, jc.ErrorIsNil)
@@ -228,6 +250,7 @@ func (s *TrackerSuite) TestCloudSpecWatchCloses(c *gc.C) {
 	fix.Run(c, func(context *runContext) {
 		tracker, err := caasbroker.NewTracker(caasbroker.Config{
 			ConfigAPI:              context,
+			Clock:                  testclock.NewClock(time.Time{}),
 			NewContainerBrokerFunc: newMockBroker,
 		})
 		c.Assert(err, jc.ErrorIsNil)
@@ -249,6 +272,7 @@ func (s *TrackerSuite) TestWatchedModelConfigFails(c *gc.C) {
 	fix.Run(c, func(context *runContext) {
 		tracker, err := caasbroker.NewTracker(caasbroker.Config{
 			ConfigAPI:              context,
+			Clock:                  testclock.NewClock(time.Time{}),
 			NewContainerBrokerFunc: newMockBroker,
 		})
 		c.Check(err, jc.ErrorIsNil)
@@ -266,6 +290,7 @@ func (s *TrackerSuite) TestWatchedModelConfigIncompatible(c *gc.C) {
 	fix.Run(c, func(context *runContext) {
 		tracker, err := caasbroker.NewTracker(caasbroker.Config{
 			ConfigAPI: context,
+			Clock:     testclock.NewClock(time.Time{}),
 			NewContainerBrokerFunc: func(environs.OpenParams) (caas.Broker, error) {
 				broker := &mockBroker{}
 				broker.SetErrors(errors.New("SetConfig is broken"))
@@ -291,6 +316,7 @@ func (s *TrackerSuite) TestWatchedModelConfigUpdates(c *gc.C) {
 	fix.Run(c, func(context *runContext) {
 		tracker, err := caasbroker.NewTracker(caasbroker.Config{
 			ConfigAPI:              context,
+			Clock:                  testclock.NewClock(time.Time{}),
 			NewContainerBrokerFunc: newMockBroker,
 		})
 		c.Check(err, jc.ErrorIsNil)
@@ -329,6 +355,7 @@ func (s *TrackerSuite) TestWatchedCloudSpecUpdates(c *gc.C) {
 	fix.Run(c, func(context *runContext) {
 		tracker, err := caasbroker.NewTracker(caasbroker.Config{
 			ConfigAPI:              context,
+			Clock:                  testclock.NewClock(time.Time{}),
 			NewContainerBrokerFunc: newMockBroker,
 		})
 		c.Check(err, jc.ErrorIsNil)
@@ -357,3 +384,59 @@ func (s *TrackerSuite) TestWatchedCloudSpecUpdates(c *gc.C) {
 		}
 	})
 }
+
+func (s *TrackerSuite) TestHealthCheckHealthy(c *gc.C) {
+	fix := s.validFixture()
+	fix.Run(c, func(context *runContext) {
+		clock := testclock.NewClock(time.Time{})
+		broker := newHealthCheckBroker()
+		tracker, err := caasbroker.NewTracker(caasbroker.Config{
+			ConfigAPI: context,
+			Clock:     clock,
+			NewContainerBrokerFunc: func(args environs.OpenParams) (caas.Broker, error) {
+				mock, err := newMockBroker(args)
+				broker.mockBroker = mock.(*mockBroker)
+				return broker, err
+			},
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		defer workertest.CleanKill(c, tracker)
+
+		for i := 0; i < 2; i++ {
+			err = clock.WaitAdvance(caasbroker.DefaultHealthCheckInterval, coretesting.LongWait, 1)
+			c.Assert(err, jc.ErrorIsNil)
+			broker.waitForCheck(c)
+		}
+		workertest.CheckAlive(c, tracker)
+	})
+}
+
+func (s *TrackerSuite) TestHealthCheckRecoveryBounces(c *gc.C) {
+	fix := s.validFixture()
+	fix.Run(c, func(context *runContext) {
+		clock := testclock.NewClock(time.Time{})
+		broker := newHealthCheckBroker(errors.New("boom"), errors.New("boom"))
+		tracker, err := caasbroker.NewTracker(caasbroker.Config{
+			ConfigAPI:           context,
+			Clock:               clock,
+			HealthCheckInterval: time.Hour,
+			NewContainerBrokerFunc: func(args environs.OpenParams) (caas.Broker, error) {
+				mock, err := newMockBroker(args)
+				broker.mockBroker = mock.(*mockBroker)
+				return broker, err
+			},
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		defer workertest.DirtyKill(c, tracker)
+
+		// The first check is made after the configured interval, and
+		// failed checks are retried with an increasing delay.
+		for _, delay := range []time.Duration{time.Hour, 5 * time.Second, 10 * time.Second} {
+			err = clock.WaitAdvance(delay, coretesting.LongWait, 1)
+			c.Assert(err, jc.ErrorIsNil)
+			broker.waitForCheck(c)
+		}
+		err = workertest.CheckKilled(c, tracker)
+		c.Assert(err, gc.Equals, dependency.ErrBounce)
+	})
+}
@@ -5,6 +5,7 @@ package caasbroker_test
 
 import (
 	"sync"
+	"time"
 
 	"github.com/juju/testing"
 	gc "gopkg.in/check.v1"
@@ -263,3 +264,38 @@ func (e *mockBroker) SetConfig(cfg *config.Config) error {
 	e.cfg = cfg
 	return nil
 }
+
+type healthCheckBroker struct {
+	*mockBroker
+	checked chan struct{}
+	errs    []error
+}
+
+func newHealthCheckBroker(errs ...error) *healthCheckBroker {
+	return &healthCheckBroker{
+		checked: make(chan struct{}, 10),
+		errs:    errs,
+	}
+}
+
+// CheckHealth is part of the caas.HealthChecker interface.
+func (e *healthCheckBroker) CheckHealth() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.MethodCall(e, "CheckHealth")
+	e.checked <- struct{}{}
+	if len(e.errs) == 0 {
+		return nil
+	}
+	err := e.errs[0]
+	e.errs = e.errs[1:]
+	return err
+}
+
+func (e *healthCheckBroker) waitForCheck(c *gc.C) {
+	select {
+	case <-e.checked:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for health check")
+	}
+}
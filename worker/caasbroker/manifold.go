@@ -4,6 +4,7 @@
 package caasbroker
 
 import (
+	"github.com/juju/clock"
 	"github.com/juju/errors"
 	"gopkg.in/juju/worker.v1"
 	"gopkg.in/juju/worker.v1/dependency"
@@ -18,6 +19,7 @@ import (
 // ManifoldConfig describes the resources used by a Tracker.
 type ManifoldConfig struct {
 	APICallerName          string
+	ClockName              string
 	NewContainerBrokerFunc caas.NewContainerBrokerFunc
 }
 
@@ -27,6 +29,7 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 	manifold := dependency.Manifold{
 		Inputs: []string{
 			config.APICallerName,
+			config.ClockName,
 		},
 		Output: manifoldOutput,
 		Start: func(context dependency.Context) (worker.Worker, error) {
@@ -34,6 +37,10 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 			if err := context.Get(config.APICallerName, &apiCaller); err != nil {
 				return nil, errors.Trace(err)
 			}
+			var clock clock.Clock
+			if err := context.Get(config.ClockName, &clock); err != nil {
+				return nil, errors.Trace(err)
+			}
 			api, err := caasagent.NewClient(apiCaller)
 			if err != nil {
 				return nil, errors.Trace(err)
@@ -41,6 +48,7 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 			w, err := NewTracker(Config{
 				ConfigAPI:              api,
 				NewContainerBrokerFunc: config.NewContainerBrokerFunc,
+				Clock:                  clock,
 			})
 			if err != nil {
 				return nil, errors.Trace(err)
@@ -4,6 +4,8 @@
 package caasoperatorprovisioner
 
 import (
+	"time"
+
 	"github.com/juju/clock"
 	"github.com/juju/errors"
 	"gopkg.in/juju/worker.v1"
@@ -22,6 +24,11 @@ type ManifoldConfig struct {
 	BrokerName    string
 	ClockName     string
 
+	// ForceDeleteOperatorAfter is how long the operator of a removed
+	// application is given to terminate before it is force deleted.
+	// The worker's default is used if it is zero.
+	ForceDeleteOperatorAfter time.Duration
+
 	NewWorker func(Config) (worker.Worker, error)
 }
 
@@ -78,11 +85,12 @@ func (config ManifoldConfig) start(context dependency.Context) (worker.Worker, e
 	api := caasoperatorprovisioner.NewClient(apiCaller)
 	agentConfig := agent.CurrentConfig()
 	w, err := config.NewWorker(Config{
-		Facade:      api,
-		Broker:      broker,
-		ModelTag:    modelTag,
-		AgentConfig: agentConfig,
-		Clock:       clock,
+		Facade:                   api,
+		Broker:                   broker,
+		ModelTag:                 modelTag,
+		AgentConfig:              agentConfig,
+		Clock:                    clock,
+		ForceDeleteOperatorAfter: config.ForceDeleteOperatorAfter,
 	})
 	if err != nil {
 		return nil, errors.Trace(err)
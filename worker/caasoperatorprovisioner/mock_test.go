@@ -17,7 +17,9 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/caas"
 	"github.com/juju/juju/core/life"
+	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/core/watcher"
+	"github.com/juju/juju/environs/config"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/worker/caasoperatorprovisioner"
 )
@@ -28,7 +30,11 @@ type mockProvisionerFacade struct {
 	caasoperatorprovisioner.CAASProvisionerFacade
 	applicationsWatcher *mockStringsWatcher
 	apiWatcher          *mockNotifyWatcher
+	configWatcher       *mockNotifyWatcher
 	life                life.Value
+	modelAttrs          coretesting.Attrs
+	operatorVersions    map[string]version.Number
+	provisioningMode    string
 }
 
 func newMockProvisionerFacade(stub *testing.Stub) *mockProvisionerFacade {
@@ -36,9 +42,47 @@ func newMockProvisionerFacade(stub *testing.Stub) *mockProvisionerFacade {
 		stub:                stub,
 		applicationsWatcher: newMockStringsWatcher(),
 		apiWatcher:          newMockNotifyWatcher(),
+		configWatcher:       newMockNotifyWatcher(),
+		operatorVersions:    make(map[string]version.Number),
 	}
 }
 
+func (m *mockProvisionerFacade) setOperatorVersion(appName string, vers version.Number) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.operatorVersions[appName] = vers
+}
+
+func (m *mockProvisionerFacade) WatchForModelConfigChanges() (watcher.NotifyWatcher, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stub.MethodCall(m, "WatchForModelConfigChanges")
+	if err := m.stub.NextErr(); err != nil {
+		return nil, err
+	}
+	return m.configWatcher, nil
+}
+
+func (m *mockProvisionerFacade) ModelConfig() (*config.Config, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stub.MethodCall(m, "ModelConfig")
+	if err := m.stub.NextErr(); err != nil {
+		return nil, err
+	}
+	return config.New(config.UseDefaults, coretesting.FakeConfig().Merge(m.modelAttrs))
+}
+
+func (m *mockProvisionerFacade) OperatorVersion(appName string) (version.Number, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stub.MethodCall(m, "OperatorVersion", appName)
+	if err := m.stub.NextErr(); err != nil {
+		return version.Zero, err
+	}
+	return m.operatorVersions[appName], nil
+}
+
 func (m *mockProvisionerFacade) WatchApplications() (watcher.StringsWatcher, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -49,24 +93,33 @@ func (m *mockProvisionerFacade) WatchApplications() (watcher.StringsWatcher, err
 	return m.applicationsWatcher, nil
 }
 
-func (m *mockProvisionerFacade) OperatorProvisioningInfo() (apicaasprovisioner.OperatorProvisioningInfo, error) {
+func (m *mockProvisionerFacade) OperatorProvisioningInfo(appName string) (apicaasprovisioner.OperatorProvisioningInfo, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	m.stub.MethodCall(m, "OperatorProvisioningInfo")
+	m.stub.MethodCall(m, "OperatorProvisioningInfo", appName)
 	if err := m.stub.NextErr(); err != nil {
 		return apicaasprovisioner.OperatorProvisioningInfo{}, err
 	}
 	return apicaasprovisioner.OperatorProvisioningInfo{
-		ImagePath:    "juju-operator-image",
-		Version:      version.MustParse("2.99.0"),
-		APIAddresses: []string{"10.0.0.1:17070", "192.18.1.1:17070"},
-		Tags:         map[string]string{"fred": "mary"},
+		ProvisioningMode: m.provisioningMode,
+		ImagePath:        "juju-operator-image",
+		ImageUsername:    "fred",
+		ImagePassword:    "secret",
+		Version:          version.MustParse("2.99.0"),
+		APIAddresses:     []string{"10.0.0.1:17070", "192.18.1.1:17070"},
+		Tags:             map[string]string{"fred": "mary"},
 		CharmStorage: storage.KubernetesFilesystemParams{
 			Provider:     "kubernetes",
 			Size:         uint64(1024),
 			ResourceTags: map[string]string{"foo": "bar"},
 			Attributes:   map[string]interface{}{"key": "value"},
 		},
+		CPU:          "500m",
+		Memory:       "256Mi",
+		NodeSelector: []string{"pool=controllers"},
+		NodeAffinity: []string{"zone=a|b"},
+		Tolerations:  []string{"dedicated=juju:NoSchedule"},
+		Replicas:     3,
 	}, nil
 }
 
@@ -80,6 +133,13 @@ func (m *mockProvisionerFacade) Life(entityName string) (life.Value, error) {
 	return m.life, nil
 }
 
+func (m *mockProvisionerFacade) SetOperatorStatus(appName string, status status.Status, message string, data map[string]interface{}) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stub.MethodCall(m, "SetOperatorStatus", appName, status, message, data)
+	return m.stub.NextErr()
+}
+
 func (m *mockProvisionerFacade) SetPasswords(passwords []apicaasprovisioner.ApplicationPassword) (params.ErrorResults, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -120,9 +180,10 @@ type mockBroker struct {
 	testing.Stub
 	caas.Broker
 
-	mu             sync.Mutex
-	terminating    bool
-	operatorExists bool
+	mu              sync.Mutex
+	terminating     bool
+	operatorExists  bool
+	operatorWatcher *mockNotifyWatcher
 }
 
 func (m *mockBroker) setTerminating(terminating bool) {
@@ -144,6 +205,18 @@ func (m *mockBroker) EnsureOperator(appName, agentPath string, config *caas.Oper
 	return m.NextErr()
 }
 
+func (m *mockBroker) EnsureSidecar(appName string, config *caas.SidecarConfig) error {
+	m.MethodCall(m, "EnsureSidecar", appName, config)
+	return m.NextErr()
+}
+
+func (m *mockBroker) SidecarExists(appName string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.MethodCall(m, "SidecarExists", appName)
+	return m.operatorExists, m.NextErr()
+}
+
 func (m *mockBroker) OperatorExists(appName string) (caas.OperatorState, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -151,11 +224,29 @@ func (m *mockBroker) OperatorExists(appName string) (caas.OperatorState, error)
 	return caas.OperatorState{Exists: m.operatorExists, Terminating: m.terminating}, m.NextErr()
 }
 
+func (m *mockBroker) WatchOperator(appName string) (watcher.NotifyWatcher, error) {
+	m.MethodCall(m, "WatchOperator", appName)
+	if err := m.NextErr(); err != nil {
+		return nil, err
+	}
+	return m.operatorWatcher, nil
+}
+
 func (m *mockBroker) DeleteOperator(appName string) error {
 	m.MethodCall(m, "DeleteOperator", appName)
 	return m.NextErr()
 }
 
+func (m *mockBroker) Upgrade(appName string, vers version.Number) error {
+	m.MethodCall(m, "Upgrade", appName, vers)
+	return m.NextErr()
+}
+
+func (m *mockBroker) ForceDeleteOperator(appName string) error {
+	m.MethodCall(m, "ForceDeleteOperator", appName)
+	return m.NextErr()
+}
+
 type mockWatcher struct {
 	testing.Stub
 	tomb.Tomb
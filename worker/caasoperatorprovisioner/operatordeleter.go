@@ -0,0 +1,170 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasoperatorprovisioner
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"gopkg.in/juju/worker.v1/catacomb"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/caas"
+	"github.com/juju/juju/core/status"
+)
+
+const (
+	// DefaultForceDeleteOperatorAfter is how long an operator is given
+	// to terminate after being deleted before it is force deleted.
+	DefaultForceDeleteOperatorAfter = 5 * time.Minute
+
+	// operatorDeletePollInitial and operatorDeletePollMax bound the
+	// exponentially growing delay between checks of whether a deleted
+	// operator has terminated.
+	operatorDeletePollInitial = time.Second
+	operatorDeletePollMax     = 30 * time.Second
+)
+
+// operatorDeleter deletes an application's operator and waits for it
+// to terminate, force deleting it if it is still terminating after
+// forceAfter. Progress is recorded in the application's operator
+// status, for as long as the application exists. The application's
+// name is sent on done when the deleter has finished.
+type operatorDeleter struct {
+	catacomb   catacomb.Catacomb
+	app        string
+	broker     caas.Broker
+	facade     CAASProvisionerFacade
+	clock      clock.Clock
+	forceAfter time.Duration
+	done       chan<- string
+}
+
+func newOperatorDeleter(
+	app string,
+	broker caas.Broker,
+	facade CAASProvisionerFacade,
+	clock clock.Clock,
+	forceAfter time.Duration,
+	done chan<- string,
+) (*operatorDeleter, error) {
+	d := &operatorDeleter{
+		app:        app,
+		broker:     broker,
+		facade:     facade,
+		clock:      clock,
+		forceAfter: forceAfter,
+		done:       done,
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &d.catacomb,
+		Work: d.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return d, nil
+}
+
+// Kill is part of the worker.Worker interface.
+func (d *operatorDeleter) Kill() {
+	d.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (d *operatorDeleter) Wait() error {
+	return d.catacomb.Wait()
+}
+
+func (d *operatorDeleter) loop() error {
+	if err := d.deleteOperator(); err != nil {
+		return errors.Trace(err)
+	}
+	select {
+	case <-d.catacomb.Dying():
+		return d.catacomb.ErrDying()
+	case d.done <- d.app:
+		return nil
+	}
+}
+
+func (d *operatorDeleter) deleteOperator() error {
+	logger.Debugf("deleting operator for %q", d.app)
+	if err := d.broker.DeleteOperator(d.app); err != nil {
+		return errors.Annotatef(err, "failed to stop operator for %q", d.app)
+	}
+	start := d.clock.Now()
+	forced := false
+	delay := operatorDeletePollInitial
+	for {
+		opState, err := d.broker.OperatorExists(d.app)
+		if err != nil {
+			return errors.Annotatef(err, "failed to find operator for %q", d.app)
+		}
+		if !opState.Exists {
+			logger.Infof("deleted operator for %q", d.app)
+			return nil
+		}
+		if !opState.Terminating {
+			// A new operator has been deployed for an application
+			// of the same name; it must be left alone.
+			logger.Debugf("operator for %q is running again", d.app)
+			return nil
+		}
+
+		waited := d.clock.Now().Sub(start)
+		switch {
+		case !forced && waited >= d.forceAfter:
+			logger.Warningf("operator for %q still terminating after %v, force deleting", d.app, d.forceAfter)
+			if err := d.broker.ForceDeleteOperator(d.app); err != nil {
+				return errors.Annotatef(err, "failed to force delete operator for %q", d.app)
+			}
+			forced = true
+			d.setStatus(status.Maintenance, fmt.Sprintf(
+				"force deleting operator, still terminating after %v", d.forceAfter))
+		case forced && waited >= 2*d.forceAfter:
+			// Nothing more can be done; the operator's remains must be
+			// cleaned up in the cluster.
+			message := fmt.Sprintf("operator still terminating %v after being force deleted", waited-d.forceAfter)
+			logger.Errorf("giving up deleting operator for %q: %s", d.app, message)
+			d.setStatus(status.Error, message)
+			return nil
+		case !forced:
+			d.setStatus(status.Maintenance, fmt.Sprintf(
+				"waiting for operator to terminate (%v)", waited.Round(time.Second)))
+		}
+
+		select {
+		case <-d.catacomb.Dying():
+			return d.catacomb.ErrDying()
+		case <-d.clock.After(delay):
+		}
+		delay *= 2
+		if delay > operatorDeletePollMax {
+			delay = operatorDeletePollMax
+		}
+	}
+}
+
+// setStatus records the progress of the deletion in the application's
+// operator status. The application is usually removed while its
+// operator is deleted, so failures are only logged.
+func (d *operatorDeleter) setStatus(s status.Status, message string) {
+	recordOperatorStatus(d.facade, d.app, s, message)
+}
+
+// recordOperatorStatus records the progress of work on an application's
+// operator in its operator status, logging any failure to do so.
+func recordOperatorStatus(facade CAASProvisionerFacade, app string, s status.Status, message string) {
+	err := facade.SetOperatorStatus(app, s, message, nil)
+	switch {
+	case err == nil:
+	case errors.IsNotFound(err), params.IsCodeNotFound(err), errors.IsNotSupported(err):
+		logger.Debugf("cannot set operator status for %q: %v", app, err)
+	default:
+		logger.Warningf("cannot set operator status for %q: %v", app, err)
+	}
+}
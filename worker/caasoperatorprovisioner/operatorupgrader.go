@@ -0,0 +1,213 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasoperatorprovisioner
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"github.com/juju/version"
+	"gopkg.in/juju/worker.v1/catacomb"
+
+	"github.com/juju/juju/caas"
+	"github.com/juju/juju/core/status"
+)
+
+const (
+	// operatorUpgradePollInterval is how often the operators being
+	// upgraded are checked for the new version.
+	operatorUpgradePollInterval = 5 * time.Second
+
+	// operatorUpgradeTimeout is how long a batch of operators is given
+	// to report the new version before the next batch is upgraded.
+	operatorUpgradeTimeout = 10 * time.Minute
+)
+
+// operatorUpgrader upgrades the operators of a set of applications to a
+// new agent version, a batch of at most concurrency at a time. It waits
+// for the operators in a batch to report the new version, or for
+// operatorUpgradeTimeout, before upgrading the next batch. Progress is
+// recorded in each application's operator status.
+type operatorUpgrader struct {
+	catacomb    catacomb.Catacomb
+	apps        []string
+	version     version.Number
+	concurrency int
+	broker      caas.Upgrader
+	facade      CAASProvisionerFacade
+	clock       clock.Clock
+
+	// upgraded holds the applications whose operators have been
+	// upgraded; it's read by the provisioner.
+	mu       sync.Mutex
+	upgraded set.Strings
+}
+
+func newOperatorUpgrader(
+	apps []string,
+	vers version.Number,
+	concurrency int,
+	broker caas.Upgrader,
+	facade CAASProvisionerFacade,
+	clock clock.Clock,
+) (*operatorUpgrader, error) {
+	if concurrency < 1 {
+		return nil, errors.NotValidf("operator upgrade concurrency %d", concurrency)
+	}
+	u := &operatorUpgrader{
+		apps:        apps,
+		version:     vers,
+		concurrency: concurrency,
+		broker:      broker,
+		facade:      facade,
+		clock:       clock,
+		upgraded:    set.NewStrings(),
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &u.catacomb,
+		Work: u.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return u, nil
+}
+
+// Kill is part of the worker.Worker interface.
+func (u *operatorUpgrader) Kill() {
+	u.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (u *operatorUpgrader) Wait() error {
+	return u.catacomb.Wait()
+}
+
+// started reports whether the operator of the application has been
+// upgraded, though it may still be starting up.
+func (u *operatorUpgrader) started(app string) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.upgraded.Contains(app)
+}
+
+func (u *operatorUpgrader) loop() error {
+	apps, err := u.outdatedOperators(u.apps)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(apps) == 0 {
+		logger.Debugf("all operators are running %v", u.version)
+		return nil
+	}
+	var batches [][]string
+	for len(apps) > 0 {
+		n := u.concurrency
+		if n > len(apps) {
+			n = len(apps)
+		}
+		batches = append(batches, apps[:n])
+		apps = apps[n:]
+	}
+	logger.Infof("upgrading operators to %v in %d batches of up to %d", u.version, len(batches), u.concurrency)
+	for i, batch := range batches[1:] {
+		for _, app := range batch {
+			u.setStatus(app, status.Waiting, fmt.Sprintf(
+				"waiting to upgrade operator to %v (batch %d of %d)", u.version, i+2, len(batches)))
+		}
+	}
+	for i, batch := range batches {
+		if err := u.upgradeBatch(batch, i+1, len(batches)); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	logger.Infof("upgraded operators to %v", u.version)
+	return nil
+}
+
+// outdatedOperators returns those of the given applications whose
+// operators report an older version than the one being upgraded to.
+// Operators which haven't reported a version are new, and are deployed
+// with the current version.
+func (u *operatorUpgrader) outdatedOperators(apps []string) ([]string, error) {
+	var outdated []string
+	for _, app := range apps {
+		current, err := u.facade.OperatorVersion(app)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Annotatef(err, "failed to get operator version for %q", app)
+		}
+		if current != version.Zero && current.Compare(u.version) < 0 {
+			outdated = append(outdated, app)
+		}
+	}
+	return outdated, nil
+}
+
+// upgradeBatch upgrades the operators of the given applications, and
+// waits for them to report the new version.
+func (u *operatorUpgrader) upgradeBatch(apps []string, batch, batches int) error {
+	var upgrading []string
+	for _, app := range apps {
+		u.setStatus(app, status.Maintenance, fmt.Sprintf(
+			"upgrading operator to %v (batch %d of %d)", u.version, batch, batches))
+		if err := u.broker.Upgrade(app, u.version); errors.IsNotFound(err) || errors.IsNotSupported(err) {
+			// The operator has gone away.
+			logger.Debugf("cannot upgrade operator for %q: %v", app, err)
+			continue
+		} else if err != nil {
+			message := fmt.Sprintf("failed to upgrade operator to %v: %v", u.version, err)
+			logger.Errorf("%s for %q", message, app)
+			u.setStatus(app, status.Error, message)
+			continue
+		}
+		u.mu.Lock()
+		u.upgraded.Add(app)
+		u.mu.Unlock()
+		upgrading = append(upgrading, app)
+	}
+
+	timeout := u.clock.After(operatorUpgradeTimeout)
+	for len(upgrading) > 0 {
+		select {
+		case <-u.catacomb.Dying():
+			return u.catacomb.ErrDying()
+		case <-timeout:
+			// Carry on with the next batch; one stuck operator
+			// shouldn't hold back the rest of the model.
+			for _, app := range upgrading {
+				message := fmt.Sprintf("operator not upgraded to %v after %v", u.version, operatorUpgradeTimeout)
+				logger.Warningf("%s for %q", message, app)
+				u.setStatus(app, status.Error, message)
+			}
+			return nil
+		case <-u.clock.After(operatorUpgradePollInterval):
+		}
+		outdated, err := u.outdatedOperators(upgrading)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		pending := make(map[string]bool)
+		for _, app := range outdated {
+			pending[app] = true
+		}
+		for _, app := range upgrading {
+			if !pending[app] {
+				logger.Infof("upgraded operator for %q to %v", app, u.version)
+				u.setStatus(app, status.Active, "")
+			}
+		}
+		upgrading = outdated
+	}
+	return nil
+}
+
+func (u *operatorUpgrader) setStatus(app string, s status.Status, message string) {
+	recordOperatorStatus(u.facade, app, s, message)
+}
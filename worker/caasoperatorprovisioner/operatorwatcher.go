@@ -0,0 +1,66 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasoperatorprovisioner
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/worker.v1"
+	"gopkg.in/juju/worker.v1/catacomb"
+
+	"github.com/juju/juju/core/watcher"
+)
+
+// operatorWatcher forwards the changes to an application's operator,
+// as the application's name, to the provisioner.
+type operatorWatcher struct {
+	catacomb catacomb.Catacomb
+	app      string
+	watcher  watcher.NotifyWatcher
+	out      chan<- string
+}
+
+func newOperatorWatcher(app string, w watcher.NotifyWatcher, out chan<- string) (*operatorWatcher, error) {
+	ow := &operatorWatcher{
+		app:     app,
+		watcher: w,
+		out:     out,
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &ow.catacomb,
+		Work: ow.loop,
+		Init: []worker.Worker{w},
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return ow, nil
+}
+
+// Kill is part of the worker.Worker interface.
+func (ow *operatorWatcher) Kill() {
+	ow.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (ow *operatorWatcher) Wait() error {
+	return ow.catacomb.Wait()
+}
+
+func (ow *operatorWatcher) loop() error {
+	for {
+		select {
+		case <-ow.catacomb.Dying():
+			return ow.catacomb.ErrDying()
+		case _, ok := <-ow.watcher.Changes():
+			if !ok {
+				return errors.Errorf("operator watcher for %q closed channel", ow.app)
+			}
+			select {
+			case <-ow.catacomb.Dying():
+				return ow.catacomb.ErrDying()
+			case ow.out <- ow.app:
+			}
+		}
+	}
+}
@@ -4,6 +4,7 @@
 package caasoperatorprovisioner
 
 import (
+	"sort"
 	"strings"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/juju/loggo"
 	"github.com/juju/retry"
 	"github.com/juju/utils"
+	"github.com/juju/version"
 	"gopkg.in/juju/names.v3"
 	"gopkg.in/juju/worker.v1"
 	"gopkg.in/juju/worker.v1/catacomb"
@@ -21,8 +23,11 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/caas"
 	"github.com/juju/juju/caas/kubernetes/provider"
+	"github.com/juju/juju/cloudconfig/podcfg"
 	"github.com/juju/juju/core/life"
+	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/core/watcher"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/storage"
 )
 
@@ -30,10 +35,14 @@ var logger = loggo.GetLogger("juju.workers.caasprovisioner")
 
 // CAASProvisionerFacade exposes CAAS provisioning functionality to a worker.
 type CAASProvisionerFacade interface {
-	OperatorProvisioningInfo() (apicaasprovisioner.OperatorProvisioningInfo, error)
+	OperatorProvisioningInfo(string) (apicaasprovisioner.OperatorProvisioningInfo, error)
 	WatchApplications() (watcher.StringsWatcher, error)
 	SetPasswords([]apicaasprovisioner.ApplicationPassword) (params.ErrorResults, error)
 	Life(string) (life.Value, error)
+	SetOperatorStatus(appName string, status status.Status, message string, data map[string]interface{}) error
+	WatchForModelConfigChanges() (watcher.NotifyWatcher, error)
+	ModelConfig() (*config.Config, error)
+	OperatorVersion(appName string) (version.Number, error)
 }
 
 // Config defines the operation of a Worker.
@@ -43,16 +52,30 @@ type Config struct {
 	ModelTag    names.ModelTag
 	AgentConfig agent.Config
 	Clock       clock.Clock
+
+	// ForceDeleteOperatorAfter is how long the operator of a removed
+	// application is given to terminate before it is force deleted.
+	// DefaultForceDeleteOperatorAfter is used if it is zero.
+	ForceDeleteOperatorAfter time.Duration
 }
 
 // NewProvisionerWorker starts and returns a new CAAS provisioner worker.
 func NewProvisionerWorker(config Config) (worker.Worker, error) {
+	forceDeleteAfter := config.ForceDeleteOperatorAfter
+	if forceDeleteAfter == 0 {
+		forceDeleteAfter = DefaultForceDeleteOperatorAfter
+	}
 	p := &provisioner{
 		provisionerFacade: config.Facade,
 		broker:            config.Broker,
 		modelTag:          config.ModelTag,
 		agentConfig:       config.AgentConfig,
 		clock:             config.Clock,
+		forceDeleteAfter:  forceDeleteAfter,
+		operatorWatchers:  make(map[string]worker.Worker),
+		operatorChanges:   make(chan string),
+		operatorDeleters:  make(map[string]worker.Worker),
+		operatorsDeleted:  make(chan string),
 	}
 	err := catacomb.Invoke(catacomb.Plan{
 		Site: &p.catacomb,
@@ -67,8 +90,26 @@ type provisioner struct {
 	broker            caas.Broker
 	clock             clock.Clock
 
-	modelTag    names.ModelTag
-	agentConfig agent.Config
+	modelTag         names.ModelTag
+	agentConfig      agent.Config
+	forceDeleteAfter time.Duration
+
+	// operatorWatchers holds a worker for each application whose
+	// operator is being watched; they send the application's name
+	// on operatorChanges when its operator changes.
+	operatorWatchers map[string]worker.Worker
+	operatorChanges  chan string
+
+	// operatorDeleters holds a worker for each removed application
+	// whose operator is being deleted; they send the application's
+	// name on operatorsDeleted when they have finished.
+	operatorDeleters map[string]worker.Worker
+	operatorsDeleted chan string
+
+	// operatorUpgrader rolls out upgrades of the operators to
+	// upgradeVersion, the model's agent version.
+	operatorUpgrader *operatorUpgrader
+	upgradeVersion   version.Number
 }
 
 // Kill is part of the worker.Worker interface.
@@ -82,11 +123,6 @@ func (p *provisioner) Wait() error {
 }
 
 func (p *provisioner) loop() error {
-	// TODO(caas) -  this loop should also keep an eye on kubernetes and ensure
-	// that the operator stays up, redeploying it if the pod goes
-	// away. For some runtimes we *could* rely on the the runtime's
-	// features to do this.
-
 	appWatcher, err := p.provisionerFacade.WatchApplications()
 	if err != nil {
 		return errors.Trace(err)
@@ -95,6 +131,18 @@ func (p *provisioner) loop() error {
 		return errors.Trace(err)
 	}
 
+	// Operators are upgraded by the operator provisioner if the
+	// controller supports it; otherwise each upgrades itself.
+	configWatcher, err := p.provisionerFacade.WatchForModelConfigChanges()
+	if errors.IsNotSupported(err) {
+		logger.Debugf("not rolling out operator upgrades: %v", err)
+	} else if err != nil {
+		return errors.Trace(err)
+	} else if err := p.catacomb.Add(configWatcher); err != nil {
+		return errors.Trace(err)
+	}
+	var configChanges watcher.NotifyChannel
+
 	for {
 		select {
 		case <-p.catacomb.Dying():
@@ -105,19 +153,33 @@ func (p *provisioner) loop() error {
 			if !ok {
 				return errors.New("app watcher closed channel")
 			}
+			// Only look for operators to upgrade once the existing
+			// applications are known.
+			if configWatcher != nil {
+				configChanges = configWatcher.Changes()
+			}
 			var newApps []string
 			for _, app := range apps {
 				appLife, err := p.provisionerFacade.Life(app)
 				if errors.IsNotFound(err) || appLife == life.Dead {
-					logger.Debugf("deleting operator for %q", app)
-					if err := p.broker.DeleteOperator(app); err != nil {
-						return errors.Annotatef(err, "failed to stop operator for %q", app)
+					if err := p.stopWatchingOperator(app); err != nil {
+						return errors.Trace(err)
+					}
+					if err := p.deleteOperator(app); err != nil {
+						return errors.Trace(err)
 					}
 					continue
 				}
 				if appLife != life.Alive {
 					continue
 				}
+				// An application may be deployed again while the
+				// operator of a removed one of the same name is
+				// still being deleted; ensureOperators waits for
+				// that operator to terminate.
+				if err := p.stopDeletingOperator(app); err != nil {
+					return errors.Trace(err)
+				}
 				newApps = append(newApps, app)
 			}
 			if len(newApps) == 0 {
@@ -126,8 +188,180 @@ func (p *provisioner) loop() error {
 			if err := p.ensureOperators(newApps); err != nil {
 				return errors.Trace(err)
 			}
+
+		// An operator has been deleted, or given up on.
+		case app := <-p.operatorsDeleted:
+			delete(p.operatorDeleters, app)
+
+		// The model config changed, so upgrade the operators if the
+		// agent version has changed.
+		case _, ok := <-configChanges:
+			if !ok {
+				return errors.New("model config watcher closed channel")
+			}
+			if err := p.upgradeOperators(); err != nil {
+				return errors.Trace(err)
+			}
+
+		// An operator changed, so make sure it still exists.
+		case app := <-p.operatorChanges:
+			if err := p.operatorChanged(app); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
+
+// operatorChanged redeploys the operator for the specified application
+// if it has gone away while the application is still alive. Problems
+// with a running operator's pod are reported by the unit provisioner.
+func (p *provisioner) operatorChanged(app string) error {
+	opState, err := p.broker.OperatorExists(app)
+	if err != nil {
+		return errors.Annotatef(err, "failed to find operator for %q", app)
+	}
+	if opState.Exists {
+		return nil
+	}
+	appLife, err := p.provisionerFacade.Life(app)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if appLife != life.Alive {
+		return nil
+	}
+	logger.Infof("operator for application %q has gone away, redeploying", app)
+	return errors.Trace(p.ensureOperators([]string{app}))
+}
+
+// watchOperator starts watching the operator of the specified
+// application, if it is not already being watched.
+func (p *provisioner) watchOperator(app string) error {
+	if _, ok := p.operatorWatchers[app]; ok {
+		return nil
+	}
+	w, err := p.broker.WatchOperator(app)
+	if err != nil {
+		return errors.Annotatef(err, "failed to watch operator for %q", app)
+	}
+	ow, err := newOperatorWatcher(app, w, p.operatorChanges)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := p.catacomb.Add(ow); err != nil {
+		return errors.Trace(err)
+	}
+	p.operatorWatchers[app] = ow
+	return nil
+}
+
+// stopWatchingOperator stops watching the operator of the specified
+// application.
+func (p *provisioner) stopWatchingOperator(app string) error {
+	w, ok := p.operatorWatchers[app]
+	if !ok {
+		return nil
+	}
+	delete(p.operatorWatchers, app)
+	return errors.Trace(worker.Stop(w))
+}
+
+// deleteOperator starts deleting the operator of the specified removed
+// application, if it is not already being deleted.
+func (p *provisioner) deleteOperator(app string) error {
+	if _, ok := p.operatorDeleters[app]; ok {
+		return nil
+	}
+	d, err := newOperatorDeleter(app, p.broker, p.provisionerFacade, p.clock, p.forceDeleteAfter, p.operatorsDeleted)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := p.catacomb.Add(d); err != nil {
+		return errors.Trace(err)
+	}
+	p.operatorDeleters[app] = d
+	return nil
+}
+
+// stopDeletingOperator stops deleting the operator of the specified
+// application.
+func (p *provisioner) stopDeletingOperator(app string) error {
+	d, ok := p.operatorDeleters[app]
+	if !ok {
+		return nil
+	}
+	delete(p.operatorDeleters, app)
+	return errors.Trace(worker.Stop(d))
+}
+
+// upgradeOperators starts upgrading the operators of the applications
+// with operators to the model's agent version, if it has changed. Any
+// upgrade to a previous version is abandoned.
+func (p *provisioner) upgradeOperators() error {
+	cfg, err := p.provisionerFacade.ModelConfig()
+	if err != nil {
+		return errors.Annotate(err, "failed to get model config")
+	}
+	vers, ok := cfg.AgentVersion()
+	if !ok || vers == p.upgradeVersion {
+		return nil
+	}
+	if p.operatorUpgrader != nil {
+		if err := worker.Stop(p.operatorUpgrader); err != nil {
+			return errors.Trace(err)
 		}
+		p.operatorUpgrader = nil
+	}
+	var apps []string
+	for app := range p.operatorWatchers {
+		apps = append(apps, app)
+	}
+	sort.Strings(apps)
+	u, err := newOperatorUpgrader(apps, vers, cfg.OperatorUpgradeConcurrency(), p.broker, p.provisionerFacade, p.clock)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := p.catacomb.Add(u); err != nil {
+		return errors.Trace(err)
+	}
+	p.operatorUpgrader = u
+	p.upgradeVersion = vers
+	return nil
+}
+
+// pinOperatorVersion keeps an existing operator at the version it is
+// running if that is older than the version in opConfig, so that it is
+// only upgraded as part of a rolling upgrade.
+func (p *provisioner) pinOperatorVersion(app string, opConfig *caas.OperatorConfig) error {
+	if p.operatorUpgrader != nil && p.operatorUpgrader.started(app) {
+		// The operator may not have reported its new version yet.
+		return nil
+	}
+	current, err := p.provisionerFacade.OperatorVersion(app)
+	if errors.IsNotSupported(err) {
+		return nil
+	} else if err != nil {
+		return errors.Annotatef(err, "failed to get operator version for %q", app)
+	}
+	if current == version.Zero || current.Compare(opConfig.Version) >= 0 {
+		return nil
+	}
+	opConfig.Version = current
+	opConfig.OperatorImagePath = podcfg.RebuildOldOperatorImagePath(opConfig.OperatorImagePath, current)
+	return nil
+}
+
+// setOperatorStatus records the status of an application's operator.
+// Controllers which can't record it are tolerated.
+func (p *provisioner) setOperatorStatus(app string, s status.Status, message string) error {
+	err := p.provisionerFacade.SetOperatorStatus(app, s, message, nil)
+	if errors.IsNotSupported(err) {
+		logger.Debugf("cannot set operator status for %q: %v", app, err)
+		return nil
 	}
+	return errors.Annotatef(err, "failed to set operator status for %q", app)
 }
 
 func (p *provisioner) waitForOperatorTerminated(app string) error {
@@ -159,11 +393,24 @@ func (p *provisioner) waitForOperatorTerminated(app string) error {
 }
 
 // ensureOperators creates operator pods for the specified app names -> api passwords.
+// The charms of applications in sidecar mode run in each of the application's
+// pods instead, so only their agent config is created.
 func (p *provisioner) ensureOperators(apps []string) error {
 	var appPasswords []apicaasprovisioner.ApplicationPassword
 	operatorConfig := make([]*caas.OperatorConfig, len(apps))
+	sidecars := make([]bool, len(apps))
 	for i, app := range apps {
-		opState, err := p.broker.OperatorExists(app)
+		info, err := p.provisionerFacade.OperatorProvisioningInfo(app)
+		if err != nil {
+			return errors.Annotatef(err, "failed to get operator provisioning info for %q", app)
+		}
+		sidecars[i] = caas.ProvisioningMode(info.ProvisioningMode) == caas.ProvisioningModeSidecar
+		var opState caas.OperatorState
+		if sidecars[i] {
+			opState.Exists, err = p.broker.SidecarExists(app)
+		} else {
+			opState, err = p.broker.OperatorExists(app)
+		}
 		if err != nil {
 			return errors.Annotatef(err, "failed to find operator for %q", app)
 		}
@@ -186,10 +433,15 @@ func (p *provisioner) ensureOperators(apps []string) error {
 			appPasswords = append(appPasswords, apicaasprovisioner.ApplicationPassword{Name: app, Password: password})
 		}
 
-		config, err := p.makeOperatorConfig(app, password)
+		config, err := p.makeOperatorConfig(app, password, info)
 		if err != nil {
 			return errors.Annotatef(err, "failed to generate operator config for %q", app)
 		}
+		if opState.Exists && !sidecars[i] {
+			if err := p.pinOperatorVersion(app, config); err != nil {
+				return errors.Trace(err)
+			}
+		}
 		operatorConfig[i] = config
 	}
 	// If we did create any passwords for new operators, first they need
@@ -208,10 +460,25 @@ func (p *provisioner) ensureOperators(apps []string) error {
 	// the operators themselves.
 	var errorStrings []string
 	for i, app := range apps {
-		if err := p.ensureOperator(app, operatorConfig[i]); err != nil {
+		var err error
+		if sidecars[i] {
+			err = p.ensureSidecar(app, operatorConfig[i])
+		} else {
+			err = p.ensureOperator(app, operatorConfig[i])
+		}
+		if err != nil {
 			errorStrings = append(errorStrings, err.Error())
+			if err := p.setOperatorStatus(app, status.Error, err.Error()); err != nil {
+				return errors.Trace(err)
+			}
 			continue
 		}
+		if sidecars[i] {
+			continue
+		}
+		if err := p.watchOperator(app); err != nil {
+			return errors.Trace(err)
+		}
 	}
 	if errorStrings != nil {
 		err := errors.New(strings.Join(errorStrings, "\n"))
@@ -228,29 +495,54 @@ func (p *provisioner) ensureOperator(app string, config *caas.OperatorConfig) er
 	return nil
 }
 
-func (p *provisioner) makeOperatorConfig(appName, password string) (*caas.OperatorConfig, error) {
-	appTag := names.NewApplicationTag(appName)
-	info, err := p.provisionerFacade.OperatorProvisioningInfo()
-	if err != nil {
-		return nil, errors.Trace(err)
+// ensureSidecar creates or updates the agent config used by the charm
+// containers of an application in sidecar mode.
+func (p *provisioner) ensureSidecar(app string, config *caas.OperatorConfig) error {
+	sidecarConfig := &caas.SidecarConfig{
+		AgentConf:    config.AgentConf,
+		ResourceTags: config.ResourceTags,
 	}
+	if err := p.broker.EnsureSidecar(app, sidecarConfig); err != nil {
+		return errors.Annotatef(err, "failed to prepare charm containers for %q", app)
+	}
+	logger.Infof("prepared charm containers for application %q", app)
+	return nil
+}
+
+func (p *provisioner) makeOperatorConfig(
+	appName, password string, info apicaasprovisioner.OperatorProvisioningInfo,
+) (*caas.OperatorConfig, error) {
+	appTag := names.NewApplicationTag(appName)
 	// All operators must have storage configured because charms
 	// have persistent state which must be preserved between any
-	// operator restarts.
-	if info.CharmStorage.Provider != provider.K8s_ProviderType {
+	// operator restarts. Charms in sidecar mode have no operator.
+	sidecar := caas.ProvisioningMode(info.ProvisioningMode) == caas.ProvisioningModeSidecar
+	if !sidecar && info.CharmStorage.Provider != provider.K8s_ProviderType {
 		if spType := info.CharmStorage.Provider; spType == "" {
 			return nil, errors.NotValidf("missing operator storage provider")
 		} else {
 			return nil, errors.NotSupportedf("operator storage provider %q", spType)
 		}
 	}
-	logger.Debugf("using caas operator info %+v", info)
+	loggedInfo := info
+	if loggedInfo.ImagePassword != "" {
+		loggedInfo.ImagePassword = "<redacted>"
+	}
+	logger.Debugf("using caas operator info %+v", loggedInfo)
 
 	cfg := &caas.OperatorConfig{
-		OperatorImagePath: info.ImagePath,
-		Version:           info.Version,
-		ResourceTags:      info.Tags,
-		CharmStorage:      charmStorageParams(info.CharmStorage),
+		OperatorImagePath:     info.ImagePath,
+		OperatorImageUsername: info.ImageUsername,
+		OperatorImagePassword: info.ImagePassword,
+		Version:               info.Version,
+		ResourceTags:          info.Tags,
+		CharmStorage:          charmStorageParams(info.CharmStorage),
+		CPU:                   info.CPU,
+		Memory:                info.Memory,
+		NodeSelector:          info.NodeSelector,
+		NodeAffinity:          info.NodeAffinity,
+		Tolerations:           info.Tolerations,
+		Replicas:              info.Replicas,
 	}
 	// If no password required, we leave the agent conf empty.
 	if password == "" {
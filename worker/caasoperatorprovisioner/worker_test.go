@@ -22,6 +22,7 @@ import (
 	"github.com/juju/juju/agent"
 	apicaasprovisioner "github.com/juju/juju/api/caasoperatorprovisioner"
 	"github.com/juju/juju/caas"
+	"github.com/juju/juju/core/status"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/worker/caasoperatorprovisioner"
 )
@@ -37,6 +38,7 @@ type CAASProvisionerSuite struct {
 	agentConfig       agent.Config
 	clock             *testclock.Clock
 	modelTag          names.ModelTag
+	forceDeleteAfter  time.Duration
 }
 
 func (s *CAASProvisionerSuite) SetUpTest(c *gc.C) {
@@ -44,7 +46,7 @@ func (s *CAASProvisionerSuite) SetUpTest(c *gc.C) {
 
 	s.stub = new(jujutesting.Stub)
 	s.provisionerFacade = newMockProvisionerFacade(s.stub)
-	s.caasClient = &mockBroker{}
+	s.caasClient = &mockBroker{operatorWatcher: newMockNotifyWatcher()}
 	s.agentConfig = &mockAgentConfig{}
 	s.modelTag = coretesting.ModelTag
 	s.clock = testclock.NewClock(time.Now())
@@ -72,10 +74,13 @@ func (s *CAASProvisionerSuite) assertWorker(c *gc.C) worker.Worker {
 		ModelTag:    s.modelTag,
 		AgentConfig: s.agentConfig,
 		Clock:       s.clock,
+
+		ForceDeleteOperatorAfter: s.forceDeleteAfter,
 	})
 	c.Assert(err, jc.ErrorIsNil)
 	expected := []jujutesting.StubCall{
 		{"WatchApplications", nil},
+		{"WatchForModelConfigChanges", nil},
 	}
 	s.waitForWorkerStubCalls(c, expected)
 	s.stub.ResetCalls()
@@ -92,9 +97,9 @@ func (s *CAASProvisionerSuite) assertOperatorCreated(c *gc.C, exists, terminatin
 	s.provisionerFacade.life = "alive"
 	s.provisionerFacade.applicationsWatcher.changes <- []string{"myapp"}
 
-	expectedCalls := 2
+	expectedCalls := 3
 	if terminating {
-		expectedCalls = 4
+		expectedCalls = 5
 	}
 	for a := coretesting.LongAttempt.Start(); a.Next(); {
 		nrCalls := len(s.caasClient.Calls())
@@ -107,9 +112,9 @@ func (s *CAASProvisionerSuite) assertOperatorCreated(c *gc.C, exists, terminatin
 			s.clock.Advance(4 * time.Second)
 		}
 	}
-	callNames := []string{"OperatorExists", "EnsureOperator"}
+	callNames := []string{"OperatorExists", "EnsureOperator", "WatchOperator"}
 	if terminating {
-		callNames = []string{"OperatorExists", "OperatorExists", "OperatorExists", "EnsureOperator"}
+		callNames = []string{"OperatorExists", "OperatorExists", "OperatorExists", "EnsureOperator", "WatchOperator"}
 	}
 	s.caasClient.CheckCallNames(c, callNames...)
 	c.Assert(s.caasClient.Calls(), gc.HasLen, expectedCalls)
@@ -129,8 +134,16 @@ func (s *CAASProvisionerSuite) assertOperatorCreated(c *gc.C, exists, terminatin
 	c.Assert(args[2], gc.FitsTypeOf, &caas.OperatorConfig{})
 	config := args[2].(*caas.OperatorConfig)
 	c.Assert(config.OperatorImagePath, gc.Equals, "juju-operator-image")
+	c.Assert(config.OperatorImageUsername, gc.Equals, "fred")
+	c.Assert(config.OperatorImagePassword, gc.Equals, "secret")
 	c.Assert(config.Version, gc.Equals, version.MustParse("2.99.0"))
 	c.Assert(config.ResourceTags, jc.DeepEquals, map[string]string{"fred": "mary"})
+	c.Assert(config.CPU, gc.Equals, "500m")
+	c.Assert(config.Memory, gc.Equals, "256Mi")
+	c.Assert(config.NodeSelector, jc.DeepEquals, []string{"pool=controllers"})
+	c.Assert(config.NodeAffinity, jc.DeepEquals, []string{"zone=a|b"})
+	c.Assert(config.Tolerations, jc.DeepEquals, []string{"dedicated=juju:NoSchedule"})
+	c.Assert(config.Replicas, gc.Equals, 3)
 	c.Assert(config.CharmStorage, jc.DeepEquals, caas.CharmStorageParams{
 		Provider:     "kubernetes",
 		Size:         uint64(1024),
@@ -159,13 +172,15 @@ func (s *CAASProvisionerSuite) assertOperatorCreated(c *gc.C, exists, terminatin
 	}
 
 	if exists && !terminating {
-		s.provisionerFacade.stub.CheckCallNames(c, "Life", "OperatorProvisioningInfo")
+		s.provisionerFacade.stub.CheckCallNames(c, "Life", "OperatorProvisioningInfo", "OperatorVersion")
 		c.Assert(s.provisionerFacade.stub.Calls()[0].Args[0], gc.Equals, "myapp")
+		c.Assert(s.provisionerFacade.stub.Calls()[1].Args[0], gc.Equals, "myapp")
 		return
 	}
 
 	s.provisionerFacade.stub.CheckCallNames(c, "Life", "OperatorProvisioningInfo", "SetPasswords")
 	c.Assert(s.provisionerFacade.stub.Calls()[0].Args[0], gc.Equals, "myapp")
+	c.Assert(s.provisionerFacade.stub.Calls()[1].Args[0], gc.Equals, "myapp")
 	passwords := s.provisionerFacade.stub.Calls()[2].Args[0].([]apicaasprovisioner.ApplicationPassword)
 
 	c.Assert(passwords, gc.HasLen, 1)
@@ -196,6 +211,35 @@ func (s *CAASProvisionerSuite) TestNewApplicationWaitsOperatorTerminated(c *gc.C
 	s.assertOperatorCreated(c, true, true)
 }
 
+func (s *CAASProvisionerSuite) TestNewSidecarApplicationCreatesAgentConfig(c *gc.C) {
+	s.provisionerFacade.provisioningMode = "sidecar"
+	w := s.assertWorker(c)
+	defer workertest.CleanKill(c, w)
+
+	s.provisionerFacade.life = "alive"
+	s.provisionerFacade.applicationsWatcher.changes <- []string{"myapp"}
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		if len(s.caasClient.Calls()) >= 2 {
+			break
+		}
+	}
+	s.caasClient.CheckCallNames(c, "SidecarExists", "EnsureSidecar")
+	args := s.caasClient.Calls()[1].Args
+	c.Assert(args, gc.HasLen, 2)
+	c.Assert(args[0], gc.Equals, "myapp")
+	config := args[1].(*caas.SidecarConfig)
+	c.Assert(config.ResourceTags, jc.DeepEquals, map[string]string{"fred": "mary"})
+
+	agentFile := filepath.Join(c.MkDir(), "agent.config")
+	err := ioutil.WriteFile(agentFile, config.AgentConf, 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	cfg, err := agent.ReadConfig(agentFile)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.Tag(), gc.Equals, names.NewApplicationTag("myapp"))
+
+	s.provisionerFacade.stub.CheckCallNames(c, "Life", "OperatorProvisioningInfo", "SetPasswords")
+}
+
 func (s *CAASProvisionerSuite) TestApplicationDeletedRemovesOperator(c *gc.C) {
 	w := s.assertWorker(c)
 	defer workertest.CleanKill(c, w)
@@ -207,10 +251,233 @@ func (s *CAASProvisionerSuite) TestApplicationDeletedRemovesOperator(c *gc.C) {
 	s.provisionerFacade.applicationsWatcher.changes <- []string{"myapp"}
 
 	for a := coretesting.LongAttempt.Start(); a.Next(); {
-		if len(s.caasClient.Calls()) > 0 {
+		if len(s.caasClient.Calls()) >= 2 {
 			break
 		}
 	}
-	s.caasClient.CheckCallNames(c, "DeleteOperator")
+	s.caasClient.CheckCallNames(c, "DeleteOperator", "OperatorExists")
 	c.Assert(s.caasClient.Calls()[0].Args[0], gc.Equals, "myapp")
 }
+
+func (s *CAASProvisionerSuite) deleteApplication(c *gc.C) {
+	s.caasClient.setOperatorExists(true)
+	s.caasClient.setTerminating(true)
+	s.provisionerFacade.stub.SetErrors(errors.NotFoundf("myapp"))
+	s.provisionerFacade.life = "dead"
+	s.provisionerFacade.applicationsWatcher.changes <- []string{"myapp"}
+
+	waitForStubCalls(c, s.provisionerFacade.stub, []jujutesting.StubCall{
+		{"Life", []interface{}{"myapp"}},
+		{"SetOperatorStatus", []interface{}{
+			"myapp", status.Maintenance, "waiting for operator to terminate (0s)", map[string]interface{}(nil)}},
+	})
+}
+
+func (s *CAASProvisionerSuite) TestApplicationDeletedWaitsOperatorTerminated(c *gc.C) {
+	w := s.assertWorker(c)
+	defer workertest.CleanKill(c, w)
+
+	s.deleteApplication(c)
+	s.caasClient.setOperatorExists(false)
+	s.caasClient.setTerminating(false)
+	err := s.clock.WaitAdvance(time.Second, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		if len(s.caasClient.Calls()) >= 3 {
+			break
+		}
+	}
+	s.caasClient.CheckCallNames(c, "DeleteOperator", "OperatorExists", "OperatorExists")
+	s.provisionerFacade.stub.CheckCallNames(c, "Life", "SetOperatorStatus")
+}
+
+func (s *CAASProvisionerSuite) TestApplicationDeletedForceDeletesStuckOperator(c *gc.C) {
+	s.forceDeleteAfter = 10 * time.Second
+	w := s.assertWorker(c)
+	defer workertest.CleanKill(c, w)
+
+	s.deleteApplication(c)
+	for _, d := range []time.Duration{1, 2, 4, 8} {
+		err := s.clock.WaitAdvance(d*time.Second, coretesting.LongWait, 1)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	// The operator is force deleted once it has been terminating for
+	// 15s, and is checked again 16s later.
+	err := s.clock.WaitAdvance(0, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	s.caasClient.setOperatorExists(false)
+	s.caasClient.setTerminating(false)
+	err = s.clock.WaitAdvance(16*time.Second, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		if len(s.caasClient.Calls()) >= 8 {
+			break
+		}
+	}
+	s.caasClient.CheckCallNames(c,
+		"DeleteOperator",
+		"OperatorExists", "OperatorExists", "OperatorExists", "OperatorExists", "OperatorExists",
+		"ForceDeleteOperator",
+		"OperatorExists",
+	)
+	s.provisionerFacade.stub.CheckCall(c, 5, "SetOperatorStatus",
+		"myapp", status.Maintenance, "force deleting operator, still terminating after 10s", map[string]interface{}(nil))
+}
+
+func (s *CAASProvisionerSuite) TestApplicationDeletedStopsWatchingOperator(c *gc.C) {
+	w := s.assertWorker(c)
+	defer workertest.CleanKill(c, w)
+
+	s.assertOperatorCreated(c, false, false)
+	s.provisionerFacade.stub.SetErrors(errors.NotFoundf("myapp"))
+	s.provisionerFacade.life = "dead"
+	s.provisionerFacade.applicationsWatcher.changes <- []string{"myapp"}
+
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		if s.caasClient.operatorWatcher.killed() {
+			return
+		}
+	}
+	c.Fatal("operator watcher not stopped")
+}
+
+func (s *CAASProvisionerSuite) TestOperatorGoneRedeploysOperator(c *gc.C) {
+	w := s.assertWorker(c)
+	defer workertest.CleanKill(c, w)
+
+	s.assertOperatorCreated(c, false, false)
+	s.caasClient.ResetCalls()
+	s.provisionerFacade.stub.ResetCalls()
+	s.caasClient.setOperatorExists(false)
+	s.caasClient.operatorWatcher.changes <- struct{}{}
+
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		if len(s.caasClient.Calls()) >= 3 {
+			break
+		}
+	}
+	s.caasClient.CheckCallNames(c, "OperatorExists", "OperatorExists", "EnsureOperator")
+	c.Assert(s.caasClient.Calls()[2].Args[0], gc.Equals, "myapp")
+	s.provisionerFacade.stub.CheckCallNames(c, "Life", "OperatorProvisioningInfo", "SetPasswords")
+}
+
+func (s *CAASProvisionerSuite) TestOperatorChangedStillExists(c *gc.C) {
+	w := s.assertWorker(c)
+	defer workertest.CleanKill(c, w)
+
+	s.assertOperatorCreated(c, false, false)
+	s.caasClient.ResetCalls()
+	s.provisionerFacade.stub.ResetCalls()
+	s.caasClient.setOperatorExists(true)
+	s.caasClient.operatorWatcher.changes <- struct{}{}
+
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		if len(s.caasClient.Calls()) >= 1 {
+			break
+		}
+	}
+	workertest.CleanKill(c, w)
+	s.caasClient.CheckCallNames(c, "OperatorExists")
+	s.provisionerFacade.stub.CheckNoCalls(c)
+}
+
+func (s *CAASProvisionerSuite) TestEnsureOperatorFailureSetsStatus(c *gc.C) {
+	w := s.assertWorker(c)
+	defer workertest.DirtyKill(c, w)
+
+	s.caasClient.SetErrors(nil, errors.New("boom"))
+	s.provisionerFacade.life = "alive"
+	s.provisionerFacade.applicationsWatcher.changes <- []string{"myapp"}
+
+	err := workertest.CheckKilled(c, w)
+	c.Assert(err, gc.ErrorMatches, "failed to provision all operators: failed to start operator for \"myapp\": boom")
+	s.caasClient.CheckCallNames(c, "OperatorExists", "EnsureOperator")
+	s.provisionerFacade.stub.CheckCallNames(c, "Life", "OperatorProvisioningInfo", "SetPasswords", "SetOperatorStatus")
+	s.provisionerFacade.stub.CheckCall(c, 3, "SetOperatorStatus",
+		"myapp", status.Error, "failed to start operator for \"myapp\": boom", map[string]interface{}(nil))
+}
+
+func (s *CAASProvisionerSuite) waitForCallNames(c *gc.C, stub *jujutesting.Stub, n int) []string {
+	var callNames []string
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		callNames = callNames[:0]
+		for _, call := range stub.Calls() {
+			callNames = append(callNames, call.FuncName)
+		}
+		if len(callNames) >= n {
+			return callNames
+		}
+	}
+	c.Fatalf("expected %d calls, saw: %v", n, callNames)
+	return nil
+}
+
+func (s *CAASProvisionerSuite) TestAgentVersionChangeUpgradesOperatorsInBatches(c *gc.C) {
+	s.provisionerFacade.modelAttrs = coretesting.Attrs{
+		"agent-version":                "2.99.0",
+		"operator-upgrade-concurrency": 1,
+	}
+	w := s.assertWorker(c)
+	defer workertest.CleanKill(c, w)
+
+	s.provisionerFacade.life = "alive"
+	s.provisionerFacade.applicationsWatcher.changes <- []string{"app1", "app2"}
+	s.waitForCallNames(c, &s.caasClient.Stub, 6)
+	s.caasClient.ResetCalls()
+	s.provisionerFacade.stub.ResetCalls()
+
+	oldVersion := version.MustParse("2.98.0")
+	newVersion := version.MustParse("2.99.0")
+	s.provisionerFacade.setOperatorVersion("app1", oldVersion)
+	s.provisionerFacade.setOperatorVersion("app2", oldVersion)
+	s.provisionerFacade.configWatcher.changes <- struct{}{}
+
+	// Only the first batch is upgraded until it reports the new version.
+	s.waitForCallNames(c, s.provisionerFacade.stub, 5)
+	s.provisionerFacade.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"ModelConfig", nil},
+		{"OperatorVersion", []interface{}{"app1"}},
+		{"OperatorVersion", []interface{}{"app2"}},
+		{"SetOperatorStatus", []interface{}{
+			"app2", status.Waiting, "waiting to upgrade operator to 2.99.0 (batch 2 of 2)", map[string]interface{}(nil)}},
+		{"SetOperatorStatus", []interface{}{
+			"app1", status.Maintenance, "upgrading operator to 2.99.0 (batch 1 of 2)", map[string]interface{}(nil)}},
+	})
+	s.waitForCallNames(c, &s.caasClient.Stub, 1)
+	s.caasClient.CheckCalls(c, []jujutesting.StubCall{
+		{"Upgrade", []interface{}{"app1", newVersion}},
+	})
+
+	s.provisionerFacade.stub.ResetCalls()
+	s.provisionerFacade.setOperatorVersion("app1", newVersion)
+	err := s.clock.WaitAdvance(5*time.Second, coretesting.LongWait, 2)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.waitForCallNames(c, &s.caasClient.Stub, 2)
+	s.caasClient.CheckCall(c, 1, "Upgrade", "app2", newVersion)
+	s.waitForCallNames(c, s.provisionerFacade.stub, 3)
+	s.provisionerFacade.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"OperatorVersion", []interface{}{"app1"}},
+		{"SetOperatorStatus", []interface{}{"app1", status.Active, "", map[string]interface{}(nil)}},
+		{"SetOperatorStatus", []interface{}{
+			"app2", status.Maintenance, "upgrading operator to 2.99.0 (batch 2 of 2)", map[string]interface{}(nil)}},
+	})
+}
+
+func (s *CAASProvisionerSuite) TestExistingOperatorKeepsVersionUntilUpgraded(c *gc.C) {
+	s.caasClient.operatorExists = true
+	s.provisionerFacade.setOperatorVersion("myapp", version.MustParse("2.98.0"))
+	w := s.assertWorker(c)
+	defer workertest.CleanKill(c, w)
+
+	s.provisionerFacade.life = "alive"
+	s.provisionerFacade.applicationsWatcher.changes <- []string{"myapp"}
+	s.waitForCallNames(c, &s.caasClient.Stub, 3)
+	s.caasClient.CheckCallNames(c, "OperatorExists", "EnsureOperator", "WatchOperator")
+
+	config := s.caasClient.Calls()[1].Args[2].(*caas.OperatorConfig)
+	c.Assert(config.Version, gc.Equals, version.MustParse("2.98.0"))
+	c.Assert(config.OperatorImagePath, gc.Equals, "juju-operator-image:2.98.0")
+}
@@ -108,6 +108,7 @@ func (aw *applicationWorker) loop() error {
 	// so we only report true changes.
 	lastReportedStatus := make(map[string]status.StatusInfo)
 	lastReportedScale := -1
+	var lastOperatorStatus *status.StatusInfo
 
 	for {
 		// The caas watcher can just die from underneath so recreate if needed.
@@ -218,23 +219,36 @@ func (aw *applicationWorker) loop() error {
 				continue
 			}
 			logger.Debugf("operator update for %v", aw.application)
+			var operatorStatus status.StatusInfo
 			operator, err := aw.containerBroker.Operator(aw.application)
 			if errors.IsNotFound(err) {
 				logger.Debugf("pod not found for application %q", aw.application)
-				if err := aw.provisioningStatusSetter.SetOperatorStatus(aw.application, status.Terminated, "", nil); err != nil {
-					return errors.Trace(err)
-				}
+				operatorStatus = status.StatusInfo{Status: status.Terminated}
 			} else if err != nil {
 				return errors.Trace(err)
 			} else {
-				if err := aw.provisioningStatusSetter.SetOperatorStatus(aw.application, operator.Status.Status, operator.Status.Message, operator.Status.Data); err != nil {
-					return errors.Trace(err)
-				}
+				operatorStatus = operator.Status
+			}
+			// The operator watcher also fires for each event recorded
+			// against the operator pod, most of which don't change its
+			// status, so only report what has actually changed.
+			if lastOperatorStatus != nil && sameOperatorStatus(*lastOperatorStatus, operatorStatus) {
+				continue
+			}
+			if err := aw.provisioningStatusSetter.SetOperatorStatus(aw.application, operatorStatus.Status, operatorStatus.Message, operatorStatus.Data); err != nil {
+				return errors.Trace(err)
 			}
+			lastOperatorStatus = &operatorStatus
 		}
 	}
 }
 
+// sameOperatorStatus returns true if the operator statuses only differ
+// in when they were set.
+func sameOperatorStatus(a, b status.StatusInfo) bool {
+	return a.Status == b.Status && a.Message == b.Message && reflect.DeepEqual(a.Data, b.Data)
+}
+
 func (aw *applicationWorker) clusterChanged(
 	service *caas.Service,
 	lastReportedStatus map[string]status.StatusInfo,
@@ -9,10 +9,12 @@ import (
 	"gopkg.in/juju/worker.v1"
 	"gopkg.in/juju/worker.v1/catacomb"
 
+	apicaasunitprovisioner "github.com/juju/juju/api/caasunitprovisioner"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/caas"
 	k8sprovider "github.com/juju/juju/caas/kubernetes/provider"
 	k8sspecs "github.com/juju/juju/caas/kubernetes/provider/specs"
+	"github.com/juju/juju/caas/specs"
 	"github.com/juju/juju/core/watcher"
 )
 
@@ -162,6 +164,7 @@ func (w *deploymentWorker) loop() error {
 				DeploymentType: caas.DeploymentType(info.DeploymentInfo.DeploymentType),
 				ServiceType:    caas.ServiceType(info.DeploymentInfo.ServiceType),
 			},
+			Sidecar: sidecarParams(info.Sidecar),
 		}
 		err = w.broker.EnsureService(w.application, w.provisioningStatusSetter.SetOperatorStatus, serviceParams, desiredScale, appConfig)
 		if err != nil {
@@ -188,6 +191,29 @@ func (w *deploymentWorker) loop() error {
 	}
 }
 
+// sidecarParams returns the charm container to run in each pod of an
+// application in sidecar mode, or nil if the charm runs in an operator.
+func sidecarParams(info *apicaasunitprovisioner.SidecarInfo) *caas.SidecarParams {
+	if info == nil {
+		return nil
+	}
+	result := &caas.SidecarParams{
+		ImageDetails: specs.ImageDetails{
+			ImagePath: info.ImagePath,
+			Username:  info.ImageUsername,
+			Password:  info.ImagePassword,
+		},
+	}
+	for _, m := range info.Mounts {
+		result.Mounts = append(result.Mounts, caas.SidecarMount{
+			StorageName: m.StorageName,
+			MountPoint:  m.MountPoint,
+			ReadOnly:    m.ReadOnly,
+		})
+	}
+	return result
+}
+
 func updateApplicationService(appTag names.ApplicationTag, svc *caas.Service, updater ApplicationUpdater) error {
 	if svc == nil || svc.Id == "" {
 		return nil
@@ -464,6 +464,66 @@ containers:
 		"gitlab", expectedParams, 1, application.ConfigAttributes{"juju-external-hostname": "exthost"})
 }
 
+func (s *WorkerSuite) TestNewPodSpecChangeSidecar(c *gc.C) {
+	w := s.setupNewUnitScenario(c)
+	defer workertest.CleanKill(c, w)
+
+	s.serviceBroker.ResetCalls()
+
+	anotherSpec := `
+containers:
+  - name: gitlab
+    image: gitlab/latest
+`[1:]
+	anotherParsedSpec := &specs.PodSpec{}
+	anotherParsedSpec.Version = specs.CurrentVersion
+	anotherParsedSpec.Containers = []specs.ContainerSpec{{
+		Name:  "gitlab",
+		Image: "gitlab/latest",
+	}}
+
+	s.podSpecGetter.setProvisioningInfo(apicaasunitprovisioner.ProvisioningInfo{
+		PodSpec: anotherSpec,
+		Tags:    map[string]string{"foo": "bar"},
+		Sidecar: &apicaasunitprovisioner.SidecarInfo{
+			ImagePath:     "jujusolutions/jujud-operator:2.6.1",
+			ImageUsername: "fred",
+			ImagePassword: "secret",
+			Mounts: []apicaasunitprovisioner.SidecarMount{{
+				StorageName: "database",
+				MountPoint:  "/var/lib/juju/storage/database/0",
+			}},
+		},
+	})
+	s.sendContainerSpecChange(c)
+	s.podSpecGetter.assertSpecRetrieved(c)
+
+	select {
+	case <-s.serviceEnsured:
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for service to be ensured")
+	}
+
+	expectedParams := &caas.ServiceParams{
+		PodSpec:      anotherParsedSpec,
+		ResourceTags: map[string]string{"foo": "bar"},
+		Sidecar: &caas.SidecarParams{
+			ImageDetails: specs.ImageDetails{
+				ImagePath: "jujusolutions/jujud-operator:2.6.1",
+				Username:  "fred",
+				Password:  "secret",
+			},
+			Mounts: []caas.SidecarMount{{
+				StorageName: "database",
+				MountPoint:  "/var/lib/juju/storage/database/0",
+			}},
+		},
+	}
+	s.serviceBroker.CheckCallNames(c, "EnsureService")
+	s.serviceBroker.CheckCall(c, 0, "EnsureService",
+		"gitlab", expectedParams, 1, application.ConfigAttributes{"juju-external-hostname": "exthost"})
+}
+
 func (s *WorkerSuite) TestScaleZero(c *gc.C) {
 	w := s.setupNewUnitScenario(c)
 	defer workertest.CleanKill(c, w)
@@ -695,6 +755,58 @@ func (s *WorkerSuite) TestOperatorChange(c *gc.C) {
 	})
 }
 
+func (s *WorkerSuite) TestOperatorChangeSameStatusNotReported(c *gc.C) {
+	w, err := caasunitprovisioner.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, w)
+
+	select {
+	case s.applicationChanges <- []string{"gitlab"}:
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out sending applications change")
+	}
+
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		if len(s.containerBroker.Calls()) >= 2 {
+			break
+		}
+	}
+	s.containerBroker.CheckCallNames(c, "WatchUnits", "WatchOperator")
+	s.containerBroker.ResetCalls()
+
+	sendOperatorChange := func(operatorStatus status.Status, expectedCalls int) {
+		s.containerBroker.reportedOperatorStatus = operatorStatus
+		select {
+		case s.caasOperatorChanges <- struct{}{}:
+		case <-time.After(coretesting.LongWait):
+			c.Fatal("timed out sending operator change")
+		}
+		for a := coretesting.LongAttempt.Start(); a.Next(); {
+			if len(s.containerBroker.Calls()) >= expectedCalls {
+				break
+			}
+		}
+	}
+	// The second change has the same status, so isn't reported.
+	sendOperatorChange(status.Active, 1)
+	sendOperatorChange(status.Active, 2)
+	sendOperatorChange(status.Error, 3)
+	s.containerBroker.CheckCallNames(c, "Operator", "Operator", "Operator")
+
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		if len(s.statusSetter.Calls()) >= 2 {
+			break
+		}
+	}
+	s.statusSetter.CheckCallNames(c, "SetOperatorStatus", "SetOperatorStatus")
+	c.Assert(s.statusSetter.Calls()[0].Args, jc.DeepEquals, []interface{}{
+		"gitlab", status.Active, "testing 1. 2. 3.", map[string]interface{}{"zip": "zap"},
+	})
+	c.Assert(s.statusSetter.Calls()[1].Args, jc.DeepEquals, []interface{}{
+		"gitlab", status.Error, "testing 1. 2. 3.", map[string]interface{}{"zip": "zap"},
+	})
+}
+
 func (s *WorkerSuite) assertUnitChange(c *gc.C, reported, expectedUnitStatus status.Status) {
 	s.containerBroker.ResetCalls()
 	s.unitUpdater.ResetCalls()
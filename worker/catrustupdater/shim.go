@@ -0,0 +1,38 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package catrustupdater
+
+import (
+	"os/exec"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/api/base"
+	apicatrustupdater "github.com/juju/juju/api/catrustupdater"
+)
+
+// NewFacade returns a Facade backed by the CATrustUpdater API facade.
+func NewFacade(apiCaller base.APICaller) (Facade, error) {
+	return apicatrustupdater.NewFacade(apiCaller), nil
+}
+
+// NewWorker returns a catrustupdater worker.
+func NewWorker(config Config) (worker.Worker, error) {
+	worker, err := New(config)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return worker, nil
+}
+
+// UpdateCACertificates rebuilds the system trust store with
+// update-ca-certificates.
+func UpdateCACertificates() error {
+	out, err := exec.Command("update-ca-certificates").CombinedOutput()
+	if err != nil {
+		return errors.Annotatef(err, "update-ca-certificates failed: %s", out)
+	}
+	return nil
+}
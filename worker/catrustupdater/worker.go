@@ -0,0 +1,217 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package catrustupdater
+
+import (
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"gopkg.in/juju/worker.v1"
+	"gopkg.in/juju/worker.v1/catacomb"
+
+	"github.com/juju/juju/core/watcher"
+)
+
+var logger = loggo.GetLogger("juju.worker.catrustupdater")
+
+// TrustDir is the directory, relative to the root directory, holding
+// the CA certificates that the worker adds to the system trust store.
+const TrustDir = "usr/local/share/ca-certificates/juju"
+
+// Facade exposes controller functionality to a Worker.
+type Facade interface {
+	WatchForCATrustBundleChanges() (watcher.NotifyWatcher, error)
+	CATrustBundle() (string, error)
+}
+
+// Config defines the parameters of the catrustupdater worker.
+type Config struct {
+	Facade  Facade
+	RootDir string
+
+	// UpdateTrustStore is called to rebuild the system trust store
+	// after the certificates in TrustDir have changed.
+	UpdateTrustStore func() error
+}
+
+// Validate returns an error if Config cannot drive a catrustupdater.
+func (config Config) Validate() error {
+	if config.Facade == nil {
+		return errors.NotValidf("nil Facade")
+	}
+	if config.UpdateTrustStore == nil {
+		return errors.NotValidf("nil UpdateTrustStore")
+	}
+	return nil
+}
+
+// New returns a Worker that keeps the certificates in the system trust
+// store in line with the CA trust bundle of the model.
+func New(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &catrustupdater{config: config}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+type catrustupdater struct {
+	catacomb catacomb.Catacomb
+	config   Config
+}
+
+// Kill implements worker.Worker.
+func (w *catrustupdater) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait implements worker.Worker.
+func (w *catrustupdater) Wait() error {
+	return w.catacomb.Wait()
+}
+
+func (w *catrustupdater) loop() error {
+	// Install the current bundle right away; the watcher does not
+	// send an initial event.
+	if err := w.update(); err != nil {
+		return errors.Trace(err)
+	}
+	bundleWatcher, err := w.config.Facade.WatchForCATrustBundleChanges()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := w.catacomb.Add(bundleWatcher); err != nil {
+		return errors.Trace(err)
+	}
+
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case _, ok := <-bundleWatcher.Changes():
+			if !ok {
+				return errors.New("CA trust bundle watcher closed")
+			}
+			if err := w.update(); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
+
+// update replaces the certificates in the trust directory with those
+// in the current bundle, and rebuilds the trust store if they changed.
+func (w *catrustupdater) update() error {
+	bundle, err := w.config.Facade.CATrustBundle()
+	if err != nil {
+		return errors.Annotate(err, "cannot get CA trust bundle")
+	}
+	wanted, err := splitBundle(bundle)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	dir := filepath.Join(w.config.RootDir, TrustDir)
+	current, err := readTrustDir(dir)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if sameFiles(current, wanted) {
+		logger.Debugf("CA trust bundle unchanged")
+		return nil
+	}
+
+	logger.Infof("installing %d CA certificate(s) in %s", len(wanted), dir)
+	if len(wanted) > 0 {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	for name := range current {
+		if _, ok := wanted[name]; ok {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	for name, content := range wanted {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if len(wanted) == 0 {
+		if err := os.Remove(dir); err != nil && !os.IsNotExist(err) {
+			return errors.Trace(err)
+		}
+	}
+	return errors.Annotate(w.config.UpdateTrustStore(), "cannot update trust store")
+}
+
+// splitBundle returns the certificates in the bundle, keyed on the
+// names of the files that hold them.
+func splitBundle(bundle string) (map[string]string, error) {
+	certs := make(map[string]string)
+	rest := []byte(bundle)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return nil, errors.Errorf("unexpected PEM block %q in CA trust bundle", block.Type)
+		}
+		name := fmt.Sprintf("juju-ca-%d.crt", len(certs))
+		certs[name] = string(pem.EncodeToMemory(block))
+	}
+	return certs, nil
+}
+
+// readTrustDir returns the contents of the certificate files in dir,
+// keyed on their names.
+func readTrustDir(dir string) (map[string]string, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	files := make(map[string]string)
+	for _, info := range infos {
+		if info.IsDir() || !strings.HasSuffix(info.Name(), ".crt") {
+			continue
+		}
+		content, err := ioutil.ReadFile(filepath.Join(dir, info.Name()))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		files[info.Name()] = string(content)
+	}
+	return files, nil
+}
+
+func sameFiles(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, content := range a {
+		if other, ok := b[name]; !ok || other != content {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,164 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package catrustupdater_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/worker.v1"
+	"gopkg.in/juju/worker.v1/workertest"
+
+	"github.com/juju/juju/core/watcher"
+	"github.com/juju/juju/core/watcher/watchertest"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/catrustupdater"
+)
+
+type WorkerSuite struct {
+	jujutesting.IsolationSuite
+
+	dir     string
+	facade  *fakeFacade
+	updates chan struct{}
+	config  catrustupdater.Config
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.dir = c.MkDir()
+	s.facade = &fakeFacade{changes: make(chan struct{})}
+	s.updates = make(chan struct{}, 10)
+	s.config = catrustupdater.Config{
+		Facade:  s.facade,
+		RootDir: s.dir,
+		UpdateTrustStore: func() error {
+			s.updates <- struct{}{}
+			return nil
+		},
+	}
+}
+
+func (s *WorkerSuite) startWorker(c *gc.C) worker.Worker {
+	w, err := catrustupdater.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(c *gc.C) { workertest.CleanKill(c, w) })
+	return w
+}
+
+func (s *WorkerSuite) waitForUpdate(c *gc.C) {
+	select {
+	case <-s.updates:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for trust store update")
+	}
+}
+
+func (s *WorkerSuite) sendChange(c *gc.C) {
+	select {
+	case s.facade.changes <- struct{}{}:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out sending change")
+	}
+}
+
+func (s *WorkerSuite) trustDirContents(c *gc.C) map[string]string {
+	dir := filepath.Join(s.dir, catrustupdater.TrustDir)
+	infos, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	c.Assert(err, jc.ErrorIsNil)
+	files := make(map[string]string)
+	for _, info := range infos {
+		content, err := ioutil.ReadFile(filepath.Join(dir, info.Name()))
+		c.Assert(err, jc.ErrorIsNil)
+		files[info.Name()] = string(content)
+	}
+	return files
+}
+
+func (s *WorkerSuite) TestInvalidConfig(c *gc.C) {
+	s.config.UpdateTrustStore = nil
+	_, err := catrustupdater.New(s.config)
+	c.Assert(err, gc.ErrorMatches, "nil UpdateTrustStore not valid")
+}
+
+func (s *WorkerSuite) TestInstallsBundle(c *gc.C) {
+	s.facade.setBundle(coretesting.CACert + coretesting.OtherCACert)
+	s.startWorker(c)
+	s.waitForUpdate(c)
+
+	c.Assert(s.trustDirContents(c), jc.DeepEquals, map[string]string{
+		"juju-ca-0.crt": coretesting.CACert,
+		"juju-ca-1.crt": coretesting.OtherCACert,
+	})
+}
+
+func (s *WorkerSuite) TestRotatesBundle(c *gc.C) {
+	s.facade.setBundle(coretesting.CACert + coretesting.OtherCACert)
+	s.startWorker(c)
+	s.waitForUpdate(c)
+
+	s.facade.setBundle(coretesting.OtherCACert)
+	s.sendChange(c)
+	s.waitForUpdate(c)
+	c.Assert(s.trustDirContents(c), jc.DeepEquals, map[string]string{
+		"juju-ca-0.crt": coretesting.OtherCACert,
+	})
+
+	s.facade.setBundle("")
+	s.sendChange(c)
+	s.waitForUpdate(c)
+	c.Assert(s.trustDirContents(c), gc.HasLen, 0)
+}
+
+func (s *WorkerSuite) TestUnchangedBundle(c *gc.C) {
+	s.startWorker(c)
+
+	// An unchanged bundle does not update the trust store, so the
+	// only update seen is for the bundle set later.
+	s.sendChange(c)
+	s.facade.setBundle(coretesting.CACert)
+	s.sendChange(c)
+	s.waitForUpdate(c)
+	select {
+	case <-s.updates:
+		c.Fatalf("unexpected trust store update")
+	case <-time.After(coretesting.ShortWait):
+	}
+	c.Assert(s.trustDirContents(c), jc.DeepEquals, map[string]string{
+		"juju-ca-0.crt": coretesting.CACert,
+	})
+}
+
+type fakeFacade struct {
+	mu      sync.Mutex
+	bundle  string
+	changes chan struct{}
+}
+
+func (f *fakeFacade) setBundle(bundle string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.bundle = bundle
+}
+
+func (f *fakeFacade) CATrustBundle() (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.bundle, nil
+}
+
+func (f *fakeFacade) WatchForCATrustBundleChanges() (watcher.NotifyWatcher, error) {
+	return watchertest.NewMockNotifyWatcher(f.changes), nil
+}
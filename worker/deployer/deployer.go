@@ -5,13 +5,16 @@ package deployer
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/juju/clock"
 	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/utils"
 	"gopkg.in/juju/names.v3"
 	"gopkg.in/juju/worker.v1"
+	"gopkg.in/juju/worker.v1/catacomb"
 
 	"github.com/juju/juju/agent"
 	apideployer "github.com/juju/juju/api/deployer"
@@ -22,13 +25,50 @@ import (
 
 var logger = loggo.GetLogger("juju.worker.deployer")
 
+// verifyPeriod is the amount of time to wait between checks that the
+// agent services of deployed units are still installed. Services are
+// commonly deleted out-of-band by overzealous manual cleanup, and nothing
+// else would notice until the unit's agent was reported as lost.
+const verifyPeriod = 5 * time.Minute
+
+// MessageRepairedAgent is the agent status message set on a unit whose
+// agent service the deployer has had to reinstall.
+const MessageRepairedAgent = "agent service reinstalled after out-of-band removal"
+
+const (
+	// maxAgentRestarts is the number of times the deployer restarts a
+	// unit's agent service within agentRestartWindow before giving up on
+	// it, so that an agent which fails repeatedly is not restarted
+	// forever.
+	maxAgentRestarts = 3
+
+	// agentRestartWindow is the period over which a unit's agent service
+	// restarts are counted.
+	agentRestartWindow = 30 * time.Minute
+)
+
 // Deployer is responsible for deploying and recalling unit agents, according
 // to changes in a set of state units; and for the final removal of its agents'
 // units from state when they are no longer needed.
 type Deployer struct {
+	catacomb catacomb.Catacomb
 	st       *apideployer.State
 	ctx      Context
+	clock    clock.Clock
+	period   time.Duration
+
+	// deployed holds the units whose agents are deployed; assigned holds
+	// the units reported by the machine's units watcher that the deployer
+	// may still need to act upon.
 	deployed set.Strings
+	assigned set.Strings
+
+	// restarts holds the times at which the deployer has restarted the
+	// agent service of each unit, within agentRestartWindow; failed
+	// holds the units whose agents have been reported as failed, having
+	// been restarted too often.
+	restarts map[string][]time.Time
+	failed   set.Strings
 }
 
 // Context abstracts away the differences between different unit deployment
@@ -48,29 +88,101 @@ type Context interface {
 	// DeployedUnits returns the names of all units deployed by the manager.
 	DeployedUnits() ([]string, error)
 
+	// RepairUnit reinstalls and starts the agent service for a deployed
+	// unit whose service has been removed out-of-band, reusing the unit's
+	// existing agent configuration. It returns an error satisfying
+	// errors.IsNotFound if that configuration has gone too, in which case
+	// the unit must be deployed afresh.
+	RepairUnit(unitName string) error
+
+	// UnitAgentRunning reports whether the agent service of a deployed
+	// unit is running.
+	UnitAgentRunning(unitName string) (bool, error)
+
+	// RestartUnit restarts the agent service of a deployed unit, starting
+	// it if it has stopped running.
+	RestartUnit(unitName string) error
+
+	// UpdateUnitPassword rewrites the agent configuration of a deployed
+	// unit to use the supplied API password. The unit's agent goes on
+	// using its old password until it is restarted. It returns an error
+	// satisfying errors.IsNotFound if the unit has no agent configuration.
+	UpdateUnitPassword(unitName, password string) error
+
 	// AgentConfig returns the agent config for the machine agent that is
 	// running the deployer.
 	AgentConfig() agent.Config
 }
 
 // NewDeployer returns a Worker that deploys and recalls unit agents
-// via ctx, taking a machine id to operate on.
+// via ctx, taking a machine id to operate on. The worker periodically
+// verifies that the agent services of deployed units are still installed,
+// repairing any that are not, and running, restarting any that are not.
 func NewDeployer(st *apideployer.State, ctx Context) (worker.Worker, error) {
+	return newDeployer(st, ctx, clock.WallClock, verifyPeriod)
+}
+
+func newDeployer(st *apideployer.State, ctx Context, clock clock.Clock, period time.Duration) (worker.Worker, error) {
 	d := &Deployer{
 		st:       st,
 		ctx:      ctx,
+		clock:    clock,
+		period:   period,
 		deployed: make(set.Strings),
+		assigned: make(set.Strings),
+		restarts: make(map[string][]time.Time),
+		failed:   make(set.Strings),
 	}
-	w, err := watcher.NewStringsWorker(watcher.StringsConfig{
-		Handler: d,
-	})
-	if err != nil {
+	if err := catacomb.Invoke(catacomb.Plan{
+		Site: &d.catacomb,
+		Work: d.loop,
+	}); err != nil {
 		return nil, errors.Trace(err)
 	}
-	return w, nil
+	return d, nil
+}
+
+// Kill is part of the worker.Worker interface.
+func (d *Deployer) Kill() {
+	d.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (d *Deployer) Wait() error {
+	return d.catacomb.Wait()
+}
+
+func (d *Deployer) loop() error {
+	unitsWatcher, err := d.setUp()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := d.catacomb.Add(unitsWatcher); err != nil {
+		return errors.Trace(err)
+	}
+	timer := d.clock.NewTimer(d.period)
+	defer timer.Stop()
+	for {
+		select {
+		case <-d.catacomb.Dying():
+			return d.catacomb.ErrDying()
+		case unitNames, ok := <-unitsWatcher.Changes():
+			if !ok {
+				return errors.New("units watcher closed")
+			}
+			if err := d.handle(unitNames); err != nil {
+				return errors.Trace(err)
+			}
+		case <-timer.Chan():
+			if err := d.verify(); err != nil {
+				return errors.Trace(err)
+			}
+			timer.Reset(d.period)
+		}
+	}
 }
 
-func (d *Deployer) SetUp() (watcher.StringsWatcher, error) {
+func (d *Deployer) setUp() (watcher.StringsWatcher, error) {
 	tag := d.ctx.AgentConfig().Tag()
 	machineTag, ok := tag.(names.MachineTag)
 	if !ok {
@@ -98,8 +210,9 @@ func (d *Deployer) SetUp() (watcher.StringsWatcher, error) {
 	return machineUnitsWatcher, nil
 }
 
-func (d *Deployer) Handle(_ <-chan struct{}, unitNames []string) error {
+func (d *Deployer) handle(unitNames []string) error {
 	for _, unitName := range unitNames {
+		d.assigned.Add(unitName)
 		if err := d.changed(unitName); err != nil {
 			return err
 		}
@@ -181,6 +294,8 @@ func (d *Deployer) recall(unitName string) error {
 		return err
 	}
 	d.deployed.Remove(unitName)
+	delete(d.restarts, unitName)
+	d.failed.Remove(unitName)
 	return nil
 }
 
@@ -197,7 +312,198 @@ func (d *Deployer) remove(unit *apideployer.Unit) error {
 	return unit.Remove()
 }
 
-func (d *Deployer) TearDown() error {
-	// Nothing to do here.
+// rotatePassword gives the agent of the named deployed unit a new API
+// password, if one has been requested for the unit. The request stands
+// until the new password is set on the controller, so a rotation that
+// fails is logged and retried when the units are next verified, rather
+// than stopping the deployer.
+func (d *Deployer) rotatePassword(unitName string) error {
+	unit, err := d.st.Unit(names.NewUnitTag(unitName))
+	if params.IsCodeNotFoundOrCodeUnauthorized(err) {
+		// The unit's gone, and will be recalled when the
+		// watcher reports it.
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	requested, err := unit.PasswordRotationRequested()
+	if errors.IsNotSupported(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	} else if !requested {
+		return nil
+	}
+	logger.Infof("rotating password for unit %q", unitName)
+	password, err := utils.RandomPassword()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// The agent's configuration is written first: were the password set
+	// on the controller first, failing to write it would lock the agent
+	// out for good. The running agent keeps using its old password, and
+	// is only restarted with the new one once it has been set.
+	if err := d.ctx.UpdateUnitPassword(unitName, password); err != nil {
+		logger.Errorf("cannot update agent password for unit %q: %v", unitName, err)
+		return nil
+	}
+	if err := unit.SetPassword(password); err != nil {
+		logger.Errorf("cannot set password for unit %q: %v", unitName, err)
+		return nil
+	}
+	if err := d.ctx.RestartUnit(unitName); err != nil {
+		return errors.Annotatef(err, "cannot restart agent service for unit %q", unitName)
+	}
+	return nil
+}
+
+// verify compares the units the deployer is responsible for with the agent
+// services actually installed. Services found for units the deployer has no
+// record of are adopted and re-evaluated, as at startup; units assigned to
+// the machine that were never deployed are re-evaluated; deployed units
+// whose services have gone are repaired; deployed units whose services
+// are not running are restarted; and the agents of deployed units for
+// which a new password has been requested are given one.
+func (d *Deployer) verify() error {
+	installed, err := d.ctx.DeployedUnits()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	installedSet := set.NewStrings(installed...)
+	for _, unitName := range installedSet.Difference(d.deployed).SortedValues() {
+		logger.Warningf("found agent service for unrecorded unit %q", unitName)
+		d.deployed.Add(unitName)
+		if err := d.changed(unitName); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	for _, unitName := range d.assigned.Difference(d.deployed).SortedValues() {
+		if err := d.changed(unitName); err != nil {
+			return errors.Trace(err)
+		}
+		if !d.deployed.Contains(unitName) {
+			// The unit's been dealt with, and will be reported by
+			// the watcher if it's of further interest.
+			d.assigned.Remove(unitName)
+		}
+	}
+	for _, unitName := range d.deployed.Difference(installedSet).SortedValues() {
+		if err := d.repair(unitName); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	for _, unitName := range d.deployed.Intersection(installedSet).SortedValues() {
+		if err := d.checkRunning(unitName); err != nil {
+			return errors.Trace(err)
+		}
+		if err := d.rotatePassword(unitName); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// checkRunning restarts the agent service of the named deployed unit if it
+// is not running, unless it has already been restarted maxAgentRestarts
+// times within agentRestartWindow; in that case the agent is reported as
+// failed instead, so that it is not seen merely as lost.
+func (d *Deployer) checkRunning(unitName string) error {
+	running, err := d.ctx.UnitAgentRunning(unitName)
+	if err != nil {
+		return errors.Annotatef(err, "cannot check agent service for unit %q", unitName)
+	}
+	now := d.clock.Now()
+	var recent []time.Time
+	for _, t := range d.restarts[unitName] {
+		if now.Sub(t) < agentRestartWindow {
+			recent = append(recent, t)
+		}
+	}
+	// Restarts are remembered while the agent runs, so that one which
+	// keeps failing soon after starting is still caught.
+	d.restarts[unitName] = recent
+	if running {
+		d.failed.Remove(unitName)
+		return nil
+	}
+	if len(recent) >= maxAgentRestarts {
+		if d.failed.Contains(unitName) {
+			// The failure has already been reported.
+			return nil
+		}
+		d.failed.Add(unitName)
+		message := fmt.Sprintf(
+			"agent service not running; restarted %d times in %v",
+			maxAgentRestarts, agentRestartWindow,
+		)
+		logger.Errorf("%s for unit %q; not restarting again", message, unitName)
+		return errors.Trace(d.setAgentFailed(unitName, message))
+	}
+	logger.Warningf("agent service for unit %q is not running; restarting", unitName)
+	if err := d.ctx.RestartUnit(unitName); err != nil {
+		return errors.Annotatef(err, "cannot restart agent service for unit %q", unitName)
+	}
+	d.restarts[unitName] = append(recent, now)
+	d.failed.Remove(unitName)
 	return nil
 }
+
+// setAgentFailed reports the agent of the named unit as failed, for the
+// given reason.
+func (d *Deployer) setAgentFailed(unitName, message string) error {
+	unit, err := d.st.Unit(names.NewUnitTag(unitName))
+	if params.IsCodeNotFoundOrCodeUnauthorized(err) {
+		// The unit's gone, and will be recalled when the
+		// watcher reports it.
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	err = unit.SetAgentStatus(status.Failed, message, nil)
+	if errors.IsNotSupported(err) {
+		logger.Debugf("cannot report failed agent for unit %q: %v", unitName, err)
+		return nil
+	}
+	return errors.Trace(err)
+}
+
+// repair restores the agent service of the named deployed unit, which has
+// been found to be missing, and reports the repair on the unit's agent
+// status. The workload status belongs to the charm and is left alone.
+func (d *Deployer) repair(unitName string) error {
+	unit, err := d.st.Unit(names.NewUnitTag(unitName))
+	if params.IsCodeNotFoundOrCodeUnauthorized(err) {
+		// The unit's gone; there's no service to recall, so
+		// just forget about it.
+		logger.Infof("forgetting removed unit %q with missing agent service", unitName)
+		d.deployed.Remove(unitName)
+		d.assigned.Remove(unitName)
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if unit.Life() != params.Alive {
+		d.deployed.Remove(unitName)
+		return d.changed(unitName)
+	}
+	logger.Warningf("agent service for unit %q is missing; repairing", unitName)
+	err = d.ctx.RepairUnit(unitName)
+	if errors.IsNotFound(err) {
+		// The agent's configuration is gone too, so it must be
+		// deployed from scratch with a new password.
+		logger.Warningf("cannot repair unit %q (%v); redeploying", unitName, err)
+		d.deployed.Remove(unitName)
+		return d.deploy(unit)
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot repair unit %q", unitName)
+	}
+	logger.Infof("reinstalled agent service for unit %q", unitName)
+	err = unit.SetAgentStatus(status.Idle, MessageRepairedAgent, map[string]interface{}{
+		"repaired": "agent-service",
+	})
+	if errors.IsNotSupported(err) {
+		logger.Debugf("cannot report repaired agent for unit %q: %v", unitName, err)
+		return nil
+	}
+	return errors.Trace(err)
+}
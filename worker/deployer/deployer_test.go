@@ -4,15 +4,20 @@
 package deployer_test
 
 import (
+	"os"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/juju/clock/testclock"
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
 	"gopkg.in/juju/worker.v1"
 
+	"github.com/juju/juju/agent"
 	"github.com/juju/juju/api"
 	apideployer "github.com/juju/juju/api/deployer"
 	"github.com/juju/juju/core/status"
@@ -241,6 +246,231 @@ func (s *deployerSuite) TestNonAliveSubordinates(c *gc.C) {
 	s.waitFor(c, isRemoved(s.State, sub1.Name()))
 }
 
+func (s *deployerSuite) TestVerifyRepairsMissingService(c *gc.C) {
+	app := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	u0, err := app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = u0.AssignToMachine(s.machine)
+	c.Assert(err, jc.ErrorIsNil)
+
+	clock := testclock.NewClock(time.Now())
+	ctx := s.getContextForMachine(c, s.machine.Tag())
+	dep, err := deployer.NewTestDeployer(s.deployerState, ctx, clock, time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	defer stop(c, dep)
+	s.waitFor(c, isDeployed(ctx, u0.Name()))
+
+	// The charm's workload status must survive the repair.
+	now := time.Now()
+	err = u0.SetStatus(status.StatusInfo{
+		Status:  status.Active,
+		Message: "serving",
+		Since:   &now,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Delete the unit's service out-of-band, and check that the
+	// next verification pass reinstalls it.
+	err = s.data.SetStatus("jujud-"+names.NewUnitTag(u0.Name()).String(), "")
+	c.Assert(err, jc.ErrorIsNil)
+	s.waitFor(c, isDeployed(ctx))
+
+	err = clock.WaitAdvance(time.Minute, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	s.waitFor(c, isDeployed(ctx, u0.Name()))
+	s.waitFor(c, unitAgentStatus(u0, status.StatusInfo{
+		Status:  status.Idle,
+		Message: deployer.MessageRepairedAgent,
+	}))
+	sInfo, err := u0.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(sInfo.Status, gc.Equals, status.Active)
+	c.Check(sInfo.Message, gc.Equals, "serving")
+}
+
+func (s *deployerSuite) TestVerifyRedeploysWhenAgentConfigMissing(c *gc.C) {
+	app := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	u0, err := app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = u0.AssignToMachine(s.machine)
+	c.Assert(err, jc.ErrorIsNil)
+
+	clock := testclock.NewClock(time.Now())
+	ctx := s.getContextForMachine(c, s.machine.Tag())
+	dep, err := deployer.NewTestDeployer(s.deployerState, ctx, clock, time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	defer stop(c, dep)
+	s.waitFor(c, isDeployed(ctx, u0.Name()))
+
+	tag := names.NewUnitTag(u0.Name())
+	err = s.data.SetStatus("jujud-"+tag.String(), "")
+	c.Assert(err, jc.ErrorIsNil)
+	agentDir, _ := s.paths(tag)
+	err = os.RemoveAll(agentDir)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = clock.WaitAdvance(time.Minute, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	s.waitFor(c, isDeployed(ctx, u0.Name()))
+	s.waitFor(c, unitStatus(u0, status.StatusInfo{
+		Status:  status.Waiting,
+		Message: status.MessageInstallingAgent,
+	}))
+}
+
+func (s *deployerSuite) TestRestartsStoppedAgentService(c *gc.C) {
+	app := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	u0, err := app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = u0.AssignToMachine(s.machine)
+	c.Assert(err, jc.ErrorIsNil)
+
+	clock := testclock.NewClock(time.Now())
+	ctx := s.getContextForMachine(c, s.machine.Tag())
+	dep, err := deployer.NewTestDeployer(s.deployerState, ctx, clock, time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	defer stop(c, dep)
+	s.waitFor(c, isDeployed(ctx, u0.Name()))
+
+	svcName := "jujud-" + names.NewUnitTag(u0.Name()).String()
+	isRunning := func(c *gc.C) bool {
+		running, err := ctx.UnitAgentRunning(u0.Name())
+		c.Assert(err, jc.ErrorIsNil)
+		return running
+	}
+	// The agent service is restarted each time it stops, up to a limit.
+	for i := 0; i < 3; i++ {
+		err = s.data.SetStatus(svcName, "installed")
+		c.Assert(err, jc.ErrorIsNil)
+		err = clock.WaitAdvance(time.Minute, coretesting.LongWait, 1)
+		c.Assert(err, jc.ErrorIsNil)
+		s.waitFor(c, isRunning)
+	}
+
+	// After that, the agent is reported as failed rather than restarted.
+	err = s.data.SetStatus(svcName, "installed")
+	c.Assert(err, jc.ErrorIsNil)
+	err = clock.WaitAdvance(time.Minute, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	s.waitFor(c, unitAgentStatus(u0, status.StatusInfo{
+		Status:  status.Failed,
+		Message: "agent service not running; restarted 3 times in 30m0s",
+	}))
+	c.Assert(isRunning(c), jc.IsFalse)
+}
+
+func (s *deployerSuite) TestRotatesRequestedUnitPassword(c *gc.C) {
+	app := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	u0, err := app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = u0.AssignToMachine(s.machine)
+	c.Assert(err, jc.ErrorIsNil)
+
+	clock := testclock.NewClock(time.Now())
+	ctx := s.getContextForMachine(c, s.machine.Tag())
+	dep, err := deployer.NewTestDeployer(s.deployerState, ctx, clock, time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	defer stop(c, dep)
+	s.waitFor(c, isDeployed(ctx, u0.Name()))
+	oldPassword := s.agentPassword(c, u0.Name())
+
+	err = u0.RequestPasswordRotation()
+	c.Assert(err, jc.ErrorIsNil)
+	err = clock.WaitAdvance(time.Minute, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	s.waitFor(c, passwordRotated(u0))
+
+	newPassword := s.agentPassword(c, u0.Name())
+	c.Assert(newPassword, gc.Not(gc.Equals), oldPassword)
+	c.Assert(u0.PasswordValid(newPassword), jc.IsTrue)
+	c.Assert(u0.PasswordValid(oldPassword), jc.IsFalse)
+}
+
+func (s *deployerSuite) TestRotateUnitPasswordAgentConfigFailure(c *gc.C) {
+	app := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	u0, err := app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = u0.AssignToMachine(s.machine)
+	c.Assert(err, jc.ErrorIsNil)
+
+	clock := testclock.NewClock(time.Now())
+	ctx := &failingPasswordContext{
+		Context:  s.getContextForMachine(c, s.machine.Tag()),
+		fail:     true,
+		attempts: make(chan string, 1),
+	}
+	dep, err := deployer.NewTestDeployer(s.deployerState, ctx, clock, time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	defer stop(c, dep)
+	s.waitFor(c, isDeployed(ctx, u0.Name()))
+	oldPassword := s.agentPassword(c, u0.Name())
+
+	err = u0.RequestPasswordRotation()
+	c.Assert(err, jc.ErrorIsNil)
+	err = clock.WaitAdvance(time.Minute, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case unitName := <-ctx.attempts:
+		c.Assert(unitName, gc.Equals, u0.Name())
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for password rotation")
+	}
+
+	// The agent's configuration couldn't be written, so the password
+	// on the controller is unchanged, and the rotation still pending.
+	err = u0.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(u0.PasswordValid(oldPassword), jc.IsTrue)
+	c.Assert(u0.PasswordRotationRequested(), jc.IsTrue)
+	c.Assert(s.agentPassword(c, u0.Name()), gc.Equals, oldPassword)
+
+	// The rotation is retried when the units are next verified.
+	ctx.setFail(false)
+	err = clock.WaitAdvance(time.Minute, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	s.waitFor(c, passwordRotated(u0))
+	newPassword := s.agentPassword(c, u0.Name())
+	c.Assert(newPassword, gc.Not(gc.Equals), oldPassword)
+	c.Assert(u0.PasswordValid(newPassword), jc.IsTrue)
+}
+
+// agentPassword returns the API password in the agent configuration of
+// the named unit.
+func (s *deployerSuite) agentPassword(c *gc.C, unitName string) string {
+	conf, err := agent.ReadConfig(agent.ConfigPath(s.DataDir(), names.NewUnitTag(unitName)))
+	c.Assert(err, jc.ErrorIsNil)
+	apiInfo, ok := conf.APIInfo()
+	c.Assert(ok, jc.IsTrue)
+	return apiInfo.Password
+}
+
+// failingPasswordContext is a deployer.Context which fails to update the
+// passwords of unit agents while fail is set, reporting each attempt.
+type failingPasswordContext struct {
+	deployer.Context
+
+	mu       sync.Mutex
+	fail     bool
+	attempts chan string
+}
+
+func (ctx *failingPasswordContext) setFail(fail bool) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	ctx.fail = fail
+}
+
+func (ctx *failingPasswordContext) UpdateUnitPassword(unitName, password string) error {
+	ctx.mu.Lock()
+	fail := ctx.fail
+	ctx.mu.Unlock()
+	if fail {
+		ctx.attempts <- unitName
+		return errors.New("disk full")
+	}
+	return ctx.Context.UpdateUnitPassword(unitName, password)
+}
+
 func (s *deployerSuite) waitFor(c *gc.C, t func(c *gc.C) bool) {
 	s.BackingState.StartSync()
 	if t(c) {
@@ -288,6 +518,22 @@ func unitStatus(u *state.Unit, statusInfo status.StatusInfo) func(*gc.C) bool {
 	}
 }
 
+func unitAgentStatus(u *state.Unit, statusInfo status.StatusInfo) func(*gc.C) bool {
+	return func(c *gc.C) bool {
+		sInfo, err := u.AgentStatus()
+		c.Assert(err, jc.ErrorIsNil)
+		return sInfo.Status == statusInfo.Status && sInfo.Message == statusInfo.Message
+	}
+}
+
+func passwordRotated(u *state.Unit) func(*gc.C) bool {
+	return func(c *gc.C) bool {
+		err := u.Refresh()
+		c.Assert(err, jc.ErrorIsNil)
+		return !u.PasswordRotationRequested()
+	}
+}
+
 func stop(c *gc.C, w worker.Worker) {
 	c.Assert(worker.Stop(w), gc.IsNil)
 }
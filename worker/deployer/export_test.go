@@ -4,7 +4,13 @@
 package deployer
 
 import (
+	"time"
+
+	"github.com/juju/clock"
+	"gopkg.in/juju/worker.v1"
+
 	"github.com/juju/juju/agent"
+	apideployer "github.com/juju/juju/api/deployer"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/service/common"
 	svctesting "github.com/juju/juju/service/common/testing"
@@ -32,3 +38,7 @@ func NewTestSimpleContext(agentConfig agent.Config, logDir string, data *svctest
 		},
 	}
 }
+
+func NewTestDeployer(st *apideployer.State, ctx Context, clock clock.Clock, period time.Duration) (worker.Worker, error) {
+	return newDeployer(st, ctx, clock, period)
+}
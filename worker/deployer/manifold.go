@@ -19,7 +19,7 @@ import (
 type ManifoldConfig struct {
 	AgentName        string
 	APICallerName    string
-	NewDeployContext func(st *apideployer.State, agentConfig agent.Config) Context
+	NewDeployContext func(st *apideployer.State, agentConfig agent.Config) (Context, error)
 }
 
 // Manifold returns a dependency manifold that runs a deployer worker,
@@ -43,7 +43,10 @@ func (config ManifoldConfig) newWorker(a agent.Agent, apiCaller base.APICaller)
 		return nil, errors.New("agent's tag is not a machine tag")
 	}
 	deployerFacade := apideployer.NewState(apiCaller)
-	context := config.NewDeployContext(deployerFacade, cfg)
+	context, err := config.NewDeployContext(deployerFacade, cfg)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot create unit agent deployer context")
+	}
 	w, err := NewDeployer(deployerFacade, context)
 	if err != nil {
 		return nil, errors.Annotate(err, "cannot start unit agent deployer worker")
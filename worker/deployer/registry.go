@@ -0,0 +1,94 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package deployer
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/agent"
+)
+
+// SimpleContextKind identifies the default Context implementation, which
+// installs unit agents as services on the local init system.
+const SimpleContextKind = "simple"
+
+// ContextFactory creates a Context for the machine agent described by
+// agentConfig, using api to look up controller connection details.
+type ContextFactory func(agentConfig agent.Config, api APICalls) (Context, error)
+
+var (
+	contextFactoriesMu sync.Mutex
+	contextFactories   = map[string]ContextFactory{
+		SimpleContextKind: func(agentConfig agent.Config, api APICalls) (Context, error) {
+			return NewSimpleContext(agentConfig, api), nil
+		},
+	}
+)
+
+// RegisterContext makes a Context implementation available under the given
+// kind, so that it can be selected by setting agent.DeployerContext in the
+// machine agent's config. It is an error to register the same kind twice.
+func RegisterContext(kind string, factory ContextFactory) error {
+	if kind == "" {
+		return errors.NotValidf("empty deployer context kind")
+	}
+	if factory == nil {
+		return errors.NotValidf("nil factory for deployer context %q", kind)
+	}
+	contextFactoriesMu.Lock()
+	defer contextFactoriesMu.Unlock()
+	if _, ok := contextFactories[kind]; ok {
+		return errors.AlreadyExistsf("deployer context %q", kind)
+	}
+	contextFactories[kind] = factory
+	return nil
+}
+
+// UnregisterContext removes the Context implementation registered under
+// the given kind. The default simple context cannot be removed.
+func UnregisterContext(kind string) {
+	if kind == SimpleContextKind {
+		return
+	}
+	contextFactoriesMu.Lock()
+	defer contextFactoriesMu.Unlock()
+	delete(contextFactories, kind)
+}
+
+// RegisteredContexts returns the sorted kinds of all registered Context
+// implementations.
+func RegisteredContexts() []string {
+	contextFactoriesMu.Lock()
+	defer contextFactoriesMu.Unlock()
+	kinds := make([]string, 0, len(contextFactories))
+	for kind := range contextFactories {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// NewContext returns the Context implementation selected by the
+// agent.DeployerContext value in agentConfig. If no value is set, a
+// SimpleContext is returned.
+func NewContext(agentConfig agent.Config, api APICalls) (Context, error) {
+	kind := agentConfig.Value(agent.DeployerContext)
+	if kind == "" {
+		kind = SimpleContextKind
+	}
+	contextFactoriesMu.Lock()
+	factory, ok := contextFactories[kind]
+	contextFactoriesMu.Unlock()
+	if !ok {
+		return nil, errors.NotFoundf("deployer context %q", kind)
+	}
+	ctx, err := factory(agentConfig, api)
+	if err != nil {
+		return nil, errors.Annotatef(err, "creating deployer context %q", kind)
+	}
+	return ctx, nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package deployer_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/deployer"
+)
+
+type RegistrySuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&RegistrySuite{})
+
+type stubAPI struct{}
+
+func (stubAPI) ConnectionInfo() (params.DeployerConnectionValues, error) {
+	return params.DeployerConnectionValues{}, nil
+}
+
+type stubContext struct {
+	deployer.Context
+	agentConfig agent.Config
+}
+
+func (s *RegistrySuite) config(kind string) agent.Config {
+	return &mockConfig{
+		tag:    names.NewMachineTag("42"),
+		values: map[string]string{agent.DeployerContext: kind},
+	}
+}
+
+func (s *RegistrySuite) TestDefaultIsSimpleContext(c *gc.C) {
+	ctx, err := deployer.NewContext(s.config(""), stubAPI{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctx, gc.FitsTypeOf, &deployer.SimpleContext{})
+
+	ctx, err = deployer.NewContext(s.config(deployer.SimpleContextKind), stubAPI{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctx, gc.FitsTypeOf, &deployer.SimpleContext{})
+}
+
+func (s *RegistrySuite) TestRegisteredContextSelected(c *gc.C) {
+	err := deployer.RegisterContext("oci", func(agentConfig agent.Config, api deployer.APICalls) (deployer.Context, error) {
+		return &stubContext{agentConfig: agentConfig}, nil
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer deployer.UnregisterContext("oci")
+	c.Assert(deployer.RegisteredContexts(), jc.DeepEquals, []string{"oci", "simple"})
+
+	config := s.config("oci")
+	ctx, err := deployer.NewContext(config, stubAPI{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctx, gc.FitsTypeOf, &stubContext{})
+	c.Assert(ctx.(*stubContext).agentConfig, gc.Equals, config)
+}
+
+func (s *RegistrySuite) TestRegisterDuplicate(c *gc.C) {
+	err := deployer.RegisterContext(deployer.SimpleContextKind, func(agent.Config, deployer.APICalls) (deployer.Context, error) {
+		return nil, nil
+	})
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *RegistrySuite) TestRegisterInvalid(c *gc.C) {
+	err := deployer.RegisterContext("", func(agent.Config, deployer.APICalls) (deployer.Context, error) {
+		return nil, nil
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	err = deployer.RegisterContext("snap", nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *RegistrySuite) TestUnknownContext(c *gc.C) {
+	_, err := deployer.NewContext(s.config("unknown"), stubAPI{})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `deployer context "unknown" not found`)
+}
+
+func (s *RegistrySuite) TestFactoryError(c *gc.C) {
+	err := deployer.RegisterContext("broken", func(agent.Config, deployer.APICalls) (deployer.Context, error) {
+		return nil, errors.New("boom")
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer deployer.UnregisterContext("broken")
+
+	_, err = deployer.NewContext(s.config("broken"), stubAPI{})
+	c.Assert(err, gc.ErrorMatches, `creating deployer context "broken": boom`)
+}
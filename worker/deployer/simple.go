@@ -18,6 +18,7 @@ import (
 	"github.com/juju/juju/agent"
 	"github.com/juju/juju/agent/tools"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/resource/cache"
 	"github.com/juju/juju/service"
 	"github.com/juju/juju/service/common"
 	jujuversion "github.com/juju/juju/version"
@@ -108,19 +109,9 @@ func (ctx *SimpleContext) DeployUnit(unitName, initialPassword string) (err erro
 	tag := names.NewUnitTag(unitName)
 	dataDir := ctx.agentConfig.DataDir()
 	logDir := ctx.agentConfig.LogDir()
-	hostSeries, err := series.HostSeries()
-	if err != nil {
-		return errors.Trace(err)
-	}
-	current := version.Binary{
-		Number: jujuversion.Current,
-		Arch:   arch.HostArch(),
-		Series: hostSeries,
-	}
 	toolsDir := tools.ToolsDir(dataDir, tag.String())
 	defer removeOnErr(&err, toolsDir)
-	_, err = tools.ChangeAgentTools(dataDir, tag.String(), current)
-	if err != nil {
+	if err = linkAgentTools(dataDir, tag); err != nil {
 		return errors.Trace(err)
 	}
 
@@ -166,8 +157,110 @@ func (ctx *SimpleContext) DeployUnit(unitName, initialPassword string) (err erro
 	return nil
 }
 
+// RepairUnit is part of the Context interface.
+func (ctx *SimpleContext) RepairUnit(unitName string) error {
+	tag := names.NewUnitTag(unitName)
+	dataDir := ctx.agentConfig.DataDir()
+	if _, err := os.Stat(agent.ConfigPath(dataDir, tag)); os.IsNotExist(err) {
+		return errors.NotFoundf("agent configuration for unit %q", unitName)
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := os.Stat(tools.ToolsDir(dataDir, tag.String())); os.IsNotExist(err) {
+		if err := linkAgentTools(dataDir, tag); err != nil {
+			return errors.Trace(err)
+		}
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+
+	renderer, err := shell.NewRenderer("")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	svc, err := ctx.service(unitName, renderer)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	installed, err := svc.Installed()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if installed {
+		return nil
+	}
+	logger.Infof("reinstalling agent service for unit %q", unitName)
+	return errors.Trace(service.InstallAndStart(svc))
+}
+
+// UpdateUnitPassword is part of the Context interface.
+func (ctx *SimpleContext) UpdateUnitPassword(unitName, password string) error {
+	tag := names.NewUnitTag(unitName)
+	configPath := agent.ConfigPath(ctx.agentConfig.DataDir(), tag)
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		return errors.NotFoundf("agent configuration for unit %q", unitName)
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	conf, err := agent.ReadConfig(configPath)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// The current password is kept as the old password, as when the
+	// agent changes its own password, so the agent can still log in if
+	// the new password is never set on the controller. An agent that
+	// logs in with its old password goes on to change its password.
+	oldPassword := conf.OldPassword()
+	if info, ok := conf.APIInfo(); ok && info.Password != "" {
+		oldPassword = info.Password
+	}
+	conf.SetOldPassword(oldPassword)
+	conf.SetPassword(password)
+	return errors.Trace(conf.Write())
+}
+
+// UnitAgentRunning is part of the Context interface.
+func (ctx *SimpleContext) UnitAgentRunning(unitName string) (bool, error) {
+	svc, err := ctx.findInitSystemJob(unitName)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	running, err := svc.Running()
+	return running, errors.Trace(err)
+}
+
+// RestartUnit is part of the Context interface.
+func (ctx *SimpleContext) RestartUnit(unitName string) error {
+	svc, err := ctx.findInitSystemJob(unitName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	logger.Infof("restarting agent service for unit %q", unitName)
+	if err := svc.Stop(); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(svc.Start())
+}
+
+// linkAgentTools links the tools of the running machine agent for use by
+// the agent with the supplied tag.
+func linkAgentTools(dataDir string, tag names.Tag) error {
+	hostSeries, err := series.HostSeries()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	current := version.Binary{
+		Number: jujuversion.Current,
+		Arch:   arch.HostArch(),
+		Series: hostSeries,
+	}
+	_, err = tools.ChangeAgentTools(dataDir, tag.String(), current)
+	return errors.Trace(err)
+}
+
 type deployerService interface {
 	Installed() (bool, error)
+	Running() (bool, error)
 	Install() error
 	Remove() error
 	Start() error
@@ -219,6 +312,11 @@ func (ctx *SimpleContext) RecallUnit(unitName string) error {
 	if err := os.RemoveAll(agentDir); err != nil {
 		return errors.Trace(err)
 	}
+	// Drop the unit's references to resources it shares with other
+	// units, removing any that are no longer in use.
+	if err := cache.New(cache.Dir(dataDir)).Release(unitName); err != nil {
+		return errors.Annotatef(err, "cannot release cached resources of unit %q", unitName)
+	}
 	// TODO(dfc) should take a Tag
 	toolsDir := tools.ToolsDir(dataDir, tag.String())
 	return os.Remove(toolsDir)
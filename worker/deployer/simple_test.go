@@ -11,16 +11,20 @@ import (
 	"regexp"
 	"runtime"
 	"sort"
+	"strings"
 
+	"github.com/juju/errors"
 	"github.com/juju/os/series"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/arch"
 	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
+	charmresource "gopkg.in/juju/charm.v6/resource"
 	"gopkg.in/juju/names.v3"
 
 	"github.com/juju/juju/agent"
 	"github.com/juju/juju/agent/tools"
+	"github.com/juju/juju/resource/cache"
 	svctesting "github.com/juju/juju/service/common/testing"
 	"github.com/juju/juju/service/upstart"
 	"github.com/juju/juju/state/multiwatcher"
@@ -78,6 +82,112 @@ func (s *SimpleContextSuite) TestDeployRecall(c *gc.C) {
 	s.checkUnitRemoved(c, "foo/123")
 }
 
+func (s *SimpleContextSuite) TestRecallReleasesCachedResources(c *gc.C) {
+	mgr := s.getContext(c)
+	err := mgr.DeployUnit("foo/123", "some-password")
+	c.Assert(err, jc.ErrorIsNil)
+
+	data := "some data"
+	fp, err := charmresource.GenerateFingerprint(strings.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+	path := filepath.Join(c.MkDir(), "eggs.tgz")
+	err = ioutil.WriteFile(path, []byte(data), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	resourceCache := cache.New(cache.Dir(s.dataDir))
+	err = resourceCache.Add("foo/123", fp, path)
+	c.Assert(err, jc.ErrorIsNil)
+	err = resourceCache.Add("bar/0", fp, path)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = mgr.RecallUnit("foo/123")
+	c.Assert(err, jc.ErrorIsNil)
+	units, err := resourceCache.Units(fp)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, jc.DeepEquals, []string{"bar/0"})
+}
+
+func (s *SimpleContextSuite) TestRepairUnit(c *gc.C) {
+	mgr := s.getContext(c)
+	err := mgr.DeployUnit("foo/123", "some-password")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUpstartCount(c, 1)
+
+	// Repairing an intact unit is a no-op.
+	err = mgr.RepairUnit("foo/123")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUpstartCount(c, 1)
+
+	err = s.data.SetStatus("jujud-unit-foo-123", "")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUpstartCount(c, 0)
+
+	err = mgr.RepairUnit("foo/123")
+	c.Assert(err, jc.ErrorIsNil)
+	units, err := mgr.DeployedUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.DeepEquals, []string{"foo/123"})
+	s.checkUnitInstalled(c, "foo/123", "some-password")
+}
+
+func (s *SimpleContextSuite) TestRepairUnitMissingAgentConfig(c *gc.C) {
+	mgr := s.getContext(c)
+	err := mgr.RepairUnit("foo/123")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `agent configuration for unit "foo/123" not found`)
+	s.assertUpstartCount(c, 0)
+}
+
+func (s *SimpleContextSuite) TestRestartUnit(c *gc.C) {
+	mgr := s.getContext(c)
+	err := mgr.DeployUnit("foo/123", "some-password")
+	c.Assert(err, jc.ErrorIsNil)
+	running, err := mgr.UnitAgentRunning("foo/123")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(running, jc.IsTrue)
+
+	err = s.data.SetStatus("jujud-unit-foo-123", "installed")
+	c.Assert(err, jc.ErrorIsNil)
+	running, err = mgr.UnitAgentRunning("foo/123")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(running, jc.IsFalse)
+
+	err = mgr.RestartUnit("foo/123")
+	c.Assert(err, jc.ErrorIsNil)
+	running, err = mgr.UnitAgentRunning("foo/123")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(running, jc.IsTrue)
+}
+
+func (s *SimpleContextSuite) TestUnitAgentRunningNotDeployed(c *gc.C) {
+	mgr := s.getContext(c)
+	_, err := mgr.UnitAgentRunning("foo/123")
+	c.Assert(err, gc.ErrorMatches, `unit "foo/123" is not deployed`)
+}
+
+func (s *SimpleContextSuite) TestUpdateUnitPassword(c *gc.C) {
+	mgr := s.getContext(c)
+	err := mgr.DeployUnit("foo/123", "some-password")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = mgr.UpdateUnitPassword("foo/123", "new-password")
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkUnitInstalled(c, "foo/123", "new-password")
+
+	conf, err := agent.ReadConfig(agent.ConfigPath(s.dataDir, names.NewUnitTag("foo/123")))
+	c.Assert(err, jc.ErrorIsNil)
+	apiInfo, ok := conf.APIInfo()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(apiInfo.Password, gc.Equals, "new-password")
+	c.Assert(conf.OldPassword(), gc.Equals, "some-password")
+}
+
+func (s *SimpleContextSuite) TestUpdateUnitPasswordMissingAgentConfig(c *gc.C) {
+	mgr := s.getContext(c)
+	err := mgr.UpdateUnitPassword("foo/123", "new-password")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `agent configuration for unit "foo/123" not found`)
+}
+
 func (s *SimpleContextSuite) TestOldDeployedUnitsCanBeRecalled(c *gc.C) {
 	// After r1347 deployer tag is no longer part of the upstart conf filenames,
 	// now only the units' tags are used. This change is with the assumption only
@@ -296,6 +406,7 @@ type mockConfig struct {
 	logdir            string
 	upgradedToVersion version.Number
 	jobs              []multiwatcher.MachineJob
+	values            map[string]string
 }
 
 func (mock *mockConfig) Tag() names.Tag {
@@ -335,8 +446,8 @@ func (mock *mockConfig) CACert() string {
 	return testing.CACert
 }
 
-func (mock *mockConfig) Value(_ string) string {
-	return ""
+func (mock *mockConfig) Value(key string) string {
+	return mock.values[key]
 }
 
 func agentConfig(tag names.Tag, datadir, logdir string) agent.Config {
@@ -129,3 +129,21 @@ func (s *DiffRulesSuite) TestDiffRangesClosesPortsIfRulesAreDisjoint(c *gc.C) {
 	c.Assert(toOpen, gc.DeepEquals, wanted)
 	c.Assert(toClose, gc.DeepEquals, current)
 }
+
+func (s *DiffRulesSuite) TestDiffEgressRules(c *gc.C) {
+	current := []network.EgressRule{
+		network.MustNewEgressRule("tcp", 443, 443, "10.0.0.0/8"),
+		network.MustNewEgressRule("udp", 53, 53),
+	}
+	wanted := []network.EgressRule{
+		network.MustNewEgressRule("udp", 53, 53, "0.0.0.0/0"),
+		network.MustNewEgressRule("tcp", 443, 443, "192.168.0.0/16"),
+	}
+	toOpen, toClose := diffEgressRules(current, wanted)
+	c.Assert(toOpen, jc.DeepEquals, []network.EgressRule{
+		network.MustNewEgressRule("tcp", 443, 443, "192.168.0.0/16"),
+	})
+	c.Assert(toClose, jc.DeepEquals, []network.EgressRule{
+		network.MustNewEgressRule("tcp", 443, 443, "10.0.0.0/8"),
+	})
+}
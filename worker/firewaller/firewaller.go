@@ -5,6 +5,8 @@ package firewaller
 
 import (
 	"io"
+	"net"
+	"reflect"
 	"strings"
 	"time"
 
@@ -47,6 +49,7 @@ type FirewallerAPI interface {
 	MacaroonForRelation(relationKey string) (*macaroon.Macaroon, error)
 	SetRelationStatus(relationKey string, status relation.Status, message string) error
 	FirewallRules(applicationNames ...string) ([]params.FirewallRule, error)
+	WatchFirewallRules() (watcher.NotifyWatcher, error)
 }
 
 // CrossModelFirewallerFacade exposes firewaller functionality on the
@@ -133,14 +136,20 @@ type Firewaller struct {
 
 	machinesWatcher      watcher.StringsWatcher
 	portsWatcher         watcher.StringsWatcher
+	rulesWatcher         watcher.NotifyWatcher
 	machineds            map[names.MachineTag]*machineData
 	unitsChange          chan *unitsChange
 	unitds               map[names.UnitTag]*unitData
 	applicationids       map[names.ApplicationTag]*applicationData
 	exposedChange        chan *exposedChange
+	egressRulesChange    chan *egressRulesChange
 	globalMode           bool
 	globalIngressRuleRef map[string]int // map of rule names to count of occurrences
 
+	// exposedCIDRs holds the model's whitelist for exposed
+	// application ports. If empty, they are open to the world.
+	exposedCIDRs set.Strings
+
 	modelUUID                  string
 	newRemoteFirewallerAPIFunc newCrossModelFacadeFunc
 	remoteRelationsWatcher     watcher.StringsWatcher
@@ -174,6 +183,7 @@ func NewFirewaller(cfg Config) (worker.Worker, error) {
 		unitds:                     make(map[names.UnitTag]*unitData),
 		applicationids:             make(map[names.ApplicationTag]*applicationData),
 		exposedChange:              make(chan *exposedChange),
+		egressRulesChange:          make(chan *egressRulesChange),
 		relationIngress:            make(map[names.RelationTag]*remoteRelationData),
 		localRelationsChange:       make(chan *remoteRelationNetworkChange),
 		pollClock:                  clk,
@@ -236,6 +246,21 @@ func (fw *Firewaller) setUp() error {
 		return errors.Trace(err)
 	}
 
+	// Read the exposed application whitelist before any machines
+	// are flushed, so ports are never opened wider than allowed.
+	fw.exposedCIDRs, err = fw.exposedApplicationCIDRs()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	fw.rulesWatcher, err = fw.firewallerApi.WatchFirewallRules()
+	if errors.IsNotSupported(err) {
+		logger.Debugf("firewall rule changes will not be tracked: %v", err)
+	} else if err != nil {
+		return errors.Annotatef(err, "failed to start firewall rules watcher")
+	} else if err := fw.catacomb.Add(fw.rulesWatcher); err != nil {
+		return errors.Trace(err)
+	}
+
 	logger.Debugf("started watching opened port ranges for the model")
 	return nil
 }
@@ -246,6 +271,10 @@ func (fw *Firewaller) loop() error {
 	}
 	var reconciled bool
 	portsChange := fw.portsWatcher.Changes()
+	var rulesChange watcher.NotifyChannel
+	if fw.rulesWatcher != nil {
+		rulesChange = fw.rulesWatcher.Changes()
+	}
 	for {
 		select {
 		case <-fw.catacomb.Dying():
@@ -284,6 +313,13 @@ func (fw *Firewaller) loop() error {
 					return errors.Trace(err)
 				}
 			}
+		case _, ok := <-rulesChange:
+			if !ok {
+				return errors.New("firewall rules watcher closed")
+			}
+			if err := fw.firewallRulesChanged(); err != nil {
+				return errors.Trace(err)
+			}
 		case change, ok := <-fw.remoteRelationsWatcher.Changes():
 			if !ok {
 				return errors.New("remote relations watcher closed")
@@ -306,6 +342,7 @@ func (fw *Firewaller) loop() error {
 			}
 		case change := <-fw.exposedChange:
 			change.applicationd.exposed = change.exposed
+			change.applicationd.exposedEndpoints = change.exposedEndpoints
 			unitds := []*unitData{}
 			for _, unitd := range change.applicationd.unitds {
 				unitds = append(unitds, unitd)
@@ -313,6 +350,15 @@ func (fw *Firewaller) loop() error {
 			if err := fw.flushUnits(unitds); err != nil {
 				return errors.Annotate(err, "cannot change firewall ports")
 			}
+		case change := <-fw.egressRulesChange:
+			change.applicationd.egressRules = change.rules
+			unitds := []*unitData{}
+			for _, unitd := range change.applicationd.unitds {
+				unitds = append(unitds, unitd)
+			}
+			if err := fw.flushUnits(unitds); err != nil {
+				return errors.Annotate(err, "cannot change egress rules")
+			}
 		}
 	}
 }
@@ -347,6 +393,7 @@ func (fw *Firewaller) startMachine(tag names.MachineTag) error {
 		tag:          tag,
 		unitds:       make(map[names.UnitTag]*unitData),
 		ingressRules: make([]network.IngressRule, 0),
+		egressRules:  make([]network.EgressRule, 0),
 		definedPorts: make(map[names.UnitTag]portRanges),
 	}
 	m, err := machined.machine()
@@ -470,22 +517,28 @@ func (fw *Firewaller) startUnit(unit *firewaller.Unit, machineTag names.MachineT
 // startApplication creates a new data value for tracking details of the
 // application and starts watching the application for exposure changes.
 func (fw *Firewaller) startApplication(app *firewaller.Application) error {
-	exposed, err := app.IsExposed()
+	exposed, exposedEndpoints, err := app.ExposeInfo()
+	if err != nil {
+		return err
+	}
+	egressRules, err := app.EgressRules()
 	if err != nil {
 		return err
 	}
 	applicationd := &applicationData{
-		fw:          fw,
-		application: app,
-		exposed:     exposed,
-		unitds:      make(map[names.UnitTag]*unitData),
+		fw:               fw,
+		application:      app,
+		exposed:          exposed,
+		exposedEndpoints: exposedEndpoints,
+		egressRules:      egressRules,
+		unitds:           make(map[names.UnitTag]*unitData),
 	}
 	fw.applicationids[app.Tag()] = applicationd
 
 	err = catacomb.Invoke(catacomb.Plan{
 		Site: &applicationd.catacomb,
 		Work: func() error {
-			return applicationd.watchLoop(exposed)
+			return applicationd.watchLoop(exposed, exposedEndpoints, egressRules)
 		},
 	})
 	if err != nil {
@@ -591,6 +644,28 @@ func (fw *Firewaller) reconcileInstances() error {
 				return err
 			}
 		}
+
+		egressInstance, ok := envInstances[0].(instances.InstanceEgressFirewaller)
+		if !ok {
+			continue
+		}
+		initialEgress, err := egressInstance.EgressRules(fw.cloudCallContext, machineId)
+		if err != nil {
+			return err
+		}
+		toAllow, toDeny := diffEgressRules(initialEgress, machined.egressRules)
+		if len(toAllow) > 0 {
+			logger.Infof("opening instance egress rules %v for %q", toAllow, machined.tag)
+			if err := egressInstance.OpenEgress(fw.cloudCallContext, machineId, toAllow); err != nil {
+				return err
+			}
+		}
+		if len(toDeny) > 0 {
+			logger.Infof("closing instance egress rules %v for %q", toDeny, machined.tag)
+			if err := egressInstance.CloseEgress(fw.cloudCallContext, machineId, toDeny); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
@@ -746,9 +821,35 @@ func (fw *Firewaller) flushMachine(machined *machineData) error {
 	toOpen, toClose := diffRanges(machined.ingressRules, want)
 	machined.ingressRules = want
 	if fw.globalMode {
+		// Egress rules are only supported per instance; a global
+		// firewall is shared by every machine in the model.
 		return fw.flushGlobalPorts(toOpen, toClose)
 	}
-	return fw.flushInstancePorts(machined, toOpen, toClose)
+	if err := fw.flushInstancePorts(machined, toOpen, toClose); err != nil {
+		return errors.Trace(err)
+	}
+	wantEgress := fw.gatherEgressRules(machined)
+	toAllow, toDeny := diffEgressRules(machined.egressRules, wantEgress)
+	machined.egressRules = wantEgress
+	return fw.flushInstanceEgress(machined, toAllow, toDeny)
+}
+
+// gatherEgressRules returns the egress rules required by the
+// applications of the units on the specified machine.
+func (fw *Firewaller) gatherEgressRules(machined *machineData) []network.EgressRule {
+	var want []network.EgressRule
+	seen := set.NewStrings()
+	for _, unitd := range machined.unitds {
+		for _, rule := range unitd.applicationd.egressRules {
+			if seen.Contains(rule.String()) {
+				continue
+			}
+			seen.Add(rule.String())
+			want = append(want, rule)
+		}
+	}
+	network.SortEgressRules(want)
+	return want
 }
 
 // gatherIngressRules returns the ingress rules to open and close
@@ -764,9 +865,8 @@ func (fw *Firewaller) gatherIngressRules(machines ...*machineData) ([]network.In
 			}
 
 			cidrs := set.NewStrings()
-			// If the unit is exposed, allow access from everywhere.
 			if unitd.applicationd.exposed {
-				cidrs.Add("0.0.0.0/0")
+				cidrs = cidrs.Union(fw.exposedSourceCIDRs(unitd.applicationd))
 			} else {
 				// Not exposed, so add any ingress rules required by remote relations.
 				if err := fw.updateForRemoteRelationIngress(unitd.applicationd.application.Tag(), cidrs); err != nil {
@@ -789,6 +889,103 @@ func (fw *Firewaller) gatherIngressRules(machines ...*machineData) ([]network.In
 	return want, nil
 }
 
+// exposedSourceCIDRs returns the source CIDRs from which the ports of
+// the given exposed application may be reached. Opened ports are not
+// attributed to endpoints, so the sources of every exposed endpoint
+// apply to all of the application's ports. An endpoint without expose
+// settings, like an application exposed without any, may be reached
+// from the model's whitelist, or from everywhere if there isn't one.
+// The sources of endpoints with expose settings are restricted to the
+// model's whitelist, if there is one.
+func (fw *Firewaller) exposedSourceCIDRs(applicationd *applicationData) set.Strings {
+	cidrs := set.NewStrings()
+	useDefault := len(applicationd.exposedEndpoints) == 0
+	for _, exposed := range applicationd.exposedEndpoints {
+		if len(exposed.ExposeToSpaces) == 0 && len(exposed.ExposeToCIDRs) == 0 {
+			useDefault = true
+			continue
+		}
+		// The CIDRs include the subnets of any spaces; a space
+		// without subnets allows no access.
+		for _, cidr := range exposed.ExposeToCIDRs {
+			cidrs.Add(cidr)
+		}
+	}
+	if fw.exposedCIDRs.Size() > 0 {
+		cidrs = intersectCIDRs(cidrs, fw.exposedCIDRs)
+		if useDefault {
+			cidrs = cidrs.Union(fw.exposedCIDRs)
+		}
+	} else if useDefault {
+		cidrs.Add("0.0.0.0/0")
+	}
+	return cidrs
+}
+
+// intersectCIDRs returns the CIDRs covering the addresses that are in
+// both a CIDR of a and a CIDR of b. CIDRs either contain one another or
+// are disjoint, so the intersection of two is the narrower one.
+func intersectCIDRs(a, b set.Strings) set.Strings {
+	result := set.NewStrings()
+	for _, cidrA := range a.Values() {
+		_, netA, err := net.ParseCIDR(cidrA)
+		if err != nil {
+			continue
+		}
+		onesA, _ := netA.Mask.Size()
+		for _, cidrB := range b.Values() {
+			_, netB, err := net.ParseCIDR(cidrB)
+			if err != nil {
+				continue
+			}
+			onesB, _ := netB.Mask.Size()
+			switch {
+			case onesA >= onesB && netB.Contains(netA.IP):
+				result.Add(cidrA)
+			case onesB > onesA && netA.Contains(netB.IP):
+				result.Add(cidrB)
+			}
+		}
+	}
+	return result
+}
+
+// exposedApplicationCIDRs returns the whitelist of source CIDRs
+// from which exposed application ports may be reached.
+func (fw *Firewaller) exposedApplicationCIDRs() (set.Strings, error) {
+	rules, err := fw.firewallerApi.FirewallRules(string(params.JujuExposedApplicationRule))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cidrs := set.NewStrings()
+	for _, rule := range rules {
+		for _, cidr := range rule.WhitelistCIDRS {
+			cidrs.Add(cidr)
+		}
+	}
+	return cidrs, nil
+}
+
+// firewallRulesChanged re-reads the exposed application whitelist
+// and, if it has changed, updates the ports of every machine.
+func (fw *Firewaller) firewallRulesChanged() error {
+	cidrs, err := fw.exposedApplicationCIDRs()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if cidrs.Difference(fw.exposedCIDRs).IsEmpty() && fw.exposedCIDRs.Difference(cidrs).IsEmpty() {
+		return nil
+	}
+	logger.Debugf("exposed application whitelist changed %v => %v", fw.exposedCIDRs.SortedValues(), cidrs.SortedValues())
+	fw.exposedCIDRs = cidrs
+	for _, machined := range fw.machineds {
+		if err := fw.flushMachine(machined); err != nil {
+			return errors.Annotate(err, "cannot change firewall ports")
+		}
+	}
+	return nil
+}
+
 // TODO(wallyworld) - consider making this configurable.
 const maxAllowedCIDRS = 20
 
@@ -946,6 +1143,57 @@ func (fw *Firewaller) flushInstancePorts(machined *machineData, toOpen, toClose
 	return nil
 }
 
+// flushInstanceEgress opens and closes egress rules on the machine's
+// instance, if the provider supports egress security group rules.
+func (fw *Firewaller) flushInstanceEgress(machined *machineData, toOpen, toClose []network.EgressRule) (err error) {
+	defer func() {
+		if params.IsCodeNotFound(err) {
+			err = nil
+		}
+	}()
+
+	logger.Debugf("flush instance egress: to open %v, to close %v", toOpen, toClose)
+	if len(toOpen) == 0 && len(toClose) == 0 {
+		return nil
+	}
+	m, err := machined.machine()
+	if err != nil {
+		return err
+	}
+	machineId := machined.tag.Id()
+	instanceId, err := m.InstanceId()
+	if params.IsCodeNotProvisioned(err) {
+		// Not provisioned yet, so nothing to do for this instance
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	envInstances, err := fw.environInstances.Instances(fw.cloudCallContext, []instance.Id{instanceId})
+	if err != nil {
+		return err
+	}
+	fwInstance, ok := envInstances[0].(instances.InstanceEgressFirewaller)
+	if !ok {
+		logger.Debugf("instance of type %T doesn't support egress rules", envInstances[0])
+		return nil
+	}
+
+	if len(toOpen) > 0 {
+		if err := fwInstance.OpenEgress(fw.cloudCallContext, machineId, toOpen); err != nil {
+			return err
+		}
+		logger.Infof("opened egress rules %v on %q", toOpen, machined.tag)
+	}
+	if len(toClose) > 0 {
+		if err := fwInstance.CloseEgress(fw.cloudCallContext, machineId, toClose); err != nil {
+			return err
+		}
+		logger.Infof("closed egress rules %v on %q", toClose, machined.tag)
+	}
+	return nil
+}
+
 // machineLifeChanged starts watching new machines when the firewaller
 // is starting, or when new machines come to life, and stops watching
 // machines that are dying.
@@ -1040,6 +1288,7 @@ type machineData struct {
 	tag          names.MachineTag
 	unitds       map[names.UnitTag]*unitData
 	ingressRules []network.IngressRule
+	egressRules  []network.EgressRule
 	// ports defined by units on this machine
 	definedPorts map[names.UnitTag]portRanges
 }
@@ -1089,23 +1338,38 @@ type unitData struct {
 	machined     *machineData
 }
 
-// exposedChange contains the changed exposed flag for one specific application.
+// exposedChange contains the changed exposed flag and expose settings
+// for one specific application.
 type exposedChange struct {
-	applicationd *applicationData
-	exposed      bool
+	applicationd     *applicationData
+	exposed          bool
+	exposedEndpoints map[string]params.ExposedEndpoint
 }
 
-// applicationData holds application details and watches exposure changes.
-type applicationData struct {
-	catacomb    catacomb.Catacomb
-	fw          *Firewaller
-	application *firewaller.Application
-	exposed     bool
-	unitds      map[names.UnitTag]*unitData
+// egressRulesChange contains the changed egress rules for one specific
+// application.
+type egressRulesChange struct {
+	applicationd *applicationData
+	rules        []network.EgressRule
 }
 
-// watchLoop watches the application's exposed flag for changes.
-func (ad *applicationData) watchLoop(exposed bool) error {
+// applicationData holds application details and watches exposure and
+// egress rule changes.
+type applicationData struct {
+	catacomb         catacomb.Catacomb
+	fw               *Firewaller
+	application      *firewaller.Application
+	exposed          bool
+	exposedEndpoints map[string]params.ExposedEndpoint
+	egressRules      []network.EgressRule
+	unitds           map[names.UnitTag]*unitData
+}
+
+// watchLoop watches the application's exposed flag, expose settings
+// and egress rules for changes.
+func (ad *applicationData) watchLoop(
+	exposed bool, exposedEndpoints map[string]params.ExposedEndpoint, egressRules []network.EgressRule,
+) error {
 	appWatcher, err := ad.application.Watch()
 	if err != nil {
 		if params.IsCodeNotFound(err) {
@@ -1124,25 +1388,45 @@ func (ad *applicationData) watchLoop(exposed bool) error {
 			if !ok {
 				return errors.New("application watcher closed")
 			}
-			change, err := ad.application.IsExposed()
+			rules, err := ad.application.EgressRules()
 			if err != nil {
 				if errors.IsNotFound(err) {
-					logger.Debugf("application(%q).IsExposed() returned NotFound: %v", ad.application.Name(), err)
+					logger.Debugf("application(%q).EgressRules() returned NotFound: %v", ad.application.Name(), err)
 					return nil
 				}
 				return errors.Trace(err)
 			}
-			if change == exposed {
-				logger.Tracef("application(%q).IsExposed() == %v (unchanged)", ad.application.Name(), exposed)
+			if !egressRulesEqual(rules, egressRules) {
+				logger.Tracef("application(%q).EgressRules() changed %v => %v", ad.application.Name(), egressRules, rules)
+				egressRules = rules
+				select {
+				case <-ad.catacomb.Dying():
+					return ad.catacomb.ErrDying()
+				case ad.fw.egressRulesChange <- &egressRulesChange{ad, rules}:
+				}
+			}
+
+			change, endpoints, err := ad.application.ExposeInfo()
+			if err != nil {
+				if errors.IsNotFound(err) {
+					logger.Debugf("application(%q).ExposeInfo() returned NotFound: %v", ad.application.Name(), err)
+					return nil
+				}
+				return errors.Trace(err)
+			}
+			if change == exposed && reflect.DeepEqual(endpoints, exposedEndpoints) {
+				logger.Tracef("application(%q).ExposeInfo() == %v, %v (unchanged)", ad.application.Name(), exposed, exposedEndpoints)
 				continue
 			}
-			logger.Tracef("application(%q).IsExposed() changed %v => %v", ad.application.Name(), exposed, change)
+			logger.Tracef("application(%q).ExposeInfo() changed %v, %v => %v, %v",
+				ad.application.Name(), exposed, exposedEndpoints, change, endpoints)
 
 			exposed = change
+			exposedEndpoints = endpoints
 			select {
 			case <-ad.catacomb.Dying():
 				return ad.catacomb.ErrDying()
-			case ad.fw.exposedChange <- &exposedChange{ad, change}:
+			case ad.fw.exposedChange <- &exposedChange{ad, change, endpoints}:
 			}
 		}
 	}
@@ -1234,6 +1518,39 @@ func diffRanges(currentRules, wantedRules []network.IngressRule) (toOpen, toClos
 	return toOpen, toClose
 }
 
+// diffEgressRules returns the egress rules which must be opened and
+// closed to move from the current to the wanted rules.
+func diffEgressRules(currentRules, wantedRules []network.EgressRule) (toOpen, toClose []network.EgressRule) {
+	current := make(map[string]network.EgressRule)
+	for _, rule := range currentRules {
+		current[rule.String()] = rule
+	}
+	wanted := make(map[string]network.EgressRule)
+	for _, rule := range wantedRules {
+		wanted[rule.String()] = rule
+	}
+	for key, rule := range wanted {
+		if _, ok := current[key]; !ok {
+			toOpen = append(toOpen, rule)
+		}
+	}
+	for key, rule := range current {
+		if _, ok := wanted[key]; !ok {
+			toClose = append(toClose, rule)
+		}
+	}
+	network.SortEgressRules(toOpen)
+	network.SortEgressRules(toClose)
+	return toOpen, toClose
+}
+
+// egressRulesEqual reports whether a and b hold the same egress rules,
+// regardless of order.
+func egressRulesEqual(a, b []network.EgressRule) bool {
+	toOpen, toClose := diffEgressRules(a, b)
+	return len(toOpen) == 0 && len(toClose) == 0
+}
+
 // relationLifeChanged manages the workers to process ingress changes for
 // the specified relation.
 func (fw *Firewaller) relationLifeChanged(tag names.RelationTag) error {
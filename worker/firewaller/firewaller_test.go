@@ -129,6 +129,33 @@ func (s *firewallerBaseSuite) assertPorts(c *gc.C, inst instances.Instance, mach
 	}
 }
 
+// assertEgressRules retrieves the egress rules of the instance and compares
+// them to the expected.
+func (s *firewallerBaseSuite) assertEgressRules(c *gc.C, inst instances.Instance, machineId string, expected []network.EgressRule) {
+	fwInst, ok := inst.(instances.InstanceEgressFirewaller)
+	c.Assert(ok, gc.Equals, true)
+
+	start := time.Now()
+	for {
+		s.BackingState.StartSync()
+		got, err := fwInst.EgressRules(s.callCtx, machineId)
+		if err != nil {
+			c.Fatal(err)
+			return
+		}
+		network.SortEgressRules(expected)
+		if reflect.DeepEqual(got, expected) {
+			c.Succeed()
+			return
+		}
+		if time.Since(start) > coretesting.LongWait {
+			c.Fatalf("timed out: expected %q; got %q", expected, got)
+			return
+		}
+		time.Sleep(coretesting.ShortWait)
+	}
+}
+
 // assertEnvironPorts retrieves the open ports of environment and compares them
 // to the expected.
 func (s *firewallerBaseSuite) assertEnvironPorts(c *gc.C, expected []network.IngressRule) {
@@ -537,6 +564,162 @@ func (s *InstanceModeSuite) TestSetClearExposedApplication(c *gc.C) {
 	s.assertPorts(c, inst, m.Id(), nil)
 }
 
+func (s *InstanceModeSuite) TestExposedApplicationWhitelist(c *gc.C) {
+	fwRules := state.NewFirewallRules(s.State)
+	err := fwRules.Save(state.FirewallRule{
+		WellKnownService: state.JujuExposedApplicationRule,
+		WhitelistCIDRs:   []string{"192.168.1.0/24"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	fw := s.newFirewaller(c)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	app := s.AddTestingApplication(c, "wordpress", s.charm)
+	err = app.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	u, m := s.addUnit(c, app)
+	inst := s.startInstance(c, m)
+	err = u.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertPorts(c, inst, m.Id(), []network.IngressRule{
+		network.MustNewIngressRule("tcp", 80, 80, "192.168.1.0/24"),
+	})
+
+	// Changing the whitelist updates the opened ports.
+	err = fwRules.Save(state.FirewallRule{
+		WellKnownService: state.JujuExposedApplicationRule,
+		WhitelistCIDRs:   []string{"10.0.0.0/8", "192.168.2.0/24"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertPorts(c, inst, m.Id(), []network.IngressRule{
+		network.MustNewIngressRule("tcp", 80, 80, "10.0.0.0/8", "192.168.2.0/24"),
+	})
+}
+
+func (s *InstanceModeSuite) TestExposedEndpoints(c *gc.C) {
+	fw := s.newFirewaller(c)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	app := s.AddTestingApplication(c, "wordpress", s.charm)
+	u, m := s.addUnit(c, app)
+	inst := s.startInstance(c, m)
+	err := u.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Exposing an endpoint to a CIDR opens the ports to that CIDR only.
+	err = app.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"url": {ExposeToCIDRs: []string{"10.0.0.0/24"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertPorts(c, inst, m.Id(), []network.IngressRule{
+		network.MustNewIngressRule("tcp", 80, 80, "10.0.0.0/24"),
+	})
+
+	// Opened ports are not attributed to endpoints, so the sources of
+	// every exposed endpoint apply.
+	err = app.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"db": {ExposeToCIDRs: []string{"192.168.1.0/24"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertPorts(c, inst, m.Id(), []network.IngressRule{
+		network.MustNewIngressRule("tcp", 80, 80, "10.0.0.0/24", "192.168.1.0/24"),
+	})
+
+	// An endpoint exposed without settings may be reached from anywhere.
+	err = app.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"cache": {},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertPorts(c, inst, m.Id(), []network.IngressRule{
+		network.MustNewIngressRule("tcp", 80, 80, "0.0.0.0/0", "10.0.0.0/24", "192.168.1.0/24"),
+	})
+
+	// Unexposing clears the settings and closes the ports.
+	err = app.ClearExposed()
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertPorts(c, inst, m.Id(), nil)
+}
+
+func (s *InstanceModeSuite) TestExposedEndpointsWhitelist(c *gc.C) {
+	fwRules := state.NewFirewallRules(s.State)
+	err := fwRules.Save(state.FirewallRule{
+		WellKnownService: state.JujuExposedApplicationRule,
+		WhitelistCIDRs:   []string{"10.0.0.0/8"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	fw := s.newFirewaller(c)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	app := s.AddTestingApplication(c, "wordpress", s.charm)
+	u, m := s.addUnit(c, app)
+	inst := s.startInstance(c, m)
+	err = u.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Endpoint CIDRs wider than the whitelist are narrowed to it, and
+	// those outside it are dropped.
+	err = app.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"url": {ExposeToCIDRs: []string{"0.0.0.0/0", "192.168.1.0/24"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertPorts(c, inst, m.Id(), []network.IngressRule{
+		network.MustNewIngressRule("tcp", 80, 80, "10.0.0.0/8"),
+	})
+
+	// Endpoint CIDRs within the whitelist are kept.
+	err = app.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"url": {ExposeToCIDRs: []string{"10.1.0.0/16", "192.168.1.0/24"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertPorts(c, inst, m.Id(), []network.IngressRule{
+		network.MustNewIngressRule("tcp", 80, 80, "10.1.0.0/16"),
+	})
+
+	// An endpoint CIDR entirely outside the whitelist opens nothing.
+	err = app.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"url": {ExposeToCIDRs: []string{"192.168.1.0/24"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertPorts(c, inst, m.Id(), nil)
+}
+
+func (s *InstanceModeSuite) TestSetClearEgressRules(c *gc.C) {
+	fw := s.newFirewaller(c)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	app := s.AddTestingApplication(c, "wordpress", s.charm)
+	_, m := s.addUnit(c, app)
+	inst := s.startInstance(c, m)
+	s.assertEgressRules(c, inst, m.Id(), nil)
+
+	rules := []network.EgressRule{
+		network.MustNewEgressRule("tcp", 443, 443, "10.0.0.0/8"),
+		network.MustNewEgressRule("udp", 53, 53),
+	}
+	err := app.SetEgressRules(rules)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertEgressRules(c, inst, m.Id(), rules)
+
+	err = app.SetEgressRules(rules[1:])
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertEgressRules(c, inst, m.Id(), rules[1:])
+
+	err = app.SetEgressRules(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertEgressRules(c, inst, m.Id(), nil)
+}
+
 func (s *InstanceModeSuite) TestRemoveUnit(c *gc.C) {
 	fw := s.newFirewaller(c)
 	defer statetesting.AssertKillAndWait(c, fw)
@@ -0,0 +1,127 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package instancemutater
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs"
+)
+
+// profileCleaner removes the charm lxd profiles, named juju-<model>-<app>-<rev>,
+// that are no longer used by any instance, such as those left behind by
+// charm upgrades.
+//
+// A profile is only removed once it has been found unused by two
+// consecutive passes, so that a profile just written for a machine is not
+// removed before it can be applied to the machine's instance.
+type profileCleaner struct {
+	cleaner environs.LXDProfileCleaner
+	logger  Logger
+	dryRun  bool
+
+	mu sync.Mutex
+	// candidates holds the profiles found unused by the last pass.
+	candidates set.Strings
+	lastRun    time.Time
+	unused     []string
+	removed    []string
+	err        error
+}
+
+func newProfileCleaner(cleaner environs.LXDProfileCleaner, logger Logger, dryRun bool) *profileCleaner {
+	return &profileCleaner{
+		cleaner:    cleaner,
+		logger:     logger,
+		dryRun:     dryRun,
+		candidates: set.NewStrings(),
+	}
+}
+
+// cleanup runs a single pass, removing the profiles found unused by both
+// this pass and the last. Profiles that cannot be removed are left for
+// the next pass, failing to clean up is never fatal to the worker.
+func (c *profileCleaner) cleanup(now time.Time) {
+	unused, err := c.cleaner.UnusedLXDProfileNames()
+	if err != nil {
+		c.logger.Warningf("cannot list unused lxd profiles: %v", err)
+		c.record(now, nil, nil, errors.Trace(err))
+		return
+	}
+
+	var removed []string
+	for _, name := range unused {
+		if !c.candidates.Contains(name) {
+			continue
+		}
+		if c.dryRun {
+			c.logger.Debugf("would remove unused lxd profile %q", name)
+			removed = append(removed, name)
+			continue
+		}
+		if err := c.cleaner.DeleteLXDProfile(name); err != nil {
+			// The profile may have been applied since it was
+			// listed, in which case lxd refuses to remove it.
+			c.logger.Debugf("cannot remove unused lxd profile %q: %v", name, err)
+			continue
+		}
+		c.logger.Debugf("removed unused lxd profile %q", name)
+		removed = append(removed, name)
+	}
+
+	// Profiles removed, or reported as such in a dry run, are not
+	// candidates again until they are seen unused by another pass.
+	candidates := set.NewStrings(unused...)
+	if !c.dryRun {
+		for _, name := range removed {
+			candidates.Remove(name)
+		}
+	}
+	c.candidates = candidates
+	c.record(now, unused, removed, nil)
+}
+
+func (c *profileCleaner) record(now time.Time, unused, removed []string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastRun = now
+	c.unused = unused
+	c.removed = removed
+	c.err = err
+}
+
+// report returns the outcome of the last pass, for introspection.
+func (c *profileCleaner) report() map[string]interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	result := map[string]interface{}{
+		"dry-run": c.dryRun,
+	}
+	if c.lastRun.IsZero() {
+		return result
+	}
+	removedKey := "removed"
+	if c.dryRun {
+		removedKey = "would-remove"
+	}
+	result["last-run"] = c.lastRun.UTC().Format(time.RFC3339)
+	result["unused"] = sortedCopy(c.unused)
+	result[removedKey] = sortedCopy(c.removed)
+	if c.err != nil {
+		result["error"] = c.err.Error()
+	}
+	return result
+}
+
+func sortedCopy(values []string) []string {
+	result := make([]string, len(values))
+	copy(result, values)
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,121 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package instancemutater_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/instancemutater"
+)
+
+type profileCleanerSuite struct {
+	testing.IsolationSuite
+
+	cleaner *fakeProfileCleaner
+	now     time.Time
+}
+
+var _ = gc.Suite(&profileCleanerSuite{})
+
+func (s *profileCleanerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.cleaner = &fakeProfileCleaner{}
+	s.now = time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+}
+
+func (s *profileCleanerSuite) newCleaner(dryRun bool) instancemutater.ProfileCleaner {
+	return instancemutater.NewProfileCleaner(s.cleaner, loggo.GetLogger("test"), dryRun)
+}
+
+func (s *profileCleanerSuite) TestRemovesProfilesUnusedByTwoPasses(c *gc.C) {
+	cleaner := s.newCleaner(false)
+
+	s.cleaner.unused = []string{"juju-default-mysql-1", "juju-default-lxd-profile-5"}
+	cleaner.Cleanup(s.now)
+	c.Check(s.cleaner.deleted, gc.HasLen, 0)
+
+	// The mysql profile has since been applied to an instance.
+	s.cleaner.unused = []string{"juju-default-lxd-profile-5"}
+	cleaner.Cleanup(s.now.Add(time.Hour))
+	c.Check(s.cleaner.deleted, jc.DeepEquals, []string{"juju-default-lxd-profile-5"})
+
+	c.Check(cleaner.Report(), jc.DeepEquals, map[string]interface{}{
+		"dry-run":  false,
+		"last-run": "2019-06-01T13:00:00Z",
+		"unused":   []string{"juju-default-lxd-profile-5"},
+		"removed":  []string{"juju-default-lxd-profile-5"},
+	})
+}
+
+func (s *profileCleanerSuite) TestDeleteFailureRetried(c *gc.C) {
+	cleaner := s.newCleaner(false)
+
+	s.cleaner.unused = []string{"juju-default-mysql-1"}
+	s.cleaner.deleteErr = errors.New("profile in use")
+	cleaner.Cleanup(s.now)
+	cleaner.Cleanup(s.now.Add(time.Hour))
+	c.Check(s.cleaner.deleted, gc.HasLen, 0)
+
+	s.cleaner.deleteErr = nil
+	cleaner.Cleanup(s.now.Add(2 * time.Hour))
+	c.Check(s.cleaner.deleted, jc.DeepEquals, []string{"juju-default-mysql-1"})
+}
+
+func (s *profileCleanerSuite) TestDryRun(c *gc.C) {
+	cleaner := s.newCleaner(true)
+	c.Check(cleaner.Report(), jc.DeepEquals, map[string]interface{}{
+		"dry-run": true,
+	})
+
+	s.cleaner.unused = []string{"juju-default-mysql-1"}
+	cleaner.Cleanup(s.now)
+	cleaner.Cleanup(s.now.Add(time.Hour))
+	c.Check(s.cleaner.deleted, gc.HasLen, 0)
+
+	c.Check(cleaner.Report(), jc.DeepEquals, map[string]interface{}{
+		"dry-run":      true,
+		"last-run":     "2019-06-01T13:00:00Z",
+		"unused":       []string{"juju-default-mysql-1"},
+		"would-remove": []string{"juju-default-mysql-1"},
+	})
+}
+
+func (s *profileCleanerSuite) TestListError(c *gc.C) {
+	cleaner := s.newCleaner(false)
+
+	s.cleaner.unusedErr = errors.New("boom")
+	cleaner.Cleanup(s.now)
+	c.Check(cleaner.Report(), jc.DeepEquals, map[string]interface{}{
+		"dry-run":  false,
+		"last-run": "2019-06-01T12:00:00Z",
+		"unused":   []string{},
+		"removed":  []string{},
+		"error":    "boom",
+	})
+}
+
+type fakeProfileCleaner struct {
+	unused    []string
+	unusedErr error
+	deleted   []string
+	deleteErr error
+}
+
+func (f *fakeProfileCleaner) UnusedLXDProfileNames() ([]string, error) {
+	return f.unused, f.unusedErr
+}
+
+func (f *fakeProfileCleaner) DeleteLXDProfile(name string) error {
+	if f.deleteErr != nil {
+		return f.deleteErr
+	}
+	f.deleted = append(f.deleted, name)
+	return nil
+}
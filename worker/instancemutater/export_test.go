@@ -4,7 +4,11 @@
 package instancemutater
 
 import (
+	"time"
+
 	"github.com/juju/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
 	"github.com/juju/juju/api/instancemutater"
 	"github.com/juju/juju/core/lxdprofile"
 	"github.com/juju/juju/environs"
@@ -14,7 +18,7 @@ import (
 
 func NewMachineContext(
 	logger Logger,
-	broker environs.LXDProfiler,
+	broker environs.MutaterBroker,
 	machine instancemutater.MutaterMachine,
 	fn RequiredLXDProfilesFunc,
 	id string,
@@ -31,16 +35,19 @@ func NewMachineContext(
 		logger:     logger,
 		machineApi: machine,
 		id:         id,
+		metrics:    newMetrics("", ""),
 	}
 }
 
+var EnvironRequiredLXDProfiles = environRequiredLXDProfiles
+
 func NewEnvironTestWorker(config Config, ctxFn RequiredMutaterContextFunc) (worker.Worker, error) {
-	config.GetMachineWatcher = config.Facade.WatchMachines
+	config.GetMachineWatcher = config.Facade.WatchLXDProfileMachines
 	config.GetRequiredLXDProfiles = func(modelName string) []string {
-		return []string{"default", "juju-" + modelName}
+		return environRequiredLXDProfiles(config.Broker, modelName)
 	}
 	config.GetRequiredContext = ctxFn
-	return newWorker(config)
+	return newWorker(config, "environ")
 }
 
 func NewContainerTestWorker(config Config, ctxFn RequiredMutaterContextFunc) (worker.Worker, error) {
@@ -51,7 +58,7 @@ func NewContainerTestWorker(config Config, ctxFn RequiredMutaterContextFunc) (wo
 	config.GetRequiredLXDProfiles = func(_ string) []string { return []string{"default"} }
 	config.GetMachineWatcher = m.WatchContainers
 	config.GetRequiredContext = ctxFn
-	return newWorker(config)
+	return newWorker(config, "container")
 }
 
 func ProcessMachineProfileChanges(m *MutaterMachine, info *instancemutater.UnitProfileInfo) error {
@@ -62,6 +69,41 @@ func GatherProfileData(m *MutaterMachine, info *instancemutater.UnitProfileInfo)
 	return m.gatherProfileData(info)
 }
 
+func ReconcileProfiles(m *MutaterMachine) (bool, error) {
+	return m.reconcileProfiles()
+}
+
 func VerifyCurrentProfiles(m *MutaterMachine, instId string, expectedProfiles []string) (bool, error) {
-	return m.verifyCurrentProfiles(instId, expectedProfiles)
+	_, verified, err := m.verifyCurrentProfiles(instId, expectedProfiles)
+	return verified, err
+}
+
+// MachineMetrics returns the values of the counters in the machine's
+// metrics.
+func MachineMetrics(m *MutaterMachine) (applied, verificationFailures, brokerErrors float64) {
+	return testutil.ToFloat64(m.metrics.profilesApplied),
+		testutil.ToFloat64(m.metrics.verificationFailures),
+		testutil.ToFloat64(m.metrics.brokerErrors)
+}
+
+// ProfileCleaner exposes the worker's removal of unused lxd profiles.
+type ProfileCleaner interface {
+	Cleanup(now time.Time)
+	Report() map[string]interface{}
+}
+
+type profileCleanerShim struct {
+	*profileCleaner
+}
+
+func (s profileCleanerShim) Cleanup(now time.Time) {
+	s.cleanup(now)
+}
+
+func (s profileCleanerShim) Report() map[string]interface{} {
+	return s.report()
+}
+
+func NewProfileCleaner(cleaner environs.LXDProfileCleaner, logger Logger, dryRun bool) ProfileCleaner {
+	return profileCleanerShim{newProfileCleaner(cleaner, logger, dryRun)}
 }
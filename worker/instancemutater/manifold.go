@@ -5,17 +5,19 @@ package instancemutater
 
 import (
 	"github.com/juju/errors"
+	"github.com/prometheus/client_golang/prometheus"
 	worker "gopkg.in/juju/worker.v1"
 	"gopkg.in/juju/worker.v1/dependency"
 
 	"github.com/juju/juju/agent"
 	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/environs"
 )
 
 //go:generate mockgen -package mocks -destination mocks/worker_mock.go gopkg.in/juju/worker.v1 Worker
 //go:generate mockgen -package mocks -destination mocks/dependency_mock.go gopkg.in/juju/worker.v1/dependency Context
-//go:generate mockgen -package mocks -destination mocks/environs_mock.go github.com/juju/juju/environs Environ,LXDProfiler,InstanceBroker
+//go:generate mockgen -package mocks -destination mocks/environs_mock.go github.com/juju/juju/environs Environ,LXDProfiler,InstanceBroker,LXDProfileValidator,MutaterBroker
 //go:generate mockgen -package mocks -destination mocks/base_mock.go github.com/juju/juju/api/base APICaller
 //go:generate mockgen -package mocks -destination mocks/agent_mock.go github.com/juju/juju/agent Agent,Config
 
@@ -28,6 +30,10 @@ type ModelManifoldConfig struct {
 	Logger    Logger
 	NewWorker func(Config) (worker.Worker, error)
 	NewClient func(base.APICaller) InstanceMutaterAPI
+
+	// PrometheusRegisterer, if non-nil, is used to register the
+	// worker's metrics.
+	PrometheusRegisterer prometheus.Registerer
 }
 
 // Validate validates the manifold configuration.
@@ -57,13 +63,13 @@ func (config ModelManifoldConfig) newWorker(environ environs.Environ, apiCaller
 	if err := config.Validate(); err != nil {
 		return nil, errors.Trace(err)
 	}
-	// If we don't have a LXDProfiler, we should uninstall the worker as quickly
-	// as possible.
-	broker, ok := environ.(environs.LXDProfiler)
+	// If we don't have a MutaterBroker, we should uninstall the worker as
+	// quickly as possible.
+	broker, ok := environ.(environs.MutaterBroker)
 	if !ok {
-		// If we don't have an LXDProfiler broker, there is no need to
+		// If we don't have a MutaterBroker, there is no need to
 		// run this worker.
-		config.Logger.Debugf("Uninstalling worker because the broker is not a LXDProfiler %T", environ)
+		config.Logger.Debugf("Uninstalling worker because the broker is not a MutaterBroker %T", environ)
 		return nil, dependency.ErrUninstall
 	}
 	facade := config.NewClient(apiCaller)
@@ -74,6 +80,8 @@ func (config ModelManifoldConfig) newWorker(environ environs.Environ, apiCaller
 		Broker:      broker,
 		AgentConfig: agentConfig,
 		Tag:         agentConfig.Tag(),
+
+		PrometheusRegisterer: config.PrometheusRegisterer,
 	}
 
 	w, err := config.NewWorker(cfg)
@@ -142,6 +150,14 @@ type MachineManifoldConfig struct {
 	Logger    Logger
 	NewWorker func(Config) (worker.Worker, error)
 	NewClient func(base.APICaller) InstanceMutaterAPI
+
+	// ContainerBrokers holds the brokers used to mutate the machine's
+	// containers of types other than lxd, keyed by container type.
+	ContainerBrokers map[instance.ContainerType]environs.MutaterBroker
+
+	// PrometheusRegisterer, if non-nil, is used to register the
+	// worker's metrics.
+	PrometheusRegisterer prometheus.Registerer
 }
 
 // Validate validates the manifold configuration.
@@ -171,13 +187,13 @@ func (config MachineManifoldConfig) newWorker(instanceBroker environs.InstanceBr
 	if err := config.Validate(); err != nil {
 		return nil, errors.Trace(err)
 	}
-	// If we don't have a LXDProfiler, we should uninstall the worker as quickly
-	// as possible.
-	broker, ok := instanceBroker.(environs.LXDProfiler)
+	// If we don't have a MutaterBroker, we should uninstall the worker as
+	// quickly as possible.
+	broker, ok := instanceBroker.(environs.MutaterBroker)
 	if !ok {
-		// If we don't have an LXDProfiler broker, there is no need to
+		// If we don't have a MutaterBroker, there is no need to
 		// run this worker.
-		config.Logger.Debugf("Uninstalling worker because the broker is not a LXDProfiler %T", instanceBroker)
+		config.Logger.Debugf("Uninstalling worker because the broker is not a MutaterBroker %T", instanceBroker)
 		return nil, dependency.ErrUninstall
 	}
 	facade := config.NewClient(apiCaller)
@@ -188,6 +204,9 @@ func (config MachineManifoldConfig) newWorker(instanceBroker environs.InstanceBr
 		Broker:      broker,
 		AgentConfig: agentConfig,
 		Tag:         agentConfig.Tag(),
+
+		ContainerBrokers:     config.ContainerBrokers,
+		PrometheusRegisterer: config.PrometheusRegisterer,
 	}
 
 	w, err := config.NewWorker(cfg)
@@ -0,0 +1,89 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package instancemutater
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	metricsNamespace = "juju"
+	metricsSubsystem = "instancemutater"
+
+	modelLabel  = "model"
+	workerLabel = "worker"
+)
+
+// metrics is a prometheus.Collector that collects metrics about the lxd
+// profile changes made by an instancemutater worker.
+type metrics struct {
+	profilesApplied      prometheus.Counter
+	applyDuration        prometheus.Histogram
+	verificationFailures prometheus.Counter
+	brokerErrors         prometheus.Counter
+}
+
+// newMetrics returns a new metrics collector, with its metrics labelled
+// with the given model UUID and kind of worker, so that the collectors
+// of several workers may be registered together.
+func newMetrics(modelUUID, workerKind string) *metrics {
+	labels := prometheus.Labels{
+		modelLabel:  modelUUID,
+		workerLabel: workerKind,
+	}
+	return &metrics{
+		profilesApplied: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace:   metricsNamespace,
+			Subsystem:   metricsSubsystem,
+			Name:        "profiles_applied_total",
+			Help:        "Total number of machines whose lxd profiles have been changed.",
+			ConstLabels: labels,
+		}),
+		applyDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace:   metricsNamespace,
+			Subsystem:   metricsSubsystem,
+			Name:        "apply_duration_seconds",
+			Help:        "Time taken by the broker to apply lxd profiles to a machine.",
+			ConstLabels: labels,
+			Buckets:     prometheus.ExponentialBuckets(0.1, 2, 10),
+		}),
+		verificationFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace:   metricsNamespace,
+			Subsystem:   metricsSubsystem,
+			Name:        "verification_failures_total",
+			Help:        "Total number of machines found with lxd profiles that have drifted from those expected.",
+			ConstLabels: labels,
+		}),
+		brokerErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace:   metricsNamespace,
+			Subsystem:   metricsSubsystem,
+			Name:        "broker_errors_total",
+			Help:        "Total number of errors returned by the broker when reading or applying lxd profiles.",
+			ConstLabels: labels,
+		}),
+	}
+}
+
+// Describe is part of the prometheus.Collector interface.
+func (m *metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.profilesApplied.Describe(ch)
+	m.applyDuration.Describe(ch)
+	m.verificationFailures.Describe(ch)
+	m.brokerErrors.Describe(ch)
+}
+
+// Collect is part of the prometheus.Collector interface.
+func (m *metrics) Collect(ch chan<- prometheus.Metric) {
+	m.profilesApplied.Collect(ch)
+	m.applyDuration.Collect(ch)
+	m.verificationFailures.Collect(ch)
+	m.brokerErrors.Collect(ch)
+}
+
+// brokerError counts the broker error and returns it, recorded as a
+// brokerError.
+func (m *metrics) brokerError(err error) error {
+	m.brokerErrors.Inc()
+	return &brokerError{err}
+}
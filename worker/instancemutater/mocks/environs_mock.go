@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/juju/juju/environs (interfaces: Environ,LXDProfiler,InstanceBroker)
+// Source: github.com/juju/juju/environs (interfaces: Environ,LXDProfiler,InstanceBroker,LXDProfileValidator,MutaterBroker)
 
 // Package mocks is a generated GoMock package.
 package mocks
@@ -460,3 +460,87 @@ func (mr *MockInstanceBrokerMockRecorder) StopInstances(arg0 interface{}, arg1 .
 	varargs := append([]interface{}{arg0}, arg1...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopInstances", reflect.TypeOf((*MockInstanceBroker)(nil).StopInstances), varargs...)
 }
+
+// MockLXDProfileValidator is a mock of LXDProfileValidator interface
+type MockLXDProfileValidator struct {
+	ctrl     *gomock.Controller
+	recorder *MockLXDProfileValidatorMockRecorder
+}
+
+// MockLXDProfileValidatorMockRecorder is the mock recorder for MockLXDProfileValidator
+type MockLXDProfileValidatorMockRecorder struct {
+	mock *MockLXDProfileValidator
+}
+
+// NewMockLXDProfileValidator creates a new mock instance
+func NewMockLXDProfileValidator(ctrl *gomock.Controller) *MockLXDProfileValidator {
+	mock := &MockLXDProfileValidator{ctrl: ctrl}
+	mock.recorder = &MockLXDProfileValidatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockLXDProfileValidator) EXPECT() *MockLXDProfileValidatorMockRecorder {
+	return m.recorder
+}
+
+// ValidateLXDProfile mocks base method
+func (m *MockLXDProfileValidator) ValidateLXDProfile(arg0 lxdprofile.Profile) error {
+	ret := m.ctrl.Call(m, "ValidateLXDProfile", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateLXDProfile indicates an expected call of ValidateLXDProfile
+func (mr *MockLXDProfileValidatorMockRecorder) ValidateLXDProfile(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateLXDProfile", reflect.TypeOf((*MockLXDProfileValidator)(nil).ValidateLXDProfile), arg0)
+}
+
+// MockMutaterBroker is a mock of MutaterBroker interface
+type MockMutaterBroker struct {
+	ctrl     *gomock.Controller
+	recorder *MockMutaterBrokerMockRecorder
+}
+
+// MockMutaterBrokerMockRecorder is the mock recorder for MockMutaterBroker
+type MockMutaterBrokerMockRecorder struct {
+	mock *MockMutaterBroker
+}
+
+// NewMockMutaterBroker creates a new mock instance
+func NewMockMutaterBroker(ctrl *gomock.Controller) *MockMutaterBroker {
+	mock := &MockMutaterBroker{ctrl: ctrl}
+	mock.recorder = &MockMutaterBrokerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockMutaterBroker) EXPECT() *MockMutaterBrokerMockRecorder {
+	return m.recorder
+}
+
+// AssignLXDProfiles mocks base method
+func (m *MockMutaterBroker) AssignLXDProfiles(arg0 string, arg1 []string, arg2 []lxdprofile.ProfilePost) ([]string, error) {
+	ret := m.ctrl.Call(m, "AssignLXDProfiles", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AssignLXDProfiles indicates an expected call of AssignLXDProfiles
+func (mr *MockMutaterBrokerMockRecorder) AssignLXDProfiles(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignLXDProfiles", reflect.TypeOf((*MockMutaterBroker)(nil).AssignLXDProfiles), arg0, arg1, arg2)
+}
+
+// LXDProfileNames mocks base method
+func (m *MockMutaterBroker) LXDProfileNames(arg0 string) ([]string, error) {
+	ret := m.ctrl.Call(m, "LXDProfileNames", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LXDProfileNames indicates an expected call of LXDProfileNames
+func (mr *MockMutaterBrokerMockRecorder) LXDProfileNames(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LXDProfileNames", reflect.TypeOf((*MockMutaterBroker)(nil).LXDProfileNames), arg0)
+}
@@ -48,15 +48,15 @@ func (mr *MockInstanceMutaterAPIMockRecorder) Machine(arg0 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Machine", reflect.TypeOf((*MockInstanceMutaterAPI)(nil).Machine), arg0)
 }
 
-// WatchMachines mocks base method
-func (m *MockInstanceMutaterAPI) WatchMachines() (watcher.StringsWatcher, error) {
-	ret := m.ctrl.Call(m, "WatchMachines")
+// WatchLXDProfileMachines mocks base method
+func (m *MockInstanceMutaterAPI) WatchLXDProfileMachines() (watcher.StringsWatcher, error) {
+	ret := m.ctrl.Call(m, "WatchLXDProfileMachines")
 	ret0, _ := ret[0].(watcher.StringsWatcher)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// WatchMachines indicates an expected call of WatchMachines
-func (mr *MockInstanceMutaterAPIMockRecorder) WatchMachines() *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchMachines", reflect.TypeOf((*MockInstanceMutaterAPI)(nil).WatchMachines))
+// WatchLXDProfileMachines indicates an expected call of WatchLXDProfileMachines
+func (mr *MockInstanceMutaterAPIMockRecorder) WatchLXDProfileMachines() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchLXDProfileMachines", reflect.TypeOf((*MockInstanceMutaterAPI)(nil).WatchLXDProfileMachines))
 }
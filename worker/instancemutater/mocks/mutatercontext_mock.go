@@ -84,9 +84,9 @@ func (mr *MockMutaterContextMockRecorder) errDying() *gomock.Call {
 }
 
 // getBroker mocks base method
-func (m *MockMutaterContext) getBroker() environs.LXDProfiler {
+func (m *MockMutaterContext) getBroker() environs.MutaterBroker {
 	ret := m.ctrl.Call(m, "getBroker")
-	ret0, _ := ret[0].(environs.LXDProfiler)
+	ret0, _ := ret[0].(environs.MutaterBroker)
 	return ret0
 }
 
@@ -5,9 +5,13 @@ package instancemutater
 
 import (
 	"fmt"
+	"math/rand"
+	"time"
 
+	"github.com/juju/clock"
 	"github.com/juju/collections/set"
 	"github.com/juju/errors"
+	"github.com/prometheus/client_golang/prometheus"
 	"gopkg.in/juju/names.v3"
 	"gopkg.in/juju/worker.v1"
 
@@ -22,6 +26,34 @@ import (
 
 //go:generate mockgen -package mocks -destination mocks/mutatercontext_mock.go github.com/juju/juju/worker/instancemutater MutaterContext
 
+const (
+	// maxBrokerRetryDelay is the longest delay between retries of a
+	// failed broker call.
+	maxBrokerRetryDelay = 2 * time.Minute
+
+	// brokerRetryJitter is the fraction by which the delay between
+	// retries of a failed broker call is randomly varied.
+	brokerRetryJitter = 0.2
+)
+
+// brokerError records that an error was returned by the MutaterBroker
+// broker, rather than by the API.
+type brokerError struct {
+	error
+}
+
+// isRetryableBrokerError returns whether err was returned by the broker
+// and may be transient. Errors that show the request itself to be at
+// fault are not worth retrying.
+func isRetryableBrokerError(err error) bool {
+	brokerErr, ok := errors.Cause(err).(*brokerError)
+	if !ok {
+		return false
+	}
+	cause := errors.Cause(brokerErr.error)
+	return !errors.IsNotValid(cause) && !errors.IsNotSupported(cause)
+}
+
 // lifetimeContext was extracted to allow the various Context clients to get
 // the benefits of the catacomb encapsulating everything that should happen
 // here. A clean implementation would almost certainly not need this.
@@ -35,15 +67,44 @@ type lifetimeContext interface {
 
 type MachineContext interface {
 	lifetimeContext
-	getBroker() environs.LXDProfiler
+	getBroker() environs.MutaterBroker
 	getRequiredLXDProfiles(string) []string
 }
 
+// brokerMachineContext is a MachineContext which has its machine
+// mutated by the given broker, rather than by the worker's own.
+type brokerMachineContext struct {
+	MachineContext
+	broker environs.MutaterBroker
+}
+
+// getBroker is part of the MachineContext interface.
+func (c brokerMachineContext) getBroker() environs.MutaterBroker {
+	return c.broker
+}
+
 type MutaterMachine struct {
 	context    MachineContext
 	logger     Logger
 	machineApi instancemutater.MutaterMachine
 	id         string
+
+	// clock and batchWindow control the coalescing of profile changes;
+	// changes are processed as they arrive if batchWindow is zero.
+	clock       clock.Clock
+	batchWindow time.Duration
+
+	// verifyInterval is the period between checks for drift in the
+	// machine's applied profiles; no checks are made if it is zero.
+	verifyInterval time.Duration
+
+	// retryAttempts and retryDelay control the retrying of transient
+	// broker errors; they are not retried if retryAttempts is zero.
+	retryAttempts int
+	retryDelay    time.Duration
+
+	// metrics records the profile changes made to the machine.
+	metrics *metrics
 }
 
 type MutaterContext interface {
@@ -53,10 +114,20 @@ type MutaterContext interface {
 }
 
 type mutater struct {
-	context     MutaterContext
-	logger      Logger
-	machines    map[names.MachineTag]chan struct{}
-	machineDead chan instancemutater.MutaterMachine
+	context        MutaterContext
+	logger         Logger
+	machines       map[names.MachineTag]chan struct{}
+	machineDead    chan instancemutater.MutaterMachine
+	clock          clock.Clock
+	batchWindow    time.Duration
+	verifyInterval time.Duration
+	retryAttempts  int
+	retryDelay     time.Duration
+	metrics        *metrics
+
+	// brokers holds the brokers used to mutate containers of types
+	// other than lxd, keyed by container type.
+	brokers map[instance.ContainerType]environs.MutaterBroker
 }
 
 func (m *mutater) startMachines(tags []names.MachineTag) error {
@@ -75,12 +146,16 @@ func (m *mutater) startMachines(tags []names.MachineTag) error {
 			}
 			id := api.Tag().Id()
 
-			// Ensure we do not watch any KVM containers.
+			// KVM containers are mutated by the broker for their type,
+			// and ignored if there is none.
 			containerType, err := api.ContainerType()
 			if err != nil {
 				return errors.Trace(err)
 			}
-			if containerType == instance.KVM {
+			context := m.context.newMachineContext()
+			if broker, ok := m.brokers[containerType]; ok {
+				context = brokerMachineContext{MachineContext: context, broker: broker}
+			} else if containerType == instance.KVM {
 				m.logger.Tracef("ignoring KVM container machine-%s", id)
 				continue
 			}
@@ -89,10 +164,16 @@ func (m *mutater) startMachines(tags []names.MachineTag) error {
 			m.machines[tag] = c
 
 			machine := MutaterMachine{
-				context:    m.context.newMachineContext(),
-				logger:     m.logger,
-				machineApi: api,
-				id:         id,
+				context:        context,
+				logger:         m.logger,
+				machineApi:     api,
+				id:             id,
+				clock:          m.clock,
+				batchWindow:    m.batchWindow,
+				verifyInterval: m.verifyInterval,
+				retryAttempts:  m.retryAttempts,
+				retryDelay:     m.retryDelay,
+				metrics:        m.metrics,
 			}
 
 			go runMachine(machine, c, m.machineDead)
@@ -138,28 +219,68 @@ func runMachine(machine MutaterMachine, removed <-chan struct{}, died chan<- ins
 // watchProfileChanges, any error returned will cause the worker to restart.
 func (m MutaterMachine) watchProfileChangesLoop(removed <-chan struct{}, profileChangeWatcher watcher.NotifyWatcher) error {
 	m.logger.Tracef("watching change on MutaterMachine %s", m.id)
+	// batch fires at the end of the window in which profile changes are
+	// being coalesced; it is nil when no changes are pending.
+	var batch <-chan time.Time
+	// verify fires when the machine's applied profiles are next to be
+	// checked for drift.
+	var verify <-chan time.Time
+	if m.verifyInterval > 0 {
+		verify = m.clock.After(m.verifyInterval)
+	}
+	// retry fires when profile changes that failed with a transient
+	// broker error are next to be retried; it is nil when there is no
+	// retry pending. Other changes wait for the retry, which applies
+	// them all.
+	var retry <-chan time.Time
+	var failures int
+	handle := func(done bool, err error) (bool, error) {
+		if err == nil {
+			failures = 0
+			return done, nil
+		}
+		if !isRetryableBrokerError(err) || failures >= m.retryAttempts {
+			return false, errors.Trace(err)
+		}
+		failures++
+		delay := m.retryBackoff(failures)
+		m.logger.Warningf("cannot update lxd profiles of machine-%s, retrying in %s (attempt %d of %d): %v", m.id, delay, failures, m.retryAttempts, err)
+		retry = m.clock.After(delay)
+		return false, nil
+	}
 	for {
+		var done bool
+		var err error
 		select {
 		case <-m.context.dying():
 			return m.context.errDying()
 		case <-profileChangeWatcher.Changes():
-			info, err := m.machineApi.CharmProfilingInfo()
-			if err != nil {
-				// If the machine is not provisioned then we need to wait for
-				// new changes from the watcher.
-				if params.IsCodeNotProvisioned(errors.Cause(err)) {
-					m.logger.Tracef("got not provisioned machine-%s on charm profiling info, wait for another change", m.id)
-					continue
+			if retry != nil {
+				continue
+			}
+			if m.batchWindow > 0 {
+				if batch == nil {
+					m.logger.Tracef("coalescing lxd profile changes for machine-%s for %s", m.id, m.batchWindow)
+					batch = m.clock.After(m.batchWindow)
 				}
-				return errors.Trace(err)
+				continue
 			}
-			if err = m.processMachineProfileChanges(info); err != nil && errors.IsNotValid(err) {
-				// Return to stop mutating the machine, but no need to restart
-				// the worker.
-				return nil
-			} else if err != nil {
-				return errors.Trace(err)
+			done, err = handle(m.applyProfileChanges())
+		case <-batch:
+			batch = nil
+			if retry != nil {
+				continue
 			}
+			done, err = handle(m.applyProfileChanges())
+		case <-retry:
+			retry = nil
+			done, err = handle(m.applyProfileChanges())
+		case <-verify:
+			verify = m.clock.After(m.verifyInterval)
+			if retry != nil {
+				continue
+			}
+			done, err = handle(m.reconcileProfiles())
 		case <-removed:
 			if err := m.machineApi.Refresh(); err != nil {
 				return errors.Trace(err)
@@ -168,7 +289,50 @@ func (m MutaterMachine) watchProfileChangesLoop(removed <-chan struct{}, profile
 				return nil
 			}
 		}
+		if err != nil || done {
+			return errors.Trace(err)
+		}
+	}
+}
+
+// retryBackoff returns the delay before the given retry of a failed
+// broker call: the retry delay, doubled for each earlier retry up to
+// maxBrokerRetryDelay, and varied by brokerRetryJitter.
+func (m MutaterMachine) retryBackoff(attempt int) time.Duration {
+	delay := m.retryDelay
+	for i := 1; i < attempt && delay < maxBrokerRetryDelay; i++ {
+		delay *= 2
 	}
+	if delay > maxBrokerRetryDelay {
+		delay = maxBrokerRetryDelay
+	}
+	jitter := (rand.Float64()*2 - 1) * brokerRetryJitter
+	return time.Duration(float64(delay) * (1 + jitter))
+}
+
+// applyProfileChanges fetches the machine's current charm profiling info,
+// reflecting every change made so far, and brings the machine's profiles
+// into line with it. It returns true if the machine should no longer be
+// mutated.
+func (m MutaterMachine) applyProfileChanges() (bool, error) {
+	info, err := m.machineApi.CharmProfilingInfo()
+	if err != nil {
+		// If the machine is not provisioned then we need to wait for
+		// new changes from the watcher.
+		if params.IsCodeNotProvisioned(errors.Cause(err)) {
+			m.logger.Tracef("got not provisioned machine-%s on charm profiling info, wait for another change", m.id)
+			return false, nil
+		}
+		return false, errors.Trace(err)
+	}
+	if err = m.processMachineProfileChanges(info); err != nil && errors.IsNotValid(err) {
+		// Return to stop mutating the machine, but no need to restart
+		// the worker.
+		return true, nil
+	} else if err != nil {
+		return false, errors.Trace(err)
+	}
+	return false, nil
 }
 
 func (m MutaterMachine) processMachineProfileChanges(info *instancemutater.UnitProfileInfo) error {
@@ -204,41 +368,204 @@ func (m MutaterMachine) processMachineProfileChanges(info *instancemutater.UnitP
 		return retErr
 	}
 
-	// Convert info.ProfileChanges into a struct which can be used to
-	// add or remove profiles from a machine.  Use it to create a list
-	// of expected profiles.
-	post, err := m.gatherProfileData(info)
+	post, expectedProfiles, rollback, err := m.targetProfiles(info)
 	if err != nil {
 		return report(errors.Annotatef(err, "%s", m.id))
 	}
-
-	expectedProfiles := m.context.getRequiredLXDProfiles(info.ModelName)
-	for _, p := range post {
-		if p.Profile != nil {
-			expectedProfiles = append(expectedProfiles, p.Name)
-		}
+	if err := m.validateProfiles(post); err != nil {
+		// The profiles cannot be applied until the charm is fixed,
+		// which is seen as a new change, so the worker carries on.
+		report(errors.Annotatef(err, "%s", m.id))
+		return nil
 	}
 
-	verified, err := m.verifyCurrentProfiles(string(info.InstanceId), expectedProfiles)
+	instId := string(info.InstanceId)
+	previousProfiles, verified, err := m.verifyCurrentProfiles(instId, expectedProfiles)
 	if err != nil {
-		return report(errors.Annotatef(err, "%s", m.id))
+		return report(errors.Annotatef(m.metrics.brokerError(err), "%s", m.id))
 	}
 	if verified {
 		m.logger.Tracef("no changes necessary to machine-%s lxd profiles", m.id)
 		return report(nil)
 	}
 
-	m.logger.Tracef("machine-%s (%s) assign lxd profiles %q, %#v", m.id, string(info.InstanceId), expectedProfiles, post)
-	broker := m.context.getBroker()
-	currentProfiles, err := broker.AssignLXDProfiles(string(info.InstanceId), expectedProfiles, post)
+	m.logger.Tracef("machine-%s (%s) assign lxd profiles %q, %#v", m.id, instId, expectedProfiles, post)
+	currentProfiles, err := m.assignProfiles(instId, expectedProfiles, post)
 	if err != nil {
 		m.logger.Errorf("failure to assign lxd profiles %s to machine-%s: %s", expectedProfiles, m.id, err)
-		return report(err)
+		return report(m.restoreProfiles(instId, previousProfiles, err))
+	}
+	if rollback {
+		return m.reportRollback(expectedProfiles)
 	}
 
 	return report(m.machineApi.SetCharmProfiles(currentProfiles))
 }
 
+// targetProfiles returns the profiles to be applied to the machine, and
+// the profile changes needed to apply them. If a rollback of the
+// machine's profiles has been requested, and the expected profiles have
+// not changed since it was requested, the profiles to roll back to are
+// returned instead, and rollback is true. The rollback stands until the
+// expected profiles next change: when they are applied and recorded, the
+// request is cancelled.
+func (m MutaterMachine) targetProfiles(info *instancemutater.UnitProfileInfo) (_ []lxdprofile.ProfilePost, _ []string, rollback bool, _ error) {
+	post, expectedProfiles, err := m.expectedProfiles(info)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	if len(info.RollbackProfiles) == 0 || !sameProfiles(expectedProfiles, info.CurrentProfiles) {
+		return post, expectedProfiles, false, nil
+	}
+	// The profiles rolled back to still exist on the lxd server, as
+	// profiles are only deleted once replaced, so none are posted.
+	return nil, info.RollbackProfiles, true, nil
+}
+
+// restoreProfiles reassigns the profiles the instance had before a failed
+// attempt to change them, so that it is not left with a partial set.
+// Profiles are only deleted from the lxd server once a change succeeds,
+// so those being restored are still available. It returns the error with
+// which the change failed.
+func (m MutaterMachine) restoreProfiles(instId string, previousProfiles []string, applyErr error) error {
+	if _, err := m.context.getBroker().AssignLXDProfiles(instId, previousProfiles, nil); err != nil {
+		m.logger.Errorf("cannot roll back machine-%s lxd profiles to %q: %s", m.id, previousProfiles, err)
+		return applyErr
+	}
+	m.logger.Warningf("rolled back machine-%s lxd profiles to %q", m.id, previousProfiles)
+	return errors.Annotatef(applyErr, "rolled back to %q", previousProfiles)
+}
+
+// reportRollback notes the requested rollback of the machine's profiles
+// in its modification status. The machine's charm profiles are left as
+// they are, as recording the profiles rolled back to would cancel the
+// rollback.
+func (m MutaterMachine) reportRollback(profiles []string) error {
+	m.logger.Debugf("rolled back machine-%s lxd profiles to %q", m.id, profiles)
+	note := fmt.Sprintf("rolled back lxd profiles to %q", profiles)
+	if err := m.machineApi.SetModificationStatus(status.Applied, note, nil); err != nil {
+		m.logger.Errorf("cannot set modification status of machine %q applied: %v", m.id, err)
+	}
+	return nil
+}
+
+// reconcileProfiles compares the lxd profiles applied to the machine's
+// instance with those expected from its charm profiling info and, if
+// they have drifted apart, as happens when profiles are edited with
+// "lxc profile", reapplies the expected profiles. A repair is noted in
+// the machine's modification status. It returns true if the machine
+// should no longer be mutated.
+func (m MutaterMachine) reconcileProfiles() (bool, error) {
+	info, err := m.machineApi.CharmProfilingInfo()
+	if err != nil {
+		if params.IsCodeNotProvisioned(errors.Cause(err)) {
+			m.logger.Tracef("got not provisioned machine-%s on charm profiling info, skipping lxd profile verification", m.id)
+			return false, nil
+		}
+		return false, errors.Trace(err)
+	}
+	if err := m.machineApi.Refresh(); err != nil {
+		return false, errors.Trace(err)
+	}
+	if m.machineApi.Life() == params.Dead {
+		return true, nil
+	}
+
+	post, expectedProfiles, rollback, err := m.targetProfiles(info)
+	if err != nil {
+		return false, errors.Annotatef(err, "%s", m.id)
+	}
+	if err := m.validateProfiles(post); err != nil {
+		// The invalid profiles were reported when they were last
+		// changed; there is nothing to repair until they are fixed.
+		m.logger.Debugf("skipping lxd profile verification of machine-%s: %v", m.id, err)
+		return false, nil
+	}
+	instId := string(info.InstanceId)
+	obtainedProfiles, verified, err := m.verifyCurrentProfiles(instId, expectedProfiles)
+	if err != nil {
+		return false, errors.Trace(m.metrics.brokerError(err))
+	}
+	if verified {
+		m.logger.Tracef("lxd profiles of machine-%s verified", m.id)
+		return false, nil
+	}
+
+	m.metrics.verificationFailures.Inc()
+	m.logger.Warningf("machine-%s (%s) lxd profiles %q have drifted from %q, repairing", m.id, instId, obtainedProfiles, expectedProfiles)
+	currentProfiles, err := m.assignProfiles(instId, expectedProfiles, post)
+	if err == nil && rollback {
+		return false, m.reportRollback(expectedProfiles)
+	} else if err == nil {
+		err = m.machineApi.SetCharmProfiles(currentProfiles)
+	}
+	if err != nil {
+		m.logger.Errorf("cannot repair machine-%s lxd profiles: %s", m.id, err.Error())
+		if err := m.machineApi.SetModificationStatus(status.Error, fmt.Sprintf("cannot repair machine's lxd profile drift: %s", err.Error()), nil); err != nil {
+			m.logger.Errorf("cannot set modification status of machine %q error: %v", m.id, err)
+		}
+		return false, errors.Trace(err)
+	}
+	note := fmt.Sprintf("repaired lxd profile drift: found %q, expected %q", obtainedProfiles, expectedProfiles)
+	if err := m.machineApi.SetModificationStatus(status.Applied, note, nil); err != nil {
+		m.logger.Errorf("cannot set modification status of machine %q applied: %v", m.id, err)
+	}
+	return false, nil
+}
+
+// validateProfiles checks the charm profiles to be written against the
+// whitelist applied when a charm is uploaded and, if the broker is able
+// to, against the capabilities of its lxd server. This reports a profile
+// that cannot be applied as such, rather than as a broker failure.
+func (m MutaterMachine) validateProfiles(post []lxdprofile.ProfilePost) error {
+	validator, _ := m.context.getBroker().(environs.LXDProfileValidator)
+	for _, p := range post {
+		if p.Profile == nil {
+			continue
+		}
+		if err := lxdprofile.ValidateLXDProfile(lxdprofile.LXDProfiles{Profile: *p.Profile}); err != nil {
+			return errors.Annotatef(err, "profile %q", p.Name)
+		}
+		if validator == nil {
+			continue
+		}
+		if err := validator.ValidateLXDProfile(*p.Profile); err != nil {
+			return errors.Annotatef(err, "profile %q", p.Name)
+		}
+	}
+	return nil
+}
+
+// assignProfiles has the broker apply the expected profiles to the
+// instance, recording the outcome in the worker's metrics.
+func (m MutaterMachine) assignProfiles(instId string, expectedProfiles []string, post []lxdprofile.ProfilePost) ([]string, error) {
+	timer := prometheus.NewTimer(m.metrics.applyDuration)
+	currentProfiles, err := m.context.getBroker().AssignLXDProfiles(instId, expectedProfiles, post)
+	timer.ObserveDuration()
+	if err != nil {
+		return nil, m.metrics.brokerError(err)
+	}
+	m.metrics.profilesApplied.Inc()
+	return currentProfiles, nil
+}
+
+// expectedProfiles converts info.ProfileChanges into a struct which can
+// be used to add or remove profiles from a machine, and uses it to
+// create a list of expected profiles.
+func (m MutaterMachine) expectedProfiles(info *instancemutater.UnitProfileInfo) ([]lxdprofile.ProfilePost, []string, error) {
+	post, err := m.gatherProfileData(info)
+	if err != nil {
+		return nil, nil, err
+	}
+	expected := m.context.getRequiredLXDProfiles(info.ModelName)
+	for _, p := range post {
+		if p.Profile != nil {
+			expected = append(expected, p.Name)
+		}
+	}
+	return post, expected, nil
+}
+
 func (m MutaterMachine) gatherProfileData(info *instancemutater.UnitProfileInfo) ([]lxdprofile.ProfilePost, error) {
 	var result []lxdprofile.ProfilePost
 	for _, pu := range info.ProfileChanges {
@@ -267,22 +594,21 @@ func (m MutaterMachine) gatherProfileData(info *instancemutater.UnitProfileInfo)
 	return result, nil
 }
 
-func (m MutaterMachine) verifyCurrentProfiles(instId string, expectedProfiles []string) (bool, error) {
+// verifyCurrentProfiles returns the profiles applied to the instance, and
+// whether they are those expected.
+func (m MutaterMachine) verifyCurrentProfiles(instId string, expectedProfiles []string) ([]string, bool, error) {
 	broker := m.context.getBroker()
 	obtainedProfiles, err := broker.LXDProfileNames(instId)
 	if err != nil {
-		return false, err
-	}
-	obtainedSet := set.NewStrings(obtainedProfiles...)
-	expectedSet := set.NewStrings(expectedProfiles...)
-
-	if obtainedSet.Union(expectedSet).Size() > obtainedSet.Size() {
-		return false, nil
-	}
-
-	if expectedSet.Union(obtainedSet).Size() > expectedSet.Size() {
-		return false, nil
+		return nil, false, err
 	}
+	return obtainedProfiles, sameProfiles(obtainedProfiles, expectedProfiles), nil
+}
 
-	return true, nil
+// sameProfiles returns whether the two lists hold the same profiles,
+// regardless of order.
+func sameProfiles(a, b []string) bool {
+	aSet := set.NewStrings(a...)
+	bSet := set.NewStrings(b...)
+	return aSet.Difference(bSet).IsEmpty() && bSet.Difference(aSet).IsEmpty()
 }
@@ -56,6 +56,7 @@ func (s *mutaterSuite) TestProcessMachineProfileChanges(c *gc.C) {
 	info := s.info(startingProfiles, 1, true)
 	err := instancemutater.ProcessMachineProfileChanges(s.mutaterMachine, info)
 	c.Assert(err, jc.ErrorIsNil)
+	s.checkMetrics(c, 1, 0, 0)
 }
 
 func (s *mutaterSuite) TestProcessMachineProfileChangesMachineDead(c *gc.C) {
@@ -81,6 +82,27 @@ func (s *mutaterSuite) TestProcessMachineProfileChangesError(c *gc.C) {
 	s.expectRefreshLifeAliveStatusIdle()
 	s.expectLXDProfileNames(startingProfiles, nil)
 	s.expectAssignLXDProfiles(finishingProfiles, errors.New("fail me"))
+	// The instance's profiles are restored.
+	s.broker.EXPECT().AssignLXDProfiles(s.instId, startingProfiles, gomock.Nil()).Return(startingProfiles, nil)
+	s.expectModificationStatusError()
+
+	info := s.info(startingProfiles, 1, true)
+	err := instancemutater.ProcessMachineProfileChanges(s.mutaterMachine, info)
+	c.Assert(err, gc.ErrorMatches, `rolled back to \["default" "juju-testme"\]: fail me`)
+	s.checkMetrics(c, 0, 0, 1)
+}
+
+func (s *mutaterSuite) TestProcessMachineProfileChangesRestoreError(c *gc.C) {
+	defer s.setUpMocks(c).Finish()
+
+	startingProfiles := []string{"default", "juju-testme"}
+	finishingProfiles := append(startingProfiles, "juju-testme-lxd-profile-1")
+
+	s.ignoreLogging(c)
+	s.expectRefreshLifeAliveStatusIdle()
+	s.expectLXDProfileNames(startingProfiles, nil)
+	s.expectAssignLXDProfiles(finishingProfiles, errors.New("fail me"))
+	s.broker.EXPECT().AssignLXDProfiles(s.instId, startingProfiles, gomock.Nil()).Return(nil, errors.New("fail again"))
 	s.expectModificationStatusError()
 
 	info := s.info(startingProfiles, 1, true)
@@ -88,6 +110,120 @@ func (s *mutaterSuite) TestProcessMachineProfileChangesError(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "fail me")
 }
 
+func (s *mutaterSuite) TestProcessMachineProfileChangesInvalidProfile(c *gc.C) {
+	defer s.setUpMocks(c).Finish()
+
+	startingProfiles := []string{"default", "juju-testme"}
+
+	s.ignoreLogging(c)
+	s.expectRefreshLifeAliveStatusIdle()
+	// The profile is reported, without being passed to the broker.
+	s.machine.EXPECT().SetModificationStatus(
+		status.Error,
+		`cannot upgrade machine's lxd profile: 2: profile "juju-testme-lxd-profile-1": invalid lxd-profile: contains device type "nic"`,
+		nil,
+	).Return(nil)
+
+	info := s.info(startingProfiles, 1, true)
+	info.ProfileChanges[0].Profile = lxdprofile.Profile{
+		Devices: map[string]map[string]string{
+			"eth1": {"type": "nic"},
+		},
+	}
+	err := instancemutater.ProcessMachineProfileChanges(s.mutaterMachine, info)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkMetrics(c, 0, 0, 0)
+}
+
+func (s *mutaterSuite) TestProcessMachineProfileChangesUnsupportedProfile(c *gc.C) {
+	ctrl := s.setUpMocks(c)
+	defer ctrl.Finish()
+	validator := s.setUpValidatingBroker(ctrl)
+
+	startingProfiles := []string{"default", "juju-testme"}
+
+	s.ignoreLogging(c)
+	s.expectRefreshLifeAliveStatusIdle()
+	validator.EXPECT().ValidateLXDProfile(testProfile).Return(errors.NotSupportedf("unix-char device %q on clustered lxd server", "tun"))
+	s.machine.EXPECT().SetModificationStatus(
+		status.Error,
+		`cannot upgrade machine's lxd profile: 2: profile "juju-testme-lxd-profile-1": unix-char device "tun" on clustered lxd server not supported`,
+		nil,
+	).Return(nil)
+
+	info := s.info(startingProfiles, 1, true)
+	err := instancemutater.ProcessMachineProfileChanges(s.mutaterMachine, info)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkMetrics(c, 0, 0, 0)
+}
+
+func (s *mutaterSuite) TestProcessMachineProfileChangesSupportedProfile(c *gc.C) {
+	ctrl := s.setUpMocks(c)
+	defer ctrl.Finish()
+	validator := s.setUpValidatingBroker(ctrl)
+
+	startingProfiles := []string{"default", "juju-testme"}
+	finishingProfiles := append(startingProfiles, "juju-testme-lxd-profile-1")
+
+	s.ignoreLogging(c)
+	s.expectRefreshLifeAliveStatusIdle()
+	validator.EXPECT().ValidateLXDProfile(testProfile).Return(nil)
+	s.expectLXDProfileNames(startingProfiles, nil)
+	s.expectAssignLXDProfiles(finishingProfiles, nil)
+	s.expectSetCharmProfiles(finishingProfiles)
+	s.expectModificationStatusApplied()
+
+	info := s.info(startingProfiles, 1, true)
+	err := instancemutater.ProcessMachineProfileChanges(s.mutaterMachine, info)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkMetrics(c, 1, 0, 0)
+}
+
+func (s *mutaterSuite) TestProcessMachineProfileChangesRollback(c *gc.C) {
+	defer s.setUpMocks(c).Finish()
+
+	profiles := []string{"default", "juju-testme", "juju-testme-lxd-profile-1"}
+	rollbackProfiles := []string{"default", "juju-testme", "juju-testme-lxd-profile-0"}
+
+	s.ignoreLogging(c)
+	s.expectRefreshLifeAliveStatusIdle()
+	s.expectLXDProfileNames(profiles, nil)
+	s.broker.EXPECT().AssignLXDProfiles(s.instId, rollbackProfiles, gomock.Nil()).Return(rollbackProfiles, nil)
+	// The charm profiles are not recorded, so the rollback stands.
+	s.machine.EXPECT().SetModificationStatus(
+		status.Applied,
+		`rolled back lxd profiles to ["default" "juju-testme" "juju-testme-lxd-profile-0"]`,
+		nil,
+	).Return(nil)
+
+	info := s.info(profiles, 1, true)
+	info.RollbackProfiles = rollbackProfiles
+	err := instancemutater.ProcessMachineProfileChanges(s.mutaterMachine, info)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkMetrics(c, 1, 0, 0)
+}
+
+func (s *mutaterSuite) TestProcessMachineProfileChangesRollbackSuperseded(c *gc.C) {
+	defer s.setUpMocks(c).Finish()
+
+	startingProfiles := []string{"default", "juju-testme"}
+	finishingProfiles := append(startingProfiles, "juju-testme-lxd-profile-1")
+
+	s.ignoreLogging(c)
+	s.expectRefreshLifeAliveStatusIdle()
+	s.expectLXDProfileNames(startingProfiles, nil)
+	// The profiles have changed since the rollback was requested,
+	// so the new profiles are applied and recorded.
+	s.expectAssignLXDProfiles(finishingProfiles, nil)
+	s.expectSetCharmProfiles(finishingProfiles)
+	s.expectModificationStatusApplied()
+
+	info := s.info(startingProfiles, 1, true)
+	info.RollbackProfiles = []string{"default"}
+	err := instancemutater.ProcessMachineProfileChanges(s.mutaterMachine, info)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *mutaterSuite) TestProcessMachineProfileChangesNilInfo(c *gc.C) {
 	defer s.setUpMocks(c).Finish()
 
@@ -148,6 +284,100 @@ func (s *mutaterSuite) TestGatherProfileDataNoChange(c *gc.C) {
 	})
 }
 
+func (s *mutaterSuite) TestReconcileProfilesNoDrift(c *gc.C) {
+	defer s.setUpMocks(c).Finish()
+
+	profiles := []string{"default", "juju-testme", "juju-testme-lxd-profile-1"}
+	s.ignoreLogging(c)
+	s.expectCharmProfilingInfo(s.info(profiles, 1, true))
+	s.expectRefreshLifeAlive()
+	s.expectLXDProfileNames(profiles, nil)
+
+	done, err := instancemutater.ReconcileProfiles(s.mutaterMachine)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(done, jc.IsFalse)
+}
+
+func (s *mutaterSuite) TestReconcileProfilesRepairsDrift(c *gc.C) {
+	defer s.setUpMocks(c).Finish()
+
+	profiles := []string{"default", "juju-testme", "juju-testme-lxd-profile-1"}
+	s.ignoreLogging(c)
+	s.expectCharmProfilingInfo(s.info(profiles, 1, true))
+	s.expectRefreshLifeAlive()
+	// The charm profile has been removed from the instance by hand.
+	s.expectLXDProfileNames([]string{"default", "juju-testme", "manual"}, nil)
+	s.expectAssignLXDProfiles(profiles, nil)
+	s.expectSetCharmProfiles(profiles)
+	s.machine.EXPECT().SetModificationStatus(
+		status.Applied,
+		`repaired lxd profile drift: found ["default" "juju-testme" "manual"], expected ["default" "juju-testme" "juju-testme-lxd-profile-1"]`,
+		nil,
+	).Return(nil)
+
+	done, err := instancemutater.ReconcileProfiles(s.mutaterMachine)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(done, jc.IsFalse)
+	s.checkMetrics(c, 1, 1, 0)
+}
+
+func (s *mutaterSuite) TestReconcileProfilesHonoursRollback(c *gc.C) {
+	defer s.setUpMocks(c).Finish()
+
+	profiles := []string{"default", "juju-testme", "juju-testme-lxd-profile-1"}
+	rollbackProfiles := []string{"default", "juju-testme"}
+	info := s.info(profiles, 1, true)
+	info.RollbackProfiles = rollbackProfiles
+	s.ignoreLogging(c)
+	s.expectCharmProfilingInfo(info)
+	s.expectRefreshLifeAlive()
+	// The profiles rolled back to are not treated as drift.
+	s.expectLXDProfileNames(rollbackProfiles, nil)
+
+	done, err := instancemutater.ReconcileProfiles(s.mutaterMachine)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(done, jc.IsFalse)
+	s.checkMetrics(c, 0, 0, 0)
+}
+
+func (s *mutaterSuite) TestReconcileProfilesRepairError(c *gc.C) {
+	defer s.setUpMocks(c).Finish()
+
+	profiles := []string{"default", "juju-testme", "juju-testme-lxd-profile-1"}
+	s.ignoreLogging(c)
+	s.expectCharmProfilingInfo(s.info(profiles, 1, true))
+	s.expectRefreshLifeAlive()
+	s.expectLXDProfileNames([]string{"default"}, nil)
+	s.expectAssignLXDProfiles(profiles, errors.New("fail me"))
+	s.expectModificationStatusError()
+
+	_, err := instancemutater.ReconcileProfiles(s.mutaterMachine)
+	c.Assert(err, gc.ErrorMatches, "fail me")
+	s.checkMetrics(c, 0, 1, 1)
+}
+
+func (s *mutaterSuite) TestReconcileProfilesMachineDead(c *gc.C) {
+	defer s.setUpMocks(c).Finish()
+
+	s.expectCharmProfilingInfo(s.info([]string{"default"}, 1, true))
+	s.expectRefreshLifeDead()
+
+	done, err := instancemutater.ReconcileProfiles(s.mutaterMachine)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(done, jc.IsTrue)
+}
+
+func (s *mutaterSuite) TestReconcileProfilesNotProvisioned(c *gc.C) {
+	defer s.setUpMocks(c).Finish()
+
+	s.ignoreLogging(c)
+	s.machine.EXPECT().CharmProfilingInfo().Return(nil, params.Error{Code: params.CodeNotProvisioned})
+
+	done, err := instancemutater.ReconcileProfiles(s.mutaterMachine)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(done, jc.IsFalse)
+}
+
 func (s *mutaterSuite) info(profiles []string, rev int, add bool) *apiinstancemutater.UnitProfileInfo {
 	info := &apiinstancemutater.UnitProfileInfo{
 		ModelName:       "testme",
@@ -222,6 +452,25 @@ func (s *mutaterSuite) setUpMocks(c *gc.C) *gomock.Controller {
 	return ctrl
 }
 
+// setUpValidatingBroker replaces the mutater machine's broker with one
+// that validates lxd profiles against its lxd server.
+func (s *mutaterSuite) setUpValidatingBroker(ctrl *gomock.Controller) *mocks.MockLXDProfileValidator {
+	validator := mocks.NewMockLXDProfileValidator(ctrl)
+	broker := &validatingBroker{
+		MockLXDProfiler:         s.broker,
+		MockLXDProfileValidator: validator,
+	}
+	s.mutaterMachine = instancemutater.NewMachineContext(s.logger, broker, s.machine, s.getRequiredLXDProfiles, s.tag.Id())
+	return validator
+}
+
+func (s *mutaterSuite) checkMetrics(c *gc.C, applied, verificationFailures, brokerErrors float64) {
+	obtainedApplied, obtainedFailures, obtainedErrors := instancemutater.MachineMetrics(s.mutaterMachine)
+	c.Check(obtainedApplied, gc.Equals, applied)
+	c.Check(obtainedFailures, gc.Equals, verificationFailures)
+	c.Check(obtainedErrors, gc.Equals, brokerErrors)
+}
+
 func (s *mutaterSuite) expectLXDProfileNames(profiles []string, err error) {
 	s.broker.EXPECT().LXDProfileNames(s.instId).Return(profiles, err)
 }
@@ -233,6 +482,16 @@ func (s *mutaterSuite) expectRefreshLifeAliveStatusIdle() {
 	mExp.SetModificationStatus(status.Idle, "", nil).Return(nil)
 }
 
+func (s *mutaterSuite) expectCharmProfilingInfo(info *apiinstancemutater.UnitProfileInfo) {
+	s.machine.EXPECT().CharmProfilingInfo().Return(info, nil)
+}
+
+func (s *mutaterSuite) expectRefreshLifeAlive() {
+	mExp := s.machine.EXPECT()
+	mExp.Refresh().Return(nil)
+	mExp.Life().Return(params.Alive)
+}
+
 func (s *mutaterSuite) expectRefreshLifeDead() {
 	mExp := s.machine.EXPECT()
 	mExp.Refresh().Return(nil)
@@ -270,3 +529,8 @@ var testProfile = lxdprofile.Profile{
 		},
 	},
 }
+
+type validatingBroker struct {
+	*mocks.MockLXDProfiler
+	*mocks.MockLXDProfileValidator
+}
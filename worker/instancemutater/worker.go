@@ -4,15 +4,22 @@
 package instancemutater
 
 import (
+	"time"
+
+	"github.com/juju/clock"
 	"github.com/juju/errors"
+	"github.com/juju/utils/featureflag"
+	"github.com/prometheus/client_golang/prometheus"
 	"gopkg.in/juju/names.v3"
 	"gopkg.in/juju/worker.v1"
 	"gopkg.in/juju/worker.v1/catacomb"
 
 	"github.com/juju/juju/agent"
 	"github.com/juju/juju/api/instancemutater"
+	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/watcher"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/feature"
 )
 
 //go:generate mockgen -package mocks -destination mocks/instancebroker_mock.go github.com/juju/juju/worker/instancemutater InstanceMutaterAPI
@@ -21,7 +28,7 @@ import (
 //go:generate mockgen -package mocks -destination mocks/machinemutater_mock.go github.com/juju/juju/api/instancemutater MutaterMachine
 
 type InstanceMutaterAPI interface {
-	WatchMachines() (watcher.StringsWatcher, error)
+	WatchLXDProfileMachines() (watcher.StringsWatcher, error)
 	Machine(tag names.MachineTag) (instancemutater.MutaterMachine, error)
 }
 
@@ -41,7 +48,14 @@ type Config struct {
 	// Logger is the Logger for this worker.
 	Logger Logger
 
-	Broker environs.LXDProfiler
+	// Broker is used to mutate the machines, or the lxd containers,
+	// that the worker tracks.
+	Broker environs.MutaterBroker
+
+	// ContainerBrokers holds the brokers used to mutate containers of
+	// types other than lxd, such as kvm, keyed by container type.
+	// Containers of a type without a broker are ignored.
+	ContainerBrokers map[instance.ContainerType]environs.MutaterBroker
 
 	AgentConfig agent.Config
 
@@ -62,8 +76,76 @@ type Config struct {
 	// Note: the following is required for testing purposes when we have an
 	// error case and we want to know when it's valid to kill/clean the worker.
 	GetRequiredContext RequiredMutaterContextFunc
+
+	// Clock is used to time the window within which lxd profile changes
+	// for a machine are coalesced. It is only required when
+	// ProfileBatchWindow is set.
+	Clock clock.Clock
+
+	// ProfileBatchWindow is the period, starting from the first lxd
+	// profile change seen for a machine, during which further changes for
+	// the machine are coalesced, so that the broker is called once with
+	// the final set of profiles. Changes are applied immediately if it is
+	// zero.
+	ProfileBatchWindow time.Duration
+
+	// ProfileVerifyInterval is the period between checks that the lxd
+	// profiles applied to each machine's instance match those expected
+	// by the model, so that profiles changed out of band, e.g. with
+	// "lxc profile", are repaired. No checks are made if it is zero.
+	ProfileVerifyInterval time.Duration
+
+	// ProfileCleanupInterval is the period between removals of the charm
+	// lxd profiles that are no longer used by any instance, such as
+	// those left behind by charm upgrades. It is only used if the Broker
+	// is an environs.LXDProfileCleaner. No profiles are removed if it is
+	// zero.
+	ProfileCleanupInterval time.Duration
+
+	// ProfileCleanupDryRun, if true, causes the unused lxd profiles to be
+	// reported by the worker, but not removed.
+	ProfileCleanupDryRun bool
+
+	// BrokerRetryAttempts is the number of times that lxd profile
+	// changes for a machine are retried, with exponential backoff, when
+	// the broker fails in a way that may be transient, such as when the
+	// lxd daemon is restarting. The worker is killed by the first broker
+	// error if it is zero.
+	BrokerRetryAttempts int
+
+	// BrokerRetryDelay is the delay before the first retry of a failed
+	// broker call; each subsequent delay is doubled, up to a limit, and
+	// varied by a random jitter so that machines do not retry in step.
+	BrokerRetryDelay time.Duration
+
+	// PrometheusRegisterer, if non-nil, is used to register the worker's
+	// metrics for the lxd profile changes that it makes.
+	PrometheusRegisterer prometheus.Registerer
 }
 
+// defaultProfileBatchWindow is the ProfileBatchWindow used by the environ
+// and container workers. Applications upgraded together, as in a bundle
+// upgrade, have their profile changes recorded within moments of each
+// other; each application would otherwise cost a container restart.
+const defaultProfileBatchWindow = 3 * time.Second
+
+// defaultProfileVerifyInterval is the ProfileVerifyInterval used by the
+// environ and container workers.
+const defaultProfileVerifyInterval = 10 * time.Minute
+
+// defaultProfileCleanupInterval is the ProfileCleanupInterval used by the
+// environ and container workers.
+const defaultProfileCleanupInterval = time.Hour
+
+// defaultBrokerRetryAttempts and defaultBrokerRetryDelay are the
+// BrokerRetryAttempts and BrokerRetryDelay used by the environ and
+// container workers. The retries span a few minutes, long enough to
+// ride out an lxd daemon restart.
+const (
+	defaultBrokerRetryAttempts = 8
+	defaultBrokerRetryDelay    = time.Second
+)
+
 type RequiredLXDProfilesFunc func(string) []string
 
 type RequiredMutaterContextFunc func(MutaterContext) MutaterContext
@@ -98,6 +180,26 @@ func (config Config) Validate() error {
 	if config.GetRequiredContext == nil {
 		return errors.NotValidf("nil GetRequiredContext")
 	}
+	if config.ProfileBatchWindow < 0 {
+		return errors.NotValidf("negative ProfileBatchWindow")
+	}
+	if config.ProfileVerifyInterval < 0 {
+		return errors.NotValidf("negative ProfileVerifyInterval")
+	}
+	if config.ProfileCleanupInterval < 0 {
+		return errors.NotValidf("negative ProfileCleanupInterval")
+	}
+	if config.BrokerRetryAttempts < 0 {
+		return errors.NotValidf("negative BrokerRetryAttempts")
+	}
+	if config.BrokerRetryAttempts > 0 && config.BrokerRetryDelay <= 0 {
+		return errors.NotValidf("non-positive BrokerRetryDelay")
+	}
+	needsClock := config.ProfileBatchWindow > 0 || config.ProfileVerifyInterval > 0 ||
+		config.ProfileCleanupInterval > 0 || config.BrokerRetryAttempts > 0
+	if needsClock && config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
 	return nil
 }
 
@@ -105,14 +207,30 @@ func (config Config) Validate() error {
 // the machines in the state and polls their instance
 // for addition or removal changes.
 func NewEnvironWorker(config Config) (worker.Worker, error) {
-	config.GetMachineWatcher = config.Facade.WatchMachines
+	// Only machines hosting units whose charms declare an lxd profile
+	// can need their profiles changed, so don't track any others.
+	config.GetMachineWatcher = config.Facade.WatchLXDProfileMachines
+	broker := config.Broker
 	config.GetRequiredLXDProfiles = func(modelName string) []string {
-		return []string{"default", "juju-" + modelName}
+		return environRequiredLXDProfiles(broker, modelName)
 	}
 	config.GetRequiredContext = func(ctx MutaterContext) MutaterContext {
 		return ctx
 	}
-	return newWorker(config)
+	setProfileDefaults(&config)
+	return newWorker(config, "environ")
+}
+
+// environRequiredLXDProfiles returns the profiles that every machine in
+// the model requires: the default profile, those named by the model's
+// lxd-required-profiles config, which is read from the broker if it is an
+// environ so that changes to it are seen, and the model's own profile.
+func environRequiredLXDProfiles(broker environs.MutaterBroker, modelName string) []string {
+	profiles := []string{"default"}
+	if getter, ok := broker.(environs.ConfigGetter); ok {
+		profiles = append(profiles, getter.Config().LXDRequiredProfiles()...)
+	}
+	return append(profiles, "juju-"+modelName)
 }
 
 // NewContainerWorker returns a worker that keeps track of
@@ -128,13 +246,46 @@ func NewContainerWorker(config Config) (worker.Worker, error) {
 	config.GetRequiredContext = func(ctx MutaterContext) MutaterContext {
 		return ctx
 	}
-	return newWorker(config)
+	setProfileDefaults(&config)
+	return newWorker(config, "container")
 }
 
-func newWorker(config Config) (*mutaterWorker, error) {
+// setProfileDefaults enables the coalescing of lxd profile changes, the
+// periodic verification of applied profiles, the removal of unused
+// profiles and the retrying of broker errors, unless the config already
+// specifies how they should be done.
+func setProfileDefaults(config *Config) {
+	if config.Clock == nil {
+		config.Clock = clock.WallClock
+	}
+	if config.ProfileBatchWindow == 0 {
+		config.ProfileBatchWindow = defaultProfileBatchWindow
+	}
+	if config.ProfileVerifyInterval == 0 {
+		config.ProfileVerifyInterval = defaultProfileVerifyInterval
+	}
+	if config.ProfileCleanupInterval == 0 {
+		config.ProfileCleanupInterval = defaultProfileCleanupInterval
+	}
+	if featureflag.Enabled(feature.LXDProfileCleanupDryRun) {
+		config.ProfileCleanupDryRun = true
+	}
+	if config.BrokerRetryAttempts == 0 {
+		config.BrokerRetryAttempts = defaultBrokerRetryAttempts
+		config.BrokerRetryDelay = defaultBrokerRetryDelay
+	}
+}
+
+// newWorker returns a new worker for the given config; kind identifies
+// the worker, environ or container, in its metrics.
+func newWorker(config Config, kind string) (*mutaterWorker, error) {
 	if err := config.Validate(); err != nil {
 		return nil, errors.Trace(err)
 	}
+	var modelUUID string
+	if config.PrometheusRegisterer != nil {
+		modelUUID = config.AgentConfig.Model().Id()
+	}
 	watcher, err := config.GetMachineWatcher()
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -143,10 +294,22 @@ func newWorker(config Config) (*mutaterWorker, error) {
 		logger:                     config.Logger,
 		facade:                     config.Facade,
 		broker:                     config.Broker,
+		containerBrokers:           config.ContainerBrokers,
 		machineTag:                 config.Tag.(names.MachineTag),
 		machineWatcher:             watcher,
 		getRequiredLXDProfilesFunc: config.GetRequiredLXDProfiles,
 		getRequiredContextFunc:     config.GetRequiredContext,
+		clock:                      config.Clock,
+		profileBatchWindow:         config.ProfileBatchWindow,
+		profileVerifyInterval:      config.ProfileVerifyInterval,
+		brokerRetryAttempts:        config.BrokerRetryAttempts,
+		profileCleanupInterval:     config.ProfileCleanupInterval,
+		brokerRetryDelay:           config.BrokerRetryDelay,
+		metrics:                    newMetrics(modelUUID, kind),
+		registerer:                 config.PrometheusRegisterer,
+	}
+	if cleaner, ok := config.Broker.(environs.LXDProfileCleaner); ok && config.ProfileCleanupInterval > 0 {
+		w.profileCleaner = newProfileCleaner(cleaner, config.Logger, config.ProfileCleanupDryRun)
 	}
 	// getRequiredContextFunc returns a MutaterContext, this is for overriding
 	// during testing.
@@ -165,25 +328,58 @@ type mutaterWorker struct {
 	catacomb catacomb.Catacomb
 
 	logger                     Logger
-	broker                     environs.LXDProfiler
+	broker                     environs.MutaterBroker
+	containerBrokers           map[instance.ContainerType]environs.MutaterBroker
 	machineTag                 names.MachineTag
 	facade                     InstanceMutaterAPI
 	machineWatcher             watcher.StringsWatcher
 	getRequiredLXDProfilesFunc RequiredLXDProfilesFunc
 	getRequiredContextFunc     RequiredMutaterContextFunc
+	clock                      clock.Clock
+	profileBatchWindow         time.Duration
+	profileVerifyInterval      time.Duration
+	brokerRetryAttempts        int
+	brokerRetryDelay           time.Duration
+	profileCleanupInterval     time.Duration
+	profileCleaner             *profileCleaner
+	metrics                    *metrics
+	registerer                 prometheus.Registerer
 }
 
 func (w *mutaterWorker) loop() error {
+	if w.registerer != nil {
+		if err := w.registerer.Register(w.metrics); err != nil {
+			w.logger.Warningf("cannot register instancemutater metrics: %v", err)
+		} else {
+			defer w.registerer.Unregister(w.metrics)
+		}
+	}
 	m := &mutater{
-		context:     w.getRequiredContextFunc(w),
-		logger:      w.logger,
-		machines:    make(map[names.MachineTag]chan struct{}),
-		machineDead: make(chan instancemutater.MutaterMachine),
+		context:        w.getRequiredContextFunc(w),
+		logger:         w.logger,
+		machines:       make(map[names.MachineTag]chan struct{}),
+		machineDead:    make(chan instancemutater.MutaterMachine),
+		clock:          w.clock,
+		batchWindow:    w.profileBatchWindow,
+		verifyInterval: w.profileVerifyInterval,
+		retryAttempts:  w.brokerRetryAttempts,
+		retryDelay:     w.brokerRetryDelay,
+		metrics:        w.metrics,
+		brokers:        w.containerBrokers,
+	}
+	// cleanup fires when the unused charm profiles are next to be
+	// removed; it is nil if the broker cannot remove them.
+	var cleanup <-chan time.Time
+	if w.profileCleaner != nil {
+		cleanup = w.clock.After(w.profileCleanupInterval)
 	}
 	for {
 		select {
 		case <-m.context.dying():
 			return m.context.errDying()
+		case <-cleanup:
+			w.profileCleaner.cleanup(w.clock.Now())
+			cleanup = w.clock.After(w.profileCleanupInterval)
 		case ids, ok := <-w.machineWatcher.Changes():
 			if !ok {
 				return errors.New("machines watcher closed")
@@ -201,6 +397,17 @@ func (w *mutaterWorker) loop() error {
 	}
 }
 
+// Report is part of the dependency.Reporter interface. It reports the
+// outcome of the last removal of unused lxd profiles.
+func (w *mutaterWorker) Report() map[string]interface{} {
+	if w.profileCleaner == nil {
+		return map[string]interface{}{}
+	}
+	return map[string]interface{}{
+		"lxd-profile-cleanup": w.profileCleaner.report(),
+	}
+}
+
 // Kill implements worker.Worker.Kill.
 func (w *mutaterWorker) Kill() {
 	w.catacomb.Kill(nil)
@@ -230,7 +437,7 @@ func (w *mutaterWorker) getMachine(tag names.MachineTag) (instancemutater.Mutate
 }
 
 // getBroker is part of the MachineContext interface.
-func (w *mutaterWorker) getBroker() environs.LXDProfiler {
+func (w *mutaterWorker) getBroker() environs.MutaterBroker {
 	return w.broker
 }
 
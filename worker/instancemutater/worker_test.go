@@ -9,10 +9,12 @@ import (
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/juju/clock/testclock"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
+	"github.com/prometheus/client_golang/prometheus"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v3"
 	"gopkg.in/juju/worker.v1"
@@ -24,6 +26,9 @@ import (
 	"github.com/juju/juju/core/lxdprofile"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/core/watcher"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/worker/instancemutater"
 	"github.com/juju/juju/worker/instancemutater/mocks"
 	workermocks "github.com/juju/juju/worker/mocks"
@@ -115,6 +120,74 @@ func (s *workerConfigSuite) TestInvalidConfigValidate(c *gc.C) {
 			},
 			err: "nil GetRequiredLXDProfiles not valid",
 		},
+		{
+			description: "Test no Clock with ProfileBatchWindow",
+			config: instancemutater.Config{
+				Logger:                 mocks.NewMockLogger(ctrl),
+				Facade:                 mocks.NewMockInstanceMutaterAPI(ctrl),
+				Broker:                 mocks.NewMockLXDProfiler(ctrl),
+				AgentConfig:            mocks.NewMockConfig(ctrl),
+				Tag:                    names.NewMachineTag("3"),
+				GetMachineWatcher:      getMachineWatcher,
+				GetRequiredLXDProfiles: func(_ string) []string { return nil },
+				GetRequiredContext: func(w instancemutater.MutaterContext) instancemutater.MutaterContext {
+					return w
+				},
+				ProfileBatchWindow: time.Second,
+			},
+			err: "nil Clock not valid",
+		},
+		{
+			description: "Test no Clock with ProfileVerifyInterval",
+			config: instancemutater.Config{
+				Logger:                 mocks.NewMockLogger(ctrl),
+				Facade:                 mocks.NewMockInstanceMutaterAPI(ctrl),
+				Broker:                 mocks.NewMockLXDProfiler(ctrl),
+				AgentConfig:            mocks.NewMockConfig(ctrl),
+				Tag:                    names.NewMachineTag("3"),
+				GetMachineWatcher:      getMachineWatcher,
+				GetRequiredLXDProfiles: func(_ string) []string { return nil },
+				GetRequiredContext: func(w instancemutater.MutaterContext) instancemutater.MutaterContext {
+					return w
+				},
+				ProfileVerifyInterval: time.Minute,
+			},
+			err: "nil Clock not valid",
+		},
+		{
+			description: "Test negative ProfileCleanupInterval",
+			config: instancemutater.Config{
+				Logger:                 mocks.NewMockLogger(ctrl),
+				Facade:                 mocks.NewMockInstanceMutaterAPI(ctrl),
+				Broker:                 mocks.NewMockLXDProfiler(ctrl),
+				AgentConfig:            mocks.NewMockConfig(ctrl),
+				Tag:                    names.NewMachineTag("3"),
+				GetMachineWatcher:      getMachineWatcher,
+				GetRequiredLXDProfiles: func(_ string) []string { return nil },
+				GetRequiredContext: func(w instancemutater.MutaterContext) instancemutater.MutaterContext {
+					return w
+				},
+				ProfileCleanupInterval: -time.Minute,
+			},
+			err: "negative ProfileCleanupInterval not valid",
+		},
+		{
+			description: "Test no BrokerRetryDelay with BrokerRetryAttempts",
+			config: instancemutater.Config{
+				Logger:                 mocks.NewMockLogger(ctrl),
+				Facade:                 mocks.NewMockInstanceMutaterAPI(ctrl),
+				Broker:                 mocks.NewMockLXDProfiler(ctrl),
+				AgentConfig:            mocks.NewMockConfig(ctrl),
+				Tag:                    names.NewMachineTag("3"),
+				GetMachineWatcher:      getMachineWatcher,
+				GetRequiredLXDProfiles: func(_ string) []string { return nil },
+				GetRequiredContext: func(w instancemutater.MutaterContext) instancemutater.MutaterContext {
+					return w
+				},
+				BrokerRetryAttempts: 3,
+			},
+			err: "non-positive BrokerRetryDelay not valid",
+		},
 	}
 	for i, test := range testcases {
 		c.Logf("%d %s", i, test.description)
@@ -160,6 +233,19 @@ type workerSuite struct {
 	appLXDProfileWorker    map[int]*workermocks.MockWorker
 	getRequiredLXDProfiles instancemutater.RequiredLXDProfilesFunc
 
+	// retryClock and retryAttempts, if set, configure the retrying of
+	// broker errors by workers created for a scenario.
+	retryClock    *testclock.Clock
+	retryAttempts int
+
+	// registry, if set, is used to register the metrics of workers
+	// created for a scenario.
+	registry *prometheus.Registry
+
+	// containerBrokers, if set, are used by workers created for a
+	// scenario to mutate containers of types other than lxd.
+	containerBrokers map[instance.ContainerType]environs.MutaterBroker
+
 	// doneWG is a collection of things each test needs to wait to
 	// be completed within the test.
 	doneWG sync.WaitGroup
@@ -177,6 +263,43 @@ func (s *workerSuite) SetUpTest(c *gc.C) {
 	s.getRequiredLXDProfiles = func(modelName string) []string {
 		return []string{"default", "juju-testing"}
 	}
+	s.retryClock = nil
+	s.retryAttempts = 0
+	s.containerBrokers = nil
+	s.registry = nil
+}
+
+type requiredProfilesSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&requiredProfilesSuite{})
+
+// configBroker is a MutaterBroker which is also an environ, with model
+// config.
+type configBroker struct {
+	environs.MutaterBroker
+	cfg *config.Config
+}
+
+func (b configBroker) Config() *config.Config {
+	return b.cfg
+}
+
+func (s *requiredProfilesSuite) TestEnvironRequiredLXDProfiles(c *gc.C) {
+	cfg, err := coretesting.ModelConfig(c).Apply(map[string]interface{}{
+		"lxd-required-profiles": "site-base,site-audit",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	profiles := instancemutater.EnvironRequiredLXDProfiles(configBroker{cfg: cfg}, "testing")
+	c.Assert(profiles, jc.DeepEquals, []string{"default", "site-base", "site-audit", "juju-testing"})
+}
+
+func (s *requiredProfilesSuite) TestEnvironRequiredLXDProfilesNoConfig(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	profiles := instancemutater.EnvironRequiredLXDProfiles(mocks.NewMockMutaterBroker(ctrl), "testing")
+	c.Assert(profiles, jc.DeepEquals, []string{"default", "juju-testing"})
 }
 
 type workerEnvironSuite struct {
@@ -205,6 +328,164 @@ func (s *workerEnvironSuite) TestFullWorkflow(c *gc.C) {
 	s.cleanKill(c, s.workerForScenario(c))
 }
 
+func (s *workerEnvironSuite) TestMetrics(c *gc.C) {
+	defer s.setup(c, 1).Finish()
+
+	s.ignoreLogging(c)
+	s.notifyMachines([][]string{{"0"}})
+	s.expectFacadeMachineTag(0)
+	s.notifyMachineAppLXDProfile(0, 1)
+	s.expectMachineCharmProfilingInfo(0, 3)
+	s.expectLXDProfileNamesTrue()
+	s.expectSetCharmProfiles(0)
+	s.expectAssignLXDProfiles()
+	s.expectAliveAndSetModificationStatusIdle(0)
+	s.expectModificationStatusApplied(0)
+	s.agentConfig.EXPECT().Model().Return(coretesting.ModelTag)
+
+	s.registry = prometheus.NewRegistry()
+	w := s.workerForScenario(c)
+	s.waitDone(c)
+
+	families, err := s.registry.Gather()
+	c.Assert(err, jc.ErrorIsNil)
+	values := make(map[string]float64)
+	for _, family := range families {
+		c.Assert(family.Metric, gc.HasLen, 1)
+		metric := family.Metric[0]
+		labels := make(map[string]string)
+		for _, label := range metric.Label {
+			labels[label.GetName()] = label.GetValue()
+		}
+		c.Check(labels, jc.DeepEquals, map[string]string{
+			"model":  coretesting.ModelTag.Id(),
+			"worker": "environ",
+		})
+		if metric.Counter != nil {
+			values[family.GetName()] = metric.Counter.GetValue()
+		}
+	}
+	c.Check(values, jc.DeepEquals, map[string]float64{
+		"juju_instancemutater_profiles_applied_total":      1,
+		"juju_instancemutater_verification_failures_total": 0,
+		"juju_instancemutater_broker_errors_total":         0,
+	})
+
+	// The metrics are unregistered when the worker stops.
+	workertest.CleanKill(c, w)
+	families, err = s.registry.Gather()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(families, gc.HasLen, 0)
+}
+
+func (s *workerEnvironSuite) TestProfileChangesCoalesced(c *gc.C) {
+	defer s.setup(c, 1).Finish()
+
+	s.ignoreLogging(c)
+	s.notifyMachines([][]string{{"0"}})
+	s.expectFacadeMachineTag(0)
+	ch := s.machineAppLXDProfileChannel(0)
+	// The three changes below result in a single profile update.
+	s.expectMachineCharmProfilingInfo(0, 3)
+	s.expectLXDProfileNamesTrue()
+	s.expectSetCharmProfiles(0)
+	s.expectAssignLXDProfiles()
+	s.expectAliveAndSetModificationStatusIdle(0)
+	s.expectModificationStatusApplied(0)
+
+	clock := testclock.NewClock(time.Now())
+	w := s.workerForBatchingScenario(c, clock, time.Second)
+	for i := 0; i < 3; i++ {
+		select {
+		case ch <- struct{}{}:
+		case <-time.After(testing.LongWait):
+			c.Fatalf("timed out sending profile change %d", i)
+		}
+	}
+	err := clock.WaitAdvance(time.Second, testing.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.cleanKill(c, w)
+}
+
+func (s *workerEnvironSuite) TestBrokerErrorRetried(c *gc.C) {
+	defer s.setup(c, 1).Finish()
+
+	s.ignoreLogging(c)
+	s.notifyMachines([][]string{{"0"}})
+	s.expectFacadeMachineTag(0)
+	s.notifyMachineAppLXDProfile(0, 1)
+	// The first attempt fails as the lxd daemon is unavailable...
+	s.expectMachineCharmProfilingInfo(0, 3)
+	s.expectAliveAndSetModificationStatusIdle(0)
+	s.expectLXDProfileNamesError(errors.New("lxd unavailable"))
+	s.expectModificationStatusError(0)
+	// ...and the retry succeeds.
+	s.expectMachineCharmProfilingInfo(0, 3)
+	s.expectAliveAndSetModificationStatusIdle(0)
+	s.expectLXDProfileNamesTrue()
+	s.expectAssignLXDProfiles()
+	s.expectSetCharmProfiles(0)
+	s.expectModificationStatusApplied(0)
+
+	clock := testclock.NewClock(time.Now())
+	s.retryClock = clock
+	s.retryAttempts = 3
+	w := s.workerForScenario(c)
+	err := clock.WaitAdvance(2*time.Second, testing.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.cleanKill(c, w)
+}
+
+func (s *workerEnvironSuite) TestBrokerErrorRetriesExhausted(c *gc.C) {
+	defer s.setup(c, 1).Finish()
+
+	s.ignoreLogging(c)
+	s.notifyMachines([][]string{{"0"}})
+	s.expectFacadeMachineTag(0)
+	s.notifyMachineAppLXDProfile(0, 1)
+	for i := 0; i < 2; i++ {
+		s.expectMachineCharmProfilingInfo(0, 3)
+		s.expectAliveAndSetModificationStatusIdle(0)
+		s.expectLXDProfileNamesError(errors.New("lxd unavailable"))
+		s.expectModificationStatusError(0)
+	}
+	s.expectContextKillError()
+
+	clock := testclock.NewClock(time.Now())
+	s.retryClock = clock
+	s.retryAttempts = 1
+	w := s.workerForScenarioWithContext(c)
+	err := clock.WaitAdvance(2*time.Second, testing.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.errorKill(c, w)
+	c.Assert(err, gc.ErrorMatches, "0: lxd unavailable")
+}
+
+func (s *workerEnvironSuite) TestFatalBrokerErrorNotRetried(c *gc.C) {
+	defer s.setup(c, 1).Finish()
+
+	s.ignoreLogging(c)
+	s.notifyMachines([][]string{{"0"}})
+	s.expectFacadeMachineTag(0)
+	s.notifyMachineAppLXDProfile(0, 1)
+	s.expectMachineCharmProfilingInfo(0, 3)
+	s.expectAliveAndSetModificationStatusIdle(0)
+	s.expectLXDProfileNamesError(errors.NotSupportedf("lxd profiles"))
+	s.expectModificationStatusError(0)
+	s.expectContextKillError()
+
+	clock := testclock.NewClock(time.Now())
+	s.retryClock = clock
+	s.retryAttempts = 3
+	w := s.workerForScenarioWithContext(c)
+
+	err := s.errorKill(c, w)
+	c.Assert(err, gc.ErrorMatches, "0: lxd profiles not supported")
+}
+
 func (s *workerEnvironSuite) TestVerifyCurrentProfilesTrue(c *gc.C) {
 	defer s.setup(c, 1).Finish()
 
@@ -359,7 +640,10 @@ func (s *workerSuite) workerForScenario(c *gc.C) worker.Worker {
 		AgentConfig:            s.agentConfig,
 		Tag:                    s.machineTag,
 		GetRequiredLXDProfiles: s.getRequiredLXDProfiles,
+		ContainerBrokers:       s.containerBrokers,
 	}
+	s.setRetryConfig(&config)
+	s.setMetricsConfig(&config)
 
 	w, err := s.newWorkerFunc(config, func(ctx instancemutater.MutaterContext) instancemutater.MutaterContext {
 		return ctx
@@ -376,7 +660,10 @@ func (s *workerSuite) workerForScenarioWithContext(c *gc.C) worker.Worker {
 		AgentConfig:            s.agentConfig,
 		Tag:                    s.machineTag,
 		GetRequiredLXDProfiles: s.getRequiredLXDProfiles,
+		ContainerBrokers:       s.containerBrokers,
 	}
+	s.setRetryConfig(&config)
+	s.setMetricsConfig(&config)
 
 	w, err := s.newWorkerFunc(config, func(ctx instancemutater.MutaterContext) instancemutater.MutaterContext {
 		c := mutaterContextShim{
@@ -389,6 +676,46 @@ func (s *workerSuite) workerForScenarioWithContext(c *gc.C) worker.Worker {
 	return w
 }
 
+// setRetryConfig configures the retrying of broker errors, if the test
+// has asked for it.
+func (s *workerSuite) setRetryConfig(config *instancemutater.Config) {
+	if s.retryAttempts == 0 {
+		return
+	}
+	config.Clock = s.retryClock
+	config.BrokerRetryAttempts = s.retryAttempts
+	config.BrokerRetryDelay = time.Second
+}
+
+// setMetricsConfig configures the registration of metrics, if the test
+// has asked for it.
+func (s *workerSuite) setMetricsConfig(config *instancemutater.Config) {
+	if s.registry != nil {
+		config.PrometheusRegisterer = s.registry
+	}
+}
+
+// workerForBatchingScenario creates a worker, as workerForScenario does,
+// that coalesces profile changes within the supplied window.
+func (s *workerSuite) workerForBatchingScenario(c *gc.C, clock *testclock.Clock, window time.Duration) worker.Worker {
+	config := instancemutater.Config{
+		Facade:                 s.facade,
+		Logger:                 s.logger,
+		Broker:                 s.broker,
+		AgentConfig:            s.agentConfig,
+		Tag:                    s.machineTag,
+		GetRequiredLXDProfiles: s.getRequiredLXDProfiles,
+		Clock:                  clock,
+		ProfileBatchWindow:     window,
+	}
+
+	w, err := s.newWorkerFunc(config, func(ctx instancemutater.MutaterContext) instancemutater.MutaterContext {
+		return ctx
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return w
+}
+
 // workerErrorForScenario creates worker config based on the suite's mocks.
 // Any supplied behaviour functions are executed, then a new worker is
 // started and returned with any error in creation.
@@ -437,6 +764,10 @@ func (s *workerSuite) expectLXDProfileNamesTrue() {
 	s.broker.EXPECT().LXDProfileNames("juju-23423-0").Return([]string{"default", "juju-testing", "juju-testing-one-2"}, nil)
 }
 
+func (s *workerSuite) expectLXDProfileNamesError(err error) {
+	s.broker.EXPECT().LXDProfileNames("juju-23423-0").Return(nil, err)
+}
+
 func (s *workerSuite) expectMachineCharmProfilingInfo(machine, rev int) {
 	s.expectCharmProfilingInfo(s.machine[machine], rev)
 }
@@ -517,6 +848,10 @@ func (s *workerSuite) expectModificationStatusApplied(machine int) {
 	s.machine[machine].EXPECT().SetModificationStatus(status.Applied, "", nil).Return(nil).Do(do)
 }
 
+func (s *workerSuite) expectModificationStatusError(machine int) {
+	s.machine[machine].EXPECT().SetModificationStatus(status.Error, gomock.Any(), gomock.Any()).Return(nil)
+}
+
 func (s *workerSuite) expectAssignLXDProfiles() {
 	profiles := []string{"default", "juju-testing", "juju-testing-one-3"}
 	s.broker.EXPECT().AssignLXDProfiles("juju-23423-0", profiles, gomock.Any()).Return(profiles, nil)
@@ -548,7 +883,7 @@ func (s *workerSuite) notifyMachines(values [][]string) {
 	s.machinesWorker.EXPECT().Kill().AnyTimes()
 	s.machinesWorker.EXPECT().Wait().Return(nil).AnyTimes()
 
-	s.facade.EXPECT().WatchMachines().Return(
+	s.facade.EXPECT().WatchLXDProfileMachines().Return(
 		&fakeStringsWatcher{
 			Worker: s.machinesWorker,
 			ch:     ch,
@@ -569,7 +904,7 @@ func (s *workerSuite) notifyMachinesWaitGroup(values [][]string, group *sync.Wai
 	s.machinesWorker.EXPECT().Kill().AnyTimes()
 	s.machinesWorker.EXPECT().Wait().Return(nil).AnyTimes()
 
-	s.facade.EXPECT().WatchMachines().Return(
+	s.facade.EXPECT().WatchLXDProfileMachines().Return(
 		&fakeStringsWatcher{
 			Worker: s.machinesWorker,
 			ch:     ch,
@@ -608,6 +943,22 @@ func (s *workerSuite) notifyAppLXDProfile(mock *mocks.MockMutaterMachine, which,
 		}, nil)
 }
 
+// machineAppLXDProfileChannel returns the channel on which the machine's
+// profile change watcher delivers notifications, for the test to drive.
+func (s *workerSuite) machineAppLXDProfileChannel(machine int) chan<- struct{} {
+	ch := make(chan struct{})
+	w := s.appLXDProfileWorker[machine]
+	w.EXPECT().Kill().AnyTimes()
+	w.EXPECT().Wait().Return(nil).AnyTimes()
+
+	s.machine[machine].EXPECT().WatchLXDProfileVerificationNeeded().Return(
+		&fakeNotifyWatcher{
+			Worker: w,
+			ch:     ch,
+		}, nil)
+	return ch
+}
+
 // mutaterContextShim is required to override the KillWithError context. We
 // can't mock out the whole thing as their are private methods, so we just
 // compose it and send it back with a new KillWithError method.
@@ -739,6 +1090,38 @@ func (s *workerContainerSuite) TestFullWorkflow(c *gc.C) {
 	s.cleanKill(c, s.workerForScenario(c))
 }
 
+func (s *workerContainerSuite) TestKVMContainerWithBroker(c *gc.C) {
+	ctrl := s.setup(c)
+	defer ctrl.Finish()
+
+	kvmBroker := mocks.NewMockMutaterBroker(ctrl)
+	s.containerBrokers = map[instance.ContainerType]environs.MutaterBroker{
+		instance.KVM: kvmBroker,
+	}
+
+	s.ignoreLogging(c)
+	s.notifyContainers(0, [][]string{{"0/kvm/0"}})
+	s.expectFacadeMachineTag(0)
+	s.expectFacadeContainerTags()
+	s.expectContainerTypes()
+	s.notifyAppLXDProfile(s.kvmContainer, 0, 1)
+	s.expectCharmProfilingInfo(s.kvmContainer, 3)
+
+	profiles := []string{"default", "juju-testing-one-3"}
+	kvmBroker.EXPECT().LXDProfileNames("juju-23423-0").Return([]string{"default", "juju-testing", "juju-testing-one-2"}, nil)
+	kvmBroker.EXPECT().AssignLXDProfiles("juju-23423-0", profiles, gomock.Any()).Return(profiles, nil)
+
+	cExp := s.kvmContainer.EXPECT()
+	cExp.SetCharmProfiles(profiles)
+	cExp.Refresh().Return(nil)
+	cExp.Life().Return(params.Alive)
+	cExp.SetModificationStatus(status.Idle, gomock.Any(), gomock.Any()).Return(nil)
+	do := s.workGroupAddGetDoneFunc()
+	cExp.SetModificationStatus(status.Applied, "", nil).Return(nil).Do(do)
+
+	s.cleanKill(c, s.workerForScenario(c))
+}
+
 func (s *workerContainerSuite) setup(c *gc.C) *gomock.Controller {
 	ctrl := s.workerSuite.setup(c, 1)
 	s.lxdContainer = mocks.NewMockMutaterMachine(ctrl)
@@ -76,12 +76,41 @@ func (LeadershipSecretary) CheckDuration(duration time.Duration) error {
 	return nil
 }
 
+// OperatorSecretary implements Secretary; it checks that leases are
+// application names, and holders are non-empty pod names.
+type OperatorSecretary struct{}
+
+// CheckLease is part of the lease.Secretary interface.
+func (OperatorSecretary) CheckLease(key lease.Key) error {
+	if !names.IsValidApplication(key.Lease) {
+		return errors.NewNotValid(nil, "not an application name")
+	}
+	return nil
+}
+
+// CheckHolder is part of the lease.Secretary interface.
+func (OperatorSecretary) CheckHolder(name string) error {
+	if name == "" {
+		return errors.NewNotValid(nil, "empty pod name")
+	}
+	return nil
+}
+
+// CheckDuration is part of the lease.Secretary interface.
+func (OperatorSecretary) CheckDuration(duration time.Duration) error {
+	if duration <= 0 {
+		return errors.NewNotValid(nil, "non-positive")
+	}
+	return nil
+}
+
 // SecretaryFinder returns a function to find the correct secretary to
 // use for validation for a specific lease namespace (or an error if
 // the namespace isn't valid).
 func SecretaryFinder(controllerUUID string) func(string) (Secretary, error) {
 	secretaries := map[string]Secretary{
 		lease.ApplicationLeadershipNamespace: LeadershipSecretary{},
+		lease.ApplicationOperatorNamespace:   OperatorSecretary{},
 		lease.SingularControllerNamespace: SingularSecretary{
 			ControllerUUID: controllerUUID,
 		},
@@ -0,0 +1,117 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logforwarder
+
+import (
+	"sync"
+
+	"github.com/juju/juju/logfwd"
+)
+
+// defaultMaxBufferedRecords is the number of records read from the log
+// stream that may be waiting to be sent before the oldest are dropped.
+const defaultMaxBufferedRecords = 10000
+
+// recordBuffer holds the batches of records read from the log stream
+// until they are sent. The stream is never blocked by a slow or
+// unreachable log sink: once the buffer holds its limit of records, the
+// oldest records are dropped to make room for new ones, and counted.
+type recordBuffer struct {
+	limit int
+	ready chan struct{}
+	empty chan struct{}
+
+	mu      sync.Mutex
+	batches [][]logfwd.Record
+	size    int
+	dropped int64
+}
+
+func newRecordBuffer(limit int) *recordBuffer {
+	if limit <= 0 {
+		limit = defaultMaxBufferedRecords
+	}
+	return &recordBuffer{
+		limit: limit,
+		ready: make(chan struct{}, 1),
+		empty: make(chan struct{}, 1),
+	}
+}
+
+// add adds a batch of records to the buffer, dropping the oldest
+// records if there is no room for them.
+func (b *recordBuffer) add(records []logfwd.Record) {
+	if len(records) == 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(records) > b.limit {
+		b.dropped += int64(len(records) - b.limit)
+		records = records[len(records)-b.limit:]
+	}
+	for b.size+len(records) > b.limit {
+		oldest := b.batches[0]
+		n := b.size + len(records) - b.limit
+		if n >= len(oldest) {
+			n = len(oldest)
+			b.batches = b.batches[1:]
+		} else {
+			b.batches[0] = oldest[n:]
+		}
+		b.size -= n
+		b.dropped += int64(n)
+	}
+	b.batches = append(b.batches, records)
+	b.size += len(records)
+	signal(b.ready)
+}
+
+// take removes and returns the oldest batch of records in the buffer,
+// along with the number of records dropped since the last call.
+func (b *recordBuffer) take() ([]logfwd.Record, int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	dropped := b.dropped
+	b.dropped = 0
+	if len(b.batches) == 0 {
+		return nil, dropped
+	}
+	records := b.batches[0]
+	b.batches = b.batches[1:]
+	b.size -= len(records)
+	if len(b.batches) > 0 {
+		signal(b.ready)
+	} else {
+		signal(b.empty)
+	}
+	return records, dropped
+}
+
+// waitEmpty blocks until every record added to the buffer has been
+// taken, or abort is closed.
+func (b *recordBuffer) waitEmpty(abort <-chan struct{}) {
+	for {
+		b.mu.Lock()
+		size := b.size
+		b.mu.Unlock()
+		if size == 0 {
+			return
+		}
+		select {
+		case <-abort:
+			return
+		case <-b.empty:
+		}
+	}
+}
+
+// signal notifies the channel, without blocking if a notification is
+// already pending.
+func signal(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logforwarder_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/logfwd"
+	"github.com/juju/juju/worker/logforwarder"
+)
+
+type RecordBufferSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&RecordBufferSuite{})
+
+func records(ids ...int64) []logfwd.Record {
+	result := make([]logfwd.Record, len(ids))
+	for i, id := range ids {
+		result[i].ID = id
+	}
+	return result
+}
+
+func (s *RecordBufferSuite) TestTakeInOrder(c *gc.C) {
+	buffer := logforwarder.NewRecordBuffer(10)
+	buffer.Add(records(1, 2))
+	buffer.Add(records(3))
+
+	select {
+	case <-buffer.Ready():
+	default:
+		c.Fatalf("buffer not ready")
+	}
+	recs, dropped := buffer.Take()
+	c.Check(recs, jc.DeepEquals, records(1, 2))
+	c.Check(dropped, gc.Equals, int64(0))
+	recs, dropped = buffer.Take()
+	c.Check(recs, jc.DeepEquals, records(3))
+	c.Check(dropped, gc.Equals, int64(0))
+	recs, _ = buffer.Take()
+	c.Check(recs, gc.HasLen, 0)
+}
+
+func (s *RecordBufferSuite) TestDropsOldest(c *gc.C) {
+	buffer := logforwarder.NewRecordBuffer(4)
+	buffer.Add(records(1, 2))
+	buffer.Add(records(3, 4))
+	buffer.Add(records(5))
+	buffer.Add(records(6, 7))
+
+	recs, dropped := buffer.Take()
+	c.Check(recs, jc.DeepEquals, records(4))
+	c.Check(dropped, gc.Equals, int64(3))
+	recs, dropped = buffer.Take()
+	c.Check(recs, jc.DeepEquals, records(5))
+	c.Check(dropped, gc.Equals, int64(0))
+	recs, _ = buffer.Take()
+	c.Check(recs, jc.DeepEquals, records(6, 7))
+}
+
+func (s *RecordBufferSuite) TestDropsFromLargeBatch(c *gc.C) {
+	buffer := logforwarder.NewRecordBuffer(2)
+	buffer.Add(records(1))
+	buffer.Add(records(2, 3, 4))
+
+	recs, dropped := buffer.Take()
+	c.Check(recs, jc.DeepEquals, records(3, 4))
+	c.Check(dropped, gc.Equals, int64(2))
+}
@@ -0,0 +1,29 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logforwarder
+
+import (
+	"github.com/juju/juju/logfwd"
+)
+
+// RecordBuffer exposes a recordBuffer for testing.
+type RecordBuffer struct {
+	*recordBuffer
+}
+
+func NewRecordBuffer(limit int) RecordBuffer {
+	return RecordBuffer{newRecordBuffer(limit)}
+}
+
+func (b RecordBuffer) Add(records []logfwd.Record) {
+	b.add(records)
+}
+
+func (b RecordBuffer) Take() ([]logfwd.Record, int64) {
+	return b.take()
+}
+
+func (b RecordBuffer) Ready() <-chan struct{} {
+	return b.ready
+}
@@ -51,6 +51,13 @@ type LogForwarder struct {
 	enabledCh chan bool
 	mu        sync.Mutex
 	enabled   bool
+
+	buffer *recordBuffer
+	counts *countsTracker
+	// sent and dropped hold the counts not yet added to the
+	// controller's totals.
+	sent    int64
+	dropped int64
 }
 
 // OpenLogForwarderArgs holds the info needed to open a LogForwarder.
@@ -74,6 +81,12 @@ type OpenLogForwarderArgs struct {
 	// OpenLogStream is the function that will be used to for the
 	// log stream.
 	OpenLogStream LogStreamFn
+
+	// MaxBufferedRecords is the number of records read from the log
+	// stream that may be waiting to be sent, if the log sink cannot
+	// keep up, before the oldest are dropped. If zero, a default
+	// of 10000 is used.
+	MaxBufferedRecords int
 }
 
 // processNewConfig acts on a new syslog forward config change.
@@ -157,6 +170,8 @@ func NewLogForwarder(args OpenLogForwarderArgs) (*LogForwarder, error) {
 	lf := &LogForwarder{
 		args:      args,
 		enabledCh: make(chan bool, 1),
+		buffer:    newRecordBuffer(args.MaxBufferedRecords),
+		counts:    newCountsTracker(args.Name, args.Caller),
 	}
 	err := catacomb.Invoke(catacomb.Plan{
 		Site: &lf.catacomb,
@@ -179,7 +194,6 @@ func (lf *LogForwarder) loop() error {
 		return errors.Trace(err)
 	}
 
-	records := make(chan []logfwd.Record)
 	var stream LogStream
 	go func() {
 		for {
@@ -206,14 +220,15 @@ func (lf *LogForwarder) loop() error {
 			}
 			rec, err := stream.Next()
 			if err != nil {
+				// Forward the records already read before
+				// restarting.
+				lf.buffer.waitEmpty(lf.catacomb.Dying())
 				lf.catacomb.Kill(errors.Annotate(err, "getting next log record"))
 				break
 			}
-			select {
-			case <-lf.catacomb.Dying():
-				return
-			case records <- rec: // Wait until the last one is sent.
-			}
+			// Never wait for the records to be sent, so that a
+			// slow log sink doesn't hold up the stream.
+			lf.buffer.add(rec)
 		}
 	}()
 
@@ -235,17 +250,39 @@ func (lf *LogForwarder) loop() error {
 			if sender, err = lf.processNewConfig(sender); err != nil {
 				return errors.Trace(err)
 			}
-		case rec := <-records:
+		case <-lf.buffer.ready:
+			rec, dropped := lf.buffer.take()
 			if sender == nil {
 				continue
 			}
+			if dropped > 0 {
+				logger.Warningf("log sink %q could not keep up, dropped %d log records", lf.args.Name, dropped)
+				lf.dropped += dropped
+			}
+			if len(rec) == 0 {
+				continue
+			}
 			if err := sender.Send(rec); err != nil {
 				return errors.Trace(err)
 			}
+			lf.sent += int64(len(rec))
+			lf.reportCounts(rec[len(rec)-1].Origin.ModelUUID)
 		}
 	}
 }
 
+// reportCounts adds the numbers of records sent and dropped since the
+// last report to the controller's totals. Failing to do so is not fatal;
+// the counts are kept to be added by the next report.
+func (lf *LogForwarder) reportCounts(model string) {
+	if err := lf.counts.addCounts(model, lf.sent, lf.dropped); err != nil {
+		logger.Debugf("cannot record log forwarding counts: %v", err)
+		return
+	}
+	lf.sent = 0
+	lf.dropped = 0
+}
+
 // Kill implements Worker.Kill()
 func (lf *LogForwarder) Kill() {
 	lf.catacomb.Kill(nil)
@@ -206,6 +206,37 @@ func (s *LogForwarderSuite) TestSenderError(c *gc.C) {
 	})
 }
 
+func (s *LogForwarderSuite) TestCountsReported(c *gc.C) {
+	caller := &mockCaller{
+		addCounts: make(chan params.LogForwardingAddCountsParams, 1),
+	}
+	args := s.newLogForwarderArgs(c, s.stream, s.sender)
+	args.Caller = caller
+	args.Name = "test-sink"
+	s.stream.addRecords(c, s.rec)
+
+	lf, err := logforwarder.NewLogForwarder(args)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, lf)
+
+	s.sender.waitForSend(c)
+	select {
+	case counts := <-caller.addCounts:
+		c.Check(counts, jc.DeepEquals, params.LogForwardingAddCountsParams{
+			Params: []params.LogForwardingAddCountsParam{{
+				LogForwardingID: params.LogForwardingID{
+					ModelTag: "model-deadbeef-2f18-4fd2-967d-db9663db7bea",
+					Sink:     "test-sink",
+				},
+				Sent: 1,
+			}},
+		})
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for counts")
+	}
+	workertest.CleanKill(c, lf)
+}
+
 type mockLogForwardConfig struct {
 	enabled bool
 	host    string
@@ -230,9 +261,13 @@ func (*mockWatcher) Wait() error {
 
 type mockCaller struct {
 	base.APICaller
+	addCounts chan params.LogForwardingAddCountsParams
 }
 
-func (*mockCaller) APICall(objType string, version int, id, request string, params, response interface{}) error {
+func (c *mockCaller) APICall(objType string, version int, id, request string, args, response interface{}) error {
+	if request == "AddCounts" && c.addCounts != nil {
+		c.addCounts <- args.(params.LogForwardingAddCountsParams)
+	}
 	return nil
 }
 
@@ -0,0 +1,38 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package sinks
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/logfwd/https"
+	"github.com/juju/juju/logfwd/syslog"
+	"github.com/juju/juju/worker/logforwarder"
+)
+
+// OpenHTTPS returns a sink that posts log messages to be forwarded
+// to an https URL.
+func OpenHTTPS(cfg *syslog.RawConfig) (*logforwarder.LogSink, error) {
+	if !cfg.Enabled {
+		return nil, errors.New("log forwarding not enabled")
+	}
+	client, err := https.Open(*cfg)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &logforwarder.LogSink{
+		SendCloser: client,
+	}, nil
+}
+
+// Open returns the sink appropriate to the log forwarding target: an
+// https sink for https URLs, and a syslog sink otherwise.
+func Open(cfg *syslog.RawConfig) (*logforwarder.LogSink, error) {
+	if cfg.IsHTTPS() {
+		sink, err := OpenHTTPS(cfg)
+		return sink, errors.Trace(err)
+	}
+	sink, err := OpenSyslog(cfg)
+	return sink, errors.Trace(err)
+}
@@ -0,0 +1,42 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package sinks_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/logfwd/https"
+	"github.com/juju/juju/logfwd/syslog"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/logforwarder/sinks"
+)
+
+type HTTPSSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&HTTPSSuite{})
+
+func (s *HTTPSSuite) TestOpenHTTPS(c *gc.C) {
+	sink, err := sinks.Open(&syslog.RawConfig{
+		Enabled:    true,
+		Host:       "https://logs.example.com/juju",
+		CACert:     coretesting.CACert,
+		ClientCert: coretesting.ServerCert,
+		ClientKey:  coretesting.ServerKey,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	client, ok := sink.SendCloser.(*https.Client)
+	c.Assert(ok, jc.IsTrue)
+	c.Check(client.URL, gc.Equals, "https://logs.example.com/juju")
+}
+
+func (s *HTTPSSuite) TestOpenHTTPSNotEnabled(c *gc.C) {
+	_, err := sinks.Open(&syslog.RawConfig{
+		Host: "https://logs.example.com/juju",
+	})
+	c.Assert(err, gc.ErrorMatches, "log forwarding not enabled")
+}
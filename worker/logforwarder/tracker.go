@@ -104,3 +104,41 @@ func (lst lastSentTracker) setLastSent(records []logfwd.Record) error {
 	}
 	return nil
 }
+
+type countsTracker struct {
+	sink   string
+	client *logfwdapi.CountsClient
+}
+
+func newCountsTracker(sink string, caller base.APICaller) *countsTracker {
+	client := logfwdapi.NewCountsClient(func(name string) logfwdapi.FacadeCaller {
+		return base.NewFacadeCaller(caller, name)
+	})
+	return &countsTracker{
+		sink:   sink,
+		client: client,
+	}
+}
+
+// addCounts adds to the controller's counts of the records sent, and
+// dropped instead of being sent, for the model.
+func (ct countsTracker) addCounts(model string, sent, dropped int64) error {
+	if !names.IsValidModel(model) {
+		return errors.Errorf("bad model UUID %q", model)
+	}
+	results, err := ct.client.AddCounts([]logfwdapi.Counts{{
+		LastSentID: logfwdapi.LastSentID{
+			Model: names.NewModelTag(model),
+			Sink:  ct.sink,
+		},
+		Sent:    sent,
+		Dropped: dropped,
+	}})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := results[0].Error; err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
@@ -4,12 +4,16 @@
 package logger
 
 import (
+	"sync"
+
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/pubsub"
 	"gopkg.in/juju/names.v3"
 	"gopkg.in/juju/worker.v1"
 
 	"github.com/juju/juju/core/watcher"
+	"github.com/juju/juju/pubsub/controller"
 )
 
 // LoggerAPI represents the API calls the logger makes.
@@ -27,6 +31,11 @@ type WorkerConfig struct {
 	Logger   Logger
 	Override string
 
+	// Hub is the central hub of a controller agent. If it is set,
+	// the controller logging config published on it by the apiserver
+	// is applied on top of the model's logging config.
+	Hub *pubsub.StructuredHub
+
 	Callback func(string) error
 }
 
@@ -47,8 +56,13 @@ func (c *WorkerConfig) Validate() error {
 // loggerWorker is responsible for updating the loggo configuration when the
 // environment watcher tells the agent that the value has changed.
 type loggerWorker struct {
-	config     WorkerConfig
-	lastConfig string
+	config WorkerConfig
+
+	mu               sync.Mutex
+	lastConfig       string
+	modelConfig      string
+	controllerConfig string
+	unsubscribe      func()
 }
 
 // NewLogger returns a worker.Worker that uses the notify watcher returned
@@ -73,19 +87,52 @@ func NewLogger(config WorkerConfig) (worker.Worker, error) {
 }
 
 func (l *loggerWorker) setLogging() {
-	loggingConfig := ""
 	logger := l.config.Logger
 
-	if override := l.config.Override; override != "" {
-		logger.Debugf("overriding logging config with override from agent.conf %q", override)
-		loggingConfig = override
-	} else {
-		modelLoggingConfig, err := l.config.API.LoggingConfig(l.config.Tag)
+	var modelLoggingConfig string
+	if l.config.Override == "" {
+		var err error
+		modelLoggingConfig, err = l.config.API.LoggingConfig(l.config.Tag)
 		if err != nil {
 			logger.Errorf("%v", err)
 			return
 		}
-		loggingConfig = modelLoggingConfig
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.modelConfig = modelLoggingConfig
+	l.applyLogging()
+}
+
+// onControllerLoggingConfig is called when the apiserver publishes the
+// controller logging config.
+func (l *loggerWorker) onControllerLoggingConfig(topic string, msg controller.LoggingConfigMessage, err error) {
+	if err != nil {
+		l.config.Logger.Errorf("programming error in %s message data: %v", topic, err)
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.controllerConfig = msg.Config
+	l.applyLogging()
+}
+
+// applyLogging reconfigures the loggers if the logging config has
+// changed. The controller logging config, if any, takes precedence
+// over the model's. It must be called with l.mu held.
+func (l *loggerWorker) applyLogging() {
+	logger := l.config.Logger
+	loggingConfig := l.modelConfig
+	if override := l.config.Override; override != "" {
+		logger.Debugf("overriding logging config with override from agent.conf %q", override)
+		loggingConfig = override
+	} else if l.controllerConfig != "" {
+		// Later settings for the same module win.
+		if loggingConfig != "" {
+			loggingConfig += ";"
+		}
+		loggingConfig += l.controllerConfig
 	}
 
 	if loggingConfig != l.lastConfig {
@@ -119,6 +166,21 @@ func (l *loggerWorker) SetUp() (watcher.NotifyWatcher, error) {
 	// We need to set this up initially as the NotifyWorker sucks up the first
 	// event.
 	l.setLogging()
+	if hub := l.config.Hub; hub != nil {
+		unsubscribe, err := hub.Subscribe(controller.LoggingConfigChanged, l.onControllerLoggingConfig)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		l.unsubscribe = unsubscribe
+		// The apiserver may have published the controller logging
+		// config before we subscribed.
+		if _, err := hub.Publish(controller.LoggingConfigRequest, controller.LoggingConfigRequestMessage{
+			Requester: "logger",
+			LocalOnly: true,
+		}); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
 	return l.config.API.WatchLoggingConfig(l.config.Tag)
 }
 
@@ -130,7 +192,9 @@ func (l *loggerWorker) Handle(_ <-chan struct{}) error {
 
 // TearDown is called by the NotifyWorker when the worker is being stopped.
 func (l *loggerWorker) TearDown() error {
-	// Nothing to cleanup, only state is the watcher
+	if l.unsubscribe != nil {
+		l.unsubscribe()
+	}
 	l.config.Logger.Infof("logger worker stopped")
 	return nil
 }
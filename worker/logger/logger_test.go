@@ -8,6 +8,7 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/pubsub"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -15,6 +16,7 @@ import (
 	"gopkg.in/juju/worker.v1"
 
 	"github.com/juju/juju/core/watcher"
+	"github.com/juju/juju/pubsub/controller"
 	"github.com/juju/juju/worker/logger"
 )
 
@@ -139,6 +141,34 @@ func (s *LoggerSuite) TestConfigOverride(c *gc.C) {
 	s.waitLoggingInfo(c, expected)
 }
 
+func (s *LoggerSuite) TestControllerLoggingConfig(c *gc.C) {
+	hub := pubsub.NewStructuredHub(nil)
+	unsubscribe, err := hub.Subscribe(controller.LoggingConfigRequest,
+		func(string, controller.LoggingConfigRequestMessage, error) {
+			hub.Publish(controller.LoggingConfigChanged, controller.LoggingConfigMessage{
+				Config:    "test=TRACE",
+				LocalOnly: true,
+			})
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	defer unsubscribe()
+	s.config.Hub = hub
+	s.loggerAPI.config = "<root>=DEBUG;test=ERROR"
+
+	loggingWorker := s.makeLogger(c)
+	defer worker.Stop(loggingWorker)
+
+	// The controller logging config takes precedence.
+	s.waitLoggingInfo(c, "<root>=DEBUG;test=TRACE")
+
+	_, err = hub.Publish(controller.LoggingConfigChanged, controller.LoggingConfigMessage{
+		Config:    "juju.apiserver=INFO",
+		LocalOnly: true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.waitLoggingInfo(c, "<root>=DEBUG;juju.apiserver=INFO;test=ERROR")
+}
+
 type mockNotifyWatcher struct {
 	changes chan struct{}
 }
@@ -4,7 +4,9 @@
 package logger
 
 import (
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/pubsub"
 	"gopkg.in/juju/worker.v1"
 	"gopkg.in/juju/worker.v1/dependency"
 
@@ -30,16 +32,25 @@ type ManifoldConfig struct {
 	LoggingContext  *loggo.Context
 	Logger          Logger
 	UpdateAgentFunc func(string) error
+
+	// CentralHubName is optional. If it is set, and the central hub
+	// is available because the agent is a controller, the controller
+	// logging config is applied on top of the model's.
+	CentralHubName string
 }
 
 // Manifold returns a dependency manifold that runs a logger
 // worker, using the resource names defined in the supplied config.
 func Manifold(config ManifoldConfig) dependency.Manifold {
+	inputs := []string{
+		config.AgentName,
+		config.APICallerName,
+	}
+	if config.CentralHubName != "" {
+		inputs = append(inputs, config.CentralHubName)
+	}
 	return dependency.Manifold{
-		Inputs: []string{
-			config.AgentName,
-			config.APICallerName,
-		},
+		Inputs: inputs,
 		Start: func(context dependency.Context) (worker.Worker, error) {
 			var a agent.Agent
 			if err := context.Get(config.AgentName, &a); err != nil {
@@ -53,6 +64,15 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 				return nil, err
 			}
 
+			var hub *pubsub.StructuredHub
+			if config.CentralHubName != "" {
+				// The central hub is only available on controllers.
+				err := context.Get(config.CentralHubName, &hub)
+				if err != nil && errors.Cause(err) != dependency.ErrMissing {
+					return nil, err
+				}
+			}
+
 			loggerFacade := logger.NewState(apiCaller)
 			workerConfig := WorkerConfig{
 				Context:  config.LoggingContext,
@@ -61,6 +81,7 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 				Logger:   config.Logger,
 				Override: loggingOverride,
 				Callback: config.UpdateAgentFunc,
+				Hub:      hub,
 			}
 			return NewLogger(workerConfig)
 		},
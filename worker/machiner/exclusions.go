@@ -0,0 +1,149 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machiner
+
+import (
+	"net"
+	"path"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	corenetwork "github.com/juju/juju/core/network"
+)
+
+// addressFilter excludes addresses, by the subnet they are in or by the
+// network interface they are on, from those the machiner reports.
+type addressFilter struct {
+	cidrs      []*net.IPNet
+	interfaces []string
+}
+
+// newAddressFilter returns a filter for the given exclusions, each of
+// which is either a CIDR or a glob matching network interface names.
+func newAddressFilter(exclusions []string) (addressFilter, error) {
+	var f addressFilter
+	for _, exclusion := range exclusions {
+		if strings.Contains(exclusion, "/") {
+			_, ipNet, err := net.ParseCIDR(exclusion)
+			if err != nil {
+				return addressFilter{}, errors.NotValidf("address exclusion %q", exclusion)
+			}
+			f.cidrs = append(f.cidrs, ipNet)
+			continue
+		}
+		if _, err := path.Match(exclusion, ""); err != nil {
+			return addressFilter{}, errors.NotValidf("address exclusion %q", exclusion)
+		}
+		f.interfaces = append(f.interfaces, exclusion)
+	}
+	return f, nil
+}
+
+// excludesInterface reports whether the named interface is excluded.
+func (f addressFilter) excludesInterface(name string) bool {
+	for _, pattern := range f.interfaces {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// excludesIP reports whether the IP address is in an excluded subnet.
+func (f addressFilter) excludesIP(ip net.IP) bool {
+	for _, ipNet := range f.cidrs {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+var hostInterfaceAddrs = interfaceAddrsByName
+
+// interfaceAddrsByName returns the addresses of each of the host's network
+// interfaces, keyed by interface name.
+func interfaceAddrsByName() (map[string][]net.Addr, error) {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make(map[string][]net.Addr)
+	for _, iface := range interfaces {
+		addrs, err := iface.Addrs()
+		if err != nil {
+			return nil, errors.Annotatef(err, "getting addresses of %q", iface.Name)
+		}
+		result[iface.Name] = addrs
+	}
+	return result, nil
+}
+
+// filterAddresses returns the addresses which are neither in an excluded
+// subnet nor on an excluded interface.
+func (f addressFilter) filterAddresses(addresses []corenetwork.Address) ([]corenetwork.Address, error) {
+	if len(f.cidrs) == 0 && len(f.interfaces) == 0 {
+		return addresses, nil
+	}
+	excludedIPs := make(map[string]bool)
+	if len(f.interfaces) > 0 {
+		addrsByName, err := hostInterfaceAddrs()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for name, addrs := range addrsByName {
+			if !f.excludesInterface(name) {
+				continue
+			}
+			for _, addr := range addrs {
+				if ip := addrIP(addr); ip != nil {
+					excludedIPs[ip.String()] = true
+				}
+			}
+		}
+	}
+	var result []corenetwork.Address
+	for _, address := range addresses {
+		ip := net.ParseIP(address.Value)
+		if ip != nil && (excludedIPs[ip.String()] || f.excludesIP(ip)) {
+			logger.Debugf("excluding address %v", address)
+			continue
+		}
+		result = append(result, address)
+	}
+	return result, nil
+}
+
+// filterNetworkConfig returns the network config of the interfaces and
+// addresses which are not excluded.
+func (f addressFilter) filterNetworkConfig(config []params.NetworkConfig) []params.NetworkConfig {
+	if len(f.cidrs) == 0 && len(f.interfaces) == 0 {
+		return config
+	}
+	var result []params.NetworkConfig
+	for _, c := range config {
+		if f.excludesInterface(c.InterfaceName) {
+			continue
+		}
+		if ip := net.ParseIP(c.Address); ip != nil && f.excludesIP(ip) {
+			continue
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+// addrIP returns the IP address of the interface address, or nil if it
+// has none.
+func addrIP(addr net.Addr) net.IP {
+	switch addr := addr.(type) {
+	case *net.IPAddr:
+		return addr.IP
+	case *net.IPNet:
+		return addr.IP
+	}
+	return nil
+}
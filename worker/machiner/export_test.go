@@ -6,4 +6,9 @@ package machiner
 var (
 	InterfaceAddrs           = &interfaceAddrs
 	GetObservedNetworkConfig = &getObservedNetworkConfig
+	GetBootID                = &getBootID
+	GetHostname              = &getHostname
+	HostInterfaceAddrs       = &hostInterfaceAddrs
+	GetHostInfo              = &getHostInfo
+	ParseMemTotal            = parseMemTotal
 )
@@ -0,0 +1,67 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machiner
+
+import (
+	"bufio"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+var getHostInfo = readHostInfo
+
+// checkHostInfo records the host's kernel version, series and hardware,
+// if they have changed since they were last recorded. This keeps the
+// machine's series up to date after the operating system is upgraded
+// in place, and its hardware after memory or disk is added.
+func (w *hostnameWorker) checkHostInfo() error {
+	info, err := getHostInfo()
+	if errors.IsNotSupported(err) {
+		return nil
+	} else if err != nil {
+		logger.Warningf("cannot read host info for %q: %v", w.config.Tag, err)
+		return nil
+	}
+	if reflect.DeepEqual(info, w.hostInfo) {
+		return nil
+	}
+	if !w.ensureMachine() {
+		return nil
+	}
+	err = w.machine.SetHostInfo(info)
+	if errors.IsNotSupported(err) {
+		logger.Debugf("not recording host info for %q: %v", w.config.Tag, err)
+	} else if err != nil {
+		return errors.Annotate(err, "recording host info")
+	} else {
+		logger.Infof("host of %q is running %s with kernel %s", w.config.Tag, info.Series, info.KernelVersion)
+	}
+	w.hostInfo = info
+	return nil
+}
+
+// parseMemTotal returns the total memory in MiB, as given in the
+// MemTotal line of /proc/meminfo.
+func parseMemTotal(r io.Reader) (uint64, error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 || fields[0] != "MemTotal:" || fields[2] != "kB" {
+			continue
+		}
+		kB, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return 0, errors.Annotate(err, "parsing MemTotal")
+		}
+		return kB / 1024, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, errors.Trace(err)
+	}
+	return 0, errors.NotFoundf("MemTotal")
+}
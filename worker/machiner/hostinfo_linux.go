@@ -0,0 +1,57 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build linux
+
+package machiner
+
+import (
+	"io/ioutil"
+	"os"
+	"runtime"
+	"strings"
+	"syscall"
+
+	"github.com/juju/errors"
+	"github.com/juju/os/series"
+
+	"github.com/juju/juju/api/machiner"
+	"github.com/juju/juju/core/instance"
+)
+
+// readHostInfo returns the kernel version, series, memory, CPU cores and
+// root disk size of the host.
+func readHostInfo() (machiner.HostInfo, error) {
+	release, err := ioutil.ReadFile("/proc/sys/kernel/osrelease")
+	if err != nil {
+		return machiner.HostInfo{}, errors.Annotate(err, "reading kernel version")
+	}
+	hostSeries, err := series.HostSeries()
+	if err != nil {
+		return machiner.HostInfo{}, errors.Annotate(err, "reading series")
+	}
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return machiner.HostInfo{}, errors.Annotate(err, "reading memory")
+	}
+	defer f.Close()
+	mem, err := parseMemTotal(f)
+	if err != nil {
+		return machiner.HostInfo{}, errors.Annotate(err, "reading memory")
+	}
+	var fs syscall.Statfs_t
+	if err := syscall.Statfs("/", &fs); err != nil {
+		return machiner.HostInfo{}, errors.Annotate(err, "reading root disk size")
+	}
+	rootDisk := fs.Blocks * uint64(fs.Bsize) / (1024 * 1024)
+	cores := uint64(runtime.NumCPU())
+	return machiner.HostInfo{
+		KernelVersion: strings.TrimSpace(string(release)),
+		Series:        hostSeries,
+		Hardware: &instance.HardwareCharacteristics{
+			Mem:      &mem,
+			CpuCores: &cores,
+			RootDisk: &rootDisk,
+		},
+	}, nil
+}
@@ -0,0 +1,19 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build !linux
+
+package machiner
+
+import (
+	"runtime"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/machiner"
+)
+
+// readHostInfo is only implemented on Linux.
+func readHostInfo() (machiner.HostInfo, error) {
+	return machiner.HostInfo{}, errors.NotSupportedf("reading host info on %s", runtime.GOOS)
+}
@@ -0,0 +1,114 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machiner
+
+import (
+	"os"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/worker.v1"
+	"gopkg.in/juju/worker.v1/catacomb"
+
+	"github.com/juju/juju/api/machiner"
+)
+
+// hostnamePollInterval is how often the host's hostname is checked for
+// changes.
+const hostnamePollInterval = time.Minute
+
+var getHostname = os.Hostname
+
+// hostnameWorker records the host's hostname, kernel version, series and
+// hardware against the machine, and checks them periodically so that
+// changes made on the host are recorded too. It runs the machiner's
+// lifecycle worker, and stops when that does.
+type hostnameWorker struct {
+	catacomb catacomb.Catacomb
+	config   Config
+	machine  Machine
+	hostname string
+	hostInfo machiner.HostInfo
+}
+
+func newHostnameWorker(config Config, lifecycle worker.Worker) (*hostnameWorker, error) {
+	w := &hostnameWorker{config: config}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+		Init: []worker.Worker{lifecycle},
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *hostnameWorker) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *hostnameWorker) Wait() error {
+	return w.catacomb.Wait()
+}
+
+func (w *hostnameWorker) loop() error {
+	for {
+		if err := w.checkHostname(); err != nil {
+			return errors.Trace(err)
+		}
+		if err := w.checkHostInfo(); err != nil {
+			return errors.Trace(err)
+		}
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case <-w.config.Clock.After(hostnamePollInterval):
+		}
+	}
+}
+
+// checkHostname records the host's hostname, if it has changed since it
+// was last recorded.
+func (w *hostnameWorker) checkHostname() error {
+	hostname, err := getHostname()
+	if err != nil {
+		logger.Warningf("cannot read hostname for %q: %v", w.config.Tag, err)
+		return nil
+	}
+	if hostname == "" || hostname == w.hostname {
+		return nil
+	}
+	if !w.ensureMachine() {
+		return nil
+	}
+	err = w.machine.SetHostname(hostname)
+	if errors.IsNotSupported(err) {
+		logger.Debugf("not recording hostname for %q: %v", w.config.Tag, err)
+	} else if err != nil {
+		return errors.Annotate(err, "recording hostname")
+	} else {
+		logger.Infof("hostname of %q is %q", w.config.Tag, hostname)
+	}
+	w.hostname = hostname
+	return nil
+}
+
+// ensureMachine gets the machine, if it has not already got it, and
+// reports whether it has. The lifecycle worker deals with the machine
+// not being found, so any error getting it here is just retried later.
+func (w *hostnameWorker) ensureMachine() bool {
+	if w.machine != nil {
+		return true
+	}
+	m, err := w.config.MachineAccessor.Machine(w.config.Tag)
+	if err != nil {
+		logger.Debugf("cannot get machine %q to record host details: %v", w.config.Tag, err)
+		return false
+	}
+	w.machine = m
+	return true
+}
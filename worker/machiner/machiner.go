@@ -3,8 +3,15 @@
 package machiner
 
 import (
+	"fmt"
+	"io/ioutil"
 	"net"
+	"os"
+	"reflect"
+	"strings"
+	"time"
 
+	"github.com/juju/clock"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"gopkg.in/juju/names.v3"
@@ -33,8 +40,33 @@ type Config struct {
 	// ClearMachineAddressesOnStart indicates whether or not to clear
 	// the machine's machine addresses when the worker starts.
 	ClearMachineAddressesOnStart bool
+
+	// AddressExclusions holds CIDRs, and globs matching network interface
+	// names, whose addresses are not reported for the machine.
+	AddressExclusions []string
+
+	// Clock is used to check the host's hostname for changes
+	// periodically, and to time the shutdown grace period.
+	Clock clock.Clock
+
+	// ShutdownTasks are run in turn when the machine becomes Dying,
+	// before it is made Dead.
+	ShutdownTasks []ShutdownTask
+
+	// ShutdownGracePeriod is the time allowed for the shutdown tasks to
+	// run. If it is zero, DefaultShutdownGracePeriod is used.
+	ShutdownGracePeriod time.Duration
+
+	// ChangeDelay is how long changes to the machine after the first
+	// are gathered for, so that a burst of them is handled once. If it
+	// is zero, DefaultChangeDelay is used.
+	ChangeDelay time.Duration
 }
 
+// DefaultChangeDelay is how long changes to the machine are gathered for
+// by default. Many changes arrive together when a model is torn down.
+const DefaultChangeDelay = 250 * time.Millisecond
+
 // Validate reports whether or not the configuration is valid.
 func (cfg *Config) Validate() error {
 	if cfg.MachineAccessor == nil {
@@ -43,6 +75,12 @@ func (cfg *Config) Validate() error {
 	if cfg.Tag == (names.MachineTag{}) {
 		return errors.NotValidf("unspecified Tag")
 	}
+	if cfg.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if _, err := newAddressFilter(cfg.AddressExclusions); err != nil {
+		return errors.Trace(err)
+	}
 	return nil
 }
 
@@ -50,6 +88,25 @@ func (cfg *Config) Validate() error {
 type Machiner struct {
 	config  Config
 	machine Machine
+
+	// addressFilter excludes the addresses in AddressExclusions from
+	// those reported.
+	addressFilter addressFilter
+
+	// observedConfig holds the network config last reported, so that
+	// only the changes to it need be reported.
+	observedConfig []params.NetworkConfig
+
+	// shutdownDone records whether the shutdown tasks have been run.
+	shutdownDone bool
+
+	// cordoned records whether the machine's status last showed it
+	// to be cordoned.
+	cordoned bool
+
+	// networkProblems holds the problems with the machine's network
+	// config last shown in its status.
+	networkProblems []params.NetworkConfigValidation
 }
 
 // NewMachiner returns a Worker that will wait for the identified machine
@@ -58,18 +115,30 @@ type Machiner struct {
 //
 // The machineDead function will be called immediately after the machine's
 // lifecycle is updated to Dead.
+//
+// The worker also records the host's hostname, and any changes to it, and
+// shows in the machine's status whether an operator has cordoned it and
+// any problems the controller finds with its network config.
 var NewMachiner = func(cfg Config) (worker.Worker, error) {
 	if err := cfg.Validate(); err != nil {
 		return nil, errors.Annotate(err, "validating config")
 	}
-	handler := &Machiner{config: cfg}
+	filter, err := newAddressFilter(cfg.AddressExclusions)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	handler := &Machiner{config: cfg, addressFilter: filter}
 	w, err := watcher.NewNotifyWorker(watcher.NotifyConfig{
 		Handler: handler,
 	})
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	return w, nil
+	hw, err := newHostnameWorker(cfg, w)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return hw, nil
 }
 
 var getObservedNetworkConfig = common.GetObservedNetworkConfig
@@ -91,38 +160,94 @@ func (mr *Machiner) SetUp() (watcher.NotifyWatcher, error) {
 		}
 	} else {
 		// Set the addresses in state to the host's addresses.
-		if err := setMachineAddresses(mr.config.Tag, m); err != nil {
+		if err := setMachineAddresses(mr.config.Tag, m, mr.addressFilter); err != nil {
 			return nil, errors.Annotate(err, "setting machine addresses")
 		}
 	}
 
+	statusInfo, err := mr.recordBootID(m)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
 	// Mark the machine as started and log it.
-	if err := m.SetStatus(status.Started, "", nil); err != nil {
+	if err := m.SetStatus(status.Started, statusInfo, nil); err != nil {
 		return nil, errors.Annotatef(err, "%s failed to set status started", mr.config.Tag)
 	}
 	logger.Infof("%q started", mr.config.Tag)
 
-	return m.Watch()
+	w, err := m.Watch()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	delay := mr.config.ChangeDelay
+	if delay == 0 {
+		delay = DefaultChangeDelay
+	}
+	return watcher.NewDebouncedNotifyWatcher(w, mr.config.Clock, delay)
+}
+
+// bootIDPath is the file from which the boot id of the running kernel is
+// read.
+const bootIDPath = "/proc/sys/kernel/random/boot_id"
+
+var getBootID = readBootID
+
+// readBootID returns the boot id of the running kernel, or a not supported
+// error if it has none.
+func readBootID() (string, error) {
+	data, err := ioutil.ReadFile(bootIDPath)
+	if os.IsNotExist(err) {
+		return "", errors.NotSupportedf("boot id")
+	} else if err != nil {
+		return "", errors.Trace(err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// recordBootID records the boot id of the machine's kernel, so that reboots
+// of the machine which are not made by Juju can be detected. It returns the
+// message with which the machine should be marked as started.
+//
+// Containers share the kernel, and so the boot id, of their host, so a
+// container is seen to reboot along with its host.
+func (mr *Machiner) recordBootID(m Machine) (string, error) {
+	bootID, err := getBootID()
+	if errors.IsNotSupported(err) {
+		logger.Debugf("not recording boot id for %q: %v", mr.config.Tag, err)
+		return "", nil
+	} else if err != nil {
+		logger.Warningf("cannot read boot id for %q: %v", mr.config.Tag, err)
+		return "", nil
+	}
+	rebooted, err := m.SetBootID(bootID)
+	if errors.IsNotSupported(err) {
+		logger.Debugf("not recording boot id for %q: %v", mr.config.Tag, err)
+		return "", nil
+	} else if err != nil {
+		return "", errors.Annotate(err, "recording boot id")
+	}
+	if rebooted {
+		logger.Warningf("%q was rebooted outside of Juju", mr.config.Tag)
+		return "machine rebooted outside of Juju", nil
+	}
+	return "", nil
 }
 
 var interfaceAddrs = net.InterfaceAddrs
 
 // setMachineAddresses sets the addresses for this machine to all of the
-// host's non-loopback interface IP addresses.
-func setMachineAddresses(tag names.MachineTag, m Machine) error {
+// host's non-loopback interface IP addresses, other than those excluded
+// by the filter.
+func setMachineAddresses(tag names.MachineTag, m Machine, filter addressFilter) error {
 	addrs, err := interfaceAddrs()
 	if err != nil {
 		return err
 	}
 	var hostAddresses []corenetwork.Address
 	for _, addr := range addrs {
-		var ip net.IP
-		switch addr := addr.(type) {
-		case *net.IPAddr:
-			ip = addr.IP
-		case *net.IPNet:
-			ip = addr.IP
-		default:
+		ip := addrIP(addr)
+		if ip == nil {
 			continue
 		}
 		address := corenetwork.NewAddress(ip.String())
@@ -137,11 +262,14 @@ func setMachineAddresses(tag names.MachineTag, m Machine) error {
 	}
 	// Filter out any LXC or LXD bridge addresses.
 	hostAddresses = network.FilterBridgeAddresses(hostAddresses)
+	if hostAddresses, err = filter.filterAddresses(hostAddresses); err != nil {
+		return errors.Annotate(err, "excluding addresses")
+	}
 	logger.Infof("setting addresses for %q to %v", tag, hostAddresses)
 	return m.SetMachineAddresses(hostAddresses)
 }
 
-func (mr *Machiner) Handle(_ <-chan struct{}) error {
+func (mr *Machiner) Handle(abort <-chan struct{}) error {
 	if err := mr.machine.Refresh(); params.IsCodeNotFoundOrCodeUnauthorized(err) {
 		// NOTE(axw) we can distinguish between NotFound and CodeUnauthorized,
 		// so we could call NotifyMachineDead here in case the agent failed to
@@ -158,19 +286,27 @@ func (mr *Machiner) Handle(_ <-chan struct{}) error {
 		observedConfig, err := getObservedNetworkConfig(common.DefaultNetworkConfigSource())
 		if err != nil {
 			return errors.Annotate(err, "cannot discover observed network config")
-		} else if len(observedConfig) == 0 {
+		}
+		observedConfig = mr.addressFilter.filterNetworkConfig(observedConfig)
+		if len(observedConfig) == 0 {
 			logger.Warningf("not updating network config: no observed config found to update")
 		}
+		networkProblems := mr.networkProblems
 		if len(observedConfig) > 0 {
-			if err := mr.machine.SetObservedNetworkConfig(observedConfig); err != nil {
+			if networkProblems, err = mr.setObservedNetworkConfig(observedConfig); err != nil {
 				return errors.Annotate(err, "cannot update observed network config")
 			}
 		}
-		logger.Debugf("observed network config updated for %q to %+v", mr.config.Tag, observedConfig)
-
-		return nil
+		cordoned, err := mr.isCordoned()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		return mr.updateStartedStatus(cordoned, networkProblems)
 	}
 	logger.Debugf("%q is now %s", mr.config.Tag, life)
+	if life == params.Dying && !mr.runShutdownTasks(abort) {
+		return nil
+	}
 	if err := mr.machine.SetStatus(status.Stopped, "", nil); err != nil {
 		return errors.Annotatef(err, "%s failed to set status stopped", mr.config.Tag)
 	}
@@ -197,6 +333,133 @@ func (mr *Machiner) Handle(_ <-chan struct{}) error {
 	return jworker.ErrTerminateAgent
 }
 
+// cordonedStatusMessage is the status message of a cordoned machine.
+const cordonedStatusMessage = "machine cordoned"
+
+// networkConfigStatusKey is the key of the machine's status data which
+// holds the problems with its network config.
+const networkConfigStatusKey = "network-config"
+
+// isCordoned reports whether the machine is cordoned. Controllers which
+// do not support cordoning are taken to leave the machine as it was.
+func (mr *Machiner) isCordoned() (bool, error) {
+	cordoned, err := mr.machine.Cordoned()
+	if errors.IsNotSupported(err) {
+		return mr.cordoned, nil
+	} else if err != nil {
+		return false, errors.Annotate(err, "cannot check whether machine is cordoned")
+	}
+	return cordoned, nil
+}
+
+// updateStartedStatus shows in the machine's status whether it is
+// cordoned, and any problems with its network config, when they have
+// changed. No new units are assigned to a cordoned machine; the units
+// already on it are unaffected.
+func (mr *Machiner) updateStartedStatus(cordoned bool, networkProblems []params.NetworkConfigValidation) error {
+	if len(networkProblems) == 0 {
+		networkProblems = nil
+	}
+	if cordoned == mr.cordoned && reflect.DeepEqual(networkProblems, mr.networkProblems) {
+		return nil
+	}
+	var messages []string
+	if cordoned {
+		messages = append(messages, cordonedStatusMessage)
+	}
+	var data map[string]interface{}
+	switch len(networkProblems) {
+	case 0:
+	case 1:
+		p := networkProblems[0]
+		messages = append(messages, fmt.Sprintf("network config: %s: %s", p.InterfaceName, p.Message))
+	default:
+		messages = append(messages, fmt.Sprintf("network config: %d problems", len(networkProblems)))
+	}
+	if len(networkProblems) > 0 {
+		problems := make([]interface{}, len(networkProblems))
+		for i, p := range networkProblems {
+			problems[i] = map[string]interface{}{
+				"interface": p.InterfaceName,
+				"address":   p.Address,
+				"cidr":      p.CIDR,
+				"message":   p.Message,
+			}
+		}
+		data = map[string]interface{}{networkConfigStatusKey: problems}
+	}
+	if err := mr.machine.SetStatus(status.Started, strings.Join(messages, "; "), data); err != nil {
+		return errors.Annotatef(err, "%s failed to set status started", mr.config.Tag)
+	}
+	if cordoned != mr.cordoned {
+		if cordoned {
+			logger.Infof("%q cordoned; no new units will be assigned to it", mr.config.Tag)
+		} else {
+			logger.Infof("%q uncordoned", mr.config.Tag)
+		}
+	}
+	for _, p := range networkProblems {
+		logger.Warningf("network config of %q: %s: %s", mr.config.Tag, p.InterfaceName, p.Message)
+	}
+	mr.cordoned = cordoned
+	mr.networkProblems = networkProblems
+	return nil
+}
+
+// setObservedNetworkConfig reports the observed network config of the
+// machine. It is reported in full the first time; after that only the
+// config of the interfaces which have been added or changed is reported,
+// and nothing at all if none have. The problems the controller finds
+// with the machine's network config are returned.
+func (mr *Machiner) setObservedNetworkConfig(observedConfig []params.NetworkConfig) ([]params.NetworkConfigValidation, error) {
+	if mr.observedConfig == nil {
+		problems, err := mr.machine.SetObservedNetworkConfig(observedConfig)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		logger.Debugf("observed network config updated for %q to %+v", mr.config.Tag, observedConfig)
+		mr.observedConfig = observedConfig
+		return problems, nil
+	}
+	changes := networkConfigChanges(mr.observedConfig, observedConfig)
+	if len(changes) == 0 {
+		logger.Tracef("observed network config for %q unchanged", mr.config.Tag)
+		return mr.networkProblems, nil
+	}
+	problems, err := mr.machine.SetObservedNetworkConfigChanges(changes)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	logger.Debugf("observed network config updated for %q with %+v", mr.config.Tag, changes)
+	mr.observedConfig = observedConfig
+	return problems, nil
+}
+
+// networkConfigChanges returns the config, from observed, of the
+// interfaces whose config is not the same in last.
+func networkConfigChanges(last, observed []params.NetworkConfig) []params.NetworkConfig {
+	lastByName := networkConfigByInterface(last)
+	observedByName := networkConfigByInterface(observed)
+	var changes []params.NetworkConfig
+	for _, config := range observed {
+		name := config.InterfaceName
+		if !reflect.DeepEqual(lastByName[name], observedByName[name]) {
+			changes = append(changes, config)
+		}
+	}
+	return changes
+}
+
+// networkConfigByInterface groups the network config, which has an entry
+// for each address of each interface, by interface name.
+func networkConfigByInterface(config []params.NetworkConfig) map[string][]params.NetworkConfig {
+	result := make(map[string][]params.NetworkConfig)
+	for _, c := range config {
+		result[c.InterfaceName] = append(result[c.InterfaceName], c)
+	}
+	return result
+}
+
 func (mr *Machiner) TearDown() error {
 	// Nothing to do here.
 	return nil
@@ -7,8 +7,11 @@ import (
 	"io/ioutil"
 	"net"
 	"path/filepath"
+	"strings"
 	stdtesting "testing"
+	"time"
 
+	"github.com/juju/clock/testclock"
 	"github.com/juju/errors"
 	gitjujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
@@ -17,7 +20,9 @@ import (
 	"gopkg.in/juju/worker.v1"
 
 	"github.com/juju/juju/api/common"
+	apimachiner "github.com/juju/juju/api/machiner"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/instance"
 	corenetwork "github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/network"
@@ -35,6 +40,7 @@ type MachinerSuite struct {
 	accessor   *mockMachineAccessor
 	machineTag names.MachineTag
 	addresses  []net.Addr
+	clock      *testclock.Clock
 }
 
 var _ = gc.Suite(&MachinerSuite{})
@@ -55,6 +61,16 @@ func (s *MachinerSuite) SetUpTest(c *gc.C) {
 	s.PatchValue(machiner.GetObservedNetworkConfig, func(_ common.NetworkConfigSource) ([]params.NetworkConfig, error) {
 		return nil, nil
 	})
+	s.PatchValue(machiner.GetBootID, func() (string, error) {
+		return "", errors.NotSupportedf("boot id")
+	})
+	s.PatchValue(machiner.GetHostname, func() (string, error) {
+		return "", nil
+	})
+	s.PatchValue(machiner.GetHostInfo, func() (apimachiner.HostInfo, error) {
+		return apimachiner.HostInfo{}, errors.NotSupportedf("host info")
+	})
+	s.clock = testclock.NewClock(time.Time{})
 }
 
 func (s *MachinerSuite) TestMachinerConfigValidate(c *gc.C) {
@@ -64,10 +80,16 @@ func (s *MachinerSuite) TestMachinerConfigValidate(c *gc.C) {
 		MachineAccessor: &mockMachineAccessor{},
 	})
 	c.Assert(err, gc.ErrorMatches, "validating config: unspecified Tag not valid")
+	_, err = machiner.NewMachiner(machiner.Config{
+		MachineAccessor: &mockMachineAccessor{},
+		Tag:             names.NewMachineTag("123"),
+	})
+	c.Assert(err, gc.ErrorMatches, "validating config: nil Clock not valid")
 
 	w, err := machiner.NewMachiner(machiner.Config{
 		MachineAccessor: &mockMachineAccessor{},
 		Tag:             names.NewMachineTag("123"),
+		Clock:           s.clock,
 	})
 	c.Assert(err, jc.ErrorIsNil)
 
@@ -82,7 +104,9 @@ func (s *MachinerSuite) TestMachinerSetUpMachineNotFound(c *gc.C) {
 		&params.Error{Code: params.CodeNotFound}, // Machine
 	)
 	w, err := machiner.NewMachiner(machiner.Config{
-		s.accessor, s.machineTag, false,
+		MachineAccessor: s.accessor,
+		Tag:             s.machineTag,
+		Clock:           s.clock,
 	})
 	c.Assert(err, jc.ErrorIsNil)
 	err = stopWorker(w)
@@ -107,7 +131,9 @@ func (s *MachinerSuite) testMachinerMachineRefreshNotFoundOrUnauthorized(c *gc.C
 		&params.Error{Code: code}, // Refresh
 	)
 	w, err := machiner.NewMachiner(machiner.Config{
-		s.accessor, s.machineTag, false,
+		MachineAccessor: s.accessor,
+		Tag:             s.machineTag,
+		Clock:           s.clock,
 	})
 	c.Assert(err, jc.ErrorIsNil)
 	s.accessor.machine.watcher.changes <- struct{}{}
@@ -127,6 +153,7 @@ func (s *MachinerSuite) TestMachinerSetStatusStopped(c *gc.C) {
 	w, err := machiner.NewMachiner(machiner.Config{
 		MachineAccessor: s.accessor,
 		Tag:             s.machineTag,
+		Clock:           s.clock,
 	})
 	c.Assert(err, jc.ErrorIsNil)
 	s.accessor.machine.watcher.changes <- struct{}{}
@@ -164,6 +191,7 @@ func (s *MachinerSuite) TestMachinerMachineEnsureDeadError(c *gc.C) {
 	w, err := machiner.NewMachiner(machiner.Config{
 		MachineAccessor: s.accessor,
 		Tag:             s.machineTag,
+		Clock:           s.clock,
 	})
 	c.Assert(err, jc.ErrorIsNil)
 	s.accessor.machine.watcher.changes <- struct{}{}
@@ -193,6 +221,7 @@ func (s *MachinerSuite) TestMachinerMachineAssignedUnits(c *gc.C) {
 	w, err := machiner.NewMachiner(machiner.Config{
 		MachineAccessor: s.accessor,
 		Tag:             s.machineTag,
+		Clock:           s.clock,
 	})
 	c.Assert(err, jc.ErrorIsNil)
 	s.accessor.machine.watcher.changes <- struct{}{}
@@ -228,7 +257,9 @@ func (s *MachinerSuite) TestMachinerStorageAttached(c *gc.C) {
 	)
 
 	worker, err := machiner.NewMachiner(machiner.Config{
-		s.accessor, s.machineTag, false,
+		MachineAccessor: s.accessor,
+		Tag:             s.machineTag,
+		Clock:           s.clock,
 	})
 	c.Assert(err, jc.ErrorIsNil)
 	s.accessor.machine.watcher.changes <- struct{}{}
@@ -273,6 +304,97 @@ func (s *MachinerSuite) TestMachinerStorageAttached(c *gc.C) {
 	}})
 }
 
+func (s *MachinerSuite) TestMachinerRunsShutdownTasks(c *gc.C) {
+	s.accessor.machine.life = params.Dying
+	var ran []string
+	task := func(name string) machiner.ShutdownTask {
+		return machiner.ShutdownTask{
+			Name: name,
+			Run: func(<-chan struct{}) error {
+				ran = append(ran, name)
+				return errors.New("ignored")
+			},
+		}
+	}
+	w, err := machiner.NewMachiner(machiner.Config{
+		MachineAccessor: s.accessor,
+		Tag:             s.machineTag,
+		Clock:           s.clock,
+		ShutdownTasks:   []machiner.ShutdownTask{task("flushing logs"), task("detaching storage")},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.accessor.machine.watcher.changes <- struct{}{}
+	err = w.Wait()
+	c.Assert(err, gc.Equals, jworker.ErrTerminateAgent)
+
+	c.Assert(ran, jc.DeepEquals, []string{"flushing logs", "detaching storage"})
+	s.accessor.machine.CheckCallNames(c,
+		"SetMachineAddresses",
+		"SetStatus",
+		"Watch",
+		"Refresh",
+		"Life",
+		"SetShutdownProgress",
+		"SetShutdownProgress",
+		"SetStatus",
+		"EnsureDead",
+	)
+	s.accessor.machine.CheckCall(c, 5, "SetShutdownProgress", "flushing logs")
+	s.accessor.machine.CheckCall(c, 6, "SetShutdownProgress", "detaching storage")
+}
+
+func (s *MachinerSuite) TestMachinerShutdownGracePeriodExpires(c *gc.C) {
+	s.accessor.machine.life = params.Dying
+	started := make(chan struct{})
+	var aborted, ranSecond bool
+	w, err := machiner.NewMachiner(machiner.Config{
+		MachineAccessor:     s.accessor,
+		Tag:                 s.machineTag,
+		Clock:               s.clock,
+		ShutdownGracePeriod: 10 * time.Second,
+		ShutdownTasks: []machiner.ShutdownTask{{
+			Name: "draining units",
+			Run: func(abort <-chan struct{}) error {
+				close(started)
+				<-abort
+				aborted = true
+				return nil
+			},
+		}, {
+			Name: "flushing logs",
+			Run: func(<-chan struct{}) error {
+				ranSecond = true
+				return nil
+			},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.accessor.machine.watcher.changes <- struct{}{}
+	select {
+	case <-started:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for shutdown task")
+	}
+	// Both the grace period and the hostname poll are waiting.
+	err = s.clock.WaitAdvance(10*time.Second, coretesting.LongWait, 2)
+	c.Assert(err, jc.ErrorIsNil)
+	err = w.Wait()
+	c.Assert(err, gc.Equals, jworker.ErrTerminateAgent)
+
+	c.Assert(aborted, jc.IsTrue)
+	c.Assert(ranSecond, jc.IsFalse)
+	s.accessor.machine.CheckCallNames(c,
+		"SetMachineAddresses",
+		"SetStatus",
+		"Watch",
+		"Refresh",
+		"Life",
+		"SetShutdownProgress",
+		"SetStatus",
+		"EnsureDead",
+	)
+}
+
 func (s *MachinerSuite) TestRunStop(c *gc.C) {
 	mr := s.makeMachiner(c, false)
 	c.Assert(worker.Stop(mr), jc.ErrorIsNil)
@@ -298,6 +420,147 @@ func (s *MachinerSuite) TestStartSetsStatus(c *gc.C) {
 	)
 }
 
+func (s *MachinerSuite) TestStartRecordsBootID(c *gc.C) {
+	s.PatchValue(machiner.GetBootID, func() (string, error) {
+		return "boot-id", nil
+	})
+	mr := s.makeMachiner(c, false)
+	c.Assert(stopWorker(mr), jc.ErrorIsNil)
+	s.accessor.machine.CheckCallNames(c,
+		"SetMachineAddresses",
+		"SetBootID",
+		"SetStatus",
+		"Watch",
+	)
+	s.accessor.machine.CheckCall(c, 1, "SetBootID", "boot-id")
+	s.accessor.machine.CheckCall(
+		c, 2, "SetStatus",
+		status.Started, "", map[string]interface{}(nil),
+	)
+}
+
+func (s *MachinerSuite) TestStartReportsUnexpectedReboot(c *gc.C) {
+	s.PatchValue(machiner.GetBootID, func() (string, error) {
+		return "boot-id", nil
+	})
+	s.accessor.machine.rebooted = true
+	mr := s.makeMachiner(c, false)
+	c.Assert(stopWorker(mr), jc.ErrorIsNil)
+	s.accessor.machine.CheckCall(
+		c, 2, "SetStatus",
+		status.Started, "machine rebooted outside of Juju", map[string]interface{}(nil),
+	)
+}
+
+func (s *MachinerSuite) TestStartSetBootIDError(c *gc.C) {
+	s.PatchValue(machiner.GetBootID, func() (string, error) {
+		return "boot-id", nil
+	})
+	s.accessor.machine.SetErrors(
+		nil, // SetMachineAddresses
+		errors.New("cannot set boot id"),
+	)
+	mr := s.makeMachiner(c, false)
+	c.Assert(stopWorker(mr), gc.ErrorMatches, "recording boot id: cannot set boot id")
+	s.accessor.machine.CheckCallNames(c,
+		"SetMachineAddresses",
+		"SetBootID",
+	)
+}
+
+func (s *MachinerSuite) TestRecordsHostnameChanges(c *gc.C) {
+	hostnames := make(chan string, 1)
+	hostnames <- "juju-1"
+	s.PatchValue(machiner.GetHostname, func() (string, error) {
+		select {
+		case hostname := <-hostnames:
+			return hostname, nil
+		default:
+			return "juju-1", nil
+		}
+	})
+	// The hostname is recorded when the worker starts.
+	mr := s.makeMachiner(c, false)
+	defer worker.Stop(mr)
+	s.waitForHostname(c, "juju-1")
+
+	// It is checked again after the poll interval, and recorded only
+	// if it has changed.
+	c.Assert(s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1), jc.ErrorIsNil)
+	hostnames <- "juju-2.example.com"
+	c.Assert(s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1), jc.ErrorIsNil)
+	s.waitForHostname(c, "juju-2.example.com")
+	c.Assert(stopWorker(mr), jc.ErrorIsNil)
+	c.Assert(s.accessor.machine.hostnames(), jc.DeepEquals, []string{"juju-1", "juju-2.example.com"})
+}
+
+func (s *MachinerSuite) waitForHostname(c *gc.C, hostname string) {
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		hostnames := s.accessor.machine.hostnames()
+		if len(hostnames) > 0 && hostnames[len(hostnames)-1] == hostname {
+			return
+		}
+	}
+	c.Fatalf("timed out waiting for hostname %q to be recorded", hostname)
+}
+
+func (s *MachinerSuite) TestRecordsHostInfoChanges(c *gc.C) {
+	mem := uint64(4096)
+	infos := make(chan apimachiner.HostInfo, 1)
+	info := apimachiner.HostInfo{
+		KernelVersion: "4.4.0-159-generic",
+		Series:        "xenial",
+		Hardware:      &instance.HardwareCharacteristics{Mem: &mem},
+	}
+	s.PatchValue(machiner.GetHostInfo, func() (apimachiner.HostInfo, error) {
+		select {
+		case info = <-infos:
+		default:
+		}
+		return info, nil
+	})
+	// The host info is recorded when the worker starts.
+	mr := s.makeMachiner(c, false)
+	defer worker.Stop(mr)
+	s.waitForKernelVersion(c, "4.4.0-159-generic")
+
+	// It is checked again after the poll interval, and recorded only
+	// if it has changed.
+	c.Assert(s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1), jc.ErrorIsNil)
+	newMem := uint64(8192)
+	infos <- apimachiner.HostInfo{
+		KernelVersion: "4.15.0-58-generic",
+		Series:        "bionic",
+		Hardware:      &instance.HardwareCharacteristics{Mem: &newMem},
+	}
+	c.Assert(s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1), jc.ErrorIsNil)
+	s.waitForKernelVersion(c, "4.15.0-58-generic")
+	c.Assert(stopWorker(mr), jc.ErrorIsNil)
+	c.Assert(s.accessor.machine.kernelVersions(), jc.DeepEquals, []string{"4.4.0-159-generic", "4.15.0-58-generic"})
+}
+
+func (s *MachinerSuite) waitForKernelVersion(c *gc.C, version string) {
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		versions := s.accessor.machine.kernelVersions()
+		if len(versions) > 0 && versions[len(versions)-1] == version {
+			return
+		}
+	}
+	c.Fatalf("timed out waiting for kernel version %q to be recorded", version)
+}
+
+func (s *MachinerSuite) TestParseMemTotal(c *gc.C) {
+	mem, err := machiner.ParseMemTotal(strings.NewReader(`
+MemTotal:       16314516 kB
+MemFree:          624376 kB
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(mem, gc.Equals, uint64(15932))
+
+	_, err = machiner.ParseMemTotal(strings.NewReader("MemFree: 624376 kB\n"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *MachinerSuite) TestSetDead(c *gc.C) {
 	s.accessor.machine.life = params.Dying
 	mr := s.makeMachiner(c, false)
@@ -378,6 +641,65 @@ func (s *MachinerSuite) TestMachineAddressesWithClearFlag(c *gc.C) {
 	s.accessor.machine.CheckCall(c, 0, "SetMachineAddresses", []corenetwork.Address(nil))
 }
 
+func (s *MachinerSuite) TestSetMachineAddressesExclusions(c *gc.C) {
+	s.addresses = []net.Addr{
+		&net.IPAddr{IP: net.IPv4(10, 0, 0, 1)},
+		&net.IPAddr{IP: net.IPv4(172, 17, 0, 1)}, // on docker0
+		&net.IPAddr{IP: net.IPv4(10, 20, 5, 5)},  // in excluded subnet
+		&net.IPNet{IP: net.ParseIP("2001:db8::1")},
+	}
+	s.PatchValue(machiner.HostInterfaceAddrs, func() (map[string][]net.Addr, error) {
+		return map[string][]net.Addr{
+			"eth0":    {&net.IPAddr{IP: net.IPv4(10, 0, 0, 1)}},
+			"docker0": {&net.IPNet{IP: net.IPv4(172, 17, 0, 1)}},
+		}, nil
+	})
+
+	w, err := machiner.NewMachiner(machiner.Config{
+		MachineAccessor:   s.accessor,
+		Tag:               s.machineTag,
+		Clock:             s.clock,
+		AddressExclusions: []string{"docker*", "10.20.0.0/16"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stopWorker(w), jc.ErrorIsNil)
+	s.accessor.machine.CheckCall(c, 0, "SetMachineAddresses", []corenetwork.Address{
+		corenetwork.NewScopedAddress("10.0.0.1", corenetwork.ScopeCloudLocal),
+		corenetwork.NewAddress("2001:db8::1"),
+	})
+}
+
+func (s *MachinerSuite) TestMachinerConfigInvalidAddressExclusion(c *gc.C) {
+	_, err := machiner.NewMachiner(machiner.Config{
+		MachineAccessor:   s.accessor,
+		Tag:               s.machineTag,
+		Clock:             s.clock,
+		AddressExclusions: []string{"10.20.0.0/33"},
+	})
+	c.Assert(err, gc.ErrorMatches, `validating config: address exclusion "10.20.0.0/33" not valid`)
+}
+
+func (s *MachinerSuite) TestSetObservedNetworkConfigExclusions(c *gc.C) {
+	eth0 := params.NetworkConfig{InterfaceName: "eth0", Address: "10.0.0.2"}
+	eth0Excluded := params.NetworkConfig{InterfaceName: "eth0", Address: "10.20.0.2"}
+	virbr0 := params.NetworkConfig{InterfaceName: "virbr0", Address: "192.168.122.1"}
+	s.PatchValue(machiner.GetObservedNetworkConfig, func(common.NetworkConfigSource) ([]params.NetworkConfig, error) {
+		return []params.NetworkConfig{eth0, eth0Excluded, virbr0}, nil
+	})
+
+	w, err := machiner.NewMachiner(machiner.Config{
+		MachineAccessor:   s.accessor,
+		Tag:               s.machineTag,
+		Clock:             s.clock,
+		AddressExclusions: []string{"virbr*", "10.20.0.0/16"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.accessor.machine.watcher.changes <- struct{}{}
+	c.Assert(stopWorker(w), jc.ErrorIsNil)
+
+	s.accessor.machine.CheckCall(c, 5, "SetObservedNetworkConfig", []params.NetworkConfig{eth0})
+}
+
 func (s *MachinerSuite) TestGetObservedNetworkConfigEmpty(c *gc.C) {
 	s.PatchValue(machiner.GetObservedNetworkConfig, func(common.NetworkConfigSource) ([]params.NetworkConfig, error) {
 		return []params.NetworkConfig{}, nil
@@ -393,6 +715,7 @@ func (s *MachinerSuite) TestGetObservedNetworkConfigEmpty(c *gc.C) {
 		"Watch",
 		"Refresh",
 		"Life",
+		"Cordoned",
 	)
 }
 
@@ -412,9 +735,177 @@ func (s *MachinerSuite) TestSetObservedNetworkConfig(c *gc.C) {
 		"Refresh",
 		"Life",
 		"SetObservedNetworkConfig",
+		"Cordoned",
 	)
 }
 
+func (s *MachinerSuite) TestSetObservedNetworkConfigChanges(c *gc.C) {
+	eth0 := params.NetworkConfig{InterfaceName: "eth0", Address: "10.0.0.2"}
+	eth1 := params.NetworkConfig{InterfaceName: "eth1", Address: "10.0.1.2"}
+	eth1Changed := params.NetworkConfig{InterfaceName: "eth1", Address: "10.0.1.3"}
+	observed := [][]params.NetworkConfig{
+		{eth0, eth1},
+		{eth0, eth1},
+		{eth0, eth1Changed},
+	}
+	s.PatchValue(machiner.GetObservedNetworkConfig, func(common.NetworkConfigSource) ([]params.NetworkConfig, error) {
+		config := observed[0]
+		observed = observed[1:]
+		return config, nil
+	})
+
+	mr := s.makeMachiner(c, false)
+	s.accessor.machine.watcher.changes <- struct{}{}
+	s.sendLaterChange(c)
+	s.sendLaterChange(c)
+	c.Assert(stopWorker(mr), jc.ErrorIsNil)
+
+	// The config is reported in full first, then not at all while it is
+	// unchanged, then only for the interface that changed.
+	s.accessor.machine.CheckCallNames(c,
+		"SetMachineAddresses",
+		"SetStatus",
+		"Watch",
+		"Refresh",
+		"Life",
+		"SetObservedNetworkConfig",
+		"Cordoned",
+		"Refresh",
+		"Life",
+		"Cordoned",
+		"Refresh",
+		"Life",
+		"SetObservedNetworkConfigChanges",
+		"Cordoned",
+	)
+	s.accessor.machine.CheckCall(c, 5, "SetObservedNetworkConfig", []params.NetworkConfig{eth0, eth1})
+	s.accessor.machine.CheckCall(c, 12, "SetObservedNetworkConfigChanges", []params.NetworkConfig{eth1Changed})
+}
+
+func (s *MachinerSuite) TestReportsNetworkConfigProblems(c *gc.C) {
+	s.PatchValue(machiner.GetObservedNetworkConfig, func(common.NetworkConfigSource) ([]params.NetworkConfig, error) {
+		return []params.NetworkConfig{{InterfaceName: "eth1", CIDR: "10.0.1.0/24", Address: "10.0.1.2"}}, nil
+	})
+	s.accessor.machine.networkProblems = []params.NetworkConfigValidation{{
+		InterfaceName: "eth1",
+		Address:       "10.0.1.2",
+		CIDR:          "10.0.1.0/24",
+		Message:       `subnet "10.0.1.0/24" is not known to the model`,
+	}}
+
+	mr := s.makeMachiner(c, false)
+	s.accessor.machine.watcher.changes <- struct{}{}
+	c.Assert(stopWorker(mr), jc.ErrorIsNil)
+
+	s.accessor.machine.CheckCallNames(c,
+		"SetMachineAddresses",
+		"SetStatus",
+		"Watch",
+		"Refresh",
+		"Life",
+		"SetObservedNetworkConfig",
+		"Cordoned",
+		"SetStatus",
+	)
+	s.accessor.machine.CheckCall(c, 7, "SetStatus",
+		status.Started,
+		`network config: eth1: subnet "10.0.1.0/24" is not known to the model`,
+		map[string]interface{}{
+			"network-config": []interface{}{
+				map[string]interface{}{
+					"interface": "eth1",
+					"address":   "10.0.1.2",
+					"cidr":      "10.0.1.0/24",
+					"message":   `subnet "10.0.1.0/24" is not known to the model`,
+				},
+			},
+		},
+	)
+}
+
+func (s *MachinerSuite) TestChangesCoalesced(c *gc.C) {
+	mr := s.makeMachiner(c, false)
+	s.accessor.machine.watcher.changes <- struct{}{}
+	s.waitForCalls(c, "Refresh", 1)
+
+	// A burst of changes is handled once, after the delay.
+	for i := 0; i < 3; i++ {
+		s.accessor.machine.watcher.changes <- struct{}{}
+	}
+	c.Assert(s.clock.WaitAdvance(machiner.DefaultChangeDelay, coretesting.LongWait, 2), jc.ErrorIsNil)
+	s.waitForCalls(c, "Refresh", 2)
+	c.Assert(stopWorker(mr), jc.ErrorIsNil)
+	c.Assert(s.countCalls("Refresh"), gc.Equals, 2)
+}
+
+// sendLaterChange sends a change to the machine after the first, which
+// the machiner handles after a delay, and waits for it to be handled.
+func (s *MachinerSuite) sendLaterChange(c *gc.C) {
+	refreshes := s.countCalls("Refresh")
+	s.accessor.machine.watcher.changes <- struct{}{}
+	// The hostname poll and the change delay are waiting on the clock.
+	c.Assert(s.clock.WaitAdvance(machiner.DefaultChangeDelay, coretesting.LongWait, 2), jc.ErrorIsNil)
+	s.waitForCalls(c, "Refresh", refreshes+1)
+}
+
+func (s *MachinerSuite) waitForCalls(c *gc.C, funcName string, n int) {
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		if s.countCalls(funcName) >= n {
+			return
+		}
+	}
+	c.Fatalf("timed out waiting for %d calls to %s", n, funcName)
+}
+
+func (s *MachinerSuite) countCalls(funcName string) int {
+	count := 0
+	for _, call := range s.accessor.machine.Calls() {
+		if call.FuncName == funcName {
+			count++
+		}
+	}
+	return count
+}
+
+func (s *MachinerSuite) TestReflectsCordoned(c *gc.C) {
+	s.accessor.machine.setCordoned(true)
+	mr := s.makeMachiner(c, false)
+	s.accessor.machine.watcher.changes <- struct{}{}
+	s.waitForCalls(c, "Cordoned", 1)
+
+	// The status is only set again once the machine is uncordoned.
+	s.sendLaterChange(c)
+	s.waitForCalls(c, "Cordoned", 2)
+	s.accessor.machine.setCordoned(false)
+	s.sendLaterChange(c)
+	s.waitForCalls(c, "Cordoned", 3)
+	c.Assert(stopWorker(mr), jc.ErrorIsNil)
+
+	var infos []string
+	for _, call := range s.accessor.machine.Calls() {
+		if call.FuncName == "SetStatus" {
+			c.Assert(call.Args[0], gc.Equals, status.Started)
+			infos = append(infos, call.Args[1].(string))
+		}
+	}
+	c.Assert(infos, jc.DeepEquals, []string{"", "machine cordoned", ""})
+}
+
+func (s *MachinerSuite) TestCordonedNotSupported(c *gc.C) {
+	s.accessor.machine.SetErrors(
+		nil, // SetMachineAddresses
+		nil, // SetStatus
+		nil, // Watch
+		nil, // Refresh
+		errors.NotSupportedf("cordoning machines"),
+	)
+	mr := s.makeMachiner(c, false)
+	s.accessor.machine.watcher.changes <- struct{}{}
+	s.waitForCalls(c, "Cordoned", 1)
+	c.Assert(stopWorker(mr), jc.ErrorIsNil)
+	c.Assert(s.countCalls("SetStatus"), gc.Equals, 1)
+}
+
 func (s *MachinerSuite) TestAliveErrorGetObservedNetworkConfig(c *gc.C) {
 	s.PatchValue(machiner.GetObservedNetworkConfig, func(common.NetworkConfigSource) ([]params.NetworkConfig, error) {
 		return nil, errors.New("no config!")
@@ -441,6 +932,7 @@ func (s *MachinerSuite) makeMachiner(
 		MachineAccessor:              s.accessor,
 		Tag:                          s.machineTag,
 		ClearMachineAddressesOnStart: ignoreAddresses,
+		Clock:                        s.clock,
 	})
 	c.Assert(err, jc.ErrorIsNil)
 	return w
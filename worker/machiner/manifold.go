@@ -4,6 +4,9 @@
 package machiner
 
 import (
+	"time"
+
+	"github.com/juju/clock"
 	"github.com/juju/errors"
 	"gopkg.in/juju/names.v3"
 	"gopkg.in/juju/worker.v1"
@@ -21,6 +24,11 @@ type ManifoldConfig struct {
 	AgentName         string
 	APICallerName     string
 	FanConfigurerName string
+	Clock             clock.Clock
+
+	// ShutdownTasks are run by the machiner when the machine becomes
+	// Dying, before it is made Dead.
+	ShutdownTasks []ShutdownTask
 }
 
 // Manifold returns a dependency manifold that runs a machiner worker, using
@@ -48,7 +56,7 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 			if !fanConfigurerReady {
 				return nil, dependency.ErrMissing
 			}
-			return newWorker(agent, apiCaller)
+			return newWorker(agent, apiCaller, config)
 		},
 	}
 }
@@ -58,9 +66,17 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 // TODO(waigani) This function is currently covered by functional tests
 // under the machine agent. Add unit tests once infrastructure to do so is
 // in place.
-func newWorker(a agent.Agent, apiCaller base.APICaller) (worker.Worker, error) {
+func newWorker(a agent.Agent, apiCaller base.APICaller, config ManifoldConfig) (worker.Worker, error) {
 	currentConfig := a.CurrentConfig()
 
+	var shutdownGracePeriod time.Duration
+	if v := currentConfig.Value(agent.MachineShutdownGracePeriod); v != "" {
+		var err error
+		if shutdownGracePeriod, err = time.ParseDuration(v); err != nil {
+			return nil, errors.Annotatef(err, "parsing %s", agent.MachineShutdownGracePeriod)
+		}
+	}
+
 	// TODO(fwereade): this functionality should be on the
 	// machiner facade instead -- or, better yet, separate
 	// the networking concerns from the lifecycle ones and
@@ -90,6 +106,10 @@ func newWorker(a agent.Agent, apiCaller base.APICaller) (worker.Worker, error) {
 		MachineAccessor:              accessor,
 		Tag:                          tag.(names.MachineTag),
 		ClearMachineAddressesOnStart: ignoreMachineAddresses,
+		AddressExclusions:            modelConfig.MachineAddressExclusions(),
+		Clock:                        config.Clock,
+		ShutdownTasks:                config.ShutdownTasks,
+		ShutdownGracePeriod:          shutdownGracePeriod,
 	})
 	if err != nil {
 		return nil, errors.Annotate(err, "cannot start machiner worker")
@@ -4,9 +4,12 @@
 package machiner_test
 
 import (
+	"sync"
+
 	gitjujutesting "github.com/juju/testing"
 	"gopkg.in/juju/names.v3"
 
+	apimachiner "github.com/juju/juju/api/machiner"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/status"
@@ -31,8 +34,14 @@ func (w *mockWatcher) Wait() error {
 type mockMachine struct {
 	machiner.Machine
 	gitjujutesting.Stub
-	watcher mockWatcher
-	life    params.Life
+	watcher  mockWatcher
+	life     params.Life
+	rebooted bool
+
+	networkProblems []params.NetworkConfigValidation
+
+	mu       sync.Mutex
+	cordoned bool
 }
 
 func (m *mockMachine) Refresh() error {
@@ -55,11 +64,83 @@ func (m *mockMachine) SetMachineAddresses(addresses []network.Address) error {
 	return m.NextErr()
 }
 
-func (m *mockMachine) SetObservedNetworkConfig(netConfig []params.NetworkConfig) error {
+func (m *mockMachine) SetObservedNetworkConfig(netConfig []params.NetworkConfig) ([]params.NetworkConfigValidation, error) {
 	m.MethodCall(m, "SetObservedNetworkConfig", netConfig)
+	if err := m.NextErr(); err != nil {
+		return nil, err
+	}
+	return m.networkProblems, nil
+}
+
+func (m *mockMachine) SetObservedNetworkConfigChanges(netConfig []params.NetworkConfig) ([]params.NetworkConfigValidation, error) {
+	m.MethodCall(m, "SetObservedNetworkConfigChanges", netConfig)
+	if err := m.NextErr(); err != nil {
+		return nil, err
+	}
+	return m.networkProblems, nil
+}
+
+func (m *mockMachine) SetBootID(bootID string) (bool, error) {
+	m.MethodCall(m, "SetBootID", bootID)
+	if err := m.NextErr(); err != nil {
+		return false, err
+	}
+	return m.rebooted, nil
+}
+
+func (m *mockMachine) SetHostname(hostname string) error {
+	m.MethodCall(m, "SetHostname", hostname)
 	return m.NextErr()
 }
 
+func (m *mockMachine) SetShutdownProgress(progress string) error {
+	m.MethodCall(m, "SetShutdownProgress", progress)
+	return m.NextErr()
+}
+
+func (m *mockMachine) SetHostInfo(info apimachiner.HostInfo) error {
+	m.MethodCall(m, "SetHostInfo", info)
+	return m.NextErr()
+}
+
+func (m *mockMachine) Cordoned() (bool, error) {
+	m.MethodCall(m, "Cordoned")
+	if err := m.NextErr(); err != nil {
+		return false, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.cordoned, nil
+}
+
+func (m *mockMachine) setCordoned(cordoned bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cordoned = cordoned
+}
+
+// kernelVersions returns the kernel versions recorded with SetHostInfo.
+func (m *mockMachine) kernelVersions() []string {
+	var versions []string
+	for _, call := range m.Calls() {
+		if call.FuncName == "SetHostInfo" {
+			versions = append(versions, call.Args[0].(apimachiner.HostInfo).KernelVersion)
+		}
+	}
+	return versions
+}
+
+// hostnames returns the hostnames recorded with SetHostname.
+func (m *mockMachine) hostnames() []string {
+	var hostnames []string
+	for _, call := range m.Calls() {
+		if call.FuncName == "SetHostname" {
+			hostnames = append(hostnames, call.Args[0].(string))
+		}
+	}
+	return hostnames
+}
+
 func (m *mockMachine) SetStatus(status status.Status, info string, data map[string]interface{}) error {
 	m.MethodCall(m, "SetStatus", status, info, data)
 	return m.NextErr()
@@ -0,0 +1,97 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machiner
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// DefaultShutdownGracePeriod is the time allowed for a machine's shutdown
+// tasks to run, if no other is configured.
+const DefaultShutdownGracePeriod = 5 * time.Minute
+
+// ShutdownTask is a task run by the machiner when its machine becomes
+// Dying, before the machine is made Dead; for example to flush logs,
+// drain local units, or detach storage.
+type ShutdownTask struct {
+	// Name describes the task, and is reported as the machine's
+	// shutdown progress while it runs.
+	Name string
+
+	// Run runs the task. It should return early if abort is closed,
+	// which happens when the grace period runs out or the worker is
+	// stopped.
+	Run func(abort <-chan struct{}) error
+}
+
+// runShutdownTasks runs the configured shutdown tasks in turn, reporting
+// each as the machine's shutdown progress. Once the grace period has
+// passed, the running task is aborted and any remaining are skipped.
+// Failed tasks are logged, and do not prevent the machine from being
+// made Dead.
+//
+// It returns false if the worker was stopped before the tasks finished,
+// in which case they are run again when it is restarted.
+func (mr *Machiner) runShutdownTasks(abort <-chan struct{}) bool {
+	if mr.shutdownDone || len(mr.config.ShutdownTasks) == 0 {
+		return true
+	}
+	gracePeriod := mr.config.ShutdownGracePeriod
+	if gracePeriod <= 0 {
+		gracePeriod = DefaultShutdownGracePeriod
+	}
+	timeout := mr.config.Clock.After(gracePeriod)
+
+	taskAbort := make(chan struct{})
+	expired := make(chan struct{})
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		defer close(taskAbort)
+		select {
+		case <-abort:
+		case <-timeout:
+			close(expired)
+		case <-done:
+		}
+	}()
+
+	for _, task := range mr.config.ShutdownTasks {
+		select {
+		case <-abort:
+			return false
+		case <-expired:
+			logger.Warningf("%q shutdown grace period of %v expired before %q", mr.config.Tag, gracePeriod, task.Name)
+			mr.shutdownDone = true
+			return true
+		default:
+		}
+		mr.reportShutdownProgress(task.Name)
+		logger.Infof("%q running shutdown task %q", mr.config.Tag, task.Name)
+		if err := task.Run(taskAbort); err != nil {
+			logger.Warningf("%q shutdown task %q failed: %v", mr.config.Tag, task.Name, err)
+		}
+	}
+	select {
+	case <-abort:
+		return false
+	default:
+	}
+	mr.shutdownDone = true
+	return true
+}
+
+// reportShutdownProgress records the shutdown task being run, so that the
+// machine is seen to be draining. Failing to do so is not fatal, as it
+// only affects what is reported.
+func (mr *Machiner) reportShutdownProgress(progress string) {
+	err := mr.machine.SetShutdownProgress(progress)
+	if errors.IsNotSupported(err) {
+		logger.Debugf("not reporting shutdown progress for %q: %v", mr.config.Tag, err)
+	} else if err != nil {
+		logger.Warningf("cannot report shutdown progress for %q: %v", mr.config.Tag, err)
+	}
+}
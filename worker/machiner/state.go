@@ -24,7 +24,13 @@ type Machine interface {
 	SetMachineAddresses(addresses []network.Address) error
 	SetStatus(machineStatus status.Status, info string, data map[string]interface{}) error
 	Watch() (watcher.NotifyWatcher, error)
-	SetObservedNetworkConfig(netConfig []params.NetworkConfig) error
+	SetObservedNetworkConfig(netConfig []params.NetworkConfig) ([]params.NetworkConfigValidation, error)
+	SetObservedNetworkConfigChanges(netConfig []params.NetworkConfig) ([]params.NetworkConfigValidation, error)
+	SetBootID(bootID string) (bool, error)
+	SetHostname(hostname string) error
+	SetShutdownProgress(progress string) error
+	SetHostInfo(info machiner.HostInfo) error
+	Cordoned() (bool, error)
 }
 
 type APIMachineAccessor struct {
@@ -71,6 +71,13 @@ func (ctx *limitedContext) ModelType() model.ModelType {
 	return model.IAAS
 }
 
+// HookSandbox implements runner.Context.
+func (ctx *limitedContext) HookSandbox() context.HookSandbox {
+	// The model config is not available to the meter-status-changed hook,
+	// which is run without a sandbox.
+	return context.HookSandbox{}
+}
+
 // SetProcess implements runner.Context.
 func (ctx *limitedContext) SetProcess(process context.HookProcess) {}
 
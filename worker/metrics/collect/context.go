@@ -83,6 +83,13 @@ func (ctx *hookContext) addJujuUnitsMetric() error {
 	return nil
 }
 
+// HookSandbox implements runner.Context.
+func (ctx *hookContext) HookSandbox() context.HookSandbox {
+	// The model config is not available to the collect-metrics hook,
+	// which is run without a sandbox.
+	return context.HookSandbox{}
+}
+
 // SetProcess implements runner.Context.
 func (ctx *hookContext) SetProcess(process context.HookProcess) {}
 
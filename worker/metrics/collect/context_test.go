@@ -57,6 +57,7 @@ func (*dummyPaths) GetJujucSocket() sockets.Socket {
 }
 func (*dummyPaths) GetMetricsSpoolDir() string      { return "/dummy/spool" }
 func (*dummyPaths) ComponentDir(name string) string { return "/dummy/" + name }
+func (*dummyPaths) GetResourceCacheDir() string     { return "/dummy/resource-cache" }
 
 func (s *ContextSuite) TestHookContextEnv(c *gc.C) {
 	ctx := collect.NewHookContext("u/0", s.recorder)
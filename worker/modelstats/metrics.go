@@ -0,0 +1,76 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package modelstats
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/juju/juju/state"
+)
+
+const (
+	metricsNamespace = "juju_model"
+
+	modelLabel      = "model"
+	collectionLabel = "collection"
+)
+
+var labelNames = []string{modelLabel, collectionLabel}
+
+// collector is a prometheus.Collector exposing the most recently
+// collected model document statistics.
+type collector struct {
+	countDesc *prometheus.Desc
+	sizeDesc  *prometheus.Desc
+
+	mu    sync.Mutex
+	stats map[string][]state.CollectionStats
+}
+
+func newCollector() *collector {
+	return &collector{
+		countDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(metricsNamespace, "", "documents"),
+			"The number of documents a model has in a collection.",
+			labelNames, nil,
+		),
+		sizeDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(metricsNamespace, "", "document_bytes"),
+			"The approximate size in bytes of the documents a model has in a collection.",
+			labelNames, nil,
+		),
+	}
+}
+
+func (c *collector) update(stats map[string][]state.CollectionStats) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats = stats
+}
+
+// Describe is part of the prometheus.Collector interface.
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.countDesc
+	ch <- c.sizeDesc
+}
+
+// Collect is part of the prometheus.Collector interface.
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for modelUUID, collections := range c.stats {
+		for _, stats := range collections {
+			ch <- prometheus.MustNewConstMetric(
+				c.countDesc, prometheus.GaugeValue, float64(stats.Count),
+				modelUUID, stats.Collection,
+			)
+			ch <- prometheus.MustNewConstMetric(
+				c.sizeDesc, prometheus.GaugeValue, float64(stats.Size),
+				modelUUID, stats.Collection,
+			)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package modelstats
+
+import (
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/juju/worker.v1"
+	"gopkg.in/juju/worker.v1/catacomb"
+
+	"github.com/juju/juju/state"
+)
+
+var logger = loggo.GetLogger("juju.worker.modelstats")
+
+// Backend exposes the state functionality needed by the worker.
+type Backend interface {
+	// CollectModelStats counts the documents of each model in each
+	// model collection, keyed by model UUID.
+	CollectModelStats() (map[string][]state.CollectionStats, error)
+
+	// RecordModelStats records the given statistics, so they can be
+	// read through the API.
+	RecordModelStats(map[string][]state.CollectionStats) error
+}
+
+// Config defines the parameters of the modelstats worker.
+type Config struct {
+	Backend              Backend
+	Clock                clock.Clock
+	Interval             time.Duration
+	PrometheusRegisterer prometheus.Registerer
+}
+
+// Validate returns an error if Config cannot drive a modelstats worker.
+func (config Config) Validate() error {
+	if config.Backend == nil {
+		return errors.NotValidf("nil Backend")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if config.Interval <= 0 {
+		return errors.NotValidf("non-positive Interval")
+	}
+	if config.PrometheusRegisterer == nil {
+		return errors.NotValidf("nil PrometheusRegisterer")
+	}
+	return nil
+}
+
+// New returns a worker that periodically collects the number and size
+// of the documents of every model, records them in state, and exposes
+// them as Prometheus metrics.
+func New(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &modelStatsWorker{
+		config:    config,
+		collector: newCollector(),
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+type modelStatsWorker struct {
+	catacomb  catacomb.Catacomb
+	config    Config
+	collector *collector
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *modelStatsWorker) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *modelStatsWorker) Wait() error {
+	return w.catacomb.Wait()
+}
+
+func (w *modelStatsWorker) loop() error {
+	if err := w.config.PrometheusRegisterer.Register(w.collector); err != nil {
+		logger.Warningf("cannot register model stats metrics: %v", err)
+	} else {
+		defer w.config.PrometheusRegisterer.Unregister(w.collector)
+	}
+	timer := w.config.Clock.After(0)
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case <-timer:
+			if err := w.collect(); err != nil {
+				return errors.Trace(err)
+			}
+			timer = w.config.Clock.After(w.config.Interval)
+		}
+	}
+}
+
+func (w *modelStatsWorker) collect() error {
+	stats, err := w.config.Backend.CollectModelStats()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	w.collector.update(stats)
+	logger.Debugf("collected document stats for %d models", len(stats))
+	return errors.Trace(w.config.Backend.RecordModelStats(stats))
+}
@@ -0,0 +1,125 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package modelstats_test
+
+import (
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/prometheus/client_golang/prometheus"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/worker.v1/workertest"
+
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/modelstats"
+)
+
+type WorkerSuite struct {
+	testing.IsolationSuite
+
+	backend  *stubBackend
+	clock    *testclock.Clock
+	registry *prometheus.Registry
+	config   modelstats.Config
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.backend = &stubBackend{
+		stats: map[string][]state.CollectionStats{
+			"model-uuid": {{Collection: "machines", Count: 3, Size: 1500}},
+		},
+		recorded: make(chan map[string][]state.CollectionStats, 1),
+	}
+	s.clock = testclock.NewClock(time.Time{})
+	s.registry = prometheus.NewPedanticRegistry()
+	s.config = modelstats.Config{
+		Backend:              s.backend,
+		Clock:                s.clock,
+		Interval:             time.Minute,
+		PrometheusRegisterer: s.registry,
+	}
+}
+
+func (s *WorkerSuite) TestValidate(c *gc.C) {
+	s.testValidate(c, func(config *modelstats.Config) { config.Backend = nil }, "nil Backend not valid")
+	s.testValidate(c, func(config *modelstats.Config) { config.Clock = nil }, "nil Clock not valid")
+	s.testValidate(c, func(config *modelstats.Config) { config.Interval = 0 }, "non-positive Interval not valid")
+	s.testValidate(c, func(config *modelstats.Config) { config.PrometheusRegisterer = nil }, "nil PrometheusRegisterer not valid")
+}
+
+func (s *WorkerSuite) testValidate(c *gc.C, f func(*modelstats.Config), expect string) {
+	config := s.config
+	f(&config)
+	w, err := modelstats.New(config)
+	c.Check(w, gc.IsNil)
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+	c.Check(err, gc.ErrorMatches, expect)
+}
+
+func (s *WorkerSuite) TestCollectsPeriodically(c *gc.C) {
+	w, err := modelstats.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	s.assertRecorded(c)
+	c.Assert(s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1), jc.ErrorIsNil)
+	s.assertRecorded(c)
+}
+
+func (s *WorkerSuite) TestExposesMetrics(c *gc.C) {
+	w, err := modelstats.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+	s.assertRecorded(c)
+
+	families, err := s.registry.Gather()
+	c.Assert(err, jc.ErrorIsNil)
+	values := make(map[string]float64)
+	for _, family := range families {
+		c.Assert(family.Metric, gc.HasLen, 1)
+		labels := make(map[string]string)
+		for _, label := range family.Metric[0].Label {
+			labels[label.GetName()] = label.GetValue()
+		}
+		c.Check(labels, jc.DeepEquals, map[string]string{
+			"model":      "model-uuid",
+			"collection": "machines",
+		})
+		values[family.GetName()] = family.Metric[0].Gauge.GetValue()
+	}
+	c.Assert(values, jc.DeepEquals, map[string]float64{
+		"juju_model_documents":      3,
+		"juju_model_document_bytes": 1500,
+	})
+}
+
+func (s *WorkerSuite) assertRecorded(c *gc.C) {
+	select {
+	case stats := <-s.backend.recorded:
+		c.Assert(stats, jc.DeepEquals, s.backend.stats)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for stats to be recorded")
+	}
+}
+
+type stubBackend struct {
+	stats    map[string][]state.CollectionStats
+	recorded chan map[string][]state.CollectionStats
+}
+
+func (b *stubBackend) CollectModelStats() (map[string][]state.CollectionStats, error) {
+	return b.stats, nil
+}
+
+func (b *stubBackend) RecordModelStats(stats map[string][]state.CollectionStats) error {
+	b.recorded <- stats
+	return nil
+}
@@ -0,0 +1,271 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package networkreconciler provides a worker that periodically
+// rediscovers the subnets and spaces known to the provider, compares
+// them with those recorded for the model, and reports any drift in the
+// model status.
+package networkreconciler
+
+import (
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"gopkg.in/juju/worker.v1"
+	"gopkg.in/juju/worker.v1/catacomb"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/worker/common"
+)
+
+var logger = loggo.GetLogger("juju.worker.networkreconciler")
+
+// Facade exposes controller functionality to a Worker.
+type Facade interface {
+	ModelSubnets() ([]params.Subnet, error)
+	SetNetworkDrift(params.NetworkDrift) error
+}
+
+// Config defines the parameters of the networkreconciler worker.
+type Config struct {
+	Facade        Facade
+	Networking    environs.Networking
+	CredentialAPI common.CredentialAPI
+	Clock         clock.Clock
+
+	// Interval is how often the worker compares the model's subnets
+	// with the provider's.
+	Interval time.Duration
+}
+
+// Validate returns an error if Config cannot drive a networkreconciler
+// worker.
+func (config Config) Validate() error {
+	if config.Facade == nil {
+		return errors.NotValidf("nil Facade")
+	}
+	if config.Networking == nil {
+		return errors.NotValidf("nil Networking")
+	}
+	if config.CredentialAPI == nil {
+		return errors.NotValidf("nil CredentialAPI")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if config.Interval <= 0 {
+		return errors.NotValidf("non-positive Interval")
+	}
+	return nil
+}
+
+// New returns a Worker that periodically compares the subnets recorded
+// for the model with those reported by the provider.
+func New(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &networkReconciler{config: config}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+type networkReconciler struct {
+	catacomb    catacomb.Catacomb
+	config      Config
+	callContext context.ProviderCallContext
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *networkReconciler) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *networkReconciler) Wait() error {
+	return w.catacomb.Wait()
+}
+
+func (w *networkReconciler) loop() error {
+	w.callContext = common.NewCloudCallContext(w.config.CredentialAPI, w.catacomb.Dying)
+	for {
+		if err := w.reconcile(); err != nil {
+			return errors.Trace(err)
+		}
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case <-w.config.Clock.After(w.config.Interval):
+		}
+	}
+}
+
+// reconcile reports the differences between the model's and the
+// provider's subnets. Failing to query the provider is not fatal; the
+// comparison is retried at the next interval.
+func (w *networkReconciler) reconcile() error {
+	providerSubnets, discoveredSpaces, err := w.providerSubnets()
+	if err != nil {
+		logger.Warningf("cannot discover provider subnets: %v", err)
+		return nil
+	}
+	modelSubnets, err := w.config.Facade.ModelSubnets()
+	if err != nil {
+		return errors.Annotate(err, "getting model subnets")
+	}
+	drift := diffSubnets(modelSubnets, providerSubnets, discoveredSpaces)
+	for _, subnet := range drift.Added {
+		logger.Infof("subnet %s (%s) is known to the provider but not to the model", subnet.CIDR, subnet.ProviderId)
+	}
+	for _, subnet := range drift.Removed {
+		logger.Infof("subnet %s (%s) is no longer known to the provider", subnet.CIDR, subnet.ProviderId)
+	}
+	for _, change := range drift.Changed {
+		logger.Infof("subnet %s %s is %q in the model but %q in the provider",
+			change.CIDR, change.Attribute, change.Model, change.Provider)
+	}
+	if err := w.config.Facade.SetNetworkDrift(drift); err != nil {
+		return errors.Annotate(err, "setting network drift")
+	}
+	return nil
+}
+
+// providerSubnets returns the subnets reported by the provider, in the
+// same way as reload-spaces discovers them, and whether they were
+// discovered through the provider's spaces.
+func (w *networkReconciler) providerSubnets() ([]params.Subnet, bool, error) {
+	netEnv := w.config.Networking
+	canDiscoverSpaces, err := netEnv.SupportsSpaceDiscovery(w.callContext)
+	if err != nil && !errors.IsNotSupported(err) {
+		return nil, false, errors.Trace(err)
+	}
+
+	var subnets []params.Subnet
+	if canDiscoverSpaces {
+		spaces, err := netEnv.Spaces(w.callContext)
+		if err != nil {
+			return nil, false, errors.Trace(err)
+		}
+		for _, space := range spaces {
+			for _, info := range space.Subnets {
+				info.ProviderSpaceId = space.ProviderId
+				subnets = appendProviderSubnet(subnets, info)
+			}
+		}
+	} else {
+		infos, err := netEnv.Subnets(w.callContext, instance.UnknownId, nil)
+		if err != nil {
+			return nil, false, errors.Trace(err)
+		}
+		for _, info := range infos {
+			subnets = appendProviderSubnet(subnets, info)
+		}
+	}
+	return subnets, canDiscoverSpaces, nil
+}
+
+// appendProviderSubnet appends the given subnet unless it is one that
+// reload-spaces would not record.
+func appendProviderSubnet(subnets []params.Subnet, info network.SubnetInfo) []params.Subnet {
+	ip, _, err := net.ParseCIDR(info.CIDR)
+	if err != nil {
+		logger.Debugf("ignoring provider subnet with invalid CIDR %q", info.CIDR)
+		return subnets
+	}
+	if ip.IsInterfaceLocalMulticast() || ip.IsLinkLocalMulticast() || ip.IsLinkLocalUnicast() {
+		return subnets
+	}
+	return append(subnets, params.Subnet{
+		CIDR:              info.CIDR,
+		ProviderId:        string(info.ProviderId),
+		ProviderNetworkId: string(info.ProviderNetworkId),
+		ProviderSpaceId:   string(info.ProviderSpaceId),
+		VLANTag:           info.VLANTag,
+		Zones:             info.AvailabilityZones,
+	})
+}
+
+// diffSubnets compares the model's subnets with the provider's. Subnets
+// are matched by provider ID where they have one, and by CIDR
+// otherwise. The provider space of each subnet is only compared when
+// the provider's subnets were discovered through its spaces.
+func diffSubnets(modelSubnets, providerSubnets []params.Subnet, compareSpaces bool) params.NetworkDrift {
+	var drift params.NetworkDrift
+	known := make(map[string]params.Subnet)
+	for _, subnet := range modelSubnets {
+		if subnet.Life != params.Alive {
+			continue
+		}
+		known[subnetKey(subnet)] = subnet
+	}
+
+	for _, provider := range providerSubnets {
+		key := subnetKey(provider)
+		model, ok := known[key]
+		if !ok {
+			drift.Added = append(drift.Added, provider)
+			continue
+		}
+		delete(known, key)
+
+		changed := func(attribute, modelValue, providerValue string) {
+			if modelValue != providerValue {
+				drift.Changed = append(drift.Changed, params.SubnetDrift{
+					CIDR:      model.CIDR,
+					Attribute: attribute,
+					Model:     modelValue,
+					Provider:  providerValue,
+				})
+			}
+		}
+		changed("cidr", model.CIDR, provider.CIDR)
+		changed("vlan-tag", strconv.Itoa(model.VLANTag), strconv.Itoa(provider.VLANTag))
+		changed("zones", zonesString(model.Zones), zonesString(provider.Zones))
+		if compareSpaces {
+			changed("provider-space-id", model.ProviderSpaceId, provider.ProviderSpaceId)
+		}
+	}
+	for _, subnet := range known {
+		drift.Removed = append(drift.Removed, subnet)
+	}
+
+	sort.Slice(drift.Added, func(i, j int) bool {
+		return drift.Added[i].CIDR < drift.Added[j].CIDR
+	})
+	sort.Slice(drift.Removed, func(i, j int) bool {
+		return drift.Removed[i].CIDR < drift.Removed[j].CIDR
+	})
+	sort.SliceStable(drift.Changed, func(i, j int) bool {
+		return drift.Changed[i].CIDR < drift.Changed[j].CIDR
+	})
+	return drift
+}
+
+func subnetKey(subnet params.Subnet) string {
+	if subnet.ProviderId != "" {
+		return "id:" + subnet.ProviderId
+	}
+	return "cidr:" + subnet.CIDR
+}
+
+func zonesString(zones []string) string {
+	sorted := append([]string(nil), zones...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
@@ -0,0 +1,265 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package networkreconciler_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/worker.v1/workertest"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/context"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/networkreconciler"
+)
+
+type WorkerSuite struct {
+	jujutesting.IsolationSuite
+
+	clock      *testclock.Clock
+	facade     *fakeFacade
+	networking *fakeNetworking
+	config     networkreconciler.Config
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.clock = testclock.NewClock(time.Now())
+	s.facade = &fakeFacade{
+		subnets: []params.Subnet{{
+			CIDR:       "10.0.0.0/24",
+			ProviderId: "subnet-0",
+			VLANTag:    0,
+			Zones:      []string{"az1", "az2"},
+			Life:       params.Alive,
+		}, {
+			CIDR:       "10.0.1.0/24",
+			ProviderId: "subnet-1",
+			Life:       params.Alive,
+		}, {
+			CIDR: "192.168.0.0/24",
+			Life: params.Alive,
+		}, {
+			CIDR:       "10.0.9.0/24",
+			ProviderId: "subnet-9",
+			Life:       params.Dead,
+		}},
+		drift: make(chan params.NetworkDrift, 10),
+	}
+	s.networking = &fakeNetworking{
+		subnets: []network.SubnetInfo{{
+			CIDR:              "10.0.0.0/24",
+			ProviderId:        "subnet-0",
+			AvailabilityZones: []string{"az2", "az1"},
+		}, {
+			CIDR:       "10.0.1.0/24",
+			ProviderId: "subnet-1",
+			VLANTag:    42,
+		}, {
+			CIDR:       "10.0.2.0/24",
+			ProviderId: "subnet-2",
+		}, {
+			CIDR:       "169.254.0.0/16",
+			ProviderId: "link-local",
+		}},
+	}
+	s.config = networkreconciler.Config{
+		Facade:        s.facade,
+		Networking:    s.networking,
+		CredentialAPI: fakeCredentialAPI{},
+		Clock:         s.clock,
+		Interval:      time.Minute,
+	}
+}
+
+func (s *WorkerSuite) startWorker(c *gc.C) {
+	w, err := networkreconciler.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(c *gc.C) { workertest.CleanKill(c, w) })
+}
+
+func (s *WorkerSuite) waitForDrift(c *gc.C, expected params.NetworkDrift) {
+	select {
+	case drift := <-s.facade.drift:
+		c.Assert(drift, jc.DeepEquals, expected)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for network drift")
+	}
+}
+
+func (s *WorkerSuite) TestInvalidConfig(c *gc.C) {
+	s.config.Networking = nil
+	_, err := networkreconciler.New(s.config)
+	c.Assert(err, gc.ErrorMatches, "nil Networking not valid")
+}
+
+func (s *WorkerSuite) TestReportsSubnetDrift(c *gc.C) {
+	s.startWorker(c)
+	s.waitForDrift(c, params.NetworkDrift{
+		Added: []params.Subnet{{
+			CIDR:       "10.0.2.0/24",
+			ProviderId: "subnet-2",
+		}},
+		Removed: []params.Subnet{{
+			CIDR: "192.168.0.0/24",
+			Life: params.Alive,
+		}},
+		Changed: []params.SubnetDrift{{
+			CIDR:      "10.0.1.0/24",
+			Attribute: "vlan-tag",
+			Model:     "0",
+			Provider:  "42",
+		}},
+	})
+}
+
+func (s *WorkerSuite) TestComparesProviderSpaces(c *gc.C) {
+	s.facade.subnets = []params.Subnet{{
+		CIDR:            "10.0.0.0/24",
+		ProviderId:      "subnet-0",
+		ProviderSpaceId: "sp-db",
+		Life:            params.Alive,
+	}}
+	s.networking.spaces = []network.SpaceInfo{{
+		Name:       "web",
+		ProviderId: "sp-web",
+		Subnets: []network.SubnetInfo{{
+			CIDR:       "10.0.0.0/24",
+			ProviderId: "subnet-0",
+		}},
+	}}
+	s.startWorker(c)
+	s.waitForDrift(c, params.NetworkDrift{
+		Changed: []params.SubnetDrift{{
+			CIDR:      "10.0.0.0/24",
+			Attribute: "provider-space-id",
+			Model:     "sp-db",
+			Provider:  "sp-web",
+		}},
+	})
+}
+
+func (s *WorkerSuite) TestClearsResolvedDrift(c *gc.C) {
+	s.startWorker(c)
+	select {
+	case <-s.facade.drift:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for network drift")
+	}
+
+	s.networking.setSubnets([]network.SubnetInfo{{
+		CIDR:              "10.0.0.0/24",
+		ProviderId:        "subnet-0",
+		AvailabilityZones: []string{"az1", "az2"},
+	}, {
+		CIDR:       "10.0.1.0/24",
+		ProviderId: "subnet-1",
+	}, {
+		CIDR: "192.168.0.0/24",
+	}})
+	err := s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	s.waitForDrift(c, params.NetworkDrift{})
+}
+
+func (s *WorkerSuite) TestProviderErrorIsRetried(c *gc.C) {
+	s.networking.setError(errors.New("throttled"))
+	s.startWorker(c)
+
+	err := s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case drift := <-s.facade.drift:
+		c.Fatalf("unexpected network drift %v", drift)
+	case <-time.After(coretesting.ShortWait):
+	}
+
+	s.networking.setError(nil)
+	err = s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case <-s.facade.drift:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for network drift")
+	}
+}
+
+func (s *WorkerSuite) TestFacadeErrorStopsWorker(c *gc.C) {
+	s.facade.err = errors.New("boom")
+	w, err := networkreconciler.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	err = workertest.CheckKilled(c, w)
+	c.Assert(err, gc.ErrorMatches, "getting model subnets: boom")
+}
+
+type fakeFacade struct {
+	subnets []params.Subnet
+	err     error
+	drift   chan params.NetworkDrift
+}
+
+func (f *fakeFacade) ModelSubnets() ([]params.Subnet, error) {
+	return f.subnets, f.err
+}
+
+func (f *fakeFacade) SetNetworkDrift(drift params.NetworkDrift) error {
+	f.drift <- drift
+	return nil
+}
+
+type fakeNetworking struct {
+	environs.Networking
+
+	mu      sync.Mutex
+	subnets []network.SubnetInfo
+	spaces  []network.SpaceInfo
+	err     error
+}
+
+func (f *fakeNetworking) setSubnets(subnets []network.SubnetInfo) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.subnets = subnets
+}
+
+func (f *fakeNetworking) setError(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+func (f *fakeNetworking) SupportsSpaceDiscovery(context.ProviderCallContext) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.spaces) > 0, f.err
+}
+
+func (f *fakeNetworking) Spaces(context.ProviderCallContext) ([]network.SpaceInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.spaces, f.err
+}
+
+func (f *fakeNetworking) Subnets(context.ProviderCallContext, instance.Id, []network.Id) ([]network.SubnetInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.subnets, f.err
+}
+
+type fakeCredentialAPI struct{}
+
+func (fakeCredentialAPI) InvalidateModelCredential(string) error {
+	return nil
+}
@@ -18,6 +18,8 @@ import (
 	"gopkg.in/juju/worker.v1"
 	"gopkg.in/juju/worker.v1/catacomb"
 
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/api"
 	apiprovisioner "github.com/juju/juju/api/provisioner"
 	"github.com/juju/juju/apiserver/common/networkingcommon"
 	"github.com/juju/juju/apiserver/params"
@@ -37,6 +39,7 @@ import (
 	"github.com/juju/juju/environs/imagemetadata"
 	"github.com/juju/juju/environs/instances"
 	"github.com/juju/juju/environs/simplestreams"
+	"github.com/juju/juju/mongo"
 	providercommon "github.com/juju/juju/provider/common"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/multiwatcher"
@@ -340,7 +343,9 @@ func instanceIds(instances []instances.Instance) []string {
 func (task *provisionerTask) populateMachineMaps(ids []string) error {
 	task.instances = make(map[instance.Id]instances.Instance)
 
+	span := context.StartSpan("provider.AllRunningInstances")
 	instances, err := task.broker.AllRunningInstances(task.cloudCallCtx)
+	context.EndSpan(span, err)
 	if err != nil {
 		return errors.Annotate(err, "failed to get all instances from broker")
 	}
@@ -542,7 +547,10 @@ func (task *provisionerTask) stopInstances(instances []instances.Instance) error
 	for i, inst := range instances {
 		ids[i] = inst.Id()
 	}
-	if err := task.broker.StopInstances(task.cloudCallCtx, ids...); err != nil {
+	span := context.StartSpan("provider.StopInstances")
+	err := task.broker.StopInstances(task.cloudCallCtx, ids...)
+	context.EndSpan(span, err)
+	if err != nil {
 		return errors.Annotate(err, "broker failed to stop instances")
 	}
 	return nil
@@ -554,7 +562,16 @@ func (task *provisionerTask) constructInstanceConfig(
 	pInfo *params.ProvisioningInfo,
 ) (*instancecfg.InstanceConfig, error) {
 
-	stateInfo, apiInfo, err := auth.SetupAuthentication(machine)
+	var (
+		stateInfo *mongo.MongoInfo
+		apiInfo   *api.Info
+		err       error
+	)
+	if pInfo.InstanceIdentityEnrollment {
+		apiInfo, err = auth.SetupEnrollment(machine)
+	} else {
+		stateInfo, apiInfo, err = auth.SetupAuthentication(machine)
+	}
 	if err != nil {
 		return nil, errors.Annotate(err, "failed to setup authentication")
 	}
@@ -581,6 +598,11 @@ func (task *provisionerTask) constructInstanceConfig(
 	}
 
 	instanceConfig.Tags = pInfo.Tags
+	if pInfo.InstanceIdentityEnrollment {
+		instanceConfig.AgentEnvironment = map[string]string{
+			agent.InstanceIdentityEnrollment: "true",
+		}
+	}
 	if len(pInfo.Jobs) > 0 {
 		instanceConfig.Jobs = pInfo.Jobs
 	}
@@ -708,24 +730,41 @@ func (task *provisionerTask) constructStartInstanceParams(
 	}
 
 	startInstanceParams := environs.StartInstanceParams{
-		ControllerUUID:    controllerUUID,
-		Constraints:       provisioningInfo.Constraints,
-		Tools:             possibleTools,
-		InstanceConfig:    instanceConfig,
-		Placement:         provisioningInfo.Placement,
-		Volumes:           volumes,
-		VolumeAttachments: volumeAttachments,
-		SubnetsToZones:    subnetsToZones,
-		EndpointBindings:  endpointBindings,
-		ImageMetadata:     possibleImageMetadata,
-		StatusCallback:    machine.SetInstanceStatus,
-		Abort:             task.catacomb.Dying(),
-		CharmLXDProfiles:  provisioningInfo.CharmLXDProfiles,
+		ControllerUUID:     controllerUUID,
+		Constraints:        provisioningInfo.Constraints,
+		Tools:              possibleTools,
+		InstanceConfig:     instanceConfig,
+		Placement:          provisioningInfo.Placement,
+		Volumes:            volumes,
+		VolumeAttachments:  volumeAttachments,
+		SubnetsToZones:     subnetsToZones,
+		EndpointBindings:   endpointBindings,
+		ImageMetadata:      possibleImageMetadata,
+		StatusCallback:     machine.SetInstanceStatus,
+		Abort:              task.catacomb.Dying(),
+		CharmLXDProfiles:   provisioningInfo.CharmLXDProfiles,
+		PublishNetworkBoot: networkBootPublisher(machine),
 	}
 
 	return startInstanceParams, nil
 }
 
+// networkBootPublisher returns an environs.NetworkBootPublishFunc which
+// publishes the user-data of the machine through the controller.
+func networkBootPublisher(machine apiprovisioner.MachineProvisioner) environs.NetworkBootPublishFunc {
+	return func(userData []byte) (environs.NetworkBootSeed, error) {
+		seed, err := machine.PublishNetworkBootUserData(userData)
+		if err != nil {
+			return environs.NetworkBootSeed{}, errors.Trace(err)
+		}
+		return environs.NetworkBootSeed{
+			URL:     seed.URL,
+			CACert:  seed.CACert,
+			Expires: seed.Expires,
+		}, nil
+	}
+}
+
 func (task *provisionerTask) maintainMachines(machines []apiprovisioner.MachineProvisioner) error {
 	for _, m := range machines {
 		task.logger.Infof("maintainMachines: %v", m)
@@ -1110,7 +1149,9 @@ func (task *provisionerTask) startMachine(
 				machine, startInstanceParams.AvailabilityZone)
 		}
 
+		span := context.StartSpan("provider.StartInstance")
 		attemptResult, err := task.broker.StartInstance(task.cloudCallCtx, startInstanceParams)
+		context.EndSpan(span, err)
 		if err == nil {
 			result = attemptResult
 			break
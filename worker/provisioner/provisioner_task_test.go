@@ -21,6 +21,7 @@ import (
 	"gopkg.in/juju/names.v3"
 	"gopkg.in/juju/worker.v1/workertest"
 
+	"github.com/juju/juju/agent"
 	"github.com/juju/juju/api"
 	apiprovisioner "github.com/juju/juju/api/provisioner"
 	"github.com/juju/juju/apiserver/params"
@@ -203,6 +204,35 @@ func (s *ProvisionerTaskSuite) TestProvisionerRetries(c *gc.C) {
 	s.instanceBroker.CheckCallNames(c, "StartInstance", "StartInstance")
 }
 
+func (s *ProvisionerTaskSuite) TestProvisionerInstanceIdentityEnrollment(c *gc.C) {
+	s.instanceBroker.SetErrors(errors.New("no capacity"))
+
+	task := s.newProvisionerTaskWithRetry(c,
+		config.HarvestAll,
+		&mockDistributionGroupFinder{},
+		mockToolsFinder{},
+		provisioner.NewRetryStrategy(0*time.Second, 0),
+	)
+
+	m0 := &testMachine{
+		id:               "0",
+		enrollByIdentity: true,
+	}
+	s.machineStatusResults = []apiprovisioner.MachineStatusResult{
+		{Machine: m0, Status: params.StatusResult{}},
+	}
+	s.sendMachineErrorRetryChange(c)
+
+	s.waitForTask(c, []string{"StartInstance"})
+
+	workertest.CleanKill(c, task)
+	close(s.instanceBroker.callsChan)
+	s.auth.CheckCallNames(c, "SetupEnrollment")
+	s.instanceBroker.CheckCallNames(c, "StartInstance")
+	args := s.instanceBroker.Calls()[0].Args[1].(environs.StartInstanceParams)
+	c.Assert(args.InstanceConfig.AgentEnvironment[agent.InstanceIdentityEnrollment], gc.Equals, "true")
+}
+
 func (s *ProvisionerTaskSuite) TestZoneConstraintsNoZoneAvailable(c *gc.C) {
 	ctrl := gomock.NewController(c)
 	defer ctrl.Finish()
@@ -525,8 +555,9 @@ type testMachine struct {
 	instance     *testInstance
 	keepInstance bool
 
-	markForRemoval bool
-	constraints    string
+	markForRemoval   bool
+	constraints      string
+	enrollByIdentity bool
 
 	instStatusMsg string
 	modStatusMsg  string
@@ -621,6 +652,8 @@ func (m *testMachine) ProvisioningInfo() (*params.ProvisioningInfo, error) {
 		ControllerConfig: coretesting.FakeControllerConfig(),
 		Series:           series.DefaultSupportedLTS(),
 		Constraints:      constraints.MustParse(m.constraints),
+
+		InstanceIdentityEnrollment: m.enrollByIdentity,
 	}, nil
 }
 
@@ -635,6 +668,13 @@ func (m *testAuthenticationProvider) SetupAuthentication(
 	return nil, nil, nil
 }
 
+func (m *testAuthenticationProvider) SetupEnrollment(
+	machine authentication.TaggedPasswordChanger,
+) (*api.Info, error) {
+	m.AddCall("SetupEnrollment", machine)
+	return nil, nil
+}
+
 // startInstanceParamsMatcher is a GoMock matcher that applies a collection of
 // conditions to an environs.StartInstanceParams.
 // All conditions must be true in order for a positive match.
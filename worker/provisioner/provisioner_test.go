@@ -647,6 +647,44 @@ func (s *ProvisionerSuite) TestSetUpToStartMachine(c *gc.C) {
 	)
 }
 
+func (s *ProvisionerSuite) TestSetUpToStartMachinePublishNetworkBoot(c *gc.C) {
+	task := s.newProvisionerTask(
+		c,
+		config.HarvestAll,
+		s.Environ,
+		s.provisioner,
+		&mockDistributionGroupFinder{},
+		mockToolsFinder{},
+	)
+	defer workertest.CleanKill(c, task)
+
+	machine, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.provisioner.Machines(machine.MachineTag())
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, gc.HasLen, 1)
+	c.Assert(result[0].Err, gc.IsNil)
+	apiMachine := result[0].Machine
+
+	v, err := apiMachine.ModelAgentVersion()
+	c.Assert(err, jc.ErrorIsNil)
+
+	startInstanceParams, err := provisioner.SetupToStartMachine(task, apiMachine, v)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(startInstanceParams.PublishNetworkBoot, gc.NotNil)
+
+	seed, err := startInstanceParams.PublishNetworkBoot([]byte("#cloud-config\n"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(seed.URL, gc.Matches, `https://.*/network-boot/[[:xdigit:]]+`)
+	c.Assert(seed.KernelParameter(), gc.Equals, "ds=nocloud-net;s="+seed.URL+"/")
+
+	token := seed.URL[strings.LastIndex(seed.URL, "/")+1:]
+	data, err := s.State.ConsumeNetworkBootUserData(token)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data.MachineId, gc.Equals, machine.Id())
+}
+
 func (s *ProvisionerSuite) TestProvisionerSetsErrorStatusWhenNoToolsAreAvailable(c *gc.C) {
 	p := s.newEnvironProvisioner(c)
 	defer workertest.CleanKill(c, p)
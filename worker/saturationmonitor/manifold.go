@@ -0,0 +1,172 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package saturationmonitor
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/pubsub"
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/juju/names.v3"
+	"gopkg.in/juju/worker.v1"
+	"gopkg.in/juju/worker.v1/dependency"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/state"
+	workerstate "github.com/juju/juju/worker/state"
+)
+
+// ManifoldConfig holds the information necessary to run a saturation
+// monitor in a dependency.Engine.
+type ManifoldConfig struct {
+	AgentName string
+	ClockName string
+	HubName   string
+	StateName string
+
+	// Interval is how often the thresholds are evaluated.
+	Interval time.Duration
+
+	// WebhookTimeout is how long the worker waits for the alert
+	// webhook to respond.
+	WebhookTimeout time.Duration
+
+	PrometheusGatherer prometheus.Gatherer
+	NewWorker          func(Config) (worker.Worker, error)
+}
+
+// Validate is called by start to check for bad configuration.
+func (config ManifoldConfig) Validate() error {
+	if config.AgentName == "" {
+		return errors.NotValidf("empty AgentName")
+	}
+	if config.ClockName == "" {
+		return errors.NotValidf("empty ClockName")
+	}
+	if config.HubName == "" {
+		return errors.NotValidf("empty HubName")
+	}
+	if config.StateName == "" {
+		return errors.NotValidf("empty StateName")
+	}
+	if config.Interval <= 0 {
+		return errors.NotValidf("non-positive Interval")
+	}
+	if config.WebhookTimeout <= 0 {
+		return errors.NotValidf("non-positive WebhookTimeout")
+	}
+	if config.PrometheusGatherer == nil {
+		return errors.NotValidf("nil PrometheusGatherer")
+	}
+	if config.NewWorker == nil {
+		return errors.NotValidf("nil NewWorker")
+	}
+	return nil
+}
+
+// Manifold returns a dependency.Manifold that will run a saturation
+// monitor.
+func Manifold(config ManifoldConfig) dependency.Manifold {
+	return dependency.Manifold{
+		Inputs: []string{
+			config.AgentName,
+			config.ClockName,
+			config.HubName,
+			config.StateName,
+		},
+		Start: config.start,
+	}
+}
+
+// start is a method on ManifoldConfig because it's more readable than a closure.
+func (config ManifoldConfig) start(context dependency.Context) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var agent agent.Agent
+	if err := context.Get(config.AgentName, &agent); err != nil {
+		return nil, errors.Trace(err)
+	}
+	machineTag, ok := agent.CurrentConfig().Tag().(names.MachineTag)
+	if !ok {
+		return nil, errors.New("saturation monitor must run on a controller machine")
+	}
+
+	var clock clock.Clock
+	if err := context.Get(config.ClockName, &clock); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var hub *pubsub.StructuredHub
+	if err := context.Get(config.HubName, &hub); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var stTracker workerstate.StateTracker
+	if err := context.Get(config.StateName, &stTracker); err != nil {
+		return nil, errors.Trace(err)
+	}
+	statePool, err := stTracker.Use()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	w, err := config.NewWorker(Config{
+		MachineId:  machineTag.Id(),
+		Backend:    stateBackend{statePool},
+		Gatherer:   config.PrometheusGatherer,
+		Hub:        hub,
+		HTTPClient: &http.Client{Timeout: config.WebhookTimeout},
+		Clock:      clock,
+		Interval:   config.Interval,
+	})
+	if err != nil {
+		stTracker.Done()
+		return nil, errors.Trace(err)
+	}
+	go func() {
+		w.Wait()
+		stTracker.Done()
+	}()
+	return w, nil
+}
+
+// stateBackend implements Backend using a state pool.
+type stateBackend struct {
+	pool *state.StatePool
+}
+
+// ControllerConfig is part of the Backend interface.
+func (b stateBackend) ControllerConfig() (controller.Config, error) {
+	return b.pool.SystemState().ControllerConfig()
+}
+
+// TxnWatcherDeliveryLag is part of the Backend interface.
+func (b stateBackend) TxnWatcherDeliveryLag(abort <-chan struct{}) (time.Duration, error) {
+	return b.pool.TxnWatcherDeliveryLag(abort)
+}
+
+// MachineStatus is part of the Backend interface.
+func (b stateBackend) MachineStatus(id string) (status.StatusInfo, error) {
+	m, err := b.pool.SystemState().Machine(id)
+	if err != nil {
+		return status.StatusInfo{}, errors.Trace(err)
+	}
+	return m.Status()
+}
+
+// SetMachineStatus is part of the Backend interface.
+func (b stateBackend) SetMachineStatus(id string, info status.StatusInfo) error {
+	m, err := b.pool.SystemState().Machine(id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return m.SetStatus(info)
+}
@@ -0,0 +1,174 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package saturationmonitor_test
+
+import (
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
+	"github.com/juju/pubsub"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/prometheus/client_golang/prometheus"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+	"gopkg.in/juju/worker.v1"
+	"gopkg.in/juju/worker.v1/dependency"
+	dt "gopkg.in/juju/worker.v1/dependency/testing"
+	"gopkg.in/juju/worker.v1/workertest"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/saturationmonitor"
+)
+
+type ManifoldSuite struct {
+	testing.IsolationSuite
+
+	agent        *mockAgent
+	clock        *testclock.Clock
+	hub          *pubsub.StructuredHub
+	stateTracker stubStateTracker
+	stub         testing.Stub
+	manifold     dependency.Manifold
+}
+
+var _ = gc.Suite(&ManifoldSuite{})
+
+func (s *ManifoldSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.agent = &mockAgent{conf: mockAgentConfig{tag: names.NewMachineTag("0")}}
+	s.clock = testclock.NewClock(time.Time{})
+	s.hub = pubsub.NewStructuredHub(nil)
+	s.stateTracker = stubStateTracker{}
+	s.stub.ResetCalls()
+
+	s.manifold = saturationmonitor.Manifold(saturationmonitor.ManifoldConfig{
+		AgentName:          "agent",
+		ClockName:          "clock",
+		HubName:            "hub",
+		StateName:          "state",
+		Interval:           time.Minute,
+		WebhookTimeout:     time.Second,
+		PrometheusGatherer: prometheus.NewRegistry(),
+		NewWorker:          s.newWorker,
+	})
+}
+
+func (s *ManifoldSuite) newContext(overlay map[string]interface{}) dependency.Context {
+	resources := map[string]interface{}{
+		"agent": s.agent,
+		"clock": s.clock,
+		"hub":   s.hub,
+		"state": &s.stateTracker,
+	}
+	for k, v := range overlay {
+		resources[k] = v
+	}
+	return dt.StubContext(nil, resources)
+}
+
+func (s *ManifoldSuite) newWorker(config saturationmonitor.Config) (worker.Worker, error) {
+	s.stub.MethodCall(s, "NewWorker", config)
+	if err := s.stub.NextErr(); err != nil {
+		return nil, err
+	}
+	return workertest.NewErrorWorker(nil), nil
+}
+
+var expectedInputs = []string{"agent", "clock", "hub", "state"}
+
+func (s *ManifoldSuite) TestInputs(c *gc.C) {
+	c.Assert(s.manifold.Inputs, jc.SameContents, expectedInputs)
+}
+
+func (s *ManifoldSuite) TestMissingInputs(c *gc.C) {
+	for _, input := range expectedInputs {
+		context := s.newContext(map[string]interface{}{
+			input: dependency.ErrMissing,
+		})
+		_, err := s.manifold.Start(context)
+		c.Assert(errors.Cause(err), gc.Equals, dependency.ErrMissing)
+	}
+}
+
+func (s *ManifoldSuite) TestStartMachineAgent(c *gc.C) {
+	w, err := s.manifold.Start(s.newContext(nil))
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	s.stub.CheckCallNames(c, "NewWorker")
+	config := s.stub.Calls()[0].Args[0].(saturationmonitor.Config)
+	c.Check(config.MachineId, gc.Equals, "0")
+	c.Check(config.Clock, gc.Equals, s.clock)
+	c.Check(config.Hub, gc.Equals, s.hub)
+	c.Check(config.Interval, gc.Equals, time.Minute)
+	s.stateTracker.CheckCallNames(c, "Use")
+}
+
+func (s *ManifoldSuite) TestStartNonMachineAgent(c *gc.C) {
+	s.agent.conf.tag = names.NewUnitTag("app/0")
+	_, err := s.manifold.Start(s.newContext(nil))
+	c.Assert(err, gc.ErrorMatches, "saturation monitor must run on a controller machine")
+	s.stub.CheckNoCalls(c)
+}
+
+func (s *ManifoldSuite) TestStopWorkerReleasesState(c *gc.C) {
+	w, err := s.manifold.Start(s.newContext(nil))
+	c.Assert(err, jc.ErrorIsNil)
+	workertest.CleanKill(c, w)
+
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		if len(s.stateTracker.Calls()) == 2 {
+			break
+		}
+	}
+	s.stateTracker.CheckCallNames(c, "Use", "Done")
+}
+
+func (s *ManifoldSuite) TestReleasesStateOnWorkerError(c *gc.C) {
+	s.stub.SetErrors(errors.New("splat"))
+	_, err := s.manifold.Start(s.newContext(nil))
+	c.Assert(err, gc.ErrorMatches, "splat")
+	s.stateTracker.CheckCallNames(c, "Use", "Done")
+}
+
+type mockAgent struct {
+	agent.Agent
+	conf mockAgentConfig
+}
+
+func (ma *mockAgent) CurrentConfig() agent.Config {
+	return &ma.conf
+}
+
+type mockAgentConfig struct {
+	agent.Config
+	tag names.Tag
+}
+
+func (c *mockAgentConfig) Tag() names.Tag {
+	return c.tag
+}
+
+type stubStateTracker struct {
+	testing.Stub
+}
+
+func (s *stubStateTracker) Use() (*state.StatePool, error) {
+	s.MethodCall(s, "Use")
+	return nil, s.NextErr()
+}
+
+func (s *stubStateTracker) Done() error {
+	s.MethodCall(s, "Done")
+	return s.NextErr()
+}
+
+func (s *stubStateTracker) Report() map[string]interface{} {
+	s.MethodCall(s, "Report")
+	return nil
+}
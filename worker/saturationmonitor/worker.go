@@ -0,0 +1,419 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package saturationmonitor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"gopkg.in/juju/worker.v1"
+	"gopkg.in/juju/worker.v1/catacomb"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/core/raftlease"
+	"github.com/juju/juju/core/status"
+	controllermsg "github.com/juju/juju/pubsub/controller"
+)
+
+var logger = loggo.GetLogger("juju.worker.saturationmonitor")
+
+const (
+	// leaseRequestMetric is the summary of lease store request times,
+	// in milliseconds, labelled by operation and result.
+	leaseRequestMetric = "juju_raftlease_request"
+
+	// txnOpsMetric is the counter of mgo/txn operations, labelled by
+	// whether they failed.
+	txnOpsMetric = "juju_mgo_txn_ops_total"
+
+	// leaseClaimQuantile is the quantile of lease claim times that is
+	// compared against the threshold.
+	leaseClaimQuantile = 0.99
+
+	// statusDataKey is the key under which the raised alerts are
+	// recorded in the controller machine's status data.
+	statusDataKey = "saturation-alerts"
+
+	// statusMessagePrefix starts the controller machine's status
+	// message while alerts are raised.
+	statusMessagePrefix = "controller saturated: "
+)
+
+// Backend exposes the state functionality needed by the worker.
+type Backend interface {
+	// ControllerConfig returns the controller config, which holds the
+	// alert thresholds.
+	ControllerConfig() (controller.Config, error)
+
+	// TxnWatcherDeliveryLag returns how far behind the transactions it
+	// delivered the txn watcher was when it last synced.
+	TxnWatcherDeliveryLag(abort <-chan struct{}) (time.Duration, error)
+
+	// MachineStatus returns the status of the identified machine.
+	MachineStatus(id string) (status.StatusInfo, error)
+
+	// SetMachineStatus sets the status of the identified machine.
+	SetMachineStatus(id string, info status.StatusInfo) error
+}
+
+// Hub is the pubsub hub on which alert changes are published.
+type Hub interface {
+	Publish(topic string, data interface{}) (<-chan struct{}, error)
+}
+
+// HTTPClient is used to post alert changes to the configured webhook.
+type HTTPClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// Config defines the parameters of the saturation monitor.
+type Config struct {
+	// MachineId identifies the controller machine being monitored.
+	MachineId string
+
+	Backend    Backend
+	Gatherer   prometheus.Gatherer
+	Hub        Hub
+	HTTPClient HTTPClient
+	Clock      clock.Clock
+
+	// Interval is how often the thresholds are evaluated.
+	Interval time.Duration
+}
+
+// Validate returns an error if Config cannot drive a saturation monitor.
+func (config Config) Validate() error {
+	if config.MachineId == "" {
+		return errors.NotValidf("empty MachineId")
+	}
+	if config.Backend == nil {
+		return errors.NotValidf("nil Backend")
+	}
+	if config.Gatherer == nil {
+		return errors.NotValidf("nil Gatherer")
+	}
+	if config.Hub == nil {
+		return errors.NotValidf("nil Hub")
+	}
+	if config.HTTPClient == nil {
+		return errors.NotValidf("nil HTTPClient")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if config.Interval <= 0 {
+		return errors.NotValidf("non-positive Interval")
+	}
+	return nil
+}
+
+// Payload is the JSON body posted to the saturation alert webhook.
+type Payload struct {
+	// Machine identifies the controller machine.
+	Machine string `json:"machine"`
+
+	// Alerts holds the alerts currently raised; it is empty when the
+	// controller has recovered.
+	Alerts []string `json:"alerts"`
+}
+
+// New returns a worker that periodically compares the lease claim
+// latency, txn retry rate and watcher delivery lag of the controller
+// with the thresholds in controller config. While any are exceeded,
+// the controller machine's status message says so; whenever the set
+// of exceeded thresholds changes, an event is published on the hub and
+// posted to the configured webhook.
+func New(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &saturationMonitor{config: config}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+type saturationMonitor struct {
+	catacomb catacomb.Catacomb
+	config   Config
+
+	// alerts holds the alerts raised by the last change in the
+	// exceeded thresholds.
+	alerts []alert
+
+	// txnSampled records whether txnTotal and txnFailed hold the
+	// counts seen by a previous check.
+	txnSampled bool
+	txnTotal   float64
+	txnFailed  float64
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *saturationMonitor) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *saturationMonitor) Wait() error {
+	return w.catacomb.Wait()
+}
+
+func (w *saturationMonitor) loop() error {
+	first := true
+	timer := w.config.Clock.NewTimer(w.config.Interval)
+	defer timer.Stop()
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case <-timer.Chan():
+			if err := w.check(first); err != nil {
+				return errors.Trace(err)
+			}
+			first = false
+			timer.Reset(w.config.Interval)
+		}
+	}
+}
+
+// sample holds the measurements compared against the thresholds.
+type sample struct {
+	leaseClaimLatency time.Duration
+	txnRetryRate      float64
+	watcherLag        time.Duration
+}
+
+// check evaluates the thresholds and reports any change in which of
+// them are exceeded. The first check always updates the machine status,
+// so that a message left by a previous run is cleared.
+func (w *saturationMonitor) check(first bool) error {
+	cfg, err := w.config.Backend.ControllerConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	s, err := w.sample()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	alerts := evaluate(cfg, s)
+	changed := !sameThresholds(alerts, w.alerts)
+	if !first && !changed {
+		return nil
+	}
+	text := messages(alerts)
+	if err := w.setStatus(text); err != nil {
+		return errors.Trace(err)
+	}
+	if changed {
+		if len(alerts) > 0 {
+			logger.Warningf("%s%s", statusMessagePrefix, strings.Join(text, "; "))
+		} else {
+			logger.Infof("controller no longer saturated")
+		}
+		w.notify(cfg.SaturationAlertWebhook(), text)
+	}
+	w.alerts = alerts
+	return nil
+}
+
+// sample measures the controller's current lease claim latency, txn
+// retry rate and watcher delivery lag.
+func (w *saturationMonitor) sample() (sample, error) {
+	var s sample
+	lag, err := w.config.Backend.TxnWatcherDeliveryLag(w.catacomb.Dying())
+	if err != nil {
+		return s, errors.Trace(err)
+	}
+	s.watcherLag = lag
+
+	families, err := w.config.Gatherer.Gather()
+	if err != nil {
+		return s, errors.Trace(err)
+	}
+	var txnTotal, txnFailed float64
+	for _, family := range families {
+		switch family.GetName() {
+		case leaseRequestMetric:
+			for _, m := range family.GetMetric() {
+				if labelValue(m.GetLabel(), "operation") != raftlease.OperationClaim {
+					continue
+				}
+				for _, q := range m.GetSummary().GetQuantile() {
+					if q.GetQuantile() != leaseClaimQuantile || math.IsNaN(q.GetValue()) {
+						continue
+					}
+					latency := time.Duration(q.GetValue() * float64(time.Millisecond))
+					if latency > s.leaseClaimLatency {
+						s.leaseClaimLatency = latency
+					}
+				}
+			}
+		case txnOpsMetric:
+			for _, m := range family.GetMetric() {
+				value := m.GetCounter().GetValue()
+				txnTotal += value
+				if labelValue(m.GetLabel(), "failed") != "" {
+					txnFailed += value
+				}
+			}
+		}
+	}
+	// The txn counters only ever grow, so the retry rate is measured
+	// over the operations run since the previous check.
+	if w.txnSampled && txnTotal > w.txnTotal {
+		s.txnRetryRate = (txnFailed - w.txnFailed) / (txnTotal - w.txnTotal)
+	}
+	w.txnSampled = true
+	w.txnTotal, w.txnFailed = txnTotal, txnFailed
+	return s, nil
+}
+
+// alert describes an exceeded threshold.
+type alert struct {
+	// threshold names the controller config key of the threshold.
+	threshold string
+
+	// message describes the measurement that exceeded it.
+	message string
+}
+
+// evaluate returns an alert for each threshold in cfg that is exceeded
+// by the sample.
+func evaluate(cfg controller.Config, s sample) []alert {
+	var alerts []alert
+	if limit := cfg.SaturationLeaseClaimLatency(); limit > 0 && s.leaseClaimLatency > limit {
+		alerts = append(alerts, alert{controller.SaturationLeaseClaimLatency, fmt.Sprintf(
+			"lease claim latency %v exceeds %v", s.leaseClaimLatency.Round(time.Millisecond), limit)})
+	}
+	if limit := cfg.SaturationTxnRetryRate(); limit > 0 && s.txnRetryRate > limit {
+		alerts = append(alerts, alert{controller.SaturationTxnRetryRate, fmt.Sprintf(
+			"txn retry rate %.2f exceeds %.2f", s.txnRetryRate, limit)})
+	}
+	if limit := cfg.SaturationWatcherLag(); limit > 0 && s.watcherLag > limit {
+		alerts = append(alerts, alert{controller.SaturationWatcherLag, fmt.Sprintf(
+			"watcher delivery lag %v exceeds %v", s.watcherLag.Round(time.Second), limit)})
+	}
+	return alerts
+}
+
+// sameThresholds reports whether a and b were raised for the same
+// thresholds, regardless of the measurements that exceeded them.
+func sameThresholds(a, b []alert) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].threshold != b[i].threshold {
+			return false
+		}
+	}
+	return true
+}
+
+func messages(alerts []alert) []string {
+	result := make([]string, len(alerts))
+	for i, a := range alerts {
+		result[i] = a.message
+	}
+	return result
+}
+
+func labelValue(labels []*dto.LabelPair, name string) string {
+	for _, label := range labels {
+		if label.GetName() == name {
+			return label.GetValue()
+		}
+	}
+	return ""
+}
+
+// setStatus records the alerts in the message of the controller
+// machine's status. The status itself is left alone, and a message
+// not set by this worker is only replaced while alerts are raised.
+func (w *saturationMonitor) setStatus(alerts []string) error {
+	current, err := w.config.Backend.MachineStatus(w.config.MachineId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, ours := current.Data[statusDataKey]
+	if len(alerts) == 0 && !ours {
+		return nil
+	}
+	info := status.StatusInfo{
+		Status: current.Status,
+		Data:   make(map[string]interface{}),
+	}
+	for k, v := range current.Data {
+		if k != statusDataKey {
+			info.Data[k] = v
+		}
+	}
+	if len(alerts) > 0 {
+		info.Message = statusMessagePrefix + strings.Join(alerts, "; ")
+		info.Data[statusDataKey] = alerts
+	}
+	now := w.config.Clock.Now()
+	info.Since = &now
+	return errors.Trace(w.config.Backend.SetMachineStatus(w.config.MachineId, info))
+}
+
+// notify publishes the alerts on the hub and posts them to the webhook,
+// if one is configured. Failures are logged rather than returned, so
+// that an unreachable webhook does not stop the monitoring.
+func (w *saturationMonitor) notify(webhook string, alerts []string) {
+	if _, err := w.config.Hub.Publish(controllermsg.SaturationAlertsChanged, controllermsg.SaturationAlertsMessage{
+		MachineId: w.config.MachineId,
+		Alerts:    alerts,
+	}); err != nil {
+		logger.Errorf("cannot publish saturation alerts: %v", err)
+	}
+	if webhook == "" {
+		return
+	}
+	if err := w.post(webhook, alerts); err != nil {
+		logger.Errorf("cannot post saturation alerts to %q: %v", webhook, err)
+	}
+}
+
+func (w *saturationMonitor) post(webhook string, alerts []string) error {
+	data, err := json.Marshal(Payload{
+		Machine: w.config.MachineId,
+		Alerts:  alerts,
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	req, err := http.NewRequest("POST", webhook, bytes.NewReader(data))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := w.config.HTTPClient.Do(req)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,343 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package saturationmonitor_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/prometheus/client_golang/prometheus"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/worker.v1/workertest"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/core/status"
+	controllermsg "github.com/juju/juju/pubsub/controller"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/saturationmonitor"
+)
+
+type WorkerSuite struct {
+	testing.IsolationSuite
+
+	backend    *stubBackend
+	hub        *stubHub
+	httpClient *stubHTTPClient
+	clock      *testclock.Clock
+	leaseTimes *prometheus.SummaryVec
+	txnOps     *prometheus.CounterVec
+	config     saturationmonitor.Config
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.backend = &stubBackend{
+		config: controller.Config{
+			controller.SaturationLeaseClaimLatency: time.Second,
+			controller.SaturationTxnRetryRate:      0.2,
+			controller.SaturationWatcherLag:        30 * time.Second,
+			controller.SaturationAlertWebhook:      "https://alerts.example.com/juju",
+		},
+		status: status.StatusInfo{Status: status.Started},
+		set:    make(chan status.StatusInfo, 10),
+	}
+	s.hub = &stubHub{published: make(chan controllermsg.SaturationAlertsMessage, 10)}
+	s.httpClient = &stubHTTPClient{posted: make(chan saturationmonitor.Payload, 10)}
+	s.clock = testclock.NewClock(time.Time{})
+
+	registry := prometheus.NewPedanticRegistry()
+	s.leaseTimes = prometheus.NewSummaryVec(prometheus.SummaryOpts{
+		Namespace:  "juju_raftlease",
+		Name:       "request",
+		Help:       "Request times for lease store operations in ms",
+		Objectives: map[float64]float64{0.99: 0.001},
+	}, []string{"operation", "result"})
+	s.txnOps = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "juju",
+		Name:      "mgo_txn_ops_total",
+		Help:      "Total number of mgo/txn ops executed.",
+	}, []string{"failed"})
+	registry.MustRegister(s.leaseTimes, s.txnOps)
+
+	s.config = saturationmonitor.Config{
+		MachineId:  "0",
+		Backend:    s.backend,
+		Gatherer:   registry,
+		Hub:        s.hub,
+		HTTPClient: s.httpClient,
+		Clock:      s.clock,
+		Interval:   time.Minute,
+	}
+}
+
+func (s *WorkerSuite) TestValidate(c *gc.C) {
+	s.testValidate(c, func(config *saturationmonitor.Config) { config.MachineId = "" }, "empty MachineId not valid")
+	s.testValidate(c, func(config *saturationmonitor.Config) { config.Backend = nil }, "nil Backend not valid")
+	s.testValidate(c, func(config *saturationmonitor.Config) { config.Gatherer = nil }, "nil Gatherer not valid")
+	s.testValidate(c, func(config *saturationmonitor.Config) { config.Hub = nil }, "nil Hub not valid")
+	s.testValidate(c, func(config *saturationmonitor.Config) { config.HTTPClient = nil }, "nil HTTPClient not valid")
+	s.testValidate(c, func(config *saturationmonitor.Config) { config.Clock = nil }, "nil Clock not valid")
+	s.testValidate(c, func(config *saturationmonitor.Config) { config.Interval = 0 }, "non-positive Interval not valid")
+}
+
+func (s *WorkerSuite) testValidate(c *gc.C, f func(*saturationmonitor.Config), expect string) {
+	config := s.config
+	f(&config)
+	w, err := saturationmonitor.New(config)
+	c.Check(w, gc.IsNil)
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+	c.Check(err, gc.ErrorMatches, expect)
+}
+
+func (s *WorkerSuite) TestRaisesAndClearsAlerts(c *gc.C) {
+	s.backend.setLag(time.Minute)
+	s.leaseTimes.WithLabelValues("claim", "success").Observe(2000)
+	s.leaseTimes.WithLabelValues("extend", "success").Observe(5000)
+
+	w, err := saturationmonitor.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	s.advance(c)
+	alerts := []string{
+		"lease claim latency 2s exceeds 1s",
+		"watcher delivery lag 1m0s exceeds 30s",
+	}
+	s.assertPublished(c, alerts)
+	s.assertPosted(c, alerts)
+	info := s.assertStatusSet(c)
+	c.Check(info.Status, gc.Equals, status.Started)
+	c.Check(info.Message, gc.Equals, "controller saturated: "+strings.Join(alerts, "; "))
+	c.Check(info.Data, jc.DeepEquals, map[string]interface{}{"saturation-alerts": alerts})
+
+	// Raising the threshold clears the lease alert.
+	s.backend.setConfig(controller.SaturationLeaseClaimLatency, 5*time.Second)
+	s.advance(c)
+	alerts = alerts[1:]
+	s.assertPublished(c, alerts)
+	s.assertPosted(c, alerts)
+	info = s.assertStatusSet(c)
+	c.Check(info.Message, gc.Equals, "controller saturated: "+alerts[0])
+
+	s.backend.setLag(0)
+	s.advance(c)
+	s.assertPublished(c, []string{})
+	s.assertPosted(c, []string{})
+	info = s.assertStatusSet(c)
+	c.Check(info.Status, gc.Equals, status.Started)
+	c.Check(info.Message, gc.Equals, "")
+	c.Check(info.Data, gc.HasLen, 0)
+}
+
+func (s *WorkerSuite) TestUnchangedThresholdsNotReported(c *gc.C) {
+	s.backend.setLag(time.Minute)
+	w, err := saturationmonitor.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	s.advance(c)
+	s.assertPublished(c, []string{"watcher delivery lag 1m0s exceeds 30s"})
+	s.assertStatusSet(c)
+
+	s.backend.setLag(2 * time.Minute)
+	s.advance(c)
+	s.waitIdle(c)
+	select {
+	case msg := <-s.hub.published:
+		c.Fatalf("unexpected publish %#v", msg)
+	case info := <-s.backend.set:
+		c.Fatalf("unexpected status %#v", info)
+	default:
+	}
+}
+
+func (s *WorkerSuite) TestTxnRetryRate(c *gc.C) {
+	s.txnOps.WithLabelValues("").Add(100)
+	s.txnOps.WithLabelValues("failed").Add(100)
+
+	w, err := saturationmonitor.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	// The first check only records the counts.
+	s.advance(c)
+
+	s.txnOps.WithLabelValues("").Add(6)
+	s.txnOps.WithLabelValues("failed").Add(4)
+	s.advance(c)
+	s.assertPublished(c, []string{"txn retry rate 0.40 exceeds 0.20"})
+}
+
+func (s *WorkerSuite) TestLeavesOtherStatusMessage(c *gc.C) {
+	s.backend.status.Message = "something else"
+
+	w, err := saturationmonitor.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	s.advance(c)
+	s.waitIdle(c)
+	select {
+	case info := <-s.backend.set:
+		c.Fatalf("unexpected status %#v", info)
+	default:
+	}
+}
+
+func (s *WorkerSuite) TestClearsStaleAlertsOnStart(c *gc.C) {
+	s.backend.status = status.StatusInfo{
+		Status:  status.Started,
+		Message: "controller saturated: watcher delivery lag 1m0s exceeds 30s",
+		Data:    map[string]interface{}{"saturation-alerts": []string{"watcher delivery lag 1m0s exceeds 30s"}},
+	}
+
+	w, err := saturationmonitor.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	s.advance(c)
+	info := s.assertStatusSet(c)
+	c.Check(info.Message, gc.Equals, "")
+	c.Check(info.Data, gc.HasLen, 0)
+}
+
+func (s *WorkerSuite) advance(c *gc.C) {
+	c.Assert(s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1), jc.ErrorIsNil)
+}
+
+// waitIdle waits for the worker to finish its current check.
+func (s *WorkerSuite) waitIdle(c *gc.C) {
+	c.Assert(s.clock.WaitAdvance(0, coretesting.LongWait, 1), jc.ErrorIsNil)
+}
+
+func (s *WorkerSuite) assertPublished(c *gc.C, alerts []string) {
+	select {
+	case msg := <-s.hub.published:
+		c.Assert(msg, jc.DeepEquals, controllermsg.SaturationAlertsMessage{
+			MachineId: "0",
+			Alerts:    alerts,
+		})
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for alerts to be published")
+	}
+}
+
+func (s *WorkerSuite) assertPosted(c *gc.C, alerts []string) {
+	select {
+	case payload := <-s.httpClient.posted:
+		c.Assert(payload, jc.DeepEquals, saturationmonitor.Payload{
+			Machine: "0",
+			Alerts:  alerts,
+		})
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for alerts to be posted")
+	}
+}
+
+func (s *WorkerSuite) assertStatusSet(c *gc.C) status.StatusInfo {
+	select {
+	case info := <-s.backend.set:
+		return info
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for status to be set")
+	}
+	panic("unreachable")
+}
+
+type stubBackend struct {
+	mu     sync.Mutex
+	config controller.Config
+	lag    time.Duration
+	status status.StatusInfo
+	set    chan status.StatusInfo
+}
+
+func (b *stubBackend) setConfig(key string, value interface{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.config[key] = value
+}
+
+func (b *stubBackend) setLag(lag time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.lag = lag
+}
+
+func (b *stubBackend) ControllerConfig() (controller.Config, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	cfg := make(controller.Config)
+	for k, v := range b.config {
+		cfg[k] = v
+	}
+	return cfg, nil
+}
+
+func (b *stubBackend) TxnWatcherDeliveryLag(abort <-chan struct{}) (time.Duration, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.lag, nil
+}
+
+func (b *stubBackend) MachineStatus(id string) (status.StatusInfo, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if id != "0" {
+		return status.StatusInfo{}, errors.NotFoundf("machine %q", id)
+	}
+	return b.status, nil
+}
+
+func (b *stubBackend) SetMachineStatus(id string, info status.StatusInfo) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if id != "0" {
+		return errors.NotFoundf("machine %q", id)
+	}
+	b.status = info
+	b.set <- info
+	return nil
+}
+
+type stubHub struct {
+	published chan controllermsg.SaturationAlertsMessage
+}
+
+func (h *stubHub) Publish(topic string, data interface{}) (<-chan struct{}, error) {
+	if topic != controllermsg.SaturationAlertsChanged {
+		return nil, errors.Errorf("unexpected topic %q", topic)
+	}
+	h.published <- data.(controllermsg.SaturationAlertsMessage)
+	done := make(chan struct{})
+	close(done)
+	return done, nil
+}
+
+type stubHTTPClient struct {
+	posted chan saturationmonitor.Payload
+}
+
+func (h *stubHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	var payload saturationmonitor.Payload
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+		return nil, errors.Trace(err)
+	}
+	h.posted <- payload
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Body:       ioutil.NopCloser(strings.NewReader("")),
+	}, nil
+}
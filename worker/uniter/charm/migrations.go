@@ -0,0 +1,75 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/juju/charm.v6"
+)
+
+// MigrationActionPrefix is the prefix of the charm actions that migrate
+// workload data across a revision boundary. An action named
+// "migrate-<revision>" is run automatically by the uniter, between the
+// upgrade-charm and config-changed hooks, whenever the unit is upgraded
+// from a revision below <revision> to one at or above it.
+const MigrationActionPrefix = "migrate-"
+
+// MigrationRevision returns the revision boundary guarded by the named
+// migration action, and whether the name identifies a migration action
+// at all.
+func MigrationRevision(actionName string) (int, bool) {
+	if !strings.HasPrefix(actionName, MigrationActionPrefix) {
+		return 0, false
+	}
+	rev, err := strconv.Atoi(strings.TrimPrefix(actionName, MigrationActionPrefix))
+	if err != nil || rev < 0 {
+		return 0, false
+	}
+	return rev, true
+}
+
+// MigrationActions returns the names of the migration actions declared in
+// the supplied actions that must be run when upgrading from revision from
+// to revision to, in the order in which they must be run.
+func MigrationActions(actions *charm.Actions, from, to int) []string {
+	if actions == nil || from >= to {
+		return nil
+	}
+	type migration struct {
+		name string
+		rev  int
+	}
+	var migrations []migration
+	for name := range actions.ActionSpecs {
+		rev, ok := MigrationRevision(name)
+		if !ok || rev <= from || rev > to {
+			continue
+		}
+		migrations = append(migrations, migration{name, rev})
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		if migrations[i].rev != migrations[j].rev {
+			return migrations[i].rev < migrations[j].rev
+		}
+		return migrations[i].name < migrations[j].name
+	})
+	names := make([]string, len(migrations))
+	for i, m := range migrations {
+		names[i] = m.name
+	}
+	return names
+}
+
+// IsMigrationUpgrade returns whether an upgrade from one charm URL to
+// another should run migration actions: that is, whether both URLs
+// identify revisions of the same charm, and the upgrade moves forward.
+func IsMigrationUpgrade(from, to *charm.URL) bool {
+	if from == nil || to == nil {
+		return false
+	}
+	return *from.WithRevision(-1) == *to.WithRevision(-1) && from.Revision < to.Revision
+}
@@ -0,0 +1,75 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charm_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	corecharm "gopkg.in/juju/charm.v6"
+
+	"github.com/juju/juju/worker/uniter/charm"
+)
+
+type MigrationsSuite struct{}
+
+var _ = gc.Suite(&MigrationsSuite{})
+
+func (s *MigrationsSuite) TestMigrationRevision(c *gc.C) {
+	for i, t := range []struct {
+		name string
+		rev  int
+		ok   bool
+	}{
+		{"migrate-12", 12, true},
+		{"migrate-0", 0, true},
+		{"migrate-", 0, false},
+		{"migrate-latest", 0, false},
+		{"migrate--1", 0, false},
+		{"backup", 0, false},
+	} {
+		c.Logf("test %d: %s", i, t.name)
+		rev, ok := charm.MigrationRevision(t.name)
+		c.Check(ok, gc.Equals, t.ok)
+		c.Check(rev, gc.Equals, t.rev)
+	}
+}
+
+func (s *MigrationsSuite) TestMigrationActions(c *gc.C) {
+	actions := &corecharm.Actions{
+		ActionSpecs: map[string]corecharm.ActionSpec{
+			"migrate-10": {},
+			"migrate-5":  {},
+			"migrate-20": {},
+			"migrate-x":  {},
+			"backup":     {},
+		},
+	}
+	for i, t := range []struct {
+		from, to int
+		expect   []string
+	}{
+		{1, 4, []string{}},
+		{1, 5, []string{"migrate-5"}},
+		{5, 10, []string{"migrate-10"}},
+		{4, 30, []string{"migrate-5", "migrate-10", "migrate-20"}},
+		{20, 30, []string{}},
+		{30, 4, nil},
+		{10, 10, nil},
+	} {
+		c.Logf("test %d: %d -> %d", i, t.from, t.to)
+		c.Check(charm.MigrationActions(actions, t.from, t.to), jc.DeepEquals, t.expect)
+	}
+}
+
+func (s *MigrationsSuite) TestMigrationActionsNoActions(c *gc.C) {
+	c.Check(charm.MigrationActions(nil, 1, 2), gc.HasLen, 0)
+}
+
+func (s *MigrationsSuite) TestIsMigrationUpgrade(c *gc.C) {
+	url := corecharm.MustParseURL
+	c.Check(charm.IsMigrationUpgrade(url("cs:quantal/mysql-1"), url("cs:quantal/mysql-2")), jc.IsTrue)
+	c.Check(charm.IsMigrationUpgrade(url("cs:quantal/mysql-2"), url("cs:quantal/mysql-1")), jc.IsFalse)
+	c.Check(charm.IsMigrationUpgrade(url("cs:quantal/mysql-1"), url("cs:quantal/mariadb-2")), jc.IsFalse)
+	c.Check(charm.IsMigrationUpgrade(nil, url("cs:quantal/mysql-2")), jc.IsFalse)
+}
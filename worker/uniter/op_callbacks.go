@@ -5,6 +5,8 @@ package uniter
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 
 	"github.com/juju/errors"
 	corecharm "gopkg.in/juju/charm.v6"
@@ -119,6 +121,31 @@ func (opc *operationCallbacks) SetCurrentCharm(charmURL *corecharm.URL) error {
 	return opc.u.unit.SetCharmURL(charmURL)
 }
 
+// DeployedCharmURL is part of the operation.Callbacks interface.
+func (opc *operationCallbacks) DeployedCharmURL() (*corecharm.URL, error) {
+	curl, err := charm.ReadCharmURL(filepath.Join(opc.u.paths.State.CharmDir, charm.CharmURLPath))
+	if os.IsNotExist(errors.Cause(err)) {
+		return nil, nil
+	}
+	return curl, errors.Trace(err)
+}
+
+// MigrationActions is part of the operation.Callbacks interface.
+func (opc *operationCallbacks) MigrationActions(from *corecharm.URL) ([]string, error) {
+	to, err := opc.DeployedCharmURL()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !charm.IsMigrationUpgrade(from, to) {
+		return nil, nil
+	}
+	ch, err := corecharm.ReadCharmDir(opc.u.paths.State.CharmDir)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return charm.MigrationActions(ch.Actions(), from.Revision, to.Revision), nil
+}
+
 // SetExecutingStatus is part of the operation.Callbacks interface.
 func (opc *operationCallbacks) SetExecutingStatus(message string) error {
 	return setAgentStatus(opc.u, status.Executing, message, nil)
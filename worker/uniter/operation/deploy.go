@@ -68,7 +68,19 @@ func (d *deploy) Prepare(state State) (*State, error) {
 	if err := d.callbacks.SetCurrentCharm(d.charmURL); err != nil {
 		return nil, errors.Trace(err)
 	}
-	return d.getState(state, Pending), nil
+	newState := d.getState(state, Pending)
+	if d.kind == Upgrade && newState.MigrateFrom == nil {
+		// Remember the charm we're upgrading from, so that the charm's
+		// migration actions can be run after the upgrade-charm hook. If
+		// an earlier upgrade is still incomplete we keep its origin, so
+		// that no revision boundary is skipped.
+		from, err := d.callbacks.DeployedCharmURL()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		newState.MigrateFrom = from
+	}
+	return newState, nil
 }
 
 // Execute installs or upgrades the prepared charm, and preserves any hook
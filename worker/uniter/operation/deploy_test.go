@@ -309,6 +309,67 @@ func (s *DeploySuite) TestPrepareSuccess_Upgrade_PreserveNoHook(c *gc.C) {
 	}
 }
 
+func (s *DeploySuite) TestPrepareUpgradeRecordsMigrateFrom(c *gc.C) {
+	callbacks := NewDeployCallbacks()
+	callbacks.MockDeployedCharmURL.charmURL = curl("cs:quantal/nyancat-2")
+	factory := operation.NewFactory(operation.FactoryParams{
+		Deployer:  NewMockDeployer(),
+		Callbacks: callbacks,
+	})
+	op, err := factory.NewUpgrade(curl("cs:quantal/nyancat-4"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	newState, err := op.Prepare(operation.State{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(newState, gc.DeepEquals, &operation.State{
+		Kind:        operation.Upgrade,
+		Step:        operation.Pending,
+		CharmURL:    curl("cs:quantal/nyancat-4"),
+		MigrateFrom: curl("cs:quantal/nyancat-2"),
+	})
+}
+
+func (s *DeploySuite) TestPrepareUpgradePreservesMigrateFrom(c *gc.C) {
+	callbacks := NewDeployCallbacks()
+	callbacks.MockDeployedCharmURL.err = errors.New("should not be called")
+	factory := operation.NewFactory(operation.FactoryParams{
+		Deployer:  NewMockDeployer(),
+		Callbacks: callbacks,
+	})
+	op, err := factory.NewResolvedUpgrade(curl("cs:quantal/nyancat-4"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	newState, err := op.Prepare(operation.State{
+		Kind:        operation.RunHook,
+		Step:        operation.Pending,
+		Hook:        &hook.Info{Kind: hooks.UpgradeCharm},
+		MigrateFrom: curl("cs:quantal/nyancat-1"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(newState, gc.DeepEquals, &operation.State{
+		Kind:        operation.Upgrade,
+		Step:        operation.Pending,
+		CharmURL:    curl("cs:quantal/nyancat-4"),
+		Hook:        &hook.Info{Kind: hooks.UpgradeCharm},
+		MigrateFrom: curl("cs:quantal/nyancat-1"),
+	})
+}
+
+func (s *DeploySuite) TestPrepareUpgradeDeployedCharmURLError(c *gc.C) {
+	callbacks := NewDeployCallbacks()
+	callbacks.MockDeployedCharmURL.err = errors.New("splat")
+	factory := operation.NewFactory(operation.FactoryParams{
+		Deployer:  NewMockDeployer(),
+		Callbacks: callbacks,
+	})
+	op, err := factory.NewUpgrade(curl("cs:quantal/nyancat-4"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	newState, err := op.Prepare(operation.State{})
+	c.Check(newState, gc.IsNil)
+	c.Check(err, gc.ErrorMatches, "splat")
+}
+
 func (s *DeploySuite) TestExecuteConflictError_Install(c *gc.C) {
 	s.testExecuteError(c, (operation.Factory).NewInstall)
 }
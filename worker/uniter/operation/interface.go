@@ -173,6 +173,16 @@ type Callbacks interface {
 	// charm or the application's settings for it. It's only used by Deploy operations.
 	SetCurrentCharm(charmURL *corecharm.URL) error
 
+	// DeployedCharmURL returns the URL of the charm currently deployed to
+	// the unit's charm directory, or nil if no charm has been deployed yet.
+	// It's only used by Deploy operations.
+	DeployedCharmURL() (*corecharm.URL, error)
+
+	// MigrationActions returns the names of the migration actions, declared
+	// by the deployed charm, that must be run to complete an upgrade from the
+	// supplied charm. It's only used by RunHook operations.
+	MigrationActions(from *corecharm.URL) ([]string, error)
+
 	// SetSeriesStatusUpgrade is intended to give the uniter a chance to
 	// upgrade the status of a running series upgrade before or after
 	// upgrade series hook code completes and, for display purposes, to
@@ -30,6 +30,10 @@ type runHook struct {
 
 	hookFound bool
 
+	// migrations holds the migration actions to run after a successful
+	// upgrade-charm hook.
+	migrations []string
+
 	RequiresMachineLock
 }
 
@@ -61,6 +65,13 @@ func (rh *runHook) Prepare(state State) (*State, error) {
 		return nil, err
 	}
 
+	if rh.info.Kind == hooks.UpgradeCharm && state.MigrateFrom != nil {
+		rh.migrations, err = rh.callbacks.MigrationActions(state.MigrateFrom)
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot determine charm migration actions")
+		}
+	}
+
 	if hooks.Kind(name) == hooks.LeaderElected {
 		// Check if leadership has changed between queueing of the hook and
 		// Actual execution. Skip execution if we are no longer the leader.
@@ -94,6 +105,12 @@ func RunningHookMessage(hookName string) string {
 	return fmt.Sprintf("running %s hook", hookName)
 }
 
+// RunningMigrationMessage returns the info message to print when running a
+// charm migration action.
+func RunningMigrationMessage(actionName string) string {
+	return fmt.Sprintf("running %s migration", actionName)
+}
+
 // Execute runs the hook.
 // Execute is part of the Operation interface.
 func (rh *runHook) Execute(state State) (*State, error) {
@@ -141,6 +158,16 @@ func (rh *runHook) Execute(state State) (*State, error) {
 		logger.Infof("skipped %q hook (missing)", rh.name)
 	}
 
+	if step == Done {
+		var migrateErr error
+		state.MigrationsRun, migrateErr = rh.runMigrations(state.MigrationsRun)
+		if migrateErr != nil {
+			// Record the migrations that succeeded, so that they are
+			// not run again when the hook is retried.
+			return &state, migrateErr
+		}
+	}
+
 	var hasRunStatusSet bool
 	var afterHookErr error
 	if hasRunStatusSet, afterHookErr = rh.afterHook(state); afterHookErr != nil {
@@ -154,6 +181,37 @@ func (rh *runHook) Execute(state State) (*State, error) {
 	}.apply(state), err
 }
 
+// runMigrations runs, in order, the charm migration actions that must follow
+// the upgrade-charm hook, skipping the first done of them, which completed
+// on an earlier attempt. Each one runs in a fresh action context, so that
+// the action hook tools are available to it and its changes are flushed
+// independently. A failed migration fails the hook, which leaves the unit
+// in an error state and prevents config-changed from running against
+// unmigrated data. It returns the number of migrations that have completed.
+func (rh *runHook) runMigrations(done int) (int, error) {
+	for ; done < len(rh.migrations); done++ {
+		name := rh.migrations[done]
+		if err := rh.callbacks.SetExecutingStatus(RunningMigrationMessage(name)); err != nil {
+			return done, err
+		}
+		rnr, err := rh.runnerFactory.NewMigrationRunner(name)
+		if err != nil {
+			return done, err
+		}
+		if err := rnr.Context().Prepare(); err != nil {
+			return done, errors.Trace(err)
+		}
+		if err := rnr.RunMigration(name); err != nil {
+			logger.Errorf("migration action %q failed: %v", name, err)
+			rh.callbacks.NotifyHookFailed(name, rnr.Context())
+			return done, ErrHookFailed
+		}
+		logger.Infof("ran %q migration action", name)
+		rh.callbacks.NotifyHookCompleted(name, rnr.Context())
+	}
+	return done, nil
+}
+
 func (rh *runHook) beforeHook(state State) error {
 	var err error
 	switch rh.info.Kind {
@@ -283,6 +341,9 @@ func (rh *runHook) Commit(state State) (*State, error) {
 		newState.Started = true
 	case hooks.Stop:
 		newState.Stopped = true
+	case hooks.UpgradeCharm:
+		newState.MigrateFrom = nil
+		newState.MigrationsRun = 0
 	}
 
 	return newState, nil
@@ -299,6 +299,84 @@ func (s *RunHookSuite) TestExecuteOtherError(c *gc.C) {
 	c.Assert(callbacks.MockNotifyHookCompleted.gotName, gc.IsNil)
 }
 
+func (s *RunHookSuite) TestExecuteUpgradeCharmRunsMigrations(c *gc.C) {
+	op, callbacks, runnerFactory := s.getExecuteRunnerTest(c, operation.Factory.NewRunHook, hooks.UpgradeCharm, nil)
+	callbacks.MockMigrationActions.names = []string{"migrate-5", "migrate-10"}
+	state := operation.State{MigrateFrom: curl("cs:quantal/wordpress-2")}
+	_, err := op.Prepare(state)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(callbacks.MockMigrationActions.gotFrom, gc.DeepEquals, curl("cs:quantal/wordpress-2"))
+
+	newState, err := op.Execute(state)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(newState, gc.DeepEquals, &operation.State{
+		Kind:          operation.RunHook,
+		Step:          operation.Done,
+		Hook:          &hook.Info{Kind: hooks.UpgradeCharm},
+		MigrateFrom:   curl("cs:quantal/wordpress-2"),
+		MigrationsRun: 2,
+	})
+	c.Assert(*runnerFactory.MockNewHookRunner.runner.MockRunHook.gotName, gc.Equals, "some-hook-name")
+	c.Assert(runnerFactory.MockNewMigrationRunner.gotNames, jc.DeepEquals, []string{"migrate-5", "migrate-10"})
+	runner := runnerFactory.MockNewMigrationRunner.runner
+	c.Assert(runner.MockRunMigration.gotNames, jc.DeepEquals, []string{"migrate-5", "migrate-10"})
+	c.Assert(*callbacks.MockNotifyHookCompleted.gotName, gc.Equals, "migrate-10")
+	c.Assert(callbacks.MockNotifyHookFailed.gotName, gc.IsNil)
+}
+
+func (s *RunHookSuite) TestExecuteUpgradeCharmSkipsCompletedMigrations(c *gc.C) {
+	op, callbacks, runnerFactory := s.getExecuteRunnerTest(c, operation.Factory.NewRunHook, hooks.UpgradeCharm, nil)
+	callbacks.MockMigrationActions.names = []string{"migrate-5", "migrate-10"}
+	state := operation.State{
+		MigrateFrom:   curl("cs:quantal/wordpress-2"),
+		MigrationsRun: 1,
+	}
+	_, err := op.Prepare(state)
+	c.Assert(err, jc.ErrorIsNil)
+
+	newState, err := op.Execute(state)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(newState.MigrationsRun, gc.Equals, 2)
+	runner := runnerFactory.MockNewMigrationRunner.runner
+	c.Assert(runner.MockRunMigration.gotNames, jc.DeepEquals, []string{"migrate-10"})
+}
+
+func (s *RunHookSuite) TestExecuteUpgradeCharmMigrationError(c *gc.C) {
+	op, callbacks, runnerFactory := s.getExecuteRunnerTest(c, operation.Factory.NewRunHook, hooks.UpgradeCharm, nil)
+	callbacks.MockMigrationActions.names = []string{"migrate-5", "migrate-10"}
+	runner := runnerFactory.MockNewMigrationRunner.runner
+	runner.MockRunMigration.err = errors.New("data loss imminent")
+	state := operation.State{
+		MigrateFrom:   curl("cs:quantal/wordpress-2"),
+		MigrationsRun: 1,
+	}
+	prepared, err := op.Prepare(state)
+	c.Assert(err, jc.ErrorIsNil)
+
+	newState, err := op.Execute(*prepared)
+	c.Assert(err, gc.Equals, operation.ErrHookFailed)
+	c.Assert(newState, gc.DeepEquals, &operation.State{
+		Kind:          operation.RunHook,
+		Step:          operation.Pending,
+		Hook:          &hook.Info{Kind: hooks.UpgradeCharm},
+		MigrateFrom:   curl("cs:quantal/wordpress-2"),
+		MigrationsRun: 1,
+	})
+	c.Assert(runner.MockRunMigration.gotNames, jc.DeepEquals, []string{"migrate-10"})
+	c.Assert(*callbacks.MockNotifyHookFailed.gotName, gc.Equals, "migrate-10")
+}
+
+func (s *RunHookSuite) TestExecuteUpgradeCharmNoMigrateFrom(c *gc.C) {
+	op, callbacks, runnerFactory := s.getExecuteRunnerTest(c, operation.Factory.NewRunHook, hooks.UpgradeCharm, nil)
+	_, err := op.Prepare(operation.State{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(callbacks.MockMigrationActions.gotFrom, gc.IsNil)
+
+	_, err = op.Execute(operation.State{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(runnerFactory.MockNewMigrationRunner.gotNames, gc.HasLen, 0)
+}
+
 func (s *RunHookSuite) TestInstallHookPreservesStatus(c *gc.C) {
 	op, callbacks, f := s.getExecuteRunnerTest(c, operation.Factory.NewRunHook, hooks.Install, nil)
 	err := f.MockNewHookRunner.runner.Context().SetUnitStatus(jujuc.StatusInfo{Status: "blocked", Info: "no database"})
@@ -711,6 +789,28 @@ func (s *RunHookSuite) TestQueueHook_UpgradeCharm_Preserve(c *gc.C) {
 	s.testQueueHook_Preserve(c, hooks.UpgradeCharm)
 }
 
+func (s *RunHookSuite) TestCommitUpgradeCharmClearsMigrateFrom(c *gc.C) {
+	for i, newHook := range []newHook{
+		operation.Factory.NewRunHook,
+		operation.Factory.NewSkipHook,
+	} {
+		c.Logf("variant %d", i)
+		s.testCommitSuccess(c,
+			newHook,
+			hook.Info{Kind: hooks.UpgradeCharm},
+			operation.State{
+				MigrateFrom:   curl("cs:quantal/wordpress-2"),
+				MigrationsRun: 2,
+			},
+			operation.State{
+				Kind: operation.RunHook,
+				Step: operation.Queued,
+				Hook: &hook.Info{Kind: hooks.ConfigChanged},
+			},
+		)
+	}
+}
+
 func (s *RunHookSuite) testQueueNothing_BlankSlate(c *gc.C, hookInfo hook.Info) {
 	for i, newHook := range []newHook{
 		operation.Factory.NewRunHook,
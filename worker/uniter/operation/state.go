@@ -94,6 +94,18 @@ type State struct {
 	// operation, and is otherwise blank.
 	CharmURL *charm.URL `yaml:"charm,omitempty"`
 
+	// MigrateFrom describes the charm the unit ran before the upgrade in
+	// progress. It is recorded by an Upgrade operation and cleared once the
+	// upgrade-charm hook has been committed; the charm's migration actions
+	// are chosen by comparing its revision with that of the new charm.
+	MigrateFrom *charm.URL `yaml:"migrate-from,omitempty"`
+
+	// MigrationsRun holds the number of the charm's migration actions that
+	// have completed since the upgrade from MigrateFrom, in the order in
+	// which they are run. It is cleared along with MigrateFrom, and lets a
+	// retried upgrade-charm hook skip the migrations that have succeeded.
+	MigrationsRun int `yaml:"migrations-run,omitempty"`
+
 	// ConfigHash stores a hash of the latest known charm
 	// configuration settings - it's used to determine whether we need
 	// to run config-changed.
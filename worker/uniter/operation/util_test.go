@@ -41,10 +41,20 @@ func (mock *MockSetCurrentCharm) Call(charmURL *corecharm.URL) error {
 	return mock.err
 }
 
+type MockDeployedCharmURL struct {
+	charmURL *corecharm.URL
+	err      error
+}
+
+func (mock *MockDeployedCharmURL) Call() (*corecharm.URL, error) {
+	return mock.charmURL, mock.err
+}
+
 type DeployCallbacks struct {
 	operation.Callbacks
 	*MockGetArchiveInfo
 	*MockSetCurrentCharm
+	*MockDeployedCharmURL
 	MockInitializeMetricsTimers *MockNoArgs
 }
 
@@ -56,6 +66,10 @@ func (cb *DeployCallbacks) SetCurrentCharm(charmURL *corecharm.URL) error {
 	return cb.MockSetCurrentCharm.Call(charmURL)
 }
 
+func (cb *DeployCallbacks) DeployedCharmURL() (*corecharm.URL, error) {
+	return cb.MockDeployedCharmURL.Call()
+}
+
 func (cb *DeployCallbacks) InitializeMetricsTimers() error {
 	return cb.MockInitializeMetricsTimers.Call()
 }
@@ -150,9 +164,21 @@ func (mock *MockPrepareHook) Call(hookInfo hook.Info) (string, error) {
 	return mock.name, mock.err
 }
 
+type MockMigrationActions struct {
+	gotFrom *corecharm.URL
+	names   []string
+	err     error
+}
+
+func (mock *MockMigrationActions) Call(from *corecharm.URL) ([]string, error) {
+	mock.gotFrom = from
+	return mock.names, mock.err
+}
+
 type PrepareHookCallbacks struct {
 	operation.Callbacks
 	*MockPrepareHook
+	*MockMigrationActions
 	executingMessage string
 }
 
@@ -160,6 +186,10 @@ func (cb *PrepareHookCallbacks) PrepareHook(hookInfo hook.Info) (string, error)
 	return cb.MockPrepareHook.Call(hookInfo)
 }
 
+func (cb *PrepareHookCallbacks) MigrationActions(from *corecharm.URL) ([]string, error) {
+	return cb.MockMigrationActions.Call(from)
+}
+
 func (cb *PrepareHookCallbacks) SetExecutingStatus(message string) error {
 	cb.executingMessage = message
 	return nil
@@ -234,6 +264,17 @@ func (mock *MockNewHookRunner) Call(hookInfo hook.Info) (runner.Runner, error) {
 	return mock.runner, mock.err
 }
 
+type MockNewMigrationRunner struct {
+	gotNames []string
+	runner   *MockRunner
+	err      error
+}
+
+func (mock *MockNewMigrationRunner) Call(actionName string) (runner.Runner, error) {
+	mock.gotNames = append(mock.gotNames, actionName)
+	return mock.runner, mock.err
+}
+
 type MockNewCommandRunner struct {
 	gotInfo *context.CommandInfo
 	runner  *MockRunner
@@ -248,6 +289,7 @@ func (mock *MockNewCommandRunner) Call(info context.CommandInfo) (runner.Runner,
 type MockRunnerFactory struct {
 	*MockNewActionRunner
 	*MockNewHookRunner
+	*MockNewMigrationRunner
 	*MockNewCommandRunner
 }
 
@@ -259,6 +301,10 @@ func (f *MockRunnerFactory) NewHookRunner(hookInfo hook.Info) (runner.Runner, er
 	return f.MockNewHookRunner.Call(hookInfo)
 }
 
+func (f *MockRunnerFactory) NewMigrationRunner(actionName string) (runner.Runner, error) {
+	return f.MockNewMigrationRunner.Call(actionName)
+}
+
 func (f *MockRunnerFactory) NewCommandRunner(commandInfo context.CommandInfo) (runner.Runner, error) {
 	return f.MockNewCommandRunner.Call(commandInfo)
 }
@@ -362,10 +408,21 @@ func (mock *MockRunHook) Call(hookName string) error {
 	return mock.err
 }
 
+type MockRunMigration struct {
+	gotNames []string
+	err      error
+}
+
+func (mock *MockRunMigration) Call(actionName string) error {
+	mock.gotNames = append(mock.gotNames, actionName)
+	return mock.err
+}
+
 type MockRunner struct {
 	*MockRunAction
 	*MockRunCommands
 	*MockRunHook
+	*MockRunMigration
 	context runner.Context
 }
 
@@ -381,6 +438,10 @@ func (r *MockRunner) RunCommands(commands string) (*utilexec.ExecResponse, error
 	return r.MockRunCommands.Call(commands)
 }
 
+func (r *MockRunner) RunMigration(actionName string) error {
+	return r.MockRunMigration.Call(actionName)
+}
+
 func (r *MockRunner) RunHook(hookName string) error {
 	r.Context().(*MockContext).setStatusCalled = r.MockRunHook.setStatusCalled
 	return r.MockRunHook.Call(hookName)
@@ -388,8 +449,9 @@ func (r *MockRunner) RunHook(hookName string) error {
 
 func NewDeployCallbacks() *DeployCallbacks {
 	return &DeployCallbacks{
-		MockGetArchiveInfo:  &MockGetArchiveInfo{info: &MockBundleInfo{}},
-		MockSetCurrentCharm: &MockSetCurrentCharm{},
+		MockGetArchiveInfo:   &MockGetArchiveInfo{info: &MockBundleInfo{}},
+		MockSetCurrentCharm:  &MockSetCurrentCharm{},
+		MockDeployedCharmURL: &MockDeployedCharmURL{},
 	}
 }
 
@@ -409,7 +471,8 @@ func NewMockDeployer() *MockDeployer {
 
 func NewPrepareHookCallbacks() *PrepareHookCallbacks {
 	return &PrepareHookCallbacks{
-		MockPrepareHook: &MockPrepareHook{nil, "some-hook-name", nil},
+		MockPrepareHook:      &MockPrepareHook{nil, "some-hook-name", nil},
+		MockMigrationActions: &MockMigrationActions{},
 	}
 }
 
@@ -450,6 +513,14 @@ func NewRunHookRunnerFactory(runErr error, contextOps ...func(*MockContext)) *Mo
 				context:     ctx,
 			},
 		},
+		MockNewMigrationRunner: &MockNewMigrationRunner{
+			runner: &MockRunner{
+				MockRunMigration: &MockRunMigration{},
+				context: &MockContext{
+					actionData: &context.ActionData{Migration: true},
+				},
+			},
+		},
 	}
 }
 
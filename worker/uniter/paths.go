@@ -19,6 +19,7 @@ import (
 	"github.com/juju/juju/agent/tools"
 	"github.com/juju/juju/caas/kubernetes/provider"
 	"github.com/juju/juju/juju/sockets"
+	"github.com/juju/juju/resource/cache"
 )
 
 // Paths represents the set of filesystem paths a uniter worker has reason to
@@ -32,6 +33,10 @@ type Paths struct {
 	// /var/lib/juju/agents/$UNIT_TAG/ )
 	ToolsDir string
 
+	// ResourceCacheDir is the directory of the resource cache shared by
+	// all the units on the machine (typically /var/lib/juju/resource-cache).
+	ResourceCacheDir string
+
 	// Runtime represents the set of paths that are relevant at runtime.
 	Runtime RuntimePaths
 
@@ -66,6 +71,11 @@ func (paths Paths) ComponentDir(name string) string {
 	return filepath.Join(paths.State.BaseDir, name)
 }
 
+// GetResourceCacheDir exists to satisfy the context.Paths interface.
+func (paths Paths) GetResourceCacheDir() string {
+	return paths.ResourceCacheDir
+}
+
 const jujucServerSocketPort = 30000
 
 // RuntimePaths represents the set of paths that are relevant at runtime.
@@ -196,7 +206,8 @@ func NewWorkerPaths(dataDir string, unitTag names.UnitTag, worker string, isRemo
 
 	toolsDir := tools.ToolsDir(dataDir, unitTag.String())
 	return Paths{
-		ToolsDir: filepath.FromSlash(toolsDir),
+		ToolsDir:         filepath.FromSlash(toolsDir),
+		ResourceCacheDir: cache.Dir(dataDir),
 		Runtime: RuntimePaths{
 			JujuRunSocket:     newSocket("run", false),
 			JujucServerSocket: newSocket("agent", true),
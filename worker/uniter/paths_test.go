@@ -42,7 +42,8 @@ func (s *PathsSuite) TestWindows(c *gc.C) {
 	relData := relPathFunc(dataDir)
 	relAgent := relPathFunc(relData("agents", "unit-some-application-323"))
 	c.Assert(paths, jc.DeepEquals, uniter.Paths{
-		ToolsDir: relData("tools/unit-some-application-323"),
+		ToolsDir:         relData("tools/unit-some-application-323"),
+		ResourceCacheDir: relData("resource-cache"),
 		Runtime: uniter.RuntimePaths{
 			JujuRunSocket:     sockets.Socket{Network: "unix", Address: `\\.\pipe\unit-some-application-323-run`},
 			JujucServerSocket: sockets.Socket{Network: "unix", Address: `\\.\pipe\unit-some-application-323-agent`},
@@ -71,7 +72,8 @@ func (s *PathsSuite) TestWorkerPathsWindows(c *gc.C) {
 	relData := relPathFunc(dataDir)
 	relAgent := relPathFunc(relData("agents", "unit-some-application-323"))
 	c.Assert(paths, jc.DeepEquals, uniter.Paths{
-		ToolsDir: relData("tools/unit-some-application-323"),
+		ToolsDir:         relData("tools/unit-some-application-323"),
+		ResourceCacheDir: relData("resource-cache"),
 		Runtime: uniter.RuntimePaths{
 			JujuRunSocket:     sockets.Socket{Network: "unix", Address: `\\.\pipe\unit-some-application-323-some-worker-run`},
 			JujucServerSocket: sockets.Socket{Network: "unix", Address: `\\.\pipe\unit-some-application-323-some-worker-agent`},
@@ -99,7 +101,8 @@ func (s *PathsSuite) TestOther(c *gc.C) {
 	relData := relPathFunc(dataDir)
 	relAgent := relPathFunc(relData("agents", "unit-some-application-323"))
 	c.Assert(paths, jc.DeepEquals, uniter.Paths{
-		ToolsDir: relData("tools/unit-some-application-323"),
+		ToolsDir:         relData("tools/unit-some-application-323"),
+		ResourceCacheDir: relData("resource-cache"),
 		Runtime: uniter.RuntimePaths{
 			JujuRunSocket:     sockets.Socket{Network: "unix", Address: relAgent("run.socket")},
 			JujucServerSocket: sockets.Socket{Network: "unix", Address: "@" + relAgent("agent.socket")},
@@ -145,7 +148,8 @@ func (s *PathsSuite) assertTCPRemote(c *gc.C, dataDir string) {
 	relData := relPathFunc(dataDir)
 	relAgent := relPathFunc(relData("agents", "unit-some-application-323"))
 	c.Assert(paths, jc.DeepEquals, uniter.Paths{
-		ToolsDir: relData("tools/unit-some-application-323"),
+		ToolsDir:         relData("tools/unit-some-application-323"),
+		ResourceCacheDir: relData("resource-cache"),
 		Runtime: uniter.RuntimePaths{
 			JujuRunSocket:     sockets.Socket{Network: "tcp", Address: "1.1.1.1:30666"},
 			JujucServerSocket: sockets.Socket{Network: "tcp", Address: "1.1.1.1:30323"},
@@ -174,7 +178,8 @@ func (s *PathsSuite) TestWorkerPaths(c *gc.C) {
 	relData := relPathFunc(dataDir)
 	relAgent := relPathFunc(relData("agents", "unit-some-application-323"))
 	c.Assert(paths, jc.DeepEquals, uniter.Paths{
-		ToolsDir: relData("tools/unit-some-application-323"),
+		ToolsDir:         relData("tools/unit-some-application-323"),
+		ResourceCacheDir: relData("resource-cache"),
 		Runtime: uniter.RuntimePaths{
 			JujuRunSocket:     sockets.Socket{Network: "unix", Address: relAgent(worker + "-run.socket")},
 			JujucServerSocket: sockets.Socket{Network: "unix", Address: "@" + relAgent(worker+"-agent.socket")},
@@ -194,7 +199,8 @@ func (s *PathsSuite) TestWorkerPaths(c *gc.C) {
 
 func (s *PathsSuite) TestContextInterface(c *gc.C) {
 	paths := uniter.Paths{
-		ToolsDir: "/path/to/tools",
+		ToolsDir:         "/path/to/tools",
+		ResourceCacheDir: "/path/to/resource-cache",
 		Runtime: uniter.RuntimePaths{
 			JujucServerSocket: sockets.Socket{Network: "unix", Address: "/path/to/socket"},
 		},
@@ -207,4 +213,5 @@ func (s *PathsSuite) TestContextInterface(c *gc.C) {
 	c.Assert(paths.GetCharmDir(), gc.Equals, "/path/to/charm")
 	c.Assert(paths.GetJujucSocket(), gc.DeepEquals, sockets.Socket{Address: "/path/to/socket", Network: "unix"})
 	c.Assert(paths.GetMetricsSpoolDir(), gc.Equals, "/path/to/spool/metrics")
+	c.Assert(paths.GetResourceCacheDir(), gc.Equals, "/path/to/resource-cache")
 }
@@ -4,6 +4,7 @@
 package context
 
 import (
+	"github.com/juju/utils"
 	"gopkg.in/juju/names.v3"
 )
 
@@ -15,6 +16,12 @@ type ActionData struct {
 	Failed         bool
 	ResultsMessage string
 	ResultsMap     map[string]interface{}
+
+	// Migration is true if the action is a charm migration run by the
+	// uniter itself. Such an action has no record on the controller, so
+	// its messages are logged locally and its failure fails the hook
+	// that it follows.
+	Migration bool
 }
 
 // NewActionData builds a suitable ActionData struct with no nil members.
@@ -28,6 +35,16 @@ func NewActionData(name string, tag *names.ActionTag, params map[string]interfac
 	}
 }
 
+// NewMigrationActionData builds an ActionData for running the named charm
+// migration action with the supplied parameters. The action is given a
+// fresh tag of its own, which is never known to the controller.
+func NewMigrationActionData(name string, params map[string]interface{}) *ActionData {
+	tag := names.NewActionTag(utils.MustNewUUID().String())
+	data := NewActionData(name, &tag, params)
+	data.Migration = true
+	return data
+}
+
 // addValueToMap adds the given value to the map on which the method is run.
 // This allows us to merge maps such as {foo: {bar: baz}} and {foo: {baz: faz}}
 // into {foo: {bar: baz, baz: faz}}.
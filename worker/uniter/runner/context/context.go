@@ -54,6 +54,10 @@ type Paths interface {
 	// ComponentDir returns the filesystem path to the directory
 	// containing all data files for a component.
 	ComponentDir(name string) string
+
+	// GetResourceCacheDir returns the filesystem path to the directory
+	// of the resource cache shared by the units on the machine.
+	GetResourceCacheDir() string
 }
 
 // Clock defines the methods of the full clock.Clock that are needed here.
@@ -74,6 +78,9 @@ type ComponentConfig struct {
 	UnitName string
 	// DataDir is the component's data directory.
 	DataDir string
+	// ResourceCacheDir is the directory of the resource cache shared
+	// by the units on the machine.
+	ResourceCacheDir string
 	// APICaller is the API caller the component may use.
 	APICaller base.APICaller
 }
@@ -98,6 +105,21 @@ type meterStatus struct {
 	info string
 }
 
+// HookSandbox describes the sandbox in which the charm's hooks are run,
+// as configured for the model.
+type HookSandbox struct {
+	// Enabled is true if hooks are to be run in the sandbox.
+	Enabled bool
+
+	// User is the user that hooks are run as. If it is empty, hooks are
+	// run as the unit agent's user.
+	User string
+
+	// Profile is the AppArmor profile that hooks are run under. If it is
+	// empty, a profile is generated from the charm's metadata.
+	Profile string
+}
+
 // HookProcess is an interface representing a process running a hook.
 type HookProcess interface {
 	Pid() int
@@ -186,6 +208,9 @@ type HookContext struct {
 	// meterStatus is the status of the unit's metering.
 	meterStatus *meterStatus
 
+	// hookSandbox describes the sandbox in which hooks are run.
+	hookSandbox HookSandbox
+
 	// pendingPorts contains a list of port ranges to be opened or
 	// closed when the current hook is committed.
 	pendingPorts map[PortRange]PortRangeInfo
@@ -232,6 +257,9 @@ type HookContext struct {
 	componentDir   func(string) string
 	componentFuncs map[string]ComponentFunc
 
+	// resourceCacheDir is the directory of the machine's resource cache.
+	resourceCacheDir string
+
 	// slaLevel contains the current SLA level.
 	slaLevel string
 
@@ -254,9 +282,10 @@ func (ctx *HookContext) Component(name string) (jujuc.ContextComponent, error) {
 
 	facade := ctx.state.Facade()
 	config := ComponentConfig{
-		UnitName:  ctx.unit.Name(),
-		DataDir:   ctx.componentDir(name),
-		APICaller: facade.RawAPICaller(),
+		UnitName:         ctx.unit.Name(),
+		DataDir:          ctx.componentDir(name),
+		ResourceCacheDir: ctx.resourceCacheDir,
+		APICaller:        facade.RawAPICaller(),
 	}
 	compCtx, err := compCtxFunc(config)
 	if err != nil {
@@ -323,6 +352,11 @@ func (ctx *HookContext) ModelType() model.ModelType {
 	return ctx.modelType
 }
 
+// HookSandbox returns the sandbox in which the charm's hooks are run.
+func (ctx *HookContext) HookSandbox() HookSandbox {
+	return ctx.hookSandbox
+}
+
 // UnitStatus will return the status for the current Unit.
 func (ctx *HookContext) UnitStatus() (*jujuc.StatusInfo, error) {
 	if ctx.status == nil {
@@ -575,6 +609,10 @@ func (ctx *HookContext) LogActionMessage(message string) error {
 	if ctx.actionData == nil {
 		return errors.New("not running an action")
 	}
+	if ctx.actionData.Migration {
+		logger.Infof("%s: %s", ctx.actionData.Name, message)
+		return nil
+	}
 	return ctx.unit.LogActionMessage(ctx.actionData.Tag, message)
 }
 
@@ -739,7 +777,7 @@ func (ctx *HookContext) handleReboot(err *error) {
 
 // Prepare implements the Context interface.
 func (ctx *HookContext) Prepare() error {
-	if ctx.actionData != nil {
+	if ctx.actionData != nil && !ctx.actionData.Migration {
 		err := ctx.state.ActionBegin(ctx.actionData.Tag)
 		if err != nil {
 			return errors.Trace(err)
@@ -750,10 +788,14 @@ func (ctx *HookContext) Prepare() error {
 
 // Flush implements the Context interface.
 func (ctx *HookContext) Flush(process string, ctxErr error) (err error) {
+	if ctxErr == nil && ctx.actionData != nil && ctx.actionData.Migration {
+		ctxErr = ctx.migrationError()
+	}
 	writeChanges := ctxErr == nil
 
 	// In the case of Actions, handle any errors using finalizeAction.
-	if ctx.actionData != nil {
+	// Migrations are run as part of a hook, and fail like one.
+	if ctx.actionData != nil && !ctx.actionData.Migration {
 		// If we had an error in err at this point, it's part of the
 		// normal behavior of an Action.  Errors which happen during
 		// the finalize should be handed back to the uniter.  Close
@@ -788,14 +830,16 @@ func (ctx *HookContext) Flush(process string, ctxErr error) (err error) {
 			var e error
 			var op string
 			if rangeInfo.ShouldOpen {
-				e = ctx.unit.OpenPorts(
+				e = ctx.unit.OpenPortsInHook(
+					process,
 					rangeKey.Ports.Protocol,
 					rangeKey.Ports.FromPort,
 					rangeKey.Ports.ToPort,
 				)
 				op = "open"
 			} else {
-				e = ctx.unit.ClosePorts(
+				e = ctx.unit.ClosePortsInHook(
+					process,
 					rangeKey.Ports.Protocol,
 					rangeKey.Ports.FromPort,
 					rangeKey.Ports.ToPort,
@@ -870,6 +914,18 @@ func (ctx *HookContext) commitPodSpec() error {
 	return nil
 }
 
+// migrationError returns an error if the migration action being run has
+// failed, and logs any results it set, since it has nowhere to record them.
+func (ctx *HookContext) migrationError() error {
+	if len(ctx.actionData.ResultsMap) > 0 {
+		logger.Infof("%s results: %v", ctx.actionData.Name, ctx.actionData.ResultsMap)
+	}
+	if !ctx.actionData.Failed {
+		return nil
+	}
+	return errors.Errorf("migration %q failed: %s", ctx.actionData.Name, ctx.actionData.ResultsMessage)
+}
+
 // finalizeAction passes back the final status of an Action hook to state.
 // It wraps any errors which occurred in normal behavior of the Action run;
 // only errors passed in unhandledErr will be returned.
@@ -978,3 +1034,9 @@ func (ctx *HookContext) NetworkInfo(bindingNames []string, relationId int) (map[
 	}
 	return ctx.unit.NetworkInfo(bindingNames, relId)
 }
+
+// RelatedUnitAddresses returns the addresses of the units related to the
+// unit on the named endpoint.
+func (ctx *HookContext) RelatedUnitAddresses(endpoint string) ([]params.RelatedUnitAddress, error) {
+	return ctx.unit.RelatedUnitAddresses(endpoint)
+}
@@ -371,6 +371,38 @@ func (s *InterfaceSuite) TestLogActionMessage(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *InterfaceSuite) TestMigrationContextIsLocal(c *gc.C) {
+	// A migration has no action on the controller, so neither
+	// preparing it nor logging a message makes any API call.
+	hctx := context.GetStubMigrationContext("migrate-3")
+	c.Assert(hctx.Prepare(), jc.ErrorIsNil)
+	c.Assert(hctx.LogActionMessage("hello world"), jc.ErrorIsNil)
+	name, err := hctx.ActionName()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(name, gc.Equals, "migrate-3")
+}
+
+func (s *InterfaceSuite) TestMigrationFlushSuccess(c *gc.C) {
+	hctx := context.GetStubMigrationContext("migrate-3")
+	c.Assert(hctx.UpdateActionResults([]string{"moved"}, "12"), jc.ErrorIsNil)
+	err := hctx.Flush("migrate-3", nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *InterfaceSuite) TestMigrationFlushActionFailed(c *gc.C) {
+	hctx := context.GetStubMigrationContext("migrate-3")
+	c.Assert(hctx.SetActionFailed(), jc.ErrorIsNil)
+	c.Assert(hctx.SetActionMessage("schema mismatch"), jc.ErrorIsNil)
+	err := hctx.Flush("migrate-3", nil)
+	c.Assert(err, gc.ErrorMatches, `migration "migrate-3" failed: schema mismatch`)
+}
+
+func (s *InterfaceSuite) TestMigrationFlushError(c *gc.C) {
+	hctx := context.GetStubMigrationContext("migrate-3")
+	err := hctx.Flush("migrate-3", errors.New("exit status 1"))
+	c.Assert(err, gc.ErrorMatches, "exit status 1")
+}
+
 func (s *InterfaceSuite) TestRequestRebootAfterHook(c *gc.C) {
 	var killed bool
 	p := &mockProcess{func() error {
@@ -179,6 +179,7 @@ func (f *contextFactory) coreContext() (*HookContext, error) {
 		clock:              f.clock,
 		componentDir:       f.paths.ComponentDir,
 		componentFuncs:     registeredComponentFuncs,
+		resourceCacheDir:   f.paths.GetResourceCacheDir(),
 		availabilityzone:   f.zone,
 		principal:          f.principal,
 	}
@@ -317,6 +318,11 @@ func (f *contextFactory) updateContext(ctx *HookContext) (err error) {
 	}
 	ctx.legacyProxySettings = modelConfig.LegacyProxySettings()
 	ctx.jujuProxySettings = modelConfig.JujuProxySettings()
+	ctx.hookSandbox = HookSandbox{
+		Enabled: modelConfig.HookSandbox(),
+		User:    modelConfig.HookSandboxUser(),
+		Profile: modelConfig.HookSandboxProfile(),
+	}
 
 	statusCode, statusInfo, err := f.unit.MeterStatus()
 	if err != nil {
@@ -135,6 +135,21 @@ func (s *ContextFactorySuite) TestNewHookContextRetrievesSLALevel(c *gc.C) {
 	c.Assert(ctx.SLALevel(), gc.Equals, "essential")
 }
 
+func (s *ContextFactorySuite) TestNewHookContextRetrievesHookSandbox(c *gc.C) {
+	err := s.Model.UpdateModelConfig(map[string]interface{}{
+		"hook-sandbox":      true,
+		"hook-sandbox-user": "juju-hooks",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx, err := s.factory.HookContext(hook.Info{Kind: hooks.ConfigChanged})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctx.HookSandbox(), jc.DeepEquals, context.HookSandbox{
+		Enabled: true,
+		User:    "juju-hooks",
+	})
+}
+
 func (s *ContextFactorySuite) TestNewHookContextLeadershipContext(c *gc.C) {
 	s.testLeadershipContextWiring(c, func() *context.HookContext {
 		ctx, err := s.factory.HookContext(hook.Info{Kind: hooks.ConfigChanged})
@@ -125,6 +125,12 @@ func GetStubActionContext(in map[string]interface{}) *HookContext {
 	}
 }
 
+func GetStubMigrationContext(name string) *HookContext {
+	return &HookContext{
+		actionData: NewMigrationActionData(name, nil),
+	}
+}
+
 type LeadershipContextFunc func(LeadershipSettingsAccessor, leadership.Tracker, string) LeadershipContext
 
 func PatchNewLeadershipContext(f LeadershipContextFunc) func() {
@@ -375,3 +375,7 @@ func (MockEnvPaths) GetMetricsSpoolDir() string {
 func (MockEnvPaths) ComponentDir(name string) string {
 	return filepath.Join("path-to-base-dir", name)
 }
+
+func (MockEnvPaths) GetResourceCacheDir() string {
+	return "path-to-resource-cache"
+}
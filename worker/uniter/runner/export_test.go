@@ -4,6 +4,8 @@
 package runner
 
 import (
+	"syscall"
+
 	"github.com/juju/juju/worker/uniter/runner/context"
 )
 
@@ -17,3 +19,20 @@ var (
 func RunnerPaths(rnr Runner) context.Paths {
 	return rnr.(*runner).paths
 }
+
+func SandboxHook(rnr Runner, hookCmd []string) ([]string, *syscall.SysProcAttr, error) {
+	return rnr.(*runner).sandboxHook(hookCmd)
+}
+
+// PatchLoadAppArmorProfile replaces the function that loads AppArmor
+// profiles, and forgets the profiles already loaded.
+func PatchLoadAppArmorProfile(f func(string) error) func() {
+	loadedProfilesMu.Lock()
+	defer loadedProfilesMu.Unlock()
+	loadedProfiles = make(map[string]string)
+	orig := loadAppArmorProfile
+	loadAppArmorProfile = f
+	return func() {
+		loadAppArmorProfile = orig
+	}
+}
@@ -31,6 +31,11 @@ type Factory interface {
 	// NewActionRunner returns an execution context suitable for running the
 	// action identified by the supplied id.
 	NewActionRunner(actionId string) (Runner, error)
+
+	// NewMigrationRunner returns an execution context suitable for running
+	// the charm's named migration action, which is not enqueued on the
+	// controller.
+	NewMigrationRunner(actionName string) (Runner, error)
 }
 
 // NewFactory returns a Factory capable of creating runners for executing
@@ -134,6 +139,33 @@ func (f *factory) NewActionRunner(actionId string) (Runner, error) {
 	return runner, nil
 }
 
+// NewMigrationRunner exists to satisfy the Factory interface.
+func (f *factory) NewMigrationRunner(actionName string) (Runner, error) {
+	ch, err := getCharm(f.paths.GetCharmDir())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	spec, ok := ch.Actions().ActionSpecs[actionName]
+	if !ok {
+		return nil, charmrunner.NewBadActionError(actionName, "not defined")
+	}
+	params, err := spec.InsertDefaults(map[string]interface{}{})
+	if err != nil {
+		return nil, charmrunner.NewBadActionError(actionName, err.Error())
+	}
+	if err := spec.ValidateParams(params); err != nil {
+		return nil, charmrunner.NewBadActionError(actionName, err.Error())
+	}
+
+	actionData := context.NewMigrationActionData(actionName, params)
+	ctx, err := f.contextFactory.ActionContext(actionData)
+	if err != nil {
+		return nil, charmrunner.NewBadActionError(actionName, err.Error())
+	}
+	runner := NewRunner(ctx, f.paths, f.remoteExecutor)
+	return runner, nil
+}
+
 func getCharm(charmPath string) (charm.Charm, error) {
 	ch, err := charm.ReadCharm(charmPath)
 	if err != nil {
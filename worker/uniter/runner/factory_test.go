@@ -257,6 +257,27 @@ func (s *FactorySuite) TestNewActionRunnerGood(c *gc.C) {
 	}
 }
 
+func (s *FactorySuite) TestNewMigrationRunner(c *gc.C) {
+	s.SetCharm(c, "dummy")
+	rnr, err := s.factory.NewMigrationRunner("snapshot")
+	c.Assert(err, jc.ErrorIsNil)
+	s.AssertPaths(c, rnr)
+	data, err := rnr.Context().ActionData()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data.Name, gc.Equals, "snapshot")
+	c.Assert(data.Migration, jc.IsTrue)
+	c.Assert(data.Params, jc.DeepEquals, map[string]interface{}{
+		"outfile": "foo.bz2",
+	})
+}
+
+func (s *FactorySuite) TestNewMigrationRunnerMissingAction(c *gc.C) {
+	s.SetCharm(c, "dummy")
+	rnr, err := s.factory.NewMigrationRunner("migrate-5")
+	c.Assert(rnr, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, `cannot run "migrate-5" action: not defined`)
+}
+
 func (s *FactorySuite) TestNewActionRunnerBadCharm(c *gc.C) {
 	rnr, err := s.factory.NewActionRunner("irrelevant")
 	c.Assert(rnr, gc.IsNil)
@@ -164,6 +164,10 @@ type ContextNetworking interface {
 
 	// NetworkInfo returns the network info for the given bindings on the given relation.
 	NetworkInfo(bindingNames []string, relationId int) (map[string]params.NetworkInfoResult, error)
+
+	// RelatedUnitAddresses returns the addresses of the units related
+	// to the executing unit on the named endpoint.
+	RelatedUnitAddresses(endpoint string) ([]params.RelatedUnitAddress, error)
 }
 
 // ContextLeadership is the part of a hook context related to the
@@ -0,0 +1,86 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	jujucmd "github.com/juju/juju/cmd"
+)
+
+// EndpointAddressesCommand implements the endpoint-addresses command.
+type EndpointAddressesCommand struct {
+	cmd.CommandBase
+	ctx      Context
+	endpoint string
+	out      cmd.Output
+}
+
+// NewEndpointAddressesCommand returns a new EndpointAddressesCommand.
+func NewEndpointAddressesCommand(ctx Context) (cmd.Command, error) {
+	return &EndpointAddressesCommand{ctx: ctx}, nil
+}
+
+// Info is part of the cmd.Command interface.
+func (c *EndpointAddressesCommand) Info() *cmd.Info {
+	doc := `
+endpoint-addresses lists the units of the applications related to this
+unit's application on the given endpoint, with their public and private
+addresses. Units are listed as soon as they are added to the model, even
+before they join the relation; a unit without an address yet is listed
+without one.
+`
+	return jujucmd.Info(&cmd.Info{
+		Name:    "endpoint-addresses",
+		Args:    "<endpoint>",
+		Purpose: "print the addresses of the units related on an endpoint",
+		Doc:     doc,
+	})
+}
+
+// SetFlags is part of the cmd.Command interface.
+func (c *EndpointAddressesCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+}
+
+// Init is part of the cmd.Command interface.
+func (c *EndpointAddressesCommand) Init(args []string) error {
+	if len(args) < 1 || args[0] == "" {
+		return errors.New("no endpoint specified")
+	}
+	c.endpoint = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// endpointUnitAddresses holds the addresses of a related unit, as
+// printed by endpoint-addresses.
+type endpointUnitAddresses struct {
+	Application    string `json:"application" yaml:"application"`
+	RelationId     int    `json:"relation-id" yaml:"relation-id"`
+	PublicAddress  string `json:"public-address,omitempty" yaml:"public-address,omitempty"`
+	PrivateAddress string `json:"private-address,omitempty" yaml:"private-address,omitempty"`
+}
+
+// Run is part of the cmd.Command interface.
+func (c *EndpointAddressesCommand) Run(ctx *cmd.Context) error {
+	units, err := c.ctx.RelatedUnitAddresses(c.endpoint)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	result := make(map[string]endpointUnitAddresses, len(units))
+	for _, unit := range units {
+		result[unit.Unit] = endpointUnitAddresses{
+			Application:    unit.Application,
+			RelationId:     unit.RelationId,
+			PublicAddress:  unit.PublicAddress,
+			PrivateAddress: unit.PrivateAddress,
+		}
+	}
+	return c.out.Write(ctx, result)
+}
@@ -0,0 +1,95 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type EndpointAddressesSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&EndpointAddressesSuite{})
+
+func (s *EndpointAddressesSuite) runCommand(c *gc.C, args ...string) (*cmd.Context, int) {
+	hctx := s.GetHookContext(c, -1, "")
+	hctx.info.NetworkInterface.RelatedUnits = map[string][]params.RelatedUnitAddress{
+		"db": {{
+			Unit:           "mysql/0",
+			Application:    "mysql",
+			RelationId:     1,
+			PublicAddress:  "203.0.113.1",
+			PrivateAddress: "10.0.0.1",
+		}, {
+			Unit:        "mysql/1",
+			Application: "mysql",
+			RelationId:  1,
+		}},
+		"cache": {},
+	}
+	com, err := jujuc.NewCommand(hctx, cmdString("endpoint-addresses"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(jujuc.NewJujucCommandWrappedForTest(com), ctx, args)
+	return ctx, code
+}
+
+func (s *EndpointAddressesSuite) TestOutputFormats(c *gc.C) {
+	yamlOut := `
+mysql/0:
+  application: mysql
+  relation-id: 1
+  public-address: 203.0.113.1
+  private-address: 10.0.0.1
+mysql/1:
+  application: mysql
+  relation-id: 1
+`[1:]
+	jsonOut := `{"mysql/0":{"application":"mysql","relation-id":1,"public-address":"203.0.113.1","private-address":"10.0.0.1"},"mysql/1":{"application":"mysql","relation-id":1}}
+`
+	for i, t := range []struct {
+		args []string
+		out  string
+	}{
+		{[]string{"db"}, yamlOut},
+		{[]string{"db", "--format", "yaml"}, yamlOut},
+		{[]string{"db", "--format", "json"}, jsonOut},
+		{[]string{"cache", "--format", "json"}, "{}\n"},
+	} {
+		c.Logf("test %d: %#v", i, t.args)
+		ctx, code := s.runCommand(c, t.args...)
+		c.Check(code, gc.Equals, 0)
+		c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+		c.Check(bufferString(ctx.Stdout), gc.Equals, t.out)
+	}
+}
+
+func (s *EndpointAddressesSuite) TestUnknownEndpoint(c *gc.C) {
+	ctx, code := s.runCommand(c, "nonsense")
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "ERROR endpoint \"nonsense\" not found\n")
+}
+
+func (s *EndpointAddressesSuite) TestBadArgs(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{
+		{nil, "no endpoint specified"},
+		{[]string{"db", "extra"}, `unrecognized args: \["extra"\]`},
+	} {
+		c.Logf("test %d: %#v", i, t.args)
+		hctx := s.GetHookContext(c, -1, "")
+		com, err := jujuc.NewCommand(hctx, cmdString("endpoint-addresses"))
+		c.Assert(err, jc.ErrorIsNil)
+		cmdtesting.TestInit(c, jujuc.NewJujucCommandWrappedForTest(com), t.args, t.err)
+	}
+}
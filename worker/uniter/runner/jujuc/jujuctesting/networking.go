@@ -18,6 +18,7 @@ type NetworkInterface struct {
 	PrivateAddress     string
 	Ports              []network.PortRange
 	NetworkInfoResults map[string]params.NetworkInfoResult
+	RelatedUnits       map[string][]params.RelatedUnitAddress
 }
 
 // CheckPorts checks the current ports.
@@ -112,3 +113,17 @@ func (c *ContextNetworking) NetworkInfo(bindingNames []string, relationId int) (
 
 	return c.info.NetworkInfoResults, nil
 }
+
+// RelatedUnitAddresses implements jujuc.ContextNetworking.
+func (c *ContextNetworking) RelatedUnitAddresses(endpoint string) ([]params.RelatedUnitAddress, error) {
+	c.stub.AddCall("RelatedUnitAddresses", endpoint)
+	if err := c.stub.NextErr(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	units, ok := c.info.RelatedUnits[endpoint]
+	if !ok {
+		return nil, errors.NotFoundf("endpoint %q", endpoint)
+	}
+	return units, nil
+}
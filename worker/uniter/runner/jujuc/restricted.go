@@ -90,6 +90,11 @@ func (*RestrictedContext) NetworkInfo(bindingNames []string, relationId int) (ma
 	return map[string]params.NetworkInfoResult{}, ErrRestrictedContext
 }
 
+// RelatedUnitAddresses implements hooks.Context.
+func (*RestrictedContext) RelatedUnitAddresses(endpoint string) ([]params.RelatedUnitAddress, error) {
+	return nil, ErrRestrictedContext
+}
+
 // IsLeader implements hooks.Context.
 func (*RestrictedContext) IsLeader() (bool, error) { return false, ErrRestrictedContext }
 
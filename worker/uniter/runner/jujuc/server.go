@@ -68,6 +68,7 @@ var baseCommands = map[string]creator{
 	"pod-spec-set" + cmdSuffix:            NewPodSpecSetCommand,
 	"goal-state" + cmdSuffix:              NewGoalStateCommand,
 	"credential-get" + cmdSuffix:          NewCredentialGetCommand,
+	"endpoint-addresses" + cmdSuffix:      NewEndpointAddressesCommand,
 }
 
 var storageCommands = map[string]creator{
@@ -51,6 +51,11 @@ type Runner interface {
 	// RunAction executes the action with the supplied name.
 	RunAction(name string) error
 
+	// RunMigration executes the charm's migration action with the
+	// supplied name. Unlike RunAction, a failure of the action is
+	// returned, so that it fails the hook the migration follows.
+	RunMigration(name string) error
+
 	// RunCommands executes the supplied script.
 	RunCommands(commands string) (*utilexec.ExecResponse, error)
 }
@@ -65,6 +70,7 @@ type Context interface {
 	HasExecutionSetUnitStatus() bool
 	ResetExecutionSetUnitStatus()
 	ModelType() model.ModelType
+	HookSandbox() context.HookSandbox
 
 	Prepare() error
 	Flush(badge string, failure error) error
@@ -277,6 +283,18 @@ func (runner *runner) RunAction(actionName string) error {
 	return runner.runCharmHookWithLocation(actionName, "actions", rMode)
 }
 
+// RunMigration exists to satisfy the Runner interface.
+func (runner *runner) RunMigration(actionName string) error {
+	if _, err := runner.context.ActionData(); err != nil {
+		return errors.Trace(err)
+	}
+	rMode := runOnLocal
+	if runner.context.ModelType() == model.CAAS {
+		rMode = runOnRemote
+	}
+	return runner.runCharmHookWithLocation(actionName, "actions", rMode)
+}
+
 // RunHook exists to satisfy the Runner interface.
 func (runner *runner) RunHook(hookName string) error {
 	return runner.runCharmHookWithLocation(hookName, "hooks", runOnLocal)
@@ -422,10 +440,14 @@ func (runner *runner) runCharmHookOnLocal(hookName string, env []string, charmLo
 	if err != nil {
 		return err
 	}
-	hookCmd := hookCommand(hook)
+	hookCmd, procAttr, err := runner.sandboxHook(hookCommand(hook))
+	if err != nil {
+		return errors.Annotatef(err, "cannot sandbox %s", hookName)
+	}
 	ps := exec.Command(hookCmd[0], hookCmd[1:]...)
 	ps.Env = env
 	ps.Dir = charmDir
+	ps.SysProcAttr = procAttr
 	outReader, outWriter, err := os.Pipe()
 	if err != nil {
 		return errors.Errorf("cannot make logging pipe: %v", err)
@@ -159,6 +159,7 @@ type MockContext struct {
 	flushFailure    error
 	flushResult     error
 	modelType       model.ModelType
+	hookSandbox     context.HookSandbox
 }
 
 func (ctx *MockContext) UnitName() string {
@@ -208,6 +209,10 @@ func (ctx *MockContext) ModelType() model.ModelType {
 	return ctx.modelType
 }
 
+func (ctx *MockContext) HookSandbox() context.HookSandbox {
+	return ctx.hookSandbox
+}
+
 type RunMockContextSuite struct {
 	envtesting.IsolationSuite
 	paths runnertesting.RealPaths
@@ -263,6 +268,44 @@ func (s *RunMockContextSuite) TestRunHookFlushFailure(c *gc.C) {
 	s.assertRecordedPid(c, ctx.expectPid)
 }
 
+func (s *RunMockContextSuite) TestRunMigrationFlushSuccess(c *gc.C) {
+	ctx := &MockContext{
+		actionData: &context.ActionData{Migration: true},
+	}
+	makeCharm(c, hookSpec{
+		dir:  "actions",
+		name: hookName,
+		perm: 0700,
+	}, s.paths.GetCharmDir())
+	actualErr := runner.NewRunner(ctx, s.paths, nil).RunMigration("something-happened")
+	c.Assert(actualErr, jc.ErrorIsNil)
+	c.Assert(ctx.flushBadge, gc.Equals, "something-happened")
+	c.Assert(ctx.flushFailure, gc.IsNil)
+	s.assertRecordedPid(c, ctx.expectPid)
+}
+
+func (s *RunMockContextSuite) TestRunMigrationNotAction(c *gc.C) {
+	ctx := &MockContext{}
+	err := runner.NewRunner(ctx, s.paths, nil).RunMigration("something-happened")
+	c.Assert(err, gc.ErrorMatches, "blam")
+}
+
+func (s *RunMockContextSuite) TestRunMigrationFlushFailure(c *gc.C) {
+	ctx := &MockContext{
+		actionData: &context.ActionData{Migration: true},
+	}
+	makeCharm(c, hookSpec{
+		dir:  "actions",
+		name: hookName,
+		perm: 0700,
+		code: 123,
+	}, s.paths.GetCharmDir())
+	actualErr := runner.NewRunner(ctx, s.paths, nil).RunMigration("something-happened")
+	c.Assert(actualErr, jc.ErrorIsNil)
+	c.Assert(ctx.flushBadge, gc.Equals, "something-happened")
+	c.Assert(ctx.flushFailure, gc.ErrorMatches, "exit status 123")
+}
+
 func (s *RunMockContextSuite) TestRunActionFlushSuccess(c *gc.C) {
 	expectErr := errors.New("pew pew pew")
 	ctx := &MockContext{
@@ -0,0 +1,219 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package runner
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"text/template"
+
+	"github.com/juju/errors"
+	jujuos "github.com/juju/os"
+	"github.com/juju/os/series"
+	"gopkg.in/juju/charm.v6"
+
+	"github.com/juju/juju/juju/paths"
+)
+
+// sandboxHook returns the command with which the hook command is to be
+// run, and the attributes of its process, so that it runs within the
+// sandbox configured for the model. The hook command is returned as
+// given if hooks are not sandboxed.
+//
+// The sandbox is an AppArmor profile, and optionally a separate user. No
+// seccomp filter is installed: the uniter cannot install one in the hook's
+// process without cgo or libseccomp, and the profile grants no
+// capabilities, so filtering system calls is left to a separate change.
+func (runner *runner) sandboxHook(hookCmd []string) ([]string, *syscall.SysProcAttr, error) {
+	sandbox := runner.context.HookSandbox()
+	if !sandbox.Enabled {
+		return hookCmd, nil, nil
+	}
+	if jujuos.HostOS() == jujuos.Windows {
+		return nil, nil, errors.NotSupportedf("hook sandbox on %s", jujuos.HostOS())
+	}
+
+	profile := sandbox.Profile
+	if profile == "" {
+		profile = hookProfileName(runner.context.UnitName())
+		allowed, err := runner.hookPaths()
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		content, err := hookProfile(profile, allowed)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		if err := ensureAppArmorProfile(profile, content); err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+	}
+	sandboxed := append([]string{"aa-exec", "-p", profile, "--"}, hookCmd...)
+
+	var procAttr *syscall.SysProcAttr
+	if sandbox.User != "" {
+		var err error
+		if procAttr, err = userProcAttr(sandbox.User); err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+	}
+	return sandboxed, procAttr, nil
+}
+
+// hookPaths holds the filesystem paths that a sandboxed hook may use,
+// beyond those needed by any process.
+type hookPaths struct {
+	// Writable holds the paths that the hook may read and write, and
+	// run programs from.
+	Writable []string
+
+	// Executable holds the paths that the hook may read and run
+	// programs from.
+	Executable []string
+}
+
+// hookPaths returns the paths that the unit's hooks may use: the charm
+// directory, the hook tools, and the locations of the storage declared
+// in the charm's metadata.
+func (runner *runner) hookPaths() (hookPaths, error) {
+	charmDir := runner.paths.GetCharmDir()
+	f, err := os.Open(filepath.Join(charmDir, "metadata.yaml"))
+	if err != nil {
+		return hookPaths{}, errors.Annotate(err, "cannot read charm metadata")
+	}
+	defer f.Close()
+	meta, err := charm.ReadMeta(f)
+	if err != nil {
+		return hookPaths{}, errors.Annotate(err, "cannot read charm metadata")
+	}
+
+	hostSeries, err := series.HostSeries()
+	if err != nil {
+		return hookPaths{}, errors.Trace(err)
+	}
+	storageDir, err := paths.StorageDir(hostSeries)
+	if err != nil {
+		return hookPaths{}, errors.Trace(err)
+	}
+	writable := []string{charmDir}
+	for name, storage := range meta.Storage {
+		location := storage.Location
+		if location == "" {
+			// Storage without a location is attached
+			// beneath the machine's storage directory.
+			location = filepath.Join(storageDir, name)
+		}
+		writable = append(writable, location)
+	}
+	sort.Strings(writable[1:])
+
+	// The hook tools are symlinks to the agent binary, which is
+	// installed alongside them.
+	toolsDir := runner.paths.GetToolsDir()
+	return hookPaths{
+		Writable:   writable,
+		Executable: []string{toolsDir, filepath.Dir(toolsDir)},
+	}, nil
+}
+
+// hookProfileName returns the name of the AppArmor profile generated
+// for the hooks of the named unit.
+func hookProfileName(unitName string) string {
+	return "juju-hook-" + strings.Replace(unitName, "/", "-", -1)
+}
+
+var hookProfileTemplate = template.Must(template.New("").Parse(`
+#include <tunables/global>
+
+profile {{.Name}} flags=(attach_disconnected,mediate_deleted) {
+  #include <abstractions/base>
+  #include <abstractions/bash>
+  #include <abstractions/nameservice>
+  #include <abstractions/python>
+  #include <abstractions/perl>
+
+  network,
+  unix,
+  signal (receive),
+  signal (send) peer={{.Name}},
+
+  / r,
+  /{,usr/}{,s}bin/** mrix,
+  /usr/{lib,lib32,lib64,libexec}/** mrix,
+  /{lib,lib32,lib64}/** mrix,
+  /usr/share/** r,
+  /etc/** r,
+  /proc/** r,
+  /sys/** r,
+  /tmp/ r,
+  /tmp/** rwlk,
+  /var/tmp/ r,
+  /var/tmp/** rwlk,
+{{range .Executable}}
+  {{.}}/ r,
+  {{.}}/** mrix,
+{{- end}}
+{{range .Writable}}
+  {{.}}/ rw,
+  {{.}}/** rwlkmix,
+{{- end}}
+}
+`[1:]))
+
+// hookProfile returns the text of the named AppArmor profile, which
+// allows the use of the given paths, and of the system's programs and
+// libraries. No capabilities are granted, so that a hook run as root is
+// still confined.
+func hookProfile(name string, allowed hookPaths) (string, error) {
+	var buf bytes.Buffer
+	err := hookProfileTemplate.Execute(&buf, struct {
+		Name       string
+		Writable   []string
+		Executable []string
+	}{name, allowed.Writable, allowed.Executable})
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return buf.String(), nil
+}
+
+var (
+	loadedProfilesMu sync.Mutex
+	loadedProfiles   = make(map[string]string)
+)
+
+// ensureAppArmorProfile loads the named AppArmor profile into the
+// kernel, unless the same profile has already been loaded.
+func ensureAppArmorProfile(name, content string) error {
+	loadedProfilesMu.Lock()
+	defer loadedProfilesMu.Unlock()
+	if loadedProfiles[name] == content {
+		return nil
+	}
+	if err := loadAppArmorProfile(content); err != nil {
+		return errors.Annotatef(err, "cannot load apparmor profile %q", name)
+	}
+	loadedProfiles[name] = content
+	return nil
+}
+
+// loadAppArmorProfile loads the AppArmor profile into the kernel,
+// replacing any existing profile of the same name.
+var loadAppArmorProfile = func(content string) error {
+	cmd := exec.Command("apparmor_parser", "--replace")
+	cmd.Stdin = strings.NewReader(content)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if out = bytes.TrimSpace(out); len(out) > 0 {
+			return errors.Errorf("%v: %s", err, out)
+		}
+		return errors.Trace(err)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build !windows
+
+package runner_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	envtesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/uniter/runner"
+	"github.com/juju/juju/worker/uniter/runner/context"
+	runnertesting "github.com/juju/juju/worker/uniter/runner/testing"
+)
+
+type SandboxSuite struct {
+	envtesting.IsolationSuite
+	paths    runnertesting.RealPaths
+	profiles []string
+}
+
+var _ = gc.Suite(&SandboxSuite{})
+
+func (s *SandboxSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.paths = runnertesting.NewRealPaths(c)
+	s.profiles = nil
+	restore := runner.PatchLoadAppArmorProfile(func(profile string) error {
+		s.profiles = append(s.profiles, profile)
+		return nil
+	})
+	s.AddCleanup(func(*gc.C) { restore() })
+}
+
+func (s *SandboxSuite) writeMetadata(c *gc.C, metadata string) {
+	err := ioutil.WriteFile(filepath.Join(s.paths.GetCharmDir(), "metadata.yaml"), []byte(metadata), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *SandboxSuite) TestDisabled(c *gc.C) {
+	rnr := runner.NewRunner(&MockContext{}, s.paths, nil)
+	cmd, procAttr, err := runner.SandboxHook(rnr, []string{"hooks/install"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmd, jc.DeepEquals, []string{"hooks/install"})
+	c.Check(procAttr, gc.IsNil)
+	c.Check(s.profiles, gc.HasLen, 0)
+}
+
+func (s *SandboxSuite) TestGeneratedProfile(c *gc.C) {
+	s.writeMetadata(c, `
+name: sandboxed
+summary: a charm
+description: a charm
+storage:
+  data:
+    type: filesystem
+    location: /srv/data
+  cache:
+    type: filesystem
+`[1:])
+	ctx := &MockContext{hookSandbox: context.HookSandbox{Enabled: true}}
+	rnr := runner.NewRunner(ctx, s.paths, nil)
+	cmd, procAttr, err := runner.SandboxHook(rnr, []string{"hooks/install"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmd, jc.DeepEquals, []string{"aa-exec", "-p", "juju-hook-some-unit-999", "--", "hooks/install"})
+	c.Check(procAttr, gc.IsNil)
+
+	c.Assert(s.profiles, gc.HasLen, 1)
+	profile := s.profiles[0]
+	c.Check(profile, jc.Contains, "profile juju-hook-some-unit-999 ")
+	c.Check(profile, jc.Contains, s.paths.GetCharmDir()+"/** rwlkmix,")
+	c.Check(profile, jc.Contains, "/srv/data/** rwlkmix,")
+	c.Check(profile, jc.Contains, "/var/lib/juju/storage/cache/** rwlkmix,")
+	c.Check(profile, jc.Contains, s.paths.GetToolsDir()+"/** mrix,")
+	c.Check(profile, gc.Not(jc.Contains), "capability")
+
+	// The profile is only loaded again if it changes.
+	_, _, err = runner.SandboxHook(rnr, []string{"hooks/start"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.profiles, gc.HasLen, 1)
+}
+
+func (s *SandboxSuite) TestConfiguredProfile(c *gc.C) {
+	ctx := &MockContext{hookSandbox: context.HookSandbox{
+		Enabled: true,
+		Profile: "charm-hooks",
+	}}
+	rnr := runner.NewRunner(ctx, s.paths, nil)
+	cmd, _, err := runner.SandboxHook(rnr, []string{"hooks/install"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmd, jc.DeepEquals, []string{"aa-exec", "-p", "charm-hooks", "--", "hooks/install"})
+	c.Check(s.profiles, gc.HasLen, 0)
+}
+
+func (s *SandboxSuite) TestUnknownUser(c *gc.C) {
+	ctx := &MockContext{hookSandbox: context.HookSandbox{
+		Enabled: true,
+		User:    "no-such-hook-user",
+		Profile: "charm-hooks",
+	}}
+	rnr := runner.NewRunner(ctx, s.paths, nil)
+	_, _, err := runner.SandboxHook(rnr, []string{"hooks/install"})
+	c.Assert(err, gc.ErrorMatches, `cannot find hook sandbox user "no-such-hook-user": .*`)
+}
+
+func (s *SandboxSuite) TestLoadProfileError(c *gc.C) {
+	s.writeMetadata(c, "name: sandboxed\nsummary: a charm\ndescription: a charm\n")
+	restore := runner.PatchLoadAppArmorProfile(func(string) error {
+		return errors.New("apparmor unavailable")
+	})
+	defer restore()
+
+	ctx := &MockContext{hookSandbox: context.HookSandbox{Enabled: true}}
+	rnr := runner.NewRunner(ctx, s.paths, nil)
+	_, _, err := runner.SandboxHook(rnr, []string{"hooks/install"})
+	c.Assert(err, gc.ErrorMatches, `cannot load apparmor profile "juju-hook-some-unit-999": apparmor unavailable`)
+}
+
+func (s *SandboxSuite) TestRunHookNotSandboxed(c *gc.C) {
+	ctx := &MockContext{hookSandbox: context.HookSandbox{Enabled: true}}
+	makeCharm(c, hookSpec{
+		dir:  "hooks",
+		name: hookName,
+		perm: 0700,
+	}, s.paths.GetCharmDir())
+	err := runner.NewRunner(ctx, s.paths, nil).RunHook("something-happened")
+	c.Assert(err, jc.ErrorIsNil)
+	// The hook is not run when it cannot be sandboxed.
+	c.Check(ctx.flushFailure, gc.ErrorMatches, "cannot sandbox something-happened: cannot read charm metadata: .*")
+	c.Check(ctx.expectPid, gc.Equals, 0)
+}
@@ -0,0 +1,37 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build !windows
+
+package runner
+
+import (
+	"os/user"
+	"strconv"
+	"syscall"
+
+	"github.com/juju/errors"
+)
+
+// userProcAttr returns the process attributes with which a process is
+// run as the named user, in the user's primary group.
+func userProcAttr(userName string) (*syscall.SysProcAttr, error) {
+	u, err := user.Lookup(userName)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot find hook sandbox user %q", userName)
+	}
+	uid, err := strconv.ParseUint(u.Uid, 10, 32)
+	if err != nil {
+		return nil, errors.Annotatef(err, "invalid uid for user %q", userName)
+	}
+	gid, err := strconv.ParseUint(u.Gid, 10, 32)
+	if err != nil {
+		return nil, errors.Annotatef(err, "invalid gid for user %q", userName)
+	}
+	return &syscall.SysProcAttr{
+		Credential: &syscall.Credential{
+			Uid: uint32(uid),
+			Gid: uint32(gid),
+		},
+	}, nil
+}
@@ -0,0 +1,16 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package runner
+
+import (
+	"syscall"
+
+	"github.com/juju/errors"
+)
+
+// userProcAttr is not supported on windows, where hooks cannot be run
+// in a sandbox.
+func userProcAttr(userName string) (*syscall.SysProcAttr, error) {
+	return nil, errors.NotSupportedf("running hooks as user %q on windows", userName)
+}
@@ -30,6 +30,7 @@ type RealPaths struct {
 	socket        sockets.Socket
 	metricsspool  string
 	componentDirs map[string]string
+	resourceCache string
 	fops          fops
 }
 
@@ -48,6 +49,7 @@ func NewRealPaths(c *gc.C) RealPaths {
 		socket:        osDependentSockPath(c),
 		metricsspool:  c.MkDir(),
 		componentDirs: make(map[string]string),
+		resourceCache: c.MkDir(),
 		fops:          c,
 	}
 }
@@ -76,6 +78,10 @@ func (p RealPaths) ComponentDir(name string) string {
 	return p.componentDirs[name]
 }
 
+func (p RealPaths) GetResourceCacheDir() string {
+	return p.resourceCache
+}
+
 type StorageContextAccessor struct {
 	CStorage map[names.StorageTag]*ContextStorage
 }
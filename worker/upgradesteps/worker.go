@@ -17,6 +17,7 @@ import (
 
 	"github.com/juju/juju/agent"
 	"github.com/juju/juju/api"
+	upgradestepsapi "github.com/juju/juju/api/upgradesteps"
 	cmdutil "github.com/juju/juju/cmd/jujud/util"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/mongo"
@@ -32,6 +33,10 @@ var logger = loggo.GetLogger("juju.worker.upgradesteps")
 var (
 	PerformUpgrade = upgrades.PerformUpgrade // Allow patching
 
+	// ReportUpgradeStepsCompleted tells the controller that a machine
+	// agent has completed its upgrade steps. Allow patching.
+	ReportUpgradeStepsCompleted = reportUpgradeStepsCompleted
+
 	// The maximum time a master controller will wait for other
 	// controllers to come up and indicate they are ready to begin
 	// running upgrade steps.
@@ -175,6 +180,16 @@ func (w *upgradesteps) run() error {
 		return nil
 	}
 
+	if w.isController {
+		// The upgrade step registry is verified whenever a controller
+		// starts, so that problems with it are noticed before an
+		// upgrade is attempted. The pre-upgrade checks prevent an
+		// upgrade with such problems.
+		for _, problem := range upgrades.VerifyRegistry(state.CollectionNames()) {
+			logger.Errorf("invalid upgrade step registry: %s", problem)
+		}
+	}
+
 	w.fromVersion = w.agent.CurrentConfig().UpgradedToVersion()
 	w.toVersion = jujuversion.Current
 	if w.fromVersion == w.toVersion {
@@ -241,15 +256,38 @@ func (w *upgradesteps) runUpgrades() error {
 	}
 
 	if err := w.agent.ChangeConfig(w.runUpgradeSteps); err != nil {
+		if w.isMaster && upgrades.IsRolledBack(err) {
+			if restoreErr := w.restorePreviousVersion(upgradeInfo); restoreErr != nil {
+				logger.Errorf("restoring previous version failed: %v", restoreErr)
+			}
+		}
 		return err
 	}
 
 	if err := w.finaliseUpgrade(upgradeInfo); err != nil {
 		return err
 	}
+
+	// The controller tracks which machine agents have completed their
+	// upgrade steps, so that stragglers can be found. Not being able
+	// to report it doesn't make the upgrade fail.
+	if w.tag.Kind() == names.MachineTagKind {
+		if err := ReportUpgradeStepsCompleted(w.apiConn, w.tag, w.toVersion); err != nil {
+			logger.Warningf("cannot report upgrade steps completed for %q: %v", w.tag, err)
+		}
+	}
 	return nil
 }
 
+func reportUpgradeStepsCompleted(apiConn api.Connection, tag names.Tag, vers version.Number) error {
+	err := upgradestepsapi.NewClient(apiConn).SetUpgradeStepsCompleted(tag, vers)
+	if errors.IsNotSupported(err) {
+		// The controller doesn't track upgrade steps.
+		return nil
+	}
+	return errors.Trace(err)
+}
+
 func (w *upgradesteps) prepareForUpgrade() (*state.UpgradeInfo, error) {
 	logger.Infof("checking that upgrade can proceed")
 	if err := w.preUpgradeSteps(w.pool, w.agent.CurrentConfig(), w.pool != nil, w.isMaster, w.isCaas); err != nil {
@@ -376,6 +414,23 @@ func (w *upgradesteps) runUpgradeSteps(agentConfig agent.ConfigSetter) error {
 	return nil
 }
 
+// restorePreviousVersion is called on the master controller when the
+// upgrade steps it ran failed and have been rolled back. It aborts the
+// upgrade and sets the model agent version back to the version being
+// upgraded from, so that the controllers return to it.
+func (w *upgradesteps) restorePreviousVersion(info *state.UpgradeInfo) error {
+	logger.Errorf("downgrading model agent version to %v due to rolled back upgrade",
+		w.fromVersion)
+	if err := info.Abort(); err != nil {
+		return errors.Annotate(err, "unable to abort upgrade")
+	}
+	st := w.pool.SystemState()
+	if err := st.SetModelAgentVersion(w.fromVersion, true); err != nil {
+		return errors.Annotate(err, "failed to roll back desired agent version")
+	}
+	return nil
+}
+
 func (w *upgradesteps) reportUpgradeFailure(err error, willRetry bool) {
 	retryText := "will retry"
 	if !willRetry {
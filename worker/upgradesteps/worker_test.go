@@ -20,6 +20,7 @@ import (
 	"gopkg.in/juju/worker.v1"
 
 	"github.com/juju/juju/agent"
+	"github.com/juju/juju/api"
 	cmdutil "github.com/juju/juju/cmd/jujud/util"
 	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/core/instance"
@@ -45,6 +46,7 @@ type UpgradeSuite struct {
 	connectionDead  bool
 	machineIsMaster bool
 	preUpgradeError bool
+	reported        []string
 }
 
 var _ = gc.Suite(&UpgradeSuite{})
@@ -85,6 +87,11 @@ func (s *UpgradeSuite) SetUpTest(c *gc.C) {
 	}
 	s.PatchValue(&IsMachineMaster, fakeIsMachineMaster)
 
+	s.reported = nil
+	s.PatchValue(&ReportUpgradeStepsCompleted, func(_ api.Connection, tag names.Tag, vers version.Number) error {
+		s.reported = append(s.reported, fmt.Sprintf("%s %s", tag, vers))
+		return nil
+	})
 }
 
 func (s *UpgradeSuite) captureLogs(c *gc.C) {
@@ -185,6 +192,7 @@ func (s *UpgradeSuite) TestUpgradeStepsFailure(c *gc.C) {
 	c.Assert(s.logWriter.Log(), jc.LogMatches,
 		s.makeExpectedUpgradeLogs(maxUpgradeRetries-1, "hostMachine", fails, "boom"))
 	c.Assert(doneLock.IsUnlocked(), jc.IsFalse)
+	c.Check(s.reported, gc.HasLen, 0)
 }
 
 func (s *UpgradeSuite) TestUpgradeStepsRetries(c *gc.C) {
@@ -213,6 +221,7 @@ func (s *UpgradeSuite) TestUpgradeStepsRetries(c *gc.C) {
 	c.Assert(statusCalls, jc.DeepEquals, s.makeExpectedStatusCalls(1, succeeds, "boom"))
 	c.Assert(s.logWriter.Log(), jc.LogMatches, s.makeExpectedUpgradeLogs(1, "hostMachine", succeeds, "boom"))
 	c.Check(doneLock.IsUnlocked(), jc.IsTrue)
+	c.Check(s.reported, jc.DeepEquals, []string{"machine-0 " + jujuversion.Current.String()})
 }
 
 func (s *UpgradeSuite) TestOtherUpgradeRunFailure(c *gc.C) {
@@ -244,6 +253,32 @@ func (s *UpgradeSuite) TestOtherUpgradeRunFailure(c *gc.C) {
 	c.Assert(doneLock.IsUnlocked(), jc.IsFalse)
 }
 
+func (s *UpgradeSuite) TestUpgradeStepsRolledBack(c *gc.C) {
+	// This test checks that when the upgrade steps run by the master
+	// controller fail and are rolled back, the upgrade is aborted and
+	// the model agent version is set back to the previous version.
+	err := s.State.SetModelAgentVersion(jujuversion.Current, false)
+	c.Assert(err, jc.ErrorIsNil)
+	attemptsP := s.countUpgradeAttempts(&upgrades.RolledBackError{Err: errors.New("boom")})
+	s.Factory.MakeMachine(c, &factory.MachineParams{
+		Jobs: []state.MachineJob{state.JobManageModel},
+	})
+
+	workerErr, config, statusCalls, doneLock := s.runUpgradeWorker(c, true)
+
+	c.Check(workerErr, gc.IsNil)
+	c.Check(*attemptsP, gc.Equals, maxUpgradeRetries)
+	c.Check(config.Version, gc.Equals, s.oldVersion.Number) // Upgrade didn't finish
+	c.Assert(statusCalls, jc.DeepEquals,
+		s.makeExpectedStatusCalls(maxUpgradeRetries-1, fails, "boom (steps rolled back)"))
+	c.Assert(doneLock.IsUnlocked(), jc.IsFalse)
+
+	upgrading, err := s.State.IsUpgrading()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(upgrading, jc.IsFalse)
+	s.assertEnvironAgentVersion(c, s.oldVersion.Number)
+}
+
 func (s *UpgradeSuite) TestAPIConnectionFailure(c *gc.C) {
 	// This test checks what happens when an upgrade fails because the
 	// connection to mongo has gone away. This will happen when the